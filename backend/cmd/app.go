@@ -71,7 +71,7 @@ func init() {
 	appCreateCmd.Flags().StringVar(&appDescription, "description", "", "Application description")
 	appCreateCmd.Flags().StringVar(&appHomepageURL, "homepage-url", "", "Application homepage URL")
 	appCreateCmd.Flags().StringVar(&appCallbackURLs, "callback-urls", "", "Comma-separated list of OAuth callback URLs")
-	appCreateCmd.Flags().StringVar(&appAuthMethods, "auth-methods", "password", "Comma-separated auth methods (password,oauth_google,oauth_github,oauth_yandex,oauth_telegram,otp_email,otp_sms,totp,api_key)")
+	appCreateCmd.Flags().StringVar(&appAuthMethods, "auth-methods", "password", "Comma-separated auth methods (password,oauth_google,oauth_github,oauth_yandex,oauth_telegram,oauth_apple,oauth_microsoft,oauth_linkedin,oauth_oidc-generic,otp_email,otp_sms,totp,api_key)")
 
 	appCreateCmd.MarkFlagRequired("name")
 	appCreateCmd.MarkFlagRequired("display-name")
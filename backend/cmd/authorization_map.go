@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/smilemakc/auth-gateway/internal/authmap"
+	grpcserver "github.com/smilemakc/auth-gateway/internal/grpc"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	authMapJSON bool
+	authMapOut  string
+)
+
+var authorizationMapCmd = &cobra.Command{
+	Use:   "authorization-map",
+	Short: "Export the authorization requirements of every route and gRPC method",
+	Long: `Build the router the way the server does and list every HTTP route with the credentials
+it accepts and the roles, permissions, scopes and policies its middleware require, followed
+by every gRPC method with the API key scope it requires.
+
+The map is generated from the router and middleware registration, so it reflects the
+configuration the command runs with: routes of disabled features are left out. Routes
+listed as public may still authenticate the caller in the handler, e.g. OAuth client
+authentication at the token endpoint.
+
+The same map is served to admins at GET /api/admin/authorization-map.
+
+Example:
+  auth-gateway-cli authorization-map
+  auth-gateway-cli authorization-map --json --out authorization-map.json`,
+	RunE: runAuthorizationMap,
+}
+
+func init() {
+	rootCmd.AddCommand(authorizationMapCmd)
+
+	authorizationMapCmd.Flags().BoolVar(&authMapJSON, "json", false, "Print the map as JSON")
+	authorizationMapCmd.Flags().StringVarP(&authMapOut, "out", "o", "", "File to write the map to instead of stdout")
+}
+
+func runAuthorizationMap(cmd *cobra.Command, args []string) error {
+	deps, cleanup, err := buildInfra()
+	if err != nil {
+		return fmt.Errorf("failed to initialize infra: %w", err)
+	}
+	defer cleanup()
+
+	repos := buildRepositories(deps)
+	services := buildServices(deps, repos)
+	handlers := buildHandlers(deps, repos, services)
+	middlewares := buildMiddlewares(deps, repos, services)
+	router := buildRouter(deps, services, handlers, middlewares)
+
+	authMap, err := authmap.Build(router, grpcserver.MethodAuthorization())
+	if err != nil {
+		return fmt.Errorf("failed to build authorization map: %w", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if authMapOut != "" {
+		file, err := os.Create(authMapOut)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", authMapOut, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if authMapJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(authMap)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tAUTHENTICATION\tREQUIREMENTS")
+	fmt.Fprintln(w, "------\t----\t--------------\t------------")
+	for _, route := range authMap.HTTP {
+		authentication := "public"
+		if !route.Public {
+			authentication = strings.Join(route.Authentication, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", route.Method, route.Path, authentication, formatRequirements(route.Requirements))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "GRPC METHOD\tAUTHENTICATION\tSCOPE")
+	fmt.Fprintln(w, "-----------\t--------------\t-----")
+	for _, method := range authMap.GRPC {
+		if method.Denied {
+			fmt.Fprintf(w, "%s\tdenied\t-\n", method.Method)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", method.Method, strings.Join(method.Authentication, ","), method.Scope)
+	}
+	return w.Flush()
+}
+
+// formatRequirements renders requirements as kind(value|value), joining all-of values with &
+func formatRequirements(requirements []models.AuthRequirement) string {
+	if len(requirements) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		separator := "|"
+		if requirement.All {
+			separator = "&"
+		}
+		parts[i] = fmt.Sprintf("%s(%s)", requirement.Kind, strings.Join(requirement.Values, separator))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conformanceIssuer       string
+	conformanceClientID     string
+	conformanceClientSecret string
+	conformanceJSON         bool
+)
+
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "Run OIDC provider conformance checks",
+	Long: `Exercise the discovery, JWKS, authorize, token, userinfo, introspection and revocation
+endpoints of a running Auth Gateway and check the responses against the OAuth 2.0 and
+OpenID Connect specifications.
+
+Checks that need a client are skipped unless a confidential client allowed to use the
+client_credentials grant is given. The run issues and then revokes one access token for it.
+The command exits with a non-zero status when a check fails.
+
+Example:
+  auth-gateway-cli conformance
+  auth-gateway-cli conformance --issuer https://auth.example.com --client-id agw_abc --client-secret agws_xyz
+  auth-gateway-cli conformance --json`,
+	// Only talks to the server over HTTP, no database connection needed
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if conformanceIssuer != "" {
+			return nil
+		}
+
+		var err error
+		cfg, err = config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		conformanceIssuer = cfg.OIDC.Issuer
+		if conformanceIssuer == "" {
+			conformanceIssuer = fmt.Sprintf("http://localhost:%s", cfg.Server.Port)
+		}
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {},
+	RunE:              runConformance,
+}
+
+func init() {
+	rootCmd.AddCommand(conformanceCmd)
+
+	conformanceCmd.Flags().StringVar(&conformanceIssuer, "issuer", "", "Issuer URL of the provider (defaults to OIDC_ISSUER)")
+	conformanceCmd.Flags().StringVar(&conformanceClientID, "client-id", "", "Client ID of a confidential client allowed to use client_credentials")
+	conformanceCmd.Flags().StringVar(&conformanceClientSecret, "client-secret", "", "Secret of that client")
+	conformanceCmd.Flags().BoolVar(&conformanceJSON, "json", false, "Output the report in JSON format")
+}
+
+func runConformance(cmd *cobra.Command, args []string) error {
+	log := logger.New("cli", logger.WarnLevel, false)
+	checker := service.NewOIDCConformanceService(conformanceIssuer, nil, log)
+
+	report := checker.Run(context.Background(), &models.OIDCConformanceRequest{
+		ClientID:     conformanceClientID,
+		ClientSecret: conformanceClientSecret,
+	})
+
+	if conformanceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("OIDC conformance report for %s\n\n", report.Issuer)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tCHECK\tDETAILS")
+		fmt.Fprintln(w, "------\t-----\t-------")
+		for _, result := range report.Results {
+			fmt.Fprintf(w, "%s\t%s\t%s (%s)\n", strings.ToUpper(result.Status), result.Name, result.Message, result.Spec)
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d passed, %d failed, %d skipped in %dms\n", report.Passed, report.Failed, report.Skipped, report.DurationMs)
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("%d conformance checks failed", report.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "OIDC signing key management commands",
+	Long: `Commands for generating OIDC signing keys, backing them up and restoring them into a
+new deployment. Every command is recorded in the audit log.`,
+}
+
+var (
+	keysKID         string
+	keysAlgorithm   string
+	keysOut         string
+	keysIn          string
+	keysDir         string
+	keysGeneratedBy string
+	keysWitnesses   []string
+	keysLocation    string
+	keysNotes       string
+)
+
+var keysGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a signing key and record the key ceremony",
+	Long: `Generate a new OIDC signing key (RSA 2048 for RS256, P-256 for ES256), write it as
+PKCS#8 PEM readable by the owner only, and record the key ceremony - who generated the
+key, when, with which algorithm and in whose presence - in the audit log.
+
+The key is not loaded until it is configured with OIDC_SIGNING_KEY_PATH or
+OIDC_ADDITIONAL_KEYS and the server is restarted.
+
+Example:
+  auth-gateway-cli keys generate --kid key-2025-03 --out /etc/auth-gateway/keys/key-2025-03.pem --generated-by alice@example.com
+  auth-gateway-cli keys generate --kid key-2025-03 --alg ES256 --out key.pem --generated-by alice@example.com \
+    --witness bob@example.com --witness carol@example.com --location "HQ, offline laptop"`,
+	RunE: runKeysGenerate,
+}
+
+var keysExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export an encrypted backup of the configured signing keys",
+	Long: `Read the OIDC signing keys configured with OIDC_SIGNING_KEY_PATH and OIDC_ADDITIONAL_KEYS
+and write them to a backup file encrypted with a passphrase (scrypt and AES-256-GCM).
+The passphrase is prompted for and must be at least 16 characters long.
+
+Example:
+  auth-gateway-cli keys export --out signing-keys.json`,
+	RunE: runKeysExport,
+}
+
+var keysImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Restore signing keys from an encrypted backup",
+	Long: `Decrypt a backup written by "keys export" or the admin API and write each key to
+<dir>/<kid>.pem. Existing files are never overwritten. Prints the configuration to load
+the keys with.
+
+Example:
+  auth-gateway-cli keys import --in signing-keys.json --dir /etc/auth-gateway/keys`,
+	RunE: runKeysImport,
+}
+
+func init() {
+	keysCmd.AddCommand(keysGenerateCmd)
+	keysCmd.AddCommand(keysExportCmd)
+	keysCmd.AddCommand(keysImportCmd)
+
+	keysGenerateCmd.Flags().StringVar(&keysKID, "kid", "", "Key ID (required)")
+	keysGenerateCmd.Flags().StringVar(&keysAlgorithm, "alg", string(keys.RS256), "Algorithm: RS256 or ES256")
+	keysGenerateCmd.Flags().StringVarP(&keysOut, "out", "o", "", "Private key file to write (required)")
+	keysGenerateCmd.Flags().StringVar(&keysGeneratedBy, "generated-by", "", "Person generating the key (required)")
+	keysGenerateCmd.Flags().StringArrayVar(&keysWitnesses, "witness", nil, "Witness of the ceremony (repeatable)")
+	keysGenerateCmd.Flags().StringVar(&keysLocation, "location", "", "Where the ceremony took place")
+	keysGenerateCmd.Flags().StringVar(&keysNotes, "notes", "", "Free-form notes")
+	keysGenerateCmd.MarkFlagRequired("kid")
+	keysGenerateCmd.MarkFlagRequired("out")
+	keysGenerateCmd.MarkFlagRequired("generated-by")
+
+	keysExportCmd.Flags().StringVarP(&keysOut, "out", "o", "", "Backup file to write (required)")
+	keysExportCmd.MarkFlagRequired("out")
+
+	keysImportCmd.Flags().StringVarP(&keysIn, "in", "i", "", "Backup file to read (required)")
+	keysImportCmd.Flags().StringVarP(&keysDir, "dir", "d", "", "Directory to write the keys to (required)")
+	keysImportCmd.MarkFlagRequired("in")
+	keysImportCmd.MarkFlagRequired("dir")
+}
+
+func runKeysGenerate(cmd *cobra.Command, args []string) error {
+	algorithm := keys.Algorithm(keysAlgorithm)
+	privateKey, err := keys.GenerateKey(algorithm)
+	if err != nil {
+		return err
+	}
+	encoded, err := keys.EncodePrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %w", err)
+	}
+	if err := writeSecretFile(keysOut, encoded); err != nil {
+		return err
+	}
+
+	thumbprint := keys.Thumbprint(&keys.SigningKey{
+		KID:       keysKID,
+		Algorithm: algorithm,
+		PublicKey: privateKey.(crypto.Signer).Public(),
+	})
+
+	generatedAt := time.Now().UTC()
+	details := map[string]interface{}{
+		"kid":          keysKID,
+		"algorithm":    string(algorithm),
+		"generated_by": keysGeneratedBy,
+		"generated_at": generatedAt.Format(time.RFC3339),
+		"thumbprint":   thumbprint,
+		"source":       "cli",
+	}
+	if len(keysWitnesses) > 0 {
+		details["witnesses"] = keysWitnesses
+	}
+	if keysLocation != "" {
+		details["location"] = keysLocation
+	}
+	if keysNotes != "" {
+		details["notes"] = keysNotes
+	}
+	if err := recordKeyAudit(models.ActionSigningKeyCeremony, details); err != nil {
+		return err
+	}
+
+	fmt.Println("\nSigning key generated")
+	fmt.Println("=====================")
+	fmt.Printf("Key ID:     %s\n", keysKID)
+	fmt.Printf("Algorithm:  %s\n", algorithm)
+	fmt.Printf("File:       %s\n", keysOut)
+	fmt.Printf("Thumbprint: %s\n", thumbprint)
+	fmt.Println("\nThe key ceremony has been recorded in the audit log.")
+	return nil
+}
+
+func runKeysExport(cmd *cobra.Command, args []string) error {
+	keyManager, err := keys.NewManager(buildKeyConfigs(&cfg.OIDC), cfg.OIDC.SigningKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	backup, err := keyManager.Backup()
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := promptPassphrase(true)
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	data, err := backup.Encrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	if err := writeSecretFile(keysOut, data); err != nil {
+		return err
+	}
+
+	kids := make([]string, 0, len(backup.Keys))
+	for _, key := range backup.Keys {
+		kids = append(kids, key.KID)
+	}
+	if err := recordKeyAudit(models.ActionSigningKeyBackupExport, map[string]interface{}{
+		"kids":        kids,
+		"current_kid": backup.CurrentKID,
+		"source":      "cli",
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d signing key(s) to %s: %s\n", len(kids), keysOut, strings.Join(kids, ", "))
+	fmt.Println("Store the passphrase separately from the backup.")
+	return nil
+}
+
+func runKeysImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(keysIn)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	passphrase, err := promptPassphrase(false)
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	backup, err := keys.DecryptBackup(data, passphrase)
+	if err != nil {
+		return err
+	}
+	keyConfigs, err := backup.WriteKeyFiles(keysDir)
+	if err != nil {
+		return fmt.Errorf("failed to write keys: %w", err)
+	}
+
+	kids := make([]string, 0, len(keyConfigs))
+	for _, keyConfig := range keyConfigs {
+		kids = append(kids, keyConfig.ID)
+	}
+	if err := recordKeyAudit(models.ActionSigningKeyImport, map[string]interface{}{
+		"kids":              kids,
+		"current_kid":       backup.CurrentKID,
+		"backup_created_at": backup.CreatedAt.Format(time.RFC3339),
+		"source":            "cli",
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d signing key(s) into %s\n", len(keyConfigs), keysDir)
+	fmt.Println("\nConfigure the server with:")
+	var additional []string
+	algorithms := make(map[keys.Algorithm]bool)
+	for _, keyConfig := range keyConfigs {
+		algorithms[keyConfig.Algorithm] = true
+		if keyConfig.ID == backup.CurrentKID {
+			fmt.Printf("  OIDC_SIGNING_KEY_PATH=%s\n", keyConfig.PrivateKeyPath)
+			fmt.Printf("  OIDC_SIGNING_KEY_ID=%s\n", keyConfig.ID)
+			fmt.Printf("  OIDC_SIGNING_ALGORITHM=%s\n", keyConfig.Algorithm)
+			continue
+		}
+		additional = append(additional, keyConfig.ID+":"+keyConfig.PrivateKeyPath)
+	}
+	if len(additional) > 0 {
+		fmt.Printf("  OIDC_ADDITIONAL_KEYS=%s\n", strings.Join(additional, ","))
+	}
+	if len(algorithms) > 1 {
+		fmt.Println("\nWarning: the backup mixes algorithms, but OIDC_SIGNING_ALGORITHM applies to every configured key.")
+	}
+	return nil
+}
+
+// recordKeyAudit writes a signing key audit entry; the CLI acts without a user
+func recordKeyAudit(action models.AuditAction, details map[string]interface{}) error {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	entry := &models.AuditLog{
+		Action:    string(action),
+		Status:    string(models.StatusSuccess),
+		UserAgent: "auth-gateway-cli",
+		Details:   detailsJSON,
+	}
+	if err := repository.NewAuditRepository(db).Create(context.Background(), entry); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// writeSecretFile writes data to a new file readable by the owner only
+func writeSecretFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// promptPassphrase securely prompts for the backup passphrase, twice when confirm is set
+func promptPassphrase(confirm bool) ([]byte, error) {
+	fmt.Print("Enter backup passphrase: ")
+
+	if term.IsTerminal(int(syscall.Stdin)) {
+		passphrase, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return nil, err
+		}
+		if !confirm {
+			return passphrase, nil
+		}
+
+		fmt.Print("Confirm backup passphrase: ")
+		confirmation, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return nil, err
+		}
+		if string(passphrase) != string(confirmation) {
+			return nil, fmt.Errorf("passphrases do not match")
+		}
+		return passphrase, nil
+	}
+
+	// Fallback for non-terminal input (piped input)
+	reader := bufio.NewReader(os.Stdin)
+	passphrase, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(passphrase, "\r\n")), nil
+}
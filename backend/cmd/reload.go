@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+)
+
+// watchConfig reloads the configuration on SIGHUP, and when the config file changes if
+// CONFIG_WATCH_INTERVAL is set, until ctx is done. Rate limits, CORS policies and the
+// disabled OAuth providers take effect at once; other changes are logged as needing a restart.
+func watchConfig(ctx context.Context, deps *infra, services *serviceSet, middlewares *middlewareSet) {
+	log := deps.log.Module("config")
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var fileChanged <-chan time.Time
+	var modTime time.Time
+	if deps.cfg.Reload.File != "" && deps.cfg.Reload.WatchInterval > 0 {
+		ticker := time.NewTicker(deps.cfg.Reload.WatchInterval)
+		defer ticker.Stop()
+		fileChanged = ticker.C
+		modTime = configFileModTime(deps.cfg.Reload.File)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Info("Reloading configuration on SIGHUP")
+		case <-fileChanged:
+			mt := configFileModTime(deps.cfg.Reload.File)
+			if mt.Equal(modTime) {
+				continue
+			}
+			modTime = mt
+			log.Info("Reloading configuration, config file changed", map[string]interface{}{
+				"file": deps.cfg.Reload.File,
+			})
+		}
+
+		next, err := config.Load()
+		if err != nil {
+			log.Error("Failed to reload configuration, keeping the current one", map[string]interface{}{
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		middlewares.RateLimit.SetConfig(&next.RateLimit)
+		middlewares.CORS.SetConfig(&next.CORS)
+		services.OAuth.SetDisabledProviders(next.OAuth.DisabledProviders)
+
+		// deps.cfg stays the configuration the server started with: components keep
+		// pointers into it, and the sections differing from it still wait for a restart
+		fields := map[string]interface{}{}
+		if sections := config.RestartRequired(deps.cfg, next); len(sections) > 0 {
+			fields["restart_required"] = sections
+		}
+		log.Info("Configuration reloaded", fields)
+	}
+}
+
+func configFileModTime(name string) time.Time {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -14,6 +14,14 @@ var (
 	db  *repository.Database
 )
 
+// Build information, set with -ldflags "-X github.com/smilemakc/auth-gateway/cmd.Version=v1.4.0"
+// (see LDFLAGS in the Makefile). Commit and BuildDate fall back to the VCS stamp of go build.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "auth-gateway-cli",
 	Short: "Auth Gateway CLI management tool",
@@ -50,6 +58,7 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(adminCmd)
 	rootCmd.AddCommand(appCmd)
+	rootCmd.AddCommand(keysCmd)
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(serverCmd)
 }
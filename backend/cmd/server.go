@@ -42,8 +42,8 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"html/template"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
@@ -51,8 +51,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 	_ "github.com/smilemakc/auth-gateway/docs"
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
+	"github.com/smilemakc/auth-gateway/internal/auditexport"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
+	"github.com/smilemakc/auth-gateway/internal/chaos"
 	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/credentials"
+	"github.com/smilemakc/auth-gateway/internal/eventbus"
 	grpcserver "github.com/smilemakc/auth-gateway/internal/grpc"
 	"github.com/smilemakc/auth-gateway/internal/handler"
 	"github.com/smilemakc/auth-gateway/internal/jobs"
@@ -62,10 +70,13 @@ import (
 	"github.com/smilemakc/auth-gateway/internal/repository"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/smilemakc/auth-gateway/internal/templates"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/smilemakc/auth-gateway/pkg/keys"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/smilemakc/auth-gateway/pkg/signedurl"
 	"github.com/spf13/cobra"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -75,11 +86,20 @@ type infra struct {
 	cfg            *config.Config
 	log            *logger.Logger
 	keyManager     *keys.Manager
+	keyRotation    *service.SigningKeyRotationService // nil unless OIDC key rotation is enabled
 	db             *repository.Database
 	redis          *service.RedisService
 	jwtService     *jwt.Service
 	oidcJWTService *jwt.OIDCService
 	smsProvider    sms.SMSProvider
+	eventPublisher eventbus.Publisher // nil when the event bus is disabled
+	auditSinks     []auditexport.Sink // empty when audit export is disabled
+	faults         *chaos.Injector
+	accessLog      *accesslog.Logger // nil when disabled
+
+	// Credentials broker backends; nil unless configured
+	credentialsPG  *credentials.PostgresProvisioner
+	credentialsSSH *credentials.SSHSigner
 }
 
 type repoSet struct {
@@ -98,9 +118,29 @@ type repoSet struct {
 	Template         *repository.TemplateRepository
 	Branding         *repository.BrandingRepository
 	System           *repository.SystemRepository
+	PasswordHistory  *repository.PasswordHistoryRepository
+	PasswordExpiry   *repository.PasswordExpiryRepository
+	EmailVerify      *repository.EmailVerificationRepository
+	DormantAccount   *repository.DormantAccountRepository
+	Retention        *repository.RetentionRepository
+	Guest            *repository.GuestRepository
+	AccountRecovery  *repository.AccountRecoveryRepository
+	UserEmail        *repository.UserEmailRepository
 	Geo              *repository.GeoRepository
 	OAuthProvider    *repository.OAuthProviderRepository
+	OAuthClientLogo  *repository.OAuthClientLogoRepository
+	ClientWebhook    *repository.OAuthClientWebhookRepository
+	OIDCLogout       *repository.BackchannelLogoutRepository
+	PermCatalog      *repository.PermissionCatalogRepository
+	BulkRoleJob      *repository.BulkRoleJobRepository
+	BackgroundJob    *repository.BackgroundJobRepository
+	Outbox           *repository.OutboxRepository
+	AuditExport      *repository.AuditExportRepository
+	DataExport       *repository.DataExportRepository
 	Group            *repository.GroupRepository
+	GroupAdmin       *repository.GroupAdminRepository
+	GroupDomain      *repository.GroupDomainRepository
+	UsageReport      *repository.UsageReportRepository
 	LDAP             *repository.LDAPRepository
 	SAML             *repository.SAMLRepository
 	EmailProvider    *repository.EmailProviderRepository
@@ -110,12 +150,20 @@ type repoSet struct {
 	TelegramBot      *repository.TelegramBotRepository
 	UserTelegram     *repository.UserTelegramRepository
 	SMSSettings      *repository.SMSSettingsRepository
+	Credentials      *repository.CredentialsRepository
+	RiskPolicy       *repository.RiskPolicyRepository
+	AccountLockout   *repository.AccountLockoutRepository
+	UserTimeline     *repository.UserTimelineRepository
+	WebAuthn         *repository.WebAuthnRepository
 }
 
 type serviceSet struct {
 	Geo              *service.GeoService
 	Audit            *service.AuditService
 	Blacklist        *service.BlacklistService
+	Revocations      *service.RevocationHub
+	UserCache        *service.UserCache
+	RedisSessions    *service.RedisSessionStore // nil unless sessions are kept in Redis
 	Session          *service.SessionService
 	Auth             *service.AuthService
 	User             *service.UserService
@@ -131,7 +179,21 @@ type serviceSet struct {
 	Template         *service.TemplateService
 	OAuthProvider    *service.OAuthProviderService
 	MinimalOAuthSvc  *service.OAuthProviderService
+	OIDCConformance  *service.OIDCConformanceService
+	OIDCLogout       *service.BackchannelLogoutService
+	OAuthClientLogo  *service.OAuthClientLogoService
+	ClientWebhook    *service.OAuthClientWebhookService
+	PermCatalog      *service.PermissionCatalogService
+	BulkRoleJob      *service.BulkRoleJobService
+	JobQueue         *service.JobQueueService
+	Outbox           *service.OutboxService
 	Group            *service.GroupService
+	OrgAdmin         *service.OrgAdminService
+	UsageReport      *service.UsageReportService
+	SignupPolicy     *service.SignupPolicyService
+	PasswordPolicy   *service.PasswordPolicyService
+	UserTimeline     *service.UserTimelineService
+	LogLevel         *service.LogLevelService
 	LDAP             *service.LDAPService
 	Bulk             *service.BulkService
 	SCIM             *service.SCIMService
@@ -142,11 +204,36 @@ type serviceSet struct {
 	Telegram         *service.TelegramService
 	Migration        *service.MigrationService
 	TokenExchange    *service.TokenExchangeService
+	TokenVersion     *service.TokenVersionService
+	PasswordExpiry   *service.PasswordExpiryService
+	EmailVerify      *service.EmailVerificationService
+	Guest            *service.GuestService
+	AccountRecovery  *service.AccountRecoveryService
+	UserEmail        *service.UserEmailService
+	Credentials      *service.CredentialsBrokerService // nil when disabled
+	SignedURL        *service.SignedURLService         // nil when disabled
+	Risk             *service.RiskService              // nil when disabled
+	TorExitList      *service.TorExitList              // nil unless configured
+	AccountLockout   *service.AccountLockoutService    // nil when disabled
+	WebAuthn         *webauthn.WebAuthn                // nil when disabled
+	SMSDelivery      *service.SMSDeliveryService       // nil unless SMS delivery callbacks are configured
+	MagicLink        *service.MagicLinkService         // nil when disabled
+	UserLifecycle    *service.UserLifecycleService
+	DormantAccount   *service.DormantAccountService
+	Retention        *service.RetentionService
+	SLO              *service.SLOService         // nil when disabled
+	AuditExport      *service.AuditExportService // nil when no sink is configured
+	DataExport       *service.DataExportService  // nil when disabled
 }
 
 type handlerSet struct {
 	Auth             *handler.AuthHandler
 	Health           *handler.HealthHandler
+	Status           *handler.StatusHandler
+	SLO              *handler.SLOHandler         // nil when disabled
+	AuditExport      *handler.AuditExportHandler // nil when no sink is configured
+	DataExport       *handler.DataExportHandler  // nil when disabled
+	Version          *handler.VersionHandler
 	APIKey           *handler.APIKeyHandler
 	OTP              *handler.OTPHandler
 	OAuth            *handler.OAuthHandler
@@ -157,8 +244,23 @@ type handlerSet struct {
 	Template         *handler.TemplateHandler
 	OAuthProvider    *handler.OAuthProviderHandler
 	OAuthAdmin       *handler.OAuthAdminHandler
+	OIDCConformance  *handler.OIDCConformanceHandler
+	ConnectedApp     *handler.ConnectedAppHandler
+	OAuthClientLogo  *handler.OAuthClientLogoHandler
+	ClientWebhook    *handler.OAuthClientWebhookHandler
+	PermCatalog      *handler.PermissionCatalogHandler
+	BulkRoleJob      *handler.BulkRoleJobHandler
+	BackgroundJob    *handler.BackgroundJobHandler
 	Login            *handler.LoginHandler
 	Group            *handler.GroupHandler
+	OrgAdmin         *handler.OrgAdminHandler
+	UsageReport      *handler.UsageReportHandler
+	SignupPolicy     *handler.SignupPolicyHandler
+	DormantAccount   *handler.DormantAccountHandler
+	Retention        *handler.RetentionHandler
+	PasswordPolicy   *handler.PasswordPolicyHandler
+	UserTimeline     *handler.UserTimelineHandler
+	LogLevel         *handler.LogLevelHandler
 	SCIM             *handler.SCIMHandler
 	LDAP             *handler.LDAPHandler
 	Bulk             *handler.BulkHandler
@@ -171,16 +273,34 @@ type handlerSet struct {
 	Migration        *handler.MigrationHandler
 	TokenExchange    *handler.TokenExchangeHandler
 	SMSSettings      *handler.SMSSettingsHandler
+	TokenVersion     *handler.TokenVersionHandler
+	PasswordExpiry   *handler.PasswordExpiryHandler
+	AccountRecovery  *handler.AccountRecoveryHandler
+	UserEmail        *handler.UserEmailHandler
+	Guest            *handler.GuestHandler
+	Chaos            *handler.ChaosHandler
+	Credentials      *handler.CredentialsHandler
+	SignedURL        *handler.SignedURLHandler
+	SigningKey       *handler.SigningKeyHandler
+	RiskPolicy       *handler.RiskPolicyHandler
+	AccountLockout   *handler.AccountLockoutHandler
+	WebAuthn         *handler.WebAuthnHandler
+	SMSDelivery      *handler.SMSDeliveryHandler
+	MagicLink        *handler.MagicLinkHandler
 }
 
 type middlewareSet struct {
 	Auth        *middleware.AuthMiddleware
 	APIKey      *middleware.APIKeyMiddleware
 	RateLimit   *middleware.RateLimitMiddleware
+	CORS        *middleware.CORSMiddleware
 	IPFilter    *middleware.IPFilterMiddleware
 	Maintenance *middleware.MaintenanceMiddleware
 	Application *middleware.ApplicationMiddleware
 	AppSecret   *middleware.AppSecretMiddleware
+	OrgAdmin    *middleware.OrgAdminMiddleware
+	RBAC        *middleware.RBACMiddleware
+	Idempotency *middleware.IdempotencyMiddleware
 }
 
 // serverCmd represents the server command
@@ -233,10 +353,59 @@ func runServer() {
 		startMetricsCollection(bgCtx, deps.db, deps.redis, deps.log)
 	}
 
+	go jobs.NewPasswordExpiryWarningJob(services.PasswordExpiry, deps.log).Start(bgCtx)
+	go jobs.NewUsageReportJob(services.UsageReport, deps.log).Start(bgCtx)
+	go jobs.NewEmailVerificationReminderJob(services.EmailVerify, deps.log).Start(bgCtx)
+	if deps.cfg.Security.GuestSessions.Enabled {
+		go jobs.NewGuestCleanupJob(services.Guest, deps.log).Start(bgCtx)
+	}
+	go jobs.NewDormantAccountJob(services.DormantAccount, deps.log).Start(bgCtx)
+	if deps.cfg.Security.Retention.Enabled() {
+		go jobs.NewRetentionJob(services.Retention, deps.log).Start(bgCtx)
+	}
+	if deps.cfg.Security.BlacklistFilter.Enabled {
+		go jobs.NewBlacklistFilterJob(services.Blacklist, deps.cfg.Security.BlacklistFilter.RebuildInterval, deps.log).Start(bgCtx)
+	}
+	if services.Credentials != nil {
+		go jobs.NewCredentialsCleanupJob(services.Credentials, deps.log).Start(bgCtx)
+	}
+	if services.SLO != nil {
+		go jobs.NewSLOBudgetJob(services.SLO, deps.log).Start(bgCtx)
+	}
+	if services.AuditExport != nil {
+		go jobs.NewAuditExportJob(services.AuditExport, deps.log).Start(bgCtx)
+	}
+	if services.DataExport != nil {
+		go jobs.NewDataExportCleanupJob(services.DataExport, deps.log).Start(bgCtx)
+	}
+	if storageCfg := deps.cfg.Security.SessionStorage; services.RedisSessions != nil && storageCfg.ArchiveEnabled {
+		go jobs.NewSessionArchiveJob(services.RedisSessions, storageCfg.ArchiveInterval, storageCfg.ArchiveBatchSize, deps.log).Start(bgCtx)
+	}
+	if deps.keyRotation != nil {
+		go jobs.NewSigningKeyRotationJob(deps.keyRotation, deps.cfg.OIDC.KeyRotation.CheckInterval, deps.log).Start(bgCtx)
+	}
+	if services.TorExitList != nil {
+		go jobs.NewTorExitListJob(services.TorExitList, deps.cfg.Risk.TorExitListRefresh, deps.log).Start(bgCtx)
+	}
+	go services.JobQueue.Run(bgCtx)
+	go services.Outbox.Run(bgCtx)
+	go services.Revocations.Run(bgCtx)
+	go services.LogLevel.Run(bgCtx)
+	if services.OIDCLogout != nil {
+		go services.OIDCLogout.Run(bgCtx)
+	}
+	if services.UserCache != nil {
+		go services.UserCache.Run(bgCtx)
+	}
+	go watchConfig(bgCtx, deps, services, middlewares)
+	if resolver := config.SecretResolver(); resolver != nil {
+		go resolver.Run(bgCtx, deps.log.Module("secrets"))
+	}
+
 	// Start LDAP sync job if LDAP service is available
 	var ldapSyncJob *jobs.LDAPSyncJob
 	if services.LDAP != nil {
-		ldapSyncJob = jobs.NewLDAPSyncJob(services.LDAP, deps.log)
+		ldapSyncJob = jobs.NewLDAPSyncJob(services.LDAP, deps.log.Module("ldap"))
 		ldapCtx, ldapCancel := context.WithCancel(bgCtx)
 		go func() {
 			ldapSyncJob.Start(ldapCtx)
@@ -271,8 +440,18 @@ func runServer() {
 		services.Admin,
 		services.Application,
 		deps.redis,
+		deps.db,
 		services.TokenExchange,
-		deps.log,
+		services.TokenVersion,
+		services.PermCatalog,
+		services.Blacklist.Filter(),
+		services.Revocations,
+		services.UserCache,
+		services.Session,
+		deps.accessLog,
+		deps.cfg.Metrics.Enabled,
+		deps.cfg.Tracing.Enabled,
+		deps.log.Module("grpc"),
 	)
 	if err != nil {
 		deps.log.Fatal("Failed to create gRPC server", map[string]interface{}{
@@ -330,7 +509,7 @@ func runServer() {
 		close(grpcDone)
 	}()
 
-	grpcTimeout := time.After(15 * time.Second)
+	grpcTimeout := time.After(deps.cfg.GRPC.ShutdownTimeout)
 	select {
 	case <-grpcDone:
 		deps.log.Info("gRPC server stopped gracefully")
@@ -342,19 +521,48 @@ func runServer() {
 	deps.log.Info("Servers exited successfully")
 }
 
+// newLogger creates the server logger from the logging configuration
+func newLogger(cfg *config.Config) *logger.Logger {
+	opts := []logger.Option{
+		logger.WithRedaction(logger.RedactionConfig{
+			Keys: cfg.Logging.RedactKeys,
+			PII:  cfg.Logging.RedactPII,
+		}),
+	}
+	if cfg.Logging.SamplingInitial > 0 {
+		opts = append(opts, logger.WithSampling(logger.SamplingConfig{
+			Initial:    cfg.Logging.SamplingInitial,
+			Thereafter: cfg.Logging.SamplingThereafter,
+			Tick:       cfg.Logging.SamplingTick,
+		}))
+	}
+	return logger.New("auth-gateway", logger.LogLevel(cfg.Server.LogLevel), cfg.Logging.Format != "text", opts...)
+}
+
 func buildInfra() (*infra, func(), error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	log := logger.New("auth-gateway", logger.LogLevel(cfg.Server.LogLevel), true)
+	log := newLogger(cfg)
 	logger.SetDefault(log)
 	log.Info("Starting Auth Gateway", map[string]interface{}{
 		"env":  cfg.Server.Env,
 		"port": cfg.Server.Port,
 	})
 
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Tracing.Enabled {
+		log.Info("Tracing enabled", map[string]interface{}{
+			"endpoint":     cfg.Tracing.Endpoint,
+			"sample_ratio": cfg.Tracing.SampleRatio,
+		})
+	}
+
 	var keyManager *keys.Manager
 	if cfg.OIDC.Enabled {
 		keyConfigs := buildKeyConfigs(&cfg.OIDC)
@@ -369,7 +577,14 @@ func buildInfra() (*infra, func(), error) {
 		}
 	}
 
-	db, err := repository.NewDatabase(&cfg.Database)
+	var faults *chaos.Injector
+	var dbOpts []repository.DatabaseOption
+	if cfg.Chaos.Enabled {
+		faults = chaos.NewInjector()
+		dbOpts = append(dbOpts, repository.WithQueryTracer(faults.QueryTracer()))
+	}
+
+	db, err := repository.NewDatabase(&cfg.Database, dbOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -382,37 +597,176 @@ func buildInfra() (*infra, func(), error) {
 	}
 	log.Info("Redis connected successfully")
 
+	if cfg.Metrics.Enabled {
+		db.AddQueryHook(metrics.QueryHook())
+		redis.AddHook(metrics.RedisHook())
+	}
+	if cfg.Tracing.Enabled {
+		db.AddQueryHook(tracing.QueryHook())
+		redis.AddHook(tracing.RedisHook())
+	}
+
+	if faults != nil {
+		db.AddQueryHook(faults.QueryHook())
+		redis.AddHook(faults.RedisHook())
+		log.Warn("Fault injection is enabled; admins can inject latency and errors into dependency calls")
+	}
+
+	// Rotated keys replace the configured ones, which only seed the database
+	var keyRotation *service.SigningKeyRotationService
+	if cfg.OIDC.Enabled && cfg.OIDC.KeyRotation.Enabled {
+		keyRotation = service.NewSigningKeyRotationService(
+			repository.NewSigningKeyRepository(db),
+			keyManager,
+			keys.Algorithm(cfg.OIDC.SigningAlgorithm),
+			cfg.Security.EncryptionKey,
+			cfg.OIDC.KeyRotation,
+			log.Module("keys"),
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := keyRotation.Sync(ctx)
+		cancel()
+		if err != nil {
+			_ = redis.Close()
+			_ = db.Close()
+			return nil, nil, err
+		}
+		keyManager = keyRotation.Manager()
+		log.Info("OIDC signing key rotation enabled", map[string]interface{}{
+			"interval": cfg.OIDC.KeyRotation.Interval.String(),
+			"keys":     len(keyManager.Keys()),
+		})
+	}
+
+	jwtOpts := []jwt.ServiceOption{
+		jwt.WithIssuer(cfg.JWT.Issuer),
+		jwt.WithAudience(cfg.JWT.Audience...),
+		jwt.WithClock(jwt.Clock{Skew: cfg.JWT.ClockSkew}),
+	}
+	if cfg.JWT.IsAsymmetric() {
+		jwtKeyManager, err := buildJWTKeyManager(&cfg.JWT, keyManager)
+		if err != nil {
+			_ = redis.Close()
+			_ = db.Close()
+			return nil, nil, err
+		}
+		jwtOpts = append(jwtOpts, jwt.WithKeyManager(jwtKeyManager, cfg.JWT.AcceptHMAC))
+		log.Info("First-party tokens use asymmetric signing", map[string]interface{}{
+			"algorithm":   cfg.JWT.SigningAlgorithm,
+			"accept_hmac": cfg.JWT.AcceptHMAC,
+		})
+	}
+
 	jwtService := jwt.NewService(
 		cfg.JWT.AccessSecret,
 		cfg.JWT.RefreshSecret,
 		cfg.JWT.AccessExpires,
 		cfg.JWT.RefreshExpires,
+		jwtOpts...,
 	)
 
 	var oidcJWTService *jwt.OIDCService
 	if keyManager != nil {
 		oidcJWTService = jwt.NewOIDCService(keyManager, cfg.OIDC.Issuer)
+		oidcJWTService.SetClock(jwt.Clock{Skew: cfg.JWT.ClockSkew})
+	}
+
+	var accessLog *accesslog.Logger
+	if cfg.AccessLog.Enabled {
+		accessLog, err = accesslog.New(&cfg.AccessLog)
+		if err != nil {
+			_ = redis.Close()
+			_ = db.Close()
+			return nil, nil, err
+		}
+	}
+
+	credentialsPG, credentialsSSH, err := buildCredentialsBackends(&cfg.Credentials, log)
+	if err != nil {
+		_ = accessLog.Close()
+		_ = redis.Close()
+		_ = db.Close()
+		return nil, nil, err
 	}
 
 	deps := &infra{
 		cfg:            cfg,
 		log:            log,
 		keyManager:     keyManager,
+		keyRotation:    keyRotation,
 		db:             db,
 		redis:          redis,
 		jwtService:     jwtService,
 		oidcJWTService: oidcJWTService,
 		smsProvider:    initSMSProvider(cfg, log),
+		eventPublisher: initEventPublisher(cfg, log),
+		auditSinks:     initAuditExportSinks(cfg, log),
+		faults:         faults,
+		accessLog:      accessLog,
+		credentialsPG:  credentialsPG,
+		credentialsSSH: credentialsSSH,
 	}
 
 	cleanup := func() {
 		_ = redis.Close()
 		_ = db.Close()
+		_ = accessLog.Close()
+		if deps.eventPublisher != nil {
+			_ = deps.eventPublisher.Close()
+		}
+		for _, sink := range deps.auditSinks {
+			_ = sink.Close()
+		}
+		if credentialsPG != nil {
+			credentialsPG.Close()
+		}
+		// Flush the spans still buffered
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
 	}
 
 	return deps, cleanup, nil
 }
 
+// buildCredentialsBackends connects the credentials broker to the Postgres server and loads
+// the SSH CA key it was configured with. Both are nil when the broker is disabled.
+func buildCredentialsBackends(cfg *config.CredentialsConfig, log *logger.Logger) (*credentials.PostgresProvisioner, *credentials.SSHSigner, error) {
+	if !cfg.Enabled {
+		return nil, nil, nil
+	}
+
+	var pg *credentials.PostgresProvisioner
+	var sshCA *credentials.SSHSigner
+	if cfg.PostgresDSN != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var err error
+		if pg, err = credentials.NewPostgresProvisioner(ctx, cfg.PostgresDSN); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.SSHCAKeyPath != "" {
+		var err error
+		if sshCA, err = credentials.NewSSHSigner(cfg.SSHCAKeyPath); err != nil {
+			if pg != nil {
+				pg.Close()
+			}
+			return nil, nil, err
+		}
+	}
+
+	if pg == nil && sshCA == nil {
+		log.Warn("Credentials broker is enabled without CREDENTIALS_POSTGRES_DSN or CREDENTIALS_SSH_CA_KEY; no targets can be created")
+	} else {
+		log.Info("Credentials broker enabled", map[string]interface{}{
+			"postgres": pg != nil,
+			"ssh":      sshCA != nil,
+		})
+	}
+	return pg, sshCA, nil
+}
+
 func buildRepositories(deps *infra) *repoSet {
 	return &repoSet{
 		User:             repository.NewUserRepository(deps.db),
@@ -430,9 +784,29 @@ func buildRepositories(deps *infra) *repoSet {
 		Template:         repository.NewTemplateRepository(deps.db),
 		Branding:         repository.NewBrandingRepository(deps.db),
 		System:           repository.NewSystemRepository(deps.db),
+		PasswordHistory:  repository.NewPasswordHistoryRepository(deps.db),
+		PasswordExpiry:   repository.NewPasswordExpiryRepository(deps.db),
+		EmailVerify:      repository.NewEmailVerificationRepository(deps.db),
+		DormantAccount:   repository.NewDormantAccountRepository(deps.db),
+		Retention:        repository.NewRetentionRepository(deps.db),
+		Guest:            repository.NewGuestRepository(deps.db),
+		AccountRecovery:  repository.NewAccountRecoveryRepository(deps.db),
+		UserEmail:        repository.NewUserEmailRepository(deps.db),
 		Geo:              repository.NewGeoRepository(deps.db),
 		OAuthProvider:    repository.NewOAuthProviderRepository(deps.db),
+		OAuthClientLogo:  repository.NewOAuthClientLogoRepository(deps.db),
+		ClientWebhook:    repository.NewOAuthClientWebhookRepository(deps.db),
+		OIDCLogout:       repository.NewBackchannelLogoutRepository(deps.db),
+		PermCatalog:      repository.NewPermissionCatalogRepository(deps.db),
+		BulkRoleJob:      repository.NewBulkRoleJobRepository(deps.db),
+		BackgroundJob:    repository.NewBackgroundJobRepository(deps.db),
+		Outbox:           repository.NewOutboxRepository(deps.db),
+		AuditExport:      repository.NewAuditExportRepository(deps.db),
+		DataExport:       repository.NewDataExportRepository(deps.db),
 		Group:            repository.NewGroupRepository(deps.db),
+		GroupAdmin:       repository.NewGroupAdminRepository(deps.db),
+		GroupDomain:      repository.NewGroupDomainRepository(deps.db),
+		UsageReport:      repository.NewUsageReportRepository(deps.db),
 		LDAP:             repository.NewLDAPRepository(deps.db),
 		SAML:             repository.NewSAMLRepository(deps.db),
 		EmailProvider:    repository.NewEmailProviderRepository(deps.db),
@@ -442,6 +816,11 @@ func buildRepositories(deps *infra) *repoSet {
 		TelegramBot:      repository.NewTelegramBotRepository(deps.db),
 		UserTelegram:     repository.NewUserTelegramRepository(deps.db),
 		SMSSettings:      repository.NewSMSSettingsRepository(deps.db),
+		Credentials:      repository.NewCredentialsRepository(deps.db),
+		RiskPolicy:       repository.NewRiskPolicyRepository(deps.db),
+		AccountLockout:   repository.NewAccountLockoutRepository(deps.db),
+		UserTimeline:     repository.NewUserTimelineRepository(deps.db),
+		WebAuthn:         repository.NewWebAuthnRepository(deps.db),
 	}
 }
 
@@ -453,7 +832,23 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 	}
 
 	auditService := service.NewAuditService(repos.Audit, geoService)
-	blacklistService := service.NewBlacklistService(deps.redis, repos.Token, repos.Session, deps.jwtService, deps.log, auditService)
+
+	// Sessions: PostgreSQL by default, or Redis with optional archival to PostgreSQL
+	var sessionStore service.SessionStore = repos.Session
+	var redisSessions *service.RedisSessionStore
+	if storageCfg := deps.cfg.Security.SessionStorage; storageCfg.Backend == config.SessionStorageRedis {
+		var archive service.SessionArchive
+		if storageCfg.ArchiveEnabled {
+			archive = repos.Session
+		}
+		redisSessions = service.NewRedisSessionStore(deps.redis, repos.User, archive, deps.log)
+		sessionStore = redisSessions
+		deps.log.Info("Sessions stored in Redis", map[string]interface{}{
+			"archive": storageCfg.ArchiveEnabled,
+		})
+	}
+
+	blacklistService := service.NewBlacklistService(deps.redis, repos.Token, sessionStore, deps.jwtService, deps.log, auditService)
 
 	// Synchronize blacklist from database to Redis on startup
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -466,12 +861,49 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 	} else {
 		deps.log.Info("Blacklist synchronized from database to Redis")
 	}
+	if filterCfg := deps.cfg.Security.BlacklistFilter; filterCfg.Enabled {
+		blacklistService.EnableFilter(service.NewBlacklistFilter(filterCfg.ExpectedEntries, filterCfg.FalsePositiveRate), deps.redis)
+	}
+
+	// RevocationHub: revocation events for the gRPC WatchRevocations stream
+	revocationHub := service.NewRevocationHub(deps.redis, deps.log)
+	blacklistService.SetRevocationHub(revocationHub)
 
-	sessionService := service.NewSessionService(repos.Session, blacklistService, deps.log, deps.cfg.Security.MaxActiveSessions)
+	// UserCache: users for token validation and userinfo, dropped when repositories change them
+	var userCache *service.UserCache
+	if deps.cfg.Redis.UserCacheEnabled {
+		userCache = service.NewUserCache(repos.User, deps.redis, deps.redis, deps.cfg.Redis.UserCacheTTL, deps.log)
+		deps.db.OnUserChanged(userCache.Invalidate)
+	}
+
+	sessionService := service.NewSessionService(sessionStore, blacklistService, deps.log, deps.cfg.Security.MaxActiveSessions)
 	userService := service.NewUserService(repos.User, auditService)
 	apiKeyService := service.NewAPIKeyService(repos.APIKey, repos.User, auditService)
 	emailService := service.NewEmailService(&deps.cfg.SMTP)
+	emailService.SetFaultInjector(deps.faults)
 	twoFAService := service.NewTwoFactorService(repos.User, repos.BackupCode, "Auth Gateway")
+
+	// WebAuthn: FIDO2 security keys as a second factor, offered before TOTP and backup codes
+	var relyingParty *webauthn.WebAuthn
+	if webauthnCfg := deps.cfg.Security.WebAuthn; webauthnCfg.RPID != "" {
+		rp, err := webauthn.New(&webauthn.Config{
+			RPID:          webauthnCfg.RPID,
+			RPDisplayName: webauthnCfg.RPDisplayName,
+			RPOrigins:     webauthnCfg.RPOrigins,
+			AuthenticatorSelection: protocol.AuthenticatorSelection{
+				ResidentKey:      protocol.ResidentKeyRequirementDiscouraged,
+				UserVerification: protocol.VerificationDiscouraged,
+			},
+		})
+		if err != nil {
+			deps.log.Error("Failed to configure WebAuthn, security keys are disabled", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			relyingParty = rp
+			twoFAService.SetWebAuthn(relyingParty, repos.WebAuthn, deps.redis)
+		}
+	}
 	// Convert password policy config to utils PasswordPolicy
 	passwordPolicy := utils.PasswordPolicy{
 		MinLength:        deps.cfg.Security.PasswordPolicy.MinLength,
@@ -480,11 +912,55 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		RequireNumbers:   deps.cfg.Security.PasswordPolicy.RequireNumbers,
 		RequireSpecial:   deps.cfg.Security.PasswordPolicy.RequireSpecial,
 		MaxLength:        deps.cfg.Security.PasswordPolicy.MaxLength,
+		HistoryCount:     deps.cfg.Security.PasswordPolicy.HistoryCount,
 	}
 	adminService := service.NewAdminService(repos.User, repos.APIKey, repos.Audit, repos.OAuth, repos.RBAC, repos.BackupCode, repos.Application, deps.cfg.Security.BcryptCost, deps.db)
 	rbacService := service.NewRBACService(repos.RBAC, auditService)
+	rbacService.SetUserStore(repos.User)
 	ipFilterService := service.NewIPFilterService(repos.IPFilter)
+	// JobQueueService: persistent background jobs with retries, run by every instance
+	jobQueueService := service.NewJobQueueService(repos.BackgroundJob, deps.log.Module("jobs"))
+
 	webhookService := service.NewWebhookService(repos.Webhook, auditService)
+	webhookService.SetFaultInjector(deps.faults)
+	webhookService.SetJobQueue(jobQueueService)
+	if deps.cfg.Tracing.Enabled {
+		webhookService.EnableTracing()
+	}
+
+	// OutboxService: audit logs and webhooks recorded in the transaction of their state change
+	outboxService := service.NewOutboxService(repos.Outbox, auditService, webhookService, deps.log.Module("outbox"))
+
+	// EventBusService: auth events to Kafka or NATS, published through the job queue
+	var eventBusService *service.EventBusService
+	if deps.eventPublisher != nil {
+		eventBusService = service.NewEventBusService(deps.eventPublisher, jobQueueService, deps.cfg.EventBus.DefaultTopic, deps.cfg.EventBus.Topics, deps.cfg.EventBus.MaxAttempts, deps.log.Module("eventbus"))
+		rbacService.SetEventBus(eventBusService)
+	}
+
+	// AuditExportService: audit logs to syslog and S3, exported through the job queue
+	var auditExportService *service.AuditExportService
+	if len(deps.auditSinks) > 0 {
+		auditExportService = service.NewAuditExportService(deps.auditSinks, repos.AuditExport, repos.Audit, jobQueueService, deps.cfg.AuditExport.Delay, deps.log.Module("auditexport"))
+	}
+
+	// DataExportService: GDPR exports of a user's data, generated through the job queue
+	var dataExportService *service.DataExportService
+	if exportCfg := deps.cfg.DataExport; exportCfg.Enabled {
+		dataExportService = service.NewDataExportService(
+			repos.DataExport,
+			repos.DataExport,
+			repos.User,
+			sessionStore,
+			jobQueueService,
+			signedurl.New([]byte(deps.cfg.SignedURLs.Secret)),
+			auditService,
+			deps.cfg.Server.ExternalURL,
+			exportCfg.TTL,
+			exportCfg.LinkTTL,
+			deps.log.Module("dataexport"),
+		)
+	}
 	templateService := service.NewTemplateService(repos.Template, auditService)
 
 	// Email Profile Service for multi-provider email support
@@ -496,15 +972,86 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		deps.cfg,
 		emailService,
 	)
+	emailProfileService.SetFaultInjector(deps.faults)
 
 	// LoginAlertService: detects logins from new devices and sends email alerts
-	loginAlertService := service.NewLoginAlertService(deps.redis, repos.Session, emailProfileService, geoService, deps.log)
+	loginAlertService := service.NewLoginAlertService(deps.redis, sessionStore, emailProfileService, geoService, deps.log)
 
 	// PasswordChecker: checks passwords against HaveIBeenPwned API
 	passwordChecker := service.NewPasswordChecker(deps.cfg.Security.PasswordPolicy.CheckCompromised)
 
-	authService := service.NewAuthService(repos.User, repos.Token, repos.RBAC, auditService, deps.jwtService, blacklistService, deps.redis, sessionService, twoFAService, deps.cfg.Security.BcryptCost, passwordPolicy, deps.db, repos.Application, loginAlertService, webhookService, deps.cfg.Security.StrictTokenBinding, passwordChecker)
-	oauthService := service.NewOAuthService(repos.User, repos.OAuth, repos.Token, repos.Audit, repos.RBAC, deps.jwtService, sessionService, &http.Client{Timeout: 10 * time.Second}, repos.AppOAuthProvider, repos.Application, deps.cfg.Security.JITProvisioning, loginAlertService)
+	// TokenVersionService: per-user token versions and the global invalidation epoch
+	tokenVersionService := service.NewTokenVersionService(repos.User, repos.System, deps.redis, deps.log)
+	tokenVersionService.SetRevocationHub(revocationHub)
+
+	// PasswordExpiryService: role/group password max-age policies and forced rotation
+	passwordExpiryService := service.NewPasswordExpiryService(repos.PasswordExpiry, emailProfileService, deps.cfg.Security.PasswordPolicy.MaxAgeDays, deps.cfg.Security.PasswordPolicy.ExpiryWarnDays, deps.log)
+
+	// EmailVerificationService: email verification enforcement level and reminders
+	emailVerificationService := service.NewEmailVerificationService(
+		repos.EmailVerify,
+		emailProfileService,
+		deps.cfg.Security.EmailVerification.Enforcement,
+		deps.cfg.Security.EmailVerification.GracePeriodDays,
+		deps.cfg.Security.EmailVerification.ReminderDays,
+		deps.log,
+	)
+
+	// SignupPolicyService: sign-up restrictions and organization auto-join by verified domain
+	signupPolicyService := service.NewSignupPolicyService(repos.System, repos.GroupDomain, repos.Group, deps.log)
+
+	// PasswordPolicyService: admin-managed password policy with dictionary and breach checks.
+	// The environment policy is its default until an admin changes it.
+	passwordPolicyCfg := deps.cfg.Security.PasswordPolicy
+	var passwordDictionary []string
+	if passwordPolicyCfg.DictionaryFile != "" {
+		words, err := service.LoadPasswordDictionary(passwordPolicyCfg.DictionaryFile)
+		if err != nil {
+			deps.log.Error("Failed to load password dictionary, using the built-in list", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		passwordDictionary = words
+	}
+	passwordPolicyService := service.NewPasswordPolicyService(repos.System, repos.PasswordHistory, service.NewPasswordChecker(true), models.PasswordPolicy{
+		MinLength:        passwordPolicyCfg.MinLength,
+		MaxLength:        passwordPolicyCfg.MaxLength,
+		RequireUppercase: passwordPolicyCfg.RequireUppercase,
+		RequireLowercase: passwordPolicyCfg.RequireLowercase,
+		RequireNumbers:   passwordPolicyCfg.RequireNumbers,
+		RequireSpecial:   passwordPolicyCfg.RequireSpecial,
+		HistoryCount:     passwordPolicyCfg.HistoryCount,
+		CheckDictionary:  passwordPolicyCfg.CommonPasswords,
+		CheckCompromised: passwordPolicyCfg.CheckCompromised,
+	}, passwordDictionary, deps.log)
+	adminService.SetPasswordPolicy(passwordPolicyService)
+	adminService.SetWebAuthnCredentials(repos.WebAuthn)
+	adminService.SetOutbox(outboxService)
+
+	authService := service.NewAuthService(repos.User, repos.Token, repos.RBAC, auditService, deps.jwtService, blacklistService, deps.redis, sessionService, twoFAService, deps.cfg.Security.BcryptCost, passwordPolicy, deps.db, repos.Application, loginAlertService, webhookService, deps.cfg.Security.StrictTokenBinding, passwordChecker, tokenVersionService, repos.PasswordHistory, passwordExpiryService, deps.cfg.Security.LoginIdentifiers, signupPolicyService, emailVerificationService)
+	authService.SetPasswordPolicy(passwordPolicyService)
+	authService.SetOutbox(outboxService)
+	if eventBusService != nil {
+		authService.SetEventBus(eventBusService)
+	}
+
+	// UserLifecycleService: invited/active/suspended/deactivated/deleted state transitions
+	userLifecycleService := service.NewUserLifecycleService(repos.User, repos.Token, sessionStore, tokenVersionService, auditService, deps.log)
+	userLifecycleService.SetWebhooks(webhookService)
+	authService.SetUserLifecycle(userLifecycleService)
+	adminService.SetUserLifecycle(userLifecycleService)
+
+	// DormantAccountService: notifies and then suspends/deactivates/anonymizes accounts nobody uses
+	dormantAccountService := service.NewDormantAccountService(repos.System, repos.DormantAccount, userLifecycleService, emailProfileService, auditService, deps.log)
+
+	// RetentionService: deletes audit logs and sessions past retention, erases inactive accounts and GDPR erasure requests
+	retentionService := service.NewRetentionService(deps.cfg.Security.Retention, repos.Retention, repos.DormantAccount, repos.RBAC, userLifecycleService, auditService, deps.log)
+	var providerTokenKey string
+	if deps.cfg.OAuth.StoreProviderTokens {
+		providerTokenKey = deps.cfg.Security.EncryptionKey
+	}
+	oauthService := service.NewOAuthService(repos.User, repos.OAuth, repos.Token, repos.Audit, repos.RBAC, deps.jwtService, sessionService, &http.Client{Timeout: 10 * time.Second}, repos.AppOAuthProvider, repos.Application, deps.cfg.Security.JITProvisioning, loginAlertService, providerTokenKey, deps.cfg.OAuth.EmailCollision, signupPolicyService)
+	oauthService.SetDisabledProviders(deps.cfg.OAuth.DisabledProviders)
 
 	// OTP Service
 	otpService := service.NewOTPService(
@@ -518,10 +1065,125 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 			Cache:               deps.redis,
 			Config:              deps.cfg,
 			EmailProfileService: emailProfileService,
+			SignupPolicy:        signupPolicyService,
 		},
 	)
 
+	// SMSDeliveryService: records delivery status callbacks from the SMS provider
+	var smsDeliveryService *service.SMSDeliveryService
+	if deps.smsProvider != nil && deps.cfg.SMS.StatusCallbackToken != "" {
+		smsDeliveryService = service.NewSMSDeliveryService(repos.SMSLog, auditService, deps.log)
+	}
+
+	// MagicLinkService: passwordless sign-in by emailed one-click links
+	var magicLinkService *service.MagicLinkService
+	if deps.cfg.Security.MagicLink.Enabled {
+		magicLinkService = service.NewMagicLinkService(repos.User, repos.Application, deps.redis, authService, emailProfileService, auditService, deps.cfg.Server.ExternalURL, deps.cfg.Security.MagicLink, deps.log)
+	}
+
+	// AccountRecoveryService: identity proofing for accounts that lost both password and 2FA
+	accountRecoveryService := service.NewAccountRecoveryService(
+		repos.AccountRecovery,
+		repos.User,
+		otpService,
+		authService,
+		auditService,
+		deps.cfg.Security.AccountRecovery,
+		deps.log,
+		service.AccountRecoveryServiceOptions{
+			Notifier:    emailProfileService,
+			SMSProvider: deps.smsProvider,
+			Cache:       deps.redis,
+		},
+	)
+
+	// UserEmailService: secondary email addresses and the choice of recovery address
+	userEmailService := service.NewUserEmailService(repos.UserEmail, repos.User, otpService, auditService, deps.log)
+
+	// GuestService: anonymous guest sessions upgradable to full accounts
+	guestService := service.NewGuestService(
+		repos.Guest,
+		repos.User,
+		repos.RBAC,
+		authService,
+		auditService,
+		deps.cfg.Security.BcryptCost,
+		passwordPolicy,
+		deps.cfg.Security.GuestSessions.RetentionDays,
+		deps.log,
+		service.GuestServiceOptions{
+			TokenVersions:     tokenVersionService,
+			SignupPolicy:      signupPolicyService,
+			EmailVerification: emailVerificationService,
+			PasswordChecker:   passwordChecker,
+			PasswordPolicy:    passwordPolicyService,
+		},
+	)
+
+	// CredentialsBrokerService: short-lived Postgres logins and SSH certificates for RBAC-permitted users
+	var credentialsService *service.CredentialsBrokerService
+	if deps.cfg.Credentials.Enabled {
+		opts := service.CredentialsBrokerOptions{}
+		if deps.credentialsPG != nil {
+			opts.Postgres = deps.credentialsPG
+		}
+		if deps.credentialsSSH != nil {
+			opts.SSH = deps.credentialsSSH
+		}
+		credentialsService = service.NewCredentialsBrokerService(
+			repos.Credentials,
+			repos.RBAC,
+			auditService,
+			deps.cfg.Credentials.DefaultTTL,
+			deps.cfg.Credentials.MaxTTL,
+			deps.log,
+			opts,
+		)
+	}
+
+	// RiskService: scores password sign-ins and may require 2FA or block them
+	var riskService *service.RiskService
+	var torExitList *service.TorExitList
+	if deps.cfg.Risk.Enabled {
+		var geo service.GeoLocator
+		if geoService != nil {
+			geo = geoService
+		}
+		if deps.cfg.Risk.TorExitListURL != "" {
+			torExitList = service.NewTorExitList(deps.cfg.Risk.TorExitListURL)
+		}
+		riskService = service.NewRiskService(repos.RiskPolicy, deps.redis, geo, torExitList, deps.log.Module("risk"))
+		authService.SetRiskEngine(riskService)
+	}
+
+	// AccountLockoutService: locks accounts after repeated failed password sign-ins from any IP
+	var accountLockoutService *service.AccountLockoutService
+	if lockoutCfg := deps.cfg.Security.AccountLockout; lockoutCfg.Enabled {
+		accountLockoutService = service.NewAccountLockoutService(repos.AccountLockout, models.AccountLockoutPolicy{
+			MaxFailedAttempts:  lockoutCfg.MaxFailedAttempts,
+			ResetAfter:         lockoutCfg.Window,
+			LockoutDuration:    lockoutCfg.Duration,
+			MaxLockoutDuration: lockoutCfg.MaxDuration,
+		}, deps.log.Module("lockout"))
+		authService.SetAccountLockout(accountLockoutService)
+	}
+
+	// SignedURLService: short-lived URLs to protected resources, bound to a user and scopes
+	var signedURLService *service.SignedURLService
+	if deps.cfg.SignedURLs.Enabled {
+		signedURLService = service.NewSignedURLService(
+			signedurl.New([]byte(deps.cfg.SignedURLs.Secret)),
+			repos.RBAC,
+			auditService,
+			deps.cfg.SignedURLs.AllowedOrigins,
+			deps.cfg.SignedURLs.DefaultTTL,
+			deps.cfg.SignedURLs.MaxTTL,
+		)
+	}
+
 	var oauthProviderService *service.OAuthProviderService
+	var oidcConformanceService *service.OIDCConformanceService
+	var backchannelLogoutService *service.BackchannelLogoutService
 	if deps.cfg.OIDC.Enabled && deps.oidcJWTService != nil {
 		baseURL := deps.cfg.OIDC.Issuer
 		if baseURL == "" {
@@ -534,10 +1196,27 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 			sessionService,
 			deps.oidcJWTService,
 			deps.keyManager,
-			deps.log,
+			deps.log.Module("oidc"),
 			deps.cfg.OIDC.Issuer,
 			baseURL,
 		)
+		oauthProviderService.SetUserCache(userCache)
+		oauthProviderService.SetGroups(repos.Group)
+		oidcConformanceService = service.NewOIDCConformanceService(baseURL, nil, deps.log)
+
+		backchannelLogoutService = service.NewBackchannelLogoutService(
+			repos.OIDCLogout,
+			deps.oidcJWTService,
+			&http.Client{
+				Timeout: deps.cfg.OIDC.BackchannelLogoutTimeout,
+				// Clients must answer at the registered URI
+				CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+			},
+			deps.cfg.OIDC.BackchannelLogoutMaxAttempts,
+			deps.log.Module("oidc"),
+		)
+		sessionService.SetBackchannelLogout(backchannelLogoutService)
+		authService.SetBackchannelLogout(backchannelLogoutService)
 	}
 
 	var minimalOAuth *service.OAuthProviderService
@@ -546,6 +1225,15 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 	} else {
 		minimalOAuth = service.NewOAuthProviderServiceMinimal(repos.OAuthProvider, repos.Audit, deps.log)
 	}
+	oauthClientWebhookService := service.NewOAuthClientWebhookService(repos.ClientWebhook, repos.OAuthProvider, auditService, deps.log.Module("oidc"))
+	if deps.cfg.Tracing.Enabled {
+		oauthClientWebhookService.EnableTracing()
+	}
+	minimalOAuth.SetClientEvents(oauthClientWebhookService)
+	if eventBusService != nil {
+		minimalOAuth.SetEventBus(eventBusService)
+	}
+	oauthClientLogoService := service.NewOAuthClientLogoService(repos.OAuthClientLogo, repos.OAuthProvider, deps.log, deps.cfg.OIDC.Issuer, deps.cfg.OIDC.ClientLogoMaxBytes)
 
 	groupService := service.NewGroupService(repos.Group, repos.User, deps.log)
 
@@ -554,14 +1242,14 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 	if baseURL == "" {
 		baseURL = fmt.Sprintf("http://localhost:%s", deps.cfg.Server.Port)
 	}
-	scimService := service.NewSCIMService(repos.User, repos.Group, deps.log, baseURL)
+	scimService := service.NewSCIMService(repos.User, repos.Group, deps.log.Module("scim"), baseURL)
 
 	// SAML Service
 	samlService := service.NewSAMLService(
 		repos.SAML,
 		repos.User,
 		repos.RBAC,
-		deps.log,
+		deps.log.Module("saml"),
 		baseURL,
 		baseURL,
 	)
@@ -571,7 +1259,7 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		repos.LDAP,
 		repos.User,
 		repos.Group,
-		deps.log,
+		deps.log.Module("ldap"),
 		deps.cfg.Security.EncryptionKey,
 	)
 
@@ -583,6 +1271,9 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		deps.cfg.Security.BcryptCost,
 	)
 
+	// LogLevelService: runtime log level changes for the root logger and the modules above
+	logLevelService := service.NewLogLevelService(deps.log, logger.LogLevel(deps.cfg.Server.LogLevel), deps.cfg.Logging.LevelRevertAfter, deps.redis, deps.redis)
+
 	// Application Service
 	applicationService := service.NewApplicationService(repos.Application, repos.AppOAuthProvider, deps.log)
 
@@ -598,10 +1289,20 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 	// Token Exchange Service
 	tokenExchangeService := service.NewTokenExchangeService(deps.redis, deps.jwtService, repos.Application, repos.User, auditService)
 
+	// SLOService: error budgets of the critical endpoints, alerting through webhooks
+	var sloService *service.SLOService
+	if sloCfg := deps.cfg.SLO; sloCfg.Enabled {
+		sloService = service.NewSLOService(service.DefaultSLODefinitions(), sloCfg.Window, sloCfg.AlertCooldown, deps.redis, deps.log.Module("slo"))
+		sloService.SetWebhooks(webhookService)
+	}
+
 	return &serviceSet{
 		Geo:              geoService,
 		Audit:            auditService,
 		Blacklist:        blacklistService,
+		Revocations:      revocationHub,
+		UserCache:        userCache,
+		RedisSessions:    redisSessions,
 		Session:          sessionService,
 		Auth:             authService,
 		User:             userService,
@@ -617,7 +1318,21 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		Template:         templateService,
 		OAuthProvider:    oauthProviderService,
 		MinimalOAuthSvc:  minimalOAuth,
+		OIDCConformance:  oidcConformanceService,
+		OIDCLogout:       backchannelLogoutService,
+		OAuthClientLogo:  oauthClientLogoService,
+		ClientWebhook:    oauthClientWebhookService,
+		PermCatalog:      service.NewPermissionCatalogService(repos.RBAC, repos.PermCatalog, deps.log),
+		BulkRoleJob:      service.NewBulkRoleJobService(repos.BulkRoleJob, repos.User, repos.RBAC, repos.Group, deps.log),
+		JobQueue:         jobQueueService,
+		Outbox:           outboxService,
 		Group:            groupService,
+		OrgAdmin:         service.NewOrgAdminService(repos.GroupAdmin, repos.Group, repos.User, repos.RBAC, repos.APIKey, adminService, deps.log),
+		UsageReport:      service.NewUsageReportService(repos.UsageReport, repos.Group, emailProfileService, deps.log),
+		SignupPolicy:     signupPolicyService,
+		PasswordPolicy:   passwordPolicyService,
+		UserTimeline:     service.NewUserTimelineService(repos.UserTimeline, repos.User),
+		LogLevel:         logLevelService,
 		LDAP:             ldapService,
 		Bulk:             bulkService,
 		SCIM:             scimService,
@@ -628,6 +1343,26 @@ func buildServices(deps *infra, repos *repoSet) *serviceSet {
 		Telegram:         telegramService,
 		Migration:        migrationService,
 		TokenExchange:    tokenExchangeService,
+		TokenVersion:     tokenVersionService,
+		UserLifecycle:    userLifecycleService,
+		DormantAccount:   dormantAccountService,
+		Retention:        retentionService,
+		PasswordExpiry:   passwordExpiryService,
+		EmailVerify:      emailVerificationService,
+		Guest:            guestService,
+		AccountRecovery:  accountRecoveryService,
+		UserEmail:        userEmailService,
+		Credentials:      credentialsService,
+		SignedURL:        signedURLService,
+		Risk:             riskService,
+		TorExitList:      torExitList,
+		AccountLockout:   accountLockoutService,
+		WebAuthn:         relyingParty,
+		SMSDelivery:      smsDeliveryService,
+		MagicLink:        magicLinkService,
+		SLO:              sloService,
+		AuditExport:      auditExportService,
+		DataExport:       dataExportService,
 	}
 }
 
@@ -636,6 +1371,20 @@ func buildHandlers(deps *infra, repos *repoSet, services *serviceSet) *handlerSe
 
 	authHandler := handler.NewAuthHandler(services.Auth, services.User, services.OTP, services.EmailProfile, deps.log)
 	healthHandler := handler.NewHealthHandler(deps.db, deps.redis)
+	statusHandler := handler.NewStatusHandler(deps.db, deps.redis, repos.System, Version, time.Now())
+	versionHandler := handler.NewVersionHandler(Version, Commit, BuildDate)
+	var sloHandler *handler.SLOHandler
+	if services.SLO != nil {
+		sloHandler = handler.NewSLOHandler(services.SLO)
+	}
+	var auditExportHandler *handler.AuditExportHandler
+	if services.AuditExport != nil {
+		auditExportHandler = handler.NewAuditExportHandler(services.AuditExport, deps.log)
+	}
+	var dataExportHandler *handler.DataExportHandler
+	if services.DataExport != nil {
+		dataExportHandler = handler.NewDataExportHandler(services.DataExport, deps.log)
+	}
 	apiKeyHandler := handler.NewAPIKeyHandler(services.APIKey, deps.log)
 	otpHandler := handler.NewOTPHandler(services.OTP, services.Auth, deps.log)
 	oauthHandler := handler.NewOAuthHandler(services.OAuth, deps.log, deps.cfg.OAuth.TelegramBotToken, secureCookie)
@@ -647,17 +1396,30 @@ func buildHandlers(deps *infra, repos *repoSet, services *serviceSet) *handlerSe
 
 	var oauthProviderHandler *handler.OAuthProviderHandler
 	if services.OAuthProvider != nil {
-		oauthProviderHandler = handler.NewOAuthProviderHandler(services.OAuthProvider, deps.log)
+		oauthProviderHandler = handler.NewOAuthProviderHandler(services.OAuthProvider, deps.cfg.OIDC.StateSecret, deps.log)
 	}
 	oauthAdminHandler := handler.NewOAuthAdminHandler(services.MinimalOAuthSvc, deps.log)
+	connectedAppHandler := handler.NewConnectedAppHandler(services.MinimalOAuthSvc, deps.log)
+	oauthClientLogoHandler := handler.NewOAuthClientLogoHandler(services.OAuthClientLogo, deps.log)
+	clientWebhookHandler := handler.NewOAuthClientWebhookHandler(services.ClientWebhook, deps.log)
+
+	var oidcConformanceHandler *handler.OIDCConformanceHandler
+	if services.OIDCConformance != nil {
+		oidcConformanceHandler = handler.NewOIDCConformanceHandler(services.OIDCConformance, deps.log)
+	}
 
 	loginHandler := handler.NewLoginHandler(services.Auth, services.OTP, deps.jwtService, deps.log, secureCookie)
+	if oauthProviderHandler != nil {
+		oauthProviderHandler.SetSessionTerminator(loginHandler)
+	}
 	groupHandler := handler.NewGroupHandler(services.Group, deps.log)
 	ldapHandler := handler.NewLDAPHandler(services.LDAP, deps.log)
 	bulkHandler := handler.NewBulkHandler(services.Bulk, deps.log)
 	scimHandler := handler.NewSCIMHandler(services.SCIM, deps.log)
 	samlHandler := handler.NewSAMLHandler(services.SAML, deps.log)
 	tokenHandler := handler.NewTokenHandler(deps.jwtService, services.APIKey, deps.redis, deps.log)
+	tokenHandler.SetTokenVersionService(services.TokenVersion)
+	tokenHandler.SetBlacklistFilter(services.Blacklist.Filter())
 	emailProfileHandler := handler.NewEmailProfileHandler(services.EmailProfile, deps.log)
 	applicationHandler := handler.NewApplicationHandler(services.Application, deps.log)
 	appOAuthProviderHandler := handler.NewAppOAuthProviderHandler(services.AppOAuthProvider, deps.log)
@@ -665,10 +1427,64 @@ func buildHandlers(deps *infra, repos *repoSet, services *serviceSet) *handlerSe
 	migrationHandler := handler.NewMigrationHandler(services.Migration, deps.log)
 	tokenExchangeHandler := handler.NewTokenExchangeHandler(services.TokenExchange)
 	smsSettingsHandler := handler.NewSMSSettingsHandler(repos.SMSSettings, deps.log)
+	tokenVersionHandler := handler.NewTokenVersionHandler(services.TokenVersion, services.Session, services.Audit, deps.log)
+	passwordExpiryHandler := handler.NewPasswordExpiryHandler(services.PasswordExpiry, services.Audit, deps.log)
+	accountRecoveryHandler := handler.NewAccountRecoveryHandler(services.AccountRecovery, services.Audit, deps.log)
+	userEmailHandler := handler.NewUserEmailHandler(services.UserEmail, deps.log)
+
+	var chaosHandler *handler.ChaosHandler
+	if deps.faults != nil {
+		chaosHandler = handler.NewChaosHandler(deps.faults, services.Audit, deps.log)
+	}
+
+	var credentialsHandler *handler.CredentialsHandler
+	if services.Credentials != nil {
+		credentialsHandler = handler.NewCredentialsHandler(services.Credentials, services.Audit, deps.log)
+	}
+
+	var riskPolicyHandler *handler.RiskPolicyHandler
+	if services.Risk != nil {
+		riskPolicyHandler = handler.NewRiskPolicyHandler(services.Risk, services.Audit, deps.log)
+	}
+
+	var accountLockoutHandler *handler.AccountLockoutHandler
+	if services.AccountLockout != nil {
+		accountLockoutHandler = handler.NewAccountLockoutHandler(services.AccountLockout, services.Audit, deps.log)
+	}
+
+	var webauthnHandler *handler.WebAuthnHandler
+	if services.WebAuthn != nil {
+		webauthnHandler = handler.NewWebAuthnHandler(services.TwoFA, services.Auth, services.Audit, deps.log)
+	}
+
+	var smsDeliveryHandler *handler.SMSDeliveryHandler
+	if services.SMSDelivery != nil {
+		smsDeliveryHandler = handler.NewSMSDeliveryHandler(services.SMSDelivery, deps.smsProvider, deps.cfg.SMS.StatusCallbackToken, deps.log)
+	}
+
+	var magicLinkHandler *handler.MagicLinkHandler
+	if services.MagicLink != nil {
+		magicLinkHandler = handler.NewMagicLinkHandler(services.MagicLink, deps.log)
+	}
+
+	var signedURLHandler *handler.SignedURLHandler
+	if services.SignedURL != nil {
+		signedURLHandler = handler.NewSignedURLHandler(services.SignedURL, deps.log)
+	}
+
+	var signingKeyHandler *handler.SigningKeyHandler
+	if deps.keyManager != nil {
+		signingKeyHandler = handler.NewSigningKeyHandler(deps.keyManager, services.Audit, deps.log)
+	}
 
 	return &handlerSet{
 		Auth:             authHandler,
 		Health:           healthHandler,
+		Status:           statusHandler,
+		Version:          versionHandler,
+		SLO:              sloHandler,
+		AuditExport:      auditExportHandler,
+		DataExport:       dataExportHandler,
 		APIKey:           apiKeyHandler,
 		OTP:              otpHandler,
 		OAuth:            oauthHandler,
@@ -679,8 +1495,23 @@ func buildHandlers(deps *infra, repos *repoSet, services *serviceSet) *handlerSe
 		Template:         templateHandler,
 		OAuthProvider:    oauthProviderHandler,
 		OAuthAdmin:       oauthAdminHandler,
+		OIDCConformance:  oidcConformanceHandler,
+		ConnectedApp:     connectedAppHandler,
+		OAuthClientLogo:  oauthClientLogoHandler,
+		ClientWebhook:    clientWebhookHandler,
+		PermCatalog:      handler.NewPermissionCatalogHandler(services.PermCatalog, deps.log),
+		BulkRoleJob:      handler.NewBulkRoleJobHandler(services.BulkRoleJob, deps.log),
+		BackgroundJob:    handler.NewBackgroundJobHandler(services.JobQueue, deps.log),
 		Login:            loginHandler,
 		Group:            groupHandler,
+		OrgAdmin:         handler.NewOrgAdminHandler(services.OrgAdmin, deps.log),
+		UsageReport:      handler.NewUsageReportHandler(services.UsageReport, deps.log),
+		SignupPolicy:     handler.NewSignupPolicyHandler(services.SignupPolicy, services.Audit, deps.log),
+		DormantAccount:   handler.NewDormantAccountHandler(services.DormantAccount, services.Audit, deps.log),
+		Retention:        handler.NewRetentionHandler(services.Retention, deps.log),
+		PasswordPolicy:   handler.NewPasswordPolicyHandler(services.PasswordPolicy, services.Audit, deps.log),
+		UserTimeline:     handler.NewUserTimelineHandler(services.UserTimeline, deps.log),
+		LogLevel:         handler.NewLogLevelHandler(services.LogLevel, services.Audit, deps.log),
 		SCIM:             scimHandler,
 		LDAP:             ldapHandler,
 		Bulk:             bulkHandler,
@@ -693,6 +1524,20 @@ func buildHandlers(deps *infra, repos *repoSet, services *serviceSet) *handlerSe
 		Migration:        migrationHandler,
 		TokenExchange:    tokenExchangeHandler,
 		SMSSettings:      smsSettingsHandler,
+		TokenVersion:     tokenVersionHandler,
+		PasswordExpiry:   passwordExpiryHandler,
+		AccountRecovery:  accountRecoveryHandler,
+		UserEmail:        userEmailHandler,
+		Guest:            handler.NewGuestHandler(services.Guest, services.OTP, deps.log),
+		Chaos:            chaosHandler,
+		Credentials:      credentialsHandler,
+		SignedURL:        signedURLHandler,
+		SigningKey:       signingKeyHandler,
+		RiskPolicy:       riskPolicyHandler,
+		AccountLockout:   accountLockoutHandler,
+		WebAuthn:         webauthnHandler,
+		SMSDelivery:      smsDeliveryHandler,
+		MagicLink:        magicLinkHandler,
 	}
 }
 
@@ -700,20 +1545,27 @@ func buildMiddlewares(deps *infra, repos *repoSet, services *serviceSet) *middle
 	authMiddleware := middleware.NewAuthMiddleware(deps.jwtService, services.Blacklist)
 	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(services.APIKey, services.Application, repos.RBAC)
 	authMiddleware.SetAPIKeyMiddleware(apiKeyMiddleware)
-	rateLimitMiddleware := middleware.NewRateLimitMiddleware(deps.redis, &deps.cfg.RateLimit)
+	authMiddleware.SetTokenVersionService(services.TokenVersion)
+	authMiddleware.SetRequireVerifiedEmail(services.EmailVerify.Enforced())
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(deps.redis, &deps.cfg.RateLimit, deps.log)
 	ipFilterMiddleware := middleware.NewIPFilterMiddleware(services.IPFilter)
 	maintenanceMiddleware := middleware.NewMaintenanceMiddleware(repos.System)
 	applicationMiddleware := middleware.NewApplicationMiddleware(services.Application, services.Application, deps.log)
 	appSecretMiddleware := middleware.NewAppSecretMiddleware(services.Application)
+	orgAdminMiddleware := middleware.NewOrgAdminMiddleware(services.OrgAdmin)
 
 	return &middlewareSet{
 		Auth:        authMiddleware,
 		APIKey:      apiKeyMiddleware,
 		RateLimit:   rateLimitMiddleware,
+		CORS:        middleware.NewCORSMiddleware(&deps.cfg.CORS),
 		IPFilter:    ipFilterMiddleware,
 		Maintenance: maintenanceMiddleware,
 		Application: applicationMiddleware,
 		AppSecret:   appSecretMiddleware,
+		OrgAdmin:    orgAdminMiddleware,
+		RBAC:        middleware.NewRBACMiddleware(services.RBAC),
+		Idempotency: middleware.NewIdempotencyMiddleware(deps.redis, deps.cfg.Security.IdempotencyKeyTTL),
 	}
 }
 
@@ -731,8 +1583,15 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		router.SetTrustedProxies(nil)
 	}
 
-	if deps.cfg.OIDC.Enabled {
-		tmpl, err := template.ParseGlob("internal/templates/*.html")
+	assets, err := templates.NewPipeline()
+	if err != nil {
+		deps.log.Warn("Failed to load embedded assets", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if deps.cfg.OIDC.Enabled && assets != nil {
+		tmpl, err := assets.Templates()
 		if err != nil {
 			deps.log.Warn("Failed to load OAuth templates", map[string]interface{}{
 				"error": err.Error(),
@@ -743,9 +1602,17 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		}
 	}
 
-	router.Use(middleware.Recovery(deps.log))
-	router.Use(middleware.Logger(deps.log))
-	router.Use(middleware.SetupCORS(&deps.cfg.CORS))
+	router.Use(middleware.RequestID())
+	if deps.cfg.Tracing.Enabled {
+		router.Use(tracing.Middleware(deps.cfg.Tracing.ServiceName)...)
+	}
+	router.Use(middleware.Recovery(deps.log.Module("http")))
+	if deps.accessLog != nil {
+		router.Use(middleware.AccessLog(deps.accessLog))
+	} else {
+		router.Use(middleware.Logger(deps.log.Module("http")))
+	}
+	router.Use(middlewares.CORS.Handler())
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.CSRFProtection(deps.cfg.Security.CSRFEnabled, deps.cfg.Server.Env == "production"))
 	router.Use(middlewares.Maintenance.CheckMaintenance())
@@ -757,6 +1624,9 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		router.Use(middleware.MetricsMiddleware())
 		router.Use(middleware.MetricsErrorMiddleware())
 	}
+	if services.SLO != nil {
+		router.Use(middleware.SLOTracking(services.SLO))
+	}
 
 	router.GET("/api/swagger.json", func(c *gin.Context) {
 		c.File("docs/swagger.json")
@@ -765,9 +1635,16 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		ginSwagger.URL("/api/swagger.json"),
 	))
 
+	if assets != nil {
+		router.GET(templates.AssetPathPrefix+"*filepath", gin.WrapH(assets))
+		router.HEAD(templates.AssetPathPrefix+"*filepath", gin.WrapH(assets))
+	}
+
 	router.GET("/health", handlers.Health.Health)
 	router.GET("/ready", handlers.Health.Readiness)
 	router.GET("/live", handlers.Health.Liveness)
+	router.GET("/status", handlers.Status.Status)
+	router.GET("/version", handlers.Version.Version)
 	router.GET("/system/maintenance", handlers.AdvancedAdmin.GetMaintenanceMode)
 
 	// Metrics endpoint (if enabled)
@@ -789,7 +1666,7 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 	// SCIM 2.0 API endpoints
 	if handlers.SCIM != nil {
 		scimGroup := router.Group("/scim/v2")
-		scimGroup.Use(middlewares.Auth.Authenticate()) // Require authentication
+		scimGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser()) // Require authentication
 		{
 			// Users endpoints
 			scimGroup.GET("/Users", handlers.SCIM.GetUsers)
@@ -802,6 +1679,10 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 			// Groups endpoints
 			scimGroup.GET("/Groups", handlers.SCIM.GetGroups)
 			scimGroup.GET("/Groups/:id", handlers.SCIM.GetGroup)
+			scimGroup.POST("/Groups", handlers.SCIM.CreateGroup)
+			scimGroup.PUT("/Groups/:id", handlers.SCIM.ReplaceGroup)
+			scimGroup.PATCH("/Groups/:id", handlers.SCIM.PatchGroup)
+			scimGroup.DELETE("/Groups/:id", handlers.SCIM.DeleteGroup)
 
 			// Service Provider Config
 			scimGroup.GET("/ServiceProviderConfig", handlers.SCIM.GetServiceProviderConfig)
@@ -821,23 +1702,34 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		loginGroup.GET("/logout", handlers.Login.Logout)
 	}
 
+	// Client logos stay reachable when the OIDC provider is off, e.g. for connected apps
+	router.GET(models.ClientLogoPath+":name", handlers.OAuthClientLogo.Serve)
+
 	if handlers.OAuthProvider != nil {
 		router.GET("/.well-known/openid-configuration", handlers.OAuthProvider.Discovery)
 		router.GET("/.well-known/jwks.json", handlers.OAuthProvider.JWKS)
 
+		// Tokens of clients using certificate-bound access tokens are bound to the client certificate,
+		// and are only accepted at userinfo with that certificate
+		clientCert := middleware.ClientCertificate(deps.cfg.MTLS.ClientCertHeader, deps.cfg.Server.TrustedProxies, deps.log)
+
+		limitClient := middlewares.RateLimit.LimitClient()
+
 		oauth := router.Group("/oauth")
 		{
 			oauth.GET("/authorize", handlers.OAuthProvider.Authorize)
-			oauth.POST("/token", handlers.OAuthProvider.Token)
-			oauth.POST("/introspect", handlers.OAuthProvider.Introspect)
-			oauth.POST("/revoke", handlers.OAuthProvider.Revoke)
-			oauth.GET("/userinfo", handlers.OAuthProvider.UserInfo)
-			oauth.POST("/device/code", handlers.OAuthProvider.DeviceCode)
-			oauth.POST("/device/token", handlers.OAuthProvider.DeviceToken)
+			oauth.POST("/token", middlewares.RateLimit.LimitToken(), clientCert, handlers.OAuthProvider.Token)
+			oauth.POST("/introspect", limitClient, handlers.OAuthProvider.Introspect)
+			oauth.POST("/revoke", limitClient, handlers.OAuthProvider.Revoke)
+			oauth.GET("/userinfo", clientCert, handlers.OAuthProvider.UserInfo)
+			oauth.POST("/device/code", limitClient, handlers.OAuthProvider.DeviceCode)
+			oauth.POST("/device/token", limitClient, clientCert, handlers.OAuthProvider.DeviceToken)
 			oauth.GET("/device", handlers.OAuthProvider.DeviceVerification)
 			oauth.POST("/device/approve", handlers.Login.SessionMiddleware(), handlers.OAuthProvider.DeviceApprove)
 			oauth.GET("/consent", handlers.Login.SessionMiddleware(), handlers.OAuthProvider.ConsentPage)
 			oauth.POST("/consent", handlers.Login.SessionMiddleware(), handlers.OAuthProvider.ConsentSubmit)
+			oauth.GET("/logout", handlers.Login.SessionMiddleware(), handlers.OAuthProvider.EndSession)
+			oauth.POST("/logout", handlers.Login.SessionMiddleware(), handlers.OAuthProvider.EndSession)
 		}
 	}
 
@@ -866,9 +1758,36 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 			authGroup.POST("/verify/email", handlers.OTP.VerifyEmailOTP)
 			authGroup.POST("/password/reset/request", handlers.Auth.RequestPasswordReset)
 			authGroup.POST("/password/reset/complete", handlers.Auth.ResetPassword)
+			authGroup.POST("/password/change-required", handlers.Auth.CompleteRequiredPasswordChange)
 			authGroup.POST("/2fa/login/verify", handlers.Auth.Verify2FA)
+			if handlers.WebAuthn != nil {
+				authGroup.POST("/2fa/webauthn/login/begin", handlers.WebAuthn.BeginLogin)
+				authGroup.POST("/2fa/webauthn/login/finish", handlers.WebAuthn.FinishLogin)
+			}
+			authGroup.POST("/recovery/start", middlewares.RateLimit.LimitSignin(), handlers.AccountRecovery.Start)
+			authGroup.POST("/recovery/:id/verify", handlers.AccountRecovery.VerifyStep)
+			authGroup.POST("/recovery/:id/status", handlers.AccountRecovery.GetStatus)
+			authGroup.POST("/recovery/:id/complete", handlers.AccountRecovery.Complete)
 			authGroup.POST("/token/exchange", handlers.TokenExchange.CreateExchange)
 			authGroup.POST("/token/exchange/redeem", handlers.TokenExchange.RedeemExchange)
+			if deps.cfg.Security.GuestSessions.Enabled {
+				authGroup.POST("/guest", middlewares.RateLimit.LimitSignup(), handlers.Guest.CreateGuestSession)
+			}
+			if handlers.MagicLink != nil {
+				authGroup.POST("/magic-link/request", middlewares.RateLimit.LimitSignin(), handlers.MagicLink.RequestMagicLink)
+				authGroup.GET("/magic-link/verify", middlewares.RateLimit.LimitSignin(), handlers.MagicLink.VerifyMagicLink)
+				authGroup.POST("/magic-link/complete", handlers.MagicLink.CompleteMagicLink)
+			}
+			// Authorized by the signature of the download link
+			if handlers.DataExport != nil {
+				authGroup.GET("/me/export/:id/download", handlers.DataExport.Download)
+			}
+		}
+
+		// Delivery status callbacks from the SMS provider, authenticated by the callback token
+		if handlers.SMSDelivery != nil {
+			apiGroup.GET("/sms/status/:provider", handlers.SMSDelivery.StatusCallback)
+			apiGroup.POST("/sms/status/:provider", handlers.SMSDelivery.StatusCallback)
 		}
 
 		otpGroup := apiGroup.Group("/otp")
@@ -895,27 +1814,52 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 			oauthGroup.GET("/providers", handlers.OAuth.GetProviders)
 			oauthGroup.GET("/:provider", handlers.OAuth.Login)
 			oauthGroup.GET("/:provider/callback", handlers.OAuth.Callback)
+			// Sign in with Apple posts the callback
+			oauthGroup.POST("/:provider/callback", handlers.OAuth.Callback)
 			oauthGroup.POST("/telegram/callback", handlers.OAuth.TelegramCallback)
+			oauthGroup.POST("/oauth/link/confirm", middlewares.RateLimit.LimitSignin(), handlers.OAuth.ConfirmLink)
 		}
 
 		protectedAuth := apiGroup.Group("/auth")
-		protectedAuth.Use(middlewares.Auth.Authenticate())
+		protectedAuth.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
 		{
 			protectedAuth.POST("/logout", handlers.Auth.Logout)
 			protectedAuth.GET("/profile", handlers.Auth.GetProfile)
 			protectedAuth.PUT("/profile", handlers.Auth.UpdateProfile)
+			protectedAuth.GET("/:provider/token", handlers.OAuth.GetProviderToken)
 			protectedAuth.POST("/change-password", handlers.Auth.ChangePassword)
 			protectedAuth.POST("/2fa/setup", handlers.TwoFA.Setup)
 			protectedAuth.POST("/2fa/verify", handlers.TwoFA.Verify)
 			protectedAuth.POST("/2fa/disable", handlers.TwoFA.Disable)
 			protectedAuth.GET("/2fa/status", handlers.TwoFA.GetStatus)
 			protectedAuth.POST("/2fa/backup-codes/regenerate", handlers.TwoFA.RegenerateBackupCodes)
+			if handlers.WebAuthn != nil {
+				protectedAuth.POST("/2fa/webauthn/register/begin", handlers.WebAuthn.BeginRegistration)
+				protectedAuth.POST("/2fa/webauthn/register/finish", handlers.WebAuthn.FinishRegistration)
+				protectedAuth.GET("/2fa/webauthn/credentials", handlers.WebAuthn.ListCredentials)
+				protectedAuth.DELETE("/2fa/webauthn/credentials/:id", handlers.WebAuthn.DeleteCredential)
+			}
+			protectedAuth.GET("/emails", handlers.UserEmail.List)
+			protectedAuth.POST("/emails", handlers.UserEmail.Add)
+			protectedAuth.POST("/emails/verify", handlers.UserEmail.Verify)
+			protectedAuth.DELETE("/emails/:id", handlers.UserEmail.Remove)
+			protectedAuth.POST("/emails/:id/primary", handlers.UserEmail.SetPrimary)
+			protectedAuth.PUT("/recovery-email", handlers.UserEmail.SetRecoveryEmail)
+			protectedAuth.DELETE("/recovery-email", handlers.UserEmail.ClearRecoveryEmail)
+			protectedAuth.GET("/connected-apps", handlers.ConnectedApp.List)
+			protectedAuth.DELETE("/connected-apps/:id", handlers.ConnectedApp.Revoke)
+			if handlers.DataExport != nil {
+				protectedAuth.GET("/me/export", handlers.DataExport.RequestExport)
+			}
+			if deps.cfg.Security.GuestSessions.Enabled {
+				protectedAuth.POST("/guest/upgrade", handlers.Guest.UpgradeGuest)
+			}
 		}
 
 		apiKeysGroup := apiGroup.Group("/api-keys")
-		apiKeysGroup.Use(middlewares.Auth.Authenticate())
+		apiKeysGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
 		{
-			apiKeysGroup.POST("", handlers.APIKey.Create)
+			apiKeysGroup.POST("", middlewares.Idempotency.IdempotentWithoutReplay(), handlers.APIKey.Create)
 			apiKeysGroup.GET("", handlers.APIKey.List)
 			apiKeysGroup.GET("/:id", handlers.APIKey.Get)
 			apiKeysGroup.PUT("/:id", handlers.APIKey.Update)
@@ -925,37 +1869,152 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 
 		// User Application Profile (requires auth)
 		userAppsGroup := apiGroup.Group("/applications")
-		userAppsGroup.Use(middlewares.Auth.Authenticate())
+		userAppsGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
 		{
 			userAppsGroup.GET("/:id/profile", handlers.Application.GetMyProfile)
 			userAppsGroup.PUT("/:id/profile", handlers.Application.UpdateMyProfile)
 		}
 
+		// Webhooks OAuth client owners manage for their own clients
+		clientWebhooksGroup := apiGroup.Group("/oauth-clients/:id/webhooks")
+		clientWebhooksGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
+		{
+			clientWebhooksGroup.GET("", handlers.ClientWebhook.List)
+			clientWebhooksGroup.POST("", handlers.ClientWebhook.Create)
+			clientWebhooksGroup.GET("/:webhook_id", handlers.ClientWebhook.Get)
+			clientWebhooksGroup.PUT("/:webhook_id", handlers.ClientWebhook.Update)
+			clientWebhooksGroup.DELETE("/:webhook_id", handlers.ClientWebhook.Delete)
+			clientWebhooksGroup.GET("/:webhook_id/deliveries", handlers.ClientWebhook.ListDeliveries)
+		}
+
+		// Organization-scoped admin API (organizations are groups)
+		orgsGroup := apiGroup.Group("/orgs")
+		orgsGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
+		{
+			orgsGroup.GET("", handlers.OrgAdmin.ListMyOrgs)
+
+			orgGroup := orgsGroup.Group("/:id")
+			orgGroup.Use(middlewares.OrgAdmin.RequireOrgAdmin("id"))
+			{
+				orgGroup.GET("/users", handlers.OrgAdmin.ListUsers)
+				orgGroup.POST("/users", middlewares.Idempotency.Idempotent(), handlers.OrgAdmin.CreateUser)
+				orgGroup.GET("/users/:user_id", handlers.OrgAdmin.GetUser)
+				orgGroup.PUT("/users/:user_id", handlers.OrgAdmin.UpdateUser)
+				orgGroup.DELETE("/users/:user_id", handlers.OrgAdmin.RemoveUser)
+				orgGroup.POST("/users/:user_id/roles", handlers.OrgAdmin.AssignRole)
+				orgGroup.DELETE("/users/:user_id/roles/:role_id", handlers.OrgAdmin.RemoveRole)
+				orgGroup.GET("/roles", handlers.OrgAdmin.ListRoles)
+				orgGroup.GET("/api-keys", handlers.OrgAdmin.ListAPIKeys)
+				orgGroup.POST("/api-keys/:key_id/revoke", handlers.OrgAdmin.RevokeAPIKey)
+				orgGroup.GET("/usage-report", handlers.UsageReport.GetOrgUsageReport)
+			}
+		}
+
+		// Short-lived downstream credentials (requires auth and the target's permission)
+		if handlers.Credentials != nil {
+			credentialsGroup := apiGroup.Group("/credentials")
+			credentialsGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
+			{
+				credentialsGroup.GET("", handlers.Credentials.ListAvailableTargets)
+				credentialsGroup.POST("/:target", handlers.Credentials.IssueCredentials)
+			}
+		}
+
+		// Signed URLs to protected resources (requires auth and every requested scope)
+		if handlers.SignedURL != nil {
+			apiGroup.POST("/signed-urls", middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser(), handlers.SignedURL.CreateSignedURL)
+		}
+
 		// API key only endpoints (placed before admin group)
 		apiGroup.GET("/admin/users/sync", middlewares.APIKey.Authenticate(), middlewares.APIKey.RequireScope(models.ScopeSyncUsers), handlers.Admin.SyncUsers)
 		apiGroup.POST("/admin/users/import", middlewares.APIKey.Authenticate(), middlewares.APIKey.RequireScope(models.ScopeImportUsers), handlers.Admin.ImportUsers)
 
-		adminGroup := apiGroup.Group("/admin")
-		adminGroup.Use(middlewares.Auth.Authenticate())
+		adminBase := apiGroup.Group("/admin")
+		adminBase.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
+
+		// Delegated admin API: admins and users holding the delegated admin permission of
+		// a group reach it. Delegated admins can't assign roles or change admin accounts.
+		usersAdmin := adminBase.Group("/users")
+		usersAdmin.Use(middlewares.RBAC.RequireAdminOrPermission(models.PermissionUsersManage), middlewares.RBAC.ProtectAdminUsers())
+		{
+			usersAdmin.GET("", handlers.Admin.ListUsers)
+			usersAdmin.POST("", middlewares.Idempotency.Idempotent(), handlers.Admin.CreateUser)
+			usersAdmin.GET("/:id", handlers.Admin.GetUser)
+			usersAdmin.PUT("/:id", handlers.Admin.UpdateUser)
+			usersAdmin.DELETE("/:id", handlers.Admin.DeleteUser)
+			usersAdmin.POST("/:id/state", handlers.Admin.UpdateUserState)
+			usersAdmin.POST("/:id/send-password-reset", handlers.Admin.SendPasswordReset)
+			usersAdmin.GET("/:id/oauth-accounts", handlers.Admin.GetUserOAuthAccounts)
+			usersAdmin.POST("/:id/reset-2fa", handlers.Admin.Reset2FA)
+			usersAdmin.POST("/:id/revoke-tokens", handlers.TokenVersion.RevokeUserTokens)
+			usersAdmin.POST("/:id/require-password-change", handlers.PasswordExpiry.RequirePasswordChange)
+			usersAdmin.GET("/password-expirations", handlers.PasswordExpiry.ListUpcomingExpirations)
+			if handlers.AccountLockout != nil {
+				usersAdmin.GET("/locked", handlers.AccountLockout.ListLocked)
+				usersAdmin.GET("/:id/lockout", handlers.AccountLockout.GetLockout)
+				usersAdmin.DELETE("/:id/lockout", handlers.AccountLockout.Unlock)
+			}
+			usersAdmin.GET("/:id/telegram-accounts", handlers.Telegram.ListUserTelegramAccounts)
+			usersAdmin.GET("/:id/telegram-bot-access", handlers.Telegram.ListUserTelegramBotAccess)
+			usersAdmin.GET("/:id/sessions", handlers.AdvancedAdmin.ListUserSessionsAdmin)
+			usersAdmin.GET("/:id/timeline", handlers.UserTimeline.GetUserTimeline)
+		}
+
+		adminBase.GET("/audit-logs", middlewares.RBAC.RequireAdminOrPermission(models.PermissionAuditRead), handlers.Admin.ListAuditLogs)
+		adminBase.GET("/audit-logs/stats/actions", middlewares.RBAC.RequireAdminOrPermission(models.PermissionAuditRead), handlers.Admin.GetAuditActionStats)
+		adminBase.GET("/audit-logs/stats/failures", middlewares.RBAC.RequireAdminOrPermission(models.PermissionAuditRead), handlers.Admin.GetAuditFailureStats)
+
+		webhooksGroup := adminBase.Group("/webhooks")
+		webhooksGroup.Use(middlewares.RBAC.RequireAdminOrPermission(models.PermissionWebhooksManage))
+		{
+			webhooksGroup.GET("", handlers.Webhook.ListWebhooks)
+			webhooksGroup.POST("", handlers.Webhook.CreateWebhook)
+			webhooksGroup.GET("/events", handlers.Webhook.GetAvailableEvents)
+			webhooksGroup.GET("/:id", handlers.Webhook.GetWebhook)
+			webhooksGroup.PUT("/:id", handlers.Webhook.UpdateWebhook)
+			webhooksGroup.DELETE("/:id", handlers.Webhook.DeleteWebhook)
+			webhooksGroup.POST("/:id/test", middlewares.Idempotency.Idempotent(), handlers.Webhook.TestWebhook)
+			webhooksGroup.GET("/:id/deliveries", handlers.Webhook.ListWebhookDeliveries)
+			webhooksGroup.POST("/:id/deliveries/:deliveryId/redeliver", handlers.Webhook.RedeliverWebhookDelivery)
+		}
+
+		oauthClientsAdmin := adminBase.Group("/oauth/clients")
+		oauthClientsAdmin.Use(middlewares.RBAC.RequireAdminOrPermission(models.PermissionOAuthClientsManage))
+		{
+			oauthClientsAdmin.POST("", middlewares.Idempotency.IdempotentWithoutReplay(), handlers.OAuthAdmin.CreateClient)
+			oauthClientsAdmin.GET("", handlers.OAuthAdmin.ListClients)
+			oauthClientsAdmin.GET("/:id", handlers.OAuthAdmin.GetClient)
+			oauthClientsAdmin.PUT("/:id", handlers.OAuthAdmin.UpdateClient)
+			oauthClientsAdmin.DELETE("/:id", handlers.OAuthAdmin.DeleteClient)
+			oauthClientsAdmin.POST("/:id/rotate-secret", handlers.OAuthAdmin.RotateSecret)
+			oauthClientsAdmin.POST("/:id/emulate", handlers.OAuthAdmin.EmulateFlow)
+			oauthClientsAdmin.PUT("/:id/logo", handlers.OAuthClientLogo.Upload)
+			oauthClientsAdmin.DELETE("/:id/logo", handlers.OAuthClientLogo.Delete)
+			oauthClientsAdmin.GET("/:id/consents", handlers.OAuthAdmin.ListClientConsents)
+			oauthClientsAdmin.DELETE("/:id/consents/:user_id", handlers.OAuthAdmin.RevokeUserConsent)
+		}
+
+		adminGroup := adminBase.Group("")
 		adminGroup.Use(middleware.RequireAdmin())
 		{
 			adminGroup.GET("/stats", handlers.Admin.GetStats)
-			adminGroup.GET("/users", handlers.Admin.ListUsers)
-			adminGroup.POST("/users", handlers.Admin.CreateUser)
-			adminGroup.GET("/users/:id", handlers.Admin.GetUser)
-			adminGroup.PUT("/users/:id", handlers.Admin.UpdateUser)
-			adminGroup.DELETE("/users/:id", handlers.Admin.DeleteUser)
 			adminGroup.POST("/users/:id/roles", handlers.Admin.AssignRole)
 			adminGroup.DELETE("/users/:id/roles/:roleId", handlers.Admin.RemoveRole)
-			adminGroup.POST("/users/:id/send-password-reset", handlers.Admin.SendPasswordReset)
-			adminGroup.GET("/users/:id/oauth-accounts", handlers.Admin.GetUserOAuthAccounts)
-			adminGroup.POST("/users/:id/reset-2fa", handlers.Admin.Reset2FA)
-			adminGroup.GET("/users/:id/telegram-accounts", handlers.Telegram.ListUserTelegramAccounts)
-			adminGroup.GET("/users/:id/telegram-bot-access", handlers.Telegram.ListUserTelegramBotAccess)
-			adminGroup.GET("/users/:id/sessions", handlers.AdvancedAdmin.ListUserSessionsAdmin)
+			adminGroup.DELETE("/users/:id/gdpr-erase", handlers.Retention.EraseUser)
+
+			// Organization usage and seat reports
+			adminGroup.GET("/usage-reports", handlers.UsageReport.ListUsageReports)
+
+			// Account recovery review queue
+			adminGroup.GET("/recovery-requests", handlers.AccountRecovery.ListRequests)
+			adminGroup.GET("/recovery-requests/:id", handlers.AccountRecovery.GetRequest)
+			adminGroup.POST("/recovery-requests/:id/approve", handlers.AccountRecovery.ApproveRequest)
+			adminGroup.POST("/recovery-requests/:id/reject", handlers.AccountRecovery.RejectRequest)
 			adminGroup.GET("/api-keys", handlers.Admin.ListAPIKeys)
 			adminGroup.POST("/api-keys/:id/revoke", handlers.Admin.RevokeAPIKey)
-			adminGroup.GET("/audit-logs", handlers.Admin.ListAuditLogs)
+
+			// Token claim debugging
+			adminGroup.POST("/debug/token-preview", handlers.OAuthAdmin.PreviewToken)
 
 			rbacGroup := adminGroup.Group("/rbac")
 			{
@@ -969,7 +2028,16 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 				rbacGroup.GET("/roles/:id", handlers.AdvancedAdmin.GetRole)
 				rbacGroup.PUT("/roles/:id", handlers.AdvancedAdmin.UpdateRole)
 				rbacGroup.DELETE("/roles/:id", handlers.AdvancedAdmin.DeleteRole)
+				rbacGroup.PUT("/roles/:id/password-policy", handlers.PasswordExpiry.SetRolePasswordMaxAge)
+				rbacGroup.GET("/roles/:id/effective-permissions", handlers.AdvancedAdmin.GetEffectivePermissions)
+				rbacGroup.GET("/bundles", handlers.AdvancedAdmin.ListPermissionBundles)
+				rbacGroup.POST("/bundles", handlers.AdvancedAdmin.CreatePermissionBundle)
+				rbacGroup.GET("/bundles/:id", handlers.AdvancedAdmin.GetPermissionBundle)
+				rbacGroup.PUT("/bundles/:id", handlers.AdvancedAdmin.UpdatePermissionBundle)
+				rbacGroup.DELETE("/bundles/:id", handlers.AdvancedAdmin.DeletePermissionBundle)
 				rbacGroup.GET("/permission-matrix", handlers.AdvancedAdmin.GetPermissionMatrix)
+				rbacGroup.POST("/simulate", handlers.AdvancedAdmin.SimulatePermission)
+				rbacGroup.GET("/catalog", handlers.PermCatalog.GetCatalog)
 			}
 
 			adminGroup.GET("/sessions", handlers.AdvancedAdmin.ListAllSessions)
@@ -990,25 +2058,82 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 			{
 				systemGroup.PUT("/maintenance", handlers.AdvancedAdmin.SetMaintenanceMode)
 				systemGroup.GET("/health", handlers.AdvancedAdmin.GetSystemHealth)
+				systemGroup.GET("/token-epoch", handlers.TokenVersion.GetTokenEpoch)
+				systemGroup.POST("/token-epoch", handlers.TokenVersion.InvalidateAllTokens)
 				systemGroup.GET("/password-policy", handlers.AdvancedAdmin.GetPasswordPolicy)
 				systemGroup.PUT("/password-policy", handlers.AdvancedAdmin.UpdatePasswordPolicy)
+				systemGroup.GET("/password-policy/v2", handlers.PasswordPolicy.GetPasswordPolicy)
+				systemGroup.PUT("/password-policy/v2", handlers.PasswordPolicy.UpdatePasswordPolicy)
+				systemGroup.GET("/signup-policy", handlers.SignupPolicy.GetSignupPolicy)
+				systemGroup.PUT("/signup-policy", handlers.SignupPolicy.UpdateSignupPolicy)
+				systemGroup.GET("/dormant-account-policy", handlers.DormantAccount.GetDormantAccountPolicy)
+				systemGroup.PUT("/dormant-account-policy", handlers.DormantAccount.UpdateDormantAccountPolicy)
+				systemGroup.GET("/dormant-account-policy/report", handlers.DormantAccount.GetDormantAccountReport)
+				systemGroup.GET("/log-level", handlers.LogLevel.GetLogLevel)
+				systemGroup.PUT("/log-level", handlers.LogLevel.SetLogLevel)
+				systemGroup.DELETE("/log-level", handlers.LogLevel.ResetLogLevel)
 			}
 
-			analyticsGroup := adminGroup.Group("/analytics")
+			if handlers.SLO != nil {
+				adminGroup.GET("/slos", handlers.SLO.ListSLOs)
+			}
+
+			// Audit log export to SIEMs
+			if handlers.AuditExport != nil {
+				adminGroup.GET("/audit-export", handlers.AuditExport.GetStatus)
+				adminGroup.POST("/audit-export/replay", handlers.AuditExport.Replay)
+			}
+
+			// Background job queue
+			jobsGroup := adminGroup.Group("/jobs")
 			{
-				analyticsGroup.GET("/geo-distribution", handlers.AdvancedAdmin.GetGeoDistribution)
+				jobsGroup.GET("", handlers.BackgroundJob.ListJobs)
+				jobsGroup.GET("/stats", handlers.BackgroundJob.GetStats)
+				jobsGroup.GET("/:id", handlers.BackgroundJob.GetJob)
+				jobsGroup.POST("/:id/retry", handlers.BackgroundJob.RetryJob)
+			}
+
+			// Reads the middleware chains of the router when requested, after all routes are registered
+			authorizationMap := handler.NewAuthorizationMapHandler(router, grpcserver.MethodAuthorization(), deps.log)
+			adminGroup.GET("/authorization-map", authorizationMap.GetAuthorizationMap)
+
+			// Fault injection (only registered when CHAOS_ENABLED is set outside production)
+			if handlers.Chaos != nil {
+				chaosGroup := adminGroup.Group("/chaos")
+				{
+					chaosGroup.GET("/faults", handlers.Chaos.ListFaults)
+					chaosGroup.DELETE("/faults", handlers.Chaos.ClearFaults)
+					chaosGroup.PUT("/faults/:target", handlers.Chaos.SetFault)
+					chaosGroup.DELETE("/faults/:target", handlers.Chaos.ClearFault)
+				}
+			}
+
+			if handlers.Credentials != nil {
+				adminCredentialsGroup := adminGroup.Group("/credentials")
+				{
+					adminCredentialsGroup.GET("/targets", handlers.Credentials.ListTargets)
+					adminCredentialsGroup.POST("/targets", handlers.Credentials.CreateTarget)
+					adminCredentialsGroup.PUT("/targets/:id", handlers.Credentials.UpdateTarget)
+					adminCredentialsGroup.DELETE("/targets/:id", handlers.Credentials.DeleteTarget)
+					adminCredentialsGroup.GET("/issuances", handlers.Credentials.ListIssuances)
+					adminCredentialsGroup.DELETE("/issuances/:id", handlers.Credentials.RevokeIssuance)
+				}
 			}
 
-			webhooksGroup := adminGroup.Group("/webhooks")
+			if handlers.RiskPolicy != nil {
+				riskPoliciesGroup := adminGroup.Group("/risk/policies")
+				{
+					riskPoliciesGroup.GET("", handlers.RiskPolicy.ListPolicies)
+					riskPoliciesGroup.POST("", handlers.RiskPolicy.CreatePolicy)
+					riskPoliciesGroup.GET("/:id", handlers.RiskPolicy.GetPolicy)
+					riskPoliciesGroup.PUT("/:id", handlers.RiskPolicy.UpdatePolicy)
+					riskPoliciesGroup.DELETE("/:id", handlers.RiskPolicy.DeletePolicy)
+				}
+			}
+
+			analyticsGroup := adminGroup.Group("/analytics")
 			{
-				webhooksGroup.GET("", handlers.Webhook.ListWebhooks)
-				webhooksGroup.POST("", handlers.Webhook.CreateWebhook)
-				webhooksGroup.GET("/events", handlers.Webhook.GetAvailableEvents)
-				webhooksGroup.GET("/:id", handlers.Webhook.GetWebhook)
-				webhooksGroup.PUT("/:id", handlers.Webhook.UpdateWebhook)
-				webhooksGroup.DELETE("/:id", handlers.Webhook.DeleteWebhook)
-				webhooksGroup.POST("/:id/test", handlers.Webhook.TestWebhook)
-				webhooksGroup.GET("/:id/deliveries", handlers.Webhook.ListWebhookDeliveries)
+				analyticsGroup.GET("/geo-distribution", handlers.AdvancedAdmin.GetGeoDistribution)
 			}
 
 			templatesGroup := adminGroup.Group("/templates")
@@ -1025,25 +2150,29 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 
 			adminOAuth := adminGroup.Group("/oauth")
 			{
-				adminOAuth.POST("/clients", handlers.OAuthAdmin.CreateClient)
-				adminOAuth.GET("/clients", handlers.OAuthAdmin.ListClients)
-				adminOAuth.GET("/clients/:id", handlers.OAuthAdmin.GetClient)
-				adminOAuth.PUT("/clients/:id", handlers.OAuthAdmin.UpdateClient)
-				adminOAuth.DELETE("/clients/:id", handlers.OAuthAdmin.DeleteClient)
-				adminOAuth.POST("/clients/:id/rotate-secret", handlers.OAuthAdmin.RotateSecret)
-
 				adminOAuth.GET("/scopes", handlers.OAuthAdmin.ListScopes)
 				adminOAuth.POST("/scopes", handlers.OAuthAdmin.CreateScope)
 				adminOAuth.DELETE("/scopes/:id", handlers.OAuthAdmin.DeleteScope)
-
-				adminOAuth.GET("/clients/:id/consents", handlers.OAuthAdmin.ListClientConsents)
-				adminOAuth.DELETE("/clients/:id/consents/:user_id", handlers.OAuthAdmin.RevokeUserConsent)
+				adminOAuth.GET("/scope-groups", handlers.OAuthAdmin.ListScopeGroups)
+				adminOAuth.POST("/scope-groups", handlers.OAuthAdmin.CreateScopeGroup)
+				adminOAuth.PUT("/scope-groups/:id", handlers.OAuthAdmin.UpdateScopeGroup)
+				adminOAuth.DELETE("/scope-groups/:id", handlers.OAuthAdmin.DeleteScopeGroup)
 
 				adminOAuth.GET("/providers", handlers.AppOAuthProvider.ListProvidersAdmin)
 				adminOAuth.POST("/providers", handlers.AppOAuthProvider.CreateProviderAdmin)
 				adminOAuth.GET("/providers/:providerId", handlers.AppOAuthProvider.GetProviderAdmin)
 				adminOAuth.PUT("/providers/:providerId", handlers.AppOAuthProvider.UpdateProviderAdmin)
 				adminOAuth.DELETE("/providers/:providerId", handlers.AppOAuthProvider.DeleteProviderAdmin)
+
+				if handlers.OIDCConformance != nil {
+					adminOAuth.POST("/conformance", handlers.OIDCConformance.Run)
+				}
+
+				if handlers.SigningKey != nil {
+					adminOAuth.GET("/signing-keys", handlers.SigningKey.ListKeys)
+					adminOAuth.POST("/signing-keys/backup", handlers.SigningKey.ExportBackup)
+					adminOAuth.POST("/signing-keys/ceremonies", handlers.SigningKey.RecordCeremony)
+				}
 			}
 
 			groupsGroup := adminGroup.Group("/groups")
@@ -1056,6 +2185,20 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 				groupsGroup.GET("/:id/members", handlers.Group.GetGroupMembers)
 				groupsGroup.POST("/:id/members", handlers.Group.AddGroupMembers)
 				groupsGroup.DELETE("/:id/members/:user_id", handlers.Group.RemoveGroupMember)
+				groupsGroup.GET("/:id/roles", handlers.Group.GetGroupRoles)
+				groupsGroup.PUT("/:id/roles", handlers.Group.SetGroupRoles)
+				groupsGroup.GET("/:id/admins", handlers.OrgAdmin.ListGroupAdmins)
+				groupsGroup.POST("/:id/admins", handlers.OrgAdmin.AddGroupAdmin)
+				groupsGroup.DELETE("/:id/admins/:user_id", handlers.OrgAdmin.RemoveGroupAdmin)
+				groupsGroup.GET("/:id/usage-report", handlers.UsageReport.GetUsageReport)
+				groupsGroup.GET("/:id/usage-report/settings", handlers.UsageReport.GetUsageReportSettings)
+				groupsGroup.PUT("/:id/usage-report/settings", handlers.UsageReport.UpdateUsageReportSettings)
+				groupsGroup.POST("/:id/usage-report/send", handlers.UsageReport.SendUsageReport)
+				groupsGroup.GET("/:id/domains", handlers.SignupPolicy.ListGroupDomains)
+				groupsGroup.POST("/:id/domains", handlers.SignupPolicy.AddGroupDomain)
+				groupsGroup.POST("/:id/domains/:domain_id/verify", handlers.SignupPolicy.VerifyGroupDomain)
+				groupsGroup.DELETE("/:id/domains/:domain_id", handlers.SignupPolicy.RemoveGroupDomain)
+				groupsGroup.PUT("/:id/password-policy", handlers.PasswordExpiry.SetGroupPasswordMaxAge)
 			}
 
 			ldapGroup := adminGroup.Group("/ldap")
@@ -1077,6 +2220,9 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 				bulkGroup.PUT("/bulk-update", handlers.Bulk.BulkUpdateUsers)
 				bulkGroup.POST("/bulk-delete", handlers.Bulk.BulkDeleteUsers)
 				bulkGroup.POST("/bulk-assign-roles", handlers.Bulk.BulkAssignRoles)
+				bulkGroup.POST("/bulk-role-jobs", handlers.BulkRoleJob.StartJob)
+				bulkGroup.GET("/bulk-role-jobs", handlers.BulkRoleJob.ListJobs)
+				bulkGroup.GET("/bulk-role-jobs/:id", handlers.BulkRoleJob.GetJob)
 			}
 
 			samlGroup := adminGroup.Group("/saml")
@@ -1187,7 +2333,7 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		}
 
 		sessionsGroup := apiGroup.Group("/sessions")
-		sessionsGroup.Use(middlewares.Auth.Authenticate())
+		sessionsGroup.Use(middlewares.Auth.Authenticate(), middlewares.RateLimit.LimitUser())
 		{
 			sessionsGroup.GET("", handlers.AdvancedAdmin.ListUserSessions)
 			sessionsGroup.DELETE("/:id", handlers.AdvancedAdmin.RevokeSession)
@@ -1197,17 +2343,26 @@ func buildRouter(deps *infra, services *serviceSet, handlers *handlerSet, middle
 		v1 := apiGroup.Group("/v1")
 		{
 			v1.POST("/token/validate", handlers.Token.ValidateToken)
+			v1.GET("/token/jwks.json", handlers.Token.JWKS)
+			if handlers.SignedURL != nil {
+				v1.POST("/signed-urls/verify", handlers.SignedURL.VerifySignedURL)
+			}
 		}
 
 		protectedAPI := apiGroup.Group("/v1")
-		protectedAPI.Use(func(c *gin.Context) {
+		jwtAuth := middlewares.Auth.Authenticate()
+		apiKeyAuth := middlewares.APIKey.Authenticate()
+		protectedAPI.Use(authmap.Describe(func(c *gin.Context) {
 			authHeader := c.GetHeader("Authorization")
 			if authHeader != "" && !strings.HasPrefix(authHeader, "Bearer agw_") {
-				middlewares.Auth.Authenticate()(c)
+				jwtAuth(c)
 				return
 			}
-			middlewares.APIKey.Authenticate()(c)
-		})
+			apiKeyAuth(c)
+		}, models.AuthRequirement{
+			Kind:   models.AuthRequirementAuthentication,
+			Values: []string{models.CredentialJWT, models.CredentialAPIKey, models.CredentialAppSecret},
+		}))
 		{
 			protectedAPI.GET("/profile", handlers.Auth.GetProfile)
 		}
@@ -1271,16 +2426,50 @@ func buildKeyConfigs(oidcCfg *config.OIDCConfig) []keys.KeyConfig {
 	algorithm := keys.Algorithm(oidcCfg.SigningAlgorithm)
 
 	for _, cfg := range configs {
-		keyConfigs = append(keyConfigs, keys.KeyConfig{
+		keyConfig := keys.KeyConfig{
 			ID:             cfg.KID,
 			Algorithm:      algorithm,
 			PrivateKeyPath: cfg.KeyPath,
-		})
+		}
+		if cfg.KeyPEM != "" {
+			keyConfig.PrivateKeyPEM = []byte(cfg.KeyPEM)
+		}
+		keyConfigs = append(keyConfigs, keyConfig)
 	}
 
 	return keyConfigs
 }
 
+// buildJWTKeyManager returns the key manager for first-party token signing.
+// A dedicated key is used when JWT_SIGNING_KEY or JWT_SIGNING_KEY_PATH is set; otherwise the OIDC keys are reused.
+func buildJWTKeyManager(jwtCfg *config.JWTConfig, oidcKeyManager *keys.Manager) (*keys.Manager, error) {
+	if jwtCfg.SigningKeyPath == "" && jwtCfg.SigningKey == "" {
+		if oidcKeyManager == nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY or JWT_SIGNING_KEY_PATH is required for %s signing when OIDC keys are not configured", jwtCfg.SigningAlgorithm)
+		}
+		return oidcKeyManager, nil
+	}
+
+	kid := jwtCfg.SigningKeyID
+	if kid == "" {
+		kid = "first-party"
+	}
+
+	keyConfig := keys.KeyConfig{
+		ID:             kid,
+		Algorithm:      keys.Algorithm(jwtCfg.SigningAlgorithm),
+		PrivateKeyPath: jwtCfg.SigningKeyPath,
+	}
+	if jwtCfg.SigningKey != "" {
+		keyConfig.PrivateKeyPEM = []byte(jwtCfg.SigningKey)
+	}
+	keyManager, err := keys.NewManager([]keys.KeyConfig{keyConfig}, kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init JWT key manager: %w", err)
+	}
+	return keyManager, nil
+}
+
 // initSMSProvider initializes an SMS provider based on configuration; returns nil if disabled or misconfigured.
 func initSMSProvider(cfg *config.Config, log *logger.Logger) sms.SMSProvider {
 	if !cfg.SMS.Enabled {
@@ -1290,14 +2479,18 @@ func initSMSProvider(cfg *config.Config, log *logger.Logger) sms.SMSProvider {
 	providerCfg := sms.ProviderConfig{
 		Provider:        sms.ProviderType(cfg.SMS.Provider),
 		EnableMockInDev: cfg.Server.Env != "production",
+		Senders:         cfg.SMS.CountrySenders,
 	}
 
+	statusCallbackURL := smsStatusCallbackURL(cfg)
+
 	switch sms.ProviderType(cfg.SMS.Provider) {
 	case sms.ProviderTwilio:
 		providerCfg.TwilioConfig = &sms.TwilioConfig{
-			AccountSID: cfg.SMS.TwilioAccountSID,
-			AuthToken:  cfg.SMS.TwilioAuthToken,
-			FromNumber: cfg.SMS.TwilioFromNumber,
+			AccountSID:        cfg.SMS.TwilioAccountSID,
+			AuthToken:         cfg.SMS.TwilioAuthToken,
+			FromNumber:        cfg.SMS.TwilioFromNumber,
+			StatusCallbackURL: statusCallbackURL,
 		}
 	case sms.ProviderAWSSNS:
 		providerCfg.AWSSNSConfig = &sms.AWSSNSConfig{
@@ -1306,6 +2499,23 @@ func initSMSProvider(cfg *config.Config, log *logger.Logger) sms.SMSProvider {
 			SecretAccessKey: cfg.SMS.AWSSecretAccessKey,
 			FromNumber:      cfg.SMS.AWSSenderID,
 		}
+	case sms.ProviderVonage:
+		providerCfg.VonageConfig = &sms.VonageConfig{
+			APIKey:            cfg.SMS.VonageAPIKey,
+			APISecret:         cfg.SMS.VonageAPISecret,
+			FromNumber:        cfg.SMS.VonageFromNumber,
+			StatusCallbackURL: statusCallbackURL,
+		}
+	case sms.ProviderSMPP:
+		providerCfg.SMPPConfig = &sms.SMPPConfig{
+			Addr:       cfg.SMS.SMPPAddr,
+			SystemID:   cfg.SMS.SMPPSystemID,
+			Password:   cfg.SMS.SMPPPassword,
+			SystemType: cfg.SMS.SMPPSystemType,
+			SourceAddr: cfg.SMS.SMPPSourceAddr,
+			UseTLS:     cfg.SMS.SMPPTLS,
+		}
+	case sms.ProviderMock:
 	default:
 		log.Warn("Unsupported SMS provider configured; SMS disabled", map[string]interface{}{
 			"provider": cfg.SMS.Provider,
@@ -1325,7 +2535,111 @@ func initSMSProvider(cfg *config.Config, log *logger.Logger) sms.SMSProvider {
 	}
 
 	log.Info("SMS provider initialized", map[string]interface{}{
-		"provider": cfg.SMS.Provider,
+		"provider":         cfg.SMS.Provider,
+		"country_senders":  len(cfg.SMS.CountrySenders),
+		"delivery_reports": statusCallbackURL != "",
 	})
 	return provider
 }
+
+// initEventPublisher connects the event bus publisher; returns nil if disabled or misconfigured.
+func initEventPublisher(cfg *config.Config, log *logger.Logger) eventbus.Publisher {
+	if cfg.EventBus.Provider == "" {
+		return nil
+	}
+
+	publisherCfg := eventbus.Config{Provider: eventbus.ProviderType(cfg.EventBus.Provider)}
+	switch publisherCfg.Provider {
+	case eventbus.ProviderNATS:
+		publisherCfg.NATSConfig = &eventbus.NATSConfig{
+			URL:       cfg.EventBus.NATSURL,
+			User:      cfg.EventBus.NATSUser,
+			Password:  cfg.EventBus.NATSPassword,
+			Token:     cfg.EventBus.NATSToken,
+			JetStream: cfg.EventBus.NATSJetStream,
+		}
+	case eventbus.ProviderKafka:
+		publisherCfg.KafkaConfig = &eventbus.KafkaConfig{
+			RESTURL:  cfg.EventBus.KafkaRESTURL,
+			Username: cfg.EventBus.KafkaUsername,
+			Password: cfg.EventBus.KafkaPassword,
+		}
+	}
+
+	publisher, err := eventbus.NewPublisher(publisherCfg)
+	if err != nil {
+		log.Warn("Event bus initialization failed; events are not published", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	log.Info("Event bus initialized", map[string]interface{}{
+		"provider":      cfg.EventBus.Provider,
+		"default_topic": cfg.EventBus.DefaultTopic,
+		"topics":        len(cfg.EventBus.Topics),
+	})
+	return publisher
+}
+
+// initAuditExportSinks creates the audit export sinks that are configured. A sink that fails
+// to initialize is left out.
+func initAuditExportSinks(cfg *config.Config, log *logger.Logger) []auditexport.Sink {
+	exportCfg := cfg.AuditExport
+	var sinks []auditexport.Sink
+
+	if exportCfg.SyslogAddress != "" {
+		sink, err := auditexport.NewSyslogSink(auditexport.SyslogConfig{
+			Network:   exportCfg.SyslogNetwork,
+			Address:   exportCfg.SyslogAddress,
+			TLSCAFile: exportCfg.SyslogTLSCAFile,
+			Encoder:   auditexport.Encoder{Format: auditexport.Format(exportCfg.SyslogFormat), Version: Version},
+			Interval:  exportCfg.SyslogInterval,
+		})
+		if err != nil {
+			log.Warn("Syslog audit export initialization failed; audit logs are not sent to syslog", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			sinks = append(sinks, sink)
+		}
+	}
+
+	if exportCfg.S3Bucket != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		sink, err := auditexport.NewS3Sink(ctx, auditexport.S3Config{
+			Bucket:          exportCfg.S3Bucket,
+			Prefix:          exportCfg.S3Prefix,
+			Region:          exportCfg.S3Region,
+			Endpoint:        exportCfg.S3Endpoint,
+			AccessKeyID:     exportCfg.S3AccessKeyID,
+			SecretAccessKey: exportCfg.S3SecretAccessKey,
+			Encoder:         auditexport.Encoder{Format: auditexport.Format(exportCfg.S3Format), Version: Version},
+		})
+		cancel()
+		if err != nil {
+			log.Warn("S3 audit export initialization failed; audit logs are not written to S3", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			sinks = append(sinks, sink)
+		}
+	}
+
+	for _, sink := range sinks {
+		log.Info("Audit export enabled", map[string]interface{}{
+			"sink":     sink.Name(),
+			"interval": sink.Interval().String(),
+		})
+	}
+	return sinks
+}
+
+// smsStatusCallbackURL returns the URL providers report delivery status to, or "" when
+// delivery callbacks are not configured
+func smsStatusCallbackURL(cfg *config.Config) string {
+	if cfg.SMS.StatusCallbackURL == "" || cfg.SMS.StatusCallbackToken == "" {
+		return ""
+	}
+	return cfg.SMS.StatusCallbackURL + "/" + cfg.SMS.Provider + "?token=" + url.QueryEscape(cfg.SMS.StatusCallbackToken)
+}
@@ -0,0 +1,51 @@
+package abac
+
+import (
+	"strings"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// UserAttributes returns the user.* attributes of a user holding the given roles
+func UserAttributes(user *models.User, roles []string) map[string]interface{} {
+	if roles == nil {
+		roles = []string{}
+	}
+	return map[string]interface{}{
+		"id":               user.ID.String(),
+		"email":            user.Email,
+		"username":         user.Username,
+		"account_type":     user.AccountType,
+		"state":            string(user.State),
+		"email_verified":   user.EmailVerified,
+		"phone_verified":   user.PhoneVerified,
+		"totp_enabled":     user.TOTPEnabled,
+		"webauthn_enabled": user.WebAuthnEnabled,
+		"is_guest":         user.IsGuest,
+		"roles":            roles,
+	}
+}
+
+// TimeAttributes returns the time.* attributes of an instant, in UTC
+func TimeAttributes(t time.Time) map[string]interface{} {
+	t = t.UTC()
+	return map[string]interface{}{
+		"hour":    t.Hour(),
+		"minute":  t.Minute(),
+		"hhmm":    t.Format("15:04"),
+		"weekday": strings.ToLower(t.Weekday().String()),
+		"date":    t.Format("2006-01-02"),
+		"unix":    t.Unix(),
+	}
+}
+
+// StringAttributes converts caller-supplied string attributes, such as those of a
+// gRPC request, to an attribute set
+func StringAttributes(values map[string]string) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		attrs[key] = value
+	}
+	return attrs
+}
@@ -0,0 +1,516 @@
+// Package abac evaluates the conditions of conditional permissions: small boolean
+// expressions over the attributes of the user, the resource, the request context and
+// the time of the check.
+//
+// A condition compares attributes and literals:
+//
+//	resource.owner_id == user.id
+//	resource.amount < 1000 && "finance" in user.roles
+//	user.email matches "@example\\.com$"
+//	time.weekday in ["saturday", "sunday"] || time.hhmm < "08:00"
+//
+// Attributes are read with dotted paths rooted at user, resource, context or time. A
+// missing attribute only equals null and every other comparison with it is false, so
+// resource.owner_id == user.id never holds when both are missing. Literals are strings
+// in double or single quotes, numbers, true, false, null and lists in brackets.
+// Operators, by increasing precedence, are ||, &&, !, the comparisons == != < <= > >=,
+// in (membership in a list, or substring of a string) and matches (RE2 regular
+// expression literal). Strings that look like numbers or booleans compare as such
+// against numbers and booleans, since resource attributes often arrive as strings. A
+// comparison of incompatible values is false, so conditions never fail at evaluation:
+// they are only rejected when parsed.
+package abac
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxConditionLength bounds the source of a condition
+	MaxConditionLength = 1000
+	// maxDepth bounds the nesting of a condition
+	maxDepth = 32
+)
+
+// Roots are the attribute sets a condition can read
+var Roots = []string{"user", "resource", "context", "time"}
+
+// Attributes are the attribute sets a condition is evaluated against, keyed by root
+type Attributes map[string]map[string]interface{}
+
+// Condition is a parsed condition
+type Condition struct {
+	source string
+	root   node
+}
+
+// Parse parses a condition
+func Parse(source string) (*Condition, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	if len(source) > MaxConditionLength {
+		return nil, fmt.Errorf("condition is longer than %d characters", MaxConditionLength)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+	return &Condition{source: source, root: root}, nil
+}
+
+// String returns the source of the condition
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Evaluate reports whether the condition holds for the attributes
+func (c *Condition) Evaluate(attrs Attributes) bool {
+	return truthy(c.root.eval(attrs))
+}
+
+// --- Evaluation ---
+
+type node interface {
+	eval(attrs Attributes) interface{}
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(Attributes) interface{} { return n.value }
+
+type pathNode struct {
+	root string
+	path []string
+}
+
+func (n pathNode) eval(attrs Attributes) interface{} {
+	set := attrs[n.root]
+	if set == nil {
+		return missing{}
+	}
+	value, ok := set[n.path[0]]
+	for _, key := range n.path[1:] {
+		m, isMap := value.(map[string]interface{})
+		if !ok || !isMap {
+			return missing{}
+		}
+		value, ok = m[key]
+	}
+	if !ok || value == nil {
+		return missing{}
+	}
+	return normalize(value)
+}
+
+// missing is the value of an attribute that is not set
+type missing struct{}
+
+type listNode struct{ items []node }
+
+func (n listNode) eval(attrs Attributes) interface{} {
+	values := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		values[i] = item.eval(attrs)
+	}
+	return values
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(attrs Attributes) interface{} { return !truthy(n.operand.eval(attrs)) }
+
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n logicalNode) eval(attrs Attributes) interface{} {
+	left := truthy(n.left.eval(attrs))
+	if n.and {
+		return left && truthy(n.right.eval(attrs))
+	}
+	return left || truthy(n.right.eval(attrs))
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) eval(attrs Attributes) interface{} {
+	left, right := n.left.eval(attrs), n.right.eval(attrs)
+	_, leftMissing := left.(missing)
+	_, rightMissing := right.(missing)
+	if leftMissing || rightMissing {
+		isNull := (leftMissing || left == nil) && (rightMissing || right == nil)
+		switch n.op {
+		case "==":
+			return isNull && (left == nil || right == nil)
+		case "!=":
+			return !isNull && (left == nil || right == nil)
+		}
+		return false
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		return contains(right, left)
+	default:
+		cmp, ok := compare(left, right)
+		if !ok {
+			return false
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		case ">":
+			return cmp > 0
+		default:
+			return cmp >= 0
+		}
+	}
+}
+
+type matchNode struct {
+	left    node
+	pattern *regexp.Regexp
+}
+
+func (n matchNode) eval(attrs Attributes) interface{} {
+	s, ok := n.left.eval(attrs).(string)
+	return ok && n.pattern.MatchString(s)
+}
+
+// normalize converts attribute values to the types conditions work with: nil, bool,
+// float64, string and []interface{}
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, s := range v {
+			values[i] = s
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if cmp, ok := compare(a, b); ok {
+		return cmp == 0
+	}
+	if ab, ok := asBool(a); ok {
+		if bb, ok := asBool(b); ok {
+			return ab == bb
+		}
+	}
+	return false
+}
+
+// compare orders numbers, or strings when neither side is a number
+func compare(a, b interface{}) (int, bool) {
+	_, aNumber := a.(float64)
+	_, bNumber := b.(float64)
+	if aNumber || bNumber {
+		af, aok := asNumber(a)
+		bf, bok := asNumber(b)
+		if !aok || !bok {
+			return 0, false
+		}
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return 0, false
+	}
+	return strings.Compare(as, bs), true
+}
+
+func contains(container, item interface{}) bool {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, element := range c {
+			if equal(normalize(element), item) {
+				return true
+			}
+		}
+	case string:
+		s, ok := item.(string)
+		return ok && strings.Contains(c, s)
+	}
+	return false
+}
+
+func asNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func asBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// --- Parsing ---
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	tok := p.next()
+	if tok.kind != kind || (text != "" && tok.text != text) {
+		return fmt.Errorf("expected %q at position %d, got %s", text, tok.pos, tok)
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "||") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "&&") {
+		p.next()
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot(depth int) (node, error) {
+	if p.peek().is(tokenOperator, "!") {
+		p.next()
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("condition is nested deeper than %d levels", maxDepth)
+		}
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison(depth)
+}
+
+func (p *parser) parseComparison(depth int) (node, error) {
+	left, err := p.parseOperand(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator && isComparison(tok.text), tok.is(tokenIdent, "in"):
+		p.next()
+		right, err := p.parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: tok.text, left: left, right: right}, nil
+	case tok.is(tokenIdent, "matches"):
+		p.next()
+		patternTok := p.next()
+		if patternTok.kind != tokenString {
+			return nil, fmt.Errorf("matches needs a string literal at position %d", patternTok.pos)
+		}
+		pattern, err := regexp.Compile(patternTok.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at position %d: %w", patternTok.pos, err)
+		}
+		return matchNode{left: left, pattern: pattern}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand(depth int) (node, error) {
+	if depth >= maxDepth {
+		return nil, fmt.Errorf("condition is nested deeper than %d levels", maxDepth)
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return literalNode{value: tok.text}, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return literalNode{value: f}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+		return p.parsePath(tok)
+	case tokenPunct:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenPunct, ")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			return p.parseList(depth + 1)
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+}
+
+func (p *parser) parsePath(first token) (node, error) {
+	if !isRoot(first.text) {
+		return nil, fmt.Errorf("unknown attribute %q at position %d, attributes start with %s", first.text, first.pos, strings.Join(Roots, ", "))
+	}
+	path := pathNode{root: first.text}
+	for p.peek().is(tokenPunct, ".") {
+		p.next()
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			return nil, fmt.Errorf("expected attribute name at position %d, got %s", tok.pos, tok)
+		}
+		path.path = append(path.path, tok.text)
+	}
+	if len(path.path) == 0 {
+		return nil, fmt.Errorf("%q at position %d needs an attribute name, e.g. %s.id", first.text, first.pos, first.text)
+	}
+	return path, nil
+}
+
+func (p *parser) parseList(depth int) (node, error) {
+	list := listNode{}
+	if p.peek().is(tokenPunct, "]") {
+		p.next()
+		return list, nil
+	}
+	for {
+		item, err := p.parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+		tok := p.next()
+		if tok.is(tokenPunct, "]") {
+			return list, nil
+		}
+		if !tok.is(tokenPunct, ",") {
+			return nil, fmt.Errorf("expected \",\" or \"]\" at position %d, got %s", tok.pos, tok)
+		}
+	}
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func isRoot(name string) bool {
+	for _, root := range Roots {
+		if root == name {
+			return true
+		}
+	}
+	return false
+}
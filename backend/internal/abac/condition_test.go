@@ -0,0 +1,141 @@
+package abac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAttributes() Attributes {
+	return Attributes{
+		"user": {
+			"id":             "u-1",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"roles":          []string{"editor", "finance"},
+		},
+		"resource": {
+			"owner_id": "u-1",
+			"amount":   "250",
+			"status":   "draft",
+			"public":   "false",
+		},
+		"context": {
+			"ip_country": "DE",
+		},
+		"time": TimeAttributes(time.Date(2024, 1, 15, 9, 5, 0, 0, time.UTC)),
+	}
+}
+
+func TestCondition_Evaluate(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{`resource.owner_id == user.id`, true},
+		{`resource.owner_id != user.id`, false},
+		{`resource.amount < 1000`, true},
+		{`resource.amount >= 250.5`, false},
+		{`resource.amount == 250`, true},
+		{`"finance" in user.roles`, true},
+		{`"admin" in user.roles`, false},
+		{`resource.status in ["draft", 'review']`, true},
+		{`"example" in user.email`, true},
+		{`user.email matches "@example\\.com$"`, true},
+		{`user.email matches "^bob"`, false},
+		{`user.email_verified`, true},
+		{`user.email_verified == true`, true},
+		{`resource.public`, false},
+		{`resource.public == false`, true},
+		{`!resource.public && context.ip_country == "DE"`, true},
+		{`resource.owner_id == "u-2" || "editor" in user.roles`, true},
+		{`(resource.owner_id == "u-2" || resource.amount > 100) && resource.status == "draft"`, true},
+		{`time.weekday == "monday" && time.hhmm >= "09:00" && time.hhmm < "17:30"`, true},
+		{`time.hour < 9`, false},
+		{`resource.missing == null`, true},
+		{`resource.missing != null`, false},
+		{`resource.owner_id != null`, true},
+		{`resource.missing == resource.other`, false},
+		{`resource.missing != "draft"`, false},
+		{`resource.missing in ["draft"]`, false},
+		{`resource.missing == ""`, false},
+		{`resource.missing < 10`, false},
+		{`resource.owner_id.nested == null`, true},
+		{`user.roles == "finance"`, false},
+		{`resource.status > 1`, false},
+	}
+
+	attrs := testAttributes()
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			condition, err := Parse(tt.condition)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, condition.Evaluate(attrs))
+		})
+	}
+}
+
+func TestCondition_Evaluate_ShouldBeFalse_WhenAttributeSetMissing(t *testing.T) {
+	condition, err := Parse(`resource.owner_id == user.id`)
+	require.NoError(t, err)
+
+	assert.False(t, condition.Evaluate(Attributes{"user": {"id": "u-1"}}))
+	assert.False(t, condition.Evaluate(nil))
+}
+
+func TestParse_ShouldRejectInvalidConditions(t *testing.T) {
+	tests := []struct {
+		condition string
+		errPart   string
+	}{
+		{``, "empty"},
+		{`   `, "empty"},
+		{`owner_id == "u-1"`, `unknown attribute "owner_id"`},
+		{`user == "u-1"`, "needs an attribute name"},
+		{`user.id ==`, "unexpected end of condition"},
+		{`user.id = "u-1"`, `unexpected character '='`},
+		{`(user.id == "u-1"`, `expected ")"`},
+		{`user.id == "u-1`, "unterminated string"},
+		{`user.email matches user.id`, "needs a string literal"},
+		{`user.email matches "("`, "invalid regular expression"},
+		{`user.id in ["a" "b"]`, `expected "," or "]"`},
+		{`user.id == "u-1" resource.id`, `unexpected "resource"`},
+		{`resource.amount == 1.2.3`, `invalid number`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			_, err := Parse(tt.condition)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errPart)
+		})
+	}
+}
+
+func TestParse_ShouldBoundConditionSize(t *testing.T) {
+	long := `user.id == "` + string(make([]byte, MaxConditionLength)) + `"`
+	_, err := Parse(long)
+	assert.ErrorContains(t, err, "longer than")
+
+	deep := ""
+	for i := 0; i < maxDepth+1; i++ {
+		deep += "("
+	}
+	_, err = Parse(deep + "true")
+	assert.ErrorContains(t, err, "nested deeper")
+
+	_, err = Parse("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!true")
+	assert.ErrorContains(t, err, "nested deeper")
+}
+
+func TestTimeAttributes(t *testing.T) {
+	attrs := TimeAttributes(time.Date(2024, 1, 13, 23, 7, 0, 0, time.FixedZone("CET", 3600)))
+
+	assert.Equal(t, 22, attrs["hour"])
+	assert.Equal(t, 7, attrs["minute"])
+	assert.Equal(t, "22:07", attrs["hhmm"])
+	assert.Equal(t, "saturday", attrs["weekday"])
+	assert.Equal(t, "2024-01-13", attrs["date"])
+}
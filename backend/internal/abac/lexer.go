@@ -0,0 +1,129 @@
+package abac
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of condition"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// tokenize splits a condition into tokens, ending with tokenEOF
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && isIdentPart(source[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+		case isDigit(c) || (c == '-' && i+1 < len(source) && isDigit(source[i+1])):
+			start := i
+			i++
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+		case c == '"' || c == '\'':
+			text, end, err := readString(source, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = end
+		case strings.ContainsRune("()[],.", rune(c)):
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), pos: i})
+			i++
+		default:
+			op := readOperator(source[i:])
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// readString reads the quoted string starting at start, returning its unescaped text
+// and the position after the closing quote
+func readString(source string, start int) (string, int, error) {
+	quote := source[start]
+	var b strings.Builder
+	for i := start + 1; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(source):
+			i++
+			switch source[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(source[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func readOperator(s string) string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,290 @@
+// Package accesslog writes one line per served HTTP request and gRPC call, apart from the
+// application log, in JSON or NCSA combined format.
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+)
+
+// Formats
+const (
+	FormatJSON = "json"
+	FormatNCSA = "ncsa"
+)
+
+// Protocols
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Field names, as written in JSON entries and accepted by ACCESS_LOG_FIELDS
+const (
+	FieldTime      = "time"
+	FieldProtocol  = "protocol"
+	FieldMethod    = "method"
+	FieldPath      = "path"
+	FieldStatus    = "status"
+	FieldGRPCCode  = "grpc_code"
+	FieldDuration  = "duration_ms"
+	FieldBytes     = "bytes"
+	FieldIP        = "ip"
+	FieldUserAgent = "user_agent"
+	FieldReferer   = "referer"
+	FieldUserID    = "user_id"
+	FieldRequestID = "request_id"
+)
+
+var allFields = []string{
+	FieldTime, FieldProtocol, FieldMethod, FieldPath, FieldStatus, FieldGRPCCode, FieldDuration,
+	FieldBytes, FieldIP, FieldUserAgent, FieldReferer, FieldUserID, FieldRequestID,
+}
+
+// Entry is one served request. Status is the HTTP status; gRPC calls map their code to the
+// equivalent HTTP status and keep the code itself in GRPCCode.
+type Entry struct {
+	Time      time.Time
+	Protocol  string
+	Method    string
+	Path      string // without the query string, which may carry credentials
+	Status    int
+	GRPCCode  string
+	Duration  time.Duration
+	Bytes     int
+	IP        string
+	UserAgent string
+	Referer   string
+	UserID    string
+	RequestID string
+}
+
+// Logger writes access log entries. A nil *Logger discards them.
+type Logger struct {
+	mu          sync.Mutex
+	w           io.Writer
+	closer      io.Closer
+	format      string
+	anonymizeIP bool
+	fields      map[string]bool
+}
+
+// New creates an access logger writing to cfg.Output: stdout, stderr or a file path,
+// appended to.
+func New(cfg *config.AccessLogConfig) (*Logger, error) {
+	switch cfg.Output {
+	case "", "stdout":
+		return NewWriter(os.Stdout, cfg)
+	case "stderr":
+		return NewWriter(os.Stderr, cfg)
+	}
+
+	f, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	l, err := NewWriter(f, cfg)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	l.closer = f
+	return l, nil
+}
+
+// NewWriter creates an access logger writing to w, ignoring cfg.Output
+func NewWriter(w io.Writer, cfg *config.AccessLogConfig) (*Logger, error) {
+	format := strings.ToLower(cfg.Format)
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatNCSA {
+		return nil, fmt.Errorf("unknown access log format %q (want json or ncsa)", cfg.Format)
+	}
+
+	fields, err := selectFields(cfg.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{w: w, format: format, anonymizeIP: cfg.AnonymizeIP, fields: fields}, nil
+}
+
+// selectFields validates the configured fields; none selects all of them
+func selectFields(names []string) (map[string]bool, error) {
+	known := make(map[string]bool, len(allFields))
+	for _, name := range allFields {
+		known[name] = true
+	}
+	if len(names) == 0 {
+		return known, nil
+	}
+
+	fields := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown access log field %q", name)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// Log writes an entry
+func (l *Logger) Log(e Entry) {
+	if l == nil {
+		return
+	}
+	if l.anonymizeIP {
+		e.IP = AnonymizeIP(e.IP)
+	}
+
+	var line []byte
+	if l.format == FormatNCSA {
+		line = l.ncsa(e)
+	} else {
+		line = l.json(e)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line)
+}
+
+// Close closes the access log file, if any
+func (l *Logger) Close() error {
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+func (l *Logger) json(e Entry) []byte {
+	entry := make(map[string]interface{}, len(l.fields))
+	set := func(field string, value interface{}) {
+		if l.fields[field] {
+			entry[field] = value
+		}
+	}
+	set(FieldTime, e.Time.UTC().Format(time.RFC3339Nano))
+	set(FieldProtocol, e.Protocol)
+	set(FieldMethod, e.Method)
+	set(FieldPath, e.Path)
+	set(FieldStatus, e.Status)
+	if e.GRPCCode != "" {
+		set(FieldGRPCCode, e.GRPCCode)
+	}
+	set(FieldDuration, float64(e.Duration.Microseconds())/1000)
+	set(FieldBytes, e.Bytes)
+	set(FieldIP, e.IP)
+	set(FieldUserAgent, e.UserAgent)
+	if e.Referer != "" {
+		set(FieldReferer, e.Referer)
+	}
+	if e.UserID != "" {
+		set(FieldUserID, e.UserID)
+	}
+	if e.RequestID != "" {
+		set(FieldRequestID, e.RequestID)
+	}
+
+	line, _ := json.Marshal(entry)
+	return append(line, '\n')
+}
+
+// ncsa formats the NCSA combined log format. Fields that are not selected are written as
+// "-" so that the layout stays parseable.
+//
+//	ip - user [time] "method path protocol" status bytes "referer" "user-agent"
+func (l *Logger) ncsa(e Entry) []byte {
+	field := func(name, value string) string {
+		if !l.fields[name] || value == "" {
+			return "-"
+		}
+		return value
+	}
+
+	protocol := "HTTP/1.1"
+	if e.Protocol == ProtocolGRPC {
+		protocol = "gRPC"
+	}
+	status, bytes := "-", "-"
+	if l.fields[FieldStatus] {
+		status = strconv.Itoa(e.Status)
+	}
+	if l.fields[FieldBytes] {
+		bytes = strconv.Itoa(e.Bytes)
+	}
+	timestamp := "-"
+	if l.fields[FieldTime] {
+		timestamp = "[" + e.Time.Format("02/Jan/2006:15:04:05 -0700") + "]"
+	}
+
+	return []byte(fmt.Sprintf("%s - %s %s \"%s %s %s\" %s %s \"%s\" \"%s\"\n",
+		field(FieldIP, e.IP),
+		field(FieldUserID, e.UserID),
+		timestamp,
+		escape(field(FieldMethod, e.Method)),
+		escape(field(FieldPath, e.Path)),
+		protocol,
+		status,
+		bytes,
+		escape(field(FieldReferer, e.Referer)),
+		escape(field(FieldUserAgent, e.UserAgent)),
+	))
+}
+
+// escape keeps client-supplied values from breaking out of their quoted NCSA field
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// AnonymizeIP drops the host part of an address: the last octet of IPv4 and all but the
+// first 48 bits of IPv6. Values that are not addresses are returned unchanged.
+func AnonymizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+type entryKey struct{}
+
+// NewContext returns a context carrying the entry being built for a request, so that
+// handlers further down can fill in what only they know
+func NewContext(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+// SetUserID records the authenticated user on the entry carried by ctx, if any
+func SetUserID(ctx context.Context, userID string) {
+	if e, ok := ctx.Value(entryKey{}).(*Entry); ok {
+		e.UserID = userID
+	}
+}
@@ -0,0 +1,121 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEntry() Entry {
+	return Entry{
+		Time:      time.Date(2025, 3, 4, 10, 20, 30, 0, time.UTC),
+		Protocol:  ProtocolHTTP,
+		Method:    "GET",
+		Path:      "/api/auth/profile",
+		Status:    200,
+		Duration:  1500 * time.Microsecond,
+		Bytes:     512,
+		IP:        "203.0.113.77",
+		UserAgent: `curl/8.0 "quoted"`,
+		UserID:    "user-1",
+		RequestID: "req-1",
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewWriter(&buf, &config.AccessLogConfig{Format: "json", AnonymizeIP: true})
+	require.NoError(t, err)
+
+	log.Log(testEntry())
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, map[string]interface{}{
+		"time":        "2025-03-04T10:20:30Z",
+		"protocol":    "http",
+		"method":      "GET",
+		"path":        "/api/auth/profile",
+		"status":      float64(200),
+		"duration_ms": 1.5,
+		"bytes":       float64(512),
+		"ip":          "203.0.113.0",
+		"user_agent":  `curl/8.0 "quoted"`,
+		"user_id":     "user-1",
+		"request_id":  "req-1",
+	}, entry)
+}
+
+func TestLogger_JSONFieldSelection(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewWriter(&buf, &config.AccessLogConfig{Fields: []string{"path", " Status "}})
+	require.NoError(t, err)
+
+	log.Log(testEntry())
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, map[string]interface{}{"path": "/api/auth/profile", "status": float64(200)}, entry)
+}
+
+func TestLogger_NCSA(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewWriter(&buf, &config.AccessLogConfig{Format: "ncsa"})
+	require.NoError(t, err)
+
+	log.Log(testEntry())
+
+	assert.Equal(t,
+		`203.0.113.77 - user-1 [04/Mar/2025:10:20:30 +0000] "GET /api/auth/profile HTTP/1.1" 200 512 "-" "curl/8.0 \"quoted\""`+"\n",
+		buf.String())
+}
+
+func TestLogger_NCSAFieldSelection(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewWriter(&buf, &config.AccessLogConfig{Format: "ncsa", Fields: []string{"method", "path", "status"}})
+	require.NoError(t, err)
+
+	entry := testEntry()
+	entry.Protocol = ProtocolGRPC
+	entry.Method = "POST"
+	entry.Path = "/auth.AuthService/ValidateToken\n"
+	log.Log(entry)
+
+	assert.Equal(t, `- - - - "POST /auth.AuthService/ValidateToken\x0a gRPC" 200 - "-" "-"`+"\n", buf.String())
+}
+
+func TestNewWriter_RejectsUnknownSettings(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, &config.AccessLogConfig{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = NewWriter(&bytes.Buffer{}, &config.AccessLogConfig{Fields: []string{"password"}})
+	assert.Error(t, err)
+}
+
+func TestAnonymizeIP(t *testing.T) {
+	assert.Equal(t, "192.168.1.0", AnonymizeIP("192.168.1.42"))
+	assert.Equal(t, "2001:db8:85a3::", AnonymizeIP("2001:db8:85a3:8d3:1319:8a2e:370:7348"))
+	assert.Equal(t, "10.0.0.0", AnonymizeIP("::ffff:10.0.0.9"))
+	assert.Equal(t, "unknown", AnonymizeIP("unknown"))
+}
+
+func TestSetUserID(t *testing.T) {
+	entry := &Entry{}
+	SetUserID(NewContext(context.Background(), entry), "user-1")
+	assert.Equal(t, "user-1", entry.UserID)
+
+	// No entry in the context is not an error
+	SetUserID(context.Background(), "user-1")
+}
+
+func TestLogger_NilDiscards(t *testing.T) {
+	var log *Logger
+	log.Log(testEntry())
+	assert.NoError(t, log.Close())
+}
@@ -0,0 +1,84 @@
+package auditexport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditLog() *models.AuditLog {
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	return &models.AuditLog{
+		ID:           uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		UserID:       &userID,
+		Action:       "signin",
+		Status:       string(models.StatusFailed),
+		IPAddress:    "203.0.113.7",
+		UserAgent:    "curl/8.0",
+		ResourceType: "user",
+		TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+		Details:      []byte(`{"reason":"bad=password"}`),
+		CreatedAt:    time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEncoder_CEF(t *testing.T) {
+	line, err := Encoder{Format: FormatCEF, Version: "1.2.0"}.Encode(testAuditLog())
+	require.NoError(t, err)
+
+	got := string(line)
+	assert.True(t, strings.HasPrefix(got, "CEF:0|smilemakc|auth-gateway|1.2.0|signin|signin|5|rt=1746093600000 "))
+	assert.Contains(t, got, "externalId=22222222-2222-2222-2222-222222222222")
+	assert.Contains(t, got, "suid=11111111-1111-1111-1111-111111111111")
+	assert.Contains(t, got, "src=203.0.113.7")
+	assert.Contains(t, got, "cs4Label=traceId cs4=4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Contains(t, got, `cs5={"reason":"bad\=password"}`)
+	// Empty fields are left out
+	assert.NotContains(t, got, "cs1")
+	assert.NotContains(t, got, "cs3")
+}
+
+func TestEncoder_CEFSkipsInvalidSource(t *testing.T) {
+	log := testAuditLog()
+	log.IPAddress = "203.0.113.7, 10.0.0.1"
+	line, err := Encoder{Format: FormatCEF}.Encode(log)
+	require.NoError(t, err)
+	assert.NotContains(t, string(line), "src=")
+}
+
+func TestEncoder_JSON(t *testing.T) {
+	line, err := Encoder{Format: FormatJSON}.Encode(testAuditLog())
+	require.NoError(t, err)
+
+	var rec map[string]interface{}
+	require.NoError(t, json.Unmarshal(line, &rec))
+	assert.Equal(t, "signin", rec["action"])
+	assert.Equal(t, "2025-05-01T10:00:00Z", rec["created_at"])
+	assert.Equal(t, map[string]interface{}{"reason": "bad=password"}, rec["details"])
+	assert.NotContains(t, rec, "application_id")
+}
+
+func TestEncoder_JSONDropsInvalidDetails(t *testing.T) {
+	log := testAuditLog()
+	log.Details = []byte("not json\n")
+	line, err := Encoder{Format: FormatJSON}.Encode(log)
+	require.NoError(t, err)
+	assert.NotContains(t, string(line), "details")
+}
+
+func TestCEFEscaping(t *testing.T) {
+	assert.Equal(t, `a\|b\\c d`, escapeCEFHeader("a|b\\c\nd"))
+	assert.Equal(t, `a\=b\\c\nd`, escapeCEFValue("a=b\\c\r\nd"))
+}
+
+func TestCEFSeverity(t *testing.T) {
+	assert.Equal(t, 3, cefSeverity(string(models.StatusSuccess)))
+	assert.Equal(t, 5, cefSeverity(string(models.StatusFailed)))
+	assert.Equal(t, 7, cefSeverity(string(models.StatusBlocked)))
+}
@@ -0,0 +1,70 @@
+// Package auditexport exports audit logs to SIEMs: as CEF or JSON messages streamed to a
+// syslog collector, and as hourly batches of gzipped JSON lines written to S3-compatible
+// storage. Logs are exported by time window, so any past range can be exported again.
+package auditexport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// Sink receives exported audit logs
+type Sink interface {
+	// Export sends the logs of window, read page by page from pages. Exporting a window
+	// again, as when a failed export is retried, sends its logs to a stream once more but
+	// replaces a stored batch.
+	Export(ctx context.Context, window Window, pages Pages) error
+
+	// Name identifies the sink: syslog or s3
+	Name() string
+
+	// Interval is the length of the windows the sink exports
+	Interval() time.Duration
+
+	// Close releases the sink's connections
+	Close() error
+}
+
+// Window is the time range [From, To) of the audit logs of an export
+type Window struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Pages returns the audit logs of a window page by page, oldest first; an empty page ends them
+type Pages func(ctx context.Context) ([]*models.AuditLog, error)
+
+var (
+	// ErrNotConfigured is returned when a sink is not properly configured
+	ErrNotConfigured = errors.New("audit export not configured")
+
+	// ErrUnavailable is returned when the destination of a sink cannot be reached
+	ErrUnavailable = errors.New("audit export destination unavailable")
+)
+
+// Sink names
+const (
+	SinkSyslog = "syslog"
+	SinkS3     = "s3"
+)
+
+// eachLog calls fn for every log of pages
+func eachLog(ctx context.Context, pages Pages, fn func(log *models.AuditLog) error) error {
+	for {
+		page, err := pages(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		for _, log := range page {
+			if err := fn(log); err != nil {
+				return err
+			}
+		}
+	}
+}
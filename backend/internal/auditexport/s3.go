@@ -0,0 +1,168 @@
+package auditexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// s3Interval is the length of the batches written to S3
+const s3Interval = time.Hour
+
+// s3API is the part of the S3 client the sink uses
+type s3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Sink writes the audit logs of each hour as one gzipped object of JSON lines (or CEF
+// lines) to S3 or S3-compatible storage, under prefix/YYYY/MM/DD/HH. A window exported
+// again replaces its object; hours without logs get no object.
+type S3Sink struct {
+	client  s3API
+	bucket  string
+	prefix  string
+	encoder Encoder
+}
+
+// S3Config holds S3 sink configuration
+type S3Config struct {
+	Bucket string
+	// Prefix of the object keys, e.g. audit/
+	Prefix string
+	Region string
+	// Endpoint of S3-compatible storage such as MinIO; buckets are then addressed by path
+	Endpoint string
+	// AccessKeyID and SecretAccessKey are static credentials; without them the default
+	// credential chain is used (environment, IAM roles and the like)
+	AccessKeyID     string
+	SecretAccessKey string
+	Encoder         Encoder
+}
+
+// NewS3Sink creates a new S3 sink
+func NewS3Sink(ctx context.Context, cfg S3Config) (*S3Sink, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("%w: S3 bucket is required", ErrNotConfigured)
+	}
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" && cfg.SecretAccessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AccessKeyID,
+			cfg.SecretAccessKey,
+			"",
+		)))
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to load AWS config: %v", ErrNotConfigured, err)
+	}
+
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return newS3Sink(client, cfg), nil
+}
+
+func newS3Sink(client s3API, cfg S3Config) *S3Sink {
+	encoder := cfg.Encoder
+	if !encoder.Format.IsValid() {
+		encoder.Format = FormatJSON
+	}
+	return &S3Sink{
+		client:  client,
+		bucket:  cfg.Bucket,
+		prefix:  cfg.Prefix,
+		encoder: encoder,
+	}
+}
+
+// Name returns the name of the sink
+func (s *S3Sink) Name() string {
+	return SinkS3
+}
+
+// Interval returns the length of the windows the sink exports
+func (s *S3Sink) Interval() time.Duration {
+	return s3Interval
+}
+
+// Export writes the logs of window to the object of its hour
+func (s *S3Sink) Export(ctx context.Context, window Window, pages Pages) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	count := 0
+	err := eachLog(ctx, pages, func(log *models.AuditLog) error {
+		line, err := s.encoder.Encode(log)
+		if err != nil {
+			return err
+		}
+		count++
+		_, _ = zw.Write(append(line, '\n'))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress audit logs: %w", err)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(s.Key(window)),
+		Body:            bytes.NewReader(buf.Bytes()),
+		ContentType:     aws.String(s.contentType()),
+		ContentEncoding: aws.String("gzip"),
+		Metadata: map[string]string{
+			"from":  window.From.UTC().Format(time.RFC3339),
+			"to":    window.To.UTC().Format(time.RFC3339),
+			"count": fmt.Sprint(count),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to write audit logs to S3: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// Key returns the object key of window, named after the hour it starts in
+func (s *S3Sink) Key(window Window) string {
+	ext := ".jsonl.gz"
+	if s.encoder.Format == FormatCEF {
+		ext = ".cef.gz"
+	}
+	prefix := s.prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + window.From.UTC().Format("2006/01/02/15") + ext
+}
+
+func (s *S3Sink) contentType() string {
+	if s.encoder.Format == FormatCEF {
+		return "text/plain"
+	}
+	return "application/x-ndjson"
+}
+
+// Close does nothing; the S3 client holds no connections of its own
+func (s *S3Sink) Close() error {
+	return nil
+}
@@ -0,0 +1,149 @@
+package auditexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagesOf returns the logs as a single page
+func pagesOf(logs ...*models.AuditLog) Pages {
+	done := false
+	return func(ctx context.Context) ([]*models.AuditLog, error) {
+		if done {
+			return nil, nil
+		}
+		done = true
+		return logs, nil
+	}
+}
+
+func TestSyslogSink_Export(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var msgs []string
+		for len(msgs) < 2 {
+			length, err := r.ReadString(' ')
+			if err != nil {
+				break
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				break
+			}
+			msgs = append(msgs, string(msg))
+		}
+		received <- msgs
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String()})
+	require.NoError(t, err)
+	defer sink.Close()
+	assert.Equal(t, time.Minute, sink.Interval())
+
+	success := testAuditLog()
+	success.Status = string(models.StatusSuccess)
+	require.NoError(t, sink.Export(context.Background(), Window{}, pagesOf(success, testAuditLog())))
+
+	select {
+	case msgs := <-received:
+		require.Len(t, msgs, 2)
+		assert.True(t, strings.HasPrefix(msgs[0], "<86>1 2025-05-01T10:00:00.000000Z "), msgs[0])
+		assert.Contains(t, msgs[0], " auth-gateway - audit - CEF:0|")
+		assert.True(t, strings.HasPrefix(msgs[1], "<85>1 "), msgs[1])
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog messages not received")
+	}
+}
+
+func TestNewSyslogSink_Invalid(t *testing.T) {
+	_, err := NewSyslogSink(SyslogConfig{Network: "tcp"})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	_, err = NewSyslogSink(SyslogConfig{Network: "http", Address: "localhost:514"})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
+
+type mockS3 struct {
+	puts []*s3.PutObjectInput
+	body []byte
+	err  error
+}
+
+func (m *mockS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.puts = append(m.puts, params)
+	m.body, _ = io.ReadAll(params.Body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3Sink_Export(t *testing.T) {
+	client := &mockS3{}
+	sink := newS3Sink(client, S3Config{Bucket: "siem", Prefix: "audit"})
+	window := Window{
+		From: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 5, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, sink.Export(context.Background(), window, pagesOf(testAuditLog(), testAuditLog())))
+	require.Len(t, client.puts, 1)
+	put := client.puts[0]
+	assert.Equal(t, "siem", aws.ToString(put.Bucket))
+	assert.Equal(t, "audit/2025/05/01/10.jsonl.gz", aws.ToString(put.Key))
+	assert.Equal(t, "2", put.Metadata["count"])
+
+	zr, err := gzip.NewReader(bytes.NewReader(client.body))
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], `{"id":"22222222-2222-2222-2222-222222222222"`))
+}
+
+func TestS3Sink_ExportSkipsEmptyWindow(t *testing.T) {
+	client := &mockS3{}
+	sink := newS3Sink(client, S3Config{Bucket: "siem"})
+	require.NoError(t, sink.Export(context.Background(), Window{}, pagesOf()))
+	assert.Empty(t, client.puts)
+}
+
+func TestS3Sink_ExportUnavailable(t *testing.T) {
+	client := &mockS3{err: errors.New("connection refused")}
+	sink := newS3Sink(client, S3Config{Bucket: "siem"})
+	err := sink.Export(context.Background(), Window{}, pagesOf(testAuditLog()))
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestS3Sink_KeyCEF(t *testing.T) {
+	sink := newS3Sink(&mockS3{}, S3Config{Bucket: "siem", Prefix: "audit/", Encoder: Encoder{Format: FormatCEF}})
+	window := Window{From: time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)}
+	assert.Equal(t, "audit/2025/05/01/09.cef.gz", sink.Key(window))
+}
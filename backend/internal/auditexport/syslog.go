@@ -0,0 +1,211 @@
+package auditexport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+const (
+	syslogDefaultInterval = time.Minute
+	syslogDialTimeout     = 10 * time.Second
+	syslogWriteTimeout    = 10 * time.Second
+	// syslogFacility is authpriv, the facility of security and authorization messages
+	syslogFacility = 10
+	syslogAppName  = "auth-gateway"
+	syslogMsgID    = "audit"
+)
+
+// SyslogSink streams audit logs to a syslog collector as RFC 5424 messages. Over TCP and
+// TLS messages are framed by octet counting (RFC 6587); over UDP each is one datagram.
+type SyslogSink struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	encoder   Encoder
+	interval  time.Duration
+	hostname  string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// SyslogConfig holds syslog sink configuration
+type SyslogConfig struct {
+	// Network is tcp, udp or tls
+	Network string
+	// Address of the collector, host:port
+	Address string
+	// TLSCAFile is a PEM bundle of the CAs trusted to sign the collector's certificate, in
+	// addition to the system ones
+	TLSCAFile string
+	Encoder   Encoder
+	// Interval is the length of the windows exported at once; defaults to a minute
+	Interval time.Duration
+}
+
+// NewSyslogSink creates a new syslog sink. It connects on the first export.
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("%w: syslog address is required", ErrNotConfigured)
+	}
+	if _, _, err := net.SplitHostPort(config.Address); err != nil {
+		return nil, fmt.Errorf("%w: invalid syslog address: %v", ErrNotConfigured, err)
+	}
+
+	sink := &SyslogSink{
+		network:  config.Network,
+		address:  config.Address,
+		encoder:  config.Encoder,
+		interval: config.Interval,
+	}
+	switch config.Network {
+	case "tcp", "udp":
+	case "tls":
+		tlsConfig, err := syslogTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		sink.tlsConfig = tlsConfig
+	default:
+		return nil, fmt.Errorf("%w: unsupported syslog network %q", ErrNotConfigured, config.Network)
+	}
+	if sink.interval <= 0 {
+		sink.interval = syslogDefaultInterval
+	}
+	if !sink.encoder.Format.IsValid() {
+		sink.encoder.Format = FormatCEF
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		sink.hostname = hostname
+	} else {
+		sink.hostname = "-"
+	}
+
+	return sink, nil
+}
+
+func syslogTLSConfig(config SyslogConfig) (*tls.Config, error) {
+	host, _, _ := net.SplitHostPort(config.Address)
+	tlsConfig := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if config.TLSCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(config.TLSCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read syslog CA file: %v", ErrNotConfigured, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates in syslog CA file", ErrNotConfigured)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// Name returns the name of the sink
+func (s *SyslogSink) Name() string {
+	return SinkSyslog
+}
+
+// Interval returns the length of the windows the sink exports
+func (s *SyslogSink) Interval() time.Duration {
+	return s.interval
+}
+
+// Export sends each log of window as a syslog message
+func (s *SyslogSink) Export(ctx context.Context, window Window, pages Pages) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return eachLog(ctx, pages, func(log *models.AuditLog) error {
+		msg, err := s.message(log)
+		if err != nil {
+			return err
+		}
+		return s.write(ctx, msg)
+	})
+}
+
+// message builds the RFC 5424 message of log:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *SyslogSink) message(log *models.AuditLog) ([]byte, error) {
+	body, err := s.encoder.Encode(log)
+	if err != nil {
+		return nil, err
+	}
+
+	severity := 6 // informational
+	if models.AuditStatus(log.Status) != models.StatusSuccess {
+		severity = 5 // notice
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s - %s - ",
+		syslogFacility*8+severity,
+		log.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		s.hostname, syslogAppName, syslogMsgID,
+	)
+	return append([]byte(header), body...), nil
+}
+
+// write sends msg, reconnecting once when the connection has gone away. Callers hold s.mu.
+func (s *SyslogSink) write(ctx context.Context, msg []byte) error {
+	if s.network != "udp" {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if s.conn, err = s.dial(ctx); err != nil {
+				return err
+			}
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+		if _, err = s.conn.Write(msg); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return fmt.Errorf("%w: failed to write to syslog: %v", ErrUnavailable, err)
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if s.tlsConfig != nil {
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: syslogDialTimeout}, Config: s.tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", s.address)
+	} else {
+		dialer := &net.Dialer{Timeout: syslogDialTimeout}
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to connect to syslog %s: %v", ErrUnavailable, s.address, err)
+	}
+	return conn, nil
+}
+
+// Close closes the connection to the collector
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
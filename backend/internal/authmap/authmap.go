@@ -0,0 +1,169 @@
+// Package authmap builds the authorization map of the gateway: what every HTTP route and gRPC
+// method requires of its caller. Route requirements are read from the middleware chains
+// registered with the router, so the map can't drift from what the router enforces.
+package authmap
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// ErrUnsupportedRouter is returned when the route trees of the gin engine don't have the
+// layout this package reads, e.g. after a gin upgrade
+var ErrUnsupportedRouter = errors.New("authmap: unsupported gin router layout")
+
+// described is a middleware annotated with the requirement it enforces
+type described struct {
+	handler     gin.HandlerFunc
+	requirement models.AuthRequirement
+}
+
+func (d *described) serve(c *gin.Context) {
+	d.handler(c)
+}
+
+// describedCode is the code pointer shared by all serve method values
+var describedCode = reflect.ValueOf((&described{}).serve).Pointer()
+
+// methodValue is the layout of a method value closure: the code pointer followed by the receiver
+type methodValue struct {
+	code     uintptr
+	receiver *described
+}
+
+// Describe annotates a middleware with the requirement it enforces, so that the requirement
+// shows up in the authorization map of every route the middleware guards. The middleware
+// must be built once per route registration, not once per request.
+func Describe(handler gin.HandlerFunc, requirement models.AuthRequirement) gin.HandlerFunc {
+	return (&described{handler: handler, requirement: requirement}).serve
+}
+
+// requirementOf returns the requirement a handler was annotated with by Describe
+func requirementOf(handler gin.HandlerFunc) (models.AuthRequirement, bool) {
+	if handler == nil || reflect.ValueOf(handler).Pointer() != describedCode {
+		return models.AuthRequirement{}, false
+	}
+	closure := *(**methodValue)(unsafe.Pointer(&handler))
+	return closure.receiver.requirement, true
+}
+
+// Build returns the authorization map of the routes registered with the engine and of the
+// given gRPC methods
+func Build(engine *gin.Engine, grpcMethods []models.GRPCMethodAuthorization) (*models.AuthorizationMap, error) {
+	routes, err := Routes(engine)
+	if err != nil {
+		return nil, err
+	}
+	if grpcMethods == nil {
+		grpcMethods = []models.GRPCMethodAuthorization{}
+	}
+	return &models.AuthorizationMap{
+		GeneratedAt: time.Now().UTC(),
+		HTTP:        routes,
+		GRPC:        grpcMethods,
+	}, nil
+}
+
+// Routes returns the authorization of every route registered with the engine, sorted by path
+// and method
+func Routes(engine *gin.Engine) ([]models.RouteAuthorization, error) {
+	trees := reflect.ValueOf(engine).Elem().FieldByName("trees")
+	if !trees.IsValid() || trees.Kind() != reflect.Slice {
+		return nil, ErrUnsupportedRouter
+	}
+
+	routes := []models.RouteAuthorization{}
+	for i := 0; i < trees.Len(); i++ {
+		tree := trees.Index(i)
+		method := tree.FieldByName("method")
+		root := tree.FieldByName("root")
+		if !method.IsValid() || method.Kind() != reflect.String || !root.IsValid() || root.Kind() != reflect.Ptr {
+			return nil, ErrUnsupportedRouter
+		}
+		if err := walk(method.String(), root, &routes); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes, nil
+}
+
+// walk appends the routes of a route tree node and its children
+func walk(method string, node reflect.Value, routes *[]models.RouteAuthorization) error {
+	if node.IsNil() {
+		return nil
+	}
+	node = node.Elem()
+
+	handlers := node.FieldByName("handlers")
+	fullPath := node.FieldByName("fullPath")
+	children := node.FieldByName("children")
+	if !handlers.IsValid() || handlers.Type() != reflect.TypeOf(gin.HandlersChain(nil)) ||
+		!fullPath.IsValid() || fullPath.Kind() != reflect.String ||
+		!children.IsValid() || children.Kind() != reflect.Slice {
+		return ErrUnsupportedRouter
+	}
+
+	if handlers.Len() > 0 {
+		chain := *(*gin.HandlersChain)(unsafe.Pointer(handlers.UnsafeAddr()))
+		*routes = append(*routes, describeRoute(method, fullPath.String(), chain))
+	}
+
+	for i := 0; i < children.Len(); i++ {
+		if err := walk(method, children.Index(i), routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeRoute collects the requirements of the annotated middleware in a handler chain
+func describeRoute(method, path string, chain gin.HandlersChain) models.RouteAuthorization {
+	route := models.RouteAuthorization{
+		Method:       method,
+		Path:         path,
+		Requirements: []models.AuthRequirement{},
+		Handler:      runtime.FuncForPC(reflect.ValueOf(chain[len(chain)-1]).Pointer()).Name(),
+	}
+
+	for _, handler := range chain {
+		requirement, ok := requirementOf(handler)
+		if !ok {
+			continue
+		}
+		if requirement.Kind == models.AuthRequirementAuthentication {
+			for _, credential := range requirement.Values {
+				if !contains(route.Authentication, credential) {
+					route.Authentication = append(route.Authentication, credential)
+				}
+			}
+			continue
+		}
+		route.Requirements = append(route.Requirements, requirement)
+	}
+
+	route.Public = len(route.Authentication) == 0
+	return route
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package authmap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requirePermission(permission string) gin.HandlerFunc {
+	return Describe(func(c *gin.Context) {
+		c.Set("checked", permission)
+		c.Next()
+	}, models.AuthRequirement{Kind: models.AuthRequirementPermission, Values: []string{permission}})
+}
+
+func authenticate() gin.HandlerFunc {
+	return Describe(func(c *gin.Context) { c.Next() }, models.AuthRequirement{
+		Kind:   models.AuthRequirementAuthentication,
+		Values: []string{models.CredentialJWT, models.CredentialAPIKey},
+	})
+}
+
+func ok(c *gin.Context) {
+	c.String(http.StatusOK, c.GetString("checked"))
+}
+
+func TestDescribe_ShouldRunWrappedMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/items", requirePermission("items:read"), ok)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "items:read", w.Body.String())
+}
+
+func TestRequirementOf(t *testing.T) {
+	read := requirePermission("items:read")
+	write := requirePermission("items:write")
+
+	requirement, found := requirementOf(read)
+	require.True(t, found)
+	assert.Equal(t, []string{"items:read"}, requirement.Values)
+
+	requirement, found = requirementOf(write)
+	require.True(t, found)
+	assert.Equal(t, []string{"items:write"}, requirement.Values)
+
+	_, found = requirementOf(ok)
+	assert.False(t, found)
+	_, found = requirementOf(func(c *gin.Context) {})
+	assert.False(t, found)
+	_, found = requirementOf(nil)
+	assert.False(t, found)
+}
+
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/health", ok)
+
+	api := router.Group("/api")
+	api.Use(authenticate())
+	{
+		api.GET("/items", requirePermission("items:read"), ok)
+		api.POST("/items", requirePermission("items:write"), ok)
+		api.DELETE("/items/:id", authenticate(), requirePermission("items:write"), ok)
+	}
+
+	routes, err := Routes(router)
+	require.NoError(t, err)
+	require.Len(t, routes, 4)
+
+	assert.Equal(t, "/api/items", routes[0].Path)
+	assert.Equal(t, http.MethodGet, routes[0].Method)
+	assert.False(t, routes[0].Public)
+	assert.Equal(t, []string{models.CredentialJWT, models.CredentialAPIKey}, routes[0].Authentication)
+	assert.Equal(t, []models.AuthRequirement{{Kind: models.AuthRequirementPermission, Values: []string{"items:read"}}}, routes[0].Requirements)
+	assert.Contains(t, routes[0].Handler, "authmap.ok")
+
+	assert.Equal(t, http.MethodPost, routes[1].Method)
+	assert.Equal(t, []string{"items:write"}, routes[1].Requirements[0].Values)
+
+	assert.Equal(t, "/api/items/:id", routes[2].Path)
+	assert.Equal(t, []string{models.CredentialJWT, models.CredentialAPIKey}, routes[2].Authentication, "credentials are not repeated")
+	assert.Len(t, routes[2].Requirements, 1)
+
+	assert.Equal(t, "/health", routes[3].Path)
+	assert.True(t, routes[3].Public)
+	assert.Empty(t, routes[3].Authentication)
+	assert.NotNil(t, routes[3].Requirements)
+}
+
+func TestBuild(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health", ok)
+
+	authMap, err := Build(router, nil)
+	require.NoError(t, err)
+	assert.Len(t, authMap.HTTP, 1)
+	assert.NotNil(t, authMap.GRPC)
+	assert.False(t, authMap.GeneratedAt.IsZero())
+}
@@ -0,0 +1,138 @@
+// Package chaos injects latency and errors into calls to the gateway's dependencies
+// (database, Redis, SMTP and webhooks) so degradation can be exercised in staging.
+//
+// An Injector is only created outside production. Inject and Transport are safe to call on
+// a nil *Injector and do nothing, so services can hold one unconditionally.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// ErrInjected is returned by calls failed by an injected fault
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Injector holds the active faults and applies them to dependency calls
+type Injector struct {
+	mu     sync.RWMutex
+	faults map[models.FaultTarget]models.Fault
+
+	now   func() time.Time
+	roll  func() float64
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewInjector creates an injector with no active faults
+func NewInjector() *Injector {
+	return &Injector{
+		faults: make(map[models.FaultTarget]models.Fault),
+		now:    time.Now,
+		roll:   rand.Float64,
+		sleep:  sleepContext,
+	}
+}
+
+// Set replaces the fault for target. A duration of 0 keeps it until cleared.
+func (i *Injector) Set(target models.FaultTarget, latency time.Duration, errorRate float64, duration time.Duration) models.Fault {
+	now := i.now()
+	fault := models.Fault{
+		Target:    target,
+		LatencyMS: int(latency / time.Millisecond),
+		ErrorRate: errorRate,
+		CreatedAt: now,
+	}
+	if duration > 0 {
+		expiresAt := now.Add(duration)
+		fault.ExpiresAt = &expiresAt
+	}
+
+	i.mu.Lock()
+	i.faults[target] = fault
+	i.mu.Unlock()
+
+	return fault
+}
+
+// Clear removes the fault for target
+func (i *Injector) Clear(target models.FaultTarget) {
+	i.mu.Lock()
+	delete(i.faults, target)
+	i.mu.Unlock()
+}
+
+// ClearAll removes every fault
+func (i *Injector) ClearAll() {
+	i.mu.Lock()
+	i.faults = make(map[models.FaultTarget]models.Fault)
+	i.mu.Unlock()
+}
+
+// List returns the active faults in target order
+func (i *Injector) List() []models.Fault {
+	faults := make([]models.Fault, 0)
+	for _, target := range models.FaultTargets {
+		if fault, ok := i.active(target); ok {
+			faults = append(faults, fault)
+		}
+	}
+	return faults
+}
+
+// Inject applies the fault for target, if any: it waits for the configured latency and
+// then returns ErrInjected for the configured share of calls. It returns the context
+// error if ctx is done while waiting.
+func (i *Injector) Inject(ctx context.Context, target models.FaultTarget) error {
+	if i == nil {
+		return nil
+	}
+
+	fault, ok := i.active(target)
+	if !ok {
+		return nil
+	}
+
+	if fault.LatencyMS > 0 {
+		if err := i.sleep(ctx, time.Duration(fault.LatencyMS)*time.Millisecond); err != nil {
+			return err
+		}
+	}
+	if fault.ErrorRate > 0 && i.roll() < fault.ErrorRate {
+		return ErrInjected
+	}
+	return nil
+}
+
+// active returns the unexpired fault for target
+func (i *Injector) active(target models.FaultTarget) (models.Fault, bool) {
+	i.mu.RLock()
+	fault, ok := i.faults[target]
+	i.mu.RUnlock()
+
+	if ok && fault.ExpiresAt != nil && !i.now().Before(*fault.ExpiresAt) {
+		i.mu.Lock()
+		if current, still := i.faults[target]; still && current.CreatedAt.Equal(fault.CreatedAt) {
+			delete(i.faults, target)
+		}
+		i.mu.Unlock()
+		return models.Fault{}, false
+	}
+	return fault, ok
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
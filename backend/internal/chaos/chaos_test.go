@@ -0,0 +1,134 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInjector returns an injector with a fixed clock, roll and recorded sleeps
+func newTestInjector(roll float64) (*Injector, *time.Time, *[]time.Duration) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	i := NewInjector()
+	i.now = func() time.Time { return now }
+	i.roll = func() float64 { return roll }
+	i.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return ctx.Err()
+	}
+	return i, &now, &slept
+}
+
+func TestInjector_Inject(t *testing.T) {
+	t.Run("Should do nothing without a fault", func(t *testing.T) {
+		i, _, slept := newTestInjector(0)
+
+		assert.NoError(t, i.Inject(context.Background(), models.FaultTargetDB))
+		assert.Empty(t, *slept)
+	})
+
+	t.Run("Should do nothing on nil injector", func(t *testing.T) {
+		var i *Injector
+		assert.NoError(t, i.Inject(context.Background(), models.FaultTargetDB))
+	})
+
+	t.Run("Should add latency and fail by error rate", func(t *testing.T) {
+		i, _, slept := newTestInjector(0.2)
+		i.Set(models.FaultTargetRedis, 300*time.Millisecond, 0.5, 0)
+
+		err := i.Inject(context.Background(), models.FaultTargetRedis)
+		assert.ErrorIs(t, err, ErrInjected)
+		assert.Equal(t, []time.Duration{300 * time.Millisecond}, *slept)
+
+		assert.NoError(t, i.Inject(context.Background(), models.FaultTargetDB))
+	})
+
+	t.Run("Should pass calls above error rate", func(t *testing.T) {
+		i, _, _ := newTestInjector(0.7)
+		i.Set(models.FaultTargetSMTP, 0, 0.5, 0)
+
+		assert.NoError(t, i.Inject(context.Background(), models.FaultTargetSMTP))
+	})
+
+	t.Run("Should stop waiting when context is done", func(t *testing.T) {
+		i := NewInjector()
+		i.Set(models.FaultTargetDB, time.Hour, 0, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, i.Inject(ctx, models.FaultTargetDB), context.Canceled)
+	})
+
+	t.Run("Should expire faults", func(t *testing.T) {
+		i, now, _ := newTestInjector(0)
+		i.Set(models.FaultTargetWebhook, 0, 1, time.Minute)
+		assert.Error(t, i.Inject(context.Background(), models.FaultTargetWebhook))
+
+		*now = now.Add(time.Minute)
+		assert.NoError(t, i.Inject(context.Background(), models.FaultTargetWebhook))
+		assert.Empty(t, i.List())
+	})
+}
+
+func TestInjector_List(t *testing.T) {
+	i, _, _ := newTestInjector(0)
+	i.Set(models.FaultTargetWebhook, 0, 1, 0)
+	i.Set(models.FaultTargetDB, time.Second, 0, time.Minute)
+
+	faults := i.List()
+	require.Len(t, faults, 2)
+	assert.Equal(t, models.FaultTargetDB, faults[0].Target)
+	assert.Equal(t, 1000, faults[0].LatencyMS)
+	assert.NotNil(t, faults[0].ExpiresAt)
+	assert.Equal(t, models.FaultTargetWebhook, faults[1].Target)
+	assert.Nil(t, faults[1].ExpiresAt)
+
+	i.Clear(models.FaultTargetDB)
+	assert.Len(t, i.List(), 1)
+
+	i.ClearAll()
+	assert.Empty(t, i.List())
+}
+
+func TestInjector_QueryHook(t *testing.T) {
+	i, _, _ := newTestInjector(0)
+	hook := i.QueryHook()
+
+	ctx := hook.BeforeQuery(context.Background(), nil)
+	assert.NoError(t, ctx.Err())
+
+	i.Set(models.FaultTargetDB, 0, 1, 0)
+	ctx = hook.BeforeQuery(context.Background(), nil)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.ErrorIs(t, context.Cause(ctx), ErrInjected)
+}
+
+func TestInjector_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var nilInjector *Injector
+	assert.Equal(t, http.DefaultTransport, nilInjector.Transport(http.DefaultTransport))
+
+	i, _, _ := newTestInjector(0)
+	client := &http.Client{Transport: i.Transport(nil)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	i.Set(models.FaultTargetWebhook, 0, 1, 0)
+	_, err = client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrInjected))
+}
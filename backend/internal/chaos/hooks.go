@@ -0,0 +1,122 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// QueryHook returns a bun query hook that injects database faults.
+// Query hooks cannot fail a query directly, so a failed call cancels the query context.
+func (i *Injector) QueryHook() bun.QueryHook {
+	return queryHook{injector: i}
+}
+
+type queryHook struct {
+	injector *Injector
+}
+
+// BeforeQuery implements bun.QueryHook
+func (h queryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	err := h.injector.Inject(ctx, models.FaultTargetDB)
+	if err == nil {
+		return ctx
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	cancel(err)
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook
+func (h queryHook) AfterQuery(context.Context, *bun.QueryEvent) {}
+
+// RedisHook returns a go-redis hook that injects Redis faults
+func (i *Injector) RedisHook() redis.Hook {
+	return redisHook{injector: i}
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+// DialHook implements redis.Hook
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.Inject(ctx, models.FaultTargetRedis); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.injector.Inject(ctx, models.FaultTargetRedis); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// Transport wraps next so that requests made through it get webhook faults.
+// It returns next unchanged on a nil *Injector.
+func (i *Injector) Transport(next http.RoundTripper) http.RoundTripper {
+	if i == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{injector: i, next: next}
+}
+
+type roundTripper struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.injector.Inject(req.Context(), models.FaultTargetWebhook); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// QueryTracer returns a pgx tracer that injects database faults into the queries that bypass
+// bun. Like QueryHook, a failed call cancels the query context.
+func (i *Injector) QueryTracer() pgx.QueryTracer {
+	return queryTracer{injector: i}
+}
+
+type queryTracer struct {
+	injector *Injector
+}
+
+// TraceQueryStart implements pgx.QueryTracer
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	err := t.injector.Inject(ctx, models.FaultTargetDB)
+	if err == nil {
+		return ctx
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	cancel(err)
+	return ctx
+}
+
+// TraceQueryEnd implements pgx.QueryTracer
+func (t queryTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
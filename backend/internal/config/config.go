@@ -5,28 +5,42 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server    ServerConfig
-	GRPC      GRPCConfig
-	Database  DatabaseConfig
-	Redis     RedisConfig
-	JWT       JWTConfig
-	OAuth     OAuthConfig
-	SMTP      SMTPConfig
-	SMS       SMSConfig
-	CORS      CORSConfig
-	RateLimit RateLimitConfig
-	Security  SecurityConfig
-	Metrics   MetricsConfig
-	GeoIP     GeoIPConfig
-	OIDC      OIDCConfig
-	LDAP      LDAPConfig
-	SAML      SAMLConfig
+	Server      ServerConfig
+	GRPC        GRPCConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	JWT         JWTConfig
+	OAuth       OAuthConfig
+	SMTP        SMTPConfig
+	SMS         SMSConfig
+	CORS        CORSConfig
+	RateLimit   RateLimitConfig
+	Security    SecurityConfig
+	Metrics     MetricsConfig
+	GeoIP       GeoIPConfig
+	OIDC        OIDCConfig
+	LDAP        LDAPConfig
+	SAML        SAMLConfig
+	Chaos       ChaosConfig
+	Logging     LoggingConfig
+	AccessLog   AccessLogConfig
+	Credentials CredentialsConfig
+	SignedURLs  SignedURLConfig
+	MTLS        MTLSConfig
+	Risk        RiskConfig
+	SLO         SLOConfig
+	EventBus    EventBusConfig
+	Tracing     TracingConfig
+	AuditExport AuditExportConfig
+	DataExport  DataExportConfig
+	Reload      ReloadConfig
 }
 
 // ServerConfig contains server-related configuration
@@ -46,6 +60,20 @@ type GRPCConfig struct {
 	TLSKey               string // Path to TLS private key file
 	ReflectionEnabled    bool   // Enable gRPC reflection (disable in production)
 	MaxRequestsPerMinute int    // Rate limit: max requests per minute per API key
+
+	// Mutual TLS. Certificate, key and client CA files are read again when they change, so
+	// certificates rotated on disk (cert-manager, spiffe-helper) are picked up without a restart
+	TLSClientCA        string   // Path to a PEM bundle of CAs trusted to sign client certificates
+	TLSClientAuth      string   // none, optional or require; require when a client CA is set
+	TLSClientSPIFFEIDs []string // Client certificates must carry one of these SPIFFE IDs (empty = any)
+
+	// Connection draining, so rolling deployments and load balancers move clients gradually
+	MaxConnectionAge      time.Duration // Connections get GOAWAY after this age (0 = never)
+	MaxConnectionAgeGrace time.Duration // Time calls get to finish after GOAWAY before the connection is closed
+	MaxStreamDuration     time.Duration // Streams end with Unavailable after this long, so clients reconnect (0 = never)
+	ShutdownTimeout       time.Duration // Time streams get to end on shutdown before the server is stopped
+
+	HealthCheckInterval time.Duration // How often the grpc.health.v1 statuses are refreshed from database and Redis checks
 }
 
 // Validate validates gRPC configuration
@@ -58,28 +86,74 @@ func (c *GRPCConfig) Validate() error {
 			return fmt.Errorf("GRPC_TLS_KEY_FILE is required when GRPC_TLS_ENABLED is true")
 		}
 	}
+	switch c.ClientAuthMode() {
+	case "none":
+		if len(c.TLSClientSPIFFEIDs) > 0 {
+			return fmt.Errorf("GRPC_TLS_CLIENT_SPIFFE_IDS requires client certificates, set GRPC_TLS_CLIENT_AUTH")
+		}
+	case "optional", "require":
+		if !c.TLSEnabled {
+			return fmt.Errorf("GRPC_TLS_CLIENT_AUTH requires GRPC_TLS_ENABLED")
+		}
+		if c.TLSClientCA == "" {
+			return fmt.Errorf("GRPC_TLS_CLIENT_CA_FILE is required when GRPC_TLS_CLIENT_AUTH is %s", c.ClientAuthMode())
+		}
+	default:
+		return fmt.Errorf("GRPC_TLS_CLIENT_AUTH must be none, optional or require")
+	}
+	for _, id := range c.TLSClientSPIFFEIDs {
+		if !strings.HasPrefix(id, "spiffe://") {
+			return fmt.Errorf("GRPC_TLS_CLIENT_SPIFFE_IDS entry %q is not a spiffe:// URI", id)
+		}
+	}
+	// Streams on an aged connection must end on their own before the grace period closes it
+	if c.MaxConnectionAge > 0 && c.MaxStreamDuration > 0 && c.MaxConnectionAgeGrace < c.MaxStreamDuration {
+		return fmt.Errorf("GRPC_MAX_CONNECTION_AGE_GRACE must not be shorter than GRPC_MAX_STREAM_DURATION")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("GRPC_SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.HealthCheckInterval <= 0 {
+		return fmt.Errorf("GRPC_HEALTH_CHECK_INTERVAL must be positive")
+	}
 	return nil
 }
 
+// ClientAuthMode returns how client certificates are checked: none, optional or require.
+// It defaults to require when a client CA is set and to none otherwise.
+func (c *GRPCConfig) ClientAuthMode() string {
+	if c.TLSClientAuth != "" {
+		return c.TLSClientAuth
+	}
+	if c.TLSClientCA != "" {
+		return "require"
+	}
+	return "none"
+}
+
 // DatabaseConfig contains database-related configuration
 type DatabaseConfig struct {
-	Host           string
-	Port           string
-	User           string
-	Password       string
-	DBName         string
-	SSLMode        string
-	MaxOpenConns   int
-	MaxIdleConns   int
-	EnableQueryLog bool // Enable query logging (should be false in production)
+	Host             string
+	Port             string
+	User             string
+	Password         string
+	DBName           string
+	SSLMode          string
+	MaxOpenConns     int
+	MaxIdleConns     int
+	EnableQueryLog   bool // Enable query logging (should be false in production)
+	PreparedQueries  bool // Serve hot lookups from pgx prepared statements
+	PreparedMaxConns int  // Pool size for the prepared statements
 }
 
 // RedisConfig contains Redis-related configuration
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host             string
+	Port             string
+	Password         string
+	DB               int
+	UserCacheEnabled bool          // Cache users for token validation and userinfo
+	UserCacheTTL     time.Duration // Upper bound on how long a missed invalidation leaves a user stale
 }
 
 // JWTConfig contains JWT-related configuration
@@ -88,6 +162,21 @@ type JWTConfig struct {
 	RefreshSecret  string
 	AccessExpires  time.Duration
 	RefreshExpires time.Duration
+	Issuer         string        // iss claim for first-party tokens (e.g., https://auth.example.com); empty disables the check
+	Audience       []string      // aud claim for first-party tokens; empty disables the check
+	ClockSkew      time.Duration // tolerated clock drift when checking exp and nbf of all tokens
+
+	// Asymmetric signing (RS256 or ES256). HS256 keeps the shared-secret behaviour.
+	SigningAlgorithm string
+	SigningKeyPath   string // Empty reuses the OIDC key manager when OIDC keys are configured
+	SigningKey       string // PEM private key, e.g. a secret reference; takes precedence over SigningKeyPath
+	SigningKeyID     string
+	AcceptHMAC       bool // Keep accepting HMAC-signed tokens while migrating to asymmetric signing
+}
+
+// IsAsymmetric reports whether first-party tokens are signed with an asymmetric key
+func (c *JWTConfig) IsAsymmetric() bool {
+	return c.SigningAlgorithm != "" && c.SigningAlgorithm != "HS256"
 }
 
 // Validate validates JWT configuration
@@ -95,6 +184,17 @@ type JWTConfig struct {
 func (c *JWTConfig) Validate() error {
 	const minSecretLength = 32 // Minimum 32 characters for HS256
 
+	switch c.SigningAlgorithm {
+	case "", "HS256", "RS256", "ES256":
+	default:
+		return fmt.Errorf("JWT_SIGNING_ALGORITHM must be one of HS256, RS256, ES256 (got %q)", c.SigningAlgorithm)
+	}
+
+	// Shared secrets are only needed when they sign or verify tokens
+	if c.IsAsymmetric() && !c.AcceptHMAC {
+		return nil
+	}
+
 	if c.AccessSecret == "" {
 		return fmt.Errorf("JWT_ACCESS_SECRET is required")
 	}
@@ -123,9 +223,22 @@ type OAuthConfig struct {
 	Yandex           OAuthProvider
 	GitHub           OAuthProvider
 	Instagram        OAuthProvider
+	Apple            AppleOAuthProvider
+	Microsoft        MicrosoftOAuthProvider
+	LinkedIn         OAuthProvider
 	OneC             CustomOAuthProvider
+	OIDC             OIDCOAuthProvider
 	FrontendURL      string
 	TelegramBotToken string
+	// Keep provider access/refresh tokens (encrypted with Security.EncryptionKey)
+	// so applications can call provider APIs on behalf of users
+	StoreProviderTokens bool
+	// What happens when a social login email belongs to an existing account that is not
+	// linked to the provider account: reject, auto_link or confirm_password
+	EmailCollision string
+	// Providers switched off for every application, e.g. during a provider outage;
+	// can be changed by a config reload
+	DisabledProviders []string
 }
 
 // OAuthProvider represents a single OAuth provider configuration
@@ -135,6 +248,25 @@ type OAuthProvider struct {
 	CallbackURL  string
 }
 
+// AppleOAuthProvider represents the Sign in with Apple configuration. Apple has no static
+// client secret: it is a JWT signed with the private key (.p8) of the team.
+type AppleOAuthProvider struct {
+	ClientID    string // Services ID
+	TeamID      string
+	KeyID       string
+	PrivateKey  string
+	CallbackURL string
+}
+
+// MicrosoftOAuthProvider represents the Microsoft Entra ID configuration
+type MicrosoftOAuthProvider struct {
+	ClientID     string
+	ClientSecret string
+	// Tenant ID or domain, or common, organizations or consumers
+	TenantID    string
+	CallbackURL string
+}
+
 // CustomOAuthProvider represents a custom OAuth provider with configurable URLs
 type CustomOAuthProvider struct {
 	Enabled      bool
@@ -147,6 +279,29 @@ type CustomOAuthProvider struct {
 	Scopes       string
 }
 
+// OIDCOAuthProvider represents a generic OpenID Connect provider such as Keycloak, Auth0
+// or a corporate IdP. Endpoints that are not set are read from the discovery document of
+// the issuer; claim names that are not set are the standard ones.
+type OIDCOAuthProvider struct {
+	Enabled      bool
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	CallbackURL  string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Scopes       string
+
+	ClaimSubject       string
+	ClaimEmail         string
+	ClaimEmailVerified string
+	ClaimName          string
+	ClaimUsername      string
+	ClaimPicture       string
+}
+
 // SMTPConfig contains SMTP email configuration
 type SMTPConfig struct {
 	Host      string
@@ -159,7 +314,7 @@ type SMTPConfig struct {
 
 // SMSConfig contains SMS provider configuration
 type SMSConfig struct {
-	Provider string // "twilio", "aws_sns", "vonage", "mock"
+	Provider string // "twilio", "aws_sns", "vonage", "smpp", "mock"
 	Enabled  bool
 
 	// Twilio configuration
@@ -173,28 +328,91 @@ type SMSConfig struct {
 	AWSSecretAccessKey string
 	AWSSenderID        string
 
+	// Vonage configuration
+	VonageAPIKey     string
+	VonageAPISecret  string
+	VonageFromNumber string
+
+	// SMPP configuration
+	SMPPAddr       string // SMSC host:port
+	SMPPSystemID   string
+	SMPPPassword   string
+	SMPPSystemType string
+	SMPPSourceAddr string
+	SMPPTLS        bool
+
+	// CountrySenders maps calling codes such as "+44" to the sender used for that country
+	CountrySenders map[string]string
+
+	// Delivery status callbacks. Providers are told to report to
+	// StatusCallbackURL/<provider>?token=<StatusCallbackToken>; both are required to enable them.
+	StatusCallbackURL   string
+	StatusCallbackToken string
+
 	// Rate limiting for SMS
 	SMSMaxPerHour   int
 	SMSMaxPerDay    int
 	SMSMaxPerNumber int // Max SMS per phone number per hour
 }
 
-// CORSConfig contains CORS-related configuration
+// Validate checks SMS sender and delivery callback configuration
+func (c *SMSConfig) Validate() error {
+	for prefix, sender := range c.CountrySenders {
+		if len(prefix) < 2 || prefix[0] != '+' {
+			return fmt.Errorf("SMS_COUNTRY_SENDERS: %q is not a calling code such as +44", prefix)
+		}
+		if sender == "" {
+			return fmt.Errorf("SMS_COUNTRY_SENDERS: no sender for %s", prefix)
+		}
+	}
+	if (c.StatusCallbackURL == "") != (c.StatusCallbackToken == "") {
+		return fmt.Errorf("SMS_STATUS_CALLBACK_URL and SMS_STATUS_CALLBACK_TOKEN must be set together")
+	}
+	if c.StatusCallbackToken != "" && len(c.StatusCallbackToken) < 32 {
+		return fmt.Errorf("SMS_STATUS_CALLBACK_TOKEN must be at least 32 characters long")
+	}
+	return nil
+}
+
+// CORSConfig contains CORS-related configuration. Each request is answered with the policy
+// of the route group it targets; routes outside the admin and OAuth groups, such as the
+// public auth API, use Default.
 type CORSConfig struct {
-	AllowedOrigins   []string
+	Default CORSPolicy
+	Admin   CORSPolicy // /api/admin
+	OAuth   CORSPolicy // /oauth and /.well-known
+}
+
+// CORSPolicy is the CORS policy of a route group
+type CORSPolicy struct {
+	AllowedOrigins   []string // Empty denies cross-origin requests
 	AllowedMethods   []string
 	AllowedHeaders   []string
 	AllowCredentials bool
+	MaxAge           time.Duration // How long browsers may cache preflight responses
 }
 
 // Validate checks for insecure CORS configurations
 func (c *CORSConfig) Validate() error {
-	if c.AllowCredentials {
-		for _, origin := range c.AllowedOrigins {
-			if origin == "*" {
-				return fmt.Errorf("CORS: wildcard origin '*' with AllowCredentials is insecure (RFC 6454)")
+	policies := []struct {
+		name   string
+		policy CORSPolicy
+	}{
+		{"default", c.Default},
+		{"admin", c.Admin},
+		{"oauth", c.OAuth},
+	}
+	for _, p := range policies {
+		if p.policy.AllowCredentials {
+			for _, origin := range p.policy.AllowedOrigins {
+				if origin == "*" {
+					return fmt.Errorf("CORS %s policy: wildcard origin '*' with AllowCredentials is insecure (RFC 6454)", p.name)
+				}
 			}
 		}
+		if p.policy.MaxAge < 0 {
+			return fmt.Errorf("CORS %s policy: max age must not be negative", p.name)
+		}
 	}
 	return nil
 }
@@ -209,6 +427,16 @@ type RateLimitConfig struct {
 	RefreshWindow time.Duration // Time window for refresh token rate limiting
 	APIMax        int
 	APIWindow     time.Duration
+	// Per-user limit on authenticated API requests; 0 disables it
+	UserMax    int
+	UserWindow time.Duration
+	// Per-client limit on the OAuth token, introspection and revocation endpoints; 0 disables it
+	ClientMax    int
+	ClientWindow time.Duration
+	// Token endpoint requests over ClientMax wait up to TokenQueueMaxWait for capacity instead
+	// of being rejected, at most TokenQueueSize at a time per instance; 0 rejects them at once
+	TokenQueueMaxWait time.Duration
+	TokenQueueSize    int
 }
 
 // SecurityConfig contains security-related configuration
@@ -216,12 +444,24 @@ type SecurityConfig struct {
 	BcryptCost                    int
 	TokenBlacklistCleanupInterval time.Duration
 	PasswordPolicy                PasswordPolicyConfig
-	JITProvisioning               bool   // Enable Just-In-Time user provisioning for OAuth/OIDC logins
+	JITProvisioning               bool // Enable Just-In-Time user provisioning for OAuth/OIDC logins
 	EncryptionKey                 string
 	StrictTokenBinding            bool   // Reject refresh if IP/UserAgent changed
 	CSRFEnabled                   bool   // Enable Double Submit Cookie CSRF protection
 	OTPHMACSecret                 string // HMAC secret for OTP code hashing (prevents brute-force on 6-digit codes)
 	MaxActiveSessions             int    // Maximum active sessions per user (0 = unlimited)
+	AccountRecovery               AccountRecoveryConfig
+	LoginIdentifiers              []string // Identifiers accepted by password sign-in: email, phone, username
+	EmailVerification             EmailVerificationConfig
+	GuestSessions                 GuestSessionConfig
+	Retention                     RetentionConfig
+	BlacklistFilter               BlacklistFilterConfig
+	AccountLockout                AccountLockoutConfig
+	WebAuthn                      WebAuthnConfig
+	MagicLink                     MagicLinkConfig
+	SessionStorage                SessionStorageConfig
+	// How long responses to requests with an Idempotency-Key are replayed
+	IdempotencyKeyTTL time.Duration
 }
 
 // Validate checks security configuration for common misconfigurations
@@ -232,6 +472,45 @@ func (c *SecurityConfig) Validate(env string) error {
 	if len(c.OTPHMACSecret) < 32 {
 		return fmt.Errorf("OTP_HMAC_SECRET must be at least 32 characters long (current: %d)", len(c.OTPHMACSecret))
 	}
+	switch c.EmailVerification.Enforcement {
+	case "advisory", "grace", "block":
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION_ENFORCEMENT must be advisory, grace or block (current: %q)", c.EmailVerification.Enforcement)
+	}
+	if c.AccountLockout.Enabled {
+		if c.AccountLockout.MaxFailedAttempts < 1 {
+			return fmt.Errorf("ACCOUNT_LOCKOUT_MAX_ATTEMPTS must be at least 1 (current: %d)", c.AccountLockout.MaxFailedAttempts)
+		}
+		if c.AccountLockout.Window <= 0 || c.AccountLockout.Duration <= 0 {
+			return fmt.Errorf("ACCOUNT_LOCKOUT_WINDOW and ACCOUNT_LOCKOUT_DURATION must be positive")
+		}
+		if c.AccountLockout.MaxDuration < c.AccountLockout.Duration {
+			return fmt.Errorf("ACCOUNT_LOCKOUT_MAX_DURATION must not be shorter than ACCOUNT_LOCKOUT_DURATION")
+		}
+	}
+	if c.WebAuthn.RPID != "" && len(c.WebAuthn.RPOrigins) == 0 {
+		return fmt.Errorf("WEBAUTHN_RP_ORIGINS must be set when WEBAUTHN_RP_ID is set")
+	}
+	if c.MagicLink.Enabled {
+		if c.MagicLink.RedirectURL == "" {
+			return fmt.Errorf("MAGIC_LINK_REDIRECT_URL must be set when MAGIC_LINK_ENABLED is true")
+		}
+		if c.MagicLink.TTL <= 0 {
+			return fmt.Errorf("MAGIC_LINK_TTL must be positive")
+		}
+	}
+	if c.Retention.AuditLogDays < 0 || c.Retention.SessionDays < 0 || c.Retention.InactiveAccountMonths < 0 {
+		return fmt.Errorf("RETENTION_AUDIT_LOG_DAYS, RETENTION_SESSION_DAYS and RETENTION_INACTIVE_ACCOUNT_MONTHS must not be negative")
+	}
+	switch c.SessionStorage.Backend {
+	case SessionStoragePostgres:
+	case SessionStorageRedis:
+		if c.SessionStorage.ArchiveEnabled && (c.SessionStorage.ArchiveInterval <= 0 || c.SessionStorage.ArchiveBatchSize < 1) {
+			return fmt.Errorf("SESSION_ARCHIVE_INTERVAL and SESSION_ARCHIVE_BATCH_SIZE must be positive")
+		}
+	default:
+		return fmt.Errorf("SESSION_STORAGE must be postgres or redis (current: %q)", c.SessionStorage.Backend)
+	}
 	return nil
 }
 
@@ -242,9 +521,93 @@ type PasswordPolicyConfig struct {
 	RequireLowercase bool
 	RequireNumbers   bool
 	RequireSpecial   bool
-	MaxLength        int  // 0 means no maximum
-	CommonPasswords  bool // Check against common passwords list
-	CheckCompromised bool // Check passwords against HaveIBeenPwned API
+	MaxLength        int    // 0 means no maximum
+	CommonPasswords  bool   // Check against common passwords list
+	DictionaryFile   string // Extra common passwords for the dictionary check, one per line
+	CheckCompromised bool   // Check passwords against HaveIBeenPwned API
+	HistoryCount     int    // Number of previous passwords that cannot be reused (0 = disabled)
+	MaxAgeDays       int    // Default password max age in days; role/group policies can be stricter (0 = no default)
+	ExpiryWarnDays   int    // Days before expiry to email a warning (0 = no warnings)
+}
+
+// AccountLockoutConfig contains configuration for locking accounts after repeated failed sign-ins
+type AccountLockoutConfig struct {
+	Enabled           bool
+	MaxFailedAttempts int           // Failed password sign-ins within Window that lock the account
+	Window            time.Duration // Failed attempts older than this are forgotten
+	Duration          time.Duration // First lockout; each consecutive lockout doubles it
+	MaxDuration       time.Duration // Cap of the doubling lockout duration
+}
+
+// WebAuthnConfig contains configuration for FIDO2 security keys as a second factor.
+// WebAuthn is disabled while RPID is empty.
+type WebAuthnConfig struct {
+	RPID          string   // Relying party ID: the domain users sign in on, without scheme or port
+	RPDisplayName string   // Name authenticators show when registering a key
+	RPOrigins     []string // Origins allowed to run WebAuthn ceremonies, e.g. https://auth.example.com
+}
+
+// MagicLinkConfig contains configuration for passwordless sign-in by emailed one-click links.
+// Links point at the gateway (Server.ExternalURL), which signs the user in and redirects to the app.
+type MagicLinkConfig struct {
+	Enabled             bool
+	TTL                 time.Duration // How long an emailed link can be used
+	RedirectURL         string        // App URL the user lands on when the request names none
+	AllowedRedirectURLs []string      // Other app URLs requests may name, besides the application's callback URLs
+}
+
+// AccountRecoveryConfig contains configuration for recovering accounts that lost both password and 2FA
+type AccountRecoveryConfig struct {
+	Steps       []string      // Proofing steps: secondary_email, sms, admin_review (steps the user cannot complete are skipped)
+	RequestTTL  time.Duration // How long a recovery request stays usable
+	MaxAttempts int           // Failed verification attempts before a request is cancelled
+}
+
+// EmailVerificationConfig contains configuration for enforcing email verification
+type EmailVerificationConfig struct {
+	Enforcement     string // advisory (never enforced), grace (limited access for GracePeriodDays, then blocked) or block
+	GracePeriodDays int    // Days after sign-up an unverified user may still sign in under grace enforcement
+	ReminderDays    []int  // Days after sign-up to email verification reminders (empty = no reminders)
+}
+
+// GuestSessionConfig contains configuration for anonymous guest accounts
+type GuestSessionConfig struct {
+	Enabled       bool // Allow issuing guest sessions without credentials
+	RetentionDays int  // Days a guest account is kept before it is deleted unless upgraded (0 = kept forever)
+}
+
+// RetentionConfig contains how long personal data is kept (0 = kept forever)
+type RetentionConfig struct {
+	AuditLogDays          int // Days audit log entries are kept
+	SessionDays           int // Days ended (expired or revoked) sessions are kept
+	InactiveAccountMonths int // Months without sign-in or session activity after which an account is erased
+}
+
+// Enabled reports whether any retention policy is configured
+func (c RetentionConfig) Enabled() bool {
+	return c.AuditLogDays > 0 || c.SessionDays > 0 || c.InactiveAccountMonths > 0
+}
+
+// Session storage backends
+const (
+	SessionStoragePostgres = "postgres"
+	SessionStorageRedis    = "redis"
+)
+
+// SessionStorageConfig contains configuration for where sessions are stored
+type SessionStorageConfig struct {
+	Backend          string        // postgres, or redis to keep sessions out of the database
+	ArchiveEnabled   bool          // With the redis backend, copy changed sessions to PostgreSQL in the background
+	ArchiveInterval  time.Duration // How often changed sessions are copied to PostgreSQL
+	ArchiveBatchSize int           // Sessions copied per database write
+}
+
+// BlacklistFilterConfig contains configuration for the in-memory token blacklist bloom filter
+type BlacklistFilterConfig struct {
+	Enabled           bool          // Skip Redis/DB blacklist lookups for tokens the filter knows are not blacklisted
+	ExpectedEntries   int           // Blacklist size the filter is sized for; it grows on rebuild when exceeded
+	FalsePositiveRate float64       // Share of non-blacklisted tokens still looked up in Redis
+	RebuildInterval   time.Duration // How often the filter is rebuilt from the database, dropping expired entries
 }
 
 type MetricsConfig struct {
@@ -270,6 +633,241 @@ type LDAPConfig struct {
 	AutoSyncEnabled     bool          // Enable automatic periodic sync
 }
 
+// ChaosConfig contains fault-injection configuration
+type ChaosConfig struct {
+	// Enabled exposes the admin API that injects latency and errors into the database,
+	// Redis, SMTP and webhook calls. Refused in production.
+	Enabled bool
+}
+
+// CredentialsConfig contains configuration of the credentials broker, which exchanges a
+// gateway token for short-lived Postgres logins and SSH certificates
+type CredentialsConfig struct {
+	Enabled    bool
+	DefaultTTL time.Duration // Lifetime of credentials when the request does not ask for one
+	MaxTTL     time.Duration // Upper bound for every target
+
+	// Connection string of the Postgres server logins are created on. The role needs
+	// CREATEROLE and ADMIN OPTION on the roles targets grant. Empty disables Postgres targets.
+	PostgresDSN string
+
+	// Path to the SSH CA private key user certificates are signed with. Empty disables
+	// SSH targets.
+	SSHCAKeyPath string
+}
+
+// SignedURLConfig contains configuration of signed URLs, which let users download protected
+// resources without putting a bearer token in the query string
+type SignedURLConfig struct {
+	Enabled    bool
+	Secret     string        // HMAC key; resource services that verify URLs offline need it too
+	DefaultTTL time.Duration // Lifetime of URLs when the request does not ask for one
+	MaxTTL     time.Duration
+
+	// Scheme and host of every URL that may be signed, e.g. https://files.example.com
+	AllowedOrigins []string
+}
+
+// RiskConfig contains configuration of the risk engine, which scores sign-in attempts
+// against the risk policies managed through the admin API
+type RiskConfig struct {
+	Enabled bool
+
+	// Plain-text list of Tor exit node addresses, one per line. Empty disables the
+	// tor_exit_node signal.
+	TorExitListURL     string
+	TorExitListRefresh time.Duration
+}
+
+// SLOConfig contains configuration of the built-in service level objectives of the
+// critical endpoints (sign-in, token, token validation)
+type SLOConfig struct {
+	Enabled bool
+	// Window the error budgets are computed over
+	Window time.Duration
+	// Minimum time between two budget burn alerts of the same SLO and severity
+	AlertCooldown time.Duration
+}
+
+// EventBusConfig contains configuration of the event bus auth events are published to,
+// alongside webhooks
+type EventBusConfig struct {
+	// Provider is nats or kafka; empty disables the event bus
+	Provider string
+	// Topic events go to unless Topics names another one for their type
+	DefaultTopic string
+	// Topics maps event types to topics (NATS subjects), e.g. user.signed_in:auth.signins
+	Topics map[string]string
+	// Attempts an event gets to be published before its job is dead
+	MaxAttempts int
+
+	NATSURL      string // nats://host:4222, or tls:// for TLS
+	NATSUser     string
+	NATSPassword string
+	NATSToken    string
+	// Wait for the acknowledgement of the JetStream stream capturing the subject
+	NATSJetStream bool
+
+	KafkaRESTURL  string // Base URL of the Kafka REST proxy
+	KafkaUsername string
+	KafkaPassword string
+}
+
+// Validate checks the event bus provider and its connection settings
+func (c *EventBusConfig) Validate() error {
+	switch c.Provider {
+	case "":
+		return nil
+	case "nats":
+		if c.NATSURL == "" {
+			return fmt.Errorf("EVENT_BUS_NATS_URL is required when EVENT_BUS_PROVIDER is nats")
+		}
+	case "kafka":
+		if c.KafkaRESTURL == "" {
+			return fmt.Errorf("EVENT_BUS_KAFKA_REST_URL is required when EVENT_BUS_PROVIDER is kafka")
+		}
+	default:
+		return fmt.Errorf("EVENT_BUS_PROVIDER must be nats or kafka (got %q)", c.Provider)
+	}
+	if c.DefaultTopic == "" {
+		return fmt.Errorf("EVENT_BUS_DEFAULT_TOPIC must not be empty")
+	}
+	for eventType, topic := range c.Topics {
+		if topic == "" {
+			return fmt.Errorf("EVENT_BUS_TOPICS: no topic for %s", eventType)
+		}
+	}
+	return nil
+}
+
+// TracingConfig contains OpenTelemetry tracing configuration. Spans are exported over OTLP
+// HTTP; trace context is propagated with W3C traceparent headers.
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	// OTLP HTTP endpoint of the collector, host:port or a URL such as http://collector:4318
+	Endpoint string
+	// Send spans over plain HTTP instead of HTTPS
+	Insecure bool
+	// Extra headers sent to the collector, e.g. authorization:Bearer xyz
+	Headers map[string]string
+	// Share of new traces that are sampled; traces started upstream follow the caller
+	SampleRatio float64
+}
+
+// Validate checks the tracing exporter settings
+func (c *TracingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT is required when TRACING_ENABLED is true")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1 (got %v)", c.SampleRatio)
+	}
+	return nil
+}
+
+// AuditExportConfig contains configuration of exporting audit logs to SIEMs. Each sink is
+// enabled by its destination: a syslog address or an S3 bucket.
+type AuditExportConfig struct {
+	// Delay is how long after a window ends its logs are exported, so logs written
+	// asynchronously make it in
+	Delay time.Duration
+
+	SyslogAddress   string // Collector address, host:port; empty disables the syslog sink
+	SyslogNetwork   string // tcp, udp or tls
+	SyslogFormat    string // cef or json
+	SyslogTLSCAFile string // Extra CAs trusted for the collector's certificate
+	SyslogInterval  time.Duration
+
+	S3Bucket          string // Empty disables the S3 sink
+	S3Prefix          string
+	S3Region          string
+	S3Endpoint        string // S3-compatible storage such as MinIO
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3Format          string // json or cef
+}
+
+// DataExportConfig contains configuration of user data exports (GDPR portability). Download
+// links are signed with SIGNED_URL_SECRET.
+type DataExportConfig struct {
+	Enabled bool
+	TTL     time.Duration // How long a generated export is kept before it is deleted
+	LinkTTL time.Duration // Lifetime of a download link
+}
+
+// ReloadConfig configures reloading the configuration while the server runs. A reload
+// reads the environment and the configuration sources again (see sources.go) on SIGHUP,
+// and when the config file changes if WatchInterval is set.
+type ReloadConfig struct {
+	File          string        // YAML config file (CONFIG_FILE)
+	WatchInterval time.Duration // How often the config file is checked for changes, 0 disables
+}
+
+// Validate checks the audit export sinks
+func (c *AuditExportConfig) Validate() error {
+	if c.Delay < 0 {
+		return fmt.Errorf("AUDIT_EXPORT_DELAY must not be negative")
+	}
+	if c.SyslogAddress != "" {
+		switch c.SyslogNetwork {
+		case "tcp", "udp", "tls":
+		default:
+			return fmt.Errorf("AUDIT_EXPORT_SYSLOG_NETWORK must be tcp, udp or tls (got %q)", c.SyslogNetwork)
+		}
+		if c.SyslogFormat != "cef" && c.SyslogFormat != "json" {
+			return fmt.Errorf("AUDIT_EXPORT_SYSLOG_FORMAT must be cef or json (got %q)", c.SyslogFormat)
+		}
+		if c.SyslogInterval <= 0 || c.SyslogInterval > time.Hour {
+			return fmt.Errorf("AUDIT_EXPORT_SYSLOG_INTERVAL must be between 0 and 1h (got %s)", c.SyslogInterval)
+		}
+	}
+	if c.S3Bucket != "" && c.S3Format != "json" && c.S3Format != "cef" {
+		return fmt.Errorf("AUDIT_EXPORT_S3_FORMAT must be json or cef (got %q)", c.S3Format)
+	}
+	return nil
+}
+
+// MTLSConfig contains configuration of client certificates, which certificate-bound access
+// tokens (RFC 8705) are bound to
+type MTLSConfig struct {
+	// Header a TLS-terminating proxy forwards the client certificate in, e.g.
+	// X-SSL-Client-Cert. Only honoured from TRUSTED_PROXIES; empty ignores forwarded
+	// certificates.
+	ClientCertHeader string
+}
+
+// LoggingConfig contains log output configuration; the level is Server.LogLevel
+type LoggingConfig struct {
+	Format string // json or text
+
+	// Repeated debug/info entries per second: the first SamplingInitial are logged, then
+	// every SamplingThereafter-th. 0 disables sampling.
+	SamplingInitial    int
+	SamplingThereafter int
+	SamplingTick       time.Duration
+
+	// Tokens, passwords and secrets are always redacted; these add to them
+	RedactPII  bool     // Mask email addresses and phone numbers
+	RedactKeys []string // Further field names to redact
+
+	// Log levels changed at runtime through the admin API revert after this long
+	LevelRevertAfter time.Duration
+}
+
+// AccessLogConfig contains access log configuration. When enabled, HTTP requests and gRPC
+// calls are logged here instead of in the application log.
+type AccessLogConfig struct {
+	Enabled     bool
+	Format      string   // json or ncsa (combined log format)
+	Output      string   // stdout, stderr or a file path
+	AnonymizeIP bool     // Drop the last IPv4 octet / keep the IPv6 /48
+	Fields      []string // Fields to write; empty writes all
+}
+
 // SAMLConfig contains SAML 2.0 IdP configuration
 type SAMLConfig struct {
 	Enabled     bool
@@ -289,6 +887,7 @@ type OIDCConfig struct {
 
 	// Signing key configuration
 	SigningKeyPath   string
+	SigningKey       string // PEM private key, e.g. a secret reference; takes precedence over SigningKeyPath
 	SigningKeyID     string
 	SigningAlgorithm string // RS256 or ES256
 
@@ -296,6 +895,9 @@ type OIDCConfig struct {
 	// Format: "kid1:/path/to/key1.pem,kid2:/path/to/key2.pem"
 	AdditionalKeys string
 
+	// Automatic signing key rotation; replaces the keys above, which are imported once
+	KeyRotation OIDCKeyRotationConfig
+
 	// Token TTL defaults (can be overridden per client)
 	AccessTokenTTL  int // seconds, default 900 (15 min)
 	RefreshTokenTTL int // seconds, default 604800 (7 days)
@@ -306,16 +908,48 @@ type OIDCConfig struct {
 	// Device flow settings
 	DeviceCodeInterval int // seconds, default 5
 
+	// Largest client logo accepted for upload, in bytes
+	ClientLogoMaxBytes int // default 262144 (256 KiB)
+
+	// Signs the state of the consent and device verification pages so any replica can
+	// serve the next step. Must match across replicas; defaults to JWT_ACCESS_SECRET.
+	StateSecret string
+
+	// Back-channel logout: attempts per client before giving up, and how long each may take
+	BackchannelLogoutMaxAttempts int           // default 5
+	BackchannelLogoutTimeout     time.Duration // default 5s
+
 	// Enable/disable OIDC provider
 	Enabled bool
 }
 
+// OIDCKeyRotationConfig configures automatic OIDC signing key rotation. Keys are kept in the
+// database encrypted with ENCRYPTION_KEY so all replicas sign with the same key.
+type OIDCKeyRotationConfig struct {
+	Enabled bool
+	// Interval is how long each key signs new tokens
+	Interval time.Duration // default 720h
+	// PrePublish is how long a new key is published in the JWKS before it signs, so relying
+	// parties that cache the JWKS know it in time
+	PrePublish time.Duration // default 24h
+	// Grace is how long a replaced key stays published, so tokens it signed still verify
+	Grace time.Duration // default 48h
+	// CheckInterval is how often replicas rotate when due and reload the keys
+	CheckInterval time.Duration // default 5m
+}
+
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("No .env file found will use environment variables instead.")
 	}
+	if err := applySources(); err != nil {
+		return nil, fmt.Errorf("failed to load configuration sources: %w", err)
+	}
+	if err := resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:           getEnv("PORT", "8181"),
@@ -329,31 +963,53 @@ func Load() (*Config, error) {
 			TLSEnabled:           getEnvAsBool("GRPC_TLS_ENABLED", false),
 			TLSCert:              getEnv("GRPC_TLS_CERT_FILE", ""),
 			TLSKey:               getEnv("GRPC_TLS_KEY_FILE", ""),
+			TLSClientCA:          getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+			TLSClientAuth:        getEnv("GRPC_TLS_CLIENT_AUTH", ""),
+			TLSClientSPIFFEIDs:   getEnvAsSlice("GRPC_TLS_CLIENT_SPIFFE_IDS", nil),
 			ReflectionEnabled:    getEnvAsBool("GRPC_REFLECTION_ENABLED", false),
 			MaxRequestsPerMinute: getEnvAsInt("GRPC_MAX_REQUESTS_PER_MINUTE", 100),
+
+			MaxConnectionAge:      getEnvAsDuration("GRPC_MAX_CONNECTION_AGE", "30m"),
+			MaxConnectionAgeGrace: getEnvAsDuration("GRPC_MAX_CONNECTION_AGE_GRACE", "10m"),
+			MaxStreamDuration:     getEnvAsDuration("GRPC_MAX_STREAM_DURATION", "10m"),
+			ShutdownTimeout:       getEnvAsDuration("GRPC_SHUTDOWN_TIMEOUT", "15s"),
+			HealthCheckInterval:   getEnvAsDuration("GRPC_HEALTH_CHECK_INTERVAL", "10s"),
 		},
 		Database: DatabaseConfig{
-			Host:           getEnv("DB_HOST", "localhost"),
-			Port:           getEnv("DB_PORT", "5432"),
-			User:           getEnv("DB_USER", "postgres"),
-			Password:       getEnv("DB_PASSWORD", "postgres"),
-			DBName:         getEnv("DB_NAME", "auth_gateway"),
-			SSLMode:        getEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns:   getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:   getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
-			EnableQueryLog: getEnvAsBool("DB_ENABLE_QUERY_LOG", false),
+			Host:             getEnv("DB_HOST", "localhost"),
+			Port:             getEnv("DB_PORT", "5432"),
+			User:             getEnv("DB_USER", "postgres"),
+			Password:         getEnv("DB_PASSWORD", "postgres"),
+			DBName:           getEnv("DB_NAME", "auth_gateway"),
+			SSLMode:          getEnv("DB_SSLMODE", "disable"),
+			MaxOpenConns:     getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:     getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			EnableQueryLog:   getEnvAsBool("DB_ENABLE_QUERY_LOG", false),
+			PreparedQueries:  getEnvAsBool("DB_PREPARED_QUERIES", true),
+			PreparedMaxConns: getEnvAsInt("DB_PREPARED_MAX_CONNS", 10),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
+			Host:             getEnv("REDIS_HOST", "localhost"),
+			Port:             getEnv("REDIS_PORT", "6379"),
+			Password:         getEnv("REDIS_PASSWORD", ""),
+			DB:               getEnvAsInt("REDIS_DB", 0),
+			UserCacheEnabled: getEnvAsBool("USER_CACHE_ENABLED", true),
+			UserCacheTTL:     getEnvAsDuration("USER_CACHE_TTL", "5m"),
 		},
 		JWT: JWTConfig{
 			AccessSecret:   getEnv("JWT_ACCESS_SECRET", ""),
 			RefreshSecret:  getEnv("JWT_REFRESH_SECRET", ""),
 			AccessExpires:  getEnvAsDuration("JWT_ACCESS_EXPIRES", "15m"),
 			RefreshExpires: getEnvAsDuration("JWT_REFRESH_EXPIRES", "168h"),
+			Issuer:         getEnv("JWT_ISSUER", ""),
+			Audience:       getEnvAsSlice("JWT_AUDIENCE", []string{}),
+			ClockSkew:      getEnvAsDuration("JWT_CLOCK_SKEW", "30s"),
+
+			SigningAlgorithm: getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+			SigningKeyPath:   getEnv("JWT_SIGNING_KEY_PATH", ""),
+			SigningKey:       strings.ReplaceAll(getEnv("JWT_SIGNING_KEY", ""), `\n`, "\n"),
+			SigningKeyID:     getEnv("JWT_SIGNING_KEY_ID", ""),
+			AcceptHMAC:       getEnvAsBool("JWT_ACCEPT_HMAC", true),
 		},
 		OAuth: OAuthConfig{
 			Google: OAuthProvider{
@@ -376,6 +1032,24 @@ func Load() (*Config, error) {
 				ClientSecret: getEnv("INSTAGRAM_CLIENT_SECRET", ""),
 				CallbackURL:  getEnv("INSTAGRAM_CALLBACK_URL", ""),
 			},
+			Apple: AppleOAuthProvider{
+				ClientID:    getEnv("APPLE_CLIENT_ID", ""),
+				TeamID:      getEnv("APPLE_TEAM_ID", ""),
+				KeyID:       getEnv("APPLE_KEY_ID", ""),
+				PrivateKey:  getEnv("APPLE_PRIVATE_KEY", ""),
+				CallbackURL: getEnv("APPLE_CALLBACK_URL", ""),
+			},
+			Microsoft: MicrosoftOAuthProvider{
+				ClientID:     getEnv("MICROSOFT_CLIENT_ID", ""),
+				ClientSecret: getEnv("MICROSOFT_CLIENT_SECRET", ""),
+				TenantID:     getEnv("MICROSOFT_TENANT_ID", "common"),
+				CallbackURL:  getEnv("MICROSOFT_CALLBACK_URL", ""),
+			},
+			LinkedIn: OAuthProvider{
+				ClientID:     getEnv("LINKEDIN_CLIENT_ID", ""),
+				ClientSecret: getEnv("LINKEDIN_CLIENT_SECRET", ""),
+				CallbackURL:  getEnv("LINKEDIN_CALLBACK_URL", ""),
+			},
 			OneC: CustomOAuthProvider{
 				Enabled:      getEnvAsBool("OAUTH_ONEC_ENABLED", false),
 				ClientID:     getEnv("OAUTH_ONEC_CLIENT_ID", ""),
@@ -386,8 +1060,29 @@ func Load() (*Config, error) {
 				UserInfoURL:  getEnv("OAUTH_ONEC_USERINFO_URL", ""),
 				Scopes:       getEnv("OAUTH_ONEC_SCOPES", "openid profile email"),
 			},
-			FrontendURL:      getEnv("FRONTEND_URL", "http://localhost:3001"),
-			TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
+			OIDC: OIDCOAuthProvider{
+				Enabled:            getEnvAsBool("OAUTH_OIDC_ENABLED", false),
+				DisplayName:        getEnv("OAUTH_OIDC_DISPLAY_NAME", "Single Sign-On"),
+				Issuer:             getEnv("OAUTH_OIDC_ISSUER", ""),
+				ClientID:           getEnv("OAUTH_OIDC_CLIENT_ID", ""),
+				ClientSecret:       getEnv("OAUTH_OIDC_CLIENT_SECRET", ""),
+				CallbackURL:        getEnv("OAUTH_OIDC_REDIRECT_URI", ""),
+				AuthURL:            getEnv("OAUTH_OIDC_AUTH_URL", ""),
+				TokenURL:           getEnv("OAUTH_OIDC_TOKEN_URL", ""),
+				UserInfoURL:        getEnv("OAUTH_OIDC_USERINFO_URL", ""),
+				Scopes:             getEnv("OAUTH_OIDC_SCOPES", "openid profile email"),
+				ClaimSubject:       getEnv("OAUTH_OIDC_CLAIM_SUBJECT", ""),
+				ClaimEmail:         getEnv("OAUTH_OIDC_CLAIM_EMAIL", ""),
+				ClaimEmailVerified: getEnv("OAUTH_OIDC_CLAIM_EMAIL_VERIFIED", ""),
+				ClaimName:          getEnv("OAUTH_OIDC_CLAIM_NAME", ""),
+				ClaimUsername:      getEnv("OAUTH_OIDC_CLAIM_USERNAME", ""),
+				ClaimPicture:       getEnv("OAUTH_OIDC_CLAIM_PICTURE", ""),
+			},
+			FrontendURL:         getEnv("FRONTEND_URL", "http://localhost:3001"),
+			TelegramBotToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
+			StoreProviderTokens: getEnvAsBool("OAUTH_STORE_PROVIDER_TOKENS", false),
+			EmailCollision:      getEnv("OAUTH_EMAIL_COLLISION", "reject"),
+			DisabledProviders:   getEnvAsSlice("OAUTH_DISABLED_PROVIDERS", nil),
 		},
 		SMTP: SMTPConfig{
 			Host:      getEnv("SMTP_HOST", "smtp.gmail.com"),
@@ -398,25 +1093,32 @@ func Load() (*Config, error) {
 			FromName:  getEnv("SMTP_FROM_NAME", "Auth Gateway"),
 		},
 		SMS: SMSConfig{
-			Provider:           getEnv("SMS_PROVIDER", "mock"),
-			Enabled:            getEnvAsBool("SMS_ENABLED", false),
-			TwilioAccountSID:   getEnv("TWILIO_ACCOUNT_SID", ""),
-			TwilioAuthToken:    getEnv("TWILIO_AUTH_TOKEN", ""),
-			TwilioFromNumber:   getEnv("TWILIO_FROM_NUMBER", ""),
-			AWSRegion:          getEnv("AWS_SNS_REGION", "us-east-1"),
-			AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
-			AWSSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
-			AWSSenderID:        getEnv("AWS_SNS_SENDER_ID", ""),
-			SMSMaxPerHour:      getEnvAsInt("SMS_MAX_PER_HOUR", 10),
-			SMSMaxPerDay:       getEnvAsInt("SMS_MAX_PER_DAY", 50),
-			SMSMaxPerNumber:    getEnvAsInt("SMS_MAX_PER_NUMBER", 5),
-		},
-		CORS: CORSConfig{
-			AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3001"}),
-			AllowedMethods:   getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}),
-			AllowedHeaders:   getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With", "X-Application-ID", "X-API-Key"}),
-			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+			Provider:            getEnv("SMS_PROVIDER", "mock"),
+			Enabled:             getEnvAsBool("SMS_ENABLED", false),
+			TwilioAccountSID:    getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:     getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFromNumber:    getEnv("TWILIO_FROM_NUMBER", ""),
+			AWSRegion:           getEnv("AWS_SNS_REGION", "us-east-1"),
+			AWSAccessKeyID:      getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretAccessKey:  getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			AWSSenderID:         getEnv("AWS_SNS_SENDER_ID", ""),
+			VonageAPIKey:        getEnv("VONAGE_API_KEY", ""),
+			VonageAPISecret:     getEnv("VONAGE_API_SECRET", ""),
+			VonageFromNumber:    getEnv("VONAGE_FROM", ""),
+			SMPPAddr:            getEnv("SMPP_ADDR", ""),
+			SMPPSystemID:        getEnv("SMPP_SYSTEM_ID", ""),
+			SMPPPassword:        getEnv("SMPP_PASSWORD", ""),
+			SMPPSystemType:      getEnv("SMPP_SYSTEM_TYPE", ""),
+			SMPPSourceAddr:      getEnv("SMPP_SOURCE_ADDR", ""),
+			SMPPTLS:             getEnvAsBool("SMPP_TLS", false),
+			CountrySenders:      getEnvAsMap("SMS_COUNTRY_SENDERS"),
+			StatusCallbackURL:   strings.TrimSuffix(getEnv("SMS_STATUS_CALLBACK_URL", ""), "/"),
+			StatusCallbackToken: getEnv("SMS_STATUS_CALLBACK_TOKEN", ""),
+			SMSMaxPerHour:       getEnvAsInt("SMS_MAX_PER_HOUR", 10),
+			SMSMaxPerDay:        getEnvAsInt("SMS_MAX_PER_DAY", 50),
+			SMSMaxPerNumber:     getEnvAsInt("SMS_MAX_PER_NUMBER", 5),
 		},
+		CORS: loadCORSConfig(),
 		RateLimit: RateLimitConfig{
 			SignupMax:     getEnvAsInt("RATE_LIMIT_SIGNUP_MAX", 5),
 			SignupWindow:  getEnvAsDuration("RATE_LIMIT_SIGNUP_WINDOW", "1h"),
@@ -426,6 +1128,13 @@ func Load() (*Config, error) {
 			RefreshWindow: getEnvAsDuration("RATE_LIMIT_REFRESH_WINDOW", "5m"),
 			APIMax:        getEnvAsInt("RATE_LIMIT_API_MAX", 100),
 			APIWindow:     getEnvAsDuration("RATE_LIMIT_API_WINDOW", "1m"),
+			UserMax:       getEnvAsInt("RATE_LIMIT_USER_MAX", 0),
+			UserWindow:    getEnvAsDuration("RATE_LIMIT_USER_WINDOW", "1m"),
+			ClientMax:     getEnvAsInt("RATE_LIMIT_CLIENT_MAX", 0),
+			ClientWindow:  getEnvAsDuration("RATE_LIMIT_CLIENT_WINDOW", "1m"),
+
+			TokenQueueMaxWait: getEnvAsDuration("RATE_LIMIT_TOKEN_QUEUE_MAX_WAIT", "0s"),
+			TokenQueueSize:    getEnvAsInt("RATE_LIMIT_TOKEN_QUEUE_SIZE", 100),
 		},
 		Security: SecurityConfig{
 			BcryptCost:                    getEnvAsInt("BCRYPT_COST", 12),
@@ -436,6 +1145,8 @@ func Load() (*Config, error) {
 			CSRFEnabled:                   getEnvAsBool("CSRF_ENABLED", false),
 			OTPHMACSecret:                 getEnv("OTP_HMAC_SECRET", "change-me-in-production-otp-hmac-secret-32-chars-minimum"),
 			MaxActiveSessions:             getEnvAsInt("MAX_ACTIVE_SESSIONS", 0),
+			IdempotencyKeyTTL:             getEnvAsDuration("IDEMPOTENCY_KEY_TTL", "24h"),
+			LoginIdentifiers:              getEnvAsSlice("LOGIN_IDENTIFIERS", []string{"email", "phone", "username"}),
 			PasswordPolicy: PasswordPolicyConfig{
 				MinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
 				RequireUppercase: getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false),
@@ -444,7 +1155,60 @@ func Load() (*Config, error) {
 				RequireSpecial:   getEnvAsBool("PASSWORD_REQUIRE_SPECIAL", false),
 				MaxLength:        getEnvAsInt("PASSWORD_MAX_LENGTH", 0),
 				CommonPasswords:  getEnvAsBool("PASSWORD_CHECK_COMMON", false),
+				DictionaryFile:   getEnv("PASSWORD_DICTIONARY_FILE", ""),
 				CheckCompromised: getEnvAsBool("PASSWORD_CHECK_COMPROMISED", false),
+				HistoryCount:     getEnvAsInt("PASSWORD_HISTORY_COUNT", 0),
+				MaxAgeDays:       getEnvAsInt("PASSWORD_MAX_AGE_DAYS", 0),
+				ExpiryWarnDays:   getEnvAsInt("PASSWORD_EXPIRY_WARNING_DAYS", 7),
+			},
+			AccountRecovery: AccountRecoveryConfig{
+				Steps:       getEnvAsSlice("ACCOUNT_RECOVERY_STEPS", []string{"secondary_email", "sms", "admin_review"}),
+				RequestTTL:  getEnvAsDuration("ACCOUNT_RECOVERY_TTL", "24h"),
+				MaxAttempts: getEnvAsInt("ACCOUNT_RECOVERY_MAX_ATTEMPTS", 5),
+			},
+			EmailVerification: EmailVerificationConfig{
+				Enforcement:     getEnv("EMAIL_VERIFICATION_ENFORCEMENT", "advisory"),
+				GracePeriodDays: getEnvAsInt("EMAIL_VERIFICATION_GRACE_DAYS", 7),
+				ReminderDays:    getEnvAsIntSlice("EMAIL_VERIFICATION_REMINDER_DAYS", []int{1, 3, 6}),
+			},
+			GuestSessions: GuestSessionConfig{
+				Enabled:       getEnvAsBool("GUEST_SESSIONS_ENABLED", false),
+				RetentionDays: getEnvAsInt("GUEST_RETENTION_DAYS", 30),
+			},
+			Retention: RetentionConfig{
+				AuditLogDays:          getEnvAsInt("RETENTION_AUDIT_LOG_DAYS", 0),
+				SessionDays:           getEnvAsInt("RETENTION_SESSION_DAYS", 0),
+				InactiveAccountMonths: getEnvAsInt("RETENTION_INACTIVE_ACCOUNT_MONTHS", 0),
+			},
+			BlacklistFilter: BlacklistFilterConfig{
+				Enabled:           getEnvAsBool("BLACKLIST_FILTER_ENABLED", false),
+				ExpectedEntries:   getEnvAsInt("BLACKLIST_FILTER_EXPECTED_ENTRIES", 100000),
+				FalsePositiveRate: getEnvAsFloat("BLACKLIST_FILTER_FALSE_POSITIVE_RATE", 0.01),
+				RebuildInterval:   getEnvAsDuration("BLACKLIST_FILTER_REBUILD_INTERVAL", "10m"),
+			},
+			AccountLockout: AccountLockoutConfig{
+				Enabled:           getEnvAsBool("ACCOUNT_LOCKOUT_ENABLED", false),
+				MaxFailedAttempts: getEnvAsInt("ACCOUNT_LOCKOUT_MAX_ATTEMPTS", 5),
+				Window:            getEnvAsDuration("ACCOUNT_LOCKOUT_WINDOW", "15m"),
+				Duration:          getEnvAsDuration("ACCOUNT_LOCKOUT_DURATION", "5m"),
+				MaxDuration:       getEnvAsDuration("ACCOUNT_LOCKOUT_MAX_DURATION", "24h"),
+			},
+			WebAuthn: WebAuthnConfig{
+				RPID:          getEnv("WEBAUTHN_RP_ID", ""),
+				RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "Auth Gateway"),
+				RPOrigins:     getEnvAsSlice("WEBAUTHN_RP_ORIGINS", []string{}),
+			},
+			MagicLink: MagicLinkConfig{
+				Enabled:             getEnvAsBool("MAGIC_LINK_ENABLED", false),
+				TTL:                 getEnvAsDuration("MAGIC_LINK_TTL", "15m"),
+				RedirectURL:         getEnv("MAGIC_LINK_REDIRECT_URL", ""),
+				AllowedRedirectURLs: getEnvAsSlice("MAGIC_LINK_ALLOWED_REDIRECT_URLS", []string{}),
+			},
+			SessionStorage: SessionStorageConfig{
+				Backend:          getEnv("SESSION_STORAGE", SessionStoragePostgres),
+				ArchiveEnabled:   getEnvAsBool("SESSION_ARCHIVE_ENABLED", false),
+				ArchiveInterval:  getEnvAsDuration("SESSION_ARCHIVE_INTERVAL", "1m"),
+				ArchiveBatchSize: getEnvAsInt("SESSION_ARCHIVE_BATCH_SIZE", 500),
 			},
 		},
 		Metrics: MetricsConfig{
@@ -458,6 +1222,7 @@ func Load() (*Config, error) {
 		OIDC: OIDCConfig{
 			Issuer:             getEnv("OIDC_ISSUER", ""),
 			SigningKeyPath:     getEnv("OIDC_SIGNING_KEY_PATH", ""),
+			SigningKey:         strings.ReplaceAll(getEnv("OIDC_SIGNING_KEY", ""), `\n`, "\n"),
 			SigningKeyID:       getEnv("OIDC_SIGNING_KEY_ID", ""),
 			SigningAlgorithm:   getEnv("OIDC_SIGNING_ALGORITHM", "RS256"),
 			AdditionalKeys:     getEnv("OIDC_ADDITIONAL_KEYS", ""),
@@ -467,7 +1232,112 @@ func Load() (*Config, error) {
 			AuthCodeTTL:        getEnvAsInt("OIDC_AUTH_CODE_TTL", 600),
 			DeviceCodeTTL:      getEnvAsInt("OIDC_DEVICE_CODE_TTL", 1800),
 			DeviceCodeInterval: getEnvAsInt("OIDC_DEVICE_CODE_INTERVAL", 5),
+			ClientLogoMaxBytes: getEnvAsInt("OIDC_CLIENT_LOGO_MAX_BYTES", 262144),
+			StateSecret:        getEnv("OIDC_STATE_SECRET", ""),
 			Enabled:            getEnvAsBool("OIDC_ENABLED", false),
+
+			BackchannelLogoutMaxAttempts: getEnvAsInt("OIDC_BACKCHANNEL_LOGOUT_MAX_ATTEMPTS", 5),
+			BackchannelLogoutTimeout:     getEnvAsDuration("OIDC_BACKCHANNEL_LOGOUT_TIMEOUT", "5s"),
+
+			KeyRotation: OIDCKeyRotationConfig{
+				Enabled:       getEnvAsBool("OIDC_KEY_ROTATION_ENABLED", false),
+				Interval:      getEnvAsDuration("OIDC_KEY_ROTATION_INTERVAL", "720h"),
+				PrePublish:    getEnvAsDuration("OIDC_KEY_ROTATION_PREPUBLISH", "24h"),
+				Grace:         getEnvAsDuration("OIDC_KEY_ROTATION_GRACE", "48h"),
+				CheckInterval: getEnvAsDuration("OIDC_KEY_ROTATION_CHECK_INTERVAL", "5m"),
+			},
+		},
+		Chaos: ChaosConfig{
+			Enabled: getEnvAsBool("CHAOS_ENABLED", false),
+		},
+		Logging: LoggingConfig{
+			Format:             getEnv("LOG_FORMAT", "json"),
+			SamplingInitial:    getEnvAsInt("LOG_SAMPLING_INITIAL", 0),
+			SamplingThereafter: getEnvAsInt("LOG_SAMPLING_THEREAFTER", 100),
+			SamplingTick:       getEnvAsDuration("LOG_SAMPLING_TICK", "1s"),
+			RedactPII:          getEnvAsBool("LOG_REDACT_PII", true),
+			RedactKeys:         getEnvAsSlice("LOG_REDACT_KEYS", []string{}),
+			LevelRevertAfter:   getEnvAsDuration("LOG_LEVEL_REVERT_AFTER", "30m"),
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:     getEnvAsBool("ACCESS_LOG_ENABLED", false),
+			Format:      getEnv("ACCESS_LOG_FORMAT", "json"),
+			Output:      getEnv("ACCESS_LOG_OUTPUT", "stdout"),
+			AnonymizeIP: getEnvAsBool("ACCESS_LOG_ANONYMIZE_IP", true),
+			Fields:      getEnvAsSlice("ACCESS_LOG_FIELDS", []string{}),
+		},
+		Credentials: CredentialsConfig{
+			Enabled:      getEnvAsBool("CREDENTIALS_BROKER_ENABLED", false),
+			DefaultTTL:   getEnvAsDuration("CREDENTIALS_DEFAULT_TTL", "1h"),
+			MaxTTL:       getEnvAsDuration("CREDENTIALS_MAX_TTL", "12h"),
+			PostgresDSN:  getEnv("CREDENTIALS_POSTGRES_DSN", ""),
+			SSHCAKeyPath: getEnv("CREDENTIALS_SSH_CA_KEY", ""),
+		},
+		SignedURLs: SignedURLConfig{
+			Enabled:        getEnvAsBool("SIGNED_URLS_ENABLED", false),
+			Secret:         getEnv("SIGNED_URL_SECRET", ""),
+			DefaultTTL:     getEnvAsDuration("SIGNED_URL_DEFAULT_TTL", "5m"),
+			MaxTTL:         getEnvAsDuration("SIGNED_URL_MAX_TTL", "1h"),
+			AllowedOrigins: getEnvAsSlice("SIGNED_URL_ALLOWED_ORIGINS", []string{}),
+		},
+		MTLS: MTLSConfig{
+			ClientCertHeader: getEnv("MTLS_CLIENT_CERT_HEADER", ""),
+		},
+		Risk: RiskConfig{
+			Enabled:            getEnvAsBool("RISK_ENGINE_ENABLED", false),
+			TorExitListURL:     getEnv("RISK_TOR_EXIT_LIST_URL", ""),
+			TorExitListRefresh: getEnvAsDuration("RISK_TOR_EXIT_LIST_REFRESH", "1h"),
+		},
+		SLO: SLOConfig{
+			Enabled:       getEnvAsBool("SLO_ENABLED", true),
+			Window:        getEnvAsDuration("SLO_WINDOW", "720h"),
+			AlertCooldown: getEnvAsDuration("SLO_ALERT_COOLDOWN", "1h"),
+		},
+		EventBus: EventBusConfig{
+			Provider:      getEnv("EVENT_BUS_PROVIDER", ""),
+			DefaultTopic:  getEnv("EVENT_BUS_DEFAULT_TOPIC", "auth-gateway.events"),
+			Topics:        getEnvAsMap("EVENT_BUS_TOPICS"),
+			MaxAttempts:   getEnvAsInt("EVENT_BUS_MAX_ATTEMPTS", 10),
+			NATSURL:       getEnv("EVENT_BUS_NATS_URL", ""),
+			NATSUser:      getEnv("EVENT_BUS_NATS_USER", ""),
+			NATSPassword:  getEnv("EVENT_BUS_NATS_PASSWORD", ""),
+			NATSToken:     getEnv("EVENT_BUS_NATS_TOKEN", ""),
+			NATSJetStream: getEnvAsBool("EVENT_BUS_NATS_JETSTREAM", true),
+			KafkaRESTURL:  getEnv("EVENT_BUS_KAFKA_REST_URL", ""),
+			KafkaUsername: getEnv("EVENT_BUS_KAFKA_USERNAME", ""),
+			KafkaPassword: getEnv("EVENT_BUS_KAFKA_PASSWORD", ""),
+		},
+		AuditExport: AuditExportConfig{
+			Delay:             getEnvAsDuration("AUDIT_EXPORT_DELAY", "1m"),
+			SyslogAddress:     getEnv("AUDIT_EXPORT_SYSLOG_ADDRESS", ""),
+			SyslogNetwork:     getEnv("AUDIT_EXPORT_SYSLOG_NETWORK", "tcp"),
+			SyslogFormat:      getEnv("AUDIT_EXPORT_SYSLOG_FORMAT", "cef"),
+			SyslogTLSCAFile:   getEnv("AUDIT_EXPORT_SYSLOG_TLS_CA_FILE", ""),
+			SyslogInterval:    getEnvAsDuration("AUDIT_EXPORT_SYSLOG_INTERVAL", "1m"),
+			S3Bucket:          getEnv("AUDIT_EXPORT_S3_BUCKET", ""),
+			S3Prefix:          getEnv("AUDIT_EXPORT_S3_PREFIX", "audit/"),
+			S3Region:          getEnv("AUDIT_EXPORT_S3_REGION", "us-east-1"),
+			S3Endpoint:        getEnv("AUDIT_EXPORT_S3_ENDPOINT", ""),
+			S3AccessKeyID:     getEnv("AUDIT_EXPORT_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("AUDIT_EXPORT_S3_SECRET_ACCESS_KEY", ""),
+			S3Format:          getEnv("AUDIT_EXPORT_S3_FORMAT", "json"),
+		},
+		DataExport: DataExportConfig{
+			Enabled: getEnvAsBool("DATA_EXPORT_ENABLED", false),
+			TTL:     getEnvAsDuration("DATA_EXPORT_TTL", "24h"),
+			LinkTTL: getEnvAsDuration("DATA_EXPORT_LINK_TTL", "15m"),
+		},
+		Tracing: TracingConfig{
+			Enabled:     getEnvAsBool("TRACING_ENABLED", false),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "auth-gateway"),
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			Insecure:    getEnvAsBool("OTEL_EXPORTER_OTLP_INSECURE", false),
+			Headers:     getEnvAsMap("OTEL_EXPORTER_OTLP_HEADERS"),
+			SampleRatio: getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Reload: ReloadConfig{
+			File:          getEnv("CONFIG_FILE", ""),
+			WatchInterval: getEnvAsDuration("CONFIG_WATCH_INTERVAL", "30s"),
 		},
 	}
 
@@ -488,14 +1358,161 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("CORS configuration validation failed: %w", err)
 	}
 
+	if err := cfg.SMS.Validate(); err != nil {
+		return nil, fmt.Errorf("SMS configuration validation failed: %w", err)
+	}
+
+	if cfg.Reload.WatchInterval < 0 {
+		return nil, fmt.Errorf("CONFIG_WATCH_INTERVAL must not be negative")
+	}
+
+	if err := cfg.EventBus.Validate(); err != nil {
+		return nil, fmt.Errorf("event bus configuration validation failed: %w", err)
+	}
+
+	if err := cfg.Tracing.Validate(); err != nil {
+		return nil, fmt.Errorf("tracing configuration validation failed: %w", err)
+	}
+
+	if err := cfg.AuditExport.Validate(); err != nil {
+		return nil, fmt.Errorf("audit export configuration validation failed: %w", err)
+	}
+
 	// Validate security configuration
 	if err := cfg.Security.Validate(cfg.Server.Env); err != nil {
 		return nil, fmt.Errorf("security configuration validation failed: %w", err)
 	}
+	if cfg.Security.MagicLink.Enabled && cfg.Server.ExternalURL == "" {
+		return nil, fmt.Errorf("EXTERNAL_URL must be set when MAGIC_LINK_ENABLED is true: magic links point at it")
+	}
+
+	switch cfg.OAuth.EmailCollision {
+	case "reject", "auto_link", "confirm_password":
+	default:
+		return nil, fmt.Errorf("OAUTH_EMAIL_COLLISION must be reject, auto_link or confirm_password (got %q)", cfg.OAuth.EmailCollision)
+	}
+
+	// Fault injection degrades real traffic
+	if cfg.Chaos.Enabled && cfg.Server.Env == "production" {
+		return nil, fmt.Errorf("CHAOS_ENABLED must not be set in production")
+	}
+
+	if cfg.Credentials.Enabled && (cfg.Credentials.DefaultTTL <= 0 || cfg.Credentials.MaxTTL < cfg.Credentials.DefaultTTL) {
+		return nil, fmt.Errorf("CREDENTIALS_DEFAULT_TTL must be positive and at most CREDENTIALS_MAX_TTL")
+	}
+
+	if cfg.Risk.Enabled && cfg.Risk.TorExitListURL != "" && cfg.Risk.TorExitListRefresh <= 0 {
+		return nil, fmt.Errorf("RISK_TOR_EXIT_LIST_REFRESH must be positive")
+	}
+
+	if cfg.SignedURLs.Enabled {
+		if len(cfg.SignedURLs.Secret) < 32 {
+			return nil, fmt.Errorf("SIGNED_URL_SECRET must be at least 32 characters long when SIGNED_URLS_ENABLED is set")
+		}
+		if cfg.SignedURLs.DefaultTTL <= 0 || cfg.SignedURLs.MaxTTL < cfg.SignedURLs.DefaultTTL {
+			return nil, fmt.Errorf("SIGNED_URL_DEFAULT_TTL must be positive and at most SIGNED_URL_MAX_TTL")
+		}
+		if len(cfg.SignedURLs.AllowedOrigins) == 0 {
+			return nil, fmt.Errorf("SIGNED_URL_ALLOWED_ORIGINS is required when SIGNED_URLS_ENABLED is set")
+		}
+	}
+
+	if cfg.DataExport.Enabled {
+		if len(cfg.SignedURLs.Secret) < 32 {
+			return nil, fmt.Errorf("SIGNED_URL_SECRET must be at least 32 characters long when DATA_EXPORT_ENABLED is set")
+		}
+		if cfg.DataExport.TTL <= 0 || cfg.DataExport.LinkTTL <= 0 || cfg.DataExport.LinkTTL > cfg.DataExport.TTL {
+			return nil, fmt.Errorf("DATA_EXPORT_TTL and DATA_EXPORT_LINK_TTL must be positive and the link must not outlive the export")
+		}
+	}
+
+	// Anyone could send the header if it were accepted from every peer
+	if cfg.MTLS.ClientCertHeader != "" && len(cfg.Server.TrustedProxies) == 0 {
+		return nil, fmt.Errorf("MTLS_CLIENT_CERT_HEADER requires TRUSTED_PROXIES to be set")
+	}
+
+	// Provider tokens are only ever stored encrypted
+	if cfg.OAuth.StoreProviderTokens && len(cfg.Security.EncryptionKey) != 32 {
+		return nil, fmt.Errorf("OAUTH_STORE_PROVIDER_TOKENS requires ENCRYPTION_KEY to be exactly 32 bytes")
+	}
+
+	if cfg.OIDC.Enabled && cfg.OIDC.KeyRotation.Enabled {
+		if err := cfg.OIDC.KeyRotation.validate(cfg); err != nil {
+			return nil, err
+		}
+		// Rotated keys are only ever stored encrypted
+		if len(cfg.Security.EncryptionKey) != 32 {
+			return nil, fmt.Errorf("OIDC_KEY_ROTATION_ENABLED requires ENCRYPTION_KEY to be exactly 32 bytes")
+		}
+	}
 
 	return cfg, nil
 }
 
+// validate checks that a key is published before it signs and stays published while the
+// tokens it signed are valid
+func (c *OIDCKeyRotationConfig) validate(cfg *Config) error {
+	if c.Interval <= 0 || c.PrePublish <= 0 || c.Grace <= 0 || c.CheckInterval <= 0 {
+		return fmt.Errorf("OIDC_KEY_ROTATION_INTERVAL, _PREPUBLISH, _GRACE and _CHECK_INTERVAL must be positive")
+	}
+	if c.PrePublish >= c.Interval {
+		return fmt.Errorf("OIDC_KEY_ROTATION_PREPUBLISH must be shorter than OIDC_KEY_ROTATION_INTERVAL")
+	}
+	longest := time.Duration(max(cfg.OIDC.AccessTokenTTL, cfg.OIDC.IDTokenTTL)) * time.Second
+	// First-party tokens are signed with the OIDC keys unless they have their own
+	if cfg.JWT.IsAsymmetric() && cfg.JWT.SigningKeyPath == "" && cfg.JWT.SigningKey == "" {
+		longest = max(longest, cfg.JWT.AccessExpires, cfg.JWT.RefreshExpires)
+	}
+	if c.Grace < longest {
+		return fmt.Errorf("OIDC_KEY_ROTATION_GRACE must be at least the longest lifetime of the tokens it signs (%s)", longest)
+	}
+	if c.CheckInterval >= c.PrePublish {
+		return fmt.Errorf("OIDC_KEY_ROTATION_CHECK_INTERVAL must be shorter than OIDC_KEY_ROTATION_PREPUBLISH")
+	}
+	return nil
+}
+
+// loadCORSConfig loads the default CORS policy from CORS_* and the group policies from
+// CORS_ADMIN_* and CORS_OAUTH_*. Group settings that are not set fall back to the default
+// policy, except that OAuth endpoints only take GET and POST without credentials: their
+// clients authenticate with tokens and client secrets, never with cookies.
+func loadCORSConfig() CORSConfig {
+	def := CORSPolicy{
+		AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3001"}),
+		AllowedMethods:   getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}),
+		AllowedHeaders:   getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With", "X-Application-ID", "X-API-Key", "Idempotency-Key"}),
+		AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+		MaxAge:           getEnvAsDuration("CORS_MAX_AGE", "24h"),
+	}
+
+	oauthDefaults := def
+	oauthDefaults.AllowedMethods = []string{"GET", "POST", "OPTIONS"}
+	oauthDefaults.AllowedHeaders = []string{"Content-Type", "Authorization"}
+	oauthDefaults.AllowCredentials = false
+
+	return CORSConfig{
+		Default: def,
+		Admin:   getCORSPolicy("CORS_ADMIN_", def),
+		OAuth:   getCORSPolicy("CORS_OAUTH_", oauthDefaults),
+	}
+}
+
+// getCORSPolicy reads a group CORS policy from the variables starting with prefix.
+// "none" as the origin list denies cross-origin requests to the group.
+func getCORSPolicy(prefix string, defaults CORSPolicy) CORSPolicy {
+	origins := getEnvAsSlice(prefix+"ALLOWED_ORIGINS", defaults.AllowedOrigins)
+	if len(origins) == 1 && origins[0] == "none" {
+		origins = nil
+	}
+	return CORSPolicy{
+		AllowedOrigins:   origins,
+		AllowedMethods:   getEnvAsSlice(prefix+"ALLOWED_METHODS", defaults.AllowedMethods),
+		AllowedHeaders:   getEnvAsSlice(prefix+"ALLOWED_HEADERS", defaults.AllowedHeaders),
+		AllowCredentials: getEnvAsBool(prefix+"ALLOW_CREDENTIALS", defaults.AllowCredentials),
+		MaxAge:           getEnvAsDuration(prefix+"MAX_AGE", defaults.MaxAge.String()),
+	}
+}
+
 // GetDSN returns the PostgreSQL connection string
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf(
@@ -532,26 +1549,34 @@ func setOIDCDefaults(cfg *Config) {
 	if cfg.OIDC.SigningAlgorithm == "" {
 		cfg.OIDC.SigningAlgorithm = "RS256"
 	}
+	if cfg.OIDC.StateSecret == "" {
+		cfg.OIDC.StateSecret = cfg.JWT.AccessSecret
+	}
 }
 
 // GetKeyConfigs parses key configuration and returns slice of key configs
-// Returns format: []struct{KID, KeyPath string}
+// Returns format: []struct{KID, KeyPath, KeyPEM string}; KeyPEM is set for the signing key
+// configured with OIDC_SIGNING_KEY
 func (c *OIDCConfig) GetKeyConfigs() []struct {
 	KID     string
 	KeyPath string
+	KeyPEM  string
 } {
 	var configs []struct {
 		KID     string
 		KeyPath string
+		KeyPEM  string
 	}
 
-	if c.SigningKeyPath != "" {
+	if c.SigningKeyPath != "" || c.SigningKey != "" {
 		configs = append(configs, struct {
 			KID     string
 			KeyPath string
+			KeyPEM  string
 		}{
 			KID:     c.SigningKeyID,
 			KeyPath: c.SigningKeyPath,
+			KeyPEM:  c.SigningKey,
 		})
 	}
 
@@ -563,6 +1588,7 @@ func (c *OIDCConfig) GetKeyConfigs() []struct {
 				configs = append(configs, struct {
 					KID     string
 					KeyPath string
+					KeyPEM  string
 				}{
 					KID:     parts[0],
 					KeyPath: parts[1],
@@ -576,15 +1602,23 @@ func (c *OIDCConfig) GetKeyConfigs() []struct {
 
 // Helper functions
 
+// getEnvValue returns the value of a variable, with a secret reference replaced by the secret
+func getEnvValue(key string) string {
+	if value, ok := resolvedSecrets[key]; ok {
+		return value
+	}
+	return os.Getenv(key)
+}
+
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnvValue(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
 func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnvValue(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
@@ -593,7 +1627,7 @@ func getEnvAsInt(key string, defaultValue int) int {
 }
 
 func getEnvAsBool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnvValue(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
 			return boolValue
 		}
@@ -601,6 +1635,15 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := getEnvValue(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key, defaultValue string) time.Duration {
 	value := getEnv(key, defaultValue)
 	duration, err := time.ParseDuration(value)
@@ -611,12 +1654,37 @@ func getEnvAsDuration(key, defaultValue string) time.Duration {
 }
 
 func getEnvAsSlice(key string, defaultValue []string) []string {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnvValue(key); value != "" {
 		return splitAndTrim(value, ",")
 	}
 	return defaultValue
 }
 
+// getEnvAsMap reads comma-separated key:value pairs, e.g. "+44:AuthGW,+1:+15551234567".
+// A pair without a colon maps to an empty value so validation can reject it.
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range splitAndTrim(getEnvValue(key), ",") {
+		k, v, _ := strings.Cut(item, ":")
+		result[trimSpace(k)] = trimSpace(v)
+	}
+	return result
+}
+
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	value := getEnvValue(key)
+	if value == "" {
+		return defaultValue
+	}
+	result := make([]int, 0)
+	for _, item := range splitAndTrim(value, ",") {
+		if intValue, err := strconv.Atoi(item); err == nil {
+			result = append(result, intValue)
+		}
+	}
+	return result
+}
+
 func splitAndTrim(s, sep string) []string {
 	var result []string
 	for _, item := range splitString(s, sep) {
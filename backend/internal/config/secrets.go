@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/smilemakc/auth-gateway/internal/secrets"
+)
+
+// Secret references. Any variable may hold a reference to a secret instead of its value:
+//
+//   - vault:<path>#<field> reads a field of the Vault secret at the API path, e.g.
+//     vault:secret/data/auth-gateway#jwt_access_secret for KV v2 or
+//     vault:database/creds/auth-gateway#password for dynamic credentials. VAULT_ADDR,
+//     VAULT_TOKEN and VAULT_NAMESPACE configure Vault.
+//   - aws-sm:<name or ARN>#<field> reads a field of an AWS Secrets Manager secret holding a
+//     JSON object, aws-sm:<name> the whole value. AWS_REGION and the default credential
+//     chain configure access; SECRETS_AWS_ENDPOINT overrides the endpoint.
+//
+// Every Load reads static secrets again. The Vault token and the leases of dynamic secrets
+// are renewed by the resolver returned by SecretResolver.
+
+var (
+	// secretResolver is created by the first Load that finds references; the providers it
+	// was created with are kept until restart
+	secretResolver *secrets.Resolver
+	// resolvedSecrets are the values of the variables holding references, read by getEnvValue
+	resolvedSecrets map[string]string
+)
+
+// SecretResolver returns the resolver of the secret references in the configuration, or nil
+// when there are none
+func SecretResolver() *secrets.Resolver {
+	return secretResolver
+}
+
+// resolveSecrets reads the secrets the variables refer to
+func resolveSecrets() error {
+	refs := make(map[string]secrets.Reference)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if ref, ok := secrets.ParseReference(value); ok {
+			refs[key] = ref
+		}
+	}
+	if len(refs) == 0 {
+		resolvedSecrets = nil
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sourceRequestTimeout)
+	defer cancel()
+
+	if secretResolver == nil {
+		resolver, err := newSecretResolver(ctx, refs)
+		if err != nil {
+			return err
+		}
+		secretResolver = resolver
+	}
+
+	values, err := secretResolver.Resolve(ctx, refs)
+	if err != nil {
+		return err
+	}
+	resolvedSecrets = values
+	return nil
+}
+
+// newSecretResolver creates a resolver with the providers refs use
+func newSecretResolver(ctx context.Context, refs map[string]secrets.Reference) (*secrets.Resolver, error) {
+	schemes := make(map[string]bool)
+	for _, ref := range refs {
+		schemes[ref.Scheme] = true
+	}
+
+	var providers []secrets.Provider
+	if schemes[secrets.SchemeVault] {
+		vault, err := secrets.NewVaultProvider(secrets.VaultConfig{
+			Address:   os.Getenv("VAULT_ADDR"),
+			Token:     os.Getenv("VAULT_TOKEN"),
+			Namespace: os.Getenv("VAULT_NAMESPACE"),
+			Timeout:   sourceRequestTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, vault)
+	}
+	if schemes[secrets.SchemeAWSSecretsMgr] {
+		aws, err := secrets.NewAWSSecretsManagerProvider(ctx, secrets.AWSSecretsManagerConfig{
+			Region:   os.Getenv("AWS_REGION"),
+			Endpoint: os.Getenv("SECRETS_AWS_ENDPOINT"),
+			Timeout:  sourceRequestTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, aws)
+	}
+	return secrets.NewResolver(providers...), nil
+}
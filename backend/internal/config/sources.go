@@ -0,0 +1,290 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/smilemakc/auth-gateway/internal/secrets"
+)
+
+// Configuration sources besides the environment. Their values are set as environment
+// variables, where Load reads them, so every variable can come from any source. Variables
+// set in the environment itself always win, then Vault, then Consul, then the config file:
+//
+//   - CONFIG_FILE: a YAML file of variables. Nested keys are joined with underscores and
+//     lists with commas, so rate_limit: {signin_max: 20} sets RATE_LIMIT_SIGNIN_MAX=20.
+//   - CONSUL_HTTP_ADDR and CONSUL_KV_PREFIX: every key below the prefix of the Consul KV
+//     store is a variable; CONSUL_HTTP_TOKEN authenticates.
+//   - VAULT_ADDR and VAULT_SECRET_PATH: every field of the KV v2 secret at
+//     VAULT_KV_MOUNT (default secret) / VAULT_SECRET_PATH is a variable; VAULT_TOKEN
+//     authenticates and VAULT_NAMESPACE selects an Enterprise namespace.
+//
+// The settings of the sources themselves come from the environment or the config file.
+const sourceRequestTimeout = 10 * time.Second
+
+var (
+	sourcesMu sync.Mutex
+	// sourcedKeys are the variables set from the sources by the last Load
+	sourcedKeys = map[string]struct{}{}
+)
+
+// sourceHTTPClient is the client Consul and Vault are read with
+var sourceHTTPClient = &http.Client{Timeout: sourceRequestTimeout}
+
+// applySources reads the configuration sources and sets the variables they define that are
+// not set in the environment. The variables set by a previous call are replaced, so a value
+// removed from a source goes back to its default. Nothing changes when a source fails.
+func applySources() error {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	// Variables set by the previous call are not part of the environment
+	lookupEnv := func(key string) (string, bool) {
+		if _, sourced := sourcedKeys[key]; sourced {
+			return "", false
+		}
+		return os.LookupEnv(key)
+	}
+
+	values, err := readSources(lookupEnv)
+	if err != nil {
+		return err
+	}
+
+	for key := range sourcedKeys {
+		os.Unsetenv(key)
+	}
+	sourcedKeys = make(map[string]struct{}, len(values))
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		sourcedKeys[key] = struct{}{}
+	}
+	return nil
+}
+
+// readSources returns the variables of all configured sources, later sources overriding
+// earlier ones
+func readSources(lookupEnv func(string) (string, bool)) (map[string]string, error) {
+	values := make(map[string]string)
+
+	if path, _ := lookupEnv("CONFIG_FILE"); path != "" {
+		fileValues, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range fileValues {
+			values[key] = value
+		}
+	}
+
+	// Source settings may come from the file
+	setting := func(key, defaultValue string) string {
+		if value, ok := lookupEnv(key); ok && value != "" {
+			return value
+		}
+		if value := values[key]; value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sourceRequestTimeout)
+	defer cancel()
+
+	if addr, prefix := setting("CONSUL_HTTP_ADDR", ""), setting("CONSUL_KV_PREFIX", ""); addr != "" && prefix != "" {
+		consulValues, err := readConsul(ctx, addr, setting("CONSUL_HTTP_TOKEN", ""), prefix)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range consulValues {
+			values[key] = value
+		}
+	}
+
+	if addr, path := setting("VAULT_ADDR", ""), setting("VAULT_SECRET_PATH", ""); addr != "" && path != "" {
+		vaultValues, err := readVault(ctx, addr, setting("VAULT_TOKEN", ""), setting("VAULT_NAMESPACE", ""), setting("VAULT_KV_MOUNT", "secret"), path)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range vaultValues {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// readConfigFile reads the variables of a YAML config file
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfig("", doc, values); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfig turns the nested keys of a config file into variable names
+func flattenConfig(prefix string, node map[string]interface{}, values map[string]string) error {
+	for key, value := range node {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flattenConfig(name, v, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, nested := item.(map[string]interface{}); nested {
+					return fmt.Errorf("%s: lists may only hold values", name)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// readConsul reads the keys below prefix of the Consul KV store
+func readConsul(ctx context.Context, addr, token, prefix string) (map[string]string, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/kv/"+prefix+"?recurse=true", nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONSUL_HTTP_ADDR: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	var entries []struct {
+		Key   string
+		Value string // base64, empty for folders
+	}
+	found, err := getSourceJSON(req, "Consul", &entries)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Key, prefix)
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of Consul key %s: %w", entry.Key, err)
+		}
+		values[name] = string(value)
+	}
+	return values, nil
+}
+
+// readVault reads the fields of a KV v2 secret
+func readVault(ctx context.Context, addr, token, namespace, mount, path string) (map[string]string, error) {
+	vault, err := secrets.NewVaultProvider(secrets.VaultConfig{
+		Address:   addr,
+		Token:     token,
+		Namespace: namespace,
+		Timeout:   sourceRequestTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	secret, err := vault.Read(ctx, strings.Trim(mount, "/")+"/data/"+strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault secret %s/%s: %w", mount, path, err)
+	}
+	return secret.Fields, nil
+}
+
+// getSourceJSON decodes the JSON response to req into out. It reports false when the
+// source answers 404.
+func getSourceJSON(req *http.Request, source string, out interface{}) (bool, error) {
+	resp, err := sourceHTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to read %s: status %d", source, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("invalid %s response: %w", source, err)
+	}
+	return true, nil
+}
+
+// Reloaded settings: a reload applies them to the running server, everything else only
+// takes effect after a restart
+var reloadableSections = map[string]bool{
+	"RateLimit": true,
+	"CORS":      true,
+}
+
+// RestartRequired returns the sections of next that differ from current in settings a
+// reload does not apply: rate limits, CORS policies and OAuth.DisabledProviders are applied,
+// every other setting needs a restart
+func RestartRequired(current, next *Config) []string {
+	cur, nxt := reflect.ValueOf(*current), reflect.ValueOf(*next)
+	var sections []string
+	for i := 0; i < cur.NumField(); i++ {
+		name := cur.Type().Field(i).Name
+		if reloadableSections[name] {
+			continue
+		}
+		a, b := cur.Field(i).Interface(), nxt.Field(i).Interface()
+		if name == "OAuth" {
+			oa, ob := current.OAuth, next.OAuth
+			oa.DisabledProviders, ob.DisabledProviders = nil, nil
+			a, b = oa, ob
+		}
+		if !reflect.DeepEqual(a, b) {
+			sections = append(sections, name)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
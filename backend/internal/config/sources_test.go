@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetSources forgets the variables set by previous tests
+func resetSources(t *testing.T) {
+	t.Helper()
+	for key := range sourcedKeys {
+		os.Unsetenv(key)
+	}
+	sourcedKeys = map[string]struct{}{}
+	t.Cleanup(func() {
+		for key := range sourcedKeys {
+			os.Unsetenv(key)
+		}
+		sourcedKeys = map[string]struct{}{}
+	})
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadConfigFile_ShouldFlattenNestedKeys(t *testing.T) {
+	path := writeConfigFile(t, `
+rate_limit:
+  signin_max: 20
+  enabled: true
+cors_allowed_origins:
+  - https://a.example.com
+  - https://b.example.com
+OAUTH_DISABLED_PROVIDERS: github
+empty:
+`)
+
+	values, err := readConfigFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"RATE_LIMIT_SIGNIN_MAX":    "20",
+		"RATE_LIMIT_ENABLED":       "true",
+		"CORS_ALLOWED_ORIGINS":     "https://a.example.com,https://b.example.com",
+		"OAUTH_DISABLED_PROVIDERS": "github",
+		"EMPTY":                    "",
+	}, values)
+}
+
+func TestReadConfigFile_ShouldFailOnInvalidYAML(t *testing.T) {
+	_, err := readConfigFile(writeConfigFile(t, "rate_limit: [unclosed"))
+	assert.Error(t, err)
+
+	_, err = readConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestApplySources_ShouldNotOverrideEnvironment(t *testing.T) {
+	resetSources(t)
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, "rate_limit_signin_max: 20\nrate_limit_signup_max: 7\n"))
+	t.Setenv("RATE_LIMIT_SIGNIN_MAX", "5")
+
+	require.NoError(t, applySources())
+
+	assert.Equal(t, "5", os.Getenv("RATE_LIMIT_SIGNIN_MAX"))
+	assert.Equal(t, "7", os.Getenv("RATE_LIMIT_SIGNUP_MAX"))
+}
+
+func TestApplySources_ShouldReplaceValuesOnReload(t *testing.T) {
+	resetSources(t)
+	path := writeConfigFile(t, "rate_limit_signin_max: 20\nrate_limit_signup_max: 7\n")
+	t.Setenv("CONFIG_FILE", path)
+
+	require.NoError(t, applySources())
+	assert.Equal(t, "20", os.Getenv("RATE_LIMIT_SIGNIN_MAX"))
+
+	require.NoError(t, os.WriteFile(path, []byte("rate_limit_signin_max: 30\n"), 0o600))
+	require.NoError(t, applySources())
+
+	assert.Equal(t, "30", os.Getenv("RATE_LIMIT_SIGNIN_MAX"))
+	_, set := os.LookupEnv("RATE_LIMIT_SIGNUP_MAX")
+	assert.False(t, set, "a value removed from the file should be unset")
+}
+
+func TestApplySources_ShouldKeepValuesWhenSourceFails(t *testing.T) {
+	resetSources(t)
+	path := writeConfigFile(t, "rate_limit_signin_max: 20\n")
+	t.Setenv("CONFIG_FILE", path)
+	require.NoError(t, applySources())
+
+	require.NoError(t, os.WriteFile(path, []byte("rate_limit_signin_max: [broken"), 0o600))
+	assert.Error(t, applySources())
+
+	assert.Equal(t, "20", os.Getenv("RATE_LIMIT_SIGNIN_MAX"))
+}
+
+func TestApplySources_ShouldReadConsulAndVault(t *testing.T) {
+	resetSources(t)
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/auth-gateway/", r.URL.Path)
+		assert.Equal(t, "consul-token", r.Header.Get("X-Consul-Token"))
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"Key": "auth-gateway/", "Value": nil},
+			{"Key": "auth-gateway/RATE_LIMIT_SIGNIN_MAX", "Value": base64.StdEncoding.EncodeToString([]byte("15"))},
+			{"Key": "auth-gateway/JWT_ACCESS_SECRET", "Value": base64.StdEncoding.EncodeToString([]byte("from-consul"))},
+		})
+	}))
+	defer consul.Close()
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/kv/data/auth-gateway", r.URL.Path)
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"JWT_ACCESS_SECRET": "from-vault"},
+				"metadata": map[string]interface{}{"version": 1},
+			},
+		})
+	}))
+	defer vault.Close()
+
+	// Source settings may come from the config file
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, "consul_http_addr: "+consul.URL+"\nconsul_kv_prefix: auth-gateway\nrate_limit_signin_max: 20\n"))
+	t.Setenv("CONSUL_HTTP_TOKEN", "consul-token")
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+	t.Setenv("VAULT_KV_MOUNT", "kv")
+	t.Setenv("VAULT_SECRET_PATH", "auth-gateway")
+	for _, key := range []string{"JWT_ACCESS_SECRET", "RATE_LIMIT_SIGNIN_MAX"} {
+		t.Setenv(key, "") // restored after the test
+		os.Unsetenv(key)
+	}
+
+	require.NoError(t, applySources())
+
+	assert.Equal(t, "15", os.Getenv("RATE_LIMIT_SIGNIN_MAX"))
+	assert.Equal(t, "from-vault", os.Getenv("JWT_ACCESS_SECRET"))
+}
+
+func TestApplySources_ShouldFailWhenVaultSecretIsMissing(t *testing.T) {
+	resetSources(t)
+	vault := httptest.NewServer(http.NotFoundHandler())
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_SECRET_PATH", "auth-gateway")
+
+	assert.Error(t, applySources())
+}
+
+func TestRestartRequired(t *testing.T) {
+	current := &Config{
+		Server:    ServerConfig{Port: "8181"},
+		RateLimit: RateLimitConfig{SigninMax: 5},
+		OAuth:     OAuthConfig{DisabledProviders: []string{"github"}},
+	}
+
+	next := *current
+	next.RateLimit.SigninMax = 10
+	next.CORS.Default.AllowedOrigins = []string{"https://example.com"}
+	next.OAuth.DisabledProviders = nil
+	assert.Empty(t, RestartRequired(current, &next))
+
+	next.Server.Port = "9090"
+	next.OAuth.Google.ClientID = "client"
+	assert.Equal(t, []string{"OAuth", "Server"}, RestartRequired(current, &next))
+}
@@ -0,0 +1,75 @@
+// Package credentials creates the downstream credentials handed out by the credentials
+// broker: temporary Postgres login roles and SSH user certificates.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresProvisioner creates and drops temporary login roles on a Postgres server. Its
+// connection needs the CREATEROLE attribute and must be allowed to grant the target roles.
+type PostgresProvisioner struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresProvisioner connects to the Postgres server that temporary roles are created on
+func NewPostgresProvisioner(ctx context.Context, dsn string) (*PostgresProvisioner, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to credentials postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to credentials postgres: %w", err)
+	}
+	return &PostgresProvisioner{pool: pool}, nil
+}
+
+// CreateRole creates a login role that is a member of grantRole and cannot log in after
+// validUntil. Postgres keeps sessions opened before then; DropRole ends them.
+func (p *PostgresProvisioner) CreateRole(ctx context.Context, username, password, grantRole string, validUntil time.Time) error {
+	// DDL takes no bind parameters, so identifiers and literals are quoted by format()
+	var stmt string
+	err := p.pool.QueryRow(ctx,
+		"SELECT format('CREATE ROLE %I WITH LOGIN PASSWORD %L VALID UNTIL %L IN ROLE %I', $1::text, $2::text, $3::text, $4::text)",
+		username, password, validUntil.UTC().Format(time.RFC3339), grantRole,
+	).Scan(&stmt)
+	if err != nil {
+		return fmt.Errorf("failed to build create role statement: %w", err)
+	}
+
+	if _, err := p.pool.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// DropRole ends the role's sessions and drops it. Dropping a role that does not exist
+// succeeds. It fails while the role still owns objects.
+func (p *PostgresProvisioner) DropRole(ctx context.Context, username string) error {
+	_, err := p.pool.Exec(ctx,
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = $1",
+		username,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to terminate role sessions: %w", err)
+	}
+
+	var stmt string
+	if err := p.pool.QueryRow(ctx, "SELECT format('DROP ROLE IF EXISTS %I', $1::text)", username).Scan(&stmt); err != nil {
+		return fmt.Errorf("failed to build drop role statement: %w", err)
+	}
+	if _, err := p.pool.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to drop role: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection pool
+func (p *PostgresProvisioner) Close() {
+	p.pool.Close()
+}
@@ -0,0 +1,91 @@
+package credentials
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestKey(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
+
+func TestSSHSigner_SignUserKey(t *testing.T) {
+	caKey, _ := newTestKey(t)
+	_, userPub := newTestKey(t)
+	now := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	signer := NewSSHSignerFromSigner(caKey)
+	signer.now = func() time.Time { return now }
+
+	t.Run("ShouldIssueCertificateTrustedByCA", func(t *testing.T) {
+		// Act
+		certText, err := signer.SignUserKey(userPub, "user-1/bastion", []string{"deploy"}, 42, now.Add(time.Hour))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certText))
+		if err != nil {
+			t.Fatalf("certificate does not parse: %v", err)
+		}
+		cert := parsed.(*ssh.Certificate)
+
+		checker := &ssh.CertChecker{
+			IsUserAuthority: func(auth ssh.PublicKey) bool {
+				return string(auth.Marshal()) == string(caKey.PublicKey().Marshal())
+			},
+			Clock: func() time.Time { return now },
+		}
+		if err := checker.CheckCert("deploy", cert); err != nil {
+			t.Errorf("certificate rejected: %v", err)
+		}
+		if err := checker.CheckCert("root", cert); err == nil {
+			t.Error("expected certificate to be rejected for another principal")
+		}
+		if cert.Serial != 42 || cert.KeyId != "user-1/bastion" || cert.CertType != ssh.UserCert {
+			t.Errorf("unexpected certificate fields: serial=%d key_id=%q type=%d", cert.Serial, cert.KeyId, cert.CertType)
+		}
+		if _, ok := cert.Extensions["permit-port-forwarding"]; ok {
+			t.Error("expected forwarding not to be permitted")
+		}
+	})
+
+	t.Run("ShouldExpireAtValidBefore", func(t *testing.T) {
+		// Act
+		certText, _ := signer.SignUserKey(userPub, "user-1/bastion", []string{"deploy"}, 1, now.Add(time.Hour))
+
+		// Assert
+		parsed, _, _, _, _ := ssh.ParseAuthorizedKey([]byte(certText))
+		checker := &ssh.CertChecker{
+			IsUserAuthority: func(ssh.PublicKey) bool { return true },
+			Clock:           func() time.Time { return now.Add(2 * time.Hour) },
+		}
+		if err := checker.CheckCert("deploy", parsed.(*ssh.Certificate)); err == nil {
+			t.Error("expected expired certificate to be rejected")
+		}
+	})
+
+	t.Run("ShouldRejectInvalidPublicKey", func(t *testing.T) {
+		// Act
+		_, err := signer.SignUserKey("ssh-ed25519 not-base64", "user-1/bastion", []string{"deploy"}, 1, now.Add(time.Hour))
+
+		// Assert
+		if !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("expected ErrInvalidPublicKey, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaContentType    = "application/vnd.kafka.json.v2+json"
+	kafkaAccept         = "application/vnd.kafka.v2+json"
+	kafkaDefaultTimeout = 10 * time.Second
+)
+
+// KafkaPublisher implements Publisher for Kafka through a REST proxy speaking the v2 API of
+// the Confluent REST Proxy, which Redpanda and Karapace also serve. Records are keyed, so
+// the events of one user land in one partition, in order.
+type KafkaPublisher struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// KafkaConfig holds Kafka REST proxy configuration
+type KafkaConfig struct {
+	// RESTURL is the base URL of the REST proxy, e.g. http://kafka-rest:8082
+	RESTURL string
+	// Username and Password are sent with basic auth when set
+	Username string
+	Password string
+	// Timeout bounds a publish when the context has no deadline; defaults to 10s
+	Timeout time.Duration
+}
+
+// kafkaRecord is a record of a produce request
+type kafkaRecord struct {
+	Key   string          `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaProduceResponse is the answer to a produce request, with one offset per record
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// kafkaErrorResponse is the body of a failed request
+type kafkaErrorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// NewKafkaPublisher creates a new Kafka REST proxy publisher
+func NewKafkaPublisher(config KafkaConfig) (*KafkaPublisher, error) {
+	if config.RESTURL == "" {
+		return nil, fmt.Errorf("%w: Kafka REST proxy URL is required", ErrNotConfigured)
+	}
+	if _, err := url.ParseRequestURI(config.RESTURL); err != nil {
+		return nil, fmt.Errorf("%w: invalid Kafka REST proxy URL: %v", ErrNotConfigured, err)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = kafkaDefaultTimeout
+	}
+
+	return &KafkaPublisher{
+		baseURL:  strings.TrimRight(config.RESTURL, "/"),
+		username: config.Username,
+		password: config.Password,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Publish produces payload as a JSON record keyed with key. Kafka has no use for id:
+// consumers deduplicate on the event ID in the payload.
+func (p *KafkaPublisher) Publish(ctx context.Context, topic, key, id string, payload []byte) error {
+	body, err := json.Marshal(map[string][]kafkaRecord{
+		"records": {{Key: key, Value: payload}},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", kafkaAccept)
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		var errResp kafkaErrorResponse
+		_ = json.Unmarshal(respBody, &errResp)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: REST proxy returned %d: %s", ErrUnavailable, resp.StatusCode, errResp.Message)
+		}
+		return fmt.Errorf("%w: REST proxy returned %d: %s", ErrRejected, resp.StatusCode, errResp.Message)
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &produced); err != nil {
+		return fmt.Errorf("%w: invalid REST proxy response: %v", ErrUnavailable, err)
+	}
+	if len(produced.Offsets) != 1 {
+		return fmt.Errorf("%w: REST proxy returned %d offsets for one record", ErrUnavailable, len(produced.Offsets))
+	}
+	if offset := produced.Offsets[0]; offset.ErrorCode != nil {
+		return fmt.Errorf("%w: broker error %d: %s", ErrRejected, *offset.ErrorCode, offset.Error)
+	}
+
+	return nil
+}
+
+// Name returns the provider name
+func (p *KafkaPublisher) Name() string {
+	return string(ProviderKafka)
+}
+
+// Close releases idle connections to the REST proxy
+func (p *KafkaPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
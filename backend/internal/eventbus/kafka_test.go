@@ -0,0 +1,67 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	var body map[string][]map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/auth.events", r.URL.Path)
+		assert.Equal(t, kafkaContentType, r.Header.Get("Content-Type"))
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "gateway", username)
+		assert.Equal(t, "secret", password)
+
+		data, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(data, &body))
+		w.Write([]byte(`{"offsets":[{"partition":2,"offset":41,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	publisher, err := NewKafkaPublisher(KafkaConfig{RESTURL: server.URL + "/", Username: "gateway", Password: "secret"})
+	require.NoError(t, err)
+
+	err = publisher.Publish(context.Background(), "auth.events", "user-1", "event-1", []byte(`{"type":"user.signed_in"}`))
+	require.NoError(t, err)
+
+	require.Len(t, body["records"], 1)
+	assert.JSONEq(t, `"user-1"`, string(body["records"][0]["key"]))
+	assert.JSONEq(t, `{"type":"user.signed_in"}`, string(body["records"][0]["value"]))
+}
+
+func TestKafkaPublisher_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     error
+	}{
+		{"unknown topic", http.StatusNotFound, `{"error_code":40401,"message":"Topic not found."}`, ErrRejected},
+		{"proxy failure", http.StatusInternalServerError, `{"error_code":50001,"message":"Zookeeper error"}`, ErrUnavailable},
+		{"broker error", http.StatusOK, `{"offsets":[{"partition":null,"offset":null,"error_code":2,"error":"Leader not available"}]}`, ErrRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			publisher, err := NewKafkaPublisher(KafkaConfig{RESTURL: server.URL})
+			require.NoError(t, err)
+
+			err = publisher.Publish(context.Background(), "auth.events", "", "event-1", []byte(`{}`))
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
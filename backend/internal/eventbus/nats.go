@@ -0,0 +1,450 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	natsDefaultPort    = "4222"
+	natsDefaultTimeout = 10 * time.Second
+	natsMsgIDHeader    = "Nats-Msg-Id"
+	natsMaxLineLength  = 64 << 10
+)
+
+// NATSPublisher implements Publisher for NATS over the client protocol. With JetStream, each
+// message waits for the acknowledgement of the stream capturing its subject and carries a
+// Nats-Msg-Id header, so the stream drops copies republished within its duplicate window.
+// One connection is kept open and shared by all publishes, which run one at a time; it is
+// reopened on the next publish after an error.
+type NATSPublisher struct {
+	addr      string
+	useTLS    bool
+	user      string
+	password  string
+	token     string
+	jetStream bool
+	timeout   time.Duration
+
+	mu   sync.Mutex
+	conn *natsConn
+}
+
+// NATSConfig holds NATS configuration
+type NATSConfig struct {
+	// URL is the server address, e.g. nats://nats:4222; tls:// connects over TLS
+	URL string
+	// User and Password, or Token, authenticate the connection when set
+	User     string
+	Password string
+	Token    string
+	// JetStream waits for the stream's acknowledgement of each message. Without it a message
+	// counts as published once the server has received it, and is lost if nobody listens.
+	JetStream bool
+	// Timeout bounds a publish when the context has no deadline; defaults to 10s
+	Timeout time.Duration
+}
+
+// natsConn is an open client connection
+type natsConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	inbox      string
+	maxPayload int
+	replies    uint64
+}
+
+// natsInfo is the part of the server's INFO the publisher needs
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+	Headers     bool `json:"headers"`
+}
+
+// natsPubAck is JetStream's answer to a published message
+type natsPubAck struct {
+	Stream    string `json:"stream"`
+	Sequence  uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate"`
+	Error     *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+// NewNATSPublisher creates a new NATS publisher
+func NewNATSPublisher(config NATSConfig) (*NATSPublisher, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("%w: NATS URL is required", ErrNotConfigured)
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: NATS URL must look like nats://host:4222", ErrNotConfigured)
+	}
+
+	publisher := &NATSPublisher{
+		addr:      u.Host,
+		user:      config.User,
+		password:  config.Password,
+		token:     config.Token,
+		jetStream: config.JetStream,
+		timeout:   config.Timeout,
+	}
+	switch u.Scheme {
+	case "nats":
+	case "tls":
+		publisher.useTLS = true
+	default:
+		return nil, fmt.Errorf("%w: unsupported NATS URL scheme %q", ErrNotConfigured, u.Scheme)
+	}
+	if u.Port() == "" {
+		publisher.addr = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	if u.User != nil && publisher.user == "" {
+		publisher.user = u.User.Username()
+		publisher.password, _ = u.User.Password()
+	}
+	if publisher.timeout <= 0 {
+		publisher.timeout = natsDefaultTimeout
+	}
+
+	return publisher, nil
+}
+
+// Publish publishes payload to the subject topic
+func (p *NATSPublisher) Publish(ctx context.Context, topic, key, id string, payload []byte) error {
+	if topic == "" || strings.ContainsAny(topic, " \t\r\n") {
+		return fmt.Errorf("%w: invalid NATS subject %q", ErrRejected, topic)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		conn, err := p.connect(ctx)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		p.conn = conn
+	}
+
+	err := p.publish(ctx, p.conn, topic, id, payload)
+	var ackErr *natsAckError
+	if err != nil && !errors.As(err, &ackErr) {
+		// The connection is in an unknown state, e.g. with an unread reply
+		_ = p.conn.conn.Close()
+		p.conn = nil
+		if errors.Is(err, ErrRejected) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return err
+}
+
+// Name returns the provider name
+func (p *NATSPublisher) Name() string {
+	return string(ProviderNATS)
+}
+
+// Close closes the connection
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// natsAckError is a publish JetStream refused; the connection stays usable
+type natsAckError struct {
+	message string
+}
+
+func (e *natsAckError) Error() string {
+	return e.message
+}
+
+func (e *natsAckError) Unwrap() error {
+	return ErrRejected
+}
+
+// connect opens a connection, authenticates and, with JetStream, subscribes to the inbox
+// acknowledgements come back to
+func (p *NATSPublisher) connect(ctx context.Context) (*natsConn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	nc := &natsConn{conn: conn, reader: bufio.NewReader(conn)}
+	info, err := nc.readInfo()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !info.Headers {
+		conn.Close()
+		return nil, errors.New("server does not support headers (NATS 2.2 or later required)")
+	}
+	if info.TLSRequired && !p.useTLS {
+		conn.Close()
+		return nil, errors.New("server requires TLS, use a tls:// URL")
+	}
+
+	if p.useTLS {
+		host, _, _ := net.SplitHostPort(p.addr)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		nc.conn = tlsConn
+		nc.reader = bufio.NewReader(tlsConn)
+	}
+	nc.maxPayload = info.MaxPayload
+
+	connectOpts, _ := json.Marshal(map[string]interface{}{
+		"verbose":       false,
+		"pedantic":      false,
+		"tls_required":  p.useTLS,
+		"user":          p.user,
+		"pass":          p.password,
+		"auth_token":    p.token,
+		"name":          "auth-gateway",
+		"lang":          "go",
+		"version":       "1.0.0",
+		"protocol":      1,
+		"headers":       true,
+		"no_responders": true,
+	})
+	cmd := "CONNECT " + string(connectOpts) + "\r\n"
+	if p.jetStream {
+		inbox := make([]byte, 12)
+		if _, err := rand.Read(inbox); err != nil {
+			nc.conn.Close()
+			return nil, err
+		}
+		nc.inbox = "_INBOX." + hex.EncodeToString(inbox)
+		cmd += "SUB " + nc.inbox + ".* 1\r\n"
+	}
+	cmd += "PING\r\n"
+
+	if _, err := io.WriteString(nc.conn, cmd); err != nil {
+		nc.conn.Close()
+		return nil, err
+	}
+	// The server answers a failed CONNECT, e.g. bad credentials, with -ERR instead of PONG
+	if err := nc.waitPong(); err != nil {
+		nc.conn.Close()
+		return nil, err
+	}
+
+	return nc, nil
+}
+
+// publish sends one message and waits for it to be accepted
+func (p *NATSPublisher) publish(ctx context.Context, nc *natsConn, subject, id string, payload []byte) error {
+	if nc.maxPayload > 0 && len(payload) > nc.maxPayload {
+		return fmt.Errorf("%w: payload of %d bytes exceeds the server's maximum of %d", ErrRejected, len(payload), nc.maxPayload)
+	}
+	deadline, _ := ctx.Deadline()
+	if err := nc.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	header := "NATS/1.0\r\n"
+	if id != "" {
+		header += natsMsgIDHeader + ": " + id + "\r\n"
+	}
+	header += "\r\n"
+
+	reply := ""
+	if p.jetStream {
+		nc.replies++
+		reply = nc.inbox + "." + strconv.FormatUint(nc.replies, 10)
+	}
+
+	var cmd strings.Builder
+	cmd.WriteString("HPUB " + subject + " ")
+	if reply != "" {
+		cmd.WriteString(reply + " ")
+	}
+	cmd.WriteString(strconv.Itoa(len(header)) + " " + strconv.Itoa(len(header)+len(payload)) + "\r\n")
+	cmd.WriteString(header)
+	cmd.Write(payload)
+	cmd.WriteString("\r\n")
+	if !p.jetStream {
+		// The PONG tells the server has processed the message
+		cmd.WriteString("PING\r\n")
+	}
+	if _, err := io.WriteString(nc.conn, cmd.String()); err != nil {
+		return err
+	}
+
+	if !p.jetStream {
+		return nc.waitPong()
+	}
+	return nc.waitAck(reply)
+}
+
+// readInfo reads the INFO the server greets with
+func (nc *natsConn) readInfo() (*natsInfo, error) {
+	line, err := nc.readLine()
+	if err != nil {
+		return nil, err
+	}
+	op, args, _ := strings.Cut(line, " ")
+	if !strings.EqualFold(op, "INFO") {
+		return nil, fmt.Errorf("expected INFO from server, got %q", op)
+	}
+	info := &natsInfo{}
+	if err := json.Unmarshal([]byte(args), info); err != nil {
+		return nil, fmt.Errorf("invalid INFO: %v", err)
+	}
+	return info, nil
+}
+
+// waitPong reads until the server's PONG
+func (nc *natsConn) waitPong() error {
+	for {
+		line, err := nc.readLine()
+		if err != nil {
+			return err
+		}
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "PONG":
+			return nil
+		case "PING":
+			if _, err := io.WriteString(nc.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case "-ERR":
+			return fmt.Errorf("server error: %s", strings.Trim(args, "'"))
+		case "MSG", "HMSG":
+			// A late acknowledgement of a publish that timed out
+			if _, _, err := nc.readMessage(op, args); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// waitAck reads until the acknowledgement sent to reply
+func (nc *natsConn) waitAck(reply string) error {
+	for {
+		line, err := nc.readLine()
+		if err != nil {
+			return err
+		}
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "PING":
+			if _, err := io.WriteString(nc.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case "-ERR":
+			return fmt.Errorf("server error: %s", strings.Trim(args, "'"))
+		case "MSG", "HMSG":
+			subject, header, body, err := nc.readReply(op, args)
+			if err != nil {
+				return err
+			}
+			if subject != reply {
+				continue
+			}
+			// With no_responders the server answers a subject no stream captures with a 503
+			if strings.HasPrefix(header, "NATS/1.0 503") {
+				return &natsAckError{message: "no JetStream stream captures the subject"}
+			}
+			var ack natsPubAck
+			if err := json.Unmarshal(body, &ack); err != nil {
+				return fmt.Errorf("invalid JetStream acknowledgement: %v", err)
+			}
+			if ack.Error != nil {
+				return &natsAckError{message: fmt.Sprintf("JetStream error %d: %s", ack.Error.Code, ack.Error.Description)}
+			}
+			return nil
+		}
+	}
+}
+
+// readReply reads a MSG or HMSG and returns its subject, header block and body
+func (nc *natsConn) readReply(op, args string) (string, string, []byte, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", "", nil, fmt.Errorf("malformed %s", op)
+	}
+	header, body, err := nc.readMessage(op, args)
+	return fields[0], header, body, err
+}
+
+// readMessage reads the payload of a MSG (subject sid [reply] size) or HMSG
+// (subject sid [reply] header-size total-size) and splits off the headers
+func (nc *natsConn) readMessage(op, args string) (string, []byte, error) {
+	fields := strings.Fields(args)
+	headerSize := 0
+	var err error
+	if strings.EqualFold(op, "HMSG") {
+		if len(fields) < 4 {
+			return "", nil, errors.New("malformed HMSG")
+		}
+		headerSize, err = strconv.Atoi(fields[len(fields)-2])
+		if err != nil {
+			return "", nil, errors.New("malformed HMSG")
+		}
+	} else if len(fields) < 3 {
+		return "", nil, errors.New("malformed MSG")
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < headerSize || size > natsMaxLineLength {
+		return "", nil, fmt.Errorf("malformed %s", op)
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(nc.reader, data); err != nil {
+		return "", nil, err
+	}
+	return string(data[:headerSize]), data[headerSize:size], nil
+}
+
+// readLine reads a protocol line without its CRLF
+func (nc *natsConn) readLine() (string, error) {
+	line, err := nc.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) > natsMaxLineLength {
+		return "", errors.New("protocol line too long")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,79 @@
+// Package eventbus publishes auth events to message brokers. Publishers talk to the brokers
+// over their wire protocols directly: NATS through its client protocol, with JetStream
+// acknowledgements, and Kafka through a Confluent-compatible REST proxy.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Publisher publishes messages to a broker
+type Publisher interface {
+	// Publish sends payload to topic and returns once the broker has accepted it. key
+	// identifies the message's entity: Kafka partitions on it, NATS ignores it. id is
+	// unique to the message, so brokers that deduplicate can drop a republished copy.
+	Publish(ctx context.Context, topic, key, id string, payload []byte) error
+
+	// Name returns the name of the broker type
+	Name() string
+
+	// Close releases the publisher's connections
+	Close() error
+}
+
+var (
+	// ErrNotConfigured is returned when the publisher is not properly configured
+	ErrNotConfigured = errors.New("event bus not configured")
+
+	// ErrUnavailable is returned when the broker cannot be reached
+	ErrUnavailable = errors.New("event bus unavailable")
+
+	// ErrRejected is returned when the broker refuses a message
+	ErrRejected = errors.New("event rejected by the event bus")
+)
+
+// ProviderType is the type of broker events are published to
+type ProviderType string
+
+const (
+	ProviderNATS  ProviderType = "nats"
+	ProviderKafka ProviderType = "kafka"
+)
+
+// IsValid checks if the provider type is valid
+func (p ProviderType) IsValid() bool {
+	switch p {
+	case ProviderNATS, ProviderKafka:
+		return true
+	}
+	return false
+}
+
+// Config holds the configuration of the publisher of one of the providers
+type Config struct {
+	Provider    ProviderType
+	NATSConfig  *NATSConfig
+	KafkaConfig *KafkaConfig
+}
+
+// NewPublisher creates a publisher based on configuration
+func NewPublisher(config Config) (Publisher, error) {
+	switch config.Provider {
+	case ProviderNATS:
+		if config.NATSConfig == nil {
+			return nil, fmt.Errorf("%w: NATS configuration is required", ErrNotConfigured)
+		}
+		return NewNATSPublisher(*config.NATSConfig)
+
+	case ProviderKafka:
+		if config.KafkaConfig == nil {
+			return nil, fmt.Errorf("%w: Kafka configuration is required", ErrNotConfigured)
+		}
+		return NewKafkaPublisher(*config.KafkaConfig)
+
+	default:
+		return nil, fmt.Errorf("unsupported event bus provider: %s", config.Provider)
+	}
+}
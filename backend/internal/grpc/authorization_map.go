@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"sort"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	pb "github.com/smilemakc/auth-gateway/proto"
+)
+
+// MethodAuthorization returns what every method of the auth service requires of its caller,
+// as enforced by the API key interceptors
+func MethodAuthorization() []models.GRPCMethodAuthorization {
+	desc := pb.AuthService_ServiceDesc
+	methods := make([]models.GRPCMethodAuthorization, 0, len(desc.Methods)+len(desc.Streams))
+	add := func(name string, streaming bool) {
+		fullMethod := "/" + desc.ServiceName + "/" + name
+		method := models.GRPCMethodAuthorization{
+			Method:    fullMethod,
+			Streaming: streaming,
+		}
+		if scope, ok := methodScopes[fullMethod]; ok {
+			method.Authentication = []string{models.CredentialAPIKey, models.CredentialAppSecret}
+			method.Scope = string(scope)
+		} else {
+			method.Denied = true
+		}
+		methods = append(methods, method)
+	}
+
+	for _, m := range desc.Methods {
+		add(m.MethodName, false)
+	}
+	for _, s := range desc.Streams {
+		add(s.StreamName, true)
+	}
+
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Method < methods[j].Method })
+	return methods
+}
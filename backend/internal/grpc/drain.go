@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errStreamExpired  = errors.New("stream reached its maximum duration, reconnect")
+	errServerDraining = errors.New("server is shutting down, reconnect")
+)
+
+// streamDrainer ends long-lived streams such as WatchRevocations cleanly, so rolling
+// deployments and connection ageing move their clients instead of cutting them off.
+// A stream ends when it reaches its maximum duration or when the server starts draining;
+// the client gets codes.Unavailable, which it treats as a reason to reconnect, and the
+// reconnect lands on a fresh connection - to another instance when this one is going away.
+type streamDrainer struct {
+	maxDuration time.Duration // 0 lets streams run until the client or the server ends them
+	draining    chan struct{}
+	once        sync.Once
+}
+
+func newStreamDrainer(maxDuration time.Duration) *streamDrainer {
+	return &streamDrainer{
+		maxDuration: maxDuration,
+		draining:    make(chan struct{}),
+	}
+}
+
+// Drain asks every open stream to end
+func (d *streamDrainer) Drain() {
+	d.once.Do(func() { close(d.draining) })
+}
+
+// interceptor gives each stream a context that ends with the stream's lifetime. Handlers
+// already return when their context is done; a stream ended that way reports Unavailable.
+func (d *streamDrainer) interceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithCancelCause(ss.Context())
+		defer cancel(nil)
+
+		if d.maxDuration > 0 {
+			timer := time.AfterFunc(d.streamDuration(), func() { cancel(errStreamExpired) })
+			defer timer.Stop()
+		}
+		go func() {
+			select {
+			case <-d.draining:
+				cancel(errServerDraining)
+			case <-ctx.Done():
+			}
+		}()
+
+		err := handler(srv, &drainableStream{ServerStream: ss, ctx: ctx})
+
+		cause := context.Cause(ctx)
+		if errors.Is(cause, errStreamExpired) || errors.Is(cause, errServerDraining) {
+			if code := status.Code(err); err == nil || code == codes.Canceled || code == codes.DeadlineExceeded {
+				return status.Error(codes.Unavailable, cause.Error())
+			}
+		}
+		return err
+	}
+}
+
+// streamDuration is maxDuration shortened by up to a tenth, so streams opened together,
+// such as after a deployment, do not all reconnect at the same moment
+func (d *streamDrainer) streamDuration() time.Duration {
+	return d.maxDuration - time.Duration(rand.Int64N(int64(d.maxDuration)/10+1))
+}
+
+// drainableStream is a server stream whose context ends with the stream's lifetime
+type drainableStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *drainableStream) Context() context.Context { return s.ctx }
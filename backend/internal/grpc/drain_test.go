@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// waitForContext is a stream handler that runs until its context is done, like WatchRevocations
+func waitForContext(srv interface{}, ss grpc.ServerStream) error {
+	<-ss.Context().Done()
+	return nil
+}
+
+func TestStreamDrainer_ShouldEndStreamAtMaxDuration(t *testing.T) {
+	drainer := newStreamDrainer(20 * time.Millisecond)
+
+	err := drainer.interceptor()(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, waitForContext)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestStreamDrainer_ShouldEndStreamsOnDrain(t *testing.T) {
+	drainer := newStreamDrainer(0)
+	done := make(chan error, 1)
+	go func() {
+		done <- drainer.interceptor()(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, waitForContext)
+	}()
+
+	drainer.Drain()
+	drainer.Drain()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end on drain")
+	}
+}
+
+func TestStreamDrainer_ShouldKeepOtherEndings(t *testing.T) {
+	drainer := newStreamDrainer(time.Hour)
+
+	t.Run("ClientCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := drainer.interceptor()(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{}, waitForContext)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		handlerErr := status.Error(codes.Internal, "boom")
+
+		err := drainer.interceptor()(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+			return handlerErr
+		})
+
+		assert.True(t, errors.Is(err, handlerErr))
+	})
+}
+
+func TestStreamDrainer_StreamDuration(t *testing.T) {
+	drainer := newStreamDrainer(10 * time.Minute)
+
+	for i := 0; i < 100; i++ {
+		d := drainer.streamDuration()
+		assert.LessOrEqual(t, d, 10*time.Minute)
+		assert.GreaterOrEqual(t, d, 9*time.Minute)
+	}
+}
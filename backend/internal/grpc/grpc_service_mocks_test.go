@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
@@ -77,6 +78,15 @@ func (m *mockUserStoreGRPC) GetUsersUpdatedAfter(ctx context.Context, after time
 	}
 	return nil, 0, nil
 }
+func (m *mockUserStoreGRPC) Search(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockUserStoreGRPC) Filter(ctx context.Context, limit, offset int, opts ...service.UserFilterOption) ([]*models.User, error) {
+	return nil, nil
+}
+func (m *mockUserStoreGRPC) CountFiltered(ctx context.Context, opts ...service.UserFilterOption) (int, error) {
+	return 0, nil
+}
 func (m *mockUserStoreGRPC) UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
 	return nil
 }
@@ -117,6 +127,7 @@ type mockRBACStoreGRPC struct {
 	HasPermissionFunc    func(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
 	GetUserRolesFunc     func(ctx context.Context, userID uuid.UUID) ([]models.Role, error)
 	GetUserRolesInAppFunc func(ctx context.Context, userID uuid.UUID, appID *uuid.UUID) ([]models.Role, error)
+	GetRoleByNameAndAppFunc func(ctx context.Context, name string, appID *uuid.UUID) (*models.Role, error)
 }
 
 func (m *mockRBACStoreGRPC) CreatePermission(ctx context.Context, permission *models.Permission) error {
@@ -131,7 +142,7 @@ func (m *mockRBACStoreGRPC) GetPermissionByName(ctx context.Context, name string
 func (m *mockRBACStoreGRPC) ListPermissions(ctx context.Context) ([]models.Permission, error) {
 	return nil, nil
 }
-func (m *mockRBACStoreGRPC) UpdatePermission(ctx context.Context, id uuid.UUID, description string) error {
+func (m *mockRBACStoreGRPC) UpdatePermission(ctx context.Context, id uuid.UUID, description string, condition *string) error {
 	return nil
 }
 func (m *mockRBACStoreGRPC) DeletePermission(ctx context.Context, id uuid.UUID) error { return nil }
@@ -155,7 +166,43 @@ func (m *mockRBACStoreGRPC) DeleteRole(ctx context.Context, id uuid.UUID) error
 func (m *mockRBACStoreGRPC) SetRolePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
 	return nil
 }
+func (m *mockRBACStoreGRPC) ListRoleParents(ctx context.Context) ([]models.RoleParent, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreGRPC) SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreGRPC) ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreGRPC) SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreGRPC) CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error {
+	return nil
+}
+func (m *mockRBACStoreGRPC) GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreGRPC) GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreGRPC) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreGRPC) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error {
+	return nil
+}
+func (m *mockRBACStoreGRPC) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreGRPC) SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return nil
+}
 func (m *mockRBACStoreGRPC) GetRoleByNameAndApp(ctx context.Context, name string, appID *uuid.UUID) (*models.Role, error) {
+	if m.GetRoleByNameAndAppFunc != nil {
+		return m.GetRoleByNameAndAppFunc(ctx, name, appID)
+	}
 	return nil, nil
 }
 func (m *mockRBACStoreGRPC) ListRolesByApp(ctx context.Context, appID *uuid.UUID) ([]models.Role, error) {
@@ -300,6 +347,9 @@ func (m *mockAuthServicerGRPC) CompletePasswordlessRegistration(ctx context.Cont
 func (m *mockAuthServicerGRPC) GenerateTokensForUser(ctx context.Context, user *models.User, ip, userAgent string) (*models.AuthResponse, error) {
 	return nil, nil
 }
+func (m *mockAuthServicerGRPC) CompleteRequiredPasswordChange(ctx context.Context, resetToken, newPassword, ip, userAgent string) error {
+	return nil
+}
 
 // ===================== mockOTPServicerGRPC =====================
 
@@ -391,16 +441,25 @@ func (m *mockOAuthProviderServicerGRPC) IntrospectToken(ctx context.Context, tok
 func (m *mockOAuthProviderServicerGRPC) RevokeToken(ctx context.Context, token, tokenTypeHint string, clientID *string) error {
 	return nil
 }
-func (m *mockOAuthProviderServicerGRPC) GetUserInfo(ctx context.Context, accessToken string) (*models.UserInfoResponse, error) {
+func (m *mockOAuthProviderServicerGRPC) GetUserInfo(ctx context.Context, accessToken, clientCertThumbprint string) (*models.UserInfoResponse, error) {
 	return nil, nil
 }
 func (m *mockOAuthProviderServicerGRPC) GetDiscoveryDocument() *models.OIDCDiscoveryDocument {
 	return nil
 }
 func (m *mockOAuthProviderServicerGRPC) GetJWKS() *models.JWKSDocument { return nil }
+func (m *mockOAuthProviderServicerGRPC) SignAuthorizationResponse(clientID string, params url.Values) (string, error) {
+	return "", nil
+}
+func (m *mockOAuthProviderServicerGRPC) EndSession(ctx context.Context, req *models.EndSessionRequest, userID *uuid.UUID) (*models.EndSessionResult, error) {
+	return nil, nil
+}
 func (m *mockOAuthProviderServicerGRPC) GetConsentInfo(ctx context.Context, clientID string, scopes []string) (*service.ConsentInfo, error) {
 	return nil, nil
 }
+func (m *mockOAuthProviderServicerGRPC) GetDeviceConsentInfo(ctx context.Context, userCode string) (*service.ConsentInfo, error) {
+	return nil, nil
+}
 func (m *mockOAuthProviderServicerGRPC) GrantConsent(ctx context.Context, userID uuid.UUID, clientID string, scopes []string) error {
 	return nil
 }
@@ -419,10 +478,38 @@ func (m *mockOAuthProviderServicerGRPC) CreateScope(ctx context.Context, scope *
 func (m *mockOAuthProviderServicerGRPC) DeleteScope(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
+func (m *mockOAuthProviderServicerGRPC) ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+	return nil, nil
+}
+func (m *mockOAuthProviderServicerGRPC) CreateScopeGroup(ctx context.Context, req *models.CreateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	return nil, nil
+}
+func (m *mockOAuthProviderServicerGRPC) UpdateScopeGroup(ctx context.Context, id uuid.UUID, req *models.UpdateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	return nil, nil
+}
+func (m *mockOAuthProviderServicerGRPC) DeleteScopeGroup(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
 func (m *mockOAuthProviderServicerGRPC) ListClientConsents(ctx context.Context, clientID uuid.UUID) ([]*models.UserConsent, error) {
 	return nil, nil
 }
 
+func (m *mockOAuthProviderServicerGRPC) ListConnectedApps(ctx context.Context, userID uuid.UUID) ([]*models.ConnectedApp, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthProviderServicerGRPC) RevokeConnectedApp(ctx context.Context, userID, clientID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockOAuthProviderServicerGRPC) EmulateClientFlow(ctx context.Context, id uuid.UUID, req *models.OAuthClientEmulateRequest) (*models.OAuthClientEmulateResponse, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthProviderServicerGRPC) PreviewTokens(ctx context.Context, req *models.TokenPreviewRequest) (*models.TokenPreviewResponse, error) {
+	return nil, nil
+}
+
 // ===================== mockEmailProfileServicerGRPC =====================
 
 type mockEmailProfileServicerGRPC struct {
@@ -495,17 +582,24 @@ func (m *mockEmailProfileServicerGRPC) TestProfile(ctx context.Context, profileI
 type mockAdminServicerGRPC struct {
 	CreateUserFunc func(ctx context.Context, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
 	GetUserFunc    func(ctx context.Context, userID uuid.UUID) (*models.AdminUserResponse, error)
-	ListUsersFunc  func(ctx context.Context, appID *uuid.UUID, page, pageSize int) (*models.AdminUserListResponse, error)
+	ListUsersFunc  func(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error)
 	SyncUsersFunc  func(ctx context.Context, updatedAfter time.Time, appID *uuid.UUID, limit, offset int) (*models.SyncUsersResponse, error)
 	ImportUsersFunc func(ctx context.Context, req *models.BulkImportUsersRequest, appID *uuid.UUID) (*models.ImportUsersResponse, error)
+	UpdateUserFunc  func(ctx context.Context, userID uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	UpdateUserStateFunc func(ctx context.Context, userID uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error)
+	AssignRoleFunc  func(ctx context.Context, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	RemoveRoleFunc  func(ctx context.Context, userID, roleID uuid.UUID) (*models.AdminUserResponse, error)
 }
 
-func (m *mockAdminServicerGRPC) ListUsers(ctx context.Context, appID *uuid.UUID, page, pageSize int) (*models.AdminUserListResponse, error) {
+func (m *mockAdminServicerGRPC) ListUsers(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
 	if m.ListUsersFunc != nil {
-		return m.ListUsersFunc(ctx, appID, page, pageSize)
+		return m.ListUsersFunc(ctx, appID, search, page, pageSize)
 	}
 	return nil, nil
 }
+func (m *mockAdminServicerGRPC) SearchUsers(ctx context.Context, page, pageSize int, opts ...service.UserFilterOption) (*models.AdminUserListResponse, error) {
+	return nil, nil
+}
 func (m *mockAdminServicerGRPC) GetUser(ctx context.Context, userID uuid.UUID) (*models.AdminUserResponse, error) {
 	if m.GetUserFunc != nil {
 		return m.GetUserFunc(ctx, userID)
@@ -519,9 +613,20 @@ func (m *mockAdminServicerGRPC) CreateUser(ctx context.Context, req *models.Admi
 	return nil, nil
 }
 func (m *mockAdminServicerGRPC) UpdateUser(ctx context.Context, userID uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.UpdateUserFunc != nil {
+		return m.UpdateUserFunc(ctx, userID, req, adminID)
+	}
+	return nil, nil
+}
+func (m *mockAdminServicerGRPC) DeleteUser(ctx context.Context, userID, adminID uuid.UUID) error {
+	return nil
+}
+func (m *mockAdminServicerGRPC) UpdateUserState(ctx context.Context, userID uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error) {
+	if m.UpdateUserStateFunc != nil {
+		return m.UpdateUserStateFunc(ctx, userID, req, adminID, ip, userAgent)
+	}
 	return nil, nil
 }
-func (m *mockAdminServicerGRPC) DeleteUser(ctx context.Context, userID uuid.UUID) error { return nil }
 func (m *mockAdminServicerGRPC) AdminReset2FA(ctx context.Context, userID, adminID uuid.UUID) error {
 	return nil
 }
@@ -529,9 +634,15 @@ func (m *mockAdminServicerGRPC) GetUserOAuthAccounts(ctx context.Context, userID
 	return nil, nil
 }
 func (m *mockAdminServicerGRPC) AssignRole(ctx context.Context, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.AssignRoleFunc != nil {
+		return m.AssignRoleFunc(ctx, userID, roleID, adminID)
+	}
 	return nil, nil
 }
 func (m *mockAdminServicerGRPC) RemoveRole(ctx context.Context, userID, roleID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.RemoveRoleFunc != nil {
+		return m.RemoveRoleFunc(ctx, userID, roleID)
+	}
 	return nil, nil
 }
 func (m *mockAdminServicerGRPC) ListAPIKeys(ctx context.Context, appID *uuid.UUID, page, pageSize int) (*models.AdminAPIKeyListResponse, error) {
@@ -540,7 +651,13 @@ func (m *mockAdminServicerGRPC) ListAPIKeys(ctx context.Context, appID *uuid.UUI
 func (m *mockAdminServicerGRPC) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error {
 	return nil
 }
-func (m *mockAdminServicerGRPC) ListAuditLogs(ctx context.Context, page, pageSize int, userID *uuid.UUID) (*models.AuditLogListResponse, error) {
+func (m *mockAdminServicerGRPC) ListAuditLogs(ctx context.Context, page, pageSize int, opts ...service.AuditLogListOption) (*models.AuditLogListResponse, error) {
+	return nil, nil
+}
+func (m *mockAdminServicerGRPC) GetAuditActionStats(ctx context.Context, limit int, opts ...service.AuditLogListOption) (*models.AuditActionStatsResponse, error) {
+	return nil, nil
+}
+func (m *mockAdminServicerGRPC) GetAuditFailureStats(ctx context.Context, opts ...service.AuditLogListOption) (*models.AuditFailureStatsResponse, error) {
 	return nil, nil
 }
 func (m *mockAdminServicerGRPC) GetStats(ctx context.Context) (*models.AdminStatsResponse, error) {
@@ -667,6 +784,7 @@ func (m *mockApplicationServicerGRPC) GetAuthConfig(ctx context.Context, app *mo
 
 type mockRedisServicerGRPC struct {
 	IsBlacklistedFunc func(ctx context.Context, tokenHash string) (bool, error)
+	HealthFunc        func(ctx context.Context) error
 }
 
 func (m *mockRedisServicerGRPC) Close() error { return nil }
@@ -689,7 +807,12 @@ func (m *mockRedisServicerGRPC) SetNX(ctx context.Context, key string, value int
 func (m *mockRedisServicerGRPC) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	return nil
 }
-func (m *mockRedisServicerGRPC) Health(ctx context.Context) error { return nil }
+func (m *mockRedisServicerGRPC) Health(ctx context.Context) error {
+	if m.HealthFunc != nil {
+		return m.HealthFunc(ctx)
+	}
+	return nil
+}
 func (m *mockRedisServicerGRPC) AddToBlacklist(ctx context.Context, tokenHash string, expiration time.Duration) error {
 	return nil
 }
@@ -741,6 +864,100 @@ func (m *mockTokenExchangeServicerGRPC) RedeemExchange(ctx context.Context, req
 	return nil, nil
 }
 
+// ===================== mockPermissionCatalogServicerGRPC =====================
+
+type mockPermissionCatalogServicerGRPC struct {
+	RegisterEntriesFunc func(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error)
+}
+
+func (m *mockPermissionCatalogServicerGRPC) GetCatalog(ctx context.Context) (*models.PermissionCatalogResponse, error) {
+	return &models.PermissionCatalogResponse{}, nil
+}
+func (m *mockPermissionCatalogServicerGRPC) RegisterEntries(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error) {
+	if m.RegisterEntriesFunc != nil {
+		return m.RegisterEntriesFunc(ctx, source, entries)
+	}
+	return len(entries), nil
+}
+
+// ===================== mockSessionServicerGRPC =====================
+
+type mockSessionServicerGRPC struct {
+	GetUserSessionsFunc       func(ctx context.Context, userID uuid.UUID, page, perPage int) (*models.SessionListResponse, error)
+	RevokeSessionFunc         func(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeAllUserSessionsFunc func(ctx context.Context, userID uuid.UUID, exceptSessionID *uuid.UUID) error
+}
+
+func (m *mockSessionServicerGRPC) CreateSessionWithParams(ctx context.Context, params service.SessionCreationParams) (*models.Session, error) {
+	return nil, nil
+}
+func (m *mockSessionServicerGRPC) CreateSessionNonFatal(ctx context.Context, params service.SessionCreationParams) *models.Session {
+	return nil
+}
+func (m *mockSessionServicerGRPC) CreateSessionFromRequest(ctx context.Context, userID uuid.UUID, accessToken string, refreshToken string, ipAddress string, userAgent string, tokenExpiration time.Duration) (*models.Session, error) {
+	return nil, nil
+}
+func (m *mockSessionServicerGRPC) CreateSessionFromRequestNonFatal(ctx context.Context, userID uuid.UUID, accessToken string, refreshToken string, ipAddress string, userAgent string, tokenExpiration time.Duration) *models.Session {
+	return nil
+}
+func (m *mockSessionServicerGRPC) RefreshSession(ctx context.Context, params service.SessionRefreshParams) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) RefreshSessionNonFatal(ctx context.Context, params service.SessionRefreshParams) bool {
+	return true
+}
+func (m *mockSessionServicerGRPC) RefreshSessionFromTokens(ctx context.Context, oldRefreshToken string, newRefreshToken string, newAccessToken string, newExpiresAt time.Time) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) RefreshSessionFromTokensNonFatal(ctx context.Context, oldRefreshToken string, newRefreshToken string, newAccessToken string, newExpiresAt time.Time) bool {
+	return true
+}
+func (m *mockSessionServicerGRPC) GetUserSessions(ctx context.Context, userID uuid.UUID, page, perPage int) (*models.SessionListResponse, error) {
+	if m.GetUserSessionsFunc != nil {
+		return m.GetUserSessionsFunc(ctx, userID, page, perPage)
+	}
+	return &models.SessionListResponse{}, nil
+}
+func (m *mockSessionServicerGRPC) GetAllSessions(ctx context.Context, page, perPage int) (*models.SessionListResponse, error) {
+	return &models.SessionListResponse{}, nil
+}
+func (m *mockSessionServicerGRPC) GetSessionStats(ctx context.Context) (*models.SessionStats, error) {
+	return &models.SessionStats{}, nil
+}
+func (m *mockSessionServicerGRPC) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, userID, sessionID)
+	}
+	return nil
+}
+func (m *mockSessionServicerGRPC) AdminRevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID, exceptSessionID *uuid.UUID) error {
+	if m.RevokeAllUserSessionsFunc != nil {
+		return m.RevokeAllUserSessionsFunc(ctx, userID, exceptSessionID)
+	}
+	return nil
+}
+func (m *mockSessionServicerGRPC) RevokeSessionByTokenHash(ctx context.Context, tokenHash string) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) RevokeSessionByToken(ctx context.Context, token string) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) UpdateSessionName(ctx context.Context, sessionID uuid.UUID, name string) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) CleanupExpiredSessions(ctx context.Context) error {
+	return nil
+}
+func (m *mockSessionServicerGRPC) GetUserSessionsByApp(ctx context.Context, userID, appID uuid.UUID) ([]models.Session, error) {
+	return nil, nil
+}
+func (m *mockSessionServicerGRPC) GetAppSessionsPaginated(ctx context.Context, appID uuid.UUID, page, perPage int) ([]models.Session, int, error) {
+	return nil, 0, nil
+}
+
 // ===================== Test Helper =====================
 
 func newTestAuthHandlerV2(
@@ -13,6 +13,8 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"github.com/smilemakc/auth-gateway/internal/abac"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -237,6 +239,12 @@ type AuthHandlerV2 struct {
 	appService           service.ApplicationServicer
 	redis                service.RedisServicer
 	tokenExchangeService service.TokenExchangeServicer
+	tokenVersions        service.TokenVersionServicer
+	permissionCatalog    service.PermissionCatalogServicer
+	blacklistFilter      *service.BlacklistFilter
+	revocations          *service.RevocationHub
+	userCache            *service.UserCache
+	sessionService       service.SessionServicer
 	logger               *logger.Logger
 }
 
@@ -275,8 +283,74 @@ func NewAuthHandlerV2(
 	}
 }
 
+// SetTokenVersionService enables token version and global epoch checks
+func (h *AuthHandlerV2) SetTokenVersionService(tokenVersions service.TokenVersionServicer) {
+	h.tokenVersions = tokenVersions
+}
+
+// SetPermissionCatalogService enables services to register their resources in the permissions catalog
+func (h *AuthHandlerV2) SetPermissionCatalogService(permissionCatalog service.PermissionCatalogServicer) {
+	h.permissionCatalog = permissionCatalog
+}
+
+// SetBlacklistFilter skips blacklist lookups for tokens the filter knows are not blacklisted
+func (h *AuthHandlerV2) SetBlacklistFilter(filter *service.BlacklistFilter) {
+	h.blacklistFilter = filter
+}
+
+// SetRevocationHub enables the WatchRevocations stream
+func (h *AuthHandlerV2) SetRevocationHub(hub *service.RevocationHub) {
+	h.revocations = hub
+}
+
+// SetUserCache serves the users of validated API keys from cache
+func (h *AuthHandlerV2) SetUserCache(cache *service.UserCache) {
+	h.userCache = cache
+}
+
+// SetSessionService enables the session management methods
+func (h *AuthHandlerV2) SetSessionService(sessionService service.SessionServicer) {
+	h.sessionService = sessionService
+}
+
+// userWithRoles loads the user with roles for token validation
+func (h *AuthHandlerV2) userWithRoles(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	if h.userCache != nil {
+		return h.userCache.Get(ctx, userID)
+	}
+	return h.userRepo.GetByID(ctx, userID, nil, service.UserGetWithRoles())
+}
+
+// isBlacklisted checks the blacklist in Redis, falling back to the database when Redis fails
+func (h *AuthHandlerV2) isBlacklisted(ctx context.Context, tokenHash string) bool {
+	if !h.blacklistFilter.MayContain(tokenHash) {
+		return false
+	}
+
+	blacklisted, err := h.redis.IsBlacklisted(ctx, tokenHash)
+	if err != nil {
+		if h.logger.Enabled(logger.WarnLevel) {
+			h.logger.Warn("Redis blacklist check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		blacklisted, _ = h.tokenRepo.IsBlacklisted(ctx, tokenHash)
+	}
+	return blacklisted
+}
+
 // ValidateToken validates a JWT access token or API key and returns user information
 func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
+	resp, err := h.validateToken(ctx, req)
+	tokenType := "access"
+	if strings.HasPrefix(req.AccessToken, "agw_") {
+		tokenType = "api_key"
+	}
+	metrics.RecordTokenValidation(metrics.TransportGRPC, tokenType, err == nil && resp.Valid)
+	return resp, err
+}
+
+func (h *AuthHandlerV2) validateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
 	if req.AccessToken == "" {
 		return &pb.ValidateTokenResponse{
 			Valid:        false,
@@ -289,9 +363,11 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 		// Validate API key
 		_, user, err := h.apiKeyService.ValidateAPIKey(ctx, req.AccessToken)
 		if err != nil {
-			h.logger.Debug("API key validation failed", map[string]interface{}{
-				"error": err.Error(),
-			})
+			if h.logger.Enabled(logger.DebugLevel) {
+				h.logger.Debug("API key validation failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
 			return &pb.ValidateTokenResponse{
 				Valid:        false,
 				ErrorMessage: err.Error(),
@@ -299,7 +375,7 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 		}
 
 		// Load user with roles
-		userWithRoles, err := h.userRepo.GetByID(ctx, user.ID, nil, service.UserGetWithRoles())
+		userWithRoles, err := h.userWithRoles(ctx, user.ID)
 		if err != nil {
 			h.logger.Error("Failed to load user roles", map[string]interface{}{
 				"user_id": user.ID.String(),
@@ -319,6 +395,7 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 			Roles:     extractRoleNames(userWithRoles.Roles),
 			ExpiresAt: 0,
 			IsActive:  user.IsActive,
+			IsGuest:   userWithRoles.IsGuest,
 		}
 
 		if resolvedAppID := ResolveApplicationID(ctx, req.ApplicationId); resolvedAppID != "" {
@@ -335,12 +412,17 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 		return response, nil
 	}
 
-	// Validate JWT token
-	claims, err := h.jwtService.ValidateAccessToken(req.AccessToken)
-	if err != nil {
-		h.logger.Debug("Token validation failed", map[string]interface{}{
-			"error": err.Error(),
-		})
+	// Validate JWT token. Claims are pooled: this path runs for every request of every
+	// service behind the gateway, and nothing below keeps the *Claims past the return.
+	claims := jwt.AcquireClaims()
+	defer jwt.ReleaseClaims(claims)
+
+	if err := h.jwtService.ParseAccessToken(req.AccessToken, claims); err != nil {
+		if h.logger.Enabled(logger.DebugLevel) {
+			h.logger.Debug("Token validation failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
 
 		return &pb.ValidateTokenResponse{
 			Valid:        false,
@@ -349,19 +431,17 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 	}
 
 	// Check if token is blacklisted
-	tokenHash := utils.HashToken(req.AccessToken)
-	blacklisted, err := h.redis.IsBlacklisted(ctx, tokenHash)
-	if err != nil {
-		h.logger.Warn("Redis blacklist check failed", map[string]interface{}{
-			"error": err.Error(),
-		})
-		blacklisted, _ = h.tokenRepo.IsBlacklisted(ctx, tokenHash)
+	if h.isBlacklisted(ctx, utils.HashToken(req.AccessToken)) {
+		return &pb.ValidateTokenResponse{
+			Valid:        false,
+			ErrorMessage: "token is blacklisted",
+		}, nil
 	}
 
-	if blacklisted {
+	if h.tokenVersions != nil && h.tokenVersions.IsRevoked(ctx, claims) {
 		return &pb.ValidateTokenResponse{
 			Valid:        false,
-			ErrorMessage: "token is blacklisted",
+			ErrorMessage: "token is revoked",
 		}, nil
 	}
 
@@ -373,6 +453,7 @@ func (h *AuthHandlerV2) ValidateToken(ctx context.Context, req *pb.ValidateToken
 		Roles:     claims.Roles,
 		ExpiresAt: claims.ExpiresAt.Unix(),
 		IsActive:  claims.IsActive,
+		IsGuest:   claims.IsGuest,
 	}
 
 	if claims.ApplicationID != nil {
@@ -436,18 +517,7 @@ func (h *AuthHandlerV2) GetUser(ctx context.Context, req *pb.GetUserRequest) (*p
 	}
 
 	return &pb.GetUserResponse{
-		User: &pb.User{
-			Id:                user.ID.String(),
-			Email:             user.Email,
-			Username:          user.Username,
-			FullName:          user.FullName,
-			ProfilePictureUrl: user.ProfilePictureURL,
-			Roles:             extractRoleNames(user.Roles),
-			EmailVerified:     user.EmailVerified,
-			IsActive:          user.IsActive,
-			CreatedAt:         user.CreatedAt.Unix(),
-			UpdatedAt:         user.UpdatedAt.Unix(),
-		},
+		User: userToProto(user),
 	}, nil
 }
 
@@ -488,14 +558,49 @@ func (h *AuthHandlerV2) CheckPermission(ctx context.Context, req *pb.CheckPermis
 		}, nil
 	}
 
+	// Unconditional grants win; conditional ones are evaluated only when none applies
+	var conditional []conditionalGrant
 	for _, role := range roles {
 		for _, permission := range role.Permissions {
-			if permission.Resource == req.Resource && permission.Action == req.Action {
+			if permission.Resource != req.Resource || permission.Action != req.Action {
+				continue
+			}
+			if permission.Condition == "" {
 				return &pb.CheckPermissionResponse{
 					Allowed: true,
 					Role:    role.Name,
 				}, nil
 			}
+			conditional = append(conditional, conditionalGrant{role: role.Name, permission: permission})
+		}
+	}
+
+	if len(conditional) > 0 {
+		user, err := h.userRepo.GetByID(ctx, userID, nil)
+		if err != nil {
+			h.logger.Error("Failed to get user for permission conditions", map[string]interface{}{
+				"user_id": req.UserId,
+				"error":   err.Error(),
+			})
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		attrs := permissionAttributes(user, roles, req, time.Now())
+		for _, grant := range conditional {
+			condition, err := abac.Parse(grant.permission.Condition)
+			if err != nil {
+				h.logger.Warn("Skipping permission with invalid condition", map[string]interface{}{
+					"permission": grant.permission.Name,
+					"error":      err.Error(),
+				})
+				continue
+			}
+			if condition.Evaluate(attrs) {
+				return &pb.CheckPermissionResponse{
+					Allowed: true,
+					Role:    grant.role,
+				}, nil
+			}
 		}
 	}
 
@@ -504,6 +609,26 @@ func (h *AuthHandlerV2) CheckPermission(ctx context.Context, req *pb.CheckPermis
 	}, nil
 }
 
+// conditionalGrant is a conditional permission matching a CheckPermission request
+type conditionalGrant struct {
+	role       string
+	permission models.Permission
+}
+
+// permissionAttributes collects the attributes permission conditions are evaluated against
+func permissionAttributes(user *models.User, roles []models.Role, req *pb.CheckPermissionRequest, now time.Time) abac.Attributes {
+	roleNames := make([]string, len(roles))
+	for i, role := range roles {
+		roleNames[i] = role.Name
+	}
+	return abac.Attributes{
+		"user":     abac.UserAttributes(user, roleNames),
+		"resource": abac.StringAttributes(req.ResourceAttributes),
+		"context":  abac.StringAttributes(req.ContextAttributes),
+		"time":     abac.TimeAttributes(now),
+	}
+}
+
 // IntrospectToken provides detailed information about a token
 func (h *AuthHandlerV2) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
 	if req.AccessToken == "" {
@@ -520,10 +645,9 @@ func (h *AuthHandlerV2) IntrospectToken(ctx context.Context, req *pb.IntrospectT
 	}
 
 	// Check if token is blacklisted
-	tokenHash := utils.HashToken(req.AccessToken)
-	blacklisted, err := h.redis.IsBlacklisted(ctx, tokenHash)
-	if err != nil {
-		blacklisted, _ = h.tokenRepo.IsBlacklisted(ctx, tokenHash)
+	blacklisted := h.isBlacklisted(ctx, utils.HashToken(req.AccessToken))
+	if !blacklisted && h.tokenVersions != nil {
+		blacklisted = h.tokenVersions.IsRevoked(ctx, claims)
 	}
 
 	// Get role (use first role if multiple)
@@ -547,6 +671,68 @@ func (h *AuthHandlerV2) IntrospectToken(ctx context.Context, req *pb.IntrospectT
 	}, nil
 }
 
+// WatchRevocations streams revocation events until the client disconnects. The stream ends
+// with codes.Unavailable when events may have been missed.
+func (h *AuthHandlerV2) WatchRevocations(req *pb.WatchRevocationsRequest, stream pb.AuthService_WatchRevocationsServer) error {
+	if h.revocations == nil {
+		return status.Error(codes.Unimplemented, "revocation events are not available")
+	}
+
+	var userID *uuid.UUID
+	if req.UserId != "" {
+		id, err := uuid.Parse(req.UserId)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid user_id format")
+		}
+		userID = &id
+	}
+
+	sub := h.revocations.Subscribe(userID)
+	defer sub.Close()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-sub.Events():
+			if err := stream.Send(revocationEventToProto(event)); err != nil {
+				return err
+			}
+		case <-sub.Done():
+			// Deliver what was received before the subscription ended
+			for {
+				select {
+				case event := <-sub.Events():
+					if err := stream.Send(revocationEventToProto(event)); err != nil {
+						return err
+					}
+				default:
+					return status.Error(codes.Unavailable, sub.Err().Error())
+				}
+			}
+		}
+	}
+}
+
+func revocationEventToProto(event models.RevocationEvent) *pb.RevocationEvent {
+	resp := &pb.RevocationEvent{
+		Type:      string(event.Type),
+		TokenHash: event.TokenHash,
+		RevokedAt: event.RevokedAt.Unix(),
+	}
+	if event.SessionID != nil {
+		resp.SessionId = event.SessionID.String()
+	}
+	if event.UserID != nil {
+		resp.UserId = event.UserID.String()
+	}
+	if event.ExpiresAt != nil {
+		resp.ExpiresAt = event.ExpiresAt.Unix()
+	}
+	return resp
+}
+
 // InitPasswordlessRegistration initiates passwordless two-step registration
 func (h *AuthHandlerV2) InitPasswordlessRegistration(ctx context.Context, req *pb.InitPasswordlessRegistrationRequest) (*pb.InitPasswordlessRegistrationResponse, error) {
 	// Validate that either email or phone is provided
@@ -662,18 +848,7 @@ func (h *AuthHandlerV2) CompletePasswordlessRegistration(ctx context.Context, re
 	// Convert user to proto
 	user := resp.User
 	return &pb.CompletePasswordlessRegistrationResponse{
-		User: &pb.User{
-			Id:                user.ID.String(),
-			Email:             user.Email,
-			Username:          user.Username,
-			FullName:          user.FullName,
-			ProfilePictureUrl: user.ProfilePictureURL,
-			Roles:             extractRoleNames(user.Roles),
-			EmailVerified:     user.EmailVerified,
-			IsActive:          user.IsActive,
-			CreatedAt:         user.CreatedAt.Unix(),
-			UpdatedAt:         user.UpdatedAt.Unix(),
-		},
+		User:         userToProto(user),
 		AccessToken:  resp.AccessToken,
 		RefreshToken: resp.RefreshToken,
 		ExpiresIn:    resp.ExpiresIn,
@@ -718,6 +893,14 @@ func (h *AuthHandlerV2) IntrospectOAuthToken(ctx context.Context, req *pb.Intros
 		}, nil
 	}
 
+	// A certificate-bound token is only active for the caller holding the certificate (RFC 8705)
+	if result.Active && result.Cnf != nil && clientCertThumbprint(ctx) != result.Cnf.X5TS256 {
+		return &pb.IntrospectOAuthTokenResponse{
+			Active:       false,
+			ErrorMessage: "token is bound to a different client certificate",
+		}, nil
+	}
+
 	return &pb.IntrospectOAuthTokenResponse{
 		Active:    result.Active,
 		Scope:     result.Scope,
@@ -734,6 +917,20 @@ func (h *AuthHandlerV2) IntrospectOAuthToken(ctx context.Context, req *pb.Intros
 	}, nil
 }
 
+// clientCertThumbprint returns the x5t#S256 thumbprint of the client certificate the token was
+// presented with, which resource servers and ext_authz filters send as
+// "x-client-cert-thumbprint" metadata
+func clientCertThumbprint(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get("x-client-cert-thumbprint"); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
 // ValidateOAuthClient validates OAuth client credentials
 func (h *AuthHandlerV2) ValidateOAuthClient(ctx context.Context, req *pb.ValidateOAuthClientRequest) (*pb.ValidateOAuthClientResponse, error) {
 	if req.ClientId == "" {
@@ -847,6 +1044,10 @@ func (h *AuthHandlerV2) CreateUser(ctx context.Context, req *pb.CreateUserReques
 		})
 
 		// Convert error to appropriate gRPC status
+		var policyErr *models.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return nil, status.Error(codes.InvalidArgument, policyErr.Error())
+		}
 		if appErr, ok := err.(*models.AppError); ok {
 			switch appErr.Code {
 			case 400:
@@ -874,18 +1075,7 @@ func (h *AuthHandlerV2) CreateUser(ctx context.Context, req *pb.CreateUserReques
 	// Convert response
 	user := authResp.User
 	return &pb.CreateUserResponse{
-		User: &pb.User{
-			Id:                user.ID.String(),
-			Email:             user.Email,
-			Username:          user.Username,
-			FullName:          user.FullName,
-			ProfilePictureUrl: user.ProfilePictureURL,
-			Roles:             extractRoleNames(user.Roles),
-			EmailVerified:     user.EmailVerified,
-			IsActive:          user.IsActive,
-			CreatedAt:         user.CreatedAt.Unix(),
-			UpdatedAt:         user.UpdatedAt.Unix(),
-		},
+		User:         userToProto(user),
 		AccessToken:  authResp.AccessToken,
 		RefreshToken: authResp.RefreshToken,
 		ExpiresIn:    authResp.ExpiresIn,
@@ -928,6 +1118,11 @@ func (h *AuthHandlerV2) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Lo
 			"email": req.Email,
 		})
 
+		var changeErr *models.PasswordChangeRequiredError
+		if errors.As(err, &changeErr) {
+			return nil, status.Error(codes.Unauthenticated, changeErr.Error())
+		}
+
 		// Convert error to appropriate gRPC status
 		if appErr, ok := err.(*models.AppError); ok {
 			switch appErr.Code {
@@ -935,6 +1130,8 @@ func (h *AuthHandlerV2) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Lo
 				return nil, status.Error(codes.InvalidArgument, appErr.Message)
 			case 401:
 				return nil, status.Error(codes.Unauthenticated, appErr.Message)
+			case 423:
+				return nil, status.Error(codes.PermissionDenied, appErr.Message)
 			default:
 				return nil, status.Error(codes.Internal, appErr.Message)
 			}
@@ -957,18 +1154,7 @@ func (h *AuthHandlerV2) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Lo
 	// Convert response
 	user := authResp.User
 	return &pb.LoginResponse{
-		User: &pb.User{
-			Id:                user.ID.String(),
-			Email:             user.Email,
-			Username:          user.Username,
-			FullName:          user.FullName,
-			ProfilePictureUrl: user.ProfilePictureURL,
-			Roles:             extractRoleNames(user.Roles),
-			EmailVerified:     user.EmailVerified,
-			IsActive:          user.IsActive,
-			CreatedAt:         user.CreatedAt.Unix(),
-			UpdatedAt:         user.UpdatedAt.Unix(),
-		},
+		User:         userToProto(user),
 		AccessToken:  authResp.AccessToken,
 		RefreshToken: authResp.RefreshToken,
 		ExpiresIn:    authResp.ExpiresIn,
@@ -1392,6 +1578,12 @@ func (h *AuthHandlerV2) VerifyRegistrationOTP(ctx context.Context, req *pb.Verif
 				ErrorMessage: appErr.Message,
 			}, nil
 		}
+		var policyErr *models.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return &pb.VerifyRegistrationOTPResponse{
+				ErrorMessage: policyErr.Error(),
+			}, nil
+		}
 		return &pb.VerifyRegistrationOTPResponse{
 			ErrorMessage: "failed to create user",
 		}, nil
@@ -1638,6 +1830,414 @@ func toUserAppProfileResponse(p *models.UserApplicationProfile) *pb.UserAppProfi
 	return resp
 }
 
+// ========== User Management Methods ==========
+
+// ListUsers lists users with pagination, optionally searching by email, username or name
+// or restricted to the users of an application
+func (h *AuthHandlerV2) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+
+	var appID *uuid.UUID
+	if req.ApplicationId != "" {
+		if strings.TrimSpace(req.Search) != "" {
+			return nil, status.Error(codes.InvalidArgument, "search and application_id cannot be combined")
+		}
+		id, err := uuid.Parse(req.ApplicationId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid application_id format")
+		}
+		appID = &id
+	}
+
+	result, err := h.adminService.ListUsers(ctx, appID, req.Search, int(req.Page), int(req.PageSize))
+	if err != nil {
+		h.logger.Error("Failed to list users via gRPC", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	users := make([]*pb.User, 0, len(result.Users))
+	for _, user := range result.Users {
+		users = append(users, adminUserToProto(user))
+	}
+	return &pb.ListUsersResponse{
+		Users:      users,
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		TotalPages: int32(result.TotalPages),
+	}, nil
+}
+
+// GetUserByEmail retrieves user information by email address
+func (h *AuthHandlerV2) GetUserByEmail(ctx context.Context, req *pb.GetUserByEmailRequest) (*pb.GetUserResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.GetByEmail(ctx, utils.NormalizeEmail(req.Email), nil, service.UserGetWithRoles())
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		h.logger.Error("Failed to get user by email", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetUserResponse{User: userToProto(user)}, nil
+}
+
+// UpdateUser updates a user's account; empty fields are left unchanged
+func (h *AuthHandlerV2) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	actorID, err := h.userManagementActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &models.AdminUpdateUserRequest{EmailVerified: req.EmailVerified}
+	if req.Email != "" {
+		email := utils.NormalizeEmail(req.Email)
+		if !utils.IsValidEmail(email) {
+			return nil, status.Error(codes.InvalidArgument, "invalid email format")
+		}
+		update.Email = &email
+	}
+	if req.Username != "" {
+		update.Username = &req.Username
+	}
+	if req.FullName != "" {
+		update.FullName = &req.FullName
+	}
+	if req.Phone != "" {
+		update.Phone = &req.Phone
+	}
+
+	user, err := h.adminService.UpdateUser(ctx, userID, update, actorID)
+	if err != nil {
+		return nil, h.userManagementError(err, "update user", userID)
+	}
+	return &pb.UserResponse{User: adminUserToProto(user)}, nil
+}
+
+// DeactivateUser deactivates a user's account and revokes their tokens
+func (h *AuthHandlerV2) DeactivateUser(ctx context.Context, req *pb.DeactivateUserRequest) (*pb.UserResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	actorID, err := h.userManagementActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clientInfo := extractClientInfo(ctx)
+	user, err := h.adminService.UpdateUserState(ctx, userID, &models.UpdateUserStateRequest{
+		State:  models.UserStateDeactivated,
+		Reason: req.Reason,
+	}, actorID, clientInfo.IP, clientInfo.UserAgent)
+	if err != nil {
+		return nil, h.userManagementError(err, "deactivate user", userID)
+	}
+	return &pb.UserResponse{User: adminUserToProto(user)}, nil
+}
+
+// AssignRole assigns a role to a user
+func (h *AuthHandlerV2) AssignRole(ctx context.Context, req *pb.AssignRoleRequest) (*pb.UserResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	actorID, err := h.userManagementActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleID, err := h.resolveRoleID(ctx, req.RoleId, req.RoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.adminService.AssignRole(ctx, userID, roleID, actorID)
+	if err != nil {
+		return nil, h.userManagementError(err, "assign role", userID)
+	}
+	return &pb.UserResponse{User: adminUserToProto(user)}, nil
+}
+
+// RemoveRole removes a role from a user; a user keeps at least one role
+func (h *AuthHandlerV2) RemoveRole(ctx context.Context, req *pb.RemoveRoleRequest) (*pb.UserResponse, error) {
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+	roleID, err := h.resolveRoleID(ctx, req.RoleId, req.RoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.adminService.RemoveRole(ctx, userID, roleID)
+	if err != nil {
+		return nil, h.userManagementError(err, "remove role", userID)
+	}
+	return &pb.UserResponse{User: adminUserToProto(user)}, nil
+}
+
+// ========== Session Management Methods ==========
+
+// ListUserSessions lists the active sessions of a user with pagination
+func (h *AuthHandlerV2) ListUserSessions(ctx context.Context, req *pb.ListUserSessionsRequest) (*pb.ListUserSessionsResponse, error) {
+	if h.sessionService == nil {
+		return nil, status.Error(codes.Unimplemented, "session management is not available")
+	}
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+	page := int(req.Page)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := int(req.PageSize)
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	result, err := h.sessionService.GetUserSessions(ctx, userID, page, pageSize)
+	if err != nil {
+		h.logger.Error("Failed to list user sessions via gRPC", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return nil, status.Error(codes.Internal, "failed to list user sessions")
+	}
+
+	sessions := make([]*pb.Session, 0, len(result.Sessions))
+	for _, session := range result.Sessions {
+		sessions = append(sessions, &pb.Session{
+			Id:           session.ID.String(),
+			UserId:       session.UserID.String(),
+			DeviceType:   session.DeviceType,
+			Os:           session.OS,
+			Browser:      session.Browser,
+			UserAgent:    session.UserAgent,
+			IpAddress:    session.IPAddress,
+			SessionName:  session.SessionName,
+			LastActiveAt: session.LastActiveAt.Unix(),
+			CreatedAt:    session.CreatedAt.Unix(),
+			ExpiresAt:    session.ExpiresAt.Unix(),
+		})
+	}
+	return &pb.ListUserSessionsResponse{
+		Sessions:   sessions,
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		TotalPages: int32(result.TotalPages),
+	}, nil
+}
+
+// RevokeSession revokes one session of a user and blacklists its tokens
+func (h *AuthHandlerV2) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.GenericResponse, error) {
+	if h.sessionService == nil {
+		return nil, status.Error(codes.Unimplemented, "session management is not available")
+	}
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	sessionID, err := uuid.Parse(req.SessionId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid session_id format")
+	}
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+
+	// The session store reports a missing session, one of another user and a failed lookup
+	// alike, so all of them are reported as not found
+	if err := h.sessionService.RevokeSession(ctx, userID, sessionID); err != nil {
+		h.logger.Warn("Failed to revoke session via gRPC", map[string]interface{}{
+			"user_id":    userID.String(),
+			"session_id": sessionID.String(),
+			"error":      err.Error(),
+		})
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	return &pb.GenericResponse{Success: true, Message: "Session revoked"}, nil
+}
+
+// RevokeAllUserSessions revokes every session of a user and blacklists their tokens
+func (h *AuthHandlerV2) RevokeAllUserSessions(ctx context.Context, req *pb.RevokeAllUserSessionsRequest) (*pb.GenericResponse, error) {
+	if h.sessionService == nil {
+		return nil, status.Error(codes.Unimplemented, "session management is not available")
+	}
+	userID, err := parseUserID(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.userManagementActor(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := h.sessionService.RevokeAllUserSessions(ctx, userID, nil); err != nil {
+		h.logger.Error("Failed to revoke user sessions via gRPC", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return nil, status.Error(codes.Internal, "failed to revoke user sessions")
+	}
+	return &pb.GenericResponse{Success: true, Message: "All sessions revoked"}, nil
+}
+
+// parseUserID parses the required user_id of a request
+func parseUserID(value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	return userID, nil
+}
+
+// userManagementActor returns who manages users: the owner of the calling API key, or
+// uuid.Nil for a service authenticated by its application secret. Any user can create a key
+// with any scope, so an API key may only list, look up or change users when its owner is an admin.
+func (h *AuthHandlerV2) userManagementActor(ctx context.Context) (uuid.UUID, error) {
+	value, ok := ctx.Value(GRPCUserIDKey).(string)
+	if !ok || value == "" {
+		return uuid.Nil, nil
+	}
+	ownerID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "API key owner is not an admin")
+	}
+
+	roles, err := h.rbacRepo.GetUserRoles(ctx, ownerID)
+	if err != nil {
+		h.logger.Error("Failed to get API key owner roles", map[string]interface{}{
+			"user_id": ownerID.String(),
+			"error":   err.Error(),
+		})
+		return uuid.Nil, status.Error(codes.Internal, "internal error")
+	}
+	for _, role := range roles {
+		if role.Name == string(models.RoleAdmin) {
+			return ownerID, nil
+		}
+	}
+	return uuid.Nil, status.Error(codes.PermissionDenied, "API key owner is not an admin")
+}
+
+// resolveRoleID returns role_id, or the ID of the global role named role_name when role_id is
+// empty
+func (h *AuthHandlerV2) resolveRoleID(ctx context.Context, roleID, roleName string) (uuid.UUID, error) {
+	if roleID != "" {
+		id, err := uuid.Parse(roleID)
+		if err != nil {
+			return uuid.Nil, status.Error(codes.InvalidArgument, "invalid role_id format")
+		}
+		return id, nil
+	}
+	if roleName == "" {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "role_id or role_name is required")
+	}
+
+	role, err := h.rbacRepo.GetRoleByNameAndApp(ctx, roleName, nil)
+	if err != nil || role == nil {
+		return uuid.Nil, status.Error(codes.NotFound, "role not found")
+	}
+	return role.ID, nil
+}
+
+// userManagementError converts an admin service error to a gRPC status
+func (h *AuthHandlerV2) userManagementError(err error, action string, userID uuid.UUID) error {
+	var appErr *models.AppError
+	if errors.As(err, &appErr) {
+		switch appErr.Code {
+		case 400:
+			return status.Error(codes.InvalidArgument, appErr.Message)
+		case 404:
+			return status.Error(codes.NotFound, appErr.Message)
+		case 409:
+			return status.Error(codes.FailedPrecondition, appErr.Message)
+		}
+	}
+
+	h.logger.Error("Failed to "+action+" via gRPC", map[string]interface{}{
+		"user_id": userID.String(),
+		"error":   err.Error(),
+	})
+	return status.Error(codes.Internal, "failed to "+action)
+}
+
+// userToProto converts a user to its gRPC representation
+func userToProto(user *models.User) *pb.User {
+	pbUser := &pb.User{
+		Id:                user.ID.String(),
+		Email:             user.Email,
+		Username:          user.Username,
+		FullName:          user.FullName,
+		ProfilePictureUrl: user.ProfilePictureURL,
+		Roles:             extractRoleNames(user.Roles),
+		EmailVerified:     user.EmailVerified,
+		IsActive:          user.IsActive,
+		CreatedAt:         user.CreatedAt.Unix(),
+		UpdatedAt:         user.UpdatedAt.Unix(),
+		State:             string(user.LifecycleState()),
+	}
+	if user.Phone != nil {
+		pbUser.Phone = *user.Phone
+	}
+	return pbUser
+}
+
+// adminUserToProto converts an admin view of a user to its gRPC representation
+func adminUserToProto(user *models.AdminUserResponse) *pb.User {
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+	pbUser := &pb.User{
+		Id:                user.ID.String(),
+		Email:             user.Email,
+		Username:          user.Username,
+		FullName:          user.FullName,
+		ProfilePictureUrl: user.ProfilePictureURL,
+		Roles:             roles,
+		EmailVerified:     user.EmailVerified,
+		IsActive:          user.IsActive,
+		CreatedAt:         user.CreatedAt.Unix(),
+		UpdatedAt:         user.UpdatedAt.Unix(),
+		State:             string(user.State),
+	}
+	if user.Phone != nil {
+		pbUser.Phone = *user.Phone
+	}
+	return pbUser
+}
+
 // ========== Sync & Config Methods ==========
 
 // SyncUsers returns users updated after a given timestamp for shadow table sync
@@ -1924,3 +2524,43 @@ func (h *AuthHandlerV2) RedeemTokenExchange(ctx context.Context, req *pb.RedeemT
 		ApplicationId: resp.ApplicationID,
 	}, nil
 }
+
+// RegisterPermissionCatalog stores the resources and actions a service checks, replacing the
+// ones it registered before
+func (h *AuthHandlerV2) RegisterPermissionCatalog(ctx context.Context, req *pb.RegisterPermissionCatalogRequest) (*pb.RegisterPermissionCatalogResponse, error) {
+	if h.permissionCatalog == nil {
+		return &pb.RegisterPermissionCatalogResponse{
+			ErrorMessage: "permission catalog service not configured",
+		}, nil
+	}
+
+	entries := make([]models.PermissionCatalogEntry, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		entries = append(entries, models.PermissionCatalogEntry{
+			Resource:    entry.Resource,
+			Action:      entry.Action,
+			Description: entry.Description,
+		})
+	}
+
+	registered, err := h.permissionCatalog.RegisterEntries(ctx, req.Service, entries)
+	if err != nil {
+		if appErr, ok := err.(*models.AppError); ok {
+			return &pb.RegisterPermissionCatalogResponse{
+				ErrorMessage: appErr.Message,
+			}, nil
+		}
+		h.logger.Error("Failed to register permission catalog via gRPC", map[string]interface{}{
+			"error":   err.Error(),
+			"service": req.Service,
+		})
+		return &pb.RegisterPermissionCatalogResponse{
+			ErrorMessage: "failed to register permission catalog",
+		}, nil
+	}
+
+	return &pb.RegisterPermissionCatalogResponse{
+		Success:    true,
+		Registered: int32(registered),
+	}, nil
+}
@@ -9,9 +9,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/testutil/fixtures"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	pb "github.com/smilemakc/auth-gateway/proto"
 )
@@ -28,21 +33,12 @@ func newTestJWTService() *jwt.Service {
 
 // newTestUser creates a test user with default fields and provided roles
 func newTestUser(id uuid.UUID, roles ...string) *models.User {
-	modelRoles := make([]models.Role, len(roles))
-	for i, r := range roles {
-		modelRoles[i] = models.Role{Name: r}
-	}
-	return &models.User{
-		ID:            id,
-		Email:         "test@example.com",
-		Username:      "testuser",
-		FullName:      "Test User",
-		IsActive:      true,
-		EmailVerified: true,
-		Roles:         modelRoles,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
+	return fixtures.NewUserBuilder().
+		WithID(id).
+		WithEmail("test@example.com").
+		WithUsername("testuser").
+		WithRoles(roles...).
+		Build()
 }
 
 // ===================== ValidateToken Tests =====================
@@ -348,6 +344,125 @@ func TestCheckPermission_ShouldReturnNotAllowed_WhenNoRoles(t *testing.T) {
 	assert.Equal(t, "user has no roles", resp.ErrorMessage)
 }
 
+// newConditionalPermissionHandler serves a user holding the editor role, which may only
+// edit documents the user owns
+func newConditionalPermissionHandler(userID uuid.UUID, extra ...models.Permission) *AuthHandlerV2 {
+	rbacMock := &mockRBACStoreGRPC{
+		GetUserRolesFunc: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{
+				{
+					Name: "editor",
+					Permissions: append([]models.Permission{
+						{Name: "documents:edit_own", Resource: "documents", Action: "edit", Condition: "resource.owner_id == user.id"},
+					}, extra...),
+				},
+			}, nil
+		},
+	}
+	userMock := &mockUserStoreGRPC{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...service.UserGetOption) (*models.User, error) {
+			return newTestUser(id), nil
+		},
+	}
+
+	return newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = rbacMock
+		h.userRepo = userMock
+	})
+}
+
+func TestCheckPermission_ShouldEvaluateCondition_WhenPermissionIsConditional(t *testing.T) {
+	userID := uuid.New()
+	h := newConditionalPermissionHandler(userID)
+
+	resp, err := h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:             userID.String(),
+		Resource:           "documents",
+		Action:             "edit",
+		ResourceAttributes: map[string]string{"owner_id": userID.String()},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, "editor", resp.Role)
+
+	resp, err = h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:             userID.String(),
+		Resource:           "documents",
+		Action:             "edit",
+		ResourceAttributes: map[string]string{"owner_id": uuid.New().String()},
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Allowed)
+
+	resp, err = h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:   userID.String(),
+		Resource: "documents",
+		Action:   "edit",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Allowed, "a condition on missing attributes doesn't hold")
+}
+
+func TestCheckPermission_ShouldReadContextAndUserAttributes(t *testing.T) {
+	userID := uuid.New()
+	h := newConditionalPermissionHandler(userID, models.Permission{
+		Name: "documents:publish_de", Resource: "documents", Action: "publish",
+		Condition: `context.ip_country == "DE" && "editor" in user.roles && user.email matches "@example\\.com$"`,
+	})
+
+	resp, err := h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:            userID.String(),
+		Resource:          "documents",
+		Action:            "publish",
+		ContextAttributes: map[string]string{"ip_country": "DE"},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Allowed)
+
+	resp, err = h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:            userID.String(),
+		Resource:          "documents",
+		Action:            "publish",
+		ContextAttributes: map[string]string{"ip_country": "FR"},
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Allowed)
+}
+
+func TestCheckPermission_ShouldAllowWithoutLoadingUser_WhenUnconditionalPermissionMatches(t *testing.T) {
+	userID := uuid.New()
+	h := newConditionalPermissionHandler(userID, models.Permission{Name: "documents:edit", Resource: "documents", Action: "edit"})
+	h.userRepo = &mockUserStoreGRPC{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...service.UserGetOption) (*models.User, error) {
+			t.Fatal("user must not be loaded when an unconditional permission matches")
+			return nil, nil
+		},
+	}
+
+	resp, err := h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:   userID.String(),
+		Resource: "documents",
+		Action:   "edit",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Allowed)
+}
+
+func TestCheckPermission_ShouldDeny_WhenStoredConditionInvalid(t *testing.T) {
+	userID := uuid.New()
+	h := newConditionalPermissionHandler(userID, models.Permission{Name: "documents:delete_own", Resource: "documents", Action: "delete", Condition: "owner_id = 1"})
+
+	resp, err := h.CheckPermission(context.Background(), &pb.CheckPermissionRequest{
+		UserId:   userID.String(),
+		Resource: "documents",
+		Action:   "delete",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, resp.Allowed)
+}
+
 // ===================== Login Tests =====================
 
 func TestLogin_ShouldReturnTokens_WhenValidCredentials(t *testing.T) {
@@ -725,6 +840,66 @@ func TestCreateTokenExchange_ShouldReturnError_WhenServiceFails(t *testing.T) {
 	assert.Equal(t, "invalid token", resp.ErrorMessage)
 }
 
+// ===================== RegisterPermissionCatalog Tests =====================
+
+func TestRegisterPermissionCatalog_ShouldPassEntriesToService(t *testing.T) {
+	var gotSource string
+	var gotEntries []models.PermissionCatalogEntry
+	catalogMock := &mockPermissionCatalogServicerGRPC{
+		RegisterEntriesFunc: func(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error) {
+			gotSource, gotEntries = source, entries
+			return len(entries), nil
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.permissionCatalog = catalogMock
+	})
+
+	resp, err := h.RegisterPermissionCatalog(context.Background(), &pb.RegisterPermissionCatalogRequest{
+		Service: "billing-service",
+		Entries: []*pb.PermissionCatalogEntry{
+			{Resource: "invoices", Action: "refund", Description: "Refund a paid invoice"},
+			{Resource: "invoices", Action: "read"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, int32(2), resp.Registered)
+	assert.Equal(t, "billing-service", gotSource)
+	require.Len(t, gotEntries, 2)
+	assert.Equal(t, "refund", gotEntries[0].Action)
+	assert.Equal(t, "Refund a paid invoice", gotEntries[0].Description)
+}
+
+func TestRegisterPermissionCatalog_ShouldReturnError_WhenServiceRejects(t *testing.T) {
+	catalogMock := &mockPermissionCatalogServicerGRPC{
+		RegisterEntriesFunc: func(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error) {
+			return 0, &models.AppError{Code: 400, Message: "service name is required"}
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.permissionCatalog = catalogMock
+	})
+
+	resp, err := h.RegisterPermissionCatalog(context.Background(), &pb.RegisterPermissionCatalogRequest{})
+
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "service name is required", resp.ErrorMessage)
+}
+
+func TestRegisterPermissionCatalog_ShouldReturnError_WhenServiceNil(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	resp, err := h.RegisterPermissionCatalog(context.Background(), &pb.RegisterPermissionCatalogRequest{Service: "billing-service"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "permission catalog service not configured", resp.ErrorMessage)
+}
+
 func TestRedeemTokenExchange_ShouldReturnTokens_WhenValid(t *testing.T) {
 	userID := uuid.New()
 
@@ -986,6 +1161,539 @@ func TestListApplicationUsers_ShouldReturnError_WhenInvalidAppID(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid application_id format")
 }
 
+// ===================== User Management Tests =====================
+
+func newTestAdminUser(id uuid.UUID, roles ...string) *models.AdminUserResponse {
+	user := &models.AdminUserResponse{
+		ID:       id,
+		Email:    "test@example.com",
+		Username: "testuser",
+		IsActive: true,
+		State:    models.UserStateActive,
+	}
+	for _, role := range roles {
+		user.Roles = append(user.Roles, models.RoleInfo{ID: uuid.New(), Name: role})
+	}
+	return user
+}
+
+func TestListUsers_ShouldReturnUsers_WhenValid(t *testing.T) {
+	userID := uuid.New()
+	phone := "+15551234567"
+
+	adminMock := &mockAdminServicerGRPC{
+		ListUsersFunc: func(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
+			assert.Nil(t, appID)
+			assert.Equal(t, "test", search)
+			user := newTestAdminUser(userID, "user")
+			user.Phone = &phone
+			return &models.AdminUserListResponse{
+				Users:      []*models.AdminUserResponse{user},
+				Total:      1,
+				Page:       page,
+				PageSize:   pageSize,
+				TotalPages: 1,
+			}, nil
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.adminService = adminMock
+	})
+
+	resp, err := h.ListUsers(context.Background(), &pb.ListUsersRequest{
+		Search:   "test",
+		Page:     1,
+		PageSize: 20,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, int32(1), resp.Total)
+	assert.Equal(t, userID.String(), resp.Users[0].Id)
+	assert.Equal(t, phone, resp.Users[0].Phone)
+	assert.Equal(t, "active", resp.Users[0].State)
+	assert.Equal(t, []string{"user"}, resp.Users[0].Roles)
+}
+
+func TestListUsers_ShouldReturnError_WhenSearchCombinedWithApplication(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	_, err := h.ListUsers(context.Background(), &pb.ListUsersRequest{
+		Search:        "test",
+		ApplicationId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetUserByEmail_ShouldReturnUser_WhenExists(t *testing.T) {
+	userID := uuid.New()
+
+	userRepoMock := &mockUserStoreGRPC{
+		GetByEmailFunc: func(ctx context.Context, email string, isActive *bool, opts ...service.UserGetOption) (*models.User, error) {
+			assert.Equal(t, "test@example.com", email)
+			return newTestUser(userID, "user"), nil
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.userRepo = userRepoMock
+	})
+
+	resp, err := h.GetUserByEmail(context.Background(), &pb.GetUserByEmailRequest{
+		Email: "Test@Example.com",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), resp.User.Id)
+	assert.Equal(t, "active", resp.User.State)
+}
+
+func TestGetUserByEmail_ShouldReturnNotFound_WhenUserDoesNotExist(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	_, err := h.GetUserByEmail(context.Background(), &pb.GetUserByEmailRequest{
+		Email: "missing@example.com",
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListUsers_ShouldReturnPermissionDenied_WhenAPIKeyOwnerNotAdmin(t *testing.T) {
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+				return []models.Role{{Name: "user"}}, nil
+			},
+		}
+		h.adminService = &mockAdminServicerGRPC{
+			ListUsersFunc: func(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
+				called = true
+				return &models.AdminUserListResponse{}, nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, uuid.New().String())
+	_, err := h.ListUsers(ctx, &pb.ListUsersRequest{Search: "test"})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestGetUserByEmail_ShouldReturnPermissionDenied_WhenAPIKeyOwnerNotAdmin(t *testing.T) {
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+				return []models.Role{{Name: "user"}}, nil
+			},
+		}
+		h.userRepo = &mockUserStoreGRPC{
+			GetByEmailFunc: func(ctx context.Context, email string, isActive *bool, opts ...service.UserGetOption) (*models.User, error) {
+				called = true
+				return newTestUser(uuid.New(), "user"), nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, uuid.New().String())
+	_, err := h.GetUserByEmail(ctx, &pb.GetUserByEmailRequest{Email: "test@example.com"})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestUpdateUser_ShouldPassOnlySetFields(t *testing.T) {
+	userID := uuid.New()
+	verified := true
+
+	adminMock := &mockAdminServicerGRPC{
+		UpdateUserFunc: func(ctx context.Context, id uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+			assert.Equal(t, userID, id)
+			assert.Equal(t, uuid.Nil, adminID)
+			require.NotNil(t, req.FullName)
+			assert.Equal(t, "New Name", *req.FullName)
+			assert.Nil(t, req.Email)
+			assert.Nil(t, req.Username)
+			assert.Nil(t, req.Phone)
+			assert.Nil(t, req.RoleIDs)
+			assert.Equal(t, &verified, req.EmailVerified)
+			user := newTestAdminUser(userID, "user")
+			user.FullName = *req.FullName
+			return user, nil
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.adminService = adminMock
+	})
+
+	resp, err := h.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		UserId:        userID.String(),
+		FullName:      "New Name",
+		EmailVerified: &verified,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "New Name", resp.User.FullName)
+}
+
+func TestUpdateUser_ShouldReturnError_WhenInvalidEmail(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	_, err := h.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		UserId: uuid.New().String(),
+		Email:  "not-an-email",
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUpdateUser_ShouldReturnPermissionDenied_WhenAPIKeyOwnerNotAdmin(t *testing.T) {
+	ownerID := uuid.New()
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+				return []models.Role{{Name: "user"}}, nil
+			},
+		}
+		h.adminService = &mockAdminServicerGRPC{
+			UpdateUserFunc: func(ctx context.Context, id uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+				called = true
+				return nil, nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, ownerID.String())
+	_, err := h.UpdateUser(ctx, &pb.UpdateUserRequest{
+		UserId:   uuid.New().String(),
+		FullName: "New Name",
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestDeactivateUser_ShouldDeactivateAsAPIKeyOwner_WhenOwnerIsAdmin(t *testing.T) {
+	ownerID := uuid.New()
+	userID := uuid.New()
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, id uuid.UUID) ([]models.Role, error) {
+				assert.Equal(t, ownerID, id)
+				return []models.Role{{Name: "admin"}}, nil
+			},
+		}
+		h.adminService = &mockAdminServicerGRPC{
+			UpdateUserStateFunc: func(ctx context.Context, id uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error) {
+				assert.Equal(t, userID, id)
+				assert.Equal(t, ownerID, adminID)
+				assert.Equal(t, models.UserStateDeactivated, req.State)
+				assert.Equal(t, "left the company", req.Reason)
+				user := newTestAdminUser(userID, "user")
+				user.IsActive = false
+				user.State = models.UserStateDeactivated
+				return user, nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, ownerID.String())
+	resp, err := h.DeactivateUser(ctx, &pb.DeactivateUserRequest{
+		UserId: userID.String(),
+		Reason: "left the company",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, resp.User.IsActive)
+	assert.Equal(t, "deactivated", resp.User.State)
+}
+
+func TestDeactivateUser_ShouldReturnFailedPrecondition_WhenTransitionInvalid(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.adminService = &mockAdminServicerGRPC{
+			UpdateUserStateFunc: func(ctx context.Context, id uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error) {
+				return nil, models.NewAppError(409, "Invalid user state transition")
+			},
+		}
+	})
+
+	_, err := h.DeactivateUser(context.Background(), &pb.DeactivateUserRequest{
+		UserId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestAssignRole_ShouldResolveRoleName(t *testing.T) {
+	userID := uuid.New()
+	roleID := uuid.New()
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetRoleByNameAndAppFunc: func(ctx context.Context, name string, appID *uuid.UUID) (*models.Role, error) {
+				assert.Equal(t, "editor", name)
+				assert.Nil(t, appID)
+				return &models.Role{ID: roleID, Name: name}, nil
+			},
+		}
+		h.adminService = &mockAdminServicerGRPC{
+			AssignRoleFunc: func(ctx context.Context, id, rID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+				assert.Equal(t, userID, id)
+				assert.Equal(t, roleID, rID)
+				return newTestAdminUser(userID, "user", "editor"), nil
+			},
+		}
+	})
+
+	resp, err := h.AssignRole(context.Background(), &pb.AssignRoleRequest{
+		UserId:   userID.String(),
+		RoleName: "editor",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "editor"}, resp.User.Roles)
+}
+
+func TestAssignRole_ShouldReturnNotFound_WhenRoleNameUnknown(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetRoleByNameAndAppFunc: func(ctx context.Context, name string, appID *uuid.UUID) (*models.Role, error) {
+				return nil, errors.New("role not found")
+			},
+		}
+	})
+
+	_, err := h.AssignRole(context.Background(), &pb.AssignRoleRequest{
+		UserId:   uuid.New().String(),
+		RoleName: "missing",
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRemoveRole_ShouldReturnInvalidArgument_WhenLastRole(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.adminService = &mockAdminServicerGRPC{
+			RemoveRoleFunc: func(ctx context.Context, id, roleID uuid.UUID) (*models.AdminUserResponse, error) {
+				return nil, models.NewAppError(400, "User must have at least one role")
+			},
+		}
+	})
+
+	_, err := h.RemoveRole(context.Background(), &pb.RemoveRoleRequest{
+		UserId: uuid.New().String(),
+		RoleId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "at least one role")
+}
+
+func TestRemoveRole_ShouldReturnError_WhenRoleMissing(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	_, err := h.RemoveRole(context.Background(), &pb.RemoveRoleRequest{
+		UserId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "role_id or role_name is required")
+}
+
+// ===================== Session Management Tests =====================
+
+func TestListUserSessions_ShouldReturnSessions_WhenValid(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	now := time.Now()
+
+	sessionMock := &mockSessionServicerGRPC{
+		GetUserSessionsFunc: func(ctx context.Context, id uuid.UUID, page, perPage int) (*models.SessionListResponse, error) {
+			assert.Equal(t, userID, id)
+			assert.Equal(t, 1, page)
+			assert.Equal(t, 20, perPage)
+			return &models.SessionListResponse{
+				Sessions: []models.ActiveSessionResponse{
+					{
+						ID:           sessionID,
+						UserID:       userID,
+						DeviceType:   "desktop",
+						IPAddress:    "203.0.113.7",
+						LastActiveAt: now,
+						CreatedAt:    now,
+						ExpiresAt:    now.Add(time.Hour),
+					},
+				},
+				Total:      1,
+				Page:       page,
+				PageSize:   perPage,
+				TotalPages: 1,
+			}, nil
+		},
+	}
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.sessionService = sessionMock
+	})
+
+	resp, err := h.ListUserSessions(context.Background(), &pb.ListUserSessionsRequest{
+		UserId: userID.String(),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Sessions, 1)
+	assert.Equal(t, sessionID.String(), resp.Sessions[0].Id)
+	assert.Equal(t, "203.0.113.7", resp.Sessions[0].IpAddress)
+	assert.Equal(t, now.Add(time.Hour).Unix(), resp.Sessions[0].ExpiresAt)
+	assert.Equal(t, int32(1), resp.Total)
+}
+
+func TestListUserSessions_ShouldReturnPermissionDenied_WhenAPIKeyOwnerNotAdmin(t *testing.T) {
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+				return []models.Role{{Name: "user"}}, nil
+			},
+		}
+		h.sessionService = &mockSessionServicerGRPC{
+			GetUserSessionsFunc: func(ctx context.Context, uid uuid.UUID, page, pageSize int) (*models.SessionListResponse, error) {
+				called = true
+				return &models.SessionListResponse{}, nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, uuid.New().String())
+	_, err := h.ListUserSessions(ctx, &pb.ListUserSessionsRequest{
+		UserId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestListUserSessions_ShouldReturnError_WhenInvalidUserID(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.sessionService = &mockSessionServicerGRPC{}
+	})
+
+	_, err := h.ListUserSessions(context.Background(), &pb.ListUserSessionsRequest{UserId: "not-a-uuid"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRevokeSession_ShouldRevokeSessionOfUser(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.sessionService = &mockSessionServicerGRPC{
+			RevokeSessionFunc: func(ctx context.Context, uid, sid uuid.UUID) error {
+				assert.Equal(t, userID, uid)
+				assert.Equal(t, sessionID, sid)
+				return nil
+			},
+		}
+	})
+
+	resp, err := h.RevokeSession(context.Background(), &pb.RevokeSessionRequest{
+		UserId:    userID.String(),
+		SessionId: sessionID.String(),
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+}
+
+func TestRevokeSession_ShouldReturnNotFound_WhenSessionOfAnotherUser(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.sessionService = &mockSessionServicerGRPC{
+			RevokeSessionFunc: func(ctx context.Context, uid, sid uuid.UUID) error {
+				return errors.New("session does not belong to user")
+			},
+		}
+	})
+
+	_, err := h.RevokeSession(context.Background(), &pb.RevokeSessionRequest{
+		UserId:    uuid.New().String(),
+		SessionId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRevokeAllUserSessions_ShouldRevokeEverySession(t *testing.T) {
+	userID := uuid.New()
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.sessionService = &mockSessionServicerGRPC{
+			RevokeAllUserSessionsFunc: func(ctx context.Context, uid uuid.UUID, except *uuid.UUID) error {
+				called = true
+				assert.Equal(t, userID, uid)
+				assert.Nil(t, except)
+				return nil
+			},
+		}
+	})
+
+	resp, err := h.RevokeAllUserSessions(context.Background(), &pb.RevokeAllUserSessionsRequest{
+		UserId: userID.String(),
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, called)
+}
+
+func TestRevokeAllUserSessions_ShouldReturnPermissionDenied_WhenAPIKeyOwnerNotAdmin(t *testing.T) {
+	called := false
+
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.rbacRepo = &mockRBACStoreGRPC{
+			GetUserRolesFunc: func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+				return []models.Role{{Name: "user"}}, nil
+			},
+		}
+		h.sessionService = &mockSessionServicerGRPC{
+			RevokeAllUserSessionsFunc: func(ctx context.Context, uid uuid.UUID, except *uuid.UUID) error {
+				called = true
+				return nil
+			},
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), GRPCUserIDKey, uuid.New().String())
+	_, err := h.RevokeAllUserSessions(ctx, &pb.RevokeAllUserSessionsRequest{
+		UserId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.False(t, called)
+}
+
+func TestRevokeAllUserSessions_ShouldReturnUnimplemented_WhenServiceNil(t *testing.T) {
+	h := newTestAuthHandlerV2(newTestJWTService())
+
+	_, err := h.RevokeAllUserSessions(context.Background(), &pb.RevokeAllUserSessionsRequest{
+		UserId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
 // ===================== SendEmail Tests =====================
 
 func TestSendEmail_ShouldReturnSuccess_WhenEmailSent(t *testing.T) {
@@ -1087,6 +1795,44 @@ func TestIntrospectOAuthToken_ShouldReturnInactive_WhenEmpty(t *testing.T) {
 	assert.Equal(t, "token is required", resp.ErrorMessage)
 }
 
+func TestIntrospectOAuthToken_ShouldCheckCertificateBinding(t *testing.T) {
+	oauthMock := &mockOAuthProviderServicerGRPC{
+		IntrospectTokenFunc: func(ctx context.Context, token, tokenTypeHint string, clientID *string) (*models.IntrospectionResponse, error) {
+			return &models.IntrospectionResponse{
+				Active:    true,
+				TokenType: "Bearer",
+				Cnf:       &models.Confirmation{X5TS256: "thumbprint-a"},
+			}, nil
+		},
+	}
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.oauthProviderService = oauthMock
+	})
+
+	tests := []struct {
+		name       string
+		thumbprint string
+		active     bool
+	}{
+		{"same certificate", "thumbprint-a", true},
+		{"other certificate", "thumbprint-b", false},
+		{"no certificate", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.thumbprint != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-client-cert-thumbprint", tt.thumbprint))
+			}
+
+			resp, err := h.IntrospectOAuthToken(ctx, &pb.IntrospectOAuthTokenRequest{Token: "oauth-token-abc"})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.active, resp.Active)
+		})
+	}
+}
+
 // ===================== ValidateOAuthClient Tests =====================
 
 func TestValidateOAuthClient_ShouldReturnValid_WhenCredentialsCorrect(t *testing.T) {
@@ -1749,3 +2495,99 @@ func TestListApplicationUsers_ShouldUseDefaultPagination_WhenZeroValues(t *testi
 	assert.Equal(t, 20, capturedPageSize)
 	assert.Equal(t, int32(0), resp.Total)
 }
+
+// ===================== WatchRevocations =====================
+
+// revocationStream records events sent on a WatchRevocations stream
+type revocationStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.RevocationEvent
+}
+
+func (s *revocationStream) Context() context.Context { return s.ctx }
+
+func (s *revocationStream) Send(event *pb.RevocationEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func TestWatchRevocations_ShouldStreamUserEvents(t *testing.T) {
+	hub := service.NewRevocationHub(nil, testLogger())
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.revocations = hub
+	})
+	userID, otherID := uuid.New(), uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &revocationStream{ctx: ctx, sent: make(chan *pb.RevocationEvent, 4)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.WatchRevocations(&pb.WatchRevocationsRequest{UserId: userID.String()}, stream)
+	}()
+
+	// Publish until the stream has subscribed
+	expiresAt := time.Now().Add(time.Hour)
+	var event *pb.RevocationEvent
+	for event == nil {
+		hub.Publish(ctx, models.RevocationEvent{Type: models.RevocationTypeUser, UserID: &otherID, RevokedAt: time.Now()})
+		hub.Publish(ctx, models.RevocationEvent{Type: models.RevocationTypeToken, TokenHash: "hash", UserID: &userID, RevokedAt: time.Now(), ExpiresAt: &expiresAt})
+		select {
+		case event = <-stream.sent:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	assert.Equal(t, "token", event.Type)
+	assert.Equal(t, "hash", event.TokenHash)
+	assert.Equal(t, userID.String(), event.UserId)
+	assert.Equal(t, expiresAt.Unix(), event.ExpiresAt)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestWatchRevocations_ShouldFail_WhenHubStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := service.NewRevocationHub(nil, testLogger())
+	hubDone := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(hubDone)
+	}()
+	h := newTestAuthHandlerV2(newTestJWTService(), func(h *AuthHandlerV2) {
+		h.revocations = hub
+	})
+	stream := &revocationStream{ctx: context.Background(), sent: make(chan *pb.RevocationEvent, 16)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.WatchRevocations(&pb.WatchRevocationsRequest{}, stream)
+	}()
+
+	// Publish until the stream has subscribed
+	for subscribed := false; !subscribed; {
+		hub.Publish(ctx, models.RevocationEvent{Type: models.RevocationTypeAll})
+		select {
+		case <-stream.sent:
+			subscribed = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-hubDone
+	assert.Equal(t, codes.Unavailable, status.Code(<-done))
+}
+
+func TestWatchRevocations_ShouldValidateRequest(t *testing.T) {
+	stream := &revocationStream{ctx: context.Background()}
+
+	h := newTestAuthHandlerV2(newTestJWTService())
+	err := h.WatchRevocations(&pb.WatchRevocationsRequest{}, stream)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	h.revocations = service.NewRevocationHub(nil, testLogger())
+	err = h.WatchRevocations(&pb.WatchRevocationsRequest{UserId: "not-a-uuid"}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	pb "github.com/smilemakc/auth-gateway/proto"
+)
+
+// Services reported by the health service in addition to the whole server ("") and
+// auth.AuthService, so probes can tell which dependency is down
+const (
+	healthServiceDatabase = "database"
+	healthServiceRedis    = "redis"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// DatabaseHealthChecker reports whether the database is reachable
+type DatabaseHealthChecker interface {
+	Health() error
+}
+
+// healthReporter keeps the statuses of the standard grpc.health.v1.Health service up to
+// date for Kubernetes and Envoy probes. The server and auth.AuthService are SERVING while
+// both the database and Redis are; each dependency is also reported on its own.
+type healthReporter struct {
+	server   *health.Server
+	checks   map[string]func(context.Context) error
+	interval time.Duration
+	log      *logger.Logger
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func newHealthReporter(db DatabaseHealthChecker, redis service.RedisServicer, interval time.Duration, log *logger.Logger) *healthReporter {
+	r := &healthReporter{
+		server: health.NewServer(),
+		checks: map[string]func(context.Context) error{
+			healthServiceDatabase: func(context.Context) error { return db.Health() },
+			healthServiceRedis:    redis.Health,
+		},
+		interval: interval,
+		log:      log,
+		stop:     make(chan struct{}),
+	}
+	// Nothing is SERVING before the first check
+	for _, name := range r.services() {
+		r.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return r
+}
+
+func (r *healthReporter) services() []string {
+	return []string{"", pb.AuthService_ServiceDesc.ServiceName, healthServiceDatabase, healthServiceRedis}
+}
+
+// run checks the dependencies every interval until shutdown
+func (r *healthReporter) run() {
+	r.check()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// check runs the dependency checks and updates the statuses
+func (r *healthReporter) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	for name, check := range r.checks {
+		st := healthpb.HealthCheckResponse_SERVING
+		if err := check(ctx); err != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = st
+			r.log.Warn("gRPC health check failed", map[string]interface{}{
+				"service": name,
+				"error":   err.Error(),
+			})
+		}
+		r.server.SetServingStatus(name, st)
+	}
+	r.server.SetServingStatus("", overall)
+	r.server.SetServingStatus(pb.AuthService_ServiceDesc.ServiceName, overall)
+}
+
+// shutdown reports every service as NOT_SERVING, so probes take the server out of
+// rotation while it drains, and stops the checks
+func (r *healthReporter) shutdown() {
+	r.once.Do(func() {
+		close(r.stop)
+		r.server.Shutdown()
+	})
+}
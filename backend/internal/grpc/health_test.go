@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type mockDatabaseHealth struct {
+	err error
+}
+
+func (m *mockDatabaseHealth) Health() error { return m.err }
+
+// healthStatus returns the status the health service reports for name
+func healthStatus(t *testing.T, r *healthReporter, name string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := r.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: name})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthReporter_ShouldNotServeBeforeFirstCheck(t *testing.T) {
+	r := newHealthReporter(&mockDatabaseHealth{}, &mockRedisServicerGRPC{}, time.Minute, testLogger())
+
+	for _, name := range r.services() {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, r, name), name)
+	}
+}
+
+func TestHealthReporter_ShouldReflectDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbErr    error
+		redisErr error
+		overall  healthpb.HealthCheckResponse_ServingStatus
+		database healthpb.HealthCheckResponse_ServingStatus
+		redis    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"all healthy", nil, nil, healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_SERVING},
+		{"database down", errors.New("connection refused"), nil, healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVING},
+		{"redis down", nil, errors.New("connection refused"), healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redis := &mockRedisServicerGRPC{HealthFunc: func(ctx context.Context) error { return tt.redisErr }}
+			r := newHealthReporter(&mockDatabaseHealth{err: tt.dbErr}, redis, time.Minute, testLogger())
+
+			r.check()
+
+			assert.Equal(t, tt.overall, healthStatus(t, r, ""))
+			assert.Equal(t, tt.overall, healthStatus(t, r, "auth.AuthService"))
+			assert.Equal(t, tt.database, healthStatus(t, r, healthServiceDatabase))
+			assert.Equal(t, tt.redis, healthStatus(t, r, healthServiceRedis))
+		})
+	}
+}
+
+func TestHealthReporter_ShouldRecoverWhenDependencyComesBack(t *testing.T) {
+	db := &mockDatabaseHealth{err: errors.New("connection refused")}
+	r := newHealthReporter(db, &mockRedisServicerGRPC{}, time.Minute, testLogger())
+
+	r.check()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, r, ""))
+
+	db.err = nil
+	r.check()
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, healthStatus(t, r, ""))
+}
+
+func TestHealthReporter_ShouldNotServeAfterShutdown(t *testing.T) {
+	r := newHealthReporter(&mockDatabaseHealth{}, &mockRedisServicerGRPC{}, time.Millisecond, testLogger())
+	done := make(chan struct{})
+	go func() {
+		r.run()
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		return healthStatus(t, r, "") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+
+	r.shutdown()
+	r.shutdown()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run did not return after shutdown")
+	}
+	r.check()
+	for _, name := range r.services() {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, r, name), name)
+	}
+}
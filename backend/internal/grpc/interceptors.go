@@ -3,6 +3,8 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -10,10 +12,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
 )
 
@@ -24,8 +31,137 @@ const (
 	GRPCAPIKeyKey        = "grpc_api_key"
 	GRPCUserIDKey        = "grpc_user_id"
 	GRPCUserEmailKey     = "grpc_user_email"
+	GRPCRequestIDKey     = "grpc_request_id"
 )
 
+// requestIDHeader carries the request ID in request and response metadata
+const requestIDHeader = "x-request-id"
+
+// requestIDInterceptor assigns each call an ID, taken from x-request-id metadata when the
+// caller sent one, and returns it in the response headers. The ID is carried by the context,
+// so every entry logged with it includes the request ID, and the trace ID when tracing is on.
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(requestIDHeader); len(ids) > 0 && len(ids[0]) <= 128 {
+				requestID = ids[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+		ctx = context.WithValue(ctx, GRPCRequestIDKey, requestID)
+		fields := map[string]interface{}{
+			"request_id": requestID,
+		}
+		// The tracing stats handler has already started the call's span
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			fields["trace_id"] = traceID
+		}
+		ctx = logger.ContextWithFields(ctx, fields)
+		return handler(ctx, req)
+	}
+}
+
+// metricsInterceptor records the count, status code and latency of each call. Like the
+// access log it runs ahead of rate limiting and authentication, so rejected calls count too.
+func metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.RecordGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+// accessLogInterceptor writes each call to the access log. It runs ahead of rate limiting
+// and authentication so that rejected calls are logged too; authentication fills in the user.
+func accessLogInterceptor(log *accesslog.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		entry := &accesslog.Entry{
+			Time:     time.Now(),
+			Protocol: accesslog.ProtocolGRPC,
+			Method:   "POST",
+			Path:     info.FullMethod,
+		}
+		if requestID, ok := ctx.Value(GRPCRequestIDKey).(string); ok {
+			entry.RequestID = requestID
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			entry.IP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(entry.IP); err == nil {
+				entry.IP = host
+			}
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if agents := md.Get("user-agent"); len(agents) > 0 {
+				entry.UserAgent = agents[0]
+			}
+		}
+
+		resp, err := handler(accesslog.NewContext(ctx, entry), req)
+
+		code := status.Code(err)
+		entry.Status = httpStatusFromCode(code)
+		entry.GRPCCode = code.String()
+		entry.Duration = time.Since(entry.Time)
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			entry.Bytes = proto.Size(msg)
+		}
+		log.Log(*entry)
+
+		return resp, err
+	}
+}
+
+// httpStatusFromCode maps a gRPC status code to the equivalent HTTP status
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // contextExtractorInterceptor extracts additional context from gRPC metadata.
 // Note: x-application-id is handled by apiKeyAuthInterceptor to avoid conflicts
 // with application_id set during app secret authentication.
@@ -80,9 +216,9 @@ func loggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
 
 		if err != nil {
 			fields["error"] = err.Error()
-			log.Warn("gRPC request failed", fields)
+			log.WarnContext(ctx, "gRPC request failed", fields)
 		} else {
-			log.Info("gRPC request", fields)
+			log.InfoContext(ctx, "gRPC request", fields)
 		}
 
 		return resp, err
@@ -197,6 +333,15 @@ var methodScopes = map[string]models.APIKeyScope{
 	"/auth.AuthService/BanUser":                     models.ScopeReadUsers,
 	"/auth.AuthService/UnbanUser":                   models.ScopeReadUsers,
 	"/auth.AuthService/ListApplicationUsers":        models.ScopeReadUsers,
+	"/auth.AuthService/ListUsers":                        models.ScopeReadUsers,
+	"/auth.AuthService/GetUserByEmail":                   models.ScopeReadUsers,
+	"/auth.AuthService/UpdateUser":                       models.ScopeWriteUsers,
+	"/auth.AuthService/DeactivateUser":                   models.ScopeWriteUsers,
+	"/auth.AuthService/AssignRole":                       models.ScopeAdmin,
+	"/auth.AuthService/RemoveRole":                       models.ScopeAdmin,
+	"/auth.AuthService/ListUserSessions":                 models.ScopeSessionsRead,
+	"/auth.AuthService/RevokeSession":                    models.ScopeSessionsRevoke,
+	"/auth.AuthService/RevokeAllUserSessions":            models.ScopeSessionsRevoke,
 	"/auth.AuthService/CreateUser":                       models.ScopeAuthRegister,
 	"/auth.AuthService/Login":                            models.ScopeAuthLogin,
 	"/auth.AuthService/SendOTP":                          models.ScopeAuthOTP,
@@ -214,11 +359,20 @@ var methodScopes = map[string]models.APIKeyScope{
 	"/auth.AuthService/GetOAuthClient":                   models.ScopeOAuthRead,
 	"/auth.AuthService/CreateTokenExchange":              models.ScopeExchangeManage,
 	"/auth.AuthService/RedeemTokenExchange":              models.ScopeExchangeManage,
+	"/auth.AuthService/RegisterPermissionCatalog":        models.ScopeRBACRegister,
+	"/auth.AuthService/WatchRevocations":                 models.ScopeIntrospectToken,
 }
 
 // NOTE: GetUserTelegramBots is excluded from methodScopes until fully implemented.
 // The handler exists but returns codes.Unimplemented. Deny-by-default interceptor blocks it.
 
+// isPublicMethod reports whether a method is served without authentication: health
+// checks, which probes make without credentials, and reflection
+func isPublicMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
 // apiKeyAuthInterceptor validates API key authentication for all gRPC requests
 func apiKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appService service.ApplicationServicer, log *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(
@@ -227,6 +381,10 @@ func apiKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appService serv
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			log.Warn("gRPC auth failed: no metadata", map[string]interface{}{
@@ -280,8 +438,11 @@ func apiKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appService serv
 			}
 
 			ctx = context.WithValue(ctx, GRPCApplicationIDKey, app.ID.String())
+			ctx = logger.ContextWithFields(ctx, map[string]interface{}{
+				"application_id": app.ID.String(),
+			})
 
-			log.Debug("gRPC auth successful (application secret)", map[string]interface{}{
+			log.DebugContext(ctx, "gRPC auth successful (application secret)", map[string]interface{}{
 				"method":         info.FullMethod,
 				"application_id": app.ID.String(),
 				"app_name":       app.Name,
@@ -337,11 +498,14 @@ func apiKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appService serv
 		ctx = context.WithValue(ctx, GRPCAPIKeyKey, apiKeyObj)
 		ctx = context.WithValue(ctx, GRPCUserIDKey, user.ID.String())
 		ctx = context.WithValue(ctx, GRPCUserEmailKey, user.Email)
-
-		log.Debug("gRPC auth successful (API key)", map[string]interface{}{
-			"method":  info.FullMethod,
+		ctx = logger.ContextWithFields(ctx, map[string]interface{}{
 			"user_id": user.ID.String(),
-			"api_key": apiKeyObj.KeyPrefix,
+		})
+		accesslog.SetUserID(ctx, user.ID.String())
+
+		log.DebugContext(ctx, "gRPC auth successful (API key)", map[string]interface{}{
+			"method":         info.FullMethod,
+			"api_key_prefix": apiKeyObj.KeyPrefix,
 		})
 
 		return handler(ctx, req)
@@ -356,8 +520,7 @@ func streamAPIKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appServic
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		// Allow gRPC reflection without authentication
-		if strings.HasPrefix(info.FullMethod, "/grpc.reflection.") {
+		if isPublicMethod(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
@@ -371,9 +534,18 @@ func streamAPIKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appServic
 			return status.Error(codes.Unauthenticated, "missing API key or application secret: provide x-api-key metadata")
 		}
 
-		// Application secret — full access
+		// Verify method is allowed (deny-by-default)
+		requiredScope, scopeRequired := methodScopes[info.FullMethod]
+		if !scopeRequired {
+			log.Warn("gRPC stream auth failed: method not configured", map[string]interface{}{
+				"method": info.FullMethod,
+			})
+			return status.Errorf(codes.PermissionDenied, "method %s is not configured for access", info.FullMethod)
+		}
+
+		// Application secret
 		if strings.HasPrefix(credential, "app_") {
-			_, err := appService.ValidateSecret(ss.Context(), credential)
+			app, err := appService.ValidateSecret(ss.Context(), credential)
 			if err != nil {
 				log.Warn("gRPC stream auth failed: invalid application secret", map[string]interface{}{
 					"method": info.FullMethod,
@@ -381,11 +553,19 @@ func streamAPIKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appServic
 				})
 				return status.Error(codes.Unauthenticated, "invalid application secret")
 			}
+			if len(app.AllowedGRPCScopes) > 0 && !containsScope(app.AllowedGRPCScopes, string(requiredScope)) {
+				log.Warn("gRPC stream auth failed: application scope restriction", map[string]interface{}{
+					"method":         info.FullMethod,
+					"required_scope": string(requiredScope),
+					"app_name":       app.Name,
+				})
+				return status.Errorf(codes.PermissionDenied, "application not authorized for scope %q", string(requiredScope))
+			}
 			return handler(srv, ss)
 		}
 
 		// API key
-		_, _, err := apiKeyService.ValidateAPIKey(ss.Context(), credential)
+		apiKeyObj, user, err := apiKeyService.ValidateAPIKey(ss.Context(), credential)
 		if err != nil {
 			log.Warn("gRPC stream auth failed: invalid API key", map[string]interface{}{
 				"method": info.FullMethod,
@@ -393,6 +573,14 @@ func streamAPIKeyAuthInterceptor(apiKeyService service.APIKeyServicer, appServic
 			})
 			return status.Error(codes.Unauthenticated, "invalid API key")
 		}
+		if !apiKeyService.HasScope(apiKeyObj, requiredScope) {
+			log.Warn("gRPC stream auth failed: insufficient scope", map[string]interface{}{
+				"method":         info.FullMethod,
+				"required_scope": string(requiredScope),
+				"user_id":        user.ID.String(),
+			})
+			return status.Errorf(codes.PermissionDenied, "insufficient scope: requires %s", string(requiredScope))
+		}
 
 		return handler(srv, ss)
 	}
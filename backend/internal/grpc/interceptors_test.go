@@ -1,13 +1,16 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -15,8 +18,11 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
+	"github.com/smilemakc/auth-gateway/internal/config"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/testutil/fixtures"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
 )
@@ -118,6 +124,15 @@ func (m *mockUserStoreForGRPC) Count(ctx context.Context, isActive *bool) (int,
 func (m *mockUserStoreForGRPC) GetUsersUpdatedAfter(ctx context.Context, after time.Time, appID *uuid.UUID, limit, offset int) ([]*models.User, int, error) {
 	return nil, 0, nil
 }
+func (m *mockUserStoreForGRPC) Search(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockUserStoreForGRPC) Filter(ctx context.Context, limit, offset int, opts ...service.UserFilterOption) ([]*models.User, error) {
+	return nil, nil
+}
+func (m *mockUserStoreForGRPC) CountFiltered(ctx context.Context, opts ...service.UserFilterOption) (int, error) {
+	return 0, nil
+}
 func (m *mockUserStoreForGRPC) UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
 	return nil
 }
@@ -275,12 +290,12 @@ func makeAPIKeyForPlainKey(userID uuid.UUID, plainKey string, scopes []string) *
 }
 
 func makeUser(id uuid.UUID) *models.User {
-	return &models.User{
-		ID:       id,
-		Email:    "test@example.com",
-		Username: "testuser",
-		IsActive: true,
-	}
+	return fixtures.NewUserBuilder().
+		WithID(id).
+		WithEmail("test@example.com").
+		WithUsername("testuser").
+		WithRoles().
+		Build()
 }
 
 func makeApplication(id uuid.UUID) *models.Application {
@@ -451,6 +466,91 @@ func TestGetTenantIDFromGRPCContext_ShouldReturnNil_WhenWrongType(t *testing.T)
 	assert.Nil(t, result)
 }
 
+// ===================== requestIDInterceptor Tests =====================
+
+func TestRequestIDInterceptor_ShouldKeepRequestID_WhenPresent(t *testing.T) {
+	interceptor := requestIDInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-123"))
+
+	var captured context.Context
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, contextCapturingHandler(&captured))
+
+	require.NoError(t, err)
+	assert.Equal(t, "req-123", logger.FieldsFromContext(captured)["request_id"])
+}
+
+func TestRequestIDInterceptor_ShouldGenerateRequestID_WhenMissing(t *testing.T) {
+	interceptor := requestIDInterceptor()
+
+	var captured context.Context
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, contextCapturingHandler(&captured))
+
+	require.NoError(t, err)
+	requestID, _ := logger.FieldsFromContext(captured)["request_id"].(string)
+	_, err = uuid.Parse(requestID)
+	assert.NoError(t, err)
+}
+
+// ===================== accessLogInterceptor Tests =====================
+
+func TestAccessLogInterceptor_ShouldLogCall(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := accesslog.NewWriter(&buf, &config.AccessLogConfig{Format: "json"})
+	require.NoError(t, err)
+	interceptor := accessLogInterceptor(log)
+
+	ctx := context.WithValue(context.Background(), GRPCRequestIDKey, "req-1")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "grpc-go/1.0"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		accesslog.SetUserID(ctx, "user-1")
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/auth.AuthService/GetUser"}, handler)
+	require.Error(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "grpc", entry["protocol"])
+	assert.Equal(t, "/auth.AuthService/GetUser", entry["path"])
+	assert.Equal(t, float64(http.StatusForbidden), entry["status"])
+	assert.Equal(t, "PermissionDenied", entry["grpc_code"])
+	assert.Equal(t, "grpc-go/1.0", entry["user_agent"])
+	assert.Equal(t, "user-1", entry["user_id"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
+
+// ===================== metricsInterceptor Tests =====================
+
+func TestMetricsInterceptor_ShouldRecordCall(t *testing.T) {
+	interceptor := metricsInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/auth.AuthService/CheckPermission"}, handler)
+	require.Error(t, err)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var count float64
+	for _, family := range families {
+		if family.GetName() != "auth_gateway_grpc_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == "/auth.AuthService/CheckPermission" && labels["code"] == "PermissionDenied" {
+				count = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, float64(1), count)
+}
+
 // ===================== contextExtractorInterceptor Tests =====================
 
 func TestContextExtractorInterceptor_ShouldExtractTenantID_WhenPresent(t *testing.T) {
@@ -571,6 +671,21 @@ func TestAPIKeyAuthInterceptor_ShouldRejectRequest_WhenNoMetadata(t *testing.T)
 	assert.Contains(t, st.Message(), "missing API key")
 }
 
+func TestAPIKeyAuthInterceptor_ShouldAllowHealthChecks_WithoutCredential(t *testing.T) {
+	apiKeyService := buildAPIKeyService(&mockAPIKeyStoreForGRPC{}, &mockUserStoreForGRPC{})
+	appService := buildApplicationService(&mockApplicationStoreForGRPC{})
+
+	unary := apiKeyAuthInterceptor(apiKeyService, appService, testLogger())
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, noopHandler)
+	assert.NoError(t, err)
+
+	stream := streamAPIKeyAuthInterceptor(apiKeyService, appService, testLogger())
+	err = stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch", IsServerStream: true}, func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
 func TestAPIKeyAuthInterceptor_ShouldRejectRequest_WhenNoCredential(t *testing.T) {
 	apiKeyService := buildAPIKeyService(&mockAPIKeyStoreForGRPC{}, &mockUserStoreForGRPC{})
 	appService := buildApplicationService(&mockApplicationStoreForGRPC{})
@@ -1067,6 +1182,15 @@ func TestMethodScopes_ShouldContainAllExpectedMethods(t *testing.T) {
 		"/auth.AuthService/BanUser",
 		"/auth.AuthService/UnbanUser",
 		"/auth.AuthService/ListApplicationUsers",
+		"/auth.AuthService/ListUsers",
+		"/auth.AuthService/GetUserByEmail",
+		"/auth.AuthService/UpdateUser",
+		"/auth.AuthService/DeactivateUser",
+		"/auth.AuthService/AssignRole",
+		"/auth.AuthService/RemoveRole",
+		"/auth.AuthService/ListUserSessions",
+		"/auth.AuthService/RevokeSession",
+		"/auth.AuthService/RevokeAllUserSessions",
 		"/auth.AuthService/CreateUser",
 		"/auth.AuthService/Login",
 		"/auth.AuthService/SendOTP",
@@ -1084,6 +1208,7 @@ func TestMethodScopes_ShouldContainAllExpectedMethods(t *testing.T) {
 		"/auth.AuthService/GetOAuthClient",
 		"/auth.AuthService/CreateTokenExchange",
 		"/auth.AuthService/RedeemTokenExchange",
+		"/auth.AuthService/WatchRevocations",
 	}
 
 	for _, method := range expectedMethods {
@@ -1115,7 +1240,17 @@ func TestMethodScopes_ShouldHaveCorrectScopes(t *testing.T) {
 		{"/auth.AuthService/IntrospectOAuthToken", models.ScopeOAuthRead},
 		{"/auth.AuthService/CreateTokenExchange", models.ScopeExchangeManage},
 		{"/auth.AuthService/RedeemTokenExchange", models.ScopeExchangeManage},
+		{"/auth.AuthService/RegisterPermissionCatalog", models.ScopeRBACRegister},
 		{"/auth.AuthService/GetUserApplicationProfile", models.ScopeReadProfile},
+		{"/auth.AuthService/ListUsers", models.ScopeReadUsers},
+		{"/auth.AuthService/GetUserByEmail", models.ScopeReadUsers},
+		{"/auth.AuthService/UpdateUser", models.ScopeWriteUsers},
+		{"/auth.AuthService/DeactivateUser", models.ScopeWriteUsers},
+		{"/auth.AuthService/AssignRole", models.ScopeAdmin},
+		{"/auth.AuthService/RemoveRole", models.ScopeAdmin},
+		{"/auth.AuthService/ListUserSessions", models.ScopeSessionsRead},
+		{"/auth.AuthService/RevokeSession", models.ScopeSessionsRevoke},
+		{"/auth.AuthService/RevokeAllUserSessions", models.ScopeSessionsRevoke},
 	}
 
 	for _, tt := range tests {
@@ -1166,6 +1301,76 @@ func TestAPIKeyAuthInterceptor_DenyByDefault_ShouldRejectUnknownMethod(t *testin
 	assert.Contains(t, st.Message(), "not configured for access")
 }
 
+// ===================== Stream interceptor =====================
+
+// contextStream is a server stream carrying only a context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+func TestStreamAPIKeyAuthInterceptor_ShouldEnforceScopes(t *testing.T) {
+	userID := uuid.New()
+	user := makeUser(userID)
+	keys := map[string]*models.APIKey{}
+	for plainKey, scopes := range map[string][]string{
+		"agw_introspect": {"token:introspect"},
+		"agw_readusers":  {"users:read"},
+	} {
+		keys[utils.HashToken(plainKey)] = makeAPIKeyForPlainKey(userID, plainKey, scopes)
+	}
+	apiKeyStore := &mockAPIKeyStoreForGRPC{
+		GetByKeyHashFunc: func(ctx context.Context, hash string) (*models.APIKey, error) {
+			if key, ok := keys[hash]; ok {
+				return key, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+	userStore := &mockUserStoreForGRPC{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...service.UserGetOption) (*models.User, error) {
+			return user, nil
+		},
+	}
+	restrictedSecret := "app_restricted"
+	appStore := &mockApplicationStoreForGRPC{
+		GetBySecretHashFunc: func(ctx context.Context, hash string) (*models.Application, error) {
+			if hash == utils.HashToken(restrictedSecret) {
+				return &models.Application{ID: uuid.New(), Name: "restricted", IsActive: true, SecretHash: hash, AllowedGRPCScopes: []string{"users:read"}}, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+	interceptor := streamAPIKeyAuthInterceptor(buildAPIKeyService(apiKeyStore, userStore), buildApplicationService(appStore), testLogger())
+
+	tests := []struct {
+		name       string
+		credential string
+		method     string
+		code       codes.Code
+	}{
+		{"API key with scope", "agw_introspect", "/auth.AuthService/WatchRevocations", codes.OK},
+		{"API key without scope", "agw_readusers", "/auth.AuthService/WatchRevocations", codes.PermissionDenied},
+		{"Unknown method", "agw_introspect", "/auth.AuthService/SomeNewStream", codes.PermissionDenied},
+		{"Application outside allowed scopes", restrictedSecret, "/auth.AuthService/WatchRevocations", codes.PermissionDenied},
+		{"Invalid API key", "agw_unknown", "/auth.AuthService/WatchRevocations", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.credential))
+			info := &grpc.StreamServerInfo{FullMethod: tt.method, IsServerStream: true}
+
+			err := interceptor(nil, &contextStream{ctx: ctx}, info, func(srv interface{}, ss grpc.ServerStream) error {
+				return nil
+			})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
 // ===================== Interceptor Chain Integration Tests =====================
 
 func TestInterceptorChain_ShouldRecoverFromPanicInLoggedRequest(t *testing.T) {
@@ -1186,3 +1391,29 @@ func TestInterceptorChain_ShouldRecoverFromPanicInLoggedRequest(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, codes.Internal, st.Code())
 }
+
+func TestMethodAuthorization_ShouldMatchMethodScopes(t *testing.T) {
+	methods := MethodAuthorization()
+
+	byName := make(map[string]models.GRPCMethodAuthorization, len(methods))
+	for _, method := range methods {
+		byName[method.Method] = method
+	}
+
+	validate := byName["/auth.AuthService/ValidateToken"]
+	assert.Equal(t, string(models.ScopeValidateToken), validate.Scope)
+	assert.False(t, validate.Denied)
+	assert.Equal(t, []string{models.CredentialAPIKey, models.CredentialAppSecret}, validate.Authentication)
+
+	watch := byName["/auth.AuthService/WatchRevocations"]
+	assert.True(t, watch.Streaming)
+	assert.Equal(t, string(models.ScopeIntrospectToken), watch.Scope)
+
+	bots := byName["/auth.AuthService/GetUserTelegramBots"]
+	assert.True(t, bots.Denied, "methods without a scope are denied by default")
+	assert.Empty(t, bots.Authentication)
+
+	for fullMethod := range methodScopes {
+		assert.Contains(t, byName, fullMethod)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 )
 
 type RateLimiter interface {
@@ -29,6 +31,7 @@ func rateLimitInterceptor(limiter RateLimiter, maxPerMinute int) grpc.UnaryServe
 		}
 
 		if count > int64(maxPerMinute) {
+			metrics.RecordRateLimitTrigger(metrics.TransportGRPC, info.FullMethod)
 			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded: %d requests per minute", maxPerMinute)
 		}
 
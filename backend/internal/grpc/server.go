@@ -6,10 +6,14 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
 	"github.com/smilemakc/auth-gateway/internal/config"
 	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
 	pb "github.com/smilemakc/auth-gateway/proto"
@@ -19,6 +23,8 @@ import (
 type Server struct {
 	grpcServer *grpc.Server
 	listener   net.Listener
+	drainer    *streamDrainer
+	health     *healthReporter
 	logger     *logger.Logger
 }
 
@@ -37,7 +43,17 @@ func NewServer(
 	adminService service.AdminServicer,
 	appService service.ApplicationServicer,
 	redis service.RedisServicer,
+	database DatabaseHealthChecker,
 	tokenExchangeService service.TokenExchangeServicer,
+	tokenVersions service.TokenVersionServicer,
+	permissionCatalog service.PermissionCatalogServicer,
+	blacklistFilter *service.BlacklistFilter,
+	revocations *service.RevocationHub,
+	userCache *service.UserCache,
+	sessionService service.SessionServicer,
+	accessLog *accesslog.Logger,
+	metricsEnabled bool,
+	tracingEnabled bool,
 	log *logger.Logger,
 ) (*Server, error) {
 	// Create listener
@@ -47,28 +63,57 @@ func NewServer(
 	}
 
 	// Build server options with unary and stream interceptors
+	// Calls go to metrics and the access log when they are enabled, so they also see
+	// rejected ones
+	unary := []grpc.UnaryServerInterceptor{requestIDInterceptor()}
+	if metricsEnabled {
+		unary = append(unary, metricsInterceptor())
+	}
+	if accessLog != nil {
+		unary = append(unary, accessLogInterceptor(accessLog))
+	}
+	unary = append(unary,
+		rateLimitInterceptor(redis, grpcConfig.MaxRequestsPerMinute),
+		apiKeyAuthInterceptor(apiKeyService, appService, log),
+		contextExtractorInterceptor(log),
+	)
+	if accessLog == nil {
+		unary = append(unary, loggingInterceptor(log))
+	}
+	unary = append(unary, recoveryInterceptor(log))
+
+	drainer := newStreamDrainer(grpcConfig.MaxStreamDuration)
+
 	serverOpts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(
-			rateLimitInterceptor(redis, grpcConfig.MaxRequestsPerMinute),
-			apiKeyAuthInterceptor(apiKeyService, appService, log),
-			contextExtractorInterceptor(log),
-			loggingInterceptor(log),
-			recoveryInterceptor(log),
-		),
+		grpc.ChainUnaryInterceptor(unary...),
 		grpc.ChainStreamInterceptor(
 			streamAPIKeyAuthInterceptor(apiKeyService, appService, log),
+			drainer.interceptor(),
 		),
+		// Aged connections get GOAWAY, so clients spread over instances added since they
+		// connected; zero values mean never
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionAge:      grpcConfig.MaxConnectionAge,
+			MaxConnectionAgeGrace: grpcConfig.MaxConnectionAgeGrace,
+		}),
+	}
+
+	// Spans continue the trace of the caller's traceparent metadata
+	if tracingEnabled {
+		serverOpts = append(serverOpts, grpc.StatsHandler(tracing.ServerHandler()))
 	}
 
 	// Add TLS credentials if enabled
 	if grpcConfig.TLSEnabled {
-		creds, err := credentials.NewServerTLSFromFile(grpcConfig.TLSCert, grpcConfig.TLSKey)
+		tlsConfig, err := serverTLSConfig(grpcConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
 		}
-		serverOpts = append(serverOpts, grpc.Creds(creds))
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
 		log.Info("gRPC TLS enabled", map[string]interface{}{
-			"cert": grpcConfig.TLSCert,
+			"cert":        grpcConfig.TLSCert,
+			"client_auth": grpcConfig.ClientAuthMode(),
+			"spiffe_ids":  len(grpcConfig.TLSClientSPIFFEIDs),
 		})
 	}
 
@@ -77,8 +122,18 @@ func NewServer(
 
 	// Register auth service handler
 	handler := NewAuthHandlerV2(jwtService, userRepo, tokenRepo, rbacRepo, apiKeyService, authService, oauthProviderService, otpService, emailProfileService, adminService, appService, redis, tokenExchangeService, log)
+	handler.SetTokenVersionService(tokenVersions)
+	handler.SetPermissionCatalogService(permissionCatalog)
+	handler.SetBlacklistFilter(blacklistFilter)
+	handler.SetRevocationHub(revocations)
+	handler.SetUserCache(userCache)
+	handler.SetSessionService(sessionService)
 	pb.RegisterAuthServiceServer(grpcServer, handler)
 
+	// Standard health service for Kubernetes and Envoy probes, reflecting database and Redis readiness
+	healthReporter := newHealthReporter(database, redis, grpcConfig.HealthCheckInterval, log)
+	healthpb.RegisterHealthServer(grpcServer, healthReporter.server)
+
 	// Register reflection service only when explicitly enabled (should be disabled in production)
 	if grpcConfig.ReflectionEnabled {
 		reflection.Register(grpcServer)
@@ -88,6 +143,8 @@ func NewServer(
 	return &Server{
 		grpcServer: grpcServer,
 		listener:   lis,
+		drainer:    drainer,
+		health:     healthReporter,
 		logger:     log,
 	}, nil
 }
@@ -98,12 +155,16 @@ func (s *Server) Start() error {
 		"address": s.listener.Addr().String(),
 	})
 
+	go s.health.run()
 	return s.grpcServer.Serve(s.listener)
 }
 
-// Stop gracefully stops the gRPC server
+// Stop gracefully stops the gRPC server: open streams are ended with Unavailable so their
+// clients reconnect elsewhere, connections get GOAWAY, and Stop returns once running calls finish
 func (s *Server) Stop() {
 	s.logger.Info("Stopping gRPC server...")
+	s.health.shutdown()
+	s.drainer.Drain()
 	s.grpcServer.GracefulStop()
 	s.logger.Info("gRPC server stopped")
 }
@@ -111,5 +172,6 @@ func (s *Server) Stop() {
 // ForceStop immediately stops the gRPC server without waiting for active connections
 func (s *Server) ForceStop() {
 	s.logger.Warn("Force stopping gRPC server...")
+	s.health.shutdown()
 	s.grpcServer.Stop()
 }
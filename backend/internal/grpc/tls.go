@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+)
+
+var errClientSPIFFEIDNotAllowed = errors.New("client certificate does not carry an allowed SPIFFE ID")
+
+// tlsFiles holds the server certificate and the client CA pool loaded from disk. They are
+// loaded again on the next handshake after one of the files changes, so certificates
+// rotated in place - by cert-manager or a spiffe-helper writing SVIDs - are served without
+// a restart. A rotation that fails to load keeps the previous certificates.
+type tlsFiles struct {
+	certFile string
+	keyFile  string
+	caFile   string // empty when client certificates are not checked
+
+	mu        sync.Mutex
+	modTimes  [3]time.Time
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newTLSFiles(certFile, keyFile, caFile string) (*tlsFiles, error) {
+	f := &tlsFiles{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := f.load(f.currentModTimes()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// get returns the current certificate and client CA pool, reloading them if a file changed
+func (f *tlsFiles) get() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if modTimes := f.currentModTimes(); modTimes != f.modTimes {
+		// The error is not fatal: the files may be half written, the next handshake retries
+		_ = f.load(modTimes)
+	}
+	return f.cert, f.clientCAs
+}
+
+func (f *tlsFiles) currentModTimes() [3]time.Time {
+	var modTimes [3]time.Time
+	for i, name := range []string{f.certFile, f.keyFile, f.caFile} {
+		if name == "" {
+			continue
+		}
+		if info, err := os.Stat(name); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func (f *tlsFiles) load(modTimes [3]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if f.caFile != "" {
+		pem, err := os.ReadFile(f.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates in client CA file")
+		}
+	}
+
+	f.cert, f.clientCAs, f.modTimes = &cert, clientCAs, modTimes
+	return nil
+}
+
+// serverTLSConfig builds the TLS configuration of the gRPC listener from grpcConfig.
+// With a client auth mode other than none, clients present certificates signed by the
+// client CA; with SPIFFE IDs set, the certificate must also carry one of them as a URI SAN.
+// Client certificates authenticate the connection only: calls still need an API key or an
+// application secret.
+func serverTLSConfig(grpcConfig *config.GRPCConfig) (*tls.Config, error) {
+	mode := grpcConfig.ClientAuthMode()
+
+	caFile := ""
+	clientAuth := tls.NoClientCert
+	switch mode {
+	case "optional":
+		caFile, clientAuth = grpcConfig.TLSClientCA, tls.VerifyClientCertIfGiven
+	case "require":
+		caFile, clientAuth = grpcConfig.TLSClientCA, tls.RequireAndVerifyClientCert
+	}
+
+	files, err := newTLSFiles(grpcConfig.TLSCert, grpcConfig.TLSKey, caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifyPeer func([][]byte, [][]*x509.Certificate) error
+	if len(grpcConfig.TLSClientSPIFFEIDs) > 0 {
+		verifyPeer = verifyClientSPIFFEID(grpcConfig.TLSClientSPIFFEIDs)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := files.get()
+			return &tls.Config{
+				MinVersion:            tls.VersionTLS12,
+				Certificates:          []tls.Certificate{*cert},
+				ClientAuth:            clientAuth,
+				ClientCAs:             clientCAs,
+				VerifyPeerCertificate: verifyPeer,
+				NextProtos:            []string{"h2"},
+			}, nil
+		},
+	}, nil
+}
+
+// verifyClientSPIFFEID accepts a verified client certificate only when one of its URI SANs
+// is in allowed. Connections without a client certificate are left to the client auth mode.
+func verifyClientSPIFFEID(allowed []string) func([][]byte, [][]*x509.Certificate) error {
+	ids := make(map[string]struct{}, len(allowed))
+	for _, id := range allowed {
+		ids[id] = struct{}{}
+	}
+
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+			return nil
+		}
+		for _, uri := range verifiedChains[0][0].URIs {
+			if _, ok := ids[uri.String()]; ok {
+				return nil
+			}
+		}
+		return errClientSPIFFEIDNotAllowed
+	}
+}
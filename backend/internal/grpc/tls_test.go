@@ -0,0 +1,253 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+)
+
+// testCA signs the certificates of a TLS test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a leaf certificate for name, with spiffeID as URI SAN when it is set
+func (ca *testCA) issue(t *testing.T, name, spiffeID string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if spiffeID != "" {
+		uri, err := url.Parse(spiffeID)
+		require.NoError(t, err)
+		template.URIs = []*url.URL{uri}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	)
+	require.NoError(t, err)
+	return cert
+}
+
+// writeCert writes cert and its key as PEM files, setting their modification time to modTime
+func writeCert(t *testing.T, cert tls.Certificate, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+// tlsTestSetup is a server CA and a client CA with the server certificate on disk
+type tlsTestSetup struct {
+	serverCA *testCA
+	clientCA *testCA
+	cfg      config.GRPCConfig
+}
+
+func newTLSTestSetup(t *testing.T) *tlsTestSetup {
+	t.Helper()
+	dir := t.TempDir()
+	s := &tlsTestSetup{serverCA: newTestCA(t), clientCA: newTestCA(t)}
+	s.cfg = config.GRPCConfig{
+		TLSEnabled:  true,
+		TLSCert:     filepath.Join(dir, "server.crt"),
+		TLSKey:      filepath.Join(dir, "server.key"),
+		TLSClientCA: filepath.Join(dir, "client-ca.crt"),
+	}
+	writeCert(t, s.serverCA.issue(t, "auth-gateway", ""), s.cfg.TLSCert, s.cfg.TLSKey, time.Now())
+	require.NoError(t, os.WriteFile(s.cfg.TLSClientCA, s.clientCA.pem, 0o600))
+	return s
+}
+
+// handshake connects a client presenting clientCert (none when nil) to a server using
+// serverConfig, returning the certificate the server presented and the handshake error
+func (s *tlsTestSetup) handshake(t *testing.T, serverConfig *tls.Config, clientCert *tls.Certificate) (*x509.Certificate, error) {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(s.serverCA.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "auth-gateway", NextProtos: []string{"h2"}}
+	if clientCert != nil {
+		clientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn := tls.Server(serverConn, serverConfig)
+		err := conn.Handshake()
+		if err == nil {
+			// TLS 1.3 reports a rejected client certificate on the first read
+			_, err = conn.Write([]byte{0})
+		}
+		serverErr <- err
+		serverConn.Close()
+	}()
+
+	client := tls.Client(clientConn, clientConfig)
+	err := client.Handshake()
+	if err == nil {
+		_, err = client.Read(make([]byte, 1))
+	}
+	if sErr := <-serverErr; err == nil {
+		err = sErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return client.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestServerTLSConfig_ShouldRequireClientCertificateWhenClientCAIsSet(t *testing.T) {
+	s := newTLSTestSetup(t)
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	_, err = s.handshake(t, tlsConfig, nil)
+	assert.Error(t, err)
+
+	clientCert := s.clientCA.issue(t, "billing", "")
+	_, err = s.handshake(t, tlsConfig, &clientCert)
+	assert.NoError(t, err)
+}
+
+func TestServerTLSConfig_ShouldRejectClientCertificateOfUntrustedCA(t *testing.T) {
+	s := newTLSTestSetup(t)
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	clientCert := newTestCA(t).issue(t, "billing", "")
+	_, err = s.handshake(t, tlsConfig, &clientCert)
+	assert.Error(t, err)
+}
+
+func TestServerTLSConfig_ShouldAllowMissingClientCertificateWhenOptional(t *testing.T) {
+	s := newTLSTestSetup(t)
+	s.cfg.TLSClientAuth = "optional"
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	_, err = s.handshake(t, tlsConfig, nil)
+	assert.NoError(t, err)
+
+	untrusted := newTestCA(t).issue(t, "billing", "")
+	_, err = s.handshake(t, tlsConfig, &untrusted)
+	assert.Error(t, err)
+}
+
+func TestServerTLSConfig_ShouldCheckClientSPIFFEID(t *testing.T) {
+	s := newTLSTestSetup(t)
+	s.cfg.TLSClientSPIFFEIDs = []string{"spiffe://example.org/billing"}
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	allowed := s.clientCA.issue(t, "billing", "spiffe://example.org/billing")
+	_, err = s.handshake(t, tlsConfig, &allowed)
+	assert.NoError(t, err)
+
+	other := s.clientCA.issue(t, "fraud", "spiffe://example.org/fraud")
+	_, err = s.handshake(t, tlsConfig, &other)
+	assert.Error(t, err)
+
+	withoutID := s.clientCA.issue(t, "billing", "")
+	_, err = s.handshake(t, tlsConfig, &withoutID)
+	assert.Error(t, err)
+}
+
+func TestServerTLSConfig_ShouldServeRotatedCertificate(t *testing.T) {
+	s := newTLSTestSetup(t)
+	s.cfg.TLSClientCA = ""
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	first, err := s.handshake(t, tlsConfig, nil)
+	require.NoError(t, err)
+
+	rotated := s.serverCA.issue(t, "auth-gateway", "")
+	writeCert(t, rotated, s.cfg.TLSCert, s.cfg.TLSKey, time.Now().Add(time.Minute))
+
+	second, err := s.handshake(t, tlsConfig, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.SerialNumber, second.SerialNumber)
+	assert.Equal(t, rotated.Certificate[0], second.Raw)
+}
+
+func TestServerTLSConfig_ShouldKeepCertificateWhenRotationIsBroken(t *testing.T) {
+	s := newTLSTestSetup(t)
+	tlsConfig, err := serverTLSConfig(&s.cfg)
+	require.NoError(t, err)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.WriteFile(s.cfg.TLSCert, []byte("not a certificate"), 0o600))
+	require.NoError(t, os.Chtimes(s.cfg.TLSCert, later, later))
+
+	clientCert := s.clientCA.issue(t, "billing", "")
+	_, err = s.handshake(t, tlsConfig, &clientCert)
+	assert.NoError(t, err)
+}
+
+func TestServerTLSConfig_ShouldFailOnMissingFiles(t *testing.T) {
+	s := newTLSTestSetup(t)
+	s.cfg.TLSClientCA = filepath.Join(t.TempDir(), "missing.crt")
+
+	_, err := serverTLSConfig(&s.cfg)
+	assert.Error(t, err)
+}
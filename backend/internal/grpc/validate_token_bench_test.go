@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/smilemakc/auth-gateway/proto"
+)
+
+// Allocation budgets for ValidateToken, which every service behind the gateway calls on every
+// request. Most of what remains is inside golang-jwt (header map, claims decoding, HMAC state).
+// Raise these only together with benchmark numbers showing why.
+const (
+	validateTokenAllocBudget        = 48
+	validateInvalidTokenAllocBudget = 8
+)
+
+// newValidateTokenBench returns a handler and a valid request for the JWT path of ValidateToken
+func newValidateTokenBench(tb testing.TB) (*AuthHandlerV2, *pb.ValidateTokenRequest) {
+	jwtSvc := newTestJWTService()
+	token, err := jwtSvc.GenerateAccessToken(newTestUser(uuid.New(), "admin", "user"))
+	require.NoError(tb, err)
+
+	return newTestAuthHandlerV2(jwtSvc), &pb.ValidateTokenRequest{AccessToken: token}
+}
+
+func TestValidateToken_ShouldStayWithinAllocationBudget(t *testing.T) {
+	h, req := newValidateTokenBench(t)
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = h.ValidateToken(ctx, req)
+	})
+	assert.LessOrEqual(t, allocs, float64(validateTokenAllocBudget), "valid JWT")
+
+	invalid := &pb.ValidateTokenRequest{AccessToken: "not-a-jwt"}
+	allocs = testing.AllocsPerRun(100, func() {
+		_, _ = h.ValidateToken(ctx, invalid)
+	})
+	assert.LessOrEqual(t, allocs, float64(validateInvalidTokenAllocBudget), "invalid JWT")
+}
+
+func BenchmarkValidateToken_JWT(b *testing.B) {
+	h, req := newValidateTokenBench(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if resp, _ := h.ValidateToken(ctx, req); !resp.Valid {
+			b.Fatal(resp.ErrorMessage)
+		}
+	}
+}
+
+func BenchmarkValidateToken_InvalidJWT(b *testing.B) {
+	h, _ := newValidateTokenBench(b)
+	ctx := context.Background()
+	req := &pb.ValidateTokenRequest{AccessToken: "not-a-jwt"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if resp, _ := h.ValidateToken(ctx, req); resp.Valid {
+			b.Fatal("expected invalid token")
+		}
+	}
+}
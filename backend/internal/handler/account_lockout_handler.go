@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// AccountLockoutHandler handles accounts locked after failed sign-ins (admin only)
+type AccountLockoutHandler struct {
+	lockoutService service.AccountLockoutServicer
+	auditService   service.AuditServicer
+	logger         *logger.Logger
+}
+
+// NewAccountLockoutHandler creates a new account lockout handler
+func NewAccountLockoutHandler(lockoutService service.AccountLockoutServicer, auditService service.AuditServicer, logger *logger.Logger) *AccountLockoutHandler {
+	return &AccountLockoutHandler{
+		lockoutService: lockoutService,
+		auditService:   auditService,
+		logger:         logger,
+	}
+}
+
+// ListLocked lists locked accounts
+// @Summary List locked accounts
+// @Description List the accounts currently locked after too many failed sign-ins, latest unlock first (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Success 200 {object} models.LockedAccountListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/locked [get]
+func (h *AccountLockoutHandler) ListLocked(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c)
+
+	response, err := h.lockoutService.ListLocked(c.Request.Context(), page, pageSize)
+	if err != nil {
+		h.logger.Error("Failed to list locked accounts", map[string]interface{}{
+			"error": err.Error(),
+		})
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLockout returns the lockout state of a user
+// @Summary Get user lockout
+// @Description Get the recent failed sign-ins, consecutive lockouts and current lock of a user (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} models.AccountLockout
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No failed sign-ins recorded"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/lockout [get]
+func (h *AccountLockoutHandler) GetLockout(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	lockout, err := h.lockoutService.GetLockout(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lockout)
+}
+
+// Unlock unlocks a user
+// @Summary Unlock user
+// @Description End the lock of a user and clear their failed sign-ins, so the next lockout starts again at the base duration (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No failed sign-ins recorded"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/lockout [delete]
+func (h *AccountLockoutHandler) Unlock(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	lockout, err := h.lockoutService.Unlock(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logger.Info("Account unlocked", map[string]interface{}{
+		"user_id":  userID.String(),
+		"admin_id": adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionAccountUnlocked,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target_user_id":  userID.String(),
+			"was_locked":      lockout.IsLocked(),
+			"failed_attempts": lockout.FailedAttempts,
+			"lockout_count":   lockout.LockoutCount,
+		},
+	})
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Account unlocked"})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAccountLockoutService implements service.AccountLockoutServicer
+type mockAccountLockoutService struct {
+	lockout   *models.AccountLockout
+	unlockErr error
+}
+
+func (m *mockAccountLockoutService) ListLocked(_ context.Context, page, pageSize int) (*models.LockedAccountListResponse, error) {
+	return &models.LockedAccountListResponse{Accounts: []*models.LockedAccount{}, Page: page, PageSize: pageSize}, nil
+}
+func (m *mockAccountLockoutService) GetLockout(_ context.Context, _ uuid.UUID) (*models.AccountLockout, error) {
+	return m.lockout, nil
+}
+func (m *mockAccountLockoutService) Unlock(_ context.Context, _ uuid.UUID) (*models.AccountLockout, error) {
+	if m.unlockErr != nil {
+		return nil, m.unlockErr
+	}
+	return m.lockout, nil
+}
+
+func setupAccountLockoutHandler(adminID uuid.UUID) (*mockAccountLockoutService, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	until := time.Now().Add(time.Hour)
+	svc := &mockAccountLockoutService{lockout: &models.AccountLockout{ID: uuid.New(), LockoutCount: 2, LockedUntil: &until}}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	h := NewAccountLockoutHandler(svc, audit, testLogger())
+	r.DELETE("/admin/users/:id/lockout", h.Unlock)
+	return svc, audit, r
+}
+
+func TestAccountLockoutHandler_Unlock_ShouldAudit(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	_, audit, r := setupAccountLockoutHandler(adminID)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/users/"+userID.String()+"/lockout", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionAccountUnlocked, audit.Logged[0].Action)
+	assert.Equal(t, adminID, *audit.Logged[0].UserID)
+	assert.Equal(t, userID.String(), audit.Logged[0].Details["target_user_id"])
+	assert.Equal(t, true, audit.Logged[0].Details["was_locked"])
+}
+
+func TestAccountLockoutHandler_Unlock_ShouldNotAudit_WhenNotFound(t *testing.T) {
+	svc, audit, r := setupAccountLockoutHandler(uuid.New())
+	svc.unlockErr = models.NewAppError(http.StatusNotFound, "No failed sign-ins recorded for this user")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/users/"+uuid.NewString()+"/lockout", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, audit.Logged)
+}
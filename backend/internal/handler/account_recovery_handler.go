@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// AccountRecoveryHandler handles recovery of accounts that lost both password and 2FA
+type AccountRecoveryHandler struct {
+	recoveryService service.AccountRecoveryServicer
+	auditService    service.AuditServicer
+	logger          *logger.Logger
+}
+
+// NewAccountRecoveryHandler creates a new account recovery handler
+func NewAccountRecoveryHandler(recoveryService service.AccountRecoveryServicer, auditService service.AuditServicer, logger *logger.Logger) *AccountRecoveryHandler {
+	return &AccountRecoveryHandler{
+		recoveryService: recoveryService,
+		auditService:    auditService,
+		logger:          logger,
+	}
+}
+
+// Start opens an account recovery request
+// @Summary Start account recovery
+// @Description Start recovering an account whose password and second factor are both lost. Codes are sent to the account's verified secondary email and phone, and all contact points are notified. The response is the same whether or not the account exists.
+// @Tags Account Recovery
+// @Accept json
+// @Produce json
+// @Param request body models.StartAccountRecoveryRequest true "Account email and reason"
+// @Success 202 {object} models.StartAccountRecoveryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery/start [post]
+func (h *AccountRecoveryHandler) Start(c *gin.Context) {
+	var req models.StartAccountRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	response, err := h.recoveryService.Start(c.Request.Context(), &req, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		h.respondWithError(c, "Failed to start account recovery", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// VerifyStep completes a verification step of a recovery request
+// @Summary Verify account recovery step
+// @Description Submit the code sent to the secondary email or phone. Too many wrong codes cancel the request.
+// @Tags Account Recovery
+// @Accept json
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Param request body models.VerifyAccountRecoveryStepRequest true "Client secret, step and code"
+// @Success 200 {object} models.AccountRecoveryStatusResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery/{id}/verify [post]
+func (h *AccountRecoveryHandler) VerifyStep(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.VerifyAccountRecoveryStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	response, err := h.recoveryService.VerifyStep(c.Request.Context(), id, &req, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		h.respondWithError(c, "Failed to verify account recovery step", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStatus reports the progress of a recovery request
+// @Summary Get account recovery status
+// @Description Report the status and the required and completed steps of a recovery request
+// @Tags Account Recovery
+// @Accept json
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Param request body models.AccountRecoverySecretRequest true "Client secret"
+// @Success 200 {object} models.AccountRecoveryStatusResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery/{id}/status [post]
+func (h *AccountRecoveryHandler) GetStatus(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.AccountRecoverySecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	response, err := h.recoveryService.GetStatus(c.Request.Context(), id, req.ClientSecret)
+	if err != nil {
+		h.respondWithError(c, "Failed to get account recovery status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Complete sets a new password on an approved recovery request
+// @Summary Complete account recovery
+// @Description Set a new password once all steps are complete. Two-factor authentication is reset, all sessions are revoked and all contact points are notified.
+// @Tags Account Recovery
+// @Accept json
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Param request body models.CompleteAccountRecoveryRequest true "Client secret and new password"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery/{id}/complete [post]
+func (h *AccountRecoveryHandler) Complete(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CompleteAccountRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	if err := h.recoveryService.Complete(c.Request.Context(), id, &req, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		h.respondWithError(c, "Failed to complete account recovery", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Account recovered. Sign in with your new password and set up two-factor authentication again."})
+}
+
+// ListRequests lists account recovery requests for review
+// @Summary List account recovery requests
+// @Description List account recovery requests, newest first, optionally filtered by status (admin only)
+// @Tags Admin - Account Recovery
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status" Enums(pending_verification, pending_review, approved, rejected, completed, cancelled)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Success 200 {object} models.AccountRecoveryListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/recovery-requests [get]
+func (h *AccountRecoveryHandler) ListRequests(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c)
+
+	response, err := h.recoveryService.List(c.Request.Context(), c.Query("status"), page, pageSize)
+	if err != nil {
+		h.respondWithError(c, "Failed to list account recovery requests", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRequest returns an account recovery request
+// @Summary Get account recovery request
+// @Description Get an account recovery request with the account being recovered (admin only)
+// @Tags Admin - Account Recovery
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Success 200 {object} models.AccountRecoveryRequest
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/recovery-requests/{id} [get]
+func (h *AccountRecoveryHandler) GetRequest(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	recovery, err := h.recoveryService.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, "Failed to get account recovery request", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recovery)
+}
+
+// ApproveRequest approves a recovery request awaiting review
+// @Summary Approve account recovery request
+// @Description Approve a recovery request whose verification steps are complete, allowing the requester to set a new password (admin only)
+// @Tags Admin - Account Recovery
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Param request body models.ReviewAccountRecoveryRequest false "Review note"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/recovery-requests/{id}/approve [post]
+func (h *AccountRecoveryHandler) ApproveRequest(c *gin.Context) {
+	h.review(c, models.ActionAccountRecoveryApprove, "Recovery request approved", h.recoveryService.Approve)
+}
+
+// RejectRequest rejects an active recovery request
+// @Summary Reject account recovery request
+// @Description Reject a recovery request that has not completed yet (admin only)
+// @Tags Admin - Account Recovery
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recovery request ID (UUID)"
+// @Param request body models.ReviewAccountRecoveryRequest false "Review note"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/recovery-requests/{id}/reject [post]
+func (h *AccountRecoveryHandler) RejectRequest(c *gin.Context) {
+	h.review(c, models.ActionAccountRecoveryReject, "Recovery request rejected", h.recoveryService.Reject)
+}
+
+func (h *AccountRecoveryHandler) review(c *gin.Context, action models.AuditAction, message string, decide func(ctx context.Context, id, adminID uuid.UUID, note string) error) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReviewAccountRecoveryRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+	}
+
+	if err := decide(c.Request.Context(), id, adminID, req.Note); err != nil {
+		h.respondWithError(c, "Failed to review account recovery request", err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    action,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"request_id": id.String(),
+			"note":       req.Note,
+		},
+	})
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: message})
+}
+
+func (h *AccountRecoveryHandler) respondWithError(c *gin.Context, message string, err error) {
+	if _, ok := err.(*models.AppError); !ok {
+		h.logger.Error(message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.RespondWithError(c, err)
+}
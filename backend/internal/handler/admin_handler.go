@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -59,22 +60,36 @@ func (h *AdminHandler) GetStats(c *gin.Context) {
 
 // ListUsers returns paginated list of users
 // @Summary List all users
-// @Description Get paginated list of all users (admin only)
+// @Description Get the users matching the filters, newest first (admin only). The search matches username, full name and any of the user's email addresses. Admins of an application only see its users. Pass next_cursor back as cursor to page through large results without counting them.
 // @Tags Admin - Users
 // @Security BearerAuth
 // @Produce json
-// @Param page query int false "Page number" default(1)
+// @Param search query string false "Search by username, full name or email"
+// @Param role query string false "Filter by role name; comma-separated for several"
+// @Param status query string false "Filter by state (invited, active, suspended, deactivated, deleted); comma-separated for several"
+// @Param email_verified query bool false "Filter by whether the email is verified"
+// @Param two_factor query bool false "Filter by whether TOTP or a security key is enabled"
+// @Param created_from query string false "Created at or after (RFC 3339)"
+// @Param created_to query string false "Created before (RFC 3339)"
+// @Param metadata[key] query string false "Filter by an application profile metadata attribute, e.g. metadata[plan]=pro"
+// @Param cursor query string false "Continue after the page that returned this next_cursor"
+// @Param page query int false "Page number, ignored with cursor" default(1)
 // @Param page_size query int false "Page size" default(20)
 // @Success 200 {object} models.AdminUserListResponse
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/admin/users [get]
 func (h *AdminHandler) ListUsers(c *gin.Context) {
 	page, pageSize := utils.ParsePagination(c)
-	appID, _ := utils.GetApplicationIDFromContext(c)
 
-	response, err := h.adminService.ListUsers(c.Request.Context(), appID, page, pageSize)
+	opts, ok := parseUserFilters(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.adminService.SearchUsers(c.Request.Context(), page, pageSize, opts...)
 	if err != nil {
 		h.logger.Error("Failed to list users", map[string]interface{}{
 			"error": err.Error(),
@@ -86,6 +101,96 @@ func (h *AdminHandler) ListUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// parseUserFilters reads the user filters and cursor of the query; admins of an application
+// only see its users. It responds with 400 when a filter is invalid.
+func parseUserFilters(c *gin.Context) ([]service.UserFilterOption, bool) {
+	var opts []service.UserFilterOption
+	badRequest := func(message string) ([]service.UserFilterOption, bool) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, message),
+		))
+		return nil, false
+	}
+
+	if appID, _ := utils.GetApplicationIDFromContext(c); appID != nil {
+		opts = append(opts, service.UserFilterApp(*appID))
+	}
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		opts = append(opts, service.UserFilterSearch(search))
+	}
+	if roles := splitQueryList(c.Query("role")); len(roles) > 0 {
+		opts = append(opts, service.UserFilterRoles(roles...))
+	}
+	if states := splitQueryList(c.Query("status")); len(states) > 0 {
+		for _, state := range states {
+			if !models.UserState(state).IsValid() {
+				return badRequest("Invalid status " + state)
+			}
+		}
+		opts = append(opts, service.UserFilterStates(states...))
+	}
+
+	for _, param := range []string{"email_verified", "two_factor"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return badRequest("Invalid " + param + ", expected true or false")
+		}
+		if param == "email_verified" {
+			opts = append(opts, service.UserFilterEmailVerified(v))
+		} else {
+			opts = append(opts, service.UserFilterTwoFactor(v))
+		}
+	}
+
+	for _, param := range []string{"created_from", "created_to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return badRequest("Invalid " + param + " time, expected RFC 3339")
+		}
+		if param == "created_from" {
+			opts = append(opts, service.UserFilterCreatedFrom(t))
+		} else {
+			opts = append(opts, service.UserFilterCreatedTo(t))
+		}
+	}
+
+	for key, value := range c.QueryMap("metadata") {
+		if key == "" {
+			return badRequest("Invalid metadata filter, expected metadata[key]=value")
+		}
+		opts = append(opts, service.UserFilterMetadata(key, value))
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		createdAt, id, err := models.DecodeUserCursor(cursor)
+		if err != nil {
+			return badRequest("Invalid cursor")
+		}
+		opts = append(opts, service.UserFilterAfter(createdAt, id))
+	}
+
+	return opts, true
+}
+
+// splitQueryList splits a comma-separated query parameter, dropping empty items
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // GetUser returns detailed user information
 // @Summary Get user details
 // @Description Get detailed information about a specific user (admin only)
@@ -176,6 +281,12 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 		))
 		return
 	}
+	if req.RoleIDs != nil && !requireRoleAssignment(c) {
+		return
+	}
+	if (req.Email != nil || req.EmailVerified != nil) && !requireEmailChange(c) {
+		return
+	}
 
 	adminID, ok := utils.MustGetUserID(c)
 	if !ok {
@@ -214,6 +325,9 @@ func (h *AdminHandler) CreateUser(c *gin.Context) {
 		))
 		return
 	}
+	if len(req.RoleIDs) > 0 && !requireRoleAssignment(c) {
+		return
+	}
 
 	adminID, ok := utils.MustGetUserID(c)
 	if !ok {
@@ -249,7 +363,12 @@ func (h *AdminHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.adminService.DeleteUser(c.Request.Context(), userID); err != nil {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeleteUser(c.Request.Context(), userID, adminID); err != nil {
 		utils.RespondWithError(c, err)
 		return
 	}
@@ -257,6 +376,51 @@ func (h *AdminHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, models.MessageResponse{Message: "User deleted successfully"})
 }
 
+// UpdateUserState moves a user to another lifecycle state
+// @Summary Change user state
+// @Description Suspend, deactivate, reactivate or delete a user (admin only). Suspending, deactivating or deleting revokes all of the user's tokens and sessions.
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body models.UpdateUserStateRequest true "Target state"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/state [post]
+func (h *AdminHandler) UpdateUserState(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.UpdateUserState(c.Request.Context(), userID, &req, adminID, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
 // ListAPIKeys returns all API keys
 // @Summary List all API keys
 // @Description Get list of all API keys (admin only)
@@ -316,14 +480,21 @@ func (h *AdminHandler) RevokeAPIKey(c *gin.Context) {
 
 // ListAuditLogs returns audit logs
 // @Summary List audit logs
-// @Description Get paginated audit logs (admin only)
+// @Description Get paginated audit logs matching the filters, newest first (admin only)
 // @Tags Admin - Audit Logs
 // @Security BearerAuth
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(50)
 // @Param user_id query string false "Filter by user ID (UUID)"
-// @Success 200 {array} models.AdminAuditLogResponse
+// @Param action query string false "Filter by action; comma-separated for several"
+// @Param status query string false "Filter by status (success, failed, blocked)"
+// @Param ip query string false "Filter by IP address"
+// @Param country query string false "Filter by country code"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param q query string false "Text in the action, resource, IP address, user agent, city or details"
+// @Success 200 {object} models.AuditLogListResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
@@ -332,66 +503,150 @@ func (h *AdminHandler) RevokeAPIKey(c *gin.Context) {
 func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
 	page, pageSize := utils.ParsePagination(c, 50)
 
-	var userID *uuid.UUID
+	opts, ok := parseAuditLogFilters(c)
+	if !ok {
+		return
+	}
+
+	logs, err := h.adminService.ListAuditLogs(c.Request.Context(), page, pageSize, opts...)
+	if err != nil {
+		h.logger.Error("Failed to list audit logs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// GetAuditActionStats returns the most frequent audit actions
+// @Summary Top audit actions
+// @Description Count the audit logs matching the filters per action, most frequent first, with how many did not succeed. Without from/to the last 24 hours are counted; the range may span 31 days (admin only)
+// @Tags Admin - Audit Logs
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of actions" default(10)
+// @Param user_id query string false "Filter by user ID (UUID)"
+// @Param action query string false "Filter by action; comma-separated for several"
+// @Param status query string false "Filter by status (success, failed, blocked)"
+// @Param ip query string false "Filter by IP address"
+// @Param country query string false "Filter by country code"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param q query string false "Text in the action, resource, IP address, user agent, city or details"
+// @Success 200 {object} models.AuditActionStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/audit-logs/stats/actions [get]
+func (h *AdminHandler) GetAuditActionStats(c *gin.Context) {
+	opts, ok := parseAuditLogFilters(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	stats, err := h.adminService.GetAuditActionStats(c.Request.Context(), limit, opts...)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetAuditFailureStats returns unsuccessful audit actions per hour
+// @Summary Audit failures per hour
+// @Description Count the unsuccessful audit logs matching the filters per hour, listing every hour of the range. Without from/to the last 24 hours are counted; the range may span 31 days (admin only)
+// @Tags Admin - Audit Logs
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query string false "Filter by user ID (UUID)"
+// @Param action query string false "Filter by action; comma-separated for several"
+// @Param ip query string false "Filter by IP address"
+// @Param country query string false "Filter by country code"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param q query string false "Text in the action, resource, IP address, user agent, city or details"
+// @Success 200 {object} models.AuditFailureStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/audit-logs/stats/failures [get]
+func (h *AdminHandler) GetAuditFailureStats(c *gin.Context) {
+	opts, ok := parseAuditLogFilters(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.adminService.GetAuditFailureStats(c.Request.Context(), opts...)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// parseAuditLogFilters reads the audit log filters of the query; admins of an application
+// only see its logs. It responds with 400 when a filter is invalid.
+func parseAuditLogFilters(c *gin.Context) ([]service.AuditLogListOption, bool) {
+	var opts []service.AuditLogListOption
+
+	if appID, _ := utils.GetApplicationIDFromContext(c); appID != nil {
+		opts = append(opts, service.AuditLogListApp(*appID))
+	}
+
 	if userIDStr := c.Query("user_id"); userIDStr != "" {
 		id, err := uuid.Parse(userIDStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
 				models.NewAppError(http.StatusBadRequest, "Invalid user ID"),
 			))
-			return
+			return nil, false
 		}
-		userID = &id
+		opts = append(opts, service.AuditLogListUser(id))
 	}
 
-	appID, _ := utils.GetApplicationIDFromContext(c)
-	if appID != nil {
-		offset := (page - 1) * pageSize
-		rawLogs, total, err := h.auditService.ListByApp(c.Request.Context(), *appID, pageSize, offset)
+	if actions := splitQueryList(c.Query("action")); len(actions) > 0 {
+		opts = append(opts, service.AuditLogListActions(actions...))
+	}
+	if status := c.Query("status"); status != "" {
+		opts = append(opts, service.AuditLogListStatus(status))
+	}
+	if ip := c.Query("ip"); ip != "" {
+		opts = append(opts, service.AuditLogListIP(ip))
+	}
+	if country := c.Query("country"); country != "" {
+		opts = append(opts, service.AuditLogListCountry(country))
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		opts = append(opts, service.AuditLogListSearch(q))
+	}
+
+	for _, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
 		if err != nil {
-			h.logger.Error("Failed to list audit logs", map[string]interface{}{
-				"error": err.Error(),
-			})
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
-			return
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid "+param+" time, expected RFC 3339"),
+			))
+			return nil, false
 		}
-		adminLogs := make([]*models.AdminAuditLogResponse, 0, len(rawLogs))
-		for _, log := range rawLogs {
-			resp := &models.AdminAuditLogResponse{
-				ID:        log.ID,
-				UserID:    log.UserID,
-				Action:    string(log.Action),
-				Status:    string(log.Status),
-				IP:        log.IPAddress,
-				UserAgent: log.UserAgent,
-				CreatedAt: log.CreatedAt,
-			}
-			if log.User != nil {
-				resp.UserEmail = log.User.Email
-			}
-			adminLogs = append(adminLogs, resp)
+		if param == "from" {
+			opts = append(opts, service.AuditLogListFrom(t))
+		} else {
+			opts = append(opts, service.AuditLogListTo(t))
 		}
-		totalPages := (total + pageSize - 1) / pageSize
-		c.JSON(http.StatusOK, models.AuditLogListResponse{
-			Logs:       adminLogs,
-			Total:      total,
-			Page:       page,
-			PageSize:   pageSize,
-			TotalPages: totalPages,
-		})
-		return
-	}
-
-	logs, err := h.adminService.ListAuditLogs(c.Request.Context(), page, pageSize, userID)
-	if err != nil {
-		h.logger.Error("Failed to list audit logs", map[string]interface{}{
-			"error": err.Error(),
-		})
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
-		return
 	}
 
-	c.JSON(http.StatusOK, logs)
+	return opts, true
 }
 
 // AssignRole assigns a role to a user
@@ -674,3 +929,29 @@ func (h *AdminHandler) ImportUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// requireRoleAssignment rejects delegated admins, who may manage users but not their roles:
+// otherwise users:manage could grant the admin role. It responds and returns false when
+// the request is rejected.
+func requireRoleAssignment(c *gin.Context) bool {
+	if utils.IsDelegatedAdmin(c) {
+		c.JSON(http.StatusForbidden, models.NewErrorResponse(
+			models.NewAppError(http.StatusForbidden, "Role assignment requires admin access"),
+		))
+		return false
+	}
+	return true
+}
+
+// requireEmailChange rejects delegated admins changing a user's email or its verification:
+// otherwise users:manage could point an account at their own mailbox and reset its password.
+// It responds and returns false when the request is rejected.
+func requireEmailChange(c *gin.Context) bool {
+	if utils.IsDelegatedAdmin(c) {
+		c.JSON(http.StatusForbidden, models.NewErrorResponse(
+			models.NewAppError(http.StatusForbidden, "Changing a user's email requires admin access"),
+		))
+		return false
+	}
+	return true
+}
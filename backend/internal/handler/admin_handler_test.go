@@ -158,12 +158,12 @@ func TestAdminHandler_ListUsers_ShouldReturn200_WhenUsersExist(t *testing.T) {
 	fix := setupAdminTestFixture()
 
 	userID := uuid.New()
-	fix.userStore.ListFunc = func(opts ...service.UserListOption) ([]*models.User, error) {
+	fix.userStore.FilterFunc = func(limit, offset int, o service.UserFilterOptions) ([]*models.User, error) {
 		return []*models.User{
 			{ID: userID, Email: "admin@test.com", Username: "admin", IsActive: true},
 		}, nil
 	}
-	fix.userStore.CountFunc = func(isActive *bool) (int, error) {
+	fix.userStore.CountFilteredFunc = func(o service.UserFilterOptions) (int, error) {
 		return 1, nil
 	}
 
@@ -188,7 +188,7 @@ func TestAdminHandler_ListUsers_ShouldReturn500_WhenServiceFails(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	fix := setupAdminTestFixture()
 
-	fix.userStore.ListFunc = func(opts ...service.UserListOption) ([]*models.User, error) {
+	fix.userStore.FilterFunc = func(limit, offset int, o service.UserFilterOptions) ([]*models.User, error) {
 		return nil, fmt.Errorf("database error")
 	}
 
@@ -202,6 +202,105 @@ func TestAdminHandler_ListUsers_ShouldReturn500_WhenServiceFails(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestAdminHandler_ListUsers_ShouldPassFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	var got service.UserFilterOptions
+	fix.userStore.FilterFunc = func(limit, offset int, o service.UserFilterOptions) ([]*models.User, error) {
+		got = o
+		return nil, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/admin/users", fix.handler.ListUsers)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users?search=jane&role=admin,editor&status=active&email_verified=true&two_factor=false&created_from=2024-01-01T00:00:00Z&metadata[plan]=pro", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jane", got.Search)
+	assert.Equal(t, []string{"admin", "editor"}, got.Roles)
+	assert.Equal(t, []string{"active"}, got.States)
+	require.NotNil(t, got.EmailVerified)
+	assert.True(t, *got.EmailVerified)
+	require.NotNil(t, got.TwoFactor)
+	assert.False(t, *got.TwoFactor)
+	require.NotNil(t, got.CreatedFrom)
+	assert.Equal(t, map[string]string{"plan": "pro"}, got.Metadata)
+	assert.Nil(t, got.AfterCreatedAt)
+}
+
+func TestAdminHandler_ListUsers_ShouldReturn400_WhenFilterInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"status=banned", "email_verified=maybe", "created_to=yesterday", "cursor=not-a-cursor"} {
+		fix := setupAdminTestFixture()
+		w := httptest.NewRecorder()
+		r := gin.New()
+		r.GET("/admin/users", fix.handler.ListUsers)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/users?"+query, nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestAdminHandler_ListUsers_ShouldPageByCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	users := []*models.User{
+		{ID: uuid.New(), Email: "c@test.com", CreatedAt: now},
+		{ID: uuid.New(), Email: "b@test.com", CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), Email: "a@test.com", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	fix.userStore.FilterFunc = func(limit, offset int, o service.UserFilterOptions) ([]*models.User, error) {
+		assert.Zero(t, offset)
+		var page []*models.User
+		for _, user := range users {
+			if o.AfterCreatedAt == nil || user.CreatedAt.Before(*o.AfterCreatedAt) {
+				page = append(page, user)
+			}
+		}
+		if len(page) > limit {
+			page = page[:limit]
+		}
+		return page, nil
+	}
+	counted := 0
+	fix.userStore.CountFilteredFunc = func(o service.UserFilterOptions) (int, error) {
+		counted++
+		return len(users), nil
+	}
+
+	r := gin.New()
+	r.GET("/admin/users", fix.handler.ListUsers)
+	list := func(cursor string) models.AdminUserListResponse {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users?page_size=2&cursor="+cursor, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.AdminUserListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	first := list("")
+	require.Len(t, first.Users, 2)
+	assert.Equal(t, 3, first.Total)
+	require.NotEmpty(t, first.NextCursor)
+
+	second := list(first.NextCursor)
+	require.Len(t, second.Users, 1)
+	assert.Equal(t, "a@test.com", second.Users[0].Email)
+	assert.Empty(t, second.NextCursor)
+	assert.Zero(t, second.Total)
+	assert.Equal(t, 1, counted, "listing by cursor should not count users")
+}
+
 // ---------------------------------------------------------------------------
 // GetUser Tests
 // ---------------------------------------------------------------------------
@@ -588,6 +687,59 @@ func TestAdminHandler_UpdateUser_ShouldReturn404_WhenUserNotFound(t *testing.T)
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestAdminHandler_UpdateUser_ShouldReturn403_WhenDelegatedAdminAssignsRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	fix.userStore.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+		t.Fatal("user must not be loaded when role assignment is refused")
+		return nil, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.PUT("/admin/users/:id", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		c.Set(utils.DelegatedAdminKey, true)
+		fix.handler.UpdateUser(c)
+	})
+
+	body := `{"role_ids":["` + uuid.New().String() + `"]}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/users/"+uuid.New().String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Role assignment requires admin access")
+}
+
+func TestAdminHandler_UpdateUser_ShouldReturn403_WhenDelegatedAdminChangesEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, body := range []string{`{"email":"attacker@example.com"}`, `{"email_verified":true}`} {
+		fix := setupAdminTestFixture()
+		fix.userStore.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+			t.Fatal("user must not be loaded when the email change is refused")
+			return nil, nil
+		}
+
+		w := httptest.NewRecorder()
+		r := gin.New()
+		r.PUT("/admin/users/:id", func(c *gin.Context) {
+			c.Set(utils.UserIDKey, uuid.New())
+			c.Set(utils.DelegatedAdminKey, true)
+			fix.handler.UpdateUser(c)
+		})
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/users/"+uuid.New().String(), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, body)
+		assert.Contains(t, w.Body.String(), "Changing a user's email requires admin access")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // DeleteUser Tests
 // ---------------------------------------------------------------------------
@@ -598,7 +750,10 @@ func TestAdminHandler_DeleteUser_ShouldReturn400_WhenIDInvalid(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	r := gin.New()
-	r.DELETE("/admin/users/:id", fix.handler.DeleteUser)
+	r.DELETE("/admin/users/:id", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		fix.handler.DeleteUser(c)
+	})
 
 	req := httptest.NewRequest(http.MethodDelete, "/admin/users/not-uuid", nil)
 	r.ServeHTTP(w, req)
@@ -617,7 +772,10 @@ func TestAdminHandler_DeleteUser_ShouldReturn200_WhenUserExists(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	r := gin.New()
-	r.DELETE("/admin/users/:id", fix.handler.DeleteUser)
+	r.DELETE("/admin/users/:id", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		fix.handler.DeleteUser(c)
+	})
 
 	req := httptest.NewRequest(http.MethodDelete, "/admin/users/"+userID.String(), nil)
 	r.ServeHTTP(w, req)
@@ -640,7 +798,10 @@ func TestAdminHandler_DeleteUser_ShouldReturn404_WhenUserNotFound(t *testing.T)
 
 	w := httptest.NewRecorder()
 	r := gin.New()
-	r.DELETE("/admin/users/:id", fix.handler.DeleteUser)
+	r.DELETE("/admin/users/:id", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		fix.handler.DeleteUser(c)
+	})
 
 	req := httptest.NewRequest(http.MethodDelete, "/admin/users/"+uuid.New().String(), nil)
 	r.ServeHTTP(w, req)
@@ -667,7 +828,10 @@ func TestAdminHandler_DeleteUser_ShouldReturn400_WhenLastAdmin(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	r := gin.New()
-	r.DELETE("/admin/users/:id", fix.handler.DeleteUser)
+	r.DELETE("/admin/users/:id", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		fix.handler.DeleteUser(c)
+	})
 
 	req := httptest.NewRequest(http.MethodDelete, "/admin/users/"+userID.String(), nil)
 	r.ServeHTTP(w, req)
@@ -675,6 +839,69 @@ func TestAdminHandler_DeleteUser_ShouldReturn400_WhenLastAdmin(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// ---------------------------------------------------------------------------
+// UpdateUserState Tests
+// ---------------------------------------------------------------------------
+
+func serveUpdateUserState(fix *adminTestFixture, userID uuid.UUID, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.POST("/admin/users/:id/state", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		fix.handler.UpdateUserState(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/state", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminHandler_UpdateUserState_ShouldReturn200_WhenSuspended(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	userID := uuid.New()
+	stored := &models.User{ID: userID, State: models.UserStateActive, IsActive: true}
+	fix.userStore.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+		return stored, nil
+	}
+	fix.userStore.UpdateFunc = func(user *models.User) error {
+		stored = user
+		return nil
+	}
+
+	w := serveUpdateUserState(fix, userID, `{"state":"suspended","reason":"chargeback"}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.AdminUserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.UserStateSuspended, resp.State)
+	assert.False(t, resp.IsActive)
+}
+
+func TestAdminHandler_UpdateUserState_ShouldReturn409_WhenTransitionNotAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	fix.userStore.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+		return &models.User{ID: id, State: models.UserStateDeleted}, nil
+	}
+
+	w := serveUpdateUserState(fix, uuid.New(), `{"state":"active"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminHandler_UpdateUserState_ShouldReturn400_WhenStateUnknown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	w := serveUpdateUserState(fix, uuid.New(), `{"state":"banned"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // ---------------------------------------------------------------------------
 // ListAPIKeys Tests
 // ---------------------------------------------------------------------------
@@ -798,13 +1025,10 @@ func TestAdminHandler_ListAuditLogs_ShouldReturn200_WhenLogsExist(t *testing.T)
 	fix := setupAdminTestFixture()
 
 	logID := uuid.New()
-	fix.auditRepo.ListFunc = func(limit, offset int) ([]*models.AuditLog, error) {
+	fix.auditRepo.SearchFunc = func(limit, offset int, o service.AuditLogListOptions) ([]*models.AuditLog, int, error) {
 		return []*models.AuditLog{
 			{ID: logID, Action: "signin", Status: "success", IPAddress: "127.0.0.1"},
-		}, nil
-	}
-	fix.auditRepo.CountFunc = func() (int, error) {
-		return 1, nil
+		}, 1, nil
 	}
 
 	w := httptest.NewRecorder()
@@ -843,10 +1067,12 @@ func TestAdminHandler_ListAuditLogs_ShouldReturn200_WhenFilteredByUserID(t *test
 	fix := setupAdminTestFixture()
 
 	userID := uuid.New()
-	fix.auditRepo.GetByUserIDFunc = func(uid uuid.UUID, limit, offset int) ([]*models.AuditLog, error) {
+	fix.auditRepo.SearchFunc = func(limit, offset int, o service.AuditLogListOptions) ([]*models.AuditLog, int, error) {
+		require.NotNil(t, o.UserID)
+		assert.Equal(t, userID, *o.UserID)
 		return []*models.AuditLog{
-			{ID: uuid.New(), UserID: &uid, Action: "signup", Status: "success"},
-		}, nil
+			{ID: uuid.New(), UserID: o.UserID, Action: "signup", Status: "success"},
+		}, 1, nil
 	}
 
 	w := httptest.NewRecorder()
@@ -863,8 +1089,8 @@ func TestAdminHandler_ListAuditLogs_ShouldReturn500_WhenServiceFails(t *testing.
 	gin.SetMode(gin.TestMode)
 	fix := setupAdminTestFixture()
 
-	fix.auditRepo.ListFunc = func(limit, offset int) ([]*models.AuditLog, error) {
-		return nil, fmt.Errorf("database error")
+	fix.auditRepo.SearchFunc = func(limit, offset int, o service.AuditLogListOptions) ([]*models.AuditLog, int, error) {
+		return nil, 0, fmt.Errorf("database error")
 	}
 
 	w := httptest.NewRecorder()
@@ -877,6 +1103,91 @@ func TestAdminHandler_ListAuditLogs_ShouldReturn500_WhenServiceFails(t *testing.
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestAdminHandler_ListAuditLogs_ShouldPassFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	var got service.AuditLogListOptions
+	fix.auditRepo.SearchFunc = func(limit, offset int, o service.AuditLogListOptions) ([]*models.AuditLog, int, error) {
+		got = o
+		assert.Equal(t, 20, limit)
+		assert.Equal(t, 20, offset)
+		return nil, 0, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/admin/audit-logs", fix.handler.ListAuditLogs)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit-logs?page=2&page_size=20&action=signin,signin_failed"+
+		"&status=failed&ip=10.0.0.1&country=de&from=2025-05-01T00:00:00Z&to=2025-05-02T00:00:00Z&q=curl", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"signin", "signin_failed"}, got.Actions)
+	assert.Equal(t, "failed", got.Status)
+	assert.Equal(t, "10.0.0.1", got.IPAddress)
+	assert.Equal(t, "de", got.CountryCode)
+	assert.Equal(t, "curl", got.Search)
+	require.NotNil(t, got.From)
+	require.NotNil(t, got.To)
+	assert.Equal(t, time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC), *got.To)
+}
+
+func TestAdminHandler_ListAuditLogs_ShouldReturn400_WhenTimeInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/admin/audit-logs", fix.handler.ListAuditLogs)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit-logs?from=yesterday", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminHandler_GetAuditActionStats_ShouldReturn200(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	fix.auditRepo.CountByActionFunc = func(limit int, o service.AuditLogListOptions) ([]*models.AuditActionCount, error) {
+		assert.Equal(t, 5, limit)
+		require.NotNil(t, o.From)
+		require.NotNil(t, o.To)
+		return []*models.AuditActionCount{{Action: "signin", Total: 10, Failed: 2}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/admin/audit-logs/stats/actions", fix.handler.GetAuditActionStats)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit-logs/stats/actions?limit=5", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.AuditActionStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Actions, 1)
+	assert.Equal(t, 2, resp.Actions[0].Failed)
+	assert.Equal(t, 24*time.Hour, resp.To.Sub(resp.From))
+}
+
+func TestAdminHandler_GetAuditFailureStats_ShouldReturn400_WhenRangeTooLong(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupAdminTestFixture()
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/admin/audit-logs/stats/failures", fix.handler.GetAuditFailureStats)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit-logs/stats/failures?from=2025-01-01T00:00:00Z&to=2025-03-01T00:00:00Z", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // ---------------------------------------------------------------------------
 // AssignRole Tests
 // ---------------------------------------------------------------------------
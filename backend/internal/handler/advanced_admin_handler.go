@@ -377,6 +377,177 @@ func (h *AdvancedAdminHandler) DeleteRole(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// GetEffectivePermissions godoc
+// @Summary Get the effective permissions of a role
+// @Description Resolve every permission a role grants: its own, those of its permission bundles and those of every role it inherits from, with the sources of each permission
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID (UUID)"
+// @Success 200 {object} models.EffectivePermissionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/rbac/roles/{id}/effective-permissions [get]
+func (h *AdvancedAdminHandler) GetEffectivePermissions(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.rbacService.GetEffectivePermissions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListPermissionBundles godoc
+// @Summary List all permission bundles
+// @Description Get a list of all permission bundles with their permissions
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.PermissionBundleListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/rbac/bundles [get]
+func (h *AdvancedAdminHandler) ListPermissionBundles(c *gin.Context) {
+	bundles, err := h.rbacService.ListPermissionBundles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PermissionBundleListResponse{
+		Bundles: bundles,
+		Total:   len(bundles),
+	})
+}
+
+// CreatePermissionBundle godoc
+// @Summary Create a new permission bundle
+// @Description Create a named set of permissions that can be granted to roles as a whole
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param bundle body models.CreatePermissionBundleRequest true "Bundle data"
+// @Success 201 {object} models.PermissionBundle
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/rbac/bundles [post]
+func (h *AdvancedAdminHandler) CreatePermissionBundle(c *gin.Context) {
+	var req models.CreatePermissionBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	bundle, err := h.rbacService.CreatePermissionBundle(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bundle)
+}
+
+// GetPermissionBundle godoc
+// @Summary Get a permission bundle by ID
+// @Description Get details of a specific permission bundle
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Bundle ID (UUID)"
+// @Success 200 {object} models.PermissionBundle
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/rbac/bundles/{id} [get]
+func (h *AdvancedAdminHandler) GetPermissionBundle(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	bundle, err := h.rbacService.GetPermissionBundle(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// UpdatePermissionBundle godoc
+// @Summary Update a permission bundle
+// @Description Update an existing permission bundle; roles granted the bundle pick up the change immediately
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Bundle ID (UUID)"
+// @Param bundle body models.UpdatePermissionBundleRequest true "Bundle data"
+// @Success 200 {object} models.PermissionBundle
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/rbac/bundles/{id} [put]
+func (h *AdvancedAdminHandler) UpdatePermissionBundle(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePermissionBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	bundle, err := h.rbacService.UpdatePermissionBundle(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// DeletePermissionBundle godoc
+// @Summary Delete a permission bundle
+// @Description Delete a permission bundle; roles it was granted to lose its permissions
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Param id path string true "Bundle ID (UUID)"
+// @Success 204 "Bundle deleted successfully"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/rbac/bundles/{id} [delete]
+func (h *AdvancedAdminHandler) DeletePermissionBundle(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.rbacService.DeletePermissionBundle(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetPermissionMatrix godoc
 // @Summary Get permission matrix for all roles
 // @Description Get a matrix showing which roles have which permissions
@@ -398,6 +569,36 @@ func (h *AdvancedAdminHandler) GetPermissionMatrix(c *gin.Context) {
 	c.JSON(http.StatusOK, matrix)
 }
 
+// SimulatePermission godoc
+// @Summary Simulate a permission check
+// @Description Evaluate whether a user may perform an action on a resource and return the full decision trace: roles considered, their permissions on the resource, the conditions evaluated and the rule that allowed or denied access
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RBACSimulationRequest true "Check to simulate"
+// @Success 200 {object} models.RBACSimulationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/rbac/simulate [post]
+func (h *AdvancedAdminHandler) SimulatePermission(c *gin.Context) {
+	var req models.RBACSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := h.rbacService.SimulatePermission(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // ============================================================
 // Session Management Endpoints
 // ============================================================
@@ -1010,7 +1211,7 @@ func (h *AdvancedAdminHandler) GetGeoDistribution(c *gin.Context) {
 
 // GetPasswordPolicy godoc
 // @Summary Get password policy settings
-// @Description Get current password policy configuration and JWT TTL settings
+// @Description Get the PASSWORD_* environment password policy and JWT TTL settings. The policy enforced at runtime is served by /api/admin/system/password-policy/v2
 // @Tags Admin - System
 // @Security BearerAuth
 // @Produce json
@@ -1021,15 +1222,16 @@ func (h *AdvancedAdminHandler) GetGeoDistribution(c *gin.Context) {
 // @Router /api/admin/system/password-policy [get]
 func (h *AdvancedAdminHandler) GetPasswordPolicy(c *gin.Context) {
 	response := map[string]interface{}{
-		"minLength":        h.cfg.Security.PasswordPolicy.MinLength,
-		"requireUppercase": h.cfg.Security.PasswordPolicy.RequireUppercase,
-		"requireLowercase": h.cfg.Security.PasswordPolicy.RequireLowercase,
-		"requireNumbers":   h.cfg.Security.PasswordPolicy.RequireNumbers,
-		"requireSpecial":   h.cfg.Security.PasswordPolicy.RequireSpecial,
-		"historyCount":     0,
-		"expiryDays":       0,
-		"jwtTtlMinutes":    int(h.cfg.JWT.AccessExpires.Minutes()),
-		"refreshTtlDays":   int(h.cfg.JWT.RefreshExpires.Hours() / 24),
+		"minLength":         h.cfg.Security.PasswordPolicy.MinLength,
+		"requireUppercase":  h.cfg.Security.PasswordPolicy.RequireUppercase,
+		"requireLowercase":  h.cfg.Security.PasswordPolicy.RequireLowercase,
+		"requireNumbers":    h.cfg.Security.PasswordPolicy.RequireNumbers,
+		"requireSpecial":    h.cfg.Security.PasswordPolicy.RequireSpecial,
+		"historyCount":      h.cfg.Security.PasswordPolicy.HistoryCount,
+		"expiryDays":        h.cfg.Security.PasswordPolicy.MaxAgeDays,
+		"expiryWarningDays": h.cfg.Security.PasswordPolicy.ExpiryWarnDays,
+		"jwtTtlMinutes":     int(h.cfg.JWT.AccessExpires.Minutes()),
+		"refreshTtlDays":    int(h.cfg.JWT.RefreshExpires.Hours() / 24),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -1037,7 +1239,7 @@ func (h *AdvancedAdminHandler) GetPasswordPolicy(c *gin.Context) {
 
 // UpdatePasswordPolicy godoc
 // @Summary Update password policy settings
-// @Description Update password policy configuration (admin only)
+// @Description Save password policy data (admin only). It is stored as-is and not enforced; use /api/admin/system/password-policy/v2 to change the enforced policy
 // @Tags Admin - System
 // @Security BearerAuth
 // @Accept json
@@ -1067,7 +1269,7 @@ func (h *AdvancedAdminHandler) UpdatePasswordPolicy(c *gin.Context) {
 		return
 	}
 
-	err = h.systemRepo.UpdateSetting(c.Request.Context(), "password_policy", string(jsonData), &userID)
+	err = h.systemRepo.UpdateSetting(c.Request.Context(), models.SettingLegacyPasswordPolicy, string(jsonData), &userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
@@ -107,14 +107,15 @@ func (h *AuthHandler) SignUp(c *gin.Context) {
 
 // SignIn handles user login
 // @Summary Login user
-// @Description Authenticate user with email and password
+// @Description Authenticate user with password and an email, phone (E.164) or username. Which identifiers are accepted is configured per deployment.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body models.SignInRequest true "User login data"
 // @Success 200 {object} models.AuthResponse
 // @Failure 400 {object} models.ErrorResponse
-// @Failure 401 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Invalid credentials, or models.PasswordChangeRequiredResponse when the password must be changed"
+// @Failure 423 {object} models.ErrorResponse "Account temporarily locked after too many failed sign-ins"
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/auth/signin [post]
 func (h *AuthHandler) SignIn(c *gin.Context) {
@@ -481,6 +482,40 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	})
 }
 
+// CompleteRequiredPasswordChange sets a new password after sign-in was blocked
+// @Summary Complete a required password change
+// @Description Set a new password using the reset token returned with a PASSWORD_CHANGE_REQUIRED sign-in error. The token is single-use; sign in again afterwards.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.CompleteRequiredPasswordChangeRequest true "Reset token and new password"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/password/change-required [post]
+func (h *AuthHandler) CompleteRequiredPasswordChange(c *gin.Context) {
+	var req models.CompleteRequiredPasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	ip := utils.GetClientIP(c)
+	userAgent := utils.GetUserAgent(c)
+
+	if err := h.authService.CompleteRequiredPasswordChange(c.Request.Context(), req.ResetToken, req.NewPassword, ip, userAgent); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully. Please sign in with your new password.",
+	})
+}
+
 // Verify2FA verifies 2FA code during login
 // @Summary Verify 2FA during login
 // @Description Complete two-factor authentication during login using TOTP code
@@ -490,7 +525,7 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 // @Param request body models.TwoFactorLoginVerifyRequest true "2FA token and TOTP code"
 // @Success 200 {object} models.AuthResponse
 // @Failure 400 {object} models.ErrorResponse
-// @Failure 401 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Invalid code, or models.PasswordChangeRequiredResponse when the password must be changed"
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/auth/2fa/login/verify [post]
 func (h *AuthHandler) Verify2FA(c *gin.Context) {
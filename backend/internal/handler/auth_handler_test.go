@@ -55,6 +55,12 @@ func newTestAuthService(
 		nil, // webhookService
 		false,
 		nil, // passwordChecker
+		nil, // tokenVersions
+		nil, // passwordHistory
+		nil, // passwordExpiry
+		nil, // loginIdentifiers
+		nil, // signupPolicy
+		nil, // emailVerification
 	)
 }
 
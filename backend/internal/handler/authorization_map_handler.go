@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// AuthorizationMapHandler serves the authorization map of the HTTP routes and gRPC methods
+type AuthorizationMapHandler struct {
+	router      *gin.Engine
+	grpcMethods []models.GRPCMethodAuthorization
+	logger      *logger.Logger
+}
+
+// NewAuthorizationMapHandler creates a new authorization map handler for the routes of router
+func NewAuthorizationMapHandler(router *gin.Engine, grpcMethods []models.GRPCMethodAuthorization, log *logger.Logger) *AuthorizationMapHandler {
+	return &AuthorizationMapHandler{
+		router:      router,
+		grpcMethods: grpcMethods,
+		logger:      log,
+	}
+}
+
+// GetAuthorizationMap returns the authorization map
+// @Summary Get the authorization map
+// @Description Every HTTP route with the credentials it accepts and the roles, permissions, scopes and policies its middleware require, and every gRPC method with the API key scope it requires. Generated from the router and middleware registration. Routes marked public may still authenticate the caller in the handler, e.g. OAuth client authentication.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.AuthorizationMap
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/authorization-map [get]
+func (h *AuthorizationMapHandler) GetAuthorizationMap(c *gin.Context) {
+	authMap, err := authmap.Build(h.router, h.grpcMethods)
+	if err != nil {
+		h.logger.Error("Failed to build authorization map", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusOK, authMap)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// BackgroundJobHandler lets admins inspect and retry jobs of the background job queue
+type BackgroundJobHandler struct {
+	service service.JobQueueServicer
+	logger  *logger.Logger
+}
+
+// NewBackgroundJobHandler creates a new background job handler
+func NewBackgroundJobHandler(service service.JobQueueServicer, logger *logger.Logger) *BackgroundJobHandler {
+	return &BackgroundJobHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListJobs lists background jobs
+// @Summary List background jobs
+// @Description List jobs of the background job queue, newest first, optionally filtered by status and type (admin only)
+// @Tags Admin - Background Jobs
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status" Enums(pending, running, succeeded, dead)
+// @Param type query string false "Filter by job type" example(webhook.delivery)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Success 200 {object} models.BackgroundJobListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/jobs [get]
+func (h *BackgroundJobHandler) ListJobs(c *gin.Context) {
+	page, pageSize := utils.ParsePagination(c)
+
+	resp, err := h.service.ListJobs(c.Request.Context(), c.Query("status"), c.Query("type"), page, pageSize)
+	if err != nil {
+		h.respondWithError(c, "Failed to list background jobs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetStats counts background jobs by status
+// @Summary Get background job stats
+// @Description Count jobs of the background job queue by status; dead jobs ran out of attempts and wait to be retried (admin only)
+// @Tags Admin - Background Jobs
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.BackgroundJobStatsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/jobs/stats [get]
+func (h *BackgroundJobHandler) GetStats(c *gin.Context) {
+	stats, err := h.service.GetStats(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, "Failed to get background job stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetJob returns a background job
+// @Summary Get background job
+// @Description Get a job of the background job queue with its payload, attempts and last error (admin only)
+// @Tags Admin - Background Jobs
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.BackgroundJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/jobs/{id} [get]
+func (h *BackgroundJobHandler) GetJob(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, "Failed to get background job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// RetryJob requeues a dead background job
+// @Summary Retry background job
+// @Description Requeue a dead job with a fresh set of attempts; it runs right away (admin only)
+// @Tags Admin - Background Jobs
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.BackgroundJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/jobs/{id}/retry [post]
+func (h *BackgroundJobHandler) RetryJob(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.RetryJob(c.Request.Context(), id)
+	if err != nil {
+		h.respondWithError(c, "Failed to retry background job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *BackgroundJobHandler) respondWithError(c *gin.Context, message string, err error) {
+	if _, ok := err.(*models.AppError); !ok {
+		h.logger.Error(message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.RespondWithError(c, err)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// maxUserIDListBytes limits the size of an uploaded user ID list
+const maxUserIDListBytes = 1 << 20
+
+// BulkRoleJobHandler handles background bulk role assignment jobs
+type BulkRoleJobHandler struct {
+	service service.BulkRoleJobServicer
+	logger  *logger.Logger
+}
+
+// NewBulkRoleJobHandler creates a new bulk role job handler
+func NewBulkRoleJobHandler(service service.BulkRoleJobServicer, logger *logger.Logger) *BulkRoleJobHandler {
+	return &BulkRoleJobHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// StartJob starts a bulk role job
+// @Summary Start bulk role job
+// @Description Assign a role to, or remove it from, every user matching a search query, every member of a group, or a list of user IDs. The list can be sent as user_ids or uploaded as the "file" field of a multipart form (one ID per line or comma separated, with operation, role_id and application_id as form fields). The job runs in the background; poll it for progress and per-user failures
+// @Tags Admin - Bulk Operations
+// @Security BearerAuth
+// @Accept json,multipart/form-data
+// @Produce json
+// @Param request body models.BulkRoleJobRequest false "Operation, role and user selection"
+// @Param file formData file false "User ID list"
+// @Success 202 {object} models.BulkRoleJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/bulk-role-jobs [post]
+func (h *BulkRoleJobHandler) StartJob(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkRoleJobRequest
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUserIDListBytes+multipartOverheadBytes)
+		err = bindBulkRoleJobForm(c, &req)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	job, err := h.service.StartJob(c.Request.Context(), &req, adminID)
+	if err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to start bulk role job", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetJob returns a bulk role job
+// @Summary Get bulk role job
+// @Description Get the status, progress and per-user failures of a bulk role job
+// @Tags Admin - Bulk Operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.BulkRoleJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/bulk-role-jobs/{id} [get]
+func (h *BulkRoleJobHandler) GetJob(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// ListJobs lists recent bulk role jobs
+// @Summary List bulk role jobs
+// @Description List the 50 most recent bulk role jobs, newest first, without their user lists and failures
+// @Tags Admin - Bulk Operations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.BulkRoleJobListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/bulk-role-jobs [get]
+func (h *BulkRoleJobHandler) ListJobs(c *gin.Context) {
+	jobs, err := h.service.ListJobs(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list bulk role jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// bindBulkRoleJobForm reads a bulk role job request from a multipart form with an uploaded
+// user ID list
+func bindBulkRoleJobForm(c *gin.Context, req *models.BulkRoleJobRequest) error {
+	req.Operation = c.PostForm("operation")
+	if req.Operation != models.BulkRoleOperationAssign && req.Operation != models.BulkRoleOperationRemove {
+		return fmt.Errorf("operation must be assign or remove")
+	}
+
+	roleID, err := uuid.Parse(c.PostForm("role_id"))
+	if err != nil {
+		return fmt.Errorf("invalid role_id")
+	}
+	req.RoleID = roleID
+
+	if value := c.PostForm("application_id"); value != "" {
+		appID, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid application_id")
+		}
+		req.ApplicationID = &appID
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	req.UserIDs, err = parseUserIDList(file)
+	return err
+}
+
+// parseUserIDList reads user IDs separated by newlines or commas, as exported by most
+// spreadsheets. A first line that is not an ID is treated as a header.
+func parseUserIDList(r io.Reader) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		for _, field := range strings.Split(scanner.Text(), ",") {
+			field = strings.Trim(strings.TrimSpace(field), `"`)
+			if field == "" {
+				continue
+			}
+			id, err := uuid.Parse(field)
+			if err != nil {
+				if line == 1 {
+					break
+				}
+				return nil, fmt.Errorf("line %d: %q is not a user ID", line, field)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("file contains no user IDs")
+	}
+	return ids, nil
+}
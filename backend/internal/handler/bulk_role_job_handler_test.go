@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bulkRoleJobRouter(svc *mockBulkRoleJobServicer, adminID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewBulkRoleJobHandler(svc, testLogger())
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminID)
+		c.Next()
+	})
+	r.POST("/bulk-role-jobs", h.StartJob)
+	r.GET("/bulk-role-jobs/:id", h.GetJob)
+	return r
+}
+
+func TestBulkRoleJobHandler_StartJob_ShouldReturn202_WhenJSON(t *testing.T) {
+	adminID := uuid.New()
+	roleID := uuid.New()
+	svc := &mockBulkRoleJobServicer{
+		StartJobFunc: func(req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error) {
+			assert.Equal(t, adminID, createdBy)
+			assert.Equal(t, "@acme.com", req.Query)
+			return &models.BulkRoleJob{ID: uuid.New(), RoleID: req.RoleID, Status: models.BulkRoleJobPending}, nil
+		},
+	}
+
+	body := `{"operation":"assign","role_id":"` + roleID.String() + `","query":"@acme.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/bulk-role-jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	bulkRoleJobRouter(svc, adminID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var job models.BulkRoleJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, roleID, job.RoleID)
+}
+
+func TestBulkRoleJobHandler_StartJob_ShouldReturn400_WhenOperationInvalid(t *testing.T) {
+	body := `{"operation":"toggle","role_id":"` + uuid.NewString() + `","query":"acme"}`
+	req := httptest.NewRequest(http.MethodPost, "/bulk-role-jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	bulkRoleJobRouter(&mockBulkRoleJobServicer{}, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkRoleJobHandler_StartJob_ShouldReadUploadedIDList(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	roleID := uuid.New()
+	var got *models.BulkRoleJobRequest
+	svc := &mockBulkRoleJobServicer{
+		StartJobFunc: func(req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error) {
+			got = req
+			return &models.BulkRoleJob{ID: uuid.New()}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	require.NoError(t, form.WriteField("operation", "remove"))
+	require.NoError(t, form.WriteField("role_id", roleID.String()))
+	file, err := form.CreateFormFile("file", "users.csv")
+	require.NoError(t, err)
+	_, err = file.Write([]byte("user_id\n" + first.String() + "\n\n\"" + second.String() + "\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/bulk-role-jobs", &buf)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	bulkRoleJobRouter(svc, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, models.BulkRoleOperationRemove, got.Operation)
+	assert.Equal(t, roleID, got.RoleID)
+	assert.Equal(t, []uuid.UUID{first, second}, got.UserIDs)
+}
+
+func TestParseUserIDList_ShouldRejectInvalidLines(t *testing.T) {
+	_, err := parseUserIDList(strings.NewReader(uuid.NewString() + "\nnot-an-id\n"))
+	assert.EqualError(t, err, `line 2: "not-an-id" is not a user ID`)
+
+	_, err = parseUserIDList(strings.NewReader("user_id\n"))
+	assert.Error(t, err)
+
+	ids, err := parseUserIDList(strings.NewReader(uuid.NewString() + "," + uuid.NewString()))
+	require.NoError(t, err)
+	assert.Len(t, ids, 2)
+}
+
+func TestBulkRoleJobHandler_GetJob_ShouldReturn404_WhenMissing(t *testing.T) {
+	svc := &mockBulkRoleJobServicer{
+		GetJobFunc: func(id uuid.UUID) (*models.BulkRoleJob, error) {
+			return nil, models.NewAppError(http.StatusNotFound, "Bulk role job not found")
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/bulk-role-jobs/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	bulkRoleJobRouter(svc, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/chaos"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// ChaosHandler manages faults injected into dependency calls (admin only, non-production only)
+type ChaosHandler struct {
+	faults       *chaos.Injector
+	auditService service.AuditServicer
+	logger       *logger.Logger
+}
+
+// NewChaosHandler creates a new fault-injection handler
+func NewChaosHandler(faults *chaos.Injector, auditService service.AuditServicer, logger *logger.Logger) *ChaosHandler {
+	return &ChaosHandler{
+		faults:       faults,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListFaults handles listing the active faults
+// @Summary List injected faults
+// @Description Faults currently injected into database, Redis, SMTP and webhook calls. Only available when CHAOS_ENABLED is set outside production.
+// @Tags Admin - Chaos
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.FaultListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/chaos/faults [get]
+func (h *ChaosHandler) ListFaults(c *gin.Context) {
+	c.JSON(http.StatusOK, models.FaultListResponse{Faults: h.faults.List()})
+}
+
+// SetFault handles injecting a fault into a dependency
+// @Summary Inject fault
+// @Description Add latency to every call to the dependency and fail a share of them. Replaces the dependency's current fault. Database faults cancel the query; Redis, SMTP and webhook faults return an error.
+// @Tags Admin - Chaos
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param target path string true "Dependency" Enums(db, redis, smtp, webhook)
+// @Param request body models.SetFaultRequest true "Fault"
+// @Success 200 {object} models.Fault
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/chaos/faults/{target} [put]
+func (h *ChaosHandler) SetFault(c *gin.Context) {
+	target, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetFaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	fault := h.faults.Set(target,
+		time.Duration(req.LatencyMS)*time.Millisecond,
+		req.ErrorRate,
+		time.Duration(req.DurationSeconds)*time.Second,
+	)
+
+	h.logger.Warn("Fault injected", map[string]interface{}{
+		"target":     string(target),
+		"latency_ms": fault.LatencyMS,
+		"error_rate": fault.ErrorRate,
+		"admin_id":   adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionChaosFaultSet,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target":           string(target),
+			"latency_ms":       fault.LatencyMS,
+			"error_rate":       fault.ErrorRate,
+			"duration_seconds": req.DurationSeconds,
+		},
+	})
+
+	c.JSON(http.StatusOK, fault)
+}
+
+// ClearFault handles removing the fault from a dependency
+// @Summary Clear fault
+// @Description Stop injecting faults into the dependency
+// @Tags Admin - Chaos
+// @Security BearerAuth
+// @Param target path string true "Dependency" Enums(db, redis, smtp, webhook)
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/chaos/faults/{target} [delete]
+func (h *ChaosHandler) ClearFault(c *gin.Context) {
+	target, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	h.faults.Clear(target)
+	h.logClear(c, adminID, string(target))
+
+	c.Status(http.StatusNoContent)
+}
+
+// ClearFaults handles removing every fault
+// @Summary Clear all faults
+// @Description Stop injecting faults into every dependency
+// @Tags Admin - Chaos
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/chaos/faults [delete]
+func (h *ChaosHandler) ClearFaults(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	h.faults.ClearAll()
+	h.logClear(c, adminID, "all")
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ChaosHandler) parseTarget(c *gin.Context) (models.FaultTarget, bool) {
+	target := c.Param("target")
+	if !models.IsValidFaultTarget(target) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid fault target", "target must be one of db, redis, smtp, webhook"),
+		))
+		return "", false
+	}
+	return models.FaultTarget(target), true
+}
+
+func (h *ChaosHandler) logClear(c *gin.Context, adminID uuid.UUID, target string) {
+	h.logger.Info("Fault cleared", map[string]interface{}{
+		"target":   target,
+		"admin_id": adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionChaosFaultClear,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target": target,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/chaos"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupChaosHandler(adminID uuid.UUID) (*ChaosHandler, *chaos.Injector, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	faults := chaos.NewInjector()
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	h := NewChaosHandler(faults, audit, testLogger())
+	r.GET("/chaos/faults", h.ListFaults)
+	r.DELETE("/chaos/faults", h.ClearFaults)
+	r.PUT("/chaos/faults/:target", h.SetFault)
+	r.DELETE("/chaos/faults/:target", h.ClearFault)
+	return h, faults, audit, r
+}
+
+func TestChaosHandler_SetFault_ShouldReturn200_AndAudit(t *testing.T) {
+	_, faults, audit, r := setupChaosHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	body := `{"latency_ms":250,"error_rate":0.5,"duration_seconds":60}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/chaos/faults/redis", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.Fault
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.FaultTargetRedis, resp.Target)
+	assert.Equal(t, 250, resp.LatencyMS)
+	require.NotNil(t, resp.ExpiresAt)
+	assert.WithinDuration(t, resp.CreatedAt.Add(time.Minute), *resp.ExpiresAt, time.Second)
+
+	require.Len(t, faults.List(), 1)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionChaosFaultSet, audit.Logged[0].Action)
+}
+
+func TestChaosHandler_SetFault_ShouldReturn400_WhenTargetUnknown(t *testing.T) {
+	_, faults, audit, r := setupChaosHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/chaos/faults/kafka", strings.NewReader(`{"error_rate":1}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, faults.List())
+	assert.Empty(t, audit.Logged)
+}
+
+func TestChaosHandler_SetFault_ShouldReturn400_WhenErrorRateOutOfRange(t *testing.T) {
+	_, faults, _, r := setupChaosHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/chaos/faults/db", strings.NewReader(`{"error_rate":1.5}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, faults.List())
+}
+
+func TestChaosHandler_ListFaults_ShouldReturnActiveFaults(t *testing.T) {
+	_, faults, _, r := setupChaosHandler(uuid.New())
+	faults.Set(models.FaultTargetSMTP, 0, 1, 0)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/chaos/faults", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.FaultListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Faults, 1)
+	assert.Equal(t, models.FaultTargetSMTP, resp.Faults[0].Target)
+}
+
+func TestChaosHandler_ClearFault_ShouldReturn204_AndAudit(t *testing.T) {
+	_, faults, audit, r := setupChaosHandler(uuid.New())
+	faults.Set(models.FaultTargetSMTP, 0, 1, 0)
+	faults.Set(models.FaultTargetDB, 0, 1, 0)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/chaos/faults/smtp", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, faults.List(), 1)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionChaosFaultClear, audit.Logged[0].Action)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/chaos/faults", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, faults.List())
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// ConnectedAppHandler lets the current user review and revoke the OAuth clients they consented to
+type ConnectedAppHandler struct {
+	service service.OAuthProviderServicer
+	logger  *logger.Logger
+}
+
+// NewConnectedAppHandler creates a new connected app handler
+func NewConnectedAppHandler(service service.OAuthProviderServicer, logger *logger.Logger) *ConnectedAppHandler {
+	return &ConnectedAppHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List returns the apps connected to the current user's account
+// @Summary List connected apps
+// @Description List the OAuth clients the current user has consented to, with the granted scopes, when each client was first and last issued a token and how many of its tokens are still valid
+// @Tags Connected Apps
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ConnectedAppListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/connected-apps [get]
+func (h *ConnectedAppHandler) List(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	apps, err := h.service.ListConnectedApps(c.Request.Context(), userID)
+	if err != nil {
+		h.respondWithError(c, "Failed to list connected apps", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ConnectedAppListResponse{Apps: apps, Total: len(apps)})
+}
+
+// Revoke disconnects an app from the current user's account
+// @Summary Revoke connected app
+// @Description Revoke the current user's consent for an OAuth client and every access and refresh token issued to it. The client has to ask for consent again on its next authorization request.
+// @Tags Connected Apps
+// @Security BearerAuth
+// @Param id path string true "Connected app ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/connected-apps/{id} [delete]
+func (h *ConnectedAppHandler) Revoke(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeConnectedApp(c.Request.Context(), userID, clientID); err != nil {
+		h.respondWithError(c, "Failed to revoke connected app", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ConnectedAppHandler) respondWithError(c *gin.Context, message string, err error) {
+	if _, ok := err.(*models.AppError); !ok {
+		h.logger.Error(message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.RespondWithError(c, err)
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// CredentialsHandler issues short-lived downstream credentials and manages credential targets
+type CredentialsHandler struct {
+	broker       service.CredentialsBrokerServicer
+	auditService service.AuditServicer
+	logger       *logger.Logger
+}
+
+// NewCredentialsHandler creates a new credentials broker handler
+func NewCredentialsHandler(broker service.CredentialsBrokerServicer, auditService service.AuditServicer, logger *logger.Logger) *CredentialsHandler {
+	return &CredentialsHandler{
+		broker:       broker,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListAvailableTargets handles listing the targets the user may request credentials for
+// @Summary List credential targets
+// @Description Active credential targets the authenticated user holds the permission for
+// @Tags Credentials
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.CredentialTargetListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/credentials [get]
+func (h *CredentialsHandler) ListAvailableTargets(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	targets, err := h.broker.ListAvailableTargets(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CredentialTargetListResponse{Targets: targets})
+}
+
+// IssueCredentials handles exchanging the user's token for credentials to a target
+// @Summary Issue credentials
+// @Description Issue short-lived credentials for the target. Postgres targets return a temporary login that is a member of the target's role; SSH targets return a certificate for the submitted public key. The lifetime defaults to CREDENTIALS_DEFAULT_TTL and is capped by the target and CREDENTIALS_MAX_TTL. Requires the target's permission.
+// @Tags Credentials
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param target path string true "Target name"
+// @Param request body models.IssueCredentialsRequest false "Lifetime and SSH public key"
+// @Success 201 {object} models.IssuedCredentials
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Backend for the target's kind is not configured"
+// @Router /api/credentials/{target} [post]
+func (h *CredentialsHandler) IssueCredentials(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.IssueCredentialsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+	}
+
+	issued, err := h.broker.Issue(c.Request.Context(), userID, c.Param("target"), &req, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, issued)
+}
+
+// ListTargets handles listing all credential targets
+// @Summary List credential targets (admin)
+// @Description All credential targets, including inactive ones
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.CredentialTargetListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/credentials/targets [get]
+func (h *CredentialsHandler) ListTargets(c *gin.Context) {
+	targets, err := h.broker.ListTargets(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CredentialTargetListResponse{Targets: targets})
+}
+
+// CreateTarget handles creating a credential target
+// @Summary Create credential target
+// @Description Register a downstream system users can get short-lived credentials for. Postgres targets need postgres_role, SSH targets need ssh_principals.
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCredentialTargetRequest true "Target"
+// @Success 201 {object} models.CredentialTarget
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/admin/credentials/targets [post]
+func (h *CredentialsHandler) CreateTarget(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCredentialTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	target, err := h.broker.CreateTarget(c.Request.Context(), &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logTargetChange(c, adminID, models.ActionCredentialTargetCreate, target)
+
+	c.JSON(http.StatusCreated, target)
+}
+
+// UpdateTarget handles updating a credential target
+// @Summary Update credential target
+// @Description Update a credential target; omitted fields are kept. Name and kind cannot change. Credentials already issued are not affected.
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Target ID"
+// @Param request body models.UpdateCredentialTargetRequest true "Changes"
+// @Success 200 {object} models.CredentialTarget
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/credentials/targets/{id} [put]
+func (h *CredentialsHandler) UpdateTarget(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCredentialTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	target, err := h.broker.UpdateTarget(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logTargetChange(c, adminID, models.ActionCredentialTargetUpdate, target)
+
+	c.JSON(http.StatusOK, target)
+}
+
+// DeleteTarget handles deleting a credential target
+// @Summary Delete credential target
+// @Description Delete a credential target. Credentials already issued stay valid until they expire.
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Param id path string true "Target ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/credentials/targets/{id} [delete]
+func (h *CredentialsHandler) DeleteTarget(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	target, err := h.broker.DeleteTarget(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logTargetChange(c, adminID, models.ActionCredentialTargetDelete, target)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListIssuances handles listing issued credentials
+// @Summary List issued credentials
+// @Description Credentials issued by the broker, newest first
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query string false "Only credentials issued to this user"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(50)
+// @Success 200 {object} models.CredentialIssuanceListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/credentials/issuances [get]
+func (h *CredentialsHandler) ListIssuances(c *gin.Context) {
+	var userID *uuid.UUID
+	if c.Query("user_id") != "" {
+		id, ok := utils.ParseUUIDQuery(c, "user_id")
+		if !ok {
+			return
+		}
+		userID = &id
+	}
+	page, pageSize := utils.ParsePagination(c, 50)
+
+	resp, err := h.broker.ListIssuances(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeIssuance handles dropping a temporary Postgres login before it expires
+// @Summary Revoke issued credentials
+// @Description Drop the Postgres login of an issuance and end its sessions. SSH certificates cannot be revoked.
+// @Tags Admin - Credentials
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Issuance ID"
+// @Success 200 {object} models.CredentialIssuance
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "SSH certificate"
+// @Router /api/admin/credentials/issuances/{id} [delete]
+func (h *CredentialsHandler) RevokeIssuance(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	issuance, err := h.broker.Revoke(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionCredentialsRevoke,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"issuance_id": issuance.ID.String(),
+			"target":      issuance.TargetName,
+			"username":    issuance.Username,
+			"user_id":     issuance.UserID.String(),
+		},
+	})
+
+	c.JSON(http.StatusOK, issuance)
+}
+
+func (h *CredentialsHandler) logTargetChange(c *gin.Context, adminID uuid.UUID, action models.AuditAction, target *models.CredentialTarget) {
+	h.logger.Info("Credential target changed", map[string]interface{}{
+		"action":   string(action),
+		"target":   target.Name,
+		"admin_id": adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    action,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target_id":  target.ID.String(),
+			"target":     target.Name,
+			"kind":       target.Kind,
+			"permission": target.Permission,
+			"is_active":  target.IsActive,
+		},
+	})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCredentialsBroker implements service.CredentialsBrokerServicer
+type mockCredentialsBroker struct {
+	target    *models.CredentialTarget
+	issueReq  *models.IssueCredentialsRequest
+	issueErr  error
+	revokeErr error
+}
+
+func (m *mockCredentialsBroker) CreateTarget(_ context.Context, req *models.CreateCredentialTargetRequest) (*models.CredentialTarget, error) {
+	return &models.CredentialTarget{ID: uuid.New(), Name: req.Name, Kind: req.Kind, Permission: req.Permission, IsActive: true}, nil
+}
+func (m *mockCredentialsBroker) UpdateTarget(_ context.Context, _ uuid.UUID, _ *models.UpdateCredentialTargetRequest) (*models.CredentialTarget, error) {
+	return m.target, nil
+}
+func (m *mockCredentialsBroker) DeleteTarget(_ context.Context, _ uuid.UUID) (*models.CredentialTarget, error) {
+	return m.target, nil
+}
+func (m *mockCredentialsBroker) ListTargets(_ context.Context) ([]*models.CredentialTarget, error) {
+	return []*models.CredentialTarget{m.target}, nil
+}
+func (m *mockCredentialsBroker) ListAvailableTargets(_ context.Context, _ uuid.UUID) ([]*models.CredentialTarget, error) {
+	return []*models.CredentialTarget{m.target}, nil
+}
+func (m *mockCredentialsBroker) Issue(_ context.Context, _ uuid.UUID, targetName string, req *models.IssueCredentialsRequest, _, _ string) (*models.IssuedCredentials, error) {
+	m.issueReq = req
+	if m.issueErr != nil {
+		return nil, m.issueErr
+	}
+	return &models.IssuedCredentials{
+		IssuanceID: uuid.New(),
+		Target:     targetName,
+		Kind:       models.CredentialKindPostgres,
+		ExpiresAt:  time.Now().Add(time.Hour),
+		Postgres:   &models.PostgresCredentials{Username: "gw_4f1c2a9e_7b3d", Password: "secret"},
+	}, nil
+}
+func (m *mockCredentialsBroker) ListIssuances(_ context.Context, _ *uuid.UUID, page, pageSize int) (*models.CredentialIssuanceListResponse, error) {
+	return &models.CredentialIssuanceListResponse{Page: page, PageSize: pageSize}, nil
+}
+func (m *mockCredentialsBroker) Revoke(_ context.Context, id uuid.UUID) (*models.CredentialIssuance, error) {
+	if m.revokeErr != nil {
+		return nil, m.revokeErr
+	}
+	return &models.CredentialIssuance{ID: id, TargetName: "analytics-db", Kind: models.CredentialKindPostgres}, nil
+}
+
+func setupCredentialsHandler(userID uuid.UUID) (*mockCredentialsBroker, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	broker := &mockCredentialsBroker{target: &models.CredentialTarget{ID: uuid.New(), Name: "analytics-db"}}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, userID)
+		c.Next()
+	})
+	h := NewCredentialsHandler(broker, audit, testLogger())
+	r.GET("/credentials", h.ListAvailableTargets)
+	r.POST("/credentials/:target", h.IssueCredentials)
+	r.POST("/admin/credentials/targets", h.CreateTarget)
+	r.DELETE("/admin/credentials/targets/:id", h.DeleteTarget)
+	r.DELETE("/admin/credentials/issuances/:id", h.RevokeIssuance)
+	return broker, audit, r
+}
+
+func TestCredentialsHandler_IssueCredentials_ShouldReturn201_WithoutCaching(t *testing.T) {
+	broker, _, r := setupCredentialsHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/credentials/analytics-db", strings.NewReader(`{"ttl_seconds":900}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, 900, broker.issueReq.TTLSeconds)
+	var resp models.IssuedCredentials
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "analytics-db", resp.Target)
+	require.NotNil(t, resp.Postgres)
+}
+
+func TestCredentialsHandler_IssueCredentials_ShouldAcceptEmptyBody(t *testing.T) {
+	broker, _, r := setupCredentialsHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/credentials/analytics-db", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Zero(t, broker.issueReq.TTLSeconds)
+}
+
+func TestCredentialsHandler_IssueCredentials_ShouldPassThroughForbidden(t *testing.T) {
+	broker, _, r := setupCredentialsHandler(uuid.New())
+	broker.issueErr = models.NewAppError(http.StatusForbidden, "Missing permission for this credential target")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/credentials/analytics-db", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCredentialsHandler_CreateTarget_ShouldReturn400_WhenKindUnknown(t *testing.T) {
+	_, audit, r := setupCredentialsHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	body := `{"name":"cache","kind":"redis","permission":"cache:connect"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/credentials/targets", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestCredentialsHandler_CreateTarget_ShouldAudit(t *testing.T) {
+	adminID := uuid.New()
+	_, audit, r := setupCredentialsHandler(adminID)
+
+	w := httptest.NewRecorder()
+	body := `{"name":"analytics-db","kind":"postgres","permission":"analytics-db:connect","postgres_role":"analytics_readonly"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/credentials/targets", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionCredentialTargetCreate, audit.Logged[0].Action)
+	assert.Equal(t, adminID, *audit.Logged[0].UserID)
+}
+
+func TestCredentialsHandler_RevokeIssuance_ShouldAudit(t *testing.T) {
+	_, audit, r := setupCredentialsHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/credentials/issuances/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionCredentialsRevoke, audit.Logged[0].Action)
+}
+
+func TestCredentialsHandler_RevokeIssuance_ShouldNotAudit_WhenRefused(t *testing.T) {
+	broker, audit, r := setupCredentialsHandler(uuid.New())
+	broker.revokeErr = models.NewAppError(http.StatusConflict, "SSH certificates cannot be revoked; they expire on their own")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/credentials/issuances/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, audit.Logged)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// DataExportHandler handles exports of the current user's data
+type DataExportHandler struct {
+	dataExportService service.DataExportServicer
+	logger            *logger.Logger
+}
+
+// NewDataExportHandler creates a new data export handler
+func NewDataExportHandler(dataExportService service.DataExportServicer, logger *logger.Logger) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportService: dataExportService,
+		logger:            logger,
+	}
+}
+
+// RequestExport handles a request for an export of the current user's data
+// @Summary Export my data
+// @Description Exports the profile, emails, application profiles, sessions, OAuth consents, audit events and linked identities of the current user. The export is generated in the background: the first request returns 202 with a pending export, later requests return its status. Once ready, the response carries a short-lived signed download link. A ready export is kept until expires_at and returned again instead of generating another.
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param format query string false "File format: json (default) or zip"
+// @Success 200 {object} models.DataExportResponse
+// @Success 202 {object} models.DataExportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/me/export [get]
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.dataExportService.RequestExport(
+		c.Request.Context(),
+		userID,
+		models.DataExportFormat(c.Query("format")),
+		utils.GetClientIP(c),
+		utils.GetUserAgent(c),
+	)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if export.Status == models.DataExportStatusPending {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, export)
+}
+
+// Download handles the download of an export through its signed link
+// @Summary Download my data export
+// @Description Downloads a ready export. The request is authorized by the signature of the link returned by GET /api/auth/me/export, not by a bearer token.
+// @Tags Auth
+// @Produce application/json,application/zip
+// @Param id path string true "Export ID (UUID)"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/me/export/{id}/download [get]
+func (h *DataExportHandler) Download(c *gin.Context) {
+	exportID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, err := h.dataExportService.Download(
+		c.Request.Context(),
+		exportID,
+		c.Request.Method,
+		c.Request.URL,
+		utils.GetClientIP(c),
+		utils.GetUserAgent(c),
+	)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
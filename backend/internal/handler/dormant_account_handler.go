@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// DormantAccountHandler handles the dormant account policy and its dry-run report (admin only)
+type DormantAccountHandler struct {
+	dormantAccountService service.DormantAccountServicer
+	auditService          service.AuditServicer
+	logger                *logger.Logger
+}
+
+// NewDormantAccountHandler creates a new dormant account handler
+func NewDormantAccountHandler(dormantAccountService service.DormantAccountServicer, auditService service.AuditServicer, logger *logger.Logger) *DormantAccountHandler {
+	return &DormantAccountHandler{
+		dormantAccountService: dormantAccountService,
+		auditService:          auditService,
+		logger:                logger,
+	}
+}
+
+// GetDormantAccountPolicy handles retrieving the dormant account policy
+// @Summary Get dormant account policy
+// @Description Inactivity period, notice period, action and exclusions applied to accounts nobody uses
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.DormantAccountPolicy
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/dormant-account-policy [get]
+func (h *DormantAccountHandler) GetDormantAccountPolicy(c *gin.Context) {
+	policy, err := h.dormantAccountService.GetPolicy(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateDormantAccountPolicy handles replacing the dormant account policy
+// @Summary Update dormant account policy
+// @Description Replace the dormant account policy. An account is dormant after inactive_days without a sign-in or session activity; it is emailed a notice and, if still unused after notice_days, suspended, deactivated or anonymized. Administrators, guests and service accounts are never affected. Review the report before enabling the policy.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateDormantAccountPolicyRequest true "Dormant account policy"
+// @Success 200 {object} models.DormantAccountPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/dormant-account-policy [put]
+func (h *DormantAccountHandler) UpdateDormantAccountPolicy(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateDormantAccountPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	policy, err := h.dormantAccountService.UpdatePolicy(c.Request.Context(), &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionDormantPolicyUpdate,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"enabled":           policy.Enabled,
+			"inactive_days":     policy.InactiveDays,
+			"notice_days":       policy.NoticeDays,
+			"action":            string(policy.Action),
+			"excluded_roles":    policy.ExcludedRoles,
+			"excluded_domains":  policy.ExcludedDomains,
+			"excluded_user_ids": len(policy.ExcludedUserIDs),
+		},
+	})
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetDormantAccountReport handles the dry run of the dormant account policy
+// @Summary Dormant account report
+// @Description Dry run of the current dormant account policy: the accounts it considers dormant and whether the next run would notify them, wait for their notice period or apply the action. Nothing is changed, and the report is available while the policy is disabled.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.DormantAccountReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/dormant-account-policy/report [get]
+func (h *DormantAccountHandler) GetDormantAccountReport(c *gin.Context) {
+	report, err := h.dormantAccountService.Report(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDormantAccountHandler(adminID uuid.UUID) (*DormantAccountHandler, *mockDormantAccountServicer, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockDormantAccountServicer{}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	return NewDormantAccountHandler(svc, audit, testLogger()), svc, audit, r
+}
+
+func TestDormantAccountHandler_UpdateDormantAccountPolicy_ShouldReturn200_AndAudit(t *testing.T) {
+	adminID := uuid.New()
+	h, svc, audit, r := setupDormantAccountHandler(adminID)
+
+	svc.UpdatePolicyFunc = func(req *models.UpdateDormantAccountPolicyRequest, updatedBy uuid.UUID) (*models.DormantAccountPolicy, error) {
+		assert.Equal(t, adminID, updatedBy)
+		assert.Equal(t, models.DormantActionAnonymize, req.Action)
+		return &models.DormantAccountPolicy{Enabled: true, InactiveDays: req.InactiveDays, NoticeDays: req.NoticeDays, Action: req.Action}, nil
+	}
+	r.PUT("/dormant-account-policy", h.UpdateDormantAccountPolicy)
+
+	w := httptest.NewRecorder()
+	body := `{"enabled":true,"inactive_days":365,"notice_days":30,"action":"anonymize"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/dormant-account-policy", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.DormantAccountPolicy
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 365, resp.InactiveDays)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionDormantPolicyUpdate, audit.Logged[0].Action)
+}
+
+func TestDormantAccountHandler_UpdateDormantAccountPolicy_ShouldReturn400_WhenInactiveDaysMissing(t *testing.T) {
+	h, _, audit, r := setupDormantAccountHandler(uuid.New())
+	r.PUT("/dormant-account-policy", h.UpdateDormantAccountPolicy)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/dormant-account-policy", strings.NewReader(`{"action":"suspend"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestDormantAccountHandler_GetDormantAccountReport_ShouldReturn200(t *testing.T) {
+	h, svc, _, r := setupDormantAccountHandler(uuid.New())
+
+	svc.ReportFunc = func() (*models.DormantAccountReport, error) {
+		return &models.DormantAccountReport{
+			Policy:   &models.DormantAccountPolicy{InactiveDays: 90, Action: models.DormantActionSuspend},
+			ToNotify: 1,
+			Accounts: []*models.DormantAccount{{UserID: uuid.New(), Email: "old@example.com", Stage: models.DormantStageNotify}},
+		}, nil
+	}
+	r.GET("/dormant-account-policy/report", h.GetDormantAccountReport)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dormant-account-policy/report", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.DormantAccountReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.ToNotify)
+	require.Len(t, resp.Accounts, 1)
+	assert.Equal(t, models.DormantStageNotify, resp.Accounts[0].Stage)
+}
@@ -330,3 +330,66 @@ func (h *GroupHandler) GetGroupMembers(c *gin.Context) {
 		PageSize: pageSize,
 	})
 }
+
+// GetGroupRoles handles retrieving the roles mapped to a group
+// @Summary Get group roles
+// @Description Get the roles granted to members of a group, including roles inherited from parent groups
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.GroupRolesResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/roles [get]
+func (h *GroupHandler) GetGroupRoles(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	roles, err := h.groupService.GetGroupRoles(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// SetGroupRoles handles replacing the roles mapped to a group
+// @Summary Set group roles
+// @Description Replace the roles granted to members of a group and its child groups. Members gain the new roles and lose the ones no group grants them anymore; directly assigned roles are not affected.
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param request body models.SetGroupRolesRequest true "Role IDs"
+// @Success 200 {object} models.GroupRolesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/roles [put]
+func (h *GroupHandler) SetGroupRoles(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.SetGroupRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	roles, err := h.groupService.SetGroupRoles(c.Request.Context(), id, req.RoleIDs)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
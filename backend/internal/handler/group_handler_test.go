@@ -744,3 +744,97 @@ func TestGroupHandler_GetGroupMembers_ShouldReturnEmptyList_WhenNoMembers(t *tes
 	assert.Equal(t, 0, resp.Total)
 	assert.Len(t, resp.Users, 0)
 }
+
+// ===========================================================================
+// GetGroupRoles / SetGroupRoles
+// ===========================================================================
+
+func TestGroupHandler_GetGroupRoles_ShouldReturnDirectAndInheritedRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupGroupTestFixture()
+
+	groupID := uuid.New()
+	fix.groupSvc.GetGroupRolesFunc = func(gID uuid.UUID) (*models.GroupRolesResponse, error) {
+		assert.Equal(t, groupID, gID)
+		return &models.GroupRolesResponse{
+			GroupID:        gID,
+			Roles:          []models.Role{{ID: uuid.New(), Name: "developer"}},
+			InheritedRoles: []models.Role{{ID: uuid.New(), Name: "employee"}},
+		}, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/groups/:id/roles", fix.handler.GetGroupRoles)
+
+	req := httptest.NewRequest(http.MethodGet, "/groups/"+groupID.String()+"/roles", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.GroupRolesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Roles, 1)
+	assert.Equal(t, "developer", resp.Roles[0].Name)
+	require.Len(t, resp.InheritedRoles, 1)
+	assert.Equal(t, "employee", resp.InheritedRoles[0].Name)
+}
+
+func TestGroupHandler_SetGroupRoles_ShouldPassRoleIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupGroupTestFixture()
+
+	groupID := uuid.New()
+	roleID := uuid.New()
+	fix.groupSvc.SetGroupRolesFunc = func(gID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error) {
+		assert.Equal(t, groupID, gID)
+		assert.Equal(t, []uuid.UUID{roleID}, roleIDs)
+		return &models.GroupRolesResponse{GroupID: gID, Roles: []models.Role{{ID: roleID}}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.PUT("/groups/:id/roles", fix.handler.SetGroupRoles)
+
+	body := fmt.Sprintf(`{"role_ids":["%s"]}`, roleID)
+	req := httptest.NewRequest(http.MethodPut, "/groups/"+groupID.String()+"/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGroupHandler_SetGroupRoles_ShouldReturn400_WhenBodyInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupGroupTestFixture()
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.PUT("/groups/:id/roles", fix.handler.SetGroupRoles)
+
+	req := httptest.NewRequest(http.MethodPut, "/groups/"+uuid.New().String()+"/roles", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGroupHandler_SetGroupRoles_ShouldReturnServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupGroupTestFixture()
+
+	fix.groupSvc.SetGroupRolesFunc = func(gID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error) {
+		return nil, models.NewAppError(http.StatusNotFound, "Group not found")
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.PUT("/groups/:id/roles", fix.handler.SetGroupRoles)
+
+	body := fmt.Sprintf(`{"role_ids":["%s"]}`, uuid.New())
+	req := httptest.NewRequest(http.MethodPut, "/groups/"+uuid.New().String()+"/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
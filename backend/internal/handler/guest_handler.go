@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// GuestHandler handles anonymous guest sessions and their upgrade to full accounts
+type GuestHandler struct {
+	guestService service.GuestServicer
+	otpService   service.OTPServicer
+	logger       *logger.Logger
+}
+
+// NewGuestHandler creates a new guest handler
+func NewGuestHandler(guestService service.GuestServicer, otpService service.OTPServicer, log *logger.Logger) *GuestHandler {
+	return &GuestHandler{
+		guestService: guestService,
+		otpService:   otpService,
+		logger:       log,
+	}
+}
+
+// CreateGuestSession handles issuing an anonymous guest session
+// @Summary Start guest session
+// @Description Create an anonymous guest account without credentials and sign it in. Guest tokens carry the is_guest claim. Applications that restrict auth methods must allow the "guest" method.
+// @Tags Authentication
+// @Produce json
+// @Success 201 {object} models.AuthResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/guest [post]
+func (h *GuestHandler) CreateGuestSession(c *gin.Context) {
+	ip := utils.GetClientIP(c)
+	userAgent := utils.GetUserAgent(c)
+	deviceInfo := utils.GetDeviceInfoFromContext(c)
+	appID, _ := utils.GetApplicationIDFromContext(c)
+
+	authResp, err := h.guestService.CreateGuest(c.Request.Context(), ip, userAgent, deviceInfo, appID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, authResp)
+}
+
+// UpgradeGuest handles turning the authenticated guest into a full account
+// @Summary Upgrade guest account
+// @Description Set credentials on the authenticated guest account. The user ID and all linked data are kept. Guest tokens are revoked and new tokens are returned, unless the email address must be verified first.
+// @Tags Authentication
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.UpgradeGuestRequest true "Credentials"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Not a guest account, or email, phone or username already taken"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/guest/upgrade [post]
+func (h *GuestHandler) UpgradeGuest(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpgradeGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	ip := utils.GetClientIP(c)
+	userAgent := utils.GetUserAgent(c)
+	deviceInfo := utils.GetDeviceInfoFromContext(c)
+	appID, _ := utils.GetApplicationIDFromContext(c)
+
+	authResp, err := h.guestService.UpgradeGuest(c.Request.Context(), userID, &req, ip, userAgent, deviceInfo, appID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	// Send verification email (non-blocking)
+	if authResp.User != nil && authResp.User.Email != "" && h.otpService != nil {
+		email := authResp.User.Email
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			otpReq := &models.SendOTPRequest{
+				Email:         &email,
+				Type:          models.OTPTypeVerification,
+				ApplicationID: appID,
+			}
+			if err := h.otpService.SendOTP(ctx, otpReq); err != nil {
+				h.logger.Error("Failed to send verification email", map[string]interface{}{
+					"error": err.Error(),
+					"email": email,
+				})
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK, authResp)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGuestHandler(userID *uuid.UUID) (*GuestHandler, *mockGuestServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockGuestServicer{}
+	r := gin.New()
+	if userID != nil {
+		r.Use(func(c *gin.Context) {
+			c.Set(utils.UserIDKey, *userID)
+			c.Next()
+		})
+	}
+	return NewGuestHandler(svc, nil, testLogger()), svc, r
+}
+
+func TestGuestHandler_CreateGuestSession_ShouldReturn201(t *testing.T) {
+	h, svc, r := setupGuestHandler(nil)
+	guestID := uuid.New()
+	svc.CreateGuestFunc = func(ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+		return &models.AuthResponse{AccessToken: "access", User: &models.User{ID: guestID, IsGuest: true}}, nil
+	}
+	r.POST("/guest", h.CreateGuestSession)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/guest", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, guestID, resp.User.ID)
+	assert.True(t, resp.User.IsGuest)
+}
+
+func TestGuestHandler_UpgradeGuest_ShouldPassAuthenticatedUser(t *testing.T) {
+	userID := uuid.New()
+	h, svc, r := setupGuestHandler(&userID)
+	svc.UpgradeGuestFunc = func(id uuid.UUID, req *models.UpgradeGuestRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+		assert.Equal(t, userID, id)
+		assert.Equal(t, "johndoe", req.Username)
+		return &models.AuthResponse{AccessToken: "access", User: &models.User{ID: id, Phone: req.Phone}}, nil
+	}
+	r.POST("/guest/upgrade", h.UpgradeGuest)
+
+	w := httptest.NewRecorder()
+	body := `{"phone":"+1234567890","username":"johndoe","password":"SecurePass123!"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/guest/upgrade", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGuestHandler_UpgradeGuest_ShouldReturn400_WhenPasswordMissing(t *testing.T) {
+	userID := uuid.New()
+	h, svc, r := setupGuestHandler(&userID)
+	svc.UpgradeGuestFunc = func(uuid.UUID, *models.UpgradeGuestRequest, string, string, models.DeviceInfo, *uuid.UUID) (*models.AuthResponse, error) {
+		t.Fatal("service must not be called")
+		return nil, nil
+	}
+	r.POST("/guest/upgrade", h.UpgradeGuest)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/guest/upgrade", strings.NewReader(`{"email":"user@example.com","username":"johndoe"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -31,6 +31,8 @@ type mockUserStoreHandler struct {
 	ListFunc                 func(opts ...service.UserListOption) ([]*models.User, error)
 	CountFunc                func(isActive *bool) (int, error)
 	GetUsersUpdatedAfterFunc func(after time.Time, appID *uuid.UUID, limit, offset int) ([]*models.User, int, error)
+	FilterFunc               func(limit, offset int, o service.UserFilterOptions) ([]*models.User, error)
+	CountFilteredFunc        func(o service.UserFilterOptions) (int, error)
 	UpdateTOTPSecretFunc     func(userID uuid.UUID, secret string) error
 	EnableTOTPFunc           func(userID uuid.UUID) error
 	DisableTOTPFunc          func(userID uuid.UUID) error
@@ -126,6 +128,21 @@ func (m *mockUserStoreHandler) GetUsersUpdatedAfter(_ context.Context, after tim
 	}
 	return nil, 0, nil
 }
+func (m *mockUserStoreHandler) Search(_ context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockUserStoreHandler) Filter(_ context.Context, limit, offset int, opts ...service.UserFilterOption) ([]*models.User, error) {
+	if m.FilterFunc != nil {
+		return m.FilterFunc(limit, offset, service.BuildUserFilterOptions(opts))
+	}
+	return nil, nil
+}
+func (m *mockUserStoreHandler) CountFiltered(_ context.Context, opts ...service.UserFilterOption) (int, error) {
+	if m.CountFilteredFunc != nil {
+		return m.CountFilteredFunc(service.BuildUserFilterOptions(opts))
+	}
+	return 0, nil
+}
 func (m *mockUserStoreHandler) UpdateTOTPSecret(_ context.Context, userID uuid.UUID, secret string) error {
 	if m.UpdateTOTPSecretFunc != nil {
 		return m.UpdateTOTPSecretFunc(userID, secret)
@@ -211,6 +228,18 @@ func (m *mockTokenServiceHandler) GenerateRefreshToken(user *models.User, _ ...*
 func (m *mockTokenServiceHandler) GenerateTwoFactorToken(_ *models.User, _ ...*uuid.UUID) (string, error) {
 	return "mock-2fa-token", nil
 }
+func (m *mockTokenServiceHandler) GeneratePasswordChangeToken(_ *models.User) (string, error) {
+	return "mock-password-change-token", nil
+}
+func (m *mockTokenServiceHandler) ValidatePasswordChangeToken(_ string) (*jwt.Claims, error) {
+	return nil, jwt.ErrInvalidToken
+}
+func (m *mockTokenServiceHandler) GenerateOAuthLinkToken(_ *models.User, _, _ string, _ *uuid.UUID) (string, error) {
+	return "mock-oauth-link-token", nil
+}
+func (m *mockTokenServiceHandler) ValidateOAuthLinkToken(_ string) (*jwt.Claims, error) {
+	return nil, jwt.ErrInvalidToken
+}
 func (m *mockTokenServiceHandler) ValidateAccessToken(token string) (*jwt.Claims, error) {
 	if m.ValidateAccessTokenFunc != nil {
 		return m.ValidateAccessTokenFunc(token)
@@ -291,7 +320,7 @@ func (m *mockRBACStoreHandler) ListPermissions(_ context.Context) ([]models.Perm
 	}
 	return nil, nil
 }
-func (m *mockRBACStoreHandler) UpdatePermission(_ context.Context, id uuid.UUID, desc string) error {
+func (m *mockRBACStoreHandler) UpdatePermission(_ context.Context, id uuid.UUID, desc string, _ *string) error {
 	if m.UpdatePermissionFunc != nil {
 		return m.UpdatePermissionFunc(id, desc)
 	}
@@ -348,6 +377,39 @@ func (m *mockRBACStoreHandler) DeleteRole(_ context.Context, id uuid.UUID) error
 func (m *mockRBACStoreHandler) SetRolePermissions(_ context.Context, _ uuid.UUID, _ []uuid.UUID) error {
 	return nil
 }
+func (m *mockRBACStoreHandler) ListRoleParents(ctx context.Context) ([]models.RoleParent, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreHandler) SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreHandler) ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreHandler) SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreHandler) CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error {
+	return nil
+}
+func (m *mockRBACStoreHandler) GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreHandler) GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreHandler) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStoreHandler) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error {
+	return nil
+}
+func (m *mockRBACStoreHandler) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStoreHandler) SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return nil
+}
 func (m *mockRBACStoreHandler) AssignRoleToUser(_ context.Context, _, _, _ uuid.UUID) error {
 	return nil
 }
@@ -421,6 +483,39 @@ func (m *mockAuditLoggerHandler) Log(_ service.AuditLogParams) {
 	m.LogCalled = true
 }
 
+// mockAuditServicer records the entries logged through service.AuditServicer
+type mockAuditServicer struct {
+	Logged []service.AuditLogParams
+}
+
+func (m *mockAuditServicer) Log(params service.AuditLogParams) {
+	m.Logged = append(m.Logged, params)
+}
+func (m *mockAuditServicer) LogSync(_ context.Context, params service.AuditLogParams) error {
+	m.Logged = append(m.Logged, params)
+	return nil
+}
+func (m *mockAuditServicer) LogWithAction(_ *uuid.UUID, _, _, _, _ string, _ map[string]interface{}) {
+}
+func (m *mockAuditServicer) GetByUserID(_ context.Context, _ uuid.UUID, _, _ int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+func (m *mockAuditServicer) List(_ context.Context, _, _ int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+func (m *mockAuditServicer) Count(_ context.Context) (int, error) {
+	return 0, nil
+}
+func (m *mockAuditServicer) CountByActionSince(_ context.Context, _ models.AuditAction, _ time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockAuditServicer) DeleteOlderThan(_ context.Context, _ int) error {
+	return nil
+}
+func (m *mockAuditServicer) ListByApp(_ context.Context, _ uuid.UUID, _, _ int) ([]*models.AuditLog, int, error) {
+	return nil, 0, nil
+}
+
 // ===========================================================================
 // CacheService mock
 // ===========================================================================
@@ -554,12 +649,15 @@ func (m *mockOTPStoreHandler) CountRecentByPhone(_ context.Context, _ string, _
 // ===========================================================================
 
 type mockAuditStoreHandler struct {
-	CreateFunc             func(log *models.AuditLog) error
-	ListFunc               func(limit, offset int) ([]*models.AuditLog, error)
-	CountFunc              func() (int, error)
-	GetByUserIDFunc        func(userID uuid.UUID, limit, offset int) ([]*models.AuditLog, error)
-	CountByActionSinceFunc func(action models.AuditAction, since time.Time) (int, error)
-	ListByAppFunc          func(appID uuid.UUID, limit, offset int) ([]*models.AuditLog, int, error)
+	CreateFunc              func(log *models.AuditLog) error
+	ListFunc                func(limit, offset int) ([]*models.AuditLog, error)
+	CountFunc               func() (int, error)
+	GetByUserIDFunc         func(userID uuid.UUID, limit, offset int) ([]*models.AuditLog, error)
+	CountByActionSinceFunc  func(action models.AuditAction, since time.Time) (int, error)
+	ListByAppFunc           func(appID uuid.UUID, limit, offset int) ([]*models.AuditLog, int, error)
+	SearchFunc              func(limit, offset int, o service.AuditLogListOptions) ([]*models.AuditLog, int, error)
+	CountByActionFunc       func(limit int, o service.AuditLogListOptions) ([]*models.AuditActionCount, error)
+	CountFailuresByHourFunc func(o service.AuditLogListOptions) ([]*models.AuditHourlyCount, error)
 }
 
 func (m *mockAuditStoreHandler) Create(_ context.Context, log *models.AuditLog) error {
@@ -607,6 +705,24 @@ func (m *mockAuditStoreHandler) ListByApp(_ context.Context, appID uuid.UUID, li
 	}
 	return nil, 0, nil
 }
+func (m *mockAuditStoreHandler) Search(_ context.Context, limit, offset int, opts ...service.AuditLogListOption) ([]*models.AuditLog, int, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(limit, offset, service.BuildAuditLogListOptions(opts))
+	}
+	return nil, 0, nil
+}
+func (m *mockAuditStoreHandler) CountByAction(_ context.Context, limit int, opts ...service.AuditLogListOption) ([]*models.AuditActionCount, error) {
+	if m.CountByActionFunc != nil {
+		return m.CountByActionFunc(limit, service.BuildAuditLogListOptions(opts))
+	}
+	return nil, nil
+}
+func (m *mockAuditStoreHandler) CountFailuresByHour(_ context.Context, opts ...service.AuditLogListOption) ([]*models.AuditHourlyCount, error) {
+	if m.CountFailuresByHourFunc != nil {
+		return m.CountFailuresByHourFunc(service.BuildAuditLogListOptions(opts))
+	}
+	return nil, nil
+}
 
 // ===========================================================================
 // APIKeyStore mock
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
 )
 
 // ===========================================================================
@@ -215,6 +216,7 @@ type mockAuthServicer struct {
 	InitPasswordlessRegistrationFunc    func(req *models.InitPasswordlessRegistrationRequest, ip, userAgent string) error
 	CompletePasswordlessRegistrationFunc func(req *models.CompletePasswordlessRegistrationRequest, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error)
 	GenerateTokensForUserFunc           func(user *models.User, ip, userAgent string) (*models.AuthResponse, error)
+	CompleteRequiredPasswordChangeFunc  func(resetToken, newPassword, ip, userAgent string) error
 }
 
 func (m *mockAuthServicer) SignUp(_ context.Context, req *models.CreateUserRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
@@ -287,6 +289,13 @@ func (m *mockAuthServicer) GenerateTokensForUser(_ context.Context, user *models
 	return nil, nil
 }
 
+func (m *mockAuthServicer) CompleteRequiredPasswordChange(_ context.Context, resetToken, newPassword, ip, userAgent string) error {
+	if m.CompleteRequiredPasswordChangeFunc != nil {
+		return m.CompleteRequiredPasswordChangeFunc(resetToken, newPassword, ip, userAgent)
+	}
+	return nil
+}
+
 // ===========================================================================
 // mockUserServicer
 // ===========================================================================
@@ -347,15 +356,16 @@ func (m *mockUserServicer) Count(_ context.Context) (int, error) {
 // ===========================================================================
 
 type mockWebhookServicer struct {
-	CreateWebhookFunc         func(req *models.CreateWebhookRequest, createdBy uuid.UUID) (*models.Webhook, string, error)
-	GetWebhookFunc            func(id uuid.UUID) (*models.Webhook, error)
-	ListWebhooksFunc          func(page, perPage int) (*models.WebhookListResponse, error)
-	UpdateWebhookFunc         func(id uuid.UUID, req *models.UpdateWebhookRequest, updatedBy uuid.UUID) error
-	DeleteWebhookFunc         func(id uuid.UUID, deletedBy uuid.UUID) error
-	TriggerWebhookFunc        func(eventType string, data map[string]interface{}) error
-	ListWebhookDeliveriesFunc func(webhookID uuid.UUID, page, perPage int) (*models.WebhookDeliveryListResponse, error)
-	TestWebhookFunc           func(id uuid.UUID, req *models.TestWebhookRequest) error
-	ListWebhooksByAppFunc     func(appID uuid.UUID) ([]*models.Webhook, error)
+	CreateWebhookFunc            func(req *models.CreateWebhookRequest, createdBy uuid.UUID) (*models.Webhook, string, error)
+	GetWebhookFunc               func(id uuid.UUID) (*models.Webhook, error)
+	ListWebhooksFunc             func(page, perPage int) (*models.WebhookListResponse, error)
+	UpdateWebhookFunc            func(id uuid.UUID, req *models.UpdateWebhookRequest, updatedBy uuid.UUID) error
+	DeleteWebhookFunc            func(id uuid.UUID, deletedBy uuid.UUID) error
+	TriggerWebhookFunc           func(eventType string, data map[string]interface{}) error
+	ListWebhookDeliveriesFunc    func(webhookID uuid.UUID, page, perPage int) (*models.WebhookDeliveryListResponse, error)
+	TestWebhookFunc              func(id uuid.UUID, req *models.TestWebhookRequest) error
+	ListWebhooksByAppFunc        func(appID uuid.UUID) ([]*models.Webhook, error)
+	RedeliverWebhookDeliveryFunc func(webhookID, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
 }
 
 func (m *mockWebhookServicer) CreateWebhook(_ context.Context, req *models.CreateWebhookRequest, createdBy uuid.UUID) (*models.Webhook, string, error) {
@@ -407,6 +417,13 @@ func (m *mockWebhookServicer) ListWebhookDeliveries(_ context.Context, webhookID
 	return nil, nil
 }
 
+func (m *mockWebhookServicer) RedeliverWebhookDelivery(_ context.Context, webhookID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if m.RedeliverWebhookDeliveryFunc != nil {
+		return m.RedeliverWebhookDeliveryFunc(webhookID, deliveryID)
+	}
+	return nil, nil
+}
+
 func (m *mockWebhookServicer) TestWebhook(_ context.Context, id uuid.UUID, req *models.TestWebhookRequest) error {
 	if m.TestWebhookFunc != nil {
 		return m.TestWebhookFunc(id, req)
@@ -464,6 +481,37 @@ func (m *mockBulkServicer) BulkAssignRoles(_ context.Context, req *models.BulkAs
 	return nil, nil
 }
 
+// ===========================================================================
+// mockBulkRoleJobServicer
+// ===========================================================================
+
+type mockBulkRoleJobServicer struct {
+	StartJobFunc func(req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error)
+	GetJobFunc   func(id uuid.UUID) (*models.BulkRoleJob, error)
+	ListJobsFunc func() (*models.BulkRoleJobListResponse, error)
+}
+
+func (m *mockBulkRoleJobServicer) StartJob(_ context.Context, req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error) {
+	if m.StartJobFunc != nil {
+		return m.StartJobFunc(req, createdBy)
+	}
+	return nil, nil
+}
+
+func (m *mockBulkRoleJobServicer) GetJob(_ context.Context, id uuid.UUID) (*models.BulkRoleJob, error) {
+	if m.GetJobFunc != nil {
+		return m.GetJobFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *mockBulkRoleJobServicer) ListJobs(_ context.Context) (*models.BulkRoleJobListResponse, error) {
+	if m.ListJobsFunc != nil {
+		return m.ListJobsFunc()
+	}
+	return nil, nil
+}
+
 // ===========================================================================
 // mockSMSServicer
 // ===========================================================================
@@ -554,6 +602,8 @@ type mockGroupServicer struct {
 	GetUserGroupsFunc             func(userID uuid.UUID) ([]*models.Group, error)
 	GetGroupMemberCountFunc       func(groupID uuid.UUID) (int, error)
 	GetGroupPermissionsFunc       func(groupID uuid.UUID) ([]uuid.UUID, error)
+	GetGroupRolesFunc             func(groupID uuid.UUID) (*models.GroupRolesResponse, error)
+	SetGroupRolesFunc             func(groupID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error)
 	SyncDynamicGroupMembersFunc   func(groupID uuid.UUID) error
 }
 
@@ -638,6 +688,20 @@ func (m *mockGroupServicer) GetGroupPermissions(_ context.Context, groupID uuid.
 	return nil, nil
 }
 
+func (m *mockGroupServicer) GetGroupRoles(_ context.Context, groupID uuid.UUID) (*models.GroupRolesResponse, error) {
+	if m.GetGroupRolesFunc != nil {
+		return m.GetGroupRolesFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockGroupServicer) SetGroupRoles(_ context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error) {
+	if m.SetGroupRolesFunc != nil {
+		return m.SetGroupRolesFunc(groupID, roleIDs)
+	}
+	return nil, nil
+}
+
 func (m *mockGroupServicer) SyncDynamicGroupMembers(_ context.Context, groupID uuid.UUID) error {
 	if m.SyncDynamicGroupMembersFunc != nil {
 		return m.SyncDynamicGroupMembersFunc(groupID)
@@ -1009,3 +1073,383 @@ func (m *mockApplicationServicer) GetAuthConfig(_ context.Context, app *models.A
 	}
 	return nil, nil
 }
+
+// ===========================================================================
+// mockOrgAdminServicer
+// ===========================================================================
+
+type mockOrgAdminServicer struct {
+	ListAdminsFunc      func(groupID uuid.UUID) (*models.GroupAdminListResponse, error)
+	AddAdminFunc        func(groupID, userID, createdBy uuid.UUID) (*models.GroupAdmin, error)
+	RemoveAdminFunc     func(groupID, userID uuid.UUID) error
+	ListManagedOrgsFunc func(userID uuid.UUID) (*models.OrgListResponse, error)
+	IsOrgAdminFunc      func(userID, groupID uuid.UUID) (bool, error)
+	ListUsersFunc       func(groupID uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error)
+	GetUserFunc         func(groupID, userID uuid.UUID) (*models.AdminUserResponse, error)
+	CreateUserFunc      func(groupID uuid.UUID, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	UpdateUserFunc      func(groupID, userID uuid.UUID, req *models.OrgUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	RemoveUserFunc      func(groupID, userID uuid.UUID) error
+	ListRolesFunc       func(groupID uuid.UUID) ([]models.Role, error)
+	AssignRoleFunc      func(groupID, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	RemoveRoleFunc      func(groupID, userID, roleID uuid.UUID) (*models.AdminUserResponse, error)
+	ListAPIKeysFunc     func(groupID uuid.UUID) (*models.AdminAPIKeyListResponse, error)
+	RevokeAPIKeyFunc    func(groupID, keyID uuid.UUID) error
+}
+
+func (m *mockOrgAdminServicer) ListAdmins(_ context.Context, groupID uuid.UUID) (*models.GroupAdminListResponse, error) {
+	if m.ListAdminsFunc != nil {
+		return m.ListAdminsFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) AddAdmin(_ context.Context, groupID, userID, createdBy uuid.UUID) (*models.GroupAdmin, error) {
+	if m.AddAdminFunc != nil {
+		return m.AddAdminFunc(groupID, userID, createdBy)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) RemoveAdmin(_ context.Context, groupID, userID uuid.UUID) error {
+	if m.RemoveAdminFunc != nil {
+		return m.RemoveAdminFunc(groupID, userID)
+	}
+	return nil
+}
+
+func (m *mockOrgAdminServicer) ListManagedOrgs(_ context.Context, userID uuid.UUID) (*models.OrgListResponse, error) {
+	if m.ListManagedOrgsFunc != nil {
+		return m.ListManagedOrgsFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) IsOrgAdmin(_ context.Context, userID, groupID uuid.UUID) (bool, error) {
+	if m.IsOrgAdminFunc != nil {
+		return m.IsOrgAdminFunc(userID, groupID)
+	}
+	return false, nil
+}
+
+func (m *mockOrgAdminServicer) ListUsers(_ context.Context, groupID uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc(groupID, search, page, pageSize)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) GetUser(_ context.Context, groupID, userID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(groupID, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) CreateUser(_ context.Context, groupID uuid.UUID, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(groupID, req, adminID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) UpdateUser(_ context.Context, groupID, userID uuid.UUID, req *models.OrgUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.UpdateUserFunc != nil {
+		return m.UpdateUserFunc(groupID, userID, req, adminID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) RemoveUser(_ context.Context, groupID, userID uuid.UUID) error {
+	if m.RemoveUserFunc != nil {
+		return m.RemoveUserFunc(groupID, userID)
+	}
+	return nil
+}
+
+func (m *mockOrgAdminServicer) ListRoles(_ context.Context, groupID uuid.UUID) ([]models.Role, error) {
+	if m.ListRolesFunc != nil {
+		return m.ListRolesFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) AssignRole(_ context.Context, groupID, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.AssignRoleFunc != nil {
+		return m.AssignRoleFunc(groupID, userID, roleID, adminID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) RemoveRole(_ context.Context, groupID, userID, roleID uuid.UUID) (*models.AdminUserResponse, error) {
+	if m.RemoveRoleFunc != nil {
+		return m.RemoveRoleFunc(groupID, userID, roleID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) ListAPIKeys(_ context.Context, groupID uuid.UUID) (*models.AdminAPIKeyListResponse, error) {
+	if m.ListAPIKeysFunc != nil {
+		return m.ListAPIKeysFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockOrgAdminServicer) RevokeAPIKey(_ context.Context, groupID, keyID uuid.UUID) error {
+	if m.RevokeAPIKeyFunc != nil {
+		return m.RevokeAPIKeyFunc(groupID, keyID)
+	}
+	return nil
+}
+
+// ===========================================================================
+// mockUsageReportServicer
+// ===========================================================================
+
+type mockUsageReportServicer struct {
+	GetReportFunc      func(groupID uuid.UUID) (*models.OrgUsageReport, error)
+	ListReportsFunc    func() (*models.OrgUsageReportListResponse, error)
+	GetSettingsFunc    func(groupID uuid.UUID) (*models.OrgUsageReportSettings, error)
+	UpdateSettingsFunc func(groupID uuid.UUID, req *models.UpdateOrgUsageReportSettingsRequest) (*models.OrgUsageReportSettings, error)
+	SendReportFunc     func(groupID uuid.UUID) (int, error)
+}
+
+func (m *mockUsageReportServicer) GetReport(_ context.Context, groupID uuid.UUID) (*models.OrgUsageReport, error) {
+	if m.GetReportFunc != nil {
+		return m.GetReportFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockUsageReportServicer) ListReports(_ context.Context) (*models.OrgUsageReportListResponse, error) {
+	if m.ListReportsFunc != nil {
+		return m.ListReportsFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockUsageReportServicer) GetSettings(_ context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error) {
+	if m.GetSettingsFunc != nil {
+		return m.GetSettingsFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockUsageReportServicer) UpdateSettings(_ context.Context, groupID uuid.UUID, req *models.UpdateOrgUsageReportSettingsRequest) (*models.OrgUsageReportSettings, error) {
+	if m.UpdateSettingsFunc != nil {
+		return m.UpdateSettingsFunc(groupID, req)
+	}
+	return nil, nil
+}
+
+func (m *mockUsageReportServicer) SendReport(_ context.Context, groupID uuid.UUID) (int, error) {
+	if m.SendReportFunc != nil {
+		return m.SendReportFunc(groupID)
+	}
+	return 0, nil
+}
+
+// ===========================================================================
+// mockSignupPolicyServicer
+// ===========================================================================
+
+type mockSignupPolicyServicer struct {
+	GetPolicyFunc    func() (*models.SignupPolicy, error)
+	UpdatePolicyFunc func(req *models.UpdateSignupPolicyRequest, updatedBy uuid.UUID) (*models.SignupPolicy, error)
+	ListDomainsFunc  func(groupID uuid.UUID) (*models.GroupDomainListResponse, error)
+	AddDomainFunc    func(groupID uuid.UUID, req *models.AddGroupDomainRequest, createdBy uuid.UUID) (*models.GroupDomain, error)
+	VerifyDomainFunc func(groupID, domainID uuid.UUID) (*models.GroupDomain, error)
+	RemoveDomainFunc func(groupID, domainID uuid.UUID) error
+}
+
+func (m *mockSignupPolicyServicer) GetPolicy(_ context.Context) (*models.SignupPolicy, error) {
+	if m.GetPolicyFunc != nil {
+		return m.GetPolicyFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockSignupPolicyServicer) UpdatePolicy(_ context.Context, req *models.UpdateSignupPolicyRequest, updatedBy uuid.UUID) (*models.SignupPolicy, error) {
+	if m.UpdatePolicyFunc != nil {
+		return m.UpdatePolicyFunc(req, updatedBy)
+	}
+	return nil, nil
+}
+
+func (m *mockSignupPolicyServicer) ListDomains(_ context.Context, groupID uuid.UUID) (*models.GroupDomainListResponse, error) {
+	if m.ListDomainsFunc != nil {
+		return m.ListDomainsFunc(groupID)
+	}
+	return nil, nil
+}
+
+func (m *mockSignupPolicyServicer) AddDomain(_ context.Context, groupID uuid.UUID, req *models.AddGroupDomainRequest, createdBy uuid.UUID) (*models.GroupDomain, error) {
+	if m.AddDomainFunc != nil {
+		return m.AddDomainFunc(groupID, req, createdBy)
+	}
+	return nil, nil
+}
+
+func (m *mockSignupPolicyServicer) VerifyDomain(_ context.Context, groupID, domainID uuid.UUID) (*models.GroupDomain, error) {
+	if m.VerifyDomainFunc != nil {
+		return m.VerifyDomainFunc(groupID, domainID)
+	}
+	return nil, nil
+}
+
+func (m *mockSignupPolicyServicer) RemoveDomain(_ context.Context, groupID, domainID uuid.UUID) error {
+	if m.RemoveDomainFunc != nil {
+		return m.RemoveDomainFunc(groupID, domainID)
+	}
+	return nil
+}
+
+// ===========================================================================
+// mockPasswordPolicyServicer
+// ===========================================================================
+
+type mockPasswordPolicyServicer struct {
+	GetPolicyFunc    func() (*models.PasswordPolicy, error)
+	UpdatePolicyFunc func(req *models.UpdatePasswordPolicyRequest, updatedBy uuid.UUID) (*models.PasswordPolicy, error)
+}
+
+func (m *mockPasswordPolicyServicer) GetPolicy(_ context.Context) (*models.PasswordPolicy, error) {
+	if m.GetPolicyFunc != nil {
+		return m.GetPolicyFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockPasswordPolicyServicer) UpdatePolicy(_ context.Context, req *models.UpdatePasswordPolicyRequest, updatedBy uuid.UUID) (*models.PasswordPolicy, error) {
+	if m.UpdatePolicyFunc != nil {
+		return m.UpdatePolicyFunc(req, updatedBy)
+	}
+	return nil, nil
+}
+
+// ===========================================================================
+// mockGuestServicer
+// ===========================================================================
+
+type mockGuestServicer struct {
+	CreateGuestFunc  func(ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error)
+	UpgradeGuestFunc func(userID uuid.UUID, req *models.UpgradeGuestRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error)
+}
+
+func (m *mockGuestServicer) CreateGuest(_ context.Context, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+	if m.CreateGuestFunc != nil {
+		return m.CreateGuestFunc(ip, userAgent, deviceInfo, appID)
+	}
+	return nil, nil
+}
+
+func (m *mockGuestServicer) UpgradeGuest(_ context.Context, userID uuid.UUID, req *models.UpgradeGuestRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+	if m.UpgradeGuestFunc != nil {
+		return m.UpgradeGuestFunc(userID, req, ip, userAgent, deviceInfo, appID)
+	}
+	return nil, nil
+}
+
+type mockDormantAccountServicer struct {
+	GetPolicyFunc    func() (*models.DormantAccountPolicy, error)
+	UpdatePolicyFunc func(req *models.UpdateDormantAccountPolicyRequest, updatedBy uuid.UUID) (*models.DormantAccountPolicy, error)
+	ReportFunc       func() (*models.DormantAccountReport, error)
+}
+
+func (m *mockDormantAccountServicer) GetPolicy(_ context.Context) (*models.DormantAccountPolicy, error) {
+	if m.GetPolicyFunc != nil {
+		return m.GetPolicyFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockDormantAccountServicer) UpdatePolicy(_ context.Context, req *models.UpdateDormantAccountPolicyRequest, updatedBy uuid.UUID) (*models.DormantAccountPolicy, error) {
+	if m.UpdatePolicyFunc != nil {
+		return m.UpdatePolicyFunc(req, updatedBy)
+	}
+	return nil, nil
+}
+
+func (m *mockDormantAccountServicer) Report(_ context.Context) (*models.DormantAccountReport, error) {
+	if m.ReportFunc != nil {
+		return m.ReportFunc()
+	}
+	return nil, nil
+}
+
+type mockMagicLinkServicer struct {
+	RequestFunc  func(req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error
+	VerifyFunc   func(token, ip, userAgent string, deviceInfo models.DeviceInfo) string
+	CompleteFunc func(code string) (*models.AuthResponse, error)
+}
+
+func (m *mockMagicLinkServicer) Request(_ context.Context, req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error {
+	if m.RequestFunc != nil {
+		return m.RequestFunc(req, appID, ip, userAgent)
+	}
+	return nil
+}
+
+func (m *mockMagicLinkServicer) Verify(_ context.Context, token, ip, userAgent string, deviceInfo models.DeviceInfo) string {
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(token, ip, userAgent, deviceInfo)
+	}
+	return ""
+}
+
+func (m *mockMagicLinkServicer) Complete(_ context.Context, code string) (*models.AuthResponse, error) {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(code)
+	}
+	return nil, nil
+}
+
+type mockOAuthServicer struct {
+	GenerateStateFunc      func() (string, error)
+	GetAuthURLFunc         func(provider models.OAuthProvider, state string, appID *uuid.UUID) (string, error)
+	HandleCallbackFunc     func(provider models.OAuthProvider, code, ipAddress, userAgent string, appID *uuid.UUID) (*models.OAuthLoginResponse, error)
+	IsProviderDisabledFunc func(provider models.OAuthProvider) bool
+}
+
+func (m *mockOAuthServicer) GenerateState() (string, error) {
+	if m.GenerateStateFunc != nil {
+		return m.GenerateStateFunc()
+	}
+	return "", nil
+}
+
+func (m *mockOAuthServicer) GetAuthURL(_ context.Context, provider models.OAuthProvider, state string, appID *uuid.UUID) (string, error) {
+	if m.GetAuthURLFunc != nil {
+		return m.GetAuthURLFunc(provider, state, appID)
+	}
+	return "", nil
+}
+
+func (m *mockOAuthServicer) ExchangeCode(_ context.Context, provider models.OAuthProvider, code string, appID *uuid.UUID) (*service.OAuthTokenResponse, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthServicer) GetUserInfo(_ context.Context, provider models.OAuthProvider, accessToken string, appID *uuid.UUID) (*models.OAuthUserInfo, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthServicer) HandleCallback(_ context.Context, provider models.OAuthProvider, code, ipAddress, userAgent string, appID *uuid.UUID) (*models.OAuthLoginResponse, error) {
+	if m.HandleCallbackFunc != nil {
+		return m.HandleCallbackFunc(provider, code, ipAddress, userAgent, appID)
+	}
+	return nil, nil
+}
+
+func (m *mockOAuthServicer) ConfirmLink(_ context.Context, req *models.ConfirmOAuthLinkRequest, ipAddress, userAgent string) (*models.OAuthLoginResponse, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthServicer) GetProviderToken(_ context.Context, userID uuid.UUID, provider models.OAuthProvider, appID *uuid.UUID) (*models.ProviderTokenResponse, error) {
+	return nil, nil
+}
+
+func (m *mockOAuthServicer) IsProviderDisabled(provider models.OAuthProvider) bool {
+	if m.IsProviderDisabledFunc != nil {
+		return m.IsProviderDisabledFunc(provider)
+	}
+	return false
+}
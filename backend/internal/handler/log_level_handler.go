@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// LogLevelHandler changes log levels at runtime (admin only)
+type LogLevelHandler struct {
+	logLevelService service.LogLevelServicer
+	auditService    service.AuditServicer
+	logger          *logger.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler
+func NewLogLevelHandler(logLevelService service.LogLevelServicer, auditService service.AuditServicer, logger *logger.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		logLevelService: logLevelService,
+		auditService:    auditService,
+		logger:          logger,
+	}
+}
+
+// GetLogLevel handles reading the current log levels
+// @Summary Get log levels
+// @Description Current log level, module levels and when a runtime change reverts
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.LogLevelResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/system/log-level [get]
+func (h *LogLevelHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, h.logLevelService.Get())
+}
+
+// SetLogLevel handles changing log levels at runtime
+// @Summary Set log levels
+// @Description Change the log level and the levels of individual modules on every gateway instance without a restart. Replaces any earlier change. Levels revert to the configured ones after duration_seconds, or LOG_LEVEL_REVERT_AFTER when not given.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.SetLogLevelRequest true "Log levels"
+// @Success 200 {object} models.LogLevelResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/system/log-level [put]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	resp, err := h.logLevelService.Set(c.Request.Context(), &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logger.Warn("Log levels changed", map[string]interface{}{
+		"level":      resp.Level,
+		"modules":    resp.Modules,
+		"reverts_at": resp.RevertsAt,
+		"admin_id":   adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionLogLevelSet,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"level":      resp.Level,
+			"modules":    resp.Modules,
+			"reverts_at": resp.RevertsAt,
+		},
+	})
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ResetLogLevel handles reverting runtime log level changes
+// @Summary Reset log levels
+// @Description Revert to the configured log levels now, on every gateway instance
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.LogLevelResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/system/log-level [delete]
+func (h *LogLevelHandler) ResetLogLevel(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	resp := h.logLevelService.Reset(c.Request.Context())
+
+	h.logger.Info("Log levels reset", map[string]interface{}{
+		"level":    resp.Level,
+		"admin_id": adminID.String(),
+	})
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionLogLevelReset,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+	})
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -219,7 +220,7 @@ func (h *LoginHandler) OTPVerifySubmit(c *gin.Context) {
 // @Success 302 {string} string "Redirect to login page"
 // @Router /logout [get]
 func (h *LoginHandler) Logout(c *gin.Context) {
-	c.SetCookie(sessionCookieName, "", -1, "/", "", h.secureCookie, true)
+	h.TerminateSession(c)
 
 	returnTo := c.Query("return_to")
 	if returnTo != "" {
@@ -229,6 +230,20 @@ func (h *LoginHandler) Logout(c *gin.Context) {
 	c.Redirect(http.StatusTemporaryRedirect, "/login")
 }
 
+// TerminateSession ends the login session of the request, if any. The session token is
+// revoked as on API logout, which also logs the user out of back-channel logout clients.
+func (h *LoginHandler) TerminateSession(c *gin.Context) {
+	sessionToken, err := c.Cookie(sessionCookieName)
+	if err == nil && sessionToken != "" {
+		if err := h.authService.Logout(c.Request.Context(), sessionToken, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+			h.logger.Warn("failed to revoke login session", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	c.SetCookie(sessionCookieName, "", -1, "/", "", h.secureCookie, true)
+}
+
 // SessionMiddleware validates session cookies and sets user context
 func (h *LoginHandler) SessionMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -264,17 +279,11 @@ func (h *LoginHandler) handlePasswordLogin(c *gin.Context, identifier, password,
 	userAgent := utils.GetUserAgent(c)
 	deviceInfo := utils.GetDeviceInfoFromContext(c)
 
-	var signInReq models.SignInRequest
-	if utils.IsValidEmail(identifier) {
-		signInReq.Email = utils.NormalizeEmail(identifier)
-	} else if utils.IsValidPhone(identifier) {
-		normalizedPhone := utils.NormalizePhone(identifier)
-		signInReq.Phone = &normalizedPhone
-	} else {
-		// Assume it's a username, try as email
-		signInReq.Email = identifier
+	// The auth service detects whether the identifier is an email, phone or username
+	signInReq := models.SignInRequest{
+		Identifier: identifier,
+		Password:   password,
 	}
-	signInReq.Password = password
 
 	// SignIn internally calls generateAuthResponse which creates session via SessionService
 	appID, _ := utils.GetApplicationIDFromContext(c)
@@ -287,7 +296,10 @@ func (h *LoginHandler) handlePasswordLogin(c *gin.Context, identifier, password,
 		})
 
 		errorMsg := "Invalid credentials"
-		if appErr, ok := err.(*models.AppError); ok {
+		var changeErr *models.PasswordChangeRequiredError
+		if errors.As(err, &changeErr) {
+			errorMsg = "Your password must be changed before you can sign in."
+		} else if appErr, ok := err.(*models.AppError); ok {
 			errorMsg = appErr.Message
 		}
 		h.renderLoginError(c, returnTo, errorMsg, identifier, "password")
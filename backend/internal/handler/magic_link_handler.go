@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// MagicLinkHandler handles passwordless sign-in by emailed one-click links
+type MagicLinkHandler struct {
+	magicLinkService service.MagicLinkServicer
+	logger           *logger.Logger
+}
+
+// NewMagicLinkHandler creates a new magic link handler
+func NewMagicLinkHandler(magicLinkService service.MagicLinkServicer, log *logger.Logger) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		magicLinkService: magicLinkService,
+		logger:           log,
+	}
+}
+
+// RequestMagicLink handles emailing a sign-in link
+// @Summary Request magic link
+// @Description Email a single-use sign-in link. The response is the same whether or not the account exists. redirect_url must be the configured redirect URL, one of the allowed redirect URLs or a callback URL of the application; it defaults to the configured redirect URL. Applications that restrict auth methods must allow the "magic_link" method.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.RequestMagicLinkRequest true "Email and redirect URL"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/magic-link/request [post]
+func (h *MagicLinkHandler) RequestMagicLink(c *gin.Context) {
+	var req models.RequestMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	appID, _ := utils.GetApplicationIDFromContext(c)
+	if err := h.magicLinkService.Request(c.Request.Context(), &req, appID, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{
+		Message: "If an account exists for this email, a sign-in link has been sent to it",
+	})
+}
+
+// VerifyMagicLink handles a click on an emailed link
+// @Summary Open magic link
+// @Description Target of emailed links. Signs the user in and redirects to the app with a code parameter to exchange at /api/auth/magic-link/complete, or with an error parameter: invalid_link, access_denied or server_error.
+// @Tags Authentication
+// @Param token query string true "Link token"
+// @Success 302 "Redirect to the app"
+// @Router /api/auth/magic-link/verify [get]
+func (h *MagicLinkHandler) VerifyMagicLink(c *gin.Context) {
+	redirectURL := h.magicLinkService.Verify(
+		c.Request.Context(),
+		c.Query("token"),
+		utils.GetClientIP(c),
+		utils.GetUserAgent(c),
+		utils.GetDeviceInfoFromContext(c),
+	)
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// CompleteMagicLink handles exchanging the redirect code for the session
+// @Summary Complete magic link sign-in
+// @Description Exchange the code the app received on its redirect URL for tokens. The code works once and expires after two minutes. When the user has 2FA enabled, the response asks for the second factor instead.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.CompleteMagicLinkRequest true "Redirect code"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/magic-link/complete [post]
+func (h *MagicLinkHandler) CompleteMagicLink(c *gin.Context) {
+	var req models.CompleteMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	authResp, err := h.magicLinkService.Complete(c.Request.Context(), req.Code)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authResp)
+}
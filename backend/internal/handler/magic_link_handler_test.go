@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMagicLinkHandler() (*mockMagicLinkServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockMagicLinkServicer{}
+	h := NewMagicLinkHandler(svc, testLogger())
+
+	r := gin.New()
+	r.POST("/magic-link/request", h.RequestMagicLink)
+	r.GET("/magic-link/verify", h.VerifyMagicLink)
+	r.POST("/magic-link/complete", h.CompleteMagicLink)
+	return svc, r
+}
+
+func TestMagicLinkHandler_RequestMagicLink(t *testing.T) {
+	t.Run("Sends the link", func(t *testing.T) {
+		svc, r := setupMagicLinkHandler()
+		var got *models.RequestMagicLinkRequest
+		svc.RequestFunc = func(req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error {
+			got = req
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		body := `{"email":"user@example.com","redirect_url":"https://app.example.com/callback"}`
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/magic-link/request", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got)
+		assert.Equal(t, "user@example.com", got.Email)
+		assert.Equal(t, "https://app.example.com/callback", got.RedirectURL)
+	})
+
+	t.Run("Rejects an invalid email", func(t *testing.T) {
+		svc, r := setupMagicLinkHandler()
+		svc.RequestFunc = func(req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error {
+			t.Fatal("service must not be called")
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/magic-link/request", strings.NewReader(`{"email":"nope"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Passes service errors", func(t *testing.T) {
+		svc, r := setupMagicLinkHandler()
+		svc.RequestFunc = func(req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error {
+			return models.NewAppError(http.StatusBadRequest, "Redirect URL is not allowed")
+		}
+
+		w := httptest.NewRecorder()
+		body := `{"email":"user@example.com","redirect_url":"https://evil.example.com/"}`
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/magic-link/request", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMagicLinkHandler_VerifyMagicLink(t *testing.T) {
+	svc, r := setupMagicLinkHandler()
+	svc.VerifyFunc = func(token, ip, userAgent string, deviceInfo models.DeviceInfo) string {
+		assert.Equal(t, "link-token", token)
+		return "https://app.example.com/callback?code=abc"
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/magic-link/verify?token=link-token", nil))
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/callback?code=abc", w.Header().Get("Location"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestMagicLinkHandler_CompleteMagicLink(t *testing.T) {
+	t.Run("Returns the session", func(t *testing.T) {
+		svc, r := setupMagicLinkHandler()
+		svc.CompleteFunc = func(code string) (*models.AuthResponse, error) {
+			assert.Equal(t, "abc", code)
+			return &models.AuthResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/magic-link/complete", strings.NewReader(`{"code":"abc"}`)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.AuthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "access", resp.AccessToken)
+	})
+
+	t.Run("Requires a code", func(t *testing.T) {
+		_, r := setupMagicLinkHandler()
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/magic-link/complete", strings.NewReader(`{}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -240,6 +240,80 @@ func (h *OAuthAdminHandler) RotateSecret(c *gin.Context) {
 	})
 }
 
+// EmulateFlow emulates the authorization code flow of a client
+// @Summary Emulate OAuth client flow
+// @Description Walk an OAuth 2.0 client through authorize, consent and token issuance with a synthetic user and return a step-by-step trace of the checks performed, the scopes granted and the token claims. Nothing is stored and no tokens are issued (admin only)
+// @Tags Admin - OAuth Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param request body models.OAuthClientEmulateRequest false "Authorization request to emulate"
+// @Success 200 {object} models.OAuthClientEmulateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/clients/{id}/emulate [post]
+func (h *OAuthAdminHandler) EmulateFlow(c *gin.Context) {
+	clientID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.OAuthClientEmulateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+	}
+
+	trace, err := h.service.EmulateClientFlow(c.Request.Context(), clientID, &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trace)
+}
+
+// PreviewToken previews the claims of tokens issued to a user through a client
+// @Summary Preview token claims
+// @Description Build the exact access token, ID token and userinfo claims the gateway would issue to a user through an OAuth client, with the user's current roles and the client's scope rules applied, and list the policies that would block issuance. Nothing is signed or stored (admin only)
+// @Tags Admin - Debug
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.TokenPreviewRequest true "User and client to preview tokens for"
+// @Success 200 {object} models.TokenPreviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/debug/token-preview [post]
+func (h *OAuthAdminHandler) PreviewToken(c *gin.Context) {
+	var req models.TokenPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	preview, err := h.service.PreviewTokens(c.Request.Context(), &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
 // ListScopes lists all OAuth scopes
 // @Summary List OAuth scopes
 // @Description Get list of all OAuth 2.0 scopes (admin only)
@@ -341,6 +415,129 @@ func (h *OAuthAdminHandler) DeleteScope(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ListScopeGroups lists all OAuth scope groups
+// @Summary List OAuth scope groups
+// @Description Get list of all OAuth 2.0 scope groups with the scopes each currently expands to (admin only)
+// @Tags Admin - OAuth Scopes
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.OAuthScopeGroupListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/scope-groups [get]
+func (h *OAuthAdminHandler) ListScopeGroups(c *gin.Context) {
+	groups, err := h.service.ListScopeGroups(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list OAuth scope groups", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OAuthScopeGroupListResponse{Groups: groups, Total: len(groups)})
+}
+
+// CreateScopeGroup creates an OAuth scope group
+// @Summary Create OAuth scope group
+// @Description Create a named group of scopes. Members may be scopes, "prefix:*" wildcards matching registered scopes, or other groups. Clients can be allowed, and can request, the group name; it is expanded at consent and token issuance (admin only)
+// @Tags Admin - OAuth Scopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateOAuthScopeGroupRequest true "Scope group data"
+// @Success 201 {object} models.OAuthScopeGroup
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/scope-groups [post]
+func (h *OAuthAdminHandler) CreateScopeGroup(c *gin.Context) {
+	var req models.CreateOAuthScopeGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	group, err := h.service.CreateScopeGroup(c.Request.Context(), &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateScopeGroup updates an OAuth scope group
+// @Summary Update OAuth scope group
+// @Description Update the display name, description or members of a scope group. Existing consents and tokens keep the scopes they were granted (admin only)
+// @Tags Admin - OAuth Scopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Scope group ID"
+// @Param request body models.UpdateOAuthScopeGroupRequest true "Scope group update data"
+// @Success 200 {object} models.OAuthScopeGroup
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/scope-groups/{id} [put]
+func (h *OAuthAdminHandler) UpdateScopeGroup(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOAuthScopeGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	group, err := h.service.UpdateScopeGroup(c.Request.Context(), groupID, &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteScopeGroup deletes an OAuth scope group
+// @Summary Delete OAuth scope group
+// @Description Delete a scope group. Clients that still list the group name in their allowed scopes can no longer use it (admin only)
+// @Tags Admin - OAuth Scopes
+// @Security BearerAuth
+// @Param id path string true "Scope group ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/scope-groups/{id} [delete]
+func (h *OAuthAdminHandler) DeleteScopeGroup(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteScopeGroup(c.Request.Context(), groupID); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ListClientConsents lists all user consents for a client
 // @Summary List client consents
 // @Description Get all user consents for a specific OAuth client (admin only)
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// multipartOverheadBytes leaves room for multipart headers and boundaries around the logo
+const multipartOverheadBytes = 16 * 1024
+
+var errLogoRequestTooLarge = models.NewAppError(http.StatusRequestEntityTooLarge, "Logo is too large")
+
+// OAuthClientLogoHandler handles uploading OAuth client logos and serving them to browsers
+type OAuthClientLogoHandler struct {
+	service service.OAuthClientLogoServicer
+	logger  *logger.Logger
+}
+
+// NewOAuthClientLogoHandler creates a new OAuth client logo handler
+func NewOAuthClientLogoHandler(service service.OAuthClientLogoServicer, logger *logger.Logger) *OAuthClientLogoHandler {
+	return &OAuthClientLogoHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Upload uploads a logo for an OAuth client
+// @Summary Upload OAuth client logo
+// @Description Upload a PNG, JPEG, GIF or WebP logo for an OAuth client, either as the raw request body or as the "logo" field of a multipart form. The format is detected from the content and the size is limited by OIDC_CLIENT_LOGO_MAX_BYTES. The client's logo_url is set to a content-addressed URL served by the gateway. Only logos uploaded this way are shown on the consent page (admin only)
+// @Tags Admin - OAuth Clients
+// @Security BearerAuth
+// @Accept image/png,image/jpeg,image/gif,image/webp,multipart/form-data
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param logo formData file false "Logo image"
+// @Success 200 {object} models.OAuthClient
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 415 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/clients/{id}/logo [put]
+func (h *OAuthClientLogoHandler) Upload(c *gin.Context) {
+	clientID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.service.MaxBytes()+multipartOverheadBytes))
+
+	var content io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("logo")
+		if err != nil {
+			if isMaxBytesError(err) {
+				utils.RespondWithError(c, errLogoRequestTooLarge)
+				return
+			}
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+		defer file.Close()
+		content = file
+	}
+
+	client, err := h.service.Upload(c.Request.Context(), clientID, content)
+	if err != nil {
+		if isMaxBytesError(err) {
+			err = errLogoRequestTooLarge
+		}
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to upload OAuth client logo", map[string]interface{}{
+				"error":     err.Error(),
+				"client_id": clientID.String(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// Delete removes the logo of an OAuth client
+// @Summary Delete OAuth client logo
+// @Description Remove the uploaded logo of an OAuth client and clear its logo_url (admin only)
+// @Tags Admin - OAuth Clients
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Client ID"
+// @Success 200 {object} models.OAuthClient
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/clients/{id}/logo [delete]
+func (h *OAuthClientLogoHandler) Delete(c *gin.Context) {
+	clientID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	client, err := h.service.Delete(c.Request.Context(), clientID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// Serve serves an uploaded OAuth client logo
+// @Summary Get OAuth client logo
+// @Description Serve an uploaded OAuth client logo. URLs are content-addressed, so responses are immutable and can be cached by browsers and CDNs.
+// @Tags OAuth Provider
+// @Produce image/png,image/jpeg,image/gif,image/webp
+// @Param name path string true "Logo file name (content hash and extension)"
+// @Success 200 {file} binary
+// @Success 304 "Not Modified"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /oauth/logos/{name} [get]
+func (h *OAuthClientLogoHandler) Serve(c *gin.Context) {
+	logo, err := h.service.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	etag := `"` + logo.Hash + `"`
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", etag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; sandbox")
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, logo.ContentType, logo.Content)
+}
+
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
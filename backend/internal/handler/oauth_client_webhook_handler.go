@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// OAuthClientWebhookHandler lets OAuth client owners manage webhooks for events about their clients
+type OAuthClientWebhookHandler struct {
+	service service.OAuthClientWebhookServicer
+	logger  *logger.Logger
+}
+
+// NewOAuthClientWebhookHandler creates a new OAuth client webhook handler
+func NewOAuthClientWebhookHandler(service service.OAuthClientWebhookServicer, logger *logger.Logger) *OAuthClientWebhookHandler {
+	return &OAuthClientWebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List lists the webhooks of an OAuth client
+// @Summary List OAuth client webhooks
+// @Description List the webhooks registered for an OAuth client owned by the current user, along with the events they can subscribe to
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Client ID"
+// @Success 200 {object} models.OAuthClientWebhookListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks [get]
+func (h *OAuthClientWebhookHandler) List(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListWebhooks(c.Request.Context(), ownerID, clientID)
+	if err != nil {
+		h.respondWithError(c, "Failed to list oauth client webhooks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create registers a webhook for an OAuth client
+// @Summary Create OAuth client webhook
+// @Description Register an https webhook for consent, token revocation and secret rotation events of an OAuth client owned by the current user. Deliveries are signed with HMAC-SHA256 in X-AuthGateway-Signature (sha256=<hex>) and X-Webhook-Signature (hex); the secret key is only returned once
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param request body models.CreateOAuthClientWebhookRequest true "Webhook data"
+// @Success 201 {object} models.CreateOAuthClientWebhookResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks [post]
+func (h *OAuthClientWebhookHandler) Create(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOAuthClientWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	resp, err := h.service.CreateWebhook(c.Request.Context(), ownerID, clientID, &req)
+	if err != nil {
+		h.respondWithError(c, "Failed to create oauth client webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// Get retrieves a webhook of an OAuth client
+// @Summary Get OAuth client webhook
+// @Description Get a webhook of an OAuth client owned by the current user
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} models.OAuthClientWebhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks/{webhook_id} [get]
+func (h *OAuthClientWebhookHandler) Get(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+	webhookID, ok := utils.ParseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.GetWebhook(c.Request.Context(), ownerID, clientID, webhookID)
+	if err != nil {
+		h.respondWithError(c, "Failed to get oauth client webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// Update updates a webhook of an OAuth client
+// @Summary Update OAuth client webhook
+// @Description Change the URL, events or active flag of a webhook of an OAuth client owned by the current user
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Param request body models.UpdateOAuthClientWebhookRequest true "Fields to update"
+// @Success 200 {object} models.OAuthClientWebhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks/{webhook_id} [put]
+func (h *OAuthClientWebhookHandler) Update(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+	webhookID, ok := utils.ParseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOAuthClientWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(c.Request.Context(), ownerID, clientID, webhookID, &req)
+	if err != nil {
+		h.respondWithError(c, "Failed to update oauth client webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// Delete removes a webhook of an OAuth client
+// @Summary Delete OAuth client webhook
+// @Description Delete a webhook of an OAuth client owned by the current user along with its deliveries
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks/{webhook_id} [delete]
+func (h *OAuthClientWebhookHandler) Delete(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+	webhookID, ok := utils.ParseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), ownerID, clientID, webhookID); err != nil {
+		h.respondWithError(c, "Failed to delete oauth client webhook", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries lists the deliveries of a webhook of an OAuth client
+// @Summary List OAuth client webhook deliveries
+// @Description Get a paginated list of delivery attempts of a webhook of an OAuth client owned by the current user, newest first
+// @Tags OAuth Client Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.OAuthClientWebhookDeliveryListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/oauth-clients/{id}/webhooks/{webhook_id}/deliveries [get]
+func (h *OAuthClientWebhookHandler) ListDeliveries(c *gin.Context) {
+	ownerID, clientID, ok := h.ownerAndClient(c)
+	if !ok {
+		return
+	}
+	webhookID, ok := utils.ParseUUIDParam(c, "webhook_id")
+	if !ok {
+		return
+	}
+
+	page, pageSize := utils.ParsePagination(c)
+
+	resp, err := h.service.ListDeliveries(c.Request.Context(), ownerID, clientID, webhookID, page, pageSize)
+	if err != nil {
+		h.respondWithError(c, "Failed to list oauth client webhook deliveries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OAuthClientWebhookHandler) ownerAndClient(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	ownerID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	clientID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return ownerID, clientID, true
+}
+
+func (h *OAuthClientWebhookHandler) respondWithError(c *gin.Context, message string, err error) {
+	if _, ok := err.(*models.AppError); !ok {
+		h.logger.Error(message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.RespondWithError(c, err)
+}
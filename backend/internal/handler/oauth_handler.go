@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
@@ -44,9 +45,9 @@ func NewOAuthHandler(
 
 // Login initiates OAuth login flow
 // @Summary Initiate OAuth login
-// @Description Redirect to OAuth provider for authentication (Google, Yandex, GitHub, Instagram, Telegram, 1C)
+// @Description Redirect to OAuth provider for authentication (Google, Yandex, GitHub, Instagram, Telegram, 1C, Apple, Microsoft, LinkedIn)
 // @Tags OAuth
-// @Param provider path string true "OAuth provider" Enums(google, yandex, github, instagram, telegram, onec)
+// @Param provider path string true "OAuth provider" Enums(google, yandex, github, instagram, telegram, onec, apple, microsoft, linkedin, oidc-generic)
 // @Success 302 {string} string "Redirect to OAuth provider"
 // @Failure 400 {object} models.ErrorResponse "Invalid provider"
 // @Failure 500 {object} models.ErrorResponse "Server error"
@@ -70,7 +71,11 @@ func (h *OAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Store state in session/cookie for validation
+	// Store state in session/cookie for validation. Apple posts the callback from its own
+	// site, which browsers only send cookies along with when they are SameSite=None.
+	if provider == string(models.ProviderApple) && h.secureCookie {
+		c.SetSameSite(http.SameSiteNoneMode)
+	}
 	c.SetCookie("oauth_state", state, 600, "/", "", h.secureCookie, true) // 10 minutes
 
 	// Get authorization URL (with optional app context)
@@ -95,22 +100,32 @@ func (h *OAuthHandler) Login(c *gin.Context) {
 
 // Callback handles OAuth callback
 // @Summary Handle OAuth callback
-// @Description Process OAuth callback from provider, create or login user, and redirect with tokens
+// @Description Process OAuth callback from provider, create or login user, and redirect with tokens. When the provider email belongs to an existing account that has to be linked by confirming its password, link_required and link_token are returned instead of tokens. Apple posts the code and state as form data instead of query parameters.
 // @Tags OAuth
 // @Produce json
-// @Param provider path string true "OAuth provider" Enums(google, yandex, github, instagram, onec)
+// @Param provider path string true "OAuth provider" Enums(google, yandex, github, instagram, onec, apple, microsoft, linkedin, oidc-generic)
 // @Param code query string true "Authorization code from OAuth provider"
 // @Param state query string true "CSRF state parameter"
 // @Param response_type query string false "Response type: 'json' for JSON response, otherwise redirect" Enums(json)
 // @Success 200 {object} models.OAuthLoginResponse "JSON response when response_type=json"
 // @Success 302 {string} string "Redirect to frontend with tokens"
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 403 {object} models.ErrorResponse "Automatic user creation is disabled"
+// @Failure 409 {object} models.ErrorResponse "Email belongs to an existing account"
 // @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /api/auth/{provider}/callback [get]
+// @Router /api/auth/{provider}/callback [post]
 func (h *OAuthHandler) Callback(c *gin.Context) {
 	provider := c.Param("provider")
 	code := c.Query("code")
 	state := c.Query("state")
+	// The browser has to follow the redirect with a GET after a posted callback
+	redirectStatus := http.StatusTemporaryRedirect
+	if c.Request.Method == http.MethodPost {
+		code = c.PostForm("code")
+		state = c.PostForm("state")
+		redirectStatus = http.StatusSeeOther
+	}
 
 	if !models.IsValidProvider(provider) {
 		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidProvider))
@@ -152,6 +167,10 @@ func (h *OAuthHandler) Callback(c *gin.Context) {
 	)
 
 	if err != nil {
+		if appErr, ok := err.(*models.AppError); ok {
+			c.JSON(appErr.Code, models.NewErrorResponse(appErr))
+			return
+		}
 		h.logger.Error("OAuth callback failed", map[string]interface{}{
 			"error":    err.Error(),
 			"provider": provider,
@@ -167,9 +186,19 @@ func (h *OAuthHandler) Callback(c *gin.Context) {
 		return
 	}
 
+	frontendURL := getEnv("FRONTEND_URL", "http://localhost:3001")
+
+	// Let the frontend ask for the password of the existing account
+	if response.LinkRequired {
+		c.Redirect(redirectStatus, fmt.Sprintf("%s/auth/callback?link_required=true&link_token=%s",
+			frontendURL,
+			url.QueryEscape(response.LinkToken),
+		))
+		return
+	}
+
 	// Redirect to frontend with tokens in URL (not recommended for production)
 	// Better approach: set httpOnly cookies or use a redirect with a one-time code
-	frontendURL := getEnv("FRONTEND_URL", "http://localhost:3001")
 	redirectURL := fmt.Sprintf("%s/auth/callback?access_token=%s&refresh_token=%s&is_new_user=%v",
 		frontendURL,
 		response.AccessToken,
@@ -177,7 +206,44 @@ func (h *OAuthHandler) Callback(c *gin.Context) {
 		response.IsNewUser,
 	)
 
-	c.Redirect(http.StatusTemporaryRedirect, redirectURL)
+	c.Redirect(redirectStatus, redirectURL)
+}
+
+// ConfirmLink links a provider account to an existing account
+// @Summary Confirm OAuth account link
+// @Description Link the provider account from a social login to the existing account with the same email by confirming that account's password, then sign in. Accounts with 2FA also need a code from the authenticator app.
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ConfirmOAuthLinkRequest true "Link token and password"
+// @Success 200 {object} models.OAuthLoginResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Invalid link token, password or 2FA code"
+// @Failure 409 {object} models.ErrorResponse "Provider account is linked to another account"
+// @Failure 429 {object} models.ErrorResponse "Too many attempts"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/auth/oauth/link/confirm [post]
+func (h *OAuthHandler) ConfirmLink(c *gin.Context) {
+	var req models.ConfirmOAuthLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	response, err := h.oauthService.ConfirmLink(c.Request.Context(), &req, utils.GetClientIP(c), c.Request.UserAgent())
+	if err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to confirm OAuth link", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // TelegramCallback handles Telegram widget callback
@@ -308,6 +374,48 @@ func formatTelegramValue(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
+// GetProviderToken returns the provider access token of the current user
+// @Summary Get provider access token
+// @Description Return the access token issued by an OAuth provider the user signed in with, so applications can call the provider's API on behalf of the user. Expired tokens are refreshed first. Requires OAUTH_STORE_PROVIDER_TOKENS.
+// @Tags OAuth
+// @Security BearerAuth
+// @Produce json
+// @Param provider path string true "OAuth provider" Enums(google, yandex, github, instagram, onec)
+// @Success 200 {object} models.ProviderTokenResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid provider"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No provider token available"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /api/auth/{provider}/token [get]
+func (h *OAuthHandler) GetProviderToken(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	provider := c.Param("provider")
+	if !models.IsValidProvider(provider) {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidProvider))
+		return
+	}
+
+	appID, _ := utils.GetApplicationIDFromContext(c)
+	token, err := h.oauthService.GetProviderToken(c.Request.Context(), userID, models.OAuthProvider(provider), appID)
+	if err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to get provider token", map[string]interface{}{
+				"error":    err.Error(),
+				"provider": provider,
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
 // GetProviders returns available OAuth providers
 // @Summary Get available OAuth providers
 // @Description List all configured OAuth providers with their enabled status
@@ -353,6 +461,35 @@ func (h *OAuthHandler) GetProviders(c *gin.Context) {
 			IconURL:     "/icons/onec.svg",
 			Enabled:     getEnv("OAUTH_ONEC_ENABLED", "") == "true" && getEnv("OAUTH_ONEC_CLIENT_ID", "") != "",
 		},
+		{
+			Name:        "apple",
+			DisplayName: "Apple",
+			IconURL:     "/icons/apple.svg",
+			Enabled:     getEnv("APPLE_CLIENT_ID", "") != "" && getEnv("APPLE_PRIVATE_KEY", "") != "",
+		},
+		{
+			Name:        "microsoft",
+			DisplayName: "Microsoft",
+			IconURL:     "/icons/microsoft.svg",
+			Enabled:     getEnv("MICROSOFT_CLIENT_ID", "") != "",
+		},
+		{
+			Name:        "linkedin",
+			DisplayName: "LinkedIn",
+			IconURL:     "/icons/linkedin.svg",
+			Enabled:     getEnv("LINKEDIN_CLIENT_ID", "") != "",
+		},
+		{
+			Name:        "oidc-generic",
+			DisplayName: getEnv("OAUTH_OIDC_DISPLAY_NAME", "Single Sign-On"),
+			IconURL:     "/icons/oidc.svg",
+			Enabled:     getEnv("OAUTH_OIDC_ENABLED", "") == "true" && getEnv("OAUTH_OIDC_CLIENT_ID", "") != "",
+		},
+	}
+	for i := range providers {
+		if h.oauthService.IsProviderDisabled(models.OAuthProvider(providers[i].Name)) {
+			providers[i].Enabled = false
+		}
 	}
 
 	c.JSON(http.StatusOK, providers)
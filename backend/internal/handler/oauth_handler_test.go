@@ -4,13 +4,20 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const testBotToken = "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"
@@ -142,3 +149,101 @@ func TestVerifyTelegramAuth_AuthDateNotNumber(t *testing.T) {
 
 	assert.False(t, h.verifyTelegramAuth(data))
 }
+
+func setupOAuthHandler(secureCookie bool) (*mockOAuthServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockOAuthServicer{}
+	h := NewOAuthHandler(svc, testLogger(), "", secureCookie)
+
+	r := gin.New()
+	r.GET("/auth/:provider", h.Login)
+	r.GET("/auth/:provider/callback", h.Callback)
+	r.POST("/auth/:provider/callback", h.Callback)
+	return svc, r
+}
+
+func TestOAuthHandler_Login_AppleStateCookie(t *testing.T) {
+	svc, r := setupOAuthHandler(true)
+	svc.GenerateStateFunc = func() (string, error) { return "state-123", nil }
+	svc.GetAuthURLFunc = func(provider models.OAuthProvider, state string, appID *uuid.UUID) (string, error) {
+		return "https://appleid.apple.com/auth/authorize?state=" + state, nil
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/apple", nil))
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "oauth_state", cookies[0].Name)
+	// Apple posts the callback cross-site
+	assert.Equal(t, http.SameSiteNoneMode, cookies[0].SameSite)
+	assert.True(t, cookies[0].Secure)
+}
+
+func TestOAuthHandler_Callback_Posted(t *testing.T) {
+	t.Setenv("FRONTEND_URL", "https://app.example.com")
+
+	t.Run("Signs in with the posted code", func(t *testing.T) {
+		svc, r := setupOAuthHandler(true)
+		var gotProvider models.OAuthProvider
+		var gotCode string
+		svc.HandleCallbackFunc = func(provider models.OAuthProvider, code, ipAddress, userAgent string, appID *uuid.UUID) (*models.OAuthLoginResponse, error) {
+			gotProvider, gotCode = provider, code
+			return &models.OAuthLoginResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/apple/callback", strings.NewReader("code=auth-code&state=state-123"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "state-123"})
+		r.ServeHTTP(w, req)
+
+		// The browser must follow with a GET
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Location"), "https://app.example.com/auth/callback?access_token=access"))
+		assert.Equal(t, models.ProviderApple, gotProvider)
+		assert.Equal(t, "auth-code", gotCode)
+	})
+
+	t.Run("Rejects a state mismatch", func(t *testing.T) {
+		svc, r := setupOAuthHandler(true)
+		svc.HandleCallbackFunc = func(provider models.OAuthProvider, code, ipAddress, userAgent string, appID *uuid.UUID) (*models.OAuthLoginResponse, error) {
+			t.Fatal("service must not be called")
+			return nil, nil
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/apple/callback?state=state-123", strings.NewReader("code=auth-code&state=other"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: "oauth_state", Value: "state-123"})
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestOAuthHandler_GetProviders_ShouldReportDisabledProviders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GOOGLE_CLIENT_ID", "google-client")
+	t.Setenv("GITHUB_CLIENT_ID", "github-client")
+	svc := &mockOAuthServicer{
+		IsProviderDisabledFunc: func(provider models.OAuthProvider) bool { return provider == models.ProviderGoogle },
+	}
+	h := NewOAuthHandler(svc, testLogger(), "", false)
+	r := gin.New()
+	r.GET("/providers", h.GetProviders)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/providers", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var providers []models.OAuthProviderInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &providers))
+	enabled := make(map[string]bool)
+	for _, p := range providers {
+		enabled[p.Name] = p.Enabled
+	}
+	assert.False(t, enabled["google"])
+	assert.True(t, enabled["github"])
+}
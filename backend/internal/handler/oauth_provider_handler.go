@@ -4,12 +4,14 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"html"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -17,17 +19,37 @@ import (
 )
 
 type OAuthProviderHandler struct {
-	service service.OAuthProviderServicer
-	logger  *logger.Logger
+	service   service.OAuthProviderServicer
+	pageState *pageStateSigner
+	sessions  SessionTerminator
+	logger    *logger.Logger
 }
 
-func NewOAuthProviderHandler(service service.OAuthProviderServicer, logger *logger.Logger) *OAuthProviderHandler {
+// SessionTerminator ends the gateway login session carried by a request
+type SessionTerminator interface {
+	TerminateSession(c *gin.Context)
+}
+
+// NewOAuthProviderHandler creates the OAuth provider handler. pageStateSecret signs the state
+// of the consent and device pages and must be the same on every replica.
+func NewOAuthProviderHandler(service service.OAuthProviderServicer, pageStateSecret string, logger *logger.Logger) *OAuthProviderHandler {
 	return &OAuthProviderHandler{
-		service: service,
-		logger:  logger,
+		service:   service,
+		pageState: newPageStateSigner(pageStateSecret),
+		logger:    logger,
 	}
 }
 
+// SetSessionTerminator sets what ends the login session at the end session endpoint
+func (h *OAuthProviderHandler) SetSessionTerminator(sessions SessionTerminator) {
+	h.sessions = sessions
+}
+
+// consentParams are the authorization request parameters carried from the consent page to its submission
+var consentParams = []string{
+	"client_id", "redirect_uri", "scope", "state", "response_type", "response_mode", "nonce", "code_challenge", "code_challenge_method",
+}
+
 // Authorize handles OAuth 2.0 authorization requests
 // @Summary OAuth 2.0 Authorization
 // @Description Initiates OAuth 2.0 authorization flow. Redirects user to login if not authenticated, then to consent page if required
@@ -43,13 +65,17 @@ func NewOAuthProviderHandler(service service.OAuthProviderServicer, logger *logg
 // @Param code_challenge query string false "PKCE code challenge"
 // @Param code_challenge_method query string false "PKCE code challenge method (S256 or plain)"
 // @Param prompt query string false "Prompt behavior (none, login, consent, select_account)"
+// @Param response_mode query string false "How the response is returned; the jwt modes return it as a signed JWT in the response parameter (JARM)" Enums(query, fragment, jwt, query.jwt, fragment.jwt)
 // @Success 302 {string} string "Redirect to callback with authorization code"
 // @Failure 302 {string} string "Redirect with error"
 // @Router /oauth/authorize [get]
 func (h *OAuthProviderHandler) Authorize(c *gin.Context) {
 	var req models.AuthorizeRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		h.redirectError(c, req.RedirectURI, "invalid_request", err.Error(), req.State)
+		if !isValidResponseMode(req.ResponseMode) {
+			req.ResponseMode = ""
+		}
+		h.redirectError(c, &req, "invalid_request", err.Error())
 		return
 	}
 
@@ -70,12 +96,11 @@ func (h *OAuthProviderHandler) Authorize(c *gin.Context) {
 
 		errorCode := h.mapErrorToOAuthCode(err)
 		errorDesc := err.Error()
-		h.redirectError(c, req.RedirectURI, errorCode, errorDesc, req.State)
+		h.redirectError(c, &req, errorCode, errorDesc)
 		return
 	}
 
-	redirectURL := h.buildSuccessRedirect(req.RedirectURI, authResp.Code, authResp.State)
-	c.Redirect(http.StatusTemporaryRedirect, redirectURL)
+	h.redirectSuccess(c, &req, authResp.Code, authResp.State)
 }
 
 // Token handles OAuth 2.0 token requests
@@ -90,6 +115,7 @@ func (h *OAuthProviderHandler) Authorize(c *gin.Context) {
 // @Param client_id formData string true "Client ID"
 // @Param client_secret formData string false "Client secret"
 // @Param refresh_token formData string false "Refresh token (for refresh_token grant)"
+// @Param refresh_nonce formData string false "Nonce returned with the refresh token (for refresh_token grant, when the client requires refresh nonces)"
 // @Param scope formData string false "Requested scopes"
 // @Param code_verifier formData string false "PKCE code verifier"
 // @Param device_code formData string false "Device code (for device_code grant)"
@@ -98,6 +124,10 @@ func (h *OAuthProviderHandler) Authorize(c *gin.Context) {
 // @Failure 401 {object} map[string]string "invalid_client"
 // @Router /oauth/token [post]
 func (h *OAuthProviderHandler) Token(c *gin.Context) {
+	// Token responses must not be cached (RFC 6749, section 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
 	var req models.TokenRequest
 
 	if err := c.ShouldBind(&req); err != nil {
@@ -116,6 +146,7 @@ func (h *OAuthProviderHandler) Token(c *gin.Context) {
 	// Add session context for tracking
 	req.IPAddress = utils.GetClientIP(c)
 	req.UserAgent = c.Request.UserAgent()
+	req.ClientCertThumbprint = utils.GetClientCertThumbprint(c)
 
 	var resp *models.TokenResponse
 	var err error
@@ -137,6 +168,7 @@ func (h *OAuthProviderHandler) Token(c *gin.Context) {
 	if err != nil {
 		errorCode := h.mapErrorToOAuthCode(err)
 		errorDesc := err.Error()
+		metrics.RecordOAuthGrant(req.GrantType, errorCode)
 
 		if errors.Is(err, service.ErrAuthorizationPending) {
 			h.oauthError(c, http.StatusBadRequest, errorCode, "The authorization request is still pending")
@@ -160,6 +192,7 @@ func (h *OAuthProviderHandler) Token(c *gin.Context) {
 		return
 	}
 
+	metrics.RecordOAuthGrant(req.GrantType, "success")
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -261,7 +294,7 @@ func (h *OAuthProviderHandler) Revoke(c *gin.Context) {
 
 // UserInfo handles OIDC UserInfo requests
 // @Summary OIDC UserInfo
-// @Description Get user information based on access token (OIDC UserInfo endpoint)
+// @Description Get user information based on access token (OIDC UserInfo endpoint). Certificate-bound access tokens (RFC 8705) are only accepted over a connection with the client certificate they are bound to.
 // @Tags OAuth Provider
 // @Security BearerAuth
 // @Produce json
@@ -271,6 +304,7 @@ func (h *OAuthProviderHandler) Revoke(c *gin.Context) {
 func (h *OAuthProviderHandler) UserInfo(c *gin.Context) {
 	accessToken := h.extractBearerToken(c)
 	if accessToken == "" {
+		c.Header("WWW-Authenticate", "Bearer")
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error":             "invalid_token",
 			"error_description": "No access token provided",
@@ -278,9 +312,10 @@ func (h *OAuthProviderHandler) UserInfo(c *gin.Context) {
 		return
 	}
 
-	userInfo, err := h.service.GetUserInfo(c.Request.Context(), accessToken)
+	userInfo, err := h.service.GetUserInfo(c.Request.Context(), accessToken, utils.GetClientCertThumbprint(c))
 	if err != nil {
 		h.logger.Error("failed to get user info", map[string]interface{}{"error": err.Error()})
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error":             "invalid_token",
 			"error_description": "Invalid or expired access token",
@@ -304,6 +339,139 @@ func (h *OAuthProviderHandler) Discovery(c *gin.Context) {
 	c.JSON(http.StatusOK, doc)
 }
 
+// EndSession handles OIDC RP-initiated logout requests
+// @Summary End Session
+// @Description Ends the user's login session (OIDC RP-Initiated Logout). Clients the user is signed in to that have a front-channel logout URI are logged out in iframes, then the user is sent to post_logout_redirect_uri if one was given. Without id_token_hint the user confirms the logout on a page first; the page posts logout_state back.
+// @Tags OAuth Provider
+// @Produce html
+// @Param id_token_hint query string false "ID token the client received for the user"
+// @Param client_id query string false "Client ID"
+// @Param post_logout_redirect_uri query string false "Registered URI to send the user to after logout"
+// @Param state query string false "Value passed back to post_logout_redirect_uri"
+// @Param logout_state formData string false "Signed state of a confirmed logout, posted by the confirmation page"
+// @Success 200 {string} string "HTML signed-out or confirmation page"
+// @Success 302 {string} string "Redirect to post_logout_redirect_uri"
+// @Failure 400 {string} string "HTML error page"
+// @Router /oauth/logout [get]
+// @Router /oauth/logout [post]
+func (h *OAuthProviderHandler) EndSession(c *gin.Context) {
+	var req models.EndSessionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"Error":            "invalid_request",
+			"ErrorDescription": "Invalid logout request",
+		})
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+
+	// The confirmation page posts the original request back in its signed state
+	if logoutState := c.PostForm("logout_state"); logoutState != "" {
+		signedFor := uuid.Nil
+		if userID != nil {
+			signedFor = *userID
+		}
+		values, err := h.pageState.Verify(logoutState, pageStateLogout, signedFor)
+		if err != nil {
+			c.HTML(http.StatusBadRequest, "error.html", gin.H{
+				"Error":            "invalid_request",
+				"ErrorDescription": "The logout page expired. Please sign out again.",
+			})
+			return
+		}
+		req = models.EndSessionRequest{
+			ClientID:              values.Get("client_id"),
+			PostLogoutRedirectURI: values.Get("post_logout_redirect_uri"),
+			State:                 values.Get("state"),
+			Confirmed:             true,
+		}
+	}
+
+	result, err := h.service.EndSession(c.Request.Context(), &req, userID)
+	if err != nil {
+		// Never redirect on errors: the redirect URI is not trusted
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrServerError) {
+			status = http.StatusInternalServerError
+		}
+		c.HTML(status, "error.html", gin.H{
+			"Error":            "Logout Failed",
+			"ErrorDescription": err.Error(),
+		})
+		return
+	}
+
+	if result.ConfirmationRequired {
+		h.logoutConfirmPage(c, &req, *userID)
+		return
+	}
+
+	if h.sessions != nil {
+		h.sessions.TerminateSession(c)
+	}
+
+	if len(result.FrontchannelLogoutURIs) == 0 && result.RedirectURI != "" {
+		c.Redirect(http.StatusFound, result.RedirectURI)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if len(result.FrontchannelLogoutURIs) > 0 {
+		// The default policy keeps the clients' logout pages out of frames
+		c.Header("Content-Security-Policy", "default-src 'self'; frame-src "+strings.Join(frameOrigins(result.FrontchannelLogoutURIs), " "))
+	}
+	c.HTML(http.StatusOK, "logout.html", gin.H{
+		"RedirectURI":            result.RedirectURI,
+		"FrontchannelLogoutURIs": result.FrontchannelLogoutURIs,
+	})
+}
+
+// logoutConfirmPage asks the user to confirm a logout that did not come with id_token_hint,
+// with the request signed into the page
+func (h *OAuthProviderHandler) logoutConfirmPage(c *gin.Context, req *models.EndSessionRequest, userID uuid.UUID) {
+	logoutState, err := h.pageState.Sign(pageStateLogout, userID, url.Values{
+		"client_id":                {req.ClientID},
+		"post_logout_redirect_uri": {req.PostLogoutRedirectURI},
+		"state":                    {req.State},
+	})
+	if err != nil {
+		h.logger.Error("failed to sign logout page state", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"Error":            "server_error",
+			"ErrorDescription": "Failed to load the logout page",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.HTML(http.StatusOK, "logout.html", gin.H{
+		"ConfirmationRequired": true,
+		"LogoutState":          logoutState,
+	})
+}
+
+// frameOrigins returns the distinct origins of URIs for a frame-src directive
+func frameOrigins(uris []string) []string {
+	seen := make(map[string]bool)
+	var origins []string
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
 // JWKS handles JSON Web Key Set requests
 // @Summary JWKS
 // @Description Get JSON Web Key Set for token validation (/.well-known/jwks.json)
@@ -385,32 +553,43 @@ func (h *OAuthProviderHandler) DeviceVerification(c *gin.Context) {
 // @Security BearerAuth
 // @Accept application/x-www-form-urlencoded
 // @Produce html
-// @Param user_code formData string true "User code from device"
-// @Param approve formData string true "Approval decision (true or false)"
-// @Success 200 {string} string "HTML success page"
+// @Description Without device_state, shows the client and scopes the user code asks for; with the device_state of that page, approves or denies the device code (requires authentication)
+// @Param user_code formData string false "User code from device, for the confirmation page"
+// @Param device_state formData string false "Signed state of the confirmation page"
+// @Param approve formData string false "Approval decision (true or false), with device_state"
+// @Success 200 {string} string "HTML confirmation or success page"
 // @Failure 400 {string} string "HTML error page"
-// @Failure 302 {string} string "Redirect to login"
+// @Failure 303 {string} string "Redirect to login"
 // @Router /oauth/device/approve [post]
 func (h *OAuthProviderHandler) DeviceApprove(c *gin.Context) {
 	userID, authenticated := h.getUserIDFromContext(c)
 	if !authenticated {
-		loginURL := fmt.Sprintf("/login?return_to=%s", url.QueryEscape(c.Request.URL.String()))
-		c.Redirect(http.StatusTemporaryRedirect, loginURL)
+		// Come back to the verification page, which any replica can serve, with the code kept in the URL
+		returnTo := "/oauth/device"
+		if userCode := c.PostForm("user_code"); userCode != "" {
+			returnTo += "?user_code=" + url.QueryEscape(userCode)
+		}
+		c.Redirect(http.StatusSeeOther, "/login?return_to="+url.QueryEscape(returnTo))
 		return
 	}
 
-	userCode := c.PostForm("user_code")
-	approve := c.PostForm("approve") == "true"
+	deviceState := c.PostForm("device_state")
+	if deviceState == "" {
+		h.deviceConfirmPage(c, userID)
+		return
+	}
 
-	if userCode == "" {
+	values, err := h.pageState.Verify(deviceState, pageStateDevice, userID)
+	if err != nil {
 		c.HTML(http.StatusBadRequest, "error.html", gin.H{
 			"error":   "Invalid Request",
-			"message": "User code is required",
+			"message": "The verification page expired. Please enter the code again.",
 		})
 		return
 	}
 
-	err := h.service.ApproveDeviceCode(c.Request.Context(), userID, userCode, approve)
+	approve := c.PostForm("approve") == "true"
+	err = h.service.ApproveDeviceCode(c.Request.Context(), userID, values.Get("user_code"), approve)
 	if err != nil {
 		c.HTML(http.StatusBadRequest, "error.html", gin.H{
 			"error":   "Approval Failed",
@@ -429,6 +608,44 @@ func (h *OAuthProviderHandler) DeviceApprove(c *gin.Context) {
 	c.String(http.StatusOK, html)
 }
 
+// deviceConfirmPage shows what the entered user code asks for, with the code signed into the page
+func (h *OAuthProviderHandler) deviceConfirmPage(c *gin.Context, userID uuid.UUID) {
+	userCode := strings.ToUpper(strings.TrimSpace(c.PostForm("user_code")))
+	if userCode == "" {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error":   "Invalid Request",
+			"message": "User code is required",
+		})
+		return
+	}
+
+	info, err := h.service.GetDeviceConsentInfo(c.Request.Context(), userCode)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error":   "Approval Failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	deviceState, err := h.pageState.Sign(pageStateDevice, userID, url.Values{"user_code": {userCode}})
+	if err != nil {
+		h.logger.Error("failed to sign device page state", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error":   "Server Error",
+			"message": "Failed to load device authorization",
+		})
+		return
+	}
+
+	html := h.renderDeviceConfirmPage(info, deviceState)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.String(http.StatusOK, html)
+}
+
 // ConsentPage renders the OAuth consent page
 // @Summary OAuth Consent Page
 // @Description HTML page for user to approve/deny OAuth authorization request
@@ -479,7 +696,28 @@ func (h *OAuthProviderHandler) ConsentPage(c *gin.Context) {
 		return
 	}
 
-	html := h.renderConsentPage(consentInfo, c.Request.URL.Query())
+	query := c.Request.URL.Query()
+	params := url.Values{}
+	for _, key := range consentParams {
+		if value := query.Get(key); value != "" {
+			params.Set(key, value)
+		}
+	}
+	consentState, err := h.pageState.Sign(pageStateConsent, userID, params)
+	if err != nil {
+		h.logger.Error("failed to sign consent page state", map[string]interface{}{
+			"error":     err.Error(),
+			"user_id":   userID.String(),
+			"client_id": clientID,
+		})
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error":   "Server Error",
+			"message": "Failed to load consent information",
+		})
+		return
+	}
+
+	html := h.renderConsentPage(consentInfo, consentState)
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	c.String(http.StatusOK, html)
 }
@@ -490,15 +728,8 @@ func (h *OAuthProviderHandler) ConsentPage(c *gin.Context) {
 // @Tags OAuth Provider - Consent
 // @Security BearerAuth
 // @Accept application/x-www-form-urlencoded
-// @Param client_id formData string true "Client ID"
-// @Param redirect_uri formData string true "Redirect URI"
-// @Param scope formData string true "Requested scopes"
-// @Param state formData string true "State parameter"
+// @Param consent_state formData string true "Signed authorization request from the consent page"
 // @Param approve formData string true "Approval decision (true or false)"
-// @Param response_type formData string false "Response type"
-// @Param nonce formData string false "Nonce"
-// @Param code_challenge formData string false "PKCE code challenge"
-// @Param code_challenge_method formData string false "PKCE code challenge method"
 // @Success 302 {string} string "Redirect to callback with code or error"
 // @Router /oauth/consent [post]
 func (h *OAuthProviderHandler) ConsentSubmit(c *gin.Context) {
@@ -509,43 +740,47 @@ func (h *OAuthProviderHandler) ConsentSubmit(c *gin.Context) {
 		return
 	}
 
-	clientID := c.PostForm("client_id")
-	redirectURI := c.PostForm("redirect_uri")
-	scope := c.PostForm("scope")
-	state := c.PostForm("state")
+	// The request comes back signed, so it cannot be altered between the page and the decision
+	params, err := h.pageState.Verify(c.PostForm("consent_state"), pageStateConsent, userID)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error":   "Invalid Request",
+			"message": "The authorization request expired. Please return to the application and try again.",
+		})
+		return
+	}
+
 	approve := c.PostForm("approve") == "true"
 
-	responseType := c.PostForm("response_type")
-	nonce := c.PostForm("nonce")
-	codeChallenge := c.PostForm("code_challenge")
-	codeChallengeMethod := c.PostForm("code_challenge_method")
+	nonce := params.Get("nonce")
+	codeChallenge := params.Get("code_challenge")
+	codeChallengeMethod := params.Get("code_challenge_method")
+
+	var req models.AuthorizeRequest
+	req.ResponseType = params.Get("response_type")
+	req.ClientID = params.Get("client_id")
+	req.RedirectURI = params.Get("redirect_uri")
+	req.Scope = params.Get("scope")
+	req.State = params.Get("state")
+	req.ResponseMode = params.Get("response_mode")
 
 	if !approve {
-		errorURL := h.buildErrorRedirect(redirectURI, "access_denied", "User denied consent", state)
-		c.Redirect(http.StatusTemporaryRedirect, errorURL)
+		h.redirectError(c, &req, "access_denied", "User denied consent")
 		return
 	}
 
-	scopes := strings.Split(scope, " ")
-	err := h.service.GrantConsent(c.Request.Context(), userID, clientID, scopes)
+	scopes := strings.Split(req.Scope, " ")
+	err = h.service.GrantConsent(c.Request.Context(), userID, req.ClientID, scopes)
 	if err != nil {
 		h.logger.Error("failed to grant consent", map[string]interface{}{
 			"error":     err.Error(),
 			"user_id":   userID.String(),
-			"client_id": clientID,
+			"client_id": req.ClientID,
 		})
-		errorURL := h.buildErrorRedirect(redirectURI, "server_error", "Failed to grant consent", state)
-		c.Redirect(http.StatusTemporaryRedirect, errorURL)
+		h.redirectError(c, &req, "server_error", "Failed to grant consent")
 		return
 	}
 
-	var req models.AuthorizeRequest
-	req.ResponseType = responseType
-	req.ClientID = clientID
-	req.RedirectURI = redirectURI
-	req.Scope = scope
-	req.State = state
-
 	if nonce != "" {
 		req.Nonce = &nonce
 	}
@@ -560,13 +795,11 @@ func (h *OAuthProviderHandler) ConsentSubmit(c *gin.Context) {
 	if err != nil {
 		errorCode := h.mapErrorToOAuthCode(err)
 		errorDesc := err.Error()
-		errorURL := h.buildErrorRedirect(redirectURI, errorCode, errorDesc, state)
-		c.Redirect(http.StatusTemporaryRedirect, errorURL)
+		h.redirectError(c, &req, errorCode, errorDesc)
 		return
 	}
 
-	successURL := h.buildSuccessRedirect(redirectURI, authResp.Code, authResp.State)
-	c.Redirect(http.StatusTemporaryRedirect, successURL)
+	h.redirectSuccess(c, &req, authResp.Code, authResp.State)
 }
 
 func (h *OAuthProviderHandler) extractClientCredentials(c *gin.Context) (clientID, clientSecret string) {
@@ -641,49 +874,86 @@ func (h *OAuthProviderHandler) mapErrorToOAuthCode(err error) string {
 	}
 }
 
-func (h *OAuthProviderHandler) redirectError(c *gin.Context, redirectURI, errorCode, errorDesc, state string) {
-	if redirectURI == "" {
+func (h *OAuthProviderHandler) redirectError(c *gin.Context, req *models.AuthorizeRequest, errorCode, errorDesc string) {
+	if req.RedirectURI == "" {
 		h.oauthError(c, http.StatusBadRequest, errorCode, errorDesc)
 		return
 	}
 
-	errorURL := h.buildErrorRedirect(redirectURI, errorCode, errorDesc, state)
-	c.Redirect(http.StatusTemporaryRedirect, errorURL)
-}
-
-func (h *OAuthProviderHandler) buildErrorRedirect(redirectURI, errorCode, errorDesc, state string) string {
-	u, err := url.Parse(redirectURI)
-	if err != nil {
-		return redirectURI
-	}
-
-	q := u.Query()
-	q.Set("error", errorCode)
+	params := url.Values{}
+	params.Set("error", errorCode)
 	if errorDesc != "" {
-		q.Set("error_description", errorDesc)
+		params.Set("error_description", errorDesc)
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
 	}
+	h.redirectResponse(c, req, params)
+}
+
+func (h *OAuthProviderHandler) redirectSuccess(c *gin.Context, req *models.AuthorizeRequest, code, state string) {
+	params := url.Values{}
+	params.Set("code", code)
 	if state != "" {
-		q.Set("state", state)
+		params.Set("state", state)
 	}
-	u.RawQuery = q.Encode()
+	h.redirectResponse(c, req, params)
+}
 
-	return u.String()
+// redirectResponse returns an authorization response to the client in the requested response mode
+func (h *OAuthProviderHandler) redirectResponse(c *gin.Context, req *models.AuthorizeRequest, params url.Values) {
+	redirectURL, err := h.buildResponseRedirect(req.RedirectURI, req.ResponseMode, req.ClientID, params)
+	if err != nil {
+		h.logger.Error("failed to sign authorization response", map[string]interface{}{
+			"error":     err.Error(),
+			"client_id": req.ClientID,
+		})
+		h.oauthError(c, http.StatusInternalServerError, "server_error", "Failed to sign authorization response")
+		return
+	}
+	c.Redirect(http.StatusTemporaryRedirect, redirectURL)
 }
 
-func (h *OAuthProviderHandler) buildSuccessRedirect(redirectURI, code, state string) string {
+// buildResponseRedirect adds the response parameters to the redirect URI: in the query or the
+// fragment, and for the JWT modes (JARM) as a JWT signed for the client in a single
+// "response" parameter
+func (h *OAuthProviderHandler) buildResponseRedirect(redirectURI, responseMode, clientID string, params url.Values) (string, error) {
 	u, err := url.Parse(redirectURI)
 	if err != nil {
-		return redirectURI
+		return redirectURI, nil
+	}
+
+	switch responseMode {
+	case models.ResponseModeJWT, models.ResponseModeQueryJWT, models.ResponseModeFragmentJWT:
+		response, err := h.service.SignAuthorizationResponse(clientID, params)
+		if err != nil {
+			return "", err
+		}
+		params = url.Values{"response": {response}}
+	}
+
+	if responseMode == models.ResponseModeFragment || responseMode == models.ResponseModeFragmentJWT {
+		u.Fragment = ""
+		u.RawFragment = ""
+		return u.String() + "#" + params.Encode(), nil
 	}
 
 	q := u.Query()
-	q.Set("code", code)
-	if state != "" {
-		q.Set("state", state)
+	for key, values := range params {
+		q[key] = values
 	}
 	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
 
-	return u.String()
+// isValidResponseMode reports whether mode is empty or a supported response mode
+func isValidResponseMode(mode string) bool {
+	switch mode {
+	case "", models.ResponseModeQuery, models.ResponseModeFragment,
+		models.ResponseModeJWT, models.ResponseModeQueryJWT, models.ResponseModeFragmentJWT:
+		return true
+	}
+	return false
 }
 
 func (h *OAuthProviderHandler) buildConsentURL(params url.Values) string {
@@ -715,8 +985,7 @@ func (h *OAuthProviderHandler) renderDeviceVerificationPage(prefilledCode string
         <p>Enter the code displayed on your device:</p>
         <form method="POST" action="/oauth/device/approve">
             <input type="text" name="user_code" placeholder="XXXX-XXXX" value="%s" required maxlength="9" style="text-transform: uppercase;">
-            <input type="hidden" name="approve" value="true">
-            <button type="submit">Verify</button>
+            <button type="submit">Continue</button>
         </form>
     </div>
 </body>
@@ -744,19 +1013,57 @@ func (h *OAuthProviderHandler) renderDeviceSuccessPage(message string) string {
 </html>`, message)
 }
 
-func (h *OAuthProviderHandler) renderConsentPage(info *service.ConsentInfo, params url.Values) string {
+func (h *OAuthProviderHandler) renderDeviceConfirmPage(info *service.ConsentInfo, deviceState string) string {
 	scopesList := ""
 	for _, scope := range info.RequestedScopes {
-		scopesList += fmt.Sprintf(`<li><strong>%s</strong>: %s</li>`, scope.DisplayName, scope.Description)
+		scopesList += fmt.Sprintf(`<li>%s</li>`, html.EscapeString(scope.DisplayName))
 	}
 
-	hiddenFields := ""
-	for key, values := range params {
-		for _, value := range values {
-			hiddenFields += fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, key, value)
-		}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+    <title>Device Verification</title>
+    <style>
+        body { font-family: Arial, sans-serif; max-width: 500px; margin: 50px auto; padding: 20px; }
+        .container { background: #f5f5f5; padding: 30px; border-radius: 8px; }
+        h1 { color: #333; }
+        ul { padding-left: 20px; }
+        .buttons { display: flex; gap: 10px; margin-top: 20px; }
+        button { flex: 1; padding: 12px; border: none; border-radius: 4px; font-size: 16px; cursor: pointer; color: white; }
+        .approve { background: #28a745; }
+        .deny { background: #dc3545; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>Device Verification</h1>
+        <p>Allow <strong>%s</strong> to access:</p>
+        <ul>%s</ul>
+        <form method="POST" action="/oauth/device/approve">
+            <input type="hidden" name="device_state" value="%s">
+            <div class="buttons">
+                <button type="submit" name="approve" value="false" class="deny">Deny</button>
+                <button type="submit" name="approve" value="true" class="approve">Allow</button>
+            </div>
+        </form>
+    </div>
+</body>
+</html>`, html.EscapeString(info.Client.Name), scopesList, html.EscapeString(deviceState))
+}
+
+func (h *OAuthProviderHandler) renderConsentPage(info *service.ConsentInfo, consentState string) string {
+	scopesList := ""
+	for _, scope := range info.RequestedScopes {
+		scopesList += fmt.Sprintf(`<li><strong>%s</strong>: %s</li>`, html.EscapeString(scope.DisplayName), html.EscapeString(scope.Description))
+	}
+
+	logo := ""
+	if info.LogoURL != "" {
+		logo = fmt.Sprintf(`<img src="%s" alt="" class="client-logo">`, html.EscapeString(info.LogoURL))
 	}
 
+	hiddenFields := fmt.Sprintf(`<input type="hidden" name="consent_state" value="%s">`, html.EscapeString(consentState))
+
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
@@ -767,6 +1074,7 @@ func (h *OAuthProviderHandler) renderConsentPage(info *service.ConsentInfo, para
         h1 { color: #333; }
         .client-info { background: white; padding: 15px; border-radius: 4px; margin: 20px 0; }
         .scopes { background: white; padding: 15px; border-radius: 4px; margin: 20px 0; }
+        .client-logo { max-width: 64px; max-height: 64px; }
         ul { list-style: none; padding: 0; }
         li { padding: 8px 0; border-bottom: 1px solid #eee; }
         .buttons { display: flex; gap: 10px; margin-top: 20px; }
@@ -781,6 +1089,7 @@ func (h *OAuthProviderHandler) renderConsentPage(info *service.ConsentInfo, para
     <div class="container">
         <h1>Authorization Request</h1>
         <div class="client-info">
+            %s
             <h3>%s</h3>
             <p>%s</p>
         </div>
@@ -797,5 +1106,5 @@ func (h *OAuthProviderHandler) renderConsentPage(info *service.ConsentInfo, para
         </form>
     </div>
 </body>
-</html>`, info.Client.Name, info.Client.Description, scopesList, hiddenFields)
+</html>`, logo, html.EscapeString(info.Client.Name), html.EscapeString(info.Client.Description), scopesList, hiddenFields)
 }
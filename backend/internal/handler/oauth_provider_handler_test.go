@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/templates"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOAuthProviderService struct {
+	service.OAuthProviderServicer
+	authorizeErr error
+	signed       url.Values
+	endSession   *models.EndSessionResult
+	endErr       error
+	endRequest   *models.EndSessionRequest
+}
+
+func (s *stubOAuthProviderService) Authorize(ctx context.Context, req *models.AuthorizeRequest, userID uuid.UUID) (*models.AuthorizeResponse, error) {
+	if s.authorizeErr != nil {
+		return nil, s.authorizeErr
+	}
+	return &models.AuthorizeResponse{Code: "code-1", State: req.State}, nil
+}
+
+func (s *stubOAuthProviderService) SignAuthorizationResponse(clientID string, params url.Values) (string, error) {
+	s.signed = params
+	return "signed-for-" + clientID, nil
+}
+
+func (s *stubOAuthProviderService) EndSession(ctx context.Context, req *models.EndSessionRequest, userID *uuid.UUID) (*models.EndSessionResult, error) {
+	s.endRequest = req
+	if req.Confirmed {
+		return &models.EndSessionResult{}, s.endErr
+	}
+	return s.endSession, s.endErr
+}
+
+func authorizeRedirect(t *testing.T, svc *stubOAuthProviderService, query string) *url.URL {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := NewOAuthProviderHandler(svc, "state-secret", testLogger())
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		c.Next()
+	})
+	r.GET("/oauth/authorize", h.Authorize)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query, nil))
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	return location
+}
+
+const authorizeQuery = "response_type=code&client_id=app&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb%3Fx%3D1&scope=openid&state=s1"
+
+func TestOAuthProviderHandler_Authorize_QueryResponse(t *testing.T) {
+	location := authorizeRedirect(t, &stubOAuthProviderService{}, authorizeQuery)
+
+	assert.Equal(t, "app.example.com", location.Host)
+	assert.Equal(t, "1", location.Query().Get("x"))
+	assert.Equal(t, "code-1", location.Query().Get("code"))
+	assert.Equal(t, "s1", location.Query().Get("state"))
+}
+
+func TestOAuthProviderHandler_Authorize_FragmentResponse(t *testing.T) {
+	location := authorizeRedirect(t, &stubOAuthProviderService{}, authorizeQuery+"&response_mode=fragment")
+
+	assert.Empty(t, location.Query().Get("code"))
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+	assert.Equal(t, "code-1", fragment.Get("code"))
+}
+
+func TestOAuthProviderHandler_Authorize_JWTResponse(t *testing.T) {
+	svc := &stubOAuthProviderService{}
+	location := authorizeRedirect(t, svc, authorizeQuery+"&response_mode=jwt")
+
+	assert.Equal(t, "signed-for-app", location.Query().Get("response"))
+	assert.Empty(t, location.Query().Get("code"), "parameters travel only inside the JWT")
+	assert.Equal(t, "code-1", svc.signed.Get("code"))
+	assert.Equal(t, "s1", svc.signed.Get("state"))
+
+	svc = &stubOAuthProviderService{}
+	location = authorizeRedirect(t, svc, authorizeQuery+"&response_mode=fragment.jwt")
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+	assert.Equal(t, "signed-for-app", fragment.Get("response"))
+}
+
+func TestOAuthProviderHandler_Authorize_JWTErrorResponse(t *testing.T) {
+	svc := &stubOAuthProviderService{authorizeErr: service.ErrInvalidScope}
+	location := authorizeRedirect(t, svc, authorizeQuery+"&response_mode=query.jwt")
+
+	assert.Equal(t, "signed-for-app", location.Query().Get("response"))
+	assert.Equal(t, "invalid_scope", svc.signed.Get("error"))
+	assert.Equal(t, "s1", svc.signed.Get("state"))
+}
+
+func TestOAuthProviderHandler_Authorize_UnknownResponseMode(t *testing.T) {
+	location := authorizeRedirect(t, &stubOAuthProviderService{}, authorizeQuery+"&response_mode=form_post")
+
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+}
+
+type recordingTerminator struct {
+	terminated bool
+}
+
+func (r *recordingTerminator) TerminateSession(c *gin.Context) {
+	r.terminated = true
+}
+
+func endSession(t *testing.T, svc *stubOAuthProviderService, sessions *recordingTerminator) *httptest.ResponseRecorder {
+	t.Helper()
+	return endSessionRequest(t, svc, sessions, uuid.Nil, httptest.NewRequest(http.MethodGet, "/oauth/logout?client_id=app", nil))
+}
+
+// endSessionRequest serves req at the end session endpoint, signed in as userID unless nil
+func endSessionRequest(t *testing.T, svc *stubOAuthProviderService, sessions *recordingTerminator, userID uuid.UUID, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	assets, err := templates.NewPipeline()
+	require.NoError(t, err)
+	tmpl, err := assets.Templates()
+	require.NoError(t, err)
+
+	h := NewOAuthProviderHandler(svc, "state-secret", testLogger())
+	h.SetSessionTerminator(sessions)
+	r := gin.New()
+	r.SetHTMLTemplate(tmpl)
+	if userID != uuid.Nil {
+		r.Use(func(c *gin.Context) {
+			c.Set(utils.UserIDKey, userID)
+			c.Next()
+		})
+	}
+	r.GET("/oauth/logout", h.EndSession)
+	r.POST("/oauth/logout", h.EndSession)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOAuthProviderHandler_EndSession_Redirect(t *testing.T) {
+	sessions := &recordingTerminator{}
+	w := endSession(t, &stubOAuthProviderService{
+		endSession: &models.EndSessionResult{RedirectURI: "https://app.example.com/signed-out?state=s1"},
+	}, sessions)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/signed-out?state=s1", w.Header().Get("Location"))
+	assert.True(t, sessions.terminated)
+}
+
+func TestOAuthProviderHandler_EndSession_FrontchannelLogoutPage(t *testing.T) {
+	sessions := &recordingTerminator{}
+	w := endSession(t, &stubOAuthProviderService{
+		endSession: &models.EndSessionResult{
+			RedirectURI:            "https://app.example.com/signed-out",
+			FrontchannelLogoutURIs: []string{"https://a.example.com/logout", "https://a.example.com/other", "https://b.example.com:8443/logout"},
+		},
+	}, sessions)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, sessions.terminated)
+	assert.Equal(t, "default-src 'self'; frame-src https://a.example.com https://b.example.com:8443", w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Body.String(), `<iframe src="https://a.example.com/logout"`)
+	assert.Contains(t, w.Body.String(), `data-redirect-uri="https://app.example.com/signed-out"`)
+}
+
+func TestOAuthProviderHandler_EndSession_ErrorKeepsSession(t *testing.T) {
+	sessions := &recordingTerminator{}
+	w := endSession(t, &stubOAuthProviderService{endErr: service.ErrInvalidRequest}, sessions)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+	assert.False(t, sessions.terminated)
+}
+
+func TestOAuthProviderHandler_EndSession_ConfirmationWithoutIDTokenHint(t *testing.T) {
+	userID := uuid.New()
+	sessions := &recordingTerminator{}
+	svc := &stubOAuthProviderService{endSession: &models.EndSessionResult{ConfirmationRequired: true}}
+
+	w := endSessionRequest(t, svc, sessions, userID, httptest.NewRequest(http.MethodGet,
+		"/oauth/logout?client_id=app&post_logout_redirect_uri=https%3A%2F%2Fapp.example.com%2Fsigned-out&state=s1", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, sessions.terminated, "the session ends only once the user confirms")
+	match := regexp.MustCompile(`name="logout_state" value="([^"]+)"`).FindStringSubmatch(w.Body.String())
+	require.Len(t, match, 2)
+
+	form := url.Values{"logout_state": {match[1]}}
+	req := httptest.NewRequest(http.MethodPost, "/oauth/logout", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = endSessionRequest(t, svc, sessions, userID, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, sessions.terminated)
+	assert.True(t, svc.endRequest.Confirmed)
+	assert.Equal(t, "app", svc.endRequest.ClientID)
+	assert.Equal(t, "https://app.example.com/signed-out", svc.endRequest.PostLogoutRedirectURI)
+	assert.Equal(t, "s1", svc.endRequest.State)
+}
+
+func TestOAuthProviderHandler_EndSession_ConfirmationOfAnotherUserRejected(t *testing.T) {
+	sessions := &recordingTerminator{}
+	svc := &stubOAuthProviderService{endSession: &models.EndSessionResult{ConfirmationRequired: true}}
+
+	w := endSessionRequest(t, svc, sessions, uuid.New(), httptest.NewRequest(http.MethodGet, "/oauth/logout", nil))
+	match := regexp.MustCompile(`name="logout_state" value="([^"]+)"`).FindStringSubmatch(w.Body.String())
+	require.Len(t, match, 2)
+
+	form := url.Values{"logout_state": {match[1]}}
+	req := httptest.NewRequest(http.MethodPost, "/oauth/logout", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = endSessionRequest(t, svc, sessions, uuid.New(), req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, sessions.terminated)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// OIDCConformanceHandler runs the OIDC provider conformance self-test
+type OIDCConformanceHandler struct {
+	service service.OIDCConformanceServicer
+	logger  *logger.Logger
+}
+
+// NewOIDCConformanceHandler creates a new OIDC conformance handler
+func NewOIDCConformanceHandler(service service.OIDCConformanceServicer, logger *logger.Logger) *OIDCConformanceHandler {
+	return &OIDCConformanceHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Run runs the conformance checks against this server
+// @Summary Run OIDC conformance checks
+// @Description Exercise the discovery, JWKS, authorize, token, userinfo, introspection and revocation endpoints of this server over HTTP and check the responses against the OAuth 2.0 and OpenID Connect specifications. Checks that need a client are skipped unless a confidential client allowed to use the client_credentials grant is given; the run issues and then revokes one access token for it (admin only)
+// @Tags Admin - OAuth Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.OIDCConformanceRequest false "Client to run the client-specific checks with"
+// @Success 200 {object} models.OIDCConformanceReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/oauth/conformance [post]
+func (h *OIDCConformanceHandler) Run(c *gin.Context) {
+	var req models.OIDCConformanceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			return
+		}
+	}
+
+	report := h.service.Run(c.Request.Context(), &req)
+	if !report.OK() {
+		h.logger.Warn("OIDC conformance checks failed", map[string]interface{}{
+			"failed": report.Failed,
+		})
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,503 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// OrgAdminHandler handles organization admin designation and the organization-scoped
+// admin API. Organizations are groups.
+type OrgAdminHandler struct {
+	orgAdminService service.OrgAdminServicer
+	logger          *logger.Logger
+}
+
+// NewOrgAdminHandler creates a new organization admin handler
+func NewOrgAdminHandler(orgAdminService service.OrgAdminServicer, logger *logger.Logger) *OrgAdminHandler {
+	return &OrgAdminHandler{
+		orgAdminService: orgAdminService,
+		logger:          logger,
+	}
+}
+
+// ListGroupAdmins handles listing the organization admins of a group
+// @Summary List group admins
+// @Description List the users designated as organization admins of a group
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.GroupAdminListResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/admins [get]
+func (h *OrgAdminHandler) ListGroupAdmins(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	admins, err := h.orgAdminService.ListAdmins(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, admins)
+}
+
+// AddGroupAdmin handles designating an organization admin
+// @Summary Add group admin
+// @Description Designate a user as organization admin of a group. Organization admins manage the members of the group and of its child groups.
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param request body models.AddGroupAdminRequest true "User to designate"
+// @Success 201 {object} models.GroupAdmin
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/admins [post]
+func (h *OrgAdminHandler) AddGroupAdmin(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.AddGroupAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	admin, err := h.orgAdminService.AddAdmin(c.Request.Context(), groupID, req.UserID, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, admin)
+}
+
+// RemoveGroupAdmin handles revoking an organization admin designation
+// @Summary Remove group admin
+// @Description Revoke a user's organization admin designation on a group
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/admins/{user_id} [delete]
+func (h *OrgAdminHandler) RemoveGroupAdmin(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.orgAdminService.RemoveAdmin(c.Request.Context(), groupID, userID); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Group admin removed successfully"})
+}
+
+// ListMyOrgs handles listing the organizations the current user administers
+// @Summary List my organizations
+// @Description List the groups the current user was designated organization admin of
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.OrgListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs [get]
+func (h *OrgAdminHandler) ListMyOrgs(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	orgs, err := h.orgAdminService.ListManagedOrgs(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// ListUsers handles listing organization members
+// @Summary List organization users
+// @Description List the members of an organization and its child groups (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param search query string false "Search by username, full name or email"
+// @Success 200 {object} models.AdminUserListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users [get]
+func (h *OrgAdminHandler) ListUsers(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	page, pageSize := utils.ParsePagination(c)
+
+	users, err := h.orgAdminService.ListUsers(c.Request.Context(), groupID, c.Query("search"), page, pageSize)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// GetUser handles retrieving an organization member
+// @Summary Get organization user
+// @Description Get a member of an organization (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users/{user_id} [get]
+func (h *OrgAdminHandler) GetUser(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	user, err := h.orgAdminService.GetUser(c.Request.Context(), groupID, userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// CreateUser handles creating a user inside an organization
+// @Summary Create organization user
+// @Description Create a user and add them to the organization. Only roles mapped to the organization may be requested (organization admin only).
+// @Tags Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param request body models.AdminCreateUserRequest true "User creation data"
+// @Success 201 {object} models.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users [post]
+func (h *OrgAdminHandler) CreateUser(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.AdminCreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.orgAdminService.CreateUser(c.Request.Context(), groupID, &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUser handles updating an organization member
+// @Summary Update organization user
+// @Description Update the profile or active status of an organization member (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Param request body models.OrgUpdateUserRequest true "User update data"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users/{user_id} [put]
+func (h *OrgAdminHandler) UpdateUser(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var req models.OrgUpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.orgAdminService.UpdateUser(c.Request.Context(), groupID, userID, &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RemoveUser handles removing a member from an organization
+// @Summary Remove organization user
+// @Description Remove a user from the organization and its child groups. The account itself is kept (organization admin only).
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users/{user_id} [delete]
+func (h *OrgAdminHandler) RemoveUser(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.orgAdminService.RemoveUser(c.Request.Context(), groupID, userID); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "User removed from organization"})
+}
+
+// ListRoles handles listing the roles organization admins may assign
+// @Summary List assignable roles
+// @Description List the roles organization admins may assign: the non-system roles mapped to the organization (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Success 200 {array} models.Role
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/roles [get]
+func (h *OrgAdminHandler) ListRoles(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	roles, err := h.orgAdminService.ListRoles(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// AssignRole handles granting a role to an organization member
+// @Summary Assign role to organization user
+// @Description Grant a role mapped to the organization to an organization member (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Param request body models.AssignRoleRequest true "Role to assign"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users/{user_id}/roles [post]
+func (h *OrgAdminHandler) AssignRole(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var req models.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.orgAdminService.AssignRole(c.Request.Context(), groupID, userID, req.RoleID, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RemoveRole handles revoking a role from an organization member
+// @Summary Remove role from organization user
+// @Description Revoke a role mapped to the organization from an organization member (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param user_id path string true "User ID (UUID)"
+// @Param role_id path string true "Role ID (UUID)"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/users/{user_id}/roles/{role_id} [delete]
+func (h *OrgAdminHandler) RemoveRole(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := utils.ParseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	roleID, ok := utils.ParseUUIDParam(c, "role_id")
+	if !ok {
+		return
+	}
+
+	user, err := h.orgAdminService.RemoveRole(c.Request.Context(), groupID, userID, roleID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ListAPIKeys handles listing the API keys of organization members
+// @Summary List organization API keys
+// @Description List the API keys owned by members of an organization and its child groups (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Success 200 {object} models.AdminAPIKeyListResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/api-keys [get]
+func (h *OrgAdminHandler) ListAPIKeys(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	keys, err := h.orgAdminService.ListAPIKeys(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey handles revoking an organization member's API key
+// @Summary Revoke organization API key
+// @Description Revoke an API key owned by a member of the organization (organization admin only)
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Param key_id path string true "API key ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/api-keys/{key_id}/revoke [post]
+func (h *OrgAdminHandler) RevokeAPIKey(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	keyID, ok := utils.ParseUUIDParam(c, "key_id")
+	if !ok {
+		return
+	}
+
+	if err := h.orgAdminService.RevokeAPIKey(c.Request.Context(), groupID, keyID); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "API key revoked successfully"})
+}
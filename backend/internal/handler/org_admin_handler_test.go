@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// helpers
+// ---------------------------------------------------------------------------
+
+type orgAdminTestFixture struct {
+	handler *OrgAdminHandler
+	svc     *mockOrgAdminServicer
+	userID  uuid.UUID
+}
+
+func setupOrgAdminTestFixture() *orgAdminTestFixture {
+	svc := &mockOrgAdminServicer{}
+	return &orgAdminTestFixture{
+		handler: NewOrgAdminHandler(svc, testLogger()),
+		svc:     svc,
+		userID:  uuid.New(),
+	}
+}
+
+// router returns an engine that authenticates every request as the fixture user
+func (f *orgAdminTestFixture) router() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, f.userID)
+		c.Next()
+	})
+	return r
+}
+
+// ===========================================================================
+// AddGroupAdmin
+// ===========================================================================
+
+func TestOrgAdminHandler_AddGroupAdmin_ShouldReturn201_WhenSuccessful(t *testing.T) {
+	fix := setupOrgAdminTestFixture()
+	groupID := uuid.New()
+	targetID := uuid.New()
+
+	fix.svc.AddAdminFunc = func(gid, uid, createdBy uuid.UUID) (*models.GroupAdmin, error) {
+		assert.Equal(t, groupID, gid)
+		assert.Equal(t, targetID, uid)
+		assert.Equal(t, fix.userID, createdBy)
+		return &models.GroupAdmin{GroupID: gid, UserID: uid, CreatedBy: &createdBy}, nil
+	}
+
+	r := fix.router()
+	r.POST("/groups/:id/admins", fix.handler.AddGroupAdmin)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/groups/"+groupID.String()+"/admins",
+		strings.NewReader(`{"user_id":"`+targetID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp models.GroupAdmin
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, targetID, resp.UserID)
+}
+
+func TestOrgAdminHandler_AddGroupAdmin_ShouldReturn400_WhenUserIDMissing(t *testing.T) {
+	fix := setupOrgAdminTestFixture()
+
+	r := fix.router()
+	r.POST("/groups/:id/admins", fix.handler.AddGroupAdmin)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/groups/"+uuid.New().String()+"/admins", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// ===========================================================================
+// ListMyOrgs
+// ===========================================================================
+
+func TestOrgAdminHandler_ListMyOrgs_ShouldReturnOrganizationsOfCurrentUser(t *testing.T) {
+	fix := setupOrgAdminTestFixture()
+	orgID := uuid.New()
+
+	fix.svc.ListManagedOrgsFunc = func(userID uuid.UUID) (*models.OrgListResponse, error) {
+		assert.Equal(t, fix.userID, userID)
+		return &models.OrgListResponse{Organizations: []models.GroupResponse{{ID: orgID, Name: "acme"}}}, nil
+	}
+
+	r := fix.router()
+	r.GET("/orgs", fix.handler.ListMyOrgs)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orgs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.OrgListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Organizations, 1)
+	assert.Equal(t, orgID, resp.Organizations[0].ID)
+}
+
+// ===========================================================================
+// AssignRole
+// ===========================================================================
+
+func TestOrgAdminHandler_AssignRole_ShouldReturnServiceError(t *testing.T) {
+	fix := setupOrgAdminTestFixture()
+
+	fix.svc.AssignRoleFunc = func(groupID, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+		return nil, models.NewAppError(http.StatusForbidden, "System roles cannot be assigned by organization admins")
+	}
+
+	r := fix.router()
+	r.POST("/orgs/:id/users/:user_id/roles", fix.handler.AssignRole)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/orgs/"+uuid.New().String()+"/users/"+uuid.New().String()+"/roles",
+		strings.NewReader(`{"role_id":"`+uuid.New().String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "System roles cannot be assigned")
+}
+
+// ===========================================================================
+// RevokeAPIKey
+// ===========================================================================
+
+func TestOrgAdminHandler_RevokeAPIKey_ShouldPassOrganizationAndKey(t *testing.T) {
+	fix := setupOrgAdminTestFixture()
+	groupID := uuid.New()
+	keyID := uuid.New()
+
+	fix.svc.RevokeAPIKeyFunc = func(gid, kid uuid.UUID) error {
+		assert.Equal(t, groupID, gid)
+		assert.Equal(t, keyID, kid)
+		return nil
+	}
+
+	r := fix.router()
+	r.POST("/orgs/:id/api-keys/:key_id/revoke", fix.handler.RevokeAPIKey)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orgs/"+groupID.String()+"/api-keys/"+keyID.String()+"/revoke", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
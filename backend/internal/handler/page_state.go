@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pageStateTTL bounds how long a hosted page may stay open before its form is submitted
+const pageStateTTL = 15 * time.Minute
+
+// Kinds of hosted page state; a token only verifies for the kind it was signed for
+const (
+	pageStateConsent = "consent"
+	pageStateDevice  = "device"
+	pageStateLogout  = "logout"
+)
+
+var errInvalidPageState = errors.New("invalid or expired page state")
+
+// pageStateSigner carries the intermediate state of multi-step hosted pages (consent, device
+// verification) in the page itself as a signed token. Every replica sharing the secret can
+// serve the next step, so the flows need neither sticky sessions nor server-side storage.
+//
+// Tokens are bound to the signed-in user and expire after pageStateTTL. They are signed,
+// not encrypted: the values are the request parameters the browser already saw.
+type pageStateSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+type pageStatePayload struct {
+	Kind      string     `json:"k"`
+	UserID    uuid.UUID  `json:"u"`
+	ExpiresAt int64      `json:"e"`
+	Values    url.Values `json:"v"`
+}
+
+func newPageStateSigner(secret string) *pageStateSigner {
+	return &pageStateSigner{secret: []byte(secret), now: time.Now}
+}
+
+// Sign returns a token carrying values for the user's next step of a kind of page
+func (s *pageStateSigner) Sign(kind string, userID uuid.UUID, values url.Values) (string, error) {
+	payload, err := json.Marshal(pageStatePayload{
+		Kind:      kind,
+		UserID:    userID,
+		ExpiresAt: s.now().Add(pageStateTTL).Unix(),
+		Values:    values,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.signature(encoded), nil
+}
+
+// Verify returns the values of a token signed for the kind of page and user
+func (s *pageStateSigner) Verify(token, kind string, userID uuid.UUID) (url.Values, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, errInvalidPageState
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidPageState
+	}
+	var payload pageStatePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, errInvalidPageState
+	}
+
+	if payload.Kind != kind || payload.UserID != userID || s.now().Unix() > payload.ExpiresAt {
+		return nil, errInvalidPageState
+	}
+	return payload.Values, nil
+}
+
+func (s *pageStateSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
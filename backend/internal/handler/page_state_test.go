@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageStateSigner_RoundTrip(t *testing.T) {
+	signer := newPageStateSigner("test-secret")
+	userID := uuid.New()
+	values := url.Values{"client_id": {"app"}, "redirect_uri": {"https://app.example.com/cb"}}
+
+	token, err := signer.Sign(pageStateConsent, userID, values)
+	require.NoError(t, err)
+
+	got, err := signer.Verify(token, pageStateConsent, userID)
+	require.NoError(t, err)
+	assert.Equal(t, values, got)
+
+	// Another replica with the same secret accepts the token
+	got, err = newPageStateSigner("test-secret").Verify(token, pageStateConsent, userID)
+	require.NoError(t, err)
+	assert.Equal(t, values, got)
+}
+
+func TestPageStateSigner_Rejects(t *testing.T) {
+	signer := newPageStateSigner("test-secret")
+	userID := uuid.New()
+	token, err := signer.Sign(pageStateDevice, userID, url.Values{"user_code": {"ABCD-EFGH"}})
+	require.NoError(t, err)
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	tampered, err := signer.Sign(pageStateDevice, userID, url.Values{"user_code": {"WXYZ-1234"}})
+	require.NoError(t, err)
+	tamperedEncoded, _, _ := strings.Cut(tampered, ".")
+
+	tests := []struct {
+		name   string
+		signer *pageStateSigner
+		token  string
+		kind   string
+		userID uuid.UUID
+	}{
+		{"wrong kind", signer, token, pageStateConsent, userID},
+		{"wrong user", signer, token, pageStateDevice, uuid.New()},
+		{"wrong secret", newPageStateSigner("other-secret"), token, pageStateDevice, userID},
+		{"swapped payload", signer, tamperedEncoded + "." + signature, pageStateDevice, userID},
+		{"missing signature", signer, encoded, pageStateDevice, userID},
+		{"empty", signer, "", pageStateDevice, userID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.signer.Verify(tt.token, tt.kind, tt.userID)
+			assert.ErrorIs(t, err, errInvalidPageState)
+		})
+	}
+}
+
+func TestPageStateSigner_Expires(t *testing.T) {
+	now := time.Now()
+	signer := newPageStateSigner("test-secret")
+	signer.now = func() time.Time { return now }
+	userID := uuid.New()
+
+	token, err := signer.Sign(pageStateConsent, userID, url.Values{})
+	require.NoError(t, err)
+
+	now = now.Add(pageStateTTL - time.Second)
+	_, err = signer.Verify(token, pageStateConsent, userID)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+	_, err = signer.Verify(token, pageStateConsent, userID)
+	assert.ErrorIs(t, err, errInvalidPageState)
+}
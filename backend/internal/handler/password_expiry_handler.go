@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// defaultExpiryReportDays is the look-ahead window of the upcoming expirations report
+const defaultExpiryReportDays = 14
+
+// PasswordExpiryHandler handles password max-age policies and forced rotation (admin only)
+type PasswordExpiryHandler struct {
+	passwordExpiry service.PasswordExpiryServicer
+	auditService   service.AuditServicer
+	logger         *logger.Logger
+}
+
+// NewPasswordExpiryHandler creates a new password expiry handler
+func NewPasswordExpiryHandler(passwordExpiry service.PasswordExpiryServicer, auditService service.AuditServicer, logger *logger.Logger) *PasswordExpiryHandler {
+	return &PasswordExpiryHandler{
+		passwordExpiry: passwordExpiry,
+		auditService:   auditService,
+		logger:         logger,
+	}
+}
+
+// SetRolePasswordMaxAge sets the password max age for members of a role
+// @Summary Set role password max age
+// @Description Set or clear (null) the maximum password age for members of a role. The strictest role, group or global policy applies (admin only)
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID (UUID)"
+// @Param request body models.SetPasswordMaxAgeRequest true "Max age in days"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/rbac/roles/{id}/password-policy [put]
+func (h *PasswordExpiryHandler) SetRolePasswordMaxAge(c *gin.Context) {
+	h.setMaxAge(c, "role", h.passwordExpiry.SetRoleMaxAge)
+}
+
+// SetGroupPasswordMaxAge sets the password max age for members of a group
+// @Summary Set group password max age
+// @Description Set or clear (null) the maximum password age for members of a group. The strictest role, group or global policy applies (admin only)
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param request body models.SetPasswordMaxAgeRequest true "Max age in days"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/password-policy [put]
+func (h *PasswordExpiryHandler) SetGroupPasswordMaxAge(c *gin.Context) {
+	h.setMaxAge(c, "group", h.passwordExpiry.SetGroupMaxAge)
+}
+
+func (h *PasswordExpiryHandler) setMaxAge(c *gin.Context, resource string, set func(ctx context.Context, id uuid.UUID, maxAgeDays *int) error) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetPasswordMaxAgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	if err := set(c.Request.Context(), id, req.MaxAgeDays); err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to set password max age", map[string]interface{}{
+				"resource": resource,
+				"id":       id.String(),
+				"error":    err.Error(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionPasswordPolicyUpdate,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"resource":     resource,
+			"resource_id":  id.String(),
+			"max_age_days": req.MaxAgeDays,
+		},
+	})
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Password policy updated"})
+}
+
+// RequirePasswordChange forces a user to change their password at next sign-in
+// @Summary Require password change
+// @Description Flag the user so their next sign-in is rejected with PASSWORD_CHANGE_REQUIRED until they set a new password (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/require-password-change [post]
+func (h *PasswordExpiryHandler) RequirePasswordChange(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.passwordExpiry.RequirePasswordChange(c.Request.Context(), userID); err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to require password change", map[string]interface{}{
+				"user_id": userID.String(),
+				"error":   err.Error(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionPasswordChangeRequired,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target_user_id": userID.String(),
+		},
+	})
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "User must change password at next sign-in"})
+}
+
+// ListUpcomingExpirations reports users whose passwords expire soon
+// @Summary List upcoming password expirations
+// @Description List active users whose password expires within the given number of days, soonest first; already expired passwords are included (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Look-ahead window in days" default(14)
+// @Success 200 {object} models.PasswordExpiryListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/password-expirations [get]
+func (h *PasswordExpiryHandler) ListUpcomingExpirations(c *gin.Context) {
+	days := defaultExpiryReportDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "days must be a non-negative integer"),
+			))
+			return
+		}
+		days = parsed
+	}
+
+	response, err := h.passwordExpiry.ListUpcomingExpirations(c.Request.Context(), days)
+	if err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to list upcoming password expirations", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
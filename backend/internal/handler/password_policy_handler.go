@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// PasswordPolicyHandler handles the password policy (admin only)
+type PasswordPolicyHandler struct {
+	passwordPolicyService service.PasswordPolicyServicer
+	auditService          service.AuditServicer
+	logger                *logger.Logger
+}
+
+// NewPasswordPolicyHandler creates a new password policy handler
+func NewPasswordPolicyHandler(passwordPolicyService service.PasswordPolicyServicer, auditService service.AuditServicer, logger *logger.Logger) *PasswordPolicyHandler {
+	return &PasswordPolicyHandler{
+		passwordPolicyService: passwordPolicyService,
+		auditService:          auditService,
+		logger:                logger,
+	}
+}
+
+// GetPasswordPolicy handles retrieving the password policy
+// @Summary Get password policy
+// @Description Length, character class, history, dictionary and breach rules applied to new passwords
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.PasswordPolicy
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/password-policy/v2 [get]
+func (h *PasswordPolicyHandler) GetPasswordPolicy(c *gin.Context) {
+	policy, err := h.passwordPolicyService.GetPolicy(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePasswordPolicy handles replacing the password policy
+// @Summary Update password policy
+// @Description Replace the password policy. It applies to passwords set at sign-up, guest upgrade, password change, password reset and admin user creation; existing passwords are not rechecked. Rejected passwords get a 400 with code PASSWORD_POLICY_VIOLATION listing every broken rule.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.UpdatePasswordPolicyRequest true "Password policy"
+// @Success 200 {object} models.PasswordPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/password-policy/v2 [put]
+func (h *PasswordPolicyHandler) UpdatePasswordPolicy(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePasswordPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	policy, err := h.passwordPolicyService.UpdatePolicy(c.Request.Context(), &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionPasswordPolicyUpdate,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"min_length":        policy.MinLength,
+			"max_length":        policy.MaxLength,
+			"require_uppercase": policy.RequireUppercase,
+			"require_lowercase": policy.RequireLowercase,
+			"require_numbers":   policy.RequireNumbers,
+			"require_special":   policy.RequireSpecial,
+			"history_count":     policy.HistoryCount,
+			"check_dictionary":  policy.CheckDictionary,
+			"check_compromised": policy.CheckCompromised,
+		},
+	})
+
+	c.JSON(http.StatusOK, policy)
+}
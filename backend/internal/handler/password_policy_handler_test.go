@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPasswordPolicyHandler(adminID uuid.UUID) (*PasswordPolicyHandler, *mockPasswordPolicyServicer, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockPasswordPolicyServicer{}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	return NewPasswordPolicyHandler(svc, audit, testLogger()), svc, audit, r
+}
+
+func TestPasswordPolicyHandler_GetPasswordPolicy_ShouldReturn200(t *testing.T) {
+	h, svc, _, r := setupPasswordPolicyHandler(uuid.New())
+	svc.GetPolicyFunc = func() (*models.PasswordPolicy, error) {
+		return &models.PasswordPolicy{MinLength: 12, CheckCompromised: true}, nil
+	}
+	r.GET("/password-policy", h.GetPasswordPolicy)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/password-policy", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"min_length":12`)
+	assert.Contains(t, w.Body.String(), `"check_compromised":true`)
+}
+
+func TestPasswordPolicyHandler_UpdatePasswordPolicy_ShouldReturn200_AndAudit(t *testing.T) {
+	adminID := uuid.New()
+	h, svc, audit, r := setupPasswordPolicyHandler(adminID)
+
+	svc.UpdatePolicyFunc = func(req *models.UpdatePasswordPolicyRequest, updatedBy uuid.UUID) (*models.PasswordPolicy, error) {
+		assert.Equal(t, adminID, updatedBy)
+		return &models.PasswordPolicy{MinLength: req.MinLength, HistoryCount: req.HistoryCount, CheckDictionary: req.CheckDictionary}, nil
+	}
+	r.PUT("/password-policy", h.UpdatePasswordPolicy)
+
+	w := httptest.NewRecorder()
+	body := `{"min_length":14,"history_count":5,"check_dictionary":true}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/password-policy", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.PasswordPolicy
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 14, resp.MinLength)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionPasswordPolicyUpdate, audit.Logged[0].Action)
+	assert.Equal(t, 5, audit.Logged[0].Details["history_count"])
+}
+
+func TestPasswordPolicyHandler_UpdatePasswordPolicy_ShouldReturn400_WhenBodyInvalid(t *testing.T) {
+	h, _, audit, r := setupPasswordPolicyHandler(uuid.New())
+	r.PUT("/password-policy", h.UpdatePasswordPolicy)
+
+	for _, body := range []string{`{invalid}`, `{"min_length":0}`, `{"min_length":8,"history_count":-1}`} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/password-policy", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Empty(t, audit.Logged)
+}
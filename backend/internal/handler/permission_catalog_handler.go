@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// PermissionCatalogHandler serves the machine-readable permissions catalog
+type PermissionCatalogHandler struct {
+	service service.PermissionCatalogServicer
+	logger  *logger.Logger
+}
+
+// NewPermissionCatalogHandler creates a new permission catalog handler
+func NewPermissionCatalogHandler(service service.PermissionCatalogServicer, logger *logger.Logger) *PermissionCatalogHandler {
+	return &PermissionCatalogHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetCatalog godoc
+// @Summary Get permissions catalog
+// @Description Get every known resource/action pair with its description and the roles that grant it. Pairs come from the RBAC registry and from services that registered their resources over gRPC (RegisterPermissionCatalog); registered pairs without a permission have no roles yet.
+// @Tags Admin - RBAC
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.PermissionCatalogResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/rbac/catalog [get]
+func (h *PermissionCatalogHandler) GetCatalog(c *gin.Context) {
+	catalog, err := h.service.GetCatalog(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to build permissions catalog", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusOK, catalog)
+}
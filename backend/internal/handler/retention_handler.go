@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// RetentionHandler handles erasure of users' personal data (admin only)
+type RetentionHandler struct {
+	retentionService service.RetentionServicer
+	logger           *logger.Logger
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService service.RetentionServicer, logger *logger.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+// EraseUser handles a GDPR erasure request for a user
+// @Summary Erase a user's personal data
+// @Description Scrubs the user's profile, contact details, credentials, sign-in history and the IP addresses, user agents, locations and details of their audit log entries, then deletes the account. The user row and audit log entries are kept so references to them stay valid. Administrators cannot be erased.
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} models.UserErasureResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/gdpr-erase [delete]
+func (h *RetentionHandler) EraseUser(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.retentionService.EraseUser(c.Request.Context(), service.UserErasureParams{
+		UserID:    userID,
+		ActorID:   adminID,
+		IP:        utils.GetClientIP(c),
+		UserAgent: utils.GetUserAgent(c),
+	})
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
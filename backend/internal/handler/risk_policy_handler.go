@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// RiskPolicyHandler manages the risk policies sign-in attempts are scored by
+type RiskPolicyHandler struct {
+	riskService  service.RiskPolicyServicer
+	auditService service.AuditServicer
+	logger       *logger.Logger
+}
+
+// NewRiskPolicyHandler creates a new risk policy handler
+func NewRiskPolicyHandler(riskService service.RiskPolicyServicer, auditService service.AuditServicer, logger *logger.Logger) *RiskPolicyHandler {
+	return &RiskPolicyHandler{
+		riskService:  riskService,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListPolicies handles listing all risk policies
+// @Summary List risk policies
+// @Description All risk policies, including inactive ones
+// @Tags Admin - Risk
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.RiskPolicyListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/risk/policies [get]
+func (h *RiskPolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.riskService.ListPolicies(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RiskPolicyListResponse{Policies: policies})
+}
+
+// GetPolicy handles getting a risk policy
+// @Summary Get risk policy
+// @Tags Admin - Risk
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Success 200 {object} models.RiskPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/risk/policies/{id} [get]
+func (h *RiskPolicyHandler) GetPolicy(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	policy, err := h.riskService.GetPolicy(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// CreatePolicy handles creating a risk policy
+// @Summary Create risk policy
+// @Description Create a policy scoring the password sign-ins of an application, or of every application without its own policy when application_id is omitted. Each raised signal adds its weight to the score; at step_up_threshold users with 2FA must verify a code and users without it get step_up_fallback, at block_threshold the sign-in is rejected.
+// @Tags Admin - Risk
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateRiskPolicyRequest true "Policy"
+// @Success 201 {object} models.RiskPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Name taken or the application already has a policy"
+// @Router /api/admin/risk/policies [post]
+func (h *RiskPolicyHandler) CreatePolicy(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateRiskPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	policy, err := h.riskService.CreatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logPolicyChange(c, adminID, models.ActionRiskPolicyCreate, policy)
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy handles updating a risk policy
+// @Summary Update risk policy
+// @Description Update a risk policy; omitted fields are kept and weights are replaced as a whole. Name and application cannot change.
+// @Tags Admin - Risk
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Param request body models.UpdateRiskPolicyRequest true "Changes"
+// @Success 200 {object} models.RiskPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/risk/policies/{id} [put]
+func (h *RiskPolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRiskPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	policy, err := h.riskService.UpdatePolicy(c.Request.Context(), id, &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logPolicyChange(c, adminID, models.ActionRiskPolicyUpdate, policy)
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy handles deleting a risk policy
+// @Summary Delete risk policy
+// @Description Delete a risk policy. Sign-ins it applied to fall back to the global policy, if any.
+// @Tags Admin - Risk
+// @Security BearerAuth
+// @Param id path string true "Policy ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/admin/risk/policies/{id} [delete]
+func (h *RiskPolicyHandler) DeletePolicy(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.riskService.DeletePolicy(c.Request.Context(), id)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.logPolicyChange(c, adminID, models.ActionRiskPolicyDelete, policy)
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RiskPolicyHandler) logPolicyChange(c *gin.Context, adminID uuid.UUID, action models.AuditAction, policy *models.RiskPolicy) {
+	h.logger.Info("Risk policy changed", map[string]interface{}{
+		"action":   string(action),
+		"policy":   policy.Name,
+		"admin_id": adminID.String(),
+	})
+	details := map[string]interface{}{
+		"policy_id":         policy.ID.String(),
+		"policy":            policy.Name,
+		"weights":           policy.Weights,
+		"step_up_threshold": policy.StepUpThreshold,
+		"block_threshold":   policy.BlockThreshold,
+		"step_up_fallback":  policy.StepUpFallback,
+		"is_active":         policy.IsActive,
+	}
+	if policy.ApplicationID != nil {
+		details["application_id"] = policy.ApplicationID.String()
+	}
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    action,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details:   details,
+	})
+}
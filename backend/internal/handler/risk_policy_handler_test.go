@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRiskPolicyService implements service.RiskPolicyServicer
+type mockRiskPolicyService struct {
+	policy    *models.RiskPolicy
+	createReq *models.CreateRiskPolicyRequest
+	deleteErr error
+}
+
+func (m *mockRiskPolicyService) CreatePolicy(_ context.Context, req *models.CreateRiskPolicyRequest) (*models.RiskPolicy, error) {
+	m.createReq = req
+	return &models.RiskPolicy{ID: uuid.New(), Name: req.Name, Weights: req.Weights, IsActive: true}, nil
+}
+func (m *mockRiskPolicyService) GetPolicy(_ context.Context, _ uuid.UUID) (*models.RiskPolicy, error) {
+	return m.policy, nil
+}
+func (m *mockRiskPolicyService) ListPolicies(_ context.Context) ([]*models.RiskPolicy, error) {
+	return []*models.RiskPolicy{m.policy}, nil
+}
+func (m *mockRiskPolicyService) UpdatePolicy(_ context.Context, _ uuid.UUID, _ *models.UpdateRiskPolicyRequest) (*models.RiskPolicy, error) {
+	return m.policy, nil
+}
+func (m *mockRiskPolicyService) DeletePolicy(_ context.Context, _ uuid.UUID) (*models.RiskPolicy, error) {
+	if m.deleteErr != nil {
+		return nil, m.deleteErr
+	}
+	return m.policy, nil
+}
+
+func setupRiskPolicyHandler(adminID uuid.UUID) (*mockRiskPolicyService, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockRiskPolicyService{policy: &models.RiskPolicy{ID: uuid.New(), Name: "default"}}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	h := NewRiskPolicyHandler(svc, audit, testLogger())
+	r.POST("/admin/risk/policies", h.CreatePolicy)
+	r.PUT("/admin/risk/policies/:id", h.UpdatePolicy)
+	r.DELETE("/admin/risk/policies/:id", h.DeletePolicy)
+	return svc, audit, r
+}
+
+func TestRiskPolicyHandler_CreatePolicy_ShouldAudit(t *testing.T) {
+	adminID := uuid.New()
+	svc, audit, r := setupRiskPolicyHandler(adminID)
+
+	w := httptest.NewRecorder()
+	body := `{"name":"default","weights":{"new_device":20,"tor_exit_node":50},"step_up_threshold":20,"block_threshold":70}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/risk/policies", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, map[string]int{"new_device": 20, "tor_exit_node": 50}, svc.createReq.Weights)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionRiskPolicyCreate, audit.Logged[0].Action)
+	assert.Equal(t, adminID, *audit.Logged[0].UserID)
+}
+
+func TestRiskPolicyHandler_CreatePolicy_ShouldReturn400_WhenSignalUnknown(t *testing.T) {
+	_, audit, r := setupRiskPolicyHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	body := `{"name":"default","weights":{"full_moon":20},"step_up_threshold":20}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/risk/policies", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestRiskPolicyHandler_CreatePolicy_ShouldReturn400_WhenWeightOutOfRange(t *testing.T) {
+	_, audit, r := setupRiskPolicyHandler(uuid.New())
+
+	w := httptest.NewRecorder()
+	body := `{"name":"default","weights":{"velocity":500},"step_up_threshold":20}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/risk/policies", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestRiskPolicyHandler_DeletePolicy_ShouldNotAudit_WhenNotFound(t *testing.T) {
+	svc, audit, r := setupRiskPolicyHandler(uuid.New())
+	svc.deleteErr = models.NewAppError(http.StatusNotFound, "Risk policy not found")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/risk/policies/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, audit.Logged)
+}
@@ -168,6 +168,73 @@ func (h *SCIMHandler) GetGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, group)
 }
 
+// CreateGroup handles POST /scim/v2/Groups
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var scimGroup models.SCIMGroup
+	if err := c.ShouldBindJSON(&scimGroup); err != nil {
+		h.handleSCIMError(c, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	group, err := h.scimService.CreateGroup(c.Request.Context(), &scimGroup)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// ReplaceGroup handles PUT /scim/v2/Groups/{id}
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	var scimGroup models.SCIMGroup
+	if err := c.ShouldBindJSON(&scimGroup); err != nil {
+		h.handleSCIMError(c, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	group, err := h.scimService.ReplaceGroup(c.Request.Context(), id, &scimGroup)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// PatchGroup handles PATCH /scim/v2/Groups/{id}
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	var patchReq models.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&patchReq); err != nil {
+		h.handleSCIMError(c, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	group, err := h.scimService.PatchGroup(c.Request.Context(), id, &patchReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup handles DELETE /scim/v2/Groups/{id}
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.scimService.DeleteGroup(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig
 func (h *SCIMHandler) GetServiceProviderConfig(c *gin.Context) {
 	config := h.scimService.GetServiceProviderConfig(c.Request.Context())
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SignedURLHandler mints and verifies signed URLs to protected resources
+type SignedURLHandler struct {
+	signedURLs service.SignedURLServicer
+	logger     *logger.Logger
+}
+
+// NewSignedURLHandler creates a new signed URL handler
+func NewSignedURLHandler(signedURLs service.SignedURLServicer, logger *logger.Logger) *SignedURLHandler {
+	return &SignedURLHandler{
+		signedURLs: signedURLs,
+		logger:     logger,
+	}
+}
+
+// CreateSignedURL handles minting a signed URL for the authenticated user
+// @Summary Create signed URL
+// @Description Sign a URL to a protected resource so it can be fetched without a bearer token, e.g. by a browser download or an <img> tag. The URL is bound to the user, the requested scopes and one method. Its origin must be in SIGNED_URL_ALLOWED_ORIGINS and the user must hold every scope as a permission. The lifetime defaults to SIGNED_URL_DEFAULT_TTL and is capped by SIGNED_URL_MAX_TTL.
+// @Tags Signed URLs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSignedURLRequest true "URL, method, scopes and lifetime"
+// @Success 201 {object} models.SignedURLResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/signed-urls [post]
+func (h *SignedURLHandler) CreateSignedURL(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSignedURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	resp, err := h.signedURLs.Create(c.Request.Context(), userID, &req, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// VerifySignedURL handles checking a signed URL on behalf of a resource service
+// @Summary Verify signed URL
+// @Description Checks the signature, expiry and method of a signed URL and returns the user and scopes it grants. For resource services that do not hold SIGNED_URL_SECRET; the URL may be absolute or just the request URI.
+// @Tags Signed URLs
+// @Accept json
+// @Produce json
+// @Param request body models.VerifySignedURLRequest true "Signed URL and request method"
+// @Success 200 {object} models.VerifySignedURLResponse "URL is valid"
+// @Success 401 {object} models.InvalidSignedURLResponse "URL is invalid"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/signed-urls/verify [post]
+func (h *SignedURLHandler) VerifySignedURL(c *gin.Context) {
+	var req models.VerifySignedURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	resp, err := h.signedURLs.Verify(&req)
+	if err != nil {
+		message := "Invalid signed URL"
+		if appErr, ok := err.(*models.AppError); ok {
+			message = appErr.Message
+		}
+		c.JSON(http.StatusUnauthorized, models.InvalidSignedURLResponse{
+			Valid:        false,
+			ErrorMessage: message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
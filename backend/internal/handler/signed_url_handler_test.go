@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSignedURLServicer implements service.SignedURLServicer
+type mockSignedURLServicer struct {
+	createReq *models.CreateSignedURLRequest
+	createErr error
+	verifyErr error
+}
+
+func (m *mockSignedURLServicer) Create(_ context.Context, _ uuid.UUID, req *models.CreateSignedURLRequest, _, _ string) (*models.SignedURLResponse, error) {
+	m.createReq = req
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return &models.SignedURLResponse{URL: req.URL + "?ag_signature=x", ExpiresAt: time.Now().Add(5 * time.Minute)}, nil
+}
+
+func (m *mockSignedURLServicer) Verify(req *models.VerifySignedURLRequest) (*models.VerifySignedURLResponse, error) {
+	if m.verifyErr != nil {
+		return nil, m.verifyErr
+	}
+	return &models.VerifySignedURLResponse{Valid: true, UserID: "user-1", Method: http.MethodGet}, nil
+}
+
+func setupSignedURLHandler() (*mockSignedURLServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockSignedURLServicer{}
+	r := gin.New()
+	h := NewSignedURLHandler(svc, testLogger())
+	r.POST("/signed-urls", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, uuid.New())
+		c.Next()
+	}, h.CreateSignedURL)
+	r.POST("/v1/signed-urls/verify", h.VerifySignedURL)
+	return svc, r
+}
+
+func TestSignedURLHandler_CreateSignedURL_ShouldReturn201(t *testing.T) {
+	svc, r := setupSignedURLHandler()
+
+	w := httptest.NewRecorder()
+	body := `{"url":"https://files.example.com/exports/report.csv","scopes":["exports:read"],"expires_in":60}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signed-urls", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, []string{"exports:read"}, svc.createReq.Scopes)
+	assert.Equal(t, 60, svc.createReq.ExpiresIn)
+}
+
+func TestSignedURLHandler_CreateSignedURL_ShouldReturn400_WhenMethodUnknown(t *testing.T) {
+	svc, r := setupSignedURLHandler()
+
+	w := httptest.NewRecorder()
+	body := `{"url":"https://files.example.com/exports/report.csv","method":"TRACE"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signed-urls", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, svc.createReq)
+}
+
+func TestSignedURLHandler_CreateSignedURL_ShouldPassThroughForbidden(t *testing.T) {
+	svc, r := setupSignedURLHandler()
+	svc.createErr = models.NewAppError(http.StatusForbidden, "Missing permission for a requested scope")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signed-urls", strings.NewReader(`{"url":"https://files.example.com/a"}`)))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSignedURLHandler_VerifySignedURL_ShouldReturnClaims(t *testing.T) {
+	_, r := setupSignedURLHandler()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/signed-urls/verify", strings.NewReader(`{"url":"/exports/report.csv?ag_signature=x"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.VerifySignedURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Valid)
+	assert.Equal(t, "user-1", resp.UserID)
+}
+
+func TestSignedURLHandler_VerifySignedURL_ShouldReturn401WithReason(t *testing.T) {
+	svc, r := setupSignedURLHandler()
+	svc.verifyErr = models.NewAppError(http.StatusUnauthorized, "Signed URL expired")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/signed-urls/verify", strings.NewReader(`{"url":"/exports/report.csv?ag_signature=x"}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp models.InvalidSignedURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Valid)
+	assert.Equal(t, "Signed URL expired", resp.ErrorMessage)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SigningKeyHandler exports OIDC signing key backups and records key ceremonies (admin only)
+type SigningKeyHandler struct {
+	keyManager   *keys.Manager
+	auditService service.AuditServicer
+	logger       *logger.Logger
+}
+
+// NewSigningKeyHandler creates a new signing key handler
+func NewSigningKeyHandler(keyManager *keys.Manager, auditService service.AuditServicer, logger *logger.Logger) *SigningKeyHandler {
+	return &SigningKeyHandler{
+		keyManager:   keyManager,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListKeys handles listing the loaded signing keys
+// @Summary List signing keys
+// @Description OIDC signing keys loaded by this instance, with their RFC 7638 thumbprints. Private keys are never returned.
+// @Tags Admin - Signing Keys
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SigningKeyListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/oauth/signing-keys [get]
+func (h *SigningKeyHandler) ListKeys(c *gin.Context) {
+	infos := h.keyManager.Keys()
+	resp := models.SigningKeyListResponse{Keys: make([]models.SigningKeyInfo, 0, len(infos))}
+	for _, info := range infos {
+		resp.Keys = append(resp.Keys, models.SigningKeyInfo{
+			KID:        info.KID,
+			Algorithm:  string(info.Algorithm),
+			Current:    info.Current,
+			Thumbprint: info.Thumbprint,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExportBackup handles exporting an encrypted backup of the signing keys
+// @Summary Export signing key backup
+// @Description Download every loaded OIDC signing key, encrypted with the passphrase (scrypt and AES-256-GCM). Restore it with `auth-gateway keys import`. The export is audited before the backup is returned.
+// @Tags Admin - Signing Keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ExportSigningKeysRequest true "Backup passphrase"
+// @Success 200 {object} keys.EncryptedBackup
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/signing-keys/backup [post]
+func (h *SigningKeyHandler) ExportBackup(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ExportSigningKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	backup, err := h.keyManager.Backup()
+	if err != nil {
+		h.logger.Error("Failed to back up signing keys", map[string]interface{}{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+	data, err := backup.Encrypt([]byte(req.Passphrase))
+	if err != nil {
+		h.logger.Error("Failed to encrypt signing key backup", map[string]interface{}{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	kids := make([]string, 0, len(backup.Keys))
+	for _, key := range backup.Keys {
+		kids = append(kids, key.KID)
+	}
+	// Key material leaves the server only once the export is on record
+	if err := h.auditService.LogSync(c.Request.Context(), service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionSigningKeyBackupExport,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"kids":        kids,
+			"current_kid": backup.CurrentKID,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to audit signing key export", map[string]interface{}{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	h.logger.Warn("Signing key backup exported", map[string]interface{}{
+		"kids":     kids,
+		"admin_id": adminID.String(),
+	})
+
+	filename := fmt.Sprintf("signing-keys-%s.json", backup.CreatedAt.Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// RecordCeremony handles recording a key ceremony in the audit log
+// @Summary Record key ceremony
+// @Description Record who generated an OIDC signing key, when, with which algorithm and in whose presence. The record is kept in the audit log as a signing_key_ceremony entry; the key does not need to be loaded yet.
+// @Tags Admin - Signing Keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.RecordKeyCeremonyRequest true "Ceremony"
+// @Success 201 {object} models.KeyCeremonyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/oauth/signing-keys/ceremonies [post]
+func (h *SigningKeyHandler) RecordCeremony(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RecordKeyCeremonyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+	if req.GeneratedBy == "" {
+		req.GeneratedBy = adminID.String()
+	}
+
+	resp := models.KeyCeremonyResponse{
+		RecordKeyCeremonyRequest: req,
+		RecordedBy:               adminID.String(),
+		RecordedAt:               time.Now().UTC(),
+	}
+	if err := h.auditService.LogSync(c.Request.Context(), service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionSigningKeyCeremony,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details:   keyCeremonyDetails(req),
+	}); err != nil {
+		h.logger.Error("Failed to record key ceremony", map[string]interface{}{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func keyCeremonyDetails(req models.RecordKeyCeremonyRequest) map[string]interface{} {
+	details := map[string]interface{}{
+		"kid":          req.KID,
+		"algorithm":    req.Algorithm,
+		"generated_by": req.GeneratedBy,
+		"generated_at": req.GeneratedAt.UTC().Format(time.RFC3339),
+	}
+	if req.Thumbprint != "" {
+		details["thumbprint"] = req.Thumbprint
+	}
+	if len(req.Witnesses) > 0 {
+		details["witnesses"] = req.Witnesses
+	}
+	if req.Location != "" {
+		details["location"] = req.Location
+	}
+	if req.Notes != "" {
+		details["notes"] = req.Notes
+	}
+	return details
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSigningKeyHandler(t *testing.T, adminID uuid.UUID) (*mockAuditServicer, *gin.Engine) {
+	t.Helper()
+	privateKey, err := keys.GenerateKey(keys.ES256)
+	require.NoError(t, err)
+	encoded, err := keys.EncodePrivateKey(privateKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key-1.pem")
+	require.NoError(t, os.WriteFile(path, encoded, 0o600))
+	keyManager, err := keys.NewManager([]keys.KeyConfig{{ID: "key-1", Algorithm: keys.ES256, PrivateKeyPath: path}}, "key-1")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	h := NewSigningKeyHandler(keyManager, audit, testLogger())
+	r.GET("/signing-keys", h.ListKeys)
+	r.POST("/signing-keys/backup", h.ExportBackup)
+	r.POST("/signing-keys/ceremonies", h.RecordCeremony)
+	return audit, r
+}
+
+func TestSigningKeyHandler_ListKeys_ShouldReturnKeysWithoutPrivateParts(t *testing.T) {
+	_, r := setupSigningKeyHandler(t, uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/signing-keys", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.SigningKeyListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Keys, 1)
+	assert.Equal(t, "key-1", resp.Keys[0].KID)
+	assert.True(t, resp.Keys[0].Current)
+	assert.NotEmpty(t, resp.Keys[0].Thumbprint)
+	assert.NotContains(t, w.Body.String(), "PRIVATE KEY")
+}
+
+func TestSigningKeyHandler_ExportBackup_ShouldReturnEncryptedBackup_AndAudit(t *testing.T) {
+	audit, r := setupSigningKeyHandler(t, uuid.New())
+	passphrase := "correct horse battery staple"
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signing-keys/backup", strings.NewReader(`{"passphrase":"`+passphrase+`"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.NotContains(t, w.Body.String(), "PRIVATE KEY")
+
+	backup, err := keys.DecryptBackup(w.Body.Bytes(), []byte(passphrase))
+	require.NoError(t, err)
+	require.Len(t, backup.Keys, 1)
+	assert.Equal(t, "key-1", backup.CurrentKID)
+
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionSigningKeyBackupExport, audit.Logged[0].Action)
+}
+
+func TestSigningKeyHandler_ExportBackup_ShouldReturn400_WhenPassphraseTooShort(t *testing.T) {
+	audit, r := setupSigningKeyHandler(t, uuid.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signing-keys/backup", strings.NewReader(`{"passphrase":"short"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestSigningKeyHandler_RecordCeremony_ShouldAuditCeremony(t *testing.T) {
+	adminID := uuid.New()
+	audit, r := setupSigningKeyHandler(t, adminID)
+
+	w := httptest.NewRecorder()
+	body := `{"kid":"key-2","alg":"RS256","generated_at":"2025-03-04T10:00:00Z","witnesses":["bob@example.com"]}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signing-keys/ceremonies", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp models.KeyCeremonyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, adminID.String(), resp.GeneratedBy)
+
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionSigningKeyCeremony, audit.Logged[0].Action)
+	assert.Equal(t, "key-2", audit.Logged[0].Details["kid"])
+	assert.Equal(t, "RS256", audit.Logged[0].Details["algorithm"])
+	assert.Equal(t, []string{"bob@example.com"}, audit.Logged[0].Details["witnesses"])
+}
+
+func TestSigningKeyHandler_RecordCeremony_ShouldReturn400_WhenAlgorithmUnsupported(t *testing.T) {
+	audit, r := setupSigningKeyHandler(t, uuid.New())
+
+	w := httptest.NewRecorder()
+	body := `{"kid":"key-2","alg":"HS256","generated_at":"2025-03-04T10:00:00Z"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signing-keys/ceremonies", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
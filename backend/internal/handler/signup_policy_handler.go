@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SignupPolicyHandler handles sign-up restrictions and the email domains claimed by organizations (admin only)
+type SignupPolicyHandler struct {
+	signupPolicyService service.SignupPolicyServicer
+	auditService        service.AuditServicer
+	logger              *logger.Logger
+}
+
+// NewSignupPolicyHandler creates a new sign-up policy handler
+func NewSignupPolicyHandler(signupPolicyService service.SignupPolicyServicer, auditService service.AuditServicer, logger *logger.Logger) *SignupPolicyHandler {
+	return &SignupPolicyHandler{
+		signupPolicyService: signupPolicyService,
+		auditService:        auditService,
+		logger:              logger,
+	}
+}
+
+// GetSignupPolicy handles retrieving the sign-up policy
+// @Summary Get sign-up policy
+// @Description Allowed and blocked email domains, disposable address blocking and invite-only mode applied to self-service sign-up
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SignupPolicy
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/signup-policy [get]
+func (h *SignupPolicyHandler) GetSignupPolicy(c *gin.Context) {
+	policy, err := h.signupPolicyService.GetPolicy(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateSignupPolicy handles replacing the sign-up policy
+// @Summary Update sign-up policy
+// @Description Replace the sign-up policy. Domains match their subdomains too. The policy applies to password, passwordless and social sign-up; accounts created by admins, SCIM or LDAP are not restricted. In invite-only mode only addresses on verified auto-join organization domains can sign up.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateSignupPolicyRequest true "Sign-up policy"
+// @Success 200 {object} models.SignupPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/signup-policy [put]
+func (h *SignupPolicyHandler) UpdateSignupPolicy(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSignupPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	policy, err := h.signupPolicyService.UpdatePolicy(c.Request.Context(), &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionSignupPolicyUpdate,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"allowed_domains":  policy.AllowedDomains,
+			"blocked_domains":  policy.BlockedDomains,
+			"block_disposable": policy.BlockDisposable,
+			"invite_only":      policy.InviteOnly,
+		},
+	})
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListGroupDomains handles listing the email domains claimed by a group
+// @Summary List group domains
+// @Description Email domains claimed by a group, with their DNS verification status
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.GroupDomainListResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/domains [get]
+func (h *SignupPolicyHandler) ListGroupDomains(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	domains, err := h.signupPolicyService.ListDomains(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// AddGroupDomain handles claiming an email domain for a group
+// @Summary Add group domain
+// @Description Claim an email domain for a group. Publish the returned verification_token as a TXT record at verification_record, then verify the domain. Once verified, users signing up with a confirmed address on the domain join the group when auto_join is enabled (default).
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param request body models.AddGroupDomainRequest true "Domain"
+// @Success 201 {object} models.GroupDomain
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/domains [post]
+func (h *SignupPolicyHandler) AddGroupDomain(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddGroupDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	domain, err := h.signupPolicyService.AddDomain(c.Request.Context(), groupID, &req, adminID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionGroupDomainAdd,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"group_id":  groupID.String(),
+			"domain":    domain.Domain,
+			"auto_join": domain.AutoJoin,
+		},
+	})
+
+	c.JSON(http.StatusCreated, domain)
+}
+
+// VerifyGroupDomain handles checking the DNS TXT record of a claimed domain
+// @Summary Verify group domain
+// @Description Look up the verification TXT record of a claimed domain and mark it verified if the token matches
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param domain_id path string true "Domain ID (UUID)"
+// @Success 200 {object} models.GroupDomain
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/domains/{domain_id}/verify [post]
+func (h *SignupPolicyHandler) VerifyGroupDomain(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	domainID, ok := utils.ParseUUIDParam(c, "domain_id")
+	if !ok {
+		return
+	}
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	domain, err := h.signupPolicyService.VerifyDomain(c.Request.Context(), groupID, domainID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionGroupDomainVerify,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"group_id": groupID.String(),
+			"domain":   domain.Domain,
+		},
+	})
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// RemoveGroupDomain handles releasing a domain claimed by a group
+// @Summary Remove group domain
+// @Description Release a domain claimed by a group. Existing members stay in the group.
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param domain_id path string true "Domain ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/domains/{domain_id} [delete]
+func (h *SignupPolicyHandler) RemoveGroupDomain(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	domainID, ok := utils.ParseUUIDParam(c, "domain_id")
+	if !ok {
+		return
+	}
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.signupPolicyService.RemoveDomain(c.Request.Context(), groupID, domainID); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionGroupDomainRemove,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"group_id":  groupID.String(),
+			"domain_id": domainID.String(),
+		},
+	})
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Domain removed"})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSignupPolicyHandler(adminID uuid.UUID) (*SignupPolicyHandler, *mockSignupPolicyServicer, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockSignupPolicyServicer{}
+	audit := &mockAuditServicer{}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, adminID)
+		c.Next()
+	})
+	return NewSignupPolicyHandler(svc, audit, testLogger()), svc, audit, r
+}
+
+func TestSignupPolicyHandler_UpdateSignupPolicy_ShouldReturn200_AndAudit(t *testing.T) {
+	adminID := uuid.New()
+	h, svc, audit, r := setupSignupPolicyHandler(adminID)
+
+	svc.UpdatePolicyFunc = func(req *models.UpdateSignupPolicyRequest, updatedBy uuid.UUID) (*models.SignupPolicy, error) {
+		assert.Equal(t, adminID, updatedBy)
+		assert.True(t, req.InviteOnly)
+		return &models.SignupPolicy{AllowedDomains: req.AllowedDomains, BlockedDomains: []string{}, InviteOnly: true}, nil
+	}
+	r.PUT("/signup-policy", h.UpdateSignupPolicy)
+
+	w := httptest.NewRecorder()
+	body := `{"allowed_domains":["acme.com"],"invite_only":true}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/signup-policy", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.SignupPolicy
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"acme.com"}, resp.AllowedDomains)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionSignupPolicyUpdate, audit.Logged[0].Action)
+}
+
+func TestSignupPolicyHandler_UpdateSignupPolicy_ShouldReturn400_WhenBodyInvalid(t *testing.T) {
+	h, _, audit, r := setupSignupPolicyHandler(uuid.New())
+	r.PUT("/signup-policy", h.UpdateSignupPolicy)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/signup-policy", strings.NewReader(`{"invite_only":"yes"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
+
+func TestSignupPolicyHandler_AddGroupDomain_ShouldReturn201_WhenSuccessful(t *testing.T) {
+	adminID := uuid.New()
+	h, svc, audit, r := setupSignupPolicyHandler(adminID)
+	groupID := uuid.New()
+
+	svc.AddDomainFunc = func(gid uuid.UUID, req *models.AddGroupDomainRequest, createdBy uuid.UUID) (*models.GroupDomain, error) {
+		assert.Equal(t, groupID, gid)
+		assert.Equal(t, adminID, createdBy)
+		return &models.GroupDomain{ID: uuid.New(), GroupID: gid, Domain: req.Domain, AutoJoin: true}, nil
+	}
+	r.POST("/groups/:id/domains", h.AddGroupDomain)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/groups/"+groupID.String()+"/domains", strings.NewReader(`{"domain":"acme.com"}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionGroupDomainAdd, audit.Logged[0].Action)
+}
+
+func TestSignupPolicyHandler_AddGroupDomain_ShouldReturn400_WhenDomainMissing(t *testing.T) {
+	h, svc, _, r := setupSignupPolicyHandler(uuid.New())
+	svc.AddDomainFunc = func(uuid.UUID, *models.AddGroupDomainRequest, uuid.UUID) (*models.GroupDomain, error) {
+		t.Fatal("service must not be called")
+		return nil, nil
+	}
+	r.POST("/groups/:id/domains", h.AddGroupDomain)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/groups/"+uuid.NewString()+"/domains", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSignupPolicyHandler_VerifyGroupDomain_ShouldReturnServiceError(t *testing.T) {
+	h, svc, audit, r := setupSignupPolicyHandler(uuid.New())
+	svc.VerifyDomainFunc = func(uuid.UUID, uuid.UUID) (*models.GroupDomain, error) {
+		return nil, models.NewAppError(http.StatusBadRequest, "Verification TXT record not found")
+	}
+	r.POST("/groups/:id/domains/:domain_id/verify", h.VerifyGroupDomain)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/groups/"+uuid.NewString()+"/domains/"+uuid.NewString()+"/verify", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, audit.Logged)
+}
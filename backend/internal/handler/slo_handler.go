@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/service"
+)
+
+// SLOHandler serves the service level objectives of the critical endpoints
+type SLOHandler struct {
+	slos *service.SLOService
+}
+
+// NewSLOHandler creates a new SLO handler
+func NewSLOHandler(slos *service.SLOService) *SLOHandler {
+	return &SLOHandler{slos: slos}
+}
+
+// ListSLOs returns the SLOs with their error budgets
+// @Summary List SLOs and error budgets
+// @Description Built-in availability and latency SLOs of the sign-in, token and token validation endpoints, with the error budget left over the SLO window and the burn rates over the alerting windows. Counts are those of the instance answering, since it started.
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SLOListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /api/admin/slos [get]
+func (h *SLOHandler) ListSLOs(c *gin.Context) {
+	c.JSON(http.StatusOK, h.slos.ListSLOs())
+}
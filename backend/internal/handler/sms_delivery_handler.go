@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SMSDeliveryHandler receives delivery status callbacks from the SMS provider
+type SMSDeliveryHandler struct {
+	deliveryService service.SMSDeliveryServicer
+	provider        sms.SMSProvider
+	token           string
+	logger          *logger.Logger
+}
+
+// NewSMSDeliveryHandler creates a new SMS delivery status handler. Callbacks must carry
+// token as the token query parameter.
+func NewSMSDeliveryHandler(
+	deliveryService service.SMSDeliveryServicer,
+	provider sms.SMSProvider,
+	token string,
+	logger *logger.Logger,
+) *SMSDeliveryHandler {
+	return &SMSDeliveryHandler{
+		deliveryService: deliveryService,
+		provider:        provider,
+		token:           token,
+		logger:          logger,
+	}
+}
+
+// StatusCallback records a delivery status report
+// @Summary SMS delivery status callback
+// @Description Called by the SMS provider when the delivery status of a message changes. Twilio posts form data; Vonage sends query parameters or JSON. Final outcomes are recorded in the audit log.
+// @Tags SMS
+// @Param provider path string true "SMS provider, e.g. twilio or vonage"
+// @Param token query string true "Callback token configured in SMS_STATUS_CALLBACK_TOKEN"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/sms/status/{provider} [post]
+func (h *SMSDeliveryHandler) StatusCallback(c *gin.Context) {
+	if !utils.CompareHashConstantTime(c.Query("token"), h.token) {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse(
+			models.NewAppError(http.StatusUnauthorized, "Invalid callback token"),
+		))
+		return
+	}
+
+	provider := c.Param("provider")
+	reporter, ok := h.provider.(sms.DeliveryReporter)
+	if !ok || provider != h.provider.GetProviderName() {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(
+			models.NewAppError(http.StatusNotFound, "Delivery reports are not enabled for this provider"),
+		))
+		return
+	}
+
+	report, err := reporter.ParseDeliveryReport(c.Request)
+	if err != nil {
+		if errors.Is(err, sms.ErrDeliveryReportsUnsupported) {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse(
+				models.NewAppError(http.StatusNotFound, "Delivery reports are not enabled for this provider"),
+			))
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid delivery report", err.Error()),
+		))
+		return
+	}
+
+	if err := h.deliveryService.RecordDeliveryReport(c.Request.Context(), provider, report, utils.GetClientIP(c)); err != nil {
+		h.logger.Error("Failed to record SMS delivery report", map[string]interface{}{
+			"provider":   provider,
+			"message_id": report.MessageID,
+			"error":      err.Error(),
+		})
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
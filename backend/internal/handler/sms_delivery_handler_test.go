@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCallbackToken = "callback-token-0123456789abcdef01234"
+
+type mockSMSDeliveryService struct {
+	provider string
+	reports  []*sms.DeliveryReport
+}
+
+func (m *mockSMSDeliveryService) RecordDeliveryReport(_ context.Context, provider string, report *sms.DeliveryReport, _ string) error {
+	m.provider = provider
+	m.reports = append(m.reports, report)
+	return nil
+}
+
+func setupSMSDeliveryHandler(t *testing.T, provider sms.SMSProvider) (*mockSMSDeliveryService, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc := &mockSMSDeliveryService{}
+	h := NewSMSDeliveryHandler(svc, provider, testCallbackToken, testLogger())
+
+	r := gin.New()
+	r.POST("/api/sms/status/:provider", h.StatusCallback)
+	return svc, r
+}
+
+func twilioStatusRequest(path string) *http.Request {
+	form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"delivered"}}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSMSDeliveryHandler_StatusCallback(t *testing.T) {
+	twilio, err := sms.NewTwilioProvider(sms.TwilioConfig{AccountSID: "AC123", AuthToken: "token", FromNumber: "+15550001111"})
+	require.NoError(t, err)
+
+	t.Run("Records the report", func(t *testing.T) {
+		svc, r := setupSMSDeliveryHandler(t, twilio)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, twilioStatusRequest("/api/sms/status/twilio?token="+testCallbackToken))
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "twilio", svc.provider)
+		require.Len(t, svc.reports, 1)
+		assert.Equal(t, "SM123", svc.reports[0].MessageID)
+		assert.Equal(t, sms.DeliveryStatusDelivered, svc.reports[0].Status)
+	})
+
+	t.Run("Rejects a wrong token", func(t *testing.T) {
+		svc, r := setupSMSDeliveryHandler(t, twilio)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, twilioStatusRequest("/api/sms/status/twilio?token=wrong"))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, svc.reports)
+	})
+
+	t.Run("Rejects another provider", func(t *testing.T) {
+		svc, r := setupSMSDeliveryHandler(t, twilio)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, twilioStatusRequest("/api/sms/status/vonage?token="+testCallbackToken))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, svc.reports)
+	})
+
+	t.Run("Rejects a provider without delivery reports", func(t *testing.T) {
+		svc, r := setupSMSDeliveryHandler(t, sms.NewMockProvider())
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, twilioStatusRequest("/api/sms/status/mock?token="+testCallbackToken))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, svc.reports)
+	})
+
+	t.Run("Rejects a malformed report", func(t *testing.T) {
+		svc, r := setupSMSDeliveryHandler(t, twilio)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/sms/status/twilio?token="+testCallbackToken, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, svc.reports)
+	})
+}
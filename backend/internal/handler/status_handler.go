@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/internal/service"
+)
+
+const (
+	// statusCacheTTL bounds how often public requests reach the dependencies
+	statusCacheTTL = 10 * time.Second
+	// statusCheckTimeout is how long a single dependency check may take
+	statusCheckTimeout = 3 * time.Second
+	// statusDegradedLatency is the check latency above which a component is reported degraded
+	statusDegradedLatency = 500 * time.Millisecond
+)
+
+// statusCheck probes one dependency of the service
+type statusCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// StatusHandler serves the public status of the service
+type StatusHandler struct {
+	checks    []statusCheck
+	settings  service.SystemRepositoryInterface
+	version   string
+	startedAt time.Time
+
+	mu       sync.Mutex
+	cached   *models.PublicStatusResponse
+	cachedAt time.Time
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(db *repository.Database, redis service.RedisServicer, settings service.SystemRepositoryInterface, version string, startedAt time.Time) *StatusHandler {
+	return &StatusHandler{
+		checks: []statusCheck{
+			{name: "database", check: func(ctx context.Context) error { return db.PingContext(ctx) }},
+			{name: "redis", check: redis.Health},
+		},
+		settings:  settings,
+		version:   version,
+		startedAt: startedAt,
+	}
+}
+
+// Status returns the public status of the service
+// @Summary Public service status
+// @Description Sanitized status of the service for public status pages: version, uptime, the status and check latency of each dependency, and the maintenance in progress. Always answers 200 and is served during maintenance; results are cached for 10 seconds. Use /health for probes and /api/admin/system/health for details.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.PublicStatusResponse
+// @Router /status [get]
+func (h *StatusHandler) Status(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=10")
+	c.JSON(http.StatusOK, h.status(c.Request.Context()))
+}
+
+// status returns the cached status, running the checks again once it is older than statusCacheTTL
+func (h *StatusHandler) status(ctx context.Context) *models.PublicStatusResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if h.cached != nil && now.Sub(h.cachedAt) < statusCacheTTL {
+		return h.cached
+	}
+
+	response := &models.PublicStatusResponse{
+		Status:     models.StatusOperational,
+		Version:    h.version,
+		Uptime:     int64(now.Sub(h.startedAt).Seconds()),
+		Components: h.checkComponents(ctx),
+		CheckedAt:  now.UTC(),
+	}
+
+	for _, component := range response.Components {
+		if component.Status == models.StatusOutage {
+			response.Status = models.StatusOutage
+			break
+		}
+		if component.Status == models.StatusDegraded {
+			response.Status = models.StatusDegraded
+		}
+	}
+
+	if window := h.maintenanceWindow(ctx); window != nil {
+		response.Status = models.StatusMaintenance
+		response.Maintenance = window
+	}
+
+	h.cached = response
+	h.cachedAt = now
+	return response
+}
+
+// checkComponents runs the dependency checks concurrently. Errors are reduced to a status:
+// they may name hosts or credentials and are not for public pages.
+func (h *StatusHandler) checkComponents(ctx context.Context) []models.PublicStatusComponent {
+	components := make([]models.PublicStatusComponent, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check statusCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.check(checkCtx)
+			latency := time.Since(start)
+
+			component := models.PublicStatusComponent{Name: check.name, Status: models.StatusOperational}
+			switch {
+			case err != nil:
+				component.Status = models.StatusOutage
+			case latency > statusDegradedLatency:
+				component.Status = models.StatusDegraded
+			}
+			if err == nil {
+				ms := latency.Milliseconds()
+				component.LatencyMs = &ms
+			}
+			components[i] = component
+		}(i, check)
+	}
+	wg.Wait()
+
+	return components
+}
+
+// maintenanceWindow returns the maintenance in progress, or nil when maintenance mode is off
+// or its setting can't be read
+func (h *StatusHandler) maintenanceWindow(ctx context.Context) *models.PublicMaintenanceWindow {
+	setting, err := h.settings.GetSetting(ctx, models.SettingMaintenanceMode)
+	if err != nil || setting == nil || setting.Value != "true" {
+		return nil
+	}
+
+	window := &models.PublicMaintenanceWindow{
+		Message: "System is under maintenance. Please try again later.",
+		Since:   setting.UpdatedAt.UTC(),
+	}
+	if message, err := h.settings.GetSetting(ctx, models.SettingMaintenanceMessage); err == nil && message != nil && message.Value != "" {
+		window.Message = message.Value
+	}
+	return window
+}
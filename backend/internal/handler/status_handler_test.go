@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockStatusSettings serves system settings from a map
+type mockStatusSettings struct {
+	service.SystemRepositoryInterface
+	settings map[string]*models.SystemSetting
+}
+
+func (m *mockStatusSettings) GetSetting(_ context.Context, key string) (*models.SystemSetting, error) {
+	if setting, ok := m.settings[key]; ok {
+		return setting, nil
+	}
+	return nil, errors.New("setting not found")
+}
+
+func setupStatusHandler(checks []statusCheck, settings map[string]*models.SystemSetting) (*StatusHandler, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	h := &StatusHandler{
+		checks:    checks,
+		settings:  &mockStatusSettings{settings: settings},
+		version:   "v1.4.0",
+		startedAt: time.Now().Add(-time.Hour),
+	}
+	r := gin.New()
+	r.GET("/status", h.Status)
+	return h, r
+}
+
+func getStatus(t *testing.T, r *gin.Engine) models.PublicStatusResponse {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.PublicStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func okCheck(name string) statusCheck {
+	return statusCheck{name: name, check: func(context.Context) error { return nil }}
+}
+
+func TestStatusHandler_Status_ShouldReportOperational(t *testing.T) {
+	_, r := setupStatusHandler([]statusCheck{okCheck("database"), okCheck("redis")}, nil)
+
+	resp := getStatus(t, r)
+
+	assert.Equal(t, models.StatusOperational, resp.Status)
+	assert.Equal(t, "v1.4.0", resp.Version)
+	assert.InDelta(t, 3600, resp.Uptime, 5)
+	require.Len(t, resp.Components, 2)
+	assert.Equal(t, "database", resp.Components[0].Name)
+	assert.Equal(t, models.StatusOperational, resp.Components[0].Status)
+	assert.NotNil(t, resp.Components[0].LatencyMs)
+	assert.Nil(t, resp.Maintenance)
+}
+
+func TestStatusHandler_Status_ShouldHideErrorDetails_WhenComponentIsDown(t *testing.T) {
+	down := statusCheck{name: "redis", check: func(context.Context) error {
+		return errors.New("dial tcp 10.0.0.7:6379: connection refused")
+	}}
+	_, r := setupStatusHandler([]statusCheck{okCheck("database"), down}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.7")
+	var resp models.PublicStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.StatusOutage, resp.Status)
+	assert.Equal(t, models.StatusOutage, resp.Components[1].Status)
+	assert.Nil(t, resp.Components[1].LatencyMs)
+}
+
+func TestStatusHandler_Status_ShouldReportDegraded_WhenComponentIsSlow(t *testing.T) {
+	slow := statusCheck{name: "database", check: func(context.Context) error {
+		time.Sleep(statusDegradedLatency + 50*time.Millisecond)
+		return nil
+	}}
+	_, r := setupStatusHandler([]statusCheck{slow, okCheck("redis")}, nil)
+
+	resp := getStatus(t, r)
+
+	assert.Equal(t, models.StatusDegraded, resp.Status)
+	assert.Equal(t, models.StatusDegraded, resp.Components[0].Status)
+	assert.Equal(t, models.StatusOperational, resp.Components[1].Status)
+}
+
+func TestStatusHandler_Status_ShouldReportMaintenanceWindow(t *testing.T) {
+	since := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	_, r := setupStatusHandler([]statusCheck{okCheck("database")}, map[string]*models.SystemSetting{
+		models.SettingMaintenanceMode:    {Key: models.SettingMaintenanceMode, Value: "true", UpdatedAt: since},
+		models.SettingMaintenanceMessage: {Key: models.SettingMaintenanceMessage, Value: "Database upgrade"},
+	})
+
+	resp := getStatus(t, r)
+
+	assert.Equal(t, models.StatusMaintenance, resp.Status)
+	require.NotNil(t, resp.Maintenance)
+	assert.Equal(t, "Database upgrade", resp.Maintenance.Message)
+	assert.True(t, since.Equal(resp.Maintenance.Since))
+}
+
+func TestStatusHandler_Status_ShouldCacheResult(t *testing.T) {
+	calls := 0
+	counted := statusCheck{name: "database", check: func(context.Context) error {
+		calls++
+		return nil
+	}}
+	_, r := setupStatusHandler([]statusCheck{counted}, nil)
+
+	getStatus(t, r)
+	getStatus(t, r)
+
+	assert.Equal(t, 1, calls)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -15,6 +16,8 @@ type TokenHandler struct {
 	jwtService    *jwt.Service
 	apiKeyService service.APIKeyServicer
 	redis         service.RedisServicer
+	tokenVersions service.TokenVersionServicer
+	filter        *service.BlacklistFilter
 	logger        *logger.Logger
 }
 
@@ -32,6 +35,16 @@ func NewTokenHandler(
 	}
 }
 
+// SetTokenVersionService enables token version and global epoch checks
+func (h *TokenHandler) SetTokenVersionService(tokenVersions service.TokenVersionServicer) {
+	h.tokenVersions = tokenVersions
+}
+
+// SetBlacklistFilter skips blacklist lookups for tokens the filter knows are not blacklisted
+func (h *TokenHandler) SetBlacklistFilter(filter *service.BlacklistFilter) {
+	h.filter = filter
+}
+
 type ValidateTokenRequest struct {
 	AccessToken string `json:"access_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 }
@@ -44,6 +57,7 @@ type ValidateTokenResponse struct {
 	Roles     []string `json:"roles,omitempty" example:"user,admin"`
 	ExpiresAt int64    `json:"expires_at,omitempty" example:"1234567890"`
 	IsActive  bool     `json:"is_active,omitempty" example:"true"`
+	IsGuest   bool     `json:"is_guest,omitempty" example:"false"`
 }
 
 type ValidateTokenErrorResponse struct {
@@ -73,6 +87,7 @@ func (h *TokenHandler) ValidateToken(c *gin.Context) {
 	}
 
 	if req.AccessToken == "" {
+		metrics.RecordTokenValidation(metrics.TransportREST, "access", false)
 		c.JSON(http.StatusUnauthorized, ValidateTokenErrorResponse{
 			Valid:        false,
 			ErrorMessage: "access_token is required",
@@ -82,14 +97,28 @@ func (h *TokenHandler) ValidateToken(c *gin.Context) {
 
 	// Check if it's an API key (starts with "agw_")
 	if len(req.AccessToken) > 4 && req.AccessToken[:4] == "agw_" {
-		h.validateAPIKey(c, req.AccessToken)
+		metrics.RecordTokenValidation(metrics.TransportREST, "api_key", h.validateAPIKey(c, req.AccessToken))
 		return
 	}
 
-	h.validateJWT(c, req.AccessToken)
+	metrics.RecordTokenValidation(metrics.TransportREST, "access", h.validateJWT(c, req.AccessToken))
+}
+
+// JWKS returns the public keys for verifying first-party access tokens offline
+// @Summary First-party JWKS
+// @Description Returns the JSON Web Key Set used to sign first-party access and refresh tokens. The set is empty while tokens are HMAC-signed.
+// @Tags Token
+// @Produce json
+// @Success 200 {object} models.JWKSDocument
+// @Router /api/v1/token/jwks.json [get]
+func (h *TokenHandler) JWKS(c *gin.Context) {
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.GetJWKS())
 }
 
-func (h *TokenHandler) validateAPIKey(c *gin.Context, apiKey string) {
+// validateAPIKey writes the validation response for an API key and reports whether it is valid
+func (h *TokenHandler) validateAPIKey(c *gin.Context, apiKey string) bool {
 	_, user, err := h.apiKeyService.ValidateAPIKey(c.Request.Context(), apiKey)
 	if err != nil {
 		h.logger.Debug("API key validation failed", map[string]interface{}{
@@ -99,7 +128,7 @@ func (h *TokenHandler) validateAPIKey(c *gin.Context, apiKey string) {
 			Valid:        false,
 			ErrorMessage: err.Error(),
 		})
-		return
+		return false
 	}
 
 	roleNames := make([]string, len(user.Roles))
@@ -115,10 +144,13 @@ func (h *TokenHandler) validateAPIKey(c *gin.Context, apiKey string) {
 		Roles:     roleNames,
 		ExpiresAt: 0,
 		IsActive:  user.IsActive,
+		IsGuest:   user.IsGuest,
 	})
+	return user.IsActive
 }
 
-func (h *TokenHandler) validateJWT(c *gin.Context, token string) {
+// validateJWT writes the validation response for an access token and reports whether it is valid
+func (h *TokenHandler) validateJWT(c *gin.Context, token string) bool {
 	claims, err := h.jwtService.ValidateAccessToken(token)
 	if err != nil {
 		h.logger.Debug("Token validation failed", map[string]interface{}{
@@ -128,15 +160,18 @@ func (h *TokenHandler) validateJWT(c *gin.Context, token string) {
 			Valid:        false,
 			ErrorMessage: err.Error(),
 		})
-		return
+		return false
 	}
 
 	tokenHash := utils.HashToken(token)
-	blacklisted, err := h.redis.IsBlacklisted(c.Request.Context(), tokenHash)
-	if err != nil {
-		h.logger.Warn("Redis blacklist check failed", map[string]interface{}{
-			"error": err.Error(),
-		})
+	blacklisted := false
+	if h.filter.MayContain(tokenHash) {
+		blacklisted, err = h.redis.IsBlacklisted(c.Request.Context(), tokenHash)
+		if err != nil {
+			h.logger.Warn("Redis blacklist check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
 	}
 
 	if blacklisted {
@@ -144,7 +179,15 @@ func (h *TokenHandler) validateJWT(c *gin.Context, token string) {
 			Valid:        false,
 			ErrorMessage: "token is blacklisted",
 		})
-		return
+		return false
+	}
+
+	if h.tokenVersions != nil && h.tokenVersions.IsRevoked(c.Request.Context(), claims) {
+		c.JSON(http.StatusUnauthorized, ValidateTokenErrorResponse{
+			Valid:        false,
+			ErrorMessage: "token is revoked",
+		})
+		return false
 	}
 
 	c.JSON(http.StatusOK, ValidateTokenResponse{
@@ -155,5 +198,7 @@ func (h *TokenHandler) validateJWT(c *gin.Context, token string) {
 		Roles:     claims.Roles,
 		ExpiresAt: claims.ExpiresAt.Unix(),
 		IsActive:  claims.IsActive,
+		IsGuest:   claims.IsGuest,
 	})
+	return claims.IsActive
 }
@@ -295,3 +295,24 @@ func TestTokenHandler_ValidateToken_ShouldReturn400_WhenEmptyBody(t *testing.T)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+// ---------------------------------------------------------------------------
+// JWKS Tests
+// ---------------------------------------------------------------------------
+
+func TestTokenHandler_JWKS_ShouldReturnEmptyKeySet_WhenHMAC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, _, _, _ := setupTokenHandler()
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.GET("/api/v1/token/jwks.json", h.JWKS)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/token/jwks.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	var body map[string][]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Empty(t, body["keys"])
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// TokenVersionHandler handles forced token invalidation (admin only)
+type TokenVersionHandler struct {
+	tokenVersions  service.TokenVersionServicer
+	sessionService service.SessionServicer
+	auditService   service.AuditServicer
+	logger         *logger.Logger
+}
+
+// NewTokenVersionHandler creates a new token version handler
+func NewTokenVersionHandler(tokenVersions service.TokenVersionServicer, sessionService service.SessionServicer, auditService service.AuditServicer, logger *logger.Logger) *TokenVersionHandler {
+	return &TokenVersionHandler{
+		tokenVersions:  tokenVersions,
+		sessionService: sessionService,
+		auditService:   auditService,
+		logger:         logger,
+	}
+}
+
+// RevokeUserTokens invalidates all tokens issued to a user
+// @Summary Revoke all user tokens
+// @Description Bump the user's token version so every access and refresh token issued so far is rejected, and revoke their sessions (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} object{message=string,user_id=string,token_version=int}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/revoke-tokens [post]
+func (h *TokenVersionHandler) RevokeUserTokens(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	version, err := h.tokenVersions.Bump(c.Request.Context(), userID)
+	if err != nil {
+		if appErr, ok := err.(*models.AppError); ok {
+			c.JSON(appErr.Code, models.NewErrorResponse(appErr))
+			return
+		}
+		h.logger.Error("Failed to bump token version", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	// Tokens are already rejected by version; this only keeps session listings accurate
+	if err := h.sessionService.RevokeAllUserSessions(c.Request.Context(), userID, nil); err != nil {
+		h.logger.Warn("Failed to revoke sessions after token version bump", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionTokensRevoked,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"target_user_id": userID.String(),
+			"token_version":  version,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "All tokens have been revoked for user",
+		"user_id":       userID.String(),
+		"token_version": version,
+	})
+}
+
+// GetTokenEpoch returns the global token invalidation epoch
+// @Summary Get global token epoch
+// @Description Tokens issued at or before the epoch are rejected (admin only)
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.TokenEpochResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/token-epoch [get]
+func (h *TokenVersionHandler) GetTokenEpoch(c *gin.Context) {
+	epoch, err := h.tokenVersions.Epoch(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to get token epoch", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	var response models.TokenEpochResponse
+	if !epoch.IsZero() {
+		response.Epoch = &epoch
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// InvalidateAllTokens moves the global epoch to now
+// @Summary Invalidate all tokens
+// @Description Emergency switch: reject every access and refresh token issued so far, for all users, without rotating signing keys (admin only)
+// @Tags Admin - System
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.TokenEpochResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/system/token-epoch [post]
+func (h *TokenVersionHandler) InvalidateAllTokens(c *gin.Context) {
+	adminID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	epoch, err := h.tokenVersions.InvalidateAll(c.Request.Context(), &adminID)
+	if err != nil {
+		h.logger.Error("Failed to invalidate all tokens", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.ErrInternalServer))
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &adminID,
+		Action:    models.ActionTokensInvalidatedGlobally,
+		Status:    models.StatusSuccess,
+		IP:        c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		Details: map[string]interface{}{
+			"epoch": epoch.Unix(),
+		},
+	})
+
+	c.JSON(http.StatusOK, models.TokenEpochResponse{Epoch: &epoch})
+}
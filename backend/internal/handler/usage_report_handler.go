@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// UsageReportHandler handles organization usage and seat reports
+type UsageReportHandler struct {
+	usageReportService service.UsageReportServicer
+	logger             *logger.Logger
+}
+
+// NewUsageReportHandler creates a new usage report handler
+func NewUsageReportHandler(usageReportService service.UsageReportServicer, logger *logger.Logger) *UsageReportHandler {
+	return &UsageReportHandler{
+		usageReportService: usageReportService,
+		logger:             logger,
+	}
+}
+
+// ListUsageReports handles listing the usage reports of all organizations
+// @Summary List organization usage reports
+// @Description Usage reports of all top-level groups: active users in the last 30 days, seats used against licensed seats and MFA adoption. Members of child groups count towards their parent.
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.OrgUsageReportListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/usage-reports [get]
+func (h *UsageReportHandler) ListUsageReports(c *gin.Context) {
+	reports, err := h.usageReportService.ListReports(c.Request.Context())
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetUsageReport handles building the usage report of an organization
+// @Summary Get organization usage report
+// @Description Usage report of a group and its child groups: active users in the last 30 days, seats used against licensed seats and MFA adoption
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.OrgUsageReport
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/usage-report [get]
+func (h *UsageReportHandler) GetUsageReport(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	report, err := h.usageReportService.GetReport(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetOrgUsageReport handles an organization admin viewing the usage report of their organization
+// @Summary Get organization usage report
+// @Description Usage report of the organization and its child groups
+// @Tags Organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization (group) ID (UUID)"
+// @Success 200 {object} models.OrgUsageReport
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orgs/{id}/usage-report [get]
+func (h *UsageReportHandler) GetOrgUsageReport(c *gin.Context) {
+	h.GetUsageReport(c)
+}
+
+// GetUsageReportSettings handles retrieving the seat license and delivery settings of an organization
+// @Summary Get usage report settings
+// @Description Licensed seat count and scheduled report delivery settings of a group
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.OrgUsageReportSettings
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/usage-report/settings [get]
+func (h *UsageReportHandler) GetUsageReportSettings(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	settings, err := h.usageReportService.GetSettings(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateUsageReportSettings handles replacing the seat license and delivery settings of an organization
+// @Summary Update usage report settings
+// @Description Set the licensed seat count and the recipients and frequency (weekly or monthly) of scheduled usage report emails. An empty recipient list disables scheduled delivery.
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Param request body models.UpdateOrgUsageReportSettingsRequest true "Usage report settings"
+// @Success 200 {object} models.OrgUsageReportSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/usage-report/settings [put]
+func (h *UsageReportHandler) UpdateUsageReportSettings(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOrgUsageReportSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	settings, err := h.usageReportService.UpdateSettings(c.Request.Context(), groupID, &req)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SendUsageReport handles emailing the usage report of an organization immediately
+// @Summary Send usage report
+// @Description Email the current usage report of a group to its configured recipients
+// @Tags Admin - Groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Group ID (UUID)"
+// @Success 200 {object} models.SendOrgUsageReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/groups/{id}/usage-report/send [post]
+func (h *UsageReportHandler) SendUsageReport(c *gin.Context) {
+	groupID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	sent, err := h.usageReportService.SendReport(c.Request.Context(), groupID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SendOrgUsageReportResponse{Sent: sent})
+}
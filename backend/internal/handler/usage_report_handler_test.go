@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupUsageReportHandler() (*UsageReportHandler, *mockUsageReportServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockUsageReportServicer{}
+	return NewUsageReportHandler(svc, testLogger()), svc, gin.New()
+}
+
+func TestUsageReportHandler_GetUsageReport_ShouldReturn200_WhenSuccessful(t *testing.T) {
+	h, svc, r := setupUsageReportHandler()
+	groupID := uuid.New()
+	seats := 50
+
+	svc.GetReportFunc = func(gid uuid.UUID) (*models.OrgUsageReport, error) {
+		assert.Equal(t, groupID, gid)
+		return &models.OrgUsageReport{GroupID: gid, SeatsUsed: 12, LicensedSeats: &seats}, nil
+	}
+	r.GET("/groups/:id/usage-report", h.GetUsageReport)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/groups/"+groupID.String()+"/usage-report", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.OrgUsageReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 12, resp.SeatsUsed)
+	require.NotNil(t, resp.LicensedSeats)
+	assert.Equal(t, 50, *resp.LicensedSeats)
+}
+
+func TestUsageReportHandler_GetUsageReport_ShouldReturn400_WhenIDInvalid(t *testing.T) {
+	h, _, r := setupUsageReportHandler()
+	r.GET("/groups/:id/usage-report", h.GetUsageReport)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/groups/not-a-uuid/usage-report", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUsageReportHandler_UpdateUsageReportSettings_ShouldReturn400_WhenFrequencyInvalid(t *testing.T) {
+	h, svc, r := setupUsageReportHandler()
+	svc.UpdateSettingsFunc = func(uuid.UUID, *models.UpdateOrgUsageReportSettingsRequest) (*models.OrgUsageReportSettings, error) {
+		t.Fatal("service must not be called")
+		return nil, nil
+	}
+	r.PUT("/groups/:id/usage-report/settings", h.UpdateUsageReportSettings)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/groups/"+uuid.NewString()+"/usage-report/settings",
+		strings.NewReader(`{"frequency":"daily"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUsageReportHandler_UpdateUsageReportSettings_ShouldReturn400_WhenRecipientInvalid(t *testing.T) {
+	h, _, r := setupUsageReportHandler()
+	r.PUT("/groups/:id/usage-report/settings", h.UpdateUsageReportSettings)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/groups/"+uuid.NewString()+"/usage-report/settings",
+		strings.NewReader(`{"recipients":["not-an-email"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUsageReportHandler_SendUsageReport_ShouldReturnSentCount(t *testing.T) {
+	h, svc, r := setupUsageReportHandler()
+	svc.SendReportFunc = func(uuid.UUID) (int, error) { return 2, nil }
+	r.POST("/groups/:id/usage-report/send", h.SendUsageReport)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/groups/"+uuid.NewString()+"/usage-report/send", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.SendOrgUsageReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Sent)
+}
+
+func TestUsageReportHandler_SendUsageReport_ShouldPropagateServiceError(t *testing.T) {
+	h, svc, r := setupUsageReportHandler()
+	svc.SendReportFunc = func(uuid.UUID) (int, error) {
+		return 0, models.NewAppError(http.StatusBadRequest, "No report recipients configured for this organization")
+	}
+	r.POST("/groups/:id/usage-report/send", h.SendUsageReport)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/groups/"+uuid.NewString()+"/usage-report/send", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
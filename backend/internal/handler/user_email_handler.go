@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// UserEmailHandler handles the secondary email addresses of the current user
+type UserEmailHandler struct {
+	emailService service.UserEmailServicer
+	logger       *logger.Logger
+}
+
+// NewUserEmailHandler creates a new user email handler
+func NewUserEmailHandler(emailService service.UserEmailServicer, logger *logger.Logger) *UserEmailHandler {
+	return &UserEmailHandler{
+		emailService: emailService,
+		logger:       logger,
+	}
+}
+
+// List returns the email addresses of the current user
+// @Summary List email addresses
+// @Description List the primary and secondary email addresses of the current user and the address chosen for account recovery
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.UserEmailListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/emails [get]
+func (h *UserEmailHandler) List(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.emailService.List(c.Request.Context(), userID)
+	if err != nil {
+		h.respondWithError(c, "Failed to list email addresses", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Add adds a secondary email address
+// @Summary Add email address
+// @Description Add a secondary email address and send a verification code to it. Adding an address that is pending verification sends a new code.
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.AddUserEmailRequest true "Email address"
+// @Success 201 {object} models.UserEmail
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/emails [post]
+func (h *UserEmailHandler) Add(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddUserEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	email, err := h.emailService.Add(c.Request.Context(), userID, req.Email, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		h.respondWithError(c, "Failed to add email address", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, email)
+}
+
+// Verify verifies a secondary email address
+// @Summary Verify email address
+// @Description Verify a secondary email address with the code sent to it. Verified addresses can be used to sign in.
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyUserEmailRequest true "Email address and code"
+// @Success 200 {object} models.UserEmail
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/emails/verify [post]
+func (h *UserEmailHandler) Verify(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.VerifyUserEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	email, err := h.emailService.Verify(c.Request.Context(), userID, req.Email, req.Code, utils.GetClientIP(c), utils.GetUserAgent(c))
+	if err != nil {
+		h.respondWithError(c, "Failed to verify email address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, email)
+}
+
+// Remove removes a secondary email address
+// @Summary Remove email address
+// @Description Remove a secondary email address. If it was the recovery address, no address is chosen for recovery afterwards.
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Email address ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/emails/{id} [delete]
+func (h *UserEmailHandler) Remove(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.emailService.Remove(c.Request.Context(), userID, id, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		h.respondWithError(c, "Failed to remove email address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Email address removed"})
+}
+
+// SetPrimary makes a secondary email address the primary one
+// @Summary Set primary email address
+// @Description Make a verified secondary email address the primary one. The previous primary address is kept as a secondary address.
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Email address ID (UUID)"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/emails/{id}/primary [post]
+func (h *UserEmailHandler) SetPrimary(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.emailService.SetPrimary(c.Request.Context(), userID, id, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		h.respondWithError(c, "Failed to set primary email address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Primary email address changed"})
+}
+
+// SetRecoveryEmail chooses the address account recovery messages go to
+// @Summary Set recovery email address
+// @Description Choose which verified secondary email address receives account recovery codes and notifications
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.SetRecoveryEmailRequest true "Verified secondary email address"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery-email [put]
+func (h *UserEmailHandler) SetRecoveryEmail(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetRecoveryEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	if err := h.emailService.SetRecoveryEmail(c.Request.Context(), userID, req.Email, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		h.respondWithError(c, "Failed to set recovery email address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Recovery email address set"})
+}
+
+// ClearRecoveryEmail stops sending account recovery messages to a secondary address
+// @Summary Clear recovery email address
+// @Description Stop sending account recovery messages to a secondary email address
+// @Tags Email Addresses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.MessageResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/recovery-email [delete]
+func (h *UserEmailHandler) ClearRecoveryEmail(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.emailService.ClearRecoveryEmail(c.Request.Context(), userID, utils.GetClientIP(c), utils.GetUserAgent(c)); err != nil {
+		h.respondWithError(c, "Failed to clear recovery email address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Recovery email address cleared"})
+}
+
+func (h *UserEmailHandler) respondWithError(c *gin.Context, message string, err error) {
+	if _, ok := err.(*models.AppError); !ok {
+		h.logger.Error(message, map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.RespondWithError(c, err)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// UserTimelineHandler serves the activity timeline of a user (admin only)
+type UserTimelineHandler struct {
+	timelineService service.UserTimelineServicer
+	logger          *logger.Logger
+}
+
+// NewUserTimelineHandler creates a new user timeline handler
+func NewUserTimelineHandler(timelineService service.UserTimelineServicer, logger *logger.Logger) *UserTimelineHandler {
+	return &UserTimelineHandler{
+		timelineService: timelineService,
+		logger:          logger,
+	}
+}
+
+// GetUserTimeline returns the activity timeline of a user
+// @Summary Get user activity timeline
+// @Description Get the user's audit events, sessions, role changes, OAuth consents and API key activity as one feed, newest first (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param source query string false "Comma-separated sources to include: audit, session, role, consent, api_key (default all)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Success 200 {object} models.UserTimelineResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/users/{id}/timeline [get]
+func (h *UserTimelineHandler) GetUserTimeline(c *gin.Context) {
+	userID, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	page, pageSize := utils.ParsePagination(c)
+
+	var sources []string
+	for _, source := range strings.Split(c.Query("source"), ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+
+	response, err := h.timelineService.ListUserTimeline(c.Request.Context(), userID, sources, page, pageSize)
+	if err != nil {
+		h.logger.Error("Failed to get user timeline", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
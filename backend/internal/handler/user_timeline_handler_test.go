@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockUserTimelineService implements service.UserTimelineServicer
+type mockUserTimelineService struct {
+	userID  uuid.UUID
+	sources []string
+}
+
+func (m *mockUserTimelineService) ListUserTimeline(_ context.Context, userID uuid.UUID, sources []string, page, pageSize int) (*models.UserTimelineResponse, error) {
+	m.userID = userID
+	m.sources = sources
+	return &models.UserTimelineResponse{
+		Events:   []*models.UserTimelineEvent{{Source: models.TimelineSourceAPIKey, Event: "api_key_used"}},
+		Total:    1,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func setupUserTimelineHandler() (*mockUserTimelineService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockUserTimelineService{}
+	r := gin.New()
+	h := NewUserTimelineHandler(svc, testLogger())
+	r.GET("/admin/users/:id/timeline", h.GetUserTimeline)
+	return svc, r
+}
+
+func TestUserTimelineHandler_GetUserTimeline_ShouldParseSourcesAndPagination(t *testing.T) {
+	svc, r := setupUserTimelineHandler()
+	userID := uuid.New()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/"+userID.String()+"/timeline?source=session,+api_key,&page=2&page_size=10", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, userID, svc.userID)
+	assert.Equal(t, []string{"session", "api_key"}, svc.sources)
+
+	var resp models.UserTimelineResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 10, resp.PageSize)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, "api_key_used", resp.Events[0].Event)
+}
+
+func TestUserTimelineHandler_GetUserTimeline_ShouldRejectInvalidUserID(t *testing.T) {
+	svc, r := setupUserTimelineHandler()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/not-a-uuid/timeline", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, uuid.Nil, svc.userID)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// VersionHandler serves the build information of the server
+type VersionHandler struct {
+	info models.VersionResponse
+}
+
+// NewVersionHandler creates a new version handler. An empty commit or build date is taken
+// from the VCS stamp go build embeds in the binary, when there is one.
+func NewVersionHandler(version, commit, buildDate string) *VersionHandler {
+	if version == "" {
+		version = "dev"
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if commit == "" {
+					commit = setting.Value
+				}
+			case "vcs.time":
+				if buildDate == "" {
+					buildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	return &VersionHandler{
+		info: models.VersionResponse{
+			Version:     version,
+			Commit:      commit,
+			BuildDate:   buildDate,
+			GoVersion:   runtime.Version(),
+			APIVersions: models.SupportedAPIVersions,
+		},
+	}
+}
+
+// Version returns the build information of the server
+// @Summary Server version
+// @Description Version, git commit and build date of the server and the API versions it serves. SDKs read it to check that the server has the features they need. Served during maintenance.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.VersionResponse
+// @Router /version [get]
+func (h *VersionHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, h.info)
+}
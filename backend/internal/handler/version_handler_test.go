@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandler_Version_ShouldReturnBuildInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewVersionHandler("v1.4.0", "9f1c2d3", "2024-01-15T10:30:00Z")
+	r := gin.New()
+	r.GET("/version", h.Version)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.VersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "v1.4.0", resp.Version)
+	assert.Equal(t, "9f1c2d3", resp.Commit)
+	assert.Equal(t, "2024-01-15T10:30:00Z", resp.BuildDate)
+	assert.Equal(t, runtime.Version(), resp.GoVersion)
+	assert.Contains(t, resp.APIVersions, models.APIVersionV1)
+}
+
+func TestVersionHandler_Version_ShouldDefaultToDev(t *testing.T) {
+	h := NewVersionHandler("", "", "")
+
+	assert.Equal(t, "dev", h.info.Version)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// WebAuthnHandler handles security keys registered as a second factor
+type WebAuthnHandler struct {
+	webauthnService service.WebAuthnServicer
+	loginService    service.WebAuthnLoginServicer
+	auditService    service.AuditServicer
+	logger          *logger.Logger
+}
+
+// NewWebAuthnHandler creates a new WebAuthn 2FA handler
+func NewWebAuthnHandler(
+	webauthnService service.WebAuthnServicer,
+	loginService service.WebAuthnLoginServicer,
+	auditService service.AuditServicer,
+	logger *logger.Logger,
+) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webauthnService: webauthnService,
+		loginService:    loginService,
+		auditService:    auditService,
+		logger:          logger,
+	}
+}
+
+// BeginRegistration starts registering a security key
+// @Summary Begin security key registration
+// @Description Returns the PublicKeyCredentialCreationOptions to pass to navigator.credentials.create()
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.WebAuthnRegisterBeginRequest true "Password for verification"
+// @Success 200 {object} object
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/register/begin [post]
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebAuthnRegisterBeginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	options, err := h.webauthnService.BeginWebAuthnRegistration(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishRegistration completes registering a security key
+// @Summary Finish security key registration
+// @Description Verifies the authenticator response and stores the key. Backup codes are returned when the key is the first second factor of the account.
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.WebAuthnRegisterFinishRequest true "Key name and authenticator response"
+// @Success 201 {object} models.WebAuthnRegisterFinishResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/register/finish [post]
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebAuthnRegisterFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	result, err := h.webauthnService.FinishWebAuthnRegistration(c.Request.Context(), userID, req.Name, req.Credential)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionWebAuthnRegister,
+		Status:    models.StatusSuccess,
+		IP:        utils.GetClientIP(c),
+		UserAgent: utils.GetUserAgent(c),
+		Details: map[string]interface{}{
+			"credential_id": result.Credential.ID.String(),
+			"name":          result.Credential.Name,
+		},
+	})
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// ListCredentials lists the user's security keys
+// @Summary List security keys
+// @Description Security keys registered as a second factor for the authenticated user
+// @Tags 2FA
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.WebAuthnCredential
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/credentials [get]
+func (h *WebAuthnHandler) ListCredentials(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	credentials, err := h.webauthnService.ListWebAuthnCredentials(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// DeleteCredential removes a security key
+// @Summary Remove security key
+// @Description Removes a security key. Removing the last second factor also removes the backup codes.
+// @Tags 2FA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Security key ID"
+// @Param request body models.WebAuthnCredentialDeleteRequest true "Password for verification"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/credentials/{id} [delete]
+func (h *WebAuthnHandler) DeleteCredential(c *gin.Context) {
+	userID, ok := utils.MustGetUserID(c)
+	if !ok {
+		return
+	}
+
+	credentialID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid security key ID"),
+		))
+		return
+	}
+
+	var req models.WebAuthnCredentialDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	if err := h.webauthnService.DeleteWebAuthnCredential(c.Request.Context(), userID, credentialID, req.Password); err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(service.AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionWebAuthnRemove,
+		Status:    models.StatusSuccess,
+		IP:        utils.GetClientIP(c),
+		UserAgent: utils.GetUserAgent(c),
+		Details: map[string]interface{}{
+			"credential_id": credentialID.String(),
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Security key removed",
+	})
+}
+
+// BeginLogin starts the security key step of a sign-in
+// @Summary Begin security key sign-in
+// @Description Returns the PublicKeyCredentialRequestOptions to pass to navigator.credentials.get() for a sign-in that requires 2FA
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.WebAuthnLoginBeginRequest true "2FA token"
+// @Success 200 {object} object
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/login/begin [post]
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	var req models.WebAuthnLoginBeginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	options, err := h.loginService.BeginWebAuthn2FALogin(c.Request.Context(), req.TwoFactorToken)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishLogin completes a sign-in with a security key
+// @Summary Finish security key sign-in
+// @Description Verifies the authenticator assertion and completes a sign-in that requires 2FA
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.WebAuthnLoginFinishRequest true "2FA token and authenticator assertion"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Invalid code, or models.PasswordChangeRequiredResponse when the password must be changed"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/auth/2fa/webauthn/login/finish [post]
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	var req models.WebAuthnLoginFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+			models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+		))
+		return
+	}
+
+	ip := utils.GetClientIP(c)
+	userAgent := utils.GetUserAgent(c)
+	deviceInfo := utils.GetDeviceInfoFromContext(c)
+
+	authResp, err := h.loginService.VerifyWebAuthn2FALogin(c.Request.Context(), req.TwoFactorToken, req.Credential, ip, userAgent, deviceInfo)
+	if err != nil {
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authResp)
+}
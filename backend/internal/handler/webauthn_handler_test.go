@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWebAuthnService implements service.WebAuthnServicer and service.WebAuthnLoginServicer
+type mockWebAuthnService struct {
+	name       string
+	response   string
+	deletedID  uuid.UUID
+	deleteErr  error
+	loginToken string
+}
+
+func (m *mockWebAuthnService) BeginWebAuthnRegistration(_ context.Context, _ uuid.UUID, _ string) (*protocol.CredentialCreation, error) {
+	return &protocol.CredentialCreation{}, nil
+}
+
+func (m *mockWebAuthnService) FinishWebAuthnRegistration(_ context.Context, userID uuid.UUID, name string, response []byte) (*models.WebAuthnRegisterFinishResponse, error) {
+	m.name = name
+	m.response = string(response)
+	return &models.WebAuthnRegisterFinishResponse{
+		Credential:  &models.WebAuthnCredential{ID: uuid.New(), UserID: userID, Name: name},
+		BackupCodes: []string{"a1B2c3D4"},
+	}, nil
+}
+
+func (m *mockWebAuthnService) ListWebAuthnCredentials(_ context.Context, _ uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	return []*models.WebAuthnCredential{}, nil
+}
+
+func (m *mockWebAuthnService) DeleteWebAuthnCredential(_ context.Context, _, credentialID uuid.UUID, _ string) error {
+	m.deletedID = credentialID
+	return m.deleteErr
+}
+
+func (m *mockWebAuthnService) BeginWebAuthn2FALogin(_ context.Context, twoFactorToken string) (*protocol.CredentialAssertion, error) {
+	m.loginToken = twoFactorToken
+	return &protocol.CredentialAssertion{}, nil
+}
+
+func (m *mockWebAuthnService) VerifyWebAuthn2FALogin(_ context.Context, twoFactorToken string, response []byte, _, _ string, _ models.DeviceInfo) (*models.AuthResponse, error) {
+	m.loginToken = twoFactorToken
+	m.response = string(response)
+	return &models.AuthResponse{AccessToken: "access-token"}, nil
+}
+
+func setupWebAuthnHandler(userID uuid.UUID) (*mockWebAuthnService, *mockAuditServicer, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockWebAuthnService{}
+	audit := &mockAuditServicer{}
+	h := NewWebAuthnHandler(svc, svc, audit, testLogger())
+
+	r := gin.New()
+	r.POST("/auth/2fa/webauthn/login/finish", h.FinishLogin)
+	protected := r.Group("/auth/2fa/webauthn", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, userID)
+		c.Next()
+	})
+	protected.POST("/register/finish", h.FinishRegistration)
+	protected.DELETE("/credentials/:id", h.DeleteCredential)
+	return svc, audit, r
+}
+
+func TestWebAuthnHandler_FinishRegistration_ShouldPassCredentialAndAudit(t *testing.T) {
+	svc, audit, r := setupWebAuthnHandler(uuid.New())
+
+	body := `{"name":"YubiKey 5","credential":{"id":"abc","type":"public-key"}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/2fa/webauthn/register/finish", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "YubiKey 5", svc.name)
+	assert.JSONEq(t, `{"id":"abc","type":"public-key"}`, svc.response)
+
+	var resp models.WebAuthnRegisterFinishResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"a1B2c3D4"}, resp.BackupCodes)
+
+	require.Len(t, audit.Logged, 1)
+	assert.Equal(t, models.ActionWebAuthnRegister, audit.Logged[0].Action)
+}
+
+func TestWebAuthnHandler_DeleteCredential(t *testing.T) {
+	t.Run("Removes the key and audits", func(t *testing.T) {
+		svc, audit, r := setupWebAuthnHandler(uuid.New())
+		keyID := uuid.New()
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/2fa/webauthn/credentials/"+keyID.String(), strings.NewReader(`{"password":"secret"}`)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, keyID, svc.deletedID)
+		require.Len(t, audit.Logged, 1)
+		assert.Equal(t, models.ActionWebAuthnRemove, audit.Logged[0].Action)
+	})
+
+	t.Run("Rejects an invalid ID", func(t *testing.T) {
+		svc, audit, r := setupWebAuthnHandler(uuid.New())
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/2fa/webauthn/credentials/not-a-uuid", strings.NewReader(`{"password":"secret"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, uuid.Nil, svc.deletedID)
+		assert.Empty(t, audit.Logged)
+	})
+
+	t.Run("Does not audit a failed removal", func(t *testing.T) {
+		svc, audit, r := setupWebAuthnHandler(uuid.New())
+		svc.deleteErr = models.NewAppError(http.StatusNotFound, "Security key not found")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/2fa/webauthn/credentials/"+uuid.NewString(), strings.NewReader(`{"password":"secret"}`)))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, audit.Logged)
+	})
+}
+
+func TestWebAuthnHandler_FinishLogin_ShouldReturnTokens(t *testing.T) {
+	svc, _, r := setupWebAuthnHandler(uuid.New())
+
+	body := `{"two_factor_token":"2fa-token","credential":{"id":"abc"}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/2fa/webauthn/login/finish", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2fa-token", svc.loginToken)
+	assert.JSONEq(t, `{"id":"abc"}`, svc.response)
+
+	var resp models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "access-token", resp.AccessToken)
+}
@@ -286,6 +286,44 @@ func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// RedeliverWebhookDelivery godoc
+// @Summary Redeliver a webhook delivery
+// @Description Send the payload of a past delivery again as a new delivery, retried like any other (admin only)
+// @Tags Admin - Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Param deliveryId path string true "Delivery ID (UUID)"
+// @Security BearerAuth
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	id, ok := utils.ParseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := utils.ParseUUIDParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.RedeliverWebhookDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		if _, ok := err.(*models.AppError); !ok {
+			h.logger.Error("Failed to redeliver webhook delivery", map[string]interface{}{"error": err.Error()})
+		}
+		utils.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
 // GetAvailableEvents godoc
 // @Summary Get available webhook events
 // @Description Get a list of all available webhook event types (admin only)
@@ -669,6 +669,68 @@ func TestWebhookHandler_ListWebhookDeliveries_ShouldReturn400_WhenInvalidUUID(t
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// ===========================================================================
+// RedeliverWebhookDelivery
+// ===========================================================================
+
+func TestWebhookHandler_RedeliverWebhookDelivery_ShouldReturn202_WhenSuccessful(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupWebhookTestFixture()
+
+	webhookID, deliveryID := uuid.New(), uuid.New()
+	fix.webhookSvc.RedeliverWebhookDeliveryFunc = func(id, delivery uuid.UUID) (*models.WebhookDelivery, error) {
+		assert.Equal(t, webhookID, id)
+		assert.Equal(t, deliveryID, delivery)
+		return &models.WebhookDelivery{ID: uuid.New(), WebhookID: id, EventType: "user.created", Status: "pending"}, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", fix.handler.RedeliverWebhookDelivery)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+webhookID.String()+"/deliveries/"+deliveryID.String()+"/redeliver", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var resp models.WebhookDelivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "pending", resp.Status)
+	assert.NotEqual(t, deliveryID, resp.ID)
+}
+
+func TestWebhookHandler_RedeliverWebhookDelivery_ShouldReturn404_WhenNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupWebhookTestFixture()
+
+	fix.webhookSvc.RedeliverWebhookDeliveryFunc = func(id, delivery uuid.UUID) (*models.WebhookDelivery, error) {
+		return nil, models.ErrNotFound
+	}
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", fix.handler.RedeliverWebhookDelivery)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+uuid.New().String()+"/deliveries/"+uuid.New().String()+"/redeliver", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_RedeliverWebhookDelivery_ShouldReturn400_WhenInvalidDeliveryID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fix := setupWebhookTestFixture()
+
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", fix.handler.RedeliverWebhookDelivery)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+uuid.New().String()+"/deliveries/bad-id/redeliver", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // ===========================================================================
 // GetAvailableEvents
 // ===========================================================================
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// auditExportScheduleInterval is how often due audit export windows are looked for; the
+// shortest window is a few times longer
+const auditExportScheduleInterval = 10 * time.Second
+
+// AuditExportJob periodically queues the exports of audit log windows that are due
+type AuditExportJob struct {
+	exports *service.AuditExportService
+	logger  *logger.Logger
+}
+
+// NewAuditExportJob creates a new audit export job
+func NewAuditExportJob(exports *service.AuditExportService, logger *logger.Logger) *AuditExportJob {
+	return &AuditExportJob{
+		exports: exports,
+		logger:  logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *AuditExportJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(auditExportScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("audit export job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *AuditExportJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if queued := j.exports.ScheduleDue(runCtx); queued > 0 {
+		j.logger.Debug("Queued audit exports", map[string]interface{}{
+			"count": queued,
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// BlacklistFilterJob keeps the token blacklist bloom filter current: it follows hashes
+// blacklisted on other instances and periodically rebuilds the filter from the database,
+// which also drops expired entries
+type BlacklistFilterJob struct {
+	blacklist *service.BlacklistService
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+// NewBlacklistFilterJob creates a new blacklist filter job
+func NewBlacklistFilterJob(blacklist *service.BlacklistService, interval time.Duration, logger *logger.Logger) *BlacklistFilterJob {
+	return &BlacklistFilterJob{
+		blacklist: blacklist,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Start runs the job until the context is cancelled. The filter is first built once the
+// event subscription is established, so no blacklisted hash falls between the two.
+func (j *BlacklistFilterJob) Start(ctx context.Context) {
+	resync := make(chan struct{}, 1)
+	go j.blacklist.WatchFilter(ctx, func() {
+		select {
+		case resync <- struct{}{}:
+		default:
+		}
+	})
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Blacklist filter job stopped")
+			return
+		case <-resync:
+			j.run(ctx)
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *BlacklistFilterJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	if err := j.blacklist.RebuildFilter(runCtx); err != nil {
+		j.logger.Error("Blacklist filter rebuild failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	j.logger.Debug("Blacklist filter rebuilt", map[string]interface{}{
+		"ready": j.blacklist.Filter().Ready(),
+	})
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// credentialsCleanupInterval is how often expired Postgres roles are dropped
+const credentialsCleanupInterval = 1 * time.Minute
+
+// CredentialsCleanupJob periodically drops the Postgres roles of expired broker credentials.
+// Postgres refuses new logins after VALID UNTIL by itself; dropping the role also ends the
+// sessions opened before then.
+type CredentialsCleanupJob struct {
+	broker *service.CredentialsBrokerService
+	logger *logger.Logger
+}
+
+// NewCredentialsCleanupJob creates a new credentials cleanup job
+func NewCredentialsCleanupJob(broker *service.CredentialsBrokerService, logger *logger.Logger) *CredentialsCleanupJob {
+	return &CredentialsCleanupJob{
+		broker: broker,
+		logger: logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *CredentialsCleanupJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(credentialsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Credentials cleanup job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *CredentialsCleanupJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	dropped, err := j.broker.DropExpired(runCtx)
+	if err != nil {
+		j.logger.Error("Credentials cleanup failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if dropped > 0 {
+		j.logger.Info("Dropped expired credentials roles", map[string]interface{}{
+			"count": dropped,
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// dataExportCleanupInterval is how often expired data exports are deleted
+const dataExportCleanupInterval = 1 * time.Hour
+
+// DataExportCleanupJob periodically deletes user data exports past their expiry
+type DataExportCleanupJob struct {
+	dataExport *service.DataExportService
+	logger     *logger.Logger
+}
+
+// NewDataExportCleanupJob creates a new data export cleanup job
+func NewDataExportCleanupJob(dataExport *service.DataExportService, logger *logger.Logger) *DataExportCleanupJob {
+	return &DataExportCleanupJob{
+		dataExport: dataExport,
+		logger:     logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *DataExportCleanupJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(dataExportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Data export cleanup job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *DataExportCleanupJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	deleted, err := j.dataExport.DeleteExpired(runCtx)
+	if err != nil {
+		j.logger.Error("Failed to delete expired data exports", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if deleted > 0 {
+		j.logger.Info("Deleted expired data exports", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+}
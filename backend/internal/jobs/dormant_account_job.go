@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// dormantAccountInterval is how often the dormant account policy is applied
+const dormantAccountInterval = 6 * time.Hour
+
+// DormantAccountJob periodically notifies dormant accounts and enforces the dormant account policy.
+// The policy itself decides whether anything happens, so the job always runs.
+type DormantAccountJob struct {
+	dormantAccounts *service.DormantAccountService
+	logger          *logger.Logger
+}
+
+// NewDormantAccountJob creates a new dormant account job
+func NewDormantAccountJob(dormantAccounts *service.DormantAccountService, logger *logger.Logger) *DormantAccountJob {
+	return &DormantAccountJob{
+		dormantAccounts: dormantAccounts,
+		logger:          logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *DormantAccountJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(dormantAccountInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Dormant account job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *DormantAccountJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	result, err := j.dormantAccounts.Run(runCtx)
+	if err != nil {
+		j.logger.Error("Dormant account policy run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if result.Notified > 0 || result.Enforced > 0 || result.Failed > 0 {
+		j.logger.Info("Applied dormant account policy", map[string]interface{}{
+			"notified": result.Notified,
+			"enforced": result.Enforced,
+			"failed":   result.Failed,
+		})
+	}
+}
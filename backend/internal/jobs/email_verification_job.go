@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// emailVerificationReminderInterval is how often due email verification reminders are checked
+const emailVerificationReminderInterval = 1 * time.Hour
+
+// EmailVerificationReminderJob periodically reminds users with an unverified email address to verify it
+type EmailVerificationReminderJob struct {
+	emailVerification *service.EmailVerificationService
+	logger            *logger.Logger
+}
+
+// NewEmailVerificationReminderJob creates a new email verification reminder job
+func NewEmailVerificationReminderJob(emailVerification *service.EmailVerificationService, logger *logger.Logger) *EmailVerificationReminderJob {
+	return &EmailVerificationReminderJob{
+		emailVerification: emailVerification,
+		logger:            logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *EmailVerificationReminderJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(emailVerificationReminderInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Email verification reminder job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *EmailVerificationReminderJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	sent, err := j.emailVerification.SendReminders(runCtx)
+	if err != nil {
+		j.logger.Error("Email verification reminder run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if sent > 0 {
+		j.logger.Info("Sent email verification reminders", map[string]interface{}{
+			"count": sent,
+		})
+	}
+}
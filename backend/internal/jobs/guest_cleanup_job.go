@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// guestCleanupInterval is how often expired guest accounts are deleted
+const guestCleanupInterval = 1 * time.Hour
+
+// GuestCleanupJob periodically deletes guest accounts that were not upgraded within the retention period
+type GuestCleanupJob struct {
+	guests *service.GuestService
+	logger *logger.Logger
+}
+
+// NewGuestCleanupJob creates a new guest cleanup job
+func NewGuestCleanupJob(guests *service.GuestService, logger *logger.Logger) *GuestCleanupJob {
+	return &GuestCleanupJob{
+		guests: guests,
+		logger: logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *GuestCleanupJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(guestCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Guest cleanup job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *GuestCleanupJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	deleted, err := j.guests.DeleteExpired(runCtx)
+	if err != nil {
+		j.logger.Error("Guest cleanup failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if deleted > 0 {
+		j.logger.Info("Deleted expired guest accounts", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// passwordExpiryWarningInterval is how often upcoming password expirations are checked
+const passwordExpiryWarningInterval = 1 * time.Hour
+
+// PasswordExpiryWarningJob periodically emails users whose password is about to expire
+type PasswordExpiryWarningJob struct {
+	passwordExpiry *service.PasswordExpiryService
+	logger         *logger.Logger
+}
+
+// NewPasswordExpiryWarningJob creates a new password expiry warning job
+func NewPasswordExpiryWarningJob(passwordExpiry *service.PasswordExpiryService, logger *logger.Logger) *PasswordExpiryWarningJob {
+	return &PasswordExpiryWarningJob{
+		passwordExpiry: passwordExpiry,
+		logger:         logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *PasswordExpiryWarningJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(passwordExpiryWarningInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Password expiry warning job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *PasswordExpiryWarningJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	sent, err := j.passwordExpiry.SendExpiryWarnings(runCtx)
+	if err != nil {
+		j.logger.Error("Password expiry warning run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if sent > 0 {
+		j.logger.Info("Sent password expiry warnings", map[string]interface{}{
+			"count": sent,
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// retentionInterval is how often the data retention policies are applied
+const retentionInterval = 1 * time.Hour
+
+// RetentionJob periodically deletes audit logs and sessions past their retention period and
+// erases accounts that have been inactive for too long
+type RetentionJob struct {
+	retention *service.RetentionService
+	logger    *logger.Logger
+}
+
+// NewRetentionJob creates a new retention job
+func NewRetentionJob(retention *service.RetentionService, logger *logger.Logger) *RetentionJob {
+	return &RetentionJob{
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *RetentionJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Retention job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *RetentionJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+
+	result, err := j.retention.Run(runCtx)
+	if err != nil {
+		j.logger.Error("Retention run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	if result.AuditLogsDeleted > 0 || result.SessionsDeleted > 0 || result.AccountsErased > 0 || result.Failed > 0 {
+		j.logger.Info("Applied data retention policies", map[string]interface{}{
+			"audit_logs_deleted": result.AuditLogsDeleted,
+			"sessions_deleted":   result.SessionsDeleted,
+			"accounts_erased":    result.AccountsErased,
+			"failed":             result.Failed,
+		})
+	}
+}
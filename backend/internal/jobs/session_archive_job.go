@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SessionArchiveJob periodically copies sessions changed in Redis to PostgreSQL
+type SessionArchiveJob struct {
+	sessions  *service.RedisSessionStore
+	interval  time.Duration
+	batchSize int
+	logger    *logger.Logger
+}
+
+// NewSessionArchiveJob creates a new session archive job
+func NewSessionArchiveJob(sessions *service.RedisSessionStore, interval time.Duration, batchSize int, logger *logger.Logger) *SessionArchiveJob {
+	return &SessionArchiveJob{
+		sessions:  sessions,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Start runs the job until the context is cancelled. The queue of changed sessions lives in
+// Redis, so sessions changed after the last run are archived by the next instance to run.
+func (j *SessionArchiveJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Session archive job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *SessionArchiveJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	archived, err := j.sessions.Archive(runCtx, j.batchSize)
+	if err != nil {
+		j.logger.Error("Session archive failed", map[string]interface{}{
+			"archived": archived,
+			"error":    err.Error(),
+		})
+		return
+	}
+	if archived > 0 {
+		j.logger.Debug("Archived sessions", map[string]interface{}{
+			"count": archived,
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SigningKeyRotationJob periodically rotates the OIDC signing keys when due and reloads the
+// keys other replicas generated
+type SigningKeyRotationJob struct {
+	rotation *service.SigningKeyRotationService
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+// NewSigningKeyRotationJob creates a new signing key rotation job
+func NewSigningKeyRotationJob(rotation *service.SigningKeyRotationService, interval time.Duration, logger *logger.Logger) *SigningKeyRotationJob {
+	return &SigningKeyRotationJob{
+		rotation: rotation,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *SigningKeyRotationJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Signing key rotation job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *SigningKeyRotationJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	// The loaded keys stay in use; the rotation is retried at the next tick
+	if err := j.rotation.Sync(runCtx); err != nil {
+		j.logger.Error("Signing key rotation failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
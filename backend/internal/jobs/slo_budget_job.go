@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// sloBudgetCheckInterval is how often SLO error budgets are checked, matching the
+// resolution of the burn rate windows
+const sloBudgetCheckInterval = 1 * time.Minute
+
+// SLOBudgetJob periodically updates the SLO metrics and alerts on fast error budget burn
+type SLOBudgetJob struct {
+	slos   *service.SLOService
+	logger *logger.Logger
+}
+
+// NewSLOBudgetJob creates a new SLO budget job
+func NewSLOBudgetJob(slos *service.SLOService, logger *logger.Logger) *SLOBudgetJob {
+	return &SLOBudgetJob{
+		slos:   slos,
+		logger: logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *SLOBudgetJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(sloBudgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("SLO budget job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *SLOBudgetJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if raised := j.slos.CheckBudgets(runCtx); raised > 0 {
+		j.logger.Info("Raised SLO budget burn alerts", map[string]interface{}{
+			"count": raised,
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// TorExitListJob keeps the Tor exit list the risk engine checks sign-ins against current
+type TorExitListJob struct {
+	list     *service.TorExitList
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+// NewTorExitListJob creates a new Tor exit list job
+func NewTorExitListJob(list *service.TorExitList, interval time.Duration, logger *logger.Logger) *TorExitListJob {
+	return &TorExitListJob{
+		list:     list,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Start loads the list, then reloads it every interval until the context is cancelled.
+// A failed reload keeps the previous list.
+func (j *TorExitListJob) Start(ctx context.Context) {
+	j.run(ctx)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Tor exit list job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *TorExitListJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	if err := j.list.Refresh(runCtx); err != nil {
+		j.logger.Error("Tor exit list refresh failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	j.logger.Debug("Tor exit list refreshed", map[string]interface{}{
+		"addresses": j.list.Len(),
+	})
+}
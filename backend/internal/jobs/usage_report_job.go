@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// usageReportInterval is how often scheduled organization usage reports are checked
+const usageReportInterval = 1 * time.Hour
+
+// UsageReportJob periodically emails organization usage reports whose delivery is due
+type UsageReportJob struct {
+	usageReports *service.UsageReportService
+	logger       *logger.Logger
+}
+
+// NewUsageReportJob creates a new usage report job
+func NewUsageReportJob(usageReports *service.UsageReportService, logger *logger.Logger) *UsageReportJob {
+	return &UsageReportJob{
+		usageReports: usageReports,
+		logger:       logger,
+	}
+}
+
+// Start runs the job until the context is cancelled
+func (j *UsageReportJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(usageReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Usage report job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *UsageReportJob) run(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	sent, err := j.usageReports.SendDueReports(runCtx)
+	if err != nil {
+		j.logger.Error("Usage report run failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if sent > 0 {
+		j.logger.Info("Sent organization usage reports", map[string]interface{}{
+			"count": sent,
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/uptrace/bun"
+)
+
+// QueryHook returns a bun query hook that records the count and latency of database queries
+func QueryHook() bun.QueryHook {
+	return queryHook{}
+}
+
+type queryHook struct{}
+
+// BeforeQuery implements bun.QueryHook
+func (queryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook
+func (queryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	err := event.Err
+	// Finding nothing is an answer, not a failure
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	RecordDBQuery(strings.ToLower(event.Operation()), time.Since(event.StartTime), err)
+}
+
+// RedisHook returns a go-redis hook that records the count and latency of Redis commands
+func RedisHook() redis.Hook {
+	return redisHook{}
+}
+
+type redisHook struct{}
+
+// DialHook implements redis.Hook
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		RecordRedisOperation(cmd.Name(), time.Since(start), redisError(err))
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		RecordRedisOperation("pipeline", time.Since(start), redisError(err))
+		return err
+	}
+}
+
+// redisError drops redis.Nil, which only reports a missing key
+func redisError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestQueryHook(t *testing.T) {
+	hook := QueryHook()
+	before := testutil.ToFloat64(dbQueriesTotal.WithLabelValues("select", "success"))
+	beforeErrors := testutil.ToFloat64(dbQueriesTotal.WithLabelValues("select", "error"))
+
+	// Finding no rows is not an error
+	hook.AfterQuery(context.Background(), &bun.QueryEvent{Query: "SELECT 1", StartTime: time.Now(), Err: sql.ErrNoRows})
+	hook.AfterQuery(context.Background(), &bun.QueryEvent{Query: "SELECT 1", StartTime: time.Now(), Err: errors.New("connection reset")})
+
+	assert.Equal(t, before+1, testutil.ToFloat64(dbQueriesTotal.WithLabelValues("select", "success")))
+	assert.Equal(t, beforeErrors+1, testutil.ToFloat64(dbQueriesTotal.WithLabelValues("select", "error")))
+}
+
+func TestRedisHook(t *testing.T) {
+	hook := RedisHook()
+	before := testutil.ToFloat64(redisOperationsTotal.WithLabelValues("get", "success"))
+	beforeErrors := testutil.ToFloat64(redisOperationsTotal.WithLabelValues("get", "error"))
+
+	// A missing key is not an error
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return redis.Nil })
+	assert.ErrorIs(t, process(context.Background(), redis.NewStringCmd(context.Background(), "get", "key")), redis.Nil)
+	process = hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return errors.New("i/o timeout") })
+	assert.Error(t, process(context.Background(), redis.NewStringCmd(context.Background(), "get", "key")))
+
+	assert.Equal(t, before+1, testutil.ToFloat64(redisOperationsTotal.WithLabelValues("get", "success")))
+	assert.Equal(t, beforeErrors+1, testutil.ToFloat64(redisOperationsTotal.WithLabelValues("get", "error")))
+}
@@ -27,20 +27,39 @@ var (
 		[]string{"method", "endpoint"},
 	)
 
+	// gRPC metrics
+	grpcRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_gateway_grpc_requests_total",
+			Help: "Total number of gRPC requests",
+		},
+		[]string{"method", "code"},
+	)
+
+	grpcRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "auth_gateway_grpc_request_duration_seconds",
+			Help:    "gRPC request duration in seconds",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"method"},
+	)
+
 	// Authentication metrics
 	loginTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "auth_gateway_login_total",
 			Help: "Total number of login attempts",
 		},
-		[]string{"status"}, // success, failure
+		[]string{"method", "status"}, // method: password, otp_email, oauth, ...; status: success, failure
 	)
 
-	signupTotal = promauto.NewCounter(
+	signupTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "auth_gateway_signup_total",
 			Help: "Total number of user registrations",
 		},
+		[]string{"method"}, // password, passwordless, oauth
 	)
 
 	tokenValidations = promauto.NewCounterVec(
@@ -48,7 +67,15 @@ var (
 			Name: "auth_gateway_token_validations_total",
 			Help: "Total number of token validations",
 		},
-		[]string{"type", "status"}, // type: access, refresh; status: valid, invalid
+		[]string{"transport", "type", "status"}, // transport: rest, grpc; type: access, api_key; status: valid, invalid
+	)
+
+	oauthGrantsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_gateway_oauth_grants_total",
+			Help: "Total number of OAuth token requests by grant type",
+		},
+		[]string{"grant_type", "result"}, // result: success or the OAuth error code
 	)
 
 	// Database metrics
@@ -95,6 +122,14 @@ var (
 		[]string{"operation"},
 	)
 
+	userCacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_gateway_user_cache_lookups_total",
+			Help: "Total number of user cache lookups",
+		},
+		[]string{"result"}, // hit, miss
+	)
+
 	// Session metrics
 	activeSessions = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -107,9 +142,9 @@ var (
 	rateLimitTriggers = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "auth_gateway_rate_limit_triggers_total",
-			Help: "Total number of rate limit triggers",
+			Help: "Total number of requests rejected by rate limits",
 		},
-		[]string{"endpoint", "ip"},
+		[]string{"transport", "endpoint"}, // endpoint: route or gRPC method
 	)
 
 	// Error metrics
@@ -145,6 +180,39 @@ var (
 		},
 		[]string{"action"}, // created, updated, deleted
 	)
+
+	// SLO metrics
+	sloRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_gateway_slo_requests_total",
+			Help: "Total number of requests counted against service level objectives",
+		},
+		[]string{"slo", "result"}, // result: good, bad
+	)
+
+	sloObjective = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "auth_gateway_slo_objective",
+			Help: "Share of requests that must be good for a service level objective",
+		},
+		[]string{"slo"},
+	)
+
+	sloErrorBudgetRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "auth_gateway_slo_error_budget_remaining",
+			Help: "Share of the error budget of a service level objective left over the SLO window",
+		},
+		[]string{"slo"},
+	)
+
+	sloBurnRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "auth_gateway_slo_burn_rate",
+			Help: "Rate the error budget of a service level objective burns at over a recent window",
+		},
+		[]string{"slo", "window"},
+	)
 )
 
 // MetricsCollector collects and exposes Prometheus metrics
@@ -174,27 +242,44 @@ func RecordHTTPRequest(method, endpoint string, statusCode int, duration time.Du
 	httpRequestDuration.WithLabelValues(method, endpoint).Observe(duration.Seconds())
 }
 
-// RecordLogin records a login attempt
-func RecordLogin(success bool) {
+// RecordGRPCRequest records a gRPC request with its status code
+func RecordGRPCRequest(method, code string, duration time.Duration) {
+	grpcRequestsTotal.WithLabelValues(method, code).Inc()
+	grpcRequestDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// RecordLogin records a login attempt made with method
+func RecordLogin(method string, success bool) {
 	status := "failure"
 	if success {
 		status = "success"
 	}
-	loginTotal.WithLabelValues(status).Inc()
+	loginTotal.WithLabelValues(method, status).Inc()
 }
 
-// RecordSignup records a user registration
-func RecordSignup() {
-	signupTotal.Inc()
+// RecordSignup records a user registration made with method
+func RecordSignup(method string) {
+	signupTotal.WithLabelValues(method).Inc()
 }
 
-// RecordTokenValidation records a token validation
-func RecordTokenValidation(tokenType string, valid bool) {
+// Transports requests arrive over
+const (
+	TransportREST = "rest"
+	TransportGRPC = "grpc"
+)
+
+// RecordTokenValidation records a token validation made over transport
+func RecordTokenValidation(transport, tokenType string, valid bool) {
 	status := "invalid"
 	if valid {
 		status = "valid"
 	}
-	tokenValidations.WithLabelValues(tokenType, status).Inc()
+	tokenValidations.WithLabelValues(transport, tokenType, status).Inc()
+}
+
+// RecordOAuthGrant records an OAuth token request; result is "success" or the OAuth error code
+func RecordOAuthGrant(grantType, result string) {
+	oauthGrantsTotal.WithLabelValues(grantType, result).Inc()
 }
 
 // RecordDBQuery records a database query
@@ -225,14 +310,23 @@ func RecordRedisOperation(operation string, duration time.Duration, err error) {
 	redisOperationDuration.WithLabelValues(operation).Observe(duration.Seconds())
 }
 
+// RecordUserCacheLookup records a user cache lookup
+func RecordUserCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	userCacheLookups.WithLabelValues(result).Inc()
+}
+
 // UpdateActiveSessions updates the active sessions count
 func UpdateActiveSessions(count int) {
 	activeSessions.Set(float64(count))
 }
 
-// RecordRateLimitTrigger records a rate limit trigger
-func RecordRateLimitTrigger(endpoint, ip string) {
-	rateLimitTriggers.WithLabelValues(endpoint, ip).Inc()
+// RecordRateLimitTrigger records a request rejected by a rate limit
+func RecordRateLimitTrigger(transport, endpoint string) {
+	rateLimitTriggers.WithLabelValues(transport, endpoint).Inc()
 }
 
 // RecordError records an error
@@ -252,3 +346,23 @@ func RecordLDAPSync(success bool, duration time.Duration, usersCreated, usersUpd
 	ldapSyncUsers.WithLabelValues("updated").Add(float64(usersUpdated))
 	ldapSyncUsers.WithLabelValues("deleted").Add(float64(usersDeleted))
 }
+
+// RecordSLORequest records a request counted against a service level objective
+func RecordSLORequest(slo string, good bool) {
+	result := "bad"
+	if good {
+		result = "good"
+	}
+	sloRequestsTotal.WithLabelValues(slo, result).Inc()
+}
+
+// UpdateSLOBudget updates the objective and remaining error budget of a service level objective
+func UpdateSLOBudget(slo string, objective, budgetRemaining float64) {
+	sloObjective.WithLabelValues(slo).Set(objective)
+	sloErrorBudgetRemaining.WithLabelValues(slo).Set(budgetRemaining)
+}
+
+// UpdateSLOBurnRate updates the burn rate of a service level objective over a window
+func UpdateSLOBurnRate(slo, window string, burnRate float64) {
+	sloBurnRate.WithLabelValues(slo, window).Set(burnRate)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// AccessLog middleware writes each request to the access log
+func AccessLog(log *accesslog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		entry := accesslog.Entry{
+			Time:      start,
+			Protocol:  accesslog.ProtocolHTTP,
+			Method:    c.Request.Method,
+			Path:      path,
+			Status:    c.Writer.Status(),
+			Duration:  time.Since(start),
+			Bytes:     max(c.Writer.Size(), 0),
+			IP:        utils.GetClientIP(c),
+			UserAgent: c.Request.UserAgent(),
+			Referer:   c.Request.Referer(),
+			RequestID: utils.GetRequestIDFromContext(c),
+		}
+		if userID, exists := utils.GetUserIDFromContext(c); exists {
+			entry.UserID = userID.String()
+		}
+		log.Log(entry)
+	}
+}
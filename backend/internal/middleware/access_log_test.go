@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/accesslog"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	log, err := accesslog.NewWriter(&buf, &config.AccessLogConfig{Format: "json", AnonymizeIP: true})
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	r := gin.New()
+	r.Use(RequestID(), AccessLog(log))
+	r.GET("/api/profile", func(c *gin.Context) {
+		c.Set(utils.UserIDKey, userID)
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/profile?token=secret", nil)
+	req.RemoteAddr = "198.51.100.23:4321"
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set(RequestIDHeader, "req-1")
+	r.ServeHTTP(w, req)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "http", entry["protocol"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/api/profile", entry["path"], "query strings are not logged")
+	assert.Equal(t, float64(http.StatusOK), entry["status"])
+	assert.Equal(t, float64(2), entry["bytes"])
+	assert.Equal(t, "198.51.100.0", entry["ip"])
+	assert.Equal(t, "test-agent", entry["user_agent"])
+	assert.Equal(t, userID.String(), entry["user_id"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
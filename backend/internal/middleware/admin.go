@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 )
@@ -11,7 +12,7 @@ import (
 // RequireAdmin checks if the user has admin role.
 // Application secrets and API keys bypass the role check (service-level access).
 func RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		if authType, exists := c.Get("auth_type"); exists {
 			if authType == "application" || authType == "api_key" {
 				c.Next()
@@ -35,12 +36,16 @@ func RequireAdmin() gin.HandlerFunc {
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementRole,
+		Values: []string{string(models.RoleAdmin)},
+		Bypass: []string{models.CredentialAPIKey, models.CredentialAppSecret},
+	})
 }
 
 // RequireAdminOrModerator checks if the user has admin or moderator role
 func RequireAdminOrModerator() gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		roles, exists := utils.GetUserRolesFromContext(c)
 		if !exists {
 			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
@@ -57,5 +62,8 @@ func RequireAdminOrModerator() gin.HandlerFunc {
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementRole,
+		Values: []string{string(models.RoleAdmin), string(models.RoleModerator)},
+	})
 }
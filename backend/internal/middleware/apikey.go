@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -29,33 +30,39 @@ func NewAPIKeyMiddleware(apiKeyService service.APIKeyServicer, appService servic
 // Authenticate validates the API key or application secret and sets context.
 // Supports: X-API-Key header, X-App-Secret header, Authorization: Bearer agw_/app_
 func (m *APIKeyMiddleware) Authenticate() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		token := c.GetHeader("X-API-Key")
-		if token == "" {
-			token = c.GetHeader("X-App-Secret")
-		}
-		if token == "" {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" {
-				parts := strings.SplitN(authHeader, " ", 2)
-				if len(parts) == 2 && parts[0] == "Bearer" {
-					token = parts[1]
-				}
+	return authmap.Describe(m.authenticate, models.AuthRequirement{
+		Kind:   models.AuthRequirementAuthentication,
+		Values: []string{models.CredentialAPIKey, models.CredentialAppSecret},
+	})
+}
+
+// authenticate is the Authenticate middleware, also run by AuthMiddleware for API keys and application secrets
+func (m *APIKeyMiddleware) authenticate(c *gin.Context) {
+	token := c.GetHeader("X-API-Key")
+	if token == "" {
+		token = c.GetHeader("X-App-Secret")
+	}
+	if token == "" {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" {
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) == 2 && parts[0] == "Bearer" {
+				token = parts[1]
 			}
 		}
+	}
 
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
-			c.Abort()
-			return
-		}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
+		c.Abort()
+		return
+	}
 
-		// Dispatch based on token prefix
-		if strings.HasPrefix(token, "app_") {
-			m.authenticateAppSecret(c, token)
-		} else {
-			m.authenticateAPIKey(c, token)
-		}
+	// Dispatch based on token prefix
+	if strings.HasPrefix(token, "app_") {
+		m.authenticateAppSecret(c, token)
+	} else {
+		m.authenticateAPIKey(c, token)
 	}
 }
 
@@ -76,6 +83,10 @@ func (m *APIKeyMiddleware) authenticateAPIKey(c *gin.Context, apiKey string) {
 	c.Set("api_key_id", key.ID)
 	c.Set("api_key", key)
 	c.Set("auth_type", "api_key")
+	utils.AddLogFields(c, map[string]interface{}{
+		"user_id":    user.ID.String(),
+		"api_key_id": key.ID.String(),
+	})
 
 	ctx := c.Request.Context()
 	roles, err := m.rbacRepo.GetUserRoles(ctx, user.ID)
@@ -116,7 +127,7 @@ func (m *APIKeyMiddleware) authenticateAppSecret(c *gin.Context, secret string)
 // RequireScope checks if the API key has the required scope.
 // Application secrets bypass scope checks (full access).
 func (m *APIKeyMiddleware) RequireScope(scope models.APIKeyScope) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		// Application secrets have full access
 		if authType, _ := c.Get("auth_type"); authType == "application" {
 			c.Next()
@@ -146,12 +157,16 @@ func (m *APIKeyMiddleware) RequireScope(scope models.APIKeyScope) gin.HandlerFun
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementScope,
+		Values: []string{string(scope)},
+		Bypass: []string{models.CredentialAppSecret},
+	})
 }
 
 // RequireAnyScope checks if the API key has any of the required scopes
 func (m *APIKeyMiddleware) RequireAnyScope(scopes ...models.APIKeyScope) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		// Get API key from context
 		apiKeyVal, exists := c.Get("api_key")
 		if !exists {
@@ -185,5 +200,17 @@ func (m *APIKeyMiddleware) RequireAnyScope(scopes ...models.APIKeyScope) gin.Han
 		}
 
 		c.Next()
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementScope,
+		Values: scopeNames(scopes),
+	})
+}
+
+// scopeNames returns the names of API key scopes
+func scopeNames(scopes []models.APIKeyScope) []string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
 	}
+	return names
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -19,7 +20,7 @@ func NewAppSecretMiddleware(appService service.ApplicationServicer) *AppSecretMi
 }
 
 func (m *AppSecretMiddleware) RequireAppSecret() gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		secret := ""
 
 		authHeader := c.GetHeader("Authorization")
@@ -59,5 +60,8 @@ func (m *AppSecretMiddleware) RequireAppSecret() gin.HandlerFunc {
 		c.Set(utils.ApplicationIDKey, app.ID)
 		c.Set("auth_type", "application")
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementAuthentication,
+		Values: []string{models.CredentialAppSecret},
+	})
 }
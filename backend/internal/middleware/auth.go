@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
@@ -17,6 +18,18 @@ type AuthMiddleware struct {
 	jwtService       *jwt.Service
 	blacklistService service.BlacklistServicer
 	apiKeyMiddleware *APIKeyMiddleware
+	tokenVersions    service.TokenVersionServicer
+	// requireVerifiedEmail limits tokens of users with an unverified email address to unverifiedEmailRoutes
+	requireVerifiedEmail bool
+}
+
+// unverifiedEmailRoutes are the routes a user with an unverified email address can still call
+// when email verification is enforced: enough to see their profile, verify an address and sign out
+var unverifiedEmailRoutes = map[string]bool{
+	"GET /api/auth/profile":        true,
+	"POST /api/auth/logout":        true,
+	"GET /api/auth/emails":         true,
+	"POST /api/auth/emails/verify": true,
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -32,14 +45,30 @@ func (m *AuthMiddleware) SetAPIKeyMiddleware(apiKeyMw *APIKeyMiddleware) {
 	m.apiKeyMiddleware = apiKeyMw
 }
 
+// SetTokenVersionService enables token version and global epoch checks
+func (m *AuthMiddleware) SetTokenVersionService(tokenVersions service.TokenVersionServicer) {
+	m.tokenVersions = tokenVersions
+}
+
+// SetRequireVerifiedEmail limits users with an unverified email address to the routes needed to
+// verify it. Enabled for the grace and block email verification levels.
+func (m *AuthMiddleware) SetRequireVerifiedEmail(require bool) {
+	m.requireVerifiedEmail = require
+}
+
 // Authenticate validates JWT token, API key, or application secret.
 // Priority: X-API-Key / X-App-Secret / Bearer agw_ / Bearer app_ → delegate to APIKeyMiddleware.
 // Otherwise treat as JWT.
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
-	return func(c *gin.Context) {
+	credentials := []string{models.CredentialJWT}
+	if m.apiKeyMiddleware != nil {
+		credentials = append(credentials, models.CredentialAPIKey, models.CredentialAppSecret)
+	}
+
+	return authmap.Describe(func(c *gin.Context) {
 		// Check if request carries an API key or app secret
 		if m.apiKeyMiddleware != nil && m.isAPIKeyOrAppSecret(c) {
-			m.apiKeyMiddleware.Authenticate()(c)
+			m.apiKeyMiddleware.authenticate(c)
 			return
 		}
 
@@ -64,6 +93,9 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		if err != nil {
 			if errors.Is(err, jwt.ErrExpiredToken) {
 				c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrTokenExpired))
+			} else if errors.Is(err, jwt.ErrInvalidIssuer) || errors.Is(err, jwt.ErrInvalidAudience) {
+				// Token was minted for another environment or service sharing the same secret
+				c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrTokenWrongAudience))
 			} else {
 				c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrInvalidToken))
 			}
@@ -78,10 +110,27 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		if m.tokenVersions != nil && m.tokenVersions.IsRevoked(c.Request.Context(), claims) {
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrTokenRevoked))
+			c.Abort()
+			return
+		}
+
+		// Phone-only accounts have no address to verify
+		if m.requireVerifiedEmail && claims.Email != "" && !claims.EmailVerified &&
+			!unverifiedEmailRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.JSON(http.StatusForbidden, models.NewErrorResponse(models.ErrEmailNotVerified))
+			c.Abort()
+			return
+		}
+
 		c.Set(utils.UserIDKey, claims.UserID)
 		c.Set(utils.UserEmailKey, claims.Email)
 		c.Set(utils.UserRolesKey, claims.Roles)
 		c.Set(utils.TokenKey, token)
+		utils.AddLogFields(c, map[string]interface{}{
+			"user_id": claims.UserID.String(),
+		})
 
 		if claims.ApplicationID != nil {
 			if _, exists := utils.GetApplicationIDFromContext(c); !exists {
@@ -90,7 +139,10 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementAuthentication,
+		Values: credentials,
+	})
 }
 
 // isAPIKeyOrAppSecret checks if the request carries API key or app secret credentials.
@@ -113,7 +165,7 @@ func (m *AuthMiddleware) isAPIKeyOrAppSecret(c *gin.Context) bool {
 
 // RequireRole checks if user has the required role
 func (m *AuthMiddleware) RequireRole(requiredRole string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		roles, exists := utils.GetUserRolesFromContext(c)
 		if !exists {
 			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
@@ -135,12 +187,16 @@ func (m *AuthMiddleware) RequireRole(requiredRole string) gin.HandlerFunc {
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementRole,
+		Values: []string{requiredRole},
+		Bypass: []string{string(models.RoleAdmin)},
+	})
 }
 
 // RequireAnyRole checks if user has any of the required roles
 func (m *AuthMiddleware) RequireAnyRole(roles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		userRoles, exists := utils.GetUserRolesFromContext(c)
 		if !exists {
 			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
@@ -164,7 +220,11 @@ func (m *AuthMiddleware) RequireAnyRole(roles ...string) gin.HandlerFunc {
 
 		c.JSON(http.StatusForbidden, models.NewErrorResponse(models.ErrForbidden))
 		c.Abort()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementRole,
+		Values: roles,
+		Bypass: []string{string(models.RoleAdmin)},
+	})
 }
 
 // contains checks if a string slice contains a specific string
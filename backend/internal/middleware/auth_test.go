@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/testutil/fixtures"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	jwtpkg "github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
@@ -63,15 +64,11 @@ func newTestJWTService() *jwtpkg.Service {
 }
 
 func newTestUser() *models.User {
-	return &models.User{
-		ID:       uuid.New(),
-		Email:    "test@example.com",
-		Username: "testuser",
-		IsActive: true,
-		Roles: []models.Role{
-			{Name: "user"},
-		},
-	}
+	return fixtures.NewUserBuilder().
+		WithEmail("test@example.com").
+		WithUsername("testuser").
+		Unverified().
+		Build()
 }
 
 func generateValidAccessToken(t *testing.T, jwtSvc *jwtpkg.Service, user *models.User) string {
@@ -290,6 +287,37 @@ func TestAuthenticate_ShouldReturn401_WhenTokenSignedWithDifferentSecret(t *test
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestAuthenticate_ShouldReturn401_WhenTokenIssuedForAnotherEnvironment(t *testing.T) {
+	// Arrange: staging and production share a secret but use distinct issuers
+	stagingJWTSvc := jwtpkg.NewService("test-access-secret", "test-refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		jwtpkg.WithIssuer("https://auth.staging.example.com"))
+	prodJWTSvc := jwtpkg.NewService("test-access-secret", "test-refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		jwtpkg.WithIssuer("https://auth.example.com"))
+	token := generateValidAccessToken(t, stagingJWTSvc, newTestUser())
+
+	authMw := NewAuthMiddleware(prodJWTSvc, newTestBlacklistService(prodJWTSvc))
+
+	r := gin.New()
+	r.Use(authMw.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var body models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	require.NoError(t, err)
+	assert.Equal(t, models.ErrTokenWrongAudience.Message, body.Message)
+}
+
 func TestAuthenticate_ShouldReturn401_WhenTokenIsBlacklisted(t *testing.T) {
 	// Arrange
 	jwtSvc := newTestJWTService()
@@ -319,6 +347,122 @@ func TestAuthenticate_ShouldReturn401_WhenTokenIsBlacklisted(t *testing.T) {
 	assert.Equal(t, "Token revoked", body.Message)
 }
 
+type stubTokenVersionService struct {
+	service.TokenVersionServicer
+	revoked bool
+}
+
+func (s *stubTokenVersionService) IsRevoked(ctx context.Context, claims *jwtpkg.Claims) bool {
+	return s.revoked
+}
+
+func TestAuthenticate_ShouldReturn401_WhenTokenVersionIsRevoked(t *testing.T) {
+	// Arrange
+	jwtSvc := newTestJWTService()
+	authMw := newTestAuthMiddleware(jwtSvc)
+	authMw.SetTokenVersionService(&stubTokenVersionService{revoked: true})
+	user := newTestUser()
+	token := generateValidAccessToken(t, jwtSvc, user)
+
+	r := gin.New()
+	r.Use(authMw.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var body models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	require.NoError(t, err)
+	assert.Equal(t, "Token revoked", body.Message)
+}
+
+func TestAuthenticate_ShouldLimitUnverifiedEmail_WhenVerificationRequired(t *testing.T) {
+	// Arrange
+	jwtSvc := newTestJWTService()
+	authMw := newTestAuthMiddleware(jwtSvc)
+	authMw.SetRequireVerifiedEmail(true)
+
+	unverified := generateValidAccessToken(t, jwtSvc, newTestUser())
+	verifiedUser := newTestUser()
+	verifiedUser.EmailVerified = true
+	verified := generateValidAccessToken(t, jwtSvc, verifiedUser)
+	phoneUser := newTestUser()
+	phoneUser.Email = ""
+	phoneOnly := generateValidAccessToken(t, jwtSvc, phoneUser)
+
+	r := gin.New()
+	r.Use(authMw.Authenticate())
+	r.GET("/api/auth/profile", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	r.GET("/api/api-keys", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"unverified user can read profile", "/api/auth/profile", unverified, http.StatusOK},
+		{"unverified user is limited elsewhere", "/api/api-keys", unverified, http.StatusForbidden},
+		{"verified user", "/api/api-keys", verified, http.StatusOK},
+		{"phone-only user", "/api/api-keys", phoneOnly, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusForbidden {
+				var body models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, models.EmailNotVerifiedCode, body.Details)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_ShouldAllowUnverifiedEmail_WhenVerificationAdvisory(t *testing.T) {
+	// Arrange
+	jwtSvc := newTestJWTService()
+	authMw := newTestAuthMiddleware(jwtSvc)
+	token := generateValidAccessToken(t, jwtSvc, newTestUser())
+
+	r := gin.New()
+	r.Use(authMw.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestAuthenticate_ShouldSetApplicationIDFromClaims_WhenPresent(t *testing.T) {
 	// Arrange
 	jwtSvc := newTestJWTService()
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// ClientCertificate records the thumbprint of the client certificate a request was made with,
+// for certificate-bound access tokens (RFC 8705). The certificate comes from the TLS connection,
+// or from header when the request arrives from one of trustedProxies (IPs or CIDRs), for
+// deployments where a proxy terminates mTLS. An empty header disables forwarded certificates.
+func ClientCertificate(header string, trustedProxies []string, log *logger.Logger) gin.HandlerFunc {
+	proxies := parseTrustedProxies(trustedProxies)
+
+	return func(c *gin.Context) {
+		if tls := c.Request.TLS; tls != nil && len(tls.PeerCertificates) > 0 {
+			c.Set(utils.ClientCertThumbprintKey, utils.CertThumbprint(tls.PeerCertificates[0]))
+			c.Next()
+			return
+		}
+
+		value := c.GetHeader(header)
+		if header == "" || value == "" || !fromTrustedProxy(c.RemoteIP(), proxies) {
+			c.Next()
+			return
+		}
+
+		cert, err := utils.ParseForwardedClientCert(value)
+		if err != nil {
+			log.Warn("Ignoring unparseable forwarded client certificate", map[string]interface{}{
+				"error":     err.Error(),
+				"remote_ip": c.RemoteIP(),
+			})
+			c.Next()
+			return
+		}
+		c.Set(utils.ClientCertThumbprintKey, utils.CertThumbprint(cert))
+		c.Next()
+	}
+}
+
+// parseTrustedProxies accepts the TRUSTED_PROXIES forms gin accepts: IPs and CIDRs
+func parseTrustedProxies(trustedProxies []string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+func fromTrustedProxy(remoteIP string, proxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(remoteIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClientCertHeader = "X-Client-Cert"
+
+func newClientCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestClientCertificate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cert := newClientCertificate(t)
+	forwarded := url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		tls        bool
+		header     string
+		expected   string
+	}{
+		{"from TLS connection", "203.0.113.7:1234", true, "", utils.CertThumbprint(cert)},
+		{"forwarded by trusted proxy", "10.0.0.5:1234", false, forwarded, utils.CertThumbprint(cert)},
+		{"forwarded by untrusted client", "203.0.113.7:1234", false, forwarded, ""},
+		{"unparseable header", "10.0.0.5:1234", false, "garbage", ""},
+		{"no certificate", "10.0.0.5:1234", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var thumbprint string
+			r := gin.New()
+			r.Use(ClientCertificate(testClientCertHeader, []string{"10.0.0.0/8"}, logger.New("test", logger.DebugLevel, false)))
+			r.POST("/oauth/token", func(c *gin.Context) {
+				thumbprint = utils.GetClientCertThumbprint(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/token", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			}
+			if tt.header != "" {
+				req.Header.Set(testClientCertHeader, tt.header)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, thumbprint)
+		})
+	}
+}
@@ -1,20 +1,90 @@
 package middleware
 
 import (
+	"strings"
+	"sync/atomic"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/smilemakc/auth-gateway/internal/config"
 )
 
-// SetupCORS configures CORS middleware
+// corsGroup is a route group with its own CORS policy
+type corsGroup struct {
+	prefixes []string
+	handler  gin.HandlerFunc
+}
+
+// corsHandlers are the handlers of the current CORS policies
+type corsHandlers struct {
+	defaultHandler gin.HandlerFunc
+	groups         []corsGroup
+}
+
+// CORSMiddleware applies CORS policies that can be replaced while the server runs
+type CORSMiddleware struct {
+	handlers atomic.Pointer[corsHandlers]
+}
+
+// NewCORSMiddleware creates a CORS middleware with the policies of cfg
+func NewCORSMiddleware(cfg *config.CORSConfig) *CORSMiddleware {
+	m := &CORSMiddleware{}
+	m.SetConfig(cfg)
+	return m
+}
+
+// SetConfig replaces the CORS policies, e.g. on a config reload
+func (m *CORSMiddleware) SetConfig(cfg *config.CORSConfig) {
+	m.handlers.Store(&corsHandlers{
+		defaultHandler: newCORSHandler(cfg.Default),
+		groups: []corsGroup{
+			{prefixes: []string{"/api/admin"}, handler: newCORSHandler(cfg.Admin)},
+			{prefixes: []string{"/oauth", "/.well-known"}, handler: newCORSHandler(cfg.OAuth)},
+		},
+	})
+}
+
+// Handler returns the middleware. Requests to the admin API and to the OAuth endpoints get
+// their group's policy, every other request the default one. It must be installed on the
+// router rather than on the groups: preflight requests match no route, so group middleware
+// would never see them.
+func (m *CORSMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handlers := m.handlers.Load()
+		path := c.Request.URL.Path
+		for _, group := range handlers.groups {
+			for _, prefix := range group.prefixes {
+				if hasPathPrefix(path, prefix) {
+					group.handler(c)
+					return
+				}
+			}
+		}
+		handlers.defaultHandler(c)
+	}
+}
+
+// SetupCORS configures CORS middleware with fixed policies; see CORSMiddleware.Handler
 func SetupCORS(cfg *config.CORSConfig) gin.HandlerFunc {
+	return NewCORSMiddleware(cfg).Handler()
+}
+
+func newCORSHandler(policy config.CORSPolicy) gin.HandlerFunc {
 	corsConfig := cors.Config{
-		AllowOrigins:     cfg.AllowedOrigins,
-		AllowMethods:     cfg.AllowedMethods,
-		AllowHeaders:     cfg.AllowedHeaders,
-		AllowCredentials: cfg.AllowCredentials,
-		MaxAge:           86400, // 24 hours
+		AllowOrigins:     policy.AllowedOrigins,
+		AllowMethods:     policy.AllowedMethods,
+		AllowHeaders:     policy.AllowedHeaders,
+		AllowCredentials: policy.AllowCredentials,
+		MaxAge:           policy.MaxAge,
+	}
+	if len(policy.AllowedOrigins) == 0 {
+		corsConfig.AllowOriginFunc = func(string) bool { return false }
 	}
 
 	return cors.New(corsConfig)
 }
+
+// hasPathPrefix reports whether path is prefix or lies below it
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.CORSConfig{
+		Default: config.CORSPolicy{
+			AllowedOrigins:   []string{"https://app.example.com", "https://admin.example.com"},
+			AllowedMethods:   []string{"GET", "POST"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			AllowCredentials: true,
+			MaxAge:           24 * time.Hour,
+		},
+		Admin: config.CORSPolicy{
+			AllowedOrigins:   []string{"https://admin.example.com"},
+			AllowedMethods:   []string{"GET", "POST", "DELETE"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		},
+		OAuth: config.CORSPolicy{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST"},
+			AllowedHeaders: []string{"Content-Type", "Authorization"},
+			MaxAge:         time.Hour,
+		},
+	}
+
+	r := gin.New()
+	r.Use(SetupCORS(cfg))
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	r.GET("/api/auth/profile", ok)
+	r.GET("/api/admin/users", ok)
+	r.GET("/api/administrators", ok)
+	r.POST("/oauth/token", ok)
+	return r
+}
+
+func corsRequest(r *gin.Engine, method, path, origin string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", "GET")
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetupCORS_ShouldApplyAdminPolicy_ToAdminRoutes(t *testing.T) {
+	r := setupCORSRouter()
+
+	w := corsRequest(r, http.MethodGet, "/api/admin/users", "https://app.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = corsRequest(r, http.MethodGet, "/api/admin/users", "https://admin.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestSetupCORS_ShouldApplyDefaultPolicy_OutsideGroups(t *testing.T) {
+	r := setupCORSRouter()
+
+	w := corsRequest(r, http.MethodGet, "/api/auth/profile", "https://app.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Only whole path segments select a group
+	w = corsRequest(r, http.MethodGet, "/api/administrators", "https://app.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetupCORS_ShouldAnswerPreflight_WithGroupMaxAge(t *testing.T) {
+	r := setupCORSRouter()
+
+	w := corsRequest(r, http.MethodOptions, "/api/admin/users", "https://admin.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	w = corsRequest(r, http.MethodOptions, "/api/auth/profile", "https://app.example.com")
+	assert.Equal(t, "86400", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestSetupCORS_ShouldNotAllowCredentials_OnOAuthRoutes(t *testing.T) {
+	r := setupCORSRouter()
+
+	w := corsRequest(r, http.MethodPost, "/oauth/token", "https://spa.example.org")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestSetupCORS_ShouldDenyCrossOrigin_WhenGroupHasNoOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(SetupCORS(&config.CORSConfig{
+		Default: config.CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"GET"}},
+		Admin:   config.CORSPolicy{AllowedMethods: []string{"GET"}},
+		OAuth:   config.CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+	}))
+	r.GET("/api/admin/users", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	w := corsRequest(r, http.MethodGet, "/api/admin/users", "https://app.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCORSMiddleware_ShouldApplyNewPolicies_AfterSetConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy := func(origin string) config.CORSPolicy {
+		return config.CORSPolicy{AllowedOrigins: []string{origin}, AllowedMethods: []string{"GET"}}
+	}
+	cors := NewCORSMiddleware(&config.CORSConfig{
+		Default: policy("https://app.example.com"),
+		Admin:   policy("https://app.example.com"),
+		OAuth:   policy("https://app.example.com"),
+	})
+	r := gin.New()
+	r.Use(cors.Handler())
+	r.GET("/api/auth/profile", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	w := corsRequest(r, http.MethodGet, "/api/auth/profile", "https://new.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	cors.SetConfig(&config.CORSConfig{
+		Default: policy("https://new.example.com"),
+		Admin:   policy("https://new.example.com"),
+		OAuth:   policy("https://new.example.com"),
+	})
+
+	w = corsRequest(r, http.MethodGet, "/api/auth/profile", "https://new.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://new.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
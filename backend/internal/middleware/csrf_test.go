@@ -140,3 +140,16 @@ func TestCSRFProtection_HEAD_SetsCookie(t *testing.T) {
 	}
 	assert.True(t, found, "csrf_token cookie should be set on HEAD")
 }
+
+func TestCSRFProtection_POST_OAuthCallbackExempt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CSRFProtection(true, false))
+	r.POST("/api/auth/:provider/callback", func(c *gin.Context) { c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/auth/apple/callback", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+const (
+	// IdempotencyKeyHeader carries a client-chosen key; a request retried with the same key
+	// gets the response of the first one instead of running again
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on replayed responses
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds idempotency keys
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a request in progress holds its key, in case its
+	// instance dies before storing the response
+	idempotencyLockTTL = time.Minute
+	// maxIdempotentBodyBytes is how much of the request body is fingerprinted
+	maxIdempotentBodyBytes = 1 << 20
+)
+
+// IdempotencyStore keeps idempotency records; *service.RedisService implements it
+type IdempotencyStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// idempotencyRecord is the state of a key: in progress until the response is stored
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+	// Withheld marks a successful response that was not stored because it holds a secret
+	Withheld bool `json:"withheld,omitempty"`
+}
+
+// IdempotencyMiddleware replays the stored response of a request retried with the same
+// Idempotency-Key, so retrying clients don't create duplicates
+type IdempotencyMiddleware struct {
+	store IdempotencyStore
+	ttl   time.Duration
+}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware; responses are replayed for ttl
+func NewIdempotencyMiddleware(store IdempotencyStore, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Idempotent makes a mutating endpoint honour the Idempotency-Key header. Keys are scoped to
+// the caller and the route, so it must run after authentication. The same key with a
+// different request is rejected with 422, and one whose first request is still running
+// with 409. Server errors and 429 are not stored, so the request can be retried. Requests
+// without the header, and all requests while Redis is unavailable, run as usual.
+func (m *IdempotencyMiddleware) Idempotent() gin.HandlerFunc {
+	return m.idempotent(true)
+}
+
+// IdempotentWithoutReplay is Idempotent for endpoints whose successful response holds a
+// secret shown only once, such as a new API key or client secret. Their response body is
+// never stored; a retry of a request that succeeded is rejected with 409 instead.
+func (m *IdempotencyMiddleware) IdempotentWithoutReplay() gin.HandlerFunc {
+	return m.idempotent(false)
+}
+
+// idempotent implements Idempotent; replaySuccess controls whether 2xx bodies are stored
+func (m *IdempotencyMiddleware) idempotent(replaySuccess bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid Idempotency-Key header", "must be at most 255 printable ASCII characters"),
+			))
+			c.Abort()
+			return
+		}
+
+		fingerprint, err := idempotencyFingerprint(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid request", err.Error()),
+			))
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		storeKey := idempotencyStoreKey(c, key)
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		acquired, err := m.store.SetNX(ctx, storeKey, pending, idempotencyLockTTL)
+		if err != nil {
+			// Fail open: Redis being down must not take the endpoints with it
+			c.Next()
+			return
+		}
+		if !acquired {
+			m.replay(c, storeKey, fingerprint)
+			return
+		}
+
+		writer := &recordingResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The outcome is stored even if the client went away
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			_ = m.store.Delete(storeCtx, storeKey)
+			return
+		}
+
+		record := idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if !replaySuccess && status < http.StatusMultipleChoices {
+			record = idempotencyRecord{Fingerprint: fingerprint, Completed: true, Status: status, Withheld: true}
+		}
+		value, _ := json.Marshal(record)
+		_ = m.store.Set(storeCtx, storeKey, value, m.ttl)
+	}
+}
+
+// replay answers a request whose key is already taken
+func (m *IdempotencyMiddleware) replay(c *gin.Context, storeKey, fingerprint string) {
+	defer c.Abort()
+
+	var record idempotencyRecord
+	value, err := m.store.Get(c.Request.Context(), storeKey)
+	if err == nil {
+		err = json.Unmarshal([]byte(value), &record)
+	}
+	if err != nil {
+		// The first request failed and released the key in the meantime
+		c.JSON(http.StatusConflict, models.NewErrorResponse(
+			models.NewAppError(http.StatusConflict, "A request with this Idempotency-Key is in progress"),
+		))
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, models.NewErrorResponse(
+			models.NewAppError(http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request"),
+		))
+		return
+	}
+	if !record.Completed {
+		c.JSON(http.StatusConflict, models.NewErrorResponse(
+			models.NewAppError(http.StatusConflict, "A request with this Idempotency-Key is in progress"),
+		))
+		return
+	}
+	if record.Withheld {
+		c.JSON(http.StatusConflict, models.NewErrorResponse(
+			models.NewAppError(http.StatusConflict, "A request with this Idempotency-Key already succeeded; its response is not replayed because it contains a secret"),
+		))
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+}
+
+// validIdempotencyKey accepts up to maxIdempotencyKeyLength printable ASCII characters
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyFingerprint hashes the method, path and body of the request, leaving the body
+// for the handler to read
+func idempotencyFingerprint(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// idempotencyStoreKey scopes a key to the caller and the route
+func idempotencyStoreKey(c *gin.Context, key string) string {
+	caller := "ip:" + utils.GetClientIP(c)
+	if userID, ok := utils.GetUserIDFromContext(c); ok {
+		caller = "user:" + userID.String()
+	}
+	sum := sha256.Sum256([]byte(c.Request.Method + " " + c.FullPath() + "\n" + key))
+	return "idempotency:" + caller + ":" + hex.EncodeToString(sum[:])
+}
+
+// recordingResponseWriter keeps a copy of the response body
+type recordingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyStore keeps idempotency records in memory, ignoring expiration
+type memoryIdempotencyStore struct {
+	values map[string]string
+	err    error
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{values: make(map[string]string)}
+}
+
+func (m *memoryIdempotencyStore) SetNX(_ context.Context, key string, value interface{}, _ time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if _, ok := m.values[key]; ok {
+		return false, nil
+	}
+	m.values[key] = fmt.Sprintf("%s", value)
+	return true, nil
+}
+
+func (m *memoryIdempotencyStore) Get(_ context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, nil
+}
+
+func (m *memoryIdempotencyStore) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	m.values[key] = fmt.Sprintf("%s", value)
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+// idempotentRouter counts the calls of a create handler answering with status
+func idempotentRouter(store IdempotencyStore, status int, calls *int) *gin.Engine {
+	r := gin.New()
+	r.POST("/items", NewIdempotencyMiddleware(store, time.Hour).Idempotent(), func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return r
+}
+
+func postIdempotent(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryIdempotencyStore(), http.StatusCreated, &calls)
+
+	first := postIdempotent(r, "key-1", `{"name":"a"}`)
+	second := postIdempotent(r, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", second.Header().Get("Content-Type"))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+
+	postIdempotent(r, "key-2", `{"name":"a"}`)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_WithoutReplay_DoesNotStoreSecrets(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := gin.New()
+	r.POST("/items", NewIdempotencyMiddleware(store, time.Hour).IdempotentWithoutReplay(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"plain_key": "agw_secret"})
+	})
+
+	first := postIdempotent(r, "key-1", `{"name":"a"}`)
+	second := postIdempotent(r, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, first.Body.String(), "agw_secret")
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.NotContains(t, second.Body.String(), "agw_secret")
+	require.Len(t, store.values, 1)
+	for _, value := range store.values {
+		var record idempotencyRecord
+		require.NoError(t, json.Unmarshal([]byte(value), &record))
+		assert.Empty(t, record.Body)
+		assert.True(t, record.Withheld)
+	}
+}
+
+func TestIdempotencyMiddleware_RejectsDifferentRequest(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryIdempotencyStore(), http.StatusCreated, &calls)
+
+	postIdempotent(r, "key-1", `{"name":"a"}`)
+	w := postIdempotent(r, "key-1", `{"name":"b"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_RejectsRequestInProgress(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	var w *httptest.ResponseRecorder
+	calls := 0
+
+	r := gin.New()
+	r.POST("/items", NewIdempotencyMiddleware(store, time.Hour).Idempotent(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			// A retry arriving while the first request is still running
+			w = postIdempotent(r, "key-1", `{"name":"a"}`)
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postIdempotent(r, "key-1", `{"name":"a"}`)
+
+	require.NotNil(t, w)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_DoesNotStoreServerErrors(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := idempotentRouter(store, http.StatusInternalServerError, &calls)
+
+	postIdempotent(r, "key-1", `{}`)
+	w := postIdempotent(r, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.values)
+}
+
+func TestIdempotencyMiddleware_StoresClientErrors(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryIdempotencyStore(), http.StatusBadRequest, &calls)
+
+	postIdempotent(r, "key-1", `{}`)
+	w := postIdempotent(r, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := idempotentRouter(store, http.StatusCreated, &calls)
+
+	postIdempotent(r, "", `{}`)
+	postIdempotent(r, "", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.values)
+}
+
+func TestIdempotencyMiddleware_RejectsInvalidKey(t *testing.T) {
+	calls := 0
+	r := idempotentRouter(newMemoryIdempotencyStore(), http.StatusCreated, &calls)
+
+	for _, key := range []string{strings.Repeat("k", maxIdempotencyKeyLength+1), "key\x01"} {
+		w := postIdempotent(r, key, `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	assert.Zero(t, calls)
+}
+
+func TestIdempotencyMiddleware_FailsOpen(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	store.err = errors.New("connection refused")
+	calls := 0
+	r := idempotentRouter(store, http.StatusCreated, &calls)
+
+	first := postIdempotent(r, "key-1", `{}`)
+	postIdempotent(r, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, 2, calls)
+}
@@ -33,18 +33,16 @@ func Logger(log *logger.Logger) gin.HandlerFunc {
 			"user_agent": c.Request.UserAgent(),
 		}
 
-		// Add user ID if available
-		if userID, exists := utils.GetUserIDFromContext(c); exists {
-			fields["user_id"] = userID.String()
-		}
+		// Request and user IDs come with the request context
+		ctx := c.Request.Context()
 
 		// Log based on status code
 		if statusCode >= 500 {
-			log.Error("HTTP request failed", fields)
+			log.ErrorContext(ctx, "HTTP request failed", fields)
 		} else if statusCode >= 400 {
-			log.Warn("HTTP request error", fields)
+			log.WarnContext(ctx, "HTTP request error", fields)
 		} else {
-			log.Info("HTTP request", fields)
+			log.InfoContext(ctx, "HTTP request", fields)
 		}
 	}
 }
@@ -24,10 +24,13 @@ func NewMaintenanceMiddleware(systemRepo service.SystemRepositoryInterface) *Mai
 // CheckMaintenance checks if the system is in maintenance mode
 func (m *MaintenanceMiddleware) CheckMaintenance() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip check for health endpoints
+		// Skip check for health endpoints, the public status, which reports the maintenance,
+		// and the version, which clients read when they start
 		if c.Request.URL.Path == "/auth/health" ||
 			c.Request.URL.Path == "/auth/ready" ||
-			c.Request.URL.Path == "/auth/live" {
+			c.Request.URL.Path == "/auth/live" ||
+			c.Request.URL.Path == "/status" ||
+			c.Request.URL.Path == "/version" {
 			c.Next()
 			return
 		}
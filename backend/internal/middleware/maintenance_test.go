@@ -16,7 +16,7 @@ import (
 // 2. Fail-open behavior when repository returns an error (nil repo causes error)
 
 func TestCheckMaintenance_ShouldBypassHealthEndpoint(t *testing.T) {
-	healthPaths := []string{"/auth/health", "/auth/ready", "/auth/live"}
+	healthPaths := []string{"/auth/health", "/auth/ready", "/auth/live", "/status", "/version"}
 
 	for _, path := range healthPaths {
 		t.Run(path, func(t *testing.T) {
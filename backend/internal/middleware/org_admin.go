@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// OrgAdminMiddleware restricts organization-scoped routes to the admins of that organization
+type OrgAdminMiddleware struct {
+	orgAdminService service.OrgAdminServicer
+}
+
+// NewOrgAdminMiddleware creates a new organization admin middleware
+func NewOrgAdminMiddleware(orgAdminService service.OrgAdminServicer) *OrgAdminMiddleware {
+	return &OrgAdminMiddleware{
+		orgAdminService: orgAdminService,
+	}
+}
+
+// RequireOrgAdmin checks that the user administers the group named by the given path
+// parameter, directly or through a parent group. Global admins, application secrets and
+// API keys bypass the check, as they do for RequireAdmin.
+func (m *OrgAdminMiddleware) RequireOrgAdmin(param string) gin.HandlerFunc {
+	return authmap.Describe(func(c *gin.Context) {
+		groupID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				models.NewAppError(http.StatusBadRequest, "Invalid organization ID"),
+			))
+			c.Abort()
+			return
+		}
+
+		if authType, exists := c.Get("auth_type"); exists {
+			if authType == "application" || authType == "api_key" {
+				c.Next()
+				return
+			}
+		}
+
+		if roles, exists := utils.GetUserRolesFromContext(c); exists && utils.HasRole(roles, "admin") {
+			c.Next()
+			return
+		}
+
+		userID, ok := utils.GetUserIDFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
+			c.Abort()
+			return
+		}
+
+		isAdmin, err := m.orgAdminService.IsOrgAdmin(c.Request.Context(), *userID, groupID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check organization access"})
+			c.Abort()
+			return
+		}
+
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, models.NewErrorResponse(
+				models.NewAppError(http.StatusForbidden, "Organization admin access required"),
+			))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementOrgAdmin,
+		Values: []string{param},
+		Bypass: []string{string(models.RoleAdmin), models.CredentialAPIKey, models.CredentialAppSecret},
+	})
+}
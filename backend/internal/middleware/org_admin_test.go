@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/service"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockGroupAdminStore implements service.GroupAdminStore for testing the organization
+// admin middleware. Only IsAdmin is relevant for middleware tests.
+type mockGroupAdminStore struct {
+	isAdminFn func(ctx context.Context, userID, groupID uuid.UUID) (bool, error)
+}
+
+func (m *mockGroupAdminStore) ListAdmins(ctx context.Context, groupID uuid.UUID) ([]*models.GroupAdmin, error) {
+	return nil, nil
+}
+func (m *mockGroupAdminStore) AddAdmin(ctx context.Context, admin *models.GroupAdmin) error {
+	return nil
+}
+func (m *mockGroupAdminStore) RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockGroupAdminStore) ListAdministeredGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupAdminStore) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+func (m *mockGroupAdminStore) ListMembers(ctx context.Context, groupID uuid.UUID, search string, limit, offset int) ([]*models.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockGroupAdminStore) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	return nil
+}
+func (m *mockGroupAdminStore) ListMemberAPIKeys(ctx context.Context, groupID uuid.UUID) ([]*models.APIKey, error) {
+	return nil, nil
+}
+
+func (m *mockGroupAdminStore) IsAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
+	if m.isAdminFn != nil {
+		return m.isAdminFn(ctx, userID, groupID)
+	}
+	return false, nil
+}
+
+// newOrgAdminTestRouter serves GET /orgs/:id behind RequireOrgAdmin with the given context values.
+func newOrgAdminTestRouter(store *mockGroupAdminStore, values map[string]interface{}) *gin.Engine {
+	orgAdminService := service.NewOrgAdminService(store, nil, nil, nil, nil, nil, nil)
+	mw := NewOrgAdminMiddleware(orgAdminService)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		for k, v := range values {
+			c.Set(k, v)
+		}
+		c.Next()
+	})
+	r.GET("/orgs/:id", mw.RequireOrgAdmin("id"), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestRequireOrgAdmin_ShouldAllow_WhenUserAdministersGroup(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	store := &mockGroupAdminStore{
+		isAdminFn: func(ctx context.Context, uid, gid uuid.UUID) (bool, error) {
+			return uid == userID && gid == groupID, nil
+		},
+	}
+	r := newOrgAdminTestRouter(store, map[string]interface{}{
+		utils.UserIDKey:    userID,
+		utils.UserRolesKey: []string{"user"},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/"+groupID.String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireOrgAdmin_ShouldReturn403_WhenUserDoesNotAdministerGroup(t *testing.T) {
+	r := newOrgAdminTestRouter(&mockGroupAdminStore{}, map[string]interface{}{
+		utils.UserIDKey:    uuid.New(),
+		utils.UserRolesKey: []string{"user"},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/"+uuid.New().String(), nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Organization admin access required")
+}
+
+func TestRequireOrgAdmin_ShouldAllow_WhenUserIsGlobalAdmin(t *testing.T) {
+	store := &mockGroupAdminStore{
+		isAdminFn: func(ctx context.Context, uid, gid uuid.UUID) (bool, error) {
+			t.Fatal("IsAdmin should not be called for global admins")
+			return false, nil
+		},
+	}
+	r := newOrgAdminTestRouter(store, map[string]interface{}{
+		utils.UserIDKey:    uuid.New(),
+		utils.UserRolesKey: []string{"admin"},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/"+uuid.New().String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireOrgAdmin_ShouldReturn400_WhenGroupIDInvalid(t *testing.T) {
+	r := newOrgAdminTestRouter(&mockGroupAdminStore{}, map[string]interface{}{
+		utils.UserIDKey: uuid.New(),
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/not-a-uuid", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRequireOrgAdmin_ShouldReturn401_WhenNoUserInContext(t *testing.T) {
+	r := newOrgAdminTestRouter(&mockGroupAdminStore{}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/"+uuid.New().String(), nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireOrgAdmin_ShouldReturn500_WhenStoreFails(t *testing.T) {
+	store := &mockGroupAdminStore{
+		isAdminFn: func(ctx context.Context, uid, gid uuid.UUID) (bool, error) {
+			return false, errors.New("db down")
+		},
+	}
+	r := newOrgAdminTestRouter(store, map[string]interface{}{
+		utils.UserIDKey: uuid.New(),
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orgs/"+uuid.New().String(), nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -1,112 +1,160 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
 )
 
+// maxClientIDLength bounds client IDs used in rate limit keys
+const maxClientIDLength = 255
+
+// RateLimiter counts requests over a sliding window; *service.RedisService implements it
+type RateLimiter interface {
+	SlidingWindowRateLimit(ctx context.Context, key string, limit int, window time.Duration) (*service.RateLimitResult, error)
+}
+
 // RateLimitMiddleware provides rate limiting functionality
 type RateLimitMiddleware struct {
-	redis  service.RedisServicer
-	config *config.RateLimitConfig
+	limiter RateLimiter
+	// config is read on every request, so SetConfig changes the limits of running routes
+	config atomic.Pointer[config.RateLimitConfig]
+	// tokenQueue holds a slot for each token request waiting for capacity; nil when
+	// token requests over the limit are rejected at once
+	tokenQueue chan struct{}
+	logger     *logger.Logger
 }
 
+// limitFunc returns the limit and window a request is counted against
+type limitFunc func(cfg *config.RateLimitConfig) (int, time.Duration)
+
 // NewRateLimitMiddleware creates a new rate limit middleware
-func NewRateLimitMiddleware(redis service.RedisServicer, cfg *config.RateLimitConfig) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
-		redis:  redis,
-		config: cfg,
+func NewRateLimitMiddleware(limiter RateLimiter, cfg *config.RateLimitConfig, log *logger.Logger) *RateLimitMiddleware {
+	m := &RateLimitMiddleware{
+		limiter: limiter,
+		logger:  log,
+	}
+	m.config.Store(cfg)
+	if cfg.TokenQueueMaxWait > 0 && cfg.TokenQueueSize > 0 {
+		m.tokenQueue = make(chan struct{}, cfg.TokenQueueSize)
 	}
+	return m
+}
+
+// SetConfig replaces the limits of the named Limit* middlewares, e.g. on a config reload.
+// Whether token requests are queued and the size of the queue are fixed at creation.
+func (m *RateLimitMiddleware) SetConfig(cfg *config.RateLimitConfig) {
+	m.config.Store(cfg)
 }
 
 // LimitByIP limits requests by IP address
 func (m *RateLimitMiddleware) LimitByIP(endpoint string, max int, window time.Duration) gin.HandlerFunc {
+	return m.limitByIP(endpoint, fixedLimit(max, window))
+}
+
+func (m *RateLimitMiddleware) limitByIP(endpoint string, limit limitFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ip := utils.GetClientIP(c)
-		key := fmt.Sprintf("ratelimit:%s:%s", ip, endpoint)
-
-		count, err := m.redis.IncrementRateLimit(c.Request.Context(), key, window)
-		if err != nil {
-			// Log error but don't fail the request
-			fmt.Printf("Rate limit error: %v\n", err)
-			c.Next()
-			return
-		}
-
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", max))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", max-int(count)))
-
-		if count > int64(max) {
-			c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(models.ErrRateLimitExceeded))
-			c.Abort()
-			return
-		}
-
-		c.Next()
+		max, window := limit(m.config.Load())
+		m.limit(c, fmt.Sprintf("ratelimit:%s:%s", ip, endpoint), max, window)
 	}
 }
 
+func fixedLimit(max int, window time.Duration) limitFunc {
+	return func(*config.RateLimitConfig) (int, time.Duration) { return max, window }
+}
+
 // LimitSignup limits signup requests
 func (m *RateLimitMiddleware) LimitSignup() gin.HandlerFunc {
-	return m.LimitByIP("signup", m.config.SignupMax, m.config.SignupWindow)
+	return m.limitByIP("signup", func(cfg *config.RateLimitConfig) (int, time.Duration) {
+		return cfg.SignupMax, cfg.SignupWindow
+	})
 }
 
 // LimitSignin limits signin requests
 func (m *RateLimitMiddleware) LimitSignin() gin.HandlerFunc {
-	return m.LimitByIP("signin", m.config.SigninMax, m.config.SigninWindow)
+	return m.limitByIP("signin", func(cfg *config.RateLimitConfig) (int, time.Duration) {
+		return cfg.SigninMax, cfg.SigninWindow
+	})
 }
 
 // LimitAPI limits general API requests
 func (m *RateLimitMiddleware) LimitAPI() gin.HandlerFunc {
-	return m.LimitByIP("api", m.config.APIMax, m.config.APIWindow)
+	return m.limitByIP("api", func(cfg *config.RateLimitConfig) (int, time.Duration) {
+		return cfg.APIMax, cfg.APIWindow
+	})
+}
+
+// LimitUser limits authenticated API requests per user. It must run after authentication.
+func (m *RateLimitMiddleware) LimitUser() gin.HandlerFunc {
+	return m.limitByUserID("api", func(cfg *config.RateLimitConfig) (int, time.Duration) {
+		return cfg.UserMax, cfg.UserWindow
+	})
+}
+
+// LimitClient limits OAuth endpoint requests per client
+func (m *RateLimitMiddleware) LimitClient() gin.HandlerFunc {
+	return m.limitByClient("oauth", clientLimit)
+}
+
+func clientLimit(cfg *config.RateLimitConfig) (int, time.Duration) {
+	return cfg.ClientMax, cfg.ClientWindow
+}
+
+// LimitToken limits token endpoint requests per client like LimitClient. With a token queue
+// configured, a request over the limit waits up to TokenQueueMaxWait for capacity instead of
+// being rejected, so clients retrying together after a deploy are smoothed out rather than
+// turned away.
+func (m *RateLimitMiddleware) LimitToken() gin.HandlerFunc {
+	if m.tokenQueue == nil {
+		return m.LimitClient()
+	}
+	return func(c *gin.Context) {
+		max, window := clientLimit(m.config.Load())
+		m.limitQueued(c, m.clientKey(c, "oauth"), max, window)
+	}
 }
 
 // LimitRefreshToken limits refresh token requests by user ID
 // This prevents abuse of refresh token endpoint
 func (m *RateLimitMiddleware) LimitRefreshToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		cfg := m.config.Load()
 		// Try to get user ID from refresh token claims if available
 		// Otherwise fall back to IP-based limiting
 		userID, exists := utils.GetUserIDFromContext(c)
 		if !exists {
 			// Fall back to IP-based limiting for unauthenticated refresh requests
-			m.LimitByIP("refresh", m.config.RefreshMax, m.config.RefreshWindow)(c)
+			m.LimitByIP("refresh", cfg.RefreshMax, cfg.RefreshWindow)(c)
 			return
 		}
 
 		key := fmt.Sprintf("ratelimit:refresh:%s", userID.String())
-
-		count, err := m.redis.IncrementRateLimit(c.Request.Context(), key, m.config.RefreshWindow)
-		if err != nil {
-			fmt.Printf("Rate limit error: %v\n", err)
-			c.Next()
-			return
-		}
-
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", m.config.RefreshMax))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", m.config.RefreshMax-int(count)))
-
-		if count > int64(m.config.RefreshMax) {
-			c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(models.ErrRateLimitExceeded))
-			c.Abort()
-			return
-		}
-
-		c.Next()
+		m.limit(c, key, cfg.RefreshMax, cfg.RefreshWindow)
 	}
 }
 
 // LimitByUserID limits requests by user ID (for authenticated endpoints)
 func (m *RateLimitMiddleware) LimitByUserID(endpoint string, max int, window time.Duration) gin.HandlerFunc {
+	return m.limitByUserID(endpoint, fixedLimit(max, window))
+}
+
+func (m *RateLimitMiddleware) limitByUserID(endpoint string, limit limitFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		max, window := limit(m.config.Load())
 		userID, exists := utils.GetUserIDFromContext(c)
 		if !exists {
 			// Fall back to IP-based limiting
@@ -114,24 +162,148 @@ func (m *RateLimitMiddleware) LimitByUserID(endpoint string, max int, window tim
 			return
 		}
 
-		key := fmt.Sprintf("ratelimit:%s:%s", userID.String(), endpoint)
+		m.limit(c, fmt.Sprintf("ratelimit:%s:%s", userID.String(), endpoint), max, window)
+	}
+}
+
+// LimitByClient limits requests by OAuth client ID, taken from HTTP Basic credentials or
+// the client_id form parameter. Requests naming no client are limited by IP address.
+func (m *RateLimitMiddleware) LimitByClient(endpoint string, max int, window time.Duration) gin.HandlerFunc {
+	return m.limitByClient(endpoint, fixedLimit(max, window))
+}
+
+func (m *RateLimitMiddleware) limitByClient(endpoint string, limit limitFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		max, window := limit(m.config.Load())
+		m.limit(c, m.clientKey(c, endpoint), max, window)
+	}
+}
+
+// clientKey returns the key requests of the OAuth client of a request are counted under,
+// or the key of its IP address when it names no client
+func (m *RateLimitMiddleware) clientKey(c *gin.Context, endpoint string) string {
+	clientID, _, ok := c.Request.BasicAuth()
+	if !ok || clientID == "" {
+		clientID = c.PostForm("client_id")
+	}
+	if clientID == "" || len(clientID) > maxClientIDLength {
+		return fmt.Sprintf("ratelimit:%s:%s", utils.GetClientIP(c), endpoint)
+	}
+	return fmt.Sprintf("ratelimit:client:%s:%s", clientID, endpoint)
+}
+
+// limit counts the request under key and rejects it with 429 once max requests were
+// made in the last window. A max of 0 or less disables the limit.
+func (m *RateLimitMiddleware) limit(c *gin.Context, key string, max int, window time.Duration) {
+	if max <= 0 {
+		c.Next()
+		return
+	}
+
+	result, err := m.limiter.SlidingWindowRateLimit(c.Request.Context(), key, max, window)
+	if err != nil {
+		// Log error but don't fail the request
+		m.logger.Warn("Rate limit check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		c.Next()
+		return
+	}
+
+	setRateLimitHeaders(c, max, result)
+	if !result.Allowed {
+		rejectRateLimited(c, result.RetryAfter)
+		return
+	}
 
-		count, err := m.redis.IncrementRateLimit(c.Request.Context(), key, window)
+	c.Next()
+}
+
+// limitQueued is limit for requests that may wait for capacity: a request over the limit
+// takes a slot in the token queue and retries once the window has room again, until it is
+// allowed or would wait longer than TokenQueueMaxWait. It is rejected at once when the
+// queue is full.
+func (m *RateLimitMiddleware) limitQueued(c *gin.Context, key string, max int, window time.Duration) {
+	if max <= 0 {
+		c.Next()
+		return
+	}
+
+	ctx := c.Request.Context()
+	deadline := time.Now().Add(m.config.Load().TokenQueueMaxWait)
+	queued := false
+	release := func() {
+		if queued {
+			<-m.tokenQueue
+			queued = false
+		}
+	}
+	defer release()
+
+	for {
+		result, err := m.limiter.SlidingWindowRateLimit(ctx, key, max, window)
 		if err != nil {
-			fmt.Printf("Rate limit error: %v\n", err)
+			// Log error but don't fail the request
+			m.logger.Warn("Rate limit check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			release()
+			c.Next()
+			return
+		}
+
+		setRateLimitHeaders(c, max, result)
+		if result.Allowed {
+			release()
 			c.Next()
 			return
 		}
 
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", max))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", max-int(count)))
+		if time.Until(deadline) < result.RetryAfter {
+			rejectRateLimited(c, result.RetryAfter)
+			return
+		}
+		if !queued {
+			select {
+			case m.tokenQueue <- struct{}{}:
+				queued = true
+			default:
+				rejectRateLimited(c, result.RetryAfter)
+				return
+			}
+		}
 
-		if count > int64(max) {
-			c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(models.ErrRateLimitExceeded))
+		// Up to a quarter of the wait is added at random so the waiters don't all retry
+		// at the same moment and collide again
+		wait := result.RetryAfter + time.Duration(rand.Int63n(int64(result.RetryAfter/4)+1))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			// The client is gone
+			timer.Stop()
 			c.Abort()
 			return
+		case <-timer.C:
 		}
+	}
+}
 
-		c.Next()
+// setRateLimitHeaders sets the limit and remaining requests headers
+func setRateLimitHeaders(c *gin.Context, max int, result *service.RateLimitResult) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(max))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+}
+
+// rejectRateLimited rejects a request over the limit with 429
+func rejectRateLimited(c *gin.Context, retryAfter time.Duration) {
+	endpoint := c.FullPath()
+	if endpoint == "" {
+		endpoint = "unmatched"
 	}
+	metrics.RecordRateLimitTrigger(metrics.TransportREST, endpoint)
+
+	// Whole seconds, rounded up so a client retrying on time is let through
+	c.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+	c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(models.ErrRateLimitExceeded))
+	c.Abort()
 }
@@ -1,21 +1,58 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/stretchr/testify/assert"
 )
 
-// The RateLimitMiddleware depends on *service.RedisService (concrete, requires Redis).
-// We test the constructor and middleware factory methods, and the fallback paths
-// that are deterministic without a real Redis.
+// The sliding window itself is a Lua script run by *service.RedisService (requires Redis).
+// We test the constructor and middleware factory methods, the key each limit counts
+// under and the responses, with a nil limiter or an in-memory fake.
+
+// fakeRateLimiter allows limit requests per key and records the keys it was asked about
+type fakeRateLimiter struct {
+	counts map[string]int
+	keys   []string
+	err    error
+}
+
+func (f *fakeRateLimiter) SlidingWindowRateLimit(_ context.Context, key string, limit int, window time.Duration) (*service.RateLimitResult, error) {
+	f.keys = append(f.keys, key)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.counts == nil {
+		f.counts = make(map[string]int)
+	}
+	if f.counts[key] >= limit {
+		return &service.RateLimitResult{RetryAfter: window / 2}, nil
+	}
+	f.counts[key]++
+	return &service.RateLimitResult{Allowed: true, Remaining: limit - f.counts[key]}, nil
+}
+
+func serveRateLimited(handler gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(handler)
+	r.Any("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
 
 func TestNewRateLimitMiddleware_ShouldCreateMiddleware(t *testing.T) {
 	cfg := &config.RateLimitConfig{
@@ -29,10 +66,10 @@ func TestNewRateLimitMiddleware_ShouldCreateMiddleware(t *testing.T) {
 		APIWindow:     time.Minute,
 	}
 
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	assert.NotNil(t, mw)
-	assert.Equal(t, cfg, mw.config)
+	assert.Equal(t, cfg, mw.config.Load())
 }
 
 func TestLimitByIP_ShouldContinue_WhenRedisErrors(t *testing.T) {
@@ -42,7 +79,7 @@ func TestLimitByIP_ShouldContinue_WhenRedisErrors(t *testing.T) {
 		APIMax:    100,
 		APIWindow: time.Minute,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -67,7 +104,7 @@ func TestLimitSignup_ShouldUseConfiguredLimits(t *testing.T) {
 		SignupMax:    5,
 		SignupWindow: time.Hour,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	handler := mw.LimitSignup()
 	assert.NotNil(t, handler, "LimitSignup should return a non-nil handler")
@@ -78,7 +115,7 @@ func TestLimitSignin_ShouldUseConfiguredLimits(t *testing.T) {
 		SigninMax:    10,
 		SigninWindow: 15 * time.Minute,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	handler := mw.LimitSignin()
 	assert.NotNil(t, handler, "LimitSignin should return a non-nil handler")
@@ -89,7 +126,7 @@ func TestLimitAPI_ShouldUseConfiguredLimits(t *testing.T) {
 		APIMax:    100,
 		APIWindow: time.Minute,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	handler := mw.LimitAPI()
 	assert.NotNil(t, handler, "LimitAPI should return a non-nil handler")
@@ -100,7 +137,7 @@ func TestLimitRefreshToken_ShouldUseConfiguredLimits(t *testing.T) {
 		RefreshMax:    30,
 		RefreshWindow: time.Hour,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	handler := mw.LimitRefreshToken()
 	assert.NotNil(t, handler, "LimitRefreshToken should return a non-nil handler")
@@ -109,7 +146,7 @@ func TestLimitRefreshToken_ShouldUseConfiguredLimits(t *testing.T) {
 func TestLimitByUserID_ShouldFallbackToIP_WhenNoUserInContext(t *testing.T) {
 	// Verify the fallback logic: when no user ID in context, falls back to IP-based
 	cfg := &config.RateLimitConfig{}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -132,7 +169,7 @@ func TestLimitByUserID_ShouldFallbackToIP_WhenNoUserInContext(t *testing.T) {
 
 func TestLimitByUserID_ShouldUseUserID_WhenUserInContext(t *testing.T) {
 	cfg := &config.RateLimitConfig{}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -159,7 +196,7 @@ func TestLimitRefreshToken_ShouldFallbackToIP_WhenNoUserInContext(t *testing.T)
 		RefreshMax:    30,
 		RefreshWindow: time.Hour,
 	}
-	mw := NewRateLimitMiddleware(nil, cfg)
+	mw := NewRateLimitMiddleware(nil, cfg, newTestLogger())
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -178,3 +215,171 @@ func TestLimitRefreshToken_ShouldFallbackToIP_WhenNoUserInContext(t *testing.T)
 	assert.Equal(t, http.StatusInternalServerError, w.Code,
 		"should fall back to IP-based limiting then panic on nil redis")
 }
+
+func TestLimitByIP_ShouldReturn429WithRetryAfter_WhenLimitExceeded(t *testing.T) {
+	limiter := &fakeRateLimiter{}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{}, newTestLogger())
+	handler := mw.LimitByIP("test", 2, 10*time.Second)
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		w = serveRateLimited(handler, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "ratelimit:192.168.1.1:test", limiter.keys[0])
+}
+
+func TestLimitByIP_ShouldSetRemaining_WhenAllowed(t *testing.T) {
+	mw := NewRateLimitMiddleware(&fakeRateLimiter{}, &config.RateLimitConfig{}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitByIP("test", 5, time.Minute), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestLimitByIP_ShouldContinue_WhenLimiterFails(t *testing.T) {
+	mw := NewRateLimitMiddleware(&fakeRateLimiter{err: errors.New("redis down")}, &config.RateLimitConfig{}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitByIP("test", 1, time.Minute), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetConfig_ShouldChangeLimitsOfRunningMiddleware(t *testing.T) {
+	limiter := &fakeRateLimiter{}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{SigninMax: 5, SigninWindow: time.Minute}, newTestLogger())
+	handler := mw.LimitSignin()
+
+	w := serveRateLimited(handler, httptest.NewRequest("GET", "/test", nil))
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
+
+	mw.SetConfig(&config.RateLimitConfig{SigninMax: 20, SigninWindow: time.Minute})
+
+	w = serveRateLimited(handler, httptest.NewRequest("GET", "/test", nil))
+	assert.Equal(t, "20", w.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestLimitUser_ShouldSkipLimiter_WhenDisabled(t *testing.T) {
+	limiter := &fakeRateLimiter{}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{UserMax: 0, UserWindow: time.Minute}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitUser(), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, limiter.keys)
+}
+
+func TestLimitClient_ShouldCountPerClient(t *testing.T) {
+	cases := []struct {
+		name    string
+		request func() *http.Request
+		key     string
+	}{
+		{"BasicAuth", func() *http.Request {
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("grant_type=client_credentials"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth("client-a", "secret")
+			return req
+		}, "ratelimit:client:client-a:oauth"},
+		{"FormParameter", func() *http.Request {
+			req := httptest.NewRequest("POST", "/test", strings.NewReader("client_id=client-b"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		}, "ratelimit:client:client-b:oauth"},
+		{"NoClient", func() *http.Request {
+			req := httptest.NewRequest("POST", "/test", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			return req
+		}, "ratelimit:10.0.0.1:oauth"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := &fakeRateLimiter{}
+			mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{ClientMax: 10, ClientWindow: time.Minute}, newTestLogger())
+
+			w := serveRateLimited(mw.LimitClient(), tc.request())
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, []string{tc.key}, limiter.keys)
+		})
+	}
+}
+
+// deniedThenAllowedLimiter denies the first denials requests with retryAfter, then allows
+type deniedThenAllowedLimiter struct {
+	denials    int
+	retryAfter time.Duration
+	calls      int
+}
+
+func (f *deniedThenAllowedLimiter) SlidingWindowRateLimit(_ context.Context, _ string, limit int, _ time.Duration) (*service.RateLimitResult, error) {
+	f.calls++
+	if f.calls <= f.denials {
+		return &service.RateLimitResult{RetryAfter: f.retryAfter}, nil
+	}
+	return &service.RateLimitResult{Allowed: true, Remaining: limit - 1}, nil
+}
+
+func tokenRequest() *http.Request {
+	req := httptest.NewRequest("POST", "/test", strings.NewReader("client_id=client-a"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestLimitToken_ShouldReject_WhenQueueDisabled(t *testing.T) {
+	limiter := &deniedThenAllowedLimiter{denials: 1, retryAfter: 10 * time.Millisecond}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{ClientMax: 1, ClientWindow: time.Minute}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitToken(), tokenRequest())
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, 1, limiter.calls)
+}
+
+func TestLimitToken_ShouldWaitForCapacity_WhenQueueEnabled(t *testing.T) {
+	limiter := &deniedThenAllowedLimiter{denials: 2, retryAfter: 10 * time.Millisecond}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{
+		ClientMax: 1, ClientWindow: time.Minute, TokenQueueMaxWait: time.Second, TokenQueueSize: 10,
+	}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitToken(), tokenRequest())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, limiter.calls)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+	assert.Len(t, mw.tokenQueue, 0, "the queue slot should be released")
+}
+
+func TestLimitToken_ShouldReject_WhenWaitExceedsMaxWait(t *testing.T) {
+	limiter := &deniedThenAllowedLimiter{denials: 1, retryAfter: 5 * time.Second}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{
+		ClientMax: 1, ClientWindow: time.Minute, TokenQueueMaxWait: 100 * time.Millisecond, TokenQueueSize: 10,
+	}, newTestLogger())
+
+	w := serveRateLimited(mw.LimitToken(), tokenRequest())
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Equal(t, 1, limiter.calls)
+}
+
+func TestLimitToken_ShouldReject_WhenQueueIsFull(t *testing.T) {
+	limiter := &deniedThenAllowedLimiter{denials: 1, retryAfter: 10 * time.Millisecond}
+	mw := NewRateLimitMiddleware(limiter, &config.RateLimitConfig{
+		ClientMax: 1, ClientWindow: time.Minute, TokenQueueMaxWait: time.Second, TokenQueueSize: 1,
+	}, newTestLogger())
+	mw.tokenQueue <- struct{}{}
+
+	w := serveRateLimited(mw.LimitToken(), tokenRequest())
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, 1, limiter.calls)
+}
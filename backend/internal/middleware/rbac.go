@@ -3,10 +3,13 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/google/uuid"
+
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 )
 
@@ -24,7 +27,7 @@ func NewRBACMiddleware(rbacService service.RBACServicer) *RBACMiddleware {
 
 // RequirePermission checks if the user has a specific permission
 func (m *RBACMiddleware) RequirePermission(permission string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		userID, ok := utils.GetUserIDFromContext(c)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
@@ -46,12 +49,15 @@ func (m *RBACMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementPermission,
+		Values: []string{permission},
+	})
 }
 
 // RequireAnyPermission checks if the user has any of the specified permissions
 func (m *RBACMiddleware) RequireAnyPermission(permissions ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		userID, ok := utils.GetUserIDFromContext(c)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
@@ -73,12 +79,15 @@ func (m *RBACMiddleware) RequireAnyPermission(permissions ...string) gin.Handler
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementPermission,
+		Values: permissions,
+	})
 }
 
 // RequireAllPermissions checks if the user has all of the specified permissions
 func (m *RBACMiddleware) RequireAllPermissions(permissions ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+	return authmap.Describe(func(c *gin.Context) {
 		userID, ok := utils.GetUserIDFromContext(c)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
@@ -100,5 +109,141 @@ func (m *RBACMiddleware) RequireAllPermissions(permissions ...string) gin.Handle
 		}
 
 		c.Next()
-	}
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementPermission,
+		Values: permissions,
+		All:    true,
+	})
+}
+
+// RequireAdminOrPermission lets through what RequireAdmin does (admins, application secrets
+// and API keys) and users without the admin role who hold a delegated admin permission.
+// Those are marked as delegated admins, see utils.IsDelegatedAdmin.
+func (m *RBACMiddleware) RequireAdminOrPermission(permission string) gin.HandlerFunc {
+	return authmap.Describe(func(c *gin.Context) {
+		if authType, exists := c.Get("auth_type"); exists {
+			if authType == "application" || authType == "api_key" {
+				c.Next()
+				return
+			}
+		}
+
+		roles, exists := utils.GetUserRolesFromContext(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.ErrUnauthorized))
+			c.Abort()
+			return
+		}
+		if utils.HasRole(roles, string(models.RoleAdmin)) {
+			c.Next()
+			return
+		}
+
+		userID, ok := utils.MustGetUserID(c)
+		if !ok {
+			return
+		}
+		hasPermission, err := m.rbacService.CheckUserPermission(c.Request.Context(), userID, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check permissions"})
+			c.Abort()
+			return
+		}
+		if !hasPermission {
+			c.JSON(http.StatusForbidden, models.NewErrorResponse(
+				models.NewAppError(http.StatusForbidden, "Admin access required"),
+			))
+			c.Abort()
+			return
+		}
+
+		c.Set(utils.DelegatedAdminKey, true)
+		c.Next()
+	}, models.AuthRequirement{
+		Kind:   models.AuthRequirementAdminOrPermission,
+		Values: []string{permission},
+		Bypass: []string{string(models.RoleAdmin), models.CredentialAPIKey, models.CredentialAppSecret},
+	})
+}
+
+// ProtectAdminUsers stops delegated admins from changing the user of the :id route
+// parameter when that user is an admin, another delegated admin, or holds a permission the
+// delegated admin lacks, e.g. through an inherited role, so that user management can't be
+// used to take over a more privileged account. Reads and requests by admins are not checked.
+func (m *RBACMiddleware) ProtectAdminUsers() gin.HandlerFunc {
+	return authmap.Describe(func(c *gin.Context) {
+		if !utils.IsDelegatedAdmin(c) || c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+		targetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			// Routes without a user ID, or an invalid one the handler rejects
+			c.Next()
+			return
+		}
+		callerID, ok := utils.MustGetUserID(c)
+		if !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+		roles, err := m.rbacService.GetUserRoles(ctx, targetID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check permissions"})
+			c.Abort()
+			return
+		}
+		for _, role := range roles {
+			if role.Name == string(models.RoleAdmin) {
+				rejectProtectedUser(c, "Admin users can only be managed by admins")
+				return
+			}
+		}
+
+		targetPermissions, err := m.rbacService.GetUserPermissions(ctx, targetID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check permissions"})
+			c.Abort()
+			return
+		}
+		if len(targetPermissions) == 0 {
+			c.Next()
+			return
+		}
+		callerPermissions, err := m.rbacService.GetUserPermissions(ctx, callerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to check permissions"})
+			c.Abort()
+			return
+		}
+		held := make(map[string]bool, len(callerPermissions))
+		for _, permission := range callerPermissions {
+			held[permission.Name] = true
+		}
+		for _, permission := range targetPermissions {
+			if models.IsDelegatedAdminPermission(permission.Name) {
+				rejectProtectedUser(c, "Delegated admins can only be managed by admins")
+				return
+			}
+			if !held[permission.Name] {
+				rejectProtectedUser(c, "Users with permissions you don't hold can only be managed by admins")
+				return
+			}
+		}
+
+		c.Next()
+	}, models.AuthRequirement{
+		Kind:        models.AuthRequirementPolicy,
+		Values:      []string{"protect_admin_users"},
+		Description: "Delegated admins can't change admins, other delegated admins or users holding permissions they lack",
+	})
+}
+
+// rejectProtectedUser aborts a delegated admin's request to change a protected user
+func rejectProtectedUser(c *gin.Context, message string) {
+	c.JSON(http.StatusForbidden, models.NewErrorResponse(
+		models.NewAppError(http.StatusForbidden, message),
+	))
+	c.Abort()
 }
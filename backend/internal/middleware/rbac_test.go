@@ -9,10 +9,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/authmap"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/service"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockRBACStore implements service.RBACStore for testing the RBAC middleware.
@@ -21,6 +23,8 @@ type mockRBACStore struct {
 	hasPermissionFn     func(ctx context.Context, userID uuid.UUID, permissionName string) (bool, error)
 	hasAnyPermissionFn  func(ctx context.Context, userID uuid.UUID, permissionNames []string) (bool, error)
 	hasAllPermissionsFn func(ctx context.Context, userID uuid.UUID, permissionNames []string) (bool, error)
+	getUserRolesFn      func(ctx context.Context, userID uuid.UUID) ([]models.Role, error)
+	getUserPermsFn      func(ctx context.Context, userID uuid.UUID) ([]models.Permission, error)
 }
 
 // --- PermissionRepository stubs ---
@@ -36,7 +40,7 @@ func (m *mockRBACStore) GetPermissionByName(ctx context.Context, name string) (*
 func (m *mockRBACStore) ListPermissions(ctx context.Context) ([]models.Permission, error) {
 	return nil, nil
 }
-func (m *mockRBACStore) UpdatePermission(ctx context.Context, id uuid.UUID, description string) error {
+func (m *mockRBACStore) UpdatePermission(ctx context.Context, id uuid.UUID, description string, condition *string) error {
 	return nil
 }
 func (m *mockRBACStore) DeletePermission(ctx context.Context, id uuid.UUID) error { return nil }
@@ -60,6 +64,39 @@ func (m *mockRBACStore) DeleteRole(ctx context.Context, id uuid.UUID) error { re
 func (m *mockRBACStore) SetRolePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
 	return nil
 }
+func (m *mockRBACStore) ListRoleParents(ctx context.Context) ([]models.RoleParent, error) {
+	return nil, nil
+}
+func (m *mockRBACStore) SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStore) ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStore) SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStore) CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error {
+	return nil
+}
+func (m *mockRBACStore) GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStore) GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStore) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	return nil, nil
+}
+func (m *mockRBACStore) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error {
+	return nil
+}
+func (m *mockRBACStore) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+func (m *mockRBACStore) SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return nil
+}
 func (m *mockRBACStore) GetRoleByNameAndApp(ctx context.Context, name string, appID *uuid.UUID) (*models.Role, error) {
 	return nil, nil
 }
@@ -75,6 +112,9 @@ func (m *mockRBACStore) RemoveRoleFromUser(ctx context.Context, userID, roleID u
 	return nil
 }
 func (m *mockRBACStore) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+	if m.getUserRolesFn != nil {
+		return m.getUserRolesFn(ctx, userID)
+	}
 	return nil, nil
 }
 func (m *mockRBACStore) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy uuid.UUID) error {
@@ -113,6 +153,9 @@ func (m *mockRBACStore) HasAllPermissions(ctx context.Context, userID uuid.UUID,
 }
 
 func (m *mockRBACStore) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]models.Permission, error) {
+	if m.getUserPermsFn != nil {
+		return m.getUserPermsFn(ctx, userID)
+	}
 	return nil, nil
 }
 
@@ -444,3 +487,214 @@ func TestRequireAllPermissions_ShouldReturn500_WhenPermissionCheckErrors(t *test
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+// --- RequireAdminOrPermission tests ---
+
+// serveDelegatedAdmin runs a request through RequireAdminOrPermission and ProtectAdminUsers
+// as the given user and reports whether the handler saw a delegated admin
+func serveDelegatedAdmin(mw *RBACMiddleware, userID uuid.UUID, roles []string, method, path string) (*httptest.ResponseRecorder, bool) {
+	delegated := false
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(utils.UserIDKey, userID)
+		c.Set(utils.UserRolesKey, roles)
+		c.Next()
+	})
+	users := r.Group("/users", mw.RequireAdminOrPermission(models.PermissionUsersManage), mw.ProtectAdminUsers())
+	handle := func(c *gin.Context) {
+		delegated = utils.IsDelegatedAdmin(c)
+		c.String(http.StatusOK, "ok")
+	}
+	users.GET("", handle)
+	users.PUT("/:id", handle)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w, delegated
+}
+
+func TestRequireAdminOrPermission_ShouldAllowAdmin_WithoutPermissionCheck(t *testing.T) {
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			t.Fatal("permission must not be checked for admins")
+			return false, nil
+		},
+	}
+
+	w, delegated := serveDelegatedAdmin(newTestRBACMiddleware(store), uuid.New(), []string{"admin"}, "GET", "/users")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, delegated)
+}
+
+func TestRequireAdminOrPermission_ShouldAllowDelegatedAdmin_WhenUserHasPermission(t *testing.T) {
+	userID := uuid.New()
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return uid == userID && perm == models.PermissionUsersManage, nil
+		},
+	}
+
+	w, delegated := serveDelegatedAdmin(newTestRBACMiddleware(store), userID, []string{"user"}, "GET", "/users")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, delegated)
+}
+
+func TestRequireAdminOrPermission_ShouldReturn403_WhenUserLacksPermission(t *testing.T) {
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return false, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), uuid.New(), []string{"user"}, "GET", "/users")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Admin access required")
+}
+
+// --- ProtectAdminUsers tests ---
+
+func TestProtectAdminUsers_ShouldReturn403_WhenDelegatedAdminChangesAdmin(t *testing.T) {
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return true, nil
+		},
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: "admin"}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), uuid.New(), []string{"user"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Admin users can only be managed by admins")
+}
+
+func TestProtectAdminUsers_ShouldAllow_WhenDelegatedAdminChangesRegularUser(t *testing.T) {
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return true, nil
+		},
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: "user"}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), uuid.New(), []string{"user"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestProtectAdminUsers_ShouldReturn403_WhenDelegatedAdminChangesDelegatedAdmin(t *testing.T) {
+	callerID := uuid.New()
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return true, nil
+		},
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: "helpdesk"}}, nil
+		},
+		getUserPermsFn: func(ctx context.Context, uid uuid.UUID) ([]models.Permission, error) {
+			return []models.Permission{{Name: models.PermissionUsersManage}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), callerID, []string{"helpdesk"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Delegated admins can only be managed by admins")
+}
+
+func TestProtectAdminUsers_ShouldReturn403_WhenTargetHoldsPermissionCallerLacks(t *testing.T) {
+	callerID := uuid.New()
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return true, nil
+		},
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			// A role inheriting admin's permissions without being named admin
+			return []models.Role{{Name: "operator"}}, nil
+		},
+		getUserPermsFn: func(ctx context.Context, uid uuid.UUID) ([]models.Permission, error) {
+			if uid == callerID {
+				return []models.Permission{{Name: models.PermissionUsersManage}, {Name: "users.read"}}, nil
+			}
+			return []models.Permission{{Name: "users.read"}, {Name: "settings.write"}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), callerID, []string{"helpdesk"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Users with permissions you don't hold can only be managed by admins")
+}
+
+func TestProtectAdminUsers_ShouldAllow_WhenTargetHoldsOnlyCallerPermissions(t *testing.T) {
+	callerID := uuid.New()
+	store := &mockRBACStore{
+		hasPermissionFn: func(ctx context.Context, uid uuid.UUID, perm string) (bool, error) {
+			return true, nil
+		},
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: "user"}}, nil
+		},
+		getUserPermsFn: func(ctx context.Context, uid uuid.UUID) ([]models.Permission, error) {
+			if uid == callerID {
+				return []models.Permission{{Name: models.PermissionUsersManage}, {Name: "users.read"}}, nil
+			}
+			return []models.Permission{{Name: "users.read"}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), callerID, []string{"helpdesk"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestProtectAdminUsers_ShouldAllow_WhenAdminChangesAdmin(t *testing.T) {
+	store := &mockRBACStore{
+		getUserRolesFn: func(ctx context.Context, uid uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: "admin"}}, nil
+		},
+	}
+
+	w, _ := serveDelegatedAdmin(newTestRBACMiddleware(store), uuid.New(), []string{"admin"}, "PUT", "/users/"+uuid.New().String())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRBACMiddleware_ShouldDescribeRequirementsInAuthorizationMap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newTestRBACMiddleware(&mockRBACStore{})
+	auth := NewAuthMiddleware(nil, nil)
+
+	router := gin.New()
+	admin := router.Group("/admin", auth.Authenticate())
+	admin.GET("/reports", m.RequireAllPermissions("reports:read", "reports:export"), func(c *gin.Context) {})
+	admin.PUT("/users/:id", m.RequireAdminOrPermission(models.PermissionUsersManage), m.ProtectAdminUsers(), func(c *gin.Context) {})
+	admin.GET("/stats", RequireAdmin(), func(c *gin.Context) {})
+
+	routes, err := authmap.Routes(router)
+	require.NoError(t, err)
+	require.Len(t, routes, 3)
+
+	assert.Equal(t, "/admin/reports", routes[0].Path)
+	assert.Equal(t, []string{models.CredentialJWT}, routes[0].Authentication)
+	assert.Equal(t, []models.AuthRequirement{{
+		Kind:   models.AuthRequirementPermission,
+		Values: []string{"reports:read", "reports:export"},
+		All:    true,
+	}}, routes[0].Requirements)
+
+	assert.Equal(t, "/admin/stats", routes[1].Path)
+	assert.Equal(t, models.AuthRequirementRole, routes[1].Requirements[0].Kind)
+	assert.Equal(t, []string{"admin"}, routes[1].Requirements[0].Values)
+
+	assert.Equal(t, "/admin/users/:id", routes[2].Path)
+	require.Len(t, routes[2].Requirements, 2)
+	assert.Equal(t, models.AuthRequirementAdminOrPermission, routes[2].Requirements[0].Kind)
+	assert.Equal(t, []string{models.PermissionUsersManage}, routes[2].Requirements[0].Values)
+	assert.Equal(t, models.AuthRequirementPolicy, routes[2].Requirements[1].Kind)
+}
@@ -15,7 +15,7 @@ func Recovery(log *logger.Logger) gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				// Log the panic
-				log.Error("Panic recovered", map[string]interface{}{
+				log.ErrorContext(c.Request.Context(), "Panic recovered", map[string]interface{}{
 					"error":  fmt.Sprintf("%v", err),
 					"path":   c.Request.URL.Path,
 					"method": c.Request.Method,
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID assigns each request an ID, taken from the X-Request-ID header when the client
+// or a proxy sent a usable one. The ID is echoed in the response and carried by the request
+// context, so every entry logged with it includes the request ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(utils.RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		utils.AddLogFields(c, map[string]interface{}{
+			"request_id": requestID,
+		})
+
+		c.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII IDs of reasonable length, so that client input
+// cannot forge log lines or bloat them
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string // empty expects a generated UUID
+	}{
+		{"generated when missing", "", ""},
+		{"kept from client", "req-123", "req-123"},
+		{"replaced when too long", strings.Repeat("a", maxRequestIDLength+1), ""},
+		{"replaced when not printable", "req\n123", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged map[string]interface{}
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/test", func(c *gin.Context) {
+				logged = logger.FieldsFromContext(c.Request.Context())
+				c.String(http.StatusOK, "ok")
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			r.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, requestID)
+			} else {
+				_, err := uuid.Parse(requestID)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, requestID, logged["request_id"])
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smilemakc/auth-gateway/internal/service"
+)
+
+// SLOTracking counts requests against the service level objectives of their route
+func SLOTracking(slos *service.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		slos.Record(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
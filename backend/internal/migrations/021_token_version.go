@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add token_version column: %w", err)
+		}
+
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO system_settings (key, value, description, setting_type, is_public) VALUES
+				('token_epoch', '0', 'Unix time; tokens issued at or before it are rejected', 'integer', false)
+			ON CONFLICT (key) DO NOTHING
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to insert token_epoch setting: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM system_settings WHERE key = 'token_epoch';
+			ALTER TABLE users
+			DROP COLUMN IF EXISTS token_version;
+		`)
+		return err
+	})
+}
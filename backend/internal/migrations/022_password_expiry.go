@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE roles
+			ADD COLUMN IF NOT EXISTS password_max_age_days INTEGER;
+
+			ALTER TABLE groups
+			ADD COLUMN IF NOT EXISTS password_max_age_days INTEGER;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add password_max_age_days columns: %w", err)
+		}
+
+		_, err = db.ExecContext(ctx, `
+			ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS password_expiry_warned_at TIMESTAMP;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add password expiry columns to users: %w", err)
+		}
+
+		// Existing accounts start their password age from now rather than from account creation,
+		// so enabling a policy does not lock out everyone at once
+		_, err = db.ExecContext(ctx, `
+			UPDATE users SET password_changed_at = CURRENT_TIMESTAMP
+			WHERE password_changed_at IS NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to backfill password_changed_at: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users
+			DROP COLUMN IF EXISTS password_expiry_warned_at,
+			DROP COLUMN IF EXISTS must_change_password;
+			ALTER TABLE groups
+			DROP COLUMN IF EXISTS password_max_age_days;
+			ALTER TABLE roles
+			DROP COLUMN IF EXISTS password_max_age_days;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS recovery_email VARCHAR(255),
+			ADD COLUMN IF NOT EXISTS recovery_email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add recovery email columns to users: %w", err)
+		}
+
+		// user_id is NULL for requests against unknown emails: they are stored like any other
+		// request so responses do not reveal whether an account exists, but can never progress
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS account_recovery_requests (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+				status VARCHAR(32) NOT NULL,
+				required_steps JSONB NOT NULL DEFAULT '[]'::jsonb,
+				completed_steps JSONB NOT NULL DEFAULT '[]'::jsonb,
+				failed_attempts INTEGER NOT NULL DEFAULT 0,
+				secret_hash VARCHAR(255) NOT NULL,
+				reason TEXT,
+				ip_address VARCHAR(64),
+				user_agent TEXT,
+				reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				reviewed_at TIMESTAMP,
+				review_note TEXT,
+				expires_at TIMESTAMP NOT NULL,
+				completed_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_account_recovery_requests_user_id ON account_recovery_requests(user_id);
+			CREATE INDEX IF NOT EXISTS idx_account_recovery_requests_status ON account_recovery_requests(status, created_at DESC);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create account_recovery_requests table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP TABLE IF EXISTS account_recovery_requests;
+			ALTER TABLE users
+			DROP COLUMN IF EXISTS recovery_email_verified,
+			DROP COLUMN IF EXISTS recovery_email;
+		`)
+		return err
+	})
+}
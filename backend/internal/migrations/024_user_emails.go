@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// A verified address belongs to exactly one account; unverified claims may overlap
+		// so nobody can block an address by adding it without access to the mailbox
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS user_emails (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				email VARCHAR(255) NOT NULL,
+				verified BOOLEAN NOT NULL DEFAULT FALSE,
+				verified_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, email)
+			);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_user_emails_verified_email ON user_emails(email) WHERE verified;
+			CREATE INDEX IF NOT EXISTS idx_user_emails_user_id ON user_emails(user_id);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create user_emails table: %w", err)
+		}
+
+		// Recovery emails are now chosen among verified secondary addresses
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO user_emails (user_id, email, verified, verified_at)
+			SELECT id, recovery_email, TRUE, CURRENT_TIMESTAMP
+			FROM users
+			WHERE recovery_email IS NOT NULL AND recovery_email_verified
+			ON CONFLICT DO NOTHING;
+
+			INSERT INTO user_emails (user_id, email)
+			SELECT id, recovery_email
+			FROM users
+			WHERE recovery_email IS NOT NULL AND NOT recovery_email_verified
+			ON CONFLICT DO NOTHING;
+
+			UPDATE users u
+			SET recovery_email = NULL, recovery_email_verified = FALSE
+			WHERE u.recovery_email IS NOT NULL AND NOT EXISTS (
+				SELECT 1 FROM user_emails ue
+				WHERE ue.user_id = u.id AND ue.email = u.recovery_email AND ue.verified
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to migrate recovery emails: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS user_emails;`)
+		return err
+	})
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Usernames and phones are login identifiers now, so stored values must match the
+		// normalized form used at sign-in. Rows that would collide are left for an admin to fix.
+		_, err := db.ExecContext(ctx, `
+			UPDATE users u
+			SET username = LOWER(TRIM(u.username))
+			WHERE u.username <> LOWER(TRIM(u.username))
+			AND NOT EXISTS (
+				SELECT 1 FROM users o
+				WHERE o.id <> u.id AND LOWER(TRIM(o.username)) = LOWER(TRIM(u.username))
+			);
+
+			UPDATE users SET phone = NULL WHERE TRIM(phone) = '';
+
+			UPDATE users u
+			SET phone = '+' || REGEXP_REPLACE(u.phone, '[^0-9]', '', 'g')
+			WHERE u.phone IS NOT NULL
+			AND u.phone <> '+' || REGEXP_REPLACE(u.phone, '[^0-9]', '', 'g')
+			AND NOT EXISTS (
+				SELECT 1 FROM users o
+				WHERE o.id <> u.id AND o.phone = '+' || REGEXP_REPLACE(u.phone, '[^0-9]', '', 'g')
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to normalize login identifiers: %w", err)
+		}
+
+		// Enforce case-insensitive username uniqueness once no collisions remain
+		_, err = db.ExecContext(ctx, `
+			DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM users GROUP BY LOWER(username) HAVING COUNT(*) > 1) THEN
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username));
+				END IF;
+			END $$;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create username index: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP INDEX IF EXISTS idx_users_username_lower;`)
+		return err
+	})
+}
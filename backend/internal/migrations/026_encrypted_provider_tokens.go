@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Provider tokens used to be stored in plain text; they are now only kept
+		// encrypted and when enabled, so drop the old ones. Users get new tokens
+		// on their next sign-in with the provider.
+		_, err := db.ExecContext(ctx, `
+			UPDATE oauth_accounts
+			SET access_token = NULL, refresh_token = NULL, token_expires_at = NULL
+			WHERE access_token IS NOT NULL OR refresh_token IS NOT NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to clear plain text provider tokens: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		// Cleared tokens cannot be restored
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// One uploaded logo per client, looked up by content hash when served
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS oauth_client_logos (
+				client_id UUID PRIMARY KEY REFERENCES oauth_clients(id) ON DELETE CASCADE,
+				hash VARCHAR(64) NOT NULL,
+				content_type VARCHAR(64) NOT NULL,
+				content BYTEA NOT NULL,
+				size INTEGER NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_oauth_client_logos_hash ON oauth_client_logos(hash);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create oauth_client_logos table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS oauth_client_logos;`)
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS oauth_scope_groups (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				name VARCHAR(100) NOT NULL UNIQUE,
+				display_name VARCHAR(100) NOT NULL,
+				description TEXT,
+				scopes JSONB NOT NULL DEFAULT '[]',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create oauth_scope_groups table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS oauth_scope_groups;`)
+		return err
+	})
+}
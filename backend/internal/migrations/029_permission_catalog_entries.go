@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS permission_catalog_entries (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				source VARCHAR(100) NOT NULL,
+				resource VARCHAR(50) NOT NULL,
+				action VARCHAR(50) NOT NULL,
+				description TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (source, resource, action)
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create permission_catalog_entries table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS permission_catalog_entries;`)
+		return err
+	})
+}
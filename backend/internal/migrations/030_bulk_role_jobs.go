@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS bulk_role_jobs (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				operation VARCHAR(20) NOT NULL,
+				role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+				application_id UUID REFERENCES applications(id) ON DELETE CASCADE,
+				query VARCHAR(255),
+				group_id UUID,
+				user_ids JSONB,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				total INTEGER NOT NULL DEFAULT 0,
+				processed INTEGER NOT NULL DEFAULT 0,
+				succeeded INTEGER NOT NULL DEFAULT 0,
+				failed INTEGER NOT NULL DEFAULT 0,
+				failures JSONB,
+				error TEXT,
+				created_by UUID NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				completed_at TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_bulk_role_jobs_created_at ON bulk_role_jobs(created_at DESC);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create bulk_role_jobs table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS bulk_role_jobs;`)
+		return err
+	})
+}
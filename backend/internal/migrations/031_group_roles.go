@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS group_roles (
+				group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+				role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (group_id, role_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_group_roles_role_id ON group_roles(role_id);
+
+			ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS granted_by_group BOOLEAN NOT NULL DEFAULT false;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create group_roles table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM user_roles WHERE granted_by_group = true;
+			ALTER TABLE user_roles DROP COLUMN IF EXISTS granted_by_group;
+			DROP TABLE IF EXISTS group_roles;
+		`)
+		return err
+	})
+}
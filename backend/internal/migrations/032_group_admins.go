@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS group_admins (
+				group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (group_id, user_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_group_admins_user_id ON group_admins(user_id);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create group_admins table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS group_admins;`)
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS org_usage_report_settings (
+				group_id UUID PRIMARY KEY REFERENCES groups(id) ON DELETE CASCADE,
+				licensed_seats INTEGER,
+				recipients JSONB NOT NULL DEFAULT '[]',
+				frequency VARCHAR(20) NOT NULL DEFAULT 'monthly',
+				last_sent_at TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create org_usage_report_settings table: %w", err)
+		}
+
+		// Activity lookups for the active users metric
+		_, err = db.ExecContext(ctx, `
+			CREATE INDEX IF NOT EXISTS idx_sessions_user_last_active ON sessions(user_id, last_active_at);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create sessions activity index: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_sessions_user_last_active;
+			DROP TABLE IF EXISTS org_usage_report_settings;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS group_domains (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+				domain VARCHAR(253) NOT NULL UNIQUE,
+				verification_token VARCHAR(100) NOT NULL,
+				auto_join BOOLEAN NOT NULL DEFAULT TRUE,
+				verified_at TIMESTAMP,
+				created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_group_domains_group ON group_domains(group_id);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create group_domains table: %w", err)
+		}
+
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO system_settings (key, value, description, setting_type, is_public) VALUES
+				('signup_policy', '{"allowed_domains":[],"blocked_domains":[],"block_disposable":false,"invite_only":false}',
+				 'Sign-up restrictions: email domain allow/block lists, disposable email detection, invite-only mode', 'json', false)
+			ON CONFLICT (key) DO NOTHING;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create signup_policy setting: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM system_settings WHERE key = 'signup_policy';
+			DROP TABLE IF EXISTS group_domains;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS email_verification_reminders_sent INTEGER NOT NULL DEFAULT 0;
+
+			CREATE INDEX IF NOT EXISTS idx_users_unverified_created_at
+			ON users(created_at) WHERE email_verified = FALSE;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add email verification reminder columns: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_users_unverified_created_at;
+			ALTER TABLE users
+			DROP COLUMN IF EXISTS email_verification_reminders_sent;
+		`)
+		return err
+	})
+}
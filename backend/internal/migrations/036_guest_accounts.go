@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS is_guest BOOLEAN NOT NULL DEFAULT FALSE;
+
+			CREATE INDEX IF NOT EXISTS idx_users_guest_created_at
+			ON users(created_at) WHERE is_guest = TRUE;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add guest account column: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_users_guest_created_at;
+			ALTER TABLE users
+			DROP COLUMN IF EXISTS is_guest;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_clients
+			ADD COLUMN IF NOT EXISTS backchannel_logout_uri TEXT NOT NULL DEFAULT '';
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add backchannel_logout_uri column: %w", err)
+		}
+
+		_, err = db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS backchannel_logout_deliveries (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+				user_id UUID NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_error TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_backchannel_logout_deliveries_due
+			ON backchannel_logout_deliveries(next_attempt_at) WHERE status = 'pending';
+
+			CREATE INDEX IF NOT EXISTS idx_backchannel_logout_deliveries_updated_at
+			ON backchannel_logout_deliveries(updated_at) WHERE status <> 'pending';
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create backchannel_logout_deliveries table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP TABLE IF EXISTS backchannel_logout_deliveries;
+			ALTER TABLE oauth_clients
+			DROP COLUMN IF EXISTS backchannel_logout_uri;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_clients
+			ADD COLUMN IF NOT EXISTS post_logout_redirect_uris JSONB NOT NULL DEFAULT '[]',
+			ADD COLUMN IF NOT EXISTS frontchannel_logout_uri TEXT NOT NULL DEFAULT '';
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add logout columns to oauth_clients: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_clients
+			DROP COLUMN IF EXISTS frontchannel_logout_uri,
+			DROP COLUMN IF EXISTS post_logout_redirect_uris;
+		`)
+		return err
+	})
+}
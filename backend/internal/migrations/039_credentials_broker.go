@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS credential_targets (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				name VARCHAR(100) NOT NULL UNIQUE,
+				kind VARCHAR(20) NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				permission VARCHAR(100) NOT NULL,
+				postgres_role VARCHAR(63) NOT NULL DEFAULT '',
+				ssh_principals JSONB NOT NULL DEFAULT '[]',
+				max_ttl_seconds INTEGER NOT NULL DEFAULT 0,
+				is_active BOOLEAN NOT NULL DEFAULT true,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE IF NOT EXISTS credential_issuances (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				target_id UUID REFERENCES credential_targets(id) ON DELETE SET NULL,
+				target_name VARCHAR(100) NOT NULL,
+				user_id UUID NOT NULL,
+				kind VARCHAR(20) NOT NULL,
+				username VARCHAR(255) NOT NULL,
+				serial BIGINT NOT NULL DEFAULT 0,
+				ip_address VARCHAR(45),
+				expires_at TIMESTAMP NOT NULL,
+				revoked_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_credential_issuances_created_at
+			ON credential_issuances(created_at DESC);
+
+			CREATE INDEX IF NOT EXISTS idx_credential_issuances_user_id
+			ON credential_issuances(user_id);
+
+			CREATE INDEX IF NOT EXISTS idx_credential_issuances_expired_roles
+			ON credential_issuances(expires_at) WHERE kind = 'postgres' AND revoked_at IS NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create credentials broker tables: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP TABLE IF EXISTS credential_issuances;
+			DROP TABLE IF EXISTS credential_targets;
+		`)
+		return err
+	})
+}
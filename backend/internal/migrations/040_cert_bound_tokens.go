@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_clients
+			ADD COLUMN IF NOT EXISTS tls_client_certificate_bound_access_tokens BOOLEAN NOT NULL DEFAULT FALSE;
+
+			ALTER TABLE oauth_access_tokens
+			ADD COLUMN IF NOT EXISTS cert_thumbprint VARCHAR(64);
+
+			ALTER TABLE oauth_refresh_tokens
+			ADD COLUMN IF NOT EXISTS cert_thumbprint VARCHAR(64);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add certificate binding columns: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_refresh_tokens DROP COLUMN IF EXISTS cert_thumbprint;
+			ALTER TABLE oauth_access_tokens DROP COLUMN IF EXISTS cert_thumbprint;
+			ALTER TABLE oauth_clients DROP COLUMN IF EXISTS tls_client_certificate_bound_access_tokens;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS risk_policies (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				name VARCHAR(100) NOT NULL UNIQUE,
+				description TEXT NOT NULL DEFAULT '',
+				application_id UUID REFERENCES applications(id) ON DELETE CASCADE,
+				weights JSONB NOT NULL DEFAULT '{}',
+				step_up_threshold INTEGER NOT NULL DEFAULT 0,
+				block_threshold INTEGER NOT NULL DEFAULT 0,
+				step_up_fallback VARCHAR(10) NOT NULL DEFAULT 'allow',
+				max_travel_speed_kmh INTEGER NOT NULL DEFAULT 1000,
+				velocity_max_attempts INTEGER NOT NULL DEFAULT 10,
+				velocity_window_seconds INTEGER NOT NULL DEFAULT 600,
+				is_active BOOLEAN NOT NULL DEFAULT true,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			-- One policy per application, and one for every other application
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_policies_application
+			ON risk_policies(application_id) WHERE application_id IS NOT NULL;
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_policies_global
+			ON risk_policies((true)) WHERE application_id IS NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create risk_policies table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS risk_policies;`)
+		return err
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_clients
+			ADD COLUMN IF NOT EXISTS require_refresh_nonce BOOLEAN NOT NULL DEFAULT FALSE;
+
+			ALTER TABLE oauth_refresh_tokens
+			ADD COLUMN IF NOT EXISTS nonce_hash VARCHAR(64);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add refresh nonce columns: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE oauth_refresh_tokens DROP COLUMN IF EXISTS nonce_hash;
+			ALTER TABLE oauth_clients DROP COLUMN IF EXISTS require_refresh_nonce;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE account_lockouts
+			ADD COLUMN IF NOT EXISTS lockout_count INTEGER NOT NULL DEFAULT 0;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add account_lockouts.lockout_count: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE account_lockouts DROP COLUMN IF EXISTS lockout_count;
+		`)
+		return err
+	})
+}
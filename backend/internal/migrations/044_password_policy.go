@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// An empty policy keeps the PASSWORD_* environment defaults until an admin changes it
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO system_settings (key, value, description, setting_type, is_public) VALUES
+				('password_policy', '{}',
+				 'Password policy: length, character classes, history, dictionary and breach checks', 'json', false),
+				('password_policy_legacy', '{}',
+				 'Values last saved through the original /api/admin/system/password-policy endpoint', 'json', false)
+			ON CONFLICT (key) DO NOTHING;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create password_policy setting: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM system_settings WHERE key IN ('password_policy', 'password_policy_legacy');
+		`)
+		return err
+	})
+}
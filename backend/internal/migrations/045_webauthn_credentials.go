@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS webauthn_credentials (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				name VARCHAR(100) NOT NULL,
+				credential_id BYTEA NOT NULL UNIQUE,
+				public_key BYTEA NOT NULL,
+				attestation_type VARCHAR(50) NOT NULL DEFAULT '',
+				transports JSONB NOT NULL DEFAULT '[]',
+				aaguid BYTEA,
+				sign_count BIGINT NOT NULL DEFAULT 0,
+				backup_eligible BOOLEAN NOT NULL DEFAULT false,
+				backup_state BOOLEAN NOT NULL DEFAULT false,
+				last_used_at TIMESTAMP,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+			-- Set while the user has at least one security key, so sign-in knows 2FA is on without a lookup
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS webauthn_enabled BOOLEAN NOT NULL DEFAULT false;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create webauthn_credentials table: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users DROP COLUMN IF EXISTS webauthn_enabled;
+			DROP TABLE IF EXISTS webauthn_credentials;
+		`)
+		return err
+	})
+}
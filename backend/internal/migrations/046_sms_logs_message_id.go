@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Delivery status callbacks look messages up by the ID the provider assigned
+		_, err := db.ExecContext(ctx, `
+			CREATE INDEX IF NOT EXISTS idx_sms_logs_provider_message_id ON sms_logs(provider, message_id) WHERE message_id IS NOT NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create sms_logs message_id index: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP INDEX IF EXISTS idx_sms_logs_provider_message_id;`)
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// is_active stays as a mirror of state = 'active' so existing filters keep working;
+		// accounts that were switched off before lifecycle states existed become deactivated
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS state VARCHAR(20) NOT NULL DEFAULT 'active';
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS state_changed_at TIMESTAMP;
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP;
+
+			UPDATE users SET state = 'deactivated' WHERE is_active = false AND state = 'active';
+
+			ALTER TABLE users DROP CONSTRAINT IF EXISTS users_state_check;
+			ALTER TABLE users ADD CONSTRAINT users_state_check
+				CHECK (state IN ('invited', 'active', 'suspended', 'deactivated', 'deleted'));
+
+			CREATE INDEX IF NOT EXISTS idx_users_state ON users(state);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add user lifecycle state: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_users_state;
+			ALTER TABLE users DROP CONSTRAINT IF EXISTS users_state_check;
+			ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
+			ALTER TABLE users DROP COLUMN IF EXISTS state_changed_at;
+			ALTER TABLE users DROP COLUMN IF EXISTS state;
+		`)
+		return err
+	})
+}
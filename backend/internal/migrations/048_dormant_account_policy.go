@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// The policy starts disabled; admins can review the dry-run report before enabling it
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS dormant_notified_at TIMESTAMP;
+
+			INSERT INTO system_settings (key, value, description, setting_type, is_public) VALUES
+				('dormant_account_policy',
+				 '{"enabled":false,"inactive_days":365,"notice_days":30,"action":"suspend","excluded_roles":[],"excluded_domains":[],"excluded_user_ids":[]}',
+				 'Dormant accounts: inactivity period, notice period, action and exclusions', 'json', false)
+			ON CONFLICT (key) DO NOTHING;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add dormant account policy: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM system_settings WHERE key = 'dormant_account_policy';
+			ALTER TABLE users DROP COLUMN IF EXISTS dormant_notified_at;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Sign in with Apple signs its client secret with a key of the team; Microsoft Entra ID
+		// endpoints depend on the tenant
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE application_oauth_providers ADD COLUMN IF NOT EXISTS team_id VARCHAR(50) DEFAULT '';
+			ALTER TABLE application_oauth_providers ADD COLUMN IF NOT EXISTS key_id VARCHAR(50) DEFAULT '';
+			ALTER TABLE application_oauth_providers ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) DEFAULT '';
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add oauth provider settings: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE application_oauth_providers DROP COLUMN IF EXISTS tenant_id;
+			ALTER TABLE application_oauth_providers DROP COLUMN IF EXISTS key_id;
+			ALTER TABLE application_oauth_providers DROP COLUMN IF EXISTS team_id;
+		`)
+		return err
+	})
+}
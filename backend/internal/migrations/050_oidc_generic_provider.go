@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Generic OpenID Connect providers are configured by their issuer and the claims
+		// that describe the user
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE application_oauth_providers ADD COLUMN IF NOT EXISTS issuer VARCHAR(500) DEFAULT '';
+			ALTER TABLE application_oauth_providers ADD COLUMN IF NOT EXISTS claim_mapping JSONB DEFAULT '{}'::jsonb;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add oidc provider settings: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE application_oauth_providers DROP COLUMN IF EXISTS claim_mapping;
+			ALTER TABLE application_oauth_providers DROP COLUMN IF EXISTS issuer;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Delegated admin permissions: each grants one area of the admin API to users
+		// without the admin role. The admin role gets them too, so the permission matrix
+		// shows it can do everything.
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO permissions (name, resource, action, description)
+			SELECT v.name, v.resource, v.action, v.description
+			FROM (VALUES
+				('users:manage', 'users', 'manage', 'Manage users in the admin API (no role assignment, no admin accounts)'),
+				('webhooks:manage', 'webhooks', 'manage', 'Manage webhooks in the admin API'),
+				('oauth_clients:manage', 'oauth_clients', 'manage', 'Manage OAuth clients in the admin API'),
+				('audit:read', 'audit', 'read', 'Read audit logs in the admin API')
+			) AS v(name, resource, action, description)
+			WHERE NOT EXISTS (
+				SELECT 1 FROM permissions p WHERE p.name = v.name AND p.application_id IS NULL
+			);
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id
+			FROM roles r
+			CROSS JOIN permissions p
+			WHERE r.name = 'admin' AND r.application_id IS NULL
+			  AND p.name IN ('users:manage', 'webhooks:manage', 'oauth_clients:manage', 'audit:read')
+			  AND p.application_id IS NULL
+			ON CONFLICT (role_id, permission_id) DO NOTHING;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create delegated admin permissions: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DELETE FROM role_permissions
+			WHERE permission_id IN (
+				SELECT id FROM permissions
+				WHERE name IN ('users:manage', 'webhooks:manage', 'oauth_clients:manage', 'audit:read')
+				  AND application_id IS NULL
+			);
+
+			DELETE FROM permissions
+			WHERE name IN ('users:manage', 'webhooks:manage', 'oauth_clients:manage', 'audit:read')
+			  AND application_id IS NULL;
+		`)
+		return err
+	})
+}
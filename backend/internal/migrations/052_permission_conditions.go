@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Conditional permissions: NULL keeps existing permissions unconditional
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE permissions ADD COLUMN IF NOT EXISTS condition TEXT;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add permission conditions: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE permissions DROP COLUMN IF EXISTS condition;
+		`)
+		return err
+	})
+}
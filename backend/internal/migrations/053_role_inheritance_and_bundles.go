@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS role_parents (
+				role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+				parent_role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (role_id, parent_role_id),
+				CHECK (role_id <> parent_role_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_role_parents_parent_role_id ON role_parents(parent_role_id);
+
+			CREATE TABLE IF NOT EXISTS permission_bundles (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				name VARCHAR(100) NOT NULL UNIQUE,
+				display_name VARCHAR(100) NOT NULL,
+				description TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE IF NOT EXISTS bundle_permissions (
+				bundle_id UUID NOT NULL REFERENCES permission_bundles(id) ON DELETE CASCADE,
+				permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+				PRIMARY KEY (bundle_id, permission_id)
+			);
+
+			CREATE TABLE IF NOT EXISTS role_bundles (
+				role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+				bundle_id UUID NOT NULL REFERENCES permission_bundles(id) ON DELETE CASCADE,
+				granted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (role_id, bundle_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_role_bundles_bundle_id ON role_bundles(bundle_id);
+
+			-- Permissions each role grants: its own, those of its bundles and those of every
+			-- role it inherits from. UNION stops the recursion on a cycle; the service rejects
+			-- cycles, this only keeps a bad row from hanging permission checks.
+			CREATE OR REPLACE VIEW effective_role_permissions AS
+			WITH RECURSIVE role_ancestors (role_id, ancestor_id) AS (
+				SELECT id, id FROM roles
+				UNION
+				SELECT ra.role_id, rp.parent_role_id
+				FROM role_ancestors ra
+				INNER JOIN role_parents rp ON rp.role_id = ra.ancestor_id
+			)
+			SELECT DISTINCT ra.role_id, granted.permission_id
+			FROM role_ancestors ra
+			INNER JOIN (
+				SELECT role_id, permission_id FROM role_permissions
+				UNION
+				SELECT rb.role_id, bp.permission_id
+				FROM role_bundles rb
+				INNER JOIN bundle_permissions bp ON bp.bundle_id = rb.bundle_id
+			) granted ON granted.role_id = ra.ancestor_id;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create role inheritance and permission bundles: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP VIEW IF EXISTS effective_role_permissions;
+			DROP TABLE IF EXISTS role_bundles;
+			DROP TABLE IF EXISTS bundle_permissions;
+			DROP TABLE IF EXISTS permission_bundles;
+			DROP TABLE IF EXISTS role_parents;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS oauth_client_webhooks (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+				url VARCHAR(500) NOT NULL,
+				secret_key VARCHAR(64) NOT NULL,
+				events JSONB NOT NULL DEFAULT '[]',
+				is_active BOOLEAN NOT NULL DEFAULT TRUE,
+				created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_triggered_at TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_oauth_client_webhooks_client_id ON oauth_client_webhooks(client_id);
+
+			CREATE TABLE IF NOT EXISTS oauth_client_webhook_deliveries (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				webhook_id UUID NOT NULL REFERENCES oauth_client_webhooks(id) ON DELETE CASCADE,
+				event_type VARCHAR(100) NOT NULL,
+				payload JSONB NOT NULL,
+				status VARCHAR(20) NOT NULL,
+				http_status_code INTEGER,
+				response_body TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				completed_at TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_oauth_client_webhook_deliveries_webhook ON oauth_client_webhook_deliveries(webhook_id, created_at DESC);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create oauth client webhooks: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP TABLE IF EXISTS oauth_client_webhook_deliveries;
+			DROP TABLE IF EXISTS oauth_client_webhooks;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS background_jobs (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				type VARCHAR(100) NOT NULL,
+				payload JSONB NOT NULL DEFAULT '{}',
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				max_attempts INTEGER NOT NULL DEFAULT 5,
+				run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_error TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				completed_at TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_background_jobs_due ON background_jobs(run_at) WHERE status IN ('pending', 'running');
+			CREATE INDEX IF NOT EXISTS idx_background_jobs_status ON background_jobs(status, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_background_jobs_type ON background_jobs(type, created_at DESC);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create background jobs: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS background_jobs;`)
+		return err
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS outbox_events (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				kind VARCHAR(50) NOT NULL,
+				payload JSONB NOT NULL DEFAULT '{}',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_error TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_outbox_events_available ON outbox_events(available_at);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create outbox events: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS outbox_events;`)
+		return err
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// The trace of the request that wrote the log; NULL for logs written outside a trace
+		_, err := db.ExecContext(ctx, `
+			ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS trace_id VARCHAR(32);
+
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_trace_id ON audit_logs(trace_id) WHERE trace_id IS NOT NULL;
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add audit log trace IDs: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_audit_logs_trace_id;
+			ALTER TABLE audit_logs DROP COLUMN IF EXISTS trace_id;
+		`)
+		return err
+	})
+}
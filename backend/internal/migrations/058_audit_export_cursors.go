@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// How far each audit export sink has been scheduled; instances advance it in turn
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS audit_export_cursors (
+				sink VARCHAR(20) PRIMARY KEY,
+				scheduled_until TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create audit export cursors: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS audit_export_cursors;`)
+		return err
+	})
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Filters are combined with a time range and results come newest first, so the
+		// filtered columns are indexed together with created_at. Free-text search matches
+		// substrings, which a trigram index serves; its expression must stay the same as
+		// auditSearchText in the audit repository.
+		_, err := db.ExecContext(ctx, `
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_user_created ON audit_logs(user_id, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_action_created ON audit_logs(action, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_ip_created ON audit_logs(ip_address, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_failures ON audit_logs(created_at) WHERE status <> 'success';
+			CREATE INDEX IF NOT EXISTS idx_audit_logs_search ON audit_logs USING GIN (
+				LOWER(COALESCE(action, '') || ' ' || COALESCE(resource_type, '') || ' ' ||
+					COALESCE(resource_id, '') || ' ' || COALESCE(ip_address, '') || ' ' ||
+					COALESCE(user_agent, '') || ' ' || COALESCE(city, '') || ' ' ||
+					COALESCE(details::text, '')) gin_trgm_ops
+			);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create audit log search indexes: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_audit_logs_search;
+			DROP INDEX IF EXISTS idx_audit_logs_failures;
+			DROP INDEX IF EXISTS idx_audit_logs_ip_created;
+			DROP INDEX IF EXISTS idx_audit_logs_action_created;
+			DROP INDEX IF EXISTS idx_audit_logs_user_created;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Exports of a user's data, generated in the background and kept until they expire
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS user_data_exports (
+				id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				format VARCHAR(10) NOT NULL,
+				status VARCHAR(20) NOT NULL,
+				content BYTEA,
+				size BIGINT NOT NULL DEFAULT 0,
+				error TEXT,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				completed_at TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_user_data_exports_user ON user_data_exports(user_id, created_at DESC);
+			CREATE INDEX IF NOT EXISTS idx_user_data_exports_expires ON user_data_exports(expires_at);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create user data exports: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS user_data_exports;`)
+		return err
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// OIDC signing keys generated by automatic rotation, encrypted with ENCRYPTION_KEY
+		_, err := db.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS oidc_signing_keys (
+				kid VARCHAR(255) PRIMARY KEY,
+				algorithm VARCHAR(10) NOT NULL,
+				private_key TEXT NOT NULL,
+				activates_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_oidc_signing_keys_activates ON oidc_signing_keys(activates_at);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create oidc signing keys: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS oidc_signing_keys;`)
+		return err
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// Per-client custom claims added to ID tokens and userinfo responses
+		_, err := db.ExecContext(ctx, `ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS claims_mapping JSONB;`)
+		if err != nil {
+			return fmt.Errorf("failed to add oauth client claims mapping: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `ALTER TABLE oauth_clients DROP COLUMN IF EXISTS claims_mapping;`)
+		return err
+	})
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		// The admin user listing runs newest first and continues after the last user seen, so
+		// (created_at, id) is indexed in that order. Text search matches substrings of the
+		// lowercased columns whereUserMatches in the user repository uses, which trigram
+		// indexes serve; the role filter looks up the users of a role.
+		_, err := db.ExecContext(ctx, `
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+			CREATE INDEX IF NOT EXISTS idx_users_created_id ON users(created_at DESC, id DESC);
+			CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (LOWER(username) gin_trgm_ops);
+			CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN (LOWER(full_name) gin_trgm_ops);
+			CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (LOWER(email) gin_trgm_ops);
+			CREATE INDEX IF NOT EXISTS idx_users_two_factor ON users(created_at) WHERE totp_enabled OR webauthn_enabled;
+			CREATE INDEX IF NOT EXISTS idx_user_emails_email_trgm ON user_emails USING GIN (LOWER(email) gin_trgm_ops);
+			CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role_id);
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to create user search indexes: %w", err)
+		}
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.ExecContext(ctx, `
+			DROP INDEX IF EXISTS idx_user_roles_role;
+			DROP INDEX IF EXISTS idx_user_emails_email_trgm;
+			DROP INDEX IF EXISTS idx_users_two_factor;
+			DROP INDEX IF EXISTS idx_users_email_trgm;
+			DROP INDEX IF EXISTS idx_users_full_name_trgm;
+			DROP INDEX IF EXISTS idx_users_username_trgm;
+			DROP INDEX IF EXISTS idx_users_created_id;
+		`)
+		return err
+	})
+}
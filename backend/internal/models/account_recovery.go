@@ -0,0 +1,202 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Account recovery request statuses
+const (
+	RecoveryStatusPendingVerification = "pending_verification"
+	RecoveryStatusPendingReview       = "pending_review"
+	RecoveryStatusApproved            = "approved"
+	RecoveryStatusRejected            = "rejected"
+	RecoveryStatusCompleted           = "completed"
+	RecoveryStatusCancelled           = "cancelled"
+	RecoveryStatusExpired             = "expired"
+)
+
+// Identity proofing steps of an account recovery request
+const (
+	RecoveryStepSecondaryEmail = "secondary_email"
+	RecoveryStepSMS            = "sms"
+	RecoveryStepAdminReview    = "admin_review"
+)
+
+// Account recovery events reported to the user's contact points
+const (
+	RecoveryEventStarted   = "started"
+	RecoveryEventApproved  = "approved"
+	RecoveryEventRejected  = "rejected"
+	RecoveryEventCompleted = "completed"
+)
+
+// IsValidRecoveryStep reports whether step is a known identity proofing step
+func IsValidRecoveryStep(step string) bool {
+	switch step {
+	case RecoveryStepSecondaryEmail, RecoveryStepSMS, RecoveryStepAdminReview:
+		return true
+	default:
+		return false
+	}
+}
+
+// AccountRecoveryRequest tracks the recovery of an account whose owner lost both
+// their password and their second factor
+type AccountRecoveryRequest struct {
+	bun.BaseModel `bun:"table:account_recovery_requests,alias:arr"`
+
+	// Unique recovery request identifier
+	ID uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Account being recovered; null for requests against unknown emails, which never progress
+	UserID *uuid.UUID `json:"user_id,omitempty" bun:"user_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Request status
+	Status string `json:"status" bun:"status,notnull" example:"pending_verification"`
+	// Proofing steps that must be completed
+	RequiredSteps []string `json:"required_steps" bun:"required_steps,type:jsonb,default:'[]'" example:"secondary_email,admin_review"`
+	// Proofing steps completed so far
+	CompletedSteps []string `json:"completed_steps" bun:"completed_steps,type:jsonb,default:'[]'" example:"secondary_email"`
+	// Number of failed verification attempts
+	FailedAttempts int `json:"failed_attempts" bun:"failed_attempts,notnull,default:0" example:"0"`
+	// Hash of the client secret that authorizes the requester (never exposed)
+	SecretHash string `json:"-" bun:"secret_hash,notnull"`
+	// Reason given by the requester
+	Reason string `json:"reason,omitempty" bun:"reason" example:"Lost my phone and forgot my password"`
+	// IP address the request was started from
+	IPAddress string `json:"ip_address,omitempty" bun:"ip_address" example:"192.168.1.1"`
+	// User agent the request was started from
+	UserAgent string `json:"user_agent,omitempty" bun:"user_agent" example:"Mozilla/5.0"`
+	// Admin who reviewed the request
+	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty" bun:"reviewed_by,type:uuid"`
+	// Timestamp when the request was reviewed
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" bun:"reviewed_at" example:"2024-01-15T10:30:00Z"`
+	// Note left by the reviewing admin
+	ReviewNote string `json:"review_note,omitempty" bun:"review_note" example:"Identity confirmed by phone call"`
+	// Timestamp after which the request can no longer be used
+	ExpiresAt time.Time `json:"expires_at" bun:"expires_at,notnull" example:"2024-01-16T10:30:00Z"`
+	// Timestamp when the password was reset through this request
+	CompletedAt *time.Time `json:"completed_at,omitempty" bun:"completed_at" example:"2024-01-15T12:30:00Z"`
+	// Timestamp when the request was created
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	// Timestamp when the request was last updated
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+
+	// Relations
+	User *User `json:"user,omitempty" bun:"rel:belongs-to,join:user_id=id"`
+}
+
+// IsExpired checks if the recovery request has expired
+func (r *AccountRecoveryRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// IsActive reports whether the request can still make progress
+func (r *AccountRecoveryRequest) IsActive() bool {
+	switch r.Status {
+	case RecoveryStatusPendingVerification, RecoveryStatusPendingReview, RecoveryStatusApproved:
+		return !r.IsExpired()
+	default:
+		return false
+	}
+}
+
+// HasCompletedStep reports whether the given proofing step has been completed
+func (r *AccountRecoveryRequest) HasCompletedStep(step string) bool {
+	for _, completed := range r.CompletedSteps {
+		if completed == step {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresStep reports whether the given proofing step is required
+func (r *AccountRecoveryRequest) RequiresStep(step string) bool {
+	for _, required := range r.RequiredSteps {
+		if required == step {
+			return true
+		}
+	}
+	return false
+}
+
+// StartAccountRecoveryRequest starts recovery of an account that lost both password and 2FA
+type StartAccountRecoveryRequest struct {
+	// Primary email address of the account
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	// Why the account cannot be accessed (shown to reviewing admins)
+	Reason string `json:"reason" binding:"max=1000" example:"Lost my phone and forgot my password"`
+}
+
+// StartAccountRecoveryResponse identifies a recovery request. The response is identical
+// whether or not the email belongs to an account.
+type StartAccountRecoveryResponse struct {
+	// Recovery request ID
+	RequestID uuid.UUID `json:"request_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Secret that authorizes further calls for this request; shown only once
+	ClientSecret string `json:"client_secret" example:"5f2b8c1e9a..."`
+	// Timestamp after which the request can no longer be used
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-16T10:30:00Z"`
+	// Human-readable message
+	Message string `json:"message" example:"If an account exists for this email, recovery codes have been sent to its verified contact points"`
+}
+
+// AccountRecoverySecretRequest authorizes a call on a recovery request
+type AccountRecoverySecretRequest struct {
+	// Client secret returned when the request was started
+	ClientSecret string `json:"client_secret" binding:"required" example:"5f2b8c1e9a..."`
+}
+
+// VerifyAccountRecoveryStepRequest completes one proofing step of a recovery request
+type VerifyAccountRecoveryStepRequest struct {
+	// Client secret returned when the request was started
+	ClientSecret string `json:"client_secret" binding:"required" example:"5f2b8c1e9a..."`
+	// Proofing step: "secondary_email" or "sms"
+	Step string `json:"step" binding:"required,oneof=secondary_email sms" example:"secondary_email"`
+	// 6-digit code sent for the step
+	Code string `json:"code" binding:"required,len=6" example:"123456"`
+}
+
+// CompleteAccountRecoveryRequest sets a new password on an approved recovery request
+type CompleteAccountRecoveryRequest struct {
+	// Client secret returned when the request was started
+	ClientSecret string `json:"client_secret" binding:"required" example:"5f2b8c1e9a..."`
+	// New password
+	NewPassword string `json:"new_password" binding:"required,min=8" example:"NewSecurePass123!"`
+}
+
+// AccountRecoveryStatusResponse reports the progress of a recovery request to the requester
+type AccountRecoveryStatusResponse struct {
+	// Recovery request ID
+	RequestID uuid.UUID `json:"request_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Request status
+	Status string `json:"status" example:"pending_review"`
+	// Proofing steps that must be completed
+	RequiredSteps []string `json:"required_steps" example:"secondary_email,admin_review"`
+	// Proofing steps completed so far
+	CompletedSteps []string `json:"completed_steps" example:"secondary_email"`
+	// Timestamp after which the request can no longer be used
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-16T10:30:00Z"`
+}
+
+// ReviewAccountRecoveryRequest records an admin decision on a recovery request
+type ReviewAccountRecoveryRequest struct {
+	// Note explaining the decision
+	Note string `json:"note" binding:"max=1000" example:"Identity confirmed by phone call"`
+}
+
+// AccountRecoveryListResponse contains a paginated list of recovery requests
+type AccountRecoveryListResponse struct {
+	// Recovery requests, newest first
+	Requests []*AccountRecoveryRequest `json:"requests"`
+	// Total number of matching requests
+	Total int `json:"total" example:"5"`
+	// Current page number
+	Page int `json:"page" example:"1"`
+	// Number of items per page
+	PageSize int `json:"page_size" example:"20"`
+	// Total number of pages
+	TotalPages int `json:"total_pages" example:"1"`
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,10 +43,16 @@ type AdminUserResponse struct {
 	PhoneVerified bool `json:"phone_verified" example:"false"`
 	// Whether the account is active
 	IsActive bool `json:"is_active" example:"true"`
+	// Lifecycle state: invited, active, suspended, deactivated or deleted
+	State UserState `json:"state" example:"active"`
+	// Timestamp of the last lifecycle state change
+	StateChangedAt *time.Time `json:"state_changed_at,omitempty" example:"2024-01-15T10:30:00Z"`
 	// Whether TOTP 2FA is enabled
 	TOTPEnabled bool `json:"totp_enabled" example:"false"`
 	// Timestamp when TOTP 2FA was enabled
 	TOTPEnabledAt *time.Time `json:"totp_enabled_at,omitempty" example:"2024-01-15T10:30:00Z"`
+	// Whether security keys are registered as a second factor
+	WebAuthnEnabled bool `json:"webauthn_enabled" example:"false"`
 	// Timestamp of last login
 	LastLoginAt *time.Time `json:"last_login_at,omitempty" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when user was created
@@ -60,7 +69,7 @@ type AdminUserResponse struct {
 type AdminUpdateUserRequest struct {
 	// Role IDs to assign to the user
 	RoleIDs *[]uuid.UUID `json:"role_ids,omitempty" example:"123e4567-e89b-12d3-a456-426614174000,223e4567-e89b-12d3-a456-426614174001"`
-	// Whether the account should be active
+	// Whether the account should be active; false deactivates it. Use the state endpoint to suspend.
 	IsActive *bool `json:"is_active,omitempty" example:"true"`
 	// User's email address
 	Email *string `json:"email,omitempty" example:"user@example.com"`
@@ -80,10 +89,13 @@ type AdminCreateUserRequest struct {
 	Email string `json:"email" binding:"required,email" example:"newuser@example.com"`
 	// Unique username (3-100 characters)
 	Username string `json:"username" binding:"required,min=3,max=100" example:"newuser"`
-	// User's password (minimum 8 characters)
-	Password string `json:"password" binding:"required,min=8" example:"SecurePass123!"`
+	// User's password (minimum 8 characters); required unless the user is invited
+	Password string `json:"password" binding:"omitempty,min=8" example:"SecurePass123!"`
 	// User's full name
 	FullName string `json:"full_name" binding:"required" example:"New User"`
+	// Create the account as invited: it cannot sign in until the user sets a password
+	// through a password reset
+	Invite bool `json:"invite,omitempty" example:"false"`
 	// Role IDs to assign to the user
 	RoleIDs []uuid.UUID `json:"role_ids" example:"123e4567-e89b-12d3-a456-426614174000"`
 	// Account type: "human" or "service" (defaults to "human")
@@ -134,6 +146,39 @@ type AdminUserListResponse struct {
 	PageSize int `json:"page_size" example:"20"`
 	// Total number of pages
 	TotalPages int `json:"total_pages" example:"63"`
+	// Cursor of the next page when there are more users. Listing by cursor skips counting,
+	// leaving page, total and total_pages 0.
+	NextCursor string `json:"next_cursor,omitempty" example:"MjAyNC0wMS0xNVQxMDozMDowMFp8MTIzZTQ1NjctZTg5Yi0xMmQzLWE0NTYtNDI2NjE0MTc0MDAw"`
+}
+
+// ErrInvalidUserCursor is returned for a user list cursor that was not issued by the server
+var ErrInvalidUserCursor = errors.New("invalid cursor")
+
+// EncodeUserCursor returns the cursor continuing a user listing after the user created at
+// createdAt with id
+func EncodeUserCursor(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()))
+}
+
+// DecodeUserCursor returns the creation time and ID of the user a cursor continues after
+func DecodeUserCursor(cursor string) (time.Time, uuid.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidUserCursor
+	}
+	createdAt, id, ok := strings.Cut(string(data), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidUserCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidUserCursor
+	}
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidUserCursor
+	}
+	return t, userID, nil
 }
 
 // AdminAuditLogResponse represents an audit log entry
@@ -152,6 +197,14 @@ type AdminAuditLogResponse struct {
 	IP string `json:"ip" example:"192.168.1.1"`
 	// User agent string
 	UserAgent string `json:"user_agent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"`
+	// Type of the resource acted on
+	ResourceType string `json:"resource_type,omitempty" example:"user"`
+	// ID of the resource acted on
+	ResourceID string `json:"resource_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Country the request came from (ISO code)
+	CountryCode string `json:"country_code,omitempty" example:"DE"`
+	// City the request came from
+	City string `json:"city,omitempty" example:"Berlin"`
 	// Additional details about the action
 	Details map[string]interface{} `json:"details,omitempty"`
 	// Timestamp when action was performed
@@ -216,6 +269,46 @@ type AuditLogListResponse struct {
 	TotalPages int `json:"total_pages" example:"3"`
 }
 
+// AuditActionCount is the number of audit logs of an action
+type AuditActionCount struct {
+	// Action performed
+	Action string `json:"action" bun:"action" example:"signin"`
+	// Number of logs of the action
+	Total int `json:"total" bun:"total" example:"1200"`
+	// Number of those that did not succeed (failed, blocked)
+	Failed int `json:"failed" bun:"failed" example:"35"`
+}
+
+// AuditActionStatsResponse lists the most frequent audit actions of a time range
+type AuditActionStatsResponse struct {
+	// Start of the range
+	From time.Time `json:"from" example:"2024-01-14T10:00:00Z"`
+	// End of the range
+	To time.Time `json:"to" example:"2024-01-15T10:00:00Z"`
+	// Actions, most frequent first
+	Actions []*AuditActionCount `json:"actions"`
+}
+
+// AuditHourlyCount is the number of audit logs of an hour
+type AuditHourlyCount struct {
+	// Start of the hour
+	Hour time.Time `json:"hour" bun:"hour" example:"2024-01-15T09:00:00Z"`
+	// Number of logs
+	Count int `json:"count" bun:"count" example:"12"`
+}
+
+// AuditFailureStatsResponse counts unsuccessful actions per hour of a time range
+type AuditFailureStatsResponse struct {
+	// Start of the range, at a whole hour
+	From time.Time `json:"from" example:"2024-01-14T10:00:00Z"`
+	// End of the range
+	To time.Time `json:"to" example:"2024-01-15T10:00:00Z"`
+	// Failures of every hour of the range, oldest first; hours without failures count 0
+	Hours []*AuditHourlyCount `json:"hours"`
+	// Failures in the whole range
+	Total int `json:"total" example:"35"`
+}
+
 // OAuthAccountListResponse represents user OAuth accounts list
 type OAuthAccountListResponse struct {
 	// List of OAuth accounts
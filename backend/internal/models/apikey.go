@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +24,19 @@ type APIKey struct {
 	UpdatedAt   time.Time  `json:"updated_at" bun:"updated_at"`
 }
 
+// MarshalJSON writes scopes as the stored JSON array rather than base64-encoded bytes
+func (k APIKey) MarshalJSON() ([]byte, error) {
+	type apiKey APIKey
+	scopes := json.RawMessage(k.Scopes)
+	if !json.Valid(scopes) {
+		scopes = json.RawMessage("[]")
+	}
+	return json.Marshal(struct {
+		apiKey
+		Scopes json.RawMessage `json:"scopes"`
+	}{apiKey(k), scopes})
+}
+
 // APIKeyScope represents available scopes for API keys
 type APIKeyScope string
 
@@ -62,6 +76,13 @@ const (
 
 	// Token exchange scopes
 	ScopeExchangeManage APIKeyScope = "exchange:manage"
+
+	// RBAC scopes
+	ScopeRBACRegister APIKeyScope = "rbac:register"
+
+	// Session scopes
+	ScopeSessionsRead   APIKeyScope = "sessions:read"
+	ScopeSessionsRevoke APIKeyScope = "sessions:revoke"
 )
 
 // CreateAPIKeyRequest represents a request to create a new API key
@@ -123,6 +144,9 @@ func IsValidScope(scope string) bool {
 		ScopeEmailSend,
 		ScopeOAuthRead,
 		ScopeExchangeManage,
+		ScopeRBACRegister,
+		ScopeSessionsRead,
+		ScopeSessionsRevoke,
 	}
 
 	for _, validScope := range validScopes {
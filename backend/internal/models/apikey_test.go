@@ -1,11 +1,13 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsValidScope(t *testing.T) {
@@ -190,6 +192,25 @@ func TestAPIKey_PublicAPIKey(t *testing.T) {
 	})
 }
 
+func TestAPIKey_MarshalJSON(t *testing.T) {
+	t.Run("Writes scopes as array", func(t *testing.T) {
+		data, err := json.Marshal(&APIKey{Name: "Test Key", KeyHash: "secret", Scopes: []byte(`["users:read"]`)})
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, []interface{}{"users:read"}, decoded["scopes"])
+		assert.Equal(t, "Test Key", decoded["name"])
+		assert.NotContains(t, decoded, "key_hash")
+	})
+
+	t.Run("Writes empty array for invalid scopes", func(t *testing.T) {
+		data, err := json.Marshal(APIKey{Scopes: []byte(`invalid json`)})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"scopes":[]`)
+	})
+}
+
 func TestCreateAPIKeyRequest(t *testing.T) {
 	t.Run("Valid request", func(t *testing.T) {
 		req := CreateAPIKeyRequest{
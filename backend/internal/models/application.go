@@ -16,8 +16,14 @@ var ValidAuthMethods = []string{
 	"oauth_github",
 	"oauth_yandex",
 	"oauth_telegram",
+	"oauth_apple",
+	"oauth_microsoft",
+	"oauth_linkedin",
+	"oauth_oidc-generic",
 	"totp",
 	"api_key",
+	"guest",
+	"magic_link",
 }
 
 func IsValidAuthMethod(method string) bool {
@@ -104,7 +110,7 @@ type CreateApplicationRequest struct {
 	HomepageURL  string   `json:"homepage_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com"`
 	CallbackURLs []string `json:"callback_urls,omitempty" binding:"omitempty,dive,url" example:"https://example.com/callback"`
 	IsActive           *bool    `json:"is_active,omitempty" example:"true"`
-	AllowedAuthMethods []string `json:"allowed_auth_methods,omitempty" binding:"omitempty,dive,oneof=password otp_email otp_sms oauth_google oauth_github oauth_yandex oauth_telegram totp api_key"`
+	AllowedAuthMethods []string `json:"allowed_auth_methods,omitempty" binding:"omitempty,dive,oneof=password otp_email otp_sms oauth_google oauth_github oauth_yandex oauth_telegram oauth_apple oauth_microsoft oauth_linkedin oauth_oidc-generic totp api_key guest magic_link"`
 }
 
 type UpdateApplicationRequest struct {
@@ -113,7 +119,7 @@ type UpdateApplicationRequest struct {
 	HomepageURL  string   `json:"homepage_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com"`
 	CallbackURLs []string `json:"callback_urls,omitempty" binding:"omitempty,dive,url" example:"https://example.com/callback"`
 	IsActive           *bool    `json:"is_active,omitempty" example:"true"`
-	AllowedAuthMethods []string `json:"allowed_auth_methods,omitempty" binding:"omitempty,dive,oneof=password otp_email otp_sms oauth_google oauth_github oauth_yandex oauth_telegram totp api_key"`
+	AllowedAuthMethods []string `json:"allowed_auth_methods,omitempty" binding:"omitempty,dive,oneof=password otp_email otp_sms oauth_google oauth_github oauth_yandex oauth_telegram oauth_apple oauth_microsoft oauth_linkedin oauth_oidc-generic totp api_key guest magic_link"`
 }
 
 type UpdateApplicationBrandingRequest struct {
@@ -23,6 +23,7 @@ type AuditLog struct {
 	City          string     `json:"city,omitempty" bun:"city"`
 	Latitude      float64    `json:"latitude,omitempty" bun:"latitude"`
 	Longitude     float64    `json:"longitude,omitempty" bun:"longitude"`
+	TraceID       string     `json:"trace_id,omitempty" bun:"trace_id,nullzero"`
 	CreatedAt     time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	User          *User      `bun:"rel:belongs-to,join:user_id=id" json:"-"`
 }
@@ -55,6 +56,58 @@ const (
 	ActionSend                       AuditAction = "send"
 	ActionTokenExchangeCreate        AuditAction = "token_exchange_create"
 	ActionTokenExchangeRedeem        AuditAction = "token_exchange_redeem"
+	ActionTokensRevoked              AuditAction = "tokens_revoked"
+	ActionTokensInvalidatedGlobally  AuditAction = "tokens_invalidated_globally"
+	ActionPasswordChangeRequired     AuditAction = "password_change_required"
+	ActionPasswordPolicyUpdate       AuditAction = "password_policy_update"
+	ActionAccountRecoveryStart       AuditAction = "account_recovery_start"
+	ActionAccountRecoveryVerify      AuditAction = "account_recovery_verify"
+	ActionAccountRecoveryApprove     AuditAction = "account_recovery_approve"
+	ActionAccountRecoveryReject      AuditAction = "account_recovery_reject"
+	ActionAccountRecoveryComplete    AuditAction = "account_recovery_complete"
+	ActionEmailAdd                   AuditAction = "email_add"
+	ActionEmailVerify                AuditAction = "email_verify"
+	ActionEmailRemove                AuditAction = "email_remove"
+	ActionEmailPrimaryChange         AuditAction = "email_primary_change"
+	ActionRecoveryEmailChange        AuditAction = "recovery_email_change"
+	ActionOAuthLink                  AuditAction = "oauth_link"
+	ActionOAuthLinkFailed            AuditAction = "oauth_link_failed"
+	ActionSignupPolicyUpdate         AuditAction = "signup_policy_update"
+	ActionGroupDomainAdd             AuditAction = "group_domain_add"
+	ActionGroupDomainVerify          AuditAction = "group_domain_verify"
+	ActionGroupDomainRemove          AuditAction = "group_domain_remove"
+	ActionGuestCreate                AuditAction = "guest_create"
+	ActionGuestUpgrade               AuditAction = "guest_upgrade"
+	ActionChaosFaultSet              AuditAction = "chaos_fault_set"
+	ActionChaosFaultClear            AuditAction = "chaos_fault_clear"
+	ActionLogLevelSet                AuditAction = "log_level_set"
+	ActionLogLevelReset              AuditAction = "log_level_reset"
+	ActionCredentialsIssue           AuditAction = "credentials_issue"
+	ActionCredentialsRevoke          AuditAction = "credentials_revoke"
+	ActionCredentialTargetCreate     AuditAction = "credential_target_create"
+	ActionCredentialTargetUpdate     AuditAction = "credential_target_update"
+	ActionCredentialTargetDelete     AuditAction = "credential_target_delete"
+	ActionSignedURLCreate            AuditAction = "signed_url_create"
+	ActionSigningKeyBackupExport     AuditAction = "signing_key_backup_export"
+	ActionSigningKeyImport           AuditAction = "signing_key_import"
+	ActionSigningKeyCeremony         AuditAction = "signing_key_ceremony"
+	ActionRiskStepUp                 AuditAction = "risk_step_up"
+	ActionRiskBlock                  AuditAction = "risk_block"
+	ActionRiskPolicyCreate           AuditAction = "risk_policy_create"
+	ActionRiskPolicyUpdate           AuditAction = "risk_policy_update"
+	ActionRiskPolicyDelete           AuditAction = "risk_policy_delete"
+	ActionAccountLocked              AuditAction = "account_locked"
+	ActionAccountUnlocked            AuditAction = "account_unlocked"
+	ActionWebAuthnRegister           AuditAction = "webauthn_register"
+	ActionWebAuthnRemove             AuditAction = "webauthn_remove"
+	ActionSMSDeliveryStatus          AuditAction = "sms_delivery_status"
+	ActionUserStateChange            AuditAction = "user_state_change"
+	ActionDormantPolicyUpdate        AuditAction = "dormant_account_policy_update"
+	ActionDormantAccountNotice       AuditAction = "dormant_account_notice"
+	ActionUserAnonymize              AuditAction = "user_anonymize"
+	ActionMagicLinkRequest           AuditAction = "magic_link_request"
+	ActionDataExportRequest          AuditAction = "data_export_request"
+	ActionDataExportDownload         AuditAction = "data_export_download"
 )
 
 // AuditResource represents the type of resource being audited
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AuditExportCursor is how far the audit logs have been scheduled for export to a sink: the
+// windows before ScheduledUntil have export jobs queued
+type AuditExportCursor struct {
+	bun.BaseModel `bun:"table:audit_export_cursors,alias:aec"`
+
+	Sink           string    `bun:"sink,pk"`
+	ScheduledUntil time.Time `bun:"scheduled_until,notnull"`
+	UpdatedAt      time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// AuditExportSinkStatus describes an audit export sink
+type AuditExportSinkStatus struct {
+	// Sink name
+	Name string `json:"name" example:"syslog"`
+	// Length of the windows exported at once
+	Interval string `json:"interval" example:"1m0s"`
+	// Logs created before this time have export jobs queued
+	ScheduledUntil *time.Time `json:"scheduled_until,omitempty" example:"2024-01-15T10:30:00Z"`
+}
+
+// AuditExportStatusResponse lists the configured audit export sinks
+type AuditExportStatusResponse struct {
+	Sinks []AuditExportSinkStatus `json:"sinks"`
+}
+
+// AuditExportReplayRequest asks to export the audit logs of a past time range again
+type AuditExportReplayRequest struct {
+	// Sink to export to
+	Sink string `json:"sink" binding:"required" example:"s3"`
+	// Start of the range; rounded down to the sink's interval
+	From time.Time `json:"from" binding:"required" example:"2024-01-01T00:00:00Z"`
+	// End of the range; rounded up to the sink's interval
+	To time.Time `json:"to" binding:"required" example:"2024-01-02T00:00:00Z"`
+}
+
+// AuditExportReplayResponse reports the export jobs queued for a replay
+type AuditExportReplayResponse struct {
+	Sink string    `json:"sink" example:"s3"`
+	From time.Time `json:"from" example:"2024-01-01T00:00:00Z"`
+	To   time.Time `json:"to" example:"2024-01-02T00:00:00Z"`
+	// Number of export jobs queued, one per hour or interval of the sink if longer
+	Jobs int `json:"jobs" example:"24"`
+}
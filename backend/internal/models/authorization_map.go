@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Kinds of authorization requirements a route or gRPC method places on its caller
+const (
+	// AuthRequirementAuthentication needs one of the credential types in Values
+	AuthRequirementAuthentication = "authentication"
+	// AuthRequirementRole needs one of the roles in Values
+	AuthRequirementRole = "role"
+	// AuthRequirementPermission needs one of the permissions in Values, or all of them when All is set
+	AuthRequirementPermission = "permission"
+	// AuthRequirementAdminOrPermission needs the admin role or the delegated admin permission in Values
+	AuthRequirementAdminOrPermission = "admin_or_permission"
+	// AuthRequirementScope needs an API key with one of the scopes in Values
+	AuthRequirementScope = "scope"
+	// AuthRequirementOrgAdmin needs the caller to administer the organization named by the path parameter in Values
+	AuthRequirementOrgAdmin = "org_admin"
+	// AuthRequirementPolicy is a check that is not a plain role or permission, named in Values
+	AuthRequirementPolicy = "policy"
+)
+
+// Credential types a route or gRPC method accepts
+const (
+	CredentialJWT       = "jwt"
+	CredentialAPIKey    = "api_key"
+	CredentialAppSecret = "app_secret"
+	// CredentialSession is the login session cookie of the hosted login pages
+	CredentialSession = "session"
+)
+
+// AuthRequirement is one check a middleware performs before the route handler runs
+type AuthRequirement struct {
+	Kind   string   `json:"kind" example:"permission"`
+	Values []string `json:"values,omitempty" example:"users:read"`
+	// All of Values are required rather than any one of them
+	All bool `json:"all,omitempty" example:"false"`
+	// Callers let through without meeting the requirement, e.g. admins or application secrets
+	Bypass      []string `json:"bypass,omitempty" example:"admin"`
+	Description string   `json:"description,omitempty" example:"Requires the users:read permission"`
+}
+
+// RouteAuthorization is what an HTTP route requires of its caller
+type RouteAuthorization struct {
+	Method string `json:"method" example:"GET"`
+	Path   string `json:"path" example:"/api/admin/users"`
+	// Public routes run without any of the authentication middleware. Their handlers may still
+	// authenticate the caller themselves, e.g. OAuth client authentication at the token endpoint.
+	Public bool `json:"public" example:"false"`
+	// Credential types accepted by the route, empty for public routes
+	Authentication []string          `json:"authentication,omitempty" example:"jwt,api_key,app_secret"`
+	Requirements   []AuthRequirement `json:"requirements"`
+	// Handler function serving the route
+	Handler string `json:"handler" example:"github.com/smilemakc/auth-gateway/internal/handler.(*AdminHandler).ListUsers-fm"`
+}
+
+// GRPCMethodAuthorization is what a gRPC method requires of its caller
+type GRPCMethodAuthorization struct {
+	Method string `json:"method" example:"/auth.AuthService/ValidateToken"`
+	// Streaming methods are served by the stream interceptors
+	Streaming bool `json:"streaming" example:"false"`
+	// Credential types accepted by the method
+	Authentication []string `json:"authentication,omitempty" example:"api_key,app_secret"`
+	// API key scope required to call the method. Application secrets restricted to a list of
+	// gRPC scopes need it in that list as well.
+	Scope string `json:"scope,omitempty" example:"token:validate"`
+	// Methods without a configured scope are rejected for every caller
+	Denied bool `json:"denied" example:"false"`
+}
+
+// AuthorizationMap lists the authorization requirements of every HTTP route and gRPC method
+type AuthorizationMap struct {
+	GeneratedAt time.Time                 `json:"generated_at"`
+	HTTP        []RouteAuthorization      `json:"http"`
+	GRPC        []GRPCMethodAuthorization `json:"grpc"`
+}
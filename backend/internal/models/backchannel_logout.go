@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Back-channel logout delivery statuses
+const (
+	BackchannelLogoutPending   = "pending"
+	BackchannelLogoutDelivered = "delivered"
+	BackchannelLogoutFailed    = "failed"
+)
+
+// BackchannelLogoutDelivery is a logout token owed to a client's back-channel logout URI.
+// The token itself is signed at each attempt so it is always fresh.
+type BackchannelLogoutDelivery struct {
+	bun.BaseModel `bun:"table:backchannel_logout_deliveries,alias:bld"`
+
+	ID            uuid.UUID    `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	ClientID      uuid.UUID    `json:"client_id" bun:"client_id,type:uuid,notnull"`
+	Client        *OAuthClient `json:"client,omitempty" bun:"rel:belongs-to,join:client_id=id"`
+	UserID        uuid.UUID    `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Status        string       `json:"status" bun:"status,notnull,default:'pending'"`
+	Attempts      int          `json:"attempts" bun:"attempts,notnull,default:0"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" bun:"next_attempt_at,notnull,default:current_timestamp"`
+	LastError     string       `json:"last_error,omitempty" bun:"last_error"`
+	CreatedAt     time.Time    `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time    `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Background job statuses
+const (
+	BackgroundJobPending   = "pending"
+	BackgroundJobRunning   = "running"
+	BackgroundJobSucceeded = "succeeded"
+	BackgroundJobDead      = "dead" // out of attempts or failed permanently; kept until retried
+)
+
+// BackgroundJob is a unit of work in the persistent job queue. Any instance may run it; a
+// running job whose instance dies becomes due again when its lease (RunAt) runs out.
+type BackgroundJob struct {
+	bun.BaseModel `bun:"table:background_jobs,alias:bj"`
+
+	ID          uuid.UUID       `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Type        string          `json:"type" bun:"type,notnull" example:"webhook.delivery"`
+	Payload     json.RawMessage `json:"payload" bun:"payload,type:jsonb,notnull" swaggertype:"object"`
+	Status      string          `json:"status" bun:"status,notnull,default:'pending'" example:"pending"`
+	Attempts    int             `json:"attempts" bun:"attempts,notnull,default:0" example:"1"`
+	MaxAttempts int             `json:"max_attempts" bun:"max_attempts,notnull" example:"5"`
+	RunAt       time.Time       `json:"run_at" bun:"run_at,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	LastError   string          `json:"last_error,omitempty" bun:"last_error" example:"unexpected status 502"`
+	CreatedAt   time.Time       `json:"created_at" bun:"created_at,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt   time.Time       `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp" example:"2024-01-15T10:31:00Z"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty" bun:"completed_at" example:"2024-01-15T10:31:00Z"`
+}
+
+// BackgroundJobListResponse contains paginated background jobs
+type BackgroundJobListResponse struct {
+	// Jobs, newest first
+	Jobs []*BackgroundJob `json:"jobs"`
+	// Total number of jobs
+	Total int `json:"total" example:"150"`
+	// Current page number
+	Page int `json:"page" example:"1"`
+	// Number of items per page
+	PageSize int `json:"page_size" example:"20"`
+	// Total number of pages
+	TotalPages int `json:"total_pages" example:"8"`
+}
+
+// BackgroundJobStatsResponse counts background jobs by status
+type BackgroundJobStatsResponse struct {
+	Pending   int `json:"pending" example:"3"`
+	Running   int `json:"running" example:"1"`
+	Succeeded int `json:"succeeded" example:"1200"`
+	Dead      int `json:"dead" example:"2"`
+}
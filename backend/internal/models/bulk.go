@@ -1,7 +1,10 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 // BulkCreateUsersRequest represents a request to create multiple users
@@ -69,3 +72,89 @@ type BulkOperationItemResult struct {
 	Success bool      `json:"success" example:"true"`
 	Message string    `json:"message,omitempty" example:"User created successfully"`
 }
+
+// Bulk role job operations
+const (
+	BulkRoleOperationAssign = "assign"
+	BulkRoleOperationRemove = "remove"
+)
+
+// Bulk role job statuses
+const (
+	BulkRoleJobPending   = "pending"
+	BulkRoleJobRunning   = "running"
+	BulkRoleJobCompleted = "completed"
+	BulkRoleJobFailed    = "failed"
+)
+
+// BulkRoleJobRequest starts a job that assigns a role to, or removes it from, a set of
+// users selected by exactly one of query, group_id or user_ids
+type BulkRoleJobRequest struct {
+	// Operation to perform: assign or remove
+	Operation string `json:"operation" binding:"required,oneof=assign remove" example:"assign"`
+	// Role to assign or remove
+	RoleID uuid.UUID `json:"role_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Application the role is assigned in (assign only)
+	ApplicationID *uuid.UUID `json:"application_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Select users whose username, name or email contains the query
+	Query string `json:"query,omitempty" example:"@example.com"`
+	// Select the members of a group
+	GroupID *uuid.UUID `json:"group_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Select users by ID
+	UserIDs []uuid.UUID `json:"user_ids,omitempty"`
+}
+
+// BulkRoleJobFailure is a user the job could not process
+type BulkRoleJobFailure struct {
+	UserID uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email  string    `json:"email,omitempty" example:"user@example.com"`
+	Error  string    `json:"error" example:"user not found"`
+}
+
+// BulkRoleJob tracks the progress of a bulk role assignment or removal
+type BulkRoleJob struct {
+	bun.BaseModel `bun:"table:bulk_role_jobs"`
+
+	// Job unique identifier
+	ID uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Operation: assign or remove
+	Operation string `json:"operation" bun:"operation,notnull" example:"assign"`
+	// Role assigned or removed
+	RoleID uuid.UUID `json:"role_id" bun:"role_id,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Application the role is assigned in
+	ApplicationID *uuid.UUID `json:"application_id,omitempty" bun:"application_id,type:uuid"`
+	// Search query used to select users
+	Query string `json:"query,omitempty" bun:"query" example:"@example.com"`
+	// Group whose members were selected
+	GroupID *uuid.UUID `json:"group_id,omitempty" bun:"group_id,type:uuid"`
+	// Explicitly selected user IDs
+	UserIDs []uuid.UUID `json:"user_ids,omitempty" bun:"user_ids,type:jsonb"`
+	// Status: pending, running, completed or failed
+	Status string `json:"status" bun:"status,notnull" example:"running"`
+	// Number of selected users, known once the job is running
+	Total int `json:"total" bun:"total,notnull" example:"1200"`
+	// Number of users processed so far
+	Processed int `json:"processed" bun:"processed,notnull" example:"600"`
+	// Number of users processed successfully
+	Succeeded int `json:"succeeded" bun:"succeeded,notnull" example:"598"`
+	// Number of users that could not be processed
+	Failed int `json:"failed" bun:"failed,notnull" example:"2"`
+	// Users that could not be processed, capped at the first 1000 (omitted from job lists)
+	Failures []BulkRoleJobFailure `json:"failures,omitempty" bun:"failures,type:jsonb"`
+	// Reason the whole job failed
+	Error string `json:"error,omitempty" bun:"error" example:"failed to search users"`
+	// Admin who started the job
+	CreatedBy uuid.UUID `json:"created_by" bun:"created_by,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Timestamp when the job was created
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	// Timestamp when the job last reported progress
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:05Z"`
+	// Timestamp when the job finished
+	CompletedAt *time.Time `json:"completed_at,omitempty" bun:"completed_at" example:"2024-01-15T10:31:00Z"`
+}
+
+// BulkRoleJobListResponse lists bulk role jobs, newest first
+type BulkRoleJobListResponse struct {
+	Jobs  []*BulkRoleJob `json:"jobs"`
+	Total int            `json:"total" example:"3"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// FaultTarget names a dependency faults can be injected into
+type FaultTarget string
+
+const (
+	FaultTargetDB      FaultTarget = "db"
+	FaultTargetRedis   FaultTarget = "redis"
+	FaultTargetSMTP    FaultTarget = "smtp"
+	FaultTargetWebhook FaultTarget = "webhook"
+)
+
+// FaultTargets lists every target faults can be injected into
+var FaultTargets = []FaultTarget{FaultTargetDB, FaultTargetRedis, FaultTargetSMTP, FaultTargetWebhook}
+
+// IsValidFaultTarget reports whether target is a known fault target
+func IsValidFaultTarget(target string) bool {
+	for _, t := range FaultTargets {
+		if string(t) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Fault is an active fault injected into calls to one dependency
+type Fault struct {
+	// Dependency the fault applies to
+	Target FaultTarget `json:"target" example:"redis"`
+	// Delay added before every call, in milliseconds
+	LatencyMS int `json:"latency_ms" example:"500"`
+	// Share of calls that fail, from 0 to 1
+	ErrorRate float64 `json:"error_rate" example:"0.25"`
+	// When the fault is removed automatically (nil = until cleared)
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-01-15T10:45:00Z"`
+	// When the fault was set
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// SetFaultRequest is the request to inject a fault into a dependency
+type SetFaultRequest struct {
+	// Delay added before every call, in milliseconds
+	LatencyMS int `json:"latency_ms" binding:"min=0,max=60000" example:"500"`
+	// Share of calls that fail, from 0 to 1
+	ErrorRate float64 `json:"error_rate" binding:"min=0,max=1" example:"0.25"`
+	// Remove the fault after this many seconds (0 = until cleared)
+	DurationSeconds int `json:"duration_seconds" binding:"min=0,max=86400" example:"900"`
+}
+
+// FaultListResponse lists the active faults
+type FaultListResponse struct {
+	Faults []Fault `json:"faults"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Kinds of downstream credentials the broker issues
+const (
+	CredentialKindPostgres = "postgres"
+	CredentialKindSSH      = "ssh"
+)
+
+// CredentialTarget is a downstream system users can get short-lived credentials for.
+// Users need the target's RBAC permission to request them.
+type CredentialTarget struct {
+	bun.BaseModel `bun:"table:credential_targets,alias:ct"`
+
+	ID          uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" bun:"name,notnull,unique" example:"analytics-db"`
+	Kind        string    `json:"kind" bun:"kind,notnull" example:"postgres"`
+	Description string    `json:"description" bun:"description,notnull,default:''" example:"Read-only access to the analytics database"`
+
+	// RBAC permission (by name) a user needs to request credentials
+	Permission string `json:"permission" bun:"permission,notnull" example:"analytics-db:connect"`
+
+	// Postgres: role granted to the temporary login role
+	PostgresRole string `json:"postgres_role,omitempty" bun:"postgres_role,notnull,default:''" example:"analytics_readonly"`
+
+	// SSH: principals written into the certificate
+	SSHPrincipals []string `json:"ssh_principals,omitempty" bun:"ssh_principals,type:jsonb,default:'[]'" example:"deploy"`
+
+	// Longest lifetime of issued credentials; 0 uses the broker's maximum
+	MaxTTLSeconds int `json:"max_ttl_seconds" bun:"max_ttl_seconds,notnull,default:0" example:"3600"`
+
+	IsActive  bool      `json:"is_active" bun:"is_active,notnull,default:true" example:"true"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+}
+
+// CreateCredentialTargetRequest creates a credential target
+type CreateCredentialTargetRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100" example:"analytics-db"`
+	Kind          string   `json:"kind" binding:"required,oneof=postgres ssh" example:"postgres"`
+	Description   string   `json:"description" binding:"max=500" example:"Read-only access to the analytics database"`
+	Permission    string   `json:"permission" binding:"required,max=100" example:"analytics-db:connect"`
+	PostgresRole  string   `json:"postgres_role" binding:"max=63" example:"analytics_readonly"`
+	SSHPrincipals []string `json:"ssh_principals" binding:"omitempty,dive,min=1,max=100" example:"deploy"`
+	MaxTTLSeconds int      `json:"max_ttl_seconds" binding:"min=0" example:"3600"`
+}
+
+// UpdateCredentialTargetRequest updates a credential target; omitted fields are kept
+type UpdateCredentialTargetRequest struct {
+	Description   *string  `json:"description" binding:"omitempty,max=500" example:"Read-only access to the analytics database"`
+	Permission    *string  `json:"permission" binding:"omitempty,min=1,max=100" example:"analytics-db:connect"`
+	PostgresRole  *string  `json:"postgres_role" binding:"omitempty,min=1,max=63" example:"analytics_readonly"`
+	SSHPrincipals []string `json:"ssh_principals" binding:"omitempty,dive,min=1,max=100" example:"deploy"`
+	MaxTTLSeconds *int     `json:"max_ttl_seconds" binding:"omitempty,min=0" example:"3600"`
+	IsActive      *bool    `json:"is_active" example:"true"`
+}
+
+// CredentialIssuance records credentials issued to a user. Records outlive their target
+// and user, so expired Postgres roles are still dropped.
+type CredentialIssuance struct {
+	bun.BaseModel `bun:"table:credential_issuances,alias:ci"`
+
+	ID         uuid.UUID  `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TargetID   *uuid.UUID `json:"target_id,omitempty" bun:"target_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TargetName string     `json:"target_name" bun:"target_name,notnull" example:"analytics-db"`
+	UserID     uuid.UUID  `json:"user_id" bun:"user_id,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Kind       string     `json:"kind" bun:"kind,notnull" example:"postgres"`
+
+	// Postgres login role, or the SSH certificate key ID
+	Username string `json:"username" bun:"username,notnull" example:"gw_4f1c2a9e_7b3d"`
+	// SSH certificate serial number
+	Serial int64 `json:"serial,omitempty" bun:"serial,notnull,default:0" example:"8231476520945"`
+
+	IPAddress string    `json:"ip_address,omitempty" bun:"ip_address" example:"203.0.113.7"`
+	ExpiresAt time.Time `json:"expires_at" bun:"expires_at,notnull" example:"2024-01-15T11:30:00Z"`
+	// When the Postgres login role was dropped
+	RevokedAt *time.Time `json:"revoked_at,omitempty" bun:"revoked_at" example:"2024-01-15T11:31:00Z"`
+	CreatedAt time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+}
+
+// IssueCredentialsRequest requests credentials for a target
+type IssueCredentialsRequest struct {
+	// Requested lifetime; defaults to the broker's default and is capped by the target
+	TTLSeconds int `json:"ttl_seconds" binding:"min=0" example:"900"`
+	// SSH: the public key to certify, in authorized_keys format
+	PublicKey string `json:"public_key" binding:"max=16384" example:"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO... user@laptop"`
+}
+
+// IssuedCredentials are short-lived credentials for a target
+type IssuedCredentials struct {
+	IssuanceID uuid.UUID `json:"issuance_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Target     string    `json:"target" example:"analytics-db"`
+	Kind       string    `json:"kind" example:"postgres"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2024-01-15T11:30:00Z"`
+
+	Postgres *PostgresCredentials `json:"postgres,omitempty"`
+	SSH      *SSHCredentials      `json:"ssh,omitempty"`
+}
+
+// PostgresCredentials is a temporary Postgres login
+type PostgresCredentials struct {
+	Username string `json:"username" example:"gw_4f1c2a9e_7b3d"`
+	Password string `json:"password" example:"Zk3q..."`
+}
+
+// SSHCredentials is an SSH user certificate for the submitted public key
+type SSHCredentials struct {
+	// Certificate in authorized_keys format, to be saved as <key>-cert.pub
+	Certificate string   `json:"certificate" example:"ssh-ed25519-cert-v01@openssh.com AAAAIHNzaC1lZDI1NTE5LWNlcnQt..."`
+	Principals  []string `json:"principals" example:"deploy"`
+	Serial      int64    `json:"serial" example:"8231476520945"`
+}
+
+// CredentialTargetListResponse lists credential targets
+type CredentialTargetListResponse struct {
+	Targets []*CredentialTarget `json:"targets"`
+}
+
+// CredentialIssuanceListResponse is a page of credential issuances
+type CredentialIssuanceListResponse struct {
+	Issuances  []*CredentialIssuance `json:"issuances"`
+	Total      int                   `json:"total" example:"42"`
+	Page       int                   `json:"page" example:"1"`
+	PageSize   int                   `json:"page_size" example:"50"`
+	TotalPages int                   `json:"total_pages" example:"1"`
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// DataExportFormat is the file format of a user data export
+type DataExportFormat string
+
+const (
+	// DataExportFormatJSON is a single JSON document
+	DataExportFormatJSON DataExportFormat = "json"
+	// DataExportFormatZIP is a ZIP archive with one JSON file per section
+	DataExportFormatZIP DataExportFormat = "zip"
+)
+
+// IsValid reports whether the format is known
+func (f DataExportFormat) IsValid() bool {
+	return f == DataExportFormatJSON || f == DataExportFormatZIP
+}
+
+// DataExportStatus is the state of a user data export
+type DataExportStatus string
+
+const (
+	DataExportStatusPending DataExportStatus = "pending"
+	DataExportStatusReady   DataExportStatus = "ready"
+	DataExportStatusFailed  DataExportStatus = "failed"
+)
+
+// UserDataExport is an export of a user's data, generated in the background. The file is kept
+// until ExpiresAt.
+type UserDataExport struct {
+	bun.BaseModel `bun:"table:user_data_exports,alias:ude"`
+
+	ID          uuid.UUID        `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	UserID      uuid.UUID        `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Format      DataExportFormat `json:"format" bun:"format,notnull"`
+	Status      DataExportStatus `json:"status" bun:"status,notnull"`
+	Content     []byte           `json:"-" bun:"content,type:bytea"`
+	Size        int64            `json:"size" bun:"size,notnull"`
+	Error       string           `json:"error,omitempty" bun:"error,nullzero"`
+	CreatedAt   time.Time        `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty" bun:"completed_at"`
+	ExpiresAt   time.Time        `json:"expires_at" bun:"expires_at,notnull"`
+}
+
+// DataExportResponse describes a user data export and, once it is ready, where to download it
+type DataExportResponse struct {
+	// Export unique identifier
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+
+	// pending while the export is generated, then ready or failed
+	Status DataExportStatus `json:"status" example:"ready"`
+
+	// File format: json or zip
+	Format DataExportFormat `json:"format" example:"zip"`
+
+	// Size of the file in bytes, once ready
+	Size int64 `json:"size,omitempty" example:"48213"`
+
+	// Why the export failed
+	Error string `json:"error,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2024-01-15T10:30:05Z"`
+
+	// When the file is deleted; a new export can be requested afterwards
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-16T10:30:00Z"`
+
+	// Signed link downloading the file without a bearer token, once ready
+	DownloadURL string `json:"download_url,omitempty" example:"https://auth.example.com/api/auth/me/export/123e4567-e89b-12d3-a456-426614174000/download?ag_expires=1705314600&ag_user=...&ag_signature=..."`
+
+	// When the download link stops working; request the export again for a new link
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty" example:"2024-01-15T10:45:00Z"`
+}
+
+// UserDataExportDocument is the content of a user data export. In a ZIP export each section
+// is a file of its own.
+type UserDataExportDocument struct {
+	ExportedAt          time.Time                   `json:"exported_at"`
+	Profile             *User                       `json:"profile"`
+	Emails              []*UserEmail                `json:"emails"`
+	ApplicationProfiles []*DataExportAppProfile     `json:"application_profiles"`
+	Sessions            []Session                   `json:"sessions"`
+	Consents            []*DataExportConsent        `json:"consents"`
+	AuditEvents         []*DataExportAuditEvent     `json:"audit_events"`
+	LinkedIdentities    *DataExportLinkedIdentities `json:"linked_identities"`
+}
+
+// DataExportAppProfile is the user's profile in an application
+type DataExportAppProfile struct {
+	ApplicationID uuid.UUID       `json:"application_id"`
+	DisplayName   *string         `json:"display_name,omitempty"`
+	AvatarURL     *string         `json:"avatar_url,omitempty"`
+	Nickname      *string         `json:"nickname,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	AppRoles      []string        `json:"app_roles,omitempty"`
+	IsActive      bool            `json:"is_active"`
+	IsBanned      bool            `json:"is_banned"`
+	BanReason     *string         `json:"ban_reason,omitempty"`
+	LastAccessAt  *time.Time      `json:"last_access_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// DataExportConsent is the user's consent to an OAuth client accessing their data
+type DataExportConsent struct {
+	ClientID   uuid.UUID  `json:"client_id"`
+	ClientName string     `json:"client_name,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	GrantedAt  time.Time  `json:"granted_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// DataExportAuditEvent is an audit log entry about the user
+type DataExportAuditEvent struct {
+	ID           uuid.UUID       `json:"id"`
+	Action       string          `json:"action"`
+	Status       string          `json:"status"`
+	ResourceType string          `json:"resource_type,omitempty"`
+	ResourceID   string          `json:"resource_id,omitempty"`
+	IPAddress    string          `json:"ip_address,omitempty"`
+	UserAgent    string          `json:"user_agent,omitempty"`
+	CountryCode  string          `json:"country_code,omitempty"`
+	City         string          `json:"city,omitempty"`
+	Details      json.RawMessage `json:"details,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// DataExportLinkedIdentities are the external identities and keys linked to the user
+type DataExportLinkedIdentities struct {
+	OAuth    []*DataExportOAuthAccount `json:"oauth"`
+	Telegram []*UserTelegramAccount    `json:"telegram"`
+	WebAuthn []*WebAuthnCredential     `json:"webauthn"`
+}
+
+// DataExportOAuthAccount is an account at an OAuth provider linked to the user. Provider
+// tokens are not exported.
+type DataExportOAuthAccount struct {
+	Provider       string          `json:"provider"`
+	ProviderUserID string          `json:"provider_user_id"`
+	Profile        json.RawMessage `json:"profile,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettingDormantAccountPolicy is the system setting holding the JSON encoded DormantAccountPolicy
+const SettingDormantAccountPolicy = "dormant_account_policy"
+
+// DormantAction is what happens to an account once it has been dormant past its notice period
+type DormantAction string
+
+const (
+	// DormantActionSuspend blocks sign-in; an admin can reactivate the account
+	DormantActionSuspend DormantAction = "suspend"
+	// DormantActionDeactivate blocks sign-in the same way an admin deactivation does
+	DormantActionDeactivate DormantAction = "deactivate"
+	// DormantActionAnonymize deletes the account and scrubs its personal data
+	DormantActionAnonymize DormantAction = "anonymize"
+)
+
+// IsValid reports whether the action is known
+func (a DormantAction) IsValid() bool {
+	switch a {
+	case DormantActionSuspend, DormantActionDeactivate, DormantActionAnonymize:
+		return true
+	}
+	return false
+}
+
+// DormantAccountPolicy decides which accounts count as dormant and what happens to them.
+// Administrators, guests and service accounts are never affected.
+type DormantAccountPolicy struct {
+	// Whether the background job notifies and enforces; the report works either way
+	Enabled bool `json:"enabled" example:"false"`
+
+	// Days without a sign-in or session activity after which an account is dormant
+	InactiveDays int `json:"inactive_days" example:"365"`
+
+	// Days between the notice email and enforcement; 0 enforces without notice
+	NoticeDays int `json:"notice_days" example:"30"`
+
+	// What happens to a dormant account once the notice period is over
+	Action DormantAction `json:"action" example:"suspend"`
+
+	// Accounts holding any of these roles are never dormant
+	ExcludedRoles []string `json:"excluded_roles" example:"auditor"`
+
+	// Accounts with an email address on these domains (and their subdomains) are never dormant
+	ExcludedDomains []string `json:"excluded_domains" example:"example.com"`
+
+	// These accounts are never dormant
+	ExcludedUserIDs []uuid.UUID `json:"excluded_user_ids"`
+}
+
+// UpdateDormantAccountPolicyRequest replaces the dormant account policy
+type UpdateDormantAccountPolicyRequest struct {
+	Enabled         bool          `json:"enabled" example:"true"`
+	InactiveDays    int           `json:"inactive_days" binding:"required,min=1,max=3650" example:"365"`
+	NoticeDays      int           `json:"notice_days" binding:"min=0,max=365" example:"30"`
+	Action          DormantAction `json:"action" binding:"required" example:"suspend"`
+	ExcludedRoles   []string      `json:"excluded_roles" example:"auditor"`
+	ExcludedDomains []string      `json:"excluded_domains" example:"example.com"`
+	ExcludedUserIDs []uuid.UUID   `json:"excluded_user_ids"`
+}
+
+// DormantAccountCandidate is an active account without activity since the dormancy cutoff
+type DormantAccountCandidate struct {
+	UserID       uuid.UUID  `bun:"id"`
+	Email        string     `bun:"email"`
+	Username     string     `bun:"username"`
+	LastActiveAt time.Time  `bun:"last_active_at"`
+	NotifiedAt   *time.Time `bun:"dormant_notified_at"`
+}
+
+// DormantAccountStage is where a dormant account is in the notify-then-enforce process
+type DormantAccountStage string
+
+const (
+	// DormantStageNotify accounts are sent the notice on the next run
+	DormantStageNotify DormantAccountStage = "notify"
+	// DormantStageNoticePeriod accounts were notified and are waiting for the notice period to end
+	DormantStageNoticePeriod DormantAccountStage = "notice_period"
+	// DormantStageEnforce accounts get the policy action on the next run
+	DormantStageEnforce DormantAccountStage = "enforce"
+)
+
+// DormantAccount is one account in a dormant account report
+type DormantAccount struct {
+	UserID       uuid.UUID           `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email        string              `json:"email" example:"user@example.com"`
+	Username     string              `json:"username" example:"johndoe"`
+	LastActiveAt time.Time           `json:"last_active_at" example:"2023-01-15T10:30:00Z"`
+	NotifiedAt   *time.Time          `json:"notified_at,omitempty" example:"2024-01-15T10:30:00Z"`
+	EnforceAt    time.Time           `json:"enforce_at" example:"2024-02-14T10:30:00Z"`
+	Stage        DormantAccountStage `json:"stage" example:"notice_period"`
+}
+
+// DormantAccountReport is a dry run of the dormant account policy: what the next run would do
+type DormantAccountReport struct {
+	Policy      *DormantAccountPolicy `json:"policy"`
+	GeneratedAt time.Time             `json:"generated_at" example:"2024-01-15T10:30:00Z"`
+
+	// Accounts that would be sent the notice
+	ToNotify int `json:"to_notify" example:"12"`
+	// Accounts notified and still within the notice period
+	InNoticePeriod int `json:"in_notice_period" example:"4"`
+	// Accounts the action would be applied to
+	ToEnforce int `json:"to_enforce" example:"3"`
+
+	// Dormant accounts; capped, see Truncated
+	Accounts []*DormantAccount `json:"accounts"`
+	// Whether there were more dormant accounts than listed
+	Truncated bool `json:"truncated" example:"false"`
+}
+
+// DormantAccountRunResult summarizes one enforcement run of the dormant account policy
+type DormantAccountRunResult struct {
+	Notified int `json:"notified"`
+	Enforced int `json:"enforced"`
+	Failed   int `json:"failed"`
+}
@@ -126,10 +126,19 @@ const (
 	EmailTemplateTypeCustom          = "custom"
 
 	// Notification template types
-	EmailTemplateTypePasswordChanged = "password_changed"
-	EmailTemplateTypeLoginAlert      = "login_alert"
-	EmailTemplateType2FAEnabled      = "2fa_enabled"
-	EmailTemplateType2FADisabled     = "2fa_disabled"
+	EmailTemplateTypePasswordChanged  = "password_changed"
+	EmailTemplateTypeLoginAlert       = "login_alert"
+	EmailTemplateType2FAEnabled       = "2fa_enabled"
+	EmailTemplateType2FADisabled      = "2fa_disabled"
+	EmailTemplateTypePasswordExpiring = "password_expiring"
+	EmailTemplateTypeAccountRecovery  = "account_recovery"
+	EmailTemplateTypeOrgUsageReport   = "org_usage_report"
+	// EmailTemplateTypeEmailVerificationReminder reminds users with an unverified email address to verify it
+	EmailTemplateTypeEmailVerificationReminder = "email_verification_reminder"
+	// EmailTemplateTypeAccountDormant warns users that their unused account is about to be suspended or removed
+	EmailTemplateTypeAccountDormant = "account_dormant"
+	// EmailTemplateTypeMagicLink carries a one-click sign-in link
+	EmailTemplateTypeMagicLink = "magic_link"
 )
 
 // GetDefaultTemplateVariables returns default variables for each template type
@@ -155,6 +164,18 @@ func GetDefaultTemplateVariables(templateType string) []string {
 		return []string{"username", "email", "timestamp"}
 	case EmailTemplateType2FADisabled:
 		return []string{"username", "email", "timestamp"}
+	case EmailTemplateTypePasswordExpiring:
+		return []string{"username", "email", "expires_at", "days_left"}
+	case EmailTemplateTypeAccountRecovery:
+		return []string{"username", "email", "event", "ip_address", "timestamp"}
+	case EmailTemplateTypeOrgUsageReport:
+		return []string{"organization", "total_members", "active_users", "active_days", "seats_used", "licensed_seats", "mfa_adoption_rate", "generated_at"}
+	case EmailTemplateTypeEmailVerificationReminder:
+		return []string{"username", "email", "deadline"}
+	case EmailTemplateTypeAccountDormant:
+		return []string{"username", "email", "last_active_at", "inactive_days", "action", "enforce_at"}
+	case EmailTemplateTypeMagicLink:
+		return []string{"username", "email", "link", "expiry_minutes"}
 	default:
 		return []string{}
 	}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email verification enforcement levels
+const (
+	// EmailVerificationAdvisory never restricts unverified users; they are only reminded
+	EmailVerificationAdvisory = "advisory"
+	// EmailVerificationGrace gives unverified users limited access for the grace period,
+	// then blocks sign-in until the address is verified
+	EmailVerificationGrace = "grace"
+	// EmailVerificationBlock blocks sign-in until the address is verified
+	EmailVerificationBlock = "block"
+)
+
+// EmailNotVerifiedCode is the machine-readable code returned when an unverified email address blocks a request
+const EmailNotVerifiedCode = "EMAIL_NOT_VERIFIED"
+
+// ErrEmailNotVerified is returned when sign-in or a request requires a verified email address
+var ErrEmailNotVerified = NewAppError(http.StatusForbidden, "Email address is not verified", EmailNotVerifiedCode)
+
+// EmailVerificationReminderEntry describes an unverified user due for a verification reminder
+type EmailVerificationReminderEntry struct {
+	UserID        uuid.UUID `bun:"id"`
+	Email         string    `bun:"email"`
+	Username      string    `bun:"username"`
+	CreatedAt     time.Time `bun:"created_at"`
+	RemindersSent int       `bun:"email_verification_reminders_sent"`
+}
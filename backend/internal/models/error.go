@@ -26,12 +26,14 @@ var (
 	ErrTokenExpired          = &AppError{Code: http.StatusUnauthorized, Message: "Token expired"}
 	ErrTokenRevoked          = &AppError{Code: http.StatusUnauthorized, Message: "Token revoked"}
 	ErrTokenCompromised      = &AppError{Code: http.StatusUnauthorized, Message: "Token may be compromised: device mismatch"}
+	ErrTokenWrongAudience    = &AppError{Code: http.StatusUnauthorized, Message: "Token was not issued for this service"}
 	ErrUnauthorized          = &AppError{Code: http.StatusUnauthorized, Message: "Unauthorized"}
 	ErrForbidden             = &AppError{Code: http.StatusForbidden, Message: "Forbidden"}
 	ErrBadRequest            = &AppError{Code: http.StatusBadRequest, Message: "Bad request"}
 	ErrInternalServer        = &AppError{Code: http.StatusInternalServerError, Message: "Internal server error"}
 	ErrRateLimitExceeded     = &AppError{Code: http.StatusTooManyRequests, Message: "Rate limit exceeded"}
 	ErrInvalidProvider       = &AppError{Code: http.StatusBadRequest, Message: "Invalid OAuth provider"}
+	ErrPasswordReused        = &AppError{Code: http.StatusBadRequest, Message: "Password was used recently. Please choose a different password."}
 
 	// API Key errors
 	ErrAPIKeyNotFound = &AppError{Code: http.StatusNotFound, Message: "API key not found"}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// EventSchemaVersion is the version of the event envelope and of the data of each event type.
+// It only changes on breaking changes; adding fields does not, so consumers must ignore
+// fields they don't know.
+const EventSchemaVersion = 1
+
+// EventSource is the source of every event published by the gateway
+const EventSource = "auth-gateway"
+
+// Events published to the event bus
+const (
+	EventUserSignedIn    = "user.signed_in"
+	EventUserSignedUp    = "user.signed_up"
+	EventTokenRevoked    = "token.revoked"
+	EventUserRoleChanged = "user.role_changed"
+)
+
+// GetBusEvents returns all events published to the event bus
+func GetBusEvents() []string {
+	return []string{
+		EventUserSignedIn,
+		EventUserSignedUp,
+		EventTokenRevoked,
+		EventUserRoleChanged,
+	}
+}
+
+// Event is the JSON envelope of an event published to the event bus. Delivery is
+// at-least-once, so consumers should deduplicate on ID.
+type Event struct {
+	SchemaVersion int                    `json:"schema_version"`
+	ID            string                 `json:"id"`
+	Type          string                 `json:"type"`
+	Source        string                 `json:"source"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+	Data          map[string]interface{} `json:"data"`
+}
@@ -40,6 +40,9 @@ type Group struct {
 	// This is a JSON array of permission IDs
 	PermissionIDs []uuid.UUID `json:"permission_ids,omitempty" bun:"permission_ids,type:uuid[]"`
 
+	// Maximum password age in days for members of this group (nil = no policy)
+	PasswordMaxAgeDays *int `json:"password_max_age_days,omitempty" bun:"password_max_age_days" example:"90"`
+
 	// Timestamp when group was created
 	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 
@@ -75,6 +78,15 @@ type UserGroup struct {
 	Group *Group `json:"group,omitempty" bun:"rel:belongs-to,join:group_id=id"`
 }
 
+// GroupRole maps a group to a role granted to every member of the group and of its child groups
+type GroupRole struct {
+	bun.BaseModel `bun:"table:group_roles,alias:gr"`
+
+	GroupID   uuid.UUID `json:"group_id" bun:"group_id,pk,type:uuid"`
+	RoleID    uuid.UUID `json:"role_id" bun:"role_id,pk,type:uuid"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
 // CreateGroupRequest represents a request to create a new group
 type CreateGroupRequest struct {
 	// Group name (unique identifier)
@@ -129,3 +141,61 @@ type GroupListResponse struct {
 	PageSize   int             `json:"page_size" example:"20"`
 	TotalPages int             `json:"total_pages"`
 }
+
+// SetGroupRolesRequest replaces the roles mapped to a group
+type SetGroupRolesRequest struct {
+	// Role IDs granted to members of the group and its child groups
+	RoleIDs []uuid.UUID `json:"role_ids" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// GroupRolesResponse lists the roles a group grants to its members
+type GroupRolesResponse struct {
+	GroupID uuid.UUID `json:"group_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+
+	// Roles mapped to the group itself
+	Roles []Role `json:"roles"`
+
+	// Roles mapped to parent groups, which members inherit as well
+	InheritedRoles []Role `json:"inherited_roles"`
+}
+
+// GroupAdmin delegates administration of a group, treated as an organization, to a user.
+// Organization admins manage the members of the group and of its child groups.
+type GroupAdmin struct {
+	bun.BaseModel `bun:"table:group_admins,alias:ga"`
+
+	GroupID   uuid.UUID  `json:"group_id" bun:"group_id,pk,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID    uuid.UUID  `json:"user_id" bun:"user_id,pk,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" bun:"created_by,type:uuid"`
+	CreatedAt time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+
+	User *User `json:"user,omitempty" bun:"rel:belongs-to,join:user_id=id"`
+}
+
+// AddGroupAdminRequest designates a user as organization admin of a group
+type AddGroupAdminRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// GroupAdminListResponse lists the organization admins of a group
+type GroupAdminListResponse struct {
+	Admins []*GroupAdmin `json:"admins"`
+}
+
+// OrgUpdateUserRequest is the subset of user fields an organization admin may change.
+// Email, verification flags and roles are managed through dedicated endpoints.
+type OrgUpdateUserRequest struct {
+	// Unique username
+	Username *string `json:"username,omitempty" example:"johndoe"`
+	// User's full name
+	FullName *string `json:"full_name,omitempty" example:"John Doe"`
+	// User's phone number
+	Phone *string `json:"phone,omitempty" example:"+1234567890"`
+	// Whether the account should be active
+	IsActive *bool `json:"is_active,omitempty" example:"true"`
+}
+
+// OrgListResponse lists the organizations the current user administers
+type OrgListResponse struct {
+	Organizations []GroupResponse `json:"organizations"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// AuthMethodGuest is the application auth method that allows anonymous guest sessions
+const AuthMethodGuest = "guest"
+
+// GuestUsernamePrefix prefixes the generated usernames of guest accounts
+const GuestUsernamePrefix = "guest-"
+
+// UpgradeGuestRequest represents a request to turn a guest account into a full account
+type UpgradeGuestRequest struct {
+	// Email address (optional if phone is provided)
+	Email string `json:"email,omitempty" binding:"omitempty,email" example:"user@example.com"`
+	// Phone number in E.164 format (optional if email is provided)
+	Phone *string `json:"phone,omitempty" example:"+1234567890"`
+	// Unique username (3-100 characters)
+	Username string `json:"username" binding:"required,min=3,max=100" example:"johndoe"`
+	// Password (minimum 8 characters)
+	Password string `json:"password" binding:"required,min=8" example:"SecurePass123!"`
+	// Full name (optional)
+	FullName string `json:"full_name,omitempty" example:"John Doe"`
+}
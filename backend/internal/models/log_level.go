@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SetLogLevelRequest changes log levels at runtime until they revert
+type SetLogLevelRequest struct {
+	// Level of loggers outside the listed modules (empty keeps the configured level)
+	Level string `json:"level" binding:"omitempty,oneof=debug info warn error" example:"debug"`
+	// Levels of individual modules, see available_modules
+	Modules map[string]string `json:"modules,omitempty" example:"grpc:debug"`
+	// Revert to the configured levels after this many seconds (0 = server default)
+	DurationSeconds int `json:"duration_seconds" binding:"min=0,max=86400" example:"900"`
+}
+
+// LogLevelOverride is a runtime change of log levels, shared between gateway instances
+type LogLevelOverride struct {
+	Level     string            `json:"level,omitempty"`
+	Modules   map[string]string `json:"modules,omitempty"`
+	RevertsAt time.Time         `json:"reverts_at"`
+}
+
+// LogLevelResponse describes the current log levels
+type LogLevelResponse struct {
+	// Level of loggers outside modules with their own level
+	Level string `json:"level" example:"debug"`
+	// Level from the LOG_LEVEL setting, restored on revert
+	DefaultLevel string `json:"default_level" example:"info"`
+	// Modules with a level of their own
+	Modules map[string]string `json:"modules" example:"grpc:debug"`
+	// Modules whose level can be set
+	AvailableModules []string `json:"available_modules" example:"grpc,http,ldap,oidc,saml,scim"`
+	// When the levels revert to the configured ones; omitted when they are not changed
+	RevertsAt *time.Time `json:"reverts_at,omitempty" example:"2024-01-15T10:45:00Z"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthMethodMagicLink is the application auth method that allows signing in by emailed links
+const AuthMethodMagicLink = "magic_link"
+
+// Errors a magic link click reports to the app in the error query parameter
+const (
+	// MagicLinkErrorInvalid means the link is unknown, expired or already used
+	MagicLinkErrorInvalid = "invalid_link"
+	// MagicLinkErrorDenied means the account may not sign in
+	MagicLinkErrorDenied = "access_denied"
+	// MagicLinkErrorServer means the sign-in failed for an internal reason
+	MagicLinkErrorServer = "server_error"
+)
+
+// RequestMagicLinkRequest asks for a sign-in link to be emailed
+type RequestMagicLinkRequest struct {
+	// Email address of the account
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	// App URL to land on after the link is clicked; must be allowed for the application.
+	// Defaults to the configured redirect URL.
+	RedirectURL string `json:"redirect_url,omitempty" binding:"omitempty,url" example:"https://app.example.com/auth/magic-link"`
+}
+
+// CompleteMagicLinkRequest exchanges the code the app received on its redirect URL for the session
+type CompleteMagicLinkRequest struct {
+	Code string `json:"code" binding:"required" example:"Zk3Yw1e2..."`
+}
+
+// MagicLinkToken is what an emailed link stands for until it is clicked
+type MagicLinkToken struct {
+	UserID        uuid.UUID  `json:"user_id"`
+	ApplicationID *uuid.UUID `json:"application_id,omitempty"`
+	RedirectURL   string     `json:"redirect_url"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
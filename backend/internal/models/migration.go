@@ -24,8 +24,8 @@ type ImportOAuthEntry struct {
 	Email          string `json:"email" binding:"required,email"`
 	Provider       string `json:"provider" binding:"required"`
 	ProviderUserID string `json:"provider_user_id" binding:"required"`
-	AccessToken    string `json:"access_token"`
-	RefreshToken   string `json:"refresh_token"`
+	AccessToken    string `json:"access_token"`  // Deprecated: ignored, provider tokens are not imported
+	RefreshToken   string `json:"refresh_token"` // Deprecated: ignored, provider tokens are not imported
 }
 
 type ImportRolesRequest struct {
@@ -32,12 +32,41 @@ const (
 	ProviderInstagram OAuthProvider = "instagram"
 	ProviderTelegram  OAuthProvider = "telegram"
 	ProviderOneC      OAuthProvider = "onec"
+	ProviderApple     OAuthProvider = "apple"
+	ProviderMicrosoft OAuthProvider = "microsoft"
+	ProviderLinkedIn  OAuthProvider = "linkedin"
+	// ProviderOIDCGeneric is any OpenID Connect provider configured by its issuer
+	ProviderOIDCGeneric OAuthProvider = "oidc-generic"
 )
 
 // IsValidProvider checks if a provider is valid
 func IsValidProvider(provider string) bool {
 	switch OAuthProvider(provider) {
-	case ProviderGoogle, ProviderYandex, ProviderGitHub, ProviderInstagram, ProviderTelegram, ProviderOneC:
+	case ProviderGoogle, ProviderYandex, ProviderGitHub, ProviderInstagram, ProviderTelegram, ProviderOneC,
+		ProviderApple, ProviderMicrosoft, ProviderLinkedIn, ProviderOIDCGeneric:
+		return true
+	default:
+		return false
+	}
+}
+
+// Strategies for a social login whose provider email belongs to an existing account
+// that is not linked to the provider account yet
+const (
+	// OAuthEmailCollisionReject refuses the login; the user has to sign in another way
+	OAuthEmailCollisionReject = "reject"
+	// OAuthEmailCollisionAutoLink links the provider account when both the provider and
+	// the existing account verified the email, and rejects the login otherwise
+	OAuthEmailCollisionAutoLink = "auto_link"
+	// OAuthEmailCollisionConfirmPassword links the provider account once the user
+	// confirms the password of the existing account
+	OAuthEmailCollisionConfirmPassword = "confirm_password"
+)
+
+// IsValidOAuthEmailCollision checks if an email collision strategy is valid
+func IsValidOAuthEmailCollision(strategy string) bool {
+	switch strategy {
+	case OAuthEmailCollisionReject, OAuthEmailCollisionAutoLink, OAuthEmailCollisionConfirmPassword:
 		return true
 	default:
 		return false
@@ -56,6 +85,7 @@ type OAuthProviderInfo struct {
 type OAuthUserInfo struct {
 	ProviderUserID string
 	Email          string
+	EmailVerified  bool // Whether the provider verified the email
 	Name           string
 	Username       string
 	ProfilePicture string
@@ -78,11 +108,13 @@ type OTP struct {
 type OTPType string
 
 const (
-	OTPTypeVerification  OTPType = "verification"
-	OTPTypePasswordReset OTPType = "password_reset"
-	OTPType2FA           OTPType = "2fa"
-	OTPTypeLogin         OTPType = "login"
-	OTPTypeRegistration  OTPType = "registration"
+	OTPTypeVerification    OTPType = "verification"
+	OTPTypePasswordReset   OTPType = "password_reset"
+	OTPType2FA             OTPType = "2fa"
+	OTPTypeLogin           OTPType = "login"
+	OTPTypeRegistration    OTPType = "registration"
+	OTPTypeAccountRecovery OTPType = "account_recovery"
+	OTPTypeSecondaryEmail  OTPType = "secondary_email"
 )
 
 // IsExpired checks if OTP is expired
@@ -151,4 +183,32 @@ type OAuthLoginResponse struct {
 	User *User `json:"user"`
 	// Whether this is a newly created user
 	IsNewUser bool `json:"is_new_user" example:"false"`
+	// Set when the provider email belongs to an existing account that has to be linked
+	// by confirming its password; no tokens are issued in that case
+	LinkRequired bool `json:"link_required,omitempty" example:"false"`
+	// Token for POST /api/auth/oauth/link/confirm (only when link_required is set)
+	LinkToken string `json:"link_token,omitempty"`
+}
+
+// ConfirmOAuthLinkRequest links a provider account to an existing account
+type ConfirmOAuthLinkRequest struct {
+	// Link token from the OAuth login response
+	LinkToken string `json:"link_token" binding:"required"`
+	// Password of the existing account
+	Password string `json:"password" binding:"required" example:"SecurePass123!"`
+	// Code from the authenticator app, required when the account has 2FA enabled
+	TOTPCode string `json:"totp_code,omitempty" example:"123456"`
+}
+
+// ProviderTokenResponse is a provider access token that applications can use
+// to call the provider's API on behalf of the user
+type ProviderTokenResponse struct {
+	// OAuth provider the token was issued by
+	Provider string `json:"provider" example:"google"`
+	// Provider access token
+	AccessToken string `json:"access_token" example:"ya29.a0AfH6SMBxxxxxxxxxxxxxxxxxxx"`
+	// Token type, usually Bearer
+	TokenType string `json:"token_type" example:"Bearer"`
+	// When the access token expires, if the provider reported it
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Limits of a client's claims mapping, so mapped claims cannot bloat every token
+const (
+	MaxMappedClaims      = 50
+	MaxStaticClaimsBytes = 4096
+)
+
+// ClaimsMapping configures the custom claims an OAuth client receives in its ID tokens and
+// userinfo responses, on top of the standard claims of the granted scopes
+type ClaimsMapping struct {
+	// Namespace is prepended to every custom claim name, e.g. to avoid collisions with claims
+	// the relying party already uses
+	Namespace string `json:"namespace,omitempty" example:"https://example.com/"`
+	// Static claims with fixed values
+	Static map[string]interface{} `json:"static,omitempty" swaggertype:"object"`
+	// Attributes maps claim names to user attributes, see ClaimAttributes
+	Attributes map[string]string `json:"attributes,omitempty" example:"login:username"`
+	// RolesClaim and GroupsClaim name the claims listing the user's roles and groups; empty
+	// leaves them out
+	RolesClaim  string `json:"roles_claim,omitempty" example:"roles"`
+	GroupsClaim string `json:"groups_claim,omitempty" example:"groups"`
+}
+
+// ClaimAttributes are the user attributes a claims mapping can use
+var ClaimAttributes = map[string]func(*User) interface{}{
+	"id":                  func(u *User) interface{} { return u.ID.String() },
+	"email":               func(u *User) interface{} { return u.Email },
+	"email_verified":      func(u *User) interface{} { return u.EmailVerified },
+	"username":            func(u *User) interface{} { return u.Username },
+	"full_name":           func(u *User) interface{} { return u.FullName },
+	"phone":               func(u *User) interface{} { return stringValue(u.Phone) },
+	"phone_verified":      func(u *User) interface{} { return u.PhoneVerified },
+	"profile_picture_url": func(u *User) interface{} { return u.ProfilePictureURL },
+	"account_type":        func(u *User) interface{} { return u.AccountType },
+	"created_at":          func(u *User) interface{} { return u.CreatedAt.Unix() },
+}
+
+// reservedClaims are set by the provider itself and cannot be mapped
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"auth_time": true, "nonce": true, "acr": true, "amr": true, "azp": true, "at_hash": true,
+	"c_hash": true, "sid": true, "cnf": true, "scope": true, "client_id": true,
+	"name": true, "given_name": true, "family_name": true, "middle_name": true, "nickname": true,
+	"preferred_username": true, "profile": true, "picture": true, "website": true, "gender": true,
+	"birthdate": true, "zoneinfo": true, "locale": true, "updated_at": true, "address": true,
+	"email": true, "email_verified": true, "phone_number": true, "phone_number_verified": true,
+}
+
+// IsEmpty reports whether the mapping adds no claims
+func (m *ClaimsMapping) IsEmpty() bool {
+	return m == nil || (len(m.Static) == 0 && len(m.Attributes) == 0 && m.RolesClaim == "" && m.GroupsClaim == "")
+}
+
+// Validate checks the claim names, which must be unique and must not override standard
+// claims once namespaced, and the attributes
+func (m *ClaimsMapping) Validate() error {
+	if m == nil {
+		return nil
+	}
+	if strings.ContainsAny(m.Namespace, " \t\r\n") {
+		return fmt.Errorf("namespace must not contain whitespace")
+	}
+
+	names := make(map[string]bool)
+	add := func(name string) error {
+		if name == "" || strings.ContainsAny(name, " \t\r\n") {
+			return fmt.Errorf("claim names must be non-empty and without whitespace")
+		}
+		full := m.Namespace + name
+		if reservedClaims[full] {
+			return fmt.Errorf("claim %q is reserved", full)
+		}
+		if names[full] {
+			return fmt.Errorf("claim %q is mapped more than once", full)
+		}
+		names[full] = true
+		return nil
+	}
+
+	for name := range m.Static {
+		if err := add(name); err != nil {
+			return err
+		}
+	}
+	if len(m.Static) > 0 {
+		data, err := json.Marshal(m.Static)
+		if err != nil {
+			return fmt.Errorf("static claims must be JSON values")
+		}
+		if len(data) > MaxStaticClaimsBytes {
+			return fmt.Errorf("static claims must be at most %d bytes", MaxStaticClaimsBytes)
+		}
+	}
+	for name, attribute := range m.Attributes {
+		if err := add(name); err != nil {
+			return err
+		}
+		if _, ok := ClaimAttributes[attribute]; !ok {
+			return fmt.Errorf("unknown user attribute %q", attribute)
+		}
+	}
+	for _, name := range []string{m.RolesClaim, m.GroupsClaim} {
+		if name == "" {
+			continue
+		}
+		if err := add(name); err != nil {
+			return err
+		}
+	}
+
+	if len(names) > MaxMappedClaims {
+		return fmt.Errorf("at most %d claims can be mapped", MaxMappedClaims)
+	}
+	return nil
+}
+
+// Claims returns the custom claims for user, given the names of their roles and groups
+func (m *ClaimsMapping) Claims(user *User, roles, groups []string) map[string]interface{} {
+	if m.IsEmpty() {
+		return nil
+	}
+	claims := make(map[string]interface{}, len(m.Static)+len(m.Attributes)+2)
+	for name, value := range m.Static {
+		claims[m.Namespace+name] = value
+	}
+	for name, attribute := range m.Attributes {
+		if value, ok := ClaimAttributes[attribute]; ok {
+			claims[m.Namespace+name] = value(user)
+		}
+	}
+	if m.RolesClaim != "" {
+		claims[m.Namespace+m.RolesClaim] = nonNilStrings(roles)
+	}
+	if m.GroupsClaim != "" {
+		claims[m.Namespace+m.GroupsClaim] = nonNilStrings(groups)
+	}
+	return claims
+}
+
+// MergeClaims adds custom claims to a JSON object of claims, keeping the claims it already has
+func MergeClaims(data []byte, custom map[string]interface{}) ([]byte, error) {
+	claims := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, err
+	}
+	for name, value := range custom {
+		if _, exists := claims[name]; !exists {
+			claims[name] = value
+		}
+	}
+	return json.Marshal(claims)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// nonNilStrings makes an empty list marshal as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsMapping_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		m := &ClaimsMapping{
+			Namespace:   "https://example.com/",
+			Static:      map[string]interface{}{"tenant": "acme"},
+			Attributes:  map[string]string{"email": "email"},
+			RolesClaim:  "roles",
+			GroupsClaim: "groups",
+		}
+		assert.NoError(t, m.Validate())
+	})
+
+	t.Run("Reserved claim", func(t *testing.T) {
+		m := &ClaimsMapping{Static: map[string]interface{}{"sub": "admin"}}
+		assert.Error(t, m.Validate())
+	})
+
+	t.Run("Duplicate claim", func(t *testing.T) {
+		m := &ClaimsMapping{Attributes: map[string]string{"roles": "username"}, RolesClaim: "roles"}
+		assert.Error(t, m.Validate())
+	})
+
+	t.Run("Unknown attribute", func(t *testing.T) {
+		m := &ClaimsMapping{Attributes: map[string]string{"secret": "password_hash"}}
+		assert.Error(t, m.Validate())
+	})
+
+	t.Run("Oversized static claims", func(t *testing.T) {
+		m := &ClaimsMapping{Static: map[string]interface{}{"blob": strings.Repeat("x", MaxStaticClaimsBytes)}}
+		assert.Error(t, m.Validate())
+	})
+}
+
+func TestClaimsMapping_Claims(t *testing.T) {
+	user := &User{ID: uuid.New(), Username: "jane"}
+	m := &ClaimsMapping{
+		Namespace:   "https://example.com/",
+		Static:      map[string]interface{}{"tenant": "acme"},
+		Attributes:  map[string]string{"login": "username"},
+		RolesClaim:  "roles",
+		GroupsClaim: "groups",
+	}
+
+	claims := m.Claims(user, []string{"editor"}, nil)
+
+	assert.Equal(t, map[string]interface{}{
+		"https://example.com/tenant": "acme",
+		"https://example.com/login":  "jane",
+		"https://example.com/roles":  []string{"editor"},
+		"https://example.com/groups": []string{},
+	}, claims)
+	assert.Nil(t, (*ClaimsMapping)(nil).Claims(user, nil, nil))
+}
+
+func TestMergeClaims(t *testing.T) {
+	data, err := MergeClaims([]byte(`{"sub":"123","exp":1700000000}`), map[string]interface{}{
+		"sub":    "override",
+		"tenant": "acme",
+	})
+	require.NoError(t, err)
+
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &claims))
+	assert.Equal(t, "123", claims["sub"], "existing claims should win")
+	assert.Equal(t, "acme", claims["tenant"])
+	assert.Contains(t, string(data), `"exp":1700000000`, "numbers should keep their precision")
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ClientLogoPath is the URL path under which uploaded OAuth client logos are served
+const ClientLogoPath = "/oauth/logos/"
+
+// OAuthClientLogo is an OAuth client logo uploaded to and served by the gateway.
+// Logos are addressed by content hash, so a URL never changes what it serves.
+type OAuthClientLogo struct {
+	bun.BaseModel `bun:"table:oauth_client_logos"`
+
+	ClientID    uuid.UUID `json:"client_id" bun:"client_id,pk,type:uuid"`
+	Hash        string    `json:"hash" bun:"hash,notnull"`
+	ContentType string    `json:"content_type" bun:"content_type,notnull"`
+	Content     []byte    `json:"-" bun:"content,type:bytea,notnull"`
+	Size        int       `json:"size" bun:"size,notnull"`
+	CreatedAt   time.Time `json:"created_at" bun:"created_at,default:current_timestamp"`
+}
+
+// IsManagedLogoURL reports whether logoURL points to a logo served by this gateway, either
+// as a path or as an absolute URL under baseURL
+func IsManagedLogoURL(logoURL, baseURL string) bool {
+	if strings.HasPrefix(logoURL, ClientLogoPath) {
+		return true
+	}
+	return baseURL != "" && strings.HasPrefix(logoURL, strings.TrimSuffix(baseURL, "/")+ClientLogoPath)
+}
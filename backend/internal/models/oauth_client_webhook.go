@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Events an OAuth client owner can subscribe to. They only ever carry data about the owner's client.
+const (
+	OAuthClientEventConsentGranted = "oauth_client.consent_granted"
+	OAuthClientEventConsentRevoked = "oauth_client.consent_revoked"
+	OAuthClientEventTokenRevoked   = "oauth_client.token_revoked"
+	OAuthClientEventSecretRotated  = "oauth_client.secret_rotated"
+)
+
+// GetOAuthClientWebhookEvents returns all events OAuth client webhooks can subscribe to
+func GetOAuthClientWebhookEvents() []string {
+	return []string{
+		OAuthClientEventConsentGranted,
+		OAuthClientEventConsentRevoked,
+		OAuthClientEventTokenRevoked,
+		OAuthClientEventSecretRotated,
+	}
+}
+
+// OAuthClientWebhook is a webhook registered by the owner of an OAuth client. It is kept apart from
+// the admin webhooks and only receives events about its own client.
+type OAuthClientWebhook struct {
+	bun.BaseModel `bun:"table:oauth_client_webhooks,alias:ocw"`
+
+	ID              uuid.UUID  `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ClientID        uuid.UUID  `json:"client_id" bun:"client_id,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	URL             string     `json:"url" bun:"url,notnull" example:"https://api.example.com/webhooks/oauth"`
+	SecretKey       string     `json:"-" bun:"secret_key,notnull"`
+	Events          []string   `json:"events" bun:"events,type:jsonb,notnull" example:"oauth_client.consent_granted,oauth_client.token_revoked"`
+	IsActive        bool       `json:"is_active" bun:"is_active,notnull" example:"true"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty" bun:"created_by,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt       time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty" bun:"last_triggered_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// Subscribes reports whether the webhook receives eventType
+func (w *OAuthClientWebhook) Subscribes(eventType string) bool {
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthClientWebhookDelivery is one attempt to deliver an event to an OAuth client webhook
+type OAuthClientWebhookDelivery struct {
+	bun.BaseModel `bun:"table:oauth_client_webhook_deliveries,alias:ocwd"`
+
+	ID             uuid.UUID       `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WebhookID      uuid.UUID       `json:"webhook_id" bun:"webhook_id,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EventType      string          `json:"event_type" bun:"event_type,notnull" example:"oauth_client.consent_granted"`
+	Payload        json.RawMessage `json:"payload" bun:"payload,type:jsonb,notnull" swaggertype:"object"`
+	Status         string          `json:"status" bun:"status,notnull" example:"success"` // "pending", "success", "failed"
+	HTTPStatusCode *int            `json:"http_status_code,omitempty" bun:"http_status_code" example:"200"`
+	ResponseBody   string          `json:"response_body,omitempty" bun:"response_body"`
+	CreatedAt      time.Time       `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty" bun:"completed_at" example:"2024-01-15T10:30:01Z"`
+}
+
+// CreateOAuthClientWebhookRequest registers a webhook for an OAuth client
+type CreateOAuthClientWebhookRequest struct {
+	// HTTPS endpoint the events are posted to (max 500 characters)
+	URL string `json:"url" binding:"required,url,max=500" example:"https://api.example.com/webhooks/oauth"`
+	// Events to subscribe to
+	Events []string `json:"events" binding:"required,min=1" example:"oauth_client.consent_granted,oauth_client.token_revoked"`
+}
+
+// UpdateOAuthClientWebhookRequest updates an OAuth client webhook; omitted fields keep their value
+type UpdateOAuthClientWebhookRequest struct {
+	// HTTPS endpoint the events are posted to (max 500 characters)
+	URL string `json:"url,omitempty" binding:"omitempty,url,max=500" example:"https://api.example.com/webhooks/oauth"`
+	// Events to subscribe to
+	Events []string `json:"events,omitempty" example:"oauth_client.consent_revoked"`
+	// Whether the webhook is active
+	IsActive *bool `json:"is_active,omitempty" example:"true"`
+}
+
+// CreateOAuthClientWebhookResponse contains the created webhook and its secret key
+type CreateOAuthClientWebhookResponse struct {
+	// Created webhook
+	Webhook *OAuthClientWebhook `json:"webhook"`
+	// Secret key for webhook signature verification (only shown once)
+	SecretKey string `json:"secret_key" example:"5f2b...c9a1"`
+}
+
+// OAuthClientWebhookListResponse contains the webhooks of an OAuth client
+type OAuthClientWebhookListResponse struct {
+	// Webhooks, oldest first
+	Webhooks []*OAuthClientWebhook `json:"webhooks"`
+	// Events the webhooks can subscribe to
+	AvailableEvents []string `json:"available_events" example:"oauth_client.consent_granted,oauth_client.consent_revoked"`
+}
+
+// OAuthClientWebhookDeliveryListResponse contains paginated deliveries of an OAuth client webhook
+type OAuthClientWebhookDeliveryListResponse struct {
+	// Deliveries, newest first
+	Deliveries []*OAuthClientWebhookDelivery `json:"deliveries"`
+	// Total number of deliveries
+	Total int `json:"total" example:"150"`
+	// Current page number
+	Page int `json:"page" example:"1"`
+	// Number of items per page
+	PageSize int `json:"page_size" example:"20"`
+	// Total number of pages
+	TotalPages int `json:"total_pages" example:"8"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,30 +12,41 @@ import (
 type OAuthClient struct {
 	bun.BaseModel `bun:"table:oauth_clients"`
 
-	ID                uuid.UUID    `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
-	ClientID          string       `json:"client_id" bun:"client_id,notnull,unique" example:"my_client_app_123"`
-	ClientSecretHash  *string      `json:"-" bun:"client_secret_hash"`
-	Name              string       `json:"name" bun:"name,notnull" example:"My Application"`
-	Description       string       `json:"description,omitempty" bun:"description" example:"My OAuth client application"`
-	LogoURL           string       `json:"logo_url,omitempty" bun:"logo_url" example:"https://example.com/logo.png"`
-	ClientType        string       `json:"client_type" bun:"client_type,notnull,default:'confidential'" example:"confidential"`
-	RedirectURIs      []string     `json:"redirect_uris" bun:"redirect_uris,type:jsonb,default:'[]'" example:"https://example.com/callback"`
-	AllowedGrantTypes []string     `json:"allowed_grant_types" bun:"allowed_grant_types,type:jsonb" example:"authorization_code,refresh_token"`
-	AllowedScopes     []string     `json:"allowed_scopes" bun:"allowed_scopes,type:jsonb" example:"openid,profile,email"`
-	DefaultScopes     []string     `json:"default_scopes" bun:"default_scopes,type:jsonb" example:"openid,profile"`
-	AccessTokenTTL    int          `json:"access_token_ttl" bun:"access_token_ttl,default:900" example:"900"`
-	RefreshTokenTTL   int          `json:"refresh_token_ttl" bun:"refresh_token_ttl,default:604800" example:"604800"`
-	IDTokenTTL        int          `json:"id_token_ttl" bun:"id_token_ttl,default:3600" example:"3600"`
-	RequirePKCE       bool         `json:"require_pkce" bun:"require_pkce,default:false" example:"true"`
-	RequireConsent    bool         `json:"require_consent" bun:"require_consent,default:true" example:"true"`
-	FirstParty        bool         `json:"first_party" bun:"first_party,default:false" example:"false"`
-	OwnerID           *uuid.UUID   `json:"owner_id,omitempty" bun:"owner_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Owner             *User        `json:"owner,omitempty" bun:"rel:belongs-to,join:owner_id=id"`
-	ApplicationID     *uuid.UUID   `json:"application_id,omitempty" bun:"application_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Application       *Application `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
-	IsActive          bool         `json:"is_active" bun:"is_active,default:true" example:"true"`
-	CreatedAt         time.Time    `json:"created_at" bun:"created_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
-	UpdatedAt         time.Time    `json:"updated_at" bun:"updated_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	ID               uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ClientID         string    `json:"client_id" bun:"client_id,notnull,unique" example:"my_client_app_123"`
+	ClientSecretHash *string   `json:"-" bun:"client_secret_hash"`
+	Name             string    `json:"name" bun:"name,notnull" example:"My Application"`
+	Description      string    `json:"description,omitempty" bun:"description" example:"My OAuth client application"`
+	LogoURL          string    `json:"logo_url,omitempty" bun:"logo_url" example:"https://example.com/logo.png"`
+	ClientType       string    `json:"client_type" bun:"client_type,notnull,default:'confidential'" example:"confidential"`
+	RedirectURIs     []string  `json:"redirect_uris" bun:"redirect_uris,type:jsonb,default:'[]'" example:"https://example.com/callback"`
+	// Receives logout tokens when a user signed in to the client logs out (OIDC Back-Channel Logout)
+	BackchannelLogoutURI string `json:"backchannel_logout_uri,omitempty" bun:"backchannel_logout_uri,notnull,default:''" example:"https://example.com/backchannel-logout"`
+	// Where users may be sent back to after logging out at the end session endpoint
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris" bun:"post_logout_redirect_uris,type:jsonb,default:'[]'" example:"https://example.com/signed-out"`
+	// Loaded in an iframe when a user signed in to the client logs out there (OIDC Front-Channel Logout)
+	FrontchannelLogoutURI string       `json:"frontchannel_logout_uri,omitempty" bun:"frontchannel_logout_uri,notnull,default:''" example:"https://example.com/frontchannel-logout"`
+	AllowedGrantTypes     []string     `json:"allowed_grant_types" bun:"allowed_grant_types,type:jsonb" example:"authorization_code,refresh_token"`
+	AllowedScopes         []string     `json:"allowed_scopes" bun:"allowed_scopes,type:jsonb" example:"openid,profile,email"`
+	DefaultScopes         []string     `json:"default_scopes" bun:"default_scopes,type:jsonb" example:"openid,profile"`
+	AccessTokenTTL        int          `json:"access_token_ttl" bun:"access_token_ttl,default:900" example:"900"`
+	RefreshTokenTTL       int          `json:"refresh_token_ttl" bun:"refresh_token_ttl,default:604800" example:"604800"`
+	IDTokenTTL            int          `json:"id_token_ttl" bun:"id_token_ttl,default:3600" example:"3600"`
+	RequirePKCE           bool         `json:"require_pkce" bun:"require_pkce,default:false" example:"true"`
+	RequireConsent        bool         `json:"require_consent" bun:"require_consent,default:true" example:"true"`
+	FirstParty            bool         `json:"first_party" bun:"first_party,default:false" example:"false"`
+	CertBoundAccessTokens bool         `json:"tls_client_certificate_bound_access_tokens" bun:"tls_client_certificate_bound_access_tokens,notnull,default:false" example:"false"`
+	RequireRefreshNonce   bool         `json:"require_refresh_nonce" bun:"require_refresh_nonce,notnull,default:false" example:"false"`
+	OwnerID               *uuid.UUID   `json:"owner_id,omitempty" bun:"owner_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Owner                 *User        `json:"owner,omitempty" bun:"rel:belongs-to,join:owner_id=id"`
+	ApplicationID         *uuid.UUID   `json:"application_id,omitempty" bun:"application_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Application           *Application `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
+	IsActive              bool         `json:"is_active" bun:"is_active,default:true" example:"true"`
+	CreatedAt             time.Time    `json:"created_at" bun:"created_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt             time.Time    `json:"updated_at" bun:"updated_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+
+	// Custom claims added to the client's ID tokens and userinfo responses; nil adds none
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty" bun:"claims_mapping,type:jsonb"`
 }
 
 // ClientType represents the OAuth 2.0 client type
@@ -92,6 +104,8 @@ type OAuthAccessToken struct {
 	ExpiresAt time.Time    `json:"expires_at" bun:"expires_at,notnull" example:"2024-01-15T10:45:00Z"`
 	CreatedAt time.Time    `json:"created_at" bun:"created_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 	RevokedAt *time.Time   `json:"revoked_at,omitempty" bun:"revoked_at" example:"2024-01-15T11:00:00Z"`
+	// Thumbprint of the client certificate the token is bound to (RFC 8705)
+	CertThumbprint *string `json:"-" bun:"cert_thumbprint"`
 }
 
 // OAuthRefreshToken represents an OAuth 2.0 refresh token
@@ -111,6 +125,10 @@ type OAuthRefreshToken struct {
 	ExpiresAt     time.Time         `json:"expires_at" bun:"expires_at,notnull" example:"2024-01-22T10:30:00Z"`
 	CreatedAt     time.Time         `json:"created_at" bun:"created_at,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 	RevokedAt     *time.Time        `json:"revoked_at,omitempty" bun:"revoked_at" example:"2024-01-15T11:00:00Z"`
+	// Thumbprint of the client certificate the token is bound to (RFC 8705)
+	CertThumbprint *string `json:"-" bun:"cert_thumbprint"`
+	// Hash of the refresh nonce issued with the token, for clients requiring one
+	NonceHash *string `json:"-" bun:"nonce_hash"`
 }
 
 // UserConsent represents a user's consent to an OAuth client accessing their data
@@ -182,20 +200,29 @@ const (
 
 // CreateOAuthClientRequest represents a request to create a new OAuth client
 type CreateOAuthClientRequest struct {
-	Name              string   `json:"name" binding:"required,min=3,max=100" example:"My Application"`
-	Description       string   `json:"description,omitempty" example:"My OAuth client application"`
-	LogoURL           string   `json:"logo_url,omitempty" example:"https://example.com/logo.png"`
-	ClientType        string   `json:"client_type" binding:"required,oneof=confidential public" example:"confidential"`
-	RedirectURIs      []string `json:"redirect_uris" binding:"dive,url" example:"https://example.com/callback"`
-	AllowedGrantTypes []string `json:"allowed_grant_types" binding:"required,min=1" example:"authorization_code,refresh_token"`
-	AllowedScopes     []string `json:"allowed_scopes" binding:"required,min=1" example:"openid,profile,email"`
-	DefaultScopes     []string `json:"default_scopes,omitempty" example:"openid,profile"`
-	AccessTokenTTL    *int     `json:"access_token_ttl,omitempty" example:"900"`
-	RefreshTokenTTL   *int     `json:"refresh_token_ttl,omitempty" example:"604800"`
-	IDTokenTTL        *int     `json:"id_token_ttl,omitempty" example:"3600"`
-	RequirePKCE       *bool    `json:"require_pkce,omitempty" example:"true"`
-	RequireConsent    *bool    `json:"require_consent,omitempty" example:"true"`
-	FirstParty        *bool    `json:"first_party,omitempty" example:"false"`
+	Name                   string   `json:"name" binding:"required,min=3,max=100" example:"My Application"`
+	Description            string   `json:"description,omitempty" example:"My OAuth client application"`
+	LogoURL                string   `json:"logo_url,omitempty" example:"https://example.com/logo.png"`
+	ClientType             string   `json:"client_type" binding:"required,oneof=confidential public" example:"confidential"`
+	RedirectURIs           []string `json:"redirect_uris" binding:"dive,url" example:"https://example.com/callback"`
+	BackchannelLogoutURI   string   `json:"backchannel_logout_uri,omitempty" binding:"omitempty,url" example:"https://example.com/backchannel-logout"`
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty" binding:"dive,url" example:"https://example.com/signed-out"`
+	FrontchannelLogoutURI  string   `json:"frontchannel_logout_uri,omitempty" binding:"omitempty,url" example:"https://example.com/frontchannel-logout"`
+	AllowedGrantTypes      []string `json:"allowed_grant_types" binding:"required,min=1" example:"authorization_code,refresh_token"`
+	AllowedScopes          []string `json:"allowed_scopes" binding:"required,min=1" example:"openid,profile,email"`
+	DefaultScopes          []string `json:"default_scopes,omitempty" example:"openid,profile"`
+	AccessTokenTTL         *int     `json:"access_token_ttl,omitempty" example:"900"`
+	RefreshTokenTTL        *int     `json:"refresh_token_ttl,omitempty" example:"604800"`
+	IDTokenTTL             *int     `json:"id_token_ttl,omitempty" example:"3600"`
+	RequirePKCE            *bool    `json:"require_pkce,omitempty" example:"true"`
+	RequireConsent         *bool    `json:"require_consent,omitempty" example:"true"`
+	FirstParty             *bool    `json:"first_party,omitempty" example:"false"`
+	// Bind access tokens to the client certificate presented at the token endpoint (RFC 8705)
+	CertBoundAccessTokens *bool `json:"tls_client_certificate_bound_access_tokens,omitempty" example:"false"`
+	// Require the refresh_nonce returned with each refresh token on the next refresh
+	RequireRefreshNonce *bool `json:"require_refresh_nonce,omitempty" example:"false"`
+	// Custom claims added to ID tokens and userinfo responses
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty"`
 }
 
 // CreateOAuthClientResponse represents the response when creating an OAuth client
@@ -206,19 +233,30 @@ type CreateOAuthClientResponse struct {
 
 // UpdateOAuthClientRequest represents a request to update an OAuth client
 type UpdateOAuthClientRequest struct {
-	Name              string   `json:"name,omitempty" binding:"omitempty,min=3,max=100" example:"My Updated Application"`
-	Description       string   `json:"description,omitempty" example:"Updated description"`
-	LogoURL           string   `json:"logo_url,omitempty" example:"https://example.com/new-logo.png"`
-	RedirectURIs      []string `json:"redirect_uris,omitempty" binding:"omitempty,dive,url" example:"https://example.com/callback"`
-	AllowedGrantTypes []string `json:"allowed_grant_types,omitempty" binding:"omitempty,min=1" example:"authorization_code,refresh_token"`
-	AllowedScopes     []string `json:"allowed_scopes,omitempty" binding:"omitempty,min=1" example:"openid,profile,email"`
-	DefaultScopes     []string `json:"default_scopes,omitempty" example:"openid,profile"`
-	AccessTokenTTL    *int     `json:"access_token_ttl,omitempty" example:"900"`
-	RefreshTokenTTL   *int     `json:"refresh_token_ttl,omitempty" example:"604800"`
-	IDTokenTTL        *int     `json:"id_token_ttl,omitempty" example:"3600"`
-	RequirePKCE       *bool    `json:"require_pkce,omitempty" example:"true"`
-	RequireConsent    *bool    `json:"require_consent,omitempty" example:"true"`
-	IsActive          *bool    `json:"is_active,omitempty" example:"true"`
+	Name         string   `json:"name,omitempty" binding:"omitempty,min=3,max=100" example:"My Updated Application"`
+	Description  string   `json:"description,omitempty" example:"Updated description"`
+	LogoURL      string   `json:"logo_url,omitempty" example:"https://example.com/new-logo.png"`
+	RedirectURIs []string `json:"redirect_uris,omitempty" binding:"omitempty,dive,url" example:"https://example.com/callback"`
+	// Empty clears the URI
+	BackchannelLogoutURI   *string  `json:"backchannel_logout_uri,omitempty" binding:"omitempty,url" example:"https://example.com/backchannel-logout"`
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty" binding:"omitempty,dive,url" example:"https://example.com/signed-out"`
+	// Empty clears the URI
+	FrontchannelLogoutURI *string  `json:"frontchannel_logout_uri,omitempty" binding:"omitempty,url" example:"https://example.com/frontchannel-logout"`
+	AllowedGrantTypes     []string `json:"allowed_grant_types,omitempty" binding:"omitempty,min=1" example:"authorization_code,refresh_token"`
+	AllowedScopes         []string `json:"allowed_scopes,omitempty" binding:"omitempty,min=1" example:"openid,profile,email"`
+	DefaultScopes         []string `json:"default_scopes,omitempty" example:"openid,profile"`
+	AccessTokenTTL        *int     `json:"access_token_ttl,omitempty" example:"900"`
+	RefreshTokenTTL       *int     `json:"refresh_token_ttl,omitempty" example:"604800"`
+	IDTokenTTL            *int     `json:"id_token_ttl,omitempty" example:"3600"`
+	RequirePKCE           *bool    `json:"require_pkce,omitempty" example:"true"`
+	RequireConsent        *bool    `json:"require_consent,omitempty" example:"true"`
+	IsActive              *bool    `json:"is_active,omitempty" example:"true"`
+	// Bind access tokens to the client certificate presented at the token endpoint (RFC 8705)
+	CertBoundAccessTokens *bool `json:"tls_client_certificate_bound_access_tokens,omitempty" example:"false"`
+	// Require the refresh_nonce returned with each refresh token on the next refresh
+	RequireRefreshNonce *bool `json:"require_refresh_nonce,omitempty" example:"false"`
+	// Replaces the custom claims; an empty mapping removes them
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty"`
 }
 
 // AuthorizeRequest represents an OAuth 2.0 authorization request
@@ -234,8 +272,19 @@ type AuthorizeRequest struct {
 	Prompt              *string `form:"prompt" binding:"omitempty,oneof=none login consent select_account" example:"consent"`
 	MaxAge              *int    `form:"max_age" example:"3600"`
 	Display             *string `form:"display" binding:"omitempty,oneof=page popup touch wap" example:"page"`
+	ResponseMode        string  `form:"response_mode" binding:"omitempty,oneof=query fragment jwt query.jwt fragment.jwt" example:"query.jwt"`
 }
 
+// Authorization response modes. The JWT modes (JARM) return the response parameters as a
+// signed JWT in a single "response" parameter; jwt means query.jwt for the code flow.
+const (
+	ResponseModeQuery       = "query"
+	ResponseModeFragment    = "fragment"
+	ResponseModeJWT         = "jwt"
+	ResponseModeQueryJWT    = "query.jwt"
+	ResponseModeFragmentJWT = "fragment.jwt"
+)
+
 // AuthorizeResponse represents an OAuth 2.0 authorization response
 type AuthorizeResponse struct {
 	Code  string `json:"code,omitempty" example:"authorization_code_abc123"`
@@ -255,10 +304,13 @@ type TokenRequest struct {
 	DeviceCode   *string `form:"device_code" example:"device_code_abc123"`
 	Username     *string `form:"username" example:"user@example.com"`
 	Password     *string `form:"password" example:"password123"`
+	RefreshNonce *string `form:"refresh_nonce" example:"nonce_abc123"`
 
 	// Session context (populated by handler, not from form)
 	IPAddress string `form:"-" json:"-"`
 	UserAgent string `form:"-" json:"-"`
+	// SHA-256 thumbprint of the client certificate presented over mTLS, if any
+	ClientCertThumbprint string `form:"-" json:"-"`
 }
 
 // TokenResponse represents an OAuth 2.0 token response
@@ -269,6 +321,8 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty" example:"refresh_token_xyz789"`
 	IDToken      string `json:"id_token,omitempty" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	Scope        string `json:"scope,omitempty" example:"openid profile email"`
+	// Must be sent with the next refresh of RefreshToken, for clients requiring refresh nonces
+	RefreshNonce string `json:"refresh_nonce,omitempty" example:"nonce_abc123"`
 }
 
 // IntrospectionRequest represents an OAuth 2.0 token introspection request
@@ -293,6 +347,14 @@ type IntrospectionResponse struct {
 	Audience  string `json:"aud,omitempty" example:"my_client_app_123"`
 	Issuer    string `json:"iss,omitempty" example:"https://auth.example.com"`
 	JWTID     string `json:"jti,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Set when the token is bound to a client certificate (RFC 8705)
+	Cnf *Confirmation `json:"cnf,omitempty"`
+}
+
+// Confirmation identifies the key a token is bound to (RFC 7800)
+type Confirmation struct {
+	// Base64url SHA-256 thumbprint of the client certificate
+	X5TS256 string `json:"x5t#S256" example:"bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"`
 }
 
 // DeviceAuthRequest represents a device authorization request
@@ -332,20 +394,33 @@ type UserInfoResponse struct {
 	PhoneNumber         *string `json:"phone_number,omitempty" example:"+1 (555) 123-4567"`
 	PhoneNumberVerified *bool   `json:"phone_number_verified,omitempty" example:"false"`
 	UpdatedAt           *int64  `json:"updated_at,omitempty" example:"1705315800"`
+
+	// Custom claims of the client's claims mapping
+	Custom map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON writes the custom claims next to the standard ones, which take precedence
+func (r UserInfoResponse) MarshalJSON() ([]byte, error) {
+	type userInfo UserInfoResponse
+	data, err := json.Marshal(userInfo(r))
+	if err != nil || len(r.Custom) == 0 {
+		return data, err
+	}
+	return MergeClaims(data, r.Custom)
 }
 
 // OIDCDiscoveryDocument represents the OpenID Connect discovery document
 type OIDCDiscoveryDocument struct {
 	Issuer                                     string   `json:"issuer" example:"https://auth.example.com"`
-	AuthorizationEndpoint                      string   `json:"authorization_endpoint" example:"https://auth.example.com/oauth2/authorize"`
-	TokenEndpoint                              string   `json:"token_endpoint" example:"https://auth.example.com/oauth2/token"`
-	UserInfoEndpoint                           string   `json:"userinfo_endpoint" example:"https://auth.example.com/oauth2/userinfo"`
+	AuthorizationEndpoint                      string   `json:"authorization_endpoint" example:"https://auth.example.com/oauth/authorize"`
+	TokenEndpoint                              string   `json:"token_endpoint" example:"https://auth.example.com/oauth/token"`
+	UserInfoEndpoint                           string   `json:"userinfo_endpoint" example:"https://auth.example.com/oauth/userinfo"`
 	JwksURI                                    string   `json:"jwks_uri" example:"https://auth.example.com/.well-known/jwks.json"`
-	RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty" example:"https://auth.example.com/oauth2/register"`
-	RevocationEndpoint                         string   `json:"revocation_endpoint,omitempty" example:"https://auth.example.com/oauth2/revoke"`
-	IntrospectionEndpoint                      string   `json:"introspection_endpoint,omitempty" example:"https://auth.example.com/oauth2/introspect"`
-	DeviceAuthorizationEndpoint                string   `json:"device_authorization_endpoint,omitempty" example:"https://auth.example.com/oauth2/device/code"`
-	EndSessionEndpoint                         string   `json:"end_session_endpoint,omitempty" example:"https://auth.example.com/oauth2/logout"`
+	RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty" example:"https://auth.example.com/oauth/register"`
+	RevocationEndpoint                         string   `json:"revocation_endpoint,omitempty" example:"https://auth.example.com/oauth/revoke"`
+	IntrospectionEndpoint                      string   `json:"introspection_endpoint,omitempty" example:"https://auth.example.com/oauth/introspect"`
+	DeviceAuthorizationEndpoint                string   `json:"device_authorization_endpoint,omitempty" example:"https://auth.example.com/oauth/device/code"`
+	EndSessionEndpoint                         string   `json:"end_session_endpoint,omitempty" example:"https://auth.example.com/oauth/logout"`
 	ScopesSupported                            []string `json:"scopes_supported" example:"openid,profile,email,offline_access"`
 	ResponseTypesSupported                     []string `json:"response_types_supported" example:"code,token,id_token"`
 	ResponseModesSupported                     []string `json:"response_modes_supported,omitempty" example:"query,fragment,form_post"`
@@ -363,6 +438,12 @@ type OIDCDiscoveryDocument struct {
 	RequestURIParameterSupported               bool     `json:"request_uri_parameter_supported,omitempty" example:"false"`
 	RequireRequestURIRegistration              bool     `json:"require_request_uri_registration,omitempty" example:"false"`
 	CodeChallengeMethodsSupported              []string `json:"code_challenge_methods_supported,omitempty" example:"S256,plain"`
+	BackchannelLogoutSupported                 bool     `json:"backchannel_logout_supported,omitempty" example:"true"`
+	BackchannelLogoutSessionSupported          bool     `json:"backchannel_logout_session_supported,omitempty" example:"false"`
+	FrontchannelLogoutSupported                bool     `json:"frontchannel_logout_supported,omitempty" example:"true"`
+	FrontchannelLogoutSessionSupported         bool     `json:"frontchannel_logout_session_supported,omitempty" example:"false"`
+	TLSClientCertificateBoundAccessTokens      bool     `json:"tls_client_certificate_bound_access_tokens,omitempty" example:"true"`
+	AuthorizationSigningAlgValuesSupported     []string `json:"authorization_signing_alg_values_supported,omitempty" example:"RS256,ES256"`
 	IDTokenSigningAlgValuesSupported           []string `json:"id_token_signing_alg_values_supported" example:"RS256,ES256"`
 	IDTokenEncryptionAlgValuesSupported        []string `json:"id_token_encryption_alg_values_supported,omitempty" example:"RSA-OAEP,A256KW"`
 	IDTokenEncryptionEncValuesSupported        []string `json:"id_token_encryption_enc_values_supported,omitempty" example:"A128CBC-HS256,A256GCM"`
@@ -412,6 +493,26 @@ type RevocationRequest struct {
 	ClientSecret  *string `form:"client_secret" example:"client_secret_abc123xyz789"`
 }
 
+// EndSessionRequest represents an OIDC RP-initiated logout request
+type EndSessionRequest struct {
+	IDTokenHint           string `form:"id_token_hint" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ClientID              string `form:"client_id" example:"my_client_app_123"`
+	PostLogoutRedirectURI string `form:"post_logout_redirect_uri" example:"https://example.com/signed-out"`
+	State                 string `form:"state" example:"xyz"`
+	// Confirmed is set once the user confirmed a logout that came without id_token_hint
+	Confirmed bool `form:"-"`
+}
+
+// EndSessionResult describes where a logged out user goes next
+type EndSessionResult struct {
+	// Set when the user has to confirm the logout first; the session is not ended yet
+	ConfirmationRequired bool
+	// Empty when the user stays on the signed-out page
+	RedirectURI string
+	// Front-channel logout URIs of the clients the user was signed in to, loaded in iframes
+	FrontchannelLogoutURIs []string
+}
+
 // IsExpired checks if the authorization code is expired
 func (ac *AuthorizationCode) IsExpired() bool {
 	return time.Now().After(ac.ExpiresAt)
@@ -537,3 +638,139 @@ type OAuthConsentListResponse struct {
 	// Total number of consents
 	Total int `json:"total" example:"5"`
 }
+
+// OAuthClientTokenStats summarizes the tokens a user has been issued for one OAuth client
+type OAuthClientTokenStats struct {
+	ClientID     uuid.UUID  `bun:"client_id"`
+	FirstUsedAt  *time.Time `bun:"first_used_at"`
+	LastUsedAt   *time.Time `bun:"last_used_at"`
+	ActiveTokens int        `bun:"active_tokens"`
+}
+
+// ConnectedApp is an OAuth client the user has granted access to their account
+type ConnectedApp struct {
+	// Internal client ID, used to revoke the connection
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Public OAuth client ID
+	ClientID string `json:"client_id" example:"agw_abc123"`
+	// Client name
+	Name string `json:"name" example:"My Application"`
+	// Client description
+	Description string `json:"description,omitempty" example:"A sample OAuth application"`
+	// Client logo URL
+	LogoURL string `json:"logo_url,omitempty" example:"https://example.com/logo.png"`
+	// Scopes the user consented to
+	Scopes []string `json:"scopes" example:"openid,profile,email"`
+	// When consent was granted
+	GrantedAt time.Time `json:"granted_at" example:"2024-01-15T10:30:00Z"`
+	// When the client was first issued a token for the user
+	FirstUsedAt *time.Time `json:"first_used_at,omitempty" example:"2024-01-15T10:30:00Z"`
+	// When the client was last issued a token for the user
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2024-01-20T08:00:00Z"`
+	// Number of access and refresh tokens that are still valid
+	ActiveTokens int `json:"active_tokens" example:"2"`
+}
+
+// ConnectedAppListResponse represents the list of apps connected to the user's account
+type ConnectedAppListResponse struct {
+	// Connected apps, most recently granted first
+	Apps []*ConnectedApp `json:"apps"`
+	// Total number of connected apps
+	Total int `json:"total" example:"3"`
+}
+
+// Steps of an emulated OAuth flow
+const (
+	FlowStepPassed  = "passed"
+	FlowStepFailed  = "failed"
+	FlowStepSkipped = "skipped"
+)
+
+// OAuthClientEmulateRequest describes the authorization request an admin wants to emulate for a client.
+// Empty fields fall back to what a well-behaved client would send.
+type OAuthClientEmulateRequest struct {
+	// Redirect URI to authorize with, defaults to the first registered one
+	RedirectURI string `json:"redirect_uri,omitempty" example:"https://example.com/callback"`
+	// Space separated scopes, defaults to the client's default scopes
+	Scope string `json:"scope,omitempty" example:"openid profile email"`
+	// Response type, defaults to "code"
+	ResponseType string `json:"response_type,omitempty" example:"code"`
+	// PKCE code challenge sent to the authorize endpoint
+	CodeChallenge string `json:"code_challenge,omitempty" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	// PKCE code challenge method
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty" example:"S256"`
+	// PKCE code verifier sent to the token endpoint
+	CodeVerifier string `json:"code_verifier,omitempty" example:"dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"`
+	// Nonce to put into the ID token
+	Nonce string `json:"nonce,omitempty" example:"n-0S6_WzA2Mj"`
+	// SHA-256 thumbprint of the client certificate, for clients with certificate-bound tokens
+	ClientCertThumbprint string `json:"client_cert_thumbprint,omitempty" example:"bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"`
+}
+
+// OAuthFlowStep is one step of an emulated OAuth flow
+type OAuthFlowStep struct {
+	// Step name
+	Name string `json:"name" example:"redirect_uri"`
+	// Outcome: passed, failed or skipped
+	Status string `json:"status" example:"passed"`
+	// What was checked and why it passed or failed
+	Detail string `json:"detail" example:"https://example.com/callback is registered"`
+}
+
+// OAuthClientEmulateResponse is the step-by-step trace of an emulated authorization code flow.
+// Nothing is persisted and no usable tokens are issued, only the claims they would carry.
+type OAuthClientEmulateResponse struct {
+	// Client ID of the emulated client
+	ClientID string `json:"client_id" example:"agw_abc123"`
+	// Whether the flow would complete
+	Success bool `json:"success" example:"true"`
+	// Steps in the order they were performed
+	Steps []OAuthFlowStep `json:"steps"`
+	// Scopes the tokens would be issued with
+	GrantedScopes []string `json:"granted_scopes,omitempty"`
+	// Whether a refresh token would be issued
+	RefreshToken bool `json:"refresh_token" example:"true"`
+	// Claims of the access token
+	AccessTokenClaims map[string]interface{} `json:"access_token_claims,omitempty"`
+	// Claims of the ID token, when the openid scope is granted
+	IDTokenClaims map[string]interface{} `json:"id_token_claims,omitempty"`
+}
+
+// TokenPreviewRequest selects the user and client to preview token claims for
+type TokenPreviewRequest struct {
+	// User the tokens would be issued to
+	UserID uuid.UUID `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// OAuth client ID the tokens would be issued to
+	ClientID string `json:"client_id" binding:"required" example:"agw_abc123"`
+	// Space separated scopes requested at the authorize endpoint
+	Scope string `json:"scope,omitempty" example:"openid profile email"`
+	// Nonce to put into the ID token
+	Nonce string `json:"nonce,omitempty" example:"n-0S6_WzA2Mj"`
+	// SHA-256 thumbprint of the client certificate, for clients with certificate-bound tokens
+	ClientCertThumbprint string `json:"client_cert_thumbprint,omitempty" example:"bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"`
+}
+
+// TokenPreviewResponse carries the claims the token endpoint would issue for a user and client.
+// Nothing is signed or stored.
+type TokenPreviewResponse struct {
+	// Client ID of the previewed client
+	ClientID string `json:"client_id" example:"agw_abc123"`
+	// User the tokens were previewed for
+	UserID uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Whether the gateway would issue these tokens right now
+	Issuable bool `json:"issuable" example:"true"`
+	// Reasons the gateway would refuse to issue the tokens
+	Problems []string `json:"problems,omitempty"`
+	// Scopes the tokens would be issued with
+	GrantedScopes []string `json:"granted_scopes"`
+	// Roles put into the access token
+	Roles []string `json:"roles"`
+	// Whether a refresh token would be issued
+	RefreshToken bool `json:"refresh_token" example:"true"`
+	// Claims of the access token
+	AccessTokenClaims map[string]interface{} `json:"access_token_claims"`
+	// Claims of the ID token, when the openid scope is granted
+	IDTokenClaims map[string]interface{} `json:"id_token_claims,omitempty"`
+	// Claims the userinfo endpoint would return for the access token
+	UserInfoClaims map[string]interface{} `json:"userinfo_claims"`
+}
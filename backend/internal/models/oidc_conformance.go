@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Outcomes of a conformance check
+const (
+	ConformancePassed  = "passed"
+	ConformanceFailed  = "failed"
+	ConformanceSkipped = "skipped"
+)
+
+// OIDCConformanceRequest configures a conformance run. Checks that need a registered client
+// are skipped when no client is given.
+type OIDCConformanceRequest struct {
+	// Client ID of a confidential client allowed to use the client_credentials grant
+	ClientID string `json:"client_id,omitempty" example:"agw_abc123"`
+	// Secret of that client
+	ClientSecret string `json:"client_secret,omitempty" example:"agws_secret"`
+}
+
+// OIDCConformanceResult is the outcome of a single spec assertion
+type OIDCConformanceResult struct {
+	// Check name
+	Name string `json:"name" example:"discovery.issuer"`
+	// Endpoint the check exercised
+	Endpoint string `json:"endpoint" example:"discovery"`
+	// Outcome: passed, failed or skipped
+	Status string `json:"status" example:"passed"`
+	// Specification section the assertion comes from
+	Spec string `json:"spec" example:"OpenID Connect Discovery 1.0, section 4.3"`
+	// What was observed
+	Message string `json:"message" example:"issuer matches the configured issuer"`
+}
+
+// OIDCConformanceReport is the result of a conformance run against the OIDC provider
+type OIDCConformanceReport struct {
+	// Issuer the checks ran against
+	Issuer string `json:"issuer" example:"https://auth.example.com"`
+	// Number of passed checks
+	Passed int `json:"passed" example:"18"`
+	// Number of failed checks
+	Failed int `json:"failed" example:"0"`
+	// Number of skipped checks
+	Skipped int `json:"skipped" example:"2"`
+	// Individual check results in the order they ran
+	Results []OIDCConformanceResult `json:"results"`
+	// When the run started
+	StartedAt time.Time `json:"started_at" example:"2024-01-15T10:30:00Z"`
+	// How long the run took in milliseconds
+	DurationMs int64 `json:"duration_ms" example:"420"`
+}
+
+// OK reports whether no check failed
+func (r *OIDCConformanceReport) OK() bool {
+	return r.Failed == 0
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Outbox event kinds
+const (
+	OutboxKindAudit   = "audit"
+	OutboxKindWebhook = "webhook"
+)
+
+// OutboxEvent is an audit log or webhook event written in the transaction of the state change
+// it describes, and handed over by the outbox dispatcher once committed. It is deleted when
+// dispatched; until then a failed dispatch is retried at AvailableAt.
+type OutboxEvent struct {
+	bun.BaseModel `bun:"table:outbox_events,alias:oe"`
+
+	ID          uuid.UUID       `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Kind        string          `bun:"kind,notnull"`
+	Payload     json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	Attempts    int             `bun:"attempts,notnull,default:0"`
+	AvailableAt time.Time       `bun:"available_at,notnull,default:current_timestamp"`
+	LastError   string          `bun:"last_error"`
+	CreatedAt   time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+}
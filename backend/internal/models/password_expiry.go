@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons reported when a password change is required at sign-in
+const (
+	PasswordChangeReasonExpired       = "expired"
+	PasswordChangeReasonAdminRequired = "admin_required"
+)
+
+// PasswordChangeRequiredCode is the machine-readable code returned when sign-in is blocked by a required password change
+const PasswordChangeRequiredCode = "PASSWORD_CHANGE_REQUIRED"
+
+// PasswordChangeRequiredError is returned by sign-in when the credentials are valid
+// but the user must set a new password first. ResetToken authorizes that change.
+type PasswordChangeRequiredError struct {
+	Reason     string
+	ResetToken string
+}
+
+// Error implements the error interface
+func (e *PasswordChangeRequiredError) Error() string {
+	return "password change required: " + e.Reason
+}
+
+// PasswordChangeRequiredResponse is the 401 body returned when a password change is required
+type PasswordChangeRequiredResponse struct {
+	// HTTP error status text
+	Error string `json:"error" example:"Unauthorized"`
+	// Human-readable error message
+	Message string `json:"message" example:"Password change required"`
+	// Machine-readable error code
+	Code string `json:"code" example:"PASSWORD_CHANGE_REQUIRED"`
+	// Why the change is required: "expired" or "admin_required"
+	Reason string `json:"reason" example:"expired"`
+	// Short-lived token to submit to /api/auth/password/change-required
+	ResetToken string `json:"reset_token" example:"eyJhbGciOiJIUzI1NiIs..."`
+}
+
+// CompleteRequiredPasswordChangeRequest sets a new password using the token returned at sign-in
+type CompleteRequiredPasswordChangeRequest struct {
+	// Reset token from the PASSWORD_CHANGE_REQUIRED response
+	ResetToken string `json:"reset_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIs..."`
+	// New password
+	NewPassword string `json:"new_password" binding:"required,min=8" example:"NewSecurePass123!"`
+}
+
+// SetPasswordMaxAgeRequest sets or clears the password max-age policy of a role or group
+type SetPasswordMaxAgeRequest struct {
+	// Maximum password age in days; null removes the policy
+	MaxAgeDays *int `json:"max_age_days" binding:"omitempty,min=1,max=3650" example:"90"`
+}
+
+// PasswordExpiryEntry describes a user whose password expires under the effective policy
+type PasswordExpiryEntry struct {
+	// User ID
+	UserID uuid.UUID `json:"user_id" bun:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// User's email address
+	Email string `json:"email" bun:"email" example:"user@example.com"`
+	// Username
+	Username string `json:"username" bun:"username" example:"johndoe"`
+	// Timestamp when password was last changed
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty" bun:"password_changed_at" example:"2024-01-15T10:30:00Z"`
+	// Timestamp when password expires
+	ExpiresAt time.Time `json:"expires_at" bun:"expires_at" example:"2024-04-15T10:30:00Z"`
+	// Timestamp when the expiry warning was sent
+	WarnedAt *time.Time `json:"warned_at,omitempty" bun:"password_expiry_warned_at" example:"2024-04-08T10:30:00Z"`
+}
+
+// PasswordExpiryListResponse lists users whose passwords expire within a window
+type PasswordExpiryListResponse struct {
+	// Users ordered by expiry, soonest first (already expired included)
+	Users []*PasswordExpiryEntry `json:"users"`
+	// Look-ahead window in days
+	Days int `json:"days" example:"14"`
+	// Number of users returned
+	Total int `json:"total" example:"3"`
+}
@@ -38,6 +38,7 @@ type AccountLockout struct {
 	ID                uuid.UUID  `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()"`
 	UserID            uuid.UUID  `json:"user_id" bun:"user_id,type:uuid,notnull,unique"`
 	FailedAttempts    int        `json:"failed_attempts" bun:"failed_attempts,notnull,default:0"`
+	LockoutCount      int        `json:"lockout_count" bun:"lockout_count,notnull,default:0"` // Consecutive lockouts; each one doubles the next
 	LockedUntil       *time.Time `json:"locked_until,omitempty" bun:"locked_until"`
 	LastFailedAttempt *time.Time `json:"last_failed_attempt,omitempty" bun:"last_failed_attempt"`
 	CreatedAt         time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
@@ -68,7 +69,46 @@ func (a *AccountLockout) IsLocked() bool {
 
 // AccountLockoutPolicy defines account lockout policy settings
 type AccountLockoutPolicy struct {
-	MaxFailedAttempts int           `json:"max_failed_attempts" example:"5"`
-	LockoutDuration   time.Duration `json:"lockout_duration" example:"30m"`
-	ResetAfter        time.Duration `json:"reset_after" example:"15m"` // Reset failed attempts after this duration
+	MaxFailedAttempts  int           `json:"max_failed_attempts" example:"5"`
+	LockoutDuration    time.Duration `json:"lockout_duration" example:"30m"`
+	ResetAfter         time.Duration `json:"reset_after" example:"15m"`          // Reset failed attempts after this duration
+	MaxLockoutDuration time.Duration `json:"max_lockout_duration" example:"24h"` // Cap of the doubling lockout duration
+}
+
+// LockoutDurationFor returns how long the lockoutCount-th consecutive lockout lasts:
+// LockoutDuration, doubled for every earlier lockout, capped at MaxLockoutDuration.
+func (p AccountLockoutPolicy) LockoutDurationFor(lockoutCount int) time.Duration {
+	d := p.LockoutDuration
+	for i := 1; i < lockoutCount && d < p.MaxLockoutDuration; i++ {
+		d *= 2
+	}
+	if p.MaxLockoutDuration > 0 && d > p.MaxLockoutDuration {
+		d = p.MaxLockoutDuration
+	}
+	return d
+}
+
+// LockedAccount is a locked account as listed to admins
+type LockedAccount struct {
+	UserID            uuid.UUID  `json:"user_id" bun:"user_id"`
+	Email             string     `json:"email" bun:"email" example:"user@example.com"`
+	Username          string     `json:"username" bun:"username" example:"johndoe"`
+	FailedAttempts    int        `json:"failed_attempts" bun:"failed_attempts" example:"0"`
+	LockoutCount      int        `json:"lockout_count" bun:"lockout_count" example:"2"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty" bun:"locked_until"`
+	LastFailedAttempt *time.Time `json:"last_failed_attempt,omitempty" bun:"last_failed_attempt"`
+}
+
+// LockedAccountListResponse contains a paginated list of locked accounts
+type LockedAccountListResponse struct {
+	// Locked accounts, latest unlock first
+	Accounts []*LockedAccount `json:"accounts"`
+	// Total number of locked accounts
+	Total int `json:"total" example:"3"`
+	// Current page number
+	Page int `json:"page" example:"1"`
+	// Number of items per page
+	PageSize int `json:"page_size" example:"20"`
+	// Total number of pages
+	TotalPages int `json:"total_pages" example:"1"`
 }
@@ -0,0 +1,107 @@
+package models
+
+import "strings"
+
+// SettingPasswordPolicy is the system setting holding the JSON encoded PasswordPolicy.
+// Fields missing from the setting fall back to the PASSWORD_* environment defaults.
+const SettingPasswordPolicy = "password_policy"
+
+// SettingLegacyPasswordPolicy holds the JSON saved through the original password policy
+// endpoint. It is kept apart from SettingPasswordPolicy so old clients cannot overwrite the policy.
+const SettingLegacyPasswordPolicy = "password_policy_legacy"
+
+// PasswordPolicyViolationCode is the machine-readable code returned when a new password breaks the policy
+const PasswordPolicyViolationCode = "PASSWORD_POLICY_VIOLATION"
+
+// Password policy rules reported in violations
+const (
+	PasswordRuleMinLength   = "min_length"
+	PasswordRuleMaxLength   = "max_length"
+	PasswordRuleUppercase   = "uppercase"
+	PasswordRuleLowercase   = "lowercase"
+	PasswordRuleNumber      = "number"
+	PasswordRuleSpecial     = "special"
+	PasswordRuleDictionary  = "dictionary"
+	PasswordRuleCompromised = "compromised"
+	PasswordRuleHistory     = "history"
+)
+
+// PasswordPolicy is applied to every password a user or an admin sets: at sign-up, guest
+// upgrade, password change, password reset and admin user creation
+type PasswordPolicy struct {
+	// Minimum length in characters
+	MinLength int `json:"min_length" example:"12"`
+
+	// Maximum length in characters; 0 means no maximum
+	MaxLength int `json:"max_length" example:"128"`
+
+	RequireUppercase bool `json:"require_uppercase" example:"true"`
+	RequireLowercase bool `json:"require_lowercase" example:"true"`
+	RequireNumbers   bool `json:"require_numbers" example:"true"`
+	RequireSpecial   bool `json:"require_special" example:"false"`
+
+	// Number of previous passwords that cannot be reused; 0 disables the check
+	HistoryCount int `json:"history_count" example:"5"`
+
+	// Reject common passwords and their variants with trailing digits or symbols
+	CheckDictionary bool `json:"check_dictionary" example:"true"`
+
+	// Reject passwords found in known breaches (HaveIBeenPwned, k-anonymity range query)
+	CheckCompromised bool `json:"check_compromised" example:"true"`
+}
+
+// UpdatePasswordPolicyRequest replaces the password policy
+type UpdatePasswordPolicyRequest struct {
+	MinLength        int  `json:"min_length" binding:"min=1,max=128" example:"12"`
+	MaxLength        int  `json:"max_length" binding:"min=0,max=1024" example:"128"`
+	RequireUppercase bool `json:"require_uppercase" example:"true"`
+	RequireLowercase bool `json:"require_lowercase" example:"true"`
+	RequireNumbers   bool `json:"require_numbers" example:"true"`
+	RequireSpecial   bool `json:"require_special" example:"false"`
+	HistoryCount     int  `json:"history_count" binding:"min=0,max=24" example:"5"`
+	CheckDictionary  bool `json:"check_dictionary" example:"true"`
+	CheckCompromised bool `json:"check_compromised" example:"true"`
+}
+
+// PasswordPolicyViolation is a password policy rule a password breaks
+type PasswordPolicyViolation struct {
+	// Rule that was broken: min_length, max_length, uppercase, lowercase, number, special, dictionary, compromised or history
+	Rule string `json:"rule" example:"min_length"`
+	// Human-readable explanation
+	Message string `json:"message" example:"Password must be at least 12 characters long"`
+}
+
+// PasswordPolicyError is returned when a new password breaks the password policy
+type PasswordPolicyError struct {
+	Violations []PasswordPolicyViolation
+}
+
+// Error implements the error interface
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Rules returns the rules that were broken
+func (e *PasswordPolicyError) Rules() []string {
+	rules := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		rules[i] = violation.Rule
+	}
+	return rules
+}
+
+// PasswordPolicyViolationResponse is the 400 body returned when a new password breaks the password policy
+type PasswordPolicyViolationResponse struct {
+	// HTTP error status text
+	Error string `json:"error" example:"Bad Request"`
+	// Human-readable error message
+	Message string `json:"message" example:"Password does not meet the password policy"`
+	// Machine-readable error code
+	Code string `json:"code" example:"PASSWORD_POLICY_VIOLATION"`
+	// Every rule the password breaks
+	Violations []PasswordPolicyViolation `json:"violations"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// PermissionCatalogSourceRegistry is the source reported for permissions defined in the RBAC
+// registry, as opposed to entries pushed by services
+const PermissionCatalogSourceRegistry = "registry"
+
+// PermissionCatalogEntry is a resource/action pair a service registered over gRPC
+type PermissionCatalogEntry struct {
+	bun.BaseModel `bun:"table:permission_catalog_entries"`
+
+	ID          uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Source      string    `json:"source" bun:"source,notnull"`
+	Resource    string    `json:"resource" bun:"resource,notnull"`
+	Action      string    `json:"action" bun:"action,notnull"`
+	Description string    `json:"description,omitempty" bun:"description"`
+	CreatedAt   time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// PermissionGrant links a permission to a role that grants it
+type PermissionGrant struct {
+	PermissionID    uuid.UUID `bun:"permission_id,type:uuid"`
+	RoleID          uuid.UUID `bun:"role_id,type:uuid"`
+	RoleName        string    `bun:"role_name"`
+	RoleDisplayName string    `bun:"role_display_name"`
+}
+
+// PermissionCatalogRole is a role that grants a catalog item
+type PermissionCatalogRole struct {
+	// Role unique identifier
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Role system name
+	Name string `json:"name" example:"admin"`
+	// Role display name
+	DisplayName string `json:"display_name" example:"Administrator"`
+}
+
+// PermissionCatalogItem describes one resource/action pair known to the gateway
+type PermissionCatalogItem struct {
+	// Resource the action applies to
+	Resource string `json:"resource" example:"invoices"`
+	// Action on the resource
+	Action string `json:"action" example:"refund"`
+	// Application the permission belongs to, empty for global permissions
+	ApplicationID *uuid.UUID `json:"application_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Name of the permission defined for the pair, empty when only a service registered it
+	Permission string `json:"permission,omitempty" example:"invoices.refund"`
+	// ID of the permission defined for the pair
+	PermissionID *uuid.UUID `json:"permission_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Human-readable description
+	Description string `json:"description,omitempty" example:"Refund a paid invoice"`
+	// Where the pair is known from: "registry" and/or the names of registering services
+	Sources []string `json:"sources" example:"registry,billing-service"`
+	// Roles whose permissions include the pair
+	GrantedBy []PermissionCatalogRole `json:"granted_by"`
+}
+
+// PermissionCatalogResponse is the machine-readable permissions catalog
+type PermissionCatalogResponse struct {
+	// Catalog items sorted by resource and action
+	Items []PermissionCatalogItem `json:"items"`
+	// Total number of items
+	Total int `json:"total" example:"42"`
+}
@@ -19,6 +19,8 @@ type Permission struct {
 	Action string `json:"action" bun:"action,notnull" binding:"required" example:"delete"`
 	// Human-readable description of the permission
 	Description string `json:"description,omitempty" bun:"description" example:"Allows deleting users from the system"`
+	// Condition the permission is granted under (see package abac); empty for unconditional permissions
+	Condition string `json:"condition,omitempty" bun:"condition,nullzero" example:"resource.owner_id == user.id"`
 	// Timestamp when permission was created
 	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 }
@@ -36,6 +38,8 @@ type Role struct {
 	Description string `json:"description,omitempty" bun:"description" example:"Full system access with all permissions"`
 	// Whether this is a system-defined role (cannot be deleted)
 	IsSystemRole bool `json:"is_system_role" bun:"is_system_role" example:"true"`
+	// Maximum password age in days for members of this role (nil = no policy)
+	PasswordMaxAgeDays *int `json:"password_max_age_days,omitempty" bun:"password_max_age_days" example:"90"`
 	// Timestamp when role was created
 	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when role was last updated
@@ -43,6 +47,10 @@ type Role struct {
 
 	// Many-to-many relation with Permission
 	Permissions []Permission `json:"permissions,omitempty" bun:"m2m:role_permissions,join:Role=Permission"`
+	// Roles this role inherits the permissions of
+	ParentRoles []uuid.UUID `json:"parent_roles,omitempty" bun:"-" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Permission bundles granted to this role
+	Bundles []uuid.UUID `json:"bundles,omitempty" bun:"-" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 // RolePermission represents the many-to-many relationship join table
@@ -56,6 +64,48 @@ type RolePermission struct {
 	Permission *Permission `bun:"rel:belongs-to,join:permission_id=id"`
 }
 
+// RoleParent makes a role inherit the permissions of a parent role, and through it those of
+// the parent's own parents
+type RoleParent struct {
+	RoleID       uuid.UUID `json:"role_id" bun:"role_id,pk,type:uuid"`
+	ParentRoleID uuid.UUID `json:"parent_role_id" bun:"parent_role_id,pk,type:uuid"`
+	CreatedAt    time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// PermissionBundle is a named set of permissions granted to roles as a whole
+type PermissionBundle struct {
+	// Bundle unique identifier
+	ID uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// System name for the bundle
+	Name string `json:"name" bun:"name,notnull" example:"billing_readonly"`
+	// Human-readable display name
+	DisplayName string `json:"display_name" bun:"display_name,notnull" example:"Billing (read-only)"`
+	// Bundle description
+	Description string    `json:"description,omitempty" bun:"description" example:"Read access to invoices and payments"`
+	CreatedAt   time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+
+	// Many-to-many relation with Permission
+	Permissions []Permission `json:"permissions" bun:"m2m:bundle_permissions,join:Bundle=Permission"`
+}
+
+// BundlePermission represents the many-to-many relationship between bundles and permissions
+type BundlePermission struct {
+	BundleID     uuid.UUID `json:"bundle_id" bun:"bundle_id,pk,type:uuid"`
+	PermissionID uuid.UUID `json:"permission_id" bun:"permission_id,pk,type:uuid"`
+
+	// Belongs-to relations
+	Bundle     *PermissionBundle `bun:"rel:belongs-to,join:bundle_id=id"`
+	Permission *Permission       `bun:"rel:belongs-to,join:permission_id=id"`
+}
+
+// RoleBundle grants a permission bundle to a role
+type RoleBundle struct {
+	RoleID    uuid.UUID `json:"role_id" bun:"role_id,pk,type:uuid"`
+	BundleID  uuid.UUID `json:"bundle_id" bun:"bundle_id,pk,type:uuid"`
+	GrantedAt time.Time `json:"granted_at" bun:"granted_at,nullzero,notnull,default:current_timestamp"`
+}
+
 // UserRole represents the many-to-many relationship between users and roles
 type UserRole struct {
 	UserID        uuid.UUID  `json:"user_id" bun:"user_id,pk,type:uuid"`
@@ -64,6 +114,9 @@ type UserRole struct {
 	AssignedAt    time.Time  `json:"assigned_at" bun:"assigned_at,nullzero,notnull,default:current_timestamp"`
 	AssignedBy    *uuid.UUID `json:"assigned_by,omitempty" bun:"assigned_by,type:uuid"`
 
+	// GrantedByGroup marks roles inherited from a group mapping rather than assigned directly
+	GrantedByGroup bool `json:"granted_by_group" bun:"granted_by_group,notnull,default:false"`
+
 	// Belongs-to relations
 	User *User `bun:"rel:belongs-to,join:user_id=id"`
 	Role *Role `bun:"rel:belongs-to,join:role_id=id"`
@@ -78,6 +131,24 @@ const (
 	RoleUser      RoleType = "user"
 )
 
+// Delegated admin permissions grant one area of the admin API to users without the admin
+// role, e.g. user management to helpdesk staff
+const (
+	PermissionUsersManage        = "users:manage"
+	PermissionWebhooksManage     = "webhooks:manage"
+	PermissionOAuthClientsManage = "oauth_clients:manage"
+	PermissionAuditRead          = "audit:read"
+)
+
+// IsDelegatedAdminPermission reports whether name is one of the delegated admin permissions
+func IsDelegatedAdminPermission(name string) bool {
+	switch name {
+	case PermissionUsersManage, PermissionWebhooksManage, PermissionOAuthClientsManage, PermissionAuditRead:
+		return true
+	}
+	return false
+}
+
 // ============================================================
 // Request/Response Models
 // ============================================================
@@ -92,12 +163,16 @@ type CreatePermissionRequest struct {
 	Action string `json:"action" binding:"required,min=2,max=50" example:"delete"`
 	// Permission description
 	Description string `json:"description" example:"Allows deleting users from the system"`
+	// Condition the permission is granted under; conditional permissions are only granted by CheckPermission
+	Condition string `json:"condition,omitempty" binding:"max=1000" example:"resource.owner_id == user.id"`
 }
 
 // UpdatePermissionRequest is the request body for updating a permission
 type UpdatePermissionRequest struct {
 	// Updated permission description
 	Description string `json:"description" example:"Updated permission description"`
+	// Updated condition; omit to keep it, empty string to make the permission unconditional
+	Condition *string `json:"condition,omitempty" binding:"omitempty,max=1000" example:"resource.owner_id == user.id"`
 }
 
 // CreateRoleRequest is the request body for creating a role
@@ -110,6 +185,10 @@ type CreateRoleRequest struct {
 	Description string `json:"description" example:"Can manage users and content"`
 	// List of permission IDs to assign to the role
 	Permissions []uuid.UUID `json:"permissions" example:"123e4567-e89b-12d3-a456-426614174000,223e4567-e89b-12d3-a456-426614174001"`
+	// IDs of the roles to inherit permissions from
+	ParentRoles []uuid.UUID `json:"parent_roles,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// IDs of the permission bundles to grant to the role
+	Bundles []uuid.UUID `json:"bundles,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 // UpdateRoleRequest is the request body for updating a role
@@ -120,6 +199,86 @@ type UpdateRoleRequest struct {
 	Description string `json:"description" example:"Updated role description"`
 	// List of permission IDs to assign to the role
 	Permissions []uuid.UUID `json:"permissions" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// IDs of the roles to inherit permissions from; omit to keep them, empty to inherit from none
+	ParentRoles []uuid.UUID `json:"parent_roles" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// IDs of the permission bundles granted to the role; omit to keep them
+	Bundles []uuid.UUID `json:"bundles" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// CreatePermissionBundleRequest is the request body for creating a permission bundle
+type CreatePermissionBundleRequest struct {
+	// Bundle system name (2-100 characters)
+	Name string `json:"name" binding:"required,min=2,max=100" example:"billing_readonly"`
+	// Bundle display name (2-100 characters)
+	DisplayName string `json:"display_name" binding:"required,min=2,max=100" example:"Billing (read-only)"`
+	// Bundle description
+	Description string `json:"description" example:"Read access to invoices and payments"`
+	// List of permission IDs in the bundle
+	Permissions []uuid.UUID `json:"permissions" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// UpdatePermissionBundleRequest is the request body for updating a permission bundle
+type UpdatePermissionBundleRequest struct {
+	// Bundle display name (2-100 characters)
+	DisplayName string `json:"display_name" binding:"required,min=2,max=100" example:"Billing (read-only)"`
+	// Bundle description
+	Description string `json:"description" example:"Read access to invoices and payments"`
+	// List of permission IDs in the bundle; omit to keep them
+	Permissions []uuid.UUID `json:"permissions" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// PermissionBundleListResponse represents permission bundles list
+type PermissionBundleListResponse struct {
+	// List of bundles
+	Bundles []PermissionBundle `json:"bundles"`
+	// Total number of bundles
+	Total int `json:"total" example:"3"`
+}
+
+// Ways a role can get a permission
+const (
+	PermissionSourceDirect = "direct"
+	PermissionSourceBundle = "bundle"
+)
+
+// PermissionSource is one way a role gets an effective permission
+type PermissionSource struct {
+	// direct (assigned to the role) or bundle (through a permission bundle of the role)
+	Type string `json:"type" example:"bundle"`
+	// Role the permission or bundle is assigned to: the role itself or one it inherits from
+	RoleID   uuid.UUID `json:"role_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RoleName string    `json:"role_name" example:"viewer"`
+	// Whether RoleID is a role inherited from rather than the role itself
+	Inherited bool `json:"inherited" example:"true"`
+	// Bundle granting the permission, for bundle sources
+	BundleID   *uuid.UUID `json:"bundle_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	BundleName string     `json:"bundle_name,omitempty" example:"billing_readonly"`
+}
+
+// EffectivePermission is a permission a role grants, with every way the role gets it
+type EffectivePermission struct {
+	Permission
+	Sources []PermissionSource `json:"sources"`
+}
+
+// InheritedRole is a role another role inherits from, directly or through its parents
+type InheritedRole struct {
+	ID   uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name string    `json:"name" example:"viewer"`
+	// Number of inheritance steps from the role: 1 for its parents, 2 for their parents, ...
+	Depth int `json:"depth" example:"1"`
+}
+
+// EffectivePermissionsResponse lists the permissions a role grants once inheritance and
+// bundles are resolved
+type EffectivePermissionsResponse struct {
+	RoleID   uuid.UUID `json:"role_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RoleName string    `json:"role_name" example:"editor"`
+	// Roles inherited from, nearest first
+	InheritedRoles []InheritedRole       `json:"inherited_roles"`
+	Permissions    []EffectivePermission `json:"permissions"`
+	// Total number of effective permissions
+	Total int `json:"total" example:"12"`
 }
 
 // RolePermissionsRequest is the request to set role permissions
@@ -210,3 +369,82 @@ type RoleListResponse struct {
 	// Total number of roles
 	Total int `json:"total" example:"5"`
 }
+
+// RBAC simulation decisions and the rules that produce them
+const (
+	RBACDecisionAllow = "allow"
+	RBACDecisionDeny  = "deny"
+
+	RBACRuleRoleGrantsPermission = "role_grants_permission"
+	RBACRuleNoRoles              = "no_roles"
+	RBACRuleNoMatchingPermission = "no_matching_permission"
+	RBACRuleConditionNotMet      = "condition_not_met"
+)
+
+// RBACSimulationRequest describes the permission check to simulate
+type RBACSimulationRequest struct {
+	// User the check is made for
+	UserID uuid.UUID `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Resource being accessed
+	Resource string `json:"resource" binding:"required" example:"users"`
+	// Action performed on the resource
+	Action string `json:"action" binding:"required" example:"delete"`
+	// Application the check is scoped to; only roles assigned in it are considered
+	ApplicationID *uuid.UUID `json:"application_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Request attributes, read by permission conditions as context.<key>
+	Context map[string]interface{} `json:"context,omitempty"`
+	// Resource attributes, read by permission conditions as resource.<key>
+	ResourceAttributes map[string]interface{} `json:"resource_attributes,omitempty"`
+}
+
+// RBACSimulationRole is a role of the user as seen by the simulated check
+type RBACSimulationRole struct {
+	// Role unique identifier
+	ID uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Role system name
+	Name string `json:"name" example:"moderator"`
+	// Role display name
+	DisplayName string `json:"display_name" example:"Moderator"`
+	// Whether the role took part in the decision
+	Considered bool `json:"considered" example:"true"`
+	// Whether the role grants the requested resource and action
+	Matched bool `json:"matched" example:"false"`
+	// Why the role was considered, skipped, matched or not
+	Detail string `json:"detail" example:"grants users.read, users.update on users but not delete"`
+	// Permissions of the role on the requested resource
+	ResourcePermissions []string `json:"resource_permissions"`
+}
+
+// RBACSimulationStep is one condition evaluated by the simulated check
+type RBACSimulationStep struct {
+	// Condition name
+	Name string `json:"name" example:"role_permissions"`
+	// Outcome: passed, failed or skipped
+	Status string `json:"status" example:"failed"`
+	// What was checked and why it passed or failed
+	Detail string `json:"detail" example:"none of the 2 roles grants users:delete"`
+}
+
+// RBACSimulationResponse is the full decision trace of a simulated permission check
+type RBACSimulationResponse struct {
+	// Whether access would be allowed
+	Allowed bool `json:"allowed" example:"false"`
+	// Decision: allow or deny
+	Decision string `json:"decision" example:"deny"`
+	// Rule that produced the decision: role_grants_permission, no_roles, no_matching_permission or condition_not_met
+	Rule string `json:"rule" example:"no_matching_permission"`
+	// Human-readable explanation of the decision
+	Reason string `json:"reason" example:"none of the user's roles grants delete on users"`
+	// Role that allowed access
+	MatchedRole string `json:"matched_role,omitempty" example:"admin"`
+	// Permission that allowed access
+	MatchedPermission string `json:"matched_permission,omitempty" example:"users.delete"`
+	// Roles of the user, in the order they were evaluated
+	Roles []RBACSimulationRole `json:"roles"`
+	// Conditions in the order they were evaluated
+	Steps []RBACSimulationStep `json:"steps"`
+	// Roles that would grant access if assigned to the user
+	GrantingRoles []string `json:"granting_roles"`
+	// Request attributes as received
+	Context map[string]interface{} `json:"context,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionRunResult summarizes one run of the data retention policies
+type RetentionRunResult struct {
+	AuditLogsDeleted int `json:"audit_logs_deleted"`
+	SessionsDeleted  int `json:"sessions_deleted"`
+	AccountsErased   int `json:"accounts_erased"`
+	Failed           int `json:"failed"`
+}
+
+// UserErasureResponse describes a completed erasure of a user's personal data
+type UserErasureResponse struct {
+	// Erased account; the row stays with anonymized fields so references to it remain valid
+	UserID uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+
+	// When the personal data was erased
+	ErasedAt time.Time `json:"erased_at" example:"2024-01-15T10:30:00Z"`
+
+	// Audit log entries of the user whose IP address, user agent, location and details were cleared
+	AuditLogsScrubbed int `json:"audit_logs_scrubbed" example:"42"`
+}
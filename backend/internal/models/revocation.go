@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevocationType is the kind of revocation carried by a RevocationEvent
+type RevocationType string
+
+const (
+	// RevocationTypeToken revokes one access or refresh token, identified by TokenHash
+	RevocationTypeToken RevocationType = "token"
+	// RevocationTypeSession revokes every token of SessionID
+	RevocationTypeSession RevocationType = "session"
+	// RevocationTypeUser revokes every token issued to UserID before RevokedAt
+	RevocationTypeUser RevocationType = "user"
+	// RevocationTypeAll revokes every token issued before RevokedAt
+	RevocationTypeAll RevocationType = "all"
+)
+
+// RevocationEvent announces that tokens stopped being valid before their expiry
+type RevocationEvent struct {
+	Type      RevocationType `json:"type"`
+	TokenHash string         `json:"token_hash,omitempty"`
+	SessionID *uuid.UUID     `json:"session_id,omitempty"`
+	UserID    *uuid.UUID     `json:"user_id,omitempty"`
+	RevokedAt time.Time      `json:"revoked_at"`
+	// When a token revocation no longer matters because the token itself has expired
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Risk signals raised by sign-in attempts
+const (
+	RiskSignalNewDevice        = "new_device"
+	RiskSignalNewGeo           = "new_geo"
+	RiskSignalImpossibleTravel = "impossible_travel"
+	RiskSignalTorExitNode      = "tor_exit_node"
+	RiskSignalVelocity         = "velocity"
+)
+
+// RiskSignals lists every risk signal
+var RiskSignals = []string{
+	RiskSignalNewDevice,
+	RiskSignalNewGeo,
+	RiskSignalImpossibleTravel,
+	RiskSignalTorExitNode,
+	RiskSignalVelocity,
+}
+
+// Decisions of the risk engine on a sign-in attempt
+const (
+	RiskActionAllow  = "allow"
+	RiskActionStepUp = "step_up"
+	RiskActionBlock  = "block"
+)
+
+// RiskPolicy scores sign-in attempts by the signals they raise and decides what to do
+// about them. A policy applies to the sign-ins of one application, or to every
+// application without its own policy when ApplicationID is nil.
+type RiskPolicy struct {
+	bun.BaseModel `bun:"table:risk_policies,alias:rp"`
+
+	ID            uuid.UUID  `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name          string     `json:"name" bun:"name,notnull,unique" example:"default"`
+	Description   string     `json:"description" bun:"description,notnull,default:''" example:"Step up sign-ins from new places"`
+	ApplicationID *uuid.UUID `json:"application_id,omitempty" bun:"application_id,type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+
+	// Score added by each raised signal, by signal name
+	Weights map[string]int `json:"weights" bun:"weights,type:jsonb,notnull" example:"new_device:20,new_geo:30,impossible_travel:60,tor_exit_node:50,velocity:40"`
+	// Scores at or above StepUpThreshold require 2FA; at or above BlockThreshold the attempt
+	// is rejected. 0 disables the decision.
+	StepUpThreshold int `json:"step_up_threshold" bun:"step_up_threshold,notnull,default:0" example:"30"`
+	BlockThreshold  int `json:"block_threshold" bun:"block_threshold,notnull,default:0" example:"80"`
+	// What to do when step-up is required from a user without 2FA: allow or block
+	StepUpFallback string `json:"step_up_fallback" bun:"step_up_fallback,notnull,default:'allow'" example:"allow"`
+
+	// Travel between two sign-ins faster than this raises impossible_travel
+	MaxTravelSpeedKmh int `json:"max_travel_speed_kmh" bun:"max_travel_speed_kmh,notnull,default:1000" example:"1000"`
+	// More sign-in attempts per user than VelocityMaxAttempts in VelocityWindowSeconds
+	// raise velocity
+	VelocityMaxAttempts   int `json:"velocity_max_attempts" bun:"velocity_max_attempts,notnull,default:10" example:"10"`
+	VelocityWindowSeconds int `json:"velocity_window_seconds" bun:"velocity_window_seconds,notnull,default:600" example:"600"`
+
+	IsActive  bool      `json:"is_active" bun:"is_active,notnull,default:true" example:"true"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+}
+
+// CreateRiskPolicyRequest creates a risk policy
+type CreateRiskPolicyRequest struct {
+	Name                  string         `json:"name" binding:"required,min=1,max=100" example:"default"`
+	Description           string         `json:"description" binding:"max=500" example:"Step up sign-ins from new places"`
+	ApplicationID         *uuid.UUID     `json:"application_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Weights               map[string]int `json:"weights" binding:"required,dive,keys,oneof=new_device new_geo impossible_travel tor_exit_node velocity,endkeys,min=0,max=100"`
+	StepUpThreshold       int            `json:"step_up_threshold" binding:"min=0" example:"30"`
+	BlockThreshold        int            `json:"block_threshold" binding:"min=0" example:"80"`
+	StepUpFallback        string         `json:"step_up_fallback" binding:"omitempty,oneof=allow block" example:"allow"`
+	MaxTravelSpeedKmh     int            `json:"max_travel_speed_kmh" binding:"min=0" example:"1000"`
+	VelocityMaxAttempts   int            `json:"velocity_max_attempts" binding:"min=0" example:"10"`
+	VelocityWindowSeconds int            `json:"velocity_window_seconds" binding:"min=0" example:"600"`
+}
+
+// UpdateRiskPolicyRequest updates a risk policy; omitted fields are kept
+type UpdateRiskPolicyRequest struct {
+	Description           *string        `json:"description" binding:"omitempty,max=500" example:"Step up sign-ins from new places"`
+	Weights               map[string]int `json:"weights" binding:"omitempty,dive,keys,oneof=new_device new_geo impossible_travel tor_exit_node velocity,endkeys,min=0,max=100"`
+	StepUpThreshold       *int           `json:"step_up_threshold" binding:"omitempty,min=0" example:"30"`
+	BlockThreshold        *int           `json:"block_threshold" binding:"omitempty,min=0" example:"80"`
+	StepUpFallback        *string        `json:"step_up_fallback" binding:"omitempty,oneof=allow block" example:"allow"`
+	MaxTravelSpeedKmh     *int           `json:"max_travel_speed_kmh" binding:"omitempty,min=0" example:"1000"`
+	VelocityMaxAttempts   *int           `json:"velocity_max_attempts" binding:"omitempty,min=0" example:"10"`
+	VelocityWindowSeconds *int           `json:"velocity_window_seconds" binding:"omitempty,min=0" example:"600"`
+	IsActive              *bool          `json:"is_active" example:"true"`
+}
+
+// RiskPolicyListResponse lists risk policies
+type RiskPolicyListResponse struct {
+	Policies []*RiskPolicy `json:"policies"`
+}
+
+// RiskAssessment is the risk engine's decision on a sign-in attempt
+type RiskAssessment struct {
+	PolicyID uuid.UUID `json:"policy_id"`
+	Score    int       `json:"score"`
+	Signals  []string  `json:"signals"`
+	Action   string    `json:"action"`
+}
+
+// AuditDetails returns the assessment as audit log details
+func (a *RiskAssessment) AuditDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"policy_id": a.PolicyID.String(),
+		"score":     a.Score,
+		"signals":   a.Signals,
+		"action":    a.Action,
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CreateSignedURLRequest asks for a signed URL to a protected resource
+type CreateSignedURLRequest struct {
+	// Absolute URL of the resource; its origin must be in SIGNED_URL_ALLOWED_ORIGINS
+	URL string `json:"url" binding:"required,url,max=2048" example:"https://files.example.com/exports/report.csv"`
+	// Method the URL may be used with; defaults to GET, which also admits HEAD
+	Method string `json:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE" example:"GET"`
+	// Permissions bound to the URL; the user must hold every one
+	Scopes []string `json:"scopes" binding:"omitempty,dive,min=1,max=100" example:"exports:read"`
+	// Lifetime; defaults to SIGNED_URL_DEFAULT_TTL and is capped by SIGNED_URL_MAX_TTL
+	ExpiresIn int `json:"expires_in" binding:"min=0" example:"300"`
+}
+
+// SignedURLResponse is a signed URL
+type SignedURLResponse struct {
+	URL       string    `json:"url" example:"https://files.example.com/exports/report.csv?ag_expires=1741082700&ag_signature=1a_X1cBZz8dxq_VNnB-RxOMzWP5mtF0Kd24nFndu__Q&ag_user=123e4567-e89b-12d3-a456-426614174000"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-03-04T10:05:00Z"`
+}
+
+// VerifySignedURLRequest asks whether a signed URL may be used
+type VerifySignedURLRequest struct {
+	URL string `json:"url" binding:"required,max=4096" example:"https://files.example.com/exports/report.csv?ag_expires=1741082700&ag_signature=1a_X1cBZz8dxq_VNnB-RxOMzWP5mtF0Kd24nFndu__Q&ag_user=123e4567-e89b-12d3-a456-426614174000"`
+	// Method of the request the URL came with; defaults to GET
+	Method string `json:"method" example:"GET"`
+}
+
+// VerifySignedURLResponse are the claims of a valid signed URL
+type VerifySignedURLResponse struct {
+	Valid     bool      `json:"valid" example:"true"`
+	UserID    string    `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Scopes    []string  `json:"scopes" example:"exports:read"`
+	Method    string    `json:"method" example:"GET"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-03-04T10:05:00Z"`
+}
+
+// InvalidSignedURLResponse explains why a signed URL was rejected
+type InvalidSignedURLResponse struct {
+	Valid        bool   `json:"valid" example:"false"`
+	ErrorMessage string `json:"error_message" example:"Signed URL expired"`
+}
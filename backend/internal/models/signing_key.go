@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SigningKeyInfo describes a loaded OIDC signing key without its private part
+type SigningKeyInfo struct {
+	KID       string `json:"kid" example:"key-2025-03"`
+	Algorithm string `json:"alg" example:"RS256"`
+	// Current is the key new tokens are signed with
+	Current bool `json:"current" example:"true"`
+	// RFC 7638 thumbprint of the public key, for comparing against ceremony records
+	Thumbprint string `json:"thumbprint" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+}
+
+// SigningKeyListResponse lists the loaded OIDC signing keys
+type SigningKeyListResponse struct {
+	Keys []SigningKeyInfo `json:"keys"`
+}
+
+// ExportSigningKeysRequest asks for an encrypted backup of the OIDC signing keys
+type ExportSigningKeysRequest struct {
+	// Passphrase the backup is encrypted with; it is needed to import the backup
+	Passphrase string `json:"passphrase" binding:"required,min=16,max=1024" example:"correct horse battery staple"`
+}
+
+// RecordKeyCeremonyRequest records how an OIDC signing key was generated
+type RecordKeyCeremonyRequest struct {
+	KID       string `json:"kid" binding:"required,max=255" example:"key-2025-03"`
+	Algorithm string `json:"alg" binding:"required,oneof=RS256 ES256" example:"RS256"`
+	// Who generated the key; defaults to the calling admin
+	GeneratedBy string    `json:"generated_by" binding:"max=255" example:"alice@example.com"`
+	GeneratedAt time.Time `json:"generated_at" binding:"required" example:"2025-03-04T10:00:00Z"`
+	// RFC 7638 thumbprint of the public key, as printed by `auth-gateway keys generate`
+	Thumbprint string   `json:"thumbprint" binding:"max=128" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+	Witnesses  []string `json:"witnesses" binding:"omitempty,max=20,dive,min=1,max=255" example:"bob@example.com"`
+	Location   string   `json:"location" binding:"max=255" example:"HQ, room 4.12, offline laptop"`
+	Notes      string   `json:"notes" binding:"max=2000" example:"Keys written to two encrypted USB drives"`
+}
+
+// KeyCeremonyResponse is a recorded key ceremony
+type KeyCeremonyResponse struct {
+	RecordKeyCeremonyRequest
+	RecordedBy string    `json:"recorded_by" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RecordedAt time.Time `json:"recorded_at" example:"2025-03-04T10:05:00Z"`
+}
+
+// StoredSigningKey is an OIDC signing key generated by automatic key rotation and shared by
+// all replicas through the database. PrivateKey is the PKCS#8 PEM key encrypted with
+// ENCRYPTION_KEY.
+type StoredSigningKey struct {
+	bun.BaseModel `bun:"table:oidc_signing_keys,alias:osk"`
+
+	KID        string `json:"kid" bun:"kid,pk"`
+	Algorithm  string `json:"alg" bun:"algorithm,notnull"`
+	PrivateKey string `json:"-" bun:"private_key,notnull"`
+	// ActivatesAt is when new tokens start being signed with the key; it is published in the
+	// JWKS before that
+	ActivatesAt time.Time `json:"activates_at" bun:"activates_at,notnull"`
+	CreatedAt   time.Time `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+}
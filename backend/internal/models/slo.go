@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// SLOType is what a service level objective measures
+type SLOType string
+
+const (
+	// SLOTypeAvailability counts requests answered without a server error
+	SLOTypeAvailability SLOType = "availability"
+	// SLOTypeLatency counts requests answered within the latency threshold
+	SLOTypeLatency SLOType = "latency"
+)
+
+// Severities of SLO budget burn alerts
+const (
+	// SLOAlertCritical means the error budget will be exhausted within days
+	SLOAlertCritical = "critical"
+	// SLOAlertWarning means the error budget burns faster than it may over the window
+	SLOAlertWarning = "warning"
+)
+
+// SLODefinition is a built-in service level objective of a critical endpoint
+type SLODefinition struct {
+	// Unique name, used as the metric label
+	Name string
+	// HTTP method and route of the endpoint
+	Method string
+	Route  string
+	Type   SLOType
+	// Share of requests that must be good, e.g. 0.999
+	Objective float64
+	// Latency a request must be answered within to be good; latency SLOs only
+	LatencyThreshold time.Duration
+}
+
+// SLOBurnRate is how fast the error budget of an SLO burned over a recent window. A burn
+// rate of 1 exhausts the budget exactly at the end of the SLO window.
+type SLOBurnRate struct {
+	// Window the burn rate was computed over
+	Window string `json:"window" example:"1h"`
+	// Requests and bad requests in the window
+	TotalRequests int64 `json:"total_requests" example:"12000"`
+	BadRequests   int64 `json:"bad_requests" example:"3"`
+	// Error rate divided by the error budget of the objective
+	BurnRate float64 `json:"burn_rate" example:"0.25"`
+}
+
+// SLOStatus is the state of the error budget of an SLO
+type SLOStatus struct {
+	// SLO name
+	Name string `json:"name" example:"token_availability"`
+	// Endpoint the SLO covers
+	Endpoint string  `json:"endpoint" example:"POST /oauth/token"`
+	Type     SLOType `json:"type" example:"availability"`
+	// Share of requests that must be good
+	Objective float64 `json:"objective" example:"0.999"`
+	// Latency threshold in milliseconds; latency SLOs only
+	LatencyThresholdMs int64 `json:"latency_threshold_ms,omitempty" example:"300"`
+	// Requests and bad requests over the SLO window
+	TotalRequests int64 `json:"total_requests" example:"1250000"`
+	BadRequests   int64 `json:"bad_requests" example:"310"`
+	// Share of good requests over the SLO window
+	SLI float64 `json:"sli" example:"0.99975"`
+	// Share of the error budget left; negative once the budget is exhausted
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining" example:"0.752"`
+	// Burn rates over the alerting windows
+	BurnRates []SLOBurnRate `json:"burn_rates"`
+	// Severity of the alert currently raised for the SLO, if any
+	Alert string `json:"alert,omitempty" example:"warning"`
+}
+
+// SLOListResponse lists the SLOs of the service with their error budgets
+type SLOListResponse struct {
+	// SLO window the error budgets are computed over
+	Window string      `json:"window" example:"720h0m0s"`
+	SLOs   []SLOStatus `json:"slos"`
+	// Time the SLOs were tracked since: the start of this instance
+	TrackedSince time.Time `json:"tracked_since"`
+}
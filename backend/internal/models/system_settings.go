@@ -45,6 +45,12 @@ type MaintenanceModeResponse struct {
 	Message string `json:"message" example:""`
 }
 
+// TokenEpochResponse returns the global token invalidation epoch
+type TokenEpochResponse struct {
+	// Tokens issued at or before this time are rejected; omitted if never set
+	Epoch *time.Time `json:"epoch,omitempty" example:"2024-01-15T10:30:00Z"`
+}
+
 // System setting keys
 const (
 	SettingMaintenanceMode          = "maintenance_mode"
@@ -53,6 +59,7 @@ const (
 	SettingRequireEmailVerification = "require_email_verification"
 	SettingMaxSessionsPerUser       = "max_sessions_per_user"
 	SettingSessionTimeoutHours      = "session_timeout_hours"
+	SettingTokenEpoch               = "token_epoch" // Unix seconds; tokens issued at or before it are rejected
 )
 
 // HealthMetric represents a system health metric
@@ -76,6 +83,49 @@ type SystemHealthResponse struct {
 	Metrics             []HealthMetric         `json:"metrics,omitempty"`
 }
 
+// Public status values, used for the service and for each of its components
+const (
+	StatusOperational = "operational"
+	StatusDegraded    = "degraded"
+	StatusOutage      = "outage"
+	StatusMaintenance = "maintenance" // service only
+)
+
+// PublicStatusResponse is the unauthenticated status of the service for public status
+// pages. It never carries error details, hostnames or pool statistics.
+type PublicStatusResponse struct {
+	// Overall status: operational, degraded, outage or maintenance
+	Status string `json:"status" example:"operational"`
+	// Version of the running build
+	Version string `json:"version" example:"v1.4.0"`
+	// Seconds since the instance started
+	Uptime int64 `json:"uptime_seconds" example:"86400"`
+	// Status of each dependency
+	Components []PublicStatusComponent `json:"components"`
+	// Current maintenance window; omitted when maintenance mode is off
+	Maintenance *PublicMaintenanceWindow `json:"maintenance,omitempty"`
+	// When the checks were run
+	CheckedAt time.Time `json:"checked_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// PublicStatusComponent is the status of one dependency of the service
+type PublicStatusComponent struct {
+	// Component name
+	Name string `json:"name" example:"database"`
+	// operational, degraded or outage
+	Status string `json:"status" example:"operational"`
+	// Latency of the check in milliseconds; omitted when the component is unreachable
+	LatencyMs *int64 `json:"latency_ms,omitempty" example:"3"`
+}
+
+// PublicMaintenanceWindow describes the maintenance in progress
+type PublicMaintenanceWindow struct {
+	// Message shown to users during maintenance
+	Message string `json:"message" example:"Scheduled database upgrade."`
+	// When maintenance mode was turned on
+	Since time.Time `json:"since" example:"2024-01-15T10:00:00Z"`
+}
+
 // DatabaseConnectionInfo contains database connection pool stats
 type DatabaseConnectionInfo struct {
 	MaxOpen      int `json:"max_open"`
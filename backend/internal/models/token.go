@@ -54,14 +54,18 @@ type AuthResponse struct {
 	Requires2FA bool `json:"requires_2fa,omitempty" example:"false"`
 	// Temporary token for 2FA verification (if 2FA is required)
 	TwoFactorToken string `json:"two_factor_token,omitempty" example:"temp_2fa_token_xyz"`
+	// Second factors the user can complete sign-in with, preferred first: webauthn, totp, backup_code
+	TwoFactorMethods []string `json:"two_factor_methods,omitempty" example:"webauthn,totp,backup_code"`
+	// Whether the email address must be verified before signing in (no tokens are issued)
+	RequiresEmailVerification bool `json:"requires_email_verification,omitempty" example:"false"`
 }
 
 // TwoFactorLoginVerifyRequest represents 2FA verification during login
 type TwoFactorLoginVerifyRequest struct {
 	// Temporary 2FA token from initial login response
 	TwoFactorToken string `json:"two_factor_token" binding:"required" example:"temp_2fa_token_xyz"`
-	// 6-digit TOTP code from authenticator app
-	Code string `json:"code" binding:"required,len=6" example:"123456"`
+	// 6-digit TOTP code from authenticator app, or an 8-character backup code
+	Code string `json:"code" binding:"required,min=6,max=8" example:"123456"`
 }
 
 // JWTClaims represents custom JWT claims
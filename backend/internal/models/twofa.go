@@ -44,6 +44,12 @@ type TwoFactorStatusResponse struct {
 	EnabledAt *time.Time `json:"enabled_at,omitempty" example:"2024-01-15T10:30:00Z"`
 	// Number of remaining backup codes
 	BackupCodes int `json:"backup_codes_remaining" example:"3"`
+	// Whether TOTP is enabled
+	TOTPEnabled bool `json:"totp_enabled" example:"true"`
+	// Number of registered security keys
+	WebAuthnCredentials int `json:"webauthn_credentials" example:"1"`
+	// Second factors available at sign-in, preferred first
+	Methods []string `json:"methods" example:"webauthn,totp,backup_code"`
 }
 
 // TwoFactorLoginRequest represents a 2FA code submission during login
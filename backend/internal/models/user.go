@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 // User represents a user in the system
@@ -31,18 +32,38 @@ type User struct {
 	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" bun:"email_verified_at" example:"2024-01-15T10:30:00Z"`
 	// Whether phone number has been verified
 	PhoneVerified bool `json:"phone_verified" bun:"phone_verified" example:"false"`
-	// Whether the account is active
+	// Verified secondary email chosen to receive account recovery messages
+	RecoveryEmail *string `json:"recovery_email,omitempty" bun:"recovery_email" example:"backup@example.com"`
+	// Whether the recovery email has been verified
+	RecoveryEmailVerified bool `json:"recovery_email_verified" bun:"recovery_email_verified,notnull,default:false" example:"false"`
+	// Whether the account is active; mirrors State == "active"
 	IsActive bool `json:"is_active" bun:"is_active" example:"true"`
+	// Lifecycle state: invited, active, suspended, deactivated or deleted
+	State UserState `json:"state" bun:"state,notnull" example:"active"`
+	// Timestamp of the last lifecycle state change
+	StateChangedAt *time.Time `json:"state_changed_at,omitempty" bun:"state_changed_at" example:"2024-01-15T10:30:00Z"`
+	// Timestamp of the last successful sign-in
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" bun:"last_login_at" example:"2024-01-15T10:30:00Z"`
+	// Whether this is an anonymous guest account that has not been upgraded yet
+	IsGuest bool `json:"is_guest" bun:"is_guest,notnull,default:false" example:"false"`
 	// TOTP secret for 2FA (never exposed in responses)
 	TOTPSecret *string `json:"-" bun:"totp_secret"`
 	// Whether TOTP 2FA is enabled
 	TOTPEnabled bool `json:"totp_enabled" bun:"totp_enabled" example:"false"`
 	// Timestamp when TOTP 2FA was enabled
 	TOTPEnabledAt *time.Time `json:"totp_enabled_at,omitempty" bun:"totp_enabled_at" example:"2024-01-15T10:30:00Z"`
+	// Whether the user has registered a WebAuthn security key as a second factor
+	WebAuthnEnabled bool `json:"webauthn_enabled" bun:"webauthn_enabled,notnull,default:false" example:"false"`
 	// Timestamp when password expires (optional, for password expiry policy)
 	PasswordExpiresAt *time.Time `json:"password_expires_at,omitempty" bun:"password_expires_at" example:"2024-02-15T10:30:00Z"`
 	// Timestamp when password was last changed
 	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty" bun:"password_changed_at" example:"2024-01-15T10:30:00Z"`
+	// Whether the user must set a new password before signing in again
+	MustChangePassword bool `json:"must_change_password" bun:"must_change_password,notnull,default:false" example:"false"`
+	// Timestamp when the upcoming password expiry warning was sent
+	PasswordExpiryWarnedAt *time.Time `json:"-" bun:"password_expiry_warned_at"`
+	// Token version embedded in issued JWTs; bumping it invalidates all outstanding tokens
+	TokenVersion int `json:"-" bun:"token_version,notnull,default:0"`
 	// Timestamp when user was created
 	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when user was last updated
@@ -65,12 +86,25 @@ func (u *User) BeforeInsert(ctx context.Context) error {
 	return nil
 }
 
+// BeforeAppendModel fills in the lifecycle state for code that only sets IsActive
+func (u *User) BeforeAppendModel(ctx context.Context, query bun.QueryHook) error {
+	if u.State == "" {
+		u.State = u.LifecycleState()
+	}
+	return nil
+}
+
 // BeforeUpdate hook for automatic timestamp management
 func (u *User) BeforeUpdate(ctx context.Context) error {
 	u.UpdatedAt = time.Now()
 	return nil
 }
 
+// TwoFactorEnabled reports whether sign-in needs a second factor: a security key or a TOTP code
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabled || u.WebAuthnEnabled
+}
+
 // AccountType defines user account types
 type AccountType string
 
@@ -89,6 +123,23 @@ func IsValidAccountType(accountType string) bool {
 	}
 }
 
+// Login identifiers accepted by password sign-in
+const (
+	LoginIdentifierEmail    = "email"
+	LoginIdentifierPhone    = "phone"
+	LoginIdentifierUsername = "username"
+)
+
+// IsValidLoginIdentifier checks if a login identifier kind is known
+func IsValidLoginIdentifier(kind string) bool {
+	switch kind {
+	case LoginIdentifierEmail, LoginIdentifierPhone, LoginIdentifierUsername:
+		return true
+	default:
+		return false
+	}
+}
+
 // CreateUserRequest represents a request to create a new user
 type CreateUserRequest struct {
 	// User's email address (optional if phone is provided)
@@ -123,10 +174,14 @@ type ChangePasswordRequest struct {
 
 // SignInRequest represents a sign-in request
 type SignInRequest struct {
-	// User's email address (required if phone not provided)
+	// User's email address (one of email, phone, username or identifier is required)
 	Email string `json:"email" binding:"omitempty" example:"user@example.com"`
-	// User's phone number (optional)
+	// User's phone number in E.164 format (optional)
 	Phone *string `json:"phone,omitempty" example:"+1234567890"`
+	// User's username (optional)
+	Username string `json:"username,omitempty" example:"johndoe"`
+	// Email, phone or username; the kind is detected from the format (optional)
+	Identifier string `json:"identifier,omitempty" example:"johndoe"`
 	// User's password
 	Password string `json:"password" binding:"required" example:"SecurePass123!"`
 }
@@ -166,8 +221,12 @@ func (u *User) PublicUser() *User {
 		EmailVerified:     u.EmailVerified,
 		PhoneVerified:     u.PhoneVerified,
 		IsActive:          u.IsActive,
+		State:             u.LifecycleState(),
+		IsGuest:           u.IsGuest,
 		TOTPEnabled:       u.TOTPEnabled,
 		TOTPEnabledAt:     u.TOTPEnabledAt,
+		WebAuthnEnabled:   u.WebAuthnEnabled,
+		LastLoginAt:       u.LastLoginAt,
 		CreatedAt:         u.CreatedAt,
 		UpdatedAt:         u.UpdatedAt,
 		Roles:             u.Roles,
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// MaxSecondaryEmails is the maximum number of secondary email addresses per user
+const MaxSecondaryEmails = 10
+
+// UserEmail is a secondary email address of a user. The primary address stays in users.email;
+// verified secondary addresses can be used as a login identifier and chosen as the recovery address.
+type UserEmail struct {
+	bun.BaseModel `bun:"table:user_emails,alias:ue"`
+
+	// Unique identifier
+	ID uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Owner of the address
+	UserID uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Email address (normalized)
+	Email string `json:"email" bun:"email,notnull" example:"backup@example.com"`
+	// Whether the address has been verified
+	Verified bool `json:"verified" bun:"verified,notnull,default:false" example:"true"`
+	// Timestamp when the address was verified
+	VerifiedAt *time.Time `json:"verified_at,omitempty" bun:"verified_at" example:"2024-01-15T10:30:00Z"`
+	// Whether account recovery messages go to this address
+	IsRecovery bool `json:"is_recovery" bun:"-" example:"false"`
+	// Timestamp when the address was added
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+}
+
+// UserEmailListResponse lists all email addresses of the current user
+type UserEmailListResponse struct {
+	// Primary email address
+	Primary string `json:"primary" example:"user@example.com"`
+	// Whether the primary email address has been verified
+	PrimaryVerified bool `json:"primary_verified" example:"true"`
+	// Address that receives account recovery messages, if chosen
+	RecoveryEmail *string `json:"recovery_email,omitempty" example:"backup@example.com"`
+	// Secondary email addresses
+	Emails []*UserEmail `json:"emails"`
+}
+
+// AddUserEmailRequest adds a secondary email address
+type AddUserEmailRequest struct {
+	// Email address to add; a verification code is sent to it
+	Email string `json:"email" binding:"required,email" example:"backup@example.com"`
+}
+
+// VerifyUserEmailRequest verifies a secondary email address
+type VerifyUserEmailRequest struct {
+	// Email address being verified
+	Email string `json:"email" binding:"required,email" example:"backup@example.com"`
+	// 6-digit code sent to the address
+	Code string `json:"code" binding:"required,len=6" example:"123456"`
+}
+
+// SetRecoveryEmailRequest chooses where account recovery messages go
+type SetRecoveryEmailRequest struct {
+	// One of the user's verified secondary email addresses
+	Email string `json:"email" binding:"required,email" example:"backup@example.com"`
+}
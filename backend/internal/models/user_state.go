@@ -0,0 +1,132 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// UserState is a stage in a user account's lifecycle
+type UserState string
+
+const (
+	// UserStateInvited is an account created by an administrator whose owner has not set a password yet
+	UserStateInvited UserState = "invited"
+	// UserStateActive is an account that can sign in
+	UserStateActive UserState = "active"
+	// UserStateSuspended is an account temporarily blocked by an administrator
+	UserStateSuspended UserState = "suspended"
+	// UserStateDeactivated is an account switched off by an administrator or for inactivity
+	UserStateDeactivated UserState = "deactivated"
+	// UserStateDeleted is an account that was deleted; it can never come back
+	UserStateDeleted UserState = "deleted"
+)
+
+// userStateTransitions lists the states each state may move to
+var userStateTransitions = map[UserState][]UserState{
+	UserStateInvited:     {UserStateActive, UserStateDeleted},
+	UserStateActive:      {UserStateSuspended, UserStateDeactivated, UserStateDeleted},
+	UserStateSuspended:   {UserStateActive, UserStateDeactivated, UserStateDeleted},
+	UserStateDeactivated: {UserStateActive, UserStateDeleted},
+	UserStateDeleted:     {},
+}
+
+// Sign-in errors for accounts that are not active
+var (
+	ErrAccountInvited     = &AppError{Code: http.StatusForbidden, Message: "Account invitation has not been accepted yet"}
+	ErrAccountSuspended   = &AppError{Code: http.StatusForbidden, Message: "Account is suspended"}
+	ErrAccountDeactivated = &AppError{Code: http.StatusForbidden, Message: "Account is deactivated"}
+	ErrInvalidUserState   = &AppError{Code: http.StatusBadRequest, Message: "Invalid user state"}
+	ErrUserStateConflict  = &AppError{Code: http.StatusConflict, Message: "User state was changed concurrently"}
+)
+
+// IsValid checks if the state is known
+func (s UserState) IsValid() bool {
+	_, ok := userStateTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an account in this state may move to next
+func (s UserState) CanTransitionTo(next UserState) bool {
+	for _, allowed := range userStateTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSignIn reports whether tokens may be issued to an account in this state
+func (s UserState) CanSignIn() bool {
+	return s == UserStateActive
+}
+
+// RevokesTokens reports whether entering this state must invalidate the account's tokens and sessions
+func (s UserState) RevokesTokens() bool {
+	switch s {
+	case UserStateSuspended, UserStateDeactivated, UserStateDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// SignInError returns the error shown when an account in this state tries to sign in,
+// or nil when sign-in is allowed. Deleted accounts look like unknown ones.
+func (s UserState) SignInError() error {
+	switch s {
+	case UserStateActive:
+		return nil
+	case UserStateInvited:
+		return ErrAccountInvited
+	case UserStateSuspended:
+		return ErrAccountSuspended
+	case UserStateDeactivated:
+		return ErrAccountDeactivated
+	default:
+		return ErrInvalidCredentials
+	}
+}
+
+// LifecycleState returns the account's state. Rows written before lifecycle states existed
+// only carry is_active, so the state is derived from it when unset.
+func (u *User) LifecycleState() UserState {
+	if u.State != "" {
+		return u.State
+	}
+	if u.IsActive {
+		return UserStateActive
+	}
+	return UserStateDeactivated
+}
+
+// SetState moves the account to state and keeps is_active in step with it
+func (u *User) SetState(state UserState) {
+	now := time.Now()
+	u.State = state
+	u.IsActive = state == UserStateActive
+	u.StateChangedAt = &now
+}
+
+// SetActive is the is_active flavour of SetState, used by callers that only toggle the flag
+// (SCIM, bulk updates). Deleted accounts stay deleted and invited ones stay invited until activated.
+func (u *User) SetActive(active bool) {
+	current := u.LifecycleState()
+	switch {
+	case current == UserStateDeleted:
+		u.State = current
+	case active && current != UserStateActive:
+		u.SetState(UserStateActive)
+	case !active && current == UserStateActive:
+		u.SetState(UserStateDeactivated)
+	default:
+		u.State = current
+	}
+}
+
+// UpdateUserStateRequest is an admin request to move a user to another lifecycle state
+type UpdateUserStateRequest struct {
+	// Target state: active, suspended, deactivated or deleted
+	State UserState `json:"state" binding:"required" example:"suspended"`
+	// Why the state is being changed; recorded in the audit log and the webhook
+	Reason string `json:"reason,omitempty" binding:"max=500" example:"Chargeback under investigation"`
+}
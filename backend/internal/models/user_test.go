@@ -100,3 +100,51 @@ func TestUser_PublicUser(t *testing.T) {
 	// Check new instance
 	assert.NotSame(t, u, public)
 }
+
+func TestUserState_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to UserState
+		allowed  bool
+	}{
+		{UserStateInvited, UserStateActive, true},
+		{UserStateInvited, UserStateSuspended, false},
+		{UserStateActive, UserStateSuspended, true},
+		{UserStateActive, UserStateInvited, false},
+		{UserStateSuspended, UserStateActive, true},
+		{UserStateDeactivated, UserStateActive, true},
+		{UserStateDeactivated, UserStateSuspended, false},
+		{UserStateDeleted, UserStateActive, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestUser_SetActive(t *testing.T) {
+	t.Run("Deactivates an active user", func(t *testing.T) {
+		u := &User{State: UserStateActive, IsActive: true}
+		u.SetActive(false)
+		assert.Equal(t, UserStateDeactivated, u.State)
+		assert.False(t, u.IsActive)
+		assert.NotNil(t, u.StateChangedAt)
+	})
+
+	t.Run("Keeps a suspended user suspended", func(t *testing.T) {
+		u := &User{State: UserStateSuspended}
+		u.SetActive(false)
+		assert.Equal(t, UserStateSuspended, u.State)
+	})
+
+	t.Run("Never revives a deleted user", func(t *testing.T) {
+		u := &User{State: UserStateDeleted}
+		u.SetActive(true)
+		assert.Equal(t, UserStateDeleted, u.State)
+		assert.False(t, u.IsActive)
+	})
+
+	t.Run("Derives the state of legacy users from is_active", func(t *testing.T) {
+		assert.Equal(t, UserStateActive, (&User{IsActive: true}).LifecycleState())
+		assert.Equal(t, UserStateDeactivated, (&User{}).LifecycleState())
+	})
+}
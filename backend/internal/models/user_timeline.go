@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Sources of user timeline events
+const (
+	TimelineSourceAudit   = "audit"
+	TimelineSourceSession = "session"
+	TimelineSourceRole    = "role"
+	TimelineSourceConsent = "consent"
+	TimelineSourceAPIKey  = "api_key"
+)
+
+// TimelineSources lists every user timeline source
+var TimelineSources = []string{
+	TimelineSourceAudit,
+	TimelineSourceSession,
+	TimelineSourceRole,
+	TimelineSourceConsent,
+	TimelineSourceAPIKey,
+}
+
+// UserTimelineEvent is one entry of a user's activity timeline
+type UserTimelineEvent struct {
+	// Where the event comes from: audit, session, role, consent or api_key
+	Source string `json:"source" bun:"source" example:"session"`
+	// What happened: the audit action, or session_started, session_revoked, consent_granted,
+	// consent_revoked, api_key_created, api_key_used or api_key_revoked
+	Event string `json:"event" bun:"event" example:"session_started"`
+	// ID of the audit log entry, session, consent or API key
+	ResourceID string `json:"resource_id" bun:"resource_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Outcome for audit and role events
+	Status string `json:"status,omitempty" bun:"status" example:"success"`
+	// Client IP address, when known
+	IPAddress string `json:"ip_address,omitempty" bun:"ip_address" example:"192.168.1.1"`
+	// Source-specific details: audit details, session device, consent client and scopes, API key name
+	Details json.RawMessage `json:"details,omitempty" bun:"details" swaggertype:"object"`
+	// When the event happened
+	OccurredAt time.Time `json:"occurred_at" bun:"occurred_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// UserTimelineResponse contains a page of a user's activity timeline
+type UserTimelineResponse struct {
+	// Events, newest first
+	Events []*UserTimelineEvent `json:"events"`
+	// Total number of matching events
+	Total int `json:"total" example:"120"`
+	// Current page number
+	Page int `json:"page" example:"1"`
+	// Number of items per page
+	PageSize int `json:"page_size" example:"20"`
+	// Total number of pages
+	TotalPages int `json:"total_pages" example:"6"`
+}
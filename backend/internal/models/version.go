@@ -0,0 +1,23 @@
+package models
+
+// APIVersionV1 is the version of the REST and gRPC API contract. It changes only when a
+// change breaks existing clients; additions keep the version.
+const APIVersionV1 = "v1"
+
+// SupportedAPIVersions lists the API versions this build serves, oldest first
+var SupportedAPIVersions = []string{APIVersionV1}
+
+// VersionResponse is the build information and API versions of the server, used by
+// clients to check that they are compatible with it
+type VersionResponse struct {
+	// Semantic version of the build, or "dev" for untagged builds
+	Version string `json:"version" example:"v1.4.0"`
+	// Git commit SHA the build was made from
+	Commit string `json:"commit,omitempty" example:"9f1c2d3e4b5a69788796a5b4c3d2e1f0a9b8c7d6"`
+	// When the binary was built
+	BuildDate string `json:"build_date,omitempty" example:"2024-01-15T10:30:00Z"`
+	// Go toolchain the binary was built with
+	GoVersion string `json:"go_version" example:"go1.24.1"`
+	// API versions the server serves, oldest first
+	APIVersions []string `json:"api_versions" example:"v1"`
+}
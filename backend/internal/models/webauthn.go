@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Second factors a user can complete sign-in with, in the order clients should offer them
+const (
+	TwoFactorMethodWebAuthn   = "webauthn"
+	TwoFactorMethodTOTP       = "totp"
+	TwoFactorMethodBackupCode = "backup_code"
+)
+
+// WebAuthnCredential is a FIDO2 security key registered as a second factor
+type WebAuthnCredential struct {
+	bun.BaseModel `bun:"table:webauthn_credentials,alias:wc"`
+
+	ID     uuid.UUID `json:"id" bun:"id,pk,type:uuid,default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID uuid.UUID `json:"-" bun:"user_id,type:uuid,notnull"`
+	// Name the user gave the key
+	Name string `json:"name" bun:"name,notnull" example:"YubiKey 5"`
+	// Credential ID chosen by the authenticator
+	CredentialID []byte `json:"-" bun:"credential_id,notnull"`
+	// COSE-encoded public key
+	PublicKey       []byte   `json:"-" bun:"public_key,notnull"`
+	AttestationType string   `json:"-" bun:"attestation_type,notnull"`
+	Transports      []string `json:"transports" bun:"transports,type:jsonb,notnull" example:"usb,nfc"`
+	// Authenticator model identifier
+	AAGUID []byte `json:"-" bun:"aaguid"`
+	// Signature counter of the last assertion, used to detect cloned keys
+	SignCount uint32 `json:"-" bun:"sign_count,notnull"`
+	// Whether the key can be synced between devices, as a passkey can
+	BackupEligible bool `json:"backup_eligible" bun:"backup_eligible,notnull" example:"false"`
+	BackupState    bool `json:"-" bun:"backup_state,notnull"`
+	// When the key last completed a sign-in
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bun:"last_used_at" example:"2024-01-15T10:30:00Z"`
+	CreatedAt  time.Time  `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp" example:"2024-01-15T10:30:00Z"`
+}
+
+// WebAuthnRegisterBeginRequest starts registering a security key
+type WebAuthnRegisterBeginRequest struct {
+	// User's current password for verification
+	Password string `json:"password" binding:"required" example:"SecurePass123!"`
+}
+
+// WebAuthnRegisterFinishRequest completes registering a security key
+type WebAuthnRegisterFinishRequest struct {
+	// Name for the key, shown in the list of keys
+	Name string `json:"name" binding:"required,max=100" example:"YubiKey 5"`
+	// PublicKeyCredential returned by navigator.credentials.create()
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// WebAuthnRegisterFinishResponse is returned once a security key is registered
+type WebAuthnRegisterFinishResponse struct {
+	// The registered key
+	Credential *WebAuthnCredential `json:"credential"`
+	// Backup codes, returned only when the key is the user's first second factor
+	BackupCodes []string `json:"backup_codes,omitempty" example:"a1B2c3D4,e5F6g7H8"`
+}
+
+// WebAuthnCredentialDeleteRequest removes a security key
+type WebAuthnCredentialDeleteRequest struct {
+	// User's current password for verification
+	Password string `json:"password" binding:"required" example:"SecurePass123!"`
+}
+
+// WebAuthnLoginBeginRequest starts signing in with a security key
+type WebAuthnLoginBeginRequest struct {
+	// Temporary 2FA token from the sign-in response
+	TwoFactorToken string `json:"two_factor_token" binding:"required" example:"temp_2fa_token_xyz"`
+}
+
+// WebAuthnLoginFinishRequest completes signing in with a security key
+type WebAuthnLoginFinishRequest struct {
+	// Temporary 2FA token from the sign-in response
+	TwoFactorToken string `json:"two_factor_token" binding:"required" example:"temp_2fa_token_xyz"`
+	// PublicKeyCredential returned by navigator.credentials.get()
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
@@ -145,11 +145,13 @@ const (
 	WebhookEventUserLogin         = "user.login"
 	WebhookEventUserLogout        = "user.logout"
 	WebhookEventUserPasswordReset = "user.password_reset"
+	WebhookEventUserStateChanged  = "user.state_changed"
 	WebhookEventAPIKeyCreated     = "api_key.created"
 	WebhookEventAPIKeyRevoked     = "api_key.revoked"
 	WebhookEventRoleCreated       = "role.created"
 	WebhookEventRoleUpdated       = "role.updated"
 	WebhookEventRoleDeleted       = "role.deleted"
+	WebhookEventSLOBudgetBurn     = "slo.budget_burn"
 )
 
 // GetAvailableEvents returns all available webhook events
@@ -163,11 +165,13 @@ func GetAvailableEvents() []string {
 		WebhookEventUserLogin,
 		WebhookEventUserLogout,
 		WebhookEventUserPasswordReset,
+		WebhookEventUserStateChanged,
 		WebhookEventAPIKeyCreated,
 		WebhookEventAPIKeyRevoked,
 		WebhookEventRoleCreated,
 		WebhookEventRoleUpdated,
 		WebhookEventRoleDeleted,
+		WebhookEventSLOBudgetBurn,
 	}
 }
 
@@ -1,6 +1,10 @@
 package queryopt
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // --- UserStore options ---
 
@@ -70,6 +74,93 @@ func BuildUserListOptions(opts []UserListOption) UserListOptions {
 	return o
 }
 
+// UserFilterOptions holds filters for UserStore Filter and CountFiltered.
+type UserFilterOptions struct {
+	Search        string
+	AppID         *uuid.UUID
+	Roles         []string
+	States        []string
+	EmailVerified *bool
+	TwoFactor     *bool
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	Metadata      map[string]string
+	// AfterCreatedAt and AfterID continue a listing after the user they identify
+	AfterCreatedAt *time.Time
+	AfterID        uuid.UUID
+}
+
+// UserFilterOption configures UserFilterOptions.
+type UserFilterOption func(*UserFilterOptions)
+
+// UserFilterSearch keeps users whose username, full name or any email address contain text,
+// ignoring case.
+func UserFilterSearch(text string) UserFilterOption {
+	return func(o *UserFilterOptions) { o.Search = text }
+}
+
+// UserFilterApp keeps the users of an application.
+func UserFilterApp(id uuid.UUID) UserFilterOption {
+	return func(o *UserFilterOptions) { o.AppID = &id }
+}
+
+// UserFilterRoles keeps users with any of the given roles, by name.
+func UserFilterRoles(roles ...string) UserFilterOption {
+	return func(o *UserFilterOptions) { o.Roles = append(o.Roles, roles...) }
+}
+
+// UserFilterStates keeps users in any of the given lifecycle states.
+func UserFilterStates(states ...string) UserFilterOption {
+	return func(o *UserFilterOptions) { o.States = append(o.States, states...) }
+}
+
+// UserFilterEmailVerified filters by whether the primary email is verified.
+func UserFilterEmailVerified(v bool) UserFilterOption {
+	return func(o *UserFilterOptions) { o.EmailVerified = &v }
+}
+
+// UserFilterTwoFactor filters by whether TOTP or a security key is enabled.
+func UserFilterTwoFactor(v bool) UserFilterOption {
+	return func(o *UserFilterOptions) { o.TwoFactor = &v }
+}
+
+// UserFilterCreatedFrom keeps users created at or after t.
+func UserFilterCreatedFrom(t time.Time) UserFilterOption {
+	return func(o *UserFilterOptions) { o.CreatedFrom = &t }
+}
+
+// UserFilterCreatedTo keeps users created before t.
+func UserFilterCreatedTo(t time.Time) UserFilterOption {
+	return func(o *UserFilterOptions) { o.CreatedTo = &t }
+}
+
+// UserFilterMetadata keeps users whose application profile metadata has key set to value.
+func UserFilterMetadata(key, value string) UserFilterOption {
+	return func(o *UserFilterOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// UserFilterAfter keeps the users listed after the user created at createdAt with id,
+// newest first.
+func UserFilterAfter(createdAt time.Time, id uuid.UUID) UserFilterOption {
+	return func(o *UserFilterOptions) {
+		o.AfterCreatedAt = &createdAt
+		o.AfterID = id
+	}
+}
+
+func BuildUserFilterOptions(opts []UserFilterOption) UserFilterOptions {
+	var o UserFilterOptions
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
 // --- OAuthClientStore options ---
 
 // OAuthClientListOptions holds options for OAuthProviderStore ListClients method.
@@ -121,3 +212,75 @@ func BuildAPIKeyGetOptions(opts []APIKeyGetOption) APIKeyGetOptions {
 	}
 	return o
 }
+
+// --- AuditStore options ---
+
+// AuditLogListOptions holds filters for AuditStore Search and aggregation methods.
+type AuditLogListOptions struct {
+	UserID      *uuid.UUID
+	AppID       *uuid.UUID
+	Actions     []string
+	Status      string
+	IPAddress   string
+	CountryCode string
+	From        *time.Time
+	To          *time.Time
+	Search      string
+}
+
+// AuditLogListOption configures AuditLogListOptions.
+type AuditLogListOption func(*AuditLogListOptions)
+
+// AuditLogListUser filters by user ID.
+func AuditLogListUser(id uuid.UUID) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.UserID = &id }
+}
+
+// AuditLogListApp filters by application ID.
+func AuditLogListApp(id uuid.UUID) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.AppID = &id }
+}
+
+// AuditLogListActions filters by any of the given actions.
+func AuditLogListActions(actions ...string) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.Actions = append(o.Actions, actions...) }
+}
+
+// AuditLogListStatus filters by status (success, failed, blocked).
+func AuditLogListStatus(status string) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.Status = status }
+}
+
+// AuditLogListIP filters by client IP address.
+func AuditLogListIP(ip string) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.IPAddress = ip }
+}
+
+// AuditLogListCountry filters by ISO country code.
+func AuditLogListCountry(code string) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.CountryCode = code }
+}
+
+// AuditLogListFrom keeps logs created at or after t.
+func AuditLogListFrom(t time.Time) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.From = &t }
+}
+
+// AuditLogListTo keeps logs created before t.
+func AuditLogListTo(t time.Time) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.To = &t }
+}
+
+// AuditLogListSearch keeps logs whose action, resource, IP address, user agent, city or
+// details contain text, ignoring case.
+func AuditLogListSearch(text string) AuditLogListOption {
+	return func(o *AuditLogListOptions) { o.Search = text }
+}
+
+func BuildAuditLogListOptions(opts []AuditLogListOption) AuditLogListOptions {
+	var o AuditLogListOptions
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// AccountLockoutRepository handles account lockout database operations
+type AccountLockoutRepository struct {
+	db *Database
+}
+
+// NewAccountLockoutRepository creates a new account lockout repository
+func NewAccountLockoutRepository(db *Database) *AccountLockoutRepository {
+	return &AccountLockoutRepository{db: db}
+}
+
+// GetLockout retrieves the lockout state of a user
+func (r *AccountLockoutRepository) GetLockout(ctx context.Context, userID uuid.UUID) (*models.AccountLockout, error) {
+	lockout := new(models.AccountLockout)
+
+	err := r.db.NewSelect().
+		Model(lockout).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+
+	return lockout, nil
+}
+
+// RecordFailedAttempt counts a failed sign-in of a user and returns the updated state.
+// Attempts made before windowStart are forgotten, so the count restarts at 1.
+func (r *AccountLockoutRepository) RecordFailedAttempt(ctx context.Context, userID uuid.UUID, windowStart time.Time) (*models.AccountLockout, error) {
+	now := time.Now()
+	lockout := &models.AccountLockout{
+		UserID:            userID,
+		FailedAttempts:    1,
+		LastFailedAttempt: &now,
+	}
+
+	_, err := r.db.NewInsert().
+		Model(lockout).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set(`failed_attempts = CASE
+			WHEN account_lockouts.last_failed_attempt IS NULL OR account_lockouts.last_failed_attempt < ? THEN 1
+			ELSE account_lockouts.failed_attempts + 1
+		END`, windowStart).
+		Set("last_failed_attempt = EXCLUDED.last_failed_attempt").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to record failed attempt: %w", err)
+	}
+
+	return lockout, nil
+}
+
+// Lock locks a user until the given time, recording it as the lockoutCount-th consecutive
+// lockout, and clears the failed attempts that led to it
+func (r *AccountLockoutRepository) Lock(ctx context.Context, userID uuid.UUID, lockoutCount int, until time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.AccountLockout)(nil)).
+		Set("locked_until = ?", until).
+		Set("lockout_count = ?", lockoutCount).
+		Set("failed_attempts = 0").
+		Set("updated_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	return nil
+}
+
+// Reset removes the lockout state of a user, ending any lock and the backoff
+func (r *AccountLockoutRepository) Reset(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.NewDelete().
+		Model((*models.AccountLockout)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to reset account lockout: %w", err)
+	}
+
+	return nil
+}
+
+// ListLocked returns the accounts locked at the given time, latest unlock first
+func (r *AccountLockoutRepository) ListLocked(ctx context.Context, at time.Time, page, pageSize int) ([]*models.LockedAccount, int, error) {
+	accounts := make([]*models.LockedAccount, 0)
+
+	count, err := r.db.NewSelect().
+		TableExpr("account_lockouts AS al").
+		Join("JOIN users AS u ON u.id = al.user_id").
+		ColumnExpr("al.user_id, u.email, u.username, al.failed_attempts, al.lockout_count, al.locked_until, al.last_failed_attempt").
+		Where("al.locked_until > ?", at).
+		OrderExpr("al.locked_until DESC").
+		Limit(pageSize).
+		Offset((page-1)*pageSize).
+		ScanAndCount(ctx, &accounts)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list locked accounts: %w", err)
+	}
+
+	return accounts, count, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// AccountRecoveryRepository handles account recovery database operations
+type AccountRecoveryRepository struct {
+	db *Database
+}
+
+// NewAccountRecoveryRepository creates a new account recovery repository
+func NewAccountRecoveryRepository(db *Database) *AccountRecoveryRepository {
+	return &AccountRecoveryRepository{db: db}
+}
+
+// Create creates a new recovery request
+func (r *AccountRecoveryRepository) Create(ctx context.Context, req *models.AccountRecoveryRequest) error {
+	_, err := r.db.NewInsert().
+		Model(req).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create recovery request: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a recovery request by ID together with the account being recovered
+func (r *AccountRecoveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AccountRecoveryRequest, error) {
+	req := new(models.AccountRecoveryRequest)
+
+	err := r.db.NewSelect().
+		Model(req).
+		Relation("User").
+		Where("arr.id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recovery request: %w", err)
+	}
+
+	return req, nil
+}
+
+// Update updates the progress and review fields of a recovery request
+func (r *AccountRecoveryRepository) Update(ctx context.Context, req *models.AccountRecoveryRequest) error {
+	result, err := r.db.NewUpdate().
+		Model(req).
+		Column("status", "completed_steps", "failed_attempts", "reviewed_by", "reviewed_at", "review_note", "completed_at").
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update recovery request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// List returns recovery requests for existing accounts, newest first, optionally filtered by status
+func (r *AccountRecoveryRepository) List(ctx context.Context, status string, page, pageSize int) ([]*models.AccountRecoveryRequest, int, error) {
+	requests := make([]*models.AccountRecoveryRequest, 0)
+
+	query := r.db.NewSelect().
+		Model(&requests).
+		Relation("User").
+		Where("arr.user_id IS NOT NULL")
+
+	if status != "" {
+		query = query.Where("arr.status = ?", status)
+	}
+
+	count, err := query.
+		Order("arr.created_at DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list recovery requests: %w", err)
+	}
+
+	return requests, count, nil
+}
+
+// ResetSecondFactor disables TOTP and deletes the backup codes of a recovered account
+func (r *AccountRecoveryRepository) ResetSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*models.User)(nil)).
+			Set("totp_enabled = ?", false).
+			Set("totp_secret = ?", nil).
+			Set("totp_enabled_at = ?", nil).
+			Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Where("id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to disable TOTP: %w", err)
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.BackupCode)(nil)).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete backup codes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
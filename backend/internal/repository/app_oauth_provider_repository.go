@@ -106,7 +106,9 @@ func (r *AppOAuthProviderRepository) Update(ctx context.Context, provider *model
 	result, err := r.db.NewUpdate().
 		Model(provider).
 		Column("provider", "client_id", "client_secret", "callback_url",
-			"scopes", "auth_url", "token_url", "user_info_url", "is_active", "updated_at").
+			"scopes", "auth_url", "token_url", "user_info_url", "team_id", "key_id", "tenant_id",
+			"issuer", "claim_mapping",
+			"is_active", "updated_at").
 		WherePK().
 		Returning("*").
 		Exec(ctx)
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// AuditExportRepository handles audit export cursor database operations
+type AuditExportRepository struct {
+	db *Database
+}
+
+// NewAuditExportRepository creates a new audit export repository
+func NewAuditExportRepository(db *Database) *AuditExportRepository {
+	return &AuditExportRepository{db: db}
+}
+
+// GetCursor returns how far the logs have been scheduled for export to sink, starting the
+// sink's cursor at start the first time
+func (r *AuditExportRepository) GetCursor(ctx context.Context, sink string, start time.Time) (time.Time, error) {
+	_, err := r.db.NewInsert().
+		Model(&models.AuditExportCursor{Sink: sink, ScheduledUntil: start, UpdatedAt: time.Now()}).
+		On("CONFLICT (sink) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create audit export cursor: %w", err)
+	}
+
+	cursor := new(models.AuditExportCursor)
+	err = r.db.NewSelect().
+		Model(cursor).
+		Where("sink = ?", sink).
+		Scan(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get audit export cursor: %w", err)
+	}
+
+	return cursor.ScheduledUntil, nil
+}
+
+// FindCursor returns the cursor of sink, or nil before its first export
+func (r *AuditExportRepository) FindCursor(ctx context.Context, sink string) (*models.AuditExportCursor, error) {
+	cursor := new(models.AuditExportCursor)
+	err := r.db.NewSelect().
+		Model(cursor).
+		Where("sink = ?", sink).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit export cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// AdvanceCursor moves the cursor of sink from from to to, unless another instance moved it
+// first; it reports whether it did
+func (r *AuditExportRepository) AdvanceCursor(ctx context.Context, sink string, from, to time.Time) (bool, error) {
+	res, err := r.db.NewUpdate().
+		Model((*models.AuditExportCursor)(nil)).
+		Set("scheduled_until = ?", to).
+		Set("updated_at = ?", time.Now()).
+		Where("sink = ?", sink).
+		Where("scheduled_until = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to advance audit export cursor: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to advance audit export cursor: %w", err)
+	}
+	return rows == 1, nil
+}
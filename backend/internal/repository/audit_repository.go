@@ -3,13 +3,22 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/queryopt"
 	"github.com/uptrace/bun"
 )
 
+// auditSearchText is the text free-text search matches; it is indexed by idx_audit_logs_search
+// (migration 059), so the two must stay the same
+const auditSearchText = `LOWER(COALESCE(?TableAlias.action, '') || ' ' || COALESCE(?TableAlias.resource_type, '') || ' ' ||
+	COALESCE(?TableAlias.resource_id, '') || ' ' || COALESCE(?TableAlias.ip_address, '') || ' ' ||
+	COALESCE(?TableAlias.user_agent, '') || ' ' || COALESCE(?TableAlias.city, '') || ' ' ||
+	COALESCE(?TableAlias.details::text, ''))`
+
 // AuditRepository handles audit log database operations
 type AuditRepository struct {
 	db *Database
@@ -22,8 +31,10 @@ func NewAuditRepository(db *Database) *AuditRepository {
 
 // Create creates a new audit log entry
 func (r *AuditRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	// A log written again under its ID, as when the outbox redispatches it, is skipped
 	_, err := r.db.NewInsert().
 		Model(log).
+		On("CONFLICT (id) DO NOTHING").
 		Returning("*").
 		Exec(ctx)
 
@@ -112,6 +123,26 @@ func (r *AuditRepository) List(ctx context.Context, limit, offset int) ([]*model
 	return logs, nil
 }
 
+// ListBetween retrieves audit logs created in [from, to), oldest first, with pagination
+func (r *AuditRepository) ListBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]*models.AuditLog, error) {
+	logs := make([]*models.AuditLog, 0)
+
+	err := r.db.NewSelect().
+		Model(&logs).
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to).
+		Order("created_at ASC", "id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return logs, nil
+}
+
 // Count returns the total number of audit logs
 func (r *AuditRepository) Count(ctx context.Context) (int, error) {
 	count, err := r.db.NewSelect().
@@ -180,3 +211,92 @@ func (r *AuditRepository) ListByApp(ctx context.Context, appID uuid.UUID, limit,
 
 	return logs, total, nil
 }
+
+// Search retrieves audit logs matching the filters, newest first, with pagination
+func (r *AuditRepository) Search(ctx context.Context, limit, offset int, opts ...queryopt.AuditLogListOption) ([]*models.AuditLog, int, error) {
+	logs := make([]*models.AuditLog, 0)
+
+	total, err := applyAuditLogFilters(r.db.NewSelect().Model(&logs), queryopt.BuildAuditLogListOptions(opts)).
+		Relation("User").
+		Order("audit_log.created_at DESC", "audit_log.id DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// CountByAction counts the audit logs matching the filters per action, most frequent first
+func (r *AuditRepository) CountByAction(ctx context.Context, limit int, opts ...queryopt.AuditLogListOption) ([]*models.AuditActionCount, error) {
+	counts := make([]*models.AuditActionCount, 0)
+
+	err := applyAuditLogFilters(r.db.NewSelect().Model((*models.AuditLog)(nil)), queryopt.BuildAuditLogListOptions(opts)).
+		ColumnExpr("?TableAlias.action AS action").
+		ColumnExpr("COUNT(*) AS total").
+		ColumnExpr("COUNT(*) FILTER (WHERE ?TableAlias.status <> ?) AS failed", models.StatusSuccess).
+		GroupExpr("?TableAlias.action").
+		OrderExpr("total DESC, action ASC").
+		Limit(limit).
+		Scan(ctx, &counts)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit logs by action: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountFailuresByHour counts the unsuccessful audit logs matching the filters per hour
+// (UTC), oldest first. Hours without failures are left out.
+func (r *AuditRepository) CountFailuresByHour(ctx context.Context, opts ...queryopt.AuditLogListOption) ([]*models.AuditHourlyCount, error) {
+	counts := make([]*models.AuditHourlyCount, 0)
+
+	err := applyAuditLogFilters(r.db.NewSelect().Model((*models.AuditLog)(nil)), queryopt.BuildAuditLogListOptions(opts)).
+		ColumnExpr("DATE_TRUNC('hour', ?TableAlias.created_at) AS hour").
+		ColumnExpr("COUNT(*) AS count").
+		Where("?TableAlias.status <> ?", models.StatusSuccess).
+		GroupExpr("hour").
+		OrderExpr("hour ASC").
+		Scan(ctx, &counts)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit log failures by hour: %w", err)
+	}
+
+	return counts, nil
+}
+
+func applyAuditLogFilters(query *bun.SelectQuery, o queryopt.AuditLogListOptions) *bun.SelectQuery {
+	if o.UserID != nil {
+		query = query.Where("?TableAlias.user_id = ?", *o.UserID)
+	}
+	if o.AppID != nil {
+		query = query.Where("?TableAlias.application_id = ?", *o.AppID)
+	}
+	if len(o.Actions) > 0 {
+		query = query.Where("?TableAlias.action IN (?)", bun.In(o.Actions))
+	}
+	if o.Status != "" {
+		query = query.Where("?TableAlias.status = ?", o.Status)
+	}
+	if o.IPAddress != "" {
+		query = query.Where("?TableAlias.ip_address = ?", o.IPAddress)
+	}
+	if o.CountryCode != "" {
+		query = query.Where("?TableAlias.country_code = ?", strings.ToUpper(o.CountryCode))
+	}
+	if o.From != nil {
+		query = query.Where("?TableAlias.created_at >= ?", *o.From)
+	}
+	if o.To != nil {
+		query = query.Where("?TableAlias.created_at < ?", *o.To)
+	}
+	if o.Search != "" {
+		query = query.Where(auditSearchText+" LIKE ?", "%"+strings.ToLower(o.Search)+"%")
+	}
+	return query
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// BackchannelLogoutRepository handles back-channel logout delivery database operations
+type BackchannelLogoutRepository struct {
+	db *Database
+}
+
+// NewBackchannelLogoutRepository creates a new back-channel logout repository
+func NewBackchannelLogoutRepository(db *Database) *BackchannelLogoutRepository {
+	return &BackchannelLogoutRepository{db: db}
+}
+
+// EnqueueForUser queues a delivery for every active client with a back-channel logout URI
+// that holds an unexpired, unrevoked token of the user. It returns how many were queued.
+func (r *BackchannelLogoutRepository) EnqueueForUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO backchannel_logout_deliveries (client_id, user_id)
+		SELECT c.id, ?
+		FROM oauth_clients AS c
+		WHERE c.is_active AND c.backchannel_logout_uri <> ''
+		AND (
+			EXISTS (
+				SELECT 1 FROM oauth_access_tokens AS t
+				WHERE t.client_id = c.id AND t.user_id = ? AND t.is_active
+				AND t.revoked_at IS NULL AND t.expires_at > ?
+			)
+			OR EXISTS (
+				SELECT 1 FROM oauth_refresh_tokens AS t
+				WHERE t.client_id = c.id AND t.user_id = ? AND t.is_active
+				AND t.revoked_at IS NULL AND t.expires_at > ?
+			)
+		)`, userID, userID, now, userID, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue back-channel logouts: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// ClaimDue returns up to limit pending deliveries that are due, with their clients. Claimed
+// deliveries are pushed back by lease so other instances skip them; one whose instance dies
+// mid-delivery becomes due again when the lease runs out.
+func (r *BackchannelLogoutRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackchannelLogoutDelivery, error) {
+	now := time.Now()
+	var ids []uuid.UUID
+	err := r.db.NewRaw(`
+		UPDATE backchannel_logout_deliveries
+		SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM backchannel_logout_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, now.Add(lease), models.BackchannelLogoutPending, now, limit).
+		Scan(ctx, &ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim back-channel logouts: %w", err)
+	}
+
+	deliveries := make([]*models.BackchannelLogoutDelivery, 0, len(ids))
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
+
+	err = r.db.NewSelect().
+		Model(&deliveries).
+		Relation("Client").
+		Where("bld.id IN (?)", bun.In(ids)).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load back-channel logouts: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Update records the outcome of a delivery attempt
+func (r *BackchannelLogoutRepository) Update(ctx context.Context, delivery *models.BackchannelLogoutDelivery) error {
+	delivery.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(delivery).
+		Column("status", "attempts", "next_attempt_at", "last_error", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update back-channel logout: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFinishedBefore deletes delivered and failed deliveries last updated before the given time
+func (r *BackchannelLogoutRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.NewDelete().
+		Model((*models.BackchannelLogoutDelivery)(nil)).
+		Where("status <> ?", models.BackchannelLogoutPending).
+		Where("updated_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete back-channel logouts: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
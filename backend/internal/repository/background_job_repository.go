@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// BackgroundJobRepository handles background job queue database operations
+type BackgroundJobRepository struct {
+	db *Database
+}
+
+// NewBackgroundJobRepository creates a new background job repository
+func NewBackgroundJobRepository(db *Database) *BackgroundJobRepository {
+	return &BackgroundJobRepository{db: db}
+}
+
+// Create queues a job
+func (r *BackgroundJobRepository) Create(ctx context.Context, job *models.BackgroundJob) error {
+	_, err := r.db.NewInsert().
+		Model(job).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create background job: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue marks up to limit due jobs as running and returns them. Claimed jobs are pushed
+// back by lease so other instances skip them; one whose instance dies mid-run becomes due
+// again when the lease runs out. The attempt is counted when the job is claimed.
+func (r *BackgroundJobRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackgroundJob, error) {
+	now := time.Now()
+	var ids []uuid.UUID
+	err := r.db.NewRaw(`
+		UPDATE background_jobs
+		SET status = ?, attempts = attempts + 1, run_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM background_jobs
+			WHERE status IN (?) AND run_at <= ?
+			ORDER BY run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, models.BackgroundJobRunning, now.Add(lease), now,
+		bun.In([]string{models.BackgroundJobPending, models.BackgroundJobRunning}), now, limit).
+		Scan(ctx, &ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim background jobs: %w", err)
+	}
+
+	jobs := make([]*models.BackgroundJob, 0, len(ids))
+	if len(ids) == 0 {
+		return jobs, nil
+	}
+
+	err = r.db.NewSelect().
+		Model(&jobs).
+		Where("id IN (?)", bun.In(ids)).
+		Order("run_at").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load background jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Update records the outcome of a run, or a job requeued by an admin
+func (r *BackgroundJobRepository) Update(ctx context.Context, job *models.BackgroundJob) error {
+	job.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(job).
+		Column("status", "attempts", "run_at", "last_error", "updated_at", "completed_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update background job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job
+func (r *BackgroundJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error) {
+	job := new(models.BackgroundJob)
+
+	err := r.db.NewSelect().
+		Model(job).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get background job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List retrieves jobs, newest first, optionally filtered by status and type
+func (r *BackgroundJobRepository) List(ctx context.Context, status, jobType string, page, perPage int) ([]*models.BackgroundJob, int, error) {
+	jobs := make([]*models.BackgroundJob, 0)
+
+	query := r.db.NewSelect().
+		Model(&jobs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	total, err := query.
+		Order("created_at DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list background jobs: %w", err)
+	}
+
+	return jobs, total, nil
+}
+
+// CountByStatus counts jobs per status
+func (r *BackgroundJobRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Status string `bun:"status"`
+		Count  int    `bun:"count"`
+	}
+
+	err := r.db.NewSelect().
+		Model((*models.BackgroundJob)(nil)).
+		Column("status").
+		ColumnExpr("COUNT(*) AS count").
+		Group("status").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count background jobs: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteSucceededBefore deletes succeeded jobs completed before the given time. Dead jobs
+// are kept until an admin retries them.
+func (r *BackgroundJobRepository) DeleteSucceededBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.NewDelete().
+		Model((*models.BackgroundJob)(nil)).
+		Where("status = ?", models.BackgroundJobSucceeded).
+		Where("completed_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete background jobs: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// BulkRoleJobRepository handles bulk role job persistence
+type BulkRoleJobRepository struct {
+	db *Database
+}
+
+// NewBulkRoleJobRepository creates a new bulk role job repository
+func NewBulkRoleJobRepository(db *Database) *BulkRoleJobRepository {
+	return &BulkRoleJobRepository{db: db}
+}
+
+// Create creates a bulk role job
+func (r *BulkRoleJobRepository) Create(ctx context.Context, job *models.BulkRoleJob) error {
+	_, err := r.db.NewInsert().
+		Model(job).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create bulk role job: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves the status and progress of a bulk role job
+func (r *BulkRoleJobRepository) Update(ctx context.Context, job *models.BulkRoleJob) error {
+	result, err := r.db.NewUpdate().
+		Model(job).
+		Column("status", "total", "processed", "succeeded", "failed", "failures", "error", "updated_at", "completed_at").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update bulk role job: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a bulk role job by ID
+func (r *BulkRoleJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BulkRoleJob, error) {
+	job := new(models.BulkRoleJob)
+
+	err := r.db.NewSelect().
+		Model(job).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk role job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List lists the most recent bulk role jobs, newest first
+func (r *BulkRoleJobRepository) List(ctx context.Context, limit int) ([]*models.BulkRoleJob, error) {
+	jobs := make([]*models.BulkRoleJob, 0)
+
+	err := r.db.NewSelect().
+		Model(&jobs).
+		ExcludeColumn("user_ids", "failures").
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk role jobs: %w", err)
+	}
+
+	return jobs, nil
+}
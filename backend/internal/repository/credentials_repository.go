@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// CredentialsRepository handles credential targets and the record of issued credentials
+type CredentialsRepository struct {
+	db *Database
+}
+
+// NewCredentialsRepository creates a new credentials repository
+func NewCredentialsRepository(db *Database) *CredentialsRepository {
+	return &CredentialsRepository{db: db}
+}
+
+// CreateTarget creates a credential target
+func (r *CredentialsRepository) CreateTarget(ctx context.Context, target *models.CredentialTarget) error {
+	_, err := r.db.NewInsert().
+		Model(target).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// GetTargetByID retrieves a credential target by ID
+func (r *CredentialsRepository) GetTargetByID(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error) {
+	target := new(models.CredentialTarget)
+
+	err := r.db.NewSelect().
+		Model(target).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+
+	return target, nil
+}
+
+// GetTargetByName retrieves a credential target by name
+func (r *CredentialsRepository) GetTargetByName(ctx context.Context, name string) (*models.CredentialTarget, error) {
+	target := new(models.CredentialTarget)
+
+	err := r.db.NewSelect().
+		Model(target).
+		Where("name = ?", name).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+
+	return target, nil
+}
+
+// ListTargets lists all credential targets by name
+func (r *CredentialsRepository) ListTargets(ctx context.Context) ([]*models.CredentialTarget, error) {
+	targets := make([]*models.CredentialTarget, 0)
+
+	err := r.db.NewSelect().
+		Model(&targets).
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credential targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// UpdateTarget saves the editable fields of a credential target
+func (r *CredentialsRepository) UpdateTarget(ctx context.Context, target *models.CredentialTarget) error {
+	target.UpdatedAt = time.Now()
+
+	result, err := r.db.NewUpdate().
+		Model(target).
+		Column("description", "permission", "postgres_role", "ssh_principals", "max_ttl_seconds", "is_active", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update credential target: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteTarget deletes a credential target. Its issuances are kept.
+func (r *CredentialsRepository) DeleteTarget(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.CredentialTarget)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete credential target: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateIssuance records issued credentials
+func (r *CredentialsRepository) CreateIssuance(ctx context.Context, issuance *models.CredentialIssuance) error {
+	_, err := r.db.NewInsert().
+		Model(issuance).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record credential issuance: %w", err)
+	}
+
+	return nil
+}
+
+// GetIssuanceByID retrieves an issuance by ID
+func (r *CredentialsRepository) GetIssuanceByID(ctx context.Context, id uuid.UUID) (*models.CredentialIssuance, error) {
+	issuance := new(models.CredentialIssuance)
+
+	err := r.db.NewSelect().
+		Model(issuance).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential issuance: %w", err)
+	}
+
+	return issuance, nil
+}
+
+// ListIssuances lists issuances newest first, optionally only those of a user
+func (r *CredentialsRepository) ListIssuances(ctx context.Context, userID *uuid.UUID, page, pageSize int) ([]*models.CredentialIssuance, int, error) {
+	issuances := make([]*models.CredentialIssuance, 0)
+
+	query := r.db.NewSelect().
+		Model(&issuances).
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize)
+
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	total, err := query.ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list credential issuances: %w", err)
+	}
+
+	return issuances, total, nil
+}
+
+// ListExpiredRoles lists Postgres issuances that expired before the given time and whose
+// roles were not dropped yet, oldest first
+func (r *CredentialsRepository) ListExpiredRoles(ctx context.Context, before time.Time, limit int) ([]*models.CredentialIssuance, error) {
+	issuances := make([]*models.CredentialIssuance, 0)
+
+	err := r.db.NewSelect().
+		Model(&issuances).
+		Where("kind = ?", models.CredentialKindPostgres).
+		Where("revoked_at IS NULL").
+		Where("expires_at < ?", before).
+		Order("expires_at").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired credential roles: %w", err)
+	}
+
+	return issuances, nil
+}
+
+// MarkRevoked records when an issuance's Postgres role was dropped
+func (r *CredentialsRepository) MarkRevoked(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.CredentialIssuance)(nil)).
+		Set("revoked_at = ?", at).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark credential issuance revoked: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
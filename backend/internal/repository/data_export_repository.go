@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// DataExportRepository handles user data exports and reads the data they contain
+type DataExportRepository struct {
+	db *Database
+}
+
+// NewDataExportRepository creates a new data export repository
+func NewDataExportRepository(db *Database) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// Create creates a data export
+func (r *DataExportRepository) Create(ctx context.Context, export *models.UserDataExport) error {
+	_, err := r.db.NewInsert().
+		Model(export).
+		Returning("id, created_at").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a data export without its file
+func (r *DataExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserDataExport, error) {
+	export := new(models.UserDataExport)
+
+	err := r.db.NewSelect().
+		Model(export).
+		ExcludeColumn("content").
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetLatest retrieves the newest unexpired export of a user in a format, without its file
+func (r *DataExportRepository) GetLatest(ctx context.Context, userID uuid.UUID, format models.DataExportFormat, now time.Time) (*models.UserDataExport, error) {
+	export := new(models.UserDataExport)
+
+	err := r.db.NewSelect().
+		Model(export).
+		ExcludeColumn("content").
+		Where("user_id = ?", userID).
+		Where("format = ?", format).
+		Where("expires_at > ?", now).
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest data export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetContent retrieves the file of a ready data export
+func (r *DataExportRepository) GetContent(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var content []byte
+
+	err := r.db.NewSelect().
+		Model((*models.UserDataExport)(nil)).
+		Column("content").
+		Where("id = ?", id).
+		Where("status = ?", models.DataExportStatusReady).
+		Scan(ctx, &content)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export content: %w", err)
+	}
+
+	return content, nil
+}
+
+// Complete stores the file of a pending data export and marks it ready
+func (r *DataExportRepository) Complete(ctx context.Context, id uuid.UUID, content []byte, completedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.UserDataExport)(nil)).
+		Set("status = ?", models.DataExportStatusReady).
+		Set("content = ?", content).
+		Set("size = ?", len(content)).
+		Set("completed_at = ?", completedAt).
+		Where("id = ?", id).
+		Where("status = ?", models.DataExportStatusPending).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to complete data export: %w", err)
+	}
+
+	return nil
+}
+
+// Fail marks a pending data export failed
+func (r *DataExportRepository) Fail(ctx context.Context, id uuid.UUID, message string, completedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.UserDataExport)(nil)).
+		Set("status = ?", models.DataExportStatusFailed).
+		Set("error = ?", message).
+		Set("completed_at = ?", completedAt).
+		Where("id = ?", id).
+		Where("status = ?", models.DataExportStatusPending).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to fail data export: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes data exports that expired before now and returns how many were deleted
+func (r *DataExportRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.NewDelete().
+		Model((*models.UserDataExport)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired data exports: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// ListUserEmails returns the email addresses of a user
+func (r *DataExportRepository) ListUserEmails(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error) {
+	emails := make([]*models.UserEmail, 0)
+
+	err := r.db.NewSelect().
+		Model(&emails).
+		Where("ue.user_id = ?", userID).
+		Order("ue.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// ListUserProfiles returns the application profiles of a user
+func (r *DataExportRepository) ListUserProfiles(ctx context.Context, userID uuid.UUID) ([]*models.UserApplicationProfile, error) {
+	profiles := make([]*models.UserApplicationProfile, 0)
+
+	err := r.db.NewSelect().
+		Model(&profiles).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user application profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// ListUserSessions returns every session of a user stored in the database, including ended ones
+func (r *DataExportRepository) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	sessions := make([]models.Session, 0)
+
+	err := r.db.NewSelect().
+		Model(&sessions).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// ListUserConsents returns the OAuth consents of a user with their clients, including revoked ones
+func (r *DataExportRepository) ListUserConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error) {
+	consents := make([]*models.UserConsent, 0)
+
+	err := r.db.NewSelect().
+		Model(&consents).
+		Relation("Client").
+		Where("?TableAlias.user_id = ?", userID).
+		OrderExpr("?TableAlias.granted_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user consents: %w", err)
+	}
+
+	return consents, nil
+}
+
+// ListUserAuditLogs returns a page of the audit log entries about a user, oldest first
+func (r *DataExportRepository) ListUserAuditLogs(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.AuditLog, error) {
+	logs := make([]*models.AuditLog, 0)
+
+	err := r.db.NewSelect().
+		Model(&logs).
+		Where("user_id = ?", userID).
+		Order("created_at ASC", "id ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user audit logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// ListOAuthAccounts returns the OAuth provider accounts linked to a user
+func (r *DataExportRepository) ListOAuthAccounts(ctx context.Context, userID uuid.UUID) ([]*models.OAuthAccount, error) {
+	accounts := make([]*models.OAuthAccount, 0)
+
+	err := r.db.NewSelect().
+		Model(&accounts).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// ListTelegramAccounts returns the Telegram accounts linked to a user
+func (r *DataExportRepository) ListTelegramAccounts(ctx context.Context, userID uuid.UUID) ([]*models.UserTelegramAccount, error) {
+	accounts := make([]*models.UserTelegramAccount, 0)
+
+	err := r.db.NewSelect().
+		Model(&accounts).
+		Where("uta.user_id = ?", userID).
+		Order("uta.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list telegram accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// ListWebAuthnCredentials returns the security keys and passkeys of a user
+func (r *DataExportRepository) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	credentials := make([]*models.WebAuthnCredential, 0)
+
+	err := r.db.NewSelect().
+		Model(&credentials).
+		Where("wc.user_id = ?", userID).
+		Order("wc.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %w", err)
+	}
+
+	return credentials, nil
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -20,11 +22,33 @@ import (
 // Database represents the database connection
 type Database struct {
 	*bun.DB
-	sqlDB *sql.DB // Keep reference to sql.DB for stats
+	sqlDB    *sql.DB          // Keep reference to sql.DB for stats
+	prepared *PreparedQueries // Hot lookups on pgx, nil when disabled
+
+	onUserChanged func(ctx context.Context, userID uuid.UUID)
+}
+
+// DatabaseOption configures NewDatabase
+type DatabaseOption func(*databaseOptions)
+
+type databaseOptions struct {
+	queryTracer pgx.QueryTracer
+}
+
+// WithQueryTracer traces the queries that bypass bun (see PreparedQueries)
+func WithQueryTracer(tracer pgx.QueryTracer) DatabaseOption {
+	return func(o *databaseOptions) {
+		o.queryTracer = tracer
+	}
 }
 
 // NewDatabase creates a new database connection using bun ORM
-func NewDatabase(cfg *config.DatabaseConfig) (*Database, error) {
+func NewDatabase(cfg *config.DatabaseConfig, opts ...DatabaseOption) (*Database, error) {
+	var o databaseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Create pgdriver connector
 	pgconn := pgdriver.NewConnector(
 		pgdriver.WithNetwork("tcp"),
@@ -69,6 +93,24 @@ func NewDatabase(cfg *config.DatabaseConfig) (*Database, error) {
 		))
 	}
 
+	registerModels(bunDB)
+
+	db := &Database{DB: bunDB, sqlDB: sqldb}
+	if cfg.PreparedQueries {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		prepared, err := NewPreparedQueries(ctx, cfg, o.queryTracer)
+		if err != nil {
+			_ = bunDB.Close()
+			return nil, fmt.Errorf("failed to set up prepared queries: %w", err)
+		}
+		db.prepared = prepared
+	}
+	return db, nil
+}
+
+// registerModels registers the models bun needs to know up front
+func registerModels(bunDB *bun.DB) {
 	// Register models in order: join tables FIRST, then base models
 	// This is required because bun processes m2m relations when registering models
 	// and needs the join table models to be already registered
@@ -76,19 +118,39 @@ func NewDatabase(cfg *config.DatabaseConfig) (*Database, error) {
 	bunDB.RegisterModel((*models.UserRole)(nil))
 	bunDB.RegisterModel((*models.UserGroup)(nil))
 	bunDB.RegisterModel((*models.RolePermission)(nil))
+	bunDB.RegisterModel((*models.BundlePermission)(nil))
 	// 2. Register base models (they can now safely use m2m relations)
 	bunDB.RegisterModel((*models.Permission)(nil))
 	bunDB.RegisterModel((*models.Role)(nil))
+	bunDB.RegisterModel((*models.PermissionBundle)(nil))
 	bunDB.RegisterModel((*models.User)(nil))
 	bunDB.RegisterModel((*models.Group)(nil))
-	return &Database{DB: bunDB, sqlDB: sqldb}, nil
 }
 
 // Close closes the database connection
 func (d *Database) Close() error {
+	if d.prepared != nil {
+		d.prepared.Close()
+	}
 	return d.DB.Close()
 }
 
+// OnUserChanged registers fn to be called after a repository changes a user's profile or
+// role assignments, e.g. to drop cached copies of the user. Register it before serving requests.
+func (d *Database) OnUserChanged(fn func(ctx context.Context, userID uuid.UUID)) {
+	d.onUserChanged = fn
+}
+
+// userChanged reports changed users to the OnUserChanged callback
+func (d *Database) userChanged(ctx context.Context, userIDs ...uuid.UUID) {
+	if d.onUserChanged == nil {
+		return
+	}
+	for _, id := range userIDs {
+		d.onUserChanged(ctx, id)
+	}
+}
+
 // Health checks the database health
 func (d *Database) Health() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
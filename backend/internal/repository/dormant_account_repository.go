@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// dormantLastActiveExpr is the last time a user signed in or used a session
+const dormantLastActiveExpr = "GREATEST(COALESCE(u.last_login_at, u.created_at), (SELECT MAX(s.last_active_at) FROM sessions s WHERE s.user_id = u.id))"
+
+// DormantAccountRepository handles dormant account database operations
+type DormantAccountRepository struct {
+	db *Database
+}
+
+// NewDormantAccountRepository creates a new dormant account repository
+func NewDormantAccountRepository(db *Database) *DormantAccountRepository {
+	return &DormantAccountRepository{db: db}
+}
+
+// ListCandidates returns active human accounts without activity since cutoff, ordered by ID
+// and starting after afterID. Guests, service accounts, administrators and accounts holding
+// one of excludedRoles or listed in excludedUserIDs are never returned.
+func (r *DormantAccountRepository) ListCandidates(ctx context.Context, cutoff time.Time, excludedRoles []string, excludedUserIDs []uuid.UUID, afterID uuid.UUID, limit int) ([]*models.DormantAccountCandidate, error) {
+	candidates := make([]*models.DormantAccountCandidate, 0)
+	roles := append([]string{string(models.RoleAdmin)}, excludedRoles...)
+
+	query := r.db.NewSelect().
+		TableExpr("users AS u").
+		ColumnExpr("u.id, u.email, u.username, u.dormant_notified_at").
+		ColumnExpr(dormantLastActiveExpr+" AS last_active_at").
+		Where("u.state = ?", models.UserStateActive).
+		Where("u.is_guest = FALSE").
+		Where("u.account_type IS DISTINCT FROM ?", string(models.AccountTypeService)).
+		Where(dormantLastActiveExpr+" < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM user_roles ur JOIN roles ro ON ro.id = ur.role_id WHERE ur.user_id = u.id AND ro.name IN (?))", bun.In(roles)).
+		Where("u.id > ?", afterID)
+	if len(excludedUserIDs) > 0 {
+		query = query.Where("u.id NOT IN (?)", bun.In(excludedUserIDs))
+	}
+
+	err := query.
+		OrderExpr("u.id ASC").
+		Limit(limit).
+		Scan(ctx, &candidates)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dormant accounts: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// MarkNotified records when the dormant account notice was sent
+func (r *DormantAccountRepository) MarkNotified(ctx context.Context, userID uuid.UUID, notifiedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Table("users").
+		Set("dormant_notified_at = ?", notifiedAt).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record dormant account notice: %w", err)
+	}
+
+	return nil
+}
+
+// Anonymize scrubs the personal data of an account: its profile, contact details, credentials
+// and sign-in history. The row itself stays so audit logs keep pointing at it; the lifecycle
+// state is left to the caller.
+func (r *DormantAccountRepository) Anonymize(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model((*models.User)(nil)).
+			Set("email = 'deleted-' || id::text || '@anonymized.invalid'").
+			Set("username = 'deleted-' || id::text").
+			Set("email_verified = FALSE").
+			Set("email_verified_at = NULL").
+			Set("phone = NULL").
+			Set("phone_verified = FALSE").
+			Set("recovery_email = NULL").
+			Set("recovery_email_verified = FALSE").
+			Set("full_name = ''").
+			Set("profile_picture_url = ''").
+			Set("password_hash = ''").
+			Set("totp_secret = NULL").
+			Set("totp_enabled = FALSE").
+			Set("totp_enabled_at = NULL").
+			Set("webauthn_enabled = FALSE").
+			Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Where("id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrUserNotFound
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.UserApplicationProfile)(nil)).
+			Set("display_name = NULL").
+			Set("avatar_url = NULL").
+			Set("nickname = NULL").
+			Set("metadata = '{}'::jsonb").
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to anonymize application profiles: %w", err)
+		}
+
+		for _, model := range []interface{}{
+			(*models.UserEmail)(nil),
+			(*models.OAuthAccount)(nil),
+			(*models.UserTelegramAccount)(nil),
+			(*models.WebAuthnCredential)(nil),
+			(*models.BackupCode)(nil),
+			(*models.PasswordHistory)(nil),
+			(*models.Session)(nil),
+		} {
+			if _, err := tx.NewDelete().Model(model).Where("user_id = ?", userID).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to delete %T: %w", model, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// EmailVerificationRepository handles email verification reminder database operations
+type EmailVerificationRepository struct {
+	db *Database
+}
+
+// NewEmailVerificationRepository creates a new email verification repository
+func NewEmailVerificationRepository(db *Database) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// ListUnverified returns active users with an unverified email address created within
+// [createdAfter, createdBefore] that were sent fewer than maxReminders reminders, oldest first
+func (r *EmailVerificationRepository) ListUnverified(ctx context.Context, createdAfter, createdBefore time.Time, maxReminders, limit int) ([]*models.EmailVerificationReminderEntry, error) {
+	entries := make([]*models.EmailVerificationReminderEntry, 0)
+
+	err := r.db.NewSelect().
+		TableExpr("users AS u").
+		ColumnExpr("u.id, u.email, u.username, u.created_at, u.email_verification_reminders_sent").
+		Where("u.email_verified = FALSE").
+		Where("u.is_active = TRUE").
+		Where("u.email <> ''").
+		Where("u.created_at >= ?", createdAfter).
+		Where("u.created_at <= ?", createdBefore).
+		Where("u.email_verification_reminders_sent < ?", maxReminders).
+		OrderExpr("u.created_at ASC").
+		Limit(limit).
+		Scan(ctx, &entries)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unverified users: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SetRemindersSent records how many verification reminders the user was sent
+func (r *EmailVerificationRepository) SetRemindersSent(ctx context.Context, userID uuid.UUID, count int) error {
+	_, err := r.db.NewUpdate().
+		Table("users").
+		Set("email_verification_reminders_sent = ?", count).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record email verification reminders: %w", err)
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
@@ -31,10 +31,11 @@ func handlePgError(err error) error {
 			constraint := pgErr.Field('n')
 
 			// User-specific constraints
-			if constraint == "users_email_key" || constraint == "idx_users_email_unique" {
+			if constraint == "users_email_key" || constraint == "idx_users_email_unique" ||
+				constraint == "idx_user_emails_verified_email" || constraint == "user_emails_user_id_email_key" {
 				return models.ErrEmailAlreadyExists
 			}
-			if constraint == "users_username_key" {
+			if constraint == "users_username_key" || constraint == "idx_users_username_lower" {
 				return models.ErrUsernameAlreadyExists
 			}
 			if constraint == "idx_users_phone_unique" {
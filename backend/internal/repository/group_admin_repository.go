@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// subtreeGroupsCTE resolves a group and all of its descendants. UNION (not UNION ALL)
+// stops the recursion if the hierarchy contains a cycle.
+const subtreeGroupsCTE = `
+	WITH RECURSIVE subtree (id) AS (
+		SELECT ?::uuid
+		UNION
+		SELECT g.id FROM groups AS g INNER JOIN subtree AS s ON g.parent_group_id = s.id
+	)`
+
+// subtreeMemberIDsSQL selects the users that belong to a group or one of its descendants
+const subtreeMemberIDsSQL = subtreeGroupsCTE + `
+	SELECT DISTINCT ug.user_id FROM user_groups AS ug
+	WHERE ug.group_id IN (SELECT id FROM subtree)`
+
+// GroupAdminRepository handles organization admin delegation on groups
+type GroupAdminRepository struct {
+	db *Database
+}
+
+// NewGroupAdminRepository creates a new group admin repository
+func NewGroupAdminRepository(db *Database) *GroupAdminRepository {
+	return &GroupAdminRepository{db: db}
+}
+
+// ListAdmins returns the organization admins designated directly on a group
+func (r *GroupAdminRepository) ListAdmins(ctx context.Context, groupID uuid.UUID) ([]*models.GroupAdmin, error) {
+	admins := make([]*models.GroupAdmin, 0)
+	err := r.db.NewSelect().
+		Model(&admins).
+		Relation("User").
+		Where("ga.group_id = ?", groupID).
+		Order("ga.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group admins: %w", err)
+	}
+	return admins, nil
+}
+
+// AddAdmin designates a user as organization admin of a group (idempotent)
+func (r *GroupAdminRepository) AddAdmin(ctx context.Context, admin *models.GroupAdmin) error {
+	_, err := r.db.NewInsert().
+		Model(admin).
+		On("CONFLICT (group_id, user_id) DO NOTHING").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// RemoveAdmin revokes a user's organization admin designation on a group
+func (r *GroupAdminRepository) RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.GroupAdmin)(nil)).
+		Where("group_id = ?", groupID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// ListAdministeredGroups returns the groups a user was designated organization admin of
+func (r *GroupAdminRepository) ListAdministeredGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error) {
+	groups := make([]*models.Group, 0)
+	err := r.db.NewSelect().
+		Model(&groups).
+		Join("INNER JOIN group_admins AS ga ON ga.group_id = g.id").
+		Where("ga.user_id = ?", userID).
+		Order("g.name ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list administered groups: %w", err)
+	}
+	return groups, nil
+}
+
+// IsAdmin reports whether a user is organization admin of a group or of one of its ancestors
+func (r *GroupAdminRepository) IsAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
+	var isAdmin bool
+	err := r.db.NewRaw(`
+		WITH RECURSIVE ancestors (id, parent_id) AS (
+			SELECT g.id, g.parent_group_id FROM groups AS g WHERE g.id = ?
+			UNION
+			SELECT g.id, g.parent_group_id FROM groups AS g INNER JOIN ancestors AS a ON g.id = a.parent_id
+		)
+		SELECT EXISTS (
+			SELECT 1 FROM group_admins AS ga
+			WHERE ga.user_id = ? AND ga.group_id IN (SELECT id FROM ancestors)
+		)`, groupID, userID).
+		Scan(ctx, &isAdmin)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check group admin: %w", err)
+	}
+	return isAdmin, nil
+}
+
+// IsMember reports whether a user belongs to a group or one of its descendants
+func (r *GroupAdminRepository) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	var isMember bool
+	err := r.db.NewRaw(`SELECT ? IN (`+subtreeMemberIDsSQL+`)`, userID, groupID).
+		Scan(ctx, &isMember)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check group membership: %w", err)
+	}
+	return isMember, nil
+}
+
+// ListMembers returns the users that belong to a group or one of its descendants, with
+// their roles loaded
+func (r *GroupAdminRepository) ListMembers(ctx context.Context, groupID uuid.UUID, search string, limit, offset int) ([]*models.User, int, error) {
+	users := make([]*models.User, 0)
+	query := r.db.NewSelect().
+		Model(&users).
+		Relation("Roles").
+		Where("?TableAlias.id IN ("+subtreeMemberIDsSQL+")", groupID)
+
+	if search = strings.TrimSpace(search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("LOWER(?TableAlias.username) LIKE ?", pattern).
+				WhereOr("LOWER(?TableAlias.full_name) LIKE ?", pattern).
+				WhereOr("LOWER(?TableAlias.email) LIKE ?", pattern)
+		})
+	}
+
+	total, err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list group members: %w", err)
+	}
+	return users, total, nil
+}
+
+// RemoveMember removes a user from a group and all of its descendants and revokes the
+// roles the user no longer gets from any group
+func (r *GroupAdminRepository) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.UserGroup)(nil)).
+			Where("user_id = ?", userID).
+			Where("group_id IN ("+subtreeGroupsCTE+" SELECT id FROM subtree)", groupID).
+			Exec(ctx)
+		if err != nil {
+			return handlePgError(err)
+		}
+
+		return syncGroupRoles(ctx, tx, []uuid.UUID{userID})
+	})
+}
+
+// ListMemberAPIKeys returns the API keys owned by members of a group or its descendants
+func (r *GroupAdminRepository) ListMemberAPIKeys(ctx context.Context, groupID uuid.UUID) ([]*models.APIKey, error) {
+	keys := make([]*models.APIKey, 0)
+	err := r.db.NewSelect().
+		Model(&keys).
+		Where("user_id IN ("+subtreeMemberIDsSQL+")", groupID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group member API keys: %w", err)
+	}
+	return keys, nil
+}
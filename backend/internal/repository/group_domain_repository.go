@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// GroupDomainRepository handles the email domains claimed by groups
+type GroupDomainRepository struct {
+	db *Database
+}
+
+// NewGroupDomainRepository creates a new group domain repository
+func NewGroupDomainRepository(db *Database) *GroupDomainRepository {
+	return &GroupDomainRepository{db: db}
+}
+
+// ListByGroup returns the domains claimed by a group
+func (r *GroupDomainRepository) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]*models.GroupDomain, error) {
+	domains := make([]*models.GroupDomain, 0)
+	err := r.db.NewSelect().
+		Model(&domains).
+		Where("gd.group_id = ?", groupID).
+		Order("gd.domain ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group domains: %w", err)
+	}
+	return domains, nil
+}
+
+// GetByID returns a claimed domain
+func (r *GroupDomainRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.GroupDomain, error) {
+	domain := new(models.GroupDomain)
+	err := r.db.NewSelect().
+		Model(domain).
+		Where("gd.id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group domain: %w", err)
+	}
+	return domain, nil
+}
+
+// Create claims a domain for a group. A domain can only be claimed by one group.
+func (r *GroupDomainRepository) Create(ctx context.Context, domain *models.GroupDomain) error {
+	_, err := r.db.NewInsert().
+		Model(domain).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// MarkVerified records that ownership of a domain was proven
+func (r *GroupDomainRepository) MarkVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.GroupDomain)(nil)).
+		Set("verified_at = ?", verifiedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark group domain verified: %w", err)
+	}
+	return nil
+}
+
+// Delete releases a claimed domain
+func (r *GroupDomainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.GroupDomain)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete group domain: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// ListVerifiedForDomain returns the verified domains an email domain falls under, matching
+// the domain itself and its parent domains
+func (r *GroupDomainRepository) ListVerifiedForDomain(ctx context.Context, domain string) ([]*models.GroupDomain, error) {
+	domains := make([]*models.GroupDomain, 0)
+	err := r.db.NewSelect().
+		Model(&domains).
+		Where("gd.verified_at IS NOT NULL").
+		Where("(gd.domain = ? OR ? LIKE '%.' || gd.domain)", domain, domain).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list verified group domains: %w", err)
+	}
+	return domains, nil
+}
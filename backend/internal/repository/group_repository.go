@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
 )
 
 // GroupRepository handles group-related database operations
@@ -97,19 +98,21 @@ func (r *GroupRepository) List(ctx context.Context, page, pageSize int) ([]*mode
 	return groups, count, nil
 }
 
-// Update updates a group
+// Update updates a group. Members of the group and its child groups have their
+// group-granted roles re-synced, since a new parent changes the roles they inherit.
 func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error {
-	_, err := r.db.NewUpdate().
-		Model(group).
-		Where("g.id = ?", group.ID).
-		Where("is_system_group = ?", false). // Prevent updating system groups
-		Exec(ctx)
-
-	if err != nil {
-		return fmt.Errorf("failed to update group: %w", err)
-	}
-
-	return nil
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(group).
+			Where("g.id = ?", group.ID).
+			Where("is_system_group = ?", false). // Prevent updating system groups
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to update group: %w", err)
+		}
+
+		return syncSubtreeGroupRoles(ctx, tx, group.ID)
+	})
 }
 
 // Delete deletes a group (only if not a system group and has no members)
@@ -158,30 +161,40 @@ func (r *GroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return handlePgError(err)
 }
 
-// AddUser adds a user to a group
+// AddUser adds a user to a group and grants the roles mapped to the group and its parents
 func (r *GroupRepository) AddUser(ctx context.Context, groupID, userID uuid.UUID) error {
 	userGroup := &models.UserGroup{
 		UserID:  userID,
 		GroupID: groupID,
 	}
 
-	_, err := r.db.NewInsert().
-		Model(userGroup).
-		On("CONFLICT (user_id, group_id) DO NOTHING").
-		Exec(ctx)
-
-	return handlePgError(err)
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(userGroup).
+			On("CONFLICT (user_id, group_id) DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return handlePgError(err)
+		}
+
+		return syncGroupRoles(ctx, tx, []uuid.UUID{userID})
+	})
 }
 
-// RemoveUser removes a user from a group
+// RemoveUser removes a user from a group and revokes the roles it no longer gets from any group
 func (r *GroupRepository) RemoveUser(ctx context.Context, groupID, userID uuid.UUID) error {
-	_, err := r.db.NewDelete().
-		Model((*models.UserGroup)(nil)).
-		Where("group_id = ?", groupID).
-		Where("user_id = ?", userID).
-		Exec(ctx)
-
-	return handlePgError(err)
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.UserGroup)(nil)).
+			Where("group_id = ?", groupID).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return handlePgError(err)
+		}
+
+		return syncGroupRoles(ctx, tx, []uuid.UUID{userID})
+	})
 }
 
 // GetGroupMembers retrieves all users in a group
@@ -247,3 +260,104 @@ func (r *GroupRepository) GetGroupMemberCount(ctx context.Context, groupID uuid.
 
 	return count, nil
 }
+
+// GetGroupRoles returns the roles mapped directly to a group
+func (r *GroupRepository) GetGroupRoles(ctx context.Context, groupID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.NewSelect().
+		Model(&roles).
+		Join("INNER JOIN group_roles AS gr ON gr.role_id = role.id").
+		Where("gr.group_id = ?", groupID).
+		Order("role.name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetGroupRoles replaces the roles mapped to a group and re-syncs the group-granted roles
+// of the members of the group and its child groups
+func (r *GroupRepository) SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.GroupRole)(nil)).
+			Where("group_id = ?", groupID).
+			Exec(ctx)
+		if err != nil {
+			return handlePgError(err)
+		}
+
+		if len(roleIDs) > 0 {
+			groupRoles := make([]models.GroupRole, len(roleIDs))
+			for i, roleID := range roleIDs {
+				groupRoles[i] = models.GroupRole{GroupID: groupID, RoleID: roleID}
+			}
+			_, err = tx.NewInsert().
+				Model(&groupRoles).
+				On("CONFLICT (group_id, role_id) DO NOTHING").
+				Exec(ctx)
+			if err != nil {
+				return handlePgError(err)
+			}
+		}
+
+		return syncSubtreeGroupRoles(ctx, tx, groupID)
+	})
+}
+
+// memberGroupsCTE resolves every group a user belongs to, directly or through a child
+// group. UNION (not UNION ALL) stops the recursion if the hierarchy contains a cycle.
+const memberGroupsCTE = `
+	WITH RECURSIVE member_groups (user_id, group_id) AS (
+		SELECT ug.user_id, ug.group_id FROM user_groups AS ug WHERE ug.user_id IN (?)
+		UNION
+		SELECT mg.user_id, g.parent_group_id FROM member_groups AS mg
+		INNER JOIN groups AS g ON g.id = mg.group_id
+		WHERE g.parent_group_id IS NOT NULL
+	)`
+
+// syncGroupRoles brings the group-granted roles of the given users in line with their
+// group memberships. Roles that were also assigned directly are left untouched.
+func syncGroupRoles(ctx context.Context, db bun.IDB, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := db.ExecContext(ctx, memberGroupsCTE+`
+		INSERT INTO user_roles (user_id, role_id, application_id, granted_by_group)
+		SELECT DISTINCT mg.user_id, gr.role_id, role.application_id, true
+		FROM member_groups AS mg
+		INNER JOIN group_roles AS gr ON gr.group_id = mg.group_id
+		INNER JOIN roles AS role ON role.id = gr.role_id
+		ON CONFLICT (user_id, role_id) DO NOTHING`, bun.In(userIDs))
+	if err != nil {
+		return fmt.Errorf("failed to grant group roles: %w", handlePgError(err))
+	}
+
+	_, err = db.ExecContext(ctx, memberGroupsCTE+`
+		DELETE FROM user_roles AS ur
+		WHERE ur.user_id IN (?) AND ur.granted_by_group = true
+		AND NOT EXISTS (
+			SELECT 1 FROM member_groups AS mg
+			INNER JOIN group_roles AS gr ON gr.group_id = mg.group_id
+			WHERE mg.user_id = ur.user_id AND gr.role_id = ur.role_id
+		)`, bun.In(userIDs), bun.In(userIDs))
+	if err != nil {
+		return fmt.Errorf("failed to revoke group roles: %w", handlePgError(err))
+	}
+
+	return nil
+}
+
+// syncSubtreeGroupRoles re-syncs the group-granted roles of every member of a group and
+// of its child groups
+func syncSubtreeGroupRoles(ctx context.Context, db bun.IDB, groupID uuid.UUID) error {
+	var userIDs []uuid.UUID
+	if err := db.NewRaw(subtreeMemberIDsSQL, groupID).Scan(ctx, &userIDs); err != nil {
+		return fmt.Errorf("failed to get group subtree members: %w", err)
+	}
+
+	return syncGroupRoles(ctx, db, userIDs)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// GuestRepository handles anonymous guest account database operations
+type GuestRepository struct {
+	db *Database
+}
+
+// NewGuestRepository creates a new guest repository
+func NewGuestRepository(db *Database) *GuestRepository {
+	return &GuestRepository{db: db}
+}
+
+// Create inserts a guest user and assigns it the given role in one transaction
+func (r *GuestRepository) Create(ctx context.Context, user *models.User, roleID uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(user).Returning("*").Exec(ctx); err != nil {
+			return handlePgError(err)
+		}
+
+		userRole := &models.UserRole{
+			UserID:     user.ID,
+			RoleID:     roleID,
+			AssignedBy: &user.ID,
+		}
+		if _, err := tx.NewInsert().Model(userRole).Exec(ctx); err != nil {
+			return handlePgError(err)
+		}
+
+		return nil
+	})
+}
+
+// Upgrade stores the credentials of a guest user and clears its guest flag.
+// Returns ErrNotFound if the user is not a guest (anymore).
+func (r *GuestRepository) Upgrade(ctx context.Context, user *models.User) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("email = ?", user.Email).
+		Set("phone = ?", user.Phone).
+		Set("username = ?", user.Username).
+		Set("password_hash = ?", user.PasswordHash).
+		Set("full_name = ?", user.FullName).
+		Set("password_changed_at = ?", user.PasswordChangedAt).
+		Set("is_guest = ?", false).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", user.ID).
+		Where("is_guest = ?", true).
+		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	user.IsGuest = false
+	r.db.userChanged(ctx, user.ID)
+	return nil
+}
+
+// DeleteCreatedBefore deletes up to limit guest users created before the given time.
+// Data linked to the users is removed by the cascading foreign keys.
+func (r *GuestRepository) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	expired := r.db.NewSelect().
+		Model((*models.User)(nil)).
+		Column("id").
+		Where("is_guest = ?", true).
+		Where("created_at < ?", before).
+		Limit(limit)
+
+	result, err := r.db.NewDelete().
+		Model((*models.User)(nil)).
+		Where("id IN (?)", expired).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired guests: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
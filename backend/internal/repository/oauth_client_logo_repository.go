@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// OAuthClientLogoRepository handles uploaded OAuth client logo database operations
+type OAuthClientLogoRepository struct {
+	db *Database
+}
+
+// NewOAuthClientLogoRepository creates a new OAuth client logo repository
+func NewOAuthClientLogoRepository(db *Database) *OAuthClientLogoRepository {
+	return &OAuthClientLogoRepository{db: db}
+}
+
+// Save stores the logo of a client, replacing the previous one
+func (r *OAuthClientLogoRepository) Save(ctx context.Context, logo *models.OAuthClientLogo) error {
+	_, err := r.db.NewInsert().
+		Model(logo).
+		On("CONFLICT (client_id) DO UPDATE").
+		Set("hash = EXCLUDED.hash").
+		Set("content_type = EXCLUDED.content_type").
+		Set("content = EXCLUDED.content").
+		Set("size = EXCLUDED.size").
+		Set("created_at = EXCLUDED.created_at").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to save oauth client logo: %w", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a logo by its content hash
+func (r *OAuthClientLogoRepository) GetByHash(ctx context.Context, hash string) (*models.OAuthClientLogo, error) {
+	logo := new(models.OAuthClientLogo)
+
+	err := r.db.NewSelect().
+		Model(logo).
+		Where("hash = ?", hash).
+		Limit(1).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth client logo: %w", err)
+	}
+
+	return logo, nil
+}
+
+// Delete removes the logo of a client
+func (r *OAuthClientLogoRepository) Delete(ctx context.Context, clientID uuid.UUID) error {
+	_, err := r.db.NewDelete().
+		Model((*models.OAuthClientLogo)(nil)).
+		Where("client_id = ?", clientID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth client logo: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// OAuthClientWebhookRepository handles OAuth client webhook database operations
+type OAuthClientWebhookRepository struct {
+	db *Database
+}
+
+// NewOAuthClientWebhookRepository creates a new OAuth client webhook repository
+func NewOAuthClientWebhookRepository(db *Database) *OAuthClientWebhookRepository {
+	return &OAuthClientWebhookRepository{db: db}
+}
+
+// Create stores a new webhook
+func (r *OAuthClientWebhookRepository) Create(ctx context.Context, webhook *models.OAuthClientWebhook) error {
+	_, err := r.db.NewInsert().
+		Model(webhook).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create oauth client webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a webhook of a client
+func (r *OAuthClientWebhookRepository) GetByID(ctx context.Context, clientID, id uuid.UUID) (*models.OAuthClientWebhook, error) {
+	webhook := new(models.OAuthClientWebhook)
+
+	err := r.db.NewSelect().
+		Model(webhook).
+		Where("id = ?", id).
+		Where("client_id = ?", clientID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth client webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// ListByClient retrieves the webhooks of a client, oldest first
+func (r *OAuthClientWebhookRepository) ListByClient(ctx context.Context, clientID uuid.UUID) ([]*models.OAuthClientWebhook, error) {
+	webhooks := make([]*models.OAuthClientWebhook, 0)
+
+	err := r.db.NewSelect().
+		Model(&webhooks).
+		Where("client_id = ?", clientID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth client webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// ListActiveByEvent retrieves the active webhooks of a client subscribed to an event
+func (r *OAuthClientWebhookRepository) ListActiveByEvent(ctx context.Context, clientID uuid.UUID, eventType string) ([]*models.OAuthClientWebhook, error) {
+	webhooks := make([]*models.OAuthClientWebhook, 0)
+
+	eventJSON, _ := json.Marshal([]string{eventType})
+
+	err := r.db.NewSelect().
+		Model(&webhooks).
+		Where("client_id = ?", clientID).
+		Where("is_active = ?", true).
+		Where("events @> ?::jsonb", string(eventJSON)).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth client webhooks by event: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Update saves the URL, events and active flag of a webhook
+func (r *OAuthClientWebhookRepository) Update(ctx context.Context, webhook *models.OAuthClientWebhook) error {
+	result, err := r.db.NewUpdate().
+		Model(webhook).
+		Column("url", "events", "is_active").
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", webhook.ID).
+		Where("client_id = ?", webhook.ClientID).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update oauth client webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a webhook of a client along with its deliveries
+func (r *OAuthClientWebhookRepository) Delete(ctx context.Context, clientID, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.OAuthClientWebhook)(nil)).
+		Where("id = ?", id).
+		Where("client_id = ?", clientID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth client webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkTriggered records a successful delivery to a webhook
+func (r *OAuthClientWebhookRepository) MarkTriggered(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OAuthClientWebhook)(nil)).
+		Set("last_triggered_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark oauth client webhook triggered: %w", err)
+	}
+
+	return nil
+}
+
+// CreateDelivery stores a delivery attempt
+func (r *OAuthClientWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.OAuthClientWebhookDelivery) error {
+	_, err := r.db.NewInsert().
+		Model(delivery).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create oauth client webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteDelivery records the outcome of a delivery attempt
+func (r *OAuthClientWebhookRepository) CompleteDelivery(ctx context.Context, id uuid.UUID, status string, httpStatusCode *int, responseBody string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OAuthClientWebhookDelivery)(nil)).
+		Set("status = ?", status).
+		Set("http_status_code = ?", httpStatusCode).
+		Set("response_body = ?", responseBody).
+		Set("completed_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to complete oauth client webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves the deliveries of a webhook, newest first
+func (r *OAuthClientWebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, page, perPage int) ([]*models.OAuthClientWebhookDelivery, int, error) {
+	deliveries := make([]*models.OAuthClientWebhookDelivery, 0)
+
+	total, err := r.db.NewSelect().
+		Model(&deliveries).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list oauth client webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
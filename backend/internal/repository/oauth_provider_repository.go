@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/queryopt"
+	"github.com/uptrace/bun"
 )
 
 type OAuthProviderRepository struct {
@@ -73,10 +74,11 @@ func (r *OAuthProviderRepository) UpdateClient(ctx context.Context, client *mode
 
 	result, err := r.db.NewUpdate().
 		Model(client).
-		Column("name", "description", "logo_url", "client_type", "redirect_uris",
+		Column("name", "description", "logo_url", "client_type", "redirect_uris", "backchannel_logout_uri",
+			"post_logout_redirect_uris", "frontchannel_logout_uri",
 			"allowed_grant_types", "allowed_scopes", "default_scopes", "access_token_ttl",
 			"refresh_token_ttl", "id_token_ttl", "require_pkce", "require_consent",
-			"first_party", "is_active", "updated_at").
+			"first_party", "tls_client_certificate_bound_access_tokens", "require_refresh_nonce", "claims_mapping", "is_active", "updated_at").
 		WherePK().
 		Returning("*").
 		Exec(ctx)
@@ -193,6 +195,35 @@ func (r *OAuthProviderRepository) ListActiveClients(ctx context.Context) ([]*mod
 	return clients, nil
 }
 
+// ListFrontchannelLogoutClients returns the active clients with a front-channel logout URI
+// that the user holds unexpired tokens for
+func (r *OAuthProviderRepository) ListFrontchannelLogoutClients(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClient, error) {
+	clients := make([]*models.OAuthClient, 0)
+	now := time.Now()
+
+	err := r.db.NewSelect().
+		Model(&clients).
+		Where("is_active = ?", true).
+		Where("frontchannel_logout_uri <> ''").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where(`EXISTS (SELECT 1 FROM oauth_access_tokens AS t
+					WHERE t.client_id = ?TableAlias.id AND t.user_id = ? AND t.is_active
+					AND t.revoked_at IS NULL AND t.expires_at > ?)`, userID, now).
+				WhereOr(`EXISTS (SELECT 1 FROM oauth_refresh_tokens AS t
+					WHERE t.client_id = ?TableAlias.id AND t.user_id = ? AND t.is_active
+					AND t.revoked_at IS NULL AND t.expires_at > ?)`, userID, now)
+		}).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list front-channel logout clients: %w", err)
+	}
+
+	return clients, nil
+}
+
 func (r *OAuthProviderRepository) CreateAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error {
 	code.CreatedAt = time.Now()
 
@@ -510,6 +541,53 @@ func (r *OAuthProviderRepository) DeleteExpiredRefreshTokens(ctx context.Context
 	return rows, nil
 }
 
+// GetUserTokenStats summarizes the access and refresh tokens issued to a user, per client
+func (r *OAuthProviderRepository) GetUserTokenStats(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClientTokenStats, error) {
+	now := time.Now()
+	stats := make(map[uuid.UUID]*models.OAuthClientTokenStats)
+	order := make([]uuid.UUID, 0)
+
+	for _, model := range []interface{}{(*models.OAuthAccessToken)(nil), (*models.OAuthRefreshToken)(nil)} {
+		var rows []*models.OAuthClientTokenStats
+		err := r.db.NewSelect().
+			Model(model).
+			Column("client_id").
+			ColumnExpr("MIN(created_at) as first_used_at").
+			ColumnExpr("MAX(created_at) as last_used_at").
+			ColumnExpr("COUNT(*) FILTER (WHERE is_active AND revoked_at IS NULL AND expires_at > ?) as active_tokens", now).
+			Where("user_id = ?", userID).
+			Group("client_id").
+			Scan(ctx, &rows)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user token stats: %w", err)
+		}
+
+		for _, row := range rows {
+			existing, ok := stats[row.ClientID]
+			if !ok {
+				stats[row.ClientID] = row
+				order = append(order, row.ClientID)
+				continue
+			}
+			if row.FirstUsedAt != nil && (existing.FirstUsedAt == nil || row.FirstUsedAt.Before(*existing.FirstUsedAt)) {
+				existing.FirstUsedAt = row.FirstUsedAt
+			}
+			if row.LastUsedAt != nil && (existing.LastUsedAt == nil || row.LastUsedAt.After(*existing.LastUsedAt)) {
+				existing.LastUsedAt = row.LastUsedAt
+			}
+			existing.ActiveTokens += row.ActiveTokens
+		}
+	}
+
+	result := make([]*models.OAuthClientTokenStats, 0, len(order))
+	for _, clientID := range order {
+		result = append(result, stats[clientID])
+	}
+
+	return result, nil
+}
+
 func (r *OAuthProviderRepository) CreateOrUpdateConsent(ctx context.Context, consent *models.UserConsent) error {
 	consent.GrantedAt = time.Now()
 
@@ -786,3 +864,95 @@ func (r *OAuthProviderRepository) DeleteScope(ctx context.Context, id uuid.UUID)
 
 	return nil
 }
+
+func (r *OAuthProviderRepository) CreateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error {
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+
+	_, err := r.db.NewInsert().
+		Model(group).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+func (r *OAuthProviderRepository) GetScopeGroupByID(ctx context.Context, id uuid.UUID) (*models.OAuthScopeGroup, error) {
+	group := new(models.OAuthScopeGroup)
+
+	err := r.db.NewSelect().
+		Model(group).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth scope group: %w", err)
+	}
+
+	return group, nil
+}
+
+func (r *OAuthProviderRepository) ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+	groups := make([]*models.OAuthScopeGroup, 0)
+
+	err := r.db.NewSelect().
+		Model(&groups).
+		Order("name ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth scope groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (r *OAuthProviderRepository) UpdateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error {
+	group.UpdatedAt = time.Now()
+
+	result, err := r.db.NewUpdate().
+		Model(group).
+		Column("display_name", "description", "scopes", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update oauth scope group: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OAuthProviderRepository) DeleteScopeGroup(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.OAuthScopeGroup)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth scope group: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
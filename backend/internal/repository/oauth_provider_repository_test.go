@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// queryRecorder captures the SQL bun sends, before the driver runs it
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (h *queryRecorder) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queries = append(h.queries, event.Query)
+	return ctx
+}
+
+func (h *queryRecorder) AfterQuery(context.Context, *bun.QueryEvent) {}
+
+// setupRecordingDB returns a Database whose queries are recorded and then fail
+// to reach a server, so the generated SQL can be checked without PostgreSQL.
+func setupRecordingDB(t *testing.T) (*Database, *queryRecorder) {
+	t.Helper()
+	sqldb := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithAddr("127.0.0.1:1"),
+		pgdriver.WithDialTimeout(time.Second),
+		pgdriver.WithInsecure(true),
+	))
+	t.Cleanup(func() { sqldb.Close() })
+
+	recorder := &queryRecorder{}
+	bunDB := bun.NewDB(sqldb, pgdialect.New())
+	bunDB.AddQueryHook(recorder)
+	registerModels(bunDB)
+	return &Database{DB: bunDB, sqlDB: sqldb}, recorder
+}
+
+func TestOAuthProviderRepository_UpdateClient_PersistsCertificateBinding(t *testing.T) {
+	db, recorder := setupRecordingDB(t)
+	repo := NewOAuthProviderRepository(db)
+
+	client := &models.OAuthClient{
+		ID:                    uuid.New(),
+		ClientID:              "mtls-client",
+		Name:                  "mTLS client",
+		CertBoundAccessTokens: true,
+	}
+
+	err := repo.UpdateClient(context.Background(), client)
+	require.Error(t, err)
+
+	require.Len(t, recorder.queries, 1)
+	assert.Contains(t, recorder.queries[0], `"tls_client_certificate_bound_access_tokens" = TRUE`)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// OutboxRepository handles outbox event database operations
+type OutboxRepository struct {
+	db *Database
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *Database) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// CreateWithTx writes an event through db, which is the transaction of the state change
+// the event describes
+func (r *OutboxRepository) CreateWithTx(ctx context.Context, db bun.IDB, event *models.OutboxEvent) error {
+	_, err := db.NewInsert().
+		Model(event).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit available events, oldest first, and pushes them back by lease
+// so other instances skip them; an event whose instance dies mid-dispatch becomes available
+// again when the lease runs out. The attempt is counted when the event is claimed.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error) {
+	now := time.Now()
+	var ids []uuid.UUID
+	err := r.db.NewRaw(`
+		UPDATE outbox_events
+		SET attempts = attempts + 1, available_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE available_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, now.Add(lease), now, limit).
+		Scan(ctx, &ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	events := make([]*models.OutboxEvent, 0, len(ids))
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	err = r.db.NewSelect().
+		Model(&events).
+		Where("id IN (?)", bun.In(ids)).
+		Order("created_at").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Delete removes a dispatched event
+func (r *OutboxRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.NewDelete().
+		Model((*models.OutboxEvent)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// Reschedule makes an event that failed to dispatch available again at availableAt
+func (r *OutboxRepository) Reschedule(ctx context.Context, id uuid.UUID, availableAt time.Time, lastError string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxEvent)(nil)).
+		Set("available_at = ?", availableAt).
+		Set("last_error = ?", lastError).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// effectiveExpirySQL computes when a user's password expires. The strictest of the user's role
+// and group max ages and the global default applies; an explicit password_expires_at wins if earlier.
+// LEAST ignores NULLs, so users without any policy get NULL.
+const effectiveExpirySQL = `LEAST(
+	u.password_expires_at,
+	COALESCE(u.password_changed_at, u.created_at) + LEAST(
+		(SELECT MIN(r.password_max_age_days) FROM user_roles AS ur JOIN roles AS r ON r.id = ur.role_id WHERE ur.user_id = u.id),
+		(SELECT MIN(g.password_max_age_days) FROM user_groups AS ug JOIN groups AS g ON g.id = ug.group_id WHERE ug.user_id = u.id),
+		NULLIF(?::int, 0)
+	) * INTERVAL '1 day'
+)`
+
+// PasswordExpiryRepository handles password expiry policy database operations
+type PasswordExpiryRepository struct {
+	db *Database
+}
+
+// NewPasswordExpiryRepository creates a new password expiry repository
+func NewPasswordExpiryRepository(db *Database) *PasswordExpiryRepository {
+	return &PasswordExpiryRepository{db: db}
+}
+
+// GetExpiry returns when the user's password expires, or nil if no policy applies
+func (r *PasswordExpiryRepository) GetExpiry(ctx context.Context, userID uuid.UUID, defaultMaxAgeDays int) (*time.Time, error) {
+	var expiresAt bun.NullTime
+
+	err := r.db.NewSelect().
+		TableExpr("users AS u").
+		ColumnExpr(effectiveExpirySQL, defaultMaxAgeDays).
+		Where("u.id = ?", userID).
+		Scan(ctx, &expiresAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password expiry: %w", err)
+	}
+
+	if expiresAt.IsZero() {
+		return nil, nil
+	}
+
+	return &expiresAt.Time, nil
+}
+
+// ListExpiring returns active password users whose password expires before the given time,
+// soonest first. With unwarnedOnly, users who were already sent a warning are skipped.
+func (r *PasswordExpiryRepository) ListExpiring(ctx context.Context, defaultMaxAgeDays int, before time.Time, unwarnedOnly bool, limit int) ([]*models.PasswordExpiryEntry, error) {
+	entries := make([]*models.PasswordExpiryEntry, 0)
+
+	inner := r.db.NewSelect().
+		TableExpr("users AS u").
+		ColumnExpr("u.id, u.email, u.username, u.password_changed_at, u.password_expiry_warned_at").
+		ColumnExpr(effectiveExpirySQL+" AS expires_at", defaultMaxAgeDays).
+		Where("u.is_active = TRUE").
+		Where("u.password_hash <> ''")
+
+	query := r.db.NewSelect().
+		TableExpr("(?) AS e", inner).
+		ColumnExpr("e.*").
+		Where("e.expires_at IS NOT NULL").
+		Where("e.expires_at <= ?", before).
+		OrderExpr("e.expires_at ASC").
+		Limit(limit)
+
+	if unwarnedOnly {
+		query = query.Where("e.password_expiry_warned_at IS NULL")
+	}
+
+	if err := query.Scan(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to list expiring passwords: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkWarned records that the expiry warning was sent for the current password
+func (r *PasswordExpiryRepository) MarkWarned(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("password_expiry_warned_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark password expiry warning: %w", err)
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// SetMustChangePassword sets or clears the flag forcing a password change at next sign-in
+func (r *PasswordExpiryRepository) SetMustChangePassword(ctx context.Context, userID uuid.UUID, required bool) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("must_change_password = ?", required).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update must_change_password: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// SetRoleMaxAge sets or clears (nil) the password max age of a role
+func (r *PasswordExpiryRepository) SetRoleMaxAge(ctx context.Context, roleID uuid.UUID, maxAgeDays *int) error {
+	return r.setMaxAge(ctx, (*models.Role)(nil), roleID, maxAgeDays)
+}
+
+// SetGroupMaxAge sets or clears (nil) the password max age of a group
+func (r *PasswordExpiryRepository) SetGroupMaxAge(ctx context.Context, groupID uuid.UUID, maxAgeDays *int) error {
+	return r.setMaxAge(ctx, (*models.Group)(nil), groupID, maxAgeDays)
+}
+
+func (r *PasswordExpiryRepository) setMaxAge(ctx context.Context, model interface{}, id uuid.UUID, maxAgeDays *int) error {
+	result, err := r.db.NewUpdate().
+		Model(model).
+		Set("password_max_age_days = ?", maxAgeDays).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update password max age: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// PasswordHistoryRepository handles password history database operations
+type PasswordHistoryRepository struct {
+	db *Database
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *Database) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: db}
+}
+
+// Create records a password hash in the user's history
+func (r *PasswordHistoryRepository) Create(ctx context.Context, entry *models.PasswordHistory) error {
+	_, err := r.db.NewInsert().
+		Model(entry).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to create password history entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent retrieves the user's most recent password hashes, newest first
+func (r *PasswordHistoryRepository) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*models.PasswordHistory, error) {
+	entries := make([]*models.PasswordHistory, 0, limit)
+
+	err := r.db.NewSelect().
+		Model(&entries).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Prune deletes all but the user's most recent keep entries
+func (r *PasswordHistoryRepository) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	recent := r.db.NewSelect().
+		Model((*models.PasswordHistory)(nil)).
+		Column("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(keep)
+
+	_, err := r.db.NewDelete().
+		Model((*models.PasswordHistory)(nil)).
+		Where("user_id = ?", userID).
+		Where("id NOT IN (?)", recent).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to prune password history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// PermissionCatalogRepository handles service-registered permission catalog entries
+type PermissionCatalogRepository struct {
+	db *Database
+}
+
+// NewPermissionCatalogRepository creates a new permission catalog repository
+func NewPermissionCatalogRepository(db *Database) *PermissionCatalogRepository {
+	return &PermissionCatalogRepository{db: db}
+}
+
+// ReplaceEntries replaces all entries registered by a source with the given ones
+func (r *PermissionCatalogRepository) ReplaceEntries(ctx context.Context, source string, entries []*models.PermissionCatalogEntry) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.PermissionCatalogEntry)(nil)).
+			Where("source = ?", source).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete permission catalog entries: %w", err)
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if _, err := tx.NewInsert().Model(&entries).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to insert permission catalog entries: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListEntries lists all registered entries
+func (r *PermissionCatalogRepository) ListEntries(ctx context.Context) ([]*models.PermissionCatalogEntry, error) {
+	entries := make([]*models.PermissionCatalogEntry, 0)
+
+	err := r.db.NewSelect().
+		Model(&entries).
+		Order("resource", "action", "source").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission catalog entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ListGrants lists every role-permission assignment along with the role names
+func (r *PermissionCatalogRepository) ListGrants(ctx context.Context) ([]*models.PermissionGrant, error) {
+	grants := make([]*models.PermissionGrant, 0)
+
+	err := r.db.NewSelect().
+		TableExpr("role_permissions AS rp").
+		ColumnExpr("rp.permission_id, r.id AS role_id, r.name AS role_name, r.display_name AS role_display_name").
+		Join("JOIN roles AS r ON r.id = rp.role_id").
+		Order("r.name").
+		Scan(ctx, &grants)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission grants: %w", err)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// PreparedQueries runs the highest-traffic lookups (users by ID or email, sessions and
+// refresh tokens by hash, blacklist checks) directly on pgx. Every statement is prepared once
+// per connection and rows are scanned straight into the models, skipping bun's query
+// building and reflection. Everything else keeps going through bun.
+type PreparedQueries struct {
+	pool *pgxpool.Pool
+}
+
+// column is a selected column. Columns that may be NULL but map to a non-pointer field are
+// coalesced to zero, matching what bun scans for them.
+type column struct {
+	name string
+	zero string
+}
+
+// Columns in the order of the matching *Fields functions below
+var (
+	userColumns = []column{
+		{name: "id"},
+		{name: "email"},
+		{name: "phone"},
+		{name: "username"},
+		{name: "password_hash"},
+		{name: "full_name", zero: "''"},
+		{name: "profile_picture_url", zero: "''"},
+		{name: "account_type", zero: "''"},
+		{name: "email_verified", zero: "false"},
+		{name: "email_verified_at"},
+		{name: "phone_verified", zero: "false"},
+		{name: "recovery_email"},
+		{name: "recovery_email_verified"},
+		{name: "is_active", zero: "false"},
+		{name: "state"},
+		{name: "state_changed_at"},
+		{name: "last_login_at"},
+		{name: "is_guest"},
+		{name: "totp_secret"},
+		{name: "totp_enabled", zero: "false"},
+		{name: "totp_enabled_at"},
+		{name: "webauthn_enabled", zero: "false"},
+		{name: "password_expires_at"},
+		{name: "password_changed_at"},
+		{name: "must_change_password"},
+		{name: "password_expiry_warned_at"},
+		{name: "token_version"},
+		{name: "created_at"},
+		{name: "updated_at"},
+	}
+	roleColumns = []column{
+		{name: "id"},
+		{name: "name"},
+		{name: "application_id"},
+		{name: "display_name"},
+		{name: "description", zero: "''"},
+		{name: "is_system_role", zero: "false"},
+		{name: "password_max_age_days"},
+		{name: "created_at"},
+		{name: "updated_at"},
+	}
+	sessionColumns = []column{
+		{name: "id"},
+		{name: "user_id"},
+		{name: "application_id"},
+		{name: "token_hash"},
+		{name: "access_token_hash", zero: "''"},
+		{name: "device_type", zero: "''"},
+		{name: "os", zero: "''"},
+		{name: "browser", zero: "''"},
+		{name: "ip_address", zero: "''"},
+		{name: "user_agent", zero: "''"},
+		{name: "session_name", zero: "''"},
+		{name: "last_active_at"},
+		{name: "expires_at"},
+		{name: "created_at"},
+		{name: "revoked_at"},
+	}
+	refreshTokenColumns = []column{
+		{name: "id"},
+		{name: "user_id"},
+		{name: "token_hash"},
+		{name: "device_type", zero: "''"},
+		{name: "os", zero: "''"},
+		{name: "browser", zero: "''"},
+		{name: "ip_address", zero: "''"},
+		{name: "user_agent", zero: "''"},
+		{name: "last_active_at"},
+		{name: "session_name", zero: "''"},
+		{name: "expires_at"},
+		{name: "created_at"},
+		{name: "revoked_at"},
+	}
+)
+
+func userFields(u *models.User) []any {
+	return []any{
+		&u.ID, &u.Email, &u.Phone, &u.Username, &u.PasswordHash, &u.FullName, &u.ProfilePictureURL,
+		&u.AccountType, &u.EmailVerified, &u.EmailVerifiedAt, &u.PhoneVerified, &u.RecoveryEmail,
+		&u.RecoveryEmailVerified, &u.IsActive, &u.State, &u.StateChangedAt, &u.LastLoginAt, &u.IsGuest, &u.TOTPSecret, &u.TOTPEnabled,
+		&u.TOTPEnabledAt, &u.WebAuthnEnabled, &u.PasswordExpiresAt, &u.PasswordChangedAt, &u.MustChangePassword,
+		&u.PasswordExpiryWarnedAt, &u.TokenVersion, &u.CreatedAt, &u.UpdatedAt,
+	}
+}
+
+func roleFields(r *models.Role) []any {
+	return []any{
+		&r.ID, &r.Name, &r.ApplicationID, &r.DisplayName, &r.Description, &r.IsSystemRole,
+		&r.PasswordMaxAgeDays, &r.CreatedAt, &r.UpdatedAt,
+	}
+}
+
+func sessionFields(s *models.Session) []any {
+	return []any{
+		&s.ID, &s.UserID, &s.ApplicationID, &s.TokenHash, &s.AccessTokenHash, &s.DeviceType, &s.OS,
+		&s.Browser, &s.IPAddress, &s.UserAgent, &s.SessionName, &s.LastActiveAt, &s.ExpiresAt,
+		&s.CreatedAt, &s.RevokedAt,
+	}
+}
+
+func refreshTokenFields(t *models.RefreshToken) []any {
+	return []any{
+		&t.ID, &t.UserID, &t.TokenHash, &t.DeviceType, &t.OS, &t.Browser, &t.IPAddress, &t.UserAgent,
+		&t.LastActiveAt, &t.SessionName, &t.ExpiresAt, &t.CreatedAt, &t.RevokedAt,
+	}
+}
+
+// selectList renders columns for a SELECT, qualified with alias
+func selectList(alias string, columns []column) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		if c.zero != "" {
+			parts[i] = fmt.Sprintf("COALESCE(%s.%s, %s)", alias, c.name, c.zero)
+		} else {
+			parts[i] = alias + "." + c.name
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Statement names; each is prepared on every new connection
+const (
+	stmtUserByID           = "hot_user_by_id"
+	stmtUserByEmail        = "hot_user_by_email"
+	stmtUserRoles          = "hot_user_roles"
+	stmtSessionByTokenHash = "hot_session_by_token_hash"
+	stmtRefreshTokenByHash = "hot_refresh_token_by_hash"
+	stmtTokenIsBlacklisted = "hot_token_is_blacklisted"
+)
+
+// preparedStatements maps statement names to their SQL. A NULL is_active argument matches
+// users regardless of status.
+var preparedStatements = map[string]string{
+	stmtUserByID: "SELECT " + selectList("u", userColumns) + ` FROM users AS u
+		WHERE u.id = $1 AND ($2::boolean IS NULL OR u.is_active = $2)`,
+	// Primary email first, then verified secondary emails
+	stmtUserByEmail: "SELECT " + selectList("u", userColumns) + ` FROM users AS u
+		WHERE (u.email = $1 OR u.id IN (SELECT user_id FROM user_emails WHERE email = $1 AND verified))
+		AND ($2::boolean IS NULL OR u.is_active = $2)
+		ORDER BY u.email = $1 DESC
+		LIMIT 1`,
+	stmtUserRoles: "SELECT " + selectList("r", roleColumns) + ` FROM roles AS r
+		JOIN user_roles AS ur ON ur.role_id = r.id
+		WHERE ur.user_id = $1`,
+	stmtSessionByTokenHash: "SELECT " + selectList("s", sessionColumns) + ` FROM sessions AS s
+		WHERE s.token_hash = $1 AND s.revoked_at IS NULL`,
+	stmtRefreshTokenByHash: "SELECT " + selectList("t", refreshTokenColumns) + ` FROM refresh_tokens AS t
+		WHERE t.token_hash = $1 AND t.revoked_at IS NULL`,
+	stmtTokenIsBlacklisted: `SELECT EXISTS (
+		SELECT 1 FROM token_blacklist WHERE token_hash = $1 AND expires_at > CURRENT_TIMESTAMP)`,
+}
+
+// NewPreparedQueries opens a pgx pool for the hot lookups. tracer, if not nil, sees every
+// query, e.g. to inject faults.
+func NewPreparedQueries(ctx context.Context, cfg *config.DatabaseConfig, tracer pgx.QueryTracer) (*PreparedQueries, error) {
+	poolCfg, err := pgxpool.ParseConfig(preparedQueriesDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+	if cfg.PreparedMaxConns > 0 {
+		poolCfg.MaxConns = int32(cfg.PreparedMaxConns)
+	}
+	poolCfg.ConnConfig.Tracer = tracer
+	poolCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		for name, sql := range preparedStatements {
+			if _, err := conn.Prepare(ctx, name, sql); err != nil {
+				return fmt.Errorf("failed to prepare %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	// Connect once so schema mismatches in the statements fail startup
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &PreparedQueries{pool: pool}, nil
+}
+
+func preparedQueriesDSN(cfg *config.DatabaseConfig) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     "/" + cfg.DBName,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// Close closes the pool
+func (q *PreparedQueries) Close() {
+	q.pool.Close()
+}
+
+// UserByID returns the user, with roles if withRoles is set, or an error matching sql.ErrNoRows
+func (q *PreparedQueries) UserByID(ctx context.Context, id uuid.UUID, isActive *bool, withRoles bool) (*models.User, error) {
+	return q.user(ctx, stmtUserByID, id, isActive, withRoles)
+}
+
+// UserByEmail returns the user with the primary or a verified secondary email, with roles if
+// withRoles is set, or an error matching sql.ErrNoRows
+func (q *PreparedQueries) UserByEmail(ctx context.Context, email string, isActive *bool, withRoles bool) (*models.User, error) {
+	return q.user(ctx, stmtUserByEmail, email, isActive, withRoles)
+}
+
+func (q *PreparedQueries) user(ctx context.Context, stmt string, key any, isActive *bool, withRoles bool) (*models.User, error) {
+	user := new(models.User)
+	if err := q.pool.QueryRow(ctx, stmt, key, isActive).Scan(userFields(user)...); err != nil {
+		return nil, err
+	}
+	if !withRoles {
+		return user, nil
+	}
+
+	rows, err := q.pool.Query(ctx, stmtUserRoles, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(roleFields(&role)...); err != nil {
+			return nil, err
+		}
+		user.Roles = append(user.Roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SessionByTokenHash returns the unrevoked session with the refresh token hash, or an error matching sql.ErrNoRows
+func (q *PreparedQueries) SessionByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	session := new(models.Session)
+	if err := q.pool.QueryRow(ctx, stmtSessionByTokenHash, tokenHash).Scan(sessionFields(session)...); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// RefreshTokenByHash returns the unrevoked refresh token with the hash, or an error matching sql.ErrNoRows
+func (q *PreparedQueries) RefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	token := new(models.RefreshToken)
+	if err := q.pool.QueryRow(ctx, stmtRefreshTokenByHash, tokenHash).Scan(refreshTokenFields(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// IsBlacklisted reports whether the token hash has an unexpired blacklist entry
+func (q *PreparedQueries) IsBlacklisted(ctx context.Context, tokenHash string) (bool, error) {
+	var blacklisted bool
+	err := q.pool.QueryRow(ctx, stmtTokenIsBlacklisted, tokenHash).Scan(&blacklisted)
+	return blacklisted, err
+}
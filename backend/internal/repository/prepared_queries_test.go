@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreparedQueries_ColumnsMatchFields(t *testing.T) {
+	assert.Len(t, userFields(new(models.User)), len(userColumns))
+	assert.Len(t, roleFields(new(models.Role)), len(roleColumns))
+	assert.Len(t, sessionFields(new(models.Session)), len(sessionColumns))
+	assert.Len(t, refreshTokenFields(new(models.RefreshToken)), len(refreshTokenColumns))
+}
+
+func TestSelectList_CoalescesNullableColumns(t *testing.T) {
+	got := selectList("u", []column{{name: "id"}, {name: "full_name", zero: "''"}})
+	assert.Equal(t, "u.id, COALESCE(u.full_name, '')", got)
+}
+
+func TestPreparedQueriesDSN(t *testing.T) {
+	dsn := preparedQueriesDSN(&config.DatabaseConfig{
+		Host: "db", Port: "5432", User: "app", Password: "p@ss", DBName: "auth_gateway",
+	})
+	assert.Equal(t, "postgres://app:p%40ss@db:5432/auth_gateway?sslmode=disable", dsn)
+}
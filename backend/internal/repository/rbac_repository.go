@@ -106,13 +106,18 @@ func (r *RBACRepository) ListPermissionsByResource(ctx context.Context, resource
 	return permissions, nil
 }
 
-// UpdatePermission updates a permission
-func (r *RBACRepository) UpdatePermission(ctx context.Context, id uuid.UUID, description string) error {
-	result, err := r.db.NewUpdate().
+// UpdatePermission updates a permission's description, and its condition when condition
+// is not nil (an empty condition makes the permission unconditional)
+func (r *RBACRepository) UpdatePermission(ctx context.Context, id uuid.UUID, description string, condition *string) error {
+	query := r.db.NewUpdate().
 		Model((*models.Permission)(nil)).
 		Set("description = ?", description).
-		Where("id = ?", id).
-		Exec(ctx)
+		Where("id = ?", id)
+	if condition != nil {
+		query = query.Set("condition = NULLIF(?, '')", *condition)
+	}
+
+	result, err := query.Exec(ctx)
 
 	if err != nil {
 		return fmt.Errorf("failed to update permission: %w", err)
@@ -184,7 +189,12 @@ func (r *RBACRepository) GetRoleByID(ctx context.Context, id uuid.UUID) (*models
 		return nil, fmt.Errorf("failed to get role by id: %w", err)
 	}
 
-	return role, nil
+	roles := []models.Role{*role}
+	if err := r.loadRoleLinks(ctx, roles); err != nil {
+		return nil, err
+	}
+
+	return &roles[0], nil
 }
 
 // GetRoleByName retrieves a role by name
@@ -220,6 +230,9 @@ func (r *RBACRepository) ListRoles(ctx context.Context) ([]models.Role, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to list roles: %w", err)
 	}
+	if err := r.loadRoleLinks(ctx, roles); err != nil {
+		return nil, err
+	}
 
 	return roles, nil
 }
@@ -247,11 +260,18 @@ func (r *RBACRepository) UpdateRole(ctx context.Context, id uuid.UUID, displayNa
 		return fmt.Errorf("role not found")
 	}
 
+	r.roleHoldersChanged(ctx, id)
 	return nil
 }
 
 // DeleteRole deletes a role (only if not a system role)
 func (r *RBACRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	// Assignments are removed with the role, so collect the holders first
+	holders, err := r.roleHolders(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get role holders: %w", err)
+	}
+
 	result, err := r.db.NewDelete().
 		Model((*models.Role)(nil)).
 		Where("id = ?", id).
@@ -271,9 +291,29 @@ func (r *RBACRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("role not found or is a system role")
 	}
 
+	r.db.userChanged(ctx, holders...)
 	return nil
 }
 
+// roleHolders returns the IDs of users holding the role
+func (r *RBACRepository) roleHolders(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.NewSelect().
+		Model((*models.UserRole)(nil)).
+		Column("user_id").
+		Where("role_id = ?", roleID).
+		Scan(ctx, &userIDs)
+	return userIDs, err
+}
+
+// roleHoldersChanged reports every holder of the role as changed. Failures only delay
+// invalidation until cached copies expire, so they are not returned.
+func (r *RBACRepository) roleHoldersChanged(ctx context.Context, roleID uuid.UUID) {
+	if userIDs, err := r.roleHolders(ctx, roleID); err == nil {
+		r.db.userChanged(ctx, userIDs...)
+	}
+}
+
 // ============================================================
 // Role-Permission Methods
 // ============================================================
@@ -364,18 +404,371 @@ func (r *RBACRepository) SetRolePermissions(ctx context.Context, roleID uuid.UUI
 	})
 }
 
+// ============================================================
+// Role Inheritance Methods
+// ============================================================
+
+// ListRoleParents retrieves every role inheritance link
+func (r *RBACRepository) ListRoleParents(ctx context.Context) ([]models.RoleParent, error) {
+	links := make([]models.RoleParent, 0)
+
+	err := r.db.NewSelect().
+		Model(&links).
+		Order("created_at").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role parents: %w", err)
+	}
+
+	return links, nil
+}
+
+// SetRoleParents sets the roles a role inherits from (replaces existing)
+func (r *RBACRepository) SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.RoleParent)(nil)).
+			Where("role_id = ?", roleID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing role parents: %w", err)
+		}
+
+		if len(parentIDs) > 0 {
+			links := make([]*models.RoleParent, len(parentIDs))
+			for i, parentID := range parentIDs {
+				links[i] = &models.RoleParent{
+					RoleID:       roleID,
+					ParentRoleID: parentID,
+				}
+			}
+
+			_, err = tx.NewInsert().
+				Model(&links).
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to insert role parents: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListRoleBundles retrieves every bundle granted to a role
+func (r *RBACRepository) ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error) {
+	links := make([]models.RoleBundle, 0)
+
+	err := r.db.NewSelect().
+		Model(&links).
+		Order("granted_at").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bundles: %w", err)
+	}
+
+	return links, nil
+}
+
+// SetRoleBundles sets the permission bundles granted to a role (replaces existing)
+func (r *RBACRepository) SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.RoleBundle)(nil)).
+			Where("role_id = ?", roleID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing role bundles: %w", err)
+		}
+
+		if len(bundleIDs) > 0 {
+			links := make([]*models.RoleBundle, len(bundleIDs))
+			for i, bundleID := range bundleIDs {
+				links[i] = &models.RoleBundle{
+					RoleID:   roleID,
+					BundleID: bundleID,
+				}
+			}
+
+			_, err = tx.NewInsert().
+				Model(&links).
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to insert role bundles: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// loadRoleLinks fills in the parent roles and bundles of the roles
+func (r *RBACRepository) loadRoleLinks(ctx context.Context, roles []models.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	index := make(map[uuid.UUID]int, len(roles))
+	roleIDs := make([]uuid.UUID, len(roles))
+	for i := range roles {
+		index[roles[i].ID] = i
+		roleIDs[i] = roles[i].ID
+	}
+
+	var parents []models.RoleParent
+	err := r.db.NewSelect().
+		Model(&parents).
+		Where("role_id IN (?)", bun.In(roleIDs)).
+		Order("created_at").
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get role parents: %w", err)
+	}
+	for _, link := range parents {
+		i := index[link.RoleID]
+		roles[i].ParentRoles = append(roles[i].ParentRoles, link.ParentRoleID)
+	}
+
+	var bundles []models.RoleBundle
+	err = r.db.NewSelect().
+		Model(&bundles).
+		Where("role_id IN (?)", bun.In(roleIDs)).
+		Order("granted_at").
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get role bundles: %w", err)
+	}
+	for _, link := range bundles {
+		i := index[link.RoleID]
+		roles[i].Bundles = append(roles[i].Bundles, link.BundleID)
+	}
+
+	return nil
+}
+
+// loadEffectivePermissions sets the permissions of the roles to their effective permissions:
+// their own, those of their bundles and those of the roles they inherit from
+func (r *RBACRepository) loadEffectivePermissions(ctx context.Context, roles []models.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	index := make(map[uuid.UUID]int, len(roles))
+	roleIDs := make([]uuid.UUID, len(roles))
+	for i := range roles {
+		index[roles[i].ID] = i
+		roleIDs[i] = roles[i].ID
+		roles[i].Permissions = []models.Permission{}
+	}
+
+	var grants []struct {
+		RoleID       uuid.UUID `bun:"role_id,type:uuid"`
+		PermissionID uuid.UUID `bun:"permission_id,type:uuid"`
+	}
+	err := r.db.NewSelect().
+		TableExpr("effective_role_permissions").
+		Column("role_id", "permission_id").
+		Where("role_id IN (?)", bun.In(roleIDs)).
+		Scan(ctx, &grants)
+	if err != nil {
+		return fmt.Errorf("failed to get effective role permissions: %w", err)
+	}
+	if len(grants) == 0 {
+		return nil
+	}
+
+	holders := make(map[uuid.UUID][]int)
+	permissionIDs := make([]uuid.UUID, 0, len(grants))
+	for _, grant := range grants {
+		if _, seen := holders[grant.PermissionID]; !seen {
+			permissionIDs = append(permissionIDs, grant.PermissionID)
+		}
+		holders[grant.PermissionID] = append(holders[grant.PermissionID], index[grant.RoleID])
+	}
+
+	permissions := make([]models.Permission, 0, len(permissionIDs))
+	err = r.db.NewSelect().
+		Model(&permissions).
+		Where("id IN (?)", bun.In(permissionIDs)).
+		Order("resource", "action").
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get effective permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		for _, i := range holders[permission.ID] {
+			roles[i].Permissions = append(roles[i].Permissions, permission)
+		}
+	}
+
+	return nil
+}
+
+// ============================================================
+// Permission Bundle Methods
+// ============================================================
+
+// CreatePermissionBundle creates a new permission bundle
+func (r *RBACRepository) CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error {
+	_, err := r.db.NewInsert().
+		Model(bundle).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// GetPermissionBundleByID retrieves a permission bundle by ID with its permissions
+func (r *RBACRepository) GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	bundle := new(models.PermissionBundle)
+
+	err := r.db.NewSelect().
+		Model(bundle).
+		Where("id = ?", id).
+		Relation("Permissions").
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("permission bundle not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permission bundle by id: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// GetPermissionBundleByName retrieves a permission bundle by name
+func (r *RBACRepository) GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error) {
+	bundle := new(models.PermissionBundle)
+
+	err := r.db.NewSelect().
+		Model(bundle).
+		Where("name = ?", name).
+		Relation("Permissions").
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("permission bundle not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permission bundle by name: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// ListPermissionBundles retrieves all permission bundles with their permissions
+func (r *RBACRepository) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	bundles := make([]models.PermissionBundle, 0)
+
+	err := r.db.NewSelect().
+		Model(&bundles).
+		Relation("Permissions").
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission bundles: %w", err)
+	}
+
+	return bundles, nil
+}
+
+// UpdatePermissionBundle updates a permission bundle
+func (r *RBACRepository) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.PermissionBundle)(nil)).
+		Set("display_name = ?", displayName).
+		Set("description = ?", description).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update permission bundle: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("permission bundle not found")
+	}
+
+	return nil
+}
+
+// DeletePermissionBundle deletes a permission bundle, removing it from every role
+func (r *RBACRepository) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.PermissionBundle)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete permission bundle: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("permission bundle not found")
+	}
+
+	return nil
+}
+
+// SetBundlePermissions sets all permissions of a bundle (replaces existing)
+func (r *RBACRepository) SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.BundlePermission)(nil)).
+			Where("bundle_id = ?", bundleID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing bundle permissions: %w", err)
+		}
+
+		if len(permissionIDs) > 0 {
+			bundlePermissions := make([]*models.BundlePermission, len(permissionIDs))
+			for i, permID := range permissionIDs {
+				bundlePermissions[i] = &models.BundlePermission{
+					BundleID:     bundleID,
+					PermissionID: permID,
+				}
+			}
+
+			_, err = tx.NewInsert().
+				Model(&bundlePermissions).
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to insert bundle permissions: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // ============================================================
 // User Permission Checking
 // ============================================================
 
-// GetUserPermissions retrieves all permissions for a user based on their roles
+// GetUserPermissions retrieves all permissions for a user based on their roles, including
+// those inherited from parent roles and granted through bundles
 func (r *RBACRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]models.Permission, error) {
 	permissions := make([]models.Permission, 0)
 
 	err := r.db.NewSelect().
 		Model(&permissions).
 		Distinct().
-		Join("INNER JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("INNER JOIN effective_role_permissions AS rp ON rp.permission_id = permission.id").
 		Join("INNER JOIN roles AS r ON r.id = rp.role_id").
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = r.id").
 		Join("INNER JOIN users AS u ON u.id = ur.user_id").
@@ -390,16 +783,19 @@ func (r *RBACRepository) GetUserPermissions(ctx context.Context, userID uuid.UUI
 	return permissions, nil
 }
 
-// HasPermission checks if a user has a specific permission
+// HasPermission checks if a user has a specific permission, through any of the effective
+// permissions of their roles. Conditional permissions don't count: only the gRPC
+// CheckPermission has the attributes to evaluate their conditions.
 func (r *RBACRepository) HasPermission(ctx context.Context, userID uuid.UUID, permissionName string) (bool, error) {
 	count, err := r.db.NewSelect().
 		Model((*models.Permission)(nil)).
-		Join("INNER JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("INNER JOIN effective_role_permissions AS rp ON rp.permission_id = permission.id").
 		Join("INNER JOIN roles AS r ON r.id = rp.role_id").
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = r.id").
 		Join("INNER JOIN users AS u ON u.id = ur.user_id").
 		Where("u.id = ?", userID).
 		Where("permission.name = ?", permissionName).
+		Where("permission.condition IS NULL").
 		Count(ctx)
 
 	if err != nil {
@@ -413,12 +809,13 @@ func (r *RBACRepository) HasPermission(ctx context.Context, userID uuid.UUID, pe
 func (r *RBACRepository) HasAnyPermission(ctx context.Context, userID uuid.UUID, permissionNames []string) (bool, error) {
 	count, err := r.db.NewSelect().
 		Model((*models.Permission)(nil)).
-		Join("INNER JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("INNER JOIN effective_role_permissions AS rp ON rp.permission_id = permission.id").
 		Join("INNER JOIN roles AS r ON r.id = rp.role_id").
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = r.id").
 		Join("INNER JOIN users AS u ON u.id = ur.user_id").
 		Where("u.id = ?", userID).
 		Where("permission.name IN (?)", bun.In(permissionNames)).
+		Where("permission.condition IS NULL").
 		Count(ctx)
 
 	if err != nil {
@@ -433,12 +830,13 @@ func (r *RBACRepository) HasAllPermissions(ctx context.Context, userID uuid.UUID
 	count, err := r.db.NewSelect().
 		Model((*models.Permission)(nil)).
 		ColumnExpr("COUNT(DISTINCT permission.name)").
-		Join("INNER JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("INNER JOIN effective_role_permissions AS rp ON rp.permission_id = permission.id").
 		Join("INNER JOIN roles AS r ON r.id = rp.role_id").
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = r.id").
 		Join("INNER JOIN users AS u ON u.id = ur.user_id").
 		Where("u.id = ?", userID).
 		Where("permission.name IN (?)", bun.In(permissionNames)).
+		Where("permission.condition IS NULL").
 		Count(ctx)
 
 	if err != nil {
@@ -452,20 +850,22 @@ func (r *RBACRepository) HasAllPermissions(ctx context.Context, userID uuid.UUID
 // User-Role Management Methods
 // ============================================================
 
-// GetUserRoles returns all roles assigned to a user with their permissions
+// GetUserRoles returns all roles assigned to a user with their effective permissions
 func (r *RBACRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
 	var roles []models.Role
 	err := r.db.NewSelect().
 		Model(&roles).
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = role.id").
 		Where("ur.user_id = ?", userID).
-		Relation("Permissions").
 		Order("role.name").
 		Scan(ctx)
 
 	if err != nil {
 		return nil, handlePgError(err)
 	}
+	if err := r.loadEffectivePermissions(ctx, roles); err != nil {
+		return nil, err
+	}
 	return roles, nil
 }
 
@@ -479,10 +879,14 @@ func (r *RBACRepository) AssignRoleToUser(ctx context.Context, userID, roleID, a
 
 	_, err := r.db.NewInsert().
 		Model(userRole).
-		On("CONFLICT (user_id, role_id) DO NOTHING").
+		On("CONFLICT (user_id, role_id) DO UPDATE SET granted_by_group = false").
 		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
 
-	return handlePgError(err)
+	r.db.userChanged(ctx, userID)
+	return nil
 }
 
 // AssignRoleToUserWithTx assigns a role to a user within a transaction
@@ -495,10 +899,14 @@ func (r *RBACRepository) AssignRoleToUserWithTx(ctx context.Context, tx bun.Tx,
 
 	_, err := tx.NewInsert().
 		Model(userRole).
-		On("CONFLICT (user_id, role_id) DO NOTHING").
+		On("CONFLICT (user_id, role_id) DO UPDATE SET granted_by_group = false").
 		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
 
-	return handlePgError(err)
+	r.db.userChanged(ctx, userID)
+	return nil
 }
 
 // RemoveRoleFromUser removes a role from a user
@@ -507,17 +915,23 @@ func (r *RBACRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID
 		Model((*models.UserRole)(nil)).
 		Where("user_id = ? AND role_id = ?", userID, roleID).
 		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
 
-	return handlePgError(err)
+	r.db.userChanged(ctx, userID)
+	return nil
 }
 
-// SetUserRoles atomically replaces all user roles (transaction)
+// SetUserRoles atomically replaces all directly assigned user roles (transaction).
+// Roles granted through group membership are kept.
 func (r *RBACRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy uuid.UUID) error {
-	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
-		// Delete all existing roles for this user
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		// Delete all directly assigned roles for this user
 		_, err := tx.NewDelete().
 			Model((*models.UserRole)(nil)).
 			Where("user_id = ?", userID).
+			Where("granted_by_group = ?", false).
 			Exec(ctx)
 		if err != nil {
 			return handlePgError(err)
@@ -536,6 +950,7 @@ func (r *RBACRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, rol
 
 			_, err = tx.NewInsert().
 				Model(&userRoles).
+				On("CONFLICT (user_id, role_id) DO UPDATE SET granted_by_group = false").
 				Exec(ctx)
 			if err != nil {
 				return handlePgError(err)
@@ -544,6 +959,12 @@ func (r *RBACRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, rol
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
 }
 
 // GetUsersWithRole returns all users with a specific role
@@ -662,6 +1083,9 @@ func (r *RBACRepository) ListRolesByApp(ctx context.Context, appID *uuid.UUID) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to list roles by app: %w", err)
 	}
+	if err := r.loadRoleLinks(ctx, roles); err != nil {
+		return nil, err
+	}
 
 	return roles, nil
 }
@@ -691,11 +1115,12 @@ func (r *RBACRepository) ListPermissionsByApp(ctx context.Context, appID *uuid.U
 func (r *RBACRepository) HasPermissionInApp(ctx context.Context, userID uuid.UUID, permissionName string, appID *uuid.UUID) (bool, error) {
 	query := r.db.NewSelect().
 		Model((*models.Permission)(nil)).
-		Join("INNER JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("INNER JOIN effective_role_permissions AS rp ON rp.permission_id = permission.id").
 		Join("INNER JOIN roles AS r ON r.id = rp.role_id").
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = r.id").
 		Where("ur.user_id = ?", userID).
-		Where("permission.name = ?", permissionName)
+		Where("permission.name = ?", permissionName).
+		Where("permission.condition IS NULL")
 
 	if appID != nil {
 		query = query.Where("ur.application_id = ?", *appID)
@@ -711,15 +1136,15 @@ func (r *RBACRepository) HasPermissionInApp(ctx context.Context, userID uuid.UUI
 	return count > 0, nil
 }
 
-// GetUserRolesInApp returns roles assigned to a user within an application
+// GetUserRolesInApp returns roles assigned to a user within an application, with their
+// effective permissions
 func (r *RBACRepository) GetUserRolesInApp(ctx context.Context, userID uuid.UUID, appID *uuid.UUID) ([]models.Role, error) {
 	var roles []models.Role
 
 	query := r.db.NewSelect().
 		Model(&roles).
 		Join("INNER JOIN user_roles AS ur ON ur.role_id = role.id").
-		Where("ur.user_id = ?", userID).
-		Relation("Permissions")
+		Where("ur.user_id = ?", userID)
 
 	if appID != nil {
 		query = query.Where("ur.application_id = ?", *appID)
@@ -731,6 +1156,9 @@ func (r *RBACRepository) GetUserRolesInApp(ctx context.Context, userID uuid.UUID
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user roles in app: %w", err)
 	}
+	if err := r.loadEffectivePermissions(ctx, roles); err != nil {
+		return nil, err
+	}
 
 	return roles, nil
 }
@@ -748,6 +1176,10 @@ func (r *RBACRepository) AssignRoleToUserInApp(ctx context.Context, userID, role
 		Model(userRole).
 		On("CONFLICT (user_id, role_id) DO NOTHING").
 		Exec(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
 
-	return handlePgError(err)
+	r.db.userChanged(ctx, userID)
+	return nil
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// anonymizedEmailPattern matches the addresses Anonymize gives scrubbed accounts
+const anonymizedEmailPattern = "%@anonymized.invalid"
+
+// RetentionRepository handles data retention database operations
+type RetentionRepository struct {
+	db *Database
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *Database) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// DeleteAuditLogsBefore deletes up to limit audit log entries created before the cutoff and
+// returns how many were deleted
+func (r *RetentionRepository) DeleteAuditLogsBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	subquery := r.db.NewSelect().
+		Model((*models.AuditLog)(nil)).
+		Column("id").
+		Where("created_at < ?", before).
+		Limit(limit)
+
+	result, err := r.db.NewDelete().
+		Model((*models.AuditLog)(nil)).
+		Where("id IN (?)", subquery).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old audit logs: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// DeleteSessionsEndedBefore deletes up to limit sessions that expired or were revoked before
+// the cutoff and returns how many were deleted. Active sessions are never touched.
+func (r *RetentionRepository) DeleteSessionsEndedBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	subquery := r.db.NewSelect().
+		Model((*models.Session)(nil)).
+		Column("id").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				WhereOr("expires_at < ?", before).
+				WhereOr("revoked_at < ?", before)
+		}).
+		Limit(limit)
+
+	result, err := r.db.NewDelete().
+		Model((*models.Session)(nil)).
+		Where("id IN (?)", subquery).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete ended sessions: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// ListInactiveAccounts returns the IDs of accounts without activity since cutoff whose personal
+// data was not erased yet, ordered by ID and starting after afterID. Guests, service accounts
+// and administrators are never returned.
+func (r *RetentionRepository) ListInactiveAccounts(ctx context.Context, cutoff time.Time, afterID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+
+	err := r.db.NewSelect().
+		TableExpr("users AS u").
+		Column("u.id").
+		Where("u.email NOT LIKE ?", anonymizedEmailPattern).
+		Where("u.is_guest = FALSE").
+		Where("u.account_type IS DISTINCT FROM ?", string(models.AccountTypeService)).
+		Where(dormantLastActiveExpr+" < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM user_roles ur JOIN roles ro ON ro.id = ur.role_id WHERE ur.user_id = u.id AND ro.name = ?)", string(models.RoleAdmin)).
+		Where("u.id > ?", afterID).
+		OrderExpr("u.id ASC").
+		Limit(limit).
+		Scan(ctx, &ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive accounts: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ScrubUserActivity removes the personal data the gateway recorded about a user's activity.
+// Audit log entries are kept so the trail of what happened stays intact, but lose their IP
+// address, user agent, location and details; SMS logs and data exports are deleted and
+// account recovery requests lose their free-text fields. It returns how many audit log
+// entries were scrubbed.
+func (r *RetentionRepository) ScrubUserActivity(ctx context.Context, userID uuid.UUID) (int, error) {
+	var scrubbed int
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model((*models.AuditLog)(nil)).
+			Set("ip_address = ''").
+			Set("user_agent = ''").
+			Set("details = NULL").
+			Set("country_code = ''").
+			Set("country_name = ''").
+			Set("city = ''").
+			Set("latitude = 0").
+			Set("longitude = 0").
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to scrub audit logs: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		scrubbed = int(rows)
+
+		if _, err := tx.NewDelete().Model((*models.SMSLog)(nil)).Where("user_id = ?", userID).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete SMS logs: %w", err)
+		}
+		if _, err := tx.NewDelete().Model((*models.UserDataExport)(nil)).Where("user_id = ?", userID).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete data exports: %w", err)
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.AccountRecoveryRequest)(nil)).
+			Set("reason = ''").
+			Set("ip_address = ''").
+			Set("user_agent = ''").
+			Set("review_note = ''").
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to scrub account recovery requests: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return scrubbed, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// RiskPolicyRepository handles risk policy database operations
+type RiskPolicyRepository struct {
+	db *Database
+}
+
+// NewRiskPolicyRepository creates a new risk policy repository
+func NewRiskPolicyRepository(db *Database) *RiskPolicyRepository {
+	return &RiskPolicyRepository{db: db}
+}
+
+// CreateRiskPolicy creates a risk policy
+func (r *RiskPolicyRepository) CreateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error {
+	_, err := r.db.NewInsert().
+		Model(policy).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// GetRiskPolicyByID retrieves a risk policy by ID
+func (r *RiskPolicyRepository) GetRiskPolicyByID(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error) {
+	policy := new(models.RiskPolicy)
+
+	err := r.db.NewSelect().
+		Model(policy).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+
+	return policy, nil
+}
+
+// GetActiveRiskPolicy returns the active policy of the application, or the active global
+// policy when the application has none. It returns models.ErrNotFound when neither exists.
+func (r *RiskPolicyRepository) GetActiveRiskPolicy(ctx context.Context, appID *uuid.UUID) (*models.RiskPolicy, error) {
+	policy := new(models.RiskPolicy)
+
+	query := r.db.NewSelect().
+		Model(policy).
+		Where("is_active = true").
+		Limit(1)
+
+	if appID != nil {
+		// Application policies sort before the global one
+		query = query.
+			Where("application_id = ? OR application_id IS NULL", *appID).
+			OrderExpr("application_id IS NULL")
+	} else {
+		query = query.Where("application_id IS NULL")
+	}
+
+	err := query.Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// ListRiskPolicies lists all risk policies by name
+func (r *RiskPolicyRepository) ListRiskPolicies(ctx context.Context) ([]*models.RiskPolicy, error) {
+	policies := make([]*models.RiskPolicy, 0)
+
+	err := r.db.NewSelect().
+		Model(&policies).
+		Order("name").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// UpdateRiskPolicy saves the editable fields of a risk policy
+func (r *RiskPolicyRepository) UpdateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	result, err := r.db.NewUpdate().
+		Model(policy).
+		Column("description", "weights", "step_up_threshold", "block_threshold", "step_up_fallback",
+			"max_travel_speed_kmh", "velocity_max_attempts", "velocity_window_seconds", "is_active", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update risk policy: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteRiskPolicy deletes a risk policy
+func (r *RiskPolicyRepository) DeleteRiskPolicy(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewDelete().
+		Model((*models.RiskPolicy)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete risk policy: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -55,11 +55,16 @@ func (r *SessionRepository) GetSessionByID(ctx context.Context, id uuid.UUID) (*
 func (r *SessionRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
 	session := new(models.Session)
 
-	err := r.db.NewSelect().
-		Model(session).
-		Where("token_hash = ?", tokenHash).
-		Where("revoked_at IS NULL").
-		Scan(ctx)
+	var err error
+	if r.db.prepared != nil {
+		session, err = r.db.prepared.SessionByTokenHash(ctx, tokenHash)
+	} else {
+		err = r.db.NewSelect().
+			Model(session).
+			Where("token_hash = ?", tokenHash).
+			Where("revoked_at IS NULL").
+			Scan(ctx)
+	}
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("session not found or revoked")
@@ -478,3 +483,55 @@ func (r *SessionRepository) GetAppSessionsPaginated(ctx context.Context, appID u
 
 	return sessions, total, nil
 }
+
+// ArchiveSessions inserts copies of sessions kept in Redis, updating the ones already archived.
+// Sessions of users deleted meanwhile are skipped.
+func (r *SessionRepository) ArchiveSessions(ctx context.Context, sessions []models.Session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		userIDs = append(userIDs, session.UserID)
+	}
+	var existing []uuid.UUID
+	err := r.db.NewSelect().
+		Model((*models.User)(nil)).
+		Column("id").
+		Where("id IN (?)", bun.In(userIDs)).
+		Scan(ctx, &existing)
+	if err != nil {
+		return fmt.Errorf("failed to check session users: %w", err)
+	}
+	known := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+	archived := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if known[session.UserID] {
+			archived = append(archived, session)
+		}
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+
+	_, err = r.db.NewInsert().
+		Model(&archived).
+		On("CONFLICT (id) DO UPDATE").
+		Set("token_hash = EXCLUDED.token_hash").
+		Set("access_token_hash = EXCLUDED.access_token_hash").
+		Set("session_name = EXCLUDED.session_name").
+		Set("last_active_at = EXCLUDED.last_active_at").
+		Set("expires_at = EXCLUDED.expires_at").
+		Set("revoked_at = EXCLUDED.revoked_at").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to archive sessions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// signingKeyRotationLock is the advisory lock replicas take to change the stored signing
+// keys, so only one of them generates a key when a rotation is due
+const signingKeyRotationLock = 7262001
+
+// SigningKeyRepository stores the OIDC signing keys of automatic key rotation
+type SigningKeyRepository struct {
+	db *Database
+}
+
+// NewSigningKeyRepository creates a new signing key repository
+func NewSigningKeyRepository(db *Database) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// List returns the stored keys, oldest activation first
+func (r *SigningKeyRepository) List(ctx context.Context) ([]*models.StoredSigningKey, error) {
+	var keys []*models.StoredSigningKey
+	err := r.db.NewSelect().
+		Model(&keys).
+		Order("activates_at ASC", "kid ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Update passes the stored keys to fn and then adds and deletes the keys it returns, all
+// in one transaction holding an advisory lock: replicas updating at the same time wait and
+// see the keys the first one added.
+func (r *SigningKeyRepository) Update(ctx context.Context, fn func(keys []*models.StoredSigningKey) (add []*models.StoredSigningKey, remove []string, err error)) error {
+	return r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", signingKeyRotationLock); err != nil {
+			return fmt.Errorf("failed to lock signing keys: %w", err)
+		}
+
+		var keys []*models.StoredSigningKey
+		err := tx.NewSelect().
+			Model(&keys).
+			Order("activates_at ASC", "kid ASC").
+			Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list signing keys: %w", err)
+		}
+
+		add, remove, err := fn(keys)
+		if err != nil {
+			return err
+		}
+
+		if len(add) > 0 {
+			if _, err := tx.NewInsert().Model(&add).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to store signing keys: %w", err)
+			}
+		}
+		if len(remove) > 0 {
+			_, err := tx.NewDelete().
+				Model((*models.StoredSigningKey)(nil)).
+				Where("kid IN (?)", bun.In(remove)).
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to delete signing keys: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -84,6 +84,56 @@ func (r *SMSLogRepository) UpdateStatus(ctx context.Context, id uuid.UUID, statu
 	return nil
 }
 
+// MarkSent records that the provider accepted a message, along with the ID the provider
+// assigned to it
+func (r *SMSLogRepository) MarkSent(ctx context.Context, id uuid.UUID, messageID string) error {
+	query := r.db.NewUpdate().
+		Model((*models.SMSLog)(nil)).
+		Set("status = ?", models.SMSStatusSent).
+		Set("sent_at = ?", time.Now()).
+		Where("id = ?", id)
+
+	if messageID != "" {
+		query = query.Set("message_id = ?", messageID)
+	}
+
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to mark SMS log as sent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByMessageID retrieves an SMS log by the ID the provider assigned to the message
+func (r *SMSLogRepository) GetByMessageID(ctx context.Context, provider, messageID string) (*models.SMSLog, error) {
+	log := new(models.SMSLog)
+
+	err := r.db.NewSelect().
+		Model(log).
+		Where("provider = ?", provider).
+		Where("message_id = ?", messageID).
+		Scan(ctx)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get SMS log by message ID: %w", err)
+	}
+
+	return log, nil
+}
+
 // GetByPhone retrieves SMS logs for a phone number
 func (r *SMSLogRepository) GetByPhone(ctx context.Context, phone string, limit int) ([]*models.SMSLog, error) {
 	logs := make([]*models.SMSLog, 0)
@@ -40,11 +40,16 @@ func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token *models.
 func (r *TokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	token := new(models.RefreshToken)
 
-	err := r.db.NewSelect().
-		Model(token).
-		Where("token_hash = ?", tokenHash).
-		Where("revoked_at IS NULL").
-		Scan(ctx)
+	var err error
+	if r.db.prepared != nil {
+		token, err = r.db.prepared.RefreshTokenByHash(ctx, tokenHash)
+	} else {
+		err = r.db.NewSelect().
+			Model(token).
+			Where("token_hash = ?", tokenHash).
+			Where("revoked_at IS NULL").
+			Scan(ctx)
+	}
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrInvalidToken
@@ -207,11 +212,17 @@ func (r *TokenRepository) GetAllActiveBlacklistEntries(ctx context.Context) ([]*
 
 // IsBlacklisted checks if a token is blacklisted
 func (r *TokenRepository) IsBlacklisted(ctx context.Context, tokenHash string) (bool, error) {
-	exists, err := r.db.NewSelect().
-		Model((*models.TokenBlacklist)(nil)).
-		Where("token_hash = ?", tokenHash).
-		Where("expires_at > ?", bun.Safe("CURRENT_TIMESTAMP")).
-		Exists(ctx)
+	var exists bool
+	var err error
+	if r.db.prepared != nil {
+		exists, err = r.db.prepared.IsBlacklisted(ctx, tokenHash)
+	} else {
+		exists, err = r.db.NewSelect().
+			Model((*models.TokenBlacklist)(nil)).
+			Where("token_hash = ?", tokenHash).
+			Where("expires_at > ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Exists(ctx)
+	}
 
 	if err != nil {
 		return false, fmt.Errorf("failed to check token blacklist: %w", err)
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// orgUsageCountsSQL counts the members of a group and its child groups. Seats, active users
+// and MFA adoption only consider active accounts; a user is active if they signed in or used
+// a session since the given time.
+const orgUsageCountsSQL = subtreeGroupsCTE + `,
+	members AS (
+		SELECT DISTINCT ug.user_id FROM user_groups AS ug
+		WHERE ug.group_id IN (SELECT id FROM subtree)
+	)
+	SELECT
+		COUNT(*) AS total_members,
+		COUNT(*) FILTER (WHERE u.is_active) AS seats_used,
+		COUNT(*) FILTER (WHERE u.is_active AND (
+			EXISTS (SELECT 1 FROM sessions AS s WHERE s.user_id = u.id AND s.last_active_at >= ?)
+			OR EXISTS (
+				SELECT 1 FROM audit_logs AS a
+				WHERE a.user_id = u.id AND a.action = 'signin' AND a.status = 'success' AND a.created_at >= ?
+			)
+		)) AS active_users,
+		COUNT(*) FILTER (WHERE u.is_active AND u.totp_enabled) AS mfa_enabled
+	FROM members AS m
+	INNER JOIN users AS u ON u.id = m.user_id`
+
+// UsageReportRepository handles organization usage report database operations
+type UsageReportRepository struct {
+	db *Database
+}
+
+// NewUsageReportRepository creates a new usage report repository
+func NewUsageReportRepository(db *Database) *UsageReportRepository {
+	return &UsageReportRepository{db: db}
+}
+
+// GetSettings returns the usage report settings of a group
+func (r *UsageReportRepository) GetSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error) {
+	settings := new(models.OrgUsageReportSettings)
+	err := r.db.NewSelect().
+		Model(settings).
+		Where("urs.group_id = ?", groupID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage report settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpsertSettings creates or replaces the usage report settings of a group
+func (r *UsageReportRepository) UpsertSettings(ctx context.Context, settings *models.OrgUsageReportSettings) error {
+	settings.UpdatedAt = time.Now()
+	_, err := r.db.NewInsert().
+		Model(settings).
+		On("CONFLICT (group_id) DO UPDATE").
+		Set("licensed_seats = EXCLUDED.licensed_seats").
+		Set("recipients = EXCLUDED.recipients").
+		Set("frequency = EXCLUDED.frequency").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// ListSettings returns the usage report settings of every group that has them
+func (r *UsageReportRepository) ListSettings(ctx context.Context) ([]*models.OrgUsageReportSettings, error) {
+	settings := make([]*models.OrgUsageReportSettings, 0)
+	err := r.db.NewSelect().
+		Model(&settings).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage report settings: %w", err)
+	}
+	return settings, nil
+}
+
+// MarkSent records when the usage report of a group was last delivered
+func (r *UsageReportRepository) MarkSent(ctx context.Context, groupID uuid.UUID, sentAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OrgUsageReportSettings)(nil)).
+		Set("last_sent_at = ?", sentAt).
+		Where("group_id = ?", groupID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark usage report sent: %w", err)
+	}
+	return nil
+}
+
+// GetUsageCounts counts the members, used seats, active users and MFA-enabled users of a
+// group and its child groups
+func (r *UsageReportRepository) GetUsageCounts(ctx context.Context, groupID uuid.UUID, activeSince time.Time) (*models.OrgUsageCounts, error) {
+	counts := new(models.OrgUsageCounts)
+	err := r.db.NewRaw(orgUsageCountsSQL, groupID, activeSince, activeSince).
+		Scan(ctx, counts)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count organization usage: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// UserEmailRepository handles secondary email address database operations
+type UserEmailRepository struct {
+	db *Database
+}
+
+// NewUserEmailRepository creates a new user email repository
+func NewUserEmailRepository(db *Database) *UserEmailRepository {
+	return &UserEmailRepository{db: db}
+}
+
+// ListByUser returns the secondary email addresses of a user, oldest first
+func (r *UserEmailRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error) {
+	emails := make([]*models.UserEmail, 0)
+
+	err := r.db.NewSelect().
+		Model(&emails).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// GetByID retrieves a secondary email address of a user by ID
+func (r *UserEmailRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.UserEmail, error) {
+	email := new(models.UserEmail)
+
+	err := r.db.NewSelect().
+		Model(email).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	return email, nil
+}
+
+// GetByUserAndEmail retrieves a secondary email address of a user by address
+func (r *UserEmailRepository) GetByUserAndEmail(ctx context.Context, userID uuid.UUID, address string) (*models.UserEmail, error) {
+	email := new(models.UserEmail)
+
+	err := r.db.NewSelect().
+		Model(email).
+		Where("user_id = ?", userID).
+		Where("email = ?", address).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	return email, nil
+}
+
+// Create adds an unverified secondary email address
+func (r *UserEmailRepository) Create(ctx context.Context, email *models.UserEmail) error {
+	_, err := r.db.NewInsert().
+		Model(email).
+		Returning("*").
+		Exec(ctx)
+
+	return handlePgError(err)
+}
+
+// MarkVerified marks a secondary email address as verified
+func (r *UserEmailRepository) MarkVerified(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.UserEmail)(nil)).
+		Set("verified = ?", true).
+		Set("verified_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a secondary email address, clearing it as the recovery address if chosen
+func (r *UserEmailRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		email := new(models.UserEmail)
+		_, err := tx.NewDelete().
+			Model(email).
+			Where("id = ?", id).
+			Where("user_id = ?", userID).
+			Returning("email").
+			Exec(ctx, email)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete user email: %w", err)
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.User)(nil)).
+			Set("recovery_email = ?", nil).
+			Set("recovery_email_verified = ?", false).
+			Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Where("id = ?", userID).
+			Where("recovery_email = ?", email.Email).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to clear recovery email: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// SetPrimary swaps a verified secondary email address with the user's primary address.
+// The old primary address is kept as a secondary address with its verification state.
+func (r *UserEmailRepository) SetPrimary(ctx context.Context, userID, id uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		user := new(models.User)
+		err := tx.NewSelect().
+			Model(user).
+			Column("id", "email", "email_verified", "recovery_email").
+			Where("id = ?", userID).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		email := new(models.UserEmail)
+		_, err = tx.NewDelete().
+			Model(email).
+			Where("id = ?", id).
+			Where("user_id = ?", userID).
+			Where("verified").
+			Returning("email").
+			Exec(ctx, email)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete user email: %w", err)
+		}
+
+		previous := &models.UserEmail{
+			UserID:   userID,
+			Email:    user.Email,
+			Verified: user.EmailVerified,
+		}
+		if previous.Verified {
+			previous.VerifiedAt = user.EmailVerifiedAt
+		}
+		if _, err := tx.NewInsert().Model(previous).Exec(ctx); err != nil {
+			return handlePgError(err)
+		}
+
+		update := tx.NewUpdate().
+			Model((*models.User)(nil)).
+			Set("email = ?", email.Email).
+			Set("email_verified = ?", true).
+			Set("email_verified_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+			Where("id = ?", userID)
+
+		// The recovery address has to be a secondary address
+		if user.RecoveryEmail != nil && *user.RecoveryEmail == email.Email {
+			update = update.
+				Set("recovery_email = ?", nil).
+				Set("recovery_email_verified = ?", false)
+		}
+
+		if _, err := update.Exec(ctx); err != nil {
+			return handlePgError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// SetRecoveryEmail chooses the address account recovery messages go to, or clears it when nil.
+// Callers must only pass verified addresses of the user.
+func (r *UserEmailRepository) SetRecoveryEmail(ctx context.Context, userID uuid.UUID, email *string) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("recovery_email = ?", email).
+		Set("recovery_email_verified = ?", email != nil).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to set recovery email: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrUserNotFound
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
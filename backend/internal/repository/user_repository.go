@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -48,18 +49,23 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID, isActive *bo
 	o := queryopt.BuildUserGetOptions(opts)
 	user := new(models.User)
 
-	query := r.db.NewSelect().
-		Model(user).
-		Where("id = ?", id)
+	var err error
+	if r.db.prepared != nil {
+		user, err = r.db.prepared.UserByID(ctx, id, isActive, o.WithRoles)
+	} else {
+		query := r.db.NewSelect().
+			Model(user).
+			Where("id = ?", id)
 
-	if isActive != nil {
-		query = query.Where("is_active = ?", *isActive)
-	}
-	if o.WithRoles {
-		query = query.Relation("Roles")
-	}
+		if isActive != nil {
+			query = query.Where("is_active = ?", *isActive)
+		}
+		if o.WithRoles {
+			query = query.Relation("Roles")
+		}
 
-	err := query.Scan(ctx)
+		err = query.Scan(ctx)
+	}
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrUserNotFound
@@ -71,23 +77,35 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID, isActive *bo
 	return user, nil
 }
 
-// GetByEmail retrieves a user by email
+// GetByEmail retrieves a user by primary email or by one of their verified secondary emails
 func (r *UserRepository) GetByEmail(ctx context.Context, email string, isActive *bool, opts ...queryopt.UserGetOption) (*models.User, error) {
 	o := queryopt.BuildUserGetOptions(opts)
 	user := new(models.User)
 
-	query := r.db.NewSelect().
-		Model(user).
-		Where("email = ?", email)
-
-	if isActive != nil {
-		query = query.Where("is_active = ?", *isActive)
+	var err error
+	if r.db.prepared != nil {
+		user, err = r.db.prepared.UserByEmail(ctx, email, isActive, o.WithRoles)
+	} else {
+		query := r.db.NewSelect().
+			Model(user).
+			WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.
+					Where("?TableAlias.email = ?", email).
+					WhereOr("?TableAlias.id IN (SELECT user_id FROM user_emails WHERE email = ? AND verified)", email)
+			}).
+			// Prefer the account whose primary address matches
+			OrderExpr("?TableAlias.email = ? DESC", email).
+			Limit(1)
+
+		if isActive != nil {
+			query = query.Where("is_active = ?", *isActive)
+		}
+		if o.WithRoles {
+			query = query.Relation("Roles")
+		}
+
+		err = query.Scan(ctx)
 	}
-	if o.WithRoles {
-		query = query.Relation("Roles")
-	}
-
-	err := query.Scan(ctx)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, models.ErrUserNotFound
@@ -131,7 +149,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string, isA
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	result, err := r.db.NewUpdate().
 		Model(user).
-		Column("email", "username", "full_name", "phone", "profile_picture_url", "email_verified", "updated_at", "is_active").
+		Column("email", "username", "full_name", "phone", "profile_picture_url", "email_verified", "updated_at", "is_active", "state", "state_changed_at").
 		WherePK().
 		Returning("updated_at").
 		Exec(ctx)
@@ -149,14 +167,19 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, user.ID)
 	return nil
 }
 
-// UpdatePassword updates a user's password
+// UpdatePassword updates a user's password and restarts its expiry clock
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
 	result, err := r.db.NewUpdate().
 		Model((*models.User)(nil)).
 		Set("password_hash = ?", passwordHash).
+		Set("password_changed_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Set("password_expires_at = NULL").
+		Set("password_expiry_warned_at = NULL").
+		Set("must_change_password = FALSE").
 		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
 		Where("id = ?", userID).
 		Exec(ctx)
@@ -174,14 +197,57 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, p
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, userID)
 	return nil
 }
 
-// Delete soft deletes a user (sets is_active to false)
+// GetTokenVersion returns a user's current token version
+func (r *UserRepository) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.NewSelect().
+		Model((*models.User)(nil)).
+		Column("token_version").
+		Where("id = ?", userID).
+		Scan(ctx, &version)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token version: %w", err)
+	}
+
+	return version, nil
+}
+
+// IncrementTokenVersion bumps a user's token version and returns the new value
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("token_version = token_version + 1").
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", userID).
+		Returning("token_version").
+		Scan(ctx, &version)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment token version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Delete soft deletes a user (moves it to the deleted state and clears is_active)
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result, err := r.db.NewUpdate().
 		Model((*models.User)(nil)).
 		Set("is_active = ?", false).
+		Set("state = ?", models.UserStateDeleted).
+		Set("state_changed_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
 		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
 		Where("id = ?", id).
 		Exec(ctx)
@@ -199,14 +265,62 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, id)
 	return nil
 }
 
-// EmailExists checks if an email already exists
+// UpdateState moves a user from one lifecycle state to another. The update only applies while
+// the user is still in from, so two concurrent transitions cannot both succeed.
+func (r *UserRepository) UpdateState(ctx context.Context, id uuid.UUID, from, to models.UserState) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("state = ?", to).
+		Set("is_active = ?", to == models.UserStateActive).
+		Set("state_changed_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Where("state = ?", from).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update user state: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return models.ErrUserStateConflict
+	}
+
+	r.db.userChanged(ctx, id)
+	return nil
+}
+
+// TouchLastLogin records a successful sign-in
+func (r *UserRepository) TouchLastLogin(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("last_login_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+
+	r.db.userChanged(ctx, id)
+	return nil
+}
+
+// EmailExists checks if an email is already used as a primary or verified secondary email
 func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	exists, err := r.db.NewSelect().
 		Model((*models.User)(nil)).
 		Where("email = ?", email).
+		WhereOr("id IN (SELECT user_id FROM user_emails WHERE email = ? AND verified)", email).
 		Exists(ctx)
 
 	if err != nil {
@@ -253,6 +367,7 @@ func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, userID)
 	return nil
 }
 
@@ -350,6 +465,7 @@ func (r *UserRepository) EnableTOTP(ctx context.Context, userID uuid.UUID) error
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, userID)
 	return nil
 }
 
@@ -377,6 +493,7 @@ func (r *UserRepository) DisableTOTP(ctx context.Context, userID uuid.UUID) erro
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, userID)
 	return nil
 }
 
@@ -444,6 +561,7 @@ func (r *UserRepository) MarkPhoneVerified(ctx context.Context, userID uuid.UUID
 		return models.ErrUserNotFound
 	}
 
+	r.db.userChanged(ctx, userID)
 	return nil
 }
 
@@ -487,3 +605,120 @@ func (r *UserRepository) GetUsersUpdatedAfter(ctx context.Context, after time.Ti
 
 	return users, total, nil
 }
+
+// Search finds users whose username, full name or any email address contains the query
+func (r *UserRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+	users := make([]*models.User, 0)
+
+	total, err := r.db.NewSelect().
+		Model(&users).
+		Relation("Roles").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return whereUserMatches(q, query)
+		}).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// whereUserMatches matches users whose username, full name or any email address contains
+// search, case-insensitively; the lowercased columns have trigram indexes
+func whereUserMatches(q *bun.SelectQuery, search string) *bun.SelectQuery {
+	pattern := containsPattern(search)
+	return q.
+		Where(`LOWER(?TableAlias.username) LIKE ? ESCAPE '\'`, pattern).
+		WhereOr(`LOWER(?TableAlias.full_name) LIKE ? ESCAPE '\'`, pattern).
+		WhereOr(`LOWER(?TableAlias.email) LIKE ? ESCAPE '\'`, pattern).
+		WhereOr(`EXISTS (SELECT 1 FROM user_emails ue WHERE ue.user_id = ?TableAlias.id AND LOWER(ue.email) LIKE ? ESCAPE '\')`, pattern)
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern returns a lowercased LIKE pattern matching values that contain search
+// literally; use it with ESCAPE '\'
+func containsPattern(search string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(search)) + "%"
+}
+
+// Filter returns up to limit users matching the filters with their roles, newest first.
+// Users created at the same time are ordered by ID, so a listing can continue after any user.
+func (r *UserRepository) Filter(ctx context.Context, limit, offset int, opts ...queryopt.UserFilterOption) ([]*models.User, error) {
+	o := queryopt.BuildUserFilterOptions(opts)
+	users := make([]*models.User, 0)
+
+	query := applyUserFilters(r.db.NewSelect().Model(&users).Relation("Roles"), o)
+	if o.AfterCreatedAt != nil {
+		query = query.Where("(?TableAlias.created_at, ?TableAlias.id) < (?, ?)", *o.AfterCreatedAt, o.AfterID)
+	}
+
+	err := query.
+		OrderExpr("?TableAlias.created_at DESC, ?TableAlias.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter users: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountFiltered returns the number of users matching the filters, ignoring UserFilterAfter
+func (r *UserRepository) CountFiltered(ctx context.Context, opts ...queryopt.UserFilterOption) (int, error) {
+	o := queryopt.BuildUserFilterOptions(opts)
+
+	count, err := applyUserFilters(r.db.NewSelect().Model((*models.User)(nil)), o).Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
+func applyUserFilters(query *bun.SelectQuery, o queryopt.UserFilterOptions) *bun.SelectQuery {
+	if o.Search != "" {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return whereUserMatches(q, o.Search)
+		})
+	}
+	if o.AppID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM user_application_profiles uap WHERE uap.user_id = ?TableAlias.id AND uap.application_id = ?)", *o.AppID)
+	}
+	if len(o.Roles) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE ur.user_id = ?TableAlias.id AND r.name IN (?))", bun.In(o.Roles))
+	}
+	if len(o.States) > 0 {
+		query = query.Where("?TableAlias.state IN (?)", bun.In(o.States))
+	}
+	if o.EmailVerified != nil {
+		query = query.Where("?TableAlias.email_verified = ?", *o.EmailVerified)
+	}
+	if o.TwoFactor != nil {
+		query = query.Where("(?TableAlias.totp_enabled OR ?TableAlias.webauthn_enabled) = ?", *o.TwoFactor)
+	}
+	if o.CreatedFrom != nil {
+		query = query.Where("?TableAlias.created_at >= ?", *o.CreatedFrom)
+	}
+	if o.CreatedTo != nil {
+		query = query.Where("?TableAlias.created_at < ?", *o.CreatedTo)
+	}
+	for key, value := range o.Metadata {
+		// Values compare as text, so metadata[level]=10 matches the number 10 too
+		profiles := "SELECT 1 FROM user_application_profiles uap WHERE uap.user_id = ?TableAlias.id AND uap.metadata ->> ? = ?"
+		if o.AppID != nil {
+			query = query.Where("EXISTS ("+profiles+" AND uap.application_id = ?)", key, value, *o.AppID)
+		} else {
+			query = query.Where("EXISTS ("+profiles+")", key, value)
+		}
+	}
+	return query
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/internal/queryopt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainsPattern_EscapesWildcards(t *testing.T) {
+	assert.Equal(t, "%john%", containsPattern("John"))
+	assert.Equal(t, `%\_%`, containsPattern("_"))
+	assert.Equal(t, `%50\%\_off\\%`, containsPattern(`50%_OFF\`))
+}
+
+func TestUserRepository_SearchEscapesWildcards(t *testing.T) {
+	db, recorder := setupRecordingDB(t)
+	repo := NewUserRepository(db)
+
+	_, _, err := repo.Search(context.Background(), "_", 10, 0)
+	require.Error(t, err)
+	_, err = repo.Filter(context.Background(), 10, 0, queryopt.UserFilterSearch("_"))
+	require.Error(t, err)
+
+	// Search also runs a count query
+	require.Len(t, recorder.queries, 3)
+	for _, query := range recorder.queries {
+		assert.Contains(t, query, `LIKE '%\_%' ESCAPE '\'`)
+		assert.NotContains(t, query, `LIKE '%_%'`)
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// userTimelineSQL gathers a user's events from the audit log, sessions, OAuth consents and
+// API keys. Role changes are audit log entries, told apart by their action. Sessions, consents
+// and API keys contribute one event per timestamp they carry; an API key only records its
+// last use.
+const userTimelineSQL = `(
+	SELECT
+		CASE WHEN a.action IN (?) THEN 'role' ELSE 'audit' END AS source,
+		a.action AS event, a.id::text AS resource_id, COALESCE(a.status, '') AS status,
+		COALESCE(a.ip_address, '') AS ip_address, a.details AS details, a.created_at AS occurred_at
+	FROM audit_logs AS a
+	WHERE a.user_id = ?
+	UNION ALL
+	SELECT 'session', 'session_started', s.id::text, '', COALESCE(s.ip_address, ''),
+		jsonb_build_object('session_name', s.session_name, 'device_type', s.device_type, 'os', s.os, 'browser', s.browser),
+		s.created_at
+	FROM sessions AS s
+	WHERE s.user_id = ?
+	UNION ALL
+	SELECT 'session', 'session_revoked', s.id::text, '', COALESCE(s.ip_address, ''),
+		jsonb_build_object('session_name', s.session_name), s.revoked_at
+	FROM sessions AS s
+	WHERE s.user_id = ? AND s.revoked_at IS NOT NULL
+	UNION ALL
+	SELECT 'consent', 'consent_granted', uc.id::text, '', '',
+		jsonb_build_object('client_id', uc.client_id, 'client_name', oc.name, 'scopes', uc.scopes), uc.granted_at
+	FROM user_consents AS uc
+	LEFT JOIN oauth_clients AS oc ON oc.id = uc.client_id
+	WHERE uc.user_id = ?
+	UNION ALL
+	SELECT 'consent', 'consent_revoked', uc.id::text, '', '',
+		jsonb_build_object('client_id', uc.client_id, 'client_name', oc.name), uc.revoked_at
+	FROM user_consents AS uc
+	LEFT JOIN oauth_clients AS oc ON oc.id = uc.client_id
+	WHERE uc.user_id = ? AND uc.revoked_at IS NOT NULL
+	UNION ALL
+	SELECT 'api_key', 'api_key_created', k.id::text, '', '',
+		jsonb_build_object('name', k.name, 'key_prefix', k.key_prefix), k.created_at
+	FROM api_keys AS k
+	WHERE k.user_id = ?
+	UNION ALL
+	SELECT 'api_key', 'api_key_used', k.id::text, '', '',
+		jsonb_build_object('name', k.name, 'key_prefix', k.key_prefix), k.last_used_at
+	FROM api_keys AS k
+	WHERE k.user_id = ? AND k.last_used_at IS NOT NULL
+	UNION ALL
+	SELECT 'api_key', 'api_key_revoked', k.id::text, '', '',
+		jsonb_build_object('name', k.name, 'key_prefix', k.key_prefix), k.updated_at
+	FROM api_keys AS k
+	WHERE k.user_id = ? AND NOT k.is_active
+) AS t`
+
+// timelineRoleActions are the audit actions shown as role changes
+var timelineRoleActions = []string{
+	string(models.ActionRoleAssigned),
+	string(models.ActionRoleRevoked),
+	string(models.ActionRolesUpdated),
+}
+
+// UserTimelineRepository reads a user's activity timeline
+type UserTimelineRepository struct {
+	db *Database
+}
+
+// NewUserTimelineRepository creates a new user timeline repository
+func NewUserTimelineRepository(db *Database) *UserTimelineRepository {
+	return &UserTimelineRepository{db: db}
+}
+
+// ListUserTimeline returns a page of the user's events from the given sources, newest first
+func (r *UserTimelineRepository) ListUserTimeline(ctx context.Context, userID uuid.UUID, sources []string, page, pageSize int) ([]*models.UserTimelineEvent, int, error) {
+	events := make([]*models.UserTimelineEvent, 0)
+
+	count, err := r.db.NewSelect().
+		TableExpr(userTimelineSQL, bun.In(timelineRoleActions), userID, userID, userID, userID, userID, userID, userID, userID).
+		ColumnExpr("t.*").
+		Where("t.source IN (?)", bun.In(sources)).
+		OrderExpr("t.occurred_at DESC, t.source, t.resource_id").
+		Limit(pageSize).
+		Offset((page-1)*pageSize).
+		ScanAndCount(ctx, &events)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list user timeline: %w", err)
+	}
+
+	return events, count, nil
+}
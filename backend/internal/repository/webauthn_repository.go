@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// WebAuthnRepository handles WebAuthn credential database operations. It keeps
+// users.webauthn_enabled in step with whether the user has any credential left.
+type WebAuthnRepository struct {
+	db *Database
+}
+
+// NewWebAuthnRepository creates a new WebAuthn credential repository
+func NewWebAuthnRepository(db *Database) *WebAuthnRepository {
+	return &WebAuthnRepository{db: db}
+}
+
+// Create stores a credential and marks its user as having WebAuthn enabled
+func (r *WebAuthnRepository) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(credential).Returning("*").Exec(ctx); err != nil {
+			return handlePgError(err)
+		}
+		return setWebAuthnEnabled(ctx, tx, credential.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, credential.UserID)
+	return nil
+}
+
+// ListByUserID returns the credentials of a user, oldest first
+func (r *WebAuthnRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	credentials := make([]*models.WebAuthnCredential, 0)
+	err := r.db.NewSelect().
+		Model(&credentials).
+		Where("wc.user_id = ?", userID).
+		Order("wc.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+// RecordUse stores the state an assertion left the credential in
+func (r *WebAuthnRepository) RecordUse(ctx context.Context, id uuid.UUID, signCount uint32, backupState bool, usedAt time.Time) error {
+	credential := new(models.WebAuthnCredential)
+	_, err := r.db.NewUpdate().
+		Model(credential).
+		Set("sign_count = ?", signCount).
+		Set("backup_state = ?", backupState).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Returning("user_id").
+		Exec(ctx, credential)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record webauthn credential use: %w", err)
+	}
+
+	r.db.userChanged(ctx, credential.UserID)
+	return nil
+}
+
+// Delete removes a credential of a user
+func (r *WebAuthnRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewDelete().
+			Model((*models.WebAuthnCredential)(nil)).
+			Where("id = ?", id).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete webauthn credential: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return models.ErrNotFound
+		}
+		return setWebAuthnEnabled(ctx, tx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// DeleteAllByUserID removes every credential of a user
+func (r *WebAuthnRepository) DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.WebAuthnCredential)(nil)).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete webauthn credentials: %w", err)
+		}
+		return setWebAuthnEnabled(ctx, tx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.db.userChanged(ctx, userID)
+	return nil
+}
+
+// setWebAuthnEnabled sets users.webauthn_enabled from the credentials the user has
+func setWebAuthnEnabled(ctx context.Context, tx bun.Tx, userID uuid.UUID) error {
+	_, err := tx.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("webauthn_enabled = EXISTS (SELECT 1 FROM webauthn_credentials WHERE user_id = ?)", userID).
+		Set("updated_at = ?", bun.Safe("CURRENT_TIMESTAMP")).
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update user webauthn flag: %w", err)
+	}
+	return nil
+}
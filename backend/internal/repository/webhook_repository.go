@@ -43,7 +43,7 @@ func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*
 		Scan(ctx)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("webhook not found")
+		return nil, models.ErrNotFound
 	}
 
 	return webhook, err
@@ -182,7 +182,7 @@ func (r *WebhookRepository) GetWebhookDeliveryByID(ctx context.Context, id uuid.
 		Scan(ctx)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("webhook delivery not found")
+		return nil, models.ErrNotFound
 	}
 
 	return delivery, err
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+const (
+	awsSecretsManagerService = "secretsmanager"
+	awsDefaultTimeout        = 10 * time.Second
+)
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager through its JSON API,
+// with requests signed by the default credential chain. Paths are secret names or ARNs; the
+// fields of a secret whose value is a JSON object can be referenced on their own.
+type AWSSecretsManagerProvider struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// AWSSecretsManagerConfig holds AWS Secrets Manager configuration
+type AWSSecretsManagerConfig struct {
+	Region string
+	// Endpoint overrides the regional endpoint, e.g. for LocalStack
+	Endpoint string
+	// AccessKeyID and SecretAccessKey are static credentials; without them the default
+	// credential chain is used (environment, IAM roles and the like)
+	AccessKeyID     string
+	SecretAccessKey string
+	// Timeout bounds each request; defaults to 10s
+	Timeout time.Duration
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager provider
+func NewAWSSecretsManagerProvider(ctx context.Context, cfg AWSSecretsManagerConfig) (*AWSSecretsManagerProvider, error) {
+	opts := []func(*config.LoadOptions) error{}
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+	if cfg.AccessKeyID != "" && cfg.SecretAccessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AccessKeyID,
+			cfg.SecretAccessKey,
+			"",
+		)))
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to load AWS config: %v", ErrNotConfigured, err)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("%w: AWS region is required", ErrNotConfigured)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsSecretsManagerService, awsConfig.Region)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = awsDefaultTimeout
+	}
+	return &AWSSecretsManagerProvider{
+		endpoint:    strings.TrimSuffix(endpoint, "/") + "/",
+		region:      awsConfig.Region,
+		credentials: awsConfig.Credentials,
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name returns the scheme of AWS Secrets Manager references
+func (p *AWSSecretsManagerProvider) Name() string {
+	return SchemeAWSSecretsMgr
+}
+
+// Read returns the current version of the secret named path
+func (p *AWSSecretsManagerProvider) Read(ctx context.Context, path string) (*Secret, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AWS Secrets Manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := p.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), awsSecretsManagerService, p.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign AWS Secrets Manager request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach AWS Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AWS Secrets Manager response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &awsErr)
+		if strings.HasSuffix(awsErr.Type, "ResourceNotFoundException") {
+			return nil, fmt.Errorf("%w: AWS secret %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("AWS Secrets Manager returned status %d: %s %s", resp.StatusCode, awsErr.Type, awsErr.Message)
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"` // base64 in JSON
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid AWS Secrets Manager response: %w", err)
+	}
+
+	secret := &Secret{}
+	switch {
+	case result.SecretString != nil:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(*result.SecretString), &fields); err == nil && fields != nil {
+			secret.Fields = make(map[string]string, len(fields))
+			for key, raw := range fields {
+				secret.Fields[key] = fieldValue(raw)
+			}
+		} else {
+			secret.Value = *result.SecretString
+		}
+	default:
+		secret.Value = string(result.SecretBinary)
+	}
+	return secret, nil
+}
@@ -0,0 +1,259 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// Bounds of the wait between renewal checks
+const (
+	minRenewWait     = time.Second
+	maxRenewWait     = 5 * time.Minute
+	tokenLookupRetry = 30 * time.Second
+)
+
+// leaseRenewer renews the token and leases of a provider; *VaultProvider implements it
+type leaseRenewer interface {
+	LookupToken(ctx context.Context) (time.Duration, bool, error)
+	RenewToken(ctx context.Context) (time.Duration, error)
+	RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error)
+}
+
+// Resolver replaces secret references with the values they point to. Static secrets are
+// read again on every Resolve, so a configuration reload picks up rotated values. Dynamic
+// secrets are kept while their lease lasts, so a reload does not issue new credentials, and
+// Run renews their leases.
+type Resolver struct {
+	providers map[string]Provider
+	renewer   leaseRenewer
+
+	mu     sync.Mutex
+	leased map[string]*leasedSecret
+	token  tokenState
+}
+
+// leasedSecret is a dynamic secret and its lease
+type leasedSecret struct {
+	secret *Secret
+	lease  *Lease
+}
+
+// tokenState is what the resolver knows of the Vault token
+type tokenState struct {
+	known     bool
+	renewable bool
+	ttl       time.Duration
+	expires   time.Time
+}
+
+// NewResolver creates a resolver for references to the given providers. The Vault
+// provider's token and the leases of its dynamic secrets are renewed by Run.
+func NewResolver(providers ...Provider) *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider, len(providers)),
+		leased:    make(map[string]*leasedSecret),
+	}
+	for _, provider := range providers {
+		r.providers[provider.Name()] = provider
+		if renewer, ok := provider.(leaseRenewer); ok {
+			r.renewer = renewer
+		}
+	}
+	return r
+}
+
+// Resolve returns the values refs point to, under the same keys. Each secret is read once.
+func (r *Resolver) Resolve(ctx context.Context, refs map[string]Reference) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	read := make(map[string]*Secret)
+	values := make(map[string]string, len(refs))
+	for key, ref := range refs {
+		secret, ok := read[ref.location()]
+		if !ok {
+			var err error
+			if secret, err = r.read(ctx, ref); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			read[ref.location()] = secret
+		}
+		value, err := secret.Field(ref.Field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", key, ref, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// read returns the secret ref points to, reusing dynamic secrets whose lease lasts
+func (r *Resolver) read(ctx context.Context, ref Reference) (*Secret, error) {
+	location := ref.location()
+	if cached, ok := r.leased[location]; ok {
+		if time.Now().Before(cached.lease.Expires) {
+			return cached.secret, nil
+		}
+		delete(r.leased, location)
+	}
+
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotConfigured, ref.Scheme)
+	}
+	secret, err := provider.Read(ctx, ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if secret.LeaseID != "" {
+		r.leased[location] = &leasedSecret{
+			secret: secret,
+			lease: &Lease{
+				ID:        secret.LeaseID,
+				Path:      location,
+				Duration:  secret.LeaseDuration,
+				Renewable: secret.Renewable,
+				Expires:   time.Now().Add(secret.LeaseDuration),
+			},
+		}
+	}
+	return secret, nil
+}
+
+// Leases returns the leases of the dynamic secrets in use
+func (r *Resolver) Leases() []Lease {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	leases := make(map[string]*Lease, len(r.leased))
+	for location, cached := range r.leased {
+		leases[location] = cached.lease
+	}
+	return sortedLeases(leases)
+}
+
+// Run renews the Vault token and the leases of dynamic secrets until ctx is done, each
+// once half of its TTL has passed. A lease Vault stops extending, because it reached its
+// maximum TTL, is dropped when it runs out so the next reload reads new credentials.
+func (r *Resolver) Run(ctx context.Context, log *logger.Logger) {
+	if r.renewer == nil {
+		return
+	}
+
+	for {
+		wait := r.renew(ctx, log)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// renew renews the token and the leases that are due and returns how long to wait for the
+// next one
+func (r *Resolver) renew(ctx context.Context, log *logger.Logger) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	next := now.Add(maxRenewWait)
+	due := func(expires time.Time, ttl time.Duration) time.Time {
+		return expires.Add(-ttl / 2)
+	}
+
+	if !r.token.known {
+		ttl, renewable, err := r.renewer.LookupToken(ctx)
+		if err != nil {
+			log.Error("Failed to look up Vault token", map[string]interface{}{
+				"error": err.Error(),
+			})
+			next = now.Add(tokenLookupRetry)
+		} else {
+			r.token = tokenState{known: true, renewable: renewable && ttl > 0, ttl: ttl, expires: now.Add(ttl)}
+		}
+	}
+	if r.token.renewable {
+		if at := due(r.token.expires, r.token.ttl); !now.Before(at) {
+			ttl, err := r.renewer.RenewToken(ctx)
+			if err != nil {
+				log.Error("Failed to renew Vault token", map[string]interface{}{
+					"error":   err.Error(),
+					"expires": r.token.expires,
+				})
+				// Retry soon, but no later than the token's last quarter
+				next = minTime(next, now.Add(maxDuration(time.Until(r.token.expires)/4, minRenewWait)))
+			} else {
+				if ttl < r.token.ttl {
+					log.Warn("Vault token approaches its maximum TTL", map[string]interface{}{
+						"ttl": ttl.String(),
+					})
+				}
+				r.token.ttl, r.token.expires = ttl, now.Add(ttl)
+				r.token.renewable = ttl > 0
+			}
+		}
+		if r.token.renewable {
+			next = minTime(next, due(r.token.expires, r.token.ttl))
+		}
+	}
+
+	for location, cached := range r.leased {
+		lease := cached.lease
+		if !now.Before(lease.Expires) {
+			log.Warn("Secret lease expired, reload or restart to use new credentials", map[string]interface{}{
+				"path": location,
+			})
+			delete(r.leased, location)
+			continue
+		}
+		if at := due(lease.Expires, lease.Duration); lease.Renewable && !now.Before(at) {
+			granted, err := r.renewer.RenewLease(ctx, lease.ID, lease.Duration)
+			switch {
+			case err != nil:
+				log.Error("Failed to renew secret lease", map[string]interface{}{
+					"path":    location,
+					"error":   err.Error(),
+					"expires": lease.Expires,
+				})
+				next = minTime(next, now.Add(maxDuration(time.Until(lease.Expires)/4, minRenewWait)))
+				continue
+			case granted < lease.Duration:
+				// Vault caps the lease at its maximum TTL: renewing again gains nothing
+				log.Warn("Secret lease reaches its maximum TTL, reload or restart before it expires", map[string]interface{}{
+					"path":    location,
+					"expires": now.Add(granted),
+				})
+				lease.Renewable = false
+			}
+			lease.Expires = now.Add(granted)
+		}
+		if lease.Renewable {
+			next = minTime(next, due(lease.Expires, lease.Duration))
+		} else {
+			next = minTime(next, lease.Expires)
+		}
+	}
+
+	return maxDuration(time.Until(next), minRenewWait)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
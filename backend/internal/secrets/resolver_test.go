@@ -0,0 +1,232 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// fakeProvider serves secrets from memory and counts reads; it renews like Vault
+type fakeProvider struct {
+	secrets map[string]*Secret
+	reads   map[string]int
+
+	tokenTTL       time.Duration
+	tokenRenewals  int
+	leaseRenewals  map[string]int
+	grantLease     time.Duration
+	renewLeaseErr  error
+	lookupTokenErr error
+}
+
+func newFakeProvider(secrets map[string]*Secret) *fakeProvider {
+	return &fakeProvider{secrets: secrets, reads: map[string]int{}, leaseRenewals: map[string]int{}}
+}
+
+func (p *fakeProvider) Name() string { return SchemeVault }
+
+func (p *fakeProvider) Read(_ context.Context, path string) (*Secret, error) {
+	p.reads[path]++
+	secret, ok := p.secrets[path]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return secret, nil
+}
+
+func (p *fakeProvider) LookupToken(context.Context) (time.Duration, bool, error) {
+	return p.tokenTTL, true, p.lookupTokenErr
+}
+
+func (p *fakeProvider) RenewToken(context.Context) (time.Duration, error) {
+	p.tokenRenewals++
+	return p.tokenTTL, nil
+}
+
+func (p *fakeProvider) RenewLease(_ context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	p.leaseRenewals[leaseID]++
+	if p.renewLeaseErr != nil {
+		return 0, p.renewLeaseErr
+	}
+	if p.grantLease > 0 {
+		return p.grantLease, nil
+	}
+	return increment, nil
+}
+
+func testLogger() *logger.Logger {
+	return logger.New("test", logger.ErrorLevel, false)
+}
+
+func mustParse(t *testing.T, value string) Reference {
+	t.Helper()
+	ref, ok := ParseReference(value)
+	require.True(t, ok, value)
+	return ref
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Reference
+		ok    bool
+	}{
+		{"vault:secret/data/app#jwt", Reference{Scheme: SchemeVault, Path: "secret/data/app", Field: "jwt"}, true},
+		{"aws-sm:arn:aws:secretsmanager:eu-west-1:123:secret:app#key", Reference{Scheme: SchemeAWSSecretsMgr, Path: "arn:aws:secretsmanager:eu-west-1:123:secret:app", Field: "key"}, true},
+		{"aws-sm:app/key", Reference{Scheme: SchemeAWSSecretsMgr, Path: "app/key"}, true},
+		{"plain-secret", Reference{}, false},
+		{"https://example.com", Reference{}, false},
+		{"vault:", Reference{}, false},
+		{"vault:#field", Reference{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseReference(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+			if ok {
+				assert.Equal(t, tt.value, got.String())
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"secret/data/app": {Fields: map[string]string{"access": "a", "refresh": "b"}},
+		"secret/data/key": {Fields: map[string]string{"pem": "KEY"}},
+	})
+	r := NewResolver(provider)
+
+	values, err := r.Resolve(context.Background(), map[string]Reference{
+		"JWT_ACCESS_SECRET":  mustParse(t, "vault:secret/data/app#access"),
+		"JWT_REFRESH_SECRET": mustParse(t, "vault:secret/data/app#refresh"),
+		"OIDC_SIGNING_KEY":   mustParse(t, "vault:secret/data/key"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"JWT_ACCESS_SECRET": "a", "JWT_REFRESH_SECRET": "b", "OIDC_SIGNING_KEY": "KEY"}, values)
+	assert.Equal(t, 1, provider.reads["secret/data/app"], "a secret should be read once")
+
+	// Static secrets are read again, so rotated values are picked up
+	_, err = r.Resolve(context.Background(), map[string]Reference{"JWT_ACCESS_SECRET": mustParse(t, "vault:secret/data/app#access")})
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.reads["secret/data/app"])
+}
+
+func TestResolver_Resolve_ShouldFail(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"secret/data/app": {Fields: map[string]string{"access": "a", "refresh": "b"}},
+	})
+	r := NewResolver(provider)
+
+	tests := map[string]string{
+		"missing secret":   "vault:secret/data/missing#access",
+		"missing field":    "vault:secret/data/app#other",
+		"ambiguous field":  "vault:secret/data/app",
+		"unknown provider": "aws-sm:app#access",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := r.Resolve(context.Background(), map[string]Reference{"KEY": mustParse(t, value)})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestResolver_ShouldReuseLeasedSecrets(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"database/creds/app": {
+			Fields:        map[string]string{"username": "u", "password": "p"},
+			LeaseID:       "lease-1",
+			LeaseDuration: time.Hour,
+			Renewable:     true,
+		},
+	})
+	r := NewResolver(provider)
+	refs := map[string]Reference{"DB_PASSWORD": mustParse(t, "vault:database/creds/app#password")}
+
+	for i := 0; i < 2; i++ {
+		values, err := r.Resolve(context.Background(), refs)
+		require.NoError(t, err)
+		assert.Equal(t, "p", values["DB_PASSWORD"])
+	}
+
+	assert.Equal(t, 1, provider.reads["database/creds/app"], "a reload should not issue new credentials")
+	leases := r.Leases()
+	require.Len(t, leases, 1)
+	assert.Equal(t, "lease-1", leases[0].ID)
+	assert.Equal(t, "vault:database/creds/app", leases[0].Path)
+}
+
+func TestResolver_Renew(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"database/creds/app": {Fields: map[string]string{"password": "p"}, LeaseID: "lease-1", LeaseDuration: time.Hour, Renewable: true},
+	})
+	provider.tokenTTL = time.Hour
+	r := NewResolver(provider)
+	_, err := r.Resolve(context.Background(), map[string]Reference{"DB_PASSWORD": mustParse(t, "vault:database/creds/app#password")})
+	require.NoError(t, err)
+
+	// Nothing is due before half of the TTLs
+	wait := r.renew(context.Background(), testLogger())
+	assert.Equal(t, 0, provider.tokenRenewals)
+	assert.Equal(t, 0, provider.leaseRenewals["lease-1"])
+	assert.InDelta(t, float64(maxRenewWait), float64(wait), float64(time.Second))
+
+	// Past half of the TTLs both are renewed
+	r.token.expires = time.Now().Add(20 * time.Minute)
+	r.leased["vault:database/creds/app"].lease.Expires = time.Now().Add(20 * time.Minute)
+	r.renew(context.Background(), testLogger())
+	assert.Equal(t, 1, provider.tokenRenewals)
+	assert.Equal(t, 1, provider.leaseRenewals["lease-1"])
+	assert.WithinDuration(t, time.Now().Add(time.Hour), r.leased["vault:database/creds/app"].lease.Expires, time.Second)
+}
+
+func TestResolver_Renew_ShouldStopAtMaximumTTL(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"database/creds/app": {Fields: map[string]string{"password": "p"}, LeaseID: "lease-1", LeaseDuration: time.Hour, Renewable: true},
+	})
+	provider.grantLease = 10 * time.Minute
+	r := NewResolver(provider)
+	_, err := r.Resolve(context.Background(), map[string]Reference{"DB_PASSWORD": mustParse(t, "vault:database/creds/app#password")})
+	require.NoError(t, err)
+
+	lease := r.leased["vault:database/creds/app"].lease
+	lease.Expires = time.Now().Add(20 * time.Minute)
+	r.renew(context.Background(), testLogger())
+
+	assert.False(t, lease.Renewable)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), lease.Expires, time.Second)
+
+	// An expired lease is dropped, the next Resolve reads new credentials
+	lease.Expires = time.Now().Add(-time.Second)
+	r.renew(context.Background(), testLogger())
+	assert.Empty(t, r.Leases())
+	_, err = r.Resolve(context.Background(), map[string]Reference{"DB_PASSWORD": mustParse(t, "vault:database/creds/app#password")})
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.reads["database/creds/app"])
+}
+
+func TestResolver_Renew_ShouldRetrySoonAfterFailure(t *testing.T) {
+	provider := newFakeProvider(map[string]*Secret{
+		"database/creds/app": {Fields: map[string]string{"password": "p"}, LeaseID: "lease-1", LeaseDuration: time.Hour, Renewable: true},
+	})
+	provider.lookupTokenErr = errors.New("vault sealed")
+	provider.renewLeaseErr = errors.New("vault sealed")
+	r := NewResolver(provider)
+	_, err := r.Resolve(context.Background(), map[string]Reference{"DB_PASSWORD": mustParse(t, "vault:database/creds/app#password")})
+	require.NoError(t, err)
+
+	r.leased["vault:database/creds/app"].lease.Expires = time.Now().Add(20 * time.Minute)
+	wait := r.renew(context.Background(), testLogger())
+
+	assert.Equal(t, 1, provider.leaseRenewals["lease-1"])
+	assert.LessOrEqual(t, wait, tokenLookupRetry)
+}
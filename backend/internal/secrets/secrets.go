@@ -0,0 +1,131 @@
+// Package secrets reads secrets from secrets managers: HashiCorp Vault over its HTTP API and
+// AWS Secrets Manager over its JSON API. Configuration values refer to a secret with a
+// reference such as vault:secret/data/auth-gateway#jwt_access_secret, which a Resolver
+// replaces with the secret's value, keeping the Vault token and the leases of dynamic
+// secrets renewed while the server runs.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Provider reads secrets from a secrets manager
+type Provider interface {
+	// Read returns the secret stored at path
+	Read(ctx context.Context, path string) (*Secret, error)
+
+	// Name returns the scheme of the provider's references
+	Name() string
+}
+
+var (
+	// ErrNotConfigured is returned when a reference names a provider that is not configured
+	ErrNotConfigured = errors.New("secrets provider not configured")
+
+	// ErrNotFound is returned when a secret or a field of it does not exist
+	ErrNotFound = errors.New("secret not found")
+)
+
+// Reference schemes
+const (
+	SchemeVault          = "vault"
+	SchemeAWSSecretsMgr  = "aws-sm"
+	referenceFieldMarker = "#"
+)
+
+// Secret is a secret read from a provider
+type Secret struct {
+	// Fields of the secret: the key/value pairs of a Vault secret, or of an AWS secret
+	// whose value is a JSON object
+	Fields map[string]string
+	// Value is the whole value of an AWS secret that is not a JSON object
+	Value string
+
+	// LeaseID, LeaseDuration and Renewable describe the lease of a dynamic Vault secret,
+	// e.g. database credentials; LeaseID is empty for static secrets
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+}
+
+// Field returns the field name of the secret, or its whole value when name is empty
+func (s *Secret) Field(name string) (string, error) {
+	if name == "" {
+		if s.Value != "" {
+			return s.Value, nil
+		}
+		if len(s.Fields) == 1 {
+			for _, value := range s.Fields {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("the secret has several fields, the reference must name one")
+	}
+	value, ok := s.Fields[name]
+	if !ok {
+		return "", fmt.Errorf("%w: no field %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// Reference locates a value in a secrets manager: scheme:path#field
+type Reference struct {
+	Scheme string
+	Path   string
+	Field  string
+}
+
+// ParseReference parses a secret reference. It reports false for values that are not
+// references, which are used as they are.
+func ParseReference(value string) (Reference, bool) {
+	scheme, rest, ok := strings.Cut(value, ":")
+	if !ok || (scheme != SchemeVault && scheme != SchemeAWSSecretsMgr) || rest == "" {
+		return Reference{}, false
+	}
+	ref := Reference{Scheme: scheme, Path: rest}
+	if i := strings.LastIndex(rest, referenceFieldMarker); i >= 0 {
+		ref.Path, ref.Field = rest[:i], rest[i+1:]
+	}
+	if ref.Path == "" {
+		return Reference{}, false
+	}
+	return ref, true
+}
+
+// String returns the reference as written in configuration
+func (r Reference) String() string {
+	if r.Field == "" {
+		return r.Scheme + ":" + r.Path
+	}
+	return r.Scheme + ":" + r.Path + referenceFieldMarker + r.Field
+}
+
+// location identifies the secret a reference reads
+func (r Reference) location() string {
+	return r.Scheme + ":" + r.Path
+}
+
+// Lease is the lease of a dynamic secret a Resolver keeps renewed
+type Lease struct {
+	ID        string
+	Path      string
+	Duration  time.Duration
+	Renewable bool
+	// Expires is when the lease runs out unless renewed
+	Expires time.Time
+}
+
+// sortedLeases returns leases ordered by path, for stable output
+func sortedLeases(leases map[string]*Lease) []Lease {
+	result := make([]Lease, 0, len(leases))
+	for _, lease := range leases {
+		result = append(result, *lease)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
@@ -0,0 +1,213 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const vaultDefaultTimeout = 10 * time.Second
+
+// VaultProvider reads secrets from HashiCorp Vault. Paths are API paths below /v1, so a
+// KV v2 secret is read at <mount>/data/<name> (its data is unwrapped) and dynamic
+// credentials at e.g. database/creds/<role>.
+type VaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// VaultConfig holds Vault configuration
+type VaultConfig struct {
+	// Address of the server, e.g. https://vault:8200
+	Address string
+	// Token authenticates requests
+	Token string
+	// Namespace selects a Vault Enterprise namespace
+	Namespace string
+	// Timeout bounds each request; defaults to 10s
+	Timeout time.Duration
+}
+
+// vaultResponse is the envelope of Vault API responses
+type vaultResponse struct {
+	LeaseID       string          `json:"lease_id"`
+	LeaseDuration int64           `json:"lease_duration"`
+	Renewable     bool            `json:"renewable"`
+	Data          json.RawMessage `json:"data"`
+	Auth          *struct {
+		LeaseDuration int64 `json:"lease_duration"`
+		Renewable     bool  `json:"renewable"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewVaultProvider creates a new Vault provider
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("%w: Vault address is required", ErrNotConfigured)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = vaultDefaultTimeout
+	}
+	return &VaultProvider{
+		addr:      strings.TrimSuffix(cfg.Address, "/"),
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name returns the scheme of Vault references
+func (p *VaultProvider) Name() string {
+	return SchemeVault
+}
+
+// Read returns the secret at path
+func (p *VaultProvider) Read(ctx context.Context, path string) (*Secret, error) {
+	resp, err := p.do(ctx, http.MethodGet, strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("%w: Vault path %s", ErrNotFound, path)
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid Vault secret at %s: %w", path, err)
+	}
+	// KV v2 wraps the secret in data, next to its metadata
+	if inner, ok := data["data"]; ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nil
+			if err := json.Unmarshal(inner, &data); err != nil {
+				return nil, fmt.Errorf("invalid Vault secret at %s: %w", path, err)
+			}
+			if data == nil {
+				// Deleted versions have null data
+				return nil, fmt.Errorf("%w: Vault path %s is deleted", ErrNotFound, path)
+			}
+		}
+	}
+
+	secret := &Secret{
+		Fields:        make(map[string]string, len(data)),
+		LeaseID:       resp.LeaseID,
+		LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable:     resp.Renewable,
+	}
+	for key, raw := range data {
+		secret.Fields[key] = fieldValue(raw)
+	}
+	return secret, nil
+}
+
+// LookupToken returns the remaining TTL of the provider's token and whether it can be
+// renewed. Tokens that do not expire, such as root tokens, have a zero TTL.
+func (p *VaultProvider) LookupToken(ctx context.Context) (time.Duration, bool, error) {
+	resp, err := p.do(ctx, http.MethodGet, "auth/token/lookup-self", nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if resp == nil {
+		return 0, false, fmt.Errorf("%w: Vault token lookup", ErrNotFound)
+	}
+	var data struct {
+		TTL       int64 `json:"ttl"`
+		Renewable bool  `json:"renewable"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return 0, false, fmt.Errorf("invalid Vault token lookup: %w", err)
+	}
+	return time.Duration(data.TTL) * time.Second, data.Renewable, nil
+}
+
+// RenewToken renews the provider's token and returns its new TTL
+func (p *VaultProvider) RenewToken(ctx context.Context) (time.Duration, error) {
+	resp, err := p.do(ctx, http.MethodPost, "auth/token/renew-self", map[string]interface{}{})
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil || resp.Auth == nil {
+		return 0, fmt.Errorf("invalid Vault token renewal response")
+	}
+	return time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
+
+// RenewLease extends the lease of a dynamic secret by increment and returns the duration
+// Vault granted, which is shorter than asked for once the lease nears its maximum TTL
+func (p *VaultProvider) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	resp, err := p.do(ctx, http.MethodPut, "sys/leases/renew", map[string]interface{}{
+		"lease_id":  leaseID,
+		"increment": int64(increment / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, fmt.Errorf("%w: Vault lease %s", ErrNotFound, leaseID)
+	}
+	return time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+// do sends a request to the Vault API; it returns nil for 404
+func (p *VaultProvider) do(ctx context.Context, method, path string, body interface{}) (*vaultResponse, error) {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.addr+"/v1/"+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Vault request: %w", err)
+	}
+	if p.token != "" {
+		req.Header.Set("X-Vault-Token", p.token)
+	}
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result vaultResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid Vault response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound && len(result.Errors) == 0:
+		return nil, nil
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("Vault returned status %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+	}
+	return &result, nil
+}
+
+// fieldValue returns a JSON value as a configuration value: strings as they are, anything
+// else as JSON
+func fieldValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
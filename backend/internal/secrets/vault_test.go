@@ -0,0 +1,136 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVault(t *testing.T, handler http.HandlerFunc) *VaultProvider {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	vault, err := NewVaultProvider(VaultConfig{Address: srv.URL + "/", Token: "vault-token", Namespace: "team"})
+	require.NoError(t, err)
+	return vault
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestVaultProvider_Read_ShouldUnwrapKVv2Secret(t *testing.T) {
+	vault := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/auth-gateway", r.URL.Path)
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "team", r.Header.Get("X-Vault-Namespace"))
+		writeJSON(w, map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"jwt_access_secret": "s3cret", "port": 587},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+		})
+	})
+
+	secret, err := vault.Read(context.Background(), "/secret/data/auth-gateway")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"jwt_access_secret": "s3cret", "port": "587"}, secret.Fields)
+	assert.Empty(t, secret.LeaseID)
+}
+
+func TestVaultProvider_Read_ShouldReturnLeaseOfDynamicSecret(t *testing.T) {
+	vault := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"lease_id":       "database/creds/auth-gateway/abc",
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]interface{}{"username": "v-user", "password": "v-pass"},
+		})
+	})
+
+	secret, err := vault.Read(context.Background(), "database/creds/auth-gateway")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v-pass", secret.Fields["password"])
+	assert.Equal(t, "database/creds/auth-gateway/abc", secret.LeaseID)
+	assert.Equal(t, time.Hour, secret.LeaseDuration)
+	assert.True(t, secret.Renewable)
+}
+
+func TestVaultProvider_Read_ShouldReportErrors(t *testing.T) {
+	vault := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/secret/data/missing":
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]interface{}{"errors": []string{}})
+		case "/v1/secret/data/deleted":
+			writeJSON(w, map[string]interface{}{
+				"data": map[string]interface{}{"data": nil, "metadata": map[string]interface{}{"deletion_time": "2026-01-01T00:00:00Z"}},
+			})
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]interface{}{"errors": []string{"permission denied"}})
+		}
+	})
+
+	_, err := vault.Read(context.Background(), "secret/data/missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = vault.Read(context.Background(), "secret/data/deleted")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = vault.Read(context.Background(), "secret/data/forbidden")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestVaultProvider_ShouldRenewTokenAndLeases(t *testing.T) {
+	vault := newTestVault(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"ttl": 600, "renewable": true}})
+		case "/v1/auth/token/renew-self":
+			assert.Equal(t, http.MethodPost, r.Method)
+			writeJSON(w, map[string]interface{}{"auth": map[string]interface{}{"lease_duration": 1200, "renewable": true}})
+		case "/v1/sys/leases/renew":
+			assert.Equal(t, http.MethodPut, r.Method)
+			var body struct {
+				LeaseID   string `json:"lease_id"`
+				Increment int64  `json:"increment"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "lease-1", body.LeaseID)
+			assert.Equal(t, int64(3600), body.Increment)
+			writeJSON(w, map[string]interface{}{"lease_id": body.LeaseID, "lease_duration": 1800})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	ttl, renewable, err := vault.LookupToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, ttl)
+	assert.True(t, renewable)
+
+	ttl, err = vault.RenewToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, ttl)
+
+	granted, err := vault.RenewLease(ctx, "lease-1", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, granted)
+}
+
+func TestNewVaultProvider_ShouldRequireAddress(t *testing.T) {
+	_, err := NewVaultProvider(VaultConfig{Token: "token"})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAccountLockoutStore keeps lockouts in memory
+type mockAccountLockoutStore struct {
+	lockouts map[uuid.UUID]*models.AccountLockout
+	err      error
+}
+
+func newMockAccountLockoutStore() *mockAccountLockoutStore {
+	return &mockAccountLockoutStore{lockouts: make(map[uuid.UUID]*models.AccountLockout)}
+}
+
+func (m *mockAccountLockoutStore) GetLockout(ctx context.Context, userID uuid.UUID) (*models.AccountLockout, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	lockout, ok := m.lockouts[userID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *lockout
+	return &copied, nil
+}
+
+func (m *mockAccountLockoutStore) RecordFailedAttempt(ctx context.Context, userID uuid.UUID, windowStart time.Time) (*models.AccountLockout, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	now := time.Now()
+	lockout, ok := m.lockouts[userID]
+	if !ok {
+		lockout = &models.AccountLockout{ID: uuid.New(), UserID: userID}
+		m.lockouts[userID] = lockout
+	}
+	if lockout.LastFailedAttempt == nil || lockout.LastFailedAttempt.Before(windowStart) {
+		lockout.FailedAttempts = 1
+	} else {
+		lockout.FailedAttempts++
+	}
+	lockout.LastFailedAttempt = &now
+	copied := *lockout
+	return &copied, nil
+}
+
+func (m *mockAccountLockoutStore) Lock(ctx context.Context, userID uuid.UUID, lockoutCount int, until time.Time) error {
+	lockout := m.lockouts[userID]
+	lockout.LockoutCount = lockoutCount
+	lockout.LockedUntil = &until
+	lockout.FailedAttempts = 0
+	return nil
+}
+
+func (m *mockAccountLockoutStore) Reset(ctx context.Context, userID uuid.UUID) error {
+	delete(m.lockouts, userID)
+	return nil
+}
+
+func (m *mockAccountLockoutStore) ListLocked(ctx context.Context, at time.Time, page, pageSize int) ([]*models.LockedAccount, int, error) {
+	accounts := make([]*models.LockedAccount, 0)
+	for _, lockout := range m.lockouts {
+		if lockout.LockedUntil != nil && lockout.LockedUntil.After(at) {
+			accounts = append(accounts, &models.LockedAccount{UserID: lockout.UserID, LockedUntil: lockout.LockedUntil})
+		}
+	}
+	return accounts, len(accounts), nil
+}
+
+var testLockoutPolicy = models.AccountLockoutPolicy{
+	MaxFailedAttempts:  3,
+	ResetAfter:         15 * time.Minute,
+	LockoutDuration:    5 * time.Minute,
+	MaxLockoutDuration: time.Hour,
+}
+
+func newTestAccountLockoutService() (*AccountLockoutService, *mockAccountLockoutStore) {
+	store := newMockAccountLockoutStore()
+	return NewAccountLockoutService(store, testLockoutPolicy, logger.New("test", logger.DebugLevel, false)), store
+}
+
+func TestAccountLockoutPolicy_LockoutDurationFor(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, testLockoutPolicy.LockoutDurationFor(1))
+	assert.Equal(t, 10*time.Minute, testLockoutPolicy.LockoutDurationFor(2))
+	assert.Equal(t, 40*time.Minute, testLockoutPolicy.LockoutDurationFor(4))
+	assert.Equal(t, time.Hour, testLockoutPolicy.LockoutDurationFor(5))
+	assert.Equal(t, time.Hour, testLockoutPolicy.LockoutDurationFor(1000))
+}
+
+func TestAccountLockoutService_RecordFailedAttempt_ShouldLockAtLimit(t *testing.T) {
+	svc, store := newTestAccountLockoutService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	assert.Nil(t, svc.RecordFailedAttempt(ctx, userID))
+	assert.Nil(t, svc.RecordFailedAttempt(ctx, userID))
+	assert.Nil(t, svc.LockedUntil(ctx, userID))
+
+	lockout := svc.RecordFailedAttempt(ctx, userID)
+	require.NotNil(t, lockout)
+	assert.Equal(t, 1, lockout.LockoutCount)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), *lockout.LockedUntil, time.Second)
+	assert.NotNil(t, svc.LockedUntil(ctx, userID))
+	assert.Zero(t, store.lockouts[userID].FailedAttempts)
+}
+
+func TestAccountLockoutService_RecordFailedAttempt_ShouldDoubleConsecutiveLockouts(t *testing.T) {
+	svc, store := newTestAccountLockoutService()
+	ctx := context.Background()
+	userID := uuid.New()
+	expired := time.Now().Add(-time.Second)
+	store.lockouts[userID] = &models.AccountLockout{UserID: userID, LockoutCount: 2, LockedUntil: &expired}
+
+	svc.RecordFailedAttempt(ctx, userID)
+	svc.RecordFailedAttempt(ctx, userID)
+	lockout := svc.RecordFailedAttempt(ctx, userID)
+
+	require.NotNil(t, lockout)
+	assert.Equal(t, 3, lockout.LockoutCount)
+	assert.WithinDuration(t, time.Now().Add(20*time.Minute), *lockout.LockedUntil, time.Second)
+}
+
+func TestAccountLockoutService_RecordFailedAttempt_ShouldForgetAttemptsOutsideWindow(t *testing.T) {
+	svc, store := newTestAccountLockoutService()
+	ctx := context.Background()
+	userID := uuid.New()
+	longAgo := time.Now().Add(-time.Hour)
+	store.lockouts[userID] = &models.AccountLockout{UserID: userID, FailedAttempts: 2, LastFailedAttempt: &longAgo}
+
+	assert.Nil(t, svc.RecordFailedAttempt(ctx, userID))
+	assert.Equal(t, 1, store.lockouts[userID].FailedAttempts)
+}
+
+func TestAccountLockoutService_ShouldFailOpen_WhenStoreUnavailable(t *testing.T) {
+	svc, store := newTestAccountLockoutService()
+	store.err = errors.New("connection refused")
+	ctx := context.Background()
+
+	assert.Nil(t, svc.LockedUntil(ctx, uuid.New()))
+	assert.Nil(t, svc.RecordFailedAttempt(ctx, uuid.New()))
+}
+
+func TestAccountLockoutService_Unlock(t *testing.T) {
+	svc, store := newTestAccountLockoutService()
+	ctx := context.Background()
+	userID := uuid.New()
+	until := time.Now().Add(time.Hour)
+	store.lockouts[userID] = &models.AccountLockout{UserID: userID, LockoutCount: 3, LockedUntil: &until}
+
+	lockout, err := svc.Unlock(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, lockout.IsLocked())
+	assert.Nil(t, svc.LockedUntil(ctx, userID))
+
+	_, err = svc.Unlock(ctx, userID)
+	assert.Equal(t, errLockoutNotFound, err)
+}
+
+func TestAuthService_SignIn_AccountLockout(t *testing.T) {
+	svc, mUser, _, _, mAudit, _, _, _, _ := setupAuthService()
+	lockoutService, store := newTestAccountLockoutService()
+	svc.SetAccountLockout(lockoutService)
+	ctx := context.Background()
+
+	hash, _ := utils.HashPassword("password123", 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash, IsActive: true, EmailVerified: true, TOTPEnabled: true}
+	mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	var audited []AuditLogParams
+	mAudit.LogFunc = func(params AuditLogParams) { audited = append(audited, params) }
+
+	signIn := func(password string) (*models.AuthResponse, error) {
+		return svc.SignIn(ctx, &models.SignInRequest{Email: user.Email, Password: password}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+	}
+
+	t.Run("SuccessForgetsFailedAttempts", func(t *testing.T) {
+		_, err := signIn("wrong")
+		assert.Equal(t, models.ErrInvalidCredentials, err)
+		require.Contains(t, store.lockouts, user.ID)
+
+		_, err = signIn("password123")
+		require.NoError(t, err)
+		assert.NotContains(t, store.lockouts, user.ID)
+	})
+
+	t.Run("LimitLocksAccount", func(t *testing.T) {
+		audited = nil
+		signIn("wrong")
+		signIn("wrong")
+		_, err := signIn("wrong")
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusLocked, appErr.Code)
+		require.Len(t, audited, 4)
+		assert.Equal(t, models.ActionAccountLocked, audited[3].Action)
+	})
+
+	t.Run("LockedAccountRefusesRightPassword", func(t *testing.T) {
+		audited = nil
+		resp, err := signIn("password123")
+
+		assert.Nil(t, resp)
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusLocked, appErr.Code)
+		require.Len(t, audited, 1)
+		assert.Equal(t, models.StatusBlocked, audited[0].Status)
+		assert.Equal(t, "account_locked", audited[0].Details["reason"])
+	})
+}
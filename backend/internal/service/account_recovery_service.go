@@ -0,0 +1,569 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// recoverySecretLength is the number of random bytes in a recovery client secret
+	recoverySecretLength = 32
+	// defaultRecoveryTTL applies when no request TTL is configured
+	defaultRecoveryTTL = 24 * time.Hour
+	// defaultRecoveryMaxAttempts applies when no attempt limit is configured
+	defaultRecoveryMaxAttempts = 5
+	// recoveryRatePrefix prefixes the counters of recovery requests started per IP and per email
+	recoveryRatePrefix = "account_recovery_rate:"
+	// maxRecoveryStartsPerIP bounds the requests one IP can start within a request TTL. It is
+	// below maxRecoveryStartsPerEmail so that one client can't use up an account's allowance.
+	maxRecoveryStartsPerIP = 3
+	// maxRecoveryStartsPerEmail bounds the requests started for one email within a request TTL
+	maxRecoveryStartsPerEmail = 10
+)
+
+// errRecoveryNotFound is returned for unknown requests and wrong client secrets alike
+var errRecoveryNotFound = models.NewAppError(404, "Recovery request not found")
+
+// errTooManyRecoveryStarts is returned when an IP or an email started too many requests
+var errTooManyRecoveryStarts = models.NewAppError(429, "Too many recovery requests. Please try again later.")
+
+// AccountRecoveryServiceOptions contains optional dependencies for recovery notifications.
+type AccountRecoveryServiceOptions struct {
+	Notifier    NotificationSender
+	SMSProvider sms.SMSProvider
+	// Cache counts the requests started per IP and per email; without it they are not limited
+	Cache CacheService
+}
+
+// AccountRecoveryService recovers accounts whose owner lost both their password and their
+// second factor. The user proves their identity through the configured steps they can
+// complete (verified secondary email, verified phone, admin review); once all steps pass,
+// they may set a new password, which also resets 2FA. Every contact point of the account is
+// notified whenever a recovery starts, is decided or completes.
+type AccountRecoveryService struct {
+	store        AccountRecoveryStore
+	userRepo     UserStore
+	otpService   OTPServicer
+	passwords    PasswordResetter
+	auditService AuditLogger
+	notifier     NotificationSender
+	smsProvider  sms.SMSProvider
+	cache        CacheService
+	steps        []string
+	requestTTL   time.Duration
+	maxAttempts  int
+	logger       *logger.Logger
+}
+
+// NewAccountRecoveryService creates a new account recovery service.
+// Unknown steps in cfg are ignored.
+func NewAccountRecoveryService(
+	store AccountRecoveryStore,
+	userRepo UserStore,
+	otpService OTPServicer,
+	passwords PasswordResetter,
+	auditService AuditLogger,
+	cfg config.AccountRecoveryConfig,
+	log *logger.Logger,
+	opts AccountRecoveryServiceOptions,
+) *AccountRecoveryService {
+	steps := make([]string, 0, len(cfg.Steps))
+	for _, step := range cfg.Steps {
+		if models.IsValidRecoveryStep(step) {
+			steps = append(steps, step)
+		}
+	}
+
+	requestTTL := cfg.RequestTTL
+	if requestTTL <= 0 {
+		requestTTL = defaultRecoveryTTL
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRecoveryMaxAttempts
+	}
+
+	return &AccountRecoveryService{
+		store:        store,
+		userRepo:     userRepo,
+		otpService:   otpService,
+		passwords:    passwords,
+		auditService: auditService,
+		notifier:     opts.Notifier,
+		smsProvider:  opts.SMSProvider,
+		cache:        opts.Cache,
+		steps:        steps,
+		requestTTL:   requestTTL,
+		maxAttempts:  maxAttempts,
+		logger:       log,
+	}
+}
+
+// Start opens a recovery request for the account with the given email and sends a code for
+// every verification step. The response does not reveal whether the account exists: unknown
+// emails get a request that looks the same but can never progress. Anyone can call Start, so
+// requests never replace or block one another: each has its own client secret and attempt
+// counter, and only the number of requests started per IP and per email is limited.
+func (s *AccountRecoveryService) Start(ctx context.Context, req *models.StartAccountRecoveryRequest, ip, userAgent string) (*models.StartAccountRecoveryResponse, error) {
+	email := utils.NormalizeEmail(req.Email)
+	if err := s.checkStartRateLimit(ctx, email, ip); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateRecoverySecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests for unknown emails require every configured step, like a fully set up account
+	recovery := &models.AccountRecoveryRequest{
+		ID:             uuid.New(),
+		RequiredSteps:  s.steps,
+		CompletedSteps: []string{},
+		SecretHash:     utils.HashToken(secret),
+		Reason:         req.Reason,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		ExpiresAt:      time.Now().Add(s.requestTTL),
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email, utils.Ptr(true))
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	if user != nil {
+		recovery.UserID = &user.ID
+		recovery.RequiredSteps = s.stepsFor(user)
+	} else if len(recovery.RequiredSteps) == 0 {
+		recovery.RequiredSteps = []string{models.RecoveryStepAdminReview}
+	}
+	recovery.Status = nextRecoveryStatus(recovery)
+
+	if err := s.store.Create(ctx, recovery); err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		s.sendStepCodes(ctx, user, recovery)
+		s.notifyContactPoints(ctx, user, models.RecoveryEventStarted, ip)
+		s.logAudit(&user.ID, models.ActionAccountRecoveryStart, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+			"request_id":     recovery.ID.String(),
+			"required_steps": recovery.RequiredSteps,
+		})
+	}
+
+	return &models.StartAccountRecoveryResponse{
+		RequestID:    recovery.ID,
+		ClientSecret: secret,
+		ExpiresAt:    recovery.ExpiresAt,
+		Message:      "If an account exists for this email, recovery codes have been sent to its verified contact points",
+	}, nil
+}
+
+// VerifyStep checks the code sent for a verification step. Too many wrong codes cancel the request.
+func (s *AccountRecoveryService) VerifyStep(ctx context.Context, id uuid.UUID, req *models.VerifyAccountRecoveryStepRequest, ip, userAgent string) (*models.AccountRecoveryStatusResponse, error) {
+	recovery, err := s.authorize(ctx, id, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if recovery.Status != models.RecoveryStatusPendingVerification || recovery.IsExpired() {
+		return nil, models.NewAppError(400, "Recovery request is not awaiting verification")
+	}
+
+	if !recovery.RequiresStep(req.Step) || recovery.HasCompletedStep(req.Step) {
+		return nil, models.NewAppError(400, "Step is not pending for this recovery request")
+	}
+
+	valid := false
+	if recovery.User != nil {
+		valid, err = s.verifyStepCode(ctx, recovery.User, req.Step, req.Code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !valid {
+		recovery.FailedAttempts++
+		if recovery.FailedAttempts >= s.maxAttempts {
+			recovery.Status = models.RecoveryStatusCancelled
+		}
+		if err := s.store.Update(ctx, recovery); err != nil {
+			return nil, err
+		}
+		if recovery.UserID != nil {
+			s.logAudit(recovery.UserID, models.ActionAccountRecoveryVerify, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				"request_id": recovery.ID.String(),
+				"step":       req.Step,
+				"attempts":   recovery.FailedAttempts,
+			})
+		}
+		if recovery.Status == models.RecoveryStatusCancelled {
+			return nil, models.NewAppError(429, "Too many failed attempts, recovery request cancelled")
+		}
+		return nil, models.NewAppError(400, "Invalid or expired code")
+	}
+
+	recovery.CompletedSteps = append(recovery.CompletedSteps, req.Step)
+	recovery.Status = nextRecoveryStatus(recovery)
+	if err := s.store.Update(ctx, recovery); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(recovery.UserID, models.ActionAccountRecoveryVerify, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"request_id": recovery.ID.String(),
+		"step":       req.Step,
+		"status":     recovery.Status,
+	})
+
+	return toRecoveryStatusResponse(recovery), nil
+}
+
+// GetStatus reports the progress of a recovery request to its requester
+func (s *AccountRecoveryService) GetStatus(ctx context.Context, id uuid.UUID, clientSecret string) (*models.AccountRecoveryStatusResponse, error) {
+	recovery, err := s.authorize(ctx, id, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return toRecoveryStatusResponse(recovery), nil
+}
+
+// Complete sets a new password on an approved recovery request and resets the account's 2FA
+func (s *AccountRecoveryService) Complete(ctx context.Context, id uuid.UUID, req *models.CompleteAccountRecoveryRequest, ip, userAgent string) error {
+	recovery, err := s.authorize(ctx, id, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if recovery.Status != models.RecoveryStatusApproved || recovery.IsExpired() || recovery.User == nil {
+		return models.NewAppError(400, "Recovery request is not approved")
+	}
+
+	userID := recovery.User.ID
+	if err := s.passwords.ResetPassword(ctx, userID, req.NewPassword, ip, userAgent); err != nil {
+		return err
+	}
+
+	if err := s.store.ResetSecondFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	recovery.Status = models.RecoveryStatusCompleted
+	recovery.CompletedAt = &now
+	if err := s.store.Update(ctx, recovery); err != nil {
+		return err
+	}
+
+	s.notifyContactPoints(ctx, recovery.User, models.RecoveryEventCompleted, ip)
+	s.logAudit(&userID, models.ActionAccountRecoveryComplete, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"request_id": recovery.ID.String(),
+	})
+
+	return nil
+}
+
+// List returns recovery requests for the admin review queue, newest first
+func (s *AccountRecoveryService) List(ctx context.Context, status string, page, pageSize int) (*models.AccountRecoveryListResponse, error) {
+	requests, total, err := s.store.List(ctx, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (total + pageSize - 1) / pageSize
+	}
+
+	return &models.AccountRecoveryListResponse{
+		Requests:   requests,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Get returns a recovery request for admin review
+func (s *AccountRecoveryService) Get(ctx context.Context, id uuid.UUID) (*models.AccountRecoveryRequest, error) {
+	recovery, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errRecoveryNotFound
+		}
+		return nil, err
+	}
+	return recovery, nil
+}
+
+// Approve lets a request that passed its verification steps set a new password
+func (s *AccountRecoveryService) Approve(ctx context.Context, id, adminID uuid.UUID, note string) error {
+	recovery, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if recovery.Status != models.RecoveryStatusPendingReview || recovery.IsExpired() {
+		return models.NewAppError(400, "Recovery request is not awaiting review")
+	}
+
+	recovery.CompletedSteps = append(recovery.CompletedSteps, models.RecoveryStepAdminReview)
+	recovery.Status = models.RecoveryStatusApproved
+	if err := s.review(ctx, recovery, adminID, note); err != nil {
+		return err
+	}
+
+	s.notifyContactPoints(ctx, recovery.User, models.RecoveryEventApproved, "")
+	return nil
+}
+
+// Reject closes a recovery request that can still make progress
+func (s *AccountRecoveryService) Reject(ctx context.Context, id, adminID uuid.UUID, note string) error {
+	recovery, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !recovery.IsActive() {
+		return models.NewAppError(400, "Recovery request is no longer active")
+	}
+
+	recovery.Status = models.RecoveryStatusRejected
+	if err := s.review(ctx, recovery, adminID, note); err != nil {
+		return err
+	}
+
+	s.notifyContactPoints(ctx, recovery.User, models.RecoveryEventRejected, "")
+	return nil
+}
+
+// checkStartRateLimit counts a new request against its IP and its email. The email is counted
+// whether or not it has an account, so the limit doesn't reveal which ones do; an IP over its
+// limit doesn't count against the email.
+func (s *AccountRecoveryService) checkStartRateLimit(ctx context.Context, email, ip string) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	count, err := s.cache.IncrementRateLimit(ctx, recoveryRatePrefix+"ip:"+ip, s.requestTTL)
+	if err != nil {
+		return fmt.Errorf("failed to check recovery rate limit: %w", err)
+	}
+	if count > maxRecoveryStartsPerIP {
+		return errTooManyRecoveryStarts
+	}
+
+	count, err = s.cache.IncrementRateLimit(ctx, recoveryRatePrefix+"email:"+utils.HashToken(email), s.requestTTL)
+	if err != nil {
+		return fmt.Errorf("failed to check recovery rate limit: %w", err)
+	}
+	if count > maxRecoveryStartsPerEmail {
+		return errTooManyRecoveryStarts
+	}
+	return nil
+}
+
+// authorize loads a recovery request and checks the requester's client secret
+func (s *AccountRecoveryService) authorize(ctx context.Context, id uuid.UUID, clientSecret string) (*models.AccountRecoveryRequest, error) {
+	recovery, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errRecoveryNotFound
+		}
+		return nil, err
+	}
+
+	if !utils.CompareHashConstantTime(recovery.SecretHash, utils.HashToken(clientSecret)) {
+		return nil, errRecoveryNotFound
+	}
+
+	return recovery, nil
+}
+
+func (s *AccountRecoveryService) review(ctx context.Context, recovery *models.AccountRecoveryRequest, adminID uuid.UUID, note string) error {
+	now := time.Now()
+	recovery.ReviewedBy = &adminID
+	recovery.ReviewedAt = &now
+	recovery.ReviewNote = note
+	return s.store.Update(ctx, recovery)
+}
+
+// stepsFor returns the configured steps the user can complete. Admin review is the
+// fallback when the user has no verified contact point for any configured step.
+func (s *AccountRecoveryService) stepsFor(user *models.User) []string {
+	steps := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		switch step {
+		case models.RecoveryStepSecondaryEmail:
+			if user.RecoveryEmail != nil && user.RecoveryEmailVerified {
+				steps = append(steps, step)
+			}
+		case models.RecoveryStepSMS:
+			if user.Phone != nil && user.PhoneVerified {
+				steps = append(steps, step)
+			}
+		case models.RecoveryStepAdminReview:
+			steps = append(steps, step)
+		}
+	}
+
+	if len(steps) == 0 {
+		steps = append(steps, models.RecoveryStepAdminReview)
+	}
+
+	return steps
+}
+
+func (s *AccountRecoveryService) sendStepCodes(ctx context.Context, user *models.User, recovery *models.AccountRecoveryRequest) {
+	for _, step := range recovery.RequiredSteps {
+		var req *models.SendOTPRequest
+		switch step {
+		case models.RecoveryStepSecondaryEmail:
+			req = &models.SendOTPRequest{Email: user.RecoveryEmail, Type: models.OTPTypeAccountRecovery}
+		case models.RecoveryStepSMS:
+			req = &models.SendOTPRequest{Phone: user.Phone, Type: models.OTPTypeAccountRecovery}
+		default:
+			continue
+		}
+
+		if err := s.otpService.SendOTP(ctx, req); err != nil {
+			s.logger.Warn("Failed to send account recovery code", map[string]interface{}{
+				"request_id": recovery.ID.String(),
+				"step":       step,
+				"error":      err.Error(),
+			})
+		}
+	}
+}
+
+func (s *AccountRecoveryService) verifyStepCode(ctx context.Context, user *models.User, step, code string) (bool, error) {
+	req := &models.VerifyOTPRequest{Code: code, Type: models.OTPTypeAccountRecovery}
+	switch step {
+	case models.RecoveryStepSecondaryEmail:
+		req.Email = user.RecoveryEmail
+	case models.RecoveryStepSMS:
+		req.Phone = user.Phone
+	default:
+		return false, nil
+	}
+
+	resp, err := s.otpService.VerifyOTP(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Valid, nil
+}
+
+// notifyContactPoints tells every contact point of the account about recovery activity.
+// These notifications are mandatory; delivery failures are logged and do not block recovery.
+func (s *AccountRecoveryService) notifyContactPoints(ctx context.Context, user *models.User, event, ip string) {
+	if user == nil {
+		return
+	}
+
+	timestamp := time.Now().UTC().Format("2006-01-02 15:04 MST")
+
+	if s.notifier != nil {
+		emails := []string{user.Email}
+		if user.RecoveryEmail != nil && user.RecoveryEmailVerified {
+			emails = append(emails, *user.RecoveryEmail)
+		}
+
+		variables := map[string]interface{}{
+			"username":   user.Username,
+			"email":      user.Email,
+			"event":      event,
+			"ip_address": ip,
+			"timestamp":  timestamp,
+		}
+		for _, email := range emails {
+			if err := s.notifier.SendEmail(ctx, nil, nil, email, models.EmailTemplateTypeAccountRecovery, variables); err != nil {
+				s.logger.Warn("Failed to send account recovery notification", map[string]interface{}{
+					"user_id": user.ID.String(),
+					"event":   event,
+					"error":   err.Error(),
+				})
+			}
+		}
+	}
+
+	if s.smsProvider != nil && user.Phone != nil && user.PhoneVerified {
+		message := fmt.Sprintf("Account recovery %s on your account at %s. If this wasn't you, contact support immediately.", event, timestamp)
+		if _, err := s.smsProvider.SendSMS(ctx, *user.Phone, message); err != nil {
+			s.logger.Warn("Failed to send account recovery SMS notification", map[string]interface{}{
+				"user_id": user.ID.String(),
+				"event":   event,
+				"error":   err.Error(),
+			})
+		}
+	}
+}
+
+func (s *AccountRecoveryService) logAudit(userID *uuid.UUID, action models.AuditAction, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
+	if s.auditService == nil {
+		return
+	}
+	s.auditService.Log(AuditLogParams{
+		UserID:    userID,
+		Action:    action,
+		Status:    status,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details:   details,
+	})
+}
+
+// nextRecoveryStatus derives the status of an active request from its completed steps
+func nextRecoveryStatus(recovery *models.AccountRecoveryRequest) string {
+	for _, step := range recovery.RequiredSteps {
+		if step != models.RecoveryStepAdminReview && !recovery.HasCompletedStep(step) {
+			return models.RecoveryStatusPendingVerification
+		}
+	}
+	if recovery.RequiresStep(models.RecoveryStepAdminReview) && !recovery.HasCompletedStep(models.RecoveryStepAdminReview) {
+		return models.RecoveryStatusPendingReview
+	}
+	return models.RecoveryStatusApproved
+}
+
+func toRecoveryStatusResponse(recovery *models.AccountRecoveryRequest) *models.AccountRecoveryStatusResponse {
+	status := recovery.Status
+	if recovery.IsExpired() && (status == models.RecoveryStatusPendingVerification ||
+		status == models.RecoveryStatusPendingReview || status == models.RecoveryStatusApproved) {
+		status = models.RecoveryStatusExpired
+	}
+
+	return &models.AccountRecoveryStatusResponse{
+		RequestID:      recovery.ID,
+		Status:         status,
+		RequiredSteps:  recovery.RequiredSteps,
+		CompletedSteps: recovery.CompletedSteps,
+		ExpiresAt:      recovery.ExpiresAt,
+	}
+}
+
+func generateRecoverySecret() (string, error) {
+	bytes := make([]byte, recoverySecretLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate recovery secret: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func isNotFound(err error) bool {
+	var appErr *models.AppError
+	return errors.As(err, &appErr) && appErr.Code == 404
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccountRecoveryStore struct {
+	requests        map[uuid.UUID]*models.AccountRecoveryRequest
+	users           map[uuid.UUID]*models.User
+	secondFactorOff []uuid.UUID
+}
+
+func newMockAccountRecoveryStore() *mockAccountRecoveryStore {
+	return &mockAccountRecoveryStore{
+		requests: make(map[uuid.UUID]*models.AccountRecoveryRequest),
+		users:    make(map[uuid.UUID]*models.User),
+	}
+}
+
+func (m *mockAccountRecoveryStore) Create(ctx context.Context, req *models.AccountRecoveryRequest) error {
+	m.requests[req.ID] = req
+	return nil
+}
+
+func (m *mockAccountRecoveryStore) GetByID(ctx context.Context, id uuid.UUID) (*models.AccountRecoveryRequest, error) {
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *req
+	copied.CompletedSteps = append([]string{}, req.CompletedSteps...)
+	if req.UserID != nil {
+		copied.User = m.users[*req.UserID]
+	}
+	return &copied, nil
+}
+
+func (m *mockAccountRecoveryStore) Update(ctx context.Context, req *models.AccountRecoveryRequest) error {
+	m.requests[req.ID] = req
+	return nil
+}
+
+func (m *mockAccountRecoveryStore) List(ctx context.Context, status string, page, pageSize int) ([]*models.AccountRecoveryRequest, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockAccountRecoveryStore) ResetSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	m.secondFactorOff = append(m.secondFactorOff, userID)
+	return nil
+}
+
+// mockRecoveryOTPService accepts "123456" for any destination and records what was sent
+type mockRecoveryOTPService struct {
+	sent []string
+}
+
+func (m *mockRecoveryOTPService) GenerateOTPCode() (string, error) { return "123456", nil }
+
+func (m *mockRecoveryOTPService) SendOTP(ctx context.Context, req *models.SendOTPRequest) error {
+	if req.Email != nil {
+		m.sent = append(m.sent, *req.Email)
+	}
+	if req.Phone != nil {
+		m.sent = append(m.sent, *req.Phone)
+	}
+	return nil
+}
+
+func (m *mockRecoveryOTPService) VerifyOTP(ctx context.Context, req *models.VerifyOTPRequest) (*models.VerifyOTPResponse, error) {
+	return &models.VerifyOTPResponse{Valid: req.Code == "123456"}, nil
+}
+
+func (m *mockRecoveryOTPService) CleanupExpiredOTPs() error { return nil }
+
+type mockPasswordResetter struct {
+	resetFor []uuid.UUID
+}
+
+func (m *mockPasswordResetter) ResetPassword(ctx context.Context, userID uuid.UUID, newPassword, ip, userAgent string) error {
+	m.resetFor = append(m.resetFor, userID)
+	return nil
+}
+
+type accountRecoveryFixture struct {
+	svc       *AccountRecoveryService
+	store     *mockAccountRecoveryStore
+	otp       *mockRecoveryOTPService
+	passwords *mockPasswordResetter
+	notifier  *mockNotificationSender
+	user      *models.User
+}
+
+func newAccountRecoveryFixture(t *testing.T, steps ...string) *accountRecoveryFixture {
+	t.Helper()
+
+	store := newMockAccountRecoveryStore()
+	user := &models.User{
+		ID:                    uuid.New(),
+		Email:                 "user@example.com",
+		Username:              "user",
+		RecoveryEmail:         utils.Ptr("backup@example.com"),
+		RecoveryEmailVerified: true,
+		IsActive:              true,
+	}
+	store.users[user.ID] = user
+
+	userRepo := &mockUserStore{
+		GetByEmailFunc: func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			if email == user.Email {
+				return user, nil
+			}
+			return nil, models.ErrUserNotFound
+		},
+	}
+
+	f := &accountRecoveryFixture{
+		store:     store,
+		otp:       &mockRecoveryOTPService{},
+		passwords: &mockPasswordResetter{},
+		notifier:  &mockNotificationSender{},
+		user:      user,
+	}
+	f.svc = NewAccountRecoveryService(store, userRepo, f.otp, f.passwords, &mockAuditLogger{},
+		config.AccountRecoveryConfig{Steps: steps, RequestTTL: time.Hour, MaxAttempts: 3},
+		logger.New("test", logger.DebugLevel, false),
+		AccountRecoveryServiceOptions{Notifier: f.notifier},
+	)
+	return f
+}
+
+func TestAccountRecoveryService_FullFlow(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail, models.RecoveryStepSMS, models.RecoveryStepAdminReview)
+	ctx := context.Background()
+
+	started, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: "User@Example.com"}, "10.0.0.1", "test")
+	require.NoError(t, err)
+
+	// No verified phone, so only the secondary email step and admin review apply
+	status, err := f.svc.GetStatus(ctx, started.RequestID, started.ClientSecret)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecoveryStatusPendingVerification, status.Status)
+	assert.Equal(t, []string{models.RecoveryStepSecondaryEmail, models.RecoveryStepAdminReview}, status.RequiredSteps)
+	assert.Equal(t, []string{"backup@example.com"}, f.otp.sent)
+	assert.ElementsMatch(t, []string{
+		"user@example.com:" + models.EmailTemplateTypeAccountRecovery,
+		"backup@example.com:" + models.EmailTemplateTypeAccountRecovery,
+	}, f.notifier.sent)
+
+	status, err = f.svc.VerifyStep(ctx, started.RequestID, &models.VerifyAccountRecoveryStepRequest{
+		ClientSecret: started.ClientSecret, Step: models.RecoveryStepSecondaryEmail, Code: "123456",
+	}, "10.0.0.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, models.RecoveryStatusPendingReview, status.Status)
+
+	// Not approved yet
+	err = f.svc.Complete(ctx, started.RequestID, &models.CompleteAccountRecoveryRequest{ClientSecret: started.ClientSecret, NewPassword: "NewPassword1!"}, "", "")
+	require.Error(t, err)
+
+	require.NoError(t, f.svc.Approve(ctx, started.RequestID, uuid.New(), "called the user"))
+
+	require.NoError(t, f.svc.Complete(ctx, started.RequestID, &models.CompleteAccountRecoveryRequest{ClientSecret: started.ClientSecret, NewPassword: "NewPassword1!"}, "", ""))
+	assert.Equal(t, []uuid.UUID{f.user.ID}, f.passwords.resetFor)
+	assert.Equal(t, []uuid.UUID{f.user.ID}, f.store.secondFactorOff)
+	assert.Equal(t, models.RecoveryStatusCompleted, f.store.requests[started.RequestID].Status)
+
+	// Started, approved and completed notifications to both addresses
+	assert.Len(t, f.notifier.sent, 6)
+
+	// The request cannot be reused
+	err = f.svc.Complete(ctx, started.RequestID, &models.CompleteAccountRecoveryRequest{ClientSecret: started.ClientSecret, NewPassword: "Another1!"}, "", "")
+	require.Error(t, err)
+}
+
+func TestAccountRecoveryService_WrongSecret(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail)
+	ctx := context.Background()
+
+	started, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: f.user.Email}, "", "")
+	require.NoError(t, err)
+
+	_, err = f.svc.GetStatus(ctx, started.RequestID, "wrong")
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.Code)
+}
+
+func TestAccountRecoveryService_TooManyFailedAttempts(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail)
+	ctx := context.Background()
+
+	started, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: f.user.Email}, "", "")
+	require.NoError(t, err)
+
+	verify := &models.VerifyAccountRecoveryStepRequest{ClientSecret: started.ClientSecret, Step: models.RecoveryStepSecondaryEmail, Code: "000000"}
+	for i := 0; i < 2; i++ {
+		_, err = f.svc.VerifyStep(ctx, started.RequestID, verify, "", "")
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 400, appErr.Code)
+	}
+
+	_, err = f.svc.VerifyStep(ctx, started.RequestID, verify, "", "")
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 429, appErr.Code)
+	assert.Equal(t, models.RecoveryStatusCancelled, f.store.requests[started.RequestID].Status)
+
+	// Even the right code is rejected now
+	verify.Code = "123456"
+	_, err = f.svc.VerifyStep(ctx, started.RequestID, verify, "", "")
+	require.Error(t, err)
+}
+
+func TestAccountRecoveryService_OwnerRecoversWhileAnotherRequestIsOpen(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail)
+	ctx := context.Background()
+
+	// Anyone knowing the email can call Start
+	attacker, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: f.user.Email}, "203.0.113.7", "")
+	require.NoError(t, err)
+
+	owner, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: f.user.Email}, "198.51.100.1", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, attacker.RequestID, owner.RequestID)
+	assert.Len(t, f.otp.sent, 2)
+
+	// The owner's request has its own secret and attempt counter
+	_, err = f.svc.GetStatus(ctx, owner.RequestID, attacker.ClientSecret)
+	require.Error(t, err)
+	status, err := f.svc.VerifyStep(ctx, owner.RequestID, &models.VerifyAccountRecoveryStepRequest{
+		ClientSecret: owner.ClientSecret, Step: models.RecoveryStepSecondaryEmail, Code: "123456",
+	}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.RecoveryStatusApproved, status.Status)
+	require.NoError(t, f.svc.Complete(ctx, owner.RequestID, &models.CompleteAccountRecoveryRequest{
+		ClientSecret: owner.ClientSecret, NewPassword: "N3w-Passw0rd!",
+	}, "", ""))
+	assert.Equal(t, []uuid.UUID{f.user.ID}, f.passwords.resetFor)
+
+	// Neither request replaced the other
+	assert.Equal(t, models.RecoveryStatusPendingVerification, f.store.requests[attacker.RequestID].Status)
+}
+
+func TestAccountRecoveryService_StartIsRateLimitedPerIPAndEmail(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail)
+	counts := make(map[string]int64)
+	f.svc.cache = &mockCacheService{
+		IncrementRateLimitFunc: func(ctx context.Context, key string, window time.Duration) (int64, error) {
+			assert.Equal(t, time.Hour, window)
+			counts[key]++
+			return counts[key], nil
+		},
+	}
+	ctx := context.Background()
+	start := func(email, ip string) error {
+		_, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: email}, ip, "")
+		return err
+	}
+
+	for i := 0; i < maxRecoveryStartsPerIP; i++ {
+		require.NoError(t, start(f.user.Email, "203.0.113.7"))
+	}
+	var appErr *models.AppError
+	require.ErrorAs(t, start(f.user.Email, "203.0.113.7"), &appErr)
+	assert.Equal(t, 429, appErr.Code)
+
+	// One IP can't use up the allowance of the account, so its owner can still start
+	require.NoError(t, start(f.user.Email, "198.51.100.1"))
+
+	// The email limit applies to unknown emails too, so it doesn't reveal which have accounts
+	for i := 0; i < maxRecoveryStartsPerEmail; i++ {
+		_ = start("nobody@example.com", fmt.Sprintf("192.0.2.%d", i))
+	}
+	require.ErrorAs(t, start("nobody@example.com", "192.0.2.200"), &appErr)
+	assert.Equal(t, 429, appErr.Code)
+}
+
+func TestAccountRecoveryService_UnknownEmail(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSecondaryEmail, models.RecoveryStepAdminReview)
+	ctx := context.Background()
+
+	started, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: "nobody@example.com"}, "", "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, started.ClientSecret)
+	assert.Empty(t, f.otp.sent)
+	assert.Empty(t, f.notifier.sent)
+
+	status, err := f.svc.GetStatus(ctx, started.RequestID, started.ClientSecret)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecoveryStatusPendingVerification, status.Status)
+
+	// A correct-looking code never verifies a request without an account
+	_, err = f.svc.VerifyStep(ctx, started.RequestID, &models.VerifyAccountRecoveryStepRequest{
+		ClientSecret: started.ClientSecret, Step: models.RecoveryStepSecondaryEmail, Code: "123456",
+	}, "", "")
+	require.Error(t, err)
+}
+
+func TestAccountRecoveryService_AdminReviewFallback(t *testing.T) {
+	f := newAccountRecoveryFixture(t, models.RecoveryStepSMS)
+	ctx := context.Background()
+
+	// The user has no verified phone, so admin review is the only way to prove identity
+	started, err := f.svc.Start(ctx, &models.StartAccountRecoveryRequest{Email: f.user.Email}, "", "")
+	require.NoError(t, err)
+
+	status, err := f.svc.GetStatus(ctx, started.RequestID, started.ClientSecret)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecoveryStatusPendingReview, status.Status)
+	assert.Equal(t, []string{models.RecoveryStepAdminReview}, status.RequiredSteps)
+
+	require.NoError(t, f.svc.Reject(ctx, started.RequestID, uuid.New(), "could not confirm identity"))
+	assert.Equal(t, models.RecoveryStatusRejected, f.store.requests[started.RequestID].Status)
+
+	err = f.svc.Approve(ctx, started.RequestID, uuid.New(), "")
+	require.Error(t, err)
+}
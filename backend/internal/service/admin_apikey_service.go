@@ -53,28 +53,7 @@ func (s *AdminAPIKeyService) ListAPIKeys(ctx context.Context, appID *uuid.UUID,
 	for i := start; i < end; i++ {
 		key := apiKeys[i]
 		user, _ := s.userRepo.GetByID(ctx, key.UserID, nil)
-
-		var scopes []string
-		if err := json.Unmarshal(key.Scopes, &scopes); err != nil {
-			scopes = []string{}
-		}
-
-		resp := &models.AdminAPIKeyResponse{
-			ID:         key.ID,
-			UserID:     key.UserID,
-			Name:       key.Name,
-			KeyPrefix:  key.KeyPrefix,
-			Scopes:     scopes,
-			ExpiresAt:  key.ExpiresAt,
-			LastUsedAt: key.LastUsedAt,
-			IsActive:   key.IsActive,
-			CreatedAt:  key.CreatedAt,
-		}
-		if user != nil {
-			resp.Username = user.Username
-			resp.UserEmail = user.Email
-			resp.OwnerName = user.FullName
-		}
+		resp := toAdminAPIKeyResponse(key, user)
 		adminAPIKeys = append(adminAPIKeys, resp)
 	}
 
@@ -89,6 +68,32 @@ func (s *AdminAPIKeyService) ListAPIKeys(ctx context.Context, appID *uuid.UUID,
 	}, nil
 }
 
+// toAdminAPIKeyResponse converts an API key and its (optional) owner into the admin API representation
+func toAdminAPIKeyResponse(key *models.APIKey, user *models.User) *models.AdminAPIKeyResponse {
+	var scopes []string
+	if err := json.Unmarshal(key.Scopes, &scopes); err != nil {
+		scopes = []string{}
+	}
+
+	resp := &models.AdminAPIKeyResponse{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		IsActive:   key.IsActive,
+		CreatedAt:  key.CreatedAt,
+	}
+	if user != nil {
+		resp.Username = user.Username
+		resp.UserEmail = user.Email
+		resp.OwnerName = user.FullName
+	}
+	return resp
+}
+
 func (s *AdminAPIKeyService) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error {
 	return s.apiKeyRepo.Revoke(ctx, keyID)
 }
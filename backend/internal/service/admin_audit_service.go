@@ -4,16 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 )
 
+const (
+	// auditStatsDefaultRange is the range of audit statistics requested without one
+	auditStatsDefaultRange = 24 * time.Hour
+	// auditStatsMaxRange limits the range of audit statistics
+	auditStatsMaxRange = 31 * 24 * time.Hour
+	// auditStatsMaxActions limits the actions of the top actions statistics
+	auditStatsMaxActions = 100
+)
+
+var errAuditStatsInvalidRange = models.NewAppError(http.StatusBadRequest, "Range must end after it starts and span at most 31 days")
+
 type AdminAuditService struct {
 	auditRepo AuditStore
 }
 
-func (s *AdminAuditService) ListAuditLogs(ctx context.Context, page, pageSize int, userID *uuid.UUID) (*models.AuditLogListResponse, error) {
+func (s *AdminAuditService) ListAuditLogs(ctx context.Context, page, pageSize int, opts ...AuditLogListOption) (*models.AuditLogListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -23,28 +35,9 @@ func (s *AdminAuditService) ListAuditLogs(ctx context.Context, page, pageSize in
 
 	offset := (page - 1) * pageSize
 
-	var logs []*models.AuditLog
-	var total int
-	var err error
-
-	if userID != nil {
-		logs, err = s.auditRepo.GetByUserID(ctx, *userID, pageSize, offset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list audit logs: %w", err)
-		}
-		total = len(logs)
-		if len(logs) == pageSize {
-			total = page * pageSize
-		}
-	} else {
-		logs, err = s.auditRepo.List(ctx, pageSize, offset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list audit logs: %w", err)
-		}
-		total, err = s.auditRepo.Count(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to count audit logs: %w", err)
-		}
+	logs, total, err := s.auditRepo.Search(ctx, pageSize, offset, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
 	}
 
 	adminLogs := make([]*models.AdminAuditLogResponse, 0, len(logs))
@@ -55,14 +48,18 @@ func (s *AdminAuditService) ListAuditLogs(ctx context.Context, page, pageSize in
 		}
 
 		resp := &models.AdminAuditLogResponse{
-			ID:        log.ID,
-			UserID:    log.UserID,
-			Action:    string(log.Action),
-			Status:    string(log.Status),
-			IP:        log.IPAddress,
-			UserAgent: log.UserAgent,
-			Details:   details,
-			CreatedAt: log.CreatedAt,
+			ID:           log.ID,
+			UserID:       log.UserID,
+			Action:       string(log.Action),
+			Status:       string(log.Status),
+			IP:           log.IPAddress,
+			UserAgent:    log.UserAgent,
+			ResourceType: log.ResourceType,
+			ResourceID:   log.ResourceID,
+			CountryCode:  log.CountryCode,
+			City:         log.City,
+			Details:      details,
+			CreatedAt:    log.CreatedAt,
 		}
 
 		if log.User != nil {
@@ -82,3 +79,69 @@ func (s *AdminAuditService) ListAuditLogs(ctx context.Context, page, pageSize in
 		TotalPages: totalPages,
 	}, nil
 }
+
+// GetAuditActionStats returns the most frequent actions among the audit logs matching the
+// filters, with how many did not succeed. Without a range the last 24 hours are counted.
+func (s *AdminAuditService) GetAuditActionStats(ctx context.Context, limit int, opts ...AuditLogListOption) (*models.AuditActionStatsResponse, error) {
+	if limit < 1 || limit > auditStatsMaxActions {
+		limit = 10
+	}
+	from, to, err := auditStatsRange(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, AuditLogListFrom(from), AuditLogListTo(to))
+	actions, err := s.auditRepo.CountByAction(ctx, limit, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit action stats: %w", err)
+	}
+
+	return &models.AuditActionStatsResponse{From: from, To: to, Actions: actions}, nil
+}
+
+// GetAuditFailureStats counts the unsuccessful audit logs matching the filters per hour.
+// Without a range the last 24 hours are counted; the range starts at a whole hour.
+func (s *AdminAuditService) GetAuditFailureStats(ctx context.Context, opts ...AuditLogListOption) (*models.AuditFailureStatsResponse, error) {
+	from, to, err := auditStatsRange(opts)
+	if err != nil {
+		return nil, err
+	}
+	from = from.Truncate(time.Hour)
+
+	opts = append(opts, AuditLogListFrom(from), AuditLogListTo(to))
+	counts, err := s.auditRepo.CountFailuresByHour(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit failure stats: %w", err)
+	}
+
+	// Every hour is listed, so charts need not fill the gaps
+	byHour := make(map[time.Time]int, len(counts))
+	for _, c := range counts {
+		byHour[c.Hour.UTC()] = c.Count
+	}
+	resp := &models.AuditFailureStatsResponse{From: from, To: to, Hours: make([]*models.AuditHourlyCount, 0)}
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		count := byHour[hour]
+		resp.Hours = append(resp.Hours, &models.AuditHourlyCount{Hour: hour, Count: count})
+		resp.Total += count
+	}
+	return resp, nil
+}
+
+// auditStatsRange returns the range of audit statistics, in UTC
+func auditStatsRange(opts []AuditLogListOption) (time.Time, time.Time, error) {
+	o := BuildAuditLogListOptions(opts)
+	to := time.Now().UTC()
+	if o.To != nil {
+		to = o.To.UTC()
+	}
+	from := to.Add(-auditStatsDefaultRange)
+	if o.From != nil {
+		from = o.From.UTC()
+	}
+	if !to.After(from) || to.Sub(from) > auditStatsMaxRange {
+		return time.Time{}, time.Time{}, errAuditStatsInvalidRange
+	}
+	return from, to, nil
+}
@@ -142,7 +142,7 @@ func (s *AdminBulkService) ImportUsers(ctx context.Context, req *models.BulkImpo
 					existingUser.FullName = utils.SanitizeHTML(entry.FullName)
 				}
 				if entry.IsActive != nil {
-					existingUser.IsActive = *entry.IsActive
+					existingUser.SetActive(*entry.IsActive)
 				}
 				if entry.PasswordHashImport != "" {
 					existingUser.PasswordHash = entry.PasswordHashImport
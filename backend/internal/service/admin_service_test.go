@@ -9,6 +9,7 @@ import (
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAdminService_GetStats(t *testing.T) {
@@ -95,7 +96,7 @@ func TestAdminService_ListUsers(t *testing.T) {
 			return []*models.OAuthAccount{{}}, nil
 		}
 
-		resp, err := svc.ListUsers(ctx, nil, 1, 10)
+		resp, err := svc.ListUsers(ctx, nil, "", 1, 10)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(resp.Users))
 		assert.Equal(t, "test", resp.Users[0].Username)
@@ -156,3 +157,53 @@ func TestAdminService_UpdateUser(t *testing.T) {
 		assert.False(t, resp.IsActive)
 	})
 }
+
+func TestAdminService_GetAuditFailureStats(t *testing.T) {
+	mockAudit := &mockAuditStore{}
+	svc := &AdminAuditService{auditRepo: mockAudit}
+	ctx := context.Background()
+	from := time.Date(2025, 5, 1, 9, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 1, 13, 0, 0, 0, time.UTC)
+
+	t.Run("FillsEmptyHours", func(t *testing.T) {
+		mockAudit.CountFailuresByHourFunc = func(ctx context.Context, opts ...AuditLogListOption) ([]*models.AuditHourlyCount, error) {
+			o := BuildAuditLogListOptions(opts)
+			assert.Equal(t, time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC), *o.From)
+			assert.Equal(t, []string{"signin"}, o.Actions)
+			return []*models.AuditHourlyCount{
+				{Hour: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC), Count: 4},
+				{Hour: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC), Count: 1},
+			}, nil
+		}
+
+		resp, err := svc.GetAuditFailureStats(ctx, AuditLogListActions("signin"), AuditLogListFrom(from), AuditLogListTo(to))
+		require.NoError(t, err)
+		require.Len(t, resp.Hours, 4)
+		assert.Equal(t, []int{0, 4, 0, 1}, []int{resp.Hours[0].Count, resp.Hours[1].Count, resp.Hours[2].Count, resp.Hours[3].Count})
+		assert.Equal(t, 5, resp.Total)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		_, err := svc.GetAuditFailureStats(ctx, AuditLogListFrom(to), AuditLogListTo(from))
+		assert.Equal(t, errAuditStatsInvalidRange, err)
+	})
+}
+
+func TestAdminService_GetAuditActionStats(t *testing.T) {
+	mockAudit := &mockAuditStore{}
+	svc := &AdminAuditService{auditRepo: mockAudit}
+
+	mockAudit.CountByActionFunc = func(ctx context.Context, limit int, opts ...AuditLogListOption) ([]*models.AuditActionCount, error) {
+		assert.Equal(t, 10, limit)
+		o := BuildAuditLogListOptions(opts)
+		require.NotNil(t, o.From)
+		require.NotNil(t, o.To)
+		assert.Equal(t, 24*time.Hour, o.To.Sub(*o.From))
+		return []*models.AuditActionCount{{Action: "signin", Total: 3, Failed: 1}}, nil
+	}
+
+	resp, err := svc.GetAuditActionStats(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, resp.Actions, 1)
+	assert.Equal(t, "signin", resp.Actions[0].Action)
+}
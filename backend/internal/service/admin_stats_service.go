@@ -45,7 +45,7 @@ func (s *AdminStatsService) GetStats(ctx context.Context) (*models.AdminStatsRes
 		if user.PhoneVerified {
 			stats.VerifiedPhoneUsers++
 		}
-		if user.TOTPEnabled {
+		if user.TwoFactorEnabled() {
 			stats.Users2FAEnabled++
 		}
 
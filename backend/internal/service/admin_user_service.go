@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/uptrace/bun"
 )
@@ -22,9 +24,34 @@ type AdminUserService struct {
 	appRepo        ApplicationStore
 	bcryptCost     int
 	db             TransactionDB
+	passwordPolicy PasswordPolicyEnforcer
+	credentials    WebAuthnCredentialStore
+	lifecycle      *UserLifecycleService
+	outbox         OutboxRecorder
 }
 
-func (s *AdminUserService) ListUsers(ctx context.Context, appID *uuid.UUID, page, pageSize int) (*models.AdminUserListResponse, error) {
+// SetPasswordPolicy applies policy to the passwords admins set for new users
+func (s *AdminUserService) SetPasswordPolicy(policy PasswordPolicyEnforcer) {
+	s.passwordPolicy = policy
+}
+
+// SetWebAuthnCredentials lets 2FA resets remove the user's security keys
+func (s *AdminUserService) SetWebAuthnCredentials(credentials WebAuthnCredentialStore) {
+	s.credentials = credentials
+}
+
+// SetUserLifecycle makes state changes revoke access, audit and trigger webhooks through lifecycle
+func (s *AdminUserService) SetUserLifecycle(lifecycle *UserLifecycleService) {
+	s.lifecycle = lifecycle
+}
+
+// SetOutbox audits user creation and triggers the user.created webhook through outbox, in
+// the transaction that creates the user
+func (s *AdminUserService) SetOutbox(outbox OutboxRecorder) {
+	s.outbox = outbox
+}
+
+func (s *AdminUserService) ListUsers(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -36,7 +63,12 @@ func (s *AdminUserService) ListUsers(ctx context.Context, appID *uuid.UUID, page
 	var total int
 	var err error
 
-	if appID != nil {
+	if search = strings.TrimSpace(search); search != "" {
+		users, total, err = s.userRepo.Search(ctx, search, pageSize, (page-1)*pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search users: %w", err)
+		}
+	} else if appID != nil {
 		var profiles []*models.UserApplicationProfile
 		profiles, total, err = s.appRepo.ListApplicationUsers(ctx, *appID, page, pageSize)
 		if err != nil {
@@ -60,6 +92,62 @@ func (s *AdminUserService) ListUsers(ctx context.Context, appID *uuid.UUID, page
 		}
 	}
 
+	totalPages := (total + pageSize - 1) / pageSize
+
+	return &models.AdminUserListResponse{
+		Users:      s.usersToAdminResponses(ctx, users),
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// SearchUsers lists the users matching the filters, newest first. Pages are numbered unless
+// opts continue after a cursor, which skips counting the matches.
+func (s *AdminUserService) SearchUsers(ctx context.Context, page, pageSize int, opts ...UserFilterOption) (*models.AdminUserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	byCursor := BuildUserFilterOptions(opts).AfterCreatedAt != nil
+	offset := 0
+	if !byCursor {
+		offset = (page - 1) * pageSize
+	}
+
+	// One user more than the page tells whether there is a next one
+	users, err := s.userRepo.Filter(ctx, pageSize+1, offset, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	response := &models.AdminUserListResponse{PageSize: pageSize}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		last := users[len(users)-1]
+		response.NextCursor = models.EncodeUserCursor(last.CreatedAt, last.ID)
+	}
+	if !byCursor {
+		total, err := s.userRepo.CountFiltered(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count users: %w", err)
+		}
+		response.Total = total
+		response.Page = page
+		response.TotalPages = (total + pageSize - 1) / pageSize
+	}
+	response.Users = s.usersToAdminResponses(ctx, users)
+
+	return response, nil
+}
+
+// usersToAdminResponses converts users for admin listings, with their API key and OAuth
+// account counts
+func (s *AdminUserService) usersToAdminResponses(ctx context.Context, users []*models.User) []*models.AdminUserResponse {
 	adminUsers := make([]*models.AdminUserResponse, 0, len(users))
 	for _, user := range users {
 		adminUser := s.userToAdminResponse(user)
@@ -76,16 +164,7 @@ func (s *AdminUserService) ListUsers(ctx context.Context, appID *uuid.UUID, page
 
 		adminUsers = append(adminUsers, adminUser)
 	}
-
-	totalPages := (total + pageSize - 1) / pageSize
-
-	return &models.AdminUserListResponse{
-		Users:      adminUsers,
-		Total:      total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
+	return adminUsers
 }
 
 func (s *AdminUserService) GetUser(ctx context.Context, userID uuid.UUID) (*models.AdminUserResponse, error) {
@@ -113,7 +192,7 @@ func (s *AdminUserService) CreateUser(ctx context.Context, req *models.AdminCrea
 	user := &models.User{
 		ID:            uuid.New(),
 		Email:         req.Email,
-		Username:      utils.SanitizeUsername(req.Username),
+		Username:      utils.NormalizeUsername(utils.SanitizeUsername(req.Username)),
 		FullName:      utils.SanitizeHTML(req.FullName),
 		AccountType:   req.AccountType,
 		IsActive:      true,
@@ -124,7 +203,19 @@ func (s *AdminUserService) CreateUser(ctx context.Context, req *models.AdminCrea
 		user.AccountType = string(models.AccountTypeHuman)
 	}
 
+	if req.Invite {
+		user.SetState(models.UserStateInvited)
+		req.Password = ""
+	} else if req.Password == "" {
+		return nil, models.NewAppError(400, "Password is required unless the user is invited")
+	}
+
 	if req.Password != "" {
+		if s.passwordPolicy != nil {
+			if err := s.passwordPolicy.CheckPassword(ctx, req.Password, nil); err != nil {
+				return nil, err
+			}
+		}
 		hash, err := utils.HashPassword(req.Password, s.bcryptCost)
 		if err != nil {
 			return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -159,16 +250,43 @@ func (s *AdminUserService) CreateUser(ctx context.Context, req *models.AdminCrea
 			}
 		}
 
-		return nil
+		return s.recordCreated(ctx, tx, user, adminID, req.Invite)
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if s.outbox != nil {
+		s.outbox.Wake()
+	}
 
 	return s.GetUser(ctx, user.ID)
 }
 
+// recordCreated records the audit log and the user.created webhook of a user an admin
+// created in tx
+func (s *AdminUserService) recordCreated(ctx context.Context, tx bun.Tx, user *models.User, adminID uuid.UUID, invited bool) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	err := s.outbox.RecordAudit(ctx, tx, AuditLogParams{
+		UserID: &user.ID,
+		Action: models.ActionCreate,
+		Status: models.StatusSuccess,
+		Details: map[string]interface{}{
+			"resource_type": "user",
+			"actor_id":      adminID.String(),
+			"invited":       invited,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.outbox.RecordWebhook(ctx, tx, models.WebhookEventUserCreated, userCreatedWebhookData(user, nil))
+}
+
 func (s *AdminUserService) UpdateUser(ctx context.Context, userID uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, userID, nil)
 	if err != nil {
@@ -184,8 +302,14 @@ func (s *AdminUserService) UpdateUser(ctx context.Context, userID uuid.UUID, req
 		}
 	}
 
-	if req.IsActive != nil {
-		user.IsActive = *req.IsActive
+	if req.IsActive != nil && *req.IsActive != user.IsActive {
+		state := models.UserStateDeactivated
+		if *req.IsActive {
+			state = models.UserStateActive
+		}
+		if err := s.changeState(ctx, user, state, "", adminID, "", ""); err != nil {
+			return nil, err
+		}
 	}
 
 	if req.Email != nil && *req.Email != "" {
@@ -193,7 +317,7 @@ func (s *AdminUserService) UpdateUser(ctx context.Context, userID uuid.UUID, req
 	}
 
 	if req.Username != nil && *req.Username != "" {
-		user.Username = utils.SanitizeUsername(*req.Username)
+		user.Username = utils.NormalizeUsername(utils.SanitizeUsername(*req.Username))
 	}
 
 	if req.FullName != nil {
@@ -201,7 +325,15 @@ func (s *AdminUserService) UpdateUser(ctx context.Context, userID uuid.UUID, req
 	}
 
 	if req.Phone != nil {
-		user.Phone = req.Phone
+		if *req.Phone == "" {
+			user.Phone = nil
+		} else {
+			phone := utils.NormalizePhone(*req.Phone)
+			if !utils.IsValidPhone(phone) {
+				return nil, models.NewAppError(400, "Invalid phone format")
+			}
+			user.Phone = &phone
+		}
 	}
 
 	if req.EmailVerified != nil {
@@ -215,34 +347,87 @@ func (s *AdminUserService) UpdateUser(ctx context.Context, userID uuid.UUID, req
 	return s.GetUser(ctx, userID)
 }
 
-func (s *AdminUserService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+func (s *AdminUserService) DeleteUser(ctx context.Context, userID, adminID uuid.UUID) error {
 	user, err := s.userRepo.GetByID(ctx, userID, nil)
 	if err != nil {
 		return err
 	}
 
-	userRoles, err := s.rbacRepo.GetUserRoles(ctx, userID)
-	if err == nil {
-		isAdmin := false
-		var adminRoleID uuid.UUID
-		for _, role := range userRoles {
-			if role.Name == string(models.RoleAdmin) {
-				isAdmin = true
-				adminRoleID = role.ID
-				break
-			}
-		}
+	if s.isLastAdmin(ctx, userID) {
+		return models.NewAppError(400, "Cannot delete the last admin user")
+	}
 
-		if isAdmin {
-			admins, err := s.rbacRepo.GetUsersWithRole(ctx, adminRoleID)
-			if err == nil && len(admins) <= 1 {
-				return models.NewAppError(400, "Cannot delete the last admin user")
-			}
+	return s.changeState(ctx, user, models.UserStateDeleted, StateReasonDeleted, adminID, "", "")
+}
+
+// UpdateUserState moves a user to another lifecycle state
+func (s *AdminUserService) UpdateUserState(ctx context.Context, userID uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error) {
+	if !req.State.IsValid() || req.State == models.UserStateInvited {
+		return nil, models.ErrInvalidUserState
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.State.CanSignIn() && s.isLastAdmin(ctx, userID) {
+		return nil, models.NewAppError(400, "Cannot disable the last admin user")
+	}
+
+	if err := s.changeState(ctx, user, req.State, req.Reason, adminID, ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, userID)
+}
+
+// changeState moves user to state, through the lifecycle service when one is set so tokens are
+// revoked and the change is audited. user is updated in place either way.
+func (s *AdminUserService) changeState(ctx context.Context, user *models.User, state models.UserState, reason string, adminID uuid.UUID, ip, userAgent string) error {
+	if s.lifecycle == nil {
+		from := user.LifecycleState()
+		if from == state {
+			return nil
 		}
+		if !from.CanTransitionTo(state) {
+			return models.NewAppError(409, "Invalid user state transition",
+				fmt.Sprintf("A %s user cannot become %s", from, state))
+		}
+		user.SetState(state)
+		return s.userRepo.Update(ctx, user)
 	}
 
-	user.IsActive = false
-	return s.userRepo.Update(ctx, user)
+	updated, err := s.lifecycle.Transition(ctx, UserStateTransitionParams{
+		UserID:    user.ID,
+		State:     state,
+		Reason:    reason,
+		ActorID:   &adminID,
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return err
+	}
+	user.State = updated.State
+	user.IsActive = updated.IsActive
+	user.StateChangedAt = updated.StateChangedAt
+	return nil
+}
+
+// isLastAdmin reports whether userID is the only holder of the admin role
+func (s *AdminUserService) isLastAdmin(ctx context.Context, userID uuid.UUID) bool {
+	userRoles, err := s.rbacRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return false
+	}
+	for _, role := range userRoles {
+		if role.Name == string(models.RoleAdmin) {
+			admins, err := s.rbacRepo.GetUsersWithRole(ctx, role.ID)
+			return err == nil && len(admins) <= 1
+		}
+	}
+	return false
 }
 
 func (s *AdminUserService) AdminReset2FA(ctx context.Context, userID, adminID uuid.UUID) error {
@@ -251,7 +436,7 @@ func (s *AdminUserService) AdminReset2FA(ctx context.Context, userID, adminID uu
 		return err
 	}
 
-	if !user.TOTPEnabled {
+	if !user.TwoFactorEnabled() {
 		return models.NewAppError(400, "2FA is not enabled for this user")
 	}
 
@@ -260,6 +445,12 @@ func (s *AdminUserService) AdminReset2FA(ctx context.Context, userID, adminID uu
 			return err
 		}
 
+		if s.credentials != nil {
+			if err := s.credentials.DeleteAllByUserID(ctx, userID); err != nil {
+				return err
+			}
+		}
+
 		if err := s.backupCodeRepo.DeleteAllByUserID(ctx, userID); err != nil {
 			return err
 		}
@@ -278,6 +469,7 @@ func (s *AdminUserService) AdminReset2FA(ctx context.Context, userID, adminID uu
 		Status:    string(models.StatusSuccess),
 		CreatedAt: time.Now(),
 		Details:   []byte(fmt.Sprintf(`{"target_user_id":"%s","admin_id":"%s"}`, userID, adminID)),
+		TraceID:   tracing.TraceID(ctx),
 	}
 
 	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
@@ -355,6 +547,11 @@ func (s *AdminUserService) RemoveRole(ctx context.Context, userID, roleID uuid.U
 }
 
 func (s *AdminUserService) userToAdminResponse(user *models.User) *models.AdminUserResponse {
+	return toAdminUserResponse(user)
+}
+
+// toAdminUserResponse converts a user with its roles loaded into the admin API representation
+func toAdminUserResponse(user *models.User) *models.AdminUserResponse {
 	roles := make([]models.RoleInfo, 0, len(user.Roles))
 	for _, role := range user.Roles {
 		roles = append(roles, models.RoleInfo{
@@ -376,8 +573,12 @@ func (s *AdminUserService) userToAdminResponse(user *models.User) *models.AdminU
 		EmailVerified:     user.EmailVerified,
 		PhoneVerified:     user.PhoneVerified,
 		IsActive:          user.IsActive,
+		State:             user.LifecycleState(),
+		StateChangedAt:    user.StateChangedAt,
 		TOTPEnabled:       user.TOTPEnabled,
 		TOTPEnabledAt:     user.TOTPEnabledAt,
+		WebAuthnEnabled:   user.WebAuthnEnabled,
+		LastLoginAt:       user.LastLoginAt,
 		CreatedAt:         user.CreatedAt,
 		UpdatedAt:         user.UpdatedAt,
 	}
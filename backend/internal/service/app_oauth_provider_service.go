@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
@@ -58,6 +59,11 @@ func (s *AppOAuthProviderService) Create(ctx context.Context, appID uuid.UUID, r
 		scopes = []string{}
 	}
 
+	var claimMapping models.OIDCClaimMapping
+	if req.ClaimMapping != nil {
+		claimMapping = trimClaimMapping(*req.ClaimMapping)
+	}
+
 	oauthProvider := &models.ApplicationOAuthProvider{
 		ID:            uuid.New(),
 		ApplicationID: appID,
@@ -69,6 +75,11 @@ func (s *AppOAuthProviderService) Create(ctx context.Context, appID uuid.UUID, r
 		AuthURL:       strings.TrimSpace(req.AuthURL),
 		TokenURL:      strings.TrimSpace(req.TokenURL),
 		UserInfoURL:   strings.TrimSpace(req.UserInfoURL),
+		TeamID:        strings.TrimSpace(req.TeamID),
+		KeyID:         strings.TrimSpace(req.KeyID),
+		TenantID:      strings.TrimSpace(req.TenantID),
+		Issuer:        strings.TrimSpace(req.Issuer),
+		ClaimMapping:  claimMapping,
 		IsActive:      true,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -156,6 +167,21 @@ func (s *AppOAuthProviderService) Update(ctx context.Context, id uuid.UUID, req
 	if req.UserInfoURL != nil {
 		provider.UserInfoURL = strings.TrimSpace(*req.UserInfoURL)
 	}
+	if req.TeamID != nil {
+		provider.TeamID = strings.TrimSpace(*req.TeamID)
+	}
+	if req.KeyID != nil {
+		provider.KeyID = strings.TrimSpace(*req.KeyID)
+	}
+	if req.TenantID != nil {
+		provider.TenantID = strings.TrimSpace(*req.TenantID)
+	}
+	if req.Issuer != nil {
+		provider.Issuer = strings.TrimSpace(*req.Issuer)
+	}
+	if req.ClaimMapping != nil {
+		provider.ClaimMapping = trimClaimMapping(*req.ClaimMapping)
+	}
 	if req.IsActive != nil {
 		provider.IsActive = *req.IsActive
 	}
@@ -219,6 +245,28 @@ func (s *AppOAuthProviderService) validateProviderConfig(provider string, req *m
 		"github":    true,
 		"yandex":    true,
 		"instagram": true,
+		"apple":     true,
+		"microsoft": true,
+		"linkedin":  true,
+	}
+
+	// Sign in with Apple signs its client secret with the private key (.p8) given as client_secret
+	if provider == string(models.ProviderApple) {
+		if strings.TrimSpace(req.TeamID) == "" || strings.TrimSpace(req.KeyID) == "" {
+			return ErrMissingRequiredCredentials
+		}
+		if _, err := jwt.ParseECPrivateKeyFromPEM([]byte(clientSecret)); err != nil {
+			return ErrMissingRequiredCredentials
+		}
+	}
+
+	// A generic OpenID Connect provider is found by its issuer; without one, the
+	// authorization and token endpoints have to be given
+	if provider == string(models.ProviderOIDCGeneric) {
+		if strings.TrimSpace(req.Issuer) == "" && (strings.TrimSpace(req.AuthURL) == "" || strings.TrimSpace(req.TokenURL) == "") {
+			return ErrInvalidOAuthProvider
+		}
+		return nil
 	}
 
 	if !knownProviders[provider] {
@@ -233,3 +281,15 @@ func (s *AppOAuthProviderService) validateProviderConfig(provider string, req *m
 
 	return nil
 }
+
+// trimClaimMapping removes surrounding whitespace from the claim names of a mapping
+func trimClaimMapping(mapping models.OIDCClaimMapping) models.OIDCClaimMapping {
+	return models.OIDCClaimMapping{
+		Subject:       strings.TrimSpace(mapping.Subject),
+		Email:         strings.TrimSpace(mapping.Email),
+		EmailVerified: strings.TrimSpace(mapping.EmailVerified),
+		Name:          strings.TrimSpace(mapping.Name),
+		Username:      strings.TrimSpace(mapping.Username),
+		Picture:       strings.TrimSpace(mapping.Picture),
+	}
+}
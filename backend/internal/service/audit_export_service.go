@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/auditexport"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	auditExportJob      = "audit.export"
+	auditExportPageSize = 1000
+	// auditExportMaxAttempts lets an export ride out a day-long outage of its destination
+	auditExportMaxAttempts = 30
+	// auditExportMaxWindows limits the windows a sink schedules at once, so a sink that fell
+	// behind catches up over a few runs
+	auditExportMaxWindows = 60
+	// auditExportReplayChunk is the shortest window of a replay job
+	auditExportReplayChunk = time.Hour
+	// auditExportMaxReplay limits the range of a replay request
+	auditExportMaxReplay = 31 * 24 * time.Hour
+)
+
+var (
+	errAuditExportSinkNotFound = models.NewAppError(http.StatusNotFound, "Audit export sink not configured")
+	errAuditExportInvalidRange = models.NewAppError(http.StatusBadRequest, "Replay range must end after it starts and span at most 31 days")
+)
+
+// auditExportJobPayload is the window of logs an export job sends to a sink
+type auditExportJobPayload struct {
+	Sink string    `json:"sink"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// AuditExportService exports audit logs to SIEM sinks (syslog, S3). The logs are exported by
+// time window: windows are scheduled once they ended more than delay ago, which leaves time
+// for audit logs written asynchronously, and each is exported by a background job, retried
+// until the sink accepts it. A cursor per sink records the windows scheduled, so each is
+// scheduled by one instance only. Past ranges can be replayed.
+type AuditExportService struct {
+	sinks   map[string]auditexport.Sink
+	names   []string
+	cursors AuditExportStore
+	logs    AuditRangeStore
+	jobs    *JobQueueService
+	delay   time.Duration
+	logger  *logger.Logger
+	now     func() time.Time
+}
+
+// NewAuditExportService creates an audit export service exporting to sinks
+func NewAuditExportService(sinks []auditexport.Sink, cursors AuditExportStore, logs AuditRangeStore, jobs *JobQueueService, delay time.Duration, log *logger.Logger) *AuditExportService {
+	s := &AuditExportService{
+		sinks:   make(map[string]auditexport.Sink, len(sinks)),
+		cursors: cursors,
+		logs:    logs,
+		jobs:    jobs,
+		delay:   delay,
+		logger:  log,
+		now:     time.Now,
+	}
+	for _, sink := range sinks {
+		s.sinks[sink.Name()] = sink
+		s.names = append(s.names, sink.Name())
+	}
+	jobs.Register(auditExportJob, s.runExportJob)
+	return s
+}
+
+// ScheduleDue queues export jobs for the windows of each sink that are due, and returns how
+// many it queued. A sink's first window is the one under way when it is first scheduled;
+// earlier logs are exported by replaying them.
+func (s *AuditExportService) ScheduleDue(ctx context.Context) int {
+	queued := 0
+	for _, name := range s.names {
+		queued += s.scheduleSink(ctx, s.sinks[name])
+	}
+	return queued
+}
+
+func (s *AuditExportService) scheduleSink(ctx context.Context, sink auditexport.Sink) int {
+	interval := sink.Interval()
+	horizon := s.now().UTC().Add(-s.delay)
+
+	from, err := s.cursors.GetCursor(ctx, sink.Name(), horizon.Truncate(interval))
+	if err != nil {
+		s.logger.Error("failed to get audit export cursor", map[string]interface{}{
+			"sink":  sink.Name(),
+			"error": err.Error(),
+		})
+		return 0
+	}
+	from = from.UTC()
+
+	queued := 0
+	for ; queued < auditExportMaxWindows; queued++ {
+		to := from.Add(interval)
+		if to.After(horizon) {
+			break
+		}
+		// Another instance may have scheduled the window first
+		advanced, err := s.cursors.AdvanceCursor(ctx, sink.Name(), from, to)
+		if err != nil || !advanced {
+			break
+		}
+		if err := s.enqueue(ctx, sink.Name(), from, to); err != nil {
+			// Hand the window back, so it is scheduled again
+			if _, err := s.cursors.AdvanceCursor(ctx, sink.Name(), to, from); err != nil {
+				s.logger.Error("failed to reset audit export cursor", map[string]interface{}{
+					"sink":  sink.Name(),
+					"from":  from,
+					"error": err.Error(),
+				})
+			}
+			break
+		}
+		from = to
+	}
+	return queued
+}
+
+// Replay queues export jobs for the logs created between from and to, widened to whole
+// intervals of the sink. Jobs cover an hour each, or an interval if that is longer.
+func (s *AuditExportService) Replay(ctx context.Context, sinkName string, from, to time.Time) (*models.AuditExportReplayResponse, error) {
+	sink, ok := s.sinks[sinkName]
+	if !ok {
+		return nil, errAuditExportSinkNotFound
+	}
+	if !to.After(from) || to.Sub(from) > auditExportMaxReplay {
+		return nil, errAuditExportInvalidRange
+	}
+
+	interval := sink.Interval()
+	from = from.UTC().Truncate(interval)
+	if end := to.UTC().Truncate(interval); end.Before(to) {
+		to = end.Add(interval)
+	} else {
+		to = end
+	}
+	chunk := auditExportReplayChunk
+	if interval > chunk {
+		chunk = interval
+	}
+
+	resp := &models.AuditExportReplayResponse{Sink: sinkName, From: from, To: to}
+	for start := from; start.Before(to); start = start.Add(chunk) {
+		end := start.Add(chunk)
+		if end.After(to) {
+			end = to
+		}
+		if err := s.enqueue(ctx, sinkName, start, end); err != nil {
+			return nil, err
+		}
+		resp.Jobs++
+	}
+
+	s.logger.Info("audit export replay queued", map[string]interface{}{
+		"sink": sinkName,
+		"from": from,
+		"to":   to,
+		"jobs": resp.Jobs,
+	})
+	return resp, nil
+}
+
+func (s *AuditExportService) enqueue(ctx context.Context, sink string, from, to time.Time) error {
+	_, err := s.jobs.Enqueue(ctx, auditExportJob, auditExportJobPayload{Sink: sink, From: from, To: to}, auditExportMaxAttempts)
+	if err != nil {
+		s.logger.Error("failed to queue audit export", map[string]interface{}{
+			"sink":  sink,
+			"from":  from,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to queue audit export: %w", err)
+	}
+	return nil
+}
+
+// Status lists the sinks with how far each has been scheduled
+func (s *AuditExportService) Status(ctx context.Context) (*models.AuditExportStatusResponse, error) {
+	resp := &models.AuditExportStatusResponse{Sinks: make([]models.AuditExportSinkStatus, 0, len(s.names))}
+	for _, name := range s.names {
+		cursor, err := s.cursors.FindCursor(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		status := models.AuditExportSinkStatus{Name: name, Interval: s.sinks[name].Interval().String()}
+		if cursor != nil {
+			scheduledUntil := cursor.ScheduledUntil.UTC()
+			status.ScheduledUntil = &scheduledUntil
+		}
+		resp.Sinks = append(resp.Sinks, status)
+	}
+	return resp, nil
+}
+
+// runExportJob exports a window of logs; failures are retried by the job queue
+func (s *AuditExportService) runExportJob(ctx context.Context, job *models.BackgroundJob) error {
+	var payload auditExportJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return PermanentJobError(fmt.Errorf("invalid audit export job: %w", err))
+	}
+	sink, ok := s.sinks[payload.Sink]
+	if !ok {
+		return PermanentJobError(fmt.Errorf("audit export sink %q is not configured", payload.Sink))
+	}
+
+	window := auditexport.Window{From: payload.From, To: payload.To}
+	offset := 0
+	pages := func(ctx context.Context) ([]*models.AuditLog, error) {
+		logs, err := s.logs.ListBetween(ctx, window.From, window.To, auditExportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(logs)
+		return logs, nil
+	}
+
+	if err := sink.Export(ctx, window, pages); err != nil {
+		return fmt.Errorf("failed to export audit logs to %s: %w", payload.Sink, err)
+	}
+	return nil
+}
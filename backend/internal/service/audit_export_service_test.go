@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/auditexport"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditExportStore struct {
+	cursors map[string]time.Time
+}
+
+func (m *mockAuditExportStore) GetCursor(ctx context.Context, sink string, start time.Time) (time.Time, error) {
+	if cursor, ok := m.cursors[sink]; ok {
+		return cursor, nil
+	}
+	m.cursors[sink] = start
+	return start, nil
+}
+
+func (m *mockAuditExportStore) FindCursor(ctx context.Context, sink string) (*models.AuditExportCursor, error) {
+	cursor, ok := m.cursors[sink]
+	if !ok {
+		return nil, nil
+	}
+	return &models.AuditExportCursor{Sink: sink, ScheduledUntil: cursor}, nil
+}
+
+func (m *mockAuditExportStore) AdvanceCursor(ctx context.Context, sink string, from, to time.Time) (bool, error) {
+	if !m.cursors[sink].Equal(from) {
+		return false, nil
+	}
+	m.cursors[sink] = to
+	return true, nil
+}
+
+type mockAuditRangeStore struct {
+	logs []*models.AuditLog
+}
+
+func (m *mockAuditRangeStore) ListBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]*models.AuditLog, error) {
+	if offset >= len(m.logs) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(m.logs) {
+		end = len(m.logs)
+	}
+	return m.logs[offset:end], nil
+}
+
+type mockAuditSink struct {
+	name     string
+	interval time.Duration
+	windows  []auditexport.Window
+	exported int
+	err      error
+}
+
+func (m *mockAuditSink) Export(ctx context.Context, window auditexport.Window, pages auditexport.Pages) error {
+	m.windows = append(m.windows, window)
+	for {
+		page, err := pages(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return m.err
+		}
+		m.exported += len(page)
+	}
+}
+
+func (m *mockAuditSink) Name() string            { return m.name }
+func (m *mockAuditSink) Interval() time.Duration { return m.interval }
+func (m *mockAuditSink) Close() error            { return nil }
+
+func setupAuditExportService(sinks ...auditexport.Sink) (*AuditExportService, *mockAuditExportStore, *mockAuditRangeStore, *mockBackgroundJobStore) {
+	jobs, jobStore := setupJobQueueService()
+	cursors := &mockAuditExportStore{cursors: make(map[string]time.Time)}
+	logs := &mockAuditRangeStore{}
+	svc := NewAuditExportService(sinks, cursors, logs, jobs, time.Minute, logger.New("test", logger.ErrorLevel, false))
+	return svc, cursors, logs, jobStore
+}
+
+func auditExportPayload(t *testing.T, job *models.BackgroundJob) auditExportJobPayload {
+	t.Helper()
+	var payload auditExportJobPayload
+	require.NoError(t, json.Unmarshal(job.Payload, &payload))
+	return payload
+}
+
+func TestAuditExportService_ScheduleDue(t *testing.T) {
+	sink := &mockAuditSink{name: auditexport.SinkSyslog, interval: time.Minute}
+	svc, cursors, _, jobStore := setupAuditExportService(sink)
+	now := time.Date(2025, 5, 1, 12, 0, 30, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	// The first run starts the cursor at the window under way, which is not due yet
+	assert.Equal(t, 0, svc.ScheduleDue(context.Background()))
+	assert.Equal(t, time.Date(2025, 5, 1, 11, 59, 0, 0, time.UTC), cursors.cursors[sink.name])
+
+	now = now.Add(3 * time.Minute)
+	assert.Equal(t, 3, svc.ScheduleDue(context.Background()))
+	require.Len(t, jobStore.created, 3)
+	first := auditExportPayload(t, jobStore.created[0])
+	assert.Equal(t, auditExportJob, jobStore.created[0].Type)
+	assert.Equal(t, auditExportMaxAttempts, jobStore.created[0].MaxAttempts)
+	assert.Equal(t, time.Date(2025, 5, 1, 11, 59, 0, 0, time.UTC), first.From)
+	assert.Equal(t, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC), first.To)
+	last := auditExportPayload(t, jobStore.created[2])
+	assert.Equal(t, time.Date(2025, 5, 1, 12, 2, 0, 0, time.UTC), last.To)
+	assert.Equal(t, last.To, cursors.cursors[sink.name])
+
+	// Nothing new is due
+	assert.Equal(t, 0, svc.ScheduleDue(context.Background()))
+}
+
+func TestAuditExportService_ScheduleDueLimitsWindows(t *testing.T) {
+	sink := &mockAuditSink{name: auditexport.SinkSyslog, interval: time.Minute}
+	svc, cursors, _, _ := setupAuditExportService(sink)
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	cursors.cursors[sink.name] = now.Add(-3 * time.Hour)
+
+	assert.Equal(t, auditExportMaxWindows, svc.ScheduleDue(context.Background()))
+	assert.Equal(t, now.Add(-2*time.Hour), cursors.cursors[sink.name])
+}
+
+func TestAuditExportService_Replay(t *testing.T) {
+	sink := &mockAuditSink{name: auditexport.SinkSyslog, interval: time.Minute}
+	svc, _, _, jobStore := setupAuditExportService(sink)
+	from := time.Date(2025, 5, 1, 10, 15, 30, 0, time.UTC)
+	to := time.Date(2025, 5, 1, 12, 40, 10, 0, time.UTC)
+
+	resp, err := svc.Replay(context.Background(), sink.name, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 1, 10, 15, 0, 0, time.UTC), resp.From)
+	assert.Equal(t, time.Date(2025, 5, 1, 12, 41, 0, 0, time.UTC), resp.To)
+	assert.Equal(t, 3, resp.Jobs)
+	require.Len(t, jobStore.created, 3)
+	assert.Equal(t, time.Date(2025, 5, 1, 11, 15, 0, 0, time.UTC), auditExportPayload(t, jobStore.created[0]).To)
+	assert.Equal(t, resp.To, auditExportPayload(t, jobStore.created[2]).To)
+}
+
+func TestAuditExportService_ReplayErrors(t *testing.T) {
+	sink := &mockAuditSink{name: auditexport.SinkS3, interval: time.Hour}
+	svc, _, _, _ := setupAuditExportService(sink)
+	from := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	_, err := svc.Replay(context.Background(), auditexport.SinkSyslog, from, from.Add(time.Hour))
+	assert.Equal(t, errAuditExportSinkNotFound, err)
+
+	_, err = svc.Replay(context.Background(), sink.name, from, from)
+	assert.Equal(t, errAuditExportInvalidRange, err)
+
+	_, err = svc.Replay(context.Background(), sink.name, from, from.Add(32*24*time.Hour))
+	assert.Equal(t, errAuditExportInvalidRange, err)
+}
+
+func TestAuditExportService_Status(t *testing.T) {
+	syslog := &mockAuditSink{name: auditexport.SinkSyslog, interval: time.Minute}
+	s3 := &mockAuditSink{name: auditexport.SinkS3, interval: time.Hour}
+	svc, cursors, _, _ := setupAuditExportService(syslog, s3)
+	cursors.cursors[s3.name] = time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	resp, err := svc.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Sinks, 2)
+	assert.Equal(t, "1m0s", resp.Sinks[0].Interval)
+	assert.Nil(t, resp.Sinks[0].ScheduledUntil)
+	require.NotNil(t, resp.Sinks[1].ScheduledUntil)
+	assert.Equal(t, cursors.cursors[s3.name], *resp.Sinks[1].ScheduledUntil)
+}
+
+func TestAuditExportService_RunExportJob(t *testing.T) {
+	sink := &mockAuditSink{name: auditexport.SinkS3, interval: time.Hour}
+	svc, _, logs, jobStore := setupAuditExportService(sink)
+	for i := 0; i < auditExportPageSize+5; i++ {
+		logs.logs = append(logs.logs, &models.AuditLog{Action: "signin"})
+	}
+	from := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	_, err := svc.Replay(context.Background(), sink.name, from, from.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, jobStore.created, 1)
+
+	require.NoError(t, svc.runExportJob(context.Background(), jobStore.created[0]))
+	require.Len(t, sink.windows, 1)
+	assert.Equal(t, auditexport.Window{From: from, To: from.Add(time.Hour)}, sink.windows[0])
+	assert.Equal(t, auditExportPageSize+5, sink.exported)
+
+	sink.err = errors.New("connection refused")
+	assert.Error(t, svc.runExportJob(context.Background(), jobStore.created[0]))
+
+	job := newBackgroundJob(auditExportJob, 1, 3)
+	job.Payload = []byte(`{"sink":"kafka"}`)
+	err = svc.runExportJob(context.Background(), job)
+	var jobErr *jobError
+	require.True(t, errors.As(err, &jobErr))
+	assert.True(t, jobErr.permanent)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 )
 
 type AuditService struct {
@@ -23,6 +24,8 @@ func NewAuditService(auditRepo *repository.AuditRepository, geoService *GeoServi
 }
 
 type AuditLogParams struct {
+	// ID is the ID of the log; when set, the log is written at most once
+	ID            uuid.UUID
 	UserID        *uuid.UUID
 	ApplicationID *uuid.UUID
 	Action        models.AuditAction
@@ -30,6 +33,9 @@ type AuditLogParams struct {
 	IP            string
 	UserAgent     string
 	Details       map[string]interface{}
+	// TraceID is the trace of the request that caused the log; LogSync takes it from the
+	// context when unset
+	TraceID string
 }
 
 func (s *AuditService) Log(params AuditLogParams) {
@@ -51,6 +57,9 @@ func (s *AuditService) logAsync(params AuditLogParams) {
 }
 
 func (s *AuditService) LogSync(ctx context.Context, params AuditLogParams) error {
+	if params.TraceID == "" {
+		params.TraceID = tracing.TraceID(ctx)
+	}
 	auditLog := s.buildAuditLog(params)
 
 	if s.geoService != nil && params.IP != "" {
@@ -67,8 +76,13 @@ func (s *AuditService) buildAuditLog(params AuditLogParams) *models.AuditLog {
 		detailsJSON, _ = json.Marshal(params.Details)
 	}
 
+	id := params.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
 	return &models.AuditLog{
-		ID:            uuid.New(),
+		ID:            id,
 		UserID:        params.UserID,
 		ApplicationID: params.ApplicationID,
 		Action:        string(params.Action),
@@ -76,6 +90,7 @@ func (s *AuditService) buildAuditLog(params AuditLogParams) *models.AuditLog {
 		UserAgent:     params.UserAgent,
 		Status:        string(params.Status),
 		Details:       detailsJSON,
+		TraceID:       params.TraceID,
 		CreatedAt:     time.Now(),
 	}
 }
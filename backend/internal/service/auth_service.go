@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 	"github.com/pquerna/otp/totp"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/uptrace/bun"
 )
@@ -33,6 +37,58 @@ type AuthService struct {
 	appRepo            ApplicationStore
 	strictTokenBinding bool
 	passwordChecker    *PasswordChecker
+	tokenVersions      TokenVersionChecker
+	passwordHistory    PasswordHistoryStore
+	passwordExpiry     PasswordExpiryChecker
+	loginIdentifiers   map[string]bool
+	signupPolicy       SignupEnforcer
+	emailVerification  EmailVerificationEnforcer
+	logoutNotifier     BackchannelLogoutNotifier
+	risk               RiskAssessor
+	lockout            AccountLocker
+	passwordEnforcer   PasswordPolicyEnforcer
+	lifecycle          UserLifecycleManager
+	events             EventPublisher
+	outbox             OutboxRecorder
+}
+
+// SetBackchannelLogout notifies the back-channel logout URIs of clients through notifier
+// when users log out
+func (s *AuthService) SetBackchannelLogout(notifier BackchannelLogoutNotifier) {
+	s.logoutNotifier = notifier
+}
+
+// SetRiskEngine scores password sign-ins with risk, which may require 2FA or block them
+func (s *AuthService) SetRiskEngine(risk RiskAssessor) {
+	s.risk = risk
+}
+
+// SetAccountLockout locks accounts with lockout after repeated failed password sign-ins
+func (s *AuthService) SetAccountLockout(lockout AccountLocker) {
+	s.lockout = lockout
+}
+
+// SetPasswordPolicy applies policy to new passwords instead of the static policy, breach
+// checker and history store passed to NewAuthService
+func (s *AuthService) SetPasswordPolicy(policy PasswordPolicyEnforcer) {
+	s.passwordEnforcer = policy
+}
+
+// SetUserLifecycle records sign-ins for dormancy tracking and activates invited users once
+// they reset their password
+func (s *AuthService) SetUserLifecycle(lifecycle UserLifecycleManager) {
+	s.lifecycle = lifecycle
+}
+
+// SetEventBus publishes sign-in, sign-up and logout events to events
+func (s *AuthService) SetEventBus(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records the sign-up audit log and user.created webhook in the transaction that
+// creates the user, so neither is lost when the user is created
+func (s *AuthService) SetOutbox(outbox OutboxRecorder) {
+	s.outbox = outbox
 }
 
 // TransactionDB defines the interface for database transactions
@@ -59,7 +115,24 @@ func NewAuthService(
 	webhookService *WebhookService,
 	strictTokenBinding bool,
 	passwordChecker *PasswordChecker,
+	tokenVersions TokenVersionChecker,
+	passwordHistory PasswordHistoryStore,
+	passwordExpiry PasswordExpiryChecker,
+	loginIdentifiers []string,
+	signupPolicy SignupEnforcer,
+	emailVerification EmailVerificationEnforcer,
 ) *AuthService {
+	// All identifiers are accepted unless the deployment restricts them
+	if len(loginIdentifiers) == 0 {
+		loginIdentifiers = []string{models.LoginIdentifierEmail, models.LoginIdentifierPhone, models.LoginIdentifierUsername}
+	}
+	allowedIdentifiers := make(map[string]bool, len(loginIdentifiers))
+	for _, kind := range loginIdentifiers {
+		if models.IsValidLoginIdentifier(kind) {
+			allowedIdentifiers[kind] = true
+		}
+	}
+
 	return &AuthService{
 		userRepo:           userRepo,
 		tokenRepo:          tokenRepo,
@@ -78,11 +151,20 @@ func NewAuthService(
 		appRepo:            appRepo,
 		strictTokenBinding: strictTokenBinding,
 		passwordChecker:    passwordChecker,
+		tokenVersions:      tokenVersions,
+		passwordHistory:    passwordHistory,
+		passwordExpiry:     passwordExpiry,
+		loginIdentifiers:   allowedIdentifiers,
+		signupPolicy:       signupPolicy,
+		emailVerification:  emailVerification,
 	}
 }
 
 // SignUp creates a new user account
-func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (_ *models.AuthResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.SignUp")
+	defer func() { tracing.End(span, err) }()
+
 	// Require either email or phone
 	if req.Email == "" && (req.Phone == nil || *req.Phone == "") {
 		return nil, models.NewAppError(400, "Either email or phone is required")
@@ -117,16 +199,8 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		return nil, models.NewAppError(400, "Invalid username format")
 	}
 
-	if err := utils.ValidatePassword(req.Password, s.passwordPolicy); err != nil {
-		return nil, models.NewAppError(400, err.Error())
-	}
-
-	// Check if password has been compromised
-	if s.passwordChecker != nil {
-		if compromised, count := s.passwordChecker.IsCompromised(ctx, req.Password); compromised {
-			return nil, models.NewAppError(400, "PASSWORD_COMPROMISED",
-				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
-		}
+	if err := s.checkNewPassword(ctx, req.Password, nil); err != nil {
+		return nil, err
 	}
 
 	// Check auth method is allowed for this application
@@ -134,6 +208,10 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		return nil, err
 	}
 
+	if err := s.checkSignupPolicy(ctx, email, appID, ip, userAgent); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	passwordHash, err := utils.HashPassword(req.Password, s.bcryptCost)
 	if err != nil {
@@ -166,7 +244,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 			if err := userRepo.CreateWithTx(ctx, tx, user); err != nil {
 				// Database will return unique_violation error if email/username/phone already exists
 				// handlePgError will convert it to appropriate error
-				s.logAudit(nil, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, nil, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "create_failed",
 					"error":  err.Error(),
 				})
@@ -175,7 +253,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		} else {
 			// Fallback to non-transactional method if type assertion fails
 			if err := s.userRepo.Create(ctx, user); err != nil {
-				s.logAudit(nil, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, nil, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "create_failed",
 					"error":  err.Error(),
 				})
@@ -186,7 +264,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		// Assign default "user" role to the new user
 		if rbacRepo, ok := s.rbacRepo.(*repository.RBACRepository); ok {
 			if err := rbacRepo.AssignRoleToUserWithTx(ctx, tx, user.ID, defaultRole.ID, user.ID); err != nil {
-				s.logAudit(&user.ID, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, &user.ID, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "role_assignment_failed",
 					"error":  err.Error(),
 				})
@@ -195,7 +273,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		} else {
 			// Fallback to non-transactional method if type assertion fails
 			if err := s.rbacRepo.AssignRoleToUser(ctx, user.ID, defaultRole.ID, user.ID); err != nil {
-				s.logAudit(&user.ID, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, &user.ID, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "role_assignment_failed",
 					"error":  err.Error(),
 				})
@@ -203,13 +281,21 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 			}
 		}
 
-		return nil
+		var details map[string]interface{}
+		if s.emailVerification != nil && s.emailVerification.CheckSignIn(user) != nil {
+			details = map[string]interface{}{"email_verification_required": true}
+		}
+		return s.recordSignUp(ctx, tx, user, appID, ip, userAgent, details)
 	})
 
 	if err != nil {
 		// Check if it's a unique constraint violation (already handled by handlePgError)
 		return nil, err
 	}
+	if s.outbox != nil {
+		s.outbox.Wake()
+	}
+	metrics.RecordSignup("password")
 
 	// Reload user with roles for token generation
 	user, err = s.userRepo.GetByID(ctx, user.ID, utils.Ptr(true), UserGetWithRoles())
@@ -217,14 +303,29 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 		return nil, fmt.Errorf("failed to reload user with roles: %w", err)
 	}
 
+	// No session until the address is verified when the verification level blocks sign-in
+	if s.emailVerification != nil && s.emailVerification.CheckSignIn(user) != nil {
+		if s.outbox == nil {
+			s.logAudit(ctx, &user.ID, appID, models.ActionSignUp, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+				"email_verification_required": true,
+			})
+		}
+		return &models.AuthResponse{
+			RequiresEmailVerification: true,
+			User:                      user.PublicUser(),
+		}, nil
+	}
+
 	// Generate tokens with device info (isNewUser=true suppresses login alert)
 	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, appID, true, "password")
 	if err != nil {
 		return nil, err
 	}
 
-	// Log successful signup
-	s.logAudit(&user.ID, appID, models.ActionSignUp, models.StatusSuccess, ip, userAgent, nil)
+	// Log successful signup unless the outbox recorded it with the user
+	if s.outbox == nil {
+		s.logAudit(ctx, &user.ID, appID, models.ActionSignUp, models.StatusSuccess, ip, userAgent, nil)
+	}
 
 	return authResp, nil
 }
@@ -232,9 +333,23 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.CreateUserRequest,
 // SignIn authenticates a user and returns tokens
 // Implements timing attack protection by always performing password check
 func (s *AuthService) SignIn(ctx context.Context, req *models.SignInRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
-	// Require either email or phone
-	if req.Email == "" && (req.Phone == nil || *req.Phone == "") {
-		return nil, models.NewAppError(400, "Either email or phone is required")
+	ctx, span := tracing.Start(ctx, "AuthService.SignIn")
+	resp, err := s.signIn(ctx, req, ip, userAgent, deviceInfo, appID)
+	if err != nil {
+		// Successful sign-ins are counted by finalizeAuth
+		metrics.RecordLogin("password", false)
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+func (s *AuthService) signIn(ctx context.Context, req *models.SignInRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+	kind, identifier := loginIdentifier(req)
+	if kind == "" {
+		return nil, models.NewAppError(400, "Email, phone or username is required")
+	}
+	if !s.loginIdentifiers[kind] {
+		return nil, models.NewAppError(400, fmt.Sprintf("Sign-in with %s is not enabled", kind))
 	}
 
 	// Check auth method is allowed for this application
@@ -246,78 +361,218 @@ func (s *AuthService) SignIn(ctx context.Context, req *models.SignInRequest, ip,
 	var err error
 	var passwordHash string
 
-	// Get user by email or phone
-	if req.Email != "" {
-		email := utils.NormalizeEmail(req.Email)
-		user, err = s.userRepo.GetByEmail(ctx, email, nil, UserGetWithRoles())
-		if err != nil {
-			// User not found - use dummy hash to prevent timing attacks
-			passwordHash = utils.GetDummyPasswordHash()
-		} else {
-			passwordHash = user.PasswordHash
-		}
-	} else if req.Phone != nil && *req.Phone != "" {
-		phone := utils.NormalizePhone(*req.Phone)
-		user, err = s.userRepo.GetByPhone(ctx, phone, nil, UserGetWithRoles())
-		if err != nil {
-			passwordHash = utils.GetDummyPasswordHash()
-		} else {
-			passwordHash = user.PasswordHash
-		}
+	// Get user by the normalized identifier
+	switch kind {
+	case models.LoginIdentifierEmail:
+		user, err = s.userRepo.GetByEmail(ctx, utils.NormalizeEmail(identifier), nil, UserGetWithRoles())
+	case models.LoginIdentifierPhone:
+		user, err = s.userRepo.GetByPhone(ctx, utils.NormalizePhone(identifier), nil, UserGetWithRoles())
+	case models.LoginIdentifierUsername:
+		user, err = s.userRepo.GetByUsername(ctx, utils.NormalizeUsername(identifier), nil, UserGetWithRoles())
+	}
+	if err != nil {
+		// User not found - use dummy hash to prevent timing attacks
+		user = nil
+		passwordHash = utils.GetDummyPasswordHash()
+	} else {
+		passwordHash = user.PasswordHash
 	}
 
 	// Always perform password check to prevent timing attacks
 	// This ensures consistent response time regardless of whether user exists
-	if err := utils.CheckPassword(passwordHash, req.Password); err != nil {
+	passwordErr := utils.CheckPassword(passwordHash, req.Password)
+
+	// A locked account is refused whether or not the password is right,
+	// so guesses made during the lock tell nothing
+	if user != nil && s.lockout != nil {
+		if until := s.lockout.LockedUntil(ctx, user.ID); until != nil {
+			s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusBlocked, ip, userAgent, map[string]interface{}{
+				"reason":       "account_locked",
+				"locked_until": until,
+			})
+			return nil, accountLockedError(*until)
+		}
+	}
+
+	if passwordErr != nil {
 		// Log failed attempt (but don't reveal if user exists)
 		var userID *uuid.UUID
 		if user != nil {
 			userID = &user.ID
+			if s.risk != nil {
+				s.risk.RecordFailedAttempt(ctx, user.ID, appID)
+			}
 		}
-		s.logAudit(userID, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, userID, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason": "invalid_credentials",
 		})
+		if user != nil && s.lockout != nil {
+			if lockout := s.lockout.RecordFailedAttempt(ctx, user.ID); lockout != nil {
+				s.logAudit(ctx, &user.ID, appID, models.ActionAccountLocked, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+					"lockout_count": lockout.LockoutCount,
+					"locked_until":  lockout.LockedUntil,
+				})
+				return nil, accountLockedError(*lockout.LockedUntil)
+			}
+		}
 		return nil, models.ErrInvalidCredentials
 	}
 
 	// If we reach here but user is nil, it means password matched dummy hash
 	// This should be extremely rare, but handle it for safety
 	if user == nil {
-		s.logAudit(nil, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, nil, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason": "invalid_credentials",
 		})
 		return nil, models.ErrInvalidCredentials
 	}
 
+	// Only active accounts may sign in; the password was right, so saying why is safe
+	if err := accountStateError(user); err != nil {
+		s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusBlocked, ip, userAgent, map[string]interface{}{
+			"reason": "account_" + string(user.State),
+		})
+		return nil, err
+	}
+
+	if s.lockout != nil {
+		s.lockout.RecordSignIn(ctx, user.ID)
+	}
+
+	if s.emailVerification != nil {
+		if err := s.emailVerification.CheckSignIn(user); err != nil {
+			s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				"reason": "email_not_verified",
+			})
+			return nil, err
+		}
+	}
+
+	return s.completeSignIn(ctx, user, ip, userAgent, deviceInfo, appID, "password")
+}
+
+// CompleteSignIn signs in a user whose first factor another service verified, such as a
+// magic link. The account state and lockout are checked as for a password sign-in, and
+// the risk assessment and 2FA challenge apply the same way.
+func (s *AuthService) CompleteSignIn(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, authMethod string) (*models.AuthResponse, error) {
+	if err := accountStateError(user); err != nil {
+		s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusBlocked, ip, userAgent, map[string]interface{}{
+			"reason":      "account_" + string(user.State),
+			"auth_method": authMethod,
+		})
+		return nil, err
+	}
+
+	if s.lockout != nil {
+		if until := s.lockout.LockedUntil(ctx, user.ID); until != nil {
+			s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusBlocked, ip, userAgent, map[string]interface{}{
+				"reason":       "account_locked",
+				"locked_until": until,
+				"auth_method":  authMethod,
+			})
+			return nil, accountLockedError(*until)
+		}
+	}
+
+	return s.completeSignIn(ctx, user, ip, userAgent, deviceInfo, appID, authMethod)
+}
+
+// completeSignIn finishes a sign-in once the first factor is verified: it scores the
+// attempt, challenges users with 2FA and otherwise issues the session. A required
+// password change is only revealed once every other check has passed, because its
+// token is enough to replace the password.
+func (s *AuthService) completeSignIn(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, authMethod string) (*models.AuthResponse, error) {
+	// Score the attempt; users with 2FA always verify a code, so step-up only adds to the audit trail
+	var assessment *models.RiskAssessment
+	if s.risk != nil {
+		assessment = s.risk.Assess(ctx, user, appID, ip, deviceInfo)
+	}
+	if assessment != nil {
+		switch assessment.Action {
+		case models.RiskActionBlock:
+			s.logAudit(ctx, &user.ID, appID, models.ActionRiskBlock, models.StatusFailed, ip, userAgent, assessment.AuditDetails())
+			return nil, errSignInBlockedByRisk
+		case models.RiskActionStepUp:
+			s.logAudit(ctx, &user.ID, appID, models.ActionRiskStepUp, models.StatusSuccess, ip, userAgent, assessment.AuditDetails())
+		}
+	}
+
 	// Check if 2FA is enabled
-	if user.TOTPEnabled {
+	if user.TwoFactorEnabled() {
 		// Generate temporary 2FA token
 		twoFactorToken, err := s.jwtService.GenerateTwoFactorToken(user, appID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate 2FA token: %w", err)
 		}
 
-		return &models.AuthResponse{
+		resp := &models.AuthResponse{
 			Requires2FA:    true,
 			TwoFactorToken: twoFactorToken,
 			User:           user.PublicUser(),
-		}, nil
+		}
+		if s.twoFAService != nil {
+			resp.TwoFactorMethods = s.twoFAService.Methods(user)
+		}
+		return resp, nil
+	}
+
+	// Expired or admin-flagged passwords must be replaced before a password sign-in gets a session
+	if authMethod == "password" {
+		if err := s.checkPasswordChangeRequired(ctx, user, appID, ip, userAgent); err != nil {
+			return nil, err
+		}
 	}
 
 	// Generate tokens with device info
-	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, appID, false, "password")
+	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, appID, false, authMethod)
 	if err != nil {
 		return nil, err
 	}
 
 	// Log successful signin
-	s.logAudit(&user.ID, appID, models.ActionSignIn, models.StatusSuccess, ip, userAgent, nil)
+	details := map[string]interface{}{"auth_method": authMethod}
+	if assessment != nil {
+		details["risk"] = assessment.AuditDetails()
+	}
+	s.logAudit(ctx, &user.ID, appID, models.ActionSignIn, models.StatusSuccess, ip, userAgent, details)
+	if s.risk != nil {
+		s.risk.RecordSignIn(ctx, user.ID, ip, deviceInfo)
+	}
 
 	return authResp, nil
 }
 
+// loginIdentifier returns the kind and value of the identifier a sign-in request uses.
+// Explicit fields win over the generic identifier, whose kind is detected from its format.
+func loginIdentifier(req *models.SignInRequest) (string, string) {
+	switch {
+	case req.Email != "":
+		return models.LoginIdentifierEmail, req.Email
+	case req.Phone != nil && *req.Phone != "":
+		return models.LoginIdentifierPhone, *req.Phone
+	case req.Username != "":
+		return models.LoginIdentifierUsername, req.Username
+	}
+
+	identifier := strings.TrimSpace(req.Identifier)
+	switch {
+	case identifier == "":
+		return "", ""
+	case strings.Contains(identifier, "@"):
+		return models.LoginIdentifierEmail, identifier
+	case strings.HasPrefix(identifier, "+") || strings.Trim(identifier, "0123456789 -()") == "":
+		// Digits-only identifiers are phones; such usernames need the explicit username field
+		return models.LoginIdentifierPhone, identifier
+	default:
+		return models.LoginIdentifierUsername, identifier
+	}
+}
+
 // Verify2FALogin verifies 2FA code and completes login
-func (s *AuthService) Verify2FALogin(ctx context.Context, twoFactorToken, code, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error) {
+func (s *AuthService) Verify2FALogin(ctx context.Context, twoFactorToken, code, ip, userAgent string, deviceInfo models.DeviceInfo) (_ *models.AuthResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Verify2FALogin")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate 2FA token
 	claims, err := s.jwtService.ValidateAccessToken(twoFactorToken)
 	if err != nil {
@@ -330,40 +585,112 @@ func (s *AuthService) Verify2FALogin(ctx context.Context, twoFactorToken, code,
 		return nil, err
 	}
 
-	// Verify 2FA is enabled
-	if !user.TOTPEnabled || user.TOTPSecret == nil {
+	// Verify 2FA is enabled; security key users may still sign in with a backup code
+	totpUsable := user.TOTPEnabled && user.TOTPSecret != nil
+	if !totpUsable && !user.WebAuthnEnabled {
 		return nil, models.NewAppError(400, "2FA not enabled")
 	}
 
-	// Verify TOTP code
-	if !totp.Validate(code, *user.TOTPSecret) {
-		// Try backup code using TwoFactorService
+	// Verify TOTP code, then backup codes using TwoFactorService
+	method := models.TwoFactorMethodTOTP
+	if !totpUsable || !totp.Validate(code, *user.TOTPSecret) {
+		valid := false
 		if s.twoFAService != nil {
-			valid, err := s.twoFAService.VerifyTOTP(ctx, user.ID, code)
-			if err != nil || !valid {
-				s.logAudit(&user.ID, claims.ApplicationID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
-					"reason": "invalid_2fa_code",
-				})
-				return nil, models.NewAppError(401, "Invalid 2FA code")
-			}
-		} else {
-			s.logAudit(&user.ID, claims.ApplicationID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			method, valid, err = s.twoFAService.VerifyCode(ctx, user, code)
+		}
+		if err != nil || !valid {
+			s.logAudit(ctx, &user.ID, claims.ApplicationID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
 				"reason": "invalid_2fa_code",
 			})
 			return nil, models.NewAppError(401, "Invalid 2FA code")
 		}
 	}
 
+	// A required password change is only revealed once the second factor is verified
+	if err := s.checkPasswordChangeRequired(ctx, user, claims.ApplicationID, ip, userAgent); err != nil {
+		return nil, err
+	}
+
 	// Generate full auth tokens with device info
-	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, nil, false, "totp")
+	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, nil, false, method)
 	if err != nil {
 		return nil, err
 	}
 
 	// Log successful signin with 2FA
-	s.logAudit(&user.ID, claims.ApplicationID, models.ActionSignIn, models.StatusSuccess, ip, userAgent, map[string]interface{}{
-		"2fa": true,
+	s.logAudit(ctx, &user.ID, claims.ApplicationID, models.ActionSignIn, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"2fa":        true,
+		"2fa_method": method,
+	})
+	if s.risk != nil {
+		s.risk.RecordSignIn(ctx, user.ID, ip, deviceInfo)
+	}
+
+	return authResp, nil
+}
+
+// BeginWebAuthn2FALogin starts the security key step of a sign-in that requires 2FA
+func (s *AuthService) BeginWebAuthn2FALogin(ctx context.Context, twoFactorToken string) (*protocol.CredentialAssertion, error) {
+	claims, err := s.jwtService.ValidateAccessToken(twoFactorToken)
+	if err != nil {
+		return nil, models.NewAppError(401, "Invalid or expired 2FA token")
+	}
+	if s.twoFAService == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID, utils.Ptr(true))
+	if err != nil {
+		return nil, err
+	}
+	if !user.WebAuthnEnabled {
+		return nil, models.NewAppError(400, "No security keys registered")
+	}
+
+	return s.twoFAService.BeginWebAuthnLogin(ctx, user)
+}
+
+// VerifyWebAuthn2FALogin completes a sign-in with the security key assertion
+func (s *AuthService) VerifyWebAuthn2FALogin(ctx context.Context, twoFactorToken string, response []byte, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error) {
+	claims, err := s.jwtService.ValidateAccessToken(twoFactorToken)
+	if err != nil {
+		return nil, models.NewAppError(401, "Invalid or expired 2FA token")
+	}
+	if s.twoFAService == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID, utils.Ptr(true), UserGetWithRoles())
+	if err != nil {
+		return nil, err
+	}
+	if !user.WebAuthnEnabled {
+		return nil, models.NewAppError(400, "No security keys registered")
+	}
+
+	if err := s.twoFAService.FinishWebAuthnLogin(ctx, user, response); err != nil {
+		s.logAudit(ctx, &user.ID, claims.ApplicationID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			"reason": "invalid_webauthn_assertion",
+		})
+		return nil, err
+	}
+
+	if err := s.checkPasswordChangeRequired(ctx, user, claims.ApplicationID, ip, userAgent); err != nil {
+		return nil, err
+	}
+
+	authResp, err := s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, nil, false, models.TwoFactorMethodWebAuthn)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, &user.ID, claims.ApplicationID, models.ActionSignIn, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"2fa":        true,
+		"2fa_method": models.TwoFactorMethodWebAuthn,
 	})
+	if s.risk != nil {
+		s.risk.RecordSignIn(ctx, user.ID, ip, deviceInfo)
+	}
 
 	return authResp, nil
 }
@@ -382,11 +709,14 @@ func (s *AuthService) verifyBackupCode(userID uuid.UUID, code string) (bool, err
 
 // RefreshToken generates new tokens using a refresh token
 // This operation is atomic - old token is revoked and new token is created in a single transaction
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAgent string, deviceInfo models.DeviceInfo) (_ *models.AuthResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RefreshToken")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate refresh token
 	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		s.logAudit(nil, nil, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, nil, nil, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason": "invalid_token",
 		})
 		return nil, models.ErrInvalidToken
@@ -395,12 +725,20 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 	// Check if token is blacklisted using unified blacklist service
 	oldTokenHash := utils.HashToken(refreshToken)
 	if s.blacklistService.IsBlacklisted(ctx, oldTokenHash) {
-		s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason": "token_blacklisted",
 		})
 		return nil, models.ErrTokenRevoked
 	}
 
+	// Reject tokens issued before the user's last token version bump or the global epoch
+	if s.tokenVersions != nil && s.tokenVersions.IsRevoked(ctx, claims) {
+		s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			"reason": "token_version_revoked",
+		})
+		return nil, models.ErrTokenRevoked
+	}
+
 	// Get user with roles (before transaction to avoid deadlocks)
 	user, err := s.userRepo.GetByID(ctx, claims.UserID, utils.Ptr(true), UserGetWithRoles())
 	if err != nil {
@@ -418,21 +756,21 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 		// Check if token exists and is not revoked (with lock)
 		dbToken, err = s.tokenRepo.GetRefreshTokenForUpdate(ctx, tx, oldTokenHash)
 		if err != nil {
-			s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 				"reason": "token_not_found",
 			})
 			return models.ErrInvalidToken
 		}
 
 		if dbToken.IsRevoked() {
-			s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 				"reason": "token_revoked",
 			})
 			return models.ErrTokenRevoked
 		}
 
 		if dbToken.IsExpired() {
-			s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 				"reason": "token_expired",
 			})
 			return models.ErrTokenExpired
@@ -440,7 +778,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 
 		// Device binding: reject if IP changed (when strict mode enabled)
 		if s.strictTokenBinding && dbToken.IPAddress != "" && dbToken.IPAddress != ip {
-			s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionRefreshToken, models.StatusFailed, ip, userAgent, map[string]interface{}{
 				"reason":      "device_mismatch",
 				"original_ip": dbToken.IPAddress,
 				"current_ip":  ip,
@@ -508,7 +846,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 	}
 
 	// Log successful refresh
-	s.logAudit(&user.ID, claims.ApplicationID, models.ActionRefreshToken, models.StatusSuccess, ip, userAgent, nil)
+	s.logAudit(ctx, &user.ID, claims.ApplicationID, models.ActionRefreshToken, models.StatusSuccess, ip, userAgent, nil)
 
 	return &models.AuthResponse{
 		AccessToken:  newAccessToken,
@@ -519,7 +857,10 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 }
 
 // Logout logs out a user by revoking their tokens
-func (s *AuthService) Logout(ctx context.Context, accessToken, ip, userAgent string) error {
+func (s *AuthService) Logout(ctx context.Context, accessToken, ip, userAgent string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Logout")
+	defer func() { tracing.End(span, err) }()
+
 	// Extract claims without full validation (we just need the user ID)
 	claims, err := s.jwtService.ExtractClaims(accessToken)
 	if err != nil {
@@ -537,8 +878,19 @@ func (s *AuthService) Logout(ctx context.Context, accessToken, ip, userAgent str
 		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
 	}
 
+	if s.logoutNotifier != nil {
+		s.logoutNotifier.NotifyLogout(ctx, claims.UserID)
+	}
+
 	// Log successful logout
-	s.logAudit(&claims.UserID, claims.ApplicationID, models.ActionSignOut, models.StatusSuccess, ip, userAgent, nil)
+	s.logAudit(ctx, &claims.UserID, claims.ApplicationID, models.ActionSignOut, models.StatusSuccess, ip, userAgent, nil)
+	s.publishEvent(ctx, models.EventTokenRevoked, claims.UserID, map[string]interface{}{
+		"user_id":        claims.UserID.String(),
+		"reason":         "logout",
+		"token_types":    []string{"access_token", "refresh_token"},
+		"application_id": uuidPtrToString(claims.ApplicationID),
+		"ip_address":     ip,
+	})
 
 	return nil
 }
@@ -553,23 +905,18 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 
 	// Verify old password
 	if err := utils.CheckPassword(user.PasswordHash, oldPassword); err != nil {
-		s.logAudit(&userID, nil, models.ActionChangePassword, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, &userID, nil, models.ActionChangePassword, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason": "invalid_old_password",
 		})
 		return models.ErrInvalidCredentials
 	}
 
-	// Validate new password
-	if err := utils.ValidatePassword(newPassword, s.passwordPolicy); err != nil {
-		return models.NewAppError(400, err.Error())
-	}
-
-	// Check if password has been compromised
-	if s.passwordChecker != nil {
-		if compromised, count := s.passwordChecker.IsCompromised(ctx, newPassword); compromised {
-			return models.NewAppError(400, "PASSWORD_COMPROMISED",
-				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
+	// Validate new password, rejecting recently used ones
+	if err := s.checkNewPassword(ctx, newPassword, user); err != nil {
+		if details := passwordRejectionDetails(err); details != nil {
+			s.logAudit(ctx, &userID, nil, models.ActionChangePassword, models.StatusFailed, ip, userAgent, details)
 		}
+		return err
 	}
 
 	// Hash new password
@@ -583,6 +930,10 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 		return err
 	}
 
+	if err := s.recordPasswordHistory(ctx, userID, newPasswordHash); err != nil {
+		return err
+	}
+
 	// Revoke all refresh tokens (force re-login)
 	if err := s.tokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
 		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
@@ -593,25 +944,35 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 		return fmt.Errorf("failed to blacklist session tokens: %w", err)
 	}
 
+	// Bump token version so tokens without a tracked session are rejected too
+	if err := s.bumpTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+
 	// Log successful password change
-	s.logAudit(&userID, nil, models.ActionChangePassword, models.StatusSuccess, ip, userAgent, nil)
+	s.logAudit(ctx, &userID, nil, models.ActionChangePassword, models.StatusSuccess, ip, userAgent, nil)
 
 	return nil
 }
 
 // ResetPassword resets a user's password (used for password reset flow)
 func (s *AuthService) ResetPassword(ctx context.Context, userID uuid.UUID, newPassword, ip, userAgent string) error {
-	// Validate new password
-	if err := utils.ValidatePassword(newPassword, s.passwordPolicy); err != nil {
-		return models.NewAppError(400, err.Error())
+	// The user is only needed to reject recently used passwords
+	var user *models.User
+	if s.passwordEnforcer != nil || s.passwordHistoryEnabled() {
+		var err error
+		if user, err = s.userRepo.GetByID(ctx, userID, nil); err != nil {
+			return err
+		}
 	}
 
-	// Check if password has been compromised
-	if s.passwordChecker != nil {
-		if compromised, count := s.passwordChecker.IsCompromised(ctx, newPassword); compromised {
-			return models.NewAppError(400, "PASSWORD_COMPROMISED",
-				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
+	// Validate new password
+	if err := s.checkNewPassword(ctx, newPassword, user); err != nil {
+		if details := passwordRejectionDetails(err); details != nil {
+			details["reset"] = true
+			s.logAudit(ctx, &userID, nil, models.ActionChangePassword, models.StatusFailed, ip, userAgent, details)
 		}
+		return err
 	}
 
 	// Hash new password
@@ -625,6 +986,10 @@ func (s *AuthService) ResetPassword(ctx context.Context, userID uuid.UUID, newPa
 		return err
 	}
 
+	if err := s.recordPasswordHistory(ctx, userID, newPasswordHash); err != nil {
+		return err
+	}
+
 	// Revoke all refresh tokens (force re-login)
 	if err := s.tokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
 		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
@@ -635,14 +1000,42 @@ func (s *AuthService) ResetPassword(ctx context.Context, userID uuid.UUID, newPa
 		return fmt.Errorf("failed to blacklist session tokens: %w", err)
 	}
 
+	// Bump token version so tokens without a tracked session are rejected too
+	if err := s.bumpTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+
 	// Log successful password reset
-	s.logAudit(&userID, nil, models.ActionChangePassword, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+	s.logAudit(ctx, &userID, nil, models.ActionChangePassword, models.StatusSuccess, ip, userAgent, map[string]interface{}{
 		"reset": true,
 	})
 
+	// Setting their own password is how invited users accept the invitation
+	if s.lifecycle != nil {
+		if err := s.lifecycle.AcceptInvitation(ctx, userID, ip, userAgent); err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// CompleteRequiredPasswordChange sets a new password using the token issued when sign-in
+// was blocked by a required password change. The token is single-use: resetting the
+// password bumps the token version it carries.
+func (s *AuthService) CompleteRequiredPasswordChange(ctx context.Context, resetToken, newPassword, ip, userAgent string) error {
+	claims, err := s.jwtService.ValidatePasswordChangeToken(resetToken)
+	if err != nil {
+		return models.NewAppError(401, "Invalid or expired password change token")
+	}
+
+	if s.tokenVersions != nil && s.tokenVersions.IsRevoked(ctx, claims) {
+		return models.NewAppError(401, "Invalid or expired password change token")
+	}
+
+	return s.ResetPassword(ctx, claims.UserID, newPassword, ip, userAgent)
+}
+
 // PendingRegistrationExpiration is the TTL for pending registration data in Redis
 const PendingRegistrationExpiration = 10 * time.Minute
 
@@ -695,6 +1088,10 @@ func (s *AuthService) InitPasswordlessRegistration(ctx context.Context, req *mod
 		return models.NewAppError(400, "Invalid username format")
 	}
 
+	if err := s.checkSignupPolicy(ctx, email, nil, ip, userAgent); err != nil {
+		return err
+	}
+
 	// Note: We don't pre-check if username exists. If it conflicts during creation,
 	// the database unique constraint will catch it and we'll handle the error.
 	// For username conflicts, we'll append a random suffix during CompletePasswordlessRegistration if needed.
@@ -713,7 +1110,7 @@ func (s *AuthService) InitPasswordlessRegistration(ctx context.Context, req *mod
 	}
 
 	// Log init registration
-	s.logAudit(nil, nil, models.ActionSignUp, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+	s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusSuccess, ip, userAgent, map[string]interface{}{
 		"step":       "init",
 		"identifier": identifier,
 	})
@@ -747,7 +1144,7 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 	// Retrieve pending registration from Redis
 	pending, err := s.redis.GetPendingRegistration(ctx, identifier)
 	if err != nil {
-		s.logAudit(nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 			"reason":     "pending_not_found",
 			"identifier": identifier,
 		})
@@ -759,6 +1156,11 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 		return nil, models.NewAppError(400, "Registration data mismatch")
 	}
 
+	// The policy may have changed since the registration was initiated
+	if err := s.checkSignupPolicy(ctx, pending.Email, nil, ip, userAgent); err != nil {
+		return nil, err
+	}
+
 	// Get default "user" role
 	defaultRole, err := s.rbacRepo.GetRoleByName(ctx, "user")
 	if err != nil {
@@ -788,14 +1190,14 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 				if err == models.ErrUsernameAlreadyExists {
 					user.Username = fmt.Sprintf("%s_%d", pending.Username, time.Now().UnixNano()%10000)
 					if err := userRepo.CreateWithTx(ctx, tx, user); err != nil {
-						s.logAudit(nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+						s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 							"reason": "create_failed",
 							"error":  err.Error(),
 						})
 						return err
 					}
 				} else {
-					s.logAudit(nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+					s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 						"reason": "create_failed",
 						"error":  err.Error(),
 					})
@@ -809,14 +1211,14 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 				if err == models.ErrUsernameAlreadyExists {
 					user.Username = fmt.Sprintf("%s_%d", pending.Username, time.Now().UnixNano()%10000)
 					if err := s.userRepo.Create(ctx, user); err != nil {
-						s.logAudit(nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+						s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 							"reason": "create_failed",
 							"error":  err.Error(),
 						})
 						return err
 					}
 				} else {
-					s.logAudit(nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+					s.logAudit(ctx, nil, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 						"reason": "create_failed",
 						"error":  err.Error(),
 					})
@@ -828,7 +1230,7 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 		// Assign default "user" role
 		if rbacRepo, ok := s.rbacRepo.(*repository.RBACRepository); ok {
 			if err := rbacRepo.AssignRoleToUserWithTx(ctx, tx, user.ID, defaultRole.ID, user.ID); err != nil {
-				s.logAudit(&user.ID, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, &user.ID, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "role_assignment_failed",
 					"error":  err.Error(),
 				})
@@ -837,7 +1239,7 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 		} else {
 			// Fallback to non-transactional method if type assertion fails
 			if err := s.rbacRepo.AssignRoleToUser(ctx, user.ID, defaultRole.ID, user.ID); err != nil {
-				s.logAudit(&user.ID, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				s.logAudit(ctx, &user.ID, nil, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
 					"reason": "role_assignment_failed",
 					"error":  err.Error(),
 				})
@@ -845,12 +1247,23 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 			}
 		}
 
-		return nil
+		return s.recordSignUp(ctx, tx, user, nil, ip, userAgent, map[string]interface{}{
+			"passwordless": true,
+		})
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if s.outbox != nil {
+		s.outbox.Wake()
+	}
+	metrics.RecordSignup("passwordless")
+
+	// Join organizations by verified domain before loading the roles they grant
+	if s.signupPolicy != nil {
+		s.signupPolicy.AutoJoin(ctx, user)
+	}
 
 	// Reload user with roles for token generation
 	user, err = s.userRepo.GetByID(ctx, user.ID, utils.Ptr(true), UserGetWithRoles())
@@ -870,16 +1283,38 @@ func (s *AuthService) CompletePasswordlessRegistration(ctx context.Context, req
 		return nil, err
 	}
 
-	// Log successful signup
-	s.logAudit(&user.ID, nil, models.ActionSignUp, models.StatusSuccess, ip, userAgent, map[string]interface{}{
-		"passwordless": true,
-	})
+	// Log successful signup unless the outbox recorded it with the user
+	if s.outbox == nil {
+		s.logAudit(ctx, &user.ID, nil, models.ActionSignUp, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+			"passwordless": true,
+		})
+	}
 
 	return authResp, nil
 }
 
+// checkSignupPolicy rejects self-service registrations the sign-up policy does not allow
+func (s *AuthService) checkSignupPolicy(ctx context.Context, email string, appID *uuid.UUID, ip, userAgent string) error {
+	if s.signupPolicy == nil {
+		return nil
+	}
+	if err := s.signupPolicy.CheckSignup(ctx, email); err != nil {
+		s.logAudit(ctx, nil, appID, models.ActionSignUp, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			"reason": "signup_restricted",
+			"email":  email,
+		})
+		return err
+	}
+	return nil
+}
+
 // finalizeAuth generates access and refresh tokens, creates app profile, triggers webhook, and saves refresh token with device info
 func (s *AuthService) finalizeAuth(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, isNewUser bool, authMethod string) (*models.AuthResponse, error) {
+	// Every sign-in method ends here, so no method can issue tokens to an inactive account
+	if err := accountStateError(user); err != nil {
+		return nil, err
+	}
+
 	// Auto-create/update app profile on login
 	if appID != nil && s.appRepo != nil {
 		profile, _ := s.appRepo.GetUserProfile(ctx, user.ID, *appID)
@@ -968,6 +1403,10 @@ func (s *AuthService) finalizeAuth(ctx context.Context, user *models.User, ip, u
 		}()
 	}
 
+	if s.lifecycle != nil {
+		s.lifecycle.RecordSignIn(ctx, user.ID)
+	}
+
 	// Trigger webhook for user.login event (async, non-blocking)
 	if s.webhookService != nil {
 		go func() {
@@ -983,6 +1422,20 @@ func (s *AuthService) finalizeAuth(ctx context.Context, user *models.User, ip, u
 		}()
 	}
 
+	eventData := map[string]interface{}{
+		"user_id":        user.ID.String(),
+		"email":          user.Email,
+		"auth_method":    authMethod,
+		"application_id": uuidPtrToString(appID),
+		"ip_address":     ip,
+		"new_user":       isNewUser,
+	}
+	if isNewUser {
+		s.publishEvent(ctx, models.EventUserSignedUp, user.ID, eventData)
+	}
+	s.publishEvent(ctx, models.EventUserSignedIn, user.ID, eventData)
+	metrics.RecordLogin(authMethod, true)
+
 	return &models.AuthResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -991,6 +1444,26 @@ func (s *AuthService) finalizeAuth(ctx context.Context, user *models.User, ip, u
 	}, nil
 }
 
+// accountStateError returns why the user's lifecycle state forbids signing in, or nil.
+// Users loaded from the database always carry a state; a user without one was built in
+// memory and is left to the caller.
+func accountStateError(user *models.User) error {
+	if user.State == "" {
+		return nil
+	}
+	return user.State.SignInError()
+}
+
+// IssueSession issues tokens and records a session for a user authenticated by another service
+func (s *AuthService) IssueSession(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, isNewUser bool, authMethod string) (*models.AuthResponse, error) {
+	return s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, appID, isNewUser, authMethod)
+}
+
+// CheckAuthMethodAllowed returns an error if the application does not allow the auth method
+func (s *AuthService) CheckAuthMethodAllowed(ctx context.Context, appID *uuid.UUID, method string) error {
+	return s.checkAuthMethodAllowed(ctx, appID, method)
+}
+
 // GenerateTokensForUser generates tokens for a given user (used for OTP/SMS passwordless login).
 // This method properly saves the refresh token and creates a session.
 func (s *AuthService) GenerateTokensForUser(ctx context.Context, user *models.User, ip, userAgent string) (*models.AuthResponse, error) {
@@ -1001,7 +1474,168 @@ func (s *AuthService) GenerateTokensForUser(ctx context.Context, user *models.Us
 	return s.finalizeAuth(ctx, user, ip, userAgent, deviceInfo, nil, false, "password")
 }
 
-func (s *AuthService) logAudit(userID *uuid.UUID, appID *uuid.UUID, action models.AuditAction, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
+// checkPasswordChangeRequired returns a PasswordChangeRequiredError carrying a short-lived
+// reset token when the user's password has expired or an admin requires a change
+func (s *AuthService) checkPasswordChangeRequired(ctx context.Context, user *models.User, appID *uuid.UUID, ip, userAgent string) error {
+	if s.passwordExpiry == nil {
+		return nil
+	}
+
+	reason, err := s.passwordExpiry.ChangeRequired(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to check password expiry: %w", err)
+	}
+	if reason == "" {
+		return nil
+	}
+
+	resetToken, err := s.jwtService.GeneratePasswordChangeToken(user)
+	if err != nil {
+		return fmt.Errorf("failed to generate password change token: %w", err)
+	}
+
+	s.logAudit(ctx, &user.ID, appID, models.ActionSignInFailed, models.StatusFailed, ip, userAgent, map[string]interface{}{
+		"reason": "password_change_required",
+		"detail": reason,
+	})
+
+	return &models.PasswordChangeRequiredError{Reason: reason, ResetToken: resetToken}
+}
+
+// checkNewPassword applies the password policy to a password being set. user is nil for
+// new accounts, which have no password history to check.
+func (s *AuthService) checkNewPassword(ctx context.Context, password string, user *models.User) error {
+	if s.passwordEnforcer != nil {
+		return s.passwordEnforcer.CheckPassword(ctx, password, user)
+	}
+
+	if err := utils.ValidatePassword(password, s.passwordPolicy); err != nil {
+		return models.NewAppError(400, err.Error())
+	}
+
+	// Check if password has been compromised
+	if s.passwordChecker != nil {
+		if compromised, count := s.passwordChecker.IsCompromised(ctx, password); compromised {
+			return models.NewAppError(400, "PASSWORD_COMPROMISED",
+				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
+		}
+	}
+
+	if user == nil {
+		return nil
+	}
+	return s.checkPasswordHistory(ctx, user.ID, user.PasswordHash, password)
+}
+
+// passwordRejectionDetails describes a new password rejected by the policy for the audit log.
+// It returns nil for other errors and for the static policy's validation errors.
+func passwordRejectionDetails(err error) map[string]interface{} {
+	var policyErr *models.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		return map[string]interface{}{
+			"reason": "password_policy",
+			"rules":  policyErr.Rules(),
+		}
+	case errors.Is(err, models.ErrPasswordReused):
+		return map[string]interface{}{"reason": "password_reused"}
+	}
+	return nil
+}
+
+// passwordHistoryEnabled reports whether password reuse is restricted
+func (s *AuthService) passwordHistoryEnabled() bool {
+	return s.passwordHistory != nil && s.passwordPolicy.HistoryCount > 0
+}
+
+// checkPasswordHistory rejects a new password that matches the current password
+// or any of the last HistoryCount passwords
+func (s *AuthService) checkPasswordHistory(ctx context.Context, userID uuid.UUID, currentHash, newPassword string) error {
+	if !s.passwordHistoryEnabled() {
+		return nil
+	}
+
+	if currentHash != "" && utils.CheckPassword(currentHash, newPassword) == nil {
+		return models.ErrPasswordReused
+	}
+
+	entries, err := s.passwordHistory.GetRecent(ctx, userID, s.passwordPolicy.HistoryCount)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if utils.CheckPassword(entry.PasswordHash, newPassword) == nil {
+			return models.ErrPasswordReused
+		}
+	}
+
+	return nil
+}
+
+// recordPasswordHistory stores the new password hash and trims history to the configured depth
+func (s *AuthService) recordPasswordHistory(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	if s.passwordEnforcer != nil {
+		return s.passwordEnforcer.RecordPassword(ctx, userID, passwordHash)
+	}
+	if !s.passwordHistoryEnabled() {
+		return nil
+	}
+
+	if err := s.passwordHistory.Create(ctx, &models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}); err != nil {
+		return err
+	}
+
+	return s.passwordHistory.Prune(ctx, userID, s.passwordPolicy.HistoryCount)
+}
+
+// bumpTokenVersion invalidates every token issued to the user so far
+func (s *AuthService) bumpTokenVersion(ctx context.Context, userID uuid.UUID) error {
+	if s.tokenVersions == nil {
+		return nil
+	}
+	if _, err := s.tokenVersions.Bump(ctx, userID); err != nil {
+		return fmt.Errorf("failed to bump token version: %w", err)
+	}
+	return nil
+}
+
+// recordSignUp records the sign-up audit log and the user.created webhook of a new user in
+// tx. Without an outbox the audit log is written once the user is created and no webhook is
+// sent.
+func (s *AuthService) recordSignUp(ctx context.Context, tx bun.Tx, user *models.User, appID *uuid.UUID, ip, userAgent string, details map[string]interface{}) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	err := s.outbox.RecordAudit(ctx, tx, AuditLogParams{
+		UserID:        &user.ID,
+		ApplicationID: appID,
+		Action:        models.ActionSignUp,
+		Status:        models.StatusSuccess,
+		IP:            ip,
+		UserAgent:     userAgent,
+		Details:       details,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.outbox.RecordWebhook(ctx, tx, models.WebhookEventUserCreated, userCreatedWebhookData(user, appID))
+}
+
+// userCreatedWebhookData is the data of the user.created webhook
+func userCreatedWebhookData(user *models.User, appID *uuid.UUID) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":        user.ID.String(),
+		"email":          user.Email,
+		"username":       user.Username,
+		"application_id": uuidPtrToString(appID),
+		"timestamp":      user.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *AuthService) logAudit(ctx context.Context, userID *uuid.UUID, appID *uuid.UUID, action models.AuditAction, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
 	s.auditService.Log(AuditLogParams{
 		UserID:        userID,
 		ApplicationID: appID,
@@ -1010,6 +1644,7 @@ func (s *AuthService) logAudit(userID *uuid.UUID, appID *uuid.UUID, action model
 		IP:            ip,
 		UserAgent:     userAgent,
 		Details:       details,
+		TraceID:       tracing.TraceID(ctx),
 	})
 }
 
@@ -1036,6 +1671,16 @@ func (s *AuthService) checkAuthMethodAllowed(ctx context.Context, appID *uuid.UU
 	return models.NewAppError(403, fmt.Sprintf("Auth method '%s' is not allowed for this application", method))
 }
 
+// publishEvent publishes an event about the user to the event bus, if one is configured. A
+// request cancelled after its tokens were issued or revoked still gets its event.
+func (s *AuthService) publishEvent(ctx context.Context, eventType string, userID uuid.UUID, data map[string]interface{}) {
+	if s.events == nil {
+		return
+	}
+	// Failures are logged by the event bus; the sign-in or logout itself succeeded
+	_ = s.events.PublishEvent(context.WithoutCancel(ctx), eventType, userID.String(), data)
+}
+
 // uuidPtrToString converts a UUID pointer to string, returning empty string if nil
 func uuidPtrToString(id *uuid.UUID) string {
 	if id == nil {
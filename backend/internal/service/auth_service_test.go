@@ -12,6 +12,7 @@ import (
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
 )
 
@@ -32,7 +33,7 @@ func setupAuthService() (*AuthService, *mockUserStore, *mockTokenStore, *mockRBA
 	passwordPolicy := utils.DefaultPasswordPolicy()
 
 	// TwoFactorService, LoginAlertService, WebhookService, and PasswordChecker are nil for tests
-	svc := NewAuthService(mUser, mToken, mRBAC, mAudit, mJWT, mBlacklist, mCache, mSessionMgr, nil, 10, passwordPolicy, mDB, nil, nil, nil, false, nil)
+	svc := NewAuthService(mUser, mToken, mRBAC, mAudit, mJWT, mBlacklist, mCache, mSessionMgr, nil, 10, passwordPolicy, mDB, nil, nil, nil, false, nil, nil, nil, nil, nil, nil, nil)
 	return svc, mUser, mToken, mRBAC, mAudit, mJWT, mCache, mBlacklist, mDB
 }
 
@@ -198,6 +199,41 @@ func TestAuthService_SignUp(t *testing.T) {
 	})
 }
 
+func TestAuthService_SignUpRecordsOutbox(t *testing.T) {
+	svc, mUser, mToken, mRBAC, mAudit, mJWT, _, _, _ := setupAuthService()
+	outbox, store, _, _ := setupOutboxService()
+	svc.SetOutbox(outbox)
+
+	mRBAC.GetRoleByNameFunc = func(ctx context.Context, name string) (*models.Role, error) {
+		return &models.Role{ID: uuid.New(), Name: "user"}, nil
+	}
+	mUser.CreateFunc = func(ctx context.Context, user *models.User) error { return nil }
+	mRBAC.AssignRoleToUserFunc = func(ctx context.Context, userID, roleID, assignedBy uuid.UUID) error { return nil }
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return &models.User{ID: id, Email: "test@example.com", Roles: []models.Role{{Name: "user"}}}, nil
+	}
+	mJWT.GenerateAccessTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "access_token", nil }
+	mJWT.GenerateRefreshTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "refresh_token", nil }
+	mJWT.GetAccessTokenExpirationFunc = func() time.Duration { return time.Hour }
+	mJWT.GetRefreshTokenExpirationFunc = func() time.Duration { return 24 * time.Hour }
+	mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
+	mAudit.LogFunc = func(params AuditLogParams) {
+		assert.NotEqual(t, models.ActionSignUp, params.Action, "the sign-up is audited through the outbox")
+	}
+
+	_, err := svc.SignUp(context.Background(), &models.CreateUserRequest{
+		Email:    "test@example.com",
+		Username: "testuser",
+		Password: "password123",
+	}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, store.created, 2)
+	assert.Equal(t, models.OutboxKindAudit, store.created[0].Kind)
+	assert.Equal(t, models.OutboxKindWebhook, store.created[1].Kind)
+	assert.Contains(t, string(store.created[1].Payload), models.WebhookEventUserCreated)
+}
+
 func TestAuthService_SignIn(t *testing.T) {
 	svc, mUser, mToken, _, mAudit, mJWT, _, _, _ := setupAuthService()
 	ctx := context.Background()
@@ -289,28 +325,35 @@ func TestAuthService_SignIn(t *testing.T) {
 		assert.Empty(t, resp.AccessToken)
 	})
 
-	t.Run("InactiveUser_StillSignsIn", func(t *testing.T) {
-		// SignIn does NOT explicitly check IsActive (passes nil to GetByEmail).
-		// An inactive user with correct credentials will still authenticate.
-		mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
-			return &models.User{
-				ID:           userID,
-				Email:        req.Email,
-				PasswordHash: hash,
-				IsActive:     false,
-			}, nil
+	t.Run("InactiveUser_Refused", func(t *testing.T) {
+		// A correct password is not enough: only active accounts may sign in
+		tests := []struct {
+			state models.UserState
+			want  error
+		}{
+			{models.UserStateSuspended, models.ErrAccountSuspended},
+			{models.UserStateDeactivated, models.ErrAccountDeactivated},
+			{models.UserStateInvited, models.ErrAccountInvited},
+			{models.UserStateDeleted, models.ErrInvalidCredentials},
+		}
+		for _, tt := range tests {
+			mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+				return &models.User{
+					ID:           userID,
+					Email:        req.Email,
+					PasswordHash: hash,
+					State:        tt.state,
+				}, nil
+			}
+			var audited AuditLogParams
+			mAudit.LogFunc = func(params AuditLogParams) { audited = params }
+
+			resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+			assert.ErrorIs(t, err, tt.want, tt.state)
+			assert.Nil(t, resp)
+			assert.Equal(t, models.StatusBlocked, audited.Status)
+			assert.Equal(t, "account_"+string(tt.state), audited.Details["reason"])
 		}
-		mJWT.GenerateAccessTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "at", nil }
-		mJWT.GenerateRefreshTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "rt", nil }
-		mJWT.GetAccessTokenExpirationFunc = func() time.Duration { return time.Hour }
-		mJWT.GetRefreshTokenExpirationFunc = func() time.Duration { return 24 * time.Hour }
-		mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
-		mAudit.LogFunc = func(params AuditLogParams) {}
-
-		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
-		assert.NoError(t, err)
-		assert.NotNil(t, resp)
-		assert.Equal(t, "at", resp.AccessToken)
 	})
 
 	t.Run("DBError_GetByEmail", func(t *testing.T) {
@@ -424,6 +467,23 @@ func TestAuthService_RefreshToken(t *testing.T) {
 		// so they will be zero-value. Verify the response is returned without error.
 	})
 
+	t.Run("TokenVersionRevoked", func(t *testing.T) {
+		svc.tokenVersions = &mockTokenVersionChecker{revoked: true}
+		defer func() { svc.tokenVersions = nil }()
+
+		mJWT.ValidateRefreshTokenFunc = func(tokenString string) (*jwt.Claims, error) {
+			return &jwt.Claims{UserID: userID}, nil
+		}
+		mAudit.LogFunc = func(params AuditLogParams) {
+			assert.Equal(t, models.StatusFailed, params.Status)
+			assert.Equal(t, "token_version_revoked", params.Details["reason"])
+		}
+
+		resp, err := svc.RefreshToken(ctx, refreshToken, "1.1.1.1", "ua", models.DeviceInfo{})
+		assert.ErrorIs(t, err, models.ErrTokenRevoked)
+		assert.Nil(t, resp)
+	})
+
 	t.Run("RevokedToken", func(t *testing.T) {
 		mJWT.ValidateRefreshTokenFunc = func(tokenString string) (*jwt.Claims, error) {
 			return &jwt.Claims{UserID: userID}, nil
@@ -613,6 +673,21 @@ func TestAuthService_ChangePassword(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("BumpsTokenVersion", func(t *testing.T) {
+		tokenVersions := &mockTokenVersionChecker{}
+		svc.tokenVersions = tokenVersions
+		defer func() { svc.tokenVersions = nil }()
+
+		mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			return &models.User{ID: userID, PasswordHash: hash, IsActive: true}, nil
+		}
+		mAudit.LogFunc = nil
+
+		err := svc.ChangePassword(ctx, userID, oldPwd, newPwd, "1.1.1.1", "ua")
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{userID}, tokenVersions.bumped)
+	})
+
 	t.Run("WrongPassword", func(t *testing.T) {
 		mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
 			return &models.User{ID: userID, PasswordHash: hash, IsActive: true}, nil
@@ -628,6 +703,339 @@ func TestAuthService_ChangePassword(t *testing.T) {
 	})
 }
 
+func TestAuthService_ChangePassword_PasswordHistory(t *testing.T) {
+	svc, mUser, _, _, _, _, _, _, _ := setupAuthService()
+	history := &mockPasswordHistoryStore{}
+	svc.passwordHistory = history
+	svc.passwordPolicy.HistoryCount = 2
+	ctx := context.Background()
+	userID := uuid.New()
+
+	currentHash, _ := utils.HashPassword("password1", 4)
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return &models.User{ID: userID, PasswordHash: currentHash, IsActive: true}, nil
+	}
+	mUser.UpdatePasswordFunc = func(ctx context.Context, id uuid.UUID, hash string) error {
+		currentHash = hash
+		return nil
+	}
+
+	t.Run("RejectsCurrentPassword", func(t *testing.T) {
+		err := svc.ChangePassword(ctx, userID, "password1", "password1", "1.1.1.1", "ua")
+		assert.ErrorIs(t, err, models.ErrPasswordReused)
+	})
+
+	t.Run("RecordsAndRejectsRecentPasswords", func(t *testing.T) {
+		assert.NoError(t, svc.ChangePassword(ctx, userID, "password1", "password2", "1.1.1.1", "ua"))
+		assert.NoError(t, svc.ChangePassword(ctx, userID, "password2", "password3", "1.1.1.1", "ua"))
+
+		err := svc.ChangePassword(ctx, userID, "password3", "password2", "1.1.1.1", "ua")
+		assert.ErrorIs(t, err, models.ErrPasswordReused)
+	})
+
+	t.Run("AllowsPasswordsBeyondHistoryDepth", func(t *testing.T) {
+		assert.NoError(t, svc.ChangePassword(ctx, userID, "password3", "password4", "1.1.1.1", "ua"))
+		assert.Len(t, history.entries, 2)
+		assert.Equal(t, 1, history.pruned)
+
+		// password2 has dropped out of the two-entry history
+		assert.NoError(t, svc.ChangePassword(ctx, userID, "password4", "password2", "1.1.1.1", "ua"))
+	})
+
+	t.Run("ResetPasswordRejectsRecentPasswords", func(t *testing.T) {
+		err := svc.ResetPassword(ctx, userID, "password4", "1.1.1.1", "ua")
+		assert.ErrorIs(t, err, models.ErrPasswordReused)
+	})
+}
+
+func TestLoginIdentifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       models.SignInRequest
+		wantKind  string
+		wantValue string
+	}{
+		{"Email field", models.SignInRequest{Email: "user@example.com"}, models.LoginIdentifierEmail, "user@example.com"},
+		{"Phone field", models.SignInRequest{Phone: utils.Ptr("+15551234567")}, models.LoginIdentifierPhone, "+15551234567"},
+		{"Username field", models.SignInRequest{Username: "johndoe"}, models.LoginIdentifierUsername, "johndoe"},
+		{"Identifier email", models.SignInRequest{Identifier: "User@Example.com"}, models.LoginIdentifierEmail, "User@Example.com"},
+		{"Identifier E.164 phone", models.SignInRequest{Identifier: "+1 (555) 123-4567"}, models.LoginIdentifierPhone, "+1 (555) 123-4567"},
+		{"Identifier digits", models.SignInRequest{Identifier: "15551234567"}, models.LoginIdentifierPhone, "15551234567"},
+		{"Identifier username", models.SignInRequest{Identifier: " johndoe "}, models.LoginIdentifierUsername, "johndoe"},
+		{"Nothing", models.SignInRequest{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, value := loginIdentifier(&tt.req)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestAuthService_SignIn_LoginIdentifiers(t *testing.T) {
+	svc, mUser, mToken, _, mAudit, mJWT, _, _, _ := setupAuthService()
+	ctx := context.Background()
+
+	password := "password123"
+	hash, _ := utils.HashPassword(password, 10)
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Username: "johndoe", Phone: utils.Ptr("+15551234567"), PasswordHash: hash, IsActive: true}
+
+	mUser.GetByUsernameFunc = func(ctx context.Context, username string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if username == user.Username {
+			return user, nil
+		}
+		return nil, models.ErrUserNotFound
+	}
+	mUser.GetByPhoneFunc = func(ctx context.Context, phone string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if phone == *user.Phone {
+			return user, nil
+		}
+		return nil, models.ErrUserNotFound
+	}
+	mJWT.GenerateAccessTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "access_token", nil }
+	mJWT.GenerateRefreshTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "refresh_token", nil }
+	mJWT.GetAccessTokenExpirationFunc = func() time.Duration { return time.Hour }
+	mJWT.GetRefreshTokenExpirationFunc = func() time.Duration { return 24 * time.Hour }
+	mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
+	mAudit.LogFunc = func(params AuditLogParams) {}
+
+	t.Run("UsernameIsNormalized", func(t *testing.T) {
+		resp, err := svc.SignIn(ctx, &models.SignInRequest{Identifier: "JohnDoe", Password: password}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "access_token", resp.AccessToken)
+	})
+
+	t.Run("PhoneIsNormalized", func(t *testing.T) {
+		resp, err := svc.SignIn(ctx, &models.SignInRequest{Identifier: "+1 (555) 123-4567", Password: password}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "access_token", resp.AccessToken)
+	})
+
+	t.Run("IdentifierRequired", func(t *testing.T) {
+		_, err := svc.SignIn(ctx, &models.SignInRequest{Password: password}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 400, appErr.Code)
+	})
+
+	t.Run("DisabledIdentifierRejected", func(t *testing.T) {
+		restricted := NewAuthService(mUser, mToken, &mockRBACStore{}, mAudit, mJWT, &mockBlacklistChecker{}, &mockCacheService{}, &mockSessionManager{}, nil, 10,
+			utils.DefaultPasswordPolicy(), &mockTransactionDB{}, nil, nil, nil, false, nil, nil, nil, nil, []string{models.LoginIdentifierEmail}, nil, nil)
+
+		_, err := restricted.SignIn(ctx, &models.SignInRequest{Username: "johndoe", Password: password}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 400, appErr.Code)
+	})
+}
+
+func TestAuthService_SignIn_PasswordChangeRequired(t *testing.T) {
+	svc, mUser, _, _, mAudit, mJWT, _, _, _ := setupAuthService()
+	expiryStore := newMockPasswordExpiryStore()
+	svc.passwordExpiry = newTestPasswordExpiryService(expiryStore, nil)
+	ctx := context.Background()
+
+	hash, _ := utils.HashPassword("password123", 4)
+	totpSecret := "JBSWY3DPEHPK3PXP"
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash, IsActive: true, TOTPSecret: &totpSecret}
+	mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	mJWT.GeneratePasswordChangeTokenFunc = func(u *models.User) (string, error) { return "change_token", nil }
+	mJWT.GenerateTwoFactorTokenFunc = func(u *models.User, applicationID ...*uuid.UUID) (string, error) { return "2fa_token", nil }
+	mJWT.ValidateAccessTokenFunc = func(tokenString string) (*jwt.Claims, error) {
+		return &jwt.Claims{UserID: user.ID}, nil
+	}
+	var auditReasons []interface{}
+	mAudit.LogFunc = func(params AuditLogParams) { auditReasons = append(auditReasons, params.Details["reason"]) }
+
+	req := &models.SignInRequest{Email: user.Email, Password: "password123"}
+
+	t.Run("Expired", func(t *testing.T) {
+		expiryStore.expiry[user.ID] = time.Now().Add(-time.Hour)
+		defer delete(expiryStore.expiry, user.ID)
+
+		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Nil(t, resp)
+
+		var changeErr *models.PasswordChangeRequiredError
+		assert.ErrorAs(t, err, &changeErr)
+		assert.Equal(t, models.PasswordChangeReasonExpired, changeErr.Reason)
+		assert.Equal(t, "change_token", changeErr.ResetToken)
+		assert.Contains(t, auditReasons, "password_change_required")
+	})
+
+	t.Run("RiskBlockedBeforePasswordChange", func(t *testing.T) {
+		user.MustChangePassword = true
+		defer func() { user.MustChangePassword = false }()
+		svc.SetRiskEngine(&mockRiskAssessor{assessment: &models.RiskAssessment{Action: models.RiskActionBlock}})
+		defer svc.SetRiskEngine(nil)
+
+		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Nil(t, resp)
+		assert.Equal(t, errSignInBlockedByRisk, err)
+	})
+
+	t.Run("AdminRequired_AfterTwoFactor", func(t *testing.T) {
+		// The change token replaces the password, so the password alone must not reveal it
+		user.MustChangePassword = true
+		user.TOTPEnabled = true
+		defer func() { user.MustChangePassword, user.TOTPEnabled = false, false }()
+
+		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		require.NoError(t, err)
+		assert.True(t, resp.Requires2FA)
+		assert.Empty(t, resp.AccessToken)
+
+		_, err = svc.Verify2FALogin(ctx, resp.TwoFactorToken, "invalid", "1.1.1.1", "ua", models.DeviceInfo{})
+		var changeErr *models.PasswordChangeRequiredError
+		assert.False(t, errors.As(err, &changeErr), "a wrong code must not reveal the change token")
+
+		code, err := totp.GenerateCode(totpSecret, time.Now())
+		require.NoError(t, err)
+		resp, err = svc.Verify2FALogin(ctx, resp.TwoFactorToken, code, "1.1.1.1", "ua", models.DeviceInfo{})
+		assert.Nil(t, resp)
+		require.ErrorAs(t, err, &changeErr)
+		assert.Equal(t, models.PasswordChangeReasonAdminRequired, changeErr.Reason)
+		assert.Equal(t, "change_token", changeErr.ResetToken)
+	})
+
+	t.Run("WrongPasswordStillInvalidCredentials", func(t *testing.T) {
+		resp, err := svc.SignIn(ctx, &models.SignInRequest{Email: user.Email, Password: "wrong"}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+	})
+}
+
+func TestAuthService_SignIn_EmailNotVerified(t *testing.T) {
+	svc, mUser, _, _, mAudit, _, _, _, _ := setupAuthService()
+	svc.emailVerification = NewEmailVerificationService(nil, nil, models.EmailVerificationBlock, 0, nil, nil)
+	ctx := context.Background()
+
+	hash, _ := utils.HashPassword("password123", 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash, IsActive: true, TOTPEnabled: true}
+	mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	var auditReasons []interface{}
+	mAudit.LogFunc = func(params AuditLogParams) { auditReasons = append(auditReasons, params.Details["reason"]) }
+
+	req := &models.SignInRequest{Email: user.Email, Password: "password123"}
+
+	t.Run("Blocked", func(t *testing.T) {
+		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Nil(t, resp)
+		assert.Equal(t, models.ErrEmailNotVerified, err)
+		assert.Contains(t, auditReasons, "email_not_verified")
+	})
+
+	t.Run("VerifiedProceeds", func(t *testing.T) {
+		user.EmailVerified = true
+
+		resp, err := svc.SignIn(ctx, req, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		require.NoError(t, err)
+		assert.True(t, resp.Requires2FA)
+	})
+}
+
+func TestAuthService_CompleteSignIn(t *testing.T) {
+	svc, _, mToken, _, mAudit, mJWT, _, _, _ := setupAuthService()
+	ctx := context.Background()
+
+	mJWT.GenerateAccessTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "access_token", nil }
+	mJWT.GenerateRefreshTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) { return "refresh_token", nil }
+	mJWT.GetAccessTokenExpirationFunc = func() time.Duration { return time.Hour }
+	mJWT.GetRefreshTokenExpirationFunc = func() time.Duration { return 24 * time.Hour }
+	mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
+	var audits []AuditLogParams
+	mAudit.LogFunc = func(params AuditLogParams) { audits = append(audits, params) }
+
+	t.Run("IssuesSession", func(t *testing.T) {
+		audits = nil
+		user := &models.User{ID: uuid.New(), Email: "test@example.com", State: models.UserStateActive}
+
+		resp, err := svc.CompleteSignIn(ctx, user, "1.1.1.1", "ua", models.DeviceInfo{}, nil, models.AuthMethodMagicLink)
+		require.NoError(t, err)
+		assert.Equal(t, "access_token", resp.AccessToken)
+		require.Len(t, audits, 1)
+		assert.Equal(t, models.ActionSignIn, audits[0].Action)
+		assert.Equal(t, models.AuthMethodMagicLink, audits[0].Details["auth_method"])
+	})
+
+	t.Run("Requires2FA", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), Email: "test@example.com", State: models.UserStateActive, TOTPEnabled: true}
+
+		resp, err := svc.CompleteSignIn(ctx, user, "1.1.1.1", "ua", models.DeviceInfo{}, nil, models.AuthMethodMagicLink)
+		require.NoError(t, err)
+		assert.True(t, resp.Requires2FA)
+		assert.Empty(t, resp.AccessToken)
+	})
+
+	t.Run("InactiveAccount", func(t *testing.T) {
+		audits = nil
+		user := &models.User{ID: uuid.New(), Email: "test@example.com", State: models.UserStateSuspended}
+
+		resp, err := svc.CompleteSignIn(ctx, user, "1.1.1.1", "ua", models.DeviceInfo{}, nil, models.AuthMethodMagicLink)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		require.Len(t, audits, 1)
+		assert.Equal(t, models.ActionSignInFailed, audits[0].Action)
+		assert.Equal(t, "account_suspended", audits[0].Details["reason"])
+	})
+}
+
+func TestAuthService_CompleteRequiredPasswordChange(t *testing.T) {
+	svc, mUser, _, _, mAudit, mJWT, _, _, _ := setupAuthService()
+	versions := &mockTokenVersionChecker{}
+	svc.tokenVersions = versions
+	ctx := context.Background()
+	userID := uuid.New()
+	mAudit.LogFunc = func(params AuditLogParams) {}
+
+	var updated uuid.UUID
+	mUser.UpdatePasswordFunc = func(ctx context.Context, id uuid.UUID, hash string) error {
+		updated = id
+		return nil
+	}
+	mJWT.ValidatePasswordChangeTokenFunc = func(tokenString string) (*jwt.Claims, error) {
+		if tokenString != "change_token" {
+			return nil, jwt.ErrInvalidToken
+		}
+		return &jwt.Claims{UserID: userID, TokenType: jwt.TokenTypePasswordChange}, nil
+	}
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		err := svc.CompleteRequiredPasswordChange(ctx, "bogus", "NewPassword123!", "1.1.1.1", "ua")
+		var appErr *models.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 401, appErr.Code)
+		assert.Equal(t, uuid.Nil, updated)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		err := svc.CompleteRequiredPasswordChange(ctx, "change_token", "NewPassword123!", "1.1.1.1", "ua")
+		assert.NoError(t, err)
+		assert.Equal(t, userID, updated)
+		assert.Equal(t, []uuid.UUID{userID}, versions.bumped)
+	})
+
+	t.Run("TokenAlreadyUsed", func(t *testing.T) {
+		versions.revoked = true
+		updated = uuid.Nil
+
+		err := svc.CompleteRequiredPasswordChange(ctx, "change_token", "OtherPassword123!", "1.1.1.1", "ua")
+		var appErr *models.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 401, appErr.Code)
+		assert.Equal(t, uuid.Nil, updated)
+	})
+}
+
 func TestAuthService_InitPasswordlessRegistration(t *testing.T) {
 	svc, _, _, _, mAudit, _, mCache, _, _ := setupAuthService()
 	ctx := context.Background()
@@ -839,7 +1247,7 @@ func setupAuthServiceWith2FA() (*AuthService, *mockUserStore, *mockTokenStore, *
 	// Create default password policy
 	passwordPolicy := utils.DefaultPasswordPolicy()
 
-	svc := NewAuthService(mUser, mToken, mRBAC, mAudit, mJWT, mBlacklist, mCache, mSessionMgr, twoFAService, 10, passwordPolicy, mDB, nil, nil, nil, false, nil)
+	svc := NewAuthService(mUser, mToken, mRBAC, mAudit, mJWT, mBlacklist, mCache, mSessionMgr, twoFAService, 10, passwordPolicy, mDB, nil, nil, nil, false, nil, nil, nil, nil, nil, nil, nil)
 	return svc, mUser, mToken, mRBAC, mAudit, mJWT, mCache, mBlacklist, mDB, mBackupCode
 }
 
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	backchannelLogoutTokenTTL      = 2 * time.Minute
+	backchannelLogoutBatchSize     = 50
+	backchannelLogoutLease         = 2 * time.Minute // longer than a batch of attempts takes
+	backchannelLogoutPollInterval  = 15 * time.Second
+	backchannelLogoutRetryBase     = 30 * time.Second
+	backchannelLogoutRetryMax      = time.Hour
+	backchannelLogoutRetention     = 7 * 24 * time.Hour
+	backchannelLogoutCleanupPeriod = time.Hour
+	defaultBackchannelLogoutTries  = 5
+)
+
+// BackchannelLogoutService sends logout tokens to the back-channel logout URIs of clients a
+// user is signed in to when the user logs out or a session is revoked (OIDC Back-Channel
+// Logout 1.0).
+//
+// Deliveries are queued in the database and sent by Run on any instance, retried with
+// exponential backoff until the client accepts the token, rejects it with 400, or
+// maxAttempts is reached. OAuth tokens are not tied to gateway sessions, so logout tokens
+// identify the user (sub) rather than a session (sid).
+type BackchannelLogoutService struct {
+	store       BackchannelLogoutStore
+	signer      LogoutTokenSigner
+	httpClient  HTTPClient
+	logger      *logger.Logger
+	maxAttempts int
+	now         func() time.Time
+	wake        chan struct{}
+}
+
+// NewBackchannelLogoutService creates a back-channel logout service
+func NewBackchannelLogoutService(store BackchannelLogoutStore, signer LogoutTokenSigner, httpClient HTTPClient, maxAttempts int, log *logger.Logger) *BackchannelLogoutService {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultBackchannelLogoutTries
+	}
+	return &BackchannelLogoutService{
+		store:       store,
+		signer:      signer,
+		httpClient:  httpClient,
+		logger:      log,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// NotifyLogout queues logout tokens for every client holding tokens of the user that has a
+// back-channel logout URI. Delivery starts right away on this instance.
+func (s *BackchannelLogoutService) NotifyLogout(ctx context.Context, userID uuid.UUID) {
+	queued, err := s.store.EnqueueForUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to queue back-channel logouts", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return
+	}
+	if queued == 0 {
+		return
+	}
+
+	s.logger.Debug("Back-channel logouts queued", map[string]interface{}{
+		"user_id": userID.String(),
+		"clients": queued,
+	})
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers queued logout tokens until ctx is done
+func (s *BackchannelLogoutService) Run(ctx context.Context) {
+	poll := time.NewTicker(backchannelLogoutPollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(backchannelLogoutCleanupPeriod)
+	defer cleanup.Stop()
+
+	for {
+		s.DeliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-s.wake:
+		case <-cleanup.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+// DeliverDue attempts every delivery that is due and returns how many were accepted
+func (s *BackchannelLogoutService) DeliverDue(ctx context.Context) int {
+	var delivered atomic.Int64
+	for ctx.Err() == nil {
+		batch, err := s.store.ClaimDue(ctx, backchannelLogoutBatchSize, backchannelLogoutLease)
+		if err != nil {
+			s.logger.Error("Failed to load back-channel logouts", map[string]interface{}{
+				"error": err.Error(),
+			})
+			break
+		}
+
+		// A slow client must not hold up the others
+		var wg sync.WaitGroup
+		for _, delivery := range batch {
+			wg.Add(1)
+			go func(d *models.BackchannelLogoutDelivery) {
+				defer wg.Done()
+				if s.deliver(ctx, d) {
+					delivered.Add(1)
+				}
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(batch) < backchannelLogoutBatchSize {
+			break
+		}
+	}
+	return int(delivered.Load())
+}
+
+// deliver makes one attempt and records its outcome; it reports whether the client accepted
+func (s *BackchannelLogoutService) deliver(ctx context.Context, d *models.BackchannelLogoutDelivery) bool {
+	var err error
+	permanent := false
+	if d.Client == nil || !d.Client.IsActive || d.Client.BackchannelLogoutURI == "" {
+		err, permanent = fmt.Errorf("client no longer has a back-channel logout URI"), true
+	} else {
+		err, permanent = s.send(ctx, d)
+	}
+
+	d.Attempts++
+	switch {
+	case err == nil:
+		d.Status = models.BackchannelLogoutDelivered
+		d.LastError = ""
+	case permanent || d.Attempts >= s.maxAttempts:
+		d.Status = models.BackchannelLogoutFailed
+		d.LastError = err.Error()
+	default:
+		d.NextAttemptAt = s.now().Add(backchannelLogoutBackoff(d.Attempts))
+		d.LastError = err.Error()
+	}
+
+	if err != nil {
+		fields := map[string]interface{}{
+			"delivery_id": d.ID.String(),
+			"user_id":     d.UserID.String(),
+			"attempts":    d.Attempts,
+			"status":      d.Status,
+			"error":       err.Error(),
+		}
+		if d.Client != nil {
+			fields["client_id"] = d.Client.ClientID
+		}
+		s.logger.Warn("Back-channel logout delivery failed", fields)
+	}
+
+	// The outcome is recorded even if the run is being stopped
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if updateErr := s.store.Update(updateCtx, d); updateErr != nil {
+		s.logger.Error("Failed to record back-channel logout delivery", map[string]interface{}{
+			"delivery_id": d.ID.String(),
+			"error":       updateErr.Error(),
+		})
+	}
+
+	return err == nil
+}
+
+// send posts a fresh logout token to the client. permanent reports errors that retrying
+// will not fix: the client answers 400 to tokens it rejects.
+func (s *BackchannelLogoutService) send(ctx context.Context, d *models.BackchannelLogoutDelivery) (err error, permanent bool) {
+	token, err := s.signer.GenerateLogoutToken(d.Client.ClientID, d.UserID.String(), backchannelLogoutTokenTTL)
+	if err != nil {
+		return fmt.Errorf("failed to sign logout token: %w", err), false
+	}
+
+	form := url.Values{"logout_token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Client.BackchannelLogoutURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("invalid back-channel logout URI: %w", err), true
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err, false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil, false
+	case resp.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("client rejected the logout token (status %d)", resp.StatusCode), true
+	default:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode), false
+	}
+}
+
+func (s *BackchannelLogoutService) cleanup(ctx context.Context) {
+	deleted, err := s.store.DeleteFinishedBefore(ctx, s.now().Add(-backchannelLogoutRetention))
+	if err != nil {
+		s.logger.Error("Failed to delete old back-channel logouts", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debug("Deleted old back-channel logouts", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+}
+
+// backchannelLogoutBackoff is the wait after the given number of failed attempts
+func backchannelLogoutBackoff(attempts int) time.Duration {
+	wait := backchannelLogoutRetryBase
+	for i := 1; i < attempts && wait < backchannelLogoutRetryMax; i++ {
+		wait *= 2
+	}
+	if wait > backchannelLogoutRetryMax {
+		wait = backchannelLogoutRetryMax
+	}
+	return wait
+}
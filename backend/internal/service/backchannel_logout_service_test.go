@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackchannelLogoutStore struct {
+	mu       sync.Mutex
+	due      []*models.BackchannelLogoutDelivery
+	updated  []*models.BackchannelLogoutDelivery
+	enqueued []uuid.UUID
+}
+
+func (m *mockBackchannelLogoutStore) EnqueueForUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.enqueued = append(m.enqueued, userID)
+	return 1, nil
+}
+
+func (m *mockBackchannelLogoutStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackchannelLogoutDelivery, error) {
+	due := m.due
+	m.due = nil
+	return due, nil
+}
+
+func (m *mockBackchannelLogoutStore) Update(ctx context.Context, delivery *models.BackchannelLogoutDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updated = append(m.updated, delivery)
+	return nil
+}
+
+func (m *mockBackchannelLogoutStore) DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+type mockLogoutTokenSigner struct {
+	err error
+}
+
+func (m *mockLogoutTokenSigner) GenerateLogoutToken(clientID, subject string, ttl time.Duration) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return "token-for-" + clientID + "-" + subject, nil
+}
+
+func newBackchannelLogoutDelivery(uri string, attempts int) *models.BackchannelLogoutDelivery {
+	return &models.BackchannelLogoutDelivery{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		Status:   models.BackchannelLogoutPending,
+		Attempts: attempts,
+		Client: &models.OAuthClient{
+			ClientID:             "client-1",
+			IsActive:             true,
+			BackchannelLogoutURI: uri,
+		},
+	}
+}
+
+func setupBackchannelLogoutService(t *testing.T, status int) (*BackchannelLogoutService, *mockBackchannelLogoutStore, *mockLogoutTokenSigner, string) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.Contains(t, r.PostFormValue("logout_token"), "token-for-client-1-")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &mockBackchannelLogoutStore{}
+	signer := &mockLogoutTokenSigner{}
+	svc := NewBackchannelLogoutService(store, signer, srv.Client(), 3, logger.New("test", logger.ErrorLevel, false))
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, store, signer, srv.URL
+}
+
+func TestBackchannelLogoutService_Delivered(t *testing.T) {
+	svc, store, _, uri := setupBackchannelLogoutService(t, http.StatusOK)
+	store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery(uri, 0)}
+
+	assert.Equal(t, 1, svc.DeliverDue(context.Background()))
+
+	require.Len(t, store.updated, 1)
+	assert.Equal(t, models.BackchannelLogoutDelivered, store.updated[0].Status)
+	assert.Equal(t, 1, store.updated[0].Attempts)
+	assert.Empty(t, store.updated[0].LastError)
+}
+
+func TestBackchannelLogoutService_RetriesWithBackoff(t *testing.T) {
+	svc, store, _, uri := setupBackchannelLogoutService(t, http.StatusServiceUnavailable)
+	store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery(uri, 1)}
+
+	assert.Equal(t, 0, svc.DeliverDue(context.Background()))
+
+	require.Len(t, store.updated, 1)
+	d := store.updated[0]
+	assert.Equal(t, models.BackchannelLogoutPending, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+	assert.Equal(t, svc.now().Add(time.Minute), d.NextAttemptAt)
+	assert.Contains(t, d.LastError, "503")
+}
+
+func TestBackchannelLogoutService_GivesUp(t *testing.T) {
+	t.Run("rejected token", func(t *testing.T) {
+		svc, store, _, uri := setupBackchannelLogoutService(t, http.StatusBadRequest)
+		store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery(uri, 0)}
+
+		svc.DeliverDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackchannelLogoutFailed, store.updated[0].Status)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		svc, store, _, uri := setupBackchannelLogoutService(t, http.StatusInternalServerError)
+		store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery(uri, 2)}
+
+		svc.DeliverDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackchannelLogoutFailed, store.updated[0].Status)
+		assert.Equal(t, 3, store.updated[0].Attempts)
+	})
+
+	t.Run("URI removed", func(t *testing.T) {
+		svc, store, _, _ := setupBackchannelLogoutService(t, http.StatusOK)
+		store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery("", 0)}
+
+		svc.DeliverDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackchannelLogoutFailed, store.updated[0].Status)
+	})
+}
+
+func TestBackchannelLogoutService_SigningErrorIsRetried(t *testing.T) {
+	svc, store, signer, uri := setupBackchannelLogoutService(t, http.StatusOK)
+	signer.err = errors.New("no signing key")
+	store.due = []*models.BackchannelLogoutDelivery{newBackchannelLogoutDelivery(uri, 0)}
+
+	svc.DeliverDue(context.Background())
+
+	require.Len(t, store.updated, 1)
+	assert.Equal(t, models.BackchannelLogoutPending, store.updated[0].Status)
+	assert.Equal(t, svc.now().Add(30*time.Second), store.updated[0].NextAttemptAt)
+}
+
+func TestBackchannelLogoutService_NotifyLogout(t *testing.T) {
+	svc, store, _, _ := setupBackchannelLogoutService(t, http.StatusOK)
+	userID := uuid.New()
+
+	svc.NotifyLogout(context.Background(), userID)
+	svc.NotifyLogout(context.Background(), userID)
+
+	assert.Equal(t, []uuid.UUID{userID, userID}, store.enqueued)
+	assert.Len(t, svc.wake, 1)
+}
+
+func TestBackchannelLogoutBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backchannelLogoutBackoff(1))
+	assert.Equal(t, 2*time.Minute, backchannelLogoutBackoff(3))
+	assert.Equal(t, time.Hour, backchannelLogoutBackoff(20))
+}
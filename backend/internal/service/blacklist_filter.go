@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// BlacklistFilter is an in-memory bloom filter of blacklisted token hashes.
+// A miss means the token is definitely not blacklisted, so validation can skip Redis and
+// the database; a hit may be a false positive and falls through to the regular lookup.
+//
+// The filter starts not ready and answers "maybe" for every token until the first Rebuild.
+// While updates from other instances cannot be received it is suspended and answers "maybe"
+// again until resumed and rebuilt.
+type BlacklistFilter struct {
+	mu              sync.RWMutex
+	bits            []uint64
+	hashes          uint64 // hash functions per entry
+	ready           bool
+	suspended       bool
+	generation      uint64   // bumped by Suspend and Resume so an in-flight rebuild does not mark the filter ready
+	rebuilding      bool     // a rebuild is loading entries; additions are also kept in pending
+	pending         []string // entries added while rebuilding, merged into the rebuilt filter
+	expectedEntries int
+	falsePositive   float64
+}
+
+// NewBlacklistFilter creates a filter sized for expectedEntries at the given false positive rate.
+// Rebuilds grow it when the blacklist outgrows expectedEntries.
+func NewBlacklistFilter(expectedEntries int, falsePositiveRate float64) *BlacklistFilter {
+	if expectedEntries < 1 {
+		expectedEntries = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	f := &BlacklistFilter{
+		expectedEntries: expectedEntries,
+		falsePositive:   falsePositiveRate,
+	}
+	f.bits, f.hashes = f.newBits(expectedEntries)
+	return f
+}
+
+// MayContain reports whether tokenHash may be blacklisted. It returns true on a nil or
+// not-ready filter, so callers can always consult it before the regular lookup.
+func (f *BlacklistFilter) MayContain(tokenHash string) bool {
+	if f == nil {
+		return true
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.ready {
+		return true
+	}
+	return testBits(f.bits, f.hashes, tokenHash)
+}
+
+// Add records a blacklisted token hash
+func (f *BlacklistFilter) Add(tokenHash string) {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	setBits(f.bits, f.hashes, tokenHash)
+	if f.rebuilding {
+		f.pending = append(f.pending, tokenHash)
+	}
+}
+
+// Suspend stops the filter from answering, e.g. while blacklist updates from other
+// instances cannot be received. Rebuilds do not make it ready until Resume.
+func (f *BlacklistFilter) Suspend() {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.suspended = true
+	f.ready = false
+	f.generation++
+}
+
+// Resume allows the next Rebuild to make the filter ready again. Updates may have been
+// missed in the meantime, so a rebuild started before Resume does not count.
+func (f *BlacklistFilter) Resume() {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.suspended = false
+	f.ready = false
+	f.generation++
+}
+
+// Ready reports whether the filter is answering lookups
+func (f *BlacklistFilter) Ready() bool {
+	if f == nil {
+		return false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.ready
+}
+
+// Rebuild replaces the filter contents with the entries returned by load; entries added while
+// load runs are kept. The filter becomes ready unless it is suspended or was suspended or
+// resumed during the rebuild.
+func (f *BlacklistFilter) Rebuild(ctx context.Context, load func(ctx context.Context) ([]string, error)) error {
+	f.mu.Lock()
+	f.rebuilding = true
+	f.pending = nil
+	generation := f.generation
+	f.mu.Unlock()
+
+	tokenHashes, err := load(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rebuilding = false
+	if err != nil {
+		f.pending = nil
+		return err
+	}
+
+	size := len(tokenHashes) + len(f.pending)
+	if size < f.expectedEntries {
+		size = f.expectedEntries
+	} else {
+		// Leave room for entries added until the next rebuild
+		size *= 2
+	}
+	bits, hashes := f.newBits(size)
+	for _, tokenHash := range tokenHashes {
+		setBits(bits, hashes, tokenHash)
+	}
+	for _, tokenHash := range f.pending {
+		setBits(bits, hashes, tokenHash)
+	}
+
+	f.bits, f.hashes = bits, hashes
+	f.pending = nil
+	f.ready = !f.suspended && f.generation == generation
+	return nil
+}
+
+// newBits sizes a bit set and hash count for entries at the configured false positive rate
+func (f *BlacklistFilter) newBits(entries int) ([]uint64, uint64) {
+	m := math.Ceil(-float64(entries) * math.Log(f.falsePositive) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(entries)*math.Ln2))
+	words := (uint64(m) + 63) / 64
+	return make([]uint64, words), uint64(k)
+}
+
+// bloomHashes returns the two base hashes for double hashing (FNV-1a, split into halves)
+func bloomHashes(s string) (uint64, uint64) {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h & 0xffffffff, h>>32 | 1
+}
+
+func setBits(bits []uint64, hashes uint64, s string) {
+	m := uint64(len(bits)) * 64
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < hashes; i++ {
+		idx := (h1 + i*h2) % m
+		bits[idx/64] |= 1 << (idx % 64)
+	}
+}
+
+func testBits(bits []uint64, hashes uint64, s string) bool {
+	m := uint64(len(bits)) * 64
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < hashes; i++ {
+		idx := (h1 + i*h2) % m
+		if bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadHashes(hashes ...string) func(context.Context) ([]string, error) {
+	return func(context.Context) ([]string, error) { return hashes, nil }
+}
+
+func TestBlacklistFilter_MayContain(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should answer maybe until built", func(t *testing.T) {
+		f := NewBlacklistFilter(100, 0.01)
+		assert.False(t, f.Ready())
+		assert.True(t, f.MayContain(utils.HashToken("any")))
+
+		var nilFilter *BlacklistFilter
+		assert.True(t, nilFilter.MayContain("any"))
+	})
+
+	t.Run("Should never miss a blacklisted hash", func(t *testing.T) {
+		hashes := make([]string, 1000)
+		for i := range hashes {
+			hashes[i] = utils.HashToken(fmt.Sprintf("token-%d", i))
+		}
+		f := NewBlacklistFilter(100, 0.01)
+		require.NoError(t, f.Rebuild(ctx, loadHashes(hashes[:500]...)))
+		for _, h := range hashes[500:] {
+			f.Add(h)
+		}
+
+		require.True(t, f.Ready())
+		for _, h := range hashes {
+			assert.True(t, f.MayContain(h))
+		}
+	})
+
+	t.Run("Should reject most unknown hashes", func(t *testing.T) {
+		f := NewBlacklistFilter(1000, 0.01)
+		require.NoError(t, f.Rebuild(ctx, loadHashes(utils.HashToken("revoked"))))
+
+		falsePositives := 0
+		for i := 0; i < 10000; i++ {
+			if f.MayContain(utils.HashToken(fmt.Sprintf("valid-%d", i))) {
+				falsePositives++
+			}
+		}
+		assert.Less(t, falsePositives, 100)
+	})
+}
+
+func TestBlacklistFilter_Rebuild(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should keep hashes added while loading", func(t *testing.T) {
+		f := NewBlacklistFilter(100, 0.01)
+		added := utils.HashToken("added-during-rebuild")
+
+		require.NoError(t, f.Rebuild(ctx, func(context.Context) ([]string, error) {
+			f.Add(added)
+			return nil, nil
+		}))
+		assert.True(t, f.MayContain(added))
+	})
+
+	t.Run("Should drop hashes no longer loaded", func(t *testing.T) {
+		f := NewBlacklistFilter(100, 0.01)
+		expired := utils.HashToken("expired")
+		require.NoError(t, f.Rebuild(ctx, loadHashes(expired)))
+		require.NoError(t, f.Rebuild(ctx, loadHashes()))
+
+		assert.False(t, f.MayContain(expired))
+	})
+
+	t.Run("Should stay not ready when load fails", func(t *testing.T) {
+		f := NewBlacklistFilter(100, 0.01)
+		err := f.Rebuild(ctx, func(context.Context) ([]string, error) { return nil, errors.New("db down") })
+
+		assert.Error(t, err)
+		assert.False(t, f.Ready())
+	})
+
+	t.Run("Should not become ready while suspended", func(t *testing.T) {
+		f := NewBlacklistFilter(100, 0.01)
+		f.Suspend()
+		require.NoError(t, f.Rebuild(ctx, loadHashes()))
+		assert.False(t, f.Ready())
+
+		// A rebuild that started before the subscription came back may have missed updates
+		require.NoError(t, f.Rebuild(ctx, func(context.Context) ([]string, error) {
+			f.Resume()
+			return nil, nil
+		}))
+		assert.False(t, f.Ready())
+
+		require.NoError(t, f.Rebuild(ctx, loadHashes()))
+		assert.True(t, f.Ready())
+	})
+}
+
+type mockBlacklistEvents struct {
+	Published []string
+}
+
+func (m *mockBlacklistEvents) PublishBlacklisted(ctx context.Context, tokenHash string) error {
+	m.Published = append(m.Published, tokenHash)
+	return nil
+}
+
+func (m *mockBlacklistEvents) WatchBlacklisted(ctx context.Context, onAdd func(tokenHash string), onSubscribed, onInterrupted func()) {
+}
+
+func TestBlacklistService_Filter(t *testing.T) {
+	ctx := context.Background()
+	revoked := utils.HashToken("revoked")
+	valid := utils.HashToken("valid")
+
+	redisLookups := 0
+	cache := &mockCacheService{
+		IsBlacklistedFunc: func(ctx context.Context, tokenHash string) (bool, error) {
+			redisLookups++
+			return tokenHash == revoked, nil
+		},
+	}
+	tokens := &mockTokenStore{
+		GetAllActiveBlacklistEntriesFunc: func(ctx context.Context) ([]*models.TokenBlacklist, error) {
+			return []*models.TokenBlacklist{{TokenHash: revoked, ExpiresAt: time.Now().Add(time.Hour)}}, nil
+		},
+	}
+	events := &mockBlacklistEvents{}
+	svc := NewBlacklistService(cache, tokens, &mockSessionStore{}, &mockTokenService{}, logger.New("test", logger.ErrorLevel, false), &mockAuditLogger{})
+	svc.EnableFilter(NewBlacklistFilter(100, 0.01), events)
+	require.NoError(t, svc.RebuildFilter(ctx))
+
+	assert.False(t, svc.IsBlacklisted(ctx, valid))
+	assert.Equal(t, 0, redisLookups)
+	assert.True(t, svc.IsBlacklisted(ctx, revoked))
+	assert.Equal(t, 1, redisLookups)
+
+	newlyRevoked := utils.HashToken("newly-revoked")
+	require.NoError(t, svc.AddToBlacklist(ctx, newlyRevoked, nil, time.Hour))
+	assert.True(t, svc.Filter().MayContain(newlyRevoked))
+	assert.Equal(t, []string{newlyRevoked}, events.Published)
+}
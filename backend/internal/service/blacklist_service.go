@@ -20,6 +20,14 @@ type BlacklistService struct {
 	logger      *logger.Logger
 	auditLogger AuditLogger
 	syncStats   SyncStats // Statistics for monitoring sync discrepancies
+
+	// Optional bloom filter answering "definitely not blacklisted" without a round trip,
+	// kept in sync across instances through events
+	filter *BlacklistFilter
+	events BlacklistEvents
+
+	// Optional stream of revocations for downstream services
+	revocations *RevocationHub
 }
 
 // SyncStats tracks synchronization statistics
@@ -113,6 +121,56 @@ func (s *BlacklistService) SyncFromDatabase(ctx context.Context) error {
 	return nil
 }
 
+// EnableFilter consults filter before Redis and the database, and keeps it up to date with
+// hashes blacklisted here and, through events, on other instances
+func (s *BlacklistService) EnableFilter(filter *BlacklistFilter, events BlacklistEvents) {
+	s.filter = filter
+	s.events = events
+}
+
+// SetRevocationHub announces blacklisted tokens and revoked sessions through hub
+func (s *BlacklistService) SetRevocationHub(hub *RevocationHub) {
+	s.revocations = hub
+}
+
+// Filter returns the blacklist filter, or nil when it is not enabled
+func (s *BlacklistService) Filter() *BlacklistFilter {
+	return s.filter
+}
+
+// RebuildFilter reloads the filter from the active blacklist entries in the database
+func (s *BlacklistService) RebuildFilter(ctx context.Context) error {
+	if s.filter == nil {
+		return nil
+	}
+
+	return s.filter.Rebuild(ctx, func(ctx context.Context) ([]string, error) {
+		entries, err := s.tokenRepo.GetAllActiveBlacklistEntries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blacklist entries: %w", err)
+		}
+		tokenHashes := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			tokenHashes = append(tokenHashes, entry.TokenHash)
+		}
+		return tokenHashes, nil
+	})
+}
+
+// WatchFilter adds hashes blacklisted on other instances to the filter until ctx is done.
+// The filter stops answering while the event subscription is broken, and onResync is called
+// whenever the subscription is (re)established so the caller can rebuild it.
+func (s *BlacklistService) WatchFilter(ctx context.Context, onResync func()) {
+	if s.filter == nil || s.events == nil {
+		return
+	}
+
+	s.events.WatchBlacklisted(ctx, s.filter.Add, func() {
+		s.filter.Resume()
+		onResync()
+	}, s.filter.Suspend)
+}
+
 // GetSyncStats returns current synchronization statistics
 func (s *BlacklistService) GetSyncStats() SyncStats {
 	return s.syncStats
@@ -122,6 +180,11 @@ func (s *BlacklistService) GetSyncStats() SyncStats {
 // First checks Redis (fast), then falls back to PostgreSQL if needed.
 // If found in DB but not in Redis, restores the entry to Redis cache.
 func (s *BlacklistService) IsBlacklisted(ctx context.Context, tokenHash string) bool {
+	// Common case: the filter knows the token was never blacklisted
+	if !s.filter.MayContain(tokenHash) {
+		return false
+	}
+
 	// First check Redis (fast)
 	blacklisted, redisErr := s.redis.IsBlacklisted(ctx, tokenHash)
 	if blacklisted {
@@ -171,11 +234,31 @@ func (s *BlacklistService) AddToBlacklist(ctx context.Context, tokenHash string,
 		lastErr = err
 	}
 
+	// Update the filter here right away and on other instances through events
+	s.filter.Add(tokenHash)
+	if s.events != nil {
+		if err := s.events.PublishBlacklisted(ctx, tokenHash); err != nil {
+			s.logger.Warn("Failed to publish blacklisted token", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	s.revocations.Publish(ctx, models.RevocationEvent{
+		Type:      models.RevocationTypeToken,
+		TokenHash: tokenHash,
+		UserID:    userID,
+		RevokedAt: now,
+		ExpiresAt: &expiresAt,
+	})
+
 	// Add to PostgreSQL (persistent, survives Redis restart)
 	blacklistEntry := &models.TokenBlacklist{
 		TokenHash: tokenHash,
 		UserID:    userID,
-		ExpiresAt: time.Now().Add(ttl),
+		ExpiresAt: expiresAt,
 	}
 
 	if err := s.tokenRepo.AddToBlacklist(ctx, blacklistEntry); err != nil {
@@ -247,6 +330,12 @@ func (s *BlacklistService) BlacklistSessionTokens(ctx context.Context, session *
 			"session_id": session.ID,
 		})
 	}
+	s.revocations.Publish(ctx, models.RevocationEvent{
+		Type:      models.RevocationTypeSession,
+		SessionID: &session.ID,
+		UserID:    &session.UserID,
+		RevokedAt: time.Now(),
+	})
 	s.auditLogger.Log(AuditLogParams{
 		UserID:    &session.UserID,
 		Action:    models.ActionSessionRevoked,
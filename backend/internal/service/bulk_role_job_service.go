@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// maxBulkRoleJobUsers limits how many users a single job can process
+	maxBulkRoleJobUsers = 10000
+	// maxBulkRoleJobFailures limits how many per-user failures a job keeps
+	maxBulkRoleJobFailures = 1000
+	// bulkRoleJobPageSize is the page size used to select users by query or group
+	bulkRoleJobPageSize = 500
+	// bulkRoleJobProgressEvery is how many users are processed between progress updates
+	bulkRoleJobProgressEvery = 50
+	// bulkRoleJobListLimit is how many jobs ListJobs returns
+	bulkRoleJobListLimit = 50
+)
+
+var (
+	errBulkRoleSelector      = models.NewAppError(http.StatusBadRequest, "Exactly one of query, group_id or user_ids is required")
+	errBulkRoleTooManyUsers  = models.NewAppError(http.StatusBadRequest, fmt.Sprintf("At most %d users can be processed per job", maxBulkRoleJobUsers))
+	errBulkRoleAppOnAssign   = models.NewAppError(http.StatusBadRequest, "application_id can only be used to assign a role")
+	errBulkRoleRoleNotFound  = models.NewAppError(http.StatusNotFound, "Role not found")
+	errBulkRoleGroupNotFound = models.NewAppError(http.StatusNotFound, "Group not found")
+	errBulkRoleJobNotFound   = models.NewAppError(http.StatusNotFound, "Bulk role job not found")
+)
+
+// BulkRoleJobService assigns a role to, or removes it from, many users in the background and
+// records the progress and per-user failures of each job
+type BulkRoleJobService struct {
+	store     BulkRoleJobStore
+	userRepo  UserStore
+	rbacRepo  RBACStore
+	groupRepo GroupRepository
+	logger    *logger.Logger
+	wg        sync.WaitGroup
+}
+
+// NewBulkRoleJobService creates a new bulk role job service
+func NewBulkRoleJobService(store BulkRoleJobStore, userRepo UserStore, rbacRepo RBACStore, groupRepo GroupRepository, log *logger.Logger) *BulkRoleJobService {
+	return &BulkRoleJobService{
+		store:     store,
+		userRepo:  userRepo,
+		rbacRepo:  rbacRepo,
+		groupRepo: groupRepo,
+		logger:    log,
+	}
+}
+
+// StartJob validates the request, stores a pending job and processes it in the background.
+// The returned job can be polled with GetJob.
+func (s *BulkRoleJobService) StartJob(ctx context.Context, req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	selectors := 0
+	if req.Query != "" {
+		selectors++
+	}
+	if req.GroupID != nil {
+		selectors++
+	}
+	if len(req.UserIDs) > 0 {
+		selectors++
+	}
+	if selectors != 1 {
+		return nil, errBulkRoleSelector
+	}
+	if len(req.UserIDs) > maxBulkRoleJobUsers {
+		return nil, errBulkRoleTooManyUsers
+	}
+	if req.ApplicationID != nil && req.Operation != models.BulkRoleOperationAssign {
+		return nil, errBulkRoleAppOnAssign
+	}
+
+	if _, err := s.rbacRepo.GetRoleByID(ctx, req.RoleID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errBulkRoleRoleNotFound
+		}
+		return nil, err
+	}
+	if req.GroupID != nil {
+		if _, err := s.groupRepo.GetByID(ctx, *req.GroupID); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return nil, errBulkRoleGroupNotFound
+			}
+			return nil, err
+		}
+	}
+
+	job := &models.BulkRoleJob{
+		ID:            uuid.New(),
+		Operation:     req.Operation,
+		RoleID:        req.RoleID,
+		ApplicationID: req.ApplicationID,
+		Query:         req.Query,
+		GroupID:       req.GroupID,
+		UserIDs:       uniqueUUIDs(req.UserIDs),
+		Status:        models.BulkRoleJobPending,
+		Failures:      []models.BulkRoleJobFailure{},
+		CreatedBy:     createdBy,
+	}
+	if err := s.store.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("bulk role job started", map[string]interface{}{
+		"job_id":     job.ID.String(),
+		"operation":  job.Operation,
+		"role_id":    job.RoleID.String(),
+		"created_by": createdBy.String(),
+	})
+
+	run := *job
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runJob(context.Background(), &run)
+	}()
+
+	return job, nil
+}
+
+// GetJob returns a job with its progress and failures
+func (s *BulkRoleJobService) GetJob(ctx context.Context, id uuid.UUID) (*models.BulkRoleJob, error) {
+	job, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errBulkRoleJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListJobs returns the most recent jobs without their user lists and failures
+func (s *BulkRoleJobService) ListJobs(ctx context.Context) (*models.BulkRoleJobListResponse, error) {
+	jobs, err := s.store.List(ctx, bulkRoleJobListLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BulkRoleJobListResponse{Jobs: jobs, Total: len(jobs)}, nil
+}
+
+// runJob selects the users of a job and applies the operation to each of them, saving
+// progress every bulkRoleJobProgressEvery users
+func (s *BulkRoleJobService) runJob(ctx context.Context, job *models.BulkRoleJob) {
+	users, err := s.selectUsers(ctx, job)
+	if err != nil {
+		s.finishJob(ctx, job, err)
+		return
+	}
+
+	job.Status = models.BulkRoleJobRunning
+	job.Total = job.Processed + len(users)
+	s.saveJob(ctx, job)
+
+	for i, user := range users {
+		if err := s.applyOperation(ctx, job, user.ID); err != nil {
+			recordBulkRoleFailure(job, user.ID, user.Email, err.Error())
+		} else {
+			job.Succeeded++
+			job.Processed++
+		}
+
+		if (i+1)%bulkRoleJobProgressEvery == 0 && job.Processed < job.Total {
+			s.saveJob(ctx, job)
+		}
+	}
+
+	s.finishJob(ctx, job, nil)
+}
+
+// selectUsers resolves the users a job applies to. Users selected by ID that do not exist
+// are reported as failures instead of failing the job.
+func (s *BulkRoleJobService) selectUsers(ctx context.Context, job *models.BulkRoleJob) ([]*models.User, error) {
+	var users []*models.User
+
+	switch {
+	case len(job.UserIDs) > 0:
+		for _, id := range job.UserIDs {
+			user, err := s.userRepo.GetByID(ctx, id, nil)
+			if err != nil {
+				message := "failed to load user"
+				if errors.Is(err, models.ErrNotFound) {
+					message = "user not found"
+				}
+				recordBulkRoleFailure(job, id, "", message)
+				continue
+			}
+			users = append(users, user)
+		}
+
+	case job.GroupID != nil:
+		for page := 1; ; page++ {
+			members, total, err := s.groupRepo.GetGroupMembers(ctx, *job.GroupID, page, bulkRoleJobPageSize)
+			if err != nil {
+				return nil, err
+			}
+			if total > maxBulkRoleJobUsers {
+				return nil, errBulkRoleTooManyUsers
+			}
+			users = append(users, members...)
+			if len(members) == 0 || len(users) >= total {
+				break
+			}
+		}
+
+	default:
+		for offset := 0; ; offset += bulkRoleJobPageSize {
+			found, total, err := s.userRepo.Search(ctx, job.Query, bulkRoleJobPageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+			if total > maxBulkRoleJobUsers {
+				return nil, errBulkRoleTooManyUsers
+			}
+			users = append(users, found...)
+			if len(found) == 0 || len(users) >= total {
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
+func (s *BulkRoleJobService) applyOperation(ctx context.Context, job *models.BulkRoleJob, userID uuid.UUID) error {
+	switch {
+	case job.Operation == models.BulkRoleOperationRemove:
+		return s.rbacRepo.RemoveRoleFromUser(ctx, userID, job.RoleID)
+	case job.ApplicationID != nil:
+		return s.rbacRepo.AssignRoleToUserInApp(ctx, userID, job.RoleID, job.CreatedBy, job.ApplicationID)
+	default:
+		return s.rbacRepo.AssignRoleToUser(ctx, userID, job.RoleID, job.CreatedBy)
+	}
+}
+
+func (s *BulkRoleJobService) finishJob(ctx context.Context, job *models.BulkRoleJob, err error) {
+	now := time.Now()
+	job.CompletedAt = &now
+	if err != nil {
+		job.Status = models.BulkRoleJobFailed
+		job.Error = err.Error()
+		s.logger.Error("bulk role job failed", map[string]interface{}{
+			"job_id": job.ID.String(),
+			"error":  err.Error(),
+		})
+	} else {
+		job.Status = models.BulkRoleJobCompleted
+		s.logger.Info("bulk role job completed", map[string]interface{}{
+			"job_id":    job.ID.String(),
+			"total":     job.Total,
+			"succeeded": job.Succeeded,
+			"failed":    job.Failed,
+		})
+	}
+	s.saveJob(ctx, job)
+}
+
+func (s *BulkRoleJobService) saveJob(ctx context.Context, job *models.BulkRoleJob) {
+	job.UpdatedAt = time.Now()
+	if err := s.store.Update(ctx, job); err != nil {
+		s.logger.Error("failed to save bulk role job progress", map[string]interface{}{
+			"job_id": job.ID.String(),
+			"error":  err.Error(),
+		})
+	}
+}
+
+// recordBulkRoleFailure counts a user as processed and failed, keeping the first
+// maxBulkRoleJobFailures failures
+func recordBulkRoleFailure(job *models.BulkRoleJob, userID uuid.UUID, email, message string) {
+	job.Processed++
+	job.Failed++
+	if len(job.Failures) < maxBulkRoleJobFailures {
+		job.Failures = append(job.Failures, models.BulkRoleJobFailure{
+			UserID: userID,
+			Email:  email,
+			Error:  message,
+		})
+	}
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if len(ids) == 0 {
+		return nil
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
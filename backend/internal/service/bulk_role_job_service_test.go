@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBulkRoleJobStore struct {
+	mu      sync.Mutex
+	jobs    map[uuid.UUID]models.BulkRoleJob
+	updates int
+}
+
+func (m *mockBulkRoleJobStore) Create(ctx context.Context, job *models.BulkRoleJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *mockBulkRoleJobStore) Update(ctx context.Context, job *models.BulkRoleJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates++
+	stored := *job
+	stored.Failures = append([]models.BulkRoleJobFailure(nil), job.Failures...)
+	m.jobs[job.ID] = stored
+	return nil
+}
+
+func (m *mockBulkRoleJobStore) GetByID(ctx context.Context, id uuid.UUID) (*models.BulkRoleJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (m *mockBulkRoleJobStore) List(ctx context.Context, limit int) ([]*models.BulkRoleJob, error) {
+	return nil, nil
+}
+
+func setupBulkRoleJobService() (*BulkRoleJobService, *mockBulkRoleJobStore, *mockUserStore, *mockRBACStore, *mockGroupStore) {
+	store := &mockBulkRoleJobStore{jobs: make(map[uuid.UUID]models.BulkRoleJob)}
+	mUser := &mockUserStore{}
+	mRBAC := &mockRBACStore{
+		GetRoleByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "support"}, nil
+		},
+	}
+	mGroup := newMockGroupStore()
+	svc := NewBulkRoleJobService(store, mUser, mRBAC, mGroup, logger.New("test", logger.DebugLevel, false))
+	return svc, store, mUser, mRBAC, mGroup
+}
+
+func TestBulkRoleJobService_StartJob_ShouldValidateRequest(t *testing.T) {
+	groupID := uuid.New()
+	appID := uuid.New()
+
+	tests := []struct {
+		name string
+		req  models.BulkRoleJobRequest
+		code int
+	}{
+		{"no selector", models.BulkRoleJobRequest{Operation: models.BulkRoleOperationAssign}, http.StatusBadRequest},
+		{"two selectors", models.BulkRoleJobRequest{Operation: models.BulkRoleOperationAssign, Query: "acme", GroupID: &groupID}, http.StatusBadRequest},
+		{"too many users", models.BulkRoleJobRequest{Operation: models.BulkRoleOperationAssign, UserIDs: make([]uuid.UUID, maxBulkRoleJobUsers+1)}, http.StatusBadRequest},
+		{"application on remove", models.BulkRoleJobRequest{Operation: models.BulkRoleOperationRemove, Query: "acme", ApplicationID: &appID}, http.StatusBadRequest},
+		{"unknown group", models.BulkRoleJobRequest{Operation: models.BulkRoleOperationAssign, GroupID: &groupID}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, _, _, _ := setupBulkRoleJobService()
+
+			_, err := svc.StartJob(context.Background(), &tt.req, uuid.New())
+
+			var appErr *models.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Empty(t, store.jobs)
+		})
+	}
+}
+
+func TestBulkRoleJobService_StartJob_ShouldReturnNotFound_WhenRoleMissing(t *testing.T) {
+	svc, _, _, mRBAC, _ := setupBulkRoleJobService()
+	mRBAC.GetRoleByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+		return nil, models.ErrNotFound
+	}
+
+	_, err := svc.StartJob(context.Background(), &models.BulkRoleJobRequest{
+		Operation: models.BulkRoleOperationAssign,
+		RoleID:    uuid.New(),
+		Query:     "acme",
+	}, uuid.New())
+
+	assert.Equal(t, errBulkRoleRoleNotFound, err)
+}
+
+func TestBulkRoleJobService_ShouldAssignRoleToUserIDsAndReportFailures(t *testing.T) {
+	svc, _, mUser, mRBAC, _ := setupBulkRoleJobService()
+	adminID := uuid.New()
+	ok1, ok2, missing, broken := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if id == missing {
+			return nil, models.ErrNotFound
+		}
+		return &models.User{ID: id, Email: id.String() + "@example.com"}, nil
+	}
+	var mu sync.Mutex
+	assigned := make([]uuid.UUID, 0)
+	mRBAC.AssignRoleToUserFunc = func(ctx context.Context, userID, roleID, assignedBy uuid.UUID) error {
+		assert.Equal(t, adminID, assignedBy)
+		if userID == broken {
+			return errors.New("db error")
+		}
+		mu.Lock()
+		assigned = append(assigned, userID)
+		mu.Unlock()
+		return nil
+	}
+
+	job, err := svc.StartJob(context.Background(), &models.BulkRoleJobRequest{
+		Operation: models.BulkRoleOperationAssign,
+		RoleID:    uuid.New(),
+		UserIDs:   []uuid.UUID{ok1, missing, ok2, broken, ok1},
+	}, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkRoleJobPending, job.Status)
+	svc.wg.Wait()
+
+	done, err := svc.GetJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkRoleJobCompleted, done.Status)
+	assert.Equal(t, 4, done.Total)
+	assert.Equal(t, 4, done.Processed)
+	assert.Equal(t, 2, done.Succeeded)
+	assert.Equal(t, 2, done.Failed)
+	require.Len(t, done.Failures, 2)
+	assert.Equal(t, models.BulkRoleJobFailure{UserID: missing, Error: "user not found"}, done.Failures[0])
+	assert.Equal(t, broken, done.Failures[1].UserID)
+	assert.Equal(t, "db error", done.Failures[1].Error)
+	assert.NotNil(t, done.CompletedAt)
+	assert.ElementsMatch(t, []uuid.UUID{ok1, ok2}, assigned)
+}
+
+func TestBulkRoleJobService_ShouldPageThroughSearchResults(t *testing.T) {
+	svc, store, mUser, mRBAC, _ := setupBulkRoleJobService()
+
+	users := make([]*models.User, bulkRoleJobPageSize+20)
+	for i := range users {
+		users[i] = &models.User{ID: uuid.New()}
+	}
+	mUser.SearchFunc = func(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+		assert.Equal(t, "@acme.com", query)
+		end := offset + limit
+		if end > len(users) {
+			end = len(users)
+		}
+		return users[offset:end], len(users), nil
+	}
+	var removed int
+	mRBAC.RemoveRoleFromUserFunc = func(ctx context.Context, userID, roleID uuid.UUID) error {
+		removed++
+		return nil
+	}
+
+	job, err := svc.StartJob(context.Background(), &models.BulkRoleJobRequest{
+		Operation: models.BulkRoleOperationRemove,
+		RoleID:    uuid.New(),
+		Query:     " @acme.com ",
+	}, uuid.New())
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	done, err := svc.GetJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkRoleJobCompleted, done.Status)
+	assert.Equal(t, len(users), done.Total)
+	assert.Equal(t, len(users), done.Succeeded)
+	assert.Equal(t, len(users), removed)
+	assert.Greater(t, store.updates, 2, "progress should be saved while the job runs")
+}
+
+func TestBulkRoleJobService_ShouldAssignInApplicationToGroupMembers(t *testing.T) {
+	svc, _, _, mRBAC, mGroup := setupBulkRoleJobService()
+	group := &models.Group{Name: "engineering"}
+	require.NoError(t, mGroup.Create(context.Background(), group))
+	member := uuid.New()
+	mGroup.users[group.ID] = []uuid.UUID{member}
+	appID := uuid.New()
+
+	var gotApp *uuid.UUID
+	mRBAC.AssignRoleToUserInAppFunc = func(ctx context.Context, userID, roleID, assignedBy uuid.UUID, id *uuid.UUID) error {
+		assert.Equal(t, member, userID)
+		gotApp = id
+		return nil
+	}
+
+	job, err := svc.StartJob(context.Background(), &models.BulkRoleJobRequest{
+		Operation:     models.BulkRoleOperationAssign,
+		RoleID:        uuid.New(),
+		ApplicationID: &appID,
+		GroupID:       &group.ID,
+	}, uuid.New())
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	done, err := svc.GetJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, done.Succeeded)
+	assert.Equal(t, &appID, gotApp)
+}
+
+func TestBulkRoleJobService_ShouldFailJob_WhenSelectionFails(t *testing.T) {
+	svc, _, mUser, _, _ := setupBulkRoleJobService()
+	mUser.SearchFunc = func(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+		return nil, 0, errors.New("db error")
+	}
+
+	job, err := svc.StartJob(context.Background(), &models.BulkRoleJobRequest{
+		Operation: models.BulkRoleOperationAssign,
+		RoleID:    uuid.New(),
+		Query:     "acme",
+	}, uuid.New())
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	done, err := svc.GetJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BulkRoleJobFailed, done.Status)
+	assert.Equal(t, "db error", done.Error)
+}
+
+func TestBulkRoleJobService_GetJob_ShouldReturnNotFound(t *testing.T) {
+	svc, _, _, _, _ := setupBulkRoleJobService()
+
+	_, err := svc.GetJob(context.Background(), uuid.New())
+
+	assert.Equal(t, errBulkRoleJobNotFound, err)
+}
@@ -142,7 +142,7 @@ func (s *BulkService) BulkUpdateUsers(ctx context.Context, req *models.BulkUpdat
 			user.FullName = utils.SanitizeHTML(*userReq.FullName)
 		}
 		if userReq.IsActive != nil {
-			user.IsActive = *userReq.IsActive
+			user.SetActive(*userReq.IsActive)
 		}
 
 		if err := s.userRepo.Update(ctx, user); err != nil {
@@ -185,7 +185,7 @@ func (s *BulkService) BulkDeleteUsers(ctx context.Context, req *models.BulkDelet
 			email = user.Email
 		}
 
-		// Delete user (soft delete) - get user and set is_active = false
+		// Delete user (soft delete) - get user and move it to the deleted state
 		user, err := s.userRepo.GetByID(ctx, userID, nil)
 		if err != nil {
 			result.Failed++
@@ -197,7 +197,7 @@ func (s *BulkService) BulkDeleteUsers(ctx context.Context, req *models.BulkDelet
 			})
 			continue
 		}
-		user.IsActive = false
+		user.SetState(models.UserStateDeleted)
 		if err := s.userRepo.Update(ctx, user); err != nil {
 			result.Failed++
 			result.Errors = append(result.Errors, models.BulkOperationError{
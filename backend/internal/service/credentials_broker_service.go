@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/credentials"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// credentialsCleanupBatch caps how many expired Postgres roles are dropped per run
+const credentialsCleanupBatch = 200
+
+var (
+	errCredentialTargetNotFound = models.NewAppError(http.StatusNotFound, "Credential target not found")
+	errCredentialsForbidden     = models.NewAppError(http.StatusForbidden, "Missing permission for this credential target")
+)
+
+// CredentialsBrokerOptions contains the credential backends of CredentialsBrokerService.
+// A nil backend disables targets of its kind.
+type CredentialsBrokerOptions struct {
+	Postgres PostgresRoleProvisioner
+	SSH      SSHCertificateSigner
+}
+
+// CredentialsBrokerService exchanges a gateway identity for short-lived downstream credentials.
+// Each target names the RBAC permission a user needs. Postgres targets get a temporary login
+// role that is a member of the target's role and is dropped once it expires; SSH targets get
+// a certificate for the user's public key that expires on its own. Every issuance is recorded
+// and audited.
+type CredentialsBrokerService struct {
+	store        CredentialStore
+	permissions  PermissionChecker
+	postgres     PostgresRoleProvisioner
+	ssh          SSHCertificateSigner
+	auditService AuditLogger
+	defaultTTL   time.Duration
+	maxTTL       time.Duration
+	logger       *logger.Logger
+	now          func() time.Time
+}
+
+// NewCredentialsBrokerService creates a new credentials broker service.
+// defaultTTL applies when a request names no lifetime; maxTTL caps every target.
+func NewCredentialsBrokerService(
+	store CredentialStore,
+	permissions PermissionChecker,
+	auditService AuditLogger,
+	defaultTTL, maxTTL time.Duration,
+	logger *logger.Logger,
+	opts CredentialsBrokerOptions,
+) *CredentialsBrokerService {
+	return &CredentialsBrokerService{
+		store:        store,
+		permissions:  permissions,
+		postgres:     opts.Postgres,
+		ssh:          opts.SSH,
+		auditService: auditService,
+		defaultTTL:   defaultTTL,
+		maxTTL:       maxTTL,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// CreateTarget creates a credential target
+func (s *CredentialsBrokerService) CreateTarget(ctx context.Context, req *models.CreateCredentialTargetRequest) (*models.CredentialTarget, error) {
+	target := &models.CredentialTarget{
+		Name:          strings.TrimSpace(req.Name),
+		Kind:          req.Kind,
+		Description:   req.Description,
+		Permission:    req.Permission,
+		PostgresRole:  req.PostgresRole,
+		SSHPrincipals: req.SSHPrincipals,
+		MaxTTLSeconds: req.MaxTTLSeconds,
+		IsActive:      true,
+	}
+	if err := s.validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateTarget(ctx, target); err != nil {
+		if errors.Is(err, models.ErrAlreadyExists) {
+			return nil, models.NewAppError(http.StatusConflict, "Credential target with this name already exists")
+		}
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// UpdateTarget updates a credential target. Its name and kind cannot change.
+func (s *CredentialsBrokerService) UpdateTarget(ctx context.Context, id uuid.UUID, req *models.UpdateCredentialTargetRequest) (*models.CredentialTarget, error) {
+	target, err := s.getTarget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		target.Description = *req.Description
+	}
+	if req.Permission != nil {
+		target.Permission = *req.Permission
+	}
+	if req.PostgresRole != nil {
+		target.PostgresRole = *req.PostgresRole
+	}
+	if req.SSHPrincipals != nil {
+		target.SSHPrincipals = req.SSHPrincipals
+	}
+	if req.MaxTTLSeconds != nil {
+		target.MaxTTLSeconds = *req.MaxTTLSeconds
+	}
+	if req.IsActive != nil {
+		target.IsActive = *req.IsActive
+	}
+	if err := s.validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateTarget(ctx, target); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errCredentialTargetNotFound
+		}
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// DeleteTarget deletes a credential target and returns it. Issued credentials stay valid
+// until they expire.
+func (s *CredentialsBrokerService) DeleteTarget(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error) {
+	target, err := s.getTarget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteTarget(ctx, id); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errCredentialTargetNotFound
+		}
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// ListTargets lists all credential targets
+func (s *CredentialsBrokerService) ListTargets(ctx context.Context) ([]*models.CredentialTarget, error) {
+	return s.store.ListTargets(ctx)
+}
+
+// ListAvailableTargets lists the active targets the user may request credentials for
+func (s *CredentialsBrokerService) ListAvailableTargets(ctx context.Context, userID uuid.UUID) ([]*models.CredentialTarget, error) {
+	targets, err := s.store.ListTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool)
+	available := make([]*models.CredentialTarget, 0, len(targets))
+	for _, target := range targets {
+		if !target.IsActive {
+			continue
+		}
+		allowed, checked := granted[target.Permission]
+		if !checked {
+			allowed, err = s.permissions.HasPermission(ctx, userID, target.Permission)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check permission: %w", err)
+			}
+			granted[target.Permission] = allowed
+		}
+		if allowed {
+			available = append(available, target)
+		}
+	}
+
+	return available, nil
+}
+
+// Issue creates credentials for the named target if the user holds its permission.
+// Both issued and refused requests are audited.
+func (s *CredentialsBrokerService) Issue(ctx context.Context, userID uuid.UUID, targetName string, req *models.IssueCredentialsRequest, ip, userAgent string) (*models.IssuedCredentials, error) {
+	target, err := s.store.GetTargetByName(ctx, targetName)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errCredentialTargetNotFound
+		}
+		return nil, err
+	}
+	if !target.IsActive {
+		return nil, errCredentialTargetNotFound
+	}
+
+	allowed, err := s.permissions.HasPermission(ctx, userID, target.Permission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !allowed {
+		s.auditIssue(userID, target, models.StatusFailed, ip, userAgent, map[string]interface{}{
+			"reason": "permission_denied",
+		})
+		return nil, errCredentialsForbidden
+	}
+
+	ttl := s.ttlFor(target, req.TTLSeconds)
+	now := s.now()
+	issuance := &models.CredentialIssuance{
+		ID:         uuid.New(),
+		TargetID:   &target.ID,
+		TargetName: target.Name,
+		UserID:     userID,
+		Kind:       target.Kind,
+		IPAddress:  ip,
+		ExpiresAt:  now.Add(ttl),
+	}
+	issued := &models.IssuedCredentials{
+		IssuanceID: issuance.ID,
+		Target:     target.Name,
+		Kind:       target.Kind,
+		ExpiresAt:  issuance.ExpiresAt,
+	}
+
+	switch target.Kind {
+	case models.CredentialKindPostgres:
+		issued.Postgres, err = s.issuePostgres(ctx, target, issuance)
+	case models.CredentialKindSSH:
+		issued.SSH, err = s.issueSSH(target, issuance, req.PublicKey)
+	default:
+		err = fmt.Errorf("unknown credential kind %q", target.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateIssuance(ctx, issuance); err != nil {
+		// Credentials that are not on record would never be cleaned up
+		if issued.Postgres != nil {
+			if dropErr := s.postgres.DropRole(ctx, issuance.Username); dropErr != nil {
+				s.logger.Error("Failed to drop unrecorded credentials role", map[string]interface{}{
+					"username": issuance.Username,
+					"error":    dropErr.Error(),
+				})
+			}
+		}
+		return nil, err
+	}
+
+	s.auditIssue(userID, target, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"issuance_id": issuance.ID.String(),
+		"username":    issuance.Username,
+		"serial":      issuance.Serial,
+		"ttl_seconds": int(ttl / time.Second),
+		"expires_at":  issuance.ExpiresAt,
+	})
+
+	return issued, nil
+}
+
+// ListIssuances lists issued credentials newest first, optionally only those of a user
+func (s *CredentialsBrokerService) ListIssuances(ctx context.Context, userID *uuid.UUID, page, pageSize int) (*models.CredentialIssuanceListResponse, error) {
+	issuances, total, err := s.store.ListIssuances(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CredentialIssuanceListResponse{
+		Issuances:  issuances,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// Revoke drops the Postgres role of an issuance before it expires. Revoking an issuance
+// again returns it unchanged. SSH certificates cannot be revoked.
+func (s *CredentialsBrokerService) Revoke(ctx context.Context, issuanceID uuid.UUID) (*models.CredentialIssuance, error) {
+	issuance, err := s.store.GetIssuanceByID(ctx, issuanceID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.NewAppError(http.StatusNotFound, "Credential issuance not found")
+		}
+		return nil, err
+	}
+	if issuance.Kind != models.CredentialKindPostgres {
+		return nil, models.NewAppError(http.StatusConflict, "SSH certificates cannot be revoked; they expire on their own")
+	}
+	if issuance.RevokedAt != nil {
+		return issuance, nil
+	}
+	if s.postgres == nil {
+		return nil, models.NewAppError(http.StatusServiceUnavailable, "Postgres credentials are not configured")
+	}
+
+	if err := s.dropRole(ctx, issuance); err != nil {
+		return nil, err
+	}
+
+	return issuance, nil
+}
+
+// DropExpired drops the Postgres roles of expired issuances and returns how many were
+// dropped. Roles that cannot be dropped are retried on the next run.
+func (s *CredentialsBrokerService) DropExpired(ctx context.Context) (int, error) {
+	if s.postgres == nil {
+		return 0, nil
+	}
+
+	expired, err := s.store.ListExpiredRoles(ctx, s.now(), credentialsCleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	dropped := 0
+	for _, issuance := range expired {
+		if err := s.dropRole(ctx, issuance); err != nil {
+			s.logger.Warn("Failed to drop expired credentials role", map[string]interface{}{
+				"issuance_id": issuance.ID.String(),
+				"username":    issuance.Username,
+				"error":       err.Error(),
+			})
+			continue
+		}
+		dropped++
+	}
+
+	return dropped, nil
+}
+
+func (s *CredentialsBrokerService) issuePostgres(ctx context.Context, target *models.CredentialTarget, issuance *models.CredentialIssuance) (*models.PostgresCredentials, error) {
+	if s.postgres == nil {
+		return nil, models.NewAppError(http.StatusServiceUnavailable, "Postgres credentials are not configured")
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return nil, err
+	}
+	password, err := randomPassword()
+	if err != nil {
+		return nil, err
+	}
+	// Role names show up in pg_stat_activity and server logs; tie them to the user
+	issuance.Username = "gw_" + strings.ReplaceAll(issuance.UserID.String(), "-", "")[:8] + "_" + suffix
+
+	if err := s.postgres.CreateRole(ctx, issuance.Username, password, target.PostgresRole, issuance.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to create credentials role: %w", err)
+	}
+
+	return &models.PostgresCredentials{Username: issuance.Username, Password: password}, nil
+}
+
+func (s *CredentialsBrokerService) issueSSH(target *models.CredentialTarget, issuance *models.CredentialIssuance, publicKey string) (*models.SSHCredentials, error) {
+	if s.ssh == nil {
+		return nil, models.NewAppError(http.StatusServiceUnavailable, "SSH credentials are not configured")
+	}
+	if strings.TrimSpace(publicKey) == "" {
+		return nil, models.NewAppError(http.StatusBadRequest, "public_key is required for SSH targets")
+	}
+
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+	// Serials are stored as BIGINT, so keep them positive
+	issuance.Serial = int64(binary.BigEndian.Uint64(buf[:]) >> 1)
+	issuance.Username = issuance.UserID.String() + "/" + target.Name
+
+	cert, err := s.ssh.SignUserKey(publicKey, issuance.Username, target.SSHPrincipals, uint64(issuance.Serial), issuance.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, credentials.ErrInvalidPublicKey) {
+			return nil, models.NewAppError(http.StatusBadRequest, "Invalid SSH public key")
+		}
+		return nil, err
+	}
+
+	return &models.SSHCredentials{
+		Certificate: cert,
+		Principals:  target.SSHPrincipals,
+		Serial:      issuance.Serial,
+	}, nil
+}
+
+func (s *CredentialsBrokerService) dropRole(ctx context.Context, issuance *models.CredentialIssuance) error {
+	if err := s.postgres.DropRole(ctx, issuance.Username); err != nil {
+		return err
+	}
+
+	revokedAt := s.now()
+	if err := s.store.MarkRevoked(ctx, issuance.ID, revokedAt); err != nil && !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	issuance.RevokedAt = &revokedAt
+	return nil
+}
+
+// ttlFor returns the requested lifetime capped by the target and the broker
+func (s *CredentialsBrokerService) ttlFor(target *models.CredentialTarget, requestedSeconds int) time.Duration {
+	ttl := s.defaultTTL
+	if requestedSeconds > 0 {
+		ttl = time.Duration(requestedSeconds) * time.Second
+	}
+
+	limit := s.maxTTL
+	if target.MaxTTLSeconds > 0 {
+		if targetMax := time.Duration(target.MaxTTLSeconds) * time.Second; targetMax < limit {
+			limit = targetMax
+		}
+	}
+	if ttl > limit {
+		ttl = limit
+	}
+	return ttl
+}
+
+func (s *CredentialsBrokerService) validateTarget(target *models.CredentialTarget) error {
+	switch target.Kind {
+	case models.CredentialKindPostgres:
+		if target.PostgresRole == "" {
+			return models.NewAppError(http.StatusBadRequest, "postgres_role is required for postgres targets")
+		}
+		if s.postgres == nil {
+			return models.NewAppError(http.StatusBadRequest, "Postgres credentials are not configured", "set CREDENTIALS_POSTGRES_DSN")
+		}
+	case models.CredentialKindSSH:
+		if len(target.SSHPrincipals) == 0 {
+			return models.NewAppError(http.StatusBadRequest, "ssh_principals is required for ssh targets")
+		}
+		if s.ssh == nil {
+			return models.NewAppError(http.StatusBadRequest, "SSH credentials are not configured", "set CREDENTIALS_SSH_CA_KEY")
+		}
+	default:
+		return models.NewAppError(http.StatusBadRequest, "kind must be postgres or ssh")
+	}
+	return nil
+}
+
+func (s *CredentialsBrokerService) getTarget(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error) {
+	target, err := s.store.GetTargetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errCredentialTargetNotFound
+		}
+		return nil, err
+	}
+	return target, nil
+}
+
+func (s *CredentialsBrokerService) auditIssue(userID uuid.UUID, target *models.CredentialTarget, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
+	details["target"] = target.Name
+	details["kind"] = target.Kind
+	s.auditService.Log(AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionCredentialsIssue,
+		Status:    status,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details:   details,
+	})
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/credentials"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCredentialStore struct {
+	targets     map[string]*models.CredentialTarget
+	issuances   []*models.CredentialIssuance
+	issuanceErr error
+}
+
+func newMockCredentialStore(targets ...*models.CredentialTarget) *mockCredentialStore {
+	m := &mockCredentialStore{targets: make(map[string]*models.CredentialTarget)}
+	for _, t := range targets {
+		if t.ID == uuid.Nil {
+			t.ID = uuid.New()
+		}
+		m.targets[t.Name] = t
+	}
+	return m
+}
+
+func (m *mockCredentialStore) CreateTarget(ctx context.Context, target *models.CredentialTarget) error {
+	if _, exists := m.targets[target.Name]; exists {
+		return models.ErrAlreadyExists
+	}
+	target.ID = uuid.New()
+	m.targets[target.Name] = target
+	return nil
+}
+
+func (m *mockCredentialStore) GetTargetByID(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error) {
+	for _, t := range m.targets {
+		if t.ID == id {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockCredentialStore) GetTargetByName(ctx context.Context, name string) (*models.CredentialTarget, error) {
+	t, ok := m.targets[name]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *mockCredentialStore) ListTargets(ctx context.Context) ([]*models.CredentialTarget, error) {
+	targets := make([]*models.CredentialTarget, 0, len(m.targets))
+	for _, t := range m.targets {
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+func (m *mockCredentialStore) UpdateTarget(ctx context.Context, target *models.CredentialTarget) error {
+	m.targets[target.Name] = target
+	return nil
+}
+
+func (m *mockCredentialStore) DeleteTarget(ctx context.Context, id uuid.UUID) error {
+	for name, t := range m.targets {
+		if t.ID == id {
+			delete(m.targets, name)
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func (m *mockCredentialStore) CreateIssuance(ctx context.Context, issuance *models.CredentialIssuance) error {
+	if m.issuanceErr != nil {
+		return m.issuanceErr
+	}
+	m.issuances = append(m.issuances, issuance)
+	return nil
+}
+
+func (m *mockCredentialStore) GetIssuanceByID(ctx context.Context, id uuid.UUID) (*models.CredentialIssuance, error) {
+	for _, i := range m.issuances {
+		if i.ID == id {
+			return i, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockCredentialStore) ListIssuances(ctx context.Context, userID *uuid.UUID, page, pageSize int) ([]*models.CredentialIssuance, int, error) {
+	return m.issuances, len(m.issuances), nil
+}
+
+func (m *mockCredentialStore) ListExpiredRoles(ctx context.Context, before time.Time, limit int) ([]*models.CredentialIssuance, error) {
+	var expired []*models.CredentialIssuance
+	for _, i := range m.issuances {
+		if i.Kind == models.CredentialKindPostgres && i.RevokedAt == nil && i.ExpiresAt.Before(before) {
+			expired = append(expired, i)
+		}
+	}
+	return expired, nil
+}
+
+func (m *mockCredentialStore) MarkRevoked(ctx context.Context, id uuid.UUID, at time.Time) error {
+	for _, i := range m.issuances {
+		if i.ID == id {
+			i.RevokedAt = &at
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+type mockRoleProvisioner struct {
+	roles   map[string]string // username -> granted role
+	dropErr error
+}
+
+func (m *mockRoleProvisioner) CreateRole(ctx context.Context, username, password, grantRole string, validUntil time.Time) error {
+	m.roles[username] = grantRole
+	return nil
+}
+
+func (m *mockRoleProvisioner) DropRole(ctx context.Context, username string) error {
+	if m.dropErr != nil {
+		return m.dropErr
+	}
+	delete(m.roles, username)
+	return nil
+}
+
+type mockCertSigner struct {
+	keyID       string
+	principals  []string
+	validBefore time.Time
+}
+
+func (m *mockCertSigner) SignUserKey(publicKey, keyID string, principals []string, serial uint64, validBefore time.Time) (string, error) {
+	if !strings.HasPrefix(publicKey, "ssh-") {
+		return "", credentials.ErrInvalidPublicKey
+	}
+	m.keyID, m.principals, m.validBefore = keyID, principals, validBefore
+	return "ssh-ed25519-cert-v01@openssh.com AAAA", nil
+}
+
+type credentialsBrokerFixture struct {
+	service  *CredentialsBrokerService
+	store    *mockCredentialStore
+	roles    *mockRoleProvisioner
+	signer   *mockCertSigner
+	audited  []AuditLogParams
+	granted  map[string]bool
+	now      time.Time
+	userID   uuid.UUID
+	database *models.CredentialTarget
+	bastion  *models.CredentialTarget
+}
+
+func newCredentialsBrokerFixture() *credentialsBrokerFixture {
+	f := &credentialsBrokerFixture{
+		roles:   &mockRoleProvisioner{roles: make(map[string]string)},
+		signer:  &mockCertSigner{},
+		granted: map[string]bool{"analytics-db:connect": true, "bastion:ssh": true},
+		now:     time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC),
+		userID:  uuid.MustParse("4f1c2a9e-0000-4000-8000-000000000001"),
+		database: &models.CredentialTarget{
+			Name: "analytics-db", Kind: models.CredentialKindPostgres, Permission: "analytics-db:connect",
+			PostgresRole: "analytics_readonly", MaxTTLSeconds: 1800, IsActive: true,
+		},
+		bastion: &models.CredentialTarget{
+			Name: "bastion", Kind: models.CredentialKindSSH, Permission: "bastion:ssh",
+			SSHPrincipals: []string{"deploy"}, IsActive: true,
+		},
+	}
+	f.store = newMockCredentialStore(f.database, f.bastion)
+	rbac := &mockRBACStore{
+		HasPermissionFunc: func(ctx context.Context, userID uuid.UUID, permissionName string) (bool, error) {
+			return f.granted[permissionName], nil
+		},
+	}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.audited = append(f.audited, params) }}
+	f.service = NewCredentialsBrokerService(f.store, rbac, audit, time.Hour, 12*time.Hour, logger.New("test", logger.InfoLevel, false),
+		CredentialsBrokerOptions{Postgres: f.roles, SSH: f.signer})
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestCredentialsBrokerService_Issue_ShouldCreatePostgresRole(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+
+	issued, err := f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{}, "203.0.113.7", "psql")
+
+	require.NoError(t, err)
+	require.NotNil(t, issued.Postgres)
+	assert.True(t, strings.HasPrefix(issued.Postgres.Username, "gw_4f1c2a9e_"))
+	assert.NotEmpty(t, issued.Postgres.Password)
+	assert.Equal(t, "analytics_readonly", f.roles.roles[issued.Postgres.Username])
+	// The default of 1h is capped by the target's 30 minutes
+	assert.Equal(t, f.now.Add(30*time.Minute), issued.ExpiresAt)
+
+	require.Len(t, f.store.issuances, 1)
+	assert.Equal(t, issued.IssuanceID, f.store.issuances[0].ID)
+	assert.Equal(t, "203.0.113.7", f.store.issuances[0].IPAddress)
+
+	require.Len(t, f.audited, 1)
+	assert.Equal(t, models.ActionCredentialsIssue, f.audited[0].Action)
+	assert.Equal(t, models.StatusSuccess, f.audited[0].Status)
+	assert.NotContains(t, f.audited[0].Details, "password")
+}
+
+func TestCredentialsBrokerService_Issue_ShouldSignSSHCertificate(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	req := &models.IssueCredentialsRequest{TTLSeconds: 900, PublicKey: "ssh-ed25519 AAAA user@laptop"}
+
+	issued, err := f.service.Issue(context.Background(), f.userID, "bastion", req, "203.0.113.7", "ssh")
+
+	require.NoError(t, err)
+	require.NotNil(t, issued.SSH)
+	assert.Equal(t, []string{"deploy"}, f.signer.principals)
+	assert.Equal(t, f.userID.String()+"/bastion", f.signer.keyID)
+	assert.Equal(t, f.now.Add(15*time.Minute), f.signer.validBefore)
+	assert.Positive(t, issued.SSH.Serial)
+	assert.Equal(t, issued.SSH.Serial, f.store.issuances[0].Serial)
+}
+
+func TestCredentialsBrokerService_Issue_ShouldCapTTLAtBrokerMaximum(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	req := &models.IssueCredentialsRequest{TTLSeconds: 48 * 3600, PublicKey: "ssh-ed25519 AAAA"}
+
+	issued, err := f.service.Issue(context.Background(), f.userID, "bastion", req, "", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, f.now.Add(12*time.Hour), issued.ExpiresAt)
+}
+
+func TestCredentialsBrokerService_Issue_ShouldReturn403AndAudit_WhenPermissionMissing(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	f.granted["analytics-db:connect"] = false
+
+	_, err := f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{}, "203.0.113.7", "psql")
+
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusForbidden, appErr.Code)
+	assert.Empty(t, f.roles.roles)
+	assert.Empty(t, f.store.issuances)
+	require.Len(t, f.audited, 1)
+	assert.Equal(t, models.StatusFailed, f.audited[0].Status)
+	assert.Equal(t, "permission_denied", f.audited[0].Details["reason"])
+}
+
+func TestCredentialsBrokerService_Issue_ShouldReturn404_WhenTargetInactiveOrUnknown(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	f.database.IsActive = false
+
+	for _, name := range []string{"analytics-db", "missing"} {
+		_, err := f.service.Issue(context.Background(), f.userID, name, &models.IssueCredentialsRequest{}, "", "")
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+	}
+	assert.Empty(t, f.audited)
+}
+
+func TestCredentialsBrokerService_Issue_ShouldReturn400_WhenPublicKeyInvalid(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+
+	for _, key := range []string{"", "not a key"} {
+		_, err := f.service.Issue(context.Background(), f.userID, "bastion", &models.IssueCredentialsRequest{PublicKey: key}, "", "")
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	}
+	assert.Empty(t, f.store.issuances)
+}
+
+func TestCredentialsBrokerService_Issue_ShouldDropRole_WhenRecordingFails(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	f.store.issuanceErr = errors.New("db down")
+
+	_, err := f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{}, "", "")
+
+	require.Error(t, err)
+	assert.Empty(t, f.roles.roles)
+	assert.Empty(t, f.audited)
+}
+
+func TestCredentialsBrokerService_ListAvailableTargets_ShouldFilterByPermission(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	f.granted["bastion:ssh"] = false
+
+	targets, err := f.service.ListAvailableTargets(context.Background(), f.userID)
+
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, "analytics-db", targets[0].Name)
+}
+
+func TestCredentialsBrokerService_DropExpired_ShouldDropOnlyExpiredRoles(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	_, err := f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{TTLSeconds: 60}, "", "")
+	require.NoError(t, err)
+	_, err = f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{TTLSeconds: 600}, "", "")
+	require.NoError(t, err)
+	f.now = f.now.Add(5 * time.Minute)
+
+	dropped, err := f.service.DropExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, f.roles.roles, 1)
+	assert.NotNil(t, f.store.issuances[0].RevokedAt)
+	assert.Nil(t, f.store.issuances[1].RevokedAt)
+}
+
+func TestCredentialsBrokerService_DropExpired_ShouldRetryLater_WhenDropFails(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	_, err := f.service.Issue(context.Background(), f.userID, "analytics-db", &models.IssueCredentialsRequest{TTLSeconds: 60}, "", "")
+	require.NoError(t, err)
+	f.now = f.now.Add(5 * time.Minute)
+	f.roles.dropErr = errors.New("role owns objects")
+
+	dropped, err := f.service.DropExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	assert.Nil(t, f.store.issuances[0].RevokedAt)
+}
+
+func TestCredentialsBrokerService_Revoke_ShouldRefuseSSHCertificates(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+	issued, err := f.service.Issue(context.Background(), f.userID, "bastion", &models.IssueCredentialsRequest{PublicKey: "ssh-ed25519 AAAA"}, "", "")
+	require.NoError(t, err)
+
+	_, err = f.service.Revoke(context.Background(), issued.IssuanceID)
+
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusConflict, appErr.Code)
+}
+
+func TestCredentialsBrokerService_CreateTarget_ShouldValidateKindFields(t *testing.T) {
+	f := newCredentialsBrokerFixture()
+
+	_, err := f.service.CreateTarget(context.Background(), &models.CreateCredentialTargetRequest{
+		Name: "reports-db", Kind: models.CredentialKindPostgres, Permission: "reports-db:connect",
+	})
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+
+	_, err = f.service.CreateTarget(context.Background(), &models.CreateCredentialTargetRequest{
+		Name: "bastion", Kind: models.CredentialKindSSH, Permission: "bastion:ssh", SSHPrincipals: []string{"ops"},
+	})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusConflict, appErr.Code)
+}
@@ -0,0 +1,478 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/smilemakc/auth-gateway/pkg/signedurl"
+)
+
+const (
+	dataExportJob = "user.data_export"
+	// dataExportMaxAttempts is how often generating an export is tried before it is failed
+	dataExportMaxAttempts = 3
+	// dataExportAuditPageSize is how many audit log entries are loaded per query
+	dataExportAuditPageSize = 1000
+	// dataExportMaxAuditEvents caps the audit events in one export
+	dataExportMaxAuditEvents = 100000
+	// dataExportScope marks download links signed by the export service. It is not a
+	// permission, so it cannot be requested from the signed URL endpoint.
+	dataExportScope = "user_data_export:download"
+)
+
+var (
+	errDataExportInvalidFormat = models.NewAppError(http.StatusBadRequest, "Invalid export format", "Supported formats: json, zip")
+	errDataExportNotFound      = models.NewAppError(http.StatusNotFound, "Export not found")
+	errDataExportNotReady      = models.NewAppError(http.StatusConflict, "Export is not ready")
+	errDataExportInvalidLink   = models.NewAppError(http.StatusUnauthorized, "Invalid or expired download link")
+)
+
+// dataExportJobPayload identifies the export a job generates
+type dataExportJobPayload struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+// DataExportFile is a generated export ready to be downloaded
+type DataExportFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// DataExportService exports everything stored about a user (GDPR data portability). Exports
+// are generated by a background job and kept until they expire; while one is kept, requesting
+// an export again returns it instead of generating another. A ready export is downloaded with
+// a short-lived signed link, so browsers can fetch it without a bearer token.
+type DataExportService struct {
+	store    DataExportStore
+	source   UserDataSource
+	users    UserStore
+	sessions SessionStore
+	jobs     *JobQueueService
+	signer   *signedurl.Signer
+	audit    AuditLogger
+	baseURL  string
+	ttl      time.Duration
+	linkTTL  time.Duration
+	logger   *logger.Logger
+	now      func() time.Time
+}
+
+// NewDataExportService creates a data export service. Download links are relative to baseURL,
+// or to the host the request was sent to when baseURL is empty. Exports are kept for ttl and
+// download links last linkTTL.
+func NewDataExportService(
+	store DataExportStore,
+	source UserDataSource,
+	users UserStore,
+	sessions SessionStore,
+	jobs *JobQueueService,
+	signer *signedurl.Signer,
+	audit AuditLogger,
+	baseURL string,
+	ttl, linkTTL time.Duration,
+	log *logger.Logger,
+) *DataExportService {
+	s := &DataExportService{
+		store:    store,
+		source:   source,
+		users:    users,
+		sessions: sessions,
+		jobs:     jobs,
+		signer:   signer,
+		audit:    audit,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		ttl:      ttl,
+		linkTTL:  linkTTL,
+		logger:   log,
+		now:      time.Now,
+	}
+	jobs.Register(dataExportJob, s.runExportJob)
+	return s
+}
+
+// RequestExport returns the user's current export in format, queueing a new one when there
+// is none or the last one failed
+func (s *DataExportService) RequestExport(ctx context.Context, userID uuid.UUID, format models.DataExportFormat, ip, userAgent string) (*models.DataExportResponse, error) {
+	if format == "" {
+		format = models.DataExportFormatJSON
+	}
+	if !format.IsValid() {
+		return nil, errDataExportInvalidFormat
+	}
+
+	now := s.now()
+	latest, err := s.store.GetLatest(ctx, userID, format, now)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil && latest.Status != models.DataExportStatusFailed {
+		return s.response(latest)
+	}
+
+	export := &models.UserDataExport{
+		UserID:    userID,
+		Format:    format,
+		Status:    models.DataExportStatusPending,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.store.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	if _, err := s.jobs.Enqueue(ctx, dataExportJob, dataExportJobPayload{ExportID: export.ID}, dataExportMaxAttempts); err != nil {
+		if failErr := s.store.Fail(ctx, export.ID, "Export could not be queued", now); failErr != nil {
+			s.logger.Error("failed to fail unqueued data export", map[string]interface{}{
+				"export_id": export.ID.String(),
+				"error":     failErr.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	s.audit.Log(AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionDataExportRequest,
+		Status:    models.StatusSuccess,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details: map[string]interface{}{
+			"export_id": export.ID.String(),
+			"format":    string(format),
+		},
+	})
+
+	return s.response(export)
+}
+
+// Download returns the file of the export a signed download link points to. u is the URL the
+// link was requested with; the scheme and host are not signed.
+func (s *DataExportService) Download(ctx context.Context, exportID uuid.UUID, method string, u *url.URL, ip, userAgent string) (*DataExportFile, error) {
+	claims, err := s.signer.Verify(method, u)
+	if err != nil || !slices.Contains(claims.Scopes, dataExportScope) {
+		return nil, errDataExportInvalidLink
+	}
+
+	export, err := s.store.GetByID(ctx, exportID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, errDataExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if claims.UserID != export.UserID.String() {
+		return nil, errDataExportInvalidLink
+	}
+	if !s.now().Before(export.ExpiresAt) {
+		return nil, errDataExportNotFound
+	}
+	if export.Status != models.DataExportStatusReady {
+		return nil, errDataExportNotReady
+	}
+
+	content, err := s.store.GetContent(ctx, export.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, errDataExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(AuditLogParams{
+		UserID:    &export.UserID,
+		Action:    models.ActionDataExportDownload,
+		Status:    models.StatusSuccess,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details: map[string]interface{}{
+			"export_id": export.ID.String(),
+			"format":    string(export.Format),
+		},
+	})
+
+	file := &DataExportFile{
+		Name:        fmt.Sprintf("user-data-export-%s.%s", export.CreatedAt.UTC().Format("20060102-150405"), export.Format),
+		ContentType: "application/json",
+		Content:     content,
+	}
+	if export.Format == models.DataExportFormatZIP {
+		file.ContentType = "application/zip"
+	}
+	return file, nil
+}
+
+// DeleteExpired deletes exports past their expiry and returns how many were deleted
+func (s *DataExportService) DeleteExpired(ctx context.Context) (int, error) {
+	return s.store.DeleteExpired(ctx, s.now())
+}
+
+// response describes an export, with a fresh download link once it is ready
+func (s *DataExportService) response(export *models.UserDataExport) (*models.DataExportResponse, error) {
+	resp := &models.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Format:      export.Format,
+		Size:        export.Size,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status != models.DataExportStatusReady {
+		return resp, nil
+	}
+
+	// Signed URLs carry whole seconds
+	expiresAt := s.now().Add(s.linkTTL).Truncate(time.Second)
+	if expiresAt.After(export.ExpiresAt) {
+		expiresAt = export.ExpiresAt.Truncate(time.Second)
+	}
+	link, err := s.signer.Sign(fmt.Sprintf("%s/api/auth/me/export/%s/download", s.baseURL, export.ID), signedurl.Claims{
+		UserID:    export.UserID.String(),
+		Scopes:    []string{dataExportScope},
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign data export link: %w", err)
+	}
+	resp.DownloadURL = link
+	resp.DownloadURLExpiresAt = &expiresAt
+	return resp, nil
+}
+
+// runExportJob generates an export; failures are retried by the job queue and the export is
+// failed after the last attempt
+func (s *DataExportService) runExportJob(ctx context.Context, job *models.BackgroundJob) error {
+	var payload dataExportJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return PermanentJobError(fmt.Errorf("invalid data export job: %w", err))
+	}
+
+	export, err := s.store.GetByID(ctx, payload.ExportID)
+	if errors.Is(err, models.ErrNotFound) {
+		// Expired and deleted before it was generated
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if export.Status != models.DataExportStatusPending {
+		return nil
+	}
+
+	content, err := s.generate(ctx, export)
+	if err != nil {
+		if job.Attempts >= job.MaxAttempts {
+			if failErr := s.store.Fail(ctx, export.ID, "Export could not be generated", s.now()); failErr != nil {
+				return fmt.Errorf("failed to generate data export: %w (and to mark it failed: %v)", err, failErr)
+			}
+		}
+		return fmt.Errorf("failed to generate data export: %w", err)
+	}
+
+	return s.store.Complete(ctx, export.ID, content, s.now())
+}
+
+// generate assembles the user's data and encodes it in the export's format
+func (s *DataExportService) generate(ctx context.Context, export *models.UserDataExport) ([]byte, error) {
+	doc, err := s.collect(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Format != models.DataExportFormatZIP {
+		return json.MarshalIndent(doc, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", map[string]interface{}{"exported_at": doc.ExportedAt, "profile": doc.Profile}},
+		{"emails.json", doc.Emails},
+		{"application_profiles.json", doc.ApplicationProfiles},
+		{"sessions.json", doc.Sessions},
+		{"consents.json", doc.Consents},
+		{"audit_events.json", doc.AuditEvents},
+		{"linked_identities.json", doc.LinkedIdentities},
+	}
+	for _, section := range sections {
+		data, err := json.MarshalIndent(section.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", section.name, err)
+		}
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: section.name, Method: zip.Deflate, Modified: doc.ExportedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", section.name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", section.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write data export archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// collect loads everything stored about a user
+func (s *DataExportService) collect(ctx context.Context, userID uuid.UUID) (*models.UserDataExportDocument, error) {
+	user, err := s.users.GetByID(ctx, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	doc := &models.UserDataExportDocument{
+		ExportedAt:       s.now().UTC(),
+		Profile:          user,
+		LinkedIdentities: &models.DataExportLinkedIdentities{},
+	}
+
+	if doc.Emails, err = s.source.ListUserEmails(ctx, userID); err != nil {
+		return nil, err
+	}
+	if doc.Sessions, err = s.collectSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	profiles, err := s.source.ListUserProfiles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	doc.ApplicationProfiles = make([]*models.DataExportAppProfile, 0, len(profiles))
+	for _, p := range profiles {
+		doc.ApplicationProfiles = append(doc.ApplicationProfiles, &models.DataExportAppProfile{
+			ApplicationID: p.ApplicationID,
+			DisplayName:   p.DisplayName,
+			AvatarURL:     p.AvatarURL,
+			Nickname:      p.Nickname,
+			Metadata:      rawJSON(p.Metadata),
+			AppRoles:      p.AppRoles,
+			IsActive:      p.IsActive,
+			IsBanned:      p.IsBanned,
+			BanReason:     p.BanReason,
+			LastAccessAt:  p.LastAccessAt,
+			CreatedAt:     p.CreatedAt,
+		})
+	}
+
+	consents, err := s.source.ListUserConsents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	doc.Consents = make([]*models.DataExportConsent, 0, len(consents))
+	for _, c := range consents {
+		consent := &models.DataExportConsent{
+			ClientID:  c.ClientID,
+			Scopes:    c.Scopes,
+			GrantedAt: c.GrantedAt,
+			RevokedAt: c.RevokedAt,
+		}
+		if c.Client != nil {
+			consent.ClientName = c.Client.Name
+		}
+		doc.Consents = append(doc.Consents, consent)
+	}
+
+	if doc.AuditEvents, err = s.collectAuditEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	oauthAccounts, err := s.source.ListOAuthAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	doc.LinkedIdentities.OAuth = make([]*models.DataExportOAuthAccount, 0, len(oauthAccounts))
+	for _, a := range oauthAccounts {
+		doc.LinkedIdentities.OAuth = append(doc.LinkedIdentities.OAuth, &models.DataExportOAuthAccount{
+			Provider:       a.Provider,
+			ProviderUserID: a.ProviderUserID,
+			Profile:        rawJSON(a.ProfileData),
+			CreatedAt:      a.CreatedAt,
+		})
+	}
+	if doc.LinkedIdentities.Telegram, err = s.source.ListTelegramAccounts(ctx, userID); err != nil {
+		return nil, err
+	}
+	if doc.LinkedIdentities.WebAuthn, err = s.source.ListWebAuthnCredentials(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// collectSessions merges the sessions kept in the database with the active ones of the
+// session store, which may live in Redis
+func (s *DataExportService) collectSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	stored, err := s.source.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.sessions.GetUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active sessions: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]int, len(stored))
+	for i, session := range stored {
+		seen[session.ID] = i
+	}
+	for _, session := range active {
+		if i, ok := seen[session.ID]; ok {
+			stored[i] = session
+			continue
+		}
+		stored = append(stored, session)
+	}
+	return stored, nil
+}
+
+// collectAuditEvents loads the audit log entries about a user, oldest first
+func (s *DataExportService) collectAuditEvents(ctx context.Context, userID uuid.UUID) ([]*models.DataExportAuditEvent, error) {
+	events := make([]*models.DataExportAuditEvent, 0)
+	for len(events) < dataExportMaxAuditEvents {
+		logs, err := s.source.ListUserAuditLogs(ctx, userID, len(events), dataExportAuditPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			events = append(events, &models.DataExportAuditEvent{
+				ID:           l.ID,
+				Action:       l.Action,
+				Status:       l.Status,
+				ResourceType: l.ResourceType,
+				ResourceID:   l.ResourceID,
+				IPAddress:    l.IPAddress,
+				UserAgent:    l.UserAgent,
+				CountryCode:  l.CountryCode,
+				City:         l.City,
+				Details:      rawJSON(l.Details),
+				CreatedAt:    l.CreatedAt,
+			})
+		}
+		if len(logs) < dataExportAuditPageSize {
+			break
+		}
+	}
+	return events, nil
+}
+
+// rawJSON returns stored JSON as is, or nothing when it is empty or not valid JSON
+func rawJSON(data []byte) json.RawMessage {
+	if len(data) == 0 || !json.Valid(data) {
+		return nil
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/smilemakc/auth-gateway/pkg/signedurl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDataExportStore struct {
+	exports map[uuid.UUID]*models.UserDataExport
+}
+
+func (m *mockDataExportStore) Create(ctx context.Context, export *models.UserDataExport) error {
+	export.ID = uuid.New()
+	export.CreatedAt = time.Now()
+	m.exports[export.ID] = export
+	return nil
+}
+
+func (m *mockDataExportStore) GetByID(ctx context.Context, id uuid.UUID) (*models.UserDataExport, error) {
+	export, ok := m.exports[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *export
+	copied.Content = nil
+	return &copied, nil
+}
+
+func (m *mockDataExportStore) GetLatest(ctx context.Context, userID uuid.UUID, format models.DataExportFormat, now time.Time) (*models.UserDataExport, error) {
+	var latest *models.UserDataExport
+	for _, export := range m.exports {
+		if export.UserID == userID && export.Format == format && export.ExpiresAt.After(now) &&
+			(latest == nil || export.CreatedAt.After(latest.CreatedAt)) {
+			latest = export
+		}
+	}
+	if latest == nil {
+		return nil, models.ErrNotFound
+	}
+	return m.GetByID(ctx, latest.ID)
+}
+
+func (m *mockDataExportStore) GetContent(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	export, ok := m.exports[id]
+	if !ok || export.Status != models.DataExportStatusReady {
+		return nil, models.ErrNotFound
+	}
+	return export.Content, nil
+}
+
+func (m *mockDataExportStore) Complete(ctx context.Context, id uuid.UUID, content []byte, completedAt time.Time) error {
+	export := m.exports[id]
+	export.Status = models.DataExportStatusReady
+	export.Content = content
+	export.Size = int64(len(content))
+	export.CompletedAt = &completedAt
+	return nil
+}
+
+func (m *mockDataExportStore) Fail(ctx context.Context, id uuid.UUID, message string, completedAt time.Time) error {
+	export := m.exports[id]
+	export.Status = models.DataExportStatusFailed
+	export.Error = message
+	export.CompletedAt = &completedAt
+	return nil
+}
+
+func (m *mockDataExportStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for id, export := range m.exports {
+		if !export.ExpiresAt.After(now) {
+			delete(m.exports, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type mockUserDataSource struct {
+	sessions  []models.Session
+	consents  []*models.UserConsent
+	auditLogs []*models.AuditLog
+	oauth     []*models.OAuthAccount
+	err       error
+}
+
+func (m *mockUserDataSource) ListUserEmails(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error) {
+	return []*models.UserEmail{}, m.err
+}
+
+func (m *mockUserDataSource) ListUserProfiles(ctx context.Context, userID uuid.UUID) ([]*models.UserApplicationProfile, error) {
+	return []*models.UserApplicationProfile{}, nil
+}
+
+func (m *mockUserDataSource) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	return m.sessions, nil
+}
+
+func (m *mockUserDataSource) ListUserConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error) {
+	return m.consents, nil
+}
+
+func (m *mockUserDataSource) ListUserAuditLogs(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.AuditLog, error) {
+	if offset >= len(m.auditLogs) {
+		return []*models.AuditLog{}, nil
+	}
+	end := offset + limit
+	if end > len(m.auditLogs) {
+		end = len(m.auditLogs)
+	}
+	return m.auditLogs[offset:end], nil
+}
+
+func (m *mockUserDataSource) ListOAuthAccounts(ctx context.Context, userID uuid.UUID) ([]*models.OAuthAccount, error) {
+	return m.oauth, nil
+}
+
+func (m *mockUserDataSource) ListTelegramAccounts(ctx context.Context, userID uuid.UUID) ([]*models.UserTelegramAccount, error) {
+	return []*models.UserTelegramAccount{}, nil
+}
+
+func (m *mockUserDataSource) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	return []*models.WebAuthnCredential{}, nil
+}
+
+type dataExportFixture struct {
+	svc      *DataExportService
+	store    *mockDataExportStore
+	source   *mockUserDataSource
+	sessions *mockSessionStore
+	jobStore *mockBackgroundJobStore
+	signer   *signedurl.Signer
+	user     *models.User
+	audited  []AuditLogParams
+}
+
+func setupDataExportService() *dataExportFixture {
+	f := &dataExportFixture{
+		store:    &mockDataExportStore{exports: make(map[uuid.UUID]*models.UserDataExport)},
+		source:   &mockUserDataSource{},
+		sessions: &mockSessionStore{},
+		signer:   signedurl.New([]byte(strings.Repeat("s", 32))),
+		user:     &models.User{ID: uuid.New(), Email: "user@example.com", Username: "user"},
+	}
+	users := &mockUserStore{GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if id != f.user.ID {
+			return nil, models.ErrNotFound
+		}
+		return f.user, nil
+	}}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.audited = append(f.audited, params) }}
+	var jobs *JobQueueService
+	jobs, f.jobStore = setupJobQueueService()
+	f.svc = NewDataExportService(f.store, f.source, users, f.sessions, jobs, f.signer, audit,
+		"https://auth.example.com/", 24*time.Hour, 15*time.Minute, logger.New("test", logger.ErrorLevel, false))
+	return f
+}
+
+// generate runs the job queued for the last export request
+func (f *dataExportFixture) generate(t *testing.T) {
+	t.Helper()
+	require.NotEmpty(t, f.jobStore.created)
+	job := f.jobStore.created[len(f.jobStore.created)-1]
+	job.Attempts = 1
+	require.NoError(t, f.svc.runExportJob(context.Background(), job))
+}
+
+func TestDataExportService_RequestExport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Queues an export and returns it until it expires", func(t *testing.T) {
+		f := setupDataExportService()
+
+		first, err := f.svc.RequestExport(ctx, f.user.ID, "", "10.0.0.1", "test")
+		require.NoError(t, err)
+		assert.Equal(t, models.DataExportStatusPending, first.Status)
+		assert.Equal(t, models.DataExportFormatJSON, first.Format)
+		assert.Empty(t, first.DownloadURL)
+		require.Len(t, f.jobStore.created, 1)
+		assert.Equal(t, dataExportJob, f.jobStore.created[0].Type)
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, models.ActionDataExportRequest, f.audited[0].Action)
+
+		again, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatJSON, "10.0.0.1", "test")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, again.ID)
+		assert.Len(t, f.jobStore.created, 1)
+
+		f.generate(t)
+		ready, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatJSON, "10.0.0.1", "test")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, ready.ID)
+		assert.Equal(t, models.DataExportStatusReady, ready.Status)
+		assert.True(t, strings.HasPrefix(ready.DownloadURL, "https://auth.example.com/api/auth/me/export/"+first.ID.String()+"/download?"))
+		require.NotNil(t, ready.DownloadURLExpiresAt)
+		assert.False(t, ready.DownloadURLExpiresAt.After(ready.ExpiresAt))
+	})
+
+	t.Run("Queues a new export after a failed one", func(t *testing.T) {
+		f := setupDataExportService()
+		first, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatZIP, "", "")
+		require.NoError(t, err)
+		require.NoError(t, f.store.Fail(ctx, first.ID, "failed", time.Now()))
+
+		second, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatZIP, "", "")
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Len(t, f.jobStore.created, 2)
+	})
+
+	t.Run("Rejects unknown formats", func(t *testing.T) {
+		f := setupDataExportService()
+		_, err := f.svc.RequestExport(ctx, f.user.ID, "xml", "", "")
+		assert.Equal(t, errDataExportInvalidFormat, err)
+		assert.Empty(t, f.jobStore.created)
+	})
+}
+
+func TestDataExportService_RunExportJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Generates a ZIP with every section", func(t *testing.T) {
+		f := setupDataExportService()
+		stored := models.Session{ID: uuid.New(), UserID: f.user.ID, DeviceType: "desktop"}
+		active := models.Session{ID: uuid.New(), UserID: f.user.ID, DeviceType: "mobile"}
+		f.source.sessions = []models.Session{stored}
+		f.sessions.GetUserSessionsFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+			return []models.Session{active, stored}, nil
+		}
+		f.source.consents = []*models.UserConsent{{ClientID: uuid.New(), Client: &models.OAuthClient{Name: "Notes"}, Scopes: []string{"openid"}}}
+		f.source.oauth = []*models.OAuthAccount{{Provider: "github", ProviderUserID: "42", AccessToken: "secret-token", ProfileData: []byte(`{"login":"octo"}`)}}
+		for i := 0; i < dataExportAuditPageSize+2; i++ {
+			f.source.auditLogs = append(f.source.auditLogs, &models.AuditLog{ID: uuid.New(), Action: "signin", Details: []byte(`{"method":"password"}`)})
+		}
+
+		resp, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatZIP, "", "")
+		require.NoError(t, err)
+		f.generate(t)
+
+		export := f.store.exports[resp.ID]
+		require.Equal(t, models.DataExportStatusReady, export.Status)
+		archive, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+		require.NoError(t, err)
+
+		files := make(map[string][]byte)
+		for _, file := range archive.File {
+			r, err := file.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			files[file.Name] = data
+		}
+		assert.Len(t, files, 7)
+		assert.Contains(t, string(files["profile.json"]), "user@example.com")
+
+		var sessions []models.Session
+		require.NoError(t, json.Unmarshal(files["sessions.json"], &sessions))
+		assert.Len(t, sessions, 2)
+
+		var consents []*models.DataExportConsent
+		require.NoError(t, json.Unmarshal(files["consents.json"], &consents))
+		require.Len(t, consents, 1)
+		assert.Equal(t, "Notes", consents[0].ClientName)
+
+		var events []*models.DataExportAuditEvent
+		require.NoError(t, json.Unmarshal(files["audit_events.json"], &events))
+		assert.Len(t, events, dataExportAuditPageSize+2)
+		assert.JSONEq(t, `{"method":"password"}`, string(events[0].Details))
+
+		assert.Contains(t, string(files["linked_identities.json"]), `"login": "octo"`)
+		assert.NotContains(t, string(files["linked_identities.json"]), "secret-token")
+	})
+
+	t.Run("Fails the export after the last attempt", func(t *testing.T) {
+		f := setupDataExportService()
+		f.source.err = errors.New("connection refused")
+		resp, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatJSON, "", "")
+		require.NoError(t, err)
+		job := f.jobStore.created[0]
+
+		job.Attempts = 1
+		assert.Error(t, f.svc.runExportJob(ctx, job))
+		assert.Equal(t, models.DataExportStatusPending, f.store.exports[resp.ID].Status)
+
+		job.Attempts = job.MaxAttempts
+		assert.Error(t, f.svc.runExportJob(ctx, job))
+		assert.Equal(t, models.DataExportStatusFailed, f.store.exports[resp.ID].Status)
+	})
+}
+
+func TestDataExportService_Download(t *testing.T) {
+	ctx := context.Background()
+	f := setupDataExportService()
+	resp, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatJSON, "", "")
+	require.NoError(t, err)
+	f.generate(t)
+	ready, err := f.svc.RequestExport(ctx, f.user.ID, models.DataExportFormatJSON, "", "")
+	require.NoError(t, err)
+	f.audited = nil
+
+	link, err := url.Parse(ready.DownloadURL)
+	require.NoError(t, err)
+
+	t.Run("Serves the file for a valid link", func(t *testing.T) {
+		file, err := f.svc.Download(ctx, resp.ID, "GET", link, "10.0.0.1", "test")
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", file.ContentType)
+		assert.True(t, strings.HasSuffix(file.Name, ".json"))
+
+		var doc models.UserDataExportDocument
+		require.NoError(t, json.Unmarshal(file.Content, &doc))
+		assert.Equal(t, f.user.ID, doc.Profile.ID)
+
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, models.ActionDataExportDownload, f.audited[0].Action)
+	})
+
+	t.Run("Rejects a tampered link", func(t *testing.T) {
+		tampered := *link
+		query := tampered.Query()
+		query.Set(signedurl.ParamUser, uuid.New().String())
+		tampered.RawQuery = query.Encode()
+
+		_, err := f.svc.Download(ctx, resp.ID, "GET", &tampered, "", "")
+		assert.Equal(t, errDataExportInvalidLink, err)
+	})
+
+	t.Run("Rejects a link signed for another user", func(t *testing.T) {
+		signed, err := f.signer.Sign("/api/auth/me/export/"+resp.ID.String()+"/download", signedurl.Claims{
+			UserID:    uuid.New().String(),
+			Scopes:    []string{dataExportScope},
+			ExpiresAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+
+		_, err = f.svc.Download(ctx, resp.ID, "GET", u, "", "")
+		assert.Equal(t, errDataExportInvalidLink, err)
+	})
+
+	t.Run("Rejects a link without the export scope", func(t *testing.T) {
+		signed, err := f.signer.Sign("/api/auth/me/export/"+resp.ID.String()+"/download", signedurl.Claims{
+			UserID:    f.user.ID.String(),
+			ExpiresAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+
+		_, err = f.svc.Download(ctx, resp.ID, "GET", u, "", "")
+		assert.Equal(t, errDataExportInvalidLink, err)
+	})
+
+	t.Run("Rejects a link to another export", func(t *testing.T) {
+		_, err := f.svc.Download(ctx, uuid.New(), "GET", link, "", "")
+		assert.Equal(t, errDataExportNotFound, err)
+	})
+}
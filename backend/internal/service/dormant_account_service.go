@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// dormantBatchSize is how many dormant accounts are loaded per query
+	dormantBatchSize = 100
+	// dormantReportLimit caps how many accounts a dormant account report lists
+	dormantReportLimit = 500
+)
+
+var errInvalidDormantAction = models.NewAppError(http.StatusBadRequest, "Invalid dormant account action", "Action must be one of: suspend, deactivate, anonymize")
+
+// dormantActionStates maps each action to the lifecycle state it moves accounts to
+var dormantActionStates = map[models.DormantAction]models.UserState{
+	models.DormantActionSuspend:    models.UserStateSuspended,
+	models.DormantActionDeactivate: models.UserStateDeactivated,
+	models.DormantActionAnonymize:  models.UserStateDeleted,
+}
+
+// DormantAccountService applies the dormant account policy. An account is dormant once it has
+// neither signed in nor used a session for the configured number of days. Dormant accounts
+// are sent a notice first and, if they stay unused for the notice period, are suspended,
+// deactivated or anonymized through the user lifecycle, which revokes their access. Signing
+// in again during the notice period cancels it. The report shows what the next run would do
+// without changing anything, so the policy can be reviewed before it is enabled.
+type DormantAccountService struct {
+	settings  SettingStore
+	store     DormantAccountStore
+	lifecycle *UserLifecycleService
+	notifier  NotificationSender
+	audit     AuditLogger
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewDormantAccountService creates a new dormant account service. notifier may be nil, in
+// which case the notice period still applies but no email is sent.
+func NewDormantAccountService(settings SettingStore, store DormantAccountStore, lifecycle *UserLifecycleService, notifier NotificationSender, audit AuditLogger, logger *logger.Logger) *DormantAccountService {
+	return &DormantAccountService{
+		settings:  settings,
+		store:     store,
+		lifecycle: lifecycle,
+		notifier:  notifier,
+		audit:     audit,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// GetPolicy returns the current dormant account policy
+func (s *DormantAccountService) GetPolicy(ctx context.Context) (*models.DormantAccountPolicy, error) {
+	setting, err := s.settings.GetSetting(ctx, models.SettingDormantAccountPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.DormantAccountPolicy{}
+	if err := json.Unmarshal([]byte(setting.Value), policy); err != nil {
+		return nil, fmt.Errorf("invalid dormant account policy setting: %w", err)
+	}
+	if policy.ExcludedRoles == nil {
+		policy.ExcludedRoles = []string{}
+	}
+	if policy.ExcludedDomains == nil {
+		policy.ExcludedDomains = []string{}
+	}
+	if policy.ExcludedUserIDs == nil {
+		policy.ExcludedUserIDs = []uuid.UUID{}
+	}
+	return policy, nil
+}
+
+// UpdatePolicy replaces the dormant account policy
+func (s *DormantAccountService) UpdatePolicy(ctx context.Context, req *models.UpdateDormantAccountPolicyRequest, updatedBy uuid.UUID) (*models.DormantAccountPolicy, error) {
+	if !req.Action.IsValid() {
+		return nil, errInvalidDormantAction
+	}
+	domains, err := normalizeDomains(req.ExcludedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := uniqueUUIDs(req.ExcludedUserIDs)
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+
+	policy := &models.DormantAccountPolicy{
+		Enabled:         req.Enabled,
+		InactiveDays:    req.InactiveDays,
+		NoticeDays:      req.NoticeDays,
+		Action:          req.Action,
+		ExcludedRoles:   normalizeRoleNames(req.ExcludedRoles),
+		ExcludedDomains: domains,
+		ExcludedUserIDs: userIDs,
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settings.UpdateSetting(ctx, models.SettingDormantAccountPolicy, string(value), &updatedBy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Report lists the accounts the policy currently considers dormant and what the next run would
+// do to each of them. Nothing is changed, and the report works while the policy is disabled.
+func (s *DormantAccountService) Report(ctx context.Context) (*models.DormantAccountReport, error) {
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDormantPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report := &models.DormantAccountReport{
+		Policy:      policy,
+		GeneratedAt: now,
+		Accounts:    make([]*models.DormantAccount, 0),
+	}
+
+	err = s.forEachCandidate(ctx, policy, now, func(candidate *models.DormantAccountCandidate) {
+		stage, enforceAt := dormantStage(policy, candidate, now)
+		switch stage {
+		case models.DormantStageNotify:
+			report.ToNotify++
+		case models.DormantStageNoticePeriod:
+			report.InNoticePeriod++
+		case models.DormantStageEnforce:
+			report.ToEnforce++
+		}
+
+		if len(report.Accounts) == dormantReportLimit {
+			report.Truncated = true
+			return
+		}
+		report.Accounts = append(report.Accounts, &models.DormantAccount{
+			UserID:       candidate.UserID,
+			Email:        candidate.Email,
+			Username:     candidate.Username,
+			LastActiveAt: candidate.LastActiveAt,
+			NotifiedAt:   candidate.NotifiedAt,
+			EnforceAt:    enforceAt,
+			Stage:        stage,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Run applies the policy once: dormant accounts that were not notified yet are sent the notice,
+// and accounts whose notice period is over get the policy action. Nothing happens while the
+// policy is disabled. Accounts that fail are logged, counted and retried on the next run.
+func (s *DormantAccountService) Run(ctx context.Context) (*models.DormantAccountRunResult, error) {
+	result := &models.DormantAccountRunResult{}
+
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return result, err
+	}
+	if !policy.Enabled {
+		return result, nil
+	}
+	if err := validateDormantPolicy(policy); err != nil {
+		return result, err
+	}
+
+	now := s.now()
+	err = s.forEachCandidate(ctx, policy, now, func(candidate *models.DormantAccountCandidate) {
+		stage, enforceAt := dormantStage(policy, candidate, now)
+
+		var err error
+		switch stage {
+		case models.DormantStageNotify:
+			if err = s.notify(ctx, policy, candidate, now, enforceAt); err == nil {
+				result.Notified++
+			}
+		case models.DormantStageEnforce:
+			if err = s.enforce(ctx, policy, candidate); err == nil {
+				result.Enforced++
+			}
+		}
+		if err != nil {
+			result.Failed++
+			s.logger.Warn("Failed to apply dormant account policy", map[string]interface{}{
+				"user_id": candidate.UserID.String(),
+				"stage":   string(stage),
+				"error":   err.Error(),
+			})
+		}
+	})
+
+	return result, err
+}
+
+// forEachCandidate calls fn for every dormant account the policy applies to
+func (s *DormantAccountService) forEachCandidate(ctx context.Context, policy *models.DormantAccountPolicy, now time.Time, fn func(*models.DormantAccountCandidate)) error {
+	cutoff := now.AddDate(0, 0, -policy.InactiveDays)
+	afterID := uuid.Nil
+
+	for {
+		candidates, err := s.store.ListCandidates(ctx, cutoff, policy.ExcludedRoles, policy.ExcludedUserIDs, afterID, dormantBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			if domain := utils.EmailDomain(candidate.Email); domain != "" && matchesAnyDomain(domain, policy.ExcludedDomains) {
+				continue
+			}
+			fn(candidate)
+		}
+
+		if len(candidates) < dormantBatchSize {
+			return nil
+		}
+		afterID = candidates[len(candidates)-1].UserID
+	}
+}
+
+// notify sends the dormant account notice and starts the notice period. Accounts without an
+// email address, or gateways without email, get the notice period without the email.
+func (s *DormantAccountService) notify(ctx context.Context, policy *models.DormantAccountPolicy, candidate *models.DormantAccountCandidate, now, enforceAt time.Time) error {
+	if s.notifier != nil && candidate.Email != "" {
+		variables := map[string]interface{}{
+			"username":       candidate.Username,
+			"email":          candidate.Email,
+			"last_active_at": candidate.LastActiveAt.UTC().Format("2006-01-02"),
+			"inactive_days":  policy.InactiveDays,
+			"action":         dormantActionDescription(policy.Action),
+			"enforce_at":     enforceAt.UTC().Format("2006-01-02 15:04 MST"),
+		}
+		if err := s.notifier.SendEmail(ctx, nil, nil, candidate.Email, models.EmailTemplateTypeAccountDormant, variables); err != nil {
+			return fmt.Errorf("failed to send dormant account notice: %w", err)
+		}
+	}
+
+	if err := s.store.MarkNotified(ctx, candidate.UserID, now); err != nil {
+		return err
+	}
+
+	s.audit.Log(AuditLogParams{
+		UserID: &candidate.UserID,
+		Action: models.ActionDormantAccountNotice,
+		Status: models.StatusSuccess,
+		Details: map[string]interface{}{
+			"last_active_at": candidate.LastActiveAt.UTC().Format(time.RFC3339),
+			"action":         string(policy.Action),
+			"enforce_at":     enforceAt.UTC().Format(time.RFC3339),
+		},
+	})
+	return nil
+}
+
+// enforce applies the policy action to a dormant account. Anonymized accounts are scrubbed
+// before they are deleted, so a failed deletion is picked up again by the next run.
+func (s *DormantAccountService) enforce(ctx context.Context, policy *models.DormantAccountPolicy, candidate *models.DormantAccountCandidate) error {
+	if policy.Action == models.DormantActionAnonymize {
+		if err := s.store.Anonymize(ctx, candidate.UserID); err != nil {
+			return err
+		}
+		s.audit.Log(AuditLogParams{
+			UserID:  &candidate.UserID,
+			Action:  models.ActionUserAnonymize,
+			Status:  models.StatusSuccess,
+			Details: map[string]interface{}{"reason": StateReasonDormant},
+		})
+	}
+
+	_, err := s.lifecycle.Transition(ctx, UserStateTransitionParams{
+		UserID: candidate.UserID,
+		State:  dormantActionStates[policy.Action],
+		Reason: StateReasonDormant,
+	})
+	return err
+}
+
+// dormantStage returns what the policy does next to a dormant account and when it enforces.
+// A notice sent before the account's last activity belongs to an earlier dormancy and does
+// not count.
+func dormantStage(policy *models.DormantAccountPolicy, candidate *models.DormantAccountCandidate, now time.Time) (models.DormantAccountStage, time.Time) {
+	if policy.NoticeDays == 0 {
+		return models.DormantStageEnforce, now
+	}
+	if candidate.NotifiedAt == nil || candidate.NotifiedAt.Before(candidate.LastActiveAt) {
+		return models.DormantStageNotify, now.AddDate(0, 0, policy.NoticeDays)
+	}
+
+	enforceAt := candidate.NotifiedAt.AddDate(0, 0, policy.NoticeDays)
+	if now.Before(enforceAt) {
+		return models.DormantStageNoticePeriod, enforceAt
+	}
+	return models.DormantStageEnforce, enforceAt
+}
+
+// validateDormantPolicy rejects stored policies that cannot be applied
+func validateDormantPolicy(policy *models.DormantAccountPolicy) error {
+	if !policy.Action.IsValid() {
+		return errInvalidDormantAction
+	}
+	if policy.InactiveDays < 1 || policy.NoticeDays < 0 {
+		return models.NewAppError(http.StatusBadRequest, "Invalid dormant account policy", "inactive_days must be at least 1 and notice_days must not be negative")
+	}
+	return nil
+}
+
+// dormantActionDescription describes an action for the notice email
+func dormantActionDescription(action models.DormantAction) string {
+	switch action {
+	case models.DormantActionDeactivate:
+		return "deactivated"
+	case models.DormantActionAnonymize:
+		return "deleted"
+	default:
+		return "suspended"
+	}
+}
+
+// normalizeRoleNames trims role names and drops empty and duplicate ones
+func normalizeRoleNames(roles []string) []string {
+	normalized := make([]string, 0, len(roles))
+	seen := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if role == "" || seen[role] {
+			continue
+		}
+		seen[role] = true
+		normalized = append(normalized, role)
+	}
+	return normalized
+}
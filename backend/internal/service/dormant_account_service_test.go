@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDormantAccountStore struct {
+	candidates []*models.DormantAccountCandidate
+	notified   map[uuid.UUID]time.Time
+	anonymized []uuid.UUID
+}
+
+func (m *mockDormantAccountStore) ListCandidates(ctx context.Context, cutoff time.Time, excludedRoles []string, excludedUserIDs []uuid.UUID, afterID uuid.UUID, limit int) ([]*models.DormantAccountCandidate, error) {
+	var result []*models.DormantAccountCandidate
+	for _, candidate := range m.candidates {
+		if afterID != uuid.Nil || !candidate.LastActiveAt.Before(cutoff) {
+			continue
+		}
+		copied := *candidate
+		if at, ok := m.notified[candidate.UserID]; ok {
+			copied.NotifiedAt = &at
+		}
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (m *mockDormantAccountStore) MarkNotified(ctx context.Context, userID uuid.UUID, notifiedAt time.Time) error {
+	m.notified[userID] = notifiedAt
+	return nil
+}
+
+func (m *mockDormantAccountStore) Anonymize(ctx context.Context, userID uuid.UUID) error {
+	m.anonymized = append(m.anonymized, userID)
+	return nil
+}
+
+type dormantFixture struct {
+	svc       *DormantAccountService
+	store     *mockDormantAccountStore
+	notifier  *mockNotificationSender
+	lifecycle *lifecycleFixture
+	audited   []AuditLogParams
+	now       time.Time
+}
+
+// setupDormantAccountService creates active users that were last active daysAgo[i] days ago
+func setupDormantAccountService(t *testing.T, policy models.DormantAccountPolicy, emails []string, daysAgo []int) *dormantFixture {
+	t.Helper()
+	value, err := json.Marshal(policy)
+	require.NoError(t, err)
+
+	f := &dormantFixture{
+		store:    &mockDormantAccountStore{notified: map[uuid.UUID]time.Time{}},
+		notifier: &mockNotificationSender{},
+		now:      time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	var users []*models.User
+	for i, email := range emails {
+		user := &models.User{ID: uuid.New(), Email: email, State: models.UserStateActive, IsActive: true}
+		users = append(users, user)
+		f.store.candidates = append(f.store.candidates, &models.DormantAccountCandidate{
+			UserID:       user.ID,
+			Email:        email,
+			LastActiveAt: f.now.AddDate(0, 0, -daysAgo[i]),
+		})
+	}
+	f.lifecycle = setupUserLifecycleService(users...)
+
+	settings := &mockSettingStore{values: map[string]string{models.SettingDormantAccountPolicy: string(value)}}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.audited = append(f.audited, params) }}
+	f.svc = NewDormantAccountService(settings, f.store, f.lifecycle.svc, f.notifier, audit, testLogger())
+	f.svc.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *dormantFixture) state(i int) models.UserState {
+	return f.lifecycle.store.users[f.store.candidates[i].UserID].State
+}
+
+func TestDormantAccountService_Report(t *testing.T) {
+	policy := models.DormantAccountPolicy{InactiveDays: 90, NoticeDays: 14, Action: models.DormantActionSuspend, ExcludedDomains: []string{"partner.com"}}
+	f := setupDormantAccountService(t, policy,
+		[]string{"new@example.com", "waiting@example.com", "due@example.com", "vip@eu.partner.com", "recent@example.com"},
+		[]int{120, 120, 200, 400, 10})
+	f.store.notified[f.store.candidates[1].UserID] = f.now.AddDate(0, 0, -3)
+	f.store.notified[f.store.candidates[2].UserID] = f.now.AddDate(0, 0, -30)
+
+	report, err := f.svc.Report(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.ToNotify)
+	assert.Equal(t, 1, report.InNoticePeriod)
+	assert.Equal(t, 1, report.ToEnforce)
+	require.Len(t, report.Accounts, 3)
+	assert.Equal(t, models.DormantStageNotify, report.Accounts[0].Stage)
+	assert.Equal(t, f.now.AddDate(0, 0, 14), report.Accounts[0].EnforceAt)
+	assert.Equal(t, models.DormantStageNoticePeriod, report.Accounts[1].Stage)
+	assert.Equal(t, f.now.AddDate(0, 0, 11), report.Accounts[1].EnforceAt)
+	assert.Equal(t, models.DormantStageEnforce, report.Accounts[2].Stage)
+
+	// A dry run changes nothing, even for a disabled policy
+	assert.Empty(t, f.notifier.sent)
+	assert.Equal(t, models.UserStateActive, f.state(2))
+	assert.Empty(t, f.audited)
+}
+
+func TestDormantAccountService_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Disabled policy does nothing", func(t *testing.T) {
+		f := setupDormantAccountService(t, models.DormantAccountPolicy{InactiveDays: 90, Action: models.DormantActionSuspend},
+			[]string{"old@example.com"}, []int{400})
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &models.DormantAccountRunResult{}, result)
+		assert.Equal(t, models.UserStateActive, f.state(0))
+	})
+
+	t.Run("Notifies first and suspends after the notice period", func(t *testing.T) {
+		policy := models.DormantAccountPolicy{Enabled: true, InactiveDays: 90, NoticeDays: 14, Action: models.DormantActionSuspend}
+		f := setupDormantAccountService(t, policy, []string{"old@example.com"}, []int{120})
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Notified)
+		assert.Equal(t, []string{"old@example.com:" + models.EmailTemplateTypeAccountDormant}, f.notifier.sent)
+		assert.Equal(t, models.UserStateActive, f.state(0))
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, models.ActionDormantAccountNotice, f.audited[0].Action)
+
+		// Still within the notice period
+		f.now = f.now.AddDate(0, 0, 7)
+		result, err = f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &models.DormantAccountRunResult{}, result)
+
+		f.now = f.now.AddDate(0, 0, 7)
+		result, err = f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Enforced)
+		assert.Equal(t, models.UserStateSuspended, f.state(0))
+		assert.Len(t, f.notifier.sent, 1)
+		require.Len(t, f.lifecycle.audited, 1)
+		assert.Equal(t, StateReasonDormant, f.lifecycle.audited[0].Details["reason"])
+	})
+
+	t.Run("A notice from before the last activity does not count", func(t *testing.T) {
+		policy := models.DormantAccountPolicy{Enabled: true, InactiveDays: 90, NoticeDays: 14, Action: models.DormantActionDeactivate}
+		f := setupDormantAccountService(t, policy, []string{"back@example.com"}, []int{100})
+		f.store.notified[f.store.candidates[0].UserID] = f.now.AddDate(0, 0, -300)
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Notified)
+		assert.Equal(t, 0, result.Enforced)
+		assert.Equal(t, models.UserStateActive, f.state(0))
+	})
+
+	t.Run("Anonymizes without notice", func(t *testing.T) {
+		policy := models.DormantAccountPolicy{Enabled: true, InactiveDays: 90, Action: models.DormantActionAnonymize}
+		f := setupDormantAccountService(t, policy, []string{"gone@example.com"}, []int{400})
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Enforced)
+		assert.Empty(t, f.notifier.sent)
+		assert.Equal(t, []uuid.UUID{f.store.candidates[0].UserID}, f.store.anonymized)
+		assert.Equal(t, models.UserStateDeleted, f.state(0))
+		assert.Equal(t, []uuid.UUID{f.store.candidates[0].UserID}, f.lifecycle.tokenVersions.bumped)
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, models.ActionUserAnonymize, f.audited[0].Action)
+	})
+
+	t.Run("Failed notice is retried on the next run", func(t *testing.T) {
+		policy := models.DormantAccountPolicy{Enabled: true, InactiveDays: 90, NoticeDays: 14, Action: models.DormantActionSuspend}
+		f := setupDormantAccountService(t, policy, []string{"down@example.com"}, []int{120})
+		f.notifier.fail = map[string]bool{"down@example.com": true}
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Empty(t, f.store.notified)
+	})
+}
+
+func TestDormantAccountService_UpdatePolicy(t *testing.T) {
+	f := setupDormantAccountService(t, models.DormantAccountPolicy{InactiveDays: 90, Action: models.DormantActionSuspend}, nil, nil)
+	excluded := uuid.New()
+
+	_, err := f.svc.UpdatePolicy(context.Background(), &models.UpdateDormantAccountPolicyRequest{InactiveDays: 30, Action: "ban"}, uuid.New())
+	assert.ErrorIs(t, err, errInvalidDormantAction)
+
+	policy, err := f.svc.UpdatePolicy(context.Background(), &models.UpdateDormantAccountPolicyRequest{
+		Enabled:         true,
+		InactiveDays:    180,
+		NoticeDays:      30,
+		Action:          models.DormantActionDeactivate,
+		ExcludedRoles:   []string{" auditor ", "auditor", ""},
+		ExcludedDomains: []string{"Partner.COM"},
+		ExcludedUserIDs: []uuid.UUID{excluded, excluded},
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auditor"}, policy.ExcludedRoles)
+	assert.Equal(t, []string{"partner.com"}, policy.ExcludedDomains)
+	assert.Equal(t, []uuid.UUID{excluded}, policy.ExcludedUserIDs)
+
+	stored, err := f.svc.GetPolicy(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, policy, stored)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/chaos"
 	"github.com/smilemakc/auth-gateway/internal/config"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/repository"
@@ -21,6 +22,7 @@ type EmailProfileService struct {
 	auditService    AuditLogger
 	cfg             *config.Config
 	fallbackEmail   EmailSender
+	faults          *chaos.Injector
 }
 
 func NewEmailProfileService(
@@ -41,6 +43,11 @@ func NewEmailProfileService(
 	}
 }
 
+// SetFaultInjector injects SMTP faults into emails sent through providers (non-production only)
+func (s *EmailProfileService) SetFaultInjector(faults *chaos.Injector) {
+	s.faults = faults
+}
+
 func (s *EmailProfileService) CreateProvider(ctx context.Context, req *models.CreateEmailProviderRequest) (*models.EmailProvider, error) {
 	provider := &models.EmailProvider{
 		Name:               req.Name,
@@ -618,6 +625,10 @@ func (s *EmailProfileService) sendViaSMTP(provider *models.EmailProvider, to, fr
 		return fmt.Errorf("SMTP configuration incomplete")
 	}
 
+	if err := s.faults.Inject(context.Background(), models.FaultTargetSMTP); err != nil {
+		return err
+	}
+
 	host := *provider.SMTPHost
 	port := *provider.SMTPPort
 	addr := fmt.Sprintf("%s:%d", host, port)
@@ -895,6 +906,18 @@ func (s *EmailProfileService) getDefaultNotificationSubject(templateType string)
 		return "Two-Factor Authentication Enabled"
 	case models.EmailTemplateType2FADisabled:
 		return "Two-Factor Authentication Disabled"
+	case models.EmailTemplateTypePasswordExpiring:
+		return "Your Password Will Expire Soon"
+	case models.EmailTemplateTypeAccountRecovery:
+		return "Account Recovery Activity"
+	case models.EmailTemplateTypeOrgUsageReport:
+		return "Organization Usage Report"
+	case models.EmailTemplateTypeEmailVerificationReminder:
+		return "Please Verify Your Email Address"
+	case models.EmailTemplateTypeAccountDormant:
+		return "Your Account Is Inactive"
+	case models.EmailTemplateTypeMagicLink:
+		return "Your Sign-In Link"
 	default:
 		return "Notification"
 	}
@@ -924,6 +947,34 @@ func (s *EmailProfileService) getDefaultNotificationHTMLBody(templateType string
 	case models.EmailTemplateType2FADisabled:
 		title = "2FA Disabled"
 		message = "Two-factor authentication has been disabled on your account. We recommend re-enabling it."
+	case models.EmailTemplateTypePasswordExpiring:
+		expiresAt, _ := variables["expires_at"].(string)
+		title = "Password Expiring"
+		message = fmt.Sprintf("Your password will expire on %s. Please change it before then to keep access to your account.", expiresAt)
+	case models.EmailTemplateTypeAccountRecovery:
+		event, _ := variables["event"].(string)
+		ipAddress, _ := variables["ip_address"].(string)
+		title = "Account Recovery"
+		message = fmt.Sprintf("Account recovery activity on your account: %s (IP address: %s). If this wasn't you, contact support immediately.", event, ipAddress)
+	case models.EmailTemplateTypeOrgUsageReport:
+		title = "Usage Report"
+		message = fmt.Sprintf("%v: %v of %v licensed seats used, %v active users in the last %v days, %v%% MFA adoption.",
+			variables["organization"], variables["seats_used"], variables["licensed_seats"], variables["active_users"], variables["active_days"], variables["mfa_adoption_rate"])
+	case models.EmailTemplateTypeEmailVerificationReminder:
+		title = "Verify Your Email"
+		message = "Your email address is not verified yet. Request a new verification code to verify it."
+		if deadline, _ := variables["deadline"].(string); deadline != "" {
+			message += fmt.Sprintf(" Verify it before %s to keep access to your account.", deadline)
+		}
+	case models.EmailTemplateTypeAccountDormant:
+		action, _ := variables["action"].(string)
+		enforceAt, _ := variables["enforce_at"].(string)
+		title = "Account Inactive"
+		message = fmt.Sprintf("Your account has not been used for %v days and will be %s on %s. Sign in before then to keep it.", variables["inactive_days"], action, enforceAt)
+	case models.EmailTemplateTypeMagicLink:
+		link, _ := variables["link"].(string)
+		title = "Sign In"
+		message = fmt.Sprintf(`<a href="%s">Click here to sign in</a>. The link expires in %v minutes and works once. If you did not request it, you can ignore this email.`, link, variables["expiry_minutes"])
 	default:
 		title = "Notification"
 		message = "You have a new notification."
@@ -966,6 +1017,10 @@ func (s *EmailProfileService) getDefaultSubject(otpType models.OTPType) string {
 		return "Password Reset Code"
 	case models.OTPType2FA:
 		return "Two-Factor Authentication Code"
+	case models.OTPTypeAccountRecovery:
+		return "Account Recovery Code"
+	case models.OTPTypeSecondaryEmail:
+		return "Confirm Your Email Address"
 	default:
 		return "Verification Code"
 	}
@@ -984,6 +1039,12 @@ func (s *EmailProfileService) getDefaultHTMLBody(otpType models.OTPType, code st
 	case models.OTPType2FA:
 		title = "Two-Factor Authentication"
 		message = "Please use the following code to complete your login:"
+	case models.OTPTypeAccountRecovery:
+		title = "Account Recovery"
+		message = "Please use the following code to confirm this email address for account recovery. If you did not request this, contact support:"
+	case models.OTPTypeSecondaryEmail:
+		title = "Confirm Your Email Address"
+		message = "Please use the following code to add this email address to your account:"
 	default:
 		title = "Verification Code"
 		message = "Please use the following code:"
@@ -2,12 +2,15 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"net/smtp"
 	"strconv"
 
+	"github.com/smilemakc/auth-gateway/internal/chaos"
 	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
 )
 
 // EmailService handles email sending
@@ -19,6 +22,7 @@ type EmailService struct {
 	fromEmail    string
 	fromName     string
 	sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	faults       *chaos.Injector
 }
 
 // NewEmailService creates a new email service
@@ -34,6 +38,11 @@ func NewEmailService(cfg *config.SMTPConfig) *EmailService {
 	}
 }
 
+// SetFaultInjector injects SMTP faults into sent emails (non-production only)
+func (s *EmailService) SetFaultInjector(faults *chaos.Injector) {
+	s.faults = faults
+}
+
 // SendOTP sends an OTP code via email
 func (s *EmailService) SendOTP(to, code, otpType string) error {
 	subject := "Your Verification Code"
@@ -112,6 +121,10 @@ func (s *EmailService) SendOTP(to, code, otpType string) error {
 
 // Send sends an email
 func (s *EmailService) Send(to, subject, htmlBody string) error {
+	if err := s.faults.Inject(context.Background(), models.FaultTargetSMTP); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
 	// If SMTP is not configured, just log (for development)
 	if s.smtpUsername == "" || s.smtpPassword == "" {
 		fmt.Printf("\n=== EMAIL (SMTP not configured, logging instead) ===\n")
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// emailVerificationReminderBatch caps how many reminders of each kind are sent per run
+	emailVerificationReminderBatch = 500
+	// emailVerificationReminderLateDays is how long after the last scheduled reminder a user is
+	// still considered, so a reminder missed while the job was not running is sent late
+	emailVerificationReminderLateDays = 7
+)
+
+// EmailVerificationService enforces the configured email verification level and reminds
+// unverified users to verify their address:
+//   - advisory: unverified users are never restricted
+//   - grace: unverified users sign in with limited access for the grace period, then are blocked
+//   - block: unverified users cannot sign in
+//
+// Users without an email address (phone-only accounts) are never restricted.
+type EmailVerificationService struct {
+	store        EmailVerificationStore
+	notifier     NotificationSender
+	enforcement  string
+	gracePeriod  time.Duration
+	reminderDays []int
+	logger       *logger.Logger
+}
+
+// NewEmailVerificationService creates a new email verification service.
+// reminderDays are the days after sign-up at which reminders are emailed; empty disables reminders.
+func NewEmailVerificationService(store EmailVerificationStore, notifier NotificationSender, enforcement string, gracePeriodDays int, reminderDays []int, logger *logger.Logger) *EmailVerificationService {
+	days := make([]int, 0, len(reminderDays))
+	for _, day := range reminderDays {
+		if day > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+
+	return &EmailVerificationService{
+		store:        store,
+		notifier:     notifier,
+		enforcement:  enforcement,
+		gracePeriod:  time.Duration(gracePeriodDays) * 24 * time.Hour,
+		reminderDays: days,
+		logger:       logger,
+	}
+}
+
+// Enforced reports whether unverified users are restricted (grace or block enforcement)
+func (s *EmailVerificationService) Enforced() bool {
+	return s.enforcement == models.EmailVerificationGrace || s.enforcement == models.EmailVerificationBlock
+}
+
+// CheckSignIn returns ErrEmailNotVerified if the user's unverified email address blocks sign-in
+func (s *EmailVerificationService) CheckSignIn(user *models.User) error {
+	if user.Email == "" || user.EmailVerified {
+		return nil
+	}
+
+	switch s.enforcement {
+	case models.EmailVerificationBlock:
+		return models.ErrEmailNotVerified
+	case models.EmailVerificationGrace:
+		if !time.Now().Before(s.graceEndsAt(user.CreatedAt)) {
+			return models.ErrEmailNotVerified
+		}
+	}
+	return nil
+}
+
+// SendReminders emails unverified users whose next scheduled reminder is due.
+// Returns the number of emails sent.
+func (s *EmailVerificationService) SendReminders(ctx context.Context) (int, error) {
+	if len(s.reminderDays) == 0 || s.notifier == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	oldest := now.AddDate(0, 0, -(s.reminderDays[len(s.reminderDays)-1] + emailVerificationReminderLateDays))
+
+	sent := 0
+	// Latest reminder first: a user who missed several reminders gets only the latest one
+	for i := len(s.reminderDays) - 1; i >= 0; i-- {
+		due := i + 1
+		entries, err := s.store.ListUnverified(ctx, oldest, now.AddDate(0, 0, -s.reminderDays[i]), due, emailVerificationReminderBatch)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, entry := range entries {
+			if err := s.notifier.SendEmail(ctx, nil, nil, entry.Email, models.EmailTemplateTypeEmailVerificationReminder, s.reminderVariables(entry)); err != nil {
+				s.logger.Warn("Failed to send email verification reminder", map[string]interface{}{
+					"user_id": entry.UserID.String(),
+					"error":   err.Error(),
+				})
+				continue
+			}
+			sent++
+
+			if err := s.store.SetRemindersSent(ctx, entry.UserID, due); err != nil {
+				s.logger.Warn("Failed to record email verification reminder", map[string]interface{}{
+					"user_id": entry.UserID.String(),
+					"error":   err.Error(),
+				})
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *EmailVerificationService) reminderVariables(entry *models.EmailVerificationReminderEntry) map[string]interface{} {
+	// Only grace enforcement has a deadline; block already stops sign-in
+	deadline := ""
+	if s.enforcement == models.EmailVerificationGrace {
+		deadline = s.graceEndsAt(entry.CreatedAt).UTC().Format("2006-01-02 15:04 MST")
+	}
+
+	return map[string]interface{}{
+		"username": entry.Username,
+		"email":    entry.Email,
+		"deadline": deadline,
+	}
+}
+
+func (s *EmailVerificationService) graceEndsAt(createdAt time.Time) time.Time {
+	return createdAt.Add(s.gracePeriod)
+}
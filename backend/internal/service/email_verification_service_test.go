@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEmailVerificationStore struct {
+	users map[uuid.UUID]*models.EmailVerificationReminderEntry
+}
+
+func newMockEmailVerificationStore() *mockEmailVerificationStore {
+	return &mockEmailVerificationStore{users: make(map[uuid.UUID]*models.EmailVerificationReminderEntry)}
+}
+
+func (m *mockEmailVerificationStore) ListUnverified(ctx context.Context, createdAfter, createdBefore time.Time, maxReminders, limit int) ([]*models.EmailVerificationReminderEntry, error) {
+	var entries []*models.EmailVerificationReminderEntry
+	for _, entry := range m.users {
+		if entry.CreatedAt.Before(createdAfter) || entry.CreatedAt.After(createdBefore) || entry.RemindersSent >= maxReminders {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (m *mockEmailVerificationStore) SetRemindersSent(ctx context.Context, userID uuid.UUID, count int) error {
+	m.users[userID].RemindersSent = count
+	return nil
+}
+
+func TestEmailVerificationService_CheckSignIn(t *testing.T) {
+	recent := time.Now().Add(-24 * time.Hour)
+	old := time.Now().AddDate(0, 0, -10)
+
+	tests := []struct {
+		name        string
+		enforcement string
+		user        *models.User
+		want        error
+	}{
+		{"advisory never blocks", models.EmailVerificationAdvisory, &models.User{Email: "a@example.com", CreatedAt: old}, nil},
+		{"block rejects unverified", models.EmailVerificationBlock, &models.User{Email: "a@example.com", CreatedAt: recent}, models.ErrEmailNotVerified},
+		{"block allows verified", models.EmailVerificationBlock, &models.User{Email: "a@example.com", EmailVerified: true}, nil},
+		{"block ignores phone-only users", models.EmailVerificationBlock, &models.User{Email: ""}, nil},
+		{"grace allows within period", models.EmailVerificationGrace, &models.User{Email: "a@example.com", CreatedAt: recent}, nil},
+		{"grace rejects after period", models.EmailVerificationGrace, &models.User{Email: "a@example.com", CreatedAt: old}, models.ErrEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewEmailVerificationService(nil, nil, tt.enforcement, 7, nil, nil)
+			assert.Equal(t, tt.want, svc.CheckSignIn(tt.user))
+		})
+	}
+}
+
+func TestEmailVerificationService_Enforced(t *testing.T) {
+	assert.False(t, NewEmailVerificationService(nil, nil, models.EmailVerificationAdvisory, 7, nil, nil).Enforced())
+	assert.True(t, NewEmailVerificationService(nil, nil, models.EmailVerificationGrace, 7, nil, nil).Enforced())
+	assert.True(t, NewEmailVerificationService(nil, nil, models.EmailVerificationBlock, 7, nil, nil).Enforced())
+}
+
+func TestEmailVerificationService_SendReminders(t *testing.T) {
+	store := newMockEmailVerificationStore()
+	notifier := &mockNotificationSender{}
+	svc := NewEmailVerificationService(store, notifier, models.EmailVerificationGrace, 7, []int{3, 1, 0}, logger.New("test", logger.InfoLevel, false))
+	ctx := context.Background()
+
+	add := func(email string, age time.Duration, sent int) *models.EmailVerificationReminderEntry {
+		entry := &models.EmailVerificationReminderEntry{UserID: uuid.New(), Email: email, CreatedAt: time.Now().Add(-age), RemindersSent: sent}
+		store.users[entry.UserID] = entry
+		return entry
+	}
+
+	day := 24 * time.Hour
+	fresh := add("fresh@example.com", time.Hour, 0)
+	first := add("first@example.com", 2*day, 0)
+	reminded := add("reminded@example.com", 2*day, 1)
+	missed := add("missed@example.com", 4*day, 0)
+	done := add("done@example.com", 4*day, 2)
+	add("stale@example.com", 30*day, 0)
+
+	sent, err := svc.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.ElementsMatch(t, []string{
+		"first@example.com:" + models.EmailTemplateTypeEmailVerificationReminder,
+		"missed@example.com:" + models.EmailTemplateTypeEmailVerificationReminder,
+	}, notifier.sent)
+
+	assert.Equal(t, 0, fresh.RemindersSent)
+	assert.Equal(t, 1, first.RemindersSent)
+	assert.Equal(t, 1, reminded.RemindersSent)
+	assert.Equal(t, 2, missed.RemindersSent, "missed reminders are skipped, not sent back-to-back")
+	assert.Equal(t, 2, done.RemindersSent)
+
+	// Nothing is due on the next run
+	sent, err = svc.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/eventbus"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const eventPublishJob = "event.publish"
+
+// eventPublishJobPayload is a queued event, encoded once so every attempt publishes the
+// same bytes
+type eventPublishJobPayload struct {
+	Topic   string          `json:"topic"`
+	Key     string          `json:"key,omitempty"`
+	EventID string          `json:"event_id"`
+	Event   json.RawMessage `json:"event"`
+}
+
+// EventBusService publishes auth events to Kafka or NATS, alongside webhooks. Events go
+// through the background job queue: PublishEvent stores a job, and the job is retried until
+// the broker accepts the event, so each event is delivered at least once. Retries can
+// reorder events and deliver one twice; consumers deduplicate on the event ID.
+type EventBusService struct {
+	publisher    eventbus.Publisher
+	jobs         *JobQueueService
+	defaultTopic string
+	topics       map[string]string
+	maxAttempts  int
+	logger       *logger.Logger
+	now          func() time.Time
+}
+
+// NewEventBusService creates an event bus service publishing through publisher. Events go to
+// the topic topics maps their type to, or to defaultTopic; a job gets maxAttempts attempts
+// to publish one, 0 meaning the queue's default.
+func NewEventBusService(publisher eventbus.Publisher, jobs *JobQueueService, defaultTopic string, topics map[string]string, maxAttempts int, log *logger.Logger) *EventBusService {
+	s := &EventBusService{
+		publisher:    publisher,
+		jobs:         jobs,
+		defaultTopic: defaultTopic,
+		topics:       topics,
+		maxAttempts:  maxAttempts,
+		logger:       log,
+		now:          time.Now,
+	}
+	jobs.Register(eventPublishJob, s.runPublishJob)
+	return s
+}
+
+// PublishEvent queues an event of eventType for publishing. key identifies the entity the
+// event is about, usually the user ID; Kafka keeps the events of a key in one partition.
+func (s *EventBusService) PublishEvent(ctx context.Context, eventType, key string, data map[string]interface{}) error {
+	event := models.Event{
+		SchemaVersion: models.EventSchemaVersion,
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		Source:        models.EventSource,
+		OccurredAt:    s.now().UTC(),
+		Data:          data,
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	_, err = s.jobs.Enqueue(ctx, eventPublishJob, eventPublishJobPayload{
+		Topic:   s.topicFor(eventType),
+		Key:     key,
+		EventID: event.ID,
+		Event:   encoded,
+	}, s.maxAttempts)
+	if err != nil {
+		s.logger.Error("failed to queue event", map[string]interface{}{
+			"event_type": eventType,
+			"event_id":   event.ID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to queue event: %w", err)
+	}
+	return nil
+}
+
+// topicFor returns the topic events of eventType are published to
+func (s *EventBusService) topicFor(eventType string) string {
+	if topic, ok := s.topics[eventType]; ok {
+		return topic
+	}
+	return s.defaultTopic
+}
+
+// runPublishJob publishes a queued event; failures are retried by the job queue
+func (s *EventBusService) runPublishJob(ctx context.Context, job *models.BackgroundJob) error {
+	var payload eventPublishJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return PermanentJobError(fmt.Errorf("invalid event publish job: %w", err))
+	}
+
+	if err := s.publisher.Publish(ctx, payload.Topic, payload.Key, payload.EventID, payload.Event); err != nil {
+		return fmt.Errorf("failed to publish event to %s topic %s: %w", s.publisher.Name(), payload.Topic, err)
+	}
+	return nil
+}
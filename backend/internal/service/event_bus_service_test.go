@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/internal/eventbus"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishedEvent is a message received by mockEventPublisher
+type publishedEvent struct {
+	topic, key, id string
+	payload        []byte
+}
+
+type mockEventPublisher struct {
+	mu        sync.Mutex
+	published []publishedEvent
+	err       error
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, topic, key, id string, payload []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.published = append(m.published, publishedEvent{topic: topic, key: key, id: id, payload: payload})
+	return nil
+}
+
+func (m *mockEventPublisher) Name() string { return "mock" }
+func (m *mockEventPublisher) Close() error { return nil }
+
+func setupEventBusService() (*EventBusService, *mockEventPublisher, *JobQueueService, *mockBackgroundJobStore) {
+	jobs, store := setupJobQueueService()
+	publisher := &mockEventPublisher{}
+	svc := NewEventBusService(publisher, jobs, "auth.events", map[string]string{
+		models.EventTokenRevoked: "auth.tokens",
+	}, 8, logger.New("test", logger.ErrorLevel, false))
+	svc.now = jobs.now
+	return svc, publisher, jobs, store
+}
+
+func TestEventBusService_PublishEvent(t *testing.T) {
+	svc, publisher, jobs, store := setupEventBusService()
+
+	err := svc.PublishEvent(context.Background(), models.EventUserSignedIn, "user-1", map[string]interface{}{"auth_method": "password"})
+	require.NoError(t, err)
+	require.NoError(t, svc.PublishEvent(context.Background(), models.EventTokenRevoked, "user-1", nil))
+
+	require.Len(t, store.created, 2)
+	assert.Equal(t, eventPublishJob, store.created[0].Type)
+	assert.Equal(t, 8, store.created[0].MaxAttempts)
+
+	// The queued jobs publish the events
+	store.due = store.created
+	assert.Equal(t, 2, jobs.RunDue(context.Background()))
+	require.Len(t, publisher.published, 2)
+
+	// Jobs run concurrently, so events are told apart by type
+	byType := make(map[string]publishedEvent)
+	events := make(map[string]models.Event)
+	for _, published := range publisher.published {
+		var event models.Event
+		require.NoError(t, json.Unmarshal(published.payload, &event))
+		byType[event.Type] = published
+		events[event.Type] = event
+	}
+
+	signedIn := byType[models.EventUserSignedIn]
+	event := events[models.EventUserSignedIn]
+	assert.Equal(t, "auth.events", signedIn.topic)
+	assert.Equal(t, "user-1", signedIn.key)
+	assert.Equal(t, models.EventSchemaVersion, event.SchemaVersion)
+	assert.Equal(t, signedIn.id, event.ID)
+	assert.Equal(t, models.EventSource, event.Source)
+	assert.Equal(t, svc.now().UTC(), event.OccurredAt)
+	assert.Equal(t, "password", event.Data["auth_method"])
+
+	assert.Equal(t, "auth.tokens", byType[models.EventTokenRevoked].topic)
+}
+
+func TestEventBusService_RetriesFailedPublish(t *testing.T) {
+	svc, publisher, jobs, store := setupEventBusService()
+	publisher.err = eventbus.ErrUnavailable
+
+	require.NoError(t, svc.PublishEvent(context.Background(), models.EventUserRoleChanged, "user-1", nil))
+	store.due = store.created
+	jobs.RunDue(context.Background())
+
+	require.Len(t, store.updated, 1)
+	assert.Equal(t, models.BackgroundJobPending, store.updated[0].Status)
+	assert.Contains(t, store.updated[0].LastError, "event bus unavailable")
+}
+
+func TestEventBusService_RejectsInvalidJob(t *testing.T) {
+	svc, _, _, _ := setupEventBusService()
+
+	err := svc.runPublishJob(context.Background(), &models.BackgroundJob{Payload: []byte(`not json`)})
+
+	var jobErr *jobError
+	require.True(t, errors.As(err, &jobErr))
+	assert.True(t, jobErr.permanent)
+}
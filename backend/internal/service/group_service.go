@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
@@ -9,6 +11,11 @@ import (
 	"github.com/smilemakc/auth-gateway/pkg/logger"
 )
 
+var (
+	errGroupNotFound    = models.NewAppError(http.StatusNotFound, "Group not found")
+	errGroupRoleUnknown = models.NewAppError(http.StatusBadRequest, "One or more roles do not exist")
+)
+
 // GroupService handles group-related business logic
 type GroupService struct {
 	groupRepo GroupRepository
@@ -29,6 +36,8 @@ type GroupRepository interface {
 	GetGroupMembers(ctx context.Context, groupID uuid.UUID, page, pageSize int) ([]*models.User, int, error)
 	GetUserGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error)
 	GetGroupMemberCount(ctx context.Context, groupID uuid.UUID) (int, error)
+	GetGroupRoles(ctx context.Context, groupID uuid.UUID) ([]models.Role, error)
+	SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error
 }
 
 // NewGroupService creates a new group service
@@ -215,6 +224,83 @@ func (s *GroupService) GetGroupMemberCount(ctx context.Context, groupID uuid.UUI
 	return s.groupRepo.GetGroupMemberCount(ctx, groupID)
 }
 
+// GetGroupRoles returns the roles a group grants to its members, split into the roles
+// mapped to the group itself and those inherited from its parent groups
+func (s *GroupService) GetGroupRoles(ctx context.Context, groupID uuid.UUID) (*models.GroupRolesResponse, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errGroupNotFound
+		}
+		return nil, err
+	}
+
+	roles, err := s.groupRepo.GetGroupRoles(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.GroupRolesResponse{
+		GroupID:        groupID,
+		Roles:          roles,
+		InheritedRoles: []models.Role{},
+	}
+	if resp.Roles == nil {
+		resp.Roles = []models.Role{}
+	}
+
+	seenRoles := make(map[uuid.UUID]bool, len(roles))
+	for _, role := range roles {
+		seenRoles[role.ID] = true
+	}
+	visited := map[uuid.UUID]bool{groupID: true}
+	for parentID := group.ParentGroupID; parentID != nil && *parentID != uuid.Nil && !visited[*parentID]; {
+		visited[*parentID] = true
+		parent, err := s.groupRepo.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		parentRoles, err := s.groupRepo.GetGroupRoles(ctx, parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range parentRoles {
+			if !seenRoles[role.ID] {
+				seenRoles[role.ID] = true
+				resp.InheritedRoles = append(resp.InheritedRoles, role)
+			}
+		}
+		parentID = parent.ParentGroupID
+	}
+
+	return resp, nil
+}
+
+// SetGroupRoles replaces the roles mapped to a group. Members of the group and of its
+// child groups gain the new roles and lose the ones no group grants them anymore.
+func (s *GroupService) SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error) {
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errGroupNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.groupRepo.SetGroupRoles(ctx, groupID, uniqueUUIDs(roleIDs)); err != nil {
+		if errors.Is(err, models.ErrForeignKeyViolation) {
+			return nil, errGroupRoleUnknown
+		}
+		return nil, err
+	}
+
+	s.logger.Info("group roles updated", map[string]interface{}{
+		"group_id": groupID.String(),
+		"roles":    len(roleIDs),
+	})
+
+	return s.GetGroupRoles(ctx, groupID)
+}
+
 // EvaluateDynamicGroupMembers evaluates dynamic group membership rules and returns matching user IDs
 func (s *GroupService) EvaluateDynamicGroupMembers(ctx context.Context, group *models.Group, allUsers []*models.User) []uuid.UUID {
 	if !group.IsDynamic || group.MembershipRules == nil {
@@ -16,8 +16,10 @@ type mockGroupStore struct {
 	groups       map[uuid.UUID]*models.Group
 	groupsByName map[string]*models.Group
 	users        map[uuid.UUID][]uuid.UUID // groupID -> userIDs
+	roles        map[uuid.UUID][]models.Role
 	createFunc   func(ctx context.Context, group *models.Group) error
 	getByIDFunc  func(ctx context.Context, id uuid.UUID) (*models.Group, error)
+	setRolesFunc func(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error
 }
 
 func newMockGroupStore() *mockGroupStore {
@@ -25,6 +27,7 @@ func newMockGroupStore() *mockGroupStore {
 		groups:       make(map[uuid.UUID]*models.Group),
 		groupsByName: make(map[string]*models.Group),
 		users:        make(map[uuid.UUID][]uuid.UUID),
+		roles:        make(map[uuid.UUID][]models.Role),
 	}
 }
 
@@ -135,6 +138,22 @@ func (m *mockGroupStore) GetGroupMemberCount(ctx context.Context, groupID uuid.U
 	return len(m.users[groupID]), nil
 }
 
+func (m *mockGroupStore) GetGroupRoles(ctx context.Context, groupID uuid.UUID) ([]models.Role, error) {
+	return m.roles[groupID], nil
+}
+
+func (m *mockGroupStore) SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	if m.setRolesFunc != nil {
+		return m.setRolesFunc(ctx, groupID, roleIDs)
+	}
+	roles := make([]models.Role, len(roleIDs))
+	for i, id := range roleIDs {
+		roles[i] = models.Role{ID: id}
+	}
+	m.roles[groupID] = roles
+	return nil
+}
+
 func setupGroupService() (*GroupService, *mockGroupStore, *mockUserStore) {
 	mGroup := newMockGroupStore()
 	mUser := &mockUserStore{}
@@ -289,6 +308,87 @@ func TestGroupService_RemoveGroupMember(t *testing.T) {
 	})
 }
 
+func TestGroupService_GetGroupRoles_ShouldIncludeRolesOfParentGroups(t *testing.T) {
+	svc, mGroup, _ := setupGroupService()
+	ctx := context.Background()
+
+	root, err := svc.CreateGroup(ctx, &models.CreateGroupRequest{Name: "company", DisplayName: "Company"})
+	require.NoError(t, err)
+	dept, err := svc.CreateGroup(ctx, &models.CreateGroupRequest{Name: "engineering", DisplayName: "Engineering", ParentGroupID: &root.ID})
+	require.NoError(t, err)
+	team, err := svc.CreateGroup(ctx, &models.CreateGroupRequest{Name: "platform", DisplayName: "Platform", ParentGroupID: &dept.ID})
+	require.NoError(t, err)
+
+	employee := models.Role{ID: uuid.New(), Name: "employee"}
+	developer := models.Role{ID: uuid.New(), Name: "developer"}
+	oncall := models.Role{ID: uuid.New(), Name: "oncall"}
+	mGroup.roles[root.ID] = []models.Role{employee, developer}
+	mGroup.roles[dept.ID] = []models.Role{developer}
+	mGroup.roles[team.ID] = []models.Role{oncall}
+
+	resp, err := svc.GetGroupRoles(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Role{oncall}, resp.Roles)
+	assert.Equal(t, []models.Role{developer, employee}, resp.InheritedRoles)
+
+	// A cycle in the hierarchy must not loop forever
+	root.ParentGroupID = &team.ID
+	resp, err = svc.GetGroupRoles(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, resp.InheritedRoles, 2)
+}
+
+func TestGroupService_GetGroupRoles_ShouldReturnNotFound_WhenGroupMissing(t *testing.T) {
+	svc, _, _ := setupGroupService()
+
+	_, err := svc.GetGroupRoles(context.Background(), uuid.New())
+
+	assert.Equal(t, errGroupNotFound, err)
+}
+
+func TestGroupService_SetGroupRoles_ShouldStoreUniqueRoles(t *testing.T) {
+	svc, mGroup, _ := setupGroupService()
+	ctx := context.Background()
+
+	group, err := svc.CreateGroup(ctx, &models.CreateGroupRequest{Name: "admins", DisplayName: "Admins"})
+	require.NoError(t, err)
+
+	roleID := uuid.New()
+	resp, err := svc.SetGroupRoles(ctx, group.ID, []uuid.UUID{roleID, roleID})
+	require.NoError(t, err)
+	require.Len(t, resp.Roles, 1)
+	assert.Equal(t, roleID, resp.Roles[0].ID)
+	assert.Empty(t, resp.InheritedRoles)
+	assert.Len(t, mGroup.roles[group.ID], 1)
+}
+
+func TestGroupService_SetGroupRoles_ShouldMapUnknownRoleToBadRequest(t *testing.T) {
+	svc, mGroup, _ := setupGroupService()
+	ctx := context.Background()
+
+	group, err := svc.CreateGroup(ctx, &models.CreateGroupRequest{Name: "admins", DisplayName: "Admins"})
+	require.NoError(t, err)
+	mGroup.setRolesFunc = func(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+		return models.ErrForeignKeyViolation
+	}
+
+	_, err = svc.SetGroupRoles(ctx, group.ID, []uuid.UUID{uuid.New()})
+
+	assert.Equal(t, errGroupRoleUnknown, err)
+}
+
+func TestGroupService_SetGroupRoles_ShouldReturnNotFound_WhenGroupMissing(t *testing.T) {
+	svc, mGroup, _ := setupGroupService()
+	mGroup.setRolesFunc = func(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+		t.Fatal("roles should not be stored")
+		return nil
+	}
+
+	_, err := svc.SetGroupRoles(context.Background(), uuid.New(), nil)
+
+	assert.Equal(t, errGroupNotFound, err)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// guestCleanupBatch caps how many expired guests are deleted per statement
+const guestCleanupBatch = 500
+
+var errNotGuest = models.NewAppError(http.StatusConflict, "Account is not a guest account")
+
+// GuestServiceOptions contains optional dependencies of GuestService
+type GuestServiceOptions struct {
+	TokenVersions     TokenVersionChecker
+	SignupPolicy      SignupEnforcer
+	EmailVerification EmailVerificationEnforcer
+	PasswordChecker   *PasswordChecker
+	// PasswordPolicy replaces the static password policy and PasswordChecker when set
+	PasswordPolicy PasswordPolicyEnforcer
+}
+
+// GuestService issues anonymous guest accounts for try-before-signup flows. A guest has no
+// credentials and its tokens carry the is_guest claim. Upgrading sets credentials on the same
+// account, so the user ID and everything linked to it are kept. Guests that are not upgraded
+// within the retention period are deleted.
+type GuestService struct {
+	store             GuestStore
+	userRepo          UserStore
+	rbacRepo          RBACStore
+	sessions          SessionIssuer
+	auditService      AuditLogger
+	tokenVersions     TokenVersionChecker
+	signupPolicy      SignupEnforcer
+	emailVerification EmailVerificationEnforcer
+	passwordChecker   *PasswordChecker
+	passwordEnforcer  PasswordPolicyEnforcer
+	bcryptCost        int
+	passwordPolicy    utils.PasswordPolicy
+	retention         time.Duration
+	logger            *logger.Logger
+}
+
+// NewGuestService creates a new guest service.
+// retentionDays of 0 keeps guest accounts until they are upgraded.
+func NewGuestService(
+	store GuestStore,
+	userRepo UserStore,
+	rbacRepo RBACStore,
+	sessions SessionIssuer,
+	auditService AuditLogger,
+	bcryptCost int,
+	passwordPolicy utils.PasswordPolicy,
+	retentionDays int,
+	logger *logger.Logger,
+	opts GuestServiceOptions,
+) *GuestService {
+	return &GuestService{
+		store:             store,
+		userRepo:          userRepo,
+		rbacRepo:          rbacRepo,
+		sessions:          sessions,
+		auditService:      auditService,
+		tokenVersions:     opts.TokenVersions,
+		signupPolicy:      opts.SignupPolicy,
+		emailVerification: opts.EmailVerification,
+		passwordChecker:   opts.PasswordChecker,
+		passwordEnforcer:  opts.PasswordPolicy,
+		bcryptCost:        bcryptCost,
+		passwordPolicy:    passwordPolicy,
+		retention:         time.Duration(retentionDays) * 24 * time.Hour,
+		logger:            logger,
+	}
+}
+
+// CreateGuest creates a guest account with the default role and signs it in
+func (s *GuestService) CreateGuest(ctx context.Context, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+	if err := s.sessions.CheckAuthMethodAllowed(ctx, appID, models.AuthMethodGuest); err != nil {
+		return nil, err
+	}
+
+	defaultRole, err := s.rbacRepo.GetRoleByName(ctx, "user")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default role: %w", err)
+	}
+
+	id := uuid.New()
+	user := &models.User{
+		ID:          id,
+		Username:    models.GuestUsernamePrefix + strings.ReplaceAll(id.String(), "-", ""),
+		AccountType: string(models.AccountTypeHuman),
+		IsActive:    true,
+		IsGuest:     true,
+	}
+	if err := s.store.Create(ctx, user, defaultRole.ID); err != nil {
+		return nil, err
+	}
+
+	user, err = s.userRepo.GetByID(ctx, id, utils.Ptr(true), UserGetWithRoles())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload guest with roles: %w", err)
+	}
+
+	authResp, err := s.sessions.IssueSession(ctx, user, ip, userAgent, deviceInfo, appID, true, models.AuthMethodGuest)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(&user.ID, appID, models.ActionGuestCreate, models.StatusSuccess, ip, userAgent, nil)
+
+	return authResp, nil
+}
+
+// UpgradeGuest sets credentials on a guest account, turning it into a full account with the
+// same ID. Tokens issued to the guest are revoked and new ones are returned, unless the email
+// address must be verified first.
+func (s *GuestService) UpgradeGuest(ctx context.Context, userID uuid.UUID, req *models.UpgradeGuestRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID, utils.Ptr(true))
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsGuest {
+		return nil, errNotGuest
+	}
+
+	if req.Email == "" && (req.Phone == nil || *req.Phone == "") {
+		return nil, models.NewAppError(http.StatusBadRequest, "Either email or phone is required")
+	}
+
+	var email, phone string
+	if req.Email != "" {
+		email = utils.NormalizeEmail(req.Email)
+		if err := utils.ValidateEmail(email); err != nil {
+			return nil, models.NewAppError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if req.Phone != nil && *req.Phone != "" {
+		phone = utils.NormalizePhone(*req.Phone)
+		if !utils.IsValidPhone(phone) {
+			return nil, models.NewAppError(http.StatusBadRequest, "Invalid phone format")
+		}
+	}
+
+	username := utils.NormalizeUsername(req.Username)
+	if !utils.IsValidUsername(username) {
+		return nil, models.NewAppError(http.StatusBadRequest, "Invalid username format")
+	}
+
+	if err := s.checkPassword(ctx, req.Password); err != nil {
+		return nil, err
+	}
+
+	if s.signupPolicy != nil {
+		if err := s.signupPolicy.CheckSignup(ctx, email); err != nil {
+			s.logAudit(&user.ID, appID, models.ActionGuestUpgrade, models.StatusFailed, ip, userAgent, map[string]interface{}{
+				"reason": "signup_restricted",
+				"email":  email,
+			})
+			return nil, err
+		}
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password, s.bcryptCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	user.Email = email
+	user.Phone = nil
+	if phone != "" {
+		user.Phone = &phone
+	}
+	user.Username = utils.SanitizeUsername(username)
+	user.PasswordHash = passwordHash
+	user.PasswordChangedAt = &now
+	user.FullName = utils.SanitizeHTML(req.FullName)
+
+	if err := s.store.Upgrade(ctx, user); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errNotGuest
+		}
+		return nil, err
+	}
+
+	// Guest tokens carry the is_guest claim and must not outlive the upgrade
+	if s.tokenVersions != nil {
+		if _, err := s.tokenVersions.Bump(ctx, user.ID); err != nil {
+			s.logger.Warn("Failed to revoke guest tokens after upgrade", map[string]interface{}{
+				"user_id": user.ID.String(),
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	s.logAudit(&user.ID, appID, models.ActionGuestUpgrade, models.StatusSuccess, ip, userAgent, map[string]interface{}{
+		"email": email,
+		"phone": phone,
+	})
+
+	user, err = s.userRepo.GetByID(ctx, user.ID, utils.Ptr(true), UserGetWithRoles())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload user with roles: %w", err)
+	}
+
+	if s.emailVerification != nil && s.emailVerification.CheckSignIn(user) != nil {
+		return &models.AuthResponse{
+			RequiresEmailVerification: true,
+			User:                      user.PublicUser(),
+		}, nil
+	}
+
+	return s.sessions.IssueSession(ctx, user, ip, userAgent, deviceInfo, appID, false, "password")
+}
+
+// DeleteExpired deletes guest accounts older than the retention period.
+// Returns the number of accounts deleted.
+func (s *GuestService) DeleteExpired(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-s.retention)
+	total := 0
+	for {
+		deleted, err := s.store.DeleteCreatedBefore(ctx, before, guestCleanupBatch)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if deleted < guestCleanupBatch {
+			return total, nil
+		}
+	}
+}
+
+// checkPassword applies the password policy to the password a guest upgrades with
+func (s *GuestService) checkPassword(ctx context.Context, password string) error {
+	if s.passwordEnforcer != nil {
+		return s.passwordEnforcer.CheckPassword(ctx, password, nil)
+	}
+
+	if err := utils.ValidatePassword(password, s.passwordPolicy); err != nil {
+		return models.NewAppError(http.StatusBadRequest, err.Error())
+	}
+	if s.passwordChecker != nil {
+		if compromised, count := s.passwordChecker.IsCompromised(ctx, password); compromised {
+			return models.NewAppError(http.StatusBadRequest, "PASSWORD_COMPROMISED",
+				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
+		}
+	}
+	return nil
+}
+
+func (s *GuestService) logAudit(userID *uuid.UUID, appID *uuid.UUID, action models.AuditAction, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
+	s.auditService.Log(AuditLogParams{
+		UserID:        userID,
+		ApplicationID: appID,
+		Action:        action,
+		Status:        status,
+		IP:            ip,
+		UserAgent:     userAgent,
+		Details:       details,
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGuestStore struct {
+	users   map[uuid.UUID]*models.User
+	roles   map[uuid.UUID]uuid.UUID
+	deleted []time.Time
+	expired int
+}
+
+func newMockGuestStore() *mockGuestStore {
+	return &mockGuestStore{users: make(map[uuid.UUID]*models.User), roles: make(map[uuid.UUID]uuid.UUID)}
+}
+
+func (m *mockGuestStore) Create(ctx context.Context, user *models.User, roleID uuid.UUID) error {
+	m.users[user.ID] = user
+	m.roles[user.ID] = roleID
+	return nil
+}
+
+func (m *mockGuestStore) Upgrade(ctx context.Context, user *models.User) error {
+	stored, ok := m.users[user.ID]
+	if !ok || !stored.IsGuest {
+		return models.ErrNotFound
+	}
+	upgraded := *user
+	upgraded.IsGuest = false
+	m.users[user.ID] = &upgraded
+	return nil
+}
+
+func (m *mockGuestStore) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.deleted = append(m.deleted, before)
+	deleted := m.expired
+	if deleted > limit {
+		deleted = limit
+	}
+	m.expired -= deleted
+	return deleted, nil
+}
+
+type mockSessionIssuer struct {
+	allowed bool
+	issued  []*models.User
+}
+
+func (m *mockSessionIssuer) IssueSession(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, isNewUser bool, authMethod string) (*models.AuthResponse, error) {
+	m.issued = append(m.issued, user)
+	return &models.AuthResponse{AccessToken: "access", RefreshToken: "refresh", User: user.PublicUser()}, nil
+}
+
+func (m *mockSessionIssuer) CheckAuthMethodAllowed(ctx context.Context, appID *uuid.UUID, method string) error {
+	if !m.allowed {
+		return models.NewAppError(403, "Auth method '"+method+"' is not allowed for this application")
+	}
+	return nil
+}
+
+func newTestGuestService(opts GuestServiceOptions) (*GuestService, *mockGuestStore, *mockSessionIssuer) {
+	store := newMockGuestStore()
+	sessions := &mockSessionIssuer{allowed: true}
+	userRepo := &mockUserStore{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			user, ok := store.users[id]
+			if !ok {
+				return nil, models.ErrUserNotFound
+			}
+			copied := *user
+			return &copied, nil
+		},
+	}
+	roleID := uuid.New()
+	rbacRepo := &mockRBACStore{
+		GetRoleByNameFunc: func(ctx context.Context, name string) (*models.Role, error) {
+			return &models.Role{ID: roleID, Name: name}, nil
+		},
+	}
+	svc := NewGuestService(store, userRepo, rbacRepo, sessions, &mockAuditLogger{}, 4, utils.PasswordPolicy{MinLength: 8}, 30, logger.New("test", logger.InfoLevel, false), opts)
+	return svc, store, sessions
+}
+
+func TestGuestService_CreateGuest(t *testing.T) {
+	svc, store, sessions := newTestGuestService(GuestServiceOptions{})
+
+	resp, err := svc.CreateGuest(context.Background(), "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp.User)
+	assert.True(t, resp.User.IsGuest)
+	assert.Empty(t, resp.User.Email)
+	assert.True(t, strings.HasPrefix(resp.User.Username, models.GuestUsernamePrefix))
+	assert.Len(t, store.users, 1)
+	assert.Len(t, sessions.issued, 1)
+
+	sessions.allowed = false
+	_, err = svc.CreateGuest(context.Background(), "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	assert.Error(t, err)
+	assert.Len(t, store.users, 1)
+}
+
+func TestGuestService_UpgradeGuest_ShouldKeepUserID(t *testing.T) {
+	versions := &mockTokenVersionChecker{}
+	svc, store, sessions := newTestGuestService(GuestServiceOptions{TokenVersions: versions})
+	ctx := context.Background()
+
+	guest, err := svc.CreateGuest(ctx, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+
+	resp, err := svc.UpgradeGuest(ctx, guest.User.ID, &models.UpgradeGuestRequest{
+		Email:    "New.User@Example.com",
+		Username: "NewUser",
+		Password: "password123",
+	}, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, guest.User.ID, resp.User.ID)
+	assert.False(t, resp.User.IsGuest)
+	assert.Equal(t, "new.user@example.com", resp.User.Email)
+	assert.Equal(t, "newuser", resp.User.Username)
+	assert.NoError(t, utils.CheckPassword(store.users[guest.User.ID].PasswordHash, "password123"))
+	assert.Equal(t, []uuid.UUID{guest.User.ID}, versions.bumped)
+	assert.Len(t, sessions.issued, 2)
+
+	_, err = svc.UpgradeGuest(ctx, guest.User.ID, &models.UpgradeGuestRequest{
+		Email:    "other@example.com",
+		Username: "other",
+		Password: "password123",
+	}, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	assert.Equal(t, errNotGuest, err)
+}
+
+func TestGuestService_UpgradeGuest_ShouldRequireEmailVerification(t *testing.T) {
+	enforcer := NewEmailVerificationService(nil, nil, models.EmailVerificationBlock, 0, nil, logger.New("test", logger.InfoLevel, false))
+	svc, _, sessions := newTestGuestService(GuestServiceOptions{EmailVerification: enforcer})
+	ctx := context.Background()
+
+	guest, err := svc.CreateGuest(ctx, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+
+	resp, err := svc.UpgradeGuest(ctx, guest.User.ID, &models.UpgradeGuestRequest{
+		Email:    "user@example.com",
+		Username: "user",
+		Password: "password123",
+	}, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+	assert.True(t, resp.RequiresEmailVerification)
+	assert.Empty(t, resp.AccessToken)
+	assert.Len(t, sessions.issued, 1)
+}
+
+func TestGuestService_UpgradeGuest_ShouldRejectMissingIdentifier(t *testing.T) {
+	svc, _, _ := newTestGuestService(GuestServiceOptions{})
+	ctx := context.Background()
+
+	guest, err := svc.CreateGuest(ctx, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.NoError(t, err)
+
+	_, err = svc.UpgradeGuest(ctx, guest.User.ID, &models.UpgradeGuestRequest{Username: "user", Password: "password123"}, "127.0.0.1", "test", models.DeviceInfo{}, nil)
+	require.Error(t, err)
+	appErr, ok := err.(*models.AppError)
+	require.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestGuestService_DeleteExpired(t *testing.T) {
+	svc, store, _ := newTestGuestService(GuestServiceOptions{})
+	store.expired = guestCleanupBatch + 3
+
+	deleted, err := svc.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, guestCleanupBatch+3, deleted)
+	require.Len(t, store.deleted, 2)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), store.deleted[0], time.Minute)
+
+	svc.retention = 0
+	deleted, err = svc.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	assert.Len(t, store.deleted, 2)
+}
@@ -28,6 +28,9 @@ type UserStore interface {
 	List(ctx context.Context, opts ...UserListOption) ([]*models.User, error)
 	Count(ctx context.Context, isActive *bool) (int, error)
 	GetUsersUpdatedAfter(ctx context.Context, after time.Time, appID *uuid.UUID, limit, offset int) ([]*models.User, int, error)
+	Search(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error)
+	Filter(ctx context.Context, limit, offset int, opts ...UserFilterOption) ([]*models.User, error)
+	CountFiltered(ctx context.Context, opts ...UserFilterOption) (int, error)
 	// 2FA methods
 	UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
 	EnableTOTP(ctx context.Context, userID uuid.UUID) error
@@ -63,13 +66,22 @@ type BackupCodeStore interface {
 	DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
+// WebAuthnCredentialStore defines the interface for WebAuthn security key storage
+type WebAuthnCredentialStore interface {
+	Create(ctx context.Context, credential *models.WebAuthnCredential) error
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error)
+	RecordUse(ctx context.Context, id uuid.UUID, signCount uint32, backupState bool, usedAt time.Time) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
 // PermissionRepository handles permission CRUD operations
 type PermissionRepository interface {
 	CreatePermission(ctx context.Context, permission *models.Permission) error
 	GetPermissionByID(ctx context.Context, id uuid.UUID) (*models.Permission, error)
 	GetPermissionByName(ctx context.Context, name string) (*models.Permission, error)
 	ListPermissions(ctx context.Context) ([]models.Permission, error)
-	UpdatePermission(ctx context.Context, id uuid.UUID, description string) error
+	UpdatePermission(ctx context.Context, id uuid.UUID, description string, condition *string) error
 	DeletePermission(ctx context.Context, id uuid.UUID) error
 	ListPermissionsByApp(ctx context.Context, appID *uuid.UUID) ([]models.Permission, error)
 }
@@ -87,6 +99,25 @@ type RoleRepository interface {
 	ListRolesByApp(ctx context.Context, appID *uuid.UUID) ([]models.Role, error)
 }
 
+// RoleHierarchyRepository handles role inheritance and the bundles granted to roles
+type RoleHierarchyRepository interface {
+	ListRoleParents(ctx context.Context) ([]models.RoleParent, error)
+	SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error
+	ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error)
+	SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error
+}
+
+// PermissionBundleRepository handles permission bundle CRUD operations
+type PermissionBundleRepository interface {
+	CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error
+	GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error)
+	GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error)
+	ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error)
+	UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error
+	DeletePermissionBundle(ctx context.Context, id uuid.UUID) error
+	SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error
+}
+
 // UserRoleRepository handles user-role assignment operations
 type UserRoleRepository interface {
 	AssignRoleToUser(ctx context.Context, userID, roleID, assignedBy uuid.UUID) error
@@ -112,6 +143,8 @@ type PermissionChecker interface {
 type RBACStore interface {
 	PermissionRepository
 	RoleRepository
+	RoleHierarchyRepository
+	PermissionBundleRepository
 	UserRoleRepository
 	PermissionChecker
 }
@@ -127,10 +160,98 @@ type CacheService interface {
 	DeletePendingRegistration(ctx context.Context, identifier string) error
 }
 
+// BlacklistEvents broadcasts blacklisted token hashes between gateway instances
+type BlacklistEvents interface {
+	PublishBlacklisted(ctx context.Context, tokenHash string) error
+	// WatchBlacklisted blocks until ctx is done, calling onAdd for every published hash,
+	// onSubscribed each time the subscription is (re)established and onInterrupted when it
+	// breaks. Hashes published while it is broken are lost.
+	WatchBlacklisted(ctx context.Context, onAdd func(tokenHash string), onSubscribed, onInterrupted func())
+}
+
+// RevocationBus carries revocation events between gateway instances
+type RevocationBus interface {
+	PublishRevocation(ctx context.Context, event *models.RevocationEvent) error
+	// WatchRevocations blocks until ctx is done, calling onEvent for every published event,
+	// onSubscribed each time the subscription is (re)established and onInterrupted when it
+	// breaks. Events published while it is broken are lost.
+	WatchRevocations(ctx context.Context, onEvent func(event models.RevocationEvent), onSubscribed, onInterrupted func())
+}
+
+// UserChangeBus carries user cache invalidations between gateway instances
+type UserChangeBus interface {
+	PublishUserChanged(ctx context.Context, userID uuid.UUID) error
+	// WatchUserChanged blocks until ctx is done, calling onChange for every published user,
+	// onSubscribed each time the subscription is (re)established and onInterrupted when it
+	// breaks. Changes published while it is broken are lost.
+	WatchUserChanged(ctx context.Context, onChange func(userID uuid.UUID), onSubscribed, onInterrupted func())
+}
+
+// BackchannelLogoutStore queues back-channel logout deliveries
+type BackchannelLogoutStore interface {
+	EnqueueForUser(ctx context.Context, userID uuid.UUID) (int, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackchannelLogoutDelivery, error)
+	Update(ctx context.Context, delivery *models.BackchannelLogoutDelivery) error
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// LogoutTokenSigner signs back-channel logout tokens
+type LogoutTokenSigner interface {
+	GenerateLogoutToken(clientID, subject string, ttl time.Duration) (string, error)
+}
+
+// BackchannelLogoutNotifier tells clients a user signed in to that the user logged out
+type BackchannelLogoutNotifier interface {
+	NotifyLogout(ctx context.Context, userID uuid.UUID)
+}
+
+// BackgroundJobStore persists the background job queue
+type BackgroundJobStore interface {
+	Create(ctx context.Context, job *models.BackgroundJob) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackgroundJob, error)
+	Update(ctx context.Context, job *models.BackgroundJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error)
+	List(ctx context.Context, status, jobType string, page, perPage int) ([]*models.BackgroundJob, int, error)
+	CountByStatus(ctx context.Context) (map[string]int, error)
+	DeleteSucceededBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// AuditExportStore persists how far audit logs have been scheduled for export to each sink
+type AuditExportStore interface {
+	GetCursor(ctx context.Context, sink string, start time.Time) (time.Time, error)
+	FindCursor(ctx context.Context, sink string) (*models.AuditExportCursor, error)
+	AdvanceCursor(ctx context.Context, sink string, from, to time.Time) (bool, error)
+}
+
+// AuditRangeStore reads audit logs by creation time
+type AuditRangeStore interface {
+	ListBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]*models.AuditLog, error)
+}
+
+// OutboxStore persists the outbox of audit logs and webhook events
+type OutboxStore interface {
+	CreateWithTx(ctx context.Context, db bun.IDB, event *models.OutboxEvent) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Reschedule(ctx context.Context, id uuid.UUID, availableAt time.Time, lastError string) error
+}
+
+// LogLevelBus shares runtime log level overrides between gateway instances
+type LogLevelBus interface {
+	PublishLogLevel(ctx context.Context, override string) error
+	// WatchLogLevel blocks until ctx is done, calling onChange for every published override,
+	// onSubscribed each time the subscription is (re)established and onInterrupted when it
+	// breaks. Overrides published while it is broken are lost.
+	WatchLogLevel(ctx context.Context, onChange func(override string), onSubscribed, onInterrupted func())
+}
+
 // SMSLogStore defines the interface for SMS log storage
 type SMSLogStore interface {
 	Create(ctx context.Context, log *models.SMSLog) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status models.SMSStatus, errorMsg *string) error
+	// MarkSent sets the status to sent and stores the provider's message ID
+	MarkSent(ctx context.Context, id uuid.UUID, messageID string) error
+	GetByMessageID(ctx context.Context, provider, messageID string) (*models.SMSLog, error)
 	GetStats(ctx context.Context) (*models.SMSStatsResponse, error)
 	DeleteOlderThan(ctx context.Context, duration time.Duration) (int64, error)
 }
@@ -140,8 +261,12 @@ type TokenService interface {
 	GenerateAccessToken(user *models.User, applicationID ...*uuid.UUID) (string, error)
 	GenerateRefreshToken(user *models.User, applicationID ...*uuid.UUID) (string, error)
 	GenerateTwoFactorToken(user *models.User, applicationID ...*uuid.UUID) (string, error)
+	GeneratePasswordChangeToken(user *models.User) (string, error)
 	ValidateAccessToken(tokenString string) (*jwt.Claims, error)
 	ValidateRefreshToken(tokenString string) (*jwt.Claims, error)
+	ValidatePasswordChangeToken(tokenString string) (*jwt.Claims, error)
+	GenerateOAuthLinkToken(user *models.User, provider, providerUserID string, applicationID *uuid.UUID) (string, error)
+	ValidateOAuthLinkToken(tokenString string) (*jwt.Claims, error)
 	ExtractClaims(tokenString string) (*jwt.Claims, error)
 	GetAccessTokenExpiration() time.Duration
 	GetRefreshTokenExpiration() time.Duration
@@ -207,6 +332,9 @@ type AuditStore interface {
 	DeleteOlderThan(ctx context.Context, days int) error
 	CountByActionSince(ctx context.Context, action models.AuditAction, since time.Time) (int, error)
 	ListByApp(ctx context.Context, appID uuid.UUID, limit, offset int) ([]*models.AuditLog, int, error)
+	Search(ctx context.Context, limit, offset int, opts ...AuditLogListOption) ([]*models.AuditLog, int, error)
+	CountByAction(ctx context.Context, limit int, opts ...AuditLogListOption) ([]*models.AuditActionCount, error)
+	CountFailuresByHour(ctx context.Context, opts ...AuditLogListOption) ([]*models.AuditHourlyCount, error)
 }
 
 
@@ -232,6 +360,39 @@ type APIKeyStore interface {
 	GetByUserIDAndApp(ctx context.Context, userID, appID uuid.UUID) ([]*models.APIKey, error)
 }
 
+// GroupAdminStore defines the interface for organization admin storage. Organizations are
+// groups; an admin of a group also administers its child groups.
+type GroupAdminStore interface {
+	ListAdmins(ctx context.Context, groupID uuid.UUID) ([]*models.GroupAdmin, error)
+	AddAdmin(ctx context.Context, admin *models.GroupAdmin) error
+	RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error
+	ListAdministeredGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error)
+	IsAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error)
+	IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error)
+	ListMembers(ctx context.Context, groupID uuid.UUID, search string, limit, offset int) ([]*models.User, int, error)
+	RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error
+	ListMemberAPIKeys(ctx context.Context, groupID uuid.UUID) ([]*models.APIKey, error)
+}
+
+// UsageReportStore defines the interface for organization usage report storage
+type UsageReportStore interface {
+	GetSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error)
+	UpsertSettings(ctx context.Context, settings *models.OrgUsageReportSettings) error
+	ListSettings(ctx context.Context) ([]*models.OrgUsageReportSettings, error)
+	MarkSent(ctx context.Context, groupID uuid.UUID, sentAt time.Time) error
+	GetUsageCounts(ctx context.Context, groupID uuid.UUID, activeSince time.Time) (*models.OrgUsageCounts, error)
+}
+
+// GroupDomainStore defines the interface for the email domains claimed by groups
+type GroupDomainStore interface {
+	ListByGroup(ctx context.Context, groupID uuid.UUID) ([]*models.GroupDomain, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.GroupDomain, error)
+	Create(ctx context.Context, domain *models.GroupDomain) error
+	MarkVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListVerifiedForDomain(ctx context.Context, domain string) ([]*models.GroupDomain, error)
+}
+
 // SessionStore defines the interface for session storage
 type SessionStore interface {
 	CreateSession(ctx context.Context, session *models.Session) error
@@ -252,6 +413,12 @@ type SessionStore interface {
 	GetAppSessionsPaginated(ctx context.Context, appID uuid.UUID, page, perPage int) ([]models.Session, int, error)
 }
 
+// SessionArchive keeps copies of sessions stored in Redis, for reports and audits that read
+// sessions from the database
+type SessionArchive interface {
+	ArchiveSessions(ctx context.Context, sessions []models.Session) error
+}
+
 // OAuthClientRepository handles OAuth client CRUD
 type OAuthClientRepository interface {
 	CreateClient(ctx context.Context, client *models.OAuthClient) error
@@ -262,6 +429,7 @@ type OAuthClientRepository interface {
 	HardDeleteClient(ctx context.Context, id uuid.UUID) error
 	ListClients(ctx context.Context, page, perPage int, opts ...OAuthClientListOption) ([]*models.OAuthClient, int, error)
 	ListActiveClients(ctx context.Context) ([]*models.OAuthClient, error)
+	ListFrontchannelLogoutClients(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClient, error)
 }
 
 // OAuthAuthCodeRepository handles authorization code operations
@@ -287,6 +455,7 @@ type OAuthTokenRepository interface {
 	RevokeAllUserRefreshTokens(ctx context.Context, userID, clientID uuid.UUID) error
 	RevokeAllClientRefreshTokens(ctx context.Context, clientID uuid.UUID) error
 	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
+	GetUserTokenStats(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClientTokenStats, error)
 }
 
 // OAuthConsentRepository handles user consent operations
@@ -314,6 +483,11 @@ type OAuthScopeRepository interface {
 	ListScopes(ctx context.Context) ([]*models.OAuthScope, error)
 	ListSystemScopes(ctx context.Context) ([]*models.OAuthScope, error)
 	DeleteScope(ctx context.Context, id uuid.UUID) error
+	CreateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error
+	GetScopeGroupByID(ctx context.Context, id uuid.UUID) (*models.OAuthScopeGroup, error)
+	ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error)
+	UpdateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error
+	DeleteScopeGroup(ctx context.Context, id uuid.UUID) error
 }
 
 // OAuthProviderStore composes all OAuth sub-interfaces for backward compatibility
@@ -422,6 +596,358 @@ type BlacklistChecker interface {
 	BlacklistAllUserSessions(ctx context.Context, userID uuid.UUID) error
 }
 
+// PasswordHistoryStore defines the interface for password history storage
+type PasswordHistoryStore interface {
+	Create(ctx context.Context, entry *models.PasswordHistory) error
+	GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*models.PasswordHistory, error)
+	Prune(ctx context.Context, userID uuid.UUID, keep int) error
+}
+
+// PasswordExpiryStore defines the interface for password expiry policy storage
+type PasswordExpiryStore interface {
+	GetExpiry(ctx context.Context, userID uuid.UUID, defaultMaxAgeDays int) (*time.Time, error)
+	ListExpiring(ctx context.Context, defaultMaxAgeDays int, before time.Time, unwarnedOnly bool, limit int) ([]*models.PasswordExpiryEntry, error)
+	MarkWarned(ctx context.Context, userID uuid.UUID) error
+	SetMustChangePassword(ctx context.Context, userID uuid.UUID, required bool) error
+	SetRoleMaxAge(ctx context.Context, roleID uuid.UUID, maxAgeDays *int) error
+	SetGroupMaxAge(ctx context.Context, groupID uuid.UUID, maxAgeDays *int) error
+}
+
+// AccountRecoveryStore defines the interface for account recovery storage
+type AccountRecoveryStore interface {
+	Create(ctx context.Context, req *models.AccountRecoveryRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.AccountRecoveryRequest, error)
+	Update(ctx context.Context, req *models.AccountRecoveryRequest) error
+	List(ctx context.Context, status string, page, pageSize int) ([]*models.AccountRecoveryRequest, int, error)
+	ResetSecondFactor(ctx context.Context, userID uuid.UUID) error
+}
+
+// UserEmailStore defines the interface for secondary email address storage
+type UserEmailStore interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*models.UserEmail, error)
+	GetByUserAndEmail(ctx context.Context, userID uuid.UUID, email string) (*models.UserEmail, error)
+	Create(ctx context.Context, email *models.UserEmail) error
+	MarkVerified(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	SetPrimary(ctx context.Context, userID, id uuid.UUID) error
+	SetRecoveryEmail(ctx context.Context, userID uuid.UUID, email *string) error
+}
+
+// OAuthClientLogoStore defines the interface for uploaded OAuth client logo storage
+type OAuthClientLogoStore interface {
+	Save(ctx context.Context, logo *models.OAuthClientLogo) error
+	GetByHash(ctx context.Context, hash string) (*models.OAuthClientLogo, error)
+	Delete(ctx context.Context, clientID uuid.UUID) error
+}
+
+// OAuthClientWebhookStore defines the interface for OAuth client webhook storage
+type OAuthClientWebhookStore interface {
+	Create(ctx context.Context, webhook *models.OAuthClientWebhook) error
+	GetByID(ctx context.Context, clientID, id uuid.UUID) (*models.OAuthClientWebhook, error)
+	ListByClient(ctx context.Context, clientID uuid.UUID) ([]*models.OAuthClientWebhook, error)
+	ListActiveByEvent(ctx context.Context, clientID uuid.UUID, eventType string) ([]*models.OAuthClientWebhook, error)
+	Update(ctx context.Context, webhook *models.OAuthClientWebhook) error
+	Delete(ctx context.Context, clientID, id uuid.UUID) error
+	MarkTriggered(ctx context.Context, id uuid.UUID) error
+	CreateDelivery(ctx context.Context, delivery *models.OAuthClientWebhookDelivery) error
+	CompleteDelivery(ctx context.Context, id uuid.UUID, status string, httpStatusCode *int, responseBody string) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, page, perPage int) ([]*models.OAuthClientWebhookDelivery, int, error)
+}
+
+// PermissionCatalogStore defines the interface for service-registered permission catalog entries
+type PermissionCatalogStore interface {
+	ReplaceEntries(ctx context.Context, source string, entries []*models.PermissionCatalogEntry) error
+	ListEntries(ctx context.Context) ([]*models.PermissionCatalogEntry, error)
+	ListGrants(ctx context.Context) ([]*models.PermissionGrant, error)
+}
+
+// BulkRoleJobStore defines the interface for bulk role job persistence
+type BulkRoleJobStore interface {
+	Create(ctx context.Context, job *models.BulkRoleJob) error
+	Update(ctx context.Context, job *models.BulkRoleJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BulkRoleJob, error)
+	List(ctx context.Context, limit int) ([]*models.BulkRoleJob, error)
+}
+
+// NotificationSender sends templated notification emails
+type NotificationSender interface {
+	SendEmail(ctx context.Context, profileID *uuid.UUID, applicationID *uuid.UUID, toEmail string, templateType string, variables map[string]interface{}) error
+}
+
+// EmailVerificationStore defines the interface for email verification reminder storage
+type EmailVerificationStore interface {
+	ListUnverified(ctx context.Context, createdAfter, createdBefore time.Time, maxReminders, limit int) ([]*models.EmailVerificationReminderEntry, error)
+	SetRemindersSent(ctx context.Context, userID uuid.UUID, count int) error
+}
+
+// GuestStore defines the interface for anonymous guest account storage
+type GuestStore interface {
+	Create(ctx context.Context, user *models.User, roleID uuid.UUID) error
+	Upgrade(ctx context.Context, user *models.User) error
+	DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// CredentialStore defines the interface for credentials broker storage
+type CredentialStore interface {
+	CreateTarget(ctx context.Context, target *models.CredentialTarget) error
+	GetTargetByID(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error)
+	GetTargetByName(ctx context.Context, name string) (*models.CredentialTarget, error)
+	ListTargets(ctx context.Context) ([]*models.CredentialTarget, error)
+	UpdateTarget(ctx context.Context, target *models.CredentialTarget) error
+	DeleteTarget(ctx context.Context, id uuid.UUID) error
+	CreateIssuance(ctx context.Context, issuance *models.CredentialIssuance) error
+	GetIssuanceByID(ctx context.Context, id uuid.UUID) (*models.CredentialIssuance, error)
+	ListIssuances(ctx context.Context, userID *uuid.UUID, page, pageSize int) ([]*models.CredentialIssuance, int, error)
+	ListExpiredRoles(ctx context.Context, before time.Time, limit int) ([]*models.CredentialIssuance, error)
+	MarkRevoked(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// RiskPolicyStore defines the interface for risk policy storage
+type RiskPolicyStore interface {
+	CreateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error
+	GetRiskPolicyByID(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error)
+	GetActiveRiskPolicy(ctx context.Context, appID *uuid.UUID) (*models.RiskPolicy, error)
+	ListRiskPolicies(ctx context.Context) ([]*models.RiskPolicy, error)
+	UpdateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error
+	DeleteRiskPolicy(ctx context.Context, id uuid.UUID) error
+}
+
+// RiskStateStore keeps the sign-in history the risk engine compares attempts against.
+// Used by RiskService; *RedisService implements it.
+type RiskStateStore interface {
+	SAdd(ctx context.Context, key string, members ...string) error
+	SIsMember(ctx context.Context, key string, member string) (bool, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SlidingWindowRateLimit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+// GeoLocator resolves IP addresses to locations; nil when the address is unknown.
+// Used by RiskService; *GeoService implements it.
+type GeoLocator interface {
+	GetLocation(ctx context.Context, ip string) *models.GeoLocation
+}
+
+// RiskAssessor scores sign-in attempts against the applicable risk policy.
+// Used by AuthService during password sign-in.
+type RiskAssessor interface {
+	Assess(ctx context.Context, user *models.User, appID *uuid.UUID, ip string, device models.DeviceInfo) *models.RiskAssessment
+	RecordSignIn(ctx context.Context, userID uuid.UUID, ip string, device models.DeviceInfo)
+	RecordFailedAttempt(ctx context.Context, userID uuid.UUID, appID *uuid.UUID)
+}
+
+// AccountLockoutStore defines the interface for account lockout storage
+type AccountLockoutStore interface {
+	GetLockout(ctx context.Context, userID uuid.UUID) (*models.AccountLockout, error)
+	RecordFailedAttempt(ctx context.Context, userID uuid.UUID, windowStart time.Time) (*models.AccountLockout, error)
+	Lock(ctx context.Context, userID uuid.UUID, lockoutCount int, until time.Time) error
+	Reset(ctx context.Context, userID uuid.UUID) error
+	ListLocked(ctx context.Context, at time.Time, page, pageSize int) ([]*models.LockedAccount, int, error)
+}
+
+// AccountLocker locks accounts after repeated failed sign-ins.
+// Used by AuthService during password sign-in.
+type AccountLocker interface {
+	LockedUntil(ctx context.Context, userID uuid.UUID) *time.Time
+	RecordFailedAttempt(ctx context.Context, userID uuid.UUID) *models.AccountLockout
+	RecordSignIn(ctx context.Context, userID uuid.UUID)
+}
+
+// UserStateStore defines the interface for user lifecycle state storage
+type UserStateStore interface {
+	GetByID(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error)
+	UpdateState(ctx context.Context, id uuid.UUID, from, to models.UserState) error
+	TouchLastLogin(ctx context.Context, id uuid.UUID) error
+}
+
+// DormantAccountStore defines the interface for finding and anonymizing dormant accounts
+type DormantAccountStore interface {
+	ListCandidates(ctx context.Context, cutoff time.Time, excludedRoles []string, excludedUserIDs []uuid.UUID, afterID uuid.UUID, limit int) ([]*models.DormantAccountCandidate, error)
+	MarkNotified(ctx context.Context, userID uuid.UUID, notifiedAt time.Time) error
+	Anonymize(ctx context.Context, userID uuid.UUID) error
+}
+
+// RetentionStore defines the interface for enforcing data retention and erasing personal data
+type RetentionStore interface {
+	DeleteAuditLogsBefore(ctx context.Context, before time.Time, limit int) (int, error)
+	DeleteSessionsEndedBefore(ctx context.Context, before time.Time, limit int) (int, error)
+	ListInactiveAccounts(ctx context.Context, cutoff time.Time, afterID uuid.UUID, limit int) ([]uuid.UUID, error)
+	ScrubUserActivity(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// UserAnonymizer scrubs the personal data held on an account
+type UserAnonymizer interface {
+	Anonymize(ctx context.Context, userID uuid.UUID) error
+}
+
+// DataExportStore defines the interface for user data export storage
+type DataExportStore interface {
+	Create(ctx context.Context, export *models.UserDataExport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.UserDataExport, error)
+	GetLatest(ctx context.Context, userID uuid.UUID, format models.DataExportFormat, now time.Time) (*models.UserDataExport, error)
+	GetContent(ctx context.Context, id uuid.UUID) ([]byte, error)
+	Complete(ctx context.Context, id uuid.UUID, content []byte, completedAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, message string, completedAt time.Time) error
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// SigningKeyStore defines the interface for the storage of rotated OIDC signing keys.
+// Update must run fn and apply its result while no other replica does.
+type SigningKeyStore interface {
+	List(ctx context.Context) ([]*models.StoredSigningKey, error)
+	Update(ctx context.Context, fn func(keys []*models.StoredSigningKey) (add []*models.StoredSigningKey, remove []string, err error)) error
+}
+
+// UserDataSource defines the interface for reading everything stored about a user for a data export
+type UserDataSource interface {
+	ListUserEmails(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error)
+	ListUserProfiles(ctx context.Context, userID uuid.UUID) ([]*models.UserApplicationProfile, error)
+	ListUserSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	ListUserConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error)
+	ListUserAuditLogs(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.AuditLog, error)
+	ListOAuthAccounts(ctx context.Context, userID uuid.UUID) ([]*models.OAuthAccount, error)
+	ListTelegramAccounts(ctx context.Context, userID uuid.UUID) ([]*models.UserTelegramAccount, error)
+	ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error)
+}
+
+// OAuthClientEventNotifier delivers an event about an OAuth client to the webhooks its owner registered
+type OAuthClientEventNotifier interface {
+	NotifyClientEvent(ctx context.Context, clientID uuid.UUID, eventType string, data map[string]interface{}) error
+}
+
+// UserGroupLister lists the groups a user belongs to
+type UserGroupLister interface {
+	GetUserGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error)
+}
+
+// WebhookTrigger delivers an event to the webhooks subscribed to it
+type WebhookTrigger interface {
+	TriggerWebhook(ctx context.Context, eventType string, data map[string]interface{}) error
+}
+
+// OutboxRecorder records audit logs and webhook events in the transaction of the state
+// change they describe; Wake starts their dispatch once the transaction is committed
+type OutboxRecorder interface {
+	RecordAudit(ctx context.Context, db bun.IDB, params AuditLogParams) error
+	RecordWebhook(ctx context.Context, db bun.IDB, eventType string, data map[string]interface{}) error
+	Wake()
+}
+
+// EventPublisher publishes an auth event to the event bus. key identifies the entity the
+// event is about, usually the user ID.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, eventType, key string, data map[string]interface{}) error
+}
+
+// UserLifecycleManager records sign-ins for dormancy tracking and accepts invitations.
+// Used by AuthService when tokens are issued and when a password is reset.
+type UserLifecycleManager interface {
+	RecordSignIn(ctx context.Context, userID uuid.UUID)
+	AcceptInvitation(ctx context.Context, userID uuid.UUID, ip, userAgent string) error
+}
+
+// UserTimelineStore defines the interface for reading a user's activity timeline
+type UserTimelineStore interface {
+	ListUserTimeline(ctx context.Context, userID uuid.UUID, sources []string, page, pageSize int) ([]*models.UserTimelineEvent, int, error)
+}
+
+// PostgresRoleProvisioner creates and drops temporary Postgres login roles.
+// Used by CredentialsBrokerService for postgres targets.
+type PostgresRoleProvisioner interface {
+	CreateRole(ctx context.Context, username, password, grantRole string, validUntil time.Time) error
+	DropRole(ctx context.Context, username string) error
+}
+
+// SSHCertificateSigner signs SSH user certificates.
+// Used by CredentialsBrokerService for ssh targets.
+type SSHCertificateSigner interface {
+	SignUserKey(publicKey, keyID string, principals []string, serial uint64, validBefore time.Time) (string, error)
+}
+
+// TokenVersionStore defines the interface for per-user token version storage
+type TokenVersionStore interface {
+	GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+	IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// SettingStore defines the interface for system setting storage
+type SettingStore interface {
+	GetSetting(ctx context.Context, key string) (*models.SystemSetting, error)
+	UpdateSetting(ctx context.Context, key, value string, updatedBy *uuid.UUID) error
+}
+
+// KeyValueCache defines the interface for plain key/value caching
+type KeyValueCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// OneTimeStore holds short-lived values that can be read only once.
+// Used by MagicLinkService for link tokens and session handoff codes.
+type OneTimeStore interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	GetDel(ctx context.Context, key string) (string, error)
+}
+
+// TokenVersionChecker provides token version checks and bumps.
+// Used by AuthService to invalidate outstanding tokens and reject stale refresh tokens.
+type TokenVersionChecker interface {
+	IsRevoked(ctx context.Context, claims *jwt.Claims) bool
+	Bump(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// PasswordExpiryChecker reports whether a user must change their password before signing in.
+// Used by AuthService to block sign-in on expired or admin-flagged passwords.
+type PasswordExpiryChecker interface {
+	ChangeRequired(ctx context.Context, user *models.User) (string, error)
+}
+
+// EmailVerificationEnforcer reports whether an unverified email address blocks sign-in.
+// Used by AuthService at sign-up and sign-in.
+type EmailVerificationEnforcer interface {
+	CheckSignIn(user *models.User) error
+}
+
+// SignupEnforcer applies the sign-up policy to self-service registrations.
+// Used by AuthService, OAuthService and OTPService around self-service user creation.
+type SignupEnforcer interface {
+	CheckSignup(ctx context.Context, email string) error
+	CheckUnverifiedSignup(ctx context.Context, email string) error
+	AutoJoin(ctx context.Context, user *models.User)
+}
+
+// PasswordPolicyEnforcer checks new passwords against the password policy and keeps the
+// password history the policy needs. Used by AuthService, GuestService and AdminUserService
+// wherever a password is set.
+type PasswordPolicyEnforcer interface {
+	CheckPassword(ctx context.Context, password string, user *models.User) error
+	RecordPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+}
+
+// PasswordResetter sets a new password for a user and revokes their existing tokens.
+// Used by AccountRecoveryService to finish a recovery.
+type PasswordResetter interface {
+	ResetPassword(ctx context.Context, userID uuid.UUID, newPassword, ip, userAgent string) error
+}
+
+// SessionIssuer issues tokens and records a session for a user who has authenticated.
+// Used by GuestService to sign in guests.
+type SessionIssuer interface {
+	IssueSession(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, isNewUser bool, authMethod string) (*models.AuthResponse, error)
+	CheckAuthMethodAllowed(ctx context.Context, appID *uuid.UUID, method string) error
+}
+
+// SignInCompleter finishes a sign-in whose first factor another service verified.
+// Used by MagicLinkService once a link is clicked.
+type SignInCompleter interface {
+	CompleteSignIn(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, authMethod string) (*models.AuthResponse, error)
+	CheckAuthMethodAllowed(ctx context.Context, appID *uuid.UUID, method string) error
+}
+
 // SessionManager provides session creation and management.
 // Used by AuthService for session lifecycle operations.
 type SessionManager interface {
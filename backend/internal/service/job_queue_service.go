@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	jobQueueBatchSize     = 20
+	jobQueueLease         = 5 * time.Minute // longer than a job may run
+	jobQueueJobTimeout    = 2 * time.Minute
+	jobQueuePollInterval  = 5 * time.Second
+	jobQueueRetryBase     = 30 * time.Second
+	jobQueueRetryMax      = time.Hour
+	jobQueueRetention     = 7 * 24 * time.Hour
+	jobQueueCleanupPeriod = time.Hour
+	defaultJobMaxAttempts = 5
+	// jobQueueErrorMaxLen limits the error message kept on a job
+	jobQueueErrorMaxLen = 1000
+)
+
+var errJobNotDead = models.NewAppError(http.StatusConflict, "Only dead jobs can be retried")
+
+// JobHandler runs one attempt of a job. Returning an error schedules another attempt with
+// exponential backoff until the job runs out of attempts; wrap it with PermanentJobError to
+// stop retrying or RetryJobAfter to choose the delay.
+type JobHandler func(ctx context.Context, job *models.BackgroundJob) error
+
+// jobError carries how the queue should treat a failed attempt
+type jobError struct {
+	err        error
+	permanent  bool
+	retryAfter time.Duration
+}
+
+func (e *jobError) Error() string { return e.err.Error() }
+func (e *jobError) Unwrap() error { return e.err }
+
+// PermanentJobError marks err as one retrying will not fix; the job is dead at once
+func PermanentJobError(err error) error {
+	return &jobError{err: err, permanent: true}
+}
+
+// RetryJobAfter schedules the next attempt after delay instead of the default backoff
+func RetryJobAfter(err error, delay time.Duration) error {
+	return &jobError{err: err, retryAfter: delay}
+}
+
+// JobQueueService runs background jobs from a persistent queue in the database. Jobs are
+// claimed by Run on any instance and retried with exponential backoff until they succeed or
+// run out of attempts; those that don't succeed are kept as dead jobs for admins to inspect
+// and retry.
+type JobQueueService struct {
+	store    BackgroundJobStore
+	logger   *logger.Logger
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+	now      func() time.Time
+	wake     chan struct{}
+}
+
+// NewJobQueueService creates a job queue service
+func NewJobQueueService(store BackgroundJobStore, log *logger.Logger) *JobQueueService {
+	return &JobQueueService{
+		store:    store,
+		logger:   log,
+		handlers: make(map[string]JobHandler),
+		now:      time.Now,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler of a job type. Every instance running the queue must register
+// the same types; a job without a handler is dead.
+func (s *JobQueueService) Register(jobType string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Enqueue queues a job with a JSON payload. maxAttempts <= 0 uses the default. The job
+// starts right away on this instance.
+func (s *JobQueueService) Enqueue(ctx context.Context, jobType string, payload interface{}, maxAttempts int) (*models.BackgroundJob, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultJobMaxAttempts
+	}
+
+	job := &models.BackgroundJob{
+		Type:        jobType,
+		Payload:     data,
+		Status:      models.BackgroundJobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       s.now(),
+	}
+	if err := s.store.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return job, nil
+}
+
+func (s *JobQueueService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs due jobs until ctx is done
+func (s *JobQueueService) Run(ctx context.Context) {
+	poll := time.NewTicker(jobQueuePollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(jobQueueCleanupPeriod)
+	defer cleanup.Stop()
+
+	for {
+		s.RunDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-s.wake:
+		case <-cleanup.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+// RunDue runs every job that is due and returns how many succeeded
+func (s *JobQueueService) RunDue(ctx context.Context) int {
+	succeeded := 0
+	for ctx.Err() == nil {
+		batch, err := s.store.ClaimDue(ctx, jobQueueBatchSize, jobQueueLease)
+		if err != nil {
+			s.logger.Error("Failed to claim background jobs", map[string]interface{}{
+				"error": err.Error(),
+			})
+			break
+		}
+
+		// A slow job must not hold up the others
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, job := range batch {
+			wg.Add(1)
+			go func(j *models.BackgroundJob) {
+				defer wg.Done()
+				if s.run(ctx, j) {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}(job)
+		}
+		wg.Wait()
+
+		if len(batch) < jobQueueBatchSize {
+			break
+		}
+	}
+	return succeeded
+}
+
+// run makes one attempt of a claimed job and records its outcome; it reports whether the
+// job succeeded
+func (s *JobQueueService) run(ctx context.Context, job *models.BackgroundJob) bool {
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = PermanentJobError(fmt.Errorf("no handler for job type %q", job.Type))
+	} else {
+		runCtx, cancel := context.WithTimeout(ctx, jobQueueJobTimeout)
+		err = s.invoke(runCtx, handler, job)
+		cancel()
+	}
+
+	var jobErr *jobError
+	errors.As(err, &jobErr)
+
+	now := s.now()
+	switch {
+	case err == nil:
+		job.Status = models.BackgroundJobSucceeded
+		job.LastError = ""
+		job.CompletedAt = &now
+	case (jobErr != nil && jobErr.permanent) || job.Attempts >= job.MaxAttempts:
+		job.Status = models.BackgroundJobDead
+		job.LastError = truncateJobError(err)
+		job.CompletedAt = &now
+	default:
+		delay := jobQueueBackoff(job.Attempts)
+		if jobErr != nil && jobErr.retryAfter > 0 {
+			delay = jobErr.retryAfter
+		}
+		job.Status = models.BackgroundJobPending
+		job.RunAt = now.Add(delay)
+		job.LastError = truncateJobError(err)
+	}
+
+	if err != nil {
+		s.logger.Warn("Background job failed", map[string]interface{}{
+			"job_id":   job.ID.String(),
+			"type":     job.Type,
+			"attempts": job.Attempts,
+			"status":   job.Status,
+			"error":    err.Error(),
+		})
+	}
+
+	// The outcome is recorded even if the run is being stopped
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if updateErr := s.store.Update(updateCtx, job); updateErr != nil {
+		s.logger.Error("Failed to record background job", map[string]interface{}{
+			"job_id": job.ID.String(),
+			"error":  updateErr.Error(),
+		})
+	}
+
+	return err == nil
+}
+
+// invoke calls the handler, turning a panic into a failed attempt
+func (s *JobQueueService) invoke(ctx context.Context, handler JobHandler, job *models.BackgroundJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+func (s *JobQueueService) cleanup(ctx context.Context) {
+	deleted, err := s.store.DeleteSucceededBefore(ctx, s.now().Add(-jobQueueRetention))
+	if err != nil {
+		s.logger.Error("Failed to delete old background jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debug("Deleted old background jobs", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+}
+
+// ListJobs lists jobs, newest first, optionally filtered by status and type
+func (s *JobQueueService) ListJobs(ctx context.Context, status, jobType string, page, perPage int) (*models.BackgroundJobListResponse, error) {
+	switch status {
+	case "", models.BackgroundJobPending, models.BackgroundJobRunning, models.BackgroundJobSucceeded, models.BackgroundJobDead:
+	default:
+		return nil, models.NewAppError(http.StatusBadRequest, "Invalid job status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	jobs, total, err := s.store.List(ctx, status, jobType, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BackgroundJobListResponse{
+		Jobs:       jobs,
+		Total:      total,
+		Page:       page,
+		PageSize:   perPage,
+		TotalPages: (total + perPage - 1) / perPage,
+	}, nil
+}
+
+// GetJob retrieves a job
+func (s *JobQueueService) GetJob(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error) {
+	return s.store.GetByID(ctx, id)
+}
+
+// RetryJob requeues a dead job with a fresh set of attempts
+func (s *JobQueueService) RetryJob(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error) {
+	job, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.BackgroundJobDead {
+		return nil, errJobNotDead
+	}
+
+	job.Status = models.BackgroundJobPending
+	job.Attempts = 0
+	job.RunAt = s.now()
+	job.CompletedAt = nil
+	if err := s.store.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return job, nil
+}
+
+// GetStats counts jobs by status
+func (s *JobQueueService) GetStats(ctx context.Context) (*models.BackgroundJobStatsResponse, error) {
+	counts, err := s.store.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BackgroundJobStatsResponse{
+		Pending:   counts[models.BackgroundJobPending],
+		Running:   counts[models.BackgroundJobRunning],
+		Succeeded: counts[models.BackgroundJobSucceeded],
+		Dead:      counts[models.BackgroundJobDead],
+	}, nil
+}
+
+// jobQueueBackoff is the wait after the given number of failed attempts
+func jobQueueBackoff(attempts int) time.Duration {
+	wait := jobQueueRetryBase
+	for i := 1; i < attempts && wait < jobQueueRetryMax; i++ {
+		wait *= 2
+	}
+	if wait > jobQueueRetryMax {
+		wait = jobQueueRetryMax
+	}
+	return wait
+}
+
+func truncateJobError(err error) string {
+	msg := err.Error()
+	if len(msg) > jobQueueErrorMaxLen {
+		msg = msg[:jobQueueErrorMaxLen]
+	}
+	return msg
+}
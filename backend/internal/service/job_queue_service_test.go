@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackgroundJobStore struct {
+	mu      sync.Mutex
+	created []*models.BackgroundJob
+	due     []*models.BackgroundJob
+	updated []*models.BackgroundJob
+	jobs    map[uuid.UUID]*models.BackgroundJob
+}
+
+func (m *mockBackgroundJobStore) Create(ctx context.Context, job *models.BackgroundJob) error {
+	job.ID = uuid.New()
+	m.created = append(m.created, job)
+	return nil
+}
+
+func (m *mockBackgroundJobStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.BackgroundJob, error) {
+	due := m.due
+	m.due = nil
+	for _, job := range due {
+		job.Status = models.BackgroundJobRunning
+		job.Attempts++
+	}
+	return due, nil
+}
+
+func (m *mockBackgroundJobStore) Update(ctx context.Context, job *models.BackgroundJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *job
+	m.updated = append(m.updated, &copied)
+	return nil
+}
+
+func (m *mockBackgroundJobStore) GetByID(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return job, nil
+}
+
+func (m *mockBackgroundJobStore) List(ctx context.Context, status, jobType string, page, perPage int) ([]*models.BackgroundJob, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockBackgroundJobStore) CountByStatus(ctx context.Context) (map[string]int, error) {
+	return map[string]int{models.BackgroundJobPending: 2, models.BackgroundJobDead: 1}, nil
+}
+
+func (m *mockBackgroundJobStore) DeleteSucceededBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func setupJobQueueService() (*JobQueueService, *mockBackgroundJobStore) {
+	store := &mockBackgroundJobStore{jobs: make(map[uuid.UUID]*models.BackgroundJob)}
+	svc := NewJobQueueService(store, logger.New("test", logger.ErrorLevel, false))
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, store
+}
+
+func newBackgroundJob(jobType string, attempts, maxAttempts int) *models.BackgroundJob {
+	return &models.BackgroundJob{
+		ID:          uuid.New(),
+		Type:        jobType,
+		Payload:     []byte(`{}`),
+		Status:      models.BackgroundJobPending,
+		Attempts:    attempts,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+func TestJobQueueService_Enqueue(t *testing.T) {
+	svc, store := setupJobQueueService()
+
+	job, err := svc.Enqueue(context.Background(), "test.job", map[string]string{"key": "value"}, 0)
+	require.NoError(t, err)
+
+	require.Len(t, store.created, 1)
+	assert.Equal(t, "test.job", job.Type)
+	assert.JSONEq(t, `{"key":"value"}`, string(job.Payload))
+	assert.Equal(t, models.BackgroundJobPending, job.Status)
+	assert.Equal(t, defaultJobMaxAttempts, job.MaxAttempts)
+	assert.Equal(t, svc.now(), job.RunAt)
+	assert.Len(t, svc.wake, 1)
+}
+
+func TestJobQueueService_RunDue(t *testing.T) {
+	t.Run("succeeded", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			return nil
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 0, 3)}
+
+		assert.Equal(t, 1, svc.RunDue(context.Background()))
+
+		require.Len(t, store.updated, 1)
+		job := store.updated[0]
+		assert.Equal(t, models.BackgroundJobSucceeded, job.Status)
+		assert.Equal(t, 1, job.Attempts)
+		require.NotNil(t, job.CompletedAt)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			return errors.New("unavailable")
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 1, 3)}
+
+		assert.Equal(t, 0, svc.RunDue(context.Background()))
+
+		require.Len(t, store.updated, 1)
+		job := store.updated[0]
+		assert.Equal(t, models.BackgroundJobPending, job.Status)
+		assert.Equal(t, svc.now().Add(time.Minute), job.RunAt)
+		assert.Equal(t, "unavailable", job.LastError)
+		assert.Nil(t, job.CompletedAt)
+	})
+
+	t.Run("retried after requested delay", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			return RetryJobAfter(errors.New("unavailable"), 5*time.Minute)
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 0, 3)}
+
+		svc.RunDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackgroundJobPending, store.updated[0].Status)
+		assert.Equal(t, svc.now().Add(5*time.Minute), store.updated[0].RunAt)
+	})
+
+	t.Run("dead after max attempts", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			return errors.New("unavailable")
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 2, 3)}
+
+		svc.RunDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackgroundJobDead, store.updated[0].Status)
+		assert.Equal(t, 3, store.updated[0].Attempts)
+		require.NotNil(t, store.updated[0].CompletedAt)
+	})
+
+	t.Run("dead on permanent error", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			return PermanentJobError(errors.New("invalid payload"))
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 0, 3)}
+
+		svc.RunDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackgroundJobDead, store.updated[0].Status)
+		assert.Equal(t, "invalid payload", store.updated[0].LastError)
+	})
+
+	t.Run("dead without handler", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		store.due = []*models.BackgroundJob{newBackgroundJob("unknown.job", 0, 3)}
+
+		svc.RunDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackgroundJobDead, store.updated[0].Status)
+		assert.Contains(t, store.updated[0].LastError, "unknown.job")
+	})
+
+	t.Run("panic is a failed attempt", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		svc.Register("test.job", func(ctx context.Context, job *models.BackgroundJob) error {
+			panic("boom")
+		})
+		store.due = []*models.BackgroundJob{newBackgroundJob("test.job", 0, 3)}
+
+		svc.RunDue(context.Background())
+
+		require.Len(t, store.updated, 1)
+		assert.Equal(t, models.BackgroundJobPending, store.updated[0].Status)
+		assert.Contains(t, store.updated[0].LastError, "boom")
+	})
+}
+
+func TestJobQueueService_RetryJob(t *testing.T) {
+	t.Run("dead job", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		completed := svc.now().Add(-time.Hour)
+		job := newBackgroundJob("test.job", 3, 3)
+		job.Status = models.BackgroundJobDead
+		job.CompletedAt = &completed
+		store.jobs[job.ID] = job
+
+		retried, err := svc.RetryJob(context.Background(), job.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.BackgroundJobPending, retried.Status)
+		assert.Equal(t, 0, retried.Attempts)
+		assert.Equal(t, svc.now(), retried.RunAt)
+		assert.Nil(t, retried.CompletedAt)
+		require.Len(t, store.updated, 1)
+		assert.Len(t, svc.wake, 1)
+	})
+
+	t.Run("job that is not dead", func(t *testing.T) {
+		svc, store := setupJobQueueService()
+		job := newBackgroundJob("test.job", 1, 3)
+		store.jobs[job.ID] = job
+
+		_, err := svc.RetryJob(context.Background(), job.ID)
+		assert.ErrorIs(t, err, errJobNotDead)
+		assert.Empty(t, store.updated)
+	})
+
+	t.Run("missing job", func(t *testing.T) {
+		svc, _ := setupJobQueueService()
+
+		_, err := svc.RetryJob(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
+
+func TestJobQueueService_ListJobsRejectsUnknownStatus(t *testing.T) {
+	svc, _ := setupJobQueueService()
+
+	_, err := svc.ListJobs(context.Background(), "failed", "", 1, 20)
+	require.Error(t, err)
+	assert.Equal(t, "Invalid job status", err.Error())
+}
+
+func TestJobQueueService_GetStats(t *testing.T) {
+	svc, _ := setupJobQueueService()
+
+	stats, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &models.BackgroundJobStatsResponse{Pending: 2, Dead: 1}, stats)
+}
+
+func TestJobQueueBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, jobQueueBackoff(1))
+	assert.Equal(t, 2*time.Minute, jobQueueBackoff(3))
+	assert.Equal(t, time.Hour, jobQueueBackoff(20))
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	retryConfig := models.RetryConfig{MaxAttempts: 3, BackoffSeconds: []int{60, 300}}
+
+	assert.Equal(t, time.Minute, webhookRetryDelay(retryConfig, 1))
+	assert.Equal(t, 5*time.Minute, webhookRetryDelay(retryConfig, 2))
+	assert.Equal(t, 5*time.Minute, webhookRetryDelay(retryConfig, 3))
+	assert.Equal(t, 30*time.Second, webhookRetryDelay(models.RetryConfig{MaxAttempts: 1}, 1))
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	logLevelOverrideKey         = "log_level:override"
+	defaultLogLevelRevertAfter  = 30 * time.Minute
+	logLevelOverridePublishWait = 5 * time.Second
+)
+
+var errLogLevelNoChange = models.NewAppError(http.StatusBadRequest, "Either level or modules is required")
+
+// LogLevelService changes log levels at runtime. Changes always revert to the configured
+// levels after a while, so debug logging is not left on by accident.
+//
+// Overrides are published to every gateway instance and stored in Redis until they revert,
+// so instances that start or reconnect in the meantime pick them up. Each instance reverts
+// on its own timer.
+type LogLevelService struct {
+	log         *logger.Logger
+	defaults    logger.LogLevel
+	revertAfter time.Duration
+	cache       KeyValueCache
+	bus         LogLevelBus
+	now         func() time.Time
+
+	mu       sync.Mutex
+	override *models.LogLevelOverride // nil at the configured levels
+	timer    *time.Timer
+}
+
+// NewLogLevelService creates a service managing the levels of log and its modules. A nil bus
+// keeps changes on this instance.
+func NewLogLevelService(log *logger.Logger, defaults logger.LogLevel, revertAfter time.Duration, cache KeyValueCache, bus LogLevelBus) *LogLevelService {
+	if revertAfter <= 0 {
+		revertAfter = defaultLogLevelRevertAfter
+	}
+	if _, ok := logger.ParseLevel(string(defaults)); !ok {
+		defaults = log.RootLevel()
+	}
+	return &LogLevelService{
+		log:         log,
+		defaults:    defaults,
+		revertAfter: revertAfter,
+		cache:       cache,
+		bus:         bus,
+		now:         time.Now,
+	}
+}
+
+// Get returns the current levels
+func (s *LogLevelService) Get() *models.LogLevelResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &models.LogLevelResponse{
+		Level:            string(s.log.RootLevel()),
+		DefaultLevel:     string(s.defaults),
+		Modules:          make(map[string]string),
+		AvailableModules: s.log.Modules(),
+	}
+	for module, level := range s.log.ModuleLevels() {
+		resp.Modules[module] = string(level)
+	}
+	if s.override != nil {
+		revertsAt := s.override.RevertsAt
+		resp.RevertsAt = &revertsAt
+	}
+	return resp
+}
+
+// Set replaces the runtime levels: the root level (empty keeps the configured one) and the
+// levels of the listed modules. Other modules follow the root level.
+func (s *LogLevelService) Set(ctx context.Context, req *models.SetLogLevelRequest) (*models.LogLevelResponse, error) {
+	if req.Level == "" && len(req.Modules) == 0 {
+		return nil, errLogLevelNoChange
+	}
+	if req.Level != "" {
+		if _, ok := logger.ParseLevel(req.Level); !ok {
+			return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unknown log level %q", req.Level))
+		}
+	}
+	modules := s.log.Modules()
+	for module, level := range req.Modules {
+		if !containsString(modules, module) {
+			return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unknown log module %q", module))
+		}
+		if _, ok := logger.ParseLevel(level); !ok {
+			return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unknown log level %q for module %q", level, module))
+		}
+	}
+
+	duration := s.revertAfter
+	if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+	override := &models.LogLevelOverride{
+		Level:     req.Level,
+		Modules:   req.Modules,
+		RevertsAt: s.now().Add(duration).UTC(),
+	}
+
+	s.apply(override)
+	s.share(ctx, override, duration)
+	return s.Get(), nil
+}
+
+// Reset reverts to the configured levels now
+func (s *LogLevelService) Reset(ctx context.Context) *models.LogLevelResponse {
+	s.apply(nil)
+	s.share(ctx, nil, 0)
+	return s.Get()
+}
+
+// Run applies overrides made on other instances until ctx is done
+func (s *LogLevelService) Run(ctx context.Context) {
+	if s.bus == nil {
+		<-ctx.Done()
+		return
+	}
+	s.bus.WatchLogLevel(ctx, s.received, func() {
+		// Catch up on overrides missed while not subscribed
+		s.load(ctx)
+	}, func() {})
+}
+
+// share stores and publishes an override; nil shares the revert
+func (s *LogLevelService) share(ctx context.Context, override *models.LogLevelOverride, ttl time.Duration) {
+	// The change is already applied here, so it is shared even if the request goes away
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), logLevelOverridePublishWait)
+	defer cancel()
+
+	payload := ""
+	if override != nil {
+		data, err := json.Marshal(override)
+		if err != nil {
+			return
+		}
+		payload = string(data)
+	}
+
+	if s.cache != nil {
+		var err error
+		if override != nil {
+			err = s.cache.Set(ctx, logLevelOverrideKey, payload, ttl)
+		} else {
+			err = s.cache.Delete(ctx, logLevelOverrideKey)
+		}
+		if err != nil {
+			s.log.Warn("Failed to store log level override", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	if s.bus != nil {
+		if err := s.bus.PublishLogLevel(ctx, payload); err != nil {
+			s.log.Warn("Failed to publish log level override", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+func (s *LogLevelService) load(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	payload, err := s.cache.Get(ctx, logLevelOverrideKey)
+	if err != nil {
+		// No override stored, or Redis is down: keep what this instance has
+		return
+	}
+	s.received(payload)
+}
+
+func (s *LogLevelService) received(payload string) {
+	if payload == "" {
+		s.apply(nil)
+		return
+	}
+	var override models.LogLevelOverride
+	if err := json.Unmarshal([]byte(payload), &override); err != nil {
+		s.log.Warn("Ignoring invalid log level override", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	s.apply(&override)
+}
+
+// apply sets the levels of an override, or the configured levels for nil or an expired
+// override, and schedules the revert
+func (s *LogLevelService) apply(override *models.LogLevelOverride) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applyLocked(override)
+}
+
+func (s *LogLevelService) applyLocked(override *models.LogLevelOverride) {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	wait := time.Duration(0)
+	if override != nil {
+		wait = override.RevertsAt.Sub(s.now())
+	}
+	if wait <= 0 {
+		s.override = nil
+		s.log.ResetModuleLevels()
+		s.log.SetLevel(s.defaults)
+		return
+	}
+
+	level := s.defaults
+	if override.Level != "" {
+		level = logger.LogLevel(override.Level)
+	}
+	s.log.SetLevel(level)
+	s.log.ResetModuleLevels()
+	for module, moduleLevel := range override.Modules {
+		// Modules only some instances have are skipped there
+		s.log.SetModuleLevel(module, logger.LogLevel(moduleLevel))
+	}
+	s.override = override
+
+	s.timer = time.AfterFunc(wait, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// A later override replaced this one and has its own timer
+		if s.override != override {
+			return
+		}
+		s.applyLocked(nil)
+		s.log.Info("Log levels reverted to configuration", map[string]interface{}{
+			"level": string(s.defaults),
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLogLevelBus struct {
+	published []string
+}
+
+func (m *mockLogLevelBus) PublishLogLevel(ctx context.Context, override string) error {
+	m.published = append(m.published, override)
+	return nil
+}
+
+func (m *mockLogLevelBus) WatchLogLevel(ctx context.Context, onChange func(override string), onSubscribed, onInterrupted func()) {
+	onSubscribed()
+	<-ctx.Done()
+}
+
+func setupLogLevelService() (*LogLevelService, *logger.Logger, *mockKeyValueCache, *mockLogLevelBus) {
+	log := logger.New("test", logger.InfoLevel, false)
+	log.Module("grpc")
+	log.Module("http")
+	cache := &mockKeyValueCache{values: map[string]string{}}
+	bus := &mockLogLevelBus{}
+	return NewLogLevelService(log, logger.InfoLevel, time.Hour, cache, bus), log, cache, bus
+}
+
+func TestLogLevelService_Set(t *testing.T) {
+	svc, log, cache, bus := setupLogLevelService()
+
+	resp, err := svc.Set(context.Background(), &models.SetLogLevelRequest{
+		Level:   "warn",
+		Modules: map[string]string{"grpc": "debug"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "warn", resp.Level)
+	assert.Equal(t, "info", resp.DefaultLevel)
+	assert.Equal(t, map[string]string{"grpc": "debug"}, resp.Modules)
+	assert.Equal(t, []string{"grpc", "http"}, resp.AvailableModules)
+	require.NotNil(t, resp.RevertsAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *resp.RevertsAt, time.Minute)
+
+	assert.Equal(t, logger.WarnLevel, log.RootLevel())
+	assert.Equal(t, logger.DebugLevel, log.Module("grpc").Level())
+	assert.Equal(t, logger.WarnLevel, log.Module("http").Level())
+
+	// Shared with the other instances
+	require.Len(t, bus.published, 1)
+	assert.Equal(t, bus.published[0], cache.values[logLevelOverrideKey])
+}
+
+func TestLogLevelService_SetRejectsUnknown(t *testing.T) {
+	svc, log, _, bus := setupLogLevelService()
+
+	_, err := svc.Set(context.Background(), &models.SetLogLevelRequest{})
+	assert.Error(t, err)
+	_, err = svc.Set(context.Background(), &models.SetLogLevelRequest{Level: "verbose"})
+	assert.Error(t, err)
+	_, err = svc.Set(context.Background(), &models.SetLogLevelRequest{Modules: map[string]string{"smtp": "debug"}})
+	assert.Error(t, err)
+	_, err = svc.Set(context.Background(), &models.SetLogLevelRequest{Modules: map[string]string{"grpc": "loud"}})
+	assert.Error(t, err)
+
+	assert.Equal(t, logger.InfoLevel, log.RootLevel())
+	assert.Empty(t, bus.published)
+}
+
+func TestLogLevelService_Reverts(t *testing.T) {
+	svc, log, _, _ := setupLogLevelService()
+
+	_, err := svc.Set(context.Background(), &models.SetLogLevelRequest{
+		Level:           "debug",
+		Modules:         map[string]string{"http": "error"},
+		DurationSeconds: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, logger.DebugLevel, log.RootLevel())
+
+	assert.Eventually(t, func() bool {
+		return svc.Get().RevertsAt == nil
+	}, 3*time.Second, 20*time.Millisecond)
+	assert.Equal(t, logger.InfoLevel, log.RootLevel())
+	assert.Equal(t, logger.InfoLevel, log.Module("http").Level())
+}
+
+func TestLogLevelService_Reset(t *testing.T) {
+	svc, log, cache, bus := setupLogLevelService()
+
+	_, err := svc.Set(context.Background(), &models.SetLogLevelRequest{Level: "debug"})
+	require.NoError(t, err)
+
+	resp := svc.Reset(context.Background())
+	assert.Equal(t, "info", resp.Level)
+	assert.Nil(t, resp.RevertsAt)
+	assert.Equal(t, logger.InfoLevel, log.RootLevel())
+	assert.NotContains(t, cache.values, logLevelOverrideKey)
+	require.Len(t, bus.published, 2)
+	assert.Empty(t, bus.published[1])
+}
+
+func TestLogLevelService_Received(t *testing.T) {
+	svc, log, cache, _ := setupLogLevelService()
+
+	payload, err := json.Marshal(models.LogLevelOverride{
+		Level:     "error",
+		Modules:   map[string]string{"grpc": "debug", "saml": "debug"},
+		RevertsAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	cache.values[logLevelOverrideKey] = string(payload)
+
+	// Picked up from Redis once subscribed; modules this instance lacks are skipped
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		return log.RootLevel() == logger.ErrorLevel
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+	assert.Equal(t, map[string]string{"grpc": "debug"}, svc.Get().Modules)
+
+	// Expired overrides revert right away
+	expired, err := json.Marshal(models.LogLevelOverride{Level: "debug", RevertsAt: time.Now().Add(-time.Second)})
+	require.NoError(t, err)
+	svc.received(string(expired))
+	assert.Equal(t, logger.InfoLevel, log.RootLevel())
+
+	svc.received("not json")
+	assert.Equal(t, logger.InfoLevel, log.RootLevel())
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	magicLinkPrefix     = "magic_link:"
+	magicLinkCodePrefix = "magic_link_code:"
+	// magicLinkCodeTTL is how long the app has to exchange the code it was redirected with
+	magicLinkCodeTTL = 2 * time.Minute
+	// MagicLinkVerifyPath is the gateway endpoint emailed links point at
+	MagicLinkVerifyPath = "/api/auth/magic-link/verify"
+)
+
+var (
+	errMagicLinkRedirectNotAllowed = models.NewAppError(400, "Redirect URL is not allowed")
+	errInvalidMagicLink            = models.NewAppError(400, "Invalid or expired link")
+	errInvalidMagicLinkCode        = models.NewAppError(400, "Invalid or expired code")
+)
+
+// MagicLinkService signs users in by one-click links sent to their email address. A link
+// carries a random single-use token; clicking it signs the user in like a password would,
+// so 2FA, risk checks and account state still apply. The session is not put in the redirect
+// URL: the app receives a one-time code and exchanges it for the session.
+type MagicLinkService struct {
+	userRepo    UserStore
+	appRepo     ApplicationStore
+	store       OneTimeStore
+	signIn      SignInCompleter
+	notifier    NotificationSender
+	audit       AuditLogger
+	baseURL     string
+	ttl         time.Duration
+	redirectURL string
+	allowed     map[string]bool
+	logger      *logger.Logger
+}
+
+// NewMagicLinkService creates a new magic link service.
+// baseURL is the external URL of the gateway that emailed links point at.
+func NewMagicLinkService(
+	userRepo UserStore,
+	appRepo ApplicationStore,
+	store OneTimeStore,
+	signIn SignInCompleter,
+	notifier NotificationSender,
+	audit AuditLogger,
+	baseURL string,
+	cfg config.MagicLinkConfig,
+	log *logger.Logger,
+) *MagicLinkService {
+	allowed := make(map[string]bool, len(cfg.AllowedRedirectURLs)+1)
+	allowed[cfg.RedirectURL] = true
+	for _, u := range cfg.AllowedRedirectURLs {
+		allowed[u] = true
+	}
+
+	return &MagicLinkService{
+		userRepo:    userRepo,
+		appRepo:     appRepo,
+		store:       store,
+		signIn:      signIn,
+		notifier:    notifier,
+		audit:       audit,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		ttl:         cfg.TTL,
+		redirectURL: cfg.RedirectURL,
+		allowed:     allowed,
+		logger:      log,
+	}
+}
+
+// Request emails a sign-in link to the account with the given email address. The result does
+// not reveal whether the account exists: unknown and inactive accounts are sent nothing.
+func (s *MagicLinkService) Request(ctx context.Context, req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error {
+	if err := s.signIn.CheckAuthMethodAllowed(ctx, appID, models.AuthMethodMagicLink); err != nil {
+		return err
+	}
+
+	redirectURL, err := s.resolveRedirectURL(ctx, req.RedirectURL, appID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, utils.NormalizeEmail(req.Email), utils.Ptr(true))
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if accountStateError(user) != nil {
+		return nil
+	}
+
+	token, err := generateRecoverySecret()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&models.MagicLinkToken{
+		UserID:        user.ID,
+		ApplicationID: appID,
+		RedirectURL:   redirectURL,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal magic link: %w", err)
+	}
+	if err := s.store.Set(ctx, magicLinkPrefix+utils.HashToken(token), data, s.ttl); err != nil {
+		return fmt.Errorf("failed to store magic link: %w", err)
+	}
+
+	link := s.baseURL + MagicLinkVerifyPath + "?token=" + token
+	if err := s.notifier.SendEmail(ctx, nil, appID, user.Email, models.EmailTemplateTypeMagicLink, map[string]interface{}{
+		"username":       user.Username,
+		"email":          user.Email,
+		"link":           link,
+		"expiry_minutes": int(s.ttl.Minutes()),
+	}); err != nil {
+		return fmt.Errorf("failed to send magic link: %w", err)
+	}
+
+	s.audit.Log(AuditLogParams{
+		UserID:        &user.ID,
+		ApplicationID: appID,
+		Action:        models.ActionMagicLinkRequest,
+		Status:        models.StatusSuccess,
+		IP:            ip,
+		UserAgent:     userAgent,
+		Details: map[string]interface{}{
+			"redirect_url": redirectURL,
+		},
+	})
+
+	return nil
+}
+
+// Verify redeems the token of a clicked link and returns the app URL to redirect the browser
+// to: with a code to exchange for the session, or with an error parameter when the link is
+// invalid or the account may not sign in.
+func (s *MagicLinkService) Verify(ctx context.Context, token, ip, userAgent string, deviceInfo models.DeviceInfo) string {
+	link, err := s.consumeToken(ctx, token)
+	if err != nil {
+		if !errors.Is(err, errInvalidMagicLink) {
+			s.logger.Error("Failed to redeem magic link", map[string]interface{}{"error": err.Error()})
+			return withQueryParam(s.redirectURL, "error", models.MagicLinkErrorServer)
+		}
+		return withQueryParam(s.redirectURL, "error", models.MagicLinkErrorInvalid)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, link.UserID, utils.Ptr(true), UserGetWithRoles())
+	if err != nil {
+		if isNotFound(err) {
+			return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorInvalid)
+		}
+		s.logger.Error("Failed to load magic link user", map[string]interface{}{"error": err.Error(), "user_id": link.UserID})
+		return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorServer)
+	}
+
+	authResp, err := s.signIn.CompleteSignIn(ctx, user, ip, userAgent, deviceInfo, link.ApplicationID, models.AuthMethodMagicLink)
+	if err != nil {
+		var appErr *models.AppError
+		if errors.As(err, &appErr) && appErr.Code < 500 {
+			return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorDenied)
+		}
+		s.logger.Error("Failed to sign in with magic link", map[string]interface{}{"error": err.Error(), "user_id": user.ID})
+		return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorServer)
+	}
+
+	code, err := generateExchangeCode()
+	if err != nil {
+		s.logger.Error("Failed to generate magic link code", map[string]interface{}{"error": err.Error()})
+		return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorServer)
+	}
+	data, err := json.Marshal(authResp)
+	if err == nil {
+		err = s.store.Set(ctx, magicLinkCodePrefix+utils.HashToken(code), data, magicLinkCodeTTL)
+	}
+	if err != nil {
+		s.logger.Error("Failed to store magic link code", map[string]interface{}{"error": err.Error()})
+		return withQueryParam(link.RedirectURL, "error", models.MagicLinkErrorServer)
+	}
+
+	return withQueryParam(link.RedirectURL, "code", code)
+}
+
+// Complete exchanges the code from the redirect for the session the link started. The
+// response asks for the second factor when the user has 2FA enabled.
+func (s *MagicLinkService) Complete(ctx context.Context, code string) (*models.AuthResponse, error) {
+	data, err := s.store.GetDel(ctx, magicLinkCodePrefix+utils.HashToken(code))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errInvalidMagicLinkCode
+		}
+		return nil, fmt.Errorf("failed to redeem magic link code: %w", err)
+	}
+
+	var authResp models.AuthResponse
+	if err := json.Unmarshal([]byte(data), &authResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal magic link session: %w", err)
+	}
+	return &authResp, nil
+}
+
+// consumeToken returns what a link token stands for and makes sure it cannot be used again
+func (s *MagicLinkService) consumeToken(ctx context.Context, token string) (*models.MagicLinkToken, error) {
+	if token == "" {
+		return nil, errInvalidMagicLink
+	}
+
+	data, err := s.store.GetDel(ctx, magicLinkPrefix+utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errInvalidMagicLink
+		}
+		return nil, fmt.Errorf("failed to redeem magic link: %w", err)
+	}
+
+	var link models.MagicLinkToken
+	if err := json.Unmarshal([]byte(data), &link); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal magic link: %w", err)
+	}
+	return &link, nil
+}
+
+// resolveRedirectURL returns the app URL a link lands on. Requests may name the configured
+// URLs or a callback URL of their application; anything else would let a link hand the
+// session code to a third party.
+func (s *MagicLinkService) resolveRedirectURL(ctx context.Context, requested string, appID *uuid.UUID) (string, error) {
+	if requested == "" || requested == s.redirectURL {
+		return s.redirectURL, nil
+	}
+	if s.allowed[requested] {
+		return requested, nil
+	}
+
+	if appID != nil && s.appRepo != nil {
+		app, err := s.appRepo.GetApplicationByID(ctx, *appID)
+		if err != nil && !isNotFound(err) {
+			return "", err
+		}
+		if app != nil {
+			for _, callback := range app.CallbackURLs {
+				if callback == requested {
+					return requested, nil
+				}
+			}
+		}
+	}
+
+	return "", errMagicLinkRedirectNotAllowed
+}
+
+// withQueryParam adds a query parameter to an app URL, keeping its existing ones
+func withQueryParam(rawURL, key, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOneTimeStore struct {
+	values map[string]string
+}
+
+func (m *mockOneTimeStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	m.values[key] = string(value.([]byte))
+	return nil
+}
+
+func (m *mockOneTimeStore) GetDel(ctx context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	delete(m.values, key)
+	return value, nil
+}
+
+type mockSignInCompleter struct {
+	allowed bool
+	err     error
+	method  string
+	appID   *uuid.UUID
+}
+
+func (m *mockSignInCompleter) CompleteSignIn(ctx context.Context, user *models.User, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID, authMethod string) (*models.AuthResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.method = authMethod
+	m.appID = appID
+	return &models.AuthResponse{AccessToken: "access", RefreshToken: "refresh", User: user.PublicUser()}, nil
+}
+
+func (m *mockSignInCompleter) CheckAuthMethodAllowed(ctx context.Context, appID *uuid.UUID, method string) error {
+	if !m.allowed {
+		return models.NewAppError(403, "Auth method '"+method+"' is not allowed for this application")
+	}
+	return nil
+}
+
+type magicLinkFixture struct {
+	svc      *MagicLinkService
+	user     *models.User
+	store    *mockOneTimeStore
+	signIn   *mockSignInCompleter
+	notifier *mockNotificationSender
+}
+
+func newMagicLinkFixture() *magicLinkFixture {
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Username: "user", State: models.UserStateActive}
+	userRepo := &mockUserStore{
+		GetByEmailFunc: func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			if email != user.Email {
+				return nil, models.ErrUserNotFound
+			}
+			return user, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			if id != user.ID {
+				return nil, models.ErrUserNotFound
+			}
+			return user, nil
+		},
+	}
+	appRepo := &mockApplicationStore{
+		GetApplicationByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.Application, error) {
+			return &models.Application{ID: id, IsActive: true, CallbackURLs: []string{"https://app.example.com/callback"}}, nil
+		},
+	}
+
+	f := &magicLinkFixture{
+		user:     user,
+		store:    &mockOneTimeStore{values: map[string]string{}},
+		signIn:   &mockSignInCompleter{allowed: true},
+		notifier: &mockNotificationSender{},
+	}
+	f.svc = NewMagicLinkService(userRepo, appRepo, f.store, f.signIn, f.notifier, &mockAuditLogger{}, "https://auth.example.com/", config.MagicLinkConfig{
+		Enabled:             true,
+		TTL:                 15 * time.Minute,
+		RedirectURL:         "https://app.example.com/auth/magic-link",
+		AllowedRedirectURLs: []string{"https://admin.example.com/login"},
+	}, testLogger())
+	return f
+}
+
+// requestLink requests a link for the fixture user and returns the emailed token
+func (f *magicLinkFixture) requestLink(t *testing.T, req *models.RequestMagicLinkRequest, appID *uuid.UUID) string {
+	t.Helper()
+	require.NoError(t, f.svc.Request(context.Background(), req, appID, "1.1.1.1", "ua"))
+	require.Len(t, f.notifier.variables, 1)
+
+	link, err := url.Parse(f.notifier.variables[0]["link"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "auth.example.com", link.Host)
+	assert.Equal(t, MagicLinkVerifyPath, link.Path)
+	return link.Query().Get("token")
+}
+
+func redirectParams(t *testing.T, redirectURL string) (string, url.Values) {
+	t.Helper()
+	u, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	params := u.Query()
+	u.RawQuery = ""
+	return u.String(), params
+}
+
+func TestMagicLinkService_Request(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Emails a link", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		token := f.requestLink(t, &models.RequestMagicLinkRequest{Email: "User@Example.com"}, nil)
+
+		assert.NotEmpty(t, token)
+		assert.Equal(t, []string{"user@example.com:" + models.EmailTemplateTypeMagicLink}, f.notifier.sent)
+		assert.Equal(t, 15, f.notifier.variables[0]["expiry_minutes"])
+		// Only the token hash is stored
+		for key := range f.store.values {
+			assert.False(t, strings.Contains(key, token))
+		}
+	})
+
+	t.Run("Unknown email is not revealed", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		err := f.svc.Request(ctx, &models.RequestMagicLinkRequest{Email: "nobody@example.com"}, nil, "1.1.1.1", "ua")
+
+		require.NoError(t, err)
+		assert.Empty(t, f.notifier.sent)
+		assert.Empty(t, f.store.values)
+	})
+
+	t.Run("Inactive account is sent nothing", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		f.user.State = models.UserStateSuspended
+		err := f.svc.Request(ctx, &models.RequestMagicLinkRequest{Email: "user@example.com"}, nil, "1.1.1.1", "ua")
+
+		require.NoError(t, err)
+		assert.Empty(t, f.notifier.sent)
+	})
+
+	t.Run("Auth method not allowed", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		f.signIn.allowed = false
+		err := f.svc.Request(ctx, &models.RequestMagicLinkRequest{Email: "user@example.com"}, nil, "1.1.1.1", "ua")
+
+		require.Error(t, err)
+		assert.Empty(t, f.notifier.sent)
+	})
+
+	t.Run("Redirect URLs", func(t *testing.T) {
+		appID := uuid.New()
+		for _, tc := range []struct {
+			name        string
+			redirectURL string
+			appID       *uuid.UUID
+			allowed     bool
+		}{
+			{"Configured", "https://app.example.com/auth/magic-link", nil, true},
+			{"Allowed", "https://admin.example.com/login", nil, true},
+			{"Application callback", "https://app.example.com/callback", &appID, true},
+			{"Callback without application", "https://app.example.com/callback", nil, false},
+			{"Unknown", "https://evil.example.com/", &appID, false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				f := newMagicLinkFixture()
+				err := f.svc.Request(ctx, &models.RequestMagicLinkRequest{Email: "user@example.com", RedirectURL: tc.redirectURL}, tc.appID, "1.1.1.1", "ua")
+				if tc.allowed {
+					assert.NoError(t, err)
+					assert.Len(t, f.notifier.sent, 1)
+				} else {
+					assert.ErrorIs(t, err, errMagicLinkRedirectNotAllowed)
+					assert.Empty(t, f.notifier.sent)
+				}
+			})
+		}
+	})
+}
+
+func TestMagicLinkService_VerifyAndComplete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Signs in once", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		appID := uuid.New()
+		token := f.requestLink(t, &models.RequestMagicLinkRequest{Email: "user@example.com", RedirectURL: "https://app.example.com/callback"}, &appID)
+
+		base, params := redirectParams(t, f.svc.Verify(ctx, token, "1.1.1.1", "ua", models.DeviceInfo{}))
+		assert.Equal(t, "https://app.example.com/callback", base)
+		require.NotEmpty(t, params.Get("code"))
+		assert.Equal(t, models.AuthMethodMagicLink, f.signIn.method)
+		assert.Equal(t, &appID, f.signIn.appID)
+
+		authResp, err := f.svc.Complete(ctx, params.Get("code"))
+		require.NoError(t, err)
+		assert.Equal(t, "access", authResp.AccessToken)
+		assert.Equal(t, f.user.ID, authResp.User.ID)
+
+		// Neither the code nor the link works twice
+		_, err = f.svc.Complete(ctx, params.Get("code"))
+		assert.ErrorIs(t, err, errInvalidMagicLinkCode)
+
+		base, params = redirectParams(t, f.svc.Verify(ctx, token, "1.1.1.1", "ua", models.DeviceInfo{}))
+		assert.Equal(t, "https://app.example.com/auth/magic-link", base)
+		assert.Equal(t, models.MagicLinkErrorInvalid, params.Get("error"))
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		f := newMagicLinkFixture()
+
+		_, params := redirectParams(t, f.svc.Verify(ctx, "unknown", "1.1.1.1", "ua", models.DeviceInfo{}))
+		assert.Equal(t, models.MagicLinkErrorInvalid, params.Get("error"))
+		assert.Empty(t, params.Get("code"))
+	})
+
+	t.Run("Sign-in refused", func(t *testing.T) {
+		f := newMagicLinkFixture()
+		token := f.requestLink(t, &models.RequestMagicLinkRequest{Email: "user@example.com"}, nil)
+		f.signIn.err = models.NewAppError(403, "Account is suspended")
+
+		_, params := redirectParams(t, f.svc.Verify(ctx, token, "1.1.1.1", "ua", models.DeviceInfo{}))
+		assert.Equal(t, models.MagicLinkErrorDenied, params.Get("error"))
+		assert.Empty(t, params.Get("code"))
+	})
+
+	t.Run("Unknown code", func(t *testing.T) {
+		f := newMagicLinkFixture()
+
+		_, err := f.svc.Complete(ctx, "unknown")
+		assert.ErrorIs(t, err, errInvalidMagicLinkCode)
+	})
+}
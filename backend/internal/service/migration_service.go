@@ -177,13 +177,13 @@ func (s *MigrationService) importSingleOAuthAccount(ctx context.Context, entry m
 		return fmt.Errorf("OAuth account already exists")
 	}
 
+	// Provider tokens are not imported: they are only kept encrypted by the OAuth
+	// service, which stores fresh ones on the user's next sign-in with the provider
 	oauthAccount := &models.OAuthAccount{
 		ID:             uuid.New(),
 		UserID:         user.ID,
 		Provider:       entry.Provider,
 		ProviderUserID: entry.ProviderUserID,
-		AccessToken:    entry.AccessToken,
-		RefreshToken:   entry.RefreshToken,
 	}
 
 	if err := s.oauthRepo.CreateOAuthAccount(ctx, oauthAccount); err != nil {
@@ -30,6 +30,9 @@ type mockUserStore struct {
 	CountFunc             func(ctx context.Context, isActive *bool) (int, error)
 	// Sync methods
 	GetUsersUpdatedAfterFunc func(ctx context.Context, after time.Time, appID *uuid.UUID, limit, offset int) ([]*models.User, int, error)
+	SearchFunc               func(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error)
+	FilterFunc               func(ctx context.Context, limit, offset int, opts ...UserFilterOption) ([]*models.User, error)
+	CountFilteredFunc        func(ctx context.Context, opts ...UserFilterOption) (int, error)
 	// 2FA methods
 	UpdateTOTPSecretFunc func(ctx context.Context, userID uuid.UUID, secret string) error
 	EnableTOTPFunc       func(ctx context.Context, userID uuid.UUID) error
@@ -146,6 +149,27 @@ func (m *mockUserStore) GetUsersUpdatedAfter(ctx context.Context, after time.Tim
 	return nil, 0, nil
 }
 
+func (m *mockUserStore) Search(ctx context.Context, query string, limit, offset int) ([]*models.User, int, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, query, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockUserStore) Filter(ctx context.Context, limit, offset int, opts ...UserFilterOption) ([]*models.User, error) {
+	if m.FilterFunc != nil {
+		return m.FilterFunc(ctx, limit, offset, opts...)
+	}
+	return nil, nil
+}
+
+func (m *mockUserStore) CountFiltered(ctx context.Context, opts ...UserFilterOption) (int, error) {
+	if m.CountFilteredFunc != nil {
+		return m.CountFilteredFunc(ctx, opts...)
+	}
+	return 0, nil
+}
+
 type mockTokenStore struct {
 	CreateRefreshTokenFunc  func(ctx context.Context, token *models.RefreshToken) error
 	GetRefreshTokenFunc     func(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
@@ -229,7 +253,7 @@ type mockRBACStore struct {
 	GetPermissionByIDFunc   func(ctx context.Context, id uuid.UUID) (*models.Permission, error)
 	GetPermissionByNameFunc func(ctx context.Context, name string) (*models.Permission, error)
 	ListPermissionsFunc     func(ctx context.Context) ([]models.Permission, error)
-	UpdatePermissionFunc    func(ctx context.Context, id uuid.UUID, description string) error
+	UpdatePermissionFunc    func(ctx context.Context, id uuid.UUID, description string, condition *string) error
 	DeletePermissionFunc    func(ctx context.Context, id uuid.UUID) error
 
 	// Role Methods
@@ -260,6 +284,19 @@ type mockRBACStore struct {
 	HasPermissionInAppFunc     func(ctx context.Context, userID uuid.UUID, permissionName string, appID *uuid.UUID) (bool, error)
 	GetUserRolesInAppFunc      func(ctx context.Context, userID uuid.UUID, appID *uuid.UUID) ([]models.Role, error)
 	AssignRoleToUserInAppFunc  func(ctx context.Context, userID, roleID, assignedBy uuid.UUID, appID *uuid.UUID) error
+
+	// Role Hierarchy and Permission Bundle Methods
+	ListRoleParentsFunc           func(ctx context.Context) ([]models.RoleParent, error)
+	SetRoleParentsFunc            func(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error
+	ListRoleBundlesFunc           func(ctx context.Context) ([]models.RoleBundle, error)
+	SetRoleBundlesFunc            func(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error
+	CreatePermissionBundleFunc    func(ctx context.Context, bundle *models.PermissionBundle) error
+	GetPermissionBundleByIDFunc   func(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error)
+	GetPermissionBundleByNameFunc func(ctx context.Context, name string) (*models.PermissionBundle, error)
+	ListPermissionBundlesFunc     func(ctx context.Context) ([]models.PermissionBundle, error)
+	UpdatePermissionBundleFunc    func(ctx context.Context, id uuid.UUID, displayName, description string) error
+	DeletePermissionBundleFunc    func(ctx context.Context, id uuid.UUID) error
+	SetBundlePermissionsFunc      func(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error
 }
 
 // Permission Method Implementations
@@ -287,9 +324,9 @@ func (m *mockRBACStore) ListPermissions(ctx context.Context) ([]models.Permissio
 	}
 	return nil, nil
 }
-func (m *mockRBACStore) UpdatePermission(ctx context.Context, id uuid.UUID, description string) error {
+func (m *mockRBACStore) UpdatePermission(ctx context.Context, id uuid.UUID, description string, condition *string) error {
 	if m.UpdatePermissionFunc != nil {
-		return m.UpdatePermissionFunc(ctx, id, description)
+		return m.UpdatePermissionFunc(ctx, id, description, condition)
 	}
 	return nil
 }
@@ -444,6 +481,74 @@ func (m *mockRBACStore) AssignRoleToUserInApp(ctx context.Context, userID, roleI
 	return nil
 }
 
+// Role Hierarchy and Permission Bundle Method Implementations
+func (m *mockRBACStore) ListRoleParents(ctx context.Context) ([]models.RoleParent, error) {
+	if m.ListRoleParentsFunc != nil {
+		return m.ListRoleParentsFunc(ctx)
+	}
+	return nil, nil
+}
+func (m *mockRBACStore) SetRoleParents(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	if m.SetRoleParentsFunc != nil {
+		return m.SetRoleParentsFunc(ctx, roleID, parentIDs)
+	}
+	return nil
+}
+func (m *mockRBACStore) ListRoleBundles(ctx context.Context) ([]models.RoleBundle, error) {
+	if m.ListRoleBundlesFunc != nil {
+		return m.ListRoleBundlesFunc(ctx)
+	}
+	return nil, nil
+}
+func (m *mockRBACStore) SetRoleBundles(ctx context.Context, roleID uuid.UUID, bundleIDs []uuid.UUID) error {
+	if m.SetRoleBundlesFunc != nil {
+		return m.SetRoleBundlesFunc(ctx, roleID, bundleIDs)
+	}
+	return nil
+}
+func (m *mockRBACStore) CreatePermissionBundle(ctx context.Context, bundle *models.PermissionBundle) error {
+	if m.CreatePermissionBundleFunc != nil {
+		return m.CreatePermissionBundleFunc(ctx, bundle)
+	}
+	return nil
+}
+func (m *mockRBACStore) GetPermissionBundleByID(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	if m.GetPermissionBundleByIDFunc != nil {
+		return m.GetPermissionBundleByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+func (m *mockRBACStore) GetPermissionBundleByName(ctx context.Context, name string) (*models.PermissionBundle, error) {
+	if m.GetPermissionBundleByNameFunc != nil {
+		return m.GetPermissionBundleByNameFunc(ctx, name)
+	}
+	return nil, nil
+}
+func (m *mockRBACStore) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	if m.ListPermissionBundlesFunc != nil {
+		return m.ListPermissionBundlesFunc(ctx)
+	}
+	return nil, nil
+}
+func (m *mockRBACStore) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, displayName, description string) error {
+	if m.UpdatePermissionBundleFunc != nil {
+		return m.UpdatePermissionBundleFunc(ctx, id, displayName, description)
+	}
+	return nil
+}
+func (m *mockRBACStore) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	if m.DeletePermissionBundleFunc != nil {
+		return m.DeletePermissionBundleFunc(ctx, id)
+	}
+	return nil
+}
+func (m *mockRBACStore) SetBundlePermissions(ctx context.Context, bundleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	if m.SetBundlePermissionsFunc != nil {
+		return m.SetBundlePermissionsFunc(ctx, bundleID, permissionIDs)
+	}
+	return nil
+}
+
 type mockCacheService struct {
 	IsBlacklistedFunc             func(ctx context.Context, tokenHash string) (bool, error)
 	AddToBlacklistFunc            func(ctx context.Context, tokenHash string, expiration time.Duration) error
@@ -493,6 +598,8 @@ func (m *mockCacheService) DeletePendingRegistration(ctx context.Context, identi
 type mockSMSLogStore struct {
 	CreateFunc          func(ctx context.Context, log *models.SMSLog) error
 	UpdateStatusFunc    func(ctx context.Context, id uuid.UUID, status models.SMSStatus, errorMsg *string) error
+	MarkSentFunc        func(ctx context.Context, id uuid.UUID, messageID string) error
+	GetByMessageIDFunc  func(ctx context.Context, provider, messageID string) (*models.SMSLog, error)
 	GetStatsFunc        func(ctx context.Context) (*models.SMSStatsResponse, error)
 	DeleteOlderThanFunc func(ctx context.Context, duration time.Duration) (int64, error)
 }
@@ -509,6 +616,18 @@ func (m *mockSMSLogStore) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	}
 	return nil
 }
+func (m *mockSMSLogStore) MarkSent(ctx context.Context, id uuid.UUID, messageID string) error {
+	if m.MarkSentFunc != nil {
+		return m.MarkSentFunc(ctx, id, messageID)
+	}
+	return nil
+}
+func (m *mockSMSLogStore) GetByMessageID(ctx context.Context, provider, messageID string) (*models.SMSLog, error) {
+	if m.GetByMessageIDFunc != nil {
+		return m.GetByMessageIDFunc(ctx, provider, messageID)
+	}
+	return nil, models.ErrNotFound
+}
 func (m *mockSMSLogStore) GetStats(ctx context.Context) (*models.SMSStatsResponse, error) {
 	if m.GetStatsFunc != nil {
 		return m.GetStatsFunc(ctx)
@@ -523,14 +642,18 @@ func (m *mockSMSLogStore) DeleteOlderThan(ctx context.Context, duration time.Dur
 }
 
 type mockTokenService struct {
-	GenerateAccessTokenFunc       func(user *models.User, applicationID ...*uuid.UUID) (string, error)
-	GenerateRefreshTokenFunc      func(user *models.User, applicationID ...*uuid.UUID) (string, error)
-	GenerateTwoFactorTokenFunc    func(user *models.User, applicationID ...*uuid.UUID) (string, error)
-	ValidateAccessTokenFunc       func(tokenString string) (*jwt.Claims, error)
-	ValidateRefreshTokenFunc      func(tokenString string) (*jwt.Claims, error)
-	ExtractClaimsFunc             func(tokenString string) (*jwt.Claims, error)
-	GetAccessTokenExpirationFunc  func() time.Duration
-	GetRefreshTokenExpirationFunc func() time.Duration
+	GenerateAccessTokenFunc         func(user *models.User, applicationID ...*uuid.UUID) (string, error)
+	GenerateRefreshTokenFunc        func(user *models.User, applicationID ...*uuid.UUID) (string, error)
+	GenerateTwoFactorTokenFunc      func(user *models.User, applicationID ...*uuid.UUID) (string, error)
+	GeneratePasswordChangeTokenFunc func(user *models.User) (string, error)
+	ValidatePasswordChangeTokenFunc func(tokenString string) (*jwt.Claims, error)
+	GenerateOAuthLinkTokenFunc      func(user *models.User, provider, providerUserID string, applicationID *uuid.UUID) (string, error)
+	ValidateOAuthLinkTokenFunc      func(tokenString string) (*jwt.Claims, error)
+	ValidateAccessTokenFunc         func(tokenString string) (*jwt.Claims, error)
+	ValidateRefreshTokenFunc        func(tokenString string) (*jwt.Claims, error)
+	ExtractClaimsFunc               func(tokenString string) (*jwt.Claims, error)
+	GetAccessTokenExpirationFunc    func() time.Duration
+	GetRefreshTokenExpirationFunc   func() time.Duration
 }
 
 func (m *mockTokenService) GenerateAccessToken(user *models.User, applicationID ...*uuid.UUID) (string, error) {
@@ -551,6 +674,30 @@ func (m *mockTokenService) GenerateTwoFactorToken(user *models.User, application
 	}
 	return "", nil
 }
+func (m *mockTokenService) GeneratePasswordChangeToken(user *models.User) (string, error) {
+	if m.GeneratePasswordChangeTokenFunc != nil {
+		return m.GeneratePasswordChangeTokenFunc(user)
+	}
+	return "", nil
+}
+func (m *mockTokenService) ValidatePasswordChangeToken(tokenString string) (*jwt.Claims, error) {
+	if m.ValidatePasswordChangeTokenFunc != nil {
+		return m.ValidatePasswordChangeTokenFunc(tokenString)
+	}
+	return nil, nil
+}
+func (m *mockTokenService) GenerateOAuthLinkToken(user *models.User, provider, providerUserID string, applicationID *uuid.UUID) (string, error) {
+	if m.GenerateOAuthLinkTokenFunc != nil {
+		return m.GenerateOAuthLinkTokenFunc(user, provider, providerUserID, applicationID)
+	}
+	return "", nil
+}
+func (m *mockTokenService) ValidateOAuthLinkToken(tokenString string) (*jwt.Claims, error) {
+	if m.ValidateOAuthLinkTokenFunc != nil {
+		return m.ValidateOAuthLinkTokenFunc(tokenString)
+	}
+	return nil, nil
+}
 func (m *mockTokenService) ValidateAccessToken(tokenString string) (*jwt.Claims, error) {
 	if m.ValidateAccessTokenFunc != nil {
 		return m.ValidateAccessTokenFunc(tokenString)
@@ -811,6 +958,9 @@ type mockAuditStore struct {
 	DeleteOlderThanFunc        func(ctx context.Context, days int) error
 	CountByActionSinceFunc     func(ctx context.Context, action models.AuditAction, since time.Time) (int, error)
 	ListByAppFunc              func(ctx context.Context, appID uuid.UUID, limit, offset int) ([]*models.AuditLog, int, error)
+	SearchFunc                 func(ctx context.Context, limit, offset int, opts ...AuditLogListOption) ([]*models.AuditLog, int, error)
+	CountByActionFunc          func(ctx context.Context, limit int, opts ...AuditLogListOption) ([]*models.AuditActionCount, error)
+	CountFailuresByHourFunc    func(ctx context.Context, opts ...AuditLogListOption) ([]*models.AuditHourlyCount, error)
 }
 
 func (m *mockAuditStore) Create(ctx context.Context, log *models.AuditLog) error {
@@ -867,6 +1017,24 @@ func (m *mockAuditStore) ListByApp(ctx context.Context, appID uuid.UUID, limit,
 	}
 	return nil, 0, nil
 }
+func (m *mockAuditStore) Search(ctx context.Context, limit, offset int, opts ...AuditLogListOption) ([]*models.AuditLog, int, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, limit, offset, opts...)
+	}
+	return nil, 0, nil
+}
+func (m *mockAuditStore) CountByAction(ctx context.Context, limit int, opts ...AuditLogListOption) ([]*models.AuditActionCount, error) {
+	if m.CountByActionFunc != nil {
+		return m.CountByActionFunc(ctx, limit, opts...)
+	}
+	return nil, nil
+}
+func (m *mockAuditStore) CountFailuresByHour(ctx context.Context, opts ...AuditLogListOption) ([]*models.AuditHourlyCount, error) {
+	if m.CountFailuresByHourFunc != nil {
+		return m.CountFailuresByHourFunc(ctx, opts...)
+	}
+	return nil, nil
+}
 
 
 type mockHTTPClient struct {
@@ -1192,6 +1360,45 @@ func (m *mockBlacklistChecker) BlacklistAllUserSessions(ctx context.Context, use
 	return nil
 }
 
+type mockPasswordHistoryStore struct {
+	entries []*models.PasswordHistory // newest first
+	pruned  int
+}
+
+func (m *mockPasswordHistoryStore) Create(ctx context.Context, entry *models.PasswordHistory) error {
+	m.entries = append([]*models.PasswordHistory{entry}, m.entries...)
+	return nil
+}
+
+func (m *mockPasswordHistoryStore) GetRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*models.PasswordHistory, error) {
+	if len(m.entries) > limit {
+		return m.entries[:limit], nil
+	}
+	return m.entries, nil
+}
+
+func (m *mockPasswordHistoryStore) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	if len(m.entries) > keep {
+		m.pruned += len(m.entries) - keep
+		m.entries = m.entries[:keep]
+	}
+	return nil
+}
+
+type mockTokenVersionChecker struct {
+	revoked bool
+	bumped  []uuid.UUID
+}
+
+func (m *mockTokenVersionChecker) IsRevoked(ctx context.Context, claims *jwt.Claims) bool {
+	return m.revoked
+}
+
+func (m *mockTokenVersionChecker) Bump(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.bumped = append(m.bumped, userID)
+	return len(m.bumped), nil
+}
+
 type mockSessionManager struct {
 	CreateSessionNonFatalFunc  func(ctx context.Context, params SessionCreationParams) *models.Session
 	RefreshSessionNonFatalFunc func(ctx context.Context, params SessionRefreshParams) bool
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// appleIssuer is the issuer of Apple ID tokens and the audience of client secrets
+	appleIssuer = "https://appleid.apple.com"
+	// appleClientSecretTTL keeps client secrets short-lived; Apple accepts up to six months
+	// but a new one is signed for every request anyway
+	appleClientSecretTTL = 5 * time.Minute
+)
+
+// appleClientSecret signs the client secret Sign in with Apple expects instead of a static
+// one: an ES256 JWT issued by the team for the Services ID, signed with the private key
+// (.p8) whose ID is in the header.
+func appleClientSecret(config *OAuthProviderConfig, now time.Time) (string, error) {
+	if config.TeamID == "" || config.KeyID == "" {
+		return "", fmt.Errorf("apple team ID and key ID are required")
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(config.ClientSecret))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse apple private key: %w", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    config.TeamID,
+		Subject:   config.ClientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appleClientSecretTTL)),
+	})
+	token.Header["kid"] = config.KeyID
+
+	secret, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign apple client secret: %w", err)
+	}
+	return secret, nil
+}
+
+// parseAppleIDToken returns the claims of the ID token Apple issued with the access token.
+// Apple has no user info endpoint; the user is described by this token alone.
+func parseAppleIDToken(config *OAuthProviderConfig, idToken string) (map[string]interface{}, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("apple token response has no ID token")
+	}
+
+	claims, err := parseIDToken(idToken, appleIssuer, config.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("apple: %w", err)
+	}
+	return claims, nil
+}
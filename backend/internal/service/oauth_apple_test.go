@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAppleConfig returns a Sign in with Apple configuration with a fresh private key
+func newAppleConfig(t *testing.T) (*OAuthProviderConfig, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &OAuthProviderConfig{
+		ClientID:     "com.example.web",
+		ClientSecret: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		CallbackURL:  "https://auth.example.com/api/auth/apple/callback",
+		AuthURL:      appleIssuer + "/auth/authorize",
+		TokenURL:     appleIssuer + "/auth/token",
+		Scopes:       []string{"name", "email"},
+		TeamID:       "TEAM123456",
+		KeyID:        "KEY1234567",
+	}, key
+}
+
+// appleIDToken returns an ID token with the given claims, signed with an unrelated key
+func appleIDToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAppleClientSecret(t *testing.T) {
+	config, key := newAppleConfig(t)
+	now := time.Now()
+
+	secret, err := appleClientSecret(config, now)
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(secret, &claims, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	require.NoError(t, err)
+	assert.Equal(t, "KEY1234567", token.Header["kid"])
+	assert.Equal(t, "TEAM123456", claims.Issuer)
+	assert.Equal(t, "com.example.web", claims.Subject)
+	assert.Equal(t, jwt.ClaimStrings{appleIssuer}, claims.Audience)
+	assert.WithinDuration(t, now.Add(appleClientSecretTTL), claims.ExpiresAt.Time, time.Second)
+
+	t.Run("Requires team and key", func(t *testing.T) {
+		_, err := appleClientSecret(&OAuthProviderConfig{ClientSecret: config.ClientSecret}, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("Requires a private key", func(t *testing.T) {
+		_, err := appleClientSecret(&OAuthProviderConfig{TeamID: "TEAM", KeyID: "KEY", ClientSecret: "secret"}, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseAppleIDToken(t *testing.T) {
+	config, _ := newAppleConfig(t)
+	exp := time.Now().Add(10 * time.Minute).Unix()
+
+	for _, tc := range []struct {
+		name   string
+		claims jwt.MapClaims
+		valid  bool
+	}{
+		{"Valid", jwt.MapClaims{"iss": appleIssuer, "aud": "com.example.web", "exp": exp, "sub": "001234.abcd"}, true},
+		{"Other audience", jwt.MapClaims{"iss": appleIssuer, "aud": "com.example.other", "exp": exp, "sub": "001234.abcd"}, false},
+		{"Other issuer", jwt.MapClaims{"iss": "https://evil.example.com", "aud": "com.example.web", "exp": exp, "sub": "001234.abcd"}, false},
+		{"Expired", jwt.MapClaims{"iss": appleIssuer, "aud": "com.example.web", "exp": time.Now().Add(-time.Hour).Unix(), "sub": "001234.abcd"}, false},
+		{"No expiry", jwt.MapClaims{"iss": appleIssuer, "aud": "com.example.web", "sub": "001234.abcd"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := parseAppleIDToken(config, appleIDToken(t, tc.claims))
+			if tc.valid {
+				require.NoError(t, err)
+				assert.Equal(t, "001234.abcd", claims["sub"])
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := parseAppleIDToken(config, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestOAuthService_Apple(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _, _, _, _, mHTTP := setupOAuthService()
+	config, _ := newAppleConfig(t)
+	svc.providers[models.ProviderApple] = config
+
+	t.Run("Auth URL posts the callback", func(t *testing.T) {
+		authURL, err := svc.GetAuthURL(ctx, models.ProviderApple, "test-state", nil)
+
+		require.NoError(t, err)
+		assert.Contains(t, authURL, appleIssuer+"/auth/authorize?")
+		assert.Contains(t, authURL, "response_mode=form_post")
+		assert.Contains(t, authURL, "scope=name+email")
+	})
+
+	t.Run("Signs in with the ID token", func(t *testing.T) {
+		idToken := appleIDToken(t, jwt.MapClaims{
+			"iss":            appleIssuer,
+			"aud":            "com.example.web",
+			"exp":            time.Now().Add(10 * time.Minute).Unix(),
+			"sub":            "001234.abcd",
+			"email":          "abc@privaterelay.appleid.com",
+			"email_verified": "true",
+		})
+		mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, appleIssuer+"/auth/token", req.URL.String())
+			require.NoError(t, req.ParseForm())
+			assert.Equal(t, "auth-code", req.PostForm.Get("code"))
+			// The client secret is a JWT, not the private key
+			secret := jwt.MapClaims{}
+			_, _, err := jwt.NewParser().ParseUnverified(req.PostForm.Get("client_secret"), secret)
+			require.NoError(t, err)
+			assert.Equal(t, "com.example.web", secret["sub"])
+			return newJSONResponse(http.StatusOK, OAuthTokenResponse{
+				AccessToken: "apple-access-token",
+				TokenType:   "Bearer",
+				ExpiresIn:   3600,
+				IDToken:     idToken,
+			}), nil
+		}
+
+		tokenResp, err := svc.ExchangeCode(ctx, models.ProviderApple, "auth-code", nil)
+		require.NoError(t, err)
+		info, err := svc.providerUserInfo(ctx, models.ProviderApple, tokenResp, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "001234.abcd", info.ProviderUserID)
+		assert.Equal(t, "abc@privaterelay.appleid.com", info.Email)
+		assert.True(t, info.EmailVerified)
+		assert.Equal(t, "apple", info.Provider)
+	})
+}
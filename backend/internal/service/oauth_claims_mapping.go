@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// validateClaimsMapping rejects a claims mapping that would override standard claims or
+// bloat tokens
+func validateClaimsMapping(mapping *models.ClaimsMapping) error {
+	if err := mapping.Validate(); err != nil {
+		return models.NewAppError(http.StatusBadRequest, "claims_mapping: "+err.Error())
+	}
+	return nil
+}
+
+// nonEmptyClaimsMapping stores an empty mapping as none
+func nonEmptyClaimsMapping(mapping *models.ClaimsMapping) *models.ClaimsMapping {
+	if mapping.IsEmpty() {
+		return nil
+	}
+	return mapping
+}
+
+// customClaims returns the claims client's claims mapping adds for user. The user's roles
+// must be loaded when the mapping has a roles claim.
+func (s *OAuthProviderService) customClaims(ctx context.Context, client *models.OAuthClient, user *models.User) (map[string]interface{}, error) {
+	if client == nil || client.ClaimsMapping.IsEmpty() {
+		return nil, nil
+	}
+	mapping := client.ClaimsMapping
+
+	var roles, groups []string
+	if mapping.RolesClaim != "" {
+		for _, role := range user.Roles {
+			roles = append(roles, role.Name)
+		}
+	}
+	if mapping.GroupsClaim != "" && s.groups != nil {
+		userGroups, err := s.groups.GetUserGroups(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range userGroups {
+			groups = append(groups, group.Name)
+		}
+	}
+	return mapping.Claims(user, roles, groups), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOAuthClientLogoStore struct {
+	logos map[uuid.UUID]*models.OAuthClientLogo
+}
+
+func (m *mockOAuthClientLogoStore) Save(ctx context.Context, logo *models.OAuthClientLogo) error {
+	m.logos[logo.ClientID] = logo
+	return nil
+}
+
+func (m *mockOAuthClientLogoStore) GetByHash(ctx context.Context, hash string) (*models.OAuthClientLogo, error) {
+	for _, logo := range m.logos {
+		if logo.Hash == hash {
+			return logo, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockOAuthClientLogoStore) Delete(ctx context.Context, clientID uuid.UUID) error {
+	delete(m.logos, clientID)
+	return nil
+}
+
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+func setupOAuthClientLogoService(maxBytes int) (*OAuthClientLogoService, *mockOAuthClientLogoStore, *models.OAuthClient) {
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.LogoURL = "https://tracker.example.net/logo.png"
+
+	clientRepo := &mockOAuthProviderStore{}
+	clientRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	clientRepo.UpdateClientFunc = func(ctx context.Context, c *models.OAuthClient) error {
+		return nil
+	}
+
+	store := &mockOAuthClientLogoStore{logos: make(map[uuid.UUID]*models.OAuthClientLogo)}
+	svc := NewOAuthClientLogoService(store, clientRepo, logger.New("test", logger.DebugLevel, false), "https://auth.example.com/", maxBytes)
+	return svc, store, client
+}
+
+func TestOAuthClientLogoUpload_ShouldStoreLogoAndPointClientAtIt(t *testing.T) {
+	svc, store, client := setupOAuthClientLogoService(0)
+
+	updated, err := svc.Upload(context.Background(), client.ID, bytes.NewReader(testPNG))
+	require.NoError(t, err)
+
+	logo := store.logos[client.ID]
+	require.NotNil(t, logo)
+	assert.Equal(t, "image/png", logo.ContentType)
+	assert.Equal(t, len(testPNG), logo.Size)
+	assert.Equal(t, "https://auth.example.com/oauth/logos/"+logo.Hash+".png", updated.LogoURL)
+	assert.True(t, models.IsManagedLogoURL(updated.LogoURL, "https://auth.example.com"))
+
+	served, err := svc.Get(context.Background(), logo.Hash+".png")
+	require.NoError(t, err)
+	assert.Equal(t, testPNG, served.Content)
+}
+
+func TestOAuthClientLogoUpload_ShouldRejectInvalidContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		maxBytes int
+		code     int
+	}{
+		{"empty", nil, 0, http.StatusBadRequest},
+		{"too large", testPNG, len(testPNG) - 1, http.StatusRequestEntityTooLarge},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), 0, http.StatusUnsupportedMediaType},
+		{"html", []byte("<html><body>hi</body></html>"), 0, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, client := setupOAuthClientLogoService(tt.maxBytes)
+
+			_, err := svc.Upload(context.Background(), client.ID, bytes.NewReader(tt.content))
+
+			var appErr *models.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Empty(t, store.logos)
+		})
+	}
+}
+
+func TestOAuthClientLogoGet_ShouldRejectMalformedOrMismatchedNames(t *testing.T) {
+	svc, _, client := setupOAuthClientLogoService(0)
+	_, err := svc.Upload(context.Background(), client.ID, bytes.NewReader(testPNG))
+	require.NoError(t, err)
+	hash := strings.TrimSuffix(client.LogoURL[strings.LastIndex(client.LogoURL, "/")+1:], ".png")
+
+	for _, name := range []string{hash + ".gif", hash, "../../etc/passwd", "abc.png"} {
+		_, err := svc.Get(context.Background(), name)
+		assert.ErrorIs(t, err, models.ErrNotFound, name)
+	}
+}
+
+func TestOAuthClientLogoDelete_ShouldClearLogoURL(t *testing.T) {
+	svc, store, client := setupOAuthClientLogoService(0)
+	_, err := svc.Upload(context.Background(), client.ID, bytes.NewReader(testPNG))
+	require.NoError(t, err)
+
+	updated, err := svc.Delete(context.Background(), client.ID)
+
+	require.NoError(t, err)
+	assert.Empty(t, updated.LogoURL)
+	assert.Empty(t, store.logos)
+}
+
+func TestGetConsentInfo_ShouldOnlyExposeManagedLogoURL(t *testing.T) {
+	for logoURL, want := range map[string]string{
+		"https://tracker.example.net/logo.png":                "",
+		"https://auth.example.com/oauth/logos/abc.png":        "https://auth.example.com/oauth/logos/abc.png",
+		"/oauth/logos/abc.png":                                "/oauth/logos/abc.png",
+		"https://auth.example.com.evil.net/oauth/logos/a.png": "",
+	} {
+		svc, mRepo, _, _ := setupOAuthProviderService()
+		client := createTestClient(string(models.ClientTypeConfidential))
+		client.LogoURL = logoURL
+		mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+			return client, nil
+		}
+
+		info, err := svc.GetConsentInfo(context.Background(), client.ClientID, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, info.LogoURL, logoURL)
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const oauthClientWebhookTimeout = 15 * time.Second
+
+var (
+	errClientWebhookURL     = models.NewAppError(http.StatusBadRequest, "Webhook URL must be an absolute https URL")
+	errClientWebhookAddress = errors.New("webhook address is not publicly routable")
+)
+
+// OAuthClientWebhookService lets the owner of an OAuth client manage webhooks for events about that
+// client. The webhooks are kept apart from the admin webhooks: they have their own storage, only
+// accept the oauth_client.* events and only ever receive events about their own client.
+type OAuthClientWebhookService struct {
+	store      OAuthClientWebhookStore
+	clientRepo OAuthClientRepository
+	audit      AuditLogger
+	logger     *logger.Logger
+	httpClient *http.Client
+}
+
+// NewOAuthClientWebhookService creates a new OAuth client webhook service. Deliveries are only made
+// to public addresses, since the URLs come from client owners rather than admins.
+func NewOAuthClientWebhookService(store OAuthClientWebhookStore, clientRepo OAuthClientRepository, audit AuditLogger, log *logger.Logger) *OAuthClientWebhookService {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: webhookDialControl,
+	}
+	return &OAuthClientWebhookService{
+		store:      store,
+		clientRepo: clientRepo,
+		audit:      audit,
+		logger:     log,
+		httpClient: &http.Client{
+			Timeout: oauthClientWebhookTimeout,
+			// No proxy, the dialer has to see the webhook's own address
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// EnableTracing records a span for each delivery and passes the trace on in the
+// traceparent header
+func (s *OAuthClientWebhookService) EnableTracing() {
+	s.httpClient.Transport = tracing.Transport(s.httpClient.Transport)
+}
+
+// ListWebhooks lists the webhooks of a client owned by ownerID
+func (s *OAuthClientWebhookService) ListWebhooks(ctx context.Context, ownerID, clientID uuid.UUID) (*models.OAuthClientWebhookListResponse, error) {
+	if _, err := s.ownedClient(ctx, ownerID, clientID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.store.ListByClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OAuthClientWebhookListResponse{
+		Webhooks:        webhooks,
+		AvailableEvents: models.GetOAuthClientWebhookEvents(),
+	}, nil
+}
+
+// CreateWebhook registers a webhook for a client owned by ownerID. The secret key is only returned here.
+func (s *OAuthClientWebhookService) CreateWebhook(ctx context.Context, ownerID, clientID uuid.UUID, req *models.CreateOAuthClientWebhookRequest) (*models.CreateOAuthClientWebhookResponse, error) {
+	client, err := s.ownedClient(ctx, ownerID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateClientWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := validateClientWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	secretKey := hex.EncodeToString(secretBytes)
+
+	webhook := &models.OAuthClientWebhook{
+		ClientID:  clientID,
+		URL:       req.URL,
+		SecretKey: secretKey,
+		Events:    events,
+		IsActive:  true,
+		CreatedBy: &ownerID,
+	}
+	if err := s.store.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ownerID, client, webhook, models.ActionCreate)
+
+	return &models.CreateOAuthClientWebhookResponse{
+		Webhook:   webhook,
+		SecretKey: secretKey,
+	}, nil
+}
+
+// GetWebhook retrieves a webhook of a client owned by ownerID
+func (s *OAuthClientWebhookService) GetWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID) (*models.OAuthClientWebhook, error) {
+	if _, err := s.ownedClient(ctx, ownerID, clientID); err != nil {
+		return nil, err
+	}
+	return s.store.GetByID(ctx, clientID, id)
+}
+
+// UpdateWebhook changes the URL, events or active flag of a webhook of a client owned by ownerID
+func (s *OAuthClientWebhookService) UpdateWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID, req *models.UpdateOAuthClientWebhookRequest) (*models.OAuthClientWebhook, error) {
+	client, err := s.ownedClient(ctx, ownerID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.store.GetByID(ctx, clientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		if err := validateClientWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = req.URL
+	}
+	if len(req.Events) > 0 {
+		events, err := validateClientWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+
+	if err := s.store.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ownerID, client, webhook, models.ActionUpdate)
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook of a client owned by ownerID
+func (s *OAuthClientWebhookService) DeleteWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID) error {
+	client, err := s.ownedClient(ctx, ownerID, clientID)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := s.store.GetByID(ctx, clientID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, clientID, id); err != nil {
+		return err
+	}
+
+	s.logAudit(ownerID, client, webhook, models.ActionDelete)
+
+	return nil
+}
+
+// ListDeliveries lists the deliveries of a webhook of a client owned by ownerID
+func (s *OAuthClientWebhookService) ListDeliveries(ctx context.Context, ownerID, clientID, id uuid.UUID, page, perPage int) (*models.OAuthClientWebhookDeliveryListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	if _, err := s.GetWebhook(ctx, ownerID, clientID, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.store.ListDeliveries(ctx, id, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OAuthClientWebhookDeliveryListResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Page:       page,
+		PageSize:   perPage,
+		TotalPages: (total + perPage - 1) / perPage,
+	}, nil
+}
+
+// NotifyClientEvent delivers an event to the active webhooks of a client subscribed to it.
+// The public client_id is added to data.
+func (s *OAuthClientWebhookService) NotifyClientEvent(ctx context.Context, clientID uuid.UUID, eventType string, data map[string]interface{}) error {
+	webhooks, err := s.store.ListActiveByEvent(ctx, clientID, eventType)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	client, err := s.clientRepo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get oauth client: %w", err)
+	}
+
+	eventData := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		eventData[key] = value
+	}
+	eventData["client_id"] = client.ClientID
+
+	event := models.WebhookEvent{
+		EventType: eventType,
+		Timestamp: time.Now().UTC(),
+		Data:      eventData,
+	}
+	for _, webhook := range webhooks {
+		s.deliver(ctx, webhook, event)
+	}
+
+	return nil
+}
+
+// deliver posts an event to a single webhook and records the attempt
+func (s *OAuthClientWebhookService) deliver(ctx context.Context, webhook *models.OAuthClientWebhook, event models.WebhookEvent) {
+	payload, _ := json.Marshal(event)
+
+	delivery := &models.OAuthClientWebhookDelivery{
+		WebhookID: webhook.ID,
+		EventType: event.EventType,
+		Payload:   payload,
+		Status:    "pending",
+	}
+	if err := s.store.CreateDelivery(ctx, delivery); err != nil {
+		s.logger.Warn("failed to record oauth client webhook delivery", map[string]interface{}{
+			"webhook_id": webhook.ID.String(),
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	status, httpStatus, detail := "success", 0, ""
+	resp, err := s.post(ctx, webhook, event.EventType, payload)
+	switch {
+	case err != nil:
+		status, detail = "failed", err.Error()
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		status, httpStatus, detail = "failed", resp.StatusCode, "non-2xx response"
+	default:
+		httpStatus = resp.StatusCode
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	var statusPtr *int
+	if httpStatus > 0 {
+		statusPtr = &httpStatus
+	}
+	if err := s.store.CompleteDelivery(ctx, delivery.ID, status, statusPtr, detail); err != nil {
+		s.logger.Warn("failed to complete oauth client webhook delivery", map[string]interface{}{
+			"delivery_id": delivery.ID.String(),
+			"error":       err.Error(),
+		})
+	}
+	if status == "success" {
+		if err := s.store.MarkTriggered(ctx, webhook.ID); err != nil {
+			s.logger.Warn("failed to mark oauth client webhook triggered", map[string]interface{}{
+				"webhook_id": webhook.ID.String(),
+				"error":      err.Error(),
+			})
+		}
+	}
+}
+
+func (s *OAuthClientWebhookService) post(ctx context.Context, webhook *models.OAuthClientWebhook, eventType string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setWebhookSignature(req, payload, webhook.SecretKey)
+	req.Header.Set("X-Webhook-Event", eventType)
+
+	return s.httpClient.Do(req)
+}
+
+// ownedClient loads a client and checks that ownerID owns it. Clients of other owners are reported
+// as missing so their existence is not revealed.
+func (s *OAuthClientWebhookService) ownedClient(ctx context.Context, ownerID, clientID uuid.UUID) (*models.OAuthClient, error) {
+	client, err := s.clientRepo.GetClientByID(ctx, clientID)
+	if err != nil {
+		return nil, models.ErrNotFound
+	}
+	if client.OwnerID == nil || *client.OwnerID != ownerID {
+		return nil, models.ErrNotFound
+	}
+	return client, nil
+}
+
+func (s *OAuthClientWebhookService) logAudit(ownerID uuid.UUID, client *models.OAuthClient, webhook *models.OAuthClientWebhook, action models.AuditAction) {
+	s.audit.Log(AuditLogParams{
+		UserID: &ownerID,
+		Action: action,
+		Status: models.StatusSuccess,
+		Details: map[string]interface{}{
+			"resource_type": models.ResourceWebhook,
+			"webhook_id":    webhook.ID.String(),
+			"client_id":     client.ClientID,
+			"url":           webhook.URL,
+		},
+	})
+}
+
+func validateClientWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return errClientWebhookURL
+	}
+	return nil
+}
+
+// validateClientWebhookEvents checks events against the OAuth client events and drops duplicates
+func validateClientWebhookEvents(events []string) ([]string, error) {
+	available := make(map[string]bool)
+	for _, event := range models.GetOAuthClientWebhookEvents() {
+		available[event] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		if !available[event] {
+			return nil, models.NewAppError(http.StatusBadRequest, "Invalid event type", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// webhookDialControl refuses connections to loopback, private, link-local and unspecified addresses,
+// so webhooks cannot reach the gateway's own network. It runs after DNS resolution, which also covers
+// public names resolving to internal addresses.
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errClientWebhookAddress
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/testutil/fixtures"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryClientWebhookStore keeps OAuth client webhooks and deliveries in memory
+type memoryClientWebhookStore struct {
+	webhooks   []*models.OAuthClientWebhook
+	deliveries []*models.OAuthClientWebhookDelivery
+}
+
+func (m *memoryClientWebhookStore) Create(ctx context.Context, webhook *models.OAuthClientWebhook) error {
+	webhook.ID = uuid.New()
+	m.webhooks = append(m.webhooks, webhook)
+	return nil
+}
+
+func (m *memoryClientWebhookStore) GetByID(ctx context.Context, clientID, id uuid.UUID) (*models.OAuthClientWebhook, error) {
+	for _, webhook := range m.webhooks {
+		if webhook.ID == id && webhook.ClientID == clientID {
+			return webhook, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *memoryClientWebhookStore) ListByClient(ctx context.Context, clientID uuid.UUID) ([]*models.OAuthClientWebhook, error) {
+	var result []*models.OAuthClientWebhook
+	for _, webhook := range m.webhooks {
+		if webhook.ClientID == clientID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryClientWebhookStore) ListActiveByEvent(ctx context.Context, clientID uuid.UUID, eventType string) ([]*models.OAuthClientWebhook, error) {
+	var result []*models.OAuthClientWebhook
+	for _, webhook := range m.webhooks {
+		if webhook.ClientID == clientID && webhook.IsActive && webhook.Subscribes(eventType) {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryClientWebhookStore) Update(ctx context.Context, webhook *models.OAuthClientWebhook) error {
+	return nil
+}
+
+func (m *memoryClientWebhookStore) Delete(ctx context.Context, clientID, id uuid.UUID) error {
+	for i, webhook := range m.webhooks {
+		if webhook.ID == id && webhook.ClientID == clientID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func (m *memoryClientWebhookStore) MarkTriggered(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *memoryClientWebhookStore) CreateDelivery(ctx context.Context, delivery *models.OAuthClientWebhookDelivery) error {
+	delivery.ID = uuid.New()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *memoryClientWebhookStore) CompleteDelivery(ctx context.Context, id uuid.UUID, status string, httpStatusCode *int, responseBody string) error {
+	for _, delivery := range m.deliveries {
+		if delivery.ID == id {
+			delivery.Status = status
+			delivery.HTTPStatusCode = httpStatusCode
+			delivery.ResponseBody = responseBody
+		}
+	}
+	return nil
+}
+
+func (m *memoryClientWebhookStore) ListDeliveries(ctx context.Context, webhookID uuid.UUID, page, perPage int) ([]*models.OAuthClientWebhookDelivery, int, error) {
+	return m.deliveries, len(m.deliveries), nil
+}
+
+func setupOAuthClientWebhookService(client *models.OAuthClient) (*OAuthClientWebhookService, *memoryClientWebhookStore) {
+	store := &memoryClientWebhookStore{}
+	clients := &mockOAuthProviderStore{
+		GetClientByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+			if id != client.ID {
+				return nil, models.ErrNotFound
+			}
+			return client, nil
+		},
+	}
+	svc := NewOAuthClientWebhookService(store, clients, &mockAuditLogger{}, logger.New("test", logger.DebugLevel, false))
+	return svc, store
+}
+
+func TestOAuthClientWebhookService_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	client := fixtures.NewOAuthClientBuilder().Build()
+	client.OwnerID = &ownerID
+
+	t.Run("RejectsOtherUsers", func(t *testing.T) {
+		svc, _ := setupOAuthClientWebhookService(client)
+
+		_, err := svc.CreateWebhook(ctx, uuid.New(), client.ID, &models.CreateOAuthClientWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []string{models.OAuthClientEventConsentGranted},
+		})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("RejectsPlainHTTP", func(t *testing.T) {
+		svc, _ := setupOAuthClientWebhookService(client)
+
+		_, err := svc.CreateWebhook(ctx, ownerID, client.ID, &models.CreateOAuthClientWebhookRequest{
+			URL:    "http://example.com/hook",
+			Events: []string{models.OAuthClientEventConsentGranted},
+		})
+		assert.ErrorIs(t, err, errClientWebhookURL)
+	})
+
+	t.Run("RejectsAdminEvents", func(t *testing.T) {
+		svc, _ := setupOAuthClientWebhookService(client)
+
+		_, err := svc.CreateWebhook(ctx, ownerID, client.ID, &models.CreateOAuthClientWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []string{models.WebhookEventUserCreated},
+		})
+		require.Error(t, err)
+		assert.Equal(t, "Invalid event type", err.Error())
+	})
+
+	t.Run("Creates", func(t *testing.T) {
+		svc, store := setupOAuthClientWebhookService(client)
+
+		resp, err := svc.CreateWebhook(ctx, ownerID, client.ID, &models.CreateOAuthClientWebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []string{models.OAuthClientEventTokenRevoked, models.OAuthClientEventTokenRevoked},
+		})
+		require.NoError(t, err)
+		assert.Len(t, resp.SecretKey, 64)
+		assert.Equal(t, resp.SecretKey, resp.Webhook.SecretKey)
+		assert.Equal(t, []string{models.OAuthClientEventTokenRevoked}, resp.Webhook.Events)
+		assert.True(t, resp.Webhook.IsActive)
+		require.Len(t, store.webhooks, 1)
+		assert.Equal(t, client.ID, store.webhooks[0].ClientID)
+	})
+}
+
+func TestOAuthClientWebhookService_NotifyClientEvent(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	client := fixtures.NewOAuthClientBuilder().Build()
+	client.OwnerID = &ownerID
+
+	var received []*http.Request
+	var body []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	svc, store := setupOAuthClientWebhookService(client)
+	// The test server listens on loopback, which the default transport refuses
+	svc.httpClient = server.Client()
+
+	subscribed := &models.OAuthClientWebhook{
+		ClientID:  client.ID,
+		URL:       server.URL,
+		SecretKey: "secret",
+		Events:    []string{models.OAuthClientEventConsentGranted},
+		IsActive:  true,
+	}
+	other := &models.OAuthClientWebhook{
+		ClientID: client.ID,
+		URL:      server.URL,
+		Events:   []string{models.OAuthClientEventSecretRotated},
+		IsActive: true,
+	}
+	require.NoError(t, store.Create(ctx, subscribed))
+	require.NoError(t, store.Create(ctx, other))
+
+	err := svc.NotifyClientEvent(ctx, client.ID, models.OAuthClientEventConsentGranted, map[string]interface{}{
+		"user_id": "user-1",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, models.OAuthClientEventConsentGranted, received[0].Header.Get("X-Webhook-Event"))
+	assert.Equal(t, signWebhookPayload(body, "secret"), received[0].Header.Get("X-Webhook-Signature"))
+	assert.Equal(t, "sha256="+signWebhookPayload(body, "secret"), received[0].Header.Get("X-AuthGateway-Signature"))
+
+	var event models.WebhookEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, client.ClientID, event.Data["client_id"])
+	assert.Equal(t, "user-1", event.Data["user_id"])
+
+	require.Len(t, store.deliveries, 1)
+	assert.Equal(t, subscribed.ID, store.deliveries[0].WebhookID)
+	assert.Equal(t, "success", store.deliveries[0].Status)
+	require.NotNil(t, store.deliveries[0].HTTPStatusCode)
+	assert.Equal(t, http.StatusNoContent, *store.deliveries[0].HTTPStatusCode)
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:443", "10.1.2.3:443", "192.168.0.10:443", "169.254.169.254:80", "[::1]:443", "0.0.0.0:443"} {
+		assert.ErrorIs(t, webhookDialControl("tcp", address, nil), errClientWebhookAddress, address)
+	}
+	assert.NoError(t, webhookDialControl("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, webhookDialControl("tcp6", "[2606:2800:220:1:248:1893:25c8:1946]:443", nil))
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	// oidcDiscoveryTTL is how long the discovery document of an upstream issuer is reused
+	oidcDiscoveryTTL = time.Hour
+)
+
+// oidcDiscoveryDocument is the part of the provider metadata of an upstream OpenID Connect
+// provider needed to sign users in with it (OpenID Connect Discovery 1.0, section 3)
+type oidcDiscoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+
+	fetchedAt time.Time
+}
+
+// oidcDiscoveryCache keeps the discovery documents of upstream issuers
+type oidcDiscoveryCache struct {
+	mu        sync.Mutex
+	documents map[string]*oidcDiscoveryDocument
+}
+
+// oidcProviderFromEnv returns the generic OpenID Connect provider configured with the
+// OAUTH_OIDC_* variables, or nil when it is disabled
+func oidcProviderFromEnv() *OAuthProviderConfig {
+	if os.Getenv("OAUTH_OIDC_ENABLED") != "true" {
+		return nil
+	}
+
+	scopes := os.Getenv("OAUTH_OIDC_SCOPES")
+	if scopes == "" {
+		scopes = "openid profile email"
+	}
+	return &OAuthProviderConfig{
+		ClientID:     os.Getenv("OAUTH_OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OAUTH_OIDC_CLIENT_SECRET"),
+		CallbackURL:  os.Getenv("OAUTH_OIDC_REDIRECT_URI"),
+		AuthURL:      os.Getenv("OAUTH_OIDC_AUTH_URL"),
+		TokenURL:     os.Getenv("OAUTH_OIDC_TOKEN_URL"),
+		UserInfoURL:  os.Getenv("OAUTH_OIDC_USERINFO_URL"),
+		Scopes:       splitScopes(scopes),
+		Issuer:       os.Getenv("OAUTH_OIDC_ISSUER"),
+		ClaimMapping: models.OIDCClaimMapping{
+			Subject:       os.Getenv("OAUTH_OIDC_CLAIM_SUBJECT"),
+			Email:         os.Getenv("OAUTH_OIDC_CLAIM_EMAIL"),
+			EmailVerified: os.Getenv("OAUTH_OIDC_CLAIM_EMAIL_VERIFIED"),
+			Name:          os.Getenv("OAUTH_OIDC_CLAIM_NAME"),
+			Username:      os.Getenv("OAUTH_OIDC_CLAIM_USERNAME"),
+			Picture:       os.Getenv("OAUTH_OIDC_CLAIM_PICTURE"),
+		},
+	}
+}
+
+// resolveOIDCConfig returns a copy of a generic OpenID Connect provider configuration with
+// the endpoints it doesn't set taken from the discovery document of its issuer. The openid
+// scope is always requested, since the ID token is what identifies the user.
+func (s *OAuthService) resolveOIDCConfig(ctx context.Context, config *OAuthProviderConfig) (*OAuthProviderConfig, error) {
+	resolved := *config
+
+	if len(resolved.Scopes) == 0 {
+		resolved.Scopes = []string{"openid", "profile", "email"}
+	} else if !containsString(resolved.Scopes, "openid") {
+		resolved.Scopes = append([]string{"openid"}, resolved.Scopes...)
+	}
+
+	if resolved.Issuer != "" && (resolved.AuthURL == "" || resolved.TokenURL == "" || resolved.UserInfoURL == "") {
+		doc, err := s.discoverOIDC(ctx, resolved.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		// ID tokens carry the issuer exactly as the provider spells it
+		resolved.Issuer = doc.Issuer
+		if resolved.AuthURL == "" {
+			resolved.AuthURL = doc.AuthorizationEndpoint
+		}
+		if resolved.TokenURL == "" {
+			resolved.TokenURL = doc.TokenEndpoint
+		}
+		if resolved.UserInfoURL == "" {
+			resolved.UserInfoURL = doc.UserinfoEndpoint
+		}
+		// client_secret_basic is the default when the provider lists no methods
+		methods := doc.TokenEndpointAuthMethodsSupported
+		resolved.ClientSecretBasic = len(methods) > 0 && !containsString(methods, "client_secret_post")
+	}
+
+	if resolved.AuthURL == "" || resolved.TokenURL == "" {
+		return nil, models.ErrInvalidProvider
+	}
+	return &resolved, nil
+}
+
+// discoverOIDC returns the discovery document of an upstream issuer, fetching it again once
+// the cached one is older than oidcDiscoveryTTL
+func (s *OAuthService) discoverOIDC(ctx context.Context, issuer string) (*oidcDiscoveryDocument, error) {
+	key := strings.TrimRight(issuer, "/")
+
+	s.oidcDiscovery.mu.Lock()
+	cached := s.oidcDiscovery.documents[key]
+	s.oidcDiscovery.mu.Unlock()
+	if cached != nil && time.Since(cached.fetchedAt) < oidcDiscoveryTTL {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key+oidcDiscoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery failed with status: %d", resp.StatusCode)
+	}
+
+	var doc oidcDiscoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode oidc discovery document: %w", err)
+	}
+	// The document must be about the issuer it was fetched for (OpenID Connect Discovery 4.3)
+	if strings.TrimRight(doc.Issuer, "/") != key {
+		return nil, fmt.Errorf("oidc discovery document is for issuer %q, expected %q", doc.Issuer, issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery document of %q has no authorization or token endpoint", issuer)
+	}
+	doc.fetchedAt = time.Now()
+
+	s.oidcDiscovery.mu.Lock()
+	s.oidcDiscovery.documents[key] = &doc
+	s.oidcDiscovery.mu.Unlock()
+
+	return &doc, nil
+}
+
+// oidcUserInfo returns the user a generic OpenID Connect provider issued a token response
+// for: the claims of the ID token, completed by the user info endpoint when the provider
+// has one, read through the claim mapping of the configuration.
+func (s *OAuthService) oidcUserInfo(ctx context.Context, config *OAuthProviderConfig, tokenResp *OAuthTokenResponse) (*models.OAuthUserInfo, error) {
+	if tokenResp.IDToken == "" {
+		return nil, fmt.Errorf("oidc token response has no ID token")
+	}
+	claims, err := parseIDToken(tokenResp.IDToken, config.Issuer, config.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.UserInfoURL != "" {
+		userInfo, err := s.fetchUserInfoClaims(ctx, config.UserInfoURL, tokenResp.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		// The user info must be about the user of the ID token (OpenID Connect Core 5.3.4)
+		if sub, ok := userInfo["sub"]; ok && fmt.Sprintf("%v", sub) != fmt.Sprintf("%v", claims["sub"]) {
+			return nil, fmt.Errorf("oidc user info is for another subject than the ID token")
+		}
+		for name, value := range userInfo {
+			claims[name] = value
+		}
+	}
+
+	return mapOIDCClaims(claims, config.ClaimMapping)
+}
+
+// mapOIDCClaims reads the user from the claims of a generic OpenID Connect provider
+func mapOIDCClaims(claims map[string]interface{}, mapping models.OIDCClaimMapping) (*models.OAuthUserInfo, error) {
+	claim := func(name, standard string) interface{} {
+		if name == "" {
+			name = standard
+		}
+		return claims[name]
+	}
+	str := func(value interface{}) string {
+		switch v := value.(type) {
+		case string:
+			return v
+		case nil:
+			return ""
+		case float64:
+			// Numeric IDs are decoded as float64; print them without an exponent
+			return fmt.Sprintf("%.0f", v)
+		default:
+			return fmt.Sprintf("%v", v)
+		}
+	}
+
+	userInfo := &models.OAuthUserInfo{
+		Provider:       string(models.ProviderOIDCGeneric),
+		ProviderUserID: str(claim(mapping.Subject, "sub")),
+		Email:          str(claim(mapping.Email, "email")),
+		Name:           str(claim(mapping.Name, "name")),
+		Username:       str(claim(mapping.Username, "preferred_username")),
+		ProfilePicture: str(claim(mapping.Picture, "picture")),
+	}
+	if userInfo.ProviderUserID == "" {
+		return nil, fmt.Errorf("oidc claims have no subject")
+	}
+
+	switch verified := claim(mapping.EmailVerified, "email_verified").(type) {
+	case bool:
+		userInfo.EmailVerified = verified
+	case string:
+		userInfo.EmailVerified = verified == "true"
+	}
+
+	if userInfo.Name == "" {
+		userInfo.Name = strings.TrimSpace(str(claims["given_name"]) + " " + str(claims["family_name"]))
+	}
+
+	return userInfo, nil
+}
+
+// fetchUserInfoClaims returns the claims the user info endpoint of a provider returns for
+// an access token
+func (s *OAuthService) fetchUserInfoClaims(ctx context.Context, userInfoURL, accessToken string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user info request failed with status: %d", resp.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode user info: %w", err)
+	}
+	return claims, nil
+}
+
+// parseIDToken returns the claims of an ID token received from the token endpoint of a
+// provider. It was received straight from that endpoint over TLS in exchange for the client
+// credentials, so its issuer, audience and expiry are checked instead of its signature
+// (OpenID Connect Core 3.1.3.7). The issuer is not checked when it is not known.
+func parseIDToken(idToken, issuer, audience string) (map[string]interface{}, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, claims); err != nil {
+		return nil, fmt.Errorf("failed to parse ID token: %w", err)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if err := jwt.NewValidator(opts...).Validate(claims); err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOIDCIssuer = "https://sso.example.com/realms/corp"
+
+// setupOIDCService returns an OAuth service whose HTTP client serves the discovery document
+// of testOIDCIssuer and counts how often it was fetched
+func setupOIDCService(authMethods ...string) (*OAuthService, *mockHTTPClient, *int) {
+	svc, _, _, _, _, _, _, mHTTP := setupOAuthService()
+	fetches := 0
+	mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != testOIDCIssuer+oidcDiscoveryPath {
+			return newJSONResponse(http.StatusNotFound, nil), nil
+		}
+		fetches++
+		return newJSONResponse(http.StatusOK, map[string]interface{}{
+			"issuer":                                testOIDCIssuer,
+			"authorization_endpoint":                testOIDCIssuer + "/protocol/openid-connect/auth",
+			"token_endpoint":                        testOIDCIssuer + "/protocol/openid-connect/token",
+			"userinfo_endpoint":                     testOIDCIssuer + "/protocol/openid-connect/userinfo",
+			"token_endpoint_auth_methods_supported": authMethods,
+		}), nil
+	}
+	return svc, mHTTP, &fetches
+}
+
+func testOIDCIDToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("unrelated-key"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestOAuthService_ResolveOIDCConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ShouldDiscoverEndpoints_WhenOnlyIssuerSet", func(t *testing.T) {
+		svc, _, fetches := setupOIDCService("client_secret_basic", "client_secret_post")
+		config := &OAuthProviderConfig{ClientID: "gateway", Issuer: testOIDCIssuer + "/", Scopes: []string{"email"}}
+
+		resolved, err := svc.resolveOIDCConfig(ctx, config)
+		require.NoError(t, err)
+		assert.Equal(t, testOIDCIssuer, resolved.Issuer)
+		assert.Equal(t, testOIDCIssuer+"/protocol/openid-connect/auth", resolved.AuthURL)
+		assert.Equal(t, testOIDCIssuer+"/protocol/openid-connect/token", resolved.TokenURL)
+		assert.Equal(t, testOIDCIssuer+"/protocol/openid-connect/userinfo", resolved.UserInfoURL)
+		assert.Equal(t, []string{"openid", "email"}, resolved.Scopes)
+		assert.False(t, resolved.ClientSecretBasic)
+		// The configuration it was resolved from is shared and stays as it was
+		assert.Empty(t, config.AuthURL)
+
+		_, err = svc.resolveOIDCConfig(ctx, config)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *fetches, "discovery document is cached")
+	})
+
+	t.Run("ShouldUseBasicAuth_WhenPostNotSupported", func(t *testing.T) {
+		svc, _, _ := setupOIDCService("client_secret_basic", "private_key_jwt")
+
+		resolved, err := svc.resolveOIDCConfig(ctx, &OAuthProviderConfig{ClientID: "gateway", Issuer: testOIDCIssuer})
+		require.NoError(t, err)
+		assert.True(t, resolved.ClientSecretBasic)
+		assert.Equal(t, []string{"openid", "profile", "email"}, resolved.Scopes)
+	})
+
+	t.Run("ShouldKeepConfiguredEndpoints", func(t *testing.T) {
+		svc, _, fetches := setupOIDCService()
+
+		resolved, err := svc.resolveOIDCConfig(ctx, &OAuthProviderConfig{
+			ClientID:    "gateway",
+			Issuer:      testOIDCIssuer,
+			AuthURL:     "https://login.example.com/authorize",
+			TokenURL:    "https://login.example.com/token",
+			UserInfoURL: "https://login.example.com/userinfo",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.example.com/authorize", resolved.AuthURL)
+		assert.Equal(t, 0, *fetches)
+	})
+
+	t.Run("ShouldFail_WhenDocumentIsForAnotherIssuer", func(t *testing.T) {
+		svc, mHTTP, _ := setupOIDCService()
+		mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return newJSONResponse(http.StatusOK, map[string]interface{}{
+				"issuer":                 "https://evil.example.com",
+				"authorization_endpoint": "https://evil.example.com/auth",
+				"token_endpoint":         "https://evil.example.com/token",
+			}), nil
+		}
+
+		_, err := svc.resolveOIDCConfig(ctx, &OAuthProviderConfig{ClientID: "gateway", Issuer: testOIDCIssuer})
+		assert.Error(t, err)
+	})
+
+	t.Run("ShouldFail_WhenNoIssuerAndNoEndpoints", func(t *testing.T) {
+		svc, _, _ := setupOIDCService()
+
+		_, err := svc.resolveOIDCConfig(ctx, &OAuthProviderConfig{ClientID: "gateway"})
+		assert.ErrorIs(t, err, models.ErrInvalidProvider)
+	})
+
+	t.Run("ShouldNotInheritEnvProvider_WhenAppConfigured", func(t *testing.T) {
+		svc, _, _ := setupOIDCService()
+		svc.providers[models.ProviderOIDCGeneric] = &OAuthProviderConfig{
+			ClientID: "env-client",
+			AuthURL:  "https://env.example.com/authorize",
+			TokenURL: "https://env.example.com/token",
+		}
+		appID := uuid.New()
+		svc.appOAuthProviderRepo = &oauthMockAppOAuthProviderStore{
+			GetByAppAndProviderFunc: func(ctx context.Context, id uuid.UUID, provider string) (*models.ApplicationOAuthProvider, error) {
+				if provider != string(models.ProviderOIDCGeneric) {
+					return nil, errors.New("not found")
+				}
+				return &models.ApplicationOAuthProvider{
+					Provider:     provider,
+					ClientID:     "app-client",
+					ClientSecret: "app-secret",
+					Issuer:       testOIDCIssuer,
+					ClaimMapping: models.OIDCClaimMapping{Email: "mail"},
+					IsActive:     true,
+				}, nil
+			},
+		}
+
+		config, err := svc.getProviderConfigForApp(ctx, models.ProviderOIDCGeneric, &appID)
+		require.NoError(t, err)
+		assert.Equal(t, "app-client", config.ClientID)
+		assert.Equal(t, testOIDCIssuer+"/protocol/openid-connect/auth", config.AuthURL)
+		assert.Equal(t, "mail", config.ClaimMapping.Email)
+	})
+}
+
+func TestOAuthService_OIDCUserInfo(t *testing.T) {
+	ctx := context.Background()
+	config := &OAuthProviderConfig{
+		ClientID:     "gateway",
+		Issuer:       testOIDCIssuer,
+		UserInfoURL:  testOIDCIssuer + "/userinfo",
+		ClaimMapping: models.OIDCClaimMapping{Email: "mail", Username: "uid"},
+	}
+	idTokenClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": testOIDCIssuer,
+			"aud": "gateway",
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+	}
+	userInfo := func(claims map[string]interface{}) *mockHTTPClient {
+		return &mockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "Bearer access", req.Header.Get("Authorization"))
+			return newJSONResponse(http.StatusOK, claims), nil
+		}}
+	}
+
+	t.Run("ShouldMergeUserInfoAndMapClaims", func(t *testing.T) {
+		svc, _, _ := setupOIDCService()
+		svc.httpClient = userInfo(map[string]interface{}{
+			"sub":            "user-1",
+			"mail":           "jane@corp.example.com",
+			"email_verified": "true",
+			"uid":            "jdoe",
+			"given_name":     "Jane",
+			"family_name":    "Doe",
+		})
+
+		info, err := svc.oidcUserInfo(ctx, config, &OAuthTokenResponse{AccessToken: "access", IDToken: testOIDCIDToken(t, idTokenClaims())})
+		require.NoError(t, err)
+		assert.Equal(t, "oidc-generic", info.Provider)
+		assert.Equal(t, "user-1", info.ProviderUserID)
+		assert.Equal(t, "jane@corp.example.com", info.Email)
+		assert.True(t, info.EmailVerified)
+		assert.Equal(t, "jdoe", info.Username)
+		assert.Equal(t, "Jane Doe", info.Name)
+	})
+
+	t.Run("ShouldFail_WhenUserInfoIsForAnotherSubject", func(t *testing.T) {
+		svc, _, _ := setupOIDCService()
+		svc.httpClient = userInfo(map[string]interface{}{"sub": "user-2"})
+
+		_, err := svc.oidcUserInfo(ctx, config, &OAuthTokenResponse{AccessToken: "access", IDToken: testOIDCIDToken(t, idTokenClaims())})
+		assert.Error(t, err)
+	})
+
+	t.Run("ShouldFail_WhenIDTokenIsInvalid", func(t *testing.T) {
+		for name, change := range map[string]func(jwt.MapClaims){
+			"OtherAudience": func(c jwt.MapClaims) { c["aud"] = "another-client" },
+			"OtherIssuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			"Expired":       func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		} {
+			t.Run(name, func(t *testing.T) {
+				svc, _, _ := setupOIDCService()
+				claims := idTokenClaims()
+				change(claims)
+
+				_, err := svc.oidcUserInfo(ctx, config, &OAuthTokenResponse{AccessToken: "access", IDToken: testOIDCIDToken(t, claims)})
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("ShouldFail_WhenNoIDToken", func(t *testing.T) {
+		svc, _, _ := setupOIDCService()
+
+		_, err := svc.oidcUserInfo(ctx, config, &OAuthTokenResponse{AccessToken: "access"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMapOIDCClaims(t *testing.T) {
+	t.Run("ShouldUseStandardClaims_WhenNoMapping", func(t *testing.T) {
+		info, err := mapOIDCClaims(map[string]interface{}{
+			"sub":                float64(1234567890),
+			"email":              "user@example.com",
+			"email_verified":     true,
+			"name":               "Test User",
+			"preferred_username": "test",
+			"picture":            "https://example.com/a.png",
+		}, models.OIDCClaimMapping{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1234567890", info.ProviderUserID)
+		assert.Equal(t, "user@example.com", info.Email)
+		assert.True(t, info.EmailVerified)
+		assert.Equal(t, "Test User", info.Name)
+		assert.Equal(t, "test", info.Username)
+		assert.Equal(t, "https://example.com/a.png", info.ProfilePicture)
+	})
+
+	t.Run("ShouldUseMappedClaims", func(t *testing.T) {
+		info, err := mapOIDCClaims(map[string]interface{}{
+			"sub":                         "ignored",
+			"oid":                         "object-id",
+			"https://example.com/email":   "user@example.com",
+			"https://example.com/checked": false,
+		}, models.OIDCClaimMapping{Subject: "oid", Email: "https://example.com/email", EmailVerified: "https://example.com/checked"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "object-id", info.ProviderUserID)
+		assert.Equal(t, "user@example.com", info.Email)
+		assert.False(t, info.EmailVerified)
+	})
+
+	t.Run("ShouldFail_WhenNoSubject", func(t *testing.T) {
+		_, err := mapOIDCClaims(map[string]interface{}{"email": "user@example.com"}, models.OIDCClaimMapping{})
+		assert.Error(t, err)
+	})
+}
+
+func TestOAuthService_ExchangeCode_ShouldUseBasicAuth_WhenOIDCProviderRequiresIt(t *testing.T) {
+	svc, _, _, _, _, _, _, mHTTP := setupOAuthService()
+	svc.providers[models.ProviderOIDCGeneric] = &OAuthProviderConfig{
+		ClientID:     "gateway",
+		ClientSecret: "s3cret",
+		CallbackURL:  "http://localhost/callback",
+		AuthURL:      "https://login.example.com/authorize",
+		TokenURL:     "https://login.example.com/token",
+		// Set by discovery; kept by resolveOIDCConfig when the endpoints are configured
+		ClientSecretBasic: true,
+	}
+
+	mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+		clientID, secret, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "gateway", clientID)
+		assert.Equal(t, "s3cret", secret)
+		require.NoError(t, req.ParseForm())
+		assert.Empty(t, req.PostForm.Get("client_secret"))
+		assert.Equal(t, "code-123", req.PostForm.Get("code"))
+		return newJSONResponse(http.StatusOK, OAuthTokenResponse{AccessToken: "access", IDToken: "id"}), nil
+	}
+
+	result, err := svc.ExchangeCode(context.Background(), models.ProviderOIDCGeneric, "code-123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "id", result.IDToken)
+}
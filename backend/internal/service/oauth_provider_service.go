@@ -4,17 +4,20 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/smilemakc/auth-gateway/pkg/keys"
@@ -42,6 +45,7 @@ var (
 const (
 	authorizationCodeTTL      = 10 * time.Minute
 	deviceCodeTTL             = 15 * time.Minute
+	authorizationResponseTTL  = 5 * time.Minute // JARM response JWTs are consumed right after the redirect
 	deviceCodePollingInterval = 5
 
 	clientIDPrefix     = "agw_"
@@ -56,6 +60,7 @@ const (
 
 type ConsentInfo struct {
 	Client          *models.OAuthClient `json:"client"`
+	LogoURL         string              `json:"logo_url,omitempty"`
 	RequestedScopes []ScopeInfo         `json:"requested_scopes"`
 	AlreadyGranted  []string            `json:"already_granted,omitempty"`
 }
@@ -76,6 +81,10 @@ type OAuthProviderService struct {
 	logger         *logger.Logger
 	issuer         string
 	baseURL        string
+	userCache      *UserCache
+	clientEvents   OAuthClientEventNotifier
+	events         EventPublisher
+	groups         UserGroupLister
 }
 
 func NewOAuthProviderService(
@@ -118,6 +127,16 @@ func NewOAuthProviderServiceMinimal(
 }
 
 func (s *OAuthProviderService) CreateClient(ctx context.Context, req *models.CreateOAuthClientRequest, ownerID *uuid.UUID) (*models.CreateOAuthClientResponse, error) {
+	if err := validateLogoutURI("backchannel_logout_uri", req.BackchannelLogoutURI); err != nil {
+		return nil, err
+	}
+	if err := validateLogoutURI("frontchannel_logout_uri", req.FrontchannelLogoutURI); err != nil {
+		return nil, err
+	}
+	if err := validateClaimsMapping(req.ClaimsMapping); err != nil {
+		return nil, err
+	}
+
 	clientID := s.generateClientID()
 
 	var clientSecretPlain string
@@ -164,25 +183,31 @@ func (s *OAuthProviderService) CreateClient(ctx context.Context, req *models.Cre
 	}
 
 	client := &models.OAuthClient{
-		ID:                uuid.New(),
-		ClientID:          clientID,
-		ClientSecretHash:  clientSecretHash,
-		Name:              req.Name,
-		Description:       req.Description,
-		LogoURL:           req.LogoURL,
-		ClientType:        req.ClientType,
-		RedirectURIs:      req.RedirectURIs,
-		AllowedGrantTypes: req.AllowedGrantTypes,
-		AllowedScopes:     req.AllowedScopes,
-		DefaultScopes:     req.DefaultScopes,
-		AccessTokenTTL:    accessTokenTTL,
-		RefreshTokenTTL:   refreshTokenTTL,
-		IDTokenTTL:        idTokenTTL,
-		RequirePKCE:       requirePKCE,
-		RequireConsent:    requireConsent,
-		FirstParty:        firstParty,
-		OwnerID:           ownerID,
-		IsActive:          true,
+		ID:                     uuid.New(),
+		ClientID:               clientID,
+		ClientSecretHash:       clientSecretHash,
+		Name:                   req.Name,
+		Description:            req.Description,
+		LogoURL:                req.LogoURL,
+		ClientType:             req.ClientType,
+		RedirectURIs:           req.RedirectURIs,
+		BackchannelLogoutURI:   req.BackchannelLogoutURI,
+		PostLogoutRedirectURIs: req.PostLogoutRedirectURIs,
+		FrontchannelLogoutURI:  req.FrontchannelLogoutURI,
+		AllowedGrantTypes:      req.AllowedGrantTypes,
+		AllowedScopes:          req.AllowedScopes,
+		DefaultScopes:          req.DefaultScopes,
+		AccessTokenTTL:         accessTokenTTL,
+		RefreshTokenTTL:        refreshTokenTTL,
+		IDTokenTTL:             idTokenTTL,
+		RequirePKCE:            requirePKCE,
+		RequireConsent:         requireConsent,
+		FirstParty:             firstParty,
+		CertBoundAccessTokens:  req.CertBoundAccessTokens != nil && *req.CertBoundAccessTokens,
+		RequireRefreshNonce:    req.RequireRefreshNonce != nil && *req.RequireRefreshNonce,
+		ClaimsMapping:          nonEmptyClaimsMapping(req.ClaimsMapping),
+		OwnerID:                ownerID,
+		IsActive:               true,
 	}
 
 	if err := s.repo.CreateClient(ctx, client); err != nil {
@@ -263,6 +288,33 @@ func (s *OAuthProviderService) UpdateClient(ctx context.Context, id uuid.UUID, r
 	if req.RequireConsent != nil {
 		client.RequireConsent = *req.RequireConsent
 	}
+	if req.CertBoundAccessTokens != nil {
+		client.CertBoundAccessTokens = *req.CertBoundAccessTokens
+	}
+	if req.RequireRefreshNonce != nil {
+		client.RequireRefreshNonce = *req.RequireRefreshNonce
+	}
+	if req.BackchannelLogoutURI != nil {
+		if err := validateLogoutURI("backchannel_logout_uri", *req.BackchannelLogoutURI); err != nil {
+			return nil, err
+		}
+		client.BackchannelLogoutURI = *req.BackchannelLogoutURI
+	}
+	if req.PostLogoutRedirectURIs != nil {
+		client.PostLogoutRedirectURIs = req.PostLogoutRedirectURIs
+	}
+	if req.FrontchannelLogoutURI != nil {
+		if err := validateLogoutURI("frontchannel_logout_uri", *req.FrontchannelLogoutURI); err != nil {
+			return nil, err
+		}
+		client.FrontchannelLogoutURI = *req.FrontchannelLogoutURI
+	}
+	if req.ClaimsMapping != nil {
+		if err := validateClaimsMapping(req.ClaimsMapping); err != nil {
+			return nil, err
+		}
+		client.ClaimsMapping = nonEmptyClaimsMapping(req.ClaimsMapping)
+	}
 	if req.IsActive != nil {
 		client.IsActive = *req.IsActive
 	}
@@ -347,6 +399,7 @@ func (s *OAuthProviderService) RotateClientSecret(ctx context.Context, id uuid.U
 	s.logger.Info("client secret rotated", map[string]interface{}{
 		"client_id": client.ClientID,
 	})
+	s.notifyClientEvent(client.ID, models.OAuthClientEventSecretRotated, map[string]interface{}{})
 
 	return plain, nil
 }
@@ -392,8 +445,11 @@ func (s *OAuthProviderService) Authorize(ctx context.Context, req *models.Author
 	}
 
 	requestedScopes := s.parseScopes(req.Scope)
-	if err := s.validateScopes(requestedScopes, client.AllowedScopes); err != nil {
-		return nil, err
+	if len(requestedScopes) > 0 {
+		requestedScopes, err = s.resolveClientScopes(ctx, client, requestedScopes)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	requirePKCE := client.RequirePKCE || client.ClientType == string(models.ClientTypePublic)
@@ -437,7 +493,7 @@ func (s *OAuthProviderService) Authorize(ctx context.Context, req *models.Author
 		ClientID:    client.ID,
 		UserID:      userID,
 		RedirectURI: req.RedirectURI,
-		Scope:       req.Scope,
+		Scope:       strings.Join(requestedScopes, " "),
 		ExpiresAt:   time.Now().Add(authorizationCodeTTL),
 	}
 
@@ -540,6 +596,11 @@ func (s *OAuthProviderService) ExchangeCode(ctx context.Context, req *models.Tok
 		}
 	}
 
+	certThumbprint, err := certBinding(client, req)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.MarkAuthorizationCodeUsed(ctx, authCode.ID); err != nil {
 		s.logger.Error("failed to mark authorization code as used", map[string]interface{}{
 			"error":   err.Error(),
@@ -558,7 +619,7 @@ func (s *OAuthProviderService) ExchangeCode(ctx context.Context, req *models.Tok
 
 	scopes := s.parseScopes(authCode.Scope)
 
-	response, err := s.generateTokens(ctx, client, &authCode.UserID, user, scopes, authCode.Nonce)
+	response, err := s.generateTokens(ctx, client, &authCode.UserID, user, scopes, authCode.Nonce, certThumbprint)
 	if err != nil {
 		return nil, err
 	}
@@ -601,20 +662,22 @@ func (s *OAuthProviderService) ClientCredentialsGrant(ctx context.Context, req *
 		return nil, ErrUnauthorizedClient
 	}
 
+	certThumbprint, err := certBinding(client, req)
+	if err != nil {
+		return nil, err
+	}
+
 	requestedScopes := []string{}
 	if req.Scope != nil && *req.Scope != "" {
 		requestedScopes = s.parseScopes(*req.Scope)
 	}
 
-	if err := s.validateScopes(requestedScopes, client.AllowedScopes); err != nil {
+	requestedScopes, err = s.resolveClientScopes(ctx, client, requestedScopes)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(requestedScopes) == 0 {
-		requestedScopes = client.DefaultScopes
-	}
-
-	return s.generateTokens(ctx, client, nil, nil, requestedScopes, nil)
+	return s.generateTokens(ctx, client, nil, nil, requestedScopes, nil, certThumbprint)
 }
 
 func (s *OAuthProviderService) RefreshToken(ctx context.Context, req *models.TokenRequest) (*models.TokenResponse, error) {
@@ -654,6 +717,20 @@ func (s *OAuthProviderService) RefreshToken(ctx context.Context, req *models.Tok
 		}
 	}
 
+	// A refresh token bound to a certificate may only be used with that certificate
+	if refreshToken.CertThumbprint != nil && *refreshToken.CertThumbprint != req.ClientCertThumbprint {
+		return nil, ErrInvalidGrant
+	}
+	certThumbprint, err := certBinding(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.RequireRefreshNonce && refreshToken.NonceHash != nil && !s.refreshNonceMatches(refreshToken, req.RefreshNonce) {
+		s.endRefreshLineage(ctx, refreshToken, client)
+		return nil, ErrInvalidGrant
+	}
+
 	user := refreshToken.User
 	if user == nil {
 		user, err = s.userRepo.GetByID(ctx, refreshToken.UserID, nil, UserGetWithRoles())
@@ -668,7 +745,7 @@ func (s *OAuthProviderService) RefreshToken(ctx context.Context, req *models.Tok
 		s.logger.Error("failed to revoke old refresh token", map[string]interface{}{"error": err.Error()})
 	}
 
-	response, err := s.generateTokens(ctx, client, &refreshToken.UserID, user, scopes, nil)
+	response, err := s.generateTokens(ctx, client, &refreshToken.UserID, user, scopes, nil, certThumbprint)
 	if err != nil {
 		return nil, err
 	}
@@ -691,6 +768,38 @@ func (s *OAuthProviderService) RefreshToken(ctx context.Context, req *models.Tok
 	return response, nil
 }
 
+// refreshNonceMatches reports whether nonce is the one issued with the refresh token
+func (s *OAuthProviderService) refreshNonceMatches(refreshToken *models.OAuthRefreshToken, nonce *string) bool {
+	if nonce == nil || *nonce == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(s.hashToken(*nonce)), []byte(*refreshToken.NonceHash)) == 1
+}
+
+// endRefreshLineage revokes a refresh token presented without the nonce issued with it,
+// together with its access token. Only the holder of the latest refresh response knows the
+// nonce, so the token is in use by someone else; as each refresh replaces the previous
+// token, revoking it ends the lineage for both parties and forces the user to sign in again.
+func (s *OAuthProviderService) endRefreshLineage(ctx context.Context, refreshToken *models.OAuthRefreshToken, client *models.OAuthClient) {
+	if err := s.repo.RevokeRefreshToken(ctx, refreshToken.TokenHash); err != nil {
+		s.logger.Error("failed to revoke replayed refresh token", map[string]interface{}{"error": err.Error()})
+	}
+	if refreshToken.AccessToken != nil {
+		if err := s.repo.RevokeAccessToken(ctx, refreshToken.AccessToken.TokenHash); err != nil {
+			s.logger.Error("failed to revoke access token of replayed refresh token", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	s.logger.Warn("refresh token used without its nonce", map[string]interface{}{
+		"user_id":   refreshToken.UserID.String(),
+		"client_id": client.ClientID,
+	})
+	s.logAudit(ctx, &refreshToken.UserID, "oauth_refresh_nonce_mismatch", "failed", map[string]interface{}{
+		"client_id":        client.ClientID,
+		"refresh_token_id": refreshToken.ID.String(),
+	})
+}
+
 func (s *OAuthProviderService) DeviceAuthorization(ctx context.Context, req *models.DeviceAuthRequest) (*models.DeviceAuthResponse, error) {
 	client, err := s.repo.GetClientByClientID(ctx, req.ClientID)
 	if err != nil {
@@ -710,14 +819,11 @@ func (s *OAuthProviderService) DeviceAuthorization(ctx context.Context, req *mod
 		requestedScopes = s.parseScopes(*req.Scope)
 	}
 
-	if err := s.validateScopes(requestedScopes, client.AllowedScopes); err != nil {
+	requestedScopes, err = s.resolveClientScopes(ctx, client, requestedScopes)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(requestedScopes) == 0 {
-		requestedScopes = client.DefaultScopes
-	}
-
 	scope := strings.Join(requestedScopes, " ")
 
 	deviceCodePlain, deviceCodeHash, err := s.generateToken()
@@ -808,11 +914,16 @@ func (s *OAuthProviderService) PollDeviceToken(ctx context.Context, req *models.
 
 		scopes := s.parseScopes(deviceCode.Scope)
 
+		certThumbprint, err := certBinding(client, req)
+		if err != nil {
+			return nil, err
+		}
+
 		s.logAudit(ctx, deviceCode.UserID, "oauth_device_authorized", "success", map[string]interface{}{
 			"client_id": client.ClientID,
 		})
 
-		response, err := s.generateTokens(ctx, client, deviceCode.UserID, user, scopes, nil)
+		response, err := s.generateTokens(ctx, client, deviceCode.UserID, user, scopes, nil, certThumbprint)
 		if err != nil {
 			return nil, err
 		}
@@ -909,6 +1020,12 @@ func (s *OAuthProviderService) RevokeToken(ctx context.Context, token, tokenType
 	tokenHash := s.hashToken(token)
 
 	if tokenTypeHint == "" || tokenTypeHint == "access_token" {
+		// The token is only looked up to tell the client's webhooks and the event bus whose
+		// token it was
+		var record *models.OAuthAccessToken
+		if s.clientEvents != nil || s.events != nil {
+			record, _ = s.repo.GetAccessToken(ctx, tokenHash)
+		}
 		err := s.repo.RevokeAccessToken(ctx, tokenHash)
 		if err == nil {
 			// Also revoke associated session if exists using SessionService
@@ -918,11 +1035,18 @@ func (s *OAuthProviderService) RevokeToken(ctx context.Context, token, tokenType
 			s.logger.Info("access token revoked", map[string]interface{}{
 				"token_type": "access_token",
 			})
+			if record != nil {
+				s.notifyTokenRevoked(ctx, record.ClientID, record.UserID, "access_token")
+			}
 			return nil
 		}
 	}
 
 	if tokenTypeHint == "" || tokenTypeHint == "refresh_token" {
+		var record *models.OAuthRefreshToken
+		if s.clientEvents != nil || s.events != nil {
+			record, _ = s.repo.GetRefreshToken(ctx, tokenHash)
+		}
 		err := s.repo.RevokeRefreshToken(ctx, tokenHash)
 		if err == nil {
 			// Also revoke associated session if exists using SessionService
@@ -932,6 +1056,9 @@ func (s *OAuthProviderService) RevokeToken(ctx context.Context, token, tokenType
 			s.logger.Info("refresh token revoked", map[string]interface{}{
 				"token_type": "refresh_token",
 			})
+			if record != nil {
+				s.notifyTokenRevoked(ctx, record.ClientID, &record.UserID, "refresh_token")
+			}
 			return nil
 		}
 	}
@@ -939,7 +1066,10 @@ func (s *OAuthProviderService) RevokeToken(ctx context.Context, token, tokenType
 	return nil
 }
 
-func (s *OAuthProviderService) GetUserInfo(ctx context.Context, accessToken string) (*models.UserInfoResponse, error) {
+// GetUserInfo returns the claims about the user an access token was issued for.
+// clientCertThumbprint is the thumbprint of the client certificate the request was made with,
+// or ""; a certificate-bound token (RFC 8705) is only accepted with its certificate.
+func (s *OAuthProviderService) GetUserInfo(ctx context.Context, accessToken, clientCertThumbprint string) (*models.UserInfoResponse, error) {
 	tokenHash := s.hashToken(accessToken)
 	tokenRecord, err := s.repo.GetAccessToken(ctx, tokenHash)
 	if err != nil {
@@ -947,6 +1077,9 @@ func (s *OAuthProviderService) GetUserInfo(ctx context.Context, accessToken stri
 		if err != nil {
 			return nil, ErrInvalidGrant
 		}
+		if claims.Cnf != nil && claims.Cnf.X5TS256 != clientCertThumbprint {
+			return nil, ErrInvalidGrant
+		}
 
 		if claims.Subject == "" {
 			return nil, fmt.Errorf("token has no subject")
@@ -957,46 +1090,134 @@ func (s *OAuthProviderService) GetUserInfo(ctx context.Context, accessToken stri
 			return nil, ErrInvalidGrant
 		}
 
-		user, err := s.userRepo.GetByID(ctx, userID, nil, UserGetWithRoles())
+		user, err := s.userInfoUser(ctx, userID)
 		if err != nil {
 			return nil, ErrServerError
 		}
 
+		client, err := s.repo.GetClientByClientID(ctx, claims.ClientID)
+		if err != nil {
+			return nil, ErrInvalidGrant
+		}
+
 		scopes := jwt.SplitScopes(claims.Scope)
-		return s.buildUserInfoResponse(user, scopes), nil
+		return s.userInfoResponse(ctx, client, user, scopes)
 	}
 
 	if !tokenRecord.IsValid() {
 		return nil, ErrInvalidGrant
 	}
+	if tokenRecord.CertThumbprint != nil && *tokenRecord.CertThumbprint != clientCertThumbprint {
+		return nil, ErrInvalidGrant
+	}
 
 	if tokenRecord.UserID == nil {
 		return nil, fmt.Errorf("token has no associated user")
 	}
 
 	user := tokenRecord.User
-	if user == nil {
-		user, err = s.userRepo.GetByID(ctx, *tokenRecord.UserID, nil, UserGetWithRoles())
+	// The user relation comes without roles, which a roles claim needs
+	if user == nil || (tokenRecord.Client != nil && tokenRecord.Client.ClaimsMapping != nil && tokenRecord.Client.ClaimsMapping.RolesClaim != "") {
+		user, err = s.userInfoUser(ctx, *tokenRecord.UserID)
 		if err != nil {
 			return nil, ErrServerError
 		}
 	}
 
 	scopes := s.parseScopes(tokenRecord.Scope)
-	return s.buildUserInfoResponse(user, scopes), nil
+	return s.userInfoResponse(ctx, tokenRecord.Client, user, scopes)
+}
+
+// userInfoResponse builds the userinfo response with the client's custom claims
+func (s *OAuthProviderService) userInfoResponse(ctx context.Context, client *models.OAuthClient, user *models.User, scopes []string) (*models.UserInfoResponse, error) {
+	response := s.buildUserInfoResponse(user, scopes)
+	custom, err := s.customClaims(ctx, client, user)
+	if err != nil {
+		s.logger.Error("failed to build custom userinfo claims", map[string]interface{}{"error": err.Error()})
+		return nil, ErrServerError
+	}
+	response.Custom = custom
+	return response, nil
+}
+
+// SetUserCache serves userinfo users from cache
+func (s *OAuthProviderService) SetUserCache(cache *UserCache) {
+	s.userCache = cache
+}
+
+// SetClientEvents delivers consent, token revocation and secret rotation events to the webhooks
+// client owners registered
+func (s *OAuthProviderService) SetClientEvents(events OAuthClientEventNotifier) {
+	s.clientEvents = events
+}
+
+// SetEventBus publishes token revocations to events
+func (s *OAuthProviderService) SetEventBus(events EventPublisher) {
+	s.events = events
+}
+
+// SetGroups resolves the groups claim of client claims mappings
+func (s *OAuthProviderService) SetGroups(groups UserGroupLister) {
+	s.groups = groups
+}
+
+// notifyClientEvent hands an event about a client to its owner's webhooks without holding up the caller
+func (s *OAuthProviderService) notifyClientEvent(clientID uuid.UUID, eventType string, data map[string]interface{}) {
+	if s.clientEvents == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.clientEvents.NotifyClientEvent(ctx, clientID, eventType, data); err != nil {
+			s.logger.Warn("failed to notify oauth client webhooks", map[string]interface{}{
+				"client_id":  clientID.String(),
+				"event_type": eventType,
+				"error":      err.Error(),
+			})
+		}
+	}()
+}
+
+func (s *OAuthProviderService) notifyTokenRevoked(ctx context.Context, clientID uuid.UUID, userID *uuid.UUID, tokenType string) {
+	data := map[string]interface{}{
+		"token_type": tokenType,
+	}
+	if userID != nil {
+		data["user_id"] = userID.String()
+	}
+	s.notifyClientEvent(clientID, models.OAuthClientEventTokenRevoked, data)
+
+	if s.events != nil && userID != nil {
+		// The event bus logs failures
+		_ = s.events.PublishEvent(context.WithoutCancel(ctx), models.EventTokenRevoked, userID.String(), map[string]interface{}{
+			"user_id":     userID.String(),
+			"reason":      "oauth_revocation",
+			"token_types": []string{tokenType},
+			"client_id":   clientID.String(),
+		})
+	}
+}
+
+// userInfoUser loads the user with roles for the userinfo endpoint
+func (s *OAuthProviderService) userInfoUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	if s.userCache != nil {
+		return s.userCache.Get(ctx, userID)
+	}
+	return s.userRepo.GetByID(ctx, userID, nil, UserGetWithRoles())
 }
 
 func (s *OAuthProviderService) GetDiscoveryDocument() *models.OIDCDiscoveryDocument {
 	return &models.OIDCDiscoveryDocument{
 		Issuer:                      s.issuer,
-		AuthorizationEndpoint:       fmt.Sprintf("%s/oauth2/authorize", s.baseURL),
-		TokenEndpoint:               fmt.Sprintf("%s/oauth2/token", s.baseURL),
-		UserInfoEndpoint:            fmt.Sprintf("%s/oauth2/userinfo", s.baseURL),
+		AuthorizationEndpoint:       fmt.Sprintf("%s/oauth/authorize", s.baseURL),
+		TokenEndpoint:               fmt.Sprintf("%s/oauth/token", s.baseURL),
+		UserInfoEndpoint:            fmt.Sprintf("%s/oauth/userinfo", s.baseURL),
 		JwksURI:                     fmt.Sprintf("%s/.well-known/jwks.json", s.baseURL),
-		RevocationEndpoint:          fmt.Sprintf("%s/oauth2/revoke", s.baseURL),
-		IntrospectionEndpoint:       fmt.Sprintf("%s/oauth2/introspect", s.baseURL),
-		DeviceAuthorizationEndpoint: fmt.Sprintf("%s/oauth2/device/code", s.baseURL),
-		EndSessionEndpoint:          fmt.Sprintf("%s/oauth2/logout", s.baseURL),
+		RevocationEndpoint:          fmt.Sprintf("%s/oauth/revoke", s.baseURL),
+		IntrospectionEndpoint:       fmt.Sprintf("%s/oauth/introspect", s.baseURL),
+		DeviceAuthorizationEndpoint: fmt.Sprintf("%s/oauth/device/code", s.baseURL),
+		EndSessionEndpoint:          fmt.Sprintf("%s/oauth/logout", s.baseURL),
 		ScopesSupported: []string{
 			models.ScopeOpenID,
 			models.ScopeProfile,
@@ -1005,15 +1226,141 @@ func (s *OAuthProviderService) GetDiscoveryDocument() *models.OIDCDiscoveryDocum
 			models.ScopeAddress,
 			models.ScopeOfflineAccess,
 		},
-		ResponseTypesSupported:            []string{"code"},
-		ResponseModesSupported:            []string{"query", "fragment"},
-		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code"},
-		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
-		SubjectTypesSupported:             []string{"public"},
-		IDTokenSigningAlgValuesSupported:  []string{"RS256", "ES256"},
-		CodeChallengeMethodsSupported:     []string{"plain", "S256"},
-		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "name", "email", "email_verified", "phone_number", "phone_number_verified", "picture", "preferred_username"},
+		ResponseTypesSupported: []string{"code"},
+		ResponseModesSupported: []string{
+			models.ResponseModeQuery,
+			models.ResponseModeFragment,
+			models.ResponseModeJWT,
+			models.ResponseModeQueryJWT,
+			models.ResponseModeFragmentJWT,
+		},
+		GrantTypesSupported:                    []string{"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code"},
+		TokenEndpointAuthMethodsSupported:      []string{"client_secret_basic", "client_secret_post", "none"},
+		SubjectTypesSupported:                  []string{"public"},
+		IDTokenSigningAlgValuesSupported:       []string{"RS256", "ES256"},
+		CodeChallengeMethodsSupported:          []string{"plain", "S256"},
+		AuthorizationSigningAlgValuesSupported: []string{"RS256", "ES256"},
+		BackchannelLogoutSupported:             true,
+		FrontchannelLogoutSupported:            true,
+		FrontchannelLogoutSessionSupported:     true,
+		TLSClientCertificateBoundAccessTokens:  true,
+		ClaimsSupported:                        []string{"sub", "iss", "aud", "exp", "iat", "name", "email", "email_verified", "phone_number", "phone_number_verified", "picture", "preferred_username"},
+	}
+}
+
+// validateLogoutURI checks a back- or front-channel logout URI is an absolute http(s) URL
+// without a fragment, as the OIDC logout specs require. Empty disables that kind of logout.
+func validateLogoutURI(field, uri string) error {
+	if uri == "" {
+		return nil
 	}
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.Fragment != "" {
+		return models.NewAppError(http.StatusBadRequest, field+" must be an absolute http(s) URL without a fragment")
+	}
+	return nil
+}
+
+// EndSession validates an RP-initiated logout request (OIDC RP-Initiated Logout) and returns
+// where the user goes once their session is ended. userID is the signed-in user, if any; the
+// clients they are signed in to with a front-channel logout URI are logged out in iframes.
+// Without id_token_hint any site could log the user out, so the user confirms such a logout
+// first (see EndSessionResult.ConfirmationRequired and EndSessionRequest.Confirmed).
+func (s *OAuthProviderService) EndSession(ctx context.Context, req *models.EndSessionRequest, userID *uuid.UUID) (*models.EndSessionResult, error) {
+	clientID := req.ClientID
+	if req.IDTokenHint != "" {
+		if s.oidcJWT == nil {
+			return nil, ErrServerError
+		}
+		claims, err := s.oidcJWT.ValidateIDTokenHint(req.IDTokenHint)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid id_token_hint", ErrInvalidRequest)
+		}
+		if userID != nil && claims.Subject != userID.String() {
+			return nil, fmt.Errorf("%w: id_token_hint was not issued for the signed-in user", ErrInvalidRequest)
+		}
+		switch {
+		case clientID != "" && !containsString(claims.Audience, clientID):
+			return nil, fmt.Errorf("%w: id_token_hint was not issued to client_id", ErrInvalidRequest)
+		case clientID == "" && claims.AZP != "":
+			clientID = claims.AZP
+		case clientID == "":
+			clientID = claims.Audience[0]
+		}
+	}
+
+	result := &models.EndSessionResult{}
+	if req.PostLogoutRedirectURI != "" {
+		// Only registered URIs are followed, so the endpoint is no open redirect
+		if clientID == "" {
+			return nil, fmt.Errorf("%w: post_logout_redirect_uri requires id_token_hint or client_id", ErrInvalidRequest)
+		}
+		client, err := s.repo.GetClientByClientID(ctx, clientID)
+		if err != nil || !client.IsActive {
+			return nil, ErrInvalidClient
+		}
+		if !s.validateRedirectURI(req.PostLogoutRedirectURI, client.PostLogoutRedirectURIs) {
+			return nil, fmt.Errorf("%w: post_logout_redirect_uri is not registered for the client", ErrInvalidRequest)
+		}
+
+		redirect, err := url.Parse(req.PostLogoutRedirectURI)
+		if err != nil {
+			return nil, ErrInvalidRequest
+		}
+		if req.State != "" {
+			query := redirect.Query()
+			query.Set("state", req.State)
+			redirect.RawQuery = query.Encode()
+		}
+		result.RedirectURI = redirect.String()
+	}
+
+	if userID != nil && req.IDTokenHint == "" && !req.Confirmed {
+		result.ConfirmationRequired = true
+		return result, nil
+	}
+
+	if userID != nil {
+		clients, err := s.repo.ListFrontchannelLogoutClients(ctx, *userID)
+		if err != nil {
+			// The session still ends; the clients keep theirs until their tokens expire
+			s.logger.Error("failed to list front-channel logout clients", map[string]interface{}{
+				"error":   err.Error(),
+				"user_id": userID.String(),
+			})
+		}
+		for _, client := range clients {
+			result.FrontchannelLogoutURIs = append(result.FrontchannelLogoutURIs, s.frontchannelLogoutURI(client, *userID))
+		}
+	}
+
+	return result, nil
+}
+
+// frontchannelLogoutURI adds the iss and sid parameters of OIDC Front-Channel Logout to the
+// client's logout URI, so the client can tell which of its sessions to end
+func (s *OAuthProviderService) frontchannelLogoutURI(client *models.OAuthClient, userID uuid.UUID) string {
+	if s.oidcJWT == nil {
+		return client.FrontchannelLogoutURI
+	}
+	u, err := url.Parse(client.FrontchannelLogoutURI)
+	if err != nil {
+		return client.FrontchannelLogoutURI
+	}
+	query := u.Query()
+	query.Set("iss", s.oidcJWT.Issuer())
+	query.Set("sid", s.oidcJWT.SessionID(userID, client.ClientID))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// SignAuthorizationResponse returns the parameters of an authorization response for clientID as
+// a JWT signed with the OIDC keys (JARM), so the client can verify the redirect was not altered
+func (s *OAuthProviderService) SignAuthorizationResponse(clientID string, params url.Values) (string, error) {
+	if s.oidcJWT == nil {
+		return "", ErrServerError
+	}
+	return s.oidcJWT.GenerateAuthorizationResponse(clientID, params, authorizationResponseTTL)
 }
 
 func (s *OAuthProviderService) GetJWKS() *models.JWKSDocument {
@@ -1047,6 +1394,11 @@ func (s *OAuthProviderService) GetConsentInfo(ctx context.Context, clientID stri
 		return nil, ErrInvalidClient
 	}
 
+	scopes, err = s.expandScopes(ctx, scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	scopeInfos := make([]ScopeInfo, 0, len(scopes))
 	for _, scopeName := range scopes {
 		scope, err := s.repo.GetScopeByName(ctx, scopeName)
@@ -1067,16 +1419,42 @@ func (s *OAuthProviderService) GetConsentInfo(ctx context.Context, clientID stri
 
 	return &ConsentInfo{
 		Client:          client,
+		LogoURL:         s.displayLogoURL(client),
 		RequestedScopes: scopeInfos,
 	}, nil
 }
 
+// GetDeviceConsentInfo returns what the pending device authorization with the user code asks for
+func (s *OAuthProviderService) GetDeviceConsentInfo(ctx context.Context, userCode string) (*ConsentInfo, error) {
+	deviceCode, err := s.repo.GetDeviceCodeByUserCode(ctx, userCode)
+	if err != nil {
+		return nil, fmt.Errorf("device code not found")
+	}
+	if deviceCode.IsExpired() {
+		return nil, fmt.Errorf("device code expired")
+	}
+	if deviceCode.Status != models.DeviceCodeStatusPending {
+		return nil, fmt.Errorf("device code already processed")
+	}
+
+	client, err := s.repo.GetClientByID(ctx, deviceCode.ClientID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	return s.GetConsentInfo(ctx, client.ClientID, s.parseScopes(deviceCode.Scope))
+}
+
 func (s *OAuthProviderService) GrantConsent(ctx context.Context, userID uuid.UUID, clientID string, scopes []string) error {
 	client, err := s.repo.GetClientByClientID(ctx, clientID)
 	if err != nil {
 		return ErrInvalidClient
 	}
 
+	scopes, err = s.expandScopes(ctx, scopes)
+	if err != nil {
+		return err
+	}
+
 	consent := &models.UserConsent{
 		ID:       uuid.New(),
 		UserID:   userID,
@@ -1097,6 +1475,10 @@ func (s *OAuthProviderService) GrantConsent(ctx context.Context, userID uuid.UUI
 		"client_id": clientID,
 		"scopes":    scopes,
 	})
+	s.notifyClientEvent(client.ID, models.OAuthClientEventConsentGranted, map[string]interface{}{
+		"user_id": userID.String(),
+		"scopes":  scopes,
+	})
 
 	return nil
 }
@@ -1126,6 +1508,9 @@ func (s *OAuthProviderService) RevokeConsent(ctx context.Context, userID, client
 	s.logAudit(ctx, &userID, "oauth_consent_revoked", "success", map[string]interface{}{
 		"client_id": clientID.String(),
 	})
+	s.notifyClientEvent(clientID, models.OAuthClientEventConsentRevoked, map[string]interface{}{
+		"user_id": userID.String(),
+	})
 
 	return nil
 }
@@ -1138,6 +1523,73 @@ func (s *OAuthProviderService) ListUserConsents(ctx context.Context, userID uuid
 	return consents, nil
 }
 
+// ListConnectedApps lists the clients the user currently consents to, with token usage per client
+func (s *OAuthProviderService) ListConnectedApps(ctx context.Context, userID uuid.UUID) ([]*models.ConnectedApp, error) {
+	consents, err := s.repo.ListUserConsents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user consents: %w", err)
+	}
+
+	stats, err := s.repo.GetUserTokenStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user token stats: %w", err)
+	}
+	statsByClient := make(map[uuid.UUID]*models.OAuthClientTokenStats, len(stats))
+	for _, stat := range stats {
+		statsByClient[stat.ClientID] = stat
+	}
+
+	apps := make([]*models.ConnectedApp, 0, len(consents))
+	for _, consent := range consents {
+		if consent.IsRevoked() {
+			continue
+		}
+
+		app := &models.ConnectedApp{
+			ID:        consent.ClientID,
+			Scopes:    consent.Scopes,
+			GrantedAt: consent.GrantedAt,
+		}
+		if consent.Client != nil {
+			app.ClientID = consent.Client.ClientID
+			app.Name = consent.Client.Name
+			app.Description = consent.Client.Description
+			app.LogoURL = s.displayLogoURL(consent.Client)
+		}
+		if stat, ok := statsByClient[consent.ClientID]; ok {
+			app.FirstUsedAt = stat.FirstUsedAt
+			app.LastUsedAt = stat.LastUsedAt
+			app.ActiveTokens = stat.ActiveTokens
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
+
+// RevokeConnectedApp revokes the user's consent for a client along with every token issued under it
+func (s *OAuthProviderService) RevokeConnectedApp(ctx context.Context, userID, clientID uuid.UUID) error {
+	consent, err := s.repo.GetUserConsent(ctx, userID, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get user consent: %w", err)
+	}
+	if consent == nil || consent.IsRevoked() {
+		return models.ErrNotFound
+	}
+
+	return s.RevokeConsent(ctx, userID, clientID)
+}
+
+// displayLogoURL returns the client's logo URL if the logo is served by the gateway.
+// Other URLs are not shown to users, as loading them would tell a third party who is signing in.
+func (s *OAuthProviderService) displayLogoURL(client *models.OAuthClient) string {
+	if models.IsManagedLogoURL(client.LogoURL, s.baseURL) {
+		return client.LogoURL
+	}
+	return ""
+}
+
 func (s *OAuthProviderService) generateClientID() string {
 	bytes := make([]byte, 16)
 	rand.Read(bytes)
@@ -1287,17 +1739,30 @@ func (s *OAuthProviderService) buildErrorRedirect(redirectURI, errorCode, errorD
 	return u.String()
 }
 
-func (s *OAuthProviderService) generateTokens(ctx context.Context, client *models.OAuthClient, userID *uuid.UUID, user *models.User, scopes []string, nonce *string) (*models.TokenResponse, error) {
+// certBinding returns the thumbprint of the client certificate to bind issued tokens to, or ""
+// if the client does not use certificate-bound access tokens (RFC 8705)
+func certBinding(client *models.OAuthClient, req *models.TokenRequest) (string, error) {
+	if !client.CertBoundAccessTokens {
+		return "", nil
+	}
+	if req.ClientCertThumbprint == "" {
+		return "", fmt.Errorf("%w: client certificate is required", ErrInvalidRequest)
+	}
+	return req.ClientCertThumbprint, nil
+}
+
+// generateTokens issues tokens for client; a non-empty certThumbprint binds them to that client
+// certificate
+func (s *OAuthProviderService) generateTokens(ctx context.Context, client *models.OAuthClient, userID *uuid.UUID, user *models.User, scopes []string, nonce *string, certThumbprint string) (*models.TokenResponse, error) {
 	scope := strings.Join(scopes, " ")
 
-	var roles []string
-	if user != nil && len(user.Roles) > 0 {
-		for _, role := range user.Roles {
-			roles = append(roles, role.Name)
-		}
+	claims := s.buildAccessTokenClaims(client, userID, user, scopes, certThumbprint)
+	var boundTo *string
+	if certThumbprint != "" {
+		boundTo = &certThumbprint
 	}
 
-	accessToken, err := s.oidcJWT.GenerateOAuthAccessToken(userID, client.ClientID, scope, roles, time.Duration(client.AccessTokenTTL)*time.Second)
+	accessToken, err := s.oidcJWT.SignOAuthAccessToken(claims)
 	if err != nil {
 		s.logger.Error("failed to generate access token", map[string]interface{}{"error": err.Error()})
 		return nil, ErrServerError
@@ -1312,6 +1777,8 @@ func (s *OAuthProviderService) generateTokens(ctx context.Context, client *model
 		Scope:     scope,
 		IsActive:  true,
 		ExpiresAt: time.Now().Add(time.Duration(client.AccessTokenTTL) * time.Second),
+
+		CertThumbprint: boundTo,
 	}
 
 	if err := s.repo.CreateAccessToken(ctx, accessTokenRecord); err != nil {
@@ -1342,6 +1809,18 @@ func (s *OAuthProviderService) generateTokens(ctx context.Context, client *model
 			Scope:         scope,
 			IsActive:      true,
 			ExpiresAt:     time.Now().Add(time.Duration(client.RefreshTokenTTL) * time.Second),
+
+			CertThumbprint: boundTo,
+		}
+
+		if client.RequireRefreshNonce {
+			noncePlain, nonceHash, err := s.generateToken()
+			if err != nil {
+				s.logger.Error("failed to generate refresh nonce", map[string]interface{}{"error": err.Error()})
+				return nil, ErrServerError
+			}
+			refreshTokenRecord.NonceHash = &nonceHash
+			response.RefreshNonce = noncePlain
 		}
 
 		if err := s.repo.CreateRefreshToken(ctx, refreshTokenRecord); err != nil {
@@ -1358,7 +1837,15 @@ func (s *OAuthProviderService) generateTokens(ctx context.Context, client *model
 			nonceStr = *nonce
 		}
 
-		idToken, err := s.oidcJWT.GenerateIDToken(*userID, client.ClientID, nonceStr, scopes, user, time.Duration(client.IDTokenTTL)*time.Second)
+		idTokenClaims := s.oidcJWT.BuildIDTokenClaims(*userID, client.ClientID, nonceStr, scopes, user, time.Duration(client.IDTokenTTL)*time.Second)
+		custom, err := s.customClaims(ctx, client, user)
+		if err != nil {
+			s.logger.Error("failed to build custom ID token claims", map[string]interface{}{"error": err.Error()})
+			return nil, ErrServerError
+		}
+		idTokenClaims.Custom = custom
+
+		idToken, err := s.oidcJWT.SignIDToken(idTokenClaims)
 		if err != nil {
 			s.logger.Error("failed to generate ID token", map[string]interface{}{"error": err.Error()})
 			return nil, ErrServerError
@@ -1373,6 +1860,22 @@ func (s *OAuthProviderService) generateTokens(ctx context.Context, client *model
 	return response, nil
 }
 
+// buildAccessTokenClaims builds the claims of an access token issued to client for user
+func (s *OAuthProviderService) buildAccessTokenClaims(client *models.OAuthClient, userID *uuid.UUID, user *models.User, scopes []string, certThumbprint string) *jwt.OAuthAccessTokenClaims {
+	var roles []string
+	if user != nil && len(user.Roles) > 0 {
+		for _, role := range user.Roles {
+			roles = append(roles, role.Name)
+		}
+	}
+
+	claims := s.oidcJWT.BuildOAuthAccessTokenClaims(userID, client.ClientID, strings.Join(scopes, " "), roles, time.Duration(client.AccessTokenTTL)*time.Second)
+	if certThumbprint != "" {
+		claims.Cnf = &jwt.Confirmation{X5TS256: certThumbprint}
+	}
+	return claims
+}
+
 func (s *OAuthProviderService) containsScope(scopes []string, target string) bool {
 	for _, scope := range scopes {
 		if scope == target {
@@ -1402,6 +1905,10 @@ func (s *OAuthProviderService) buildIntrospectionResponse(accessToken *models.OA
 			response.ClientID = accessToken.Client.ClientID
 		}
 
+		if accessToken.CertThumbprint != nil {
+			response.Cnf = &models.Confirmation{X5TS256: *accessToken.CertThumbprint}
+		}
+
 		if accessToken.UserID != nil {
 			response.Subject = accessToken.UserID.String()
 			if accessToken.User != nil {
@@ -1546,6 +2053,7 @@ func (s *OAuthProviderService) logAudit(ctx context.Context, userID *uuid.UUID,
 		Action:    action,
 		Status:    status,
 		Details:   detailsJSON,
+		TraceID:   tracing.TraceID(ctx),
 		CreatedAt: time.Now(),
 	}
 
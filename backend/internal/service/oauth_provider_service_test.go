@@ -2,12 +2,16 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/testutil/fixtures"
 	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/smilemakc/auth-gateway/pkg/keys"
 	"github.com/smilemakc/auth-gateway/pkg/logger"
@@ -28,6 +32,9 @@ type mockOAuthProviderStore struct {
 	ListClientsFunc         func(ctx context.Context, page, perPage int, opts ...OAuthClientListOption) ([]*models.OAuthClient, int, error)
 	ListActiveClientsFunc   func(ctx context.Context) ([]*models.OAuthClient, error)
 
+	// Logout operations
+	ListFrontchannelLogoutClientsFunc func(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClient, error)
+
 	// Authorization code operations
 	CreateAuthorizationCodeFunc         func(ctx context.Context, code *models.AuthorizationCode) error
 	GetAuthorizationCodeFunc            func(ctx context.Context, codeHash string) (*models.AuthorizationCode, error)
@@ -50,6 +57,7 @@ type mockOAuthProviderStore struct {
 	RevokeAllUserRefreshTokensFunc   func(ctx context.Context, userID, clientID uuid.UUID) error
 	RevokeAllClientRefreshTokensFunc func(ctx context.Context, clientID uuid.UUID) error
 	DeleteExpiredRefreshTokensFunc   func(ctx context.Context) (int64, error)
+	GetUserTokenStatsFunc            func(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClientTokenStats, error)
 
 	// User consent operations
 	CreateOrUpdateConsentFunc func(ctx context.Context, consent *models.UserConsent) error
@@ -71,6 +79,13 @@ type mockOAuthProviderStore struct {
 	ListScopesFunc       func(ctx context.Context) ([]*models.OAuthScope, error)
 	ListSystemScopesFunc func(ctx context.Context) ([]*models.OAuthScope, error)
 	DeleteScopeFunc      func(ctx context.Context, id uuid.UUID) error
+
+	// Scope group operations
+	CreateScopeGroupFunc  func(ctx context.Context, group *models.OAuthScopeGroup) error
+	GetScopeGroupByIDFunc func(ctx context.Context, id uuid.UUID) (*models.OAuthScopeGroup, error)
+	ListScopeGroupsFunc   func(ctx context.Context) ([]*models.OAuthScopeGroup, error)
+	UpdateScopeGroupFunc  func(ctx context.Context, group *models.OAuthScopeGroup) error
+	DeleteScopeGroupFunc  func(ctx context.Context, id uuid.UUID) error
 }
 
 // Implement all interface methods
@@ -131,6 +146,13 @@ func (m *mockOAuthProviderStore) ListActiveClients(ctx context.Context) ([]*mode
 	return nil, nil
 }
 
+func (m *mockOAuthProviderStore) ListFrontchannelLogoutClients(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClient, error) {
+	if m.ListFrontchannelLogoutClientsFunc != nil {
+		return m.ListFrontchannelLogoutClientsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
 func (m *mockOAuthProviderStore) CreateAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error {
 	if m.CreateAuthorizationCodeFunc != nil {
 		return m.CreateAuthorizationCodeFunc(ctx, code)
@@ -250,6 +272,13 @@ func (m *mockOAuthProviderStore) DeleteExpiredRefreshTokens(ctx context.Context)
 	return 0, nil
 }
 
+func (m *mockOAuthProviderStore) GetUserTokenStats(ctx context.Context, userID uuid.UUID) ([]*models.OAuthClientTokenStats, error) {
+	if m.GetUserTokenStatsFunc != nil {
+		return m.GetUserTokenStatsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
 func (m *mockOAuthProviderStore) CreateOrUpdateConsent(ctx context.Context, consent *models.UserConsent) error {
 	if m.CreateOrUpdateConsentFunc != nil {
 		return m.CreateOrUpdateConsentFunc(ctx, consent)
@@ -355,6 +384,41 @@ func (m *mockOAuthProviderStore) DeleteScope(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+func (m *mockOAuthProviderStore) CreateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error {
+	if m.CreateScopeGroupFunc != nil {
+		return m.CreateScopeGroupFunc(ctx, group)
+	}
+	return nil
+}
+
+func (m *mockOAuthProviderStore) GetScopeGroupByID(ctx context.Context, id uuid.UUID) (*models.OAuthScopeGroup, error) {
+	if m.GetScopeGroupByIDFunc != nil {
+		return m.GetScopeGroupByIDFunc(ctx, id)
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockOAuthProviderStore) ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+	if m.ListScopeGroupsFunc != nil {
+		return m.ListScopeGroupsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockOAuthProviderStore) UpdateScopeGroup(ctx context.Context, group *models.OAuthScopeGroup) error {
+	if m.UpdateScopeGroupFunc != nil {
+		return m.UpdateScopeGroupFunc(ctx, group)
+	}
+	return nil
+}
+
+func (m *mockOAuthProviderStore) DeleteScopeGroup(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteScopeGroupFunc != nil {
+		return m.DeleteScopeGroupFunc(ctx, id)
+	}
+	return nil
+}
+
 // mockKeyManager implements a minimal key manager for testing JWKS functionality
 type mockKeyManager struct {
 	jwks *keys.JWKS
@@ -428,39 +492,11 @@ func setupOAuthProviderService() (*OAuthProviderService, *mockOAuthProviderStore
 
 // Helper to create a valid test client
 func createTestClient(clientType string) *models.OAuthClient {
-	clientID := uuid.New()
-	secretHash := func() *string {
-		if clientType == string(models.ClientTypeConfidential) {
-			hash, _ := bcrypt.GenerateFromPassword([]byte("agws_test_secret"), 10)
-			h := string(hash)
-			return &h
-		}
-		return nil
-	}()
-
-	return &models.OAuthClient{
-		ID:               clientID,
-		ClientID:         "agw_test_client_123",
-		ClientSecretHash: secretHash,
-		Name:             "Test Client",
-		Description:      "A test OAuth client",
-		ClientType:       clientType,
-		RedirectURIs:     []string{"https://example.com/callback"},
-		AllowedGrantTypes: []string{
-			string(models.GrantTypeAuthorizationCode),
-			string(models.GrantTypeRefreshToken),
-			string(models.GrantTypeClientCredentials),
-		},
-		AllowedScopes:   []string{"openid", "profile", "email"},
-		DefaultScopes:   []string{"openid"},
-		AccessTokenTTL:  900,
-		RefreshTokenTTL: 604800,
-		IDTokenTTL:      3600,
-		RequirePKCE:     false,
-		RequireConsent:  true,
-		FirstParty:      false,
-		IsActive:        true,
+	builder := fixtures.NewOAuthClientBuilder().WithClientID("agw_test_client_123")
+	if clientType == string(models.ClientTypePublic) {
+		builder.Public()
 	}
+	return builder.Build()
 }
 
 // ============================================================================
@@ -473,7 +509,7 @@ func TestValidateClientCredentials_ShouldReturnClient_WhenCredentialsValid(t *te
 	ctx := context.Background()
 
 	client := createTestClient(string(models.ClientTypeConfidential))
-	secret := "agws_test_secret"
+	secret := fixtures.DefaultClientSecret
 	secretHash, _ := bcrypt.GenerateFromPassword([]byte(secret), 10)
 	hashStr := string(secretHash)
 	client.ClientSecretHash = &hashStr
@@ -606,13 +642,13 @@ func TestGetDiscoveryDocument_ShouldReturnValidDocument(t *testing.T) {
 	// Assert
 	require.NotNil(t, doc)
 	assert.Equal(t, "https://auth.example.com", doc.Issuer)
-	assert.Equal(t, "https://auth.example.com/oauth2/authorize", doc.AuthorizationEndpoint)
-	assert.Equal(t, "https://auth.example.com/oauth2/token", doc.TokenEndpoint)
-	assert.Equal(t, "https://auth.example.com/oauth2/userinfo", doc.UserInfoEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/authorize", doc.AuthorizationEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/token", doc.TokenEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/userinfo", doc.UserInfoEndpoint)
 	assert.Equal(t, "https://auth.example.com/.well-known/jwks.json", doc.JwksURI)
-	assert.Equal(t, "https://auth.example.com/oauth2/revoke", doc.RevocationEndpoint)
-	assert.Equal(t, "https://auth.example.com/oauth2/introspect", doc.IntrospectionEndpoint)
-	assert.Equal(t, "https://auth.example.com/oauth2/device/code", doc.DeviceAuthorizationEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/revoke", doc.RevocationEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/introspect", doc.IntrospectionEndpoint)
+	assert.Equal(t, "https://auth.example.com/oauth/device/code", doc.DeviceAuthorizationEndpoint)
 }
 
 func TestGetDiscoveryDocument_ShouldContainSupportedScopes(t *testing.T) {
@@ -758,6 +794,188 @@ func TestIntrospectToken_ShouldReturnActive_WhenRefreshTokenValid(t *testing.T)
 	assert.Equal(t, userID.String(), result.Subject)
 }
 
+func TestIntrospectToken_ShouldReturnCnf_WhenTokenIsCertificateBound(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	thumbprint := "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"
+
+	mRepo.GetAccessTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthAccessToken, error) {
+		return &models.OAuthAccessToken{
+			ID:             uuid.New(),
+			IsActive:       true,
+			ExpiresAt:      time.Now().Add(time.Hour),
+			CreatedAt:      time.Now(),
+			CertThumbprint: &thumbprint,
+		}, nil
+	}
+
+	// Act
+	result, err := svc.IntrospectToken(context.Background(), "bound_token", "access_token", nil)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, result.Cnf)
+	assert.Equal(t, thumbprint, result.Cnf.X5TS256)
+}
+
+func TestClientCredentialsGrant_ShouldRequireCertificate_WhenClientUsesBoundTokens(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	client := createTestClient(string(models.ClientTypeConfidential))
+	secretHash, _ := bcrypt.GenerateFromPassword([]byte(fixtures.DefaultClientSecret), 10)
+	hashStr := string(secretHash)
+	client.ClientSecretHash = &hashStr
+	client.AllowedGrantTypes = []string{string(models.GrantTypeClientCredentials)}
+	client.CertBoundAccessTokens = true
+
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	secret := fixtures.DefaultClientSecret
+
+	// Act
+	_, err := svc.ClientCredentialsGrant(context.Background(), &models.TokenRequest{
+		GrantType:    string(models.GrantTypeClientCredentials),
+		ClientID:     client.ClientID,
+		ClientSecret: &secret,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestRefreshToken_ShouldRejectOtherCertificate_WhenRefreshTokenIsBound(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	client := createTestClient(string(models.ClientTypePublic))
+	thumbprint := "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"
+	revoked := false
+
+	mRepo.GetRefreshTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthRefreshToken, error) {
+		return &models.OAuthRefreshToken{
+			ID:             uuid.New(),
+			ClientID:       client.ID,
+			Client:         client,
+			UserID:         uuid.New(),
+			IsActive:       true,
+			ExpiresAt:      time.Now().Add(time.Hour),
+			CertThumbprint: &thumbprint,
+		}, nil
+	}
+	mRepo.RevokeRefreshTokenFunc = func(ctx context.Context, tokenHash string) error {
+		revoked = true
+		return nil
+	}
+	refreshToken := "bound_refresh_token"
+
+	// Act
+	_, err := svc.RefreshToken(context.Background(), &models.TokenRequest{
+		GrantType:            string(models.GrantTypeRefreshToken),
+		ClientID:             client.ClientID,
+		RefreshToken:         &refreshToken,
+		ClientCertThumbprint: "some-other-thumbprint",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidGrant)
+	assert.False(t, revoked)
+}
+
+func TestRefreshToken_ShouldRevokeLineage_WhenRefreshNonceMismatch(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, mAudit := setupOAuthProviderService()
+	client := createTestClient(string(models.ClientTypePublic))
+	client.RequireRefreshNonce = true
+	nonceHash := svc.hashToken("issued_nonce")
+	var revokedRefresh, revokedAccess string
+	var audited *models.AuditLog
+
+	mRepo.GetRefreshTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthRefreshToken, error) {
+		return &models.OAuthRefreshToken{
+			ID:          uuid.New(),
+			TokenHash:   tokenHash,
+			ClientID:    client.ID,
+			Client:      client,
+			UserID:      uuid.New(),
+			IsActive:    true,
+			ExpiresAt:   time.Now().Add(time.Hour),
+			NonceHash:   &nonceHash,
+			AccessToken: &models.OAuthAccessToken{TokenHash: "access_hash"},
+		}, nil
+	}
+	mRepo.RevokeRefreshTokenFunc = func(ctx context.Context, tokenHash string) error {
+		revokedRefresh = tokenHash
+		return nil
+	}
+	mRepo.RevokeAccessTokenFunc = func(ctx context.Context, tokenHash string) error {
+		revokedAccess = tokenHash
+		return nil
+	}
+	mAudit.CreateFunc = func(ctx context.Context, log *models.AuditLog) error {
+		audited = log
+		return nil
+	}
+	refreshToken := "stolen_refresh_token"
+	staleNonce := "stale_nonce"
+
+	// Act
+	_, err := svc.RefreshToken(context.Background(), &models.TokenRequest{
+		GrantType:    string(models.GrantTypeRefreshToken),
+		ClientID:     client.ClientID,
+		RefreshToken: &refreshToken,
+		RefreshNonce: &staleNonce,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidGrant)
+	assert.Equal(t, svc.hashToken(refreshToken), revokedRefresh)
+	assert.Equal(t, "access_hash", revokedAccess)
+	require.NotNil(t, audited)
+	assert.Equal(t, "oauth_refresh_nonce_mismatch", audited.Action)
+}
+
+func TestRefreshToken_ShouldAcceptNonce_WhenItMatches(t *testing.T) {
+	// Arrange
+	svc, mRepo, mUserRepo, _ := setupOAuthProviderService()
+	client := createTestClient(string(models.ClientTypePublic))
+	client.RequireRefreshNonce = true
+	nonce := "issued_nonce"
+	nonceHash := svc.hashToken(nonce)
+	revoked := false
+
+	mRepo.GetRefreshTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthRefreshToken, error) {
+		return &models.OAuthRefreshToken{
+			ID:        uuid.New(),
+			ClientID:  client.ID,
+			Client:    client,
+			UserID:    uuid.New(),
+			IsActive:  true,
+			ExpiresAt: time.Now().Add(time.Hour),
+			NonceHash: &nonceHash,
+		}, nil
+	}
+	mRepo.RevokeRefreshTokenFunc = func(ctx context.Context, tokenHash string) error {
+		revoked = true
+		return nil
+	}
+	mUserRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return nil, errors.New("stop after the nonce check")
+	}
+	refreshToken := "refresh_token"
+
+	// Act
+	_, err := svc.RefreshToken(context.Background(), &models.TokenRequest{
+		GrantType:    string(models.GrantTypeRefreshToken),
+		ClientID:     client.ClientID,
+		RefreshToken: &refreshToken,
+		RefreshNonce: &nonce,
+	})
+
+	// Assert: the request got past the nonce check without revoking anything
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.False(t, revoked)
+}
+
 func TestIntrospectToken_ShouldReturnInactive_WhenTokenNotFound(t *testing.T) {
 	// Arrange
 	svc, mRepo, _, _ := setupOAuthProviderService()
@@ -1148,6 +1366,97 @@ func TestListUserConsents_ShouldReturnConsents_WhenConsentsExist(t *testing.T) {
 	assert.Len(t, consents, 2)
 }
 
+// ============================================================================
+// Connected Apps Tests
+// ============================================================================
+
+func TestListConnectedApps_ShouldSkipRevokedConsentsAndAttachTokenStats(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	client := createTestClient(string(models.ClientTypeConfidential))
+	revokedAt := time.Now().Add(-time.Hour)
+	firstUsed := time.Now().Add(-48 * time.Hour)
+	lastUsed := time.Now().Add(-time.Minute)
+
+	mRepo.ListUserConsentsFunc = func(ctx context.Context, uID uuid.UUID) ([]*models.UserConsent, error) {
+		return []*models.UserConsent{
+			{ID: uuid.New(), UserID: userID, ClientID: client.ID, Client: client, Scopes: []string{"openid", "profile"}},
+			{ID: uuid.New(), UserID: userID, ClientID: uuid.New(), Scopes: []string{"openid"}, RevokedAt: &revokedAt},
+		}, nil
+	}
+	mRepo.GetUserTokenStatsFunc = func(ctx context.Context, uID uuid.UUID) ([]*models.OAuthClientTokenStats, error) {
+		return []*models.OAuthClientTokenStats{
+			{ClientID: client.ID, FirstUsedAt: &firstUsed, LastUsedAt: &lastUsed, ActiveTokens: 2},
+		}, nil
+	}
+
+	// Act
+	apps, err := svc.ListConnectedApps(ctx, userID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, client.ID, apps[0].ID)
+	assert.Equal(t, client.ClientID, apps[0].ClientID)
+	assert.Equal(t, client.Name, apps[0].Name)
+	assert.Equal(t, []string{"openid", "profile"}, apps[0].Scopes)
+	assert.Equal(t, &firstUsed, apps[0].FirstUsedAt)
+	assert.Equal(t, &lastUsed, apps[0].LastUsedAt)
+	assert.Equal(t, 2, apps[0].ActiveTokens)
+}
+
+func TestRevokeConnectedApp_ShouldRevokeConsent_WhenConnected(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, mAuditRepo := setupOAuthProviderService()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	clientID := uuid.New()
+	consentRevoked := false
+
+	mRepo.GetUserConsentFunc = func(ctx context.Context, uID, cID uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{ID: uuid.New(), UserID: uID, ClientID: cID}, nil
+	}
+	mRepo.RevokeConsentFunc = func(ctx context.Context, uID, cID uuid.UUID) error {
+		consentRevoked = true
+		return nil
+	}
+	mAuditRepo.CreateFunc = func(ctx context.Context, log *models.AuditLog) error {
+		return nil
+	}
+
+	// Act
+	err := svc.RevokeConnectedApp(ctx, userID, clientID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, consentRevoked)
+}
+
+func TestRevokeConnectedApp_ShouldReturnNotFound_WhenAlreadyRevoked(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	revokedAt := time.Now()
+	mRepo.GetUserConsentFunc = func(ctx context.Context, uID, cID uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{ID: uuid.New(), UserID: uID, ClientID: cID, RevokedAt: &revokedAt}, nil
+	}
+	mRepo.RevokeConsentFunc = func(ctx context.Context, uID, cID uuid.UUID) error {
+		t.Fatal("consent should not be revoked twice")
+		return nil
+	}
+
+	// Act
+	err := svc.RevokeConnectedApp(ctx, uuid.New(), uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
 // ============================================================================
 // CreateScope Tests
 // ============================================================================
@@ -1527,6 +1836,41 @@ func TestRotateClientSecret_ShouldReturnError_WhenClientIsPublic(t *testing.T) {
 	assert.Empty(t, newSecret)
 }
 
+// clientEventRecorder records the client events OAuthProviderService hands to webhooks
+type clientEventRecorder struct {
+	events chan string
+}
+
+func (r *clientEventRecorder) NotifyClientEvent(ctx context.Context, clientID uuid.UUID, eventType string, data map[string]interface{}) error {
+	r.events <- eventType
+	return nil
+}
+
+func TestRotateClientSecret_ShouldNotifyClientWebhooks(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	recorder := &clientEventRecorder{events: make(chan string, 1)}
+	svc.SetClientEvents(recorder)
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	mRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	// Act
+	_, err := svc.RotateClientSecret(ctx, client.ID)
+
+	// Assert
+	require.NoError(t, err)
+	select {
+	case event := <-recorder.events:
+		assert.Equal(t, models.OAuthClientEventSecretRotated, event)
+	case <-time.After(time.Second):
+		t.Fatal("secret rotation was not reported to the client webhooks")
+	}
+}
+
 // ============================================================================
 // ApproveDeviceCode Tests
 // ============================================================================
@@ -1710,3 +2054,574 @@ func TestGetClientByClientID_ShouldReturnError_WhenClientNotFound(t *testing.T)
 	assert.Error(t, err)
 	assert.Nil(t, client)
 }
+
+// ============================================================================
+// EmulateClientFlow Tests
+// ============================================================================
+
+func flowStepStatus(resp *models.OAuthClientEmulateResponse, name string) string {
+	for _, step := range resp.Steps {
+		if step.Name == name {
+			return step.Status
+		}
+	}
+	return ""
+}
+
+func TestEmulateClientFlow_ShouldTraceTokenClaims_WithoutPersisting(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	mRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	mRepo.CreateAuthorizationCodeFunc = func(ctx context.Context, code *models.AuthorizationCode) error {
+		t.Fatal("authorization code must not be stored")
+		return nil
+	}
+	mRepo.CreateAccessTokenFunc = func(ctx context.Context, token *models.OAuthAccessToken) error {
+		t.Fatal("access token must not be stored")
+		return nil
+	}
+
+	// Act
+	resp, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{
+		Scope: "openid email",
+		Nonce: "abc",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, []string{"openid", "email"}, resp.GrantedScopes)
+	assert.True(t, resp.RefreshToken)
+	assert.Equal(t, models.FlowStepPassed, flowStepStatus(resp, "redirect_uri"))
+	assert.Equal(t, models.FlowStepPassed, flowStepStatus(resp, "consent"))
+	assert.Equal(t, models.FlowStepSkipped, flowStepStatus(resp, "pkce"))
+	assert.Equal(t, "openid email", resp.AccessTokenClaims["scope"])
+	assert.Equal(t, client.ClientID, resp.AccessTokenClaims["client_id"])
+	assert.Equal(t, "abc", resp.IDTokenClaims["nonce"])
+	assert.Equal(t, "emulated.user@example.com", resp.IDTokenClaims["email"])
+	assert.NotContains(t, resp.IDTokenClaims, "name")
+}
+
+func TestEmulateClientFlow_ShouldReportMisconfiguration(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypePublic))
+	mRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	// Act
+	resp, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{
+		RedirectURI: "https://example.com/other",
+		Scope:       "openid admin",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, models.FlowStepFailed, flowStepStatus(resp, "redirect_uri"))
+	assert.Equal(t, models.FlowStepFailed, flowStepStatus(resp, "scope"))
+	assert.Equal(t, models.FlowStepFailed, flowStepStatus(resp, "pkce"))
+	assert.Equal(t, models.FlowStepSkipped, flowStepStatus(resp, "token"))
+	assert.Nil(t, resp.AccessTokenClaims)
+}
+
+func TestEmulateClientFlow_ShouldBindAccessTokenToClientCertificate(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.CertBoundAccessTokens = true
+	mRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	// Act
+	withoutCert, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{Scope: "openid"})
+	require.NoError(t, err)
+	withCert, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{
+		Scope:                "openid",
+		ClientCertThumbprint: "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2",
+	})
+	require.NoError(t, err)
+
+	// Assert
+	assert.False(t, withoutCert.Success)
+	assert.Equal(t, models.FlowStepFailed, flowStepStatus(withoutCert, "client_certificate"))
+	assert.Nil(t, withoutCert.AccessTokenClaims)
+
+	assert.True(t, withCert.Success)
+	assert.Equal(t, models.FlowStepPassed, flowStepStatus(withCert, "client_certificate"))
+	assert.Equal(t, map[string]interface{}{"x5t#S256": "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"}, withCert.AccessTokenClaims["cnf"])
+}
+
+func TestEmulateClientFlow_ShouldVerifyPKCE(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypePublic))
+	mRepo.GetClientByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	verifier := "dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge, err := GenerateCodeChallenge(verifier, CodeChallengeMethodS256)
+	require.NoError(t, err)
+
+	// Act
+	resp, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: CodeChallengeMethodS256,
+		CodeVerifier:        verifier,
+	})
+	require.NoError(t, err)
+	mismatch, err := svc.EmulateClientFlow(ctx, client.ID, &models.OAuthClientEmulateRequest{
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: CodeChallengeMethodS256,
+		CodeVerifier:        "wrong-verifier-wrong-verifier-wrong-verifier",
+	})
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, resp.Success)
+	assert.Equal(t, models.FlowStepPassed, flowStepStatus(resp, "pkce"))
+	// Without OIDC keys the trace stops before token issuance
+	assert.Equal(t, models.FlowStepSkipped, flowStepStatus(resp, "token"))
+	assert.False(t, mismatch.Success)
+	assert.Equal(t, models.FlowStepFailed, flowStepStatus(mismatch, "pkce"))
+}
+
+// ============================================================================
+// PreviewTokens Tests
+// ============================================================================
+
+func TestPreviewTokens_ShouldBuildClaims_WithoutPersisting(t *testing.T) {
+	// Arrange
+	svc, mRepo, mUserRepo, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	user := &models.User{
+		ID:            uuid.New(),
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		FullName:      "Jane Doe",
+		IsActive:      true,
+		Roles:         []models.Role{{Name: "editor"}, {Name: "viewer"}},
+	}
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	mRepo.GetUserConsentFunc = func(ctx context.Context, userID, clientID uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{UserID: userID, ClientID: clientID, Scopes: []string{"openid", "email"}}, nil
+	}
+	mRepo.CreateAccessTokenFunc = func(ctx context.Context, token *models.OAuthAccessToken) error {
+		t.Fatal("access token must not be stored")
+		return nil
+	}
+	mUserRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+
+	// Act
+	resp, err := svc.PreviewTokens(ctx, &models.TokenPreviewRequest{
+		UserID:   user.ID,
+		ClientID: client.ClientID,
+		Scope:    "openid email",
+		Nonce:    "abc",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Issuable)
+	assert.Empty(t, resp.Problems)
+	assert.Equal(t, []string{"openid", "email"}, resp.GrantedScopes)
+	assert.Equal(t, []string{"editor", "viewer"}, resp.Roles)
+	assert.True(t, resp.RefreshToken)
+	assert.Equal(t, user.ID.String(), resp.AccessTokenClaims["sub"])
+	assert.Equal(t, "openid email", resp.AccessTokenClaims["scope"])
+	assert.Equal(t, []interface{}{"editor", "viewer"}, resp.AccessTokenClaims["roles"])
+	assert.Equal(t, "abc", resp.IDTokenClaims["nonce"])
+	assert.Equal(t, "jane@example.com", resp.IDTokenClaims["email"])
+	assert.Equal(t, "jane@example.com", resp.UserInfoClaims["email"])
+	assert.NotContains(t, resp.UserInfoClaims, "name")
+}
+
+func TestPreviewTokens_ShouldReportBlockingPolicies(t *testing.T) {
+	// Arrange
+	svc, mRepo, mUserRepo, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.CertBoundAccessTokens = true
+	user := &models.User{ID: uuid.New(), State: models.UserStateSuspended}
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	mRepo.GetUserConsentFunc = func(ctx context.Context, userID, clientID uuid.UUID) (*models.UserConsent, error) {
+		return nil, nil
+	}
+	mUserRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+
+	// Act
+	resp, err := svc.PreviewTokens(ctx, &models.TokenPreviewRequest{
+		UserID:   user.ID,
+		ClientID: client.ClientID,
+		Scope:    "profile admin",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, resp.Issuable)
+	assert.Len(t, resp.Problems, 4)
+	assert.Contains(t, resp.Problems, "user account is suspended")
+	assert.Contains(t, resp.Problems, "user has not consented to the client")
+	assert.Empty(t, resp.Roles)
+	assert.Nil(t, resp.IDTokenClaims)
+	assert.NotContains(t, resp.AccessTokenClaims, "cnf")
+}
+
+// ============================================================================
+// EndSession Tests
+// ============================================================================
+
+func TestEndSession_ShouldRedirectToRegisteredURI_WithState(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.PostLogoutRedirectURIs = []string{"https://app.example.com/signed-out?lang=en"}
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	// Act
+	result, err := svc.EndSession(ctx, &models.EndSessionRequest{
+		ClientID:              client.ClientID,
+		PostLogoutRedirectURI: "https://app.example.com/signed-out?lang=en",
+		State:                 "s1",
+	}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/signed-out?lang=en&state=s1", result.RedirectURI)
+	assert.Empty(t, result.FrontchannelLogoutURIs)
+}
+
+func TestEndSession_ShouldRejectUnregisteredRedirectURI(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.PostLogoutRedirectURIs = []string{"https://app.example.com/signed-out"}
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+
+	// Act
+	_, err := svc.EndSession(ctx, &models.EndSessionRequest{
+		ClientID:              client.ClientID,
+		PostLogoutRedirectURI: "https://evil.example.com/",
+	}, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestEndSession_ShouldRequireClient_ForRedirectURI(t *testing.T) {
+	// Arrange
+	svc, _, _, _ := setupOAuthProviderService()
+
+	// Act
+	_, err := svc.EndSession(context.Background(), &models.EndSessionRequest{
+		PostLogoutRedirectURI: "https://app.example.com/signed-out",
+	}, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestEndSession_ShouldRejectInvalidIDTokenHint(t *testing.T) {
+	// Arrange
+	svc, _, _, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+
+	// Act
+	_, err := svc.EndSession(context.Background(), &models.EndSessionRequest{IDTokenHint: "not-a-jwt"}, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestEndSession_ShouldListFrontchannelLogoutURIs_ForSignedInUser(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	userID := uuid.New()
+
+	mRepo.ListFrontchannelLogoutClientsFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthClient, error) {
+		assert.Equal(t, userID, id)
+		return []*models.OAuthClient{
+			{FrontchannelLogoutURI: "https://a.example.com/logout"},
+			{FrontchannelLogoutURI: "https://b.example.com/logout"},
+		}, nil
+	}
+
+	// Act
+	result, err := svc.EndSession(context.Background(), &models.EndSessionRequest{Confirmed: true}, &userID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, result.RedirectURI)
+	assert.Equal(t, []string{"https://a.example.com/logout", "https://b.example.com/logout"}, result.FrontchannelLogoutURIs)
+}
+
+func TestEndSession_ShouldRequireConfirmation_WithoutIDTokenHint(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	userID := uuid.New()
+	mRepo.ListFrontchannelLogoutClientsFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthClient, error) {
+		t.Error("clients must not be logged out before the user confirms")
+		return nil, nil
+	}
+
+	// Act
+	result, err := svc.EndSession(context.Background(), &models.EndSessionRequest{}, &userID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.ConfirmationRequired)
+	assert.Empty(t, result.FrontchannelLogoutURIs)
+}
+
+func TestEndSession_ShouldAddIssuerAndSessionID_ToFrontchannelLogoutURIs(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	userID := uuid.New()
+	mRepo.ListFrontchannelLogoutClientsFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthClient, error) {
+		return []*models.OAuthClient{{ClientID: "app", FrontchannelLogoutURI: "https://a.example.com/logout?tenant=1"}}, nil
+	}
+
+	// Act
+	result, err := svc.EndSession(context.Background(), &models.EndSessionRequest{Confirmed: true}, &userID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.FrontchannelLogoutURIs, 1)
+	logoutURI, err := url.Parse(result.FrontchannelLogoutURIs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "1", logoutURI.Query().Get("tenant"))
+	assert.Equal(t, "https://auth.example.com", logoutURI.Query().Get("iss"))
+	assert.Equal(t, svc.oidcJWT.SessionID(userID, "app"), logoutURI.Query().Get("sid"))
+}
+
+func TestEndSession_ShouldRejectIDTokenHint_OfAnotherUser(t *testing.T) {
+	// Arrange
+	svc, _, _, _ := setupOAuthProviderService()
+	privateKey, err := keys.GenerateKey(keys.RS256)
+	require.NoError(t, err)
+	signingKey, err := keys.NewSigningKey("k1", keys.RS256, privateKey)
+	require.NoError(t, err)
+	keyManager, err := keys.NewManagerFromKeys([]*keys.SigningKey{signingKey}, "k1")
+	require.NoError(t, err)
+	svc.oidcJWT = jwt.NewOIDCService(keyManager, "https://auth.example.com")
+
+	hint, err := svc.oidcJWT.GenerateIDToken(uuid.New(), "app", "", []string{"openid"}, &models.User{}, time.Hour)
+	require.NoError(t, err)
+	signedIn := uuid.New()
+
+	// Act
+	_, err = svc.EndSession(context.Background(), &models.EndSessionRequest{IDTokenHint: hint}, &signedIn)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestCreateClient_ShouldRejectFrontchannelLogoutURIWithFragment(t *testing.T) {
+	// Arrange
+	svc, _, _, _ := setupOAuthProviderService()
+
+	// Act
+	_, err := svc.CreateClient(context.Background(), &models.CreateOAuthClientRequest{
+		Name:                  "App",
+		ClientType:            string(models.ClientTypeConfidential),
+		FrontchannelLogoutURI: "https://app.example.com/logout#frag",
+	}, nil)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestCreateClient_ShouldRejectClaimsMappingOverridingStandardClaims(t *testing.T) {
+	// Arrange
+	svc, _, _, _ := setupOAuthProviderService()
+
+	// Act
+	_, err := svc.CreateClient(context.Background(), &models.CreateOAuthClientRequest{
+		Name:          "App",
+		ClientType:    string(models.ClientTypeConfidential),
+		ClaimsMapping: &models.ClaimsMapping{Attributes: map[string]string{"email": "username"}},
+	}, nil)
+
+	// Assert
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+type stubUserGroupLister struct {
+	groups []*models.Group
+}
+
+func (s *stubUserGroupLister) GetUserGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error) {
+	return s.groups, nil
+}
+
+func TestPreviewTokens_ShouldIncludeClientCustomClaims(t *testing.T) {
+	// Arrange
+	svc, mRepo, mUserRepo, _ := setupOAuthProviderService()
+	svc.oidcJWT = jwt.NewOIDCService(nil, "https://auth.example.com")
+	svc.SetGroups(&stubUserGroupLister{groups: []*models.Group{{Name: "engineering"}}})
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.ClaimsMapping = &models.ClaimsMapping{
+		Namespace:   "https://example.com/",
+		Static:      map[string]interface{}{"tenant": "acme"},
+		Attributes:  map[string]string{"login": "username"},
+		RolesClaim:  "roles",
+		GroupsClaim: "groups",
+	}
+	user := &models.User{ID: uuid.New(), Username: "jane", IsActive: true, Roles: []models.Role{{Name: "editor"}}}
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	mRepo.GetUserConsentFunc = func(ctx context.Context, userID, clientID uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{UserID: userID, ClientID: clientID, Scopes: []string{"openid"}}, nil
+	}
+	mUserRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+
+	// Act
+	resp, err := svc.PreviewTokens(ctx, &models.TokenPreviewRequest{UserID: user.ID, ClientID: client.ClientID, Scope: "openid"})
+
+	// Assert
+	require.NoError(t, err)
+	for _, claims := range []map[string]interface{}{resp.IDTokenClaims, resp.UserInfoClaims} {
+		assert.Equal(t, "acme", claims["https://example.com/tenant"])
+		assert.Equal(t, "jane", claims["https://example.com/login"])
+		assert.Equal(t, []interface{}{"editor"}, claims["https://example.com/roles"])
+		assert.Equal(t, []interface{}{"engineering"}, claims["https://example.com/groups"])
+	}
+	assert.Equal(t, user.ID.String(), resp.IDTokenClaims["sub"])
+	assert.NotContains(t, resp.AccessTokenClaims, "https://example.com/tenant")
+}
+
+func TestGetUserInfo_ShouldIncludeClientCustomClaims(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.ClaimsMapping = &models.ClaimsMapping{Static: map[string]interface{}{"tenant": "acme"}}
+	user := &models.User{ID: uuid.New(), Email: "jane@example.com"}
+	mRepo.GetAccessTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthAccessToken, error) {
+		return &models.OAuthAccessToken{
+			ClientID:  client.ID,
+			UserID:    &user.ID,
+			Scope:     "openid email",
+			IsActive:  true,
+			ExpiresAt: time.Now().Add(time.Hour),
+			Client:    client,
+			User:      user,
+		}, nil
+	}
+
+	// Act
+	info, err := svc.GetUserInfo(ctx, "access-token", "")
+
+	// Assert
+	require.NoError(t, err)
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"`+user.ID.String()+`","email":"jane@example.com","email_verified":false,"tenant":"acme"}`, string(data))
+}
+
+func TestGetUserInfo_ShouldRequireBoundCertificate(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	ctx := context.Background()
+
+	client := createTestClient(string(models.ClientTypeConfidential))
+	user := &models.User{ID: uuid.New(), Email: "jane@example.com"}
+	thumbprint := "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"
+	mRepo.GetAccessTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthAccessToken, error) {
+		return &models.OAuthAccessToken{
+			ClientID:       client.ID,
+			UserID:         &user.ID,
+			Scope:          "openid",
+			IsActive:       true,
+			ExpiresAt:      time.Now().Add(time.Hour),
+			CertThumbprint: &thumbprint,
+			Client:         client,
+			User:           user,
+		}, nil
+	}
+
+	// Act
+	_, withoutCert := svc.GetUserInfo(ctx, "access-token", "")
+	_, otherCert := svc.GetUserInfo(ctx, "access-token", "other-thumbprint")
+	info, err := svc.GetUserInfo(ctx, "access-token", thumbprint)
+
+	// Assert
+	assert.ErrorIs(t, withoutCert, ErrInvalidGrant)
+	assert.ErrorIs(t, otherCert, ErrInvalidGrant)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), info.Subject)
+}
+
+func TestGetUserInfo_ShouldRequireBoundCertificate_ForJWTAccessToken(t *testing.T) {
+	// Arrange
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	privateKey, err := keys.GenerateKey(keys.RS256)
+	require.NoError(t, err)
+	signingKey, err := keys.NewSigningKey("k1", keys.RS256, privateKey)
+	require.NoError(t, err)
+	keyManager, err := keys.NewManagerFromKeys([]*keys.SigningKey{signingKey}, "k1")
+	require.NoError(t, err)
+	svc.oidcJWT = jwt.NewOIDCService(keyManager, "https://auth.example.com")
+
+	mRepo.GetAccessTokenFunc = func(ctx context.Context, tokenHash string) (*models.OAuthAccessToken, error) {
+		return nil, models.ErrNotFound
+	}
+	userID := uuid.New()
+	claims := svc.oidcJWT.BuildOAuthAccessTokenClaims(&userID, "app", "openid", nil, time.Hour)
+	claims.Cnf = &jwt.Confirmation{X5TS256: "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"}
+	accessToken, err := svc.oidcJWT.SignOAuthAccessToken(claims)
+	require.NoError(t, err)
+
+	// Act
+	_, err = svc.GetUserInfo(context.Background(), accessToken, "other-thumbprint")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidGrant)
+}
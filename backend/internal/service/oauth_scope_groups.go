@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+var scopeGroupNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+var (
+	errScopeGroupInvalidName = models.NewAppError(http.StatusBadRequest, "Scope group name may only contain letters, digits and _ . : -")
+	errScopeGroupEmptyMember = models.NewAppError(http.StatusBadRequest, "Scope group members must be non-empty scope names without spaces")
+	errScopeGroupSelfMember  = models.NewAppError(http.StatusBadRequest, "Scope group cannot contain itself")
+	errScopeGroupNameTaken   = models.NewAppError(http.StatusConflict, "A scope or scope group with this name already exists")
+	errScopeGroupNotFound    = models.NewAppError(http.StatusNotFound, "Scope group not found")
+)
+
+// scopeExpander resolves scope groups and "prefix:*" wildcards into concrete scopes
+type scopeExpander struct {
+	groups map[string][]string
+	scopes []string
+}
+
+func newScopeExpander(groups []*models.OAuthScopeGroup, scopes []*models.OAuthScope) *scopeExpander {
+	e := &scopeExpander{groups: make(map[string][]string, len(groups))}
+	for _, group := range groups {
+		e.groups[group.Name] = group.Scopes
+	}
+	for _, scope := range scopes {
+		if !strings.Contains(scope.Name, "*") {
+			e.scopes = append(e.scopes, scope.Name)
+		}
+	}
+	return e
+}
+
+// expand returns the concrete scopes behind names, keeping their order and dropping
+// duplicates. Names that are neither a group nor a wildcard with registered children are
+// kept as they are. Groups that include each other are expanded once.
+func (e *scopeExpander) expand(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var walk func(name string)
+	walk = func(name string) {
+		if members, ok := e.groups[name]; ok {
+			if visited[name] {
+				return
+			}
+			visited[name] = true
+			for _, member := range members {
+				walk(member)
+			}
+			return
+		}
+
+		expanded := e.matchWildcard(name)
+		if len(expanded) == 0 {
+			expanded = []string{name}
+		}
+		for _, scope := range expanded {
+			if !seen[scope] {
+				seen[scope] = true
+				result = append(result, scope)
+			}
+		}
+	}
+
+	for _, name := range names {
+		walk(name)
+	}
+	return result
+}
+
+func (e *scopeExpander) matchWildcard(name string) []string {
+	if !strings.HasSuffix(name, models.ScopeWildcardSuffix) {
+		return nil
+	}
+	prefix := strings.TrimSuffix(name, "*")
+	var matched []string
+	for _, scope := range e.scopes {
+		if strings.HasPrefix(scope, prefix) {
+			matched = append(matched, scope)
+		}
+	}
+	return matched
+}
+
+// loadScopeExpander loads the scope groups, and the scope registry when names contain a
+// wildcard
+func (s *OAuthProviderService) loadScopeExpander(ctx context.Context, names ...[]string) (*scopeExpander, error) {
+	groups, err := s.repo.ListScopeGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	needScopes := false
+	for _, group := range groups {
+		needScopes = needScopes || hasWildcardScope(group.Scopes)
+	}
+	for _, list := range names {
+		needScopes = needScopes || hasWildcardScope(list)
+	}
+
+	var scopes []*models.OAuthScope
+	if needScopes {
+		if scopes, err = s.repo.ListScopes(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return newScopeExpander(groups, scopes), nil
+}
+
+// expandScopes resolves scope groups and wildcards in the requested scopes
+func (s *OAuthProviderService) expandScopes(ctx context.Context, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return requested, nil
+	}
+	expander, err := s.loadScopeExpander(ctx, requested)
+	if err != nil {
+		s.logger.Error("failed to load scope groups", map[string]interface{}{"error": err.Error()})
+		return nil, ErrServerError
+	}
+	return expander.expand(requested), nil
+}
+
+// resolveClientScopes expands the requested scopes, falling back to the client's default
+// scopes when none are requested, and checks them against the client's expanded allowed
+// scopes
+func (s *OAuthProviderService) resolveClientScopes(ctx context.Context, client *models.OAuthClient, requested []string) ([]string, error) {
+	expander, err := s.loadScopeExpander(ctx, requested, client.AllowedScopes, client.DefaultScopes)
+	if err != nil {
+		s.logger.Error("failed to load scope groups", map[string]interface{}{
+			"error":     err.Error(),
+			"client_id": client.ClientID,
+		})
+		return nil, ErrServerError
+	}
+
+	allowed := expander.expand(client.AllowedScopes)
+	if len(requested) == 0 {
+		return expander.expand(client.DefaultScopes), nil
+	}
+
+	scopes := expander.expand(requested)
+	if err := s.validateScopes(scopes, allowed); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+func hasWildcardScope(scopes []string) bool {
+	for _, scope := range scopes {
+		if strings.HasSuffix(scope, models.ScopeWildcardSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListScopeGroups lists all scope groups along with the scopes each currently expands to
+func (s *OAuthProviderService) ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+	groups, err := s.repo.ListScopeGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []*models.OAuthScope
+	for _, group := range groups {
+		if hasWildcardScope(group.Scopes) {
+			if scopes, err = s.repo.ListScopes(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	expander := newScopeExpander(groups, scopes)
+	for _, group := range groups {
+		group.ExpandedScopes = expander.expand([]string{group.Name})
+	}
+	return groups, nil
+}
+
+// CreateScopeGroup creates a scope group
+func (s *OAuthProviderService) CreateScopeGroup(ctx context.Context, req *models.CreateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	if !scopeGroupNamePattern.MatchString(req.Name) {
+		return nil, errScopeGroupInvalidName
+	}
+	if err := validateScopeGroupMembers(req.Name, req.Scopes); err != nil {
+		return nil, err
+	}
+
+	scopes, err := s.repo.ListScopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		if scope.Name == req.Name {
+			return nil, errScopeGroupNameTaken
+		}
+	}
+
+	group := &models.OAuthScopeGroup{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Scopes:      req.Scopes,
+	}
+	if err := s.repo.CreateScopeGroup(ctx, group); err != nil {
+		if errors.Is(err, models.ErrAlreadyExists) {
+			return nil, errScopeGroupNameTaken
+		}
+		return nil, err
+	}
+
+	s.logger.Info("oauth scope group created", map[string]interface{}{
+		"name":   group.Name,
+		"scopes": group.Scopes,
+	})
+
+	return group, nil
+}
+
+// UpdateScopeGroup updates the display name, description or members of a scope group
+func (s *OAuthProviderService) UpdateScopeGroup(ctx context.Context, id uuid.UUID, req *models.UpdateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	group, err := s.repo.GetScopeGroupByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errScopeGroupNotFound
+		}
+		return nil, err
+	}
+
+	if req.DisplayName != "" {
+		group.DisplayName = req.DisplayName
+	}
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+	if req.Scopes != nil {
+		if err := validateScopeGroupMembers(group.Name, req.Scopes); err != nil {
+			return nil, err
+		}
+		group.Scopes = req.Scopes
+	}
+	group.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateScopeGroup(ctx, group); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errScopeGroupNotFound
+		}
+		return nil, err
+	}
+
+	s.logger.Info("oauth scope group updated", map[string]interface{}{
+		"name":   group.Name,
+		"scopes": group.Scopes,
+	})
+
+	return group, nil
+}
+
+// DeleteScopeGroup deletes a scope group. Consents and tokens keep the scopes the group
+// expanded to when they were issued.
+func (s *OAuthProviderService) DeleteScopeGroup(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteScopeGroup(ctx, id); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return errScopeGroupNotFound
+		}
+		return err
+	}
+
+	s.logger.Info("oauth scope group deleted", map[string]interface{}{
+		"scope_group_id": id.String(),
+	})
+
+	return nil
+}
+
+func validateScopeGroupMembers(name string, members []string) error {
+	if len(members) == 0 {
+		return errScopeGroupEmptyMember
+	}
+	for _, member := range members {
+		if strings.TrimSpace(member) == "" || strings.ContainsAny(member, " \t\n") {
+			return errScopeGroupEmptyMember
+		}
+		if member == name {
+			return errScopeGroupSelfMember
+		}
+	}
+	return nil
+}
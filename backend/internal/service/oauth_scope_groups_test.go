@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testScopeGroups() []*models.OAuthScopeGroup {
+	return []*models.OAuthScopeGroup{
+		{ID: uuid.New(), Name: "billing", Scopes: []string{"invoices:read", "payments:*"}},
+		{ID: uuid.New(), Name: "everything", Scopes: []string{"billing", "profile", "loop"}},
+		{ID: uuid.New(), Name: "loop", Scopes: []string{"everything", "email"}},
+	}
+}
+
+func testRegisteredScopes() []*models.OAuthScope {
+	return []*models.OAuthScope{
+		{Name: "openid"},
+		{Name: "profile"},
+		{Name: "email"},
+		{Name: "invoices:read"},
+		{Name: "payments:read"},
+		{Name: "payments:refund"},
+		{Name: "payments:*"},
+	}
+}
+
+func TestScopeExpander_ShouldResolveGroupsAndWildcards(t *testing.T) {
+	expander := newScopeExpander(testScopeGroups(), testRegisteredScopes())
+
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{"plain scopes", []string{"openid", "profile"}, []string{"openid", "profile"}},
+		{"wildcard", []string{"payments:*"}, []string{"payments:read", "payments:refund"}},
+		{"group", []string{"billing"}, []string{"invoices:read", "payments:read", "payments:refund"}},
+		{"nested cyclic groups", []string{"openid", "everything"}, []string{"openid", "invoices:read", "payments:read", "payments:refund", "profile", "email"}},
+		{"duplicates", []string{"payments:read", "billing", "payments:read"}, []string{"payments:read", "invoices:read", "payments:refund"}},
+		{"unmatched wildcard", []string{"orders:*"}, []string{"orders:*"}},
+		{"unknown scope", []string{"custom"}, []string{"custom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, expander.expand(tt.input))
+		})
+	}
+}
+
+func setupScopeGroupService() (*OAuthProviderService, *mockOAuthProviderStore, *models.OAuthClient) {
+	svc, mRepo, _, _ := setupOAuthProviderService()
+	client := createTestClient(string(models.ClientTypeConfidential))
+	client.AllowedScopes = []string{"openid", "billing"}
+
+	mRepo.GetClientByClientIDFunc = func(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+		return client, nil
+	}
+	mRepo.ListScopeGroupsFunc = func(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+		return testScopeGroups(), nil
+	}
+	mRepo.ListScopesFunc = func(ctx context.Context) ([]*models.OAuthScope, error) {
+		return testRegisteredScopes(), nil
+	}
+	return svc, mRepo, client
+}
+
+func TestAuthorize_ShouldStoreExpandedScopes_WhenGroupRequested(t *testing.T) {
+	svc, mRepo, client := setupScopeGroupService()
+	userID := uuid.New()
+
+	var stored *models.AuthorizationCode
+	mRepo.CreateAuthorizationCodeFunc = func(ctx context.Context, code *models.AuthorizationCode) error {
+		stored = code
+		return nil
+	}
+	mRepo.GetUserConsentFunc = func(ctx context.Context, uid, cid uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{UserID: uid, ClientID: cid, Scopes: []string{"openid", "invoices:read", "payments:read", "payments:refund"}}, nil
+	}
+
+	_, err := svc.Authorize(context.Background(), &models.AuthorizeRequest{
+		ResponseType: "code",
+		ClientID:     client.ClientID,
+		RedirectURI:  client.RedirectURIs[0],
+		Scope:        "openid payments:*",
+		State:        "state",
+	}, userID)
+
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "openid payments:read payments:refund", stored.Scope)
+}
+
+func TestAuthorize_ShouldRequireConsent_WhenGroupGainedScopes(t *testing.T) {
+	svc, mRepo, client := setupScopeGroupService()
+	mRepo.GetUserConsentFunc = func(ctx context.Context, uid, cid uuid.UUID) (*models.UserConsent, error) {
+		return &models.UserConsent{UserID: uid, ClientID: cid, Scopes: []string{"invoices:read", "payments:read"}}, nil
+	}
+
+	_, err := svc.Authorize(context.Background(), &models.AuthorizeRequest{
+		ResponseType: "code",
+		ClientID:     client.ClientID,
+		RedirectURI:  client.RedirectURIs[0],
+		Scope:        "billing",
+		State:        "state",
+	}, uuid.New())
+
+	assert.ErrorIs(t, err, ErrConsentRequired)
+}
+
+func TestAuthorize_ShouldRejectScopeOutsideAllowedGroups(t *testing.T) {
+	svc, _, client := setupScopeGroupService()
+
+	_, err := svc.Authorize(context.Background(), &models.AuthorizeRequest{
+		ResponseType: "code",
+		ClientID:     client.ClientID,
+		RedirectURI:  client.RedirectURIs[0],
+		Scope:        "openid email",
+		State:        "state",
+	}, uuid.New())
+
+	assert.ErrorIs(t, err, ErrInvalidScope)
+}
+
+func TestResolveClientScopes_ShouldExpandDefaultScopes(t *testing.T) {
+	svc, mRepo, client := setupScopeGroupService()
+	client.DefaultScopes = []string{"billing"}
+
+	scopes, err := svc.resolveClientScopes(context.Background(), client, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoices:read", "payments:read", "payments:refund"}, scopes)
+
+	mRepo.ListScopeGroupsFunc = func(ctx context.Context) ([]*models.OAuthScopeGroup, error) {
+		return nil, assert.AnError
+	}
+	_, err = svc.resolveClientScopes(context.Background(), client, []string{"openid"})
+	assert.ErrorIs(t, err, ErrServerError)
+}
+
+func TestGrantConsent_ShouldStoreExpandedScopes(t *testing.T) {
+	svc, mRepo, client := setupScopeGroupService()
+
+	var stored *models.UserConsent
+	mRepo.CreateOrUpdateConsentFunc = func(ctx context.Context, consent *models.UserConsent) error {
+		stored = consent
+		return nil
+	}
+
+	err := svc.GrantConsent(context.Background(), uuid.New(), client.ClientID, []string{"openid", "billing"})
+
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, []string{"openid", "invoices:read", "payments:read", "payments:refund"}, stored.Scopes)
+}
+
+func TestCreateScopeGroup_ShouldValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.CreateOAuthScopeGroupRequest
+		code int
+	}{
+		{"wildcard name", models.CreateOAuthScopeGroupRequest{Name: "api:*", Scopes: []string{"openid"}}, http.StatusBadRequest},
+		{"space in name", models.CreateOAuthScopeGroupRequest{Name: "my group", Scopes: []string{"openid"}}, http.StatusBadRequest},
+		{"self member", models.CreateOAuthScopeGroupRequest{Name: "admin", Scopes: []string{"admin"}}, http.StatusBadRequest},
+		{"empty member", models.CreateOAuthScopeGroupRequest{Name: "admin", Scopes: []string{" "}}, http.StatusBadRequest},
+		{"clashes with scope", models.CreateOAuthScopeGroupRequest{Name: "profile", Scopes: []string{"email"}}, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mRepo, _ := setupScopeGroupService()
+			mRepo.CreateScopeGroupFunc = func(ctx context.Context, group *models.OAuthScopeGroup) error {
+				t.Fatal("group should not be created")
+				return nil
+			}
+
+			_, err := svc.CreateScopeGroup(context.Background(), &tt.req)
+
+			var appErr *models.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.code, appErr.Code)
+		})
+	}
+}
+
+func TestCreateScopeGroup_ShouldMapDuplicateToConflict(t *testing.T) {
+	svc, mRepo, _ := setupScopeGroupService()
+	mRepo.CreateScopeGroupFunc = func(ctx context.Context, group *models.OAuthScopeGroup) error {
+		return models.ErrAlreadyExists
+	}
+
+	_, err := svc.CreateScopeGroup(context.Background(), &models.CreateOAuthScopeGroupRequest{
+		Name:        "billing",
+		DisplayName: "Billing",
+		Scopes:      []string{"invoices:read"},
+	})
+
+	assert.Equal(t, errScopeGroupNameTaken, err)
+}
+
+func TestListScopeGroups_ShouldIncludeExpandedScopes(t *testing.T) {
+	svc, _, _ := setupScopeGroupService()
+
+	groups, err := svc.ListScopeGroups(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"invoices:read", "payments:read", "payments:refund"}, groups[0].ExpandedScopes)
+	assert.Equal(t, []string{"invoices:read", "payments:read", "payments:refund", "profile", "email"}, groups[1].ExpandedScopes)
+}
+
+func TestUpdateScopeGroup_ShouldReturnNotFound_WhenGroupMissing(t *testing.T) {
+	svc, _, _ := setupScopeGroupService()
+
+	_, err := svc.UpdateScopeGroup(context.Background(), uuid.New(), &models.UpdateOAuthScopeGroupRequest{DisplayName: "x"})
+
+	assert.Equal(t, errScopeGroupNotFound, err)
+}
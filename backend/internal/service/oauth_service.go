@@ -6,14 +6,18 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 	"github.com/smilemakc/auth-gateway/internal/utils"
 )
 
@@ -32,8 +36,25 @@ type OAuthService struct {
 	jitProvisioning      bool // Enable Just-In-Time user provisioning
 	appOAuthProviderRepo AppOAuthProviderStore
 	appRepo              ApplicationStore
+	providerTokenKey     string // Encrypts stored provider tokens; empty disables storing them
+	emailCollision       string // What to do when the provider email belongs to an unlinked account
+	signupPolicy         SignupEnforcer
+	oidcDiscovery        oidcDiscoveryCache
+	// disabledProviders are switched off for every application; replaced on config reloads
+	disabledProviders atomic.Pointer[map[models.OAuthProvider]struct{}]
 }
 
+// providerTokenRefreshLeeway refreshes provider tokens slightly before they expire
+// so the token handed out stays usable for the request it was fetched for
+const providerTokenRefreshLeeway = time.Minute
+
+var (
+	errProviderTokenUnavailable = models.NewAppError(404, "No provider token available. Sign in with the provider again.")
+	errOAuthEmailInUse          = models.NewAppError(409, "An account with this email already exists. Sign in to it with your password instead.")
+	errInvalidOAuthLinkToken    = models.NewAppError(401, "Invalid or expired link token")
+	errOAuthProviderDisabled    = models.NewAppError(403, "Sign-in with this provider is temporarily disabled")
+)
+
 // OAuthProviderConfig holds OAuth provider configuration
 type OAuthProviderConfig struct {
 	ClientID     string
@@ -43,6 +64,17 @@ type OAuthProviderConfig struct {
 	TokenURL     string
 	UserInfoURL  string
 	Scopes       []string
+	// Sign in with Apple signs its client secret with the private key in ClientSecret
+	TeamID string
+	KeyID  string
+	// Microsoft Entra ID tenant the endpoints belong to
+	TenantID string
+	// Generic OpenID Connect providers: the issuer whose discovery document supplies the
+	// endpoints not set, the claims that describe the user, and whether the token endpoint
+	// only accepts client credentials in the Authorization header
+	Issuer            string
+	ClaimMapping      models.OIDCClaimMapping
+	ClientSecretBasic bool
 }
 
 // NewOAuthService creates a new OAuth service
@@ -59,6 +91,9 @@ func NewOAuthService(
 	appRepo ApplicationStore,
 	jitProvisioning bool,
 	loginAlertService *LoginAlertService,
+	providerTokenKey string,
+	emailCollision string,
+	signupPolicy SignupEnforcer,
 ) *OAuthService {
 	// Use default HTTP client if not provided
 	if httpClient == nil {
@@ -79,6 +114,10 @@ func NewOAuthService(
 		jitProvisioning:      jitProvisioning,
 		appOAuthProviderRepo: appOAuthProviderRepo,
 		appRepo:              appRepo,
+		providerTokenKey:     providerTokenKey,
+		emailCollision:       emailCollision,
+		signupPolicy:         signupPolicy,
+		oidcDiscovery:        oidcDiscoveryCache{documents: make(map[string]*oidcDiscoveryDocument)},
 	}
 
 	// Initialize providers
@@ -132,6 +171,46 @@ func (s *OAuthService) initializeProviders() {
 		Scopes:       []string{"user_profile", "user_media"},
 	}
 
+	// Sign in with Apple: the client secret is a JWT signed with the private key (.p8)
+	// and the user is described by the ID token, so there is no user info URL
+	s.providers[models.ProviderApple] = &OAuthProviderConfig{
+		ClientID:     os.Getenv("APPLE_CLIENT_ID"),
+		ClientSecret: strings.ReplaceAll(os.Getenv("APPLE_PRIVATE_KEY"), `\n`, "\n"),
+		CallbackURL:  os.Getenv("APPLE_CALLBACK_URL"),
+		AuthURL:      appleIssuer + "/auth/authorize",
+		TokenURL:     appleIssuer + "/auth/token",
+		Scopes:       []string{"name", "email"},
+		TeamID:       os.Getenv("APPLE_TEAM_ID"),
+		KeyID:        os.Getenv("APPLE_KEY_ID"),
+	}
+
+	// Microsoft Entra ID (work, school and personal Microsoft accounts)
+	tenantID := os.Getenv("MICROSOFT_TENANT_ID")
+	if tenantID == "" {
+		tenantID = "common"
+	}
+	s.providers[models.ProviderMicrosoft] = &OAuthProviderConfig{
+		ClientID:     os.Getenv("MICROSOFT_CLIENT_ID"),
+		ClientSecret: os.Getenv("MICROSOFT_CLIENT_SECRET"),
+		CallbackURL:  os.Getenv("MICROSOFT_CALLBACK_URL"),
+		AuthURL:      microsoftEndpoint(tenantID, "authorize"),
+		TokenURL:     microsoftEndpoint(tenantID, "token"),
+		UserInfoURL:  "https://graph.microsoft.com/oidc/userinfo",
+		Scopes:       []string{"openid", "profile", "email", "offline_access"},
+		TenantID:     tenantID,
+	}
+
+	// LinkedIn (Sign In with LinkedIn using OpenID Connect)
+	s.providers[models.ProviderLinkedIn] = &OAuthProviderConfig{
+		ClientID:     os.Getenv("LINKEDIN_CLIENT_ID"),
+		ClientSecret: os.Getenv("LINKEDIN_CLIENT_SECRET"),
+		CallbackURL:  os.Getenv("LINKEDIN_CALLBACK_URL"),
+		AuthURL:      "https://www.linkedin.com/oauth/v2/authorization",
+		TokenURL:     "https://www.linkedin.com/oauth/v2/accessToken",
+		UserInfoURL:  "https://api.linkedin.com/v2/userinfo",
+		Scopes:       []string{"openid", "profile", "email"},
+	}
+
 	// Telegram (using bot API)
 	s.providers[models.ProviderTelegram] = &OAuthProviderConfig{
 		ClientID:     os.Getenv("TELEGRAM_BOT_TOKEN"),
@@ -156,6 +235,11 @@ func (s *OAuthService) initializeProviders() {
 			Scopes:       splitScopes(scopes),
 		}
 	}
+
+	// Any OpenID Connect provider (Keycloak, Auth0, corporate IdP) found by its issuer
+	if config := oidcProviderFromEnv(); config != nil {
+		s.providers[models.ProviderOIDCGeneric] = config
+	}
 }
 
 // GenerateState generates a random state for OAuth flow
@@ -167,28 +251,78 @@ func (s *OAuthService) GenerateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// SetDisabledProviders switches off providers for every application, whatever their
+// configuration; an empty list enables them all again
+func (s *OAuthService) SetDisabledProviders(providers []string) {
+	disabled := make(map[models.OAuthProvider]struct{}, len(providers))
+	for _, provider := range providers {
+		disabled[models.OAuthProvider(provider)] = struct{}{}
+	}
+	s.disabledProviders.Store(&disabled)
+}
+
+// IsProviderDisabled reports whether a provider is switched off by SetDisabledProviders
+func (s *OAuthService) IsProviderDisabled(provider models.OAuthProvider) bool {
+	disabled := s.disabledProviders.Load()
+	if disabled == nil {
+		return false
+	}
+	_, ok := (*disabled)[provider]
+	return ok
+}
+
 // getProviderConfigForApp returns OAuth provider config for a specific application.
 // Falls back to env-based config if no app-specific config is found.
 func (s *OAuthService) getProviderConfigForApp(ctx context.Context, provider models.OAuthProvider, appID *uuid.UUID) (*OAuthProviderConfig, error) {
+	if s.IsProviderDisabled(provider) {
+		return nil, errOAuthProviderDisabled
+	}
 	if appID != nil && s.appOAuthProviderRepo != nil {
 		appProvider, err := s.appOAuthProviderRepo.GetByAppAndProvider(ctx, *appID, string(provider))
 		if err == nil && appProvider != nil && appProvider.IsActive {
-			scopes := appProvider.Scopes
-			if len(scopes) == 0 {
-				// Use default scopes from env-based config if app doesn't specify
-				if envConfig, ok := s.providers[provider]; ok {
-					scopes = envConfig.Scopes
-				}
-			}
-			return &OAuthProviderConfig{
+			config := &OAuthProviderConfig{
 				ClientID:     appProvider.ClientID,
 				ClientSecret: appProvider.ClientSecret,
 				CallbackURL:  appProvider.CallbackURL,
 				AuthURL:      appProvider.AuthURL,
 				TokenURL:     appProvider.TokenURL,
 				UserInfoURL:  appProvider.UserInfoURL,
-				Scopes:       scopes,
-			}, nil
+				Scopes:       appProvider.Scopes,
+				TeamID:       appProvider.TeamID,
+				KeyID:        appProvider.KeyID,
+				TenantID:     appProvider.TenantID,
+				Issuer:       appProvider.Issuer,
+				ClaimMapping: appProvider.ClaimMapping,
+			}
+			// A generic provider of the app has nothing in common with the one of the env
+			if provider == models.ProviderOIDCGeneric {
+				return s.resolveOIDCConfig(ctx, config)
+			}
+			if provider == models.ProviderMicrosoft && config.TenantID != "" {
+				if config.AuthURL == "" {
+					config.AuthURL = microsoftEndpoint(config.TenantID, "authorize")
+				}
+				if config.TokenURL == "" {
+					config.TokenURL = microsoftEndpoint(config.TenantID, "token")
+				}
+			}
+			// Known providers need only credentials; use the default scopes and URLs
+			// for whatever the app doesn't specify
+			if envConfig, ok := s.providers[provider]; ok {
+				if len(config.Scopes) == 0 {
+					config.Scopes = envConfig.Scopes
+				}
+				if config.AuthURL == "" {
+					config.AuthURL = envConfig.AuthURL
+				}
+				if config.TokenURL == "" {
+					config.TokenURL = envConfig.TokenURL
+				}
+				if config.UserInfoURL == "" {
+					config.UserInfoURL = envConfig.UserInfoURL
+				}
+			}
+			return config, nil
 		}
 	}
 
@@ -197,6 +331,9 @@ func (s *OAuthService) getProviderConfigForApp(ctx context.Context, provider mod
 	if !exists || config.ClientID == "" {
 		return nil, models.ErrInvalidProvider
 	}
+	if provider == models.ProviderOIDCGeneric {
+		return s.resolveOIDCConfig(ctx, config)
+	}
 	return config, nil
 }
 
@@ -224,6 +361,10 @@ func (s *OAuthService) GetAuthURL(ctx context.Context, provider models.OAuthProv
 		params.Add("access_type", "offline")
 		params.Add("prompt", "consent")
 	}
+	// Apple only returns the email and name when the callback is posted
+	if provider == models.ProviderApple && len(config.Scopes) > 0 {
+		params.Add("response_mode", "form_post")
+	}
 
 	return fmt.Sprintf("%s?%s", config.AuthURL, params.Encode()), nil
 }
@@ -235,26 +376,41 @@ func (s *OAuthService) ExchangeCode(ctx context.Context, provider models.OAuthPr
 		return nil, err
 	}
 
+	clientSecret, err := s.clientSecret(provider, config)
+	if err != nil {
+		return nil, err
+	}
+
 	data := url.Values{}
 	data.Set("client_id", config.ClientID)
-	data.Set("client_secret", config.ClientSecret)
+	data.Set("client_secret", clientSecret)
 	data.Set("code", code)
 	data.Set("redirect_uri", config.CallbackURL)
 	data.Set("grant_type", "authorization_code")
 
-	req, err := http.NewRequestWithContext(ctx, "POST", config.TokenURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Instagram uses form data
-	if provider == models.ProviderInstagram {
+	var req *http.Request
+	switch provider {
+	case models.ProviderInstagram, models.ProviderApple, models.ProviderMicrosoft, models.ProviderLinkedIn, models.ProviderOIDCGeneric:
+		// These providers only take form data
+		if config.ClientSecretBasic {
+			data.Del("client_secret")
+		}
+		req, err = http.NewRequestWithContext(ctx, "POST", config.TokenURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Body = io.NopCloser(nil)
-	} else {
-		req.Header.Set("Accept", "application/json")
+		if config.ClientSecretBasic {
+			req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(clientSecret))
+		}
+	default:
+		req, err = http.NewRequestWithContext(ctx, "POST", config.TokenURL, nil)
+		if err != nil {
+			return nil, err
+		}
 		req.URL.RawQuery = data.Encode()
 	}
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
@@ -281,30 +437,45 @@ func (s *OAuthService) GetUserInfo(ctx context.Context, provider models.OAuthPro
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", config.UserInfoURL, nil)
+	rawUserInfo, err := s.fetchUserInfoClaims(ctx, config.UserInfoURL, accessToken)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", err)
+	if provider == models.ProviderOIDCGeneric {
+		return mapOIDCClaims(rawUserInfo, config.ClaimMapping)
 	}
-	defer resp.Body.Close()
+	return s.parseUserInfo(provider, rawUserInfo)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("user info request failed with status: %d", resp.StatusCode)
+// providerUserInfo returns the user a token response was issued for. Apple describes the
+// user in the ID token only, generic OpenID Connect providers in the ID token and the user
+// info; other providers are asked with the access token.
+func (s *OAuthService) providerUserInfo(ctx context.Context, provider models.OAuthProvider, tokenResp *OAuthTokenResponse, appID *uuid.UUID) (*models.OAuthUserInfo, error) {
+	if provider != models.ProviderApple && provider != models.ProviderOIDCGeneric {
+		return s.GetUserInfo(ctx, provider, tokenResp.AccessToken, appID)
 	}
 
-	var rawUserInfo map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&rawUserInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode user info: %w", err)
+	config, err := s.getProviderConfigForApp(ctx, provider, appID)
+	if err != nil {
+		return nil, err
+	}
+	if provider == models.ProviderOIDCGeneric {
+		return s.oidcUserInfo(ctx, config, tokenResp)
+	}
+	claims, err := parseAppleIDToken(config, tokenResp.IDToken)
+	if err != nil {
+		return nil, err
 	}
+	return s.parseUserInfo(provider, claims)
+}
 
-	return s.parseUserInfo(provider, rawUserInfo)
+// clientSecret returns the client secret to authenticate to the token endpoint of a provider
+func (s *OAuthService) clientSecret(provider models.OAuthProvider, config *OAuthProviderConfig) (string, error) {
+	if provider == models.ProviderApple {
+		return appleClientSecret(config, time.Now())
+	}
+	return config.ClientSecret, nil
 }
 
 // parseUserInfo parses provider-specific user info into standard format
@@ -317,18 +488,23 @@ func (s *OAuthService) parseUserInfo(provider models.OAuthProvider, data map[str
 	case models.ProviderGoogle:
 		userInfo.ProviderUserID = getString(data, "id")
 		userInfo.Email = getString(data, "email")
+		userInfo.EmailVerified = getBool(data, "verified_email") || getBool(data, "email_verified")
 		userInfo.Name = getString(data, "name")
 		userInfo.ProfilePicture = getString(data, "picture")
 
 	case models.ProviderYandex:
 		userInfo.ProviderUserID = getString(data, "id")
 		userInfo.Email = getString(data, "default_email")
+		// Yandex only exposes confirmed addresses as the default email
+		userInfo.EmailVerified = userInfo.Email != ""
 		userInfo.Name = getString(data, "real_name")
 		userInfo.Username = getString(data, "login")
 
 	case models.ProviderGitHub:
 		userInfo.ProviderUserID = fmt.Sprintf("%v", data["id"])
 		userInfo.Email = getString(data, "email")
+		// GitHub only allows verified addresses as the public profile email
+		userInfo.EmailVerified = userInfo.Email != ""
 		userInfo.Name = getString(data, "name")
 		userInfo.Username = getString(data, "login")
 		userInfo.ProfilePicture = getString(data, "avatar_url")
@@ -347,6 +523,29 @@ func (s *OAuthService) parseUserInfo(provider models.OAuthProvider, data map[str
 		userInfo.Username = getString(data, "username")
 		userInfo.ProfilePicture = getString(data, "photo_url")
 
+	case models.ProviderApple:
+		// Claims of the ID token. Apple only sends the name to the callback of the first
+		// sign-in, so it isn't known here. Relay addresses of users who hide their email
+		// are verified too.
+		userInfo.ProviderUserID = getString(data, "sub")
+		userInfo.Email = getString(data, "email")
+		userInfo.EmailVerified = getBool(data, "email_verified") || getString(data, "email_verified") == "true"
+
+	case models.ProviderMicrosoft:
+		userInfo.ProviderUserID = getString(data, "sub")
+		userInfo.Email = getString(data, "email")
+		// Entra ID lets tenant admins set any email on their accounts without verifying it,
+		// so it must never be used to match an existing account
+		userInfo.EmailVerified = false
+		userInfo.Name = getString(data, "name")
+
+	case models.ProviderLinkedIn:
+		userInfo.ProviderUserID = getString(data, "sub")
+		userInfo.Email = getString(data, "email")
+		userInfo.EmailVerified = getBool(data, "email_verified")
+		userInfo.Name = getString(data, "name")
+		userInfo.ProfilePicture = getString(data, "picture")
+
 	case models.ProviderOneC:
 		// 1C OAuth userinfo response parsing
 		// Fields may vary depending on 1C system configuration
@@ -358,6 +557,7 @@ func (s *OAuthService) parseUserInfo(provider models.OAuthProvider, data map[str
 			userInfo.ProviderUserID = getString(data, "user_id")
 		}
 		userInfo.Email = getString(data, "email")
+		userInfo.EmailVerified = getBool(data, "email_verified")
 		userInfo.Name = getString(data, "name")
 		if userInfo.Name == "" {
 			// Try to compose name from parts
@@ -392,7 +592,7 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider models.OAuth
 	}
 
 	// Get user info
-	userInfo, err := s.GetUserInfo(ctx, provider, tokenResp.AccessToken, appID)
+	userInfo, err := s.providerUserInfo(ctx, provider, tokenResp, appID)
 	if err != nil {
 		return nil, err
 	}
@@ -402,50 +602,89 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider models.OAuth
 	isNewUser := false
 
 	if oauthAccount == nil {
-		// Check if JIT provisioning is enabled
-		if !s.jitProvisioning {
-			return nil, models.NewAppError(403, "User not found. Automatic user creation is disabled.")
-		}
-
-		// OAuth account doesn't exist, create new user (JIT provisioning)
-		user, err := s.createUserFromOAuth(ctx, userInfo)
+		// The provider email may belong to an account that is not linked to the provider account yet
+		existingUser, err := s.findUserByProviderEmail(ctx, userInfo)
 		if err != nil {
 			return nil, err
 		}
 
-		// Create OAuth account link
-		oauthAccount = &models.OAuthAccount{
-			ID:             uuid.New(),
-			UserID:         user.ID,
-			Provider:       string(provider),
-			ProviderUserID: userInfo.ProviderUserID,
-			AccessToken:    tokenResp.AccessToken,
-			RefreshToken:   tokenResp.RefreshToken,
-		}
+		if existingUser != nil {
+			switch s.emailCollision {
+			case models.OAuthEmailCollisionAutoLink:
+				// Both sides must have verified the address, otherwise whoever registered it
+				// first (locally or at the provider) could take over the other account
+				if !userInfo.EmailVerified || !existingUser.EmailVerified {
+					return nil, errOAuthEmailInUse
+				}
+				oauthAccount, err = s.linkOAuthAccount(ctx, existingUser.ID, provider, userInfo, tokenResp)
+				if err != nil {
+					return nil, err
+				}
+				s.logAudit(ctx, &existingUser.ID, models.ActionOAuthLink, models.StatusSuccess, ipAddress, userAgent, map[string]interface{}{
+					"provider": string(provider),
+					"method":   models.OAuthEmailCollisionAutoLink,
+				})
+
+			case models.OAuthEmailCollisionConfirmPassword:
+				if existingUser.PasswordHash == "" {
+					return nil, errOAuthEmailInUse
+				}
+				// Provider tokens are not kept for a pending link; they are stored on the next sign-in
+				linkToken, err := s.jwtService.GenerateOAuthLinkToken(existingUser, string(provider), userInfo.ProviderUserID, appID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to generate link token: %w", err)
+				}
+				return &models.OAuthLoginResponse{
+					LinkRequired: true,
+					LinkToken:    linkToken,
+				}, nil
 
-		if tokenResp.ExpiresIn > 0 {
-			expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-			oauthAccount.TokenExpiresAt = &expiresAt
-		}
+			default:
+				return nil, errOAuthEmailInUse
+			}
+		} else {
+			// Check if JIT provisioning is enabled
+			if !s.jitProvisioning {
+				return nil, models.NewAppError(403, "User not found. Automatic user creation is disabled.")
+			}
 
-		// Store profile data as JSON
-		profileData, _ := json.Marshal(userInfo)
-		oauthAccount.ProfileData = profileData
+			if s.signupPolicy != nil {
+				// An address the provider does not vouch for cannot match the allowlist
+				check := s.signupPolicy.CheckSignup
+				if !userInfo.EmailVerified {
+					check = s.signupPolicy.CheckUnverifiedSignup
+				}
+				if err := check(ctx, userInfo.Email); err != nil {
+					s.logAudit(ctx, nil, models.ActionSignUp, models.StatusFailed, ipAddress, userAgent, map[string]interface{}{
+						"provider": string(provider),
+						"reason":   "signup_restricted",
+					})
+					return nil, err
+				}
+			}
 
-		if err := s.oauthRepo.CreateOAuthAccount(ctx, oauthAccount); err != nil {
-			return nil, err
-		}
+			// OAuth account doesn't exist, create new user (JIT provisioning)
+			user, err := s.createUserFromOAuth(ctx, userInfo)
+			if err != nil {
+				return nil, err
+			}
+
+			oauthAccount, err = s.linkOAuthAccount(ctx, user.ID, provider, userInfo, tokenResp)
+			if err != nil {
+				return nil, err
+			}
+
+			// Only an address the provider vouches for may join an organization by domain
+			if s.signupPolicy != nil && userInfo.EmailVerified {
+				s.signupPolicy.AutoJoin(ctx, user)
+			}
 
-		isNewUser = true
+			isNewUser = true
+		}
 	} else {
 		// Update existing OAuth account
-		oauthAccount.AccessToken = tokenResp.AccessToken
-		if tokenResp.RefreshToken != "" {
-			oauthAccount.RefreshToken = tokenResp.RefreshToken
-		}
-		if tokenResp.ExpiresIn > 0 {
-			expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-			oauthAccount.TokenExpiresAt = &expiresAt
+		if err := s.setProviderTokens(oauthAccount, tokenResp); err != nil {
+			return nil, err
 		}
 
 		profileData, _ := json.Marshal(userInfo)
@@ -462,6 +701,122 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider models.OAuth
 		return nil, err
 	}
 
+	return s.completeLogin(ctx, user, appID, ipAddress, userAgent, isNewUser)
+}
+
+// ConfirmLink links a provider account to an existing account after the user confirmed
+// its password (and 2FA code, if enabled) and signs the user in
+func (s *OAuthService) ConfirmLink(ctx context.Context, req *models.ConfirmOAuthLinkRequest, ipAddress, userAgent string) (*models.OAuthLoginResponse, error) {
+	claims, err := s.jwtService.ValidateOAuthLinkToken(req.LinkToken)
+	if err != nil {
+		return nil, errInvalidOAuthLinkToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID, utils.Ptr(true))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errInvalidOAuthLinkToken
+		}
+		return nil, err
+	}
+	// Signing out everywhere or changing the password invalidates pending links
+	if user.TokenVersion != claims.TokenVersion {
+		return nil, errInvalidOAuthLinkToken
+	}
+
+	details := map[string]interface{}{
+		"provider": claims.OAuthProvider,
+		"method":   models.OAuthEmailCollisionConfirmPassword,
+	}
+
+	if user.PasswordHash == "" || utils.CheckPassword(user.PasswordHash, req.Password) != nil {
+		details["reason"] = "invalid_password"
+		s.logAudit(ctx, &user.ID, models.ActionOAuthLinkFailed, models.StatusFailed, ipAddress, userAgent, details)
+		return nil, models.ErrInvalidCredentials
+	}
+
+	// Linking signs the user in, so it must not get around two-factor authentication.
+	// Security keys cannot be used here, so accounts protected only by them cannot link.
+	if user.WebAuthnEnabled && !user.TOTPEnabled {
+		details["reason"] = "webauthn_only"
+		s.logAudit(ctx, &user.ID, models.ActionOAuthLinkFailed, models.StatusFailed, ipAddress, userAgent, details)
+		return nil, models.NewAppError(403, "Sign in with your password and security key, then link the provider from your account")
+	}
+	if user.TOTPEnabled {
+		if user.TOTPSecret == nil || req.TOTPCode == "" || !totp.Validate(req.TOTPCode, *user.TOTPSecret) {
+			details["reason"] = "invalid_2fa_code"
+			s.logAudit(ctx, &user.ID, models.ActionOAuthLinkFailed, models.StatusFailed, ipAddress, userAgent, details)
+			return nil, models.NewAppError(401, "Invalid two-factor authentication code")
+		}
+	}
+
+	existing, err := s.oauthRepo.GetOAuthAccount(ctx, claims.OAuthProvider, claims.OAuthProviderUserID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if existing != nil && existing.UserID != user.ID {
+		return nil, models.NewAppError(409, "This provider account is already linked to another account")
+	}
+
+	if existing == nil {
+		account := &models.OAuthAccount{
+			ID:             uuid.New(),
+			UserID:         user.ID,
+			Provider:       claims.OAuthProvider,
+			ProviderUserID: claims.OAuthProviderUserID,
+		}
+		if err := s.oauthRepo.CreateOAuthAccount(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logAudit(ctx, &user.ID, models.ActionOAuthLink, models.StatusSuccess, ipAddress, userAgent, details)
+
+	return s.completeLogin(ctx, user, claims.ApplicationID, ipAddress, userAgent, false)
+}
+
+// findUserByProviderEmail returns the account the provider email belongs to, if any
+func (s *OAuthService) findUserByProviderEmail(ctx context.Context, userInfo *models.OAuthUserInfo) (*models.User, error) {
+	if userInfo.Email == "" {
+		return nil, nil
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, utils.NormalizeEmail(userInfo.Email), nil)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkOAuthAccount links a provider account to a user
+func (s *OAuthService) linkOAuthAccount(ctx context.Context, userID uuid.UUID, provider models.OAuthProvider, userInfo *models.OAuthUserInfo, tokenResp *OAuthTokenResponse) (*models.OAuthAccount, error) {
+	oauthAccount := &models.OAuthAccount{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Provider:       string(provider),
+		ProviderUserID: userInfo.ProviderUserID,
+	}
+
+	if err := s.setProviderTokens(oauthAccount, tokenResp); err != nil {
+		return nil, err
+	}
+
+	// Store profile data as JSON
+	profileData, _ := json.Marshal(userInfo)
+	oauthAccount.ProfileData = profileData
+
+	if err := s.oauthRepo.CreateOAuthAccount(ctx, oauthAccount); err != nil {
+		return nil, err
+	}
+
+	return oauthAccount, nil
+}
+
+// completeLogin issues tokens and creates a session for a user signed in through a provider
+func (s *OAuthService) completeLogin(ctx context.Context, user *models.User, appID *uuid.UUID, ipAddress, userAgent string, isNewUser bool) (*models.OAuthLoginResponse, error) {
 	// Generate JWT tokens
 	accessToken, err := s.jwtService.GenerateAccessToken(user, appID)
 	if err != nil {
@@ -527,6 +882,11 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider models.OAuth
 		}()
 	}
 
+	if isNewUser {
+		metrics.RecordSignup("oauth")
+	}
+	metrics.RecordLogin("oauth", true)
+
 	return &models.OAuthLoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -535,6 +895,158 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider models.OAuth
 	}, nil
 }
 
+// GetProviderToken returns a provider access token of the user so applications can call the
+// provider's API on their behalf. Expired tokens are refreshed with the stored refresh token.
+func (s *OAuthService) GetProviderToken(ctx context.Context, userID uuid.UUID, provider models.OAuthProvider, appID *uuid.UUID) (*models.ProviderTokenResponse, error) {
+	if s.providerTokenKey == "" {
+		return nil, models.NewAppError(404, "Provider token storage is disabled")
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var account *models.OAuthAccount
+	for _, a := range accounts {
+		if a.Provider == string(provider) {
+			account = a
+			break
+		}
+	}
+	if account == nil || account.AccessToken == "" {
+		return nil, errProviderTokenUnavailable
+	}
+
+	if account.TokenExpiresAt != nil && time.Now().Add(providerTokenRefreshLeeway).After(*account.TokenExpiresAt) {
+		if err := s.refreshProviderToken(ctx, provider, account, appID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tokens stored before they were encrypted cannot be decrypted and have to be obtained again
+	accessToken, err := utils.DecryptAESGCM(account.AccessToken, s.providerTokenKey)
+	if err != nil {
+		return nil, errProviderTokenUnavailable
+	}
+
+	return &models.ProviderTokenResponse{
+		Provider:    account.Provider,
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   account.TokenExpiresAt,
+	}, nil
+}
+
+// refreshProviderToken exchanges the stored refresh token of an OAuth account for a new access token
+func (s *OAuthService) refreshProviderToken(ctx context.Context, provider models.OAuthProvider, account *models.OAuthAccount, appID *uuid.UUID) error {
+	if account.RefreshToken == "" {
+		return errProviderTokenUnavailable
+	}
+	refreshToken, err := utils.DecryptAESGCM(account.RefreshToken, s.providerTokenKey)
+	if err != nil {
+		return errProviderTokenUnavailable
+	}
+
+	config, err := s.getProviderConfigForApp(ctx, provider, appID)
+	if err != nil {
+		return err
+	}
+
+	clientSecret, err := s.clientSecret(provider, config)
+	if err != nil {
+		return err
+	}
+
+	data := url.Values{}
+	data.Set("client_id", config.ClientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+	if config.ClientSecretBasic {
+		data.Del("client_secret")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", config.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if config.ClientSecretBasic {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(clientSecret))
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to refresh provider token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The user revoked access or the refresh token expired
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return errProviderTokenUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("provider token refresh failed with status: %d", resp.StatusCode)
+	}
+
+	var tokenResp OAuthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return errProviderTokenUnavailable
+	}
+
+	if err := s.setProviderTokens(account, &tokenResp); err != nil {
+		return err
+	}
+
+	return s.oauthRepo.UpdateOAuthAccount(ctx, account)
+}
+
+// setProviderTokens stores the provider tokens on an OAuth account encrypted,
+// or clears them when storing provider tokens is disabled
+func (s *OAuthService) setProviderTokens(account *models.OAuthAccount, tokenResp *OAuthTokenResponse) error {
+	if s.providerTokenKey == "" {
+		account.AccessToken = ""
+		account.RefreshToken = ""
+		account.TokenExpiresAt = nil
+		return nil
+	}
+
+	accessToken, err := utils.EncryptAESGCM(tokenResp.AccessToken, s.providerTokenKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt provider token: %w", err)
+	}
+	account.AccessToken = accessToken
+
+	// Providers such as Google only issue a refresh token on the first consent
+	if tokenResp.RefreshToken != "" {
+		refreshToken, err := utils.EncryptAESGCM(tokenResp.RefreshToken, s.providerTokenKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt provider token: %w", err)
+		}
+		account.RefreshToken = refreshToken
+	}
+
+	account.TokenExpiresAt = nil
+	if tokenResp.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+		account.TokenExpiresAt = &expiresAt
+	}
+
+	return nil
+}
+
+func (s *OAuthService) logAudit(ctx context.Context, userID *uuid.UUID, action models.AuditAction, status models.AuditStatus, ip, userAgent string, details map[string]interface{}) {
+	detailsJSON, _ := json.Marshal(details)
+	auditLog := models.CreateAuditLog(userID, action, status, ip, userAgent, detailsJSON)
+	auditLog.TraceID = tracing.TraceID(ctx)
+	_ = s.auditRepo.Create(ctx, auditLog)
+}
+
 // createUserFromOAuth creates a new user from OAuth data
 func (s *OAuthService) createUserFromOAuth(ctx context.Context, userInfo *models.OAuthUserInfo) (*models.User, error) {
 	email := userInfo.Email
@@ -602,6 +1114,12 @@ type OAuthTokenResponse struct {
 	ExpiresIn    int    `json:"expires_in"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// microsoftEndpoint returns an OAuth endpoint of a Microsoft Entra ID tenant
+func microsoftEndpoint(tenantID, endpoint string) string {
+	return fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/%s", url.PathEscape(tenantID), endpoint)
 }
 
 func getString(data map[string]interface{}, key string) string {
@@ -613,6 +1131,15 @@ func getString(data map[string]interface{}, key string) string {
 	return ""
 }
 
+func getBool(data map[string]interface{}, key string) bool {
+	if val, ok := data[key]; ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
 func joinScopes(scopes []string) string {
 	result := ""
 	for i, scope := range scopes {
@@ -11,7 +11,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -142,6 +145,9 @@ func setupOAuthService() (
 		mJWT, nil, mHTTP, mAppOAuth, mApp,
 		true, // jitProvisioning enabled
 		nil,  // loginAlertService
+		"",   // providerTokenKey
+		"",   // emailCollision
+		nil,  // signupPolicy
 	)
 
 	// Manually configure a test provider to bypass env vars
@@ -224,6 +230,25 @@ func TestOAuthService_GetAuthURL(t *testing.T) {
 		// Telegram does not use traditional OAuth params
 		assert.NotContains(t, authURL, "response_type")
 	})
+
+	t.Run("ShouldReturnError_WhenProviderDisabled", func(t *testing.T) {
+		// Arrange
+		svc, _, _, _, _, _, _, _ := setupOAuthService()
+		ctx := context.Background()
+		svc.SetDisabledProviders([]string{"google"})
+
+		// Act
+		authURL, err := svc.GetAuthURL(ctx, models.ProviderGoogle, "test-state", nil)
+
+		// Assert
+		assert.ErrorIs(t, err, errOAuthProviderDisabled)
+		assert.Empty(t, authURL)
+
+		// Re-enabled by a later reload
+		svc.SetDisabledProviders(nil)
+		_, err = svc.GetAuthURL(ctx, models.ProviderGoogle, "test-state", nil)
+		assert.NoError(t, err)
+	})
 }
 
 // --- ExchangeCode Tests ---
@@ -363,9 +388,10 @@ func TestOAuthService_HandleCallback_ShouldCreateNewUser_WhenNoExistingOAuthAcco
 	mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
 		assert.Equal(t, "google", account.Provider)
 		assert.Equal(t, "google-uid-123", account.ProviderUserID)
-		assert.Equal(t, "oauth-access", account.AccessToken)
-		assert.Equal(t, "oauth-refresh", account.RefreshToken)
-		assert.NotNil(t, account.TokenExpiresAt)
+		// Provider tokens are not stored unless enabled
+		assert.Empty(t, account.AccessToken)
+		assert.Empty(t, account.RefreshToken)
+		assert.Nil(t, account.TokenExpiresAt)
 		return nil
 	}
 
@@ -409,6 +435,7 @@ func TestOAuthService_HandleCallback_ShouldCreateNewUser_WhenNoExistingOAuthAcco
 func TestOAuthService_HandleCallback_ShouldLinkExistingUser_WhenOAuthAccountExists(t *testing.T) {
 	// Arrange
 	svc, mUser, mOAuth, mToken, _, _, mJWT, mHTTP := setupOAuthService()
+	svc.providerTokenKey = testProviderTokenKey
 	ctx := context.Background()
 	userID := uuid.New()
 	oauthAccountID := uuid.New()
@@ -445,8 +472,8 @@ func TestOAuthService_HandleCallback_ShouldLinkExistingUser_WhenOAuthAccountExis
 	// Update OAuth account with new tokens
 	mOAuth.UpdateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
 		assert.Equal(t, oauthAccountID, account.ID)
-		assert.Equal(t, "new-oauth-access", account.AccessToken)
-		assert.Equal(t, "new-oauth-refresh", account.RefreshToken)
+		assert.Equal(t, "new-oauth-access", decryptProviderToken(t, account.AccessToken))
+		assert.Equal(t, "new-oauth-refresh", decryptProviderToken(t, account.RefreshToken))
 		assert.NotNil(t, account.TokenExpiresAt)
 		return nil
 	}
@@ -894,6 +921,7 @@ func TestOAuthService_HandleCallback_ShouldReturnError_WhenSaveRefreshTokenFails
 func TestOAuthService_HandleCallback_ShouldKeepExistingRefreshToken_WhenNewTokenEmpty(t *testing.T) {
 	// Arrange: When provider returns empty refresh token, keep old one
 	svc, mUser, mOAuth, mToken, _, _, mJWT, mHTTP := setupOAuthService()
+	svc.providerTokenKey = testProviderTokenKey
 	ctx := context.Background()
 	userID := uuid.New()
 
@@ -916,12 +944,12 @@ func TestOAuthService_HandleCallback_ShouldKeepExistingRefreshToken_WhenNewToken
 	mOAuth.GetOAuthAccountFunc = func(ctx context.Context, provider, providerUserID string) (*models.OAuthAccount, error) {
 		return &models.OAuthAccount{
 			ID: uuid.New(), UserID: userID, Provider: "google", ProviderUserID: "google-uid-keeprt",
-			RefreshToken: "old-refresh-to-keep",
+			RefreshToken: encryptProviderToken(t, "old-refresh-to-keep"),
 		}, nil
 	}
 	mOAuth.UpdateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
-		assert.Equal(t, "new-access", account.AccessToken)
-		assert.Equal(t, "old-refresh-to-keep", account.RefreshToken, "should keep old refresh token when new is empty")
+		assert.Equal(t, "new-access", decryptProviderToken(t, account.AccessToken))
+		assert.Equal(t, "old-refresh-to-keep", decryptProviderToken(t, account.RefreshToken), "should keep old refresh token when new is empty")
 		return nil
 	}
 	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
@@ -1173,6 +1201,160 @@ func TestOAuthService_ParseUserInfo_ShouldHandleMissingFields(t *testing.T) {
 	assert.Equal(t, "google", info.Provider)
 }
 
+func TestOAuthService_ParseUserInfo_ShouldParseMicrosoftResponse(t *testing.T) {
+	// Arrange
+	svc, _, _, _, _, _, _, _ := setupOAuthService()
+	data := map[string]interface{}{
+		"sub":         "AAAAAAAAAAAAAAAAAAAAAIkzqFVrSaSaFHy782bbtaQ",
+		"name":        "Megan Bowen",
+		"given_name":  "Megan",
+		"family_name": "Bowen",
+		"email":       "meganb@contoso.com",
+	}
+
+	// Act
+	info, err := svc.parseUserInfo(models.ProviderMicrosoft, data)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAAAAAAAAAAAAAAAAAIkzqFVrSaSaFHy782bbtaQ", info.ProviderUserID)
+	assert.Equal(t, "meganb@contoso.com", info.Email)
+	// Entra ID does not verify emails, so they never match existing accounts
+	assert.False(t, info.EmailVerified)
+	assert.Equal(t, "Megan Bowen", info.Name)
+	assert.Equal(t, "microsoft", info.Provider)
+}
+
+func TestOAuthService_ParseUserInfo_ShouldParseLinkedInResponse(t *testing.T) {
+	// Arrange
+	svc, _, _, _, _, _, _, _ := setupOAuthService()
+	data := map[string]interface{}{
+		"sub":            "782bbtaQ",
+		"name":           "John Doe",
+		"given_name":     "John",
+		"family_name":    "Doe",
+		"picture":        "https://media.licdn.com/photo.jpg",
+		"email":          "doe@email.com",
+		"email_verified": true,
+		"locale":         "en-US",
+	}
+
+	// Act
+	info, err := svc.parseUserInfo(models.ProviderLinkedIn, data)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "782bbtaQ", info.ProviderUserID)
+	assert.Equal(t, "doe@email.com", info.Email)
+	assert.True(t, info.EmailVerified)
+	assert.Equal(t, "John Doe", info.Name)
+	assert.Equal(t, "https://media.licdn.com/photo.jpg", info.ProfilePicture)
+	assert.Equal(t, "linkedin", info.Provider)
+}
+
+// --- getProviderConfigForApp Tests ---
+
+func TestOAuthService_GetProviderConfigForApp(t *testing.T) {
+	ctx := context.Background()
+	appID := uuid.New()
+
+	setup := func(appProvider *models.ApplicationOAuthProvider) *OAuthService {
+		svc, _, _, _, _, _, _, _ := setupOAuthService()
+		svc.initializeProviders()
+		svc.appOAuthProviderRepo = &oauthMockAppOAuthProviderStore{
+			GetByAppAndProviderFunc: func(ctx context.Context, id uuid.UUID, provider string) (*models.ApplicationOAuthProvider, error) {
+				if provider != appProvider.Provider {
+					return nil, errors.New("not found")
+				}
+				return appProvider, nil
+			},
+		}
+		return svc
+	}
+
+	t.Run("ShouldUseDefaultURLs_WhenAppOnlySetsCredentials", func(t *testing.T) {
+		svc := setup(&models.ApplicationOAuthProvider{
+			Provider:     "linkedin",
+			ClientID:     "app-client-id",
+			ClientSecret: "app-client-secret",
+			CallbackURL:  "https://app.example.com/callback",
+			IsActive:     true,
+		})
+
+		config, err := svc.getProviderConfigForApp(ctx, models.ProviderLinkedIn, &appID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "app-client-id", config.ClientID)
+		assert.Equal(t, "https://www.linkedin.com/oauth/v2/authorization", config.AuthURL)
+		assert.Equal(t, "https://www.linkedin.com/oauth/v2/accessToken", config.TokenURL)
+		assert.Equal(t, "https://api.linkedin.com/v2/userinfo", config.UserInfoURL)
+		assert.Equal(t, []string{"openid", "profile", "email"}, config.Scopes)
+	})
+
+	t.Run("ShouldUseAppTenant_WhenMicrosoft", func(t *testing.T) {
+		svc := setup(&models.ApplicationOAuthProvider{
+			Provider:     "microsoft",
+			ClientID:     "app-client-id",
+			ClientSecret: "app-client-secret",
+			CallbackURL:  "https://app.example.com/callback",
+			TenantID:     "contoso.onmicrosoft.com",
+			IsActive:     true,
+		})
+
+		config, err := svc.getProviderConfigForApp(ctx, models.ProviderMicrosoft, &appID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.microsoftonline.com/contoso.onmicrosoft.com/oauth2/v2.0/authorize", config.AuthURL)
+		assert.Equal(t, "https://login.microsoftonline.com/contoso.onmicrosoft.com/oauth2/v2.0/token", config.TokenURL)
+		assert.Equal(t, "https://graph.microsoft.com/oidc/userinfo", config.UserInfoURL)
+	})
+
+	t.Run("ShouldKeepAppURLs_WhenSet", func(t *testing.T) {
+		svc := setup(&models.ApplicationOAuthProvider{
+			Provider:     "microsoft",
+			ClientID:     "app-client-id",
+			ClientSecret: "app-client-secret",
+			AuthURL:      "https://login.example.com/authorize",
+			TenantID:     "contoso.onmicrosoft.com",
+			IsActive:     true,
+		})
+
+		config, err := svc.getProviderConfigForApp(ctx, models.ProviderMicrosoft, &appID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.example.com/authorize", config.AuthURL)
+		assert.Equal(t, "https://login.microsoftonline.com/contoso.onmicrosoft.com/oauth2/v2.0/token", config.TokenURL)
+	})
+}
+
+func TestOAuthService_ExchangeCode_ShouldPostForm_WhenLinkedIn(t *testing.T) {
+	// Arrange
+	svc, _, _, _, _, _, _, mHTTP := setupOAuthService()
+	ctx := context.Background()
+	svc.providers[models.ProviderLinkedIn] = &OAuthProviderConfig{
+		ClientID:     "linkedin-client-id",
+		ClientSecret: "linkedin-client-secret",
+		CallbackURL:  "http://localhost/callback",
+		TokenURL:     "https://www.linkedin.com/oauth/v2/accessToken",
+	}
+
+	mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+		assert.Empty(t, req.URL.RawQuery)
+		require.NoError(t, req.ParseForm())
+		assert.Equal(t, "auth-code-123", req.PostForm.Get("code"))
+		assert.Equal(t, "linkedin-client-secret", req.PostForm.Get("client_secret"))
+		return newJSONResponse(http.StatusOK, OAuthTokenResponse{AccessToken: "linkedin-access-token", ExpiresIn: 3600}), nil
+	}
+
+	// Act
+	result, err := svc.ExchangeCode(ctx, models.ProviderLinkedIn, "auth-code-123", nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "linkedin-access-token", result.AccessToken)
+}
+
 // --- createUserFromOAuth Tests ---
 
 func TestOAuthService_CreateUserFromOAuth_ShouldGeneratePlaceholderEmail_WhenNoEmail(t *testing.T) {
@@ -1552,3 +1734,378 @@ func TestSplitScopes_ShouldHandleEmpty(t *testing.T) {
 func TestSplitScopes_ShouldHandleSingle(t *testing.T) {
 	assert.Equal(t, []string{"openid"}, splitScopes("openid"))
 }
+
+// --- GetProviderToken Tests ---
+
+const testProviderTokenKey = "0123456789abcdef0123456789abcdef"
+
+func encryptProviderToken(t *testing.T, token string) string {
+	t.Helper()
+	encrypted, err := utils.EncryptAESGCM(token, testProviderTokenKey)
+	require.NoError(t, err)
+	return encrypted
+}
+
+func decryptProviderToken(t *testing.T, encrypted string) string {
+	t.Helper()
+	token, err := utils.DecryptAESGCM(encrypted, testProviderTokenKey)
+	require.NoError(t, err)
+	return token
+}
+
+func TestOAuthService_GetProviderToken(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("ShouldReturnStoredToken_WhenNotExpired", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, mHTTP := setupOAuthService()
+		svc.providerTokenKey = testProviderTokenKey
+		expiresAt := time.Now().Add(time.Hour)
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			assert.Equal(t, userID, id)
+			return []*models.OAuthAccount{
+				{Provider: "github", AccessToken: encryptProviderToken(t, "github-access")},
+				{Provider: "google", AccessToken: encryptProviderToken(t, "google-access"), TokenExpiresAt: &expiresAt},
+			}, nil
+		}
+		mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+			t.Fatal("token must not be refreshed")
+			return nil, nil
+		}
+
+		token, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "google", token.Provider)
+		assert.Equal(t, "google-access", token.AccessToken)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, &expiresAt, token.ExpiresAt)
+	})
+
+	t.Run("ShouldRefreshToken_WhenExpired", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, mHTTP := setupOAuthService()
+		svc.providerTokenKey = testProviderTokenKey
+		expiredAt := time.Now().Add(-time.Minute)
+		account := &models.OAuthAccount{
+			Provider:       "google",
+			AccessToken:    encryptProviderToken(t, "old-access"),
+			RefreshToken:   encryptProviderToken(t, "google-refresh"),
+			TokenExpiresAt: &expiredAt,
+		}
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			return []*models.OAuthAccount{account}, nil
+		}
+		mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://oauth2.googleapis.com/token", req.URL.String())
+			require.NoError(t, req.ParseForm())
+			assert.Equal(t, "refresh_token", req.PostForm.Get("grant_type"))
+			assert.Equal(t, "google-refresh", req.PostForm.Get("refresh_token"))
+			assert.Equal(t, "test-client-id", req.PostForm.Get("client_id"))
+			return newJSONResponse(http.StatusOK, OAuthTokenResponse{AccessToken: "new-access", ExpiresIn: 3600}), nil
+		}
+		updated := false
+		mOAuth.UpdateOAuthAccountFunc = func(ctx context.Context, a *models.OAuthAccount) error {
+			updated = true
+			assert.Equal(t, "new-access", decryptProviderToken(t, a.AccessToken))
+			// The refresh token is kept when the provider does not rotate it
+			assert.Equal(t, "google-refresh", decryptProviderToken(t, a.RefreshToken))
+			assert.True(t, a.TokenExpiresAt.After(time.Now()))
+			return nil
+		}
+
+		token, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+
+		require.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, "new-access", token.AccessToken)
+	})
+
+	t.Run("ShouldReturnNotFound_WhenExpiredWithoutRefreshToken", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, _ := setupOAuthService()
+		svc.providerTokenKey = testProviderTokenKey
+		expiredAt := time.Now().Add(-time.Minute)
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			return []*models.OAuthAccount{
+				{Provider: "google", AccessToken: encryptProviderToken(t, "old-access"), TokenExpiresAt: &expiredAt},
+			}, nil
+		}
+
+		_, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+	})
+
+	t.Run("ShouldReturnNotFound_WhenRefreshRejected", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, mHTTP := setupOAuthService()
+		svc.providerTokenKey = testProviderTokenKey
+		expiredAt := time.Now().Add(-time.Minute)
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			return []*models.OAuthAccount{{
+				Provider:       "google",
+				AccessToken:    encryptProviderToken(t, "old-access"),
+				RefreshToken:   encryptProviderToken(t, "revoked-refresh"),
+				TokenExpiresAt: &expiredAt,
+			}}, nil
+		}
+		mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return newJSONResponse(http.StatusBadRequest, map[string]string{"error": "invalid_grant"}), nil
+		}
+
+		_, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+	})
+
+	t.Run("ShouldReturnNotFound_WhenNoLinkedAccountOrPlainTextToken", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, _ := setupOAuthService()
+		svc.providerTokenKey = testProviderTokenKey
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			return []*models.OAuthAccount{{Provider: "github", AccessToken: "plain-text-token"}}, nil
+		}
+
+		var appErr *models.AppError
+		_, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+
+		_, err = svc.GetProviderToken(context.Background(), userID, models.ProviderGitHub, nil)
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+	})
+
+	t.Run("ShouldReturnNotFound_WhenStorageDisabled", func(t *testing.T) {
+		svc, _, mOAuth, _, _, _, _, _ := setupOAuthService()
+		mOAuth.GetByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.OAuthAccount, error) {
+			t.Fatal("accounts must not be loaded")
+			return nil, nil
+		}
+
+		_, err := svc.GetProviderToken(context.Background(), userID, models.ProviderGoogle, nil)
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+	})
+}
+
+// --- Email collision Tests ---
+
+// setupEmailCollision prepares a callback for a Google account whose email belongs to
+// an existing, unlinked local account
+func setupEmailCollision(t *testing.T, strategy string, providerVerified, localVerified bool) (*OAuthService, *mockOAuthStore, *mockUserStore, *mockTokenService, *models.User) {
+	t.Helper()
+	svc, mUser, mOAuth, mToken, _, _, mJWT, mHTTP := setupOAuthService()
+	svc.emailCollision = strategy
+
+	passwordHash, err := utils.HashPassword("LocalPass123!", 4)
+	require.NoError(t, err)
+	existing := &models.User{
+		ID:            uuid.New(),
+		Email:         "taken@example.com",
+		EmailVerified: localVerified,
+		PasswordHash:  passwordHash,
+		IsActive:      true,
+	}
+
+	callCount := 0
+	mHTTP.DoFunc = func(req *http.Request) (*http.Response, error) {
+		callCount++
+		if callCount == 1 {
+			return newJSONResponse(http.StatusOK, OAuthTokenResponse{AccessToken: "oauth-access"}), nil
+		}
+		return newJSONResponse(http.StatusOK, map[string]interface{}{
+			"id":             "google-uid-collision",
+			"email":          "Taken@Example.com",
+			"verified_email": providerVerified,
+		}), nil
+	}
+	mOAuth.GetOAuthAccountFunc = func(ctx context.Context, provider, providerUserID string) (*models.OAuthAccount, error) {
+		return nil, nil
+	}
+	mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		assert.Equal(t, "taken@example.com", email)
+		return existing, nil
+	}
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if id == existing.ID {
+			return existing, nil
+		}
+		return nil, models.ErrUserNotFound
+	}
+	mUser.CreateFunc = func(ctx context.Context, user *models.User) error {
+		t.Fatal("no user must be created for a taken email")
+		return nil
+	}
+	mJWT.GenerateAccessTokenFunc = func(user *models.User, appID ...*uuid.UUID) (string, error) { return "jwt-access", nil }
+	mJWT.GenerateRefreshTokenFunc = func(user *models.User, appID ...*uuid.UUID) (string, error) { return "jwt-refresh", nil }
+	mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
+
+	return svc, mOAuth, mUser, mJWT, existing
+}
+
+func TestOAuthService_HandleCallback_EmailCollision(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ShouldReject_ByDefault", func(t *testing.T) {
+		svc, _, _, _, _ := setupEmailCollision(t, "", true, true)
+
+		result, err := svc.HandleCallback(ctx, models.ProviderGoogle, "code", "1.2.3.4", "ua", nil)
+
+		assert.Nil(t, result)
+		assert.Equal(t, errOAuthEmailInUse, err)
+	})
+
+	t.Run("ShouldAutoLink_WhenBothSidesVerified", func(t *testing.T) {
+		svc, mOAuth, _, _, existing := setupEmailCollision(t, models.OAuthEmailCollisionAutoLink, true, true)
+		var linked *models.OAuthAccount
+		mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
+			linked = account
+			return nil
+		}
+
+		result, err := svc.HandleCallback(ctx, models.ProviderGoogle, "code", "1.2.3.4", "ua", nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, linked)
+		assert.Equal(t, existing.ID, linked.UserID)
+		assert.Equal(t, "google-uid-collision", linked.ProviderUserID)
+		assert.Equal(t, "jwt-access", result.AccessToken)
+		assert.False(t, result.IsNewUser)
+	})
+
+	t.Run("ShouldNotAutoLink_WhenProviderDidNotVerifyEmail", func(t *testing.T) {
+		svc, _, _, _, _ := setupEmailCollision(t, models.OAuthEmailCollisionAutoLink, false, true)
+
+		_, err := svc.HandleCallback(ctx, models.ProviderGoogle, "code", "1.2.3.4", "ua", nil)
+
+		assert.Equal(t, errOAuthEmailInUse, err)
+	})
+
+	t.Run("ShouldNotAutoLink_WhenLocalEmailUnverified", func(t *testing.T) {
+		svc, _, _, _, _ := setupEmailCollision(t, models.OAuthEmailCollisionAutoLink, true, false)
+
+		_, err := svc.HandleCallback(ctx, models.ProviderGoogle, "code", "1.2.3.4", "ua", nil)
+
+		assert.Equal(t, errOAuthEmailInUse, err)
+	})
+
+	t.Run("ShouldRequireLink_WhenConfirmPassword", func(t *testing.T) {
+		svc, mOAuth, _, mJWT, existing := setupEmailCollision(t, models.OAuthEmailCollisionConfirmPassword, true, true)
+		mJWT.GenerateOAuthLinkTokenFunc = func(user *models.User, provider, providerUserID string, applicationID *uuid.UUID) (string, error) {
+			assert.Equal(t, existing.ID, user.ID)
+			assert.Equal(t, "google", provider)
+			assert.Equal(t, "google-uid-collision", providerUserID)
+			return "link-token", nil
+		}
+		mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
+			t.Fatal("account must not be linked before the password is confirmed")
+			return nil
+		}
+
+		result, err := svc.HandleCallback(ctx, models.ProviderGoogle, "code", "1.2.3.4", "ua", nil)
+
+		require.NoError(t, err)
+		assert.True(t, result.LinkRequired)
+		assert.Equal(t, "link-token", result.LinkToken)
+		assert.Empty(t, result.AccessToken)
+		assert.Nil(t, result.User)
+	})
+}
+
+func TestOAuthService_ConfirmLink(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*OAuthService, *mockOAuthStore, *models.User) {
+		svc, mOAuth, _, mJWT, existing := setupEmailCollision(t, models.OAuthEmailCollisionConfirmPassword, true, true)
+		mJWT.ValidateOAuthLinkTokenFunc = func(tokenString string) (*jwt.Claims, error) {
+			if tokenString != "link-token" {
+				return nil, jwt.ErrInvalidToken
+			}
+			return &jwt.Claims{
+				UserID:              existing.ID,
+				TokenType:           jwt.TokenTypeOAuthLink,
+				OAuthProvider:       "google",
+				OAuthProviderUserID: "google-uid-collision",
+			}, nil
+		}
+		return svc, mOAuth, existing
+	}
+
+	t.Run("ShouldLinkAndSignIn_WhenPasswordMatches", func(t *testing.T) {
+		svc, mOAuth, existing := setup(t)
+		var linked *models.OAuthAccount
+		mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
+			linked = account
+			return nil
+		}
+
+		result, err := svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "LocalPass123!"}, "1.2.3.4", "ua")
+
+		require.NoError(t, err)
+		require.NotNil(t, linked)
+		assert.Equal(t, existing.ID, linked.UserID)
+		assert.Equal(t, "google", linked.Provider)
+		assert.Equal(t, "jwt-access", result.AccessToken)
+	})
+
+	t.Run("ShouldReject_WhenPasswordWrong", func(t *testing.T) {
+		svc, mOAuth, _ := setup(t)
+		mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error {
+			t.Fatal("account must not be linked")
+			return nil
+		}
+
+		_, err := svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "wrong"}, "1.2.3.4", "ua")
+
+		assert.Equal(t, models.ErrInvalidCredentials, err)
+	})
+
+	t.Run("ShouldReject_WhenLinkTokenInvalidOrStale", func(t *testing.T) {
+		svc, _, existing := setup(t)
+
+		_, err := svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "forged", Password: "LocalPass123!"}, "1.2.3.4", "ua")
+		assert.Equal(t, errInvalidOAuthLinkToken, err)
+
+		// Signing out everywhere after the token was issued invalidates it
+		existing.TokenVersion++
+		_, err = svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "LocalPass123!"}, "1.2.3.4", "ua")
+		assert.Equal(t, errInvalidOAuthLinkToken, err)
+	})
+
+	t.Run("ShouldRequireTOTPCode_WhenTwoFactorEnabled", func(t *testing.T) {
+		svc, mOAuth, existing := setup(t)
+		key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: existing.Email})
+		require.NoError(t, err)
+		secret := key.Secret()
+		existing.TOTPEnabled = true
+		existing.TOTPSecret = &secret
+		mOAuth.CreateOAuthAccountFunc = func(ctx context.Context, account *models.OAuthAccount) error { return nil }
+
+		_, err = svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "LocalPass123!"}, "1.2.3.4", "ua")
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 401, appErr.Code)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		result, err := svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "LocalPass123!", TOTPCode: code}, "1.2.3.4", "ua")
+		require.NoError(t, err)
+		assert.Equal(t, "jwt-access", result.AccessToken)
+	})
+
+	t.Run("ShouldReject_WhenProviderAccountLinkedElsewhere", func(t *testing.T) {
+		svc, mOAuth, _ := setup(t)
+		mOAuth.GetOAuthAccountFunc = func(ctx context.Context, provider, providerUserID string) (*models.OAuthAccount, error) {
+			return &models.OAuthAccount{UserID: uuid.New(), Provider: provider, ProviderUserID: providerUserID}, nil
+		}
+
+		_, err := svc.ConfirmLink(ctx, &models.ConfirmOAuthLinkRequest{LinkToken: "link-token", Password: "LocalPass123!"}, "1.2.3.4", "ua")
+
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 409, appErr.Code)
+	})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider is a minimal provider whose behaviour the tests can break on purpose
+type fakeOIDCProvider struct {
+	issuer       string
+	skipNoStore  bool
+	skipBearer   bool
+	keepRevoked  bool
+	revoked      map[string]bool
+	clientSecret string
+}
+
+func (p *fakeOIDCProvider) handler(baseURL string) http.Handler {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := p.issuer
+		if issuer == "" {
+			issuer = baseURL
+		}
+		writeJSON(w, http.StatusOK, models.OIDCDiscoveryDocument{
+			Issuer:                           issuer,
+			AuthorizationEndpoint:            baseURL + "/oauth/authorize",
+			TokenEndpoint:                    baseURL + "/oauth/token",
+			UserInfoEndpoint:                 baseURL + "/oauth/userinfo",
+			JwksURI:                          baseURL + "/.well-known/jwks.json",
+			IntrospectionEndpoint:            baseURL + "/oauth/introspect",
+			RevocationEndpoint:               baseURL + "/oauth/revoke",
+			ResponseTypesSupported:           []string{"code"},
+			SubjectTypesSupported:            []string{"public"},
+			IDTokenSigningAlgValuesSupported: []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, models.JWKSDocument{Keys: []models.JWK{{KeyType: "RSA", Use: "sig", KeyID: "k1"}}})
+	})
+	mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if !p.skipNoStore {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		switch r.PostFormValue("grant_type") {
+		case "authorization_code":
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		case "client_credentials":
+			if r.PostFormValue("client_secret") != p.clientSecret {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+				return
+			}
+			writeJSON(w, http.StatusOK, models.TokenResponse{AccessToken: "issued-token", TokenType: "Bearer", ExpiresIn: 900})
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		}
+	})
+	mux.HandleFunc("/oauth/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if !p.skipBearer {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+	})
+	mux.HandleFunc("/oauth/introspect", func(w http.ResponseWriter, r *http.Request) {
+		token := r.PostFormValue("token")
+		writeJSON(w, http.StatusOK, map[string]bool{"active": token == "issued-token" && !p.revoked[token]})
+	})
+	mux.HandleFunc("/oauth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		if !p.keepRevoked {
+			p.revoked[r.PostFormValue("token")] = true
+		}
+		writeJSON(w, http.StatusOK, map[string]string{})
+	})
+	return mux
+}
+
+func runConformanceAgainst(t *testing.T, provider *fakeOIDCProvider, req *models.OIDCConformanceRequest) *models.OIDCConformanceReport {
+	t.Helper()
+
+	provider.revoked = make(map[string]bool)
+	provider.clientSecret = "secret"
+
+	var baseURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider.handler(baseURL).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	baseURL = server.URL
+
+	svc := NewOIDCConformanceService(server.URL, server.Client(), logger.New("test", logger.DebugLevel, false))
+	return svc.Run(context.Background(), req)
+}
+
+func conformanceStatus(report *models.OIDCConformanceReport, name string) string {
+	for _, result := range report.Results {
+		if result.Name == name {
+			return result.Status
+		}
+	}
+	return ""
+}
+
+func TestOIDCConformance_ShouldPassAgainstCompliantProvider(t *testing.T) {
+	report := runConformanceAgainst(t, &fakeOIDCProvider{}, &models.OIDCConformanceRequest{
+		ClientID:     "agw_client",
+		ClientSecret: "secret",
+	})
+
+	for _, result := range report.Results {
+		assert.Equal(t, models.ConformancePassed, result.Status, "%s: %s", result.Name, result.Message)
+	}
+	assert.True(t, report.OK())
+	assert.Zero(t, report.Skipped)
+	assert.Equal(t, models.ConformancePassed, conformanceStatus(report, "revocation.revokes_token"))
+}
+
+func TestOIDCConformance_ShouldSkipClientChecksWithoutClient(t *testing.T) {
+	report := runConformanceAgainst(t, &fakeOIDCProvider{}, &models.OIDCConformanceRequest{})
+
+	assert.True(t, report.OK())
+	assert.Equal(t, models.ConformancePassed, conformanceStatus(report, "token.unsupported_grant_type"))
+	assert.Equal(t, models.ConformanceSkipped, conformanceStatus(report, "token.client_credentials"))
+	assert.Equal(t, models.ConformanceSkipped, conformanceStatus(report, "introspection.inactive_token"))
+	assert.Equal(t, models.ConformanceSkipped, conformanceStatus(report, "authorize.foreign_redirect_uri"))
+}
+
+func TestOIDCConformance_ShouldReportViolations(t *testing.T) {
+	report := runConformanceAgainst(t, &fakeOIDCProvider{
+		issuer:      "https://someone-else.example.com",
+		skipNoStore: true,
+		skipBearer:  true,
+		keepRevoked: true,
+	}, &models.OIDCConformanceRequest{
+		ClientID:     "agw_client",
+		ClientSecret: "secret",
+	})
+
+	assert.False(t, report.OK())
+	assert.Equal(t, models.ConformanceFailed, conformanceStatus(report, "discovery.issuer"))
+	assert.Equal(t, models.ConformanceFailed, conformanceStatus(report, "token.client_credentials"))
+	assert.Equal(t, models.ConformanceFailed, conformanceStatus(report, "userinfo.missing_token"))
+	assert.Equal(t, models.ConformanceFailed, conformanceStatus(report, "userinfo.invalid_token"))
+	assert.Equal(t, models.ConformanceFailed, conformanceStatus(report, "revocation.revokes_token"))
+	assert.Equal(t, 5, report.Failed)
+}
+
+func TestOIDCConformance_ShouldFailWhenDiscoveryIsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	svc := NewOIDCConformanceService(server.URL, server.Client(), logger.New("test", logger.DebugLevel, false))
+	report := svc.Run(context.Background(), &models.OIDCConformanceRequest{})
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "discovery.fetch", report.Results[0].Name)
+	assert.Equal(t, models.ConformanceFailed, report.Results[0].Status)
+}
+
+func TestIsSecureEndpoint(t *testing.T) {
+	for endpoint, want := range map[string]bool{
+		"https://auth.example.com/oauth/token": true,
+		"http://localhost:8811/oauth/token":    true,
+		"http://127.0.0.1:8811/oauth/token":    true,
+		"http://auth.example.com/oauth/token":  false,
+	} {
+		assert.Equal(t, want, isSecureEndpoint(endpoint), endpoint)
+	}
+}
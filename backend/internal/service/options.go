@@ -27,6 +27,23 @@ var (
 	BuildUserListOptions = queryopt.BuildUserListOptions
 )
 
+type UserFilterOptions = queryopt.UserFilterOptions
+type UserFilterOption = queryopt.UserFilterOption
+
+var (
+	UserFilterSearch        = queryopt.UserFilterSearch
+	UserFilterApp           = queryopt.UserFilterApp
+	UserFilterRoles         = queryopt.UserFilterRoles
+	UserFilterStates        = queryopt.UserFilterStates
+	UserFilterEmailVerified = queryopt.UserFilterEmailVerified
+	UserFilterTwoFactor     = queryopt.UserFilterTwoFactor
+	UserFilterCreatedFrom   = queryopt.UserFilterCreatedFrom
+	UserFilterCreatedTo     = queryopt.UserFilterCreatedTo
+	UserFilterMetadata      = queryopt.UserFilterMetadata
+	UserFilterAfter         = queryopt.UserFilterAfter
+	BuildUserFilterOptions  = queryopt.BuildUserFilterOptions
+)
+
 // --- OAuthClientStore options ---
 
 type OAuthClientListOptions = queryopt.OAuthClientListOptions
@@ -47,3 +64,21 @@ var (
 	APIKeyActiveOnly     = queryopt.APIKeyActiveOnly
 	BuildAPIKeyGetOptions = queryopt.BuildAPIKeyGetOptions
 )
+
+// --- AuditStore options ---
+
+type AuditLogListOptions = queryopt.AuditLogListOptions
+type AuditLogListOption = queryopt.AuditLogListOption
+
+var (
+	AuditLogListUser         = queryopt.AuditLogListUser
+	AuditLogListApp          = queryopt.AuditLogListApp
+	AuditLogListActions      = queryopt.AuditLogListActions
+	AuditLogListStatus       = queryopt.AuditLogListStatus
+	AuditLogListIP           = queryopt.AuditLogListIP
+	AuditLogListCountry      = queryopt.AuditLogListCountry
+	AuditLogListFrom         = queryopt.AuditLogListFrom
+	AuditLogListTo           = queryopt.AuditLogListTo
+	AuditLogListSearch       = queryopt.AuditLogListSearch
+	BuildAuditLogListOptions = queryopt.BuildAuditLogListOptions
+)
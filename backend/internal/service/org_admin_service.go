@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+var (
+	errOrgUserNotFound     = models.NewAppError(http.StatusNotFound, "User is not a member of this organization")
+	errOrgUserIsAdmin      = models.NewAppError(http.StatusForbidden, "Global administrators cannot be managed by organization admins")
+	errOrgUserIsOrgAdmin   = models.NewAppError(http.StatusForbidden, "Organization admins cannot be managed by organization admins")
+	errOrgSystemRole       = models.NewAppError(http.StatusForbidden, "System roles cannot be assigned by organization admins")
+	errOrgRoleNotMapped    = models.NewAppError(http.StatusForbidden, "Only roles mapped to the organization can be assigned by organization admins")
+	errOrgRoleNotFound     = models.NewAppError(http.StatusNotFound, "Role not found")
+	errOrgAPIKeyNotFound   = models.NewAppError(http.StatusNotFound, "API key does not belong to this organization")
+	errOrgAdminNotFound    = models.NewAppError(http.StatusNotFound, "User is not an admin of this group")
+	errOrgAdminUserMissing = models.NewAppError(http.StatusNotFound, "User not found")
+)
+
+// OrgAdminService handles organization admin delegation. Organizations are groups: an
+// organization admin manages the users, roles and API keys of the members of a group and
+// of its child groups, and nothing outside of it.
+type OrgAdminService struct {
+	store      GroupAdminStore
+	groupRepo  GroupRepository
+	userRepo   UserStore
+	rbacRepo   RBACStore
+	apiKeyRepo APIKeyStore
+	users      AdminUserServicer
+	logger     *logger.Logger
+}
+
+// NewOrgAdminService creates a new organization admin service
+func NewOrgAdminService(
+	store GroupAdminStore,
+	groupRepo GroupRepository,
+	userRepo UserStore,
+	rbacRepo RBACStore,
+	apiKeyRepo APIKeyStore,
+	users AdminUserServicer,
+	logger *logger.Logger,
+) *OrgAdminService {
+	return &OrgAdminService{
+		store:      store,
+		groupRepo:  groupRepo,
+		userRepo:   userRepo,
+		rbacRepo:   rbacRepo,
+		apiKeyRepo: apiKeyRepo,
+		users:      users,
+		logger:     logger,
+	}
+}
+
+// ListAdmins returns the organization admins designated on a group
+func (s *OrgAdminService) ListAdmins(ctx context.Context, groupID uuid.UUID) (*models.GroupAdminListResponse, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	admins, err := s.store.ListAdmins(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GroupAdminListResponse{Admins: admins}, nil
+}
+
+// AddAdmin designates a user as organization admin of a group
+func (s *OrgAdminService) AddAdmin(ctx context.Context, groupID, userID, createdBy uuid.UUID) (*models.GroupAdmin, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errOrgAdminUserMissing
+	}
+
+	admin := &models.GroupAdmin{
+		GroupID:   groupID,
+		UserID:    userID,
+		CreatedBy: &createdBy,
+		User:      user,
+	}
+	if err := s.store.AddAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("organization admin added", map[string]interface{}{
+		"group_id":   groupID.String(),
+		"user_id":    userID.String(),
+		"created_by": createdBy.String(),
+	})
+	return admin, nil
+}
+
+// RemoveAdmin revokes a user's organization admin designation on a group
+func (s *OrgAdminService) RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error {
+	if err := s.store.RemoveAdmin(ctx, groupID, userID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return errOrgAdminNotFound
+		}
+		return err
+	}
+
+	s.logger.Info("organization admin removed", map[string]interface{}{
+		"group_id": groupID.String(),
+		"user_id":  userID.String(),
+	})
+	return nil
+}
+
+// ListManagedOrgs returns the groups a user was designated organization admin of
+func (s *OrgAdminService) ListManagedOrgs(ctx context.Context, userID uuid.UUID) (*models.OrgListResponse, error) {
+	groups, err := s.store.ListAdministeredGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]models.GroupResponse, 0, len(groups))
+	for _, group := range groups {
+		memberCount, _ := s.groupRepo.GetGroupMemberCount(ctx, group.ID)
+		orgs = append(orgs, models.GroupResponse{
+			ID:            group.ID,
+			Name:          group.Name,
+			DisplayName:   group.DisplayName,
+			Description:   group.Description,
+			ParentGroupID: group.ParentGroupID,
+			IsSystemGroup: group.IsSystemGroup,
+			MemberCount:   memberCount,
+			CreatedAt:     group.CreatedAt,
+			UpdatedAt:     group.UpdatedAt,
+		})
+	}
+	return &models.OrgListResponse{Organizations: orgs}, nil
+}
+
+// IsOrgAdmin reports whether a user administers a group, directly or through a parent group
+func (s *OrgAdminService) IsOrgAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
+	return s.store.IsAdmin(ctx, userID, groupID)
+}
+
+// ListUsers returns the members of an organization and its child groups
+func (s *OrgAdminService) ListUsers(ctx context.Context, groupID uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	users, total, err := s.store.ListMembers(ctx, groupID, search, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	adminUsers := make([]*models.AdminUserResponse, 0, len(users))
+	for _, user := range users {
+		adminUser := toAdminUserResponse(user)
+		if count, err := s.apiKeyRepo.Count(ctx, user.ID); err == nil {
+			adminUser.APIKeysCount = count
+		}
+		adminUsers = append(adminUsers, adminUser)
+	}
+
+	return &models.AdminUserListResponse{
+		Users:      adminUsers,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// GetUser returns a member of an organization
+func (s *OrgAdminService) GetUser(ctx context.Context, groupID, userID uuid.UUID) (*models.AdminUserResponse, error) {
+	if err := s.checkManagedUser(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return s.users.GetUser(ctx, userID)
+}
+
+// CreateUser creates a user and adds them to the organization. Only roles mapped to the
+// organization may be requested; without roles the user gets the default role.
+func (s *OrgAdminService) CreateUser(ctx context.Context, groupID uuid.UUID, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	for _, roleID := range req.RoleIDs {
+		if err := s.checkAssignableRole(ctx, groupID, roleID); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.users.CreateUser(ctx, req, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.AddUser(ctx, groupID, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to add user to organization: %w", err)
+	}
+
+	s.logger.Info("organization user created", map[string]interface{}{
+		"group_id": groupID.String(),
+		"user_id":  user.ID.String(),
+		"admin_id": adminID.String(),
+	})
+	return s.users.GetUser(ctx, user.ID)
+}
+
+// UpdateUser updates the profile and status of an organization member
+func (s *OrgAdminService) UpdateUser(ctx context.Context, groupID, userID uuid.UUID, req *models.OrgUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if err := s.checkChangeableUser(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.users.UpdateUser(ctx, userID, &models.AdminUpdateUserRequest{
+		Username: req.Username,
+		FullName: req.FullName,
+		Phone:    req.Phone,
+		IsActive: req.IsActive,
+	}, adminID)
+}
+
+// RemoveUser removes a member from the organization and its child groups. The account
+// itself is kept.
+func (s *OrgAdminService) RemoveUser(ctx context.Context, groupID, userID uuid.UUID) error {
+	if err := s.checkChangeableUser(ctx, groupID, userID); err != nil {
+		return err
+	}
+	return s.store.RemoveMember(ctx, groupID, userID)
+}
+
+// ListRoles returns the roles organization admins may assign: the non-system roles mapped
+// to the organization. Other roles apply gateway-wide, beyond the organization.
+func (s *OrgAdminService) ListRoles(ctx context.Context, groupID uuid.UUID) ([]models.Role, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.groupRepo.GetGroupRoles(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignable := make([]models.Role, 0, len(roles))
+	for _, role := range roles {
+		if !role.IsSystemRole {
+			assignable = append(assignable, role)
+		}
+	}
+	return assignable, nil
+}
+
+// AssignRole grants a role mapped to the organization to an organization member
+func (s *OrgAdminService) AssignRole(ctx context.Context, groupID, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	if err := s.checkChangeableUser(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignableRole(ctx, groupID, roleID); err != nil {
+		return nil, err
+	}
+	return s.users.AssignRole(ctx, userID, roleID, adminID)
+}
+
+// RemoveRole revokes a role mapped to the organization from an organization member
+func (s *OrgAdminService) RemoveRole(ctx context.Context, groupID, userID, roleID uuid.UUID) (*models.AdminUserResponse, error) {
+	if err := s.checkChangeableUser(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignableRole(ctx, groupID, roleID); err != nil {
+		return nil, err
+	}
+	return s.users.RemoveRole(ctx, userID, roleID)
+}
+
+// ListAPIKeys returns the API keys owned by organization members
+func (s *OrgAdminService) ListAPIKeys(ctx context.Context, groupID uuid.UUID) (*models.AdminAPIKeyListResponse, error) {
+	keys, err := s.store.ListMemberAPIKeys(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[uuid.UUID]*models.User)
+	apiKeys := make([]*models.AdminAPIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		owner, ok := owners[key.UserID]
+		if !ok {
+			owner, _ = s.userRepo.GetByID(ctx, key.UserID, nil)
+			owners[key.UserID] = owner
+		}
+		apiKeys = append(apiKeys, toAdminAPIKeyResponse(key, owner))
+	}
+
+	return &models.AdminAPIKeyListResponse{
+		APIKeys:    apiKeys,
+		Total:      len(apiKeys),
+		Page:       1,
+		PageSize:   len(apiKeys),
+		TotalPages: 1,
+	}, nil
+}
+
+// RevokeAPIKey revokes an API key owned by an organization member
+func (s *OrgAdminService) RevokeAPIKey(ctx context.Context, groupID, keyID uuid.UUID) error {
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	isMember, err := s.store.IsMember(ctx, groupID, key.UserID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errOrgAPIKeyNotFound
+	}
+
+	return s.apiKeyRepo.Revoke(ctx, keyID)
+}
+
+// getGroup loads a group, mapping a missing group to a 404 application error
+func (s *OrgAdminService) getGroup(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// checkManagedUser ensures the target user belongs to the organization and is not a global
+// administrator, who may only be managed through the admin API
+func (s *OrgAdminService) checkManagedUser(ctx context.Context, groupID, userID uuid.UUID) error {
+	isMember, err := s.store.IsMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errOrgUserNotFound
+	}
+
+	roles, err := s.rbacRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.Name == string(models.RoleAdmin) {
+			return errOrgUserIsAdmin
+		}
+	}
+	return nil
+}
+
+// checkChangeableUser ensures the target user may be changed by an organization admin: on
+// top of checkManagedUser, admins of the organization or of a parent organization (the
+// caller included) are refused, so organization admins cannot act on each other
+func (s *OrgAdminService) checkChangeableUser(ctx context.Context, groupID, userID uuid.UUID) error {
+	if err := s.checkManagedUser(ctx, groupID, userID); err != nil {
+		return err
+	}
+
+	isOrgAdmin, err := s.store.IsAdmin(ctx, userID, groupID)
+	if err != nil {
+		return err
+	}
+	if isOrgAdmin {
+		return errOrgUserIsOrgAdmin
+	}
+	return nil
+}
+
+// checkAssignableRole ensures a role exists, is not a system role and is mapped to the
+// organization. Roles carry their permissions gateway-wide, so organization admins may only
+// hand out the ones an administrator tied to the organization.
+func (s *OrgAdminService) checkAssignableRole(ctx context.Context, groupID, roleID uuid.UUID) error {
+	role, err := s.rbacRepo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return errOrgRoleNotFound
+	}
+	if role.IsSystemRole {
+		return errOrgSystemRole
+	}
+
+	mapped, err := s.groupRepo.GetGroupRoles(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	for _, groupRole := range mapped {
+		if groupRole.ID == roleID {
+			return nil
+		}
+	}
+	return errOrgRoleNotMapped
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGroupAdminStore is a simple mock implementation of GroupAdminStore
+type mockGroupAdminStore struct {
+	members      map[uuid.UUID]bool // userID -> member of the organization under test
+	admins       map[uuid.UUID]bool // userID -> admin of the organization or a parent organization
+	removeErr    error
+	memberAPIKey []*models.APIKey
+}
+
+func (m *mockGroupAdminStore) ListAdmins(ctx context.Context, groupID uuid.UUID) ([]*models.GroupAdmin, error) {
+	return nil, nil
+}
+func (m *mockGroupAdminStore) AddAdmin(ctx context.Context, admin *models.GroupAdmin) error {
+	return nil
+}
+func (m *mockGroupAdminStore) RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error {
+	return m.removeErr
+}
+func (m *mockGroupAdminStore) ListAdministeredGroups(ctx context.Context, userID uuid.UUID) ([]*models.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupAdminStore) IsAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
+	return m.admins[userID], nil
+}
+func (m *mockGroupAdminStore) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	return m.members[userID], nil
+}
+func (m *mockGroupAdminStore) ListMembers(ctx context.Context, groupID uuid.UUID, search string, limit, offset int) ([]*models.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockGroupAdminStore) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	delete(m.members, userID)
+	return nil
+}
+func (m *mockGroupAdminStore) ListMemberAPIKeys(ctx context.Context, groupID uuid.UUID) ([]*models.APIKey, error) {
+	return m.memberAPIKey, nil
+}
+
+// mockOrgAdminUsers implements AdminUserServicer, recording the delegated calls
+type mockOrgAdminUsers struct {
+	AdminUserServicer
+	createdUserID  uuid.UUID
+	assignedRoleID uuid.UUID
+}
+
+func (m *mockOrgAdminUsers) GetUser(ctx context.Context, userID uuid.UUID) (*models.AdminUserResponse, error) {
+	return &models.AdminUserResponse{ID: userID}, nil
+}
+
+func (m *mockOrgAdminUsers) CreateUser(ctx context.Context, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	m.createdUserID = uuid.New()
+	return &models.AdminUserResponse{ID: m.createdUserID}, nil
+}
+
+func (m *mockOrgAdminUsers) AssignRole(ctx context.Context, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error) {
+	m.assignedRoleID = roleID
+	return &models.AdminUserResponse{ID: userID}, nil
+}
+
+type orgAdminTestEnv struct {
+	svc       *OrgAdminService
+	store     *mockGroupAdminStore
+	groups    *mockGroupStore
+	rbac      *mockRBACStore
+	apiKeys   *mockAPIKeyStore
+	users     *mockOrgAdminUsers
+	groupID   uuid.UUID
+	memberID  uuid.UUID
+	roleID    uuid.UUID
+	sysRoleID uuid.UUID
+}
+
+func setupOrgAdminTest() *orgAdminTestEnv {
+	env := &orgAdminTestEnv{
+		store:     &mockGroupAdminStore{members: make(map[uuid.UUID]bool), admins: make(map[uuid.UUID]bool)},
+		groups:    newMockGroupStore(),
+		rbac:      &mockRBACStore{},
+		apiKeys:   &mockAPIKeyStore{},
+		users:     &mockOrgAdminUsers{},
+		groupID:   uuid.New(),
+		memberID:  uuid.New(),
+		roleID:    uuid.New(),
+		sysRoleID: uuid.New(),
+	}
+	env.groups.groups[env.groupID] = &models.Group{ID: env.groupID, Name: "acme"}
+	env.groups.roles[env.groupID] = []models.Role{{ID: env.roleID, Name: "editor"}}
+	env.store.members[env.memberID] = true
+
+	env.rbac.GetRoleByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+		switch id {
+		case env.roleID:
+			return &models.Role{ID: id, Name: "editor"}, nil
+		case env.sysRoleID:
+			return &models.Role{ID: id, Name: "admin", IsSystemRole: true}, nil
+		}
+		return nil, errors.New("role not found")
+	}
+	env.rbac.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+		return []models.Role{{Name: "user"}}, nil
+	}
+
+	log := logger.New("test", logger.InfoLevel, false)
+	env.svc = NewOrgAdminService(env.store, env.groups, &mockUserStore{}, env.rbac, env.apiKeys, env.users, log)
+	return env
+}
+
+func assertAppErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var appErr *models.AppError
+	require.True(t, errors.As(err, &appErr), "expected AppError, got %v", err)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func TestOrgAdminService_AssignRole_DelegatesForMember(t *testing.T) {
+	env := setupOrgAdminTest()
+
+	user, err := env.svc.AssignRole(context.Background(), env.groupID, env.memberID, env.roleID, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, env.memberID, user.ID)
+	assert.Equal(t, env.roleID, env.users.assignedRoleID)
+}
+
+func TestOrgAdminService_AssignRole_RejectsSystemRole(t *testing.T) {
+	env := setupOrgAdminTest()
+
+	_, err := env.svc.AssignRole(context.Background(), env.groupID, env.memberID, env.sysRoleID, uuid.New())
+	assertAppErrorCode(t, err, http.StatusForbidden)
+	assert.Equal(t, uuid.Nil, env.users.assignedRoleID)
+}
+
+func TestOrgAdminService_AssignRole_RejectsRoleNotMappedToOrganization(t *testing.T) {
+	env := setupOrgAdminTest()
+	globalRoleID := uuid.New()
+	env.rbac.GetRoleByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+		return &models.Role{ID: id, Name: "billing-manager"}, nil
+	}
+
+	_, err := env.svc.AssignRole(context.Background(), env.groupID, env.memberID, globalRoleID, uuid.New())
+	assert.Equal(t, errOrgRoleNotMapped, err)
+	assert.Equal(t, uuid.Nil, env.users.assignedRoleID)
+}
+
+func TestOrgAdminService_AssignRole_RejectsOrganizationAdmin(t *testing.T) {
+	env := setupOrgAdminTest()
+	// Admins of the organization and of its parents both show up in IsAdmin
+	env.store.admins[env.memberID] = true
+
+	_, err := env.svc.AssignRole(context.Background(), env.groupID, env.memberID, env.roleID, uuid.New())
+	assert.Equal(t, errOrgUserIsOrgAdmin, err)
+	assert.Equal(t, uuid.Nil, env.users.assignedRoleID)
+}
+
+func TestOrgAdminService_RemoveUser_RejectsOrganizationAdmin(t *testing.T) {
+	env := setupOrgAdminTest()
+	env.store.admins[env.memberID] = true
+
+	err := env.svc.RemoveUser(context.Background(), env.groupID, env.memberID)
+	assert.Equal(t, errOrgUserIsOrgAdmin, err)
+	assert.True(t, env.store.members[env.memberID])
+}
+
+func TestOrgAdminService_AssignRole_RejectsUserOutsideOrganization(t *testing.T) {
+	env := setupOrgAdminTest()
+
+	_, err := env.svc.AssignRole(context.Background(), env.groupID, uuid.New(), env.roleID, uuid.New())
+	assertAppErrorCode(t, err, http.StatusNotFound)
+}
+
+func TestOrgAdminService_GetUser_RejectsGlobalAdmin(t *testing.T) {
+	env := setupOrgAdminTest()
+	env.rbac.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+		return []models.Role{{Name: string(models.RoleAdmin)}}, nil
+	}
+
+	_, err := env.svc.GetUser(context.Background(), env.groupID, env.memberID)
+	assertAppErrorCode(t, err, http.StatusForbidden)
+}
+
+func TestOrgAdminService_CreateUser_AddsUserToOrganization(t *testing.T) {
+	env := setupOrgAdminTest()
+
+	user, err := env.svc.CreateUser(context.Background(), env.groupID, &models.AdminCreateUserRequest{
+		Email:    "new@acme.test",
+		Username: "newuser",
+		RoleIDs:  []uuid.UUID{env.roleID},
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, env.users.createdUserID, user.ID)
+	assert.Equal(t, []uuid.UUID{user.ID}, env.groups.users[env.groupID])
+}
+
+func TestOrgAdminService_CreateUser_RejectsSystemRole(t *testing.T) {
+	env := setupOrgAdminTest()
+
+	_, err := env.svc.CreateUser(context.Background(), env.groupID, &models.AdminCreateUserRequest{
+		Email:    "new@acme.test",
+		Username: "newuser",
+		RoleIDs:  []uuid.UUID{env.sysRoleID},
+	}, uuid.New())
+	assertAppErrorCode(t, err, http.StatusForbidden)
+	assert.Equal(t, uuid.Nil, env.users.createdUserID)
+}
+
+func TestOrgAdminService_ListRoles_ReturnsOrganizationRoles(t *testing.T) {
+	env := setupOrgAdminTest()
+	env.groups.roles[env.groupID] = []models.Role{
+		{Name: "admin", IsSystemRole: true},
+		{Name: "editor"},
+	}
+
+	roles, err := env.svc.ListRoles(context.Background(), env.groupID)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, "editor", roles[0].Name)
+}
+
+func TestOrgAdminService_RevokeAPIKey_RejectsKeyOutsideOrganization(t *testing.T) {
+	env := setupOrgAdminTest()
+	keyID := uuid.New()
+	revoked := false
+	env.apiKeys.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+		return &models.APIKey{ID: id, UserID: uuid.New()}, nil
+	}
+	env.apiKeys.RevokeFunc = func(ctx context.Context, id uuid.UUID) error {
+		revoked = true
+		return nil
+	}
+
+	err := env.svc.RevokeAPIKey(context.Background(), env.groupID, keyID)
+	assertAppErrorCode(t, err, http.StatusNotFound)
+	assert.False(t, revoked)
+}
+
+func TestOrgAdminService_RemoveAdmin_MapsNotFound(t *testing.T) {
+	env := setupOrgAdminTest()
+	env.store.removeErr = models.ErrNotFound
+
+	err := env.svc.RemoveAdmin(context.Background(), env.groupID, uuid.New())
+	assert.Equal(t, errOrgAdminNotFound, err)
+}
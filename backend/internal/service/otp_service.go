@@ -36,6 +36,7 @@ type OTPServiceOptions struct {
 	SMSLogRepo          SMSLogStore
 	Cache               CacheService
 	Config              *config.Config
+	SignupPolicy        SignupEnforcer // Joins newly verified users to organizations by email domain
 }
 
 // EmailProfileSender defines the interface for profile-based email sending
@@ -53,6 +54,7 @@ type OTPService struct {
 	auditService        AuditLogger
 	cache               CacheService
 	cfg                 *config.Config
+	signupPolicy        SignupEnforcer
 }
 
 func NewOTPService(
@@ -71,6 +73,7 @@ func NewOTPService(
 		auditService:        auditService,
 		cache:               opts.Cache,
 		cfg:                 opts.Config,
+		signupPolicy:        opts.SignupPolicy,
 	}
 }
 
@@ -380,9 +383,7 @@ func (s *OTPService) sendSMS(ctx context.Context, phone, code string, otpType mo
 	}
 
 	if logID != nil {
-		_ = s.smsLogRepo.UpdateStatus(ctx, *logID, models.SMSStatusSent, nil)
-		// store provider message id if repository supports it via UpdateStatus? For now ignored
-		_ = messageID
+		_ = s.smsLogRepo.MarkSent(ctx, *logID, messageID)
 	}
 
 	return nil
@@ -428,7 +429,8 @@ func (s *OTPService) logOTPError(otpType models.OTPType, fields map[string]inter
 
 func validateOTPType(otpType models.OTPType) error {
 	switch otpType {
-	case models.OTPTypeVerification, models.OTPTypePasswordReset, models.OTPType2FA, models.OTPTypeLogin, models.OTPTypeRegistration:
+	case models.OTPTypeVerification, models.OTPTypePasswordReset, models.OTPType2FA, models.OTPTypeLogin, models.OTPTypeRegistration,
+		models.OTPTypeAccountRecovery, models.OTPTypeSecondaryEmail:
 		return nil
 	default:
 		return models.NewAppError(400, "Unsupported OTP type")
@@ -439,9 +441,15 @@ func (s *OTPService) enrichVerificationResponseEmail(ctx context.Context, resp *
 	switch otpType {
 	case models.OTPTypeVerification:
 		user, err := s.userRepo.GetByEmail(ctx, email, utils.Ptr(true))
-		if err == nil && user != nil {
+		// GetByEmail also matches verified secondary addresses, which must not verify the primary one
+		if err == nil && user != nil && user.Email == email {
+			firstVerification := !user.EmailVerified
 			_ = s.userRepo.MarkEmailVerified(ctx, user.ID)
 			resp.User = user
+			if firstVerification && s.signupPolicy != nil {
+				user.EmailVerified = true
+				s.signupPolicy.AutoJoin(ctx, user)
+			}
 		}
 	case models.OTPTypeLogin:
 		user, err := s.userRepo.GetByEmail(ctx, email, utils.Ptr(true))
@@ -485,6 +493,10 @@ func (s *OTPService) formatOTPMessage(code string, otpType models.OTPType) strin
 		return fmt.Sprintf("Your 2FA code is: %s\n\nThis code will expire in 10 minutes.\n\nAuth Gateway", code)
 	case models.OTPTypeLogin, models.OTPTypeRegistration:
 		return fmt.Sprintf("Your login code is: %s\n\nThis code will expire in 10 minutes.\n\nAuth Gateway", code)
+	case models.OTPTypeAccountRecovery:
+		return fmt.Sprintf("Your account recovery code is: %s\n\nThis code will expire in 10 minutes. If you did not request account recovery, contact support.\n\nAuth Gateway", code)
+	case models.OTPTypeSecondaryEmail:
+		return fmt.Sprintf("Your email confirmation code is: %s\n\nThis code will expire in 10 minutes.\n\nAuth Gateway", code)
 	default:
 		return fmt.Sprintf("Your verification code is: %s\n\nThis code will expire in 10 minutes.", code)
 	}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/uptrace/bun"
+)
+
+const (
+	outboxBatchSize     = 50
+	outboxLease         = time.Minute // longer than dispatching a batch may take
+	outboxPollInterval  = 2 * time.Second
+	outboxDispatchLimit = 30 * time.Second
+)
+
+// AuditWriter writes an audit log synchronously
+type AuditWriter interface {
+	LogSync(ctx context.Context, params AuditLogParams) error
+}
+
+// outboxWebhookPayload is a recorded webhook event
+type outboxWebhookPayload struct {
+	EventType string                 `json:"event_type"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// OutboxService implements the transactional outbox for audit logs and webhook events.
+// Request paths record them in the transaction of the state change they describe, so they
+// are stored if and only if the change is committed; Run dispatches them afterwards and
+// retries those that fail until they are delivered. An event may be dispatched twice when
+// an instance dies mid-dispatch: audit logs are written under the event ID and skipped the
+// second time, webhook consumers deduplicate as they do for redeliveries.
+type OutboxService struct {
+	store    OutboxStore
+	audit    AuditWriter
+	webhooks WebhookTrigger
+	logger   *logger.Logger
+	now      func() time.Time
+	wake     chan struct{}
+}
+
+// NewOutboxService creates an outbox service
+func NewOutboxService(store OutboxStore, audit AuditWriter, webhooks WebhookTrigger, log *logger.Logger) *OutboxService {
+	return &OutboxService{
+		store:    store,
+		audit:    audit,
+		webhooks: webhooks,
+		logger:   log,
+		now:      time.Now,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// RecordAudit records an audit log through db, the transaction of the audited change
+func (s *OutboxService) RecordAudit(ctx context.Context, db bun.IDB, params AuditLogParams) error {
+	// Dispatch runs outside the request, so the trace is taken now
+	if params.TraceID == "" {
+		params.TraceID = tracing.TraceID(ctx)
+	}
+	return s.record(ctx, db, models.OutboxKindAudit, params)
+}
+
+// RecordWebhook records a webhook event through db, the transaction of the change it reports
+func (s *OutboxService) RecordWebhook(ctx context.Context, db bun.IDB, eventType string, data map[string]interface{}) error {
+	return s.record(ctx, db, models.OutboxKindWebhook, outboxWebhookPayload{EventType: eventType, Data: data})
+}
+
+func (s *OutboxService) record(ctx context.Context, db bun.IDB, kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	now := s.now()
+	return s.store.CreateWithTx(ctx, db, &models.OutboxEvent{
+		ID:          uuid.New(),
+		Kind:        kind,
+		Payload:     data,
+		AvailableAt: now,
+		CreatedAt:   now,
+	})
+}
+
+// Wake starts dispatching on this instance; call it once the transaction that recorded
+// events is committed
+func (s *OutboxService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches recorded events until ctx is done
+func (s *OutboxService) Run(ctx context.Context) {
+	poll := time.NewTicker(outboxPollInterval)
+	defer poll.Stop()
+
+	for {
+		s.DispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// DispatchDue dispatches every available event and returns how many were delivered
+func (s *OutboxService) DispatchDue(ctx context.Context) int {
+	delivered := 0
+	for ctx.Err() == nil {
+		batch, err := s.store.ClaimDue(ctx, outboxBatchSize, outboxLease)
+		if err != nil {
+			s.logger.Error("Failed to claim outbox events", map[string]interface{}{
+				"error": err.Error(),
+			})
+			break
+		}
+
+		for _, event := range batch {
+			if s.dispatch(ctx, event) {
+				delivered++
+			}
+		}
+
+		if len(batch) < outboxBatchSize {
+			break
+		}
+	}
+	return delivered
+}
+
+// dispatch delivers a claimed event, deleting it on success and rescheduling it with
+// backoff otherwise; it reports whether the event was delivered
+func (s *OutboxService) dispatch(ctx context.Context, event *models.OutboxEvent) bool {
+	dispatchCtx, cancel := context.WithTimeout(ctx, outboxDispatchLimit)
+	err := s.deliver(dispatchCtx, event)
+	cancel()
+
+	// The outcome is recorded even if the run is being stopped
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err == nil {
+		if deleteErr := s.store.Delete(storeCtx, event.ID); deleteErr != nil {
+			s.logger.Error("Failed to delete dispatched outbox event", map[string]interface{}{
+				"event_id": event.ID.String(),
+				"error":    deleteErr.Error(),
+			})
+		}
+		return true
+	}
+
+	s.logger.Warn("Outbox event dispatch failed", map[string]interface{}{
+		"event_id": event.ID.String(),
+		"kind":     event.Kind,
+		"attempts": event.Attempts,
+		"error":    err.Error(),
+	})
+	availableAt := s.now().Add(jobQueueBackoff(event.Attempts))
+	if rescheduleErr := s.store.Reschedule(storeCtx, event.ID, availableAt, truncateJobError(err)); rescheduleErr != nil {
+		s.logger.Error("Failed to reschedule outbox event", map[string]interface{}{
+			"event_id": event.ID.String(),
+			"error":    rescheduleErr.Error(),
+		})
+	}
+	return false
+}
+
+func (s *OutboxService) deliver(ctx context.Context, event *models.OutboxEvent) error {
+	switch event.Kind {
+	case models.OutboxKindAudit:
+		var params AuditLogParams
+		if err := json.Unmarshal(event.Payload, &params); err != nil {
+			return fmt.Errorf("invalid outbox audit log: %w", err)
+		}
+		params.ID = event.ID
+		return s.audit.LogSync(ctx, params)
+	case models.OutboxKindWebhook:
+		if s.webhooks == nil {
+			return nil
+		}
+		var payload outboxWebhookPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid outbox webhook event: %w", err)
+		}
+		return s.webhooks.TriggerWebhook(ctx, payload.EventType, payload.Data)
+	default:
+		return fmt.Errorf("unknown outbox event kind %q", event.Kind)
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outboxReschedule is a call to mockOutboxStore.Reschedule
+type outboxReschedule struct {
+	id          uuid.UUID
+	availableAt time.Time
+	lastError   string
+}
+
+type mockOutboxStore struct {
+	created     []*models.OutboxEvent
+	due         []*models.OutboxEvent
+	deleted     []uuid.UUID
+	rescheduled []outboxReschedule
+}
+
+func (m *mockOutboxStore) CreateWithTx(ctx context.Context, db bun.IDB, event *models.OutboxEvent) error {
+	m.created = append(m.created, event)
+	return nil
+}
+
+func (m *mockOutboxStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error) {
+	due := m.due
+	m.due = nil
+	for _, event := range due {
+		event.Attempts++
+	}
+	return due, nil
+}
+
+func (m *mockOutboxStore) Delete(ctx context.Context, id uuid.UUID) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockOutboxStore) Reschedule(ctx context.Context, id uuid.UUID, availableAt time.Time, lastError string) error {
+	m.rescheduled = append(m.rescheduled, outboxReschedule{id: id, availableAt: availableAt, lastError: lastError})
+	return nil
+}
+
+type mockAuditWriter struct {
+	logged []AuditLogParams
+	err    error
+}
+
+func (m *mockAuditWriter) LogSync(ctx context.Context, params AuditLogParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.logged = append(m.logged, params)
+	return nil
+}
+
+// triggeredWebhook is an event received by mockWebhookTrigger
+type triggeredWebhook struct {
+	eventType string
+	data      map[string]interface{}
+}
+
+type mockWebhookTrigger struct {
+	triggered []triggeredWebhook
+	err       error
+}
+
+func (m *mockWebhookTrigger) TriggerWebhook(ctx context.Context, eventType string, data map[string]interface{}) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.triggered = append(m.triggered, triggeredWebhook{eventType: eventType, data: data})
+	return nil
+}
+
+func setupOutboxService() (*OutboxService, *mockOutboxStore, *mockAuditWriter, *mockWebhookTrigger) {
+	store := &mockOutboxStore{}
+	audit := &mockAuditWriter{}
+	webhooks := &mockWebhookTrigger{}
+	svc := NewOutboxService(store, audit, webhooks, logger.New("test", logger.ErrorLevel, false))
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, store, audit, webhooks
+}
+
+func TestOutboxService_DispatchesRecordedEvents(t *testing.T) {
+	svc, store, audit, webhooks := setupOutboxService()
+	userID := uuid.New()
+
+	err := svc.RecordAudit(context.Background(), nil, AuditLogParams{
+		UserID:  &userID,
+		Action:  models.ActionSignUp,
+		Status:  models.StatusSuccess,
+		IP:      "203.0.113.7",
+		Details: map[string]interface{}{"passwordless": true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.RecordWebhook(context.Background(), nil, models.WebhookEventUserCreated, map[string]interface{}{
+		"user_id": userID.String(),
+	}))
+
+	require.Len(t, store.created, 2)
+	assert.Equal(t, models.OutboxKindAudit, store.created[0].Kind)
+	assert.Equal(t, svc.now(), store.created[0].AvailableAt)
+	assert.Equal(t, models.OutboxKindWebhook, store.created[1].Kind)
+
+	store.due = store.created
+	assert.Equal(t, 2, svc.DispatchDue(context.Background()))
+
+	// The audit log is written under the event ID, so a second dispatch is skipped
+	require.Len(t, audit.logged, 1)
+	assert.Equal(t, store.created[0].ID, audit.logged[0].ID)
+	assert.Equal(t, userID, *audit.logged[0].UserID)
+	assert.Equal(t, models.ActionSignUp, audit.logged[0].Action)
+	assert.Equal(t, "203.0.113.7", audit.logged[0].IP)
+	assert.Equal(t, true, audit.logged[0].Details["passwordless"])
+
+	require.Len(t, webhooks.triggered, 1)
+	assert.Equal(t, models.WebhookEventUserCreated, webhooks.triggered[0].eventType)
+	assert.Equal(t, userID.String(), webhooks.triggered[0].data["user_id"])
+
+	assert.Equal(t, []uuid.UUID{store.created[0].ID, store.created[1].ID}, store.deleted)
+	assert.Empty(t, store.rescheduled)
+}
+
+func TestOutboxService_ReschedulesFailedDispatch(t *testing.T) {
+	svc, store, _, webhooks := setupOutboxService()
+	webhooks.err = errors.New("database unavailable")
+
+	require.NoError(t, svc.RecordWebhook(context.Background(), nil, models.WebhookEventUserCreated, nil))
+	store.created[0].Attempts = 2
+	store.due = store.created
+
+	assert.Equal(t, 0, svc.DispatchDue(context.Background()))
+
+	assert.Empty(t, store.deleted)
+	require.Len(t, store.rescheduled, 1)
+	assert.Equal(t, store.created[0].ID, store.rescheduled[0].id)
+	assert.Equal(t, svc.now().Add(jobQueueBackoff(3)), store.rescheduled[0].availableAt)
+	assert.Equal(t, "database unavailable", store.rescheduled[0].lastError)
+}
+
+func TestOutboxService_UnknownKind(t *testing.T) {
+	svc, store, _, _ := setupOutboxService()
+	store.due = []*models.OutboxEvent{{ID: uuid.New(), Kind: "email", Payload: []byte(`{}`)}}
+
+	assert.Equal(t, 0, svc.DispatchDue(context.Background()))
+
+	require.Len(t, store.rescheduled, 1)
+	assert.Contains(t, store.rescheduled[0].lastError, `unknown outbox event kind "email"`)
+}
+
+func TestOutboxService_RecordAuditKeepsTraceID(t *testing.T) {
+	svc, store, audit, _ := setupOutboxService()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	require.NoError(t, svc.RecordAudit(ctx, nil, AuditLogParams{Action: models.ActionSignUp, Status: models.StatusSuccess}))
+	store.due = store.created
+	assert.Equal(t, 1, svc.DispatchDue(context.Background()))
+
+	// The event is dispatched outside the request, under the trace it was recorded in
+	require.Len(t, audit.logged, 1)
+	assert.Equal(t, traceID.String(), audit.logged[0].TraceID)
+}
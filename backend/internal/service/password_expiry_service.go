@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// passwordExpiryWarningBatch caps how many warnings are sent per run
+	passwordExpiryWarningBatch = 500
+	// passwordExpiryReportLimit caps the admin upcoming expirations report
+	passwordExpiryReportLimit = 1000
+)
+
+// PasswordExpiryService enforces password max-age policies. Policies are set per role and
+// per group on top of a global default; the strictest one that applies to a user wins.
+// Users are warned by email before expiry and blocked at sign-in once expired or when an
+// admin has flagged them to change their password.
+type PasswordExpiryService struct {
+	store             PasswordExpiryStore
+	notifier          NotificationSender
+	defaultMaxAgeDays int
+	warningDays       int
+	logger            *logger.Logger
+}
+
+// NewPasswordExpiryService creates a new password expiry service.
+// defaultMaxAgeDays of 0 disables the global default; warningDays of 0 disables warnings.
+func NewPasswordExpiryService(store PasswordExpiryStore, notifier NotificationSender, defaultMaxAgeDays, warningDays int, logger *logger.Logger) *PasswordExpiryService {
+	return &PasswordExpiryService{
+		store:             store,
+		notifier:          notifier,
+		defaultMaxAgeDays: defaultMaxAgeDays,
+		warningDays:       warningDays,
+		logger:            logger,
+	}
+}
+
+// ChangeRequired returns why the user must change their password before signing in,
+// or an empty string if sign-in may proceed
+func (s *PasswordExpiryService) ChangeRequired(ctx context.Context, user *models.User) (string, error) {
+	if user.MustChangePassword {
+		return models.PasswordChangeReasonAdminRequired, nil
+	}
+
+	expiresAt, err := s.store.GetExpiry(ctx, user.ID, s.defaultMaxAgeDays)
+	if err != nil {
+		return "", err
+	}
+
+	if expiresAt != nil && !time.Now().Before(*expiresAt) {
+		return models.PasswordChangeReasonExpired, nil
+	}
+
+	return "", nil
+}
+
+// RequirePasswordChange forces the user to set a new password at their next sign-in
+func (s *PasswordExpiryService) RequirePasswordChange(ctx context.Context, userID uuid.UUID) error {
+	return s.store.SetMustChangePassword(ctx, userID, true)
+}
+
+// SetRoleMaxAge sets or clears (nil) the password max age for members of a role
+func (s *PasswordExpiryService) SetRoleMaxAge(ctx context.Context, roleID uuid.UUID, maxAgeDays *int) error {
+	if err := validateMaxAge(maxAgeDays); err != nil {
+		return err
+	}
+	return s.store.SetRoleMaxAge(ctx, roleID, maxAgeDays)
+}
+
+// SetGroupMaxAge sets or clears (nil) the password max age for members of a group
+func (s *PasswordExpiryService) SetGroupMaxAge(ctx context.Context, groupID uuid.UUID, maxAgeDays *int) error {
+	if err := validateMaxAge(maxAgeDays); err != nil {
+		return err
+	}
+	return s.store.SetGroupMaxAge(ctx, groupID, maxAgeDays)
+}
+
+// ListUpcomingExpirations lists users whose password expires within the given number of days,
+// including passwords that have already expired
+func (s *PasswordExpiryService) ListUpcomingExpirations(ctx context.Context, days int) (*models.PasswordExpiryListResponse, error) {
+	if days < 0 {
+		return nil, models.NewAppError(400, "days must not be negative")
+	}
+
+	entries, err := s.store.ListExpiring(ctx, s.defaultMaxAgeDays, time.Now().AddDate(0, 0, days), false, passwordExpiryReportLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PasswordExpiryListResponse{
+		Users: entries,
+		Days:  days,
+		Total: len(entries),
+	}, nil
+}
+
+// SendExpiryWarnings emails users whose password expires within the warning window and
+// who have not been warned yet for their current password. Returns the number of emails sent.
+func (s *PasswordExpiryService) SendExpiryWarnings(ctx context.Context) (int, error) {
+	if s.warningDays <= 0 || s.notifier == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	entries, err := s.store.ListExpiring(ctx, s.defaultMaxAgeDays, now.AddDate(0, 0, s.warningDays), true, passwordExpiryWarningBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, entry := range entries {
+		// Already expired or no email address: sign-in enforcement takes over, just stop re-listing them
+		if entry.Email != "" && entry.ExpiresAt.After(now) {
+			variables := map[string]interface{}{
+				"username":   entry.Username,
+				"email":      entry.Email,
+				"expires_at": entry.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"),
+				"days_left":  int(math.Ceil(entry.ExpiresAt.Sub(now).Hours() / 24)),
+			}
+			if err := s.notifier.SendEmail(ctx, nil, nil, entry.Email, models.EmailTemplateTypePasswordExpiring, variables); err != nil {
+				s.logger.Warn("Failed to send password expiry warning", map[string]interface{}{
+					"user_id": entry.UserID.String(),
+					"error":   err.Error(),
+				})
+				continue
+			}
+			sent++
+		}
+
+		if err := s.store.MarkWarned(ctx, entry.UserID); err != nil {
+			s.logger.Warn("Failed to mark password expiry warning", map[string]interface{}{
+				"user_id": entry.UserID.String(),
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	return sent, nil
+}
+
+func validateMaxAge(maxAgeDays *int) error {
+	if maxAgeDays != nil && *maxAgeDays <= 0 {
+		return models.NewAppError(400, "max_age_days must be positive")
+	}
+	return nil
+}
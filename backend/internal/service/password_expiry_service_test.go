@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPasswordExpiryStore struct {
+	expiry     map[uuid.UUID]time.Time
+	entries    []*models.PasswordExpiryEntry
+	mustChange map[uuid.UUID]bool
+	warned     []uuid.UUID
+	roleMaxAge map[uuid.UUID]*int
+	listArgs   struct {
+		before       time.Time
+		unwarnedOnly bool
+	}
+}
+
+func newMockPasswordExpiryStore() *mockPasswordExpiryStore {
+	return &mockPasswordExpiryStore{
+		expiry:     make(map[uuid.UUID]time.Time),
+		mustChange: make(map[uuid.UUID]bool),
+		roleMaxAge: make(map[uuid.UUID]*int),
+	}
+}
+
+func (m *mockPasswordExpiryStore) GetExpiry(ctx context.Context, userID uuid.UUID, defaultMaxAgeDays int) (*time.Time, error) {
+	expiresAt, ok := m.expiry[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &expiresAt, nil
+}
+
+func (m *mockPasswordExpiryStore) ListExpiring(ctx context.Context, defaultMaxAgeDays int, before time.Time, unwarnedOnly bool, limit int) ([]*models.PasswordExpiryEntry, error) {
+	m.listArgs.before = before
+	m.listArgs.unwarnedOnly = unwarnedOnly
+	return m.entries, nil
+}
+
+func (m *mockPasswordExpiryStore) MarkWarned(ctx context.Context, userID uuid.UUID) error {
+	m.warned = append(m.warned, userID)
+	return nil
+}
+
+func (m *mockPasswordExpiryStore) SetMustChangePassword(ctx context.Context, userID uuid.UUID, required bool) error {
+	m.mustChange[userID] = required
+	return nil
+}
+
+func (m *mockPasswordExpiryStore) SetRoleMaxAge(ctx context.Context, roleID uuid.UUID, maxAgeDays *int) error {
+	m.roleMaxAge[roleID] = maxAgeDays
+	return nil
+}
+
+func (m *mockPasswordExpiryStore) SetGroupMaxAge(ctx context.Context, groupID uuid.UUID, maxAgeDays *int) error {
+	return nil
+}
+
+type mockNotificationSender struct {
+	sent      []string
+	fail      map[string]bool
+	variables []map[string]interface{}
+}
+
+func (m *mockNotificationSender) SendEmail(ctx context.Context, profileID *uuid.UUID, applicationID *uuid.UUID, toEmail string, templateType string, variables map[string]interface{}) error {
+	if m.fail[toEmail] {
+		return errors.New("smtp unavailable")
+	}
+	m.sent = append(m.sent, toEmail+":"+templateType)
+	m.variables = append(m.variables, variables)
+	return nil
+}
+
+func newTestPasswordExpiryService(store PasswordExpiryStore, notifier NotificationSender) *PasswordExpiryService {
+	return NewPasswordExpiryService(store, notifier, 90, 7, logger.New("test", logger.DebugLevel, false))
+}
+
+func TestPasswordExpiryService_ChangeRequired(t *testing.T) {
+	store := newMockPasswordExpiryStore()
+	svc := newTestPasswordExpiryService(store, nil)
+	ctx := context.Background()
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		reason, err := svc.ChangeRequired(ctx, &models.User{ID: uuid.New()})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("NotYetExpired", func(t *testing.T) {
+		user := &models.User{ID: uuid.New()}
+		store.expiry[user.ID] = time.Now().Add(time.Hour)
+
+		reason, err := svc.ChangeRequired(ctx, user)
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		user := &models.User{ID: uuid.New()}
+		store.expiry[user.ID] = time.Now().Add(-time.Minute)
+
+		reason, err := svc.ChangeRequired(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, models.PasswordChangeReasonExpired, reason)
+	})
+
+	t.Run("AdminRequired", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), MustChangePassword: true}
+
+		reason, err := svc.ChangeRequired(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, models.PasswordChangeReasonAdminRequired, reason)
+	})
+}
+
+func TestPasswordExpiryService_SetRoleMaxAge(t *testing.T) {
+	store := newMockPasswordExpiryStore()
+	svc := newTestPasswordExpiryService(store, nil)
+	ctx := context.Background()
+	roleID := uuid.New()
+
+	days := 30
+	require.NoError(t, svc.SetRoleMaxAge(ctx, roleID, &days))
+	assert.Equal(t, 30, *store.roleMaxAge[roleID])
+
+	require.NoError(t, svc.SetRoleMaxAge(ctx, roleID, nil))
+	assert.Nil(t, store.roleMaxAge[roleID])
+
+	invalid := 0
+	err := svc.SetRoleMaxAge(ctx, roleID, &invalid)
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestPasswordExpiryService_SendExpiryWarnings(t *testing.T) {
+	store := newMockPasswordExpiryStore()
+	notifier := &mockNotificationSender{fail: map[string]bool{"down@example.com": true}}
+	svc := newTestPasswordExpiryService(store, notifier)
+	ctx := context.Background()
+
+	soon := &models.PasswordExpiryEntry{UserID: uuid.New(), Email: "soon@example.com", ExpiresAt: time.Now().Add(3 * 24 * time.Hour)}
+	expired := &models.PasswordExpiryEntry{UserID: uuid.New(), Email: "expired@example.com", ExpiresAt: time.Now().Add(-time.Hour)}
+	noEmail := &models.PasswordExpiryEntry{UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	failing := &models.PasswordExpiryEntry{UserID: uuid.New(), Email: "down@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	store.entries = []*models.PasswordExpiryEntry{soon, expired, noEmail, failing}
+
+	sent, err := svc.SendExpiryWarnings(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"soon@example.com:" + models.EmailTemplateTypePasswordExpiring}, notifier.sent)
+	assert.True(t, store.listArgs.unwarnedOnly)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), store.listArgs.before, time.Minute)
+
+	// Failed sends are retried on the next run; everything else is not listed again
+	assert.ElementsMatch(t, []uuid.UUID{soon.UserID, expired.UserID, noEmail.UserID}, store.warned)
+}
+
+func TestPasswordExpiryService_SendExpiryWarnings_Disabled(t *testing.T) {
+	store := newMockPasswordExpiryStore()
+	store.entries = []*models.PasswordExpiryEntry{{UserID: uuid.New(), Email: "a@example.com", ExpiresAt: time.Now().Add(time.Hour)}}
+	notifier := &mockNotificationSender{}
+	svc := NewPasswordExpiryService(store, notifier, 90, 0, logger.New("test", logger.DebugLevel, false))
+
+	sent, err := svc.SendExpiryWarnings(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, notifier.sent)
+	assert.Empty(t, store.warned)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// CompromisedPasswordChecker looks passwords up in known breaches
+type CompromisedPasswordChecker interface {
+	IsCompromised(ctx context.Context, password string) (bool, int)
+}
+
+// PasswordPolicyService applies the password policy to new passwords. The policy lives in the
+// password_policy system setting so admins can change it at runtime; fields the setting leaves
+// out fall back to the PASSWORD_* environment defaults. Length, character class and dictionary
+// rules are checked first and reported together. The breach and history checks, which cost a
+// network call and bcrypt comparisons, only run for passwords that pass them.
+type PasswordPolicyService struct {
+	settings   SettingStore
+	history    PasswordHistoryStore
+	breaches   CompromisedPasswordChecker
+	dictionary map[string]bool
+	defaults   models.PasswordPolicy
+	logger     *logger.Logger
+}
+
+// NewPasswordPolicyService creates a new password policy service. dictionary extends the
+// built-in list of common passwords.
+func NewPasswordPolicyService(settings SettingStore, history PasswordHistoryStore, breaches CompromisedPasswordChecker, defaults models.PasswordPolicy, dictionary []string, logger *logger.Logger) *PasswordPolicyService {
+	words := make(map[string]bool, len(utils.CommonPasswords)+len(dictionary))
+	for _, list := range [][]string{utils.CommonPasswords, dictionary} {
+		for _, word := range list {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+				words[word] = true
+			}
+		}
+	}
+
+	return &PasswordPolicyService{
+		settings:   settings,
+		history:    history,
+		breaches:   breaches,
+		dictionary: words,
+		defaults:   defaults,
+		logger:     logger,
+	}
+}
+
+// LoadPasswordDictionary reads a word list with one password per line
+func LoadPasswordDictionary(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password dictionary: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password dictionary: %w", err)
+	}
+	return words, nil
+}
+
+// GetPolicy returns the current password policy
+func (s *PasswordPolicyService) GetPolicy(ctx context.Context) (*models.PasswordPolicy, error) {
+	setting, err := s.settings.GetSetting(ctx, models.SettingPasswordPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.defaults
+	if value := strings.TrimSpace(setting.Value); value != "" {
+		if err := json.Unmarshal([]byte(value), &policy); err != nil {
+			return nil, fmt.Errorf("invalid password policy setting: %w", err)
+		}
+	}
+	return &policy, nil
+}
+
+// UpdatePolicy replaces the password policy
+func (s *PasswordPolicyService) UpdatePolicy(ctx context.Context, req *models.UpdatePasswordPolicyRequest, updatedBy uuid.UUID) (*models.PasswordPolicy, error) {
+	if req.MaxLength > 0 && req.MaxLength < req.MinLength {
+		return nil, models.NewAppError(http.StatusBadRequest, "max_length must be 0 or at least min_length")
+	}
+
+	policy := &models.PasswordPolicy{
+		MinLength:        req.MinLength,
+		MaxLength:        req.MaxLength,
+		RequireUppercase: req.RequireUppercase,
+		RequireLowercase: req.RequireLowercase,
+		RequireNumbers:   req.RequireNumbers,
+		RequireSpecial:   req.RequireSpecial,
+		HistoryCount:     req.HistoryCount,
+		CheckDictionary:  req.CheckDictionary,
+		CheckCompromised: req.CheckCompromised,
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settings.UpdateSetting(ctx, models.SettingPasswordPolicy, string(value), &updatedBy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CheckPassword returns a *models.PasswordPolicyError listing the rules password breaks.
+// user is the account the password is for, or nil for a new account, which has no history.
+func (s *PasswordPolicyService) CheckPassword(ctx context.Context, password string, user *models.User) error {
+	policy := s.effectivePolicy(ctx)
+
+	if violations := s.ruleViolations(policy, password); len(violations) > 0 {
+		return &models.PasswordPolicyError{Violations: violations}
+	}
+
+	if policy.CheckCompromised && s.breaches != nil {
+		if compromised, count := s.breaches.IsCompromised(ctx, password); compromised {
+			return passwordViolation(models.PasswordRuleCompromised,
+				fmt.Sprintf("This password has appeared in %d data breaches. Please choose a different password.", count))
+		}
+	}
+
+	if user != nil && policy.HistoryCount > 0 && s.history != nil {
+		reused, err := s.reused(ctx, user, password, policy.HistoryCount)
+		if err != nil {
+			return err
+		}
+		if reused {
+			return passwordViolation(models.PasswordRuleHistory, models.ErrPasswordReused.Message)
+		}
+	}
+
+	return nil
+}
+
+// RecordPassword stores a new password hash in the user's history and trims the history to
+// the policy's depth
+func (s *PasswordPolicyService) RecordPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	policy := s.effectivePolicy(ctx)
+	if policy.HistoryCount <= 0 || s.history == nil {
+		return nil
+	}
+
+	if err := s.history.Create(ctx, &models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}); err != nil {
+		return err
+	}
+	return s.history.Prune(ctx, userID, policy.HistoryCount)
+}
+
+// effectivePolicy returns the current policy, or the environment defaults when the setting
+// cannot be read, so passwords are never accepted unchecked
+func (s *PasswordPolicyService) effectivePolicy(ctx context.Context) models.PasswordPolicy {
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load password policy, using defaults", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return s.defaults
+	}
+	return *policy
+}
+
+// ruleViolations checks the rules that need nothing but the password itself
+func (s *PasswordPolicyService) ruleViolations(policy models.PasswordPolicy, password string) []models.PasswordPolicyViolation {
+	violations := make([]models.PasswordPolicyViolation, 0)
+	add := func(rule, message string) {
+		violations = append(violations, models.PasswordPolicyViolation{Rule: rule, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		add(models.PasswordRuleMinLength, fmt.Sprintf("Password must be at least %d characters long", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		add(models.PasswordRuleMaxLength, fmt.Sprintf("Password must be at most %d characters long", policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasNumber, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+	if policy.RequireUppercase && !hasUpper {
+		add(models.PasswordRuleUppercase, "Password must contain at least one uppercase letter")
+	}
+	if policy.RequireLowercase && !hasLower {
+		add(models.PasswordRuleLowercase, "Password must contain at least one lowercase letter")
+	}
+	if policy.RequireNumbers && !hasNumber {
+		add(models.PasswordRuleNumber, "Password must contain at least one number")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		add(models.PasswordRuleSpecial, "Password must contain at least one special character")
+	}
+
+	if policy.CheckDictionary && s.isDictionaryWord(password) {
+		add(models.PasswordRuleDictionary, "Password is too common. Please choose a different password.")
+	}
+
+	return violations
+}
+
+// isDictionaryWord reports whether password is a common password, ignoring case and
+// trailing digits and symbols, so "Password123!" matches "password"
+func (s *PasswordPolicyService) isDictionaryWord(password string) bool {
+	word := strings.ToLower(password)
+	if s.dictionary[word] {
+		return true
+	}
+	base := strings.TrimRightFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+	return base != "" && s.dictionary[base]
+}
+
+// reused reports whether password is the user's current password or one of their recent ones
+func (s *PasswordPolicyService) reused(ctx context.Context, user *models.User, password string, historyCount int) (bool, error) {
+	if user.PasswordHash != "" && utils.CheckPassword(user.PasswordHash, password) == nil {
+		return true, nil
+	}
+
+	entries, err := s.history.GetRecent(ctx, user.ID, historyCount)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if utils.CheckPassword(entry.PasswordHash, password) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func passwordViolation(rule, message string) *models.PasswordPolicyError {
+	return &models.PasswordPolicyError{
+		Violations: []models.PasswordPolicyViolation{{Rule: rule, Message: message}},
+	}
+}
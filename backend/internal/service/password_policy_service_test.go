@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBreachChecker reports the passwords in breached as compromised
+type mockBreachChecker struct {
+	breached map[string]int
+	checked  int
+}
+
+func (m *mockBreachChecker) IsCompromised(ctx context.Context, password string) (bool, int) {
+	m.checked++
+	count, ok := m.breached[password]
+	return ok, count
+}
+
+var testPasswordPolicyDefaults = models.PasswordPolicy{
+	MinLength:        10,
+	RequireLowercase: true,
+	RequireNumbers:   true,
+	HistoryCount:     2,
+	CheckDictionary:  true,
+	CheckCompromised: true,
+}
+
+func newTestPasswordPolicyService(setting string) (*PasswordPolicyService, *mockSettingStore, *mockPasswordHistoryStore, *mockBreachChecker) {
+	settings := &mockSettingStore{values: map[string]string{models.SettingPasswordPolicy: setting}}
+	history := &mockPasswordHistoryStore{}
+	breaches := &mockBreachChecker{breached: map[string]int{"correcthorse1": 42}}
+	svc := NewPasswordPolicyService(settings, history, breaches, testPasswordPolicyDefaults, []string{"Tr0ub4dor"}, logger.New("test", logger.DebugLevel, false))
+	return svc, settings, history, breaches
+}
+
+func policyRules(t *testing.T, err error) []string {
+	t.Helper()
+	var policyErr *models.PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	return policyErr.Rules()
+}
+
+func TestPasswordPolicyService_GetPolicy_ShouldOverlayDefaults(t *testing.T) {
+	svc, settings, _, _ := newTestPasswordPolicyService("{}")
+
+	policy, err := svc.GetPolicy(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testPasswordPolicyDefaults, *policy)
+
+	settings.values[models.SettingPasswordPolicy] = `{"min_length":16,"check_compromised":false}`
+	policy, err = svc.GetPolicy(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 16, policy.MinLength)
+	assert.False(t, policy.CheckCompromised)
+	assert.True(t, policy.RequireNumbers)
+}
+
+func TestPasswordPolicyService_UpdatePolicy(t *testing.T) {
+	svc, settings, _, _ := newTestPasswordPolicyService("{}")
+	ctx := context.Background()
+
+	_, err := svc.UpdatePolicy(ctx, &models.UpdatePasswordPolicyRequest{MinLength: 12, MaxLength: 8}, uuid.New())
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.Code)
+
+	policy, err := svc.UpdatePolicy(ctx, &models.UpdatePasswordPolicyRequest{MinLength: 12, RequireSpecial: true}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 12, policy.MinLength)
+	assert.JSONEq(t, `{"min_length":12,"max_length":0,"require_uppercase":false,"require_lowercase":false,"require_numbers":false,
+		"require_special":true,"history_count":0,"check_dictionary":false,"check_compromised":false}`, settings.values[models.SettingPasswordPolicy])
+}
+
+func TestPasswordPolicyService_CheckPassword_ShouldReportEveryRule(t *testing.T) {
+	svc, _, _, breaches := newTestPasswordPolicyService(`{"require_uppercase":true,"require_special":true}`)
+
+	err := svc.CheckPassword(context.Background(), "short", nil)
+
+	assert.Equal(t, []string{
+		models.PasswordRuleMinLength, models.PasswordRuleUppercase, models.PasswordRuleNumber, models.PasswordRuleSpecial,
+	}, policyRules(t, err))
+	assert.Zero(t, breaches.checked, "breach check only runs for passwords that pass the local rules")
+}
+
+func TestPasswordPolicyService_CheckPassword_Dictionary(t *testing.T) {
+	svc, settings, _, _ := newTestPasswordPolicyService("{}")
+	ctx := context.Background()
+
+	for _, password := range []string{"password123", "Sunshine2024!", "tr0ub4dor99"} {
+		assert.Equal(t, []string{models.PasswordRuleDictionary}, policyRules(t, svc.CheckPassword(ctx, password, nil)), password)
+	}
+	assert.NoError(t, svc.CheckPassword(ctx, "blue4horse-staple", nil))
+
+	settings.values[models.SettingPasswordPolicy] = `{"check_dictionary":false}`
+	assert.NoError(t, svc.CheckPassword(ctx, "password123", nil))
+}
+
+func TestPasswordPolicyService_CheckPassword_Compromised(t *testing.T) {
+	svc, settings, _, breaches := newTestPasswordPolicyService("{}")
+	ctx := context.Background()
+
+	assert.Equal(t, []string{models.PasswordRuleCompromised}, policyRules(t, svc.CheckPassword(ctx, "correcthorse1", nil)))
+
+	settings.values[models.SettingPasswordPolicy] = `{"check_compromised":false}`
+	breaches.checked = 0
+	assert.NoError(t, svc.CheckPassword(ctx, "correcthorse1", nil))
+	assert.Zero(t, breaches.checked)
+}
+
+func TestPasswordPolicyService_CheckPassword_History(t *testing.T) {
+	svc, _, history, _ := newTestPasswordPolicyService("{}")
+	ctx := context.Background()
+
+	current, _ := utils.HashPassword("current-pass1", 4)
+	user := &models.User{ID: uuid.New(), PasswordHash: current}
+	for _, password := range []string{"older-pass1", "old-pass1"} {
+		hash, _ := utils.HashPassword(password, 4)
+		require.NoError(t, svc.RecordPassword(ctx, user.ID, hash))
+	}
+
+	assert.Equal(t, []string{models.PasswordRuleHistory}, policyRules(t, svc.CheckPassword(ctx, "current-pass1", user)))
+	assert.Equal(t, []string{models.PasswordRuleHistory}, policyRules(t, svc.CheckPassword(ctx, "older-pass1", user)))
+	assert.NoError(t, svc.CheckPassword(ctx, "brand-new-pass1", user))
+	assert.NoError(t, svc.CheckPassword(ctx, "current-pass1", nil), "new accounts have no history")
+
+	hash, _ := utils.HashPassword("newest-pass1", 4)
+	require.NoError(t, svc.RecordPassword(ctx, user.ID, hash))
+	assert.Len(t, history.entries, 2)
+	assert.NoError(t, svc.CheckPassword(ctx, "older-pass1", user), "entries beyond history_count are forgotten")
+}
+
+func TestPasswordPolicyService_CheckPassword_ShouldUseDefaults_WhenSettingUnreadable(t *testing.T) {
+	svc, settings, _, _ := newTestPasswordPolicyService("{}")
+	delete(settings.values, models.SettingPasswordPolicy)
+
+	assert.Equal(t, []string{models.PasswordRuleMinLength}, policyRules(t, svc.CheckPassword(context.Background(), "abc1", nil)))
+}
+
+func TestLoadPasswordDictionary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# company words\nacmecorp\n\n  widget  \n"), 0o600))
+
+	words, err := LoadPasswordDictionary(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acmecorp", "widget"}, words)
+
+	_, err = LoadPasswordDictionary(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestAuthService_ChangePassword_ShouldAuditPolicyRejection(t *testing.T) {
+	svc, mUser, _, _, mAudit, _, _, _, _ := setupAuthService()
+	policy, _, _, _ := newTestPasswordPolicyService("{}")
+	svc.SetPasswordPolicy(policy)
+	ctx := context.Background()
+
+	hash, _ := utils.HashPassword("current-pass1", 4)
+	user := &models.User{ID: uuid.New(), PasswordHash: hash, IsActive: true}
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	var audited []AuditLogParams
+	mAudit.LogFunc = func(params AuditLogParams) { audited = append(audited, params) }
+
+	err := svc.ChangePassword(ctx, user.ID, "current-pass1", "password", "1.1.1.1", "ua")
+
+	assert.Equal(t, []string{models.PasswordRuleMinLength, models.PasswordRuleNumber, models.PasswordRuleDictionary}, policyRules(t, err))
+	require.Len(t, audited, 1)
+	assert.Equal(t, models.StatusFailed, audited[0].Status)
+	assert.Equal(t, "password_policy", audited[0].Details["reason"])
+}
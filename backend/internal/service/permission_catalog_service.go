@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// maxPermissionCatalogEntries limits how many entries a single service can register
+const maxPermissionCatalogEntries = 1000
+
+var (
+	errCatalogSourceRequired = models.NewAppError(http.StatusBadRequest, "service name is required")
+	errCatalogSourceTooLong  = models.NewAppError(http.StatusBadRequest, "service name must be at most 100 characters")
+	errCatalogTooManyEntries = models.NewAppError(http.StatusBadRequest, fmt.Sprintf("at most %d entries can be registered per service", maxPermissionCatalogEntries))
+)
+
+// PermissionCatalogService builds the permissions catalog from the RBAC registry and the
+// resources services registered themselves
+type PermissionCatalogService struct {
+	rbacRepo PermissionRepository
+	store    PermissionCatalogStore
+	logger   *logger.Logger
+}
+
+// NewPermissionCatalogService creates a new permission catalog service
+func NewPermissionCatalogService(rbacRepo PermissionRepository, store PermissionCatalogStore, log *logger.Logger) *PermissionCatalogService {
+	return &PermissionCatalogService{
+		rbacRepo: rbacRepo,
+		store:    store,
+		logger:   log,
+	}
+}
+
+type catalogKey struct {
+	appID    uuid.UUID
+	resource string
+	action   string
+}
+
+// GetCatalog returns every known resource/action pair with the roles that grant it. Pairs
+// registered by services are merged into the global permission with the same resource and
+// action, if there is one.
+func (s *PermissionCatalogService) GetCatalog(ctx context.Context) (*models.PermissionCatalogResponse, error) {
+	permissions, err := s.rbacRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.store.ListEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.store.ListGrants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesByPermission := make(map[uuid.UUID][]models.PermissionCatalogRole)
+	for _, grant := range grants {
+		rolesByPermission[grant.PermissionID] = append(rolesByPermission[grant.PermissionID], models.PermissionCatalogRole{
+			ID:          grant.RoleID,
+			Name:        grant.RoleName,
+			DisplayName: grant.RoleDisplayName,
+		})
+	}
+
+	items := make(map[catalogKey]*models.PermissionCatalogItem)
+	for _, perm := range permissions {
+		key := catalogKey{resource: perm.Resource, action: perm.Action}
+		if perm.ApplicationID != nil {
+			key.appID = *perm.ApplicationID
+		}
+		if _, exists := items[key]; exists {
+			continue
+		}
+
+		id := perm.ID
+		roles := rolesByPermission[perm.ID]
+		if roles == nil {
+			roles = []models.PermissionCatalogRole{}
+		}
+		items[key] = &models.PermissionCatalogItem{
+			Resource:      perm.Resource,
+			Action:        perm.Action,
+			ApplicationID: perm.ApplicationID,
+			Permission:    perm.Name,
+			PermissionID:  &id,
+			Description:   perm.Description,
+			Sources:       []string{models.PermissionCatalogSourceRegistry},
+			GrantedBy:     roles,
+		}
+	}
+
+	for _, entry := range entries {
+		key := catalogKey{resource: entry.Resource, action: entry.Action}
+		item, exists := items[key]
+		if !exists {
+			item = &models.PermissionCatalogItem{
+				Resource:  entry.Resource,
+				Action:    entry.Action,
+				Sources:   []string{},
+				GrantedBy: []models.PermissionCatalogRole{},
+			}
+			items[key] = item
+		}
+		if item.Description == "" {
+			item.Description = entry.Description
+		}
+		item.Sources = append(item.Sources, entry.Source)
+	}
+
+	result := make([]models.PermissionCatalogItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		return a.ApplicationID == nil && b.ApplicationID != nil
+	})
+
+	return &models.PermissionCatalogResponse{Items: result, Total: len(result)}, nil
+}
+
+// RegisterEntries replaces the entries previously registered by source and returns how many
+// distinct entries were stored
+func (s *PermissionCatalogService) RegisterEntries(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return 0, errCatalogSourceRequired
+	}
+	if len(source) > 100 {
+		return 0, errCatalogSourceTooLong
+	}
+	if len(entries) > maxPermissionCatalogEntries {
+		return 0, errCatalogTooManyEntries
+	}
+
+	seen := make(map[catalogKey]bool, len(entries))
+	records := make([]*models.PermissionCatalogEntry, 0, len(entries))
+	for _, entry := range entries {
+		resource := strings.TrimSpace(entry.Resource)
+		action := strings.TrimSpace(entry.Action)
+		if err := validateCatalogPart("resource", resource); err != nil {
+			return 0, err
+		}
+		if err := validateCatalogPart("action", action); err != nil {
+			return 0, err
+		}
+
+		key := catalogKey{resource: resource, action: action}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, &models.PermissionCatalogEntry{
+			ID:          uuid.New(),
+			Source:      source,
+			Resource:    resource,
+			Action:      action,
+			Description: strings.TrimSpace(entry.Description),
+		})
+	}
+
+	if err := s.store.ReplaceEntries(ctx, source, records); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("permission catalog entries registered", map[string]interface{}{
+		"source":  source,
+		"entries": len(records),
+	})
+
+	return len(records), nil
+}
+
+// validateCatalogPart applies the length limits of CreatePermissionRequest to a resource or action
+func validateCatalogPart(field, value string) error {
+	if len(value) < 2 || len(value) > 50 {
+		return models.NewAppError(http.StatusBadRequest, fmt.Sprintf("%s must be 2-50 characters", field))
+	}
+	if strings.ContainsAny(value, " \t\n") {
+		return models.NewAppError(http.StatusBadRequest, fmt.Sprintf("%s must not contain whitespace", field))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPermissionCatalogStore struct {
+	entries map[string][]*models.PermissionCatalogEntry
+	grants  []*models.PermissionGrant
+}
+
+func (m *mockPermissionCatalogStore) ReplaceEntries(ctx context.Context, source string, entries []*models.PermissionCatalogEntry) error {
+	m.entries[source] = entries
+	return nil
+}
+
+func (m *mockPermissionCatalogStore) ListEntries(ctx context.Context) ([]*models.PermissionCatalogEntry, error) {
+	var all []*models.PermissionCatalogEntry
+	for _, entries := range m.entries {
+		all = append(all, entries...)
+	}
+	return all, nil
+}
+
+func (m *mockPermissionCatalogStore) ListGrants(ctx context.Context) ([]*models.PermissionGrant, error) {
+	return m.grants, nil
+}
+
+func TestPermissionCatalog_ShouldMergeRegistryAndServiceEntries(t *testing.T) {
+	usersRead := models.Permission{ID: uuid.New(), Name: "users.read", Resource: "users", Action: "read", Description: "Read users"}
+	appID := uuid.New()
+	appUsersRead := models.Permission{ID: uuid.New(), Name: "users.read", Resource: "users", Action: "read", ApplicationID: &appID}
+	adminRole := uuid.New()
+
+	rbac := &mockRBACStore{
+		ListPermissionsFunc: func(ctx context.Context) ([]models.Permission, error) {
+			return []models.Permission{appUsersRead, usersRead}, nil
+		},
+	}
+	store := &mockPermissionCatalogStore{
+		entries: make(map[string][]*models.PermissionCatalogEntry),
+		grants: []*models.PermissionGrant{
+			{PermissionID: usersRead.ID, RoleID: adminRole, RoleName: "admin", RoleDisplayName: "Administrator"},
+		},
+	}
+	svc := NewPermissionCatalogService(rbac, store, logger.New("test", logger.DebugLevel, false))
+
+	n, err := svc.RegisterEntries(context.Background(), "billing-service", []models.PermissionCatalogEntry{
+		{Resource: "invoices", Action: "refund", Description: "Refund a paid invoice"},
+		{Resource: "users", Action: "read", Description: "ignored, registry has one"},
+		{Resource: "invoices", Action: "refund"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	catalog, err := svc.GetCatalog(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, catalog.Total)
+
+	refund := catalog.Items[0]
+	assert.Equal(t, "invoices", refund.Resource)
+	assert.Empty(t, refund.Permission)
+	assert.Equal(t, "Refund a paid invoice", refund.Description)
+	assert.Equal(t, []string{"billing-service"}, refund.Sources)
+	assert.Empty(t, refund.GrantedBy)
+
+	global := catalog.Items[1]
+	assert.Nil(t, global.ApplicationID)
+	assert.Equal(t, "Read users", global.Description)
+	assert.Equal(t, []string{models.PermissionCatalogSourceRegistry, "billing-service"}, global.Sources)
+	require.Len(t, global.GrantedBy, 1)
+	assert.Equal(t, "admin", global.GrantedBy[0].Name)
+
+	app := catalog.Items[2]
+	assert.Equal(t, &appID, app.ApplicationID)
+	assert.Equal(t, []string{models.PermissionCatalogSourceRegistry}, app.Sources)
+}
+
+func TestPermissionCatalog_ShouldReplacePreviousEntriesOfService(t *testing.T) {
+	store := &mockPermissionCatalogStore{entries: make(map[string][]*models.PermissionCatalogEntry)}
+	svc := NewPermissionCatalogService(&mockRBACStore{}, store, logger.New("test", logger.DebugLevel, false))
+
+	_, err := svc.RegisterEntries(context.Background(), "billing-service", []models.PermissionCatalogEntry{{Resource: "invoices", Action: "read"}})
+	require.NoError(t, err)
+	_, err = svc.RegisterEntries(context.Background(), "billing-service", []models.PermissionCatalogEntry{{Resource: "payments", Action: "read"}})
+	require.NoError(t, err)
+
+	catalog, err := svc.GetCatalog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, catalog.Items, 1)
+	assert.Equal(t, "payments", catalog.Items[0].Resource)
+}
+
+func TestPermissionCatalog_ShouldRejectInvalidEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		entries []models.PermissionCatalogEntry
+	}{
+		{"missing service", " ", []models.PermissionCatalogEntry{{Resource: "invoices", Action: "read"}}},
+		{"long service", strings.Repeat("s", 101), nil},
+		{"short resource", "billing", []models.PermissionCatalogEntry{{Resource: "i", Action: "read"}}},
+		{"action with space", "billing", []models.PermissionCatalogEntry{{Resource: "invoices", Action: "read all"}}},
+		{"too many", "billing", make([]models.PermissionCatalogEntry, maxPermissionCatalogEntries+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockPermissionCatalogStore{entries: make(map[string][]*models.PermissionCatalogEntry)}
+			svc := NewPermissionCatalogService(&mockRBACStore{}, store, logger.New("test", logger.DebugLevel, false))
+
+			_, err := svc.RegisterEntries(context.Background(), tt.source, tt.entries)
+
+			var appErr *models.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.Code)
+			assert.Empty(t, store.entries)
+		})
+	}
+}
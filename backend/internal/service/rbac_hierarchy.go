@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// ErrRoleInheritanceCycle is returned when a role would end up inheriting from itself
+var ErrRoleInheritanceCycle = errors.New("role inheritance cycle")
+
+// ============================================================
+// Role Inheritance
+// ============================================================
+
+// validateRoleParents checks the roles a role is about to inherit from: each must exist, be
+// global or belong to the application of the role, and not already inherit from the role
+func (s *RBACService) validateRoleParents(ctx context.Context, role *models.Role, parentIDs []uuid.UUID) error {
+	parents := make(map[uuid.UUID]*models.Role, len(parentIDs))
+	for _, parentID := range parentIDs {
+		if parentID == role.ID {
+			return fmt.Errorf("%w: role %s cannot inherit from itself", ErrRoleInheritanceCycle, role.Name)
+		}
+		parent, err := s.rbacRepo.GetRoleByID(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("parent role %s not found", parentID)
+		}
+		if parent.ApplicationID != nil && (role.ApplicationID == nil || *parent.ApplicationID != *role.ApplicationID) {
+			return fmt.Errorf("role %s belongs to another application and cannot be inherited from", parent.Name)
+		}
+		parents[parentID] = parent
+	}
+
+	// A new role has no ID yet, so nothing can inherit from it
+	if role.ID == uuid.Nil {
+		return nil
+	}
+
+	links, err := s.rbacRepo.ListRoleParents(ctx)
+	if err != nil {
+		return err
+	}
+	graph := parentGraph(links)
+	for _, parentID := range parentIDs {
+		if inheritsFrom(graph, parentID, role.ID) {
+			return fmt.Errorf("%w: %s already inherits from %s", ErrRoleInheritanceCycle, parents[parentID].Name, role.Name)
+		}
+	}
+	return nil
+}
+
+// validateRoleBundles checks that the bundles about to be granted to a role exist
+func (s *RBACService) validateRoleBundles(ctx context.Context, bundleIDs []uuid.UUID) error {
+	for _, bundleID := range bundleIDs {
+		if _, err := s.rbacRepo.GetPermissionBundleByID(ctx, bundleID); err != nil {
+			return fmt.Errorf("permission bundle %s not found", bundleID)
+		}
+	}
+	return nil
+}
+
+// parentGraph maps each role to the roles it directly inherits from
+func parentGraph(links []models.RoleParent) map[uuid.UUID][]uuid.UUID {
+	graph := make(map[uuid.UUID][]uuid.UUID)
+	for _, link := range links {
+		graph[link.RoleID] = append(graph[link.RoleID], link.ParentRoleID)
+	}
+	return graph
+}
+
+// inheritsFrom reports whether role inherits from ancestor, directly or through its parents
+func inheritsFrom(graph map[uuid.UUID][]uuid.UUID, role, ancestor uuid.UUID) bool {
+	for _, inherited := range inheritedRoleIDs(graph, role) {
+		if inherited.id == ancestor {
+			return true
+		}
+	}
+	return role == ancestor
+}
+
+type inheritedRoleID struct {
+	id    uuid.UUID
+	depth int
+}
+
+// inheritedRoleIDs returns the roles a role inherits from, nearest first. Each role is
+// listed once, at the depth it is first reached, so a cycle in the graph ends the walk.
+func inheritedRoleIDs(graph map[uuid.UUID][]uuid.UUID, role uuid.UUID) []inheritedRoleID {
+	visited := map[uuid.UUID]bool{role: true}
+	var inherited []inheritedRoleID
+	queue := []inheritedRoleID{{id: role}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, parentID := range graph[current.id] {
+			if visited[parentID] {
+				continue
+			}
+			visited[parentID] = true
+			parent := inheritedRoleID{id: parentID, depth: current.depth + 1}
+			inherited = append(inherited, parent)
+			queue = append(queue, parent)
+		}
+	}
+	return inherited
+}
+
+// GetEffectivePermissions resolves the permissions a role grants: its own, those of its
+// bundles and those of every role it inherits from, with every way the role gets each one
+func (s *RBACService) GetEffectivePermissions(ctx context.Context, roleID uuid.UUID) (*models.EffectivePermissionsResponse, error) {
+	role, err := s.rbacRepo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.rbacRepo.ListRoleParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleBundleLinks, err := s.rbacRepo.ListRoleBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roleBundles := make(map[uuid.UUID][]uuid.UUID)
+	for _, link := range roleBundleLinks {
+		roleBundles[link.RoleID] = append(roleBundles[link.RoleID], link.BundleID)
+	}
+	var bundles map[uuid.UUID]*models.PermissionBundle
+	if len(roleBundleLinks) > 0 {
+		list, err := s.rbacRepo.ListPermissionBundles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		bundles = make(map[uuid.UUID]*models.PermissionBundle, len(list))
+		for i := range list {
+			bundles[list[i].ID] = &list[i]
+		}
+	}
+
+	resp := &models.EffectivePermissionsResponse{
+		RoleID:         role.ID,
+		RoleName:       role.Name,
+		InheritedRoles: []models.InheritedRole{},
+		Permissions:    []models.EffectivePermission{},
+	}
+	index := make(map[uuid.UUID]int)
+	grant := func(permission models.Permission, source models.PermissionSource) {
+		i, ok := index[permission.ID]
+		if !ok {
+			i = len(resp.Permissions)
+			index[permission.ID] = i
+			resp.Permissions = append(resp.Permissions, models.EffectivePermission{Permission: permission})
+		}
+		resp.Permissions[i].Sources = append(resp.Permissions[i].Sources, source)
+	}
+	collect := func(from *models.Role, inherited bool) {
+		for _, permission := range from.Permissions {
+			grant(permission, models.PermissionSource{
+				Type:      models.PermissionSourceDirect,
+				RoleID:    from.ID,
+				RoleName:  from.Name,
+				Inherited: inherited,
+			})
+		}
+		for _, bundleID := range roleBundles[from.ID] {
+			bundle, ok := bundles[bundleID]
+			if !ok {
+				continue
+			}
+			for _, permission := range bundle.Permissions {
+				grant(permission, models.PermissionSource{
+					Type:       models.PermissionSourceBundle,
+					RoleID:     from.ID,
+					RoleName:   from.Name,
+					Inherited:  inherited,
+					BundleID:   &bundle.ID,
+					BundleName: bundle.Name,
+				})
+			}
+		}
+	}
+
+	collect(role, false)
+	for _, inherited := range inheritedRoleIDs(parentGraph(links), role.ID) {
+		ancestor, err := s.rbacRepo.GetRoleByID(ctx, inherited.id)
+		if err != nil {
+			return nil, err
+		}
+		resp.InheritedRoles = append(resp.InheritedRoles, models.InheritedRole{
+			ID:    ancestor.ID,
+			Name:  ancestor.Name,
+			Depth: inherited.depth,
+		})
+		collect(ancestor, true)
+	}
+
+	sort.SliceStable(resp.Permissions, func(i, j int) bool {
+		a, b := resp.Permissions[i], resp.Permissions[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Action < b.Action
+	})
+	resp.Total = len(resp.Permissions)
+	return resp, nil
+}
+
+// ============================================================
+// Permission Bundles
+// ============================================================
+
+// CreatePermissionBundle creates a new permission bundle
+func (s *RBACService) CreatePermissionBundle(ctx context.Context, req *models.CreatePermissionBundleRequest) (*models.PermissionBundle, error) {
+	existing, err := s.rbacRepo.GetPermissionBundleByName(ctx, req.Name)
+	if err == nil && existing != nil {
+		return nil, fmt.Errorf("permission bundle with name %s already exists", req.Name)
+	}
+
+	bundle := &models.PermissionBundle{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+	}
+	if err := s.rbacRepo.CreatePermissionBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	if len(req.Permissions) > 0 {
+		if err := s.rbacRepo.SetBundlePermissions(ctx, bundle.ID, req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.rbacRepo.GetPermissionBundleByID(ctx, bundle.ID)
+}
+
+// GetPermissionBundle retrieves a permission bundle by ID
+func (s *RBACService) GetPermissionBundle(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error) {
+	return s.rbacRepo.GetPermissionBundleByID(ctx, id)
+}
+
+// ListPermissionBundles retrieves all permission bundles
+func (s *RBACService) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	return s.rbacRepo.ListPermissionBundles(ctx)
+}
+
+// UpdatePermissionBundle updates a permission bundle, and its permissions when given
+func (s *RBACService) UpdatePermissionBundle(ctx context.Context, id uuid.UUID, req *models.UpdatePermissionBundleRequest) (*models.PermissionBundle, error) {
+	if err := s.rbacRepo.UpdatePermissionBundle(ctx, id, req.DisplayName, req.Description); err != nil {
+		return nil, err
+	}
+
+	if req.Permissions != nil {
+		if err := s.rbacRepo.SetBundlePermissions(ctx, id, req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.rbacRepo.GetPermissionBundleByID(ctx, id)
+}
+
+// DeletePermissionBundle deletes a permission bundle; roles it was granted to lose its permissions
+func (s *RBACService) DeletePermissionBundle(ctx context.Context, id uuid.UUID) error {
+	return s.rbacRepo.DeletePermissionBundle(ctx, id)
+}
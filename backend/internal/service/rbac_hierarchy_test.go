@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoleHierarchyStore serves the given roles and inheritance links from a mock store
+func newRoleHierarchyStore(roles []*models.Role, links []models.RoleParent) *mockRBACStore {
+	byID := make(map[uuid.UUID]*models.Role, len(roles))
+	for _, role := range roles {
+		byID[role.ID] = role
+	}
+	return &mockRBACStore{
+		GetRoleByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+			role, ok := byID[id]
+			if !ok {
+				return nil, errors.New("role not found")
+			}
+			return role, nil
+		},
+		ListRoleParentsFunc: func(ctx context.Context) ([]models.RoleParent, error) {
+			return links, nil
+		},
+	}
+}
+
+func TestRBACService_UpdateRole_ParentRoles(t *testing.T) {
+	ctx := context.Background()
+	viewer := &models.Role{ID: uuid.New(), Name: "viewer"}
+	editor := &models.Role{ID: uuid.New(), Name: "editor"}
+	admin := &models.Role{ID: uuid.New(), Name: "admin"}
+	// admin inherits from editor, editor inherits from viewer
+	links := []models.RoleParent{
+		{RoleID: admin.ID, ParentRoleID: editor.ID},
+		{RoleID: editor.ID, ParentRoleID: viewer.ID},
+	}
+
+	t.Run("RejectsSelfInheritance", func(t *testing.T) {
+		store := newRoleHierarchyStore([]*models.Role{viewer, editor, admin}, links)
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		_, err := svc.UpdateRole(ctx, viewer.ID, &models.UpdateRoleRequest{ParentRoles: []uuid.UUID{viewer.ID}})
+		assert.ErrorIs(t, err, ErrRoleInheritanceCycle)
+	})
+
+	t.Run("RejectsIndirectCycle", func(t *testing.T) {
+		store := newRoleHierarchyStore([]*models.Role{viewer, editor, admin}, links)
+		updated := false
+		store.UpdateRoleFunc = func(ctx context.Context, id uuid.UUID, displayName, description string) error {
+			updated = true
+			return nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		_, err := svc.UpdateRole(ctx, viewer.ID, &models.UpdateRoleRequest{ParentRoles: []uuid.UUID{admin.ID}})
+		assert.ErrorIs(t, err, ErrRoleInheritanceCycle)
+		assert.Contains(t, err.Error(), "admin already inherits from viewer")
+		assert.False(t, updated, "role must not be changed when its parents are rejected")
+	})
+
+	t.Run("RejectsParentOfAnotherApplication", func(t *testing.T) {
+		appID := uuid.New()
+		appRole := &models.Role{ID: uuid.New(), Name: "app-role", ApplicationID: &appID}
+		store := newRoleHierarchyStore([]*models.Role{viewer, appRole}, nil)
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		_, err := svc.UpdateRole(ctx, viewer.ID, &models.UpdateRoleRequest{ParentRoles: []uuid.UUID{appRole.ID}})
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrRoleInheritanceCycle)
+	})
+
+	t.Run("SetsParents", func(t *testing.T) {
+		store := newRoleHierarchyStore([]*models.Role{viewer, editor, admin}, links)
+		var setParents []uuid.UUID
+		store.SetRoleParentsFunc = func(ctx context.Context, roleID uuid.UUID, parentIDs []uuid.UUID) error {
+			assert.Equal(t, admin.ID, roleID)
+			setParents = parentIDs
+			return nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		_, err := svc.UpdateRole(ctx, admin.ID, &models.UpdateRoleRequest{ParentRoles: []uuid.UUID{viewer.ID}})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{viewer.ID}, setParents)
+	})
+}
+
+func TestRBACService_GetEffectivePermissions(t *testing.T) {
+	ctx := context.Background()
+	read := models.Permission{ID: uuid.New(), Name: "posts.read", Resource: "posts", Action: "read"}
+	write := models.Permission{ID: uuid.New(), Name: "posts.write", Resource: "posts", Action: "write"}
+	manage := models.Permission{ID: uuid.New(), Name: "users.manage", Resource: "users", Action: "manage"}
+
+	bundle := models.PermissionBundle{ID: uuid.New(), Name: "publishing", Permissions: []models.Permission{read, write}}
+	viewer := &models.Role{ID: uuid.New(), Name: "viewer", Permissions: []models.Permission{read}}
+	editor := &models.Role{ID: uuid.New(), Name: "editor"}
+	admin := &models.Role{ID: uuid.New(), Name: "admin", Permissions: []models.Permission{manage}}
+	// A cycle left in the table must not hang resolution
+	links := []models.RoleParent{
+		{RoleID: admin.ID, ParentRoleID: editor.ID},
+		{RoleID: editor.ID, ParentRoleID: viewer.ID},
+		{RoleID: viewer.ID, ParentRoleID: admin.ID},
+	}
+
+	store := newRoleHierarchyStore([]*models.Role{viewer, editor, admin}, links)
+	store.ListRoleBundlesFunc = func(ctx context.Context) ([]models.RoleBundle, error) {
+		return []models.RoleBundle{{RoleID: editor.ID, BundleID: bundle.ID}}, nil
+	}
+	store.ListPermissionBundlesFunc = func(ctx context.Context) ([]models.PermissionBundle, error) {
+		return []models.PermissionBundle{bundle}, nil
+	}
+	svc := NewRBACService(store, &mockAuditLogger{})
+
+	resp, err := svc.GetEffectivePermissions(ctx, admin.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, admin.ID, resp.RoleID)
+	assert.Equal(t, []models.InheritedRole{
+		{ID: editor.ID, Name: "editor", Depth: 1},
+		{ID: viewer.ID, Name: "viewer", Depth: 2},
+	}, resp.InheritedRoles)
+
+	require.Equal(t, 3, resp.Total)
+	require.Len(t, resp.Permissions, 3)
+	assert.Equal(t, "posts.read", resp.Permissions[0].Name)
+	assert.Equal(t, "posts.write", resp.Permissions[1].Name)
+	assert.Equal(t, "users.manage", resp.Permissions[2].Name)
+
+	// posts.read comes both from the editor's bundle and from viewer directly
+	readSources := resp.Permissions[0].Sources
+	require.Len(t, readSources, 2)
+	assert.Equal(t, models.PermissionSourceBundle, readSources[0].Type)
+	assert.Equal(t, editor.ID, readSources[0].RoleID)
+	assert.Equal(t, "publishing", readSources[0].BundleName)
+	assert.True(t, readSources[0].Inherited)
+	assert.Equal(t, models.PermissionSourceDirect, readSources[1].Type)
+	assert.Equal(t, viewer.ID, readSources[1].RoleID)
+
+	manageSources := resp.Permissions[2].Sources
+	require.Len(t, manageSources, 1)
+	assert.Equal(t, models.PermissionSourceDirect, manageSources[0].Type)
+	assert.False(t, manageSources[0].Inherited)
+}
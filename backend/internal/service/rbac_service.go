@@ -5,12 +5,15 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/abac"
 	"github.com/smilemakc/auth-gateway/internal/models"
 )
 
 type RBACService struct {
 	rbacRepo     RBACStore
 	auditService AuditLogger
+	users        UserStore
+	events       EventPublisher
 }
 
 func NewRBACService(rbacRepo RBACStore, auditService AuditLogger) *RBACService {
@@ -20,6 +23,26 @@ func NewRBACService(rbacRepo RBACStore, auditService AuditLogger) *RBACService {
 	}
 }
 
+// SetUserStore lets permission simulations read the user.* attributes of conditions
+func (s *RBACService) SetUserStore(users UserStore) {
+	s.users = users
+}
+
+// SetEventBus publishes role assignment changes to events
+func (s *RBACService) SetEventBus(events EventPublisher) {
+	s.events = events
+}
+
+// publishRoleChange publishes a change of the roles of a user to the event bus, if one is
+// configured; the event bus logs failures
+func (s *RBACService) publishRoleChange(ctx context.Context, userID uuid.UUID, data map[string]interface{}) {
+	if s.events == nil {
+		return
+	}
+	data["user_id"] = userID.String()
+	_ = s.events.PublishEvent(context.WithoutCancel(ctx), models.EventUserRoleChanged, userID.String(), data)
+}
+
 // ============================================================
 // Permission Methods
 // ============================================================
@@ -31,12 +54,16 @@ func (s *RBACService) CreatePermission(ctx context.Context, req *models.CreatePe
 	if err == nil && existing != nil {
 		return nil, fmt.Errorf("permission with name %s already exists", req.Name)
 	}
+	if err := validateCondition(req.Condition); err != nil {
+		return nil, err
+	}
 
 	permission := &models.Permission{
 		Name:        req.Name,
 		Resource:    req.Resource,
 		Action:      req.Action,
 		Description: req.Description,
+		Condition:   req.Condition,
 	}
 
 	err = s.rbacRepo.CreatePermission(ctx, permission)
@@ -59,7 +86,23 @@ func (s *RBACService) ListPermissions(ctx context.Context) ([]models.Permission,
 
 // UpdatePermission updates a permission
 func (s *RBACService) UpdatePermission(ctx context.Context, id uuid.UUID, req *models.UpdatePermissionRequest) error {
-	return s.rbacRepo.UpdatePermission(ctx, id, req.Description)
+	if req.Condition != nil {
+		if err := validateCondition(*req.Condition); err != nil {
+			return err
+		}
+	}
+	return s.rbacRepo.UpdatePermission(ctx, id, req.Description, req.Condition)
+}
+
+// validateCondition checks that a permission condition parses; empty means unconditional
+func validateCondition(condition string) error {
+	if condition == "" {
+		return nil
+	}
+	if _, err := abac.Parse(condition); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+	return nil
 }
 
 // DeletePermission deletes a permission
@@ -86,6 +129,13 @@ func (s *RBACService) CreateRole(ctx context.Context, req *models.CreateRoleRequ
 		IsSystemRole: false,
 	}
 
+	if err := s.validateRoleParents(ctx, role, req.ParentRoles); err != nil {
+		return nil, err
+	}
+	if err := s.validateRoleBundles(ctx, req.Bundles); err != nil {
+		return nil, err
+	}
+
 	err = s.rbacRepo.CreateRole(ctx, role)
 	if err != nil {
 		return nil, err
@@ -99,6 +149,17 @@ func (s *RBACService) CreateRole(ctx context.Context, req *models.CreateRoleRequ
 		}
 	}
 
+	if len(req.ParentRoles) > 0 {
+		if err := s.rbacRepo.SetRoleParents(ctx, role.ID, req.ParentRoles); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Bundles) > 0 {
+		if err := s.rbacRepo.SetRoleBundles(ctx, role.ID, req.Bundles); err != nil {
+			return nil, err
+		}
+	}
+
 	// Fetch role with permissions
 	return s.rbacRepo.GetRoleByID(ctx, role.ID)
 }
@@ -120,6 +181,19 @@ func (s *RBACService) ListRoles(ctx context.Context) ([]models.Role, error) {
 
 // UpdateRole updates a role
 func (s *RBACService) UpdateRole(ctx context.Context, id uuid.UUID, req *models.UpdateRoleRequest) (*models.Role, error) {
+	if req.ParentRoles != nil || req.Bundles != nil {
+		role, err := s.rbacRepo.GetRoleByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateRoleParents(ctx, role, req.ParentRoles); err != nil {
+			return nil, err
+		}
+		if err := s.validateRoleBundles(ctx, req.Bundles); err != nil {
+			return nil, err
+		}
+	}
+
 	err := s.rbacRepo.UpdateRole(ctx, id, req.DisplayName, req.Description)
 	if err != nil {
 		return nil, err
@@ -133,6 +207,18 @@ func (s *RBACService) UpdateRole(ctx context.Context, id uuid.UUID, req *models.
 		}
 	}
 
+	// Update inheritance and bundles if provided
+	if req.ParentRoles != nil {
+		if err := s.rbacRepo.SetRoleParents(ctx, id, req.ParentRoles); err != nil {
+			return nil, err
+		}
+	}
+	if req.Bundles != nil {
+		if err := s.rbacRepo.SetRoleBundles(ctx, id, req.Bundles); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.rbacRepo.GetRoleByID(ctx, id)
 }
 
@@ -204,6 +290,12 @@ func (s *RBACService) AssignRoleToUser(ctx context.Context, userID, roleID, assi
 		Status:  models.StatusSuccess,
 		Details: details,
 	})
+	s.publishRoleChange(ctx, userID, map[string]interface{}{
+		"change":      "assigned",
+		"role_id":     roleID.String(),
+		"role_name":   role.Name,
+		"assigned_by": assignedBy.String(),
+	})
 
 	return nil
 }
@@ -239,6 +331,11 @@ func (s *RBACService) RemoveRoleFromUser(ctx context.Context, userID, roleID uui
 		Status:  models.StatusSuccess,
 		Details: details,
 	})
+	s.publishRoleChange(ctx, userID, map[string]interface{}{
+		"change":    "removed",
+		"role_id":   roleID.String(),
+		"role_name": role.Name,
+	})
 
 	return nil
 }
@@ -302,6 +399,12 @@ func (s *RBACService) SetUserRoles(ctx context.Context, userID uuid.UUID, roleID
 		Status:  models.StatusSuccess,
 		Details: details,
 	})
+	s.publishRoleChange(ctx, userID, map[string]interface{}{
+		"change":         "replaced",
+		"previous_roles": previousRoleNames,
+		"new_roles":      newRoleNames,
+		"assigned_by":    assignedBy.String(),
+	})
 
 	return nil
 }
@@ -404,6 +507,13 @@ func (s *RBACService) AssignRoleToUserInApp(ctx context.Context, userID, roleID,
 		Status:  models.StatusSuccess,
 		Details: details,
 	})
+	s.publishRoleChange(ctx, userID, map[string]interface{}{
+		"change":         "assigned",
+		"role_id":        roleID.String(),
+		"role_name":      role.Name,
+		"assigned_by":    assignedBy.String(),
+		"application_id": uuidPtrToString(appID),
+	})
 
 	return nil
 }
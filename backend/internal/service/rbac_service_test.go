@@ -61,6 +61,17 @@ func TestRBACService_CreatePermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "already exists")
 	})
 
+	t.Run("InvalidCondition", func(t *testing.T) {
+		req := &models.CreatePermissionRequest{Name: "documents:edit_own", Resource: "documents", Action: "edit", Condition: "resource.owner_id =="}
+		mockRBAC.GetPermissionByNameFunc = func(ctx context.Context, name string) (*models.Permission, error) {
+			return nil, nil
+		}
+
+		p, err := svc.CreatePermission(ctx, req)
+		assert.Nil(t, p)
+		assert.ErrorContains(t, err, "invalid condition")
+	})
+
 	t.Run("CreateFails", func(t *testing.T) {
 		req := &models.CreatePermissionRequest{
 			Name:     "new-perm",
@@ -166,9 +177,10 @@ func TestRBACService_UpdatePermission(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		req := &models.UpdatePermissionRequest{Description: "updated desc"}
-		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string) error {
+		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string, condition *string) error {
 			assert.Equal(t, permID, id)
 			assert.Equal(t, "updated desc", description)
+			assert.Nil(t, condition)
 			return nil
 		}
 
@@ -178,13 +190,38 @@ func TestRBACService_UpdatePermission(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		req := &models.UpdatePermissionRequest{Description: "updated"}
-		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string) error {
+		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string, condition *string) error {
 			return errors.New("not found")
 		}
 
 		err := svc.UpdatePermission(ctx, permID, req)
 		assert.Error(t, err)
 	})
+
+	t.Run("Condition", func(t *testing.T) {
+		condition := "resource.owner_id == user.id"
+		req := &models.UpdatePermissionRequest{Description: "own documents", Condition: &condition}
+		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string, got *string) error {
+			require.NotNil(t, got)
+			assert.Equal(t, condition, *got)
+			return nil
+		}
+
+		err := svc.UpdatePermission(ctx, permID, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("InvalidCondition", func(t *testing.T) {
+		condition := "owner_id = user.id"
+		req := &models.UpdatePermissionRequest{Condition: &condition}
+		mockRBAC.UpdatePermissionFunc = func(ctx context.Context, id uuid.UUID, description string, condition *string) error {
+			t.Fatal("invalid condition must not be stored")
+			return nil
+		}
+
+		err := svc.UpdatePermission(ctx, permID, req)
+		assert.ErrorContains(t, err, "invalid condition")
+	})
 }
 
 func TestRBACService_DeletePermission(t *testing.T) {
@@ -647,6 +684,44 @@ func TestRBACService_AssignRoleToUser(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to assign role")
 	})
+
+	t.Run("PublishesRoleChange", func(t *testing.T) {
+		mockRBAC.GetRoleByIDFunc = func(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "editor"}, nil
+		}
+		mockRBAC.AssignRoleToUserFunc = func(ctx context.Context, uid, rid, ab uuid.UUID) error {
+			return nil
+		}
+		mockAudit.LogFunc = nil
+		events := &recordingEventPublisher{}
+		svc.SetEventBus(events)
+		defer svc.SetEventBus(nil)
+
+		err := svc.AssignRoleToUser(ctx, userID, roleID, adminID)
+		require.NoError(t, err)
+
+		require.Len(t, events.events, 1)
+		assert.Equal(t, models.EventUserRoleChanged, events.events[0].eventType)
+		assert.Equal(t, userID.String(), events.events[0].key)
+		assert.Equal(t, "assigned", events.events[0].data["change"])
+		assert.Equal(t, "editor", events.events[0].data["role_name"])
+	})
+}
+
+// recordedEvent is an event published through recordingEventPublisher
+type recordedEvent struct {
+	eventType, key string
+	data           map[string]interface{}
+}
+
+// recordingEventPublisher records the events published through it
+type recordingEventPublisher struct {
+	events []recordedEvent
+}
+
+func (r *recordingEventPublisher) PublishEvent(ctx context.Context, eventType, key string, data map[string]interface{}) error {
+	r.events = append(r.events, recordedEvent{eventType: eventType, key: key, data: data})
+	return nil
 }
 
 func TestRBACService_RemoveRoleFromUser(t *testing.T) {
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/abac"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// SimulatePermission evaluates a resource/action check for a user the way the gRPC
+// CheckPermission method does and records every role and condition it looked at. When an
+// application is given only the roles assigned in that application are considered.
+func (s *RBACService) SimulatePermission(ctx context.Context, req *models.RBACSimulationRequest) (*models.RBACSimulationResponse, error) {
+	resp := &models.RBACSimulationResponse{
+		Decision:      models.RBACDecisionDeny,
+		Roles:         make([]models.RBACSimulationRole, 0),
+		Steps:         make([]models.RBACSimulationStep, 0),
+		GrantingRoles: make([]string, 0),
+		Context:       req.Context,
+	}
+	step := func(name, status, format string, args ...interface{}) {
+		resp.Steps = append(resp.Steps, models.RBACSimulationStep{
+			Name:   name,
+			Status: status,
+			Detail: fmt.Sprintf(format, args...),
+		})
+	}
+	target := req.Resource + ":" + req.Action
+
+	assigned, err := s.rbacRepo.GetUserRoles(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+	considered := assigned
+	if req.ApplicationID != nil {
+		considered, err = s.rbacRepo.GetUserRolesInApp(ctx, req.UserID, req.ApplicationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user roles in application: %w", err)
+		}
+		step("application", models.FlowStepPassed, "check is scoped to application %s, %d of the user's %d roles are assigned in it",
+			req.ApplicationID, len(considered), len(assigned))
+	} else {
+		step("application", models.FlowStepSkipped, "no application given, every role assigned to the user is considered")
+	}
+
+	permissions, err := s.rbacRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	var defined []string
+	for _, perm := range permissions {
+		if perm.Resource == req.Resource && perm.Action == req.Action {
+			defined = append(defined, perm.Name)
+		}
+	}
+	if len(defined) > 0 {
+		step("permission_registry", models.FlowStepPassed, "%s is defined by %s", target, strings.Join(defined, ", "))
+	} else {
+		step("permission_registry", models.FlowStepFailed, "no permission defines %s, so no role can grant it", target)
+	}
+
+	// Conditions are evaluated against the attributes the gRPC check would see, loaded
+	// only when a role carries a conditional permission for the request
+	var attrs abac.Attributes
+	attributes := func() abac.Attributes {
+		if attrs == nil {
+			attrs = s.simulationAttributes(ctx, req, considered)
+		}
+		return attrs
+	}
+
+	// Like CheckPermission, an unconditional grant wins over a conditional one
+	var unconditional, conditional *models.Permission
+	var unconditionalRole, conditionalRole string
+	unmet := false
+	consideredIDs := make(map[uuid.UUID]bool, len(considered))
+	for _, role := range considered {
+		consideredIDs[role.ID] = true
+		simulated, granted, roleUnmet := simulateRole(role, req.Resource, req.Action, attributes)
+		resp.Roles = append(resp.Roles, simulated)
+		unmet = unmet || roleUnmet
+
+		switch {
+		case granted == nil:
+		case granted.Condition == "" && unconditional == nil:
+			unconditional, unconditionalRole = granted, role.Name
+		case granted.Condition != "" && conditional == nil:
+			conditional, conditionalRole = granted, role.Name
+		}
+	}
+	if unconditional == nil && conditional != nil {
+		unconditional, unconditionalRole = conditional, conditionalRole
+	}
+	if unconditional != nil {
+		resp.Allowed = true
+		resp.Decision = models.RBACDecisionAllow
+		resp.Rule = models.RBACRuleRoleGrantsPermission
+		resp.MatchedRole = unconditionalRole
+		resp.MatchedPermission = unconditional.Name
+	}
+	for _, role := range assigned {
+		if consideredIDs[role.ID] {
+			continue
+		}
+		resp.Roles = append(resp.Roles, models.RBACSimulationRole{
+			ID:                  role.ID,
+			Name:                role.Name,
+			DisplayName:         role.DisplayName,
+			Detail:              "not assigned in the requested application, skipped",
+			ResourcePermissions: resourcePermissions(role, req.Resource),
+		})
+	}
+
+	switch {
+	case len(considered) == 0:
+		resp.Rule = models.RBACRuleNoRoles
+		resp.Reason = "user has no roles"
+		if req.ApplicationID != nil {
+			resp.Reason = "user has no roles in the requested application"
+		}
+		step("user_roles", models.FlowStepFailed, "%s", resp.Reason)
+	case resp.Allowed:
+		step("user_roles", models.FlowStepPassed, "user has %d role(s) to evaluate", len(considered))
+		resp.Reason = fmt.Sprintf("role %s grants %s through %s", resp.MatchedRole, target, resp.MatchedPermission)
+		step("role_permissions", models.FlowStepPassed, "%s", resp.Reason)
+	case unmet:
+		step("user_roles", models.FlowStepPassed, "user has %d role(s) to evaluate", len(considered))
+		resp.Rule = models.RBACRuleConditionNotMet
+		resp.Reason = fmt.Sprintf("the user's roles grant %s only under conditions that do not hold", target)
+		step("role_permissions", models.FlowStepFailed, "%s", resp.Reason)
+	default:
+		step("user_roles", models.FlowStepPassed, "user has %d role(s) to evaluate", len(considered))
+		resp.Rule = models.RBACRuleNoMatchingPermission
+		resp.Reason = fmt.Sprintf("none of the user's roles grants %s", target)
+		step("role_permissions", models.FlowStepFailed, "none of the %d role(s) grants %s", len(considered), target)
+	}
+
+	switch {
+	case attrs != nil && resp.Allowed && unconditional.Condition != "":
+		step("conditions", models.FlowStepPassed, "condition of %s holds: %s", unconditional.Name, unconditional.Condition)
+	case attrs != nil && resp.Allowed:
+		step("conditions", models.FlowStepSkipped, "%s is unconditional, conditions of other permissions did not affect the decision", unconditional.Name)
+	case attrs != nil:
+		step("conditions", models.FlowStepFailed, "no condition of a permission granting %s holds", target)
+	case len(req.Context) > 0:
+		keys := make([]string, 0, len(req.Context))
+		for key := range req.Context {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		step("context", models.FlowStepSkipped, "roles carry no attribute conditions, %s did not affect the decision", strings.Join(keys, ", "))
+	}
+
+	if !resp.Allowed && len(defined) > 0 {
+		roles, err := s.rbacRepo.ListRoles(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list roles: %w", err)
+		}
+		for _, role := range roles {
+			if !consideredIDs[role.ID] && matchingPermission(role, req.Resource, req.Action) != "" {
+				resp.GrantingRoles = append(resp.GrantingRoles, role.Name)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// simulateRole returns the permission the role grants the request through, preferring an
+// unconditional one, and whether it has conditional permissions whose conditions fail
+func simulateRole(role models.Role, resource, action string, attributes func() abac.Attributes) (models.RBACSimulationRole, *models.Permission, bool) {
+	result := models.RBACSimulationRole{
+		ID:                  role.ID,
+		Name:                role.Name,
+		DisplayName:         role.DisplayName,
+		Considered:          true,
+		ResourcePermissions: resourcePermissions(role, resource),
+	}
+
+	var granted *models.Permission
+	var unmet []string
+	for i := range role.Permissions {
+		perm := &role.Permissions[i]
+		if perm.Resource != resource || perm.Action != action {
+			continue
+		}
+		if perm.Condition == "" {
+			granted = perm
+			break
+		}
+		if conditionHolds(perm.Condition, attributes()) {
+			if granted == nil {
+				granted = perm
+			}
+			continue
+		}
+		unmet = append(unmet, fmt.Sprintf("%s (%s)", perm.Name, perm.Condition))
+	}
+
+	switch {
+	case granted != nil && granted.Condition == "":
+		result.Matched = true
+		result.Detail = fmt.Sprintf("grants %s:%s through %s", resource, action, granted.Name)
+	case granted != nil:
+		result.Matched = true
+		result.Detail = fmt.Sprintf("grants %s:%s through %s, its condition holds", resource, action, granted.Name)
+	case len(unmet) > 0:
+		result.Detail = fmt.Sprintf("grants %s:%s only under conditions that do not hold: %s", resource, action, strings.Join(unmet, ", "))
+	case len(result.ResourcePermissions) > 0:
+		result.Detail = fmt.Sprintf("grants %s on %s but not %s", strings.Join(result.ResourcePermissions, ", "), resource, action)
+	default:
+		result.Detail = fmt.Sprintf("has no permissions on %s", resource)
+	}
+	return result, granted, granted == nil && len(unmet) > 0
+}
+
+func matchingPermission(role models.Role, resource, action string) string {
+	for _, perm := range role.Permissions {
+		if perm.Resource == resource && perm.Action == action {
+			return perm.Name
+		}
+	}
+	return ""
+}
+
+func resourcePermissions(role models.Role, resource string) []string {
+	names := make([]string, 0)
+	for _, perm := range role.Permissions {
+		if perm.Resource == resource {
+			names = append(names, perm.Name)
+		}
+	}
+	return names
+}
+
+// conditionHolds evaluates a stored condition; one that no longer parses never holds
+func conditionHolds(source string, attrs abac.Attributes) bool {
+	condition, err := abac.Parse(source)
+	return err == nil && condition.Evaluate(attrs)
+}
+
+// simulationAttributes collects the attributes the gRPC CheckPermission would evaluate
+// conditions against. Without a user store only user.id and user.roles are known.
+func (s *RBACService) simulationAttributes(ctx context.Context, req *models.RBACSimulationRequest, roles []models.Role) abac.Attributes {
+	roleNames := make([]string, len(roles))
+	for i, role := range roles {
+		roleNames[i] = role.Name
+	}
+	userAttrs := map[string]interface{}{"id": req.UserID.String(), "roles": roleNames}
+	if s.users != nil {
+		if user, err := s.users.GetByID(ctx, req.UserID, nil); err == nil {
+			userAttrs = abac.UserAttributes(user, roleNames)
+		}
+	}
+	return abac.Attributes{
+		"user":     userAttrs,
+		"resource": req.ResourceAttributes,
+		"context":  req.Context,
+		"time":     abac.TimeAttributes(time.Now()),
+	}
+}
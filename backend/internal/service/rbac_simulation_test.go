@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func simulationFixture() (*mockRBACStore, models.Role, models.Role, models.Role) {
+	usersRead := models.Permission{ID: uuid.New(), Name: "users.read", Resource: "users", Action: "read"}
+	usersDelete := models.Permission{ID: uuid.New(), Name: "users.delete", Resource: "users", Action: "delete"}
+
+	viewer := models.Role{ID: uuid.New(), Name: "viewer", Permissions: []models.Permission{usersRead}}
+	support := models.Role{ID: uuid.New(), Name: "support"}
+	admin := models.Role{ID: uuid.New(), Name: "admin", Permissions: []models.Permission{usersRead, usersDelete}}
+
+	store := &mockRBACStore{
+		ListPermissionsFunc: func(ctx context.Context) ([]models.Permission, error) {
+			return []models.Permission{usersRead, usersDelete}, nil
+		},
+		ListRolesFunc: func(ctx context.Context) ([]models.Role, error) {
+			return []models.Role{admin, support, viewer}, nil
+		},
+	}
+	return store, viewer, support, admin
+}
+
+func stepStatuses(steps []models.RBACSimulationStep) map[string]string {
+	statuses := make(map[string]string, len(steps))
+	for _, step := range steps {
+		statuses[step.Name] = step.Status
+	}
+	return statuses
+}
+
+func TestRBACService_SimulatePermission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Allow", func(t *testing.T) {
+		store, viewer, _, admin := simulationFixture()
+		store.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+			return []models.Role{admin, viewer}, nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{UserID: uuid.New(), Resource: "users", Action: "delete"})
+		require.NoError(t, err)
+
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, models.RBACDecisionAllow, resp.Decision)
+		assert.Equal(t, models.RBACRuleRoleGrantsPermission, resp.Rule)
+		assert.Equal(t, "admin", resp.MatchedRole)
+		assert.Equal(t, "users.delete", resp.MatchedPermission)
+		require.Len(t, resp.Roles, 2)
+		assert.True(t, resp.Roles[0].Matched)
+		assert.False(t, resp.Roles[1].Matched)
+		assert.Equal(t, []string{"users.read"}, resp.Roles[1].ResourcePermissions)
+		assert.Empty(t, resp.GrantingRoles)
+	})
+
+	t.Run("DenyWithGrantingRoles", func(t *testing.T) {
+		store, viewer, support, _ := simulationFixture()
+		store.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+			return []models.Role{support, viewer}, nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{
+			UserID:   uuid.New(),
+			Resource: "users",
+			Action:   "delete",
+			Context:  map[string]interface{}{"ip": "10.0.0.1"},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, models.RBACRuleNoMatchingPermission, resp.Rule)
+		assert.Equal(t, []string{"admin"}, resp.GrantingRoles)
+		assert.Equal(t, "has no permissions on users", resp.Roles[0].Detail)
+		assert.Equal(t, map[string]string{
+			"application":         models.FlowStepSkipped,
+			"permission_registry": models.FlowStepPassed,
+			"user_roles":          models.FlowStepPassed,
+			"role_permissions":    models.FlowStepFailed,
+			"context":             models.FlowStepSkipped,
+		}, stepStatuses(resp.Steps))
+	})
+
+	t.Run("ApplicationScopeSkipsOtherRoles", func(t *testing.T) {
+		store, viewer, _, admin := simulationFixture()
+		appID := uuid.New()
+		store.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+			return []models.Role{admin, viewer}, nil
+		}
+		store.GetUserRolesInAppFunc = func(ctx context.Context, userID uuid.UUID, id *uuid.UUID) ([]models.Role, error) {
+			assert.Equal(t, appID, *id)
+			return []models.Role{viewer}, nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{UserID: uuid.New(), Resource: "users", Action: "delete", ApplicationID: &appID})
+		require.NoError(t, err)
+
+		assert.False(t, resp.Allowed)
+		require.Len(t, resp.Roles, 2)
+		assert.Equal(t, "viewer", resp.Roles[0].Name)
+		assert.True(t, resp.Roles[0].Considered)
+		assert.Equal(t, "admin", resp.Roles[1].Name)
+		assert.False(t, resp.Roles[1].Considered)
+		assert.Equal(t, []string{"admin"}, resp.GrantingRoles)
+	})
+
+	t.Run("NoRolesAndUndefinedPermission", func(t *testing.T) {
+		store, _, _, _ := simulationFixture()
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{UserID: uuid.New(), Resource: "invoices", Action: "refund"})
+		require.NoError(t, err)
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, models.RBACRuleNoRoles, resp.Rule)
+		assert.Equal(t, models.FlowStepFailed, stepStatuses(resp.Steps)["permission_registry"])
+		assert.Empty(t, resp.GrantingRoles)
+	})
+
+	t.Run("ConditionalPermission", func(t *testing.T) {
+		store, viewer, _, _ := simulationFixture()
+		userID := uuid.New()
+		deleteOwn := models.Permission{ID: uuid.New(), Name: "users.delete_self", Resource: "users", Action: "delete", Condition: "resource.id == user.id && user.email_verified"}
+		viewer.Permissions = append(viewer.Permissions, deleteOwn)
+		store.GetUserRolesFunc = func(ctx context.Context, id uuid.UUID) ([]models.Role, error) {
+			return []models.Role{viewer}, nil
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+		svc.SetUserStore(&mockUserStore{
+			GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+				return &models.User{ID: id, EmailVerified: true}, nil
+			},
+		})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{
+			UserID:             userID,
+			Resource:           "users",
+			Action:             "delete",
+			ResourceAttributes: map[string]interface{}{"id": userID.String()},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, "users.delete_self", resp.MatchedPermission)
+		assert.Equal(t, "grants users:delete through users.delete_self, its condition holds", resp.Roles[0].Detail)
+		assert.Equal(t, models.FlowStepPassed, stepStatuses(resp.Steps)["conditions"])
+
+		resp, err = svc.SimulatePermission(ctx, &models.RBACSimulationRequest{
+			UserID:             userID,
+			Resource:           "users",
+			Action:             "delete",
+			ResourceAttributes: map[string]interface{}{"id": uuid.New().String()},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, models.RBACRuleConditionNotMet, resp.Rule)
+		assert.Contains(t, resp.Roles[0].Detail, "only under conditions that do not hold: users.delete_self")
+		assert.Equal(t, models.FlowStepFailed, stepStatuses(resp.Steps)["conditions"])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		store, _, _, _ := simulationFixture()
+		store.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+			return nil, errors.New("db error")
+		}
+		svc := NewRBACService(store, &mockAuditLogger{})
+
+		resp, err := svc.SimulatePermission(ctx, &models.RBACSimulationRequest{UserID: uuid.New(), Resource: "users", Action: "read"})
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/smilemakc/auth-gateway/internal/config"
 	"github.com/smilemakc/auth-gateway/internal/models"
@@ -35,6 +36,11 @@ func NewRedisService(cfg *config.RedisConfig) (*RedisService, error) {
 	return &RedisService{client: client}, nil
 }
 
+// AddHook adds a hook around every Redis command
+func (r *RedisService) AddHook(hook redis.Hook) {
+	r.client.AddHook(hook)
+}
+
 // Close closes the Redis connection
 func (r *RedisService) Close() error {
 	return r.client.Close()
@@ -50,6 +56,11 @@ func (r *RedisService) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, key).Result()
 }
 
+// GetDel gets a value by key and deletes the key in one step, so only one caller gets it
+func (r *RedisService) GetDel(ctx context.Context, key string) (string, error) {
+	return r.client.GetDel(ctx, key).Result()
+}
+
 // Delete deletes a key
 func (r *RedisService) Delete(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
@@ -89,8 +100,108 @@ func (r *RedisService) AddToBlacklist(ctx context.Context, tokenHash string, exp
 
 // IsBlacklisted checks if a token is blacklisted
 func (r *RedisService) IsBlacklisted(ctx context.Context, tokenHash string) (bool, error) {
-	key := fmt.Sprintf("blacklist:%s", tokenHash)
-	return r.Exists(ctx, key)
+	return r.Exists(ctx, "blacklist:"+tokenHash)
+}
+
+// Pub/sub channels shared by every gateway instance
+const (
+	blacklistChannel   = "blacklist:added"    // token hashes blacklisted by any instance
+	revocationsChannel = "revocations:events" // JSON-encoded models.RevocationEvent
+	userChangedChannel = "users:changed"      // IDs of users whose cached copies were invalidated
+	logLevelChannel    = "log_level:changed"  // Runtime log level overrides, empty when reverted
+)
+
+// resubscribeDelay throttles retries while a subscription connection is down
+const resubscribeDelay = time.Second
+
+// PublishBlacklisted notifies every gateway instance that tokenHash was blacklisted
+func (r *RedisService) PublishBlacklisted(ctx context.Context, tokenHash string) error {
+	return r.client.Publish(ctx, blacklistChannel, tokenHash).Err()
+}
+
+// WatchBlacklisted calls onAdd for every token hash published by PublishBlacklisted until ctx
+// is done. onSubscribed is called each time the subscription is (re)established and
+// onInterrupted when it breaks; go-redis resubscribes on the next receive.
+func (r *RedisService) WatchBlacklisted(ctx context.Context, onAdd func(tokenHash string), onSubscribed, onInterrupted func()) {
+	r.watch(ctx, blacklistChannel, onAdd, onSubscribed, onInterrupted)
+}
+
+// PublishRevocation notifies every gateway instance of a revocation
+func (r *RedisService) PublishRevocation(ctx context.Context, event *models.RevocationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, revocationsChannel, payload).Err()
+}
+
+// WatchRevocations calls onEvent for every event published by PublishRevocation until ctx is
+// done, with the same subscription callbacks as WatchBlacklisted
+func (r *RedisService) WatchRevocations(ctx context.Context, onEvent func(event models.RevocationEvent), onSubscribed, onInterrupted func()) {
+	r.watch(ctx, revocationsChannel, func(payload string) {
+		var event models.RevocationEvent
+		if err := json.Unmarshal([]byte(payload), &event); err == nil {
+			onEvent(event)
+		}
+	}, onSubscribed, onInterrupted)
+}
+
+// PublishUserChanged notifies every gateway instance that the user's cached copy was invalidated
+func (r *RedisService) PublishUserChanged(ctx context.Context, userID uuid.UUID) error {
+	return r.client.Publish(ctx, userChangedChannel, userID.String()).Err()
+}
+
+// WatchUserChanged calls onChange for every user published by PublishUserChanged until ctx is
+// done, with the same subscription callbacks as WatchBlacklisted
+func (r *RedisService) WatchUserChanged(ctx context.Context, onChange func(userID uuid.UUID), onSubscribed, onInterrupted func()) {
+	r.watch(ctx, userChangedChannel, func(payload string) {
+		if userID, err := uuid.Parse(payload); err == nil {
+			onChange(userID)
+		}
+	}, onSubscribed, onInterrupted)
+}
+
+// PublishLogLevel notifies every gateway instance of a log level override; an empty
+// override reverts to the configured levels
+func (r *RedisService) PublishLogLevel(ctx context.Context, override string) error {
+	return r.client.Publish(ctx, logLevelChannel, override).Err()
+}
+
+// WatchLogLevel calls onChange for every override published by PublishLogLevel until ctx is
+// done, with the same subscription callbacks as WatchBlacklisted
+func (r *RedisService) WatchLogLevel(ctx context.Context, onChange func(override string), onSubscribed, onInterrupted func()) {
+	r.watch(ctx, logLevelChannel, onChange, onSubscribed, onInterrupted)
+}
+
+// watch delivers messages published on channel to onMessage until ctx is done
+func (r *RedisService) watch(ctx context.Context, channel string, onMessage func(payload string), onSubscribed, onInterrupted func()) {
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			onInterrupted()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeDelay):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				onSubscribed()
+			}
+		case *redis.Message:
+			onMessage(m.Payload)
+		}
+	}
 }
 
 // IncrementRateLimit increments the rate limit counter
@@ -110,6 +221,56 @@ func (r *RedisService) IncrementRateLimit(ctx context.Context, key string, windo
 	return count, nil
 }
 
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until a request would be allowed again; zero when allowed
+	RetryAfter time.Duration
+}
+
+// slidingWindowScript keeps the timestamps (in microseconds of the Redis clock, so all
+// gateway instances agree) of the requests allowed in the last window in a sorted set.
+// A request is allowed, and recorded, while fewer than the limit are in the window.
+// Rejected requests are not recorded, so a client is let through again as soon as its
+// oldest request leaves the window. Relies on script effects replication (Redis 5+).
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+	return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, 0, tonumber(oldest[2]) + window - now}
+`)
+
+// SlidingWindowRateLimit records a request under key and reports whether it is within
+// limit requests per window, counted over a sliding window
+func (r *RedisService) SlidingWindowRateLimit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	values, err := slidingWindowScript.Run(ctx, r.client, []string{key},
+		limit, window.Microseconds(), uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
+
 // StorePendingRegistration stores pending registration data in Redis
 func (r *RedisService) StorePendingRegistration(ctx context.Context, identifier string, data *models.PendingRegistration, expiration time.Duration) error {
 	key := fmt.Sprintf("pending:registration:%s", identifier)
@@ -0,0 +1,680 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	redisSessionKeyPrefix      = "session:"
+	redisSessionTokenKeyPrefix = "session:token:"
+	redisSessionUserKeyPrefix  = "session:user:"
+	redisSessionAppKeyPrefix   = "session:app:"
+	redisSessionActiveKey      = "session:active"
+	redisSessionExpiryKey      = "session:expiry"
+	redisSessionArchiveKey     = "session:archive"
+
+	// redisSessionLoadBatch is how many sessions are read per MGET when scanning all of them
+	redisSessionLoadBatch = 500
+	// redisSessionUpdateAttempts is how often a change is retried when the session changes under it
+	redisSessionUpdateAttempts = 5
+	// redisSessionStatsTopN matches the number of OS and browser buckets the database reports
+	redisSessionStatsTopN = 10
+)
+
+// RedisSessionStore keeps sessions in Redis instead of PostgreSQL, for deployments where
+// session writes load the database and losing sessions on a Redis failure is acceptable.
+//
+// Each session is a JSON value expiring with the session. Active sessions are indexed by
+// refresh token hash and listed in sorted sets per user, per application and overall, scored
+// by last activity. A sorted set scored by expiry drops sessions from the lists once they
+// expire. Revoked sessions leave the indexes immediately but stay readable by ID until they
+// would have expired.
+//
+// With an archive, every changed session is queued and Archive copies the queue to the
+// database, so reports reading sessions from PostgreSQL keep working with a delay.
+type RedisSessionStore struct {
+	client  *redis.Client
+	users   UserStore
+	archive SessionArchive
+	logger  *logger.Logger
+}
+
+// redisSession is the form a session is kept in: its JSON plus the token hashes the JSON hides
+type redisSession struct {
+	models.Session
+	TokenHash       string `json:"token_hash"`
+	AccessTokenHash string `json:"access_token_hash"`
+}
+
+// NewRedisSessionStore creates a session store on redis. Users are loaded from users for
+// paginated listings; a nil archive keeps sessions in Redis only.
+func NewRedisSessionStore(redis *RedisService, users UserStore, archive SessionArchive, logger *logger.Logger) *RedisSessionStore {
+	return &RedisSessionStore{
+		client:  redis.client,
+		users:   users,
+		archive: archive,
+		logger:  logger,
+	}
+}
+
+func redisSessionKey(id uuid.UUID) string {
+	return redisSessionKeyPrefix + id.String()
+}
+
+func redisSessionTokenKey(tokenHash string) string {
+	return redisSessionTokenKeyPrefix + tokenHash
+}
+
+func redisSessionUserKey(userID uuid.UUID) string {
+	return redisSessionUserKeyPrefix + userID.String()
+}
+
+func redisSessionAppKey(appID uuid.UUID) string {
+	return redisSessionAppKeyPrefix + appID.String()
+}
+
+// redisSessionExpiryMember names a session in the expiry set with the lists it is in, so an
+// expired session can be dropped from them after its value is gone
+func redisSessionExpiryMember(session *models.Session) string {
+	member := session.ID.String() + "|" + session.UserID.String() + "|"
+	if session.ApplicationID != nil {
+		member += session.ApplicationID.String()
+	}
+	return member
+}
+
+// parseRedisSessionExpiryMember reverses redisSessionExpiryMember
+func parseRedisSessionExpiryMember(member string) (id, userID uuid.UUID, appID *uuid.UUID, err error) {
+	parts := strings.Split(member, "|")
+	if len(parts) != 3 {
+		return uuid.Nil, uuid.Nil, nil, fmt.Errorf("invalid session expiry member %q", member)
+	}
+	if id, err = uuid.Parse(parts[0]); err != nil {
+		return uuid.Nil, uuid.Nil, nil, err
+	}
+	if userID, err = uuid.Parse(parts[1]); err != nil {
+		return uuid.Nil, uuid.Nil, nil, err
+	}
+	if parts[2] != "" {
+		parsed, err := uuid.Parse(parts[2])
+		if err != nil {
+			return uuid.Nil, uuid.Nil, nil, err
+		}
+		appID = &parsed
+	}
+	return id, userID, appID, nil
+}
+
+func encodeRedisSession(session *models.Session) ([]byte, error) {
+	stored := redisSession{
+		Session:         *session,
+		TokenHash:       session.TokenHash,
+		AccessTokenHash: session.AccessTokenHash,
+	}
+	stored.Session.User = nil
+	stored.Session.Application = nil
+	return json.Marshal(stored)
+}
+
+func decodeRedisSession(data string) (*models.Session, error) {
+	var stored redisSession
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	session := stored.Session
+	session.TokenHash = stored.TokenHash
+	session.AccessTokenHash = stored.AccessTokenHash
+	return &session, nil
+}
+
+// write stores a new session
+func (s *RedisSessionStore) write(ctx context.Context, session *models.Session) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		return s.queueWrite(ctx, pipe, session)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// update changes a stored session; change gets nil when there is none and returns an error
+// to leave it as is. The session is watched while it changes, so a concurrent revocation is
+// never overwritten with the session still active.
+func (s *RedisSessionStore) update(ctx context.Context, id uuid.UUID, change func(session *models.Session) error) error {
+	key := redisSessionKey(id)
+	for attempt := 0; attempt < redisSessionUpdateAttempts; attempt++ {
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			var session *models.Session
+			data, err := tx.Get(ctx, key).Result()
+			switch {
+			case errors.Is(err, redis.Nil):
+			case err != nil:
+				return fmt.Errorf("failed to get session: %w", err)
+			default:
+				if session, err = decodeRedisSession(data); err != nil {
+					return err
+				}
+			}
+			if err := change(session); err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return s.queueWrite(ctx, pipe, session)
+			})
+			return err
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed to update session: changed concurrently")
+}
+
+// queueWrite stores a session and updates the indexes: an active session is listed and found
+// by its refresh token, a revoked one is neither
+func (s *RedisSessionStore) queueWrite(ctx context.Context, pipe redis.Pipeliner, session *models.Session) error {
+	data, err := encodeRedisSession(session)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(session.ExpiresAt)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	id := session.ID.String()
+	lists := []string{redisSessionActiveKey, redisSessionUserKey(session.UserID)}
+	if session.ApplicationID != nil {
+		lists = append(lists, redisSessionAppKey(*session.ApplicationID))
+	}
+
+	pipe.Set(ctx, redisSessionKey(session.ID), data, ttl)
+	if session.RevokedAt == nil {
+		pipe.Set(ctx, redisSessionTokenKey(session.TokenHash), id, ttl)
+		for _, list := range lists {
+			pipe.ZAdd(ctx, list, redis.Z{Score: float64(session.LastActiveAt.UnixMilli()), Member: id})
+		}
+		pipe.ZAdd(ctx, redisSessionExpiryKey, redis.Z{Score: float64(session.ExpiresAt.Unix()), Member: redisSessionExpiryMember(session)})
+	} else {
+		pipe.Del(ctx, redisSessionTokenKey(session.TokenHash))
+		for _, list := range lists {
+			pipe.ZRem(ctx, list, id)
+		}
+		pipe.ZRem(ctx, redisSessionExpiryKey, redisSessionExpiryMember(session))
+	}
+	if s.archive != nil {
+		pipe.SAdd(ctx, redisSessionArchiveKey, id)
+	}
+	return nil
+}
+
+// load returns a session by ID, or nil when there is none
+func (s *RedisSessionStore) load(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	data, err := s.client.Get(ctx, redisSessionKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return decodeRedisSession(data)
+}
+
+// loadMany returns the sessions with the given IDs in order, nil for the ones that are gone
+func (s *RedisSessionStore) loadMany(ctx context.Context, ids []string) ([]*models.Session, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisSessionKeyPrefix + id
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	sessions := make([]*models.Session, len(ids))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		session, err := decodeRedisSession(data)
+		if err != nil {
+			return nil, err
+		}
+		sessions[i] = session
+	}
+	return sessions, nil
+}
+
+// list returns the active sessions of a sorted set between two ranks, most recently active
+// first, dropping entries whose sessions are gone
+func (s *RedisSessionStore) list(ctx context.Context, key string, start, stop int64) ([]models.Session, error) {
+	ids, err := s.client.ZRevRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	loaded, err := s.loadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]models.Session, 0, len(loaded))
+	var stale []interface{}
+	for i, session := range loaded {
+		if session == nil || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+			stale = append(stale, ids[i])
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+	if len(stale) > 0 {
+		s.client.ZRem(ctx, key, stale...)
+	}
+	return sessions, nil
+}
+
+// page returns a page of the active sessions of a sorted set and their total
+func (s *RedisSessionStore) page(ctx context.Context, key string, page, perPage int) ([]models.Session, int, error) {
+	if err := s.pruneExpired(ctx); err != nil {
+		return nil, 0, err
+	}
+	total, err := s.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+	start := int64((page - 1) * perPage)
+	sessions, err := s.list(ctx, key, start, start+int64(perPage)-1)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sessions, int(total), nil
+}
+
+// attachUsers loads the users of sessions, as the database store joins them
+func (s *RedisSessionStore) attachUsers(ctx context.Context, sessions []models.Session) {
+	users := make(map[uuid.UUID]*models.User)
+	for i := range sessions {
+		userID := sessions[i].UserID
+		user, ok := users[userID]
+		if !ok {
+			user, _ = s.users.GetByID(ctx, userID, nil)
+			users[userID] = user
+		}
+		sessions[i].User = user
+	}
+}
+
+// pruneExpired drops expired sessions from the lists; their values expire on their own
+func (s *RedisSessionStore) pruneExpired(ctx context.Context) error {
+	members, err := s.client.ZRangeByScore(ctx, redisSessionExpiryKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to find expired sessions: %w", err)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, member := range members {
+			id, userID, appID, err := parseRedisSessionExpiryMember(member)
+			if err == nil {
+				pipe.ZRem(ctx, redisSessionActiveKey, id.String())
+				pipe.ZRem(ctx, redisSessionUserKey(userID), id.String())
+				if appID != nil {
+					pipe.ZRem(ctx, redisSessionAppKey(*appID), id.String())
+				}
+			}
+			pipe.ZRem(ctx, redisSessionExpiryKey, member)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune expired sessions: %w", err)
+	}
+	return nil
+}
+
+// errRedisSessionNotRevocable is returned by revokeSession for a session that is gone, already
+// revoked or not allowed to be revoked
+var errRedisSessionNotRevocable = errors.New("session not found or already revoked")
+
+// revokeSession revokes an active session; matches reports whether it may be revoked
+func (s *RedisSessionStore) revokeSession(ctx context.Context, id uuid.UUID, matches func(session *models.Session) bool) error {
+	return s.update(ctx, id, func(session *models.Session) error {
+		if session == nil || session.RevokedAt != nil || !matches(session) {
+			return errRedisSessionNotRevocable
+		}
+		now := time.Now()
+		session.RevokedAt = &now
+		return nil
+	})
+}
+
+// CreateSession stores a new session
+func (s *RedisSessionStore) CreateSession(ctx context.Context, session *models.Session) error {
+	now := time.Now()
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastActiveAt.IsZero() {
+		session.LastActiveAt = now
+	}
+	return s.write(ctx, session)
+}
+
+// GetSessionByID retrieves a session by ID, revoked or not
+func (s *RedisSessionStore) GetSessionByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	session, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session not found")
+	}
+	return session, nil
+}
+
+// GetSessionByTokenHash retrieves an active session by refresh token hash
+func (s *RedisSessionStore) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	id, err := s.client.Get(ctx, redisSessionTokenKey(tokenHash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("session not found or revoked")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session by token hash: %w", err)
+	}
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session by token hash: %w", err)
+	}
+
+	session, err := s.load(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil || session.TokenHash != tokenHash {
+		return nil, fmt.Errorf("session not found or revoked")
+	}
+	return session, nil
+}
+
+// GetUserSessions retrieves all active sessions for a user
+func (s *RedisSessionStore) GetUserSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	return s.list(ctx, redisSessionUserKey(userID), 0, -1)
+}
+
+// GetUserSessionsPaginated retrieves paginated active sessions for a user
+func (s *RedisSessionStore) GetUserSessionsPaginated(ctx context.Context, userID uuid.UUID, page, perPage int) ([]models.Session, int, error) {
+	sessions, total, err := s.page(ctx, redisSessionUserKey(userID), page, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.attachUsers(ctx, sessions)
+	return sessions, total, nil
+}
+
+// GetAllSessionsPaginated retrieves all active sessions with pagination (admin)
+func (s *RedisSessionStore) GetAllSessionsPaginated(ctx context.Context, page, perPage int) ([]models.Session, int, error) {
+	sessions, total, err := s.page(ctx, redisSessionActiveKey, page, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.attachUsers(ctx, sessions)
+	return sessions, total, nil
+}
+
+// GetUserSessionsByApp retrieves active sessions for a user in a specific application
+func (s *RedisSessionStore) GetUserSessionsByApp(ctx context.Context, userID, appID uuid.UUID) ([]models.Session, error) {
+	sessions, err := s.GetUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	appSessions := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ApplicationID != nil && *session.ApplicationID == appID {
+			appSessions = append(appSessions, session)
+		}
+	}
+	return appSessions, nil
+}
+
+// GetAppSessionsPaginated retrieves paginated active sessions for an application
+func (s *RedisSessionStore) GetAppSessionsPaginated(ctx context.Context, appID uuid.UUID, page, perPage int) ([]models.Session, int, error) {
+	return s.page(ctx, redisSessionAppKey(appID), page, perPage)
+}
+
+// RevokeSession revokes a specific session
+func (s *RedisSessionStore) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	return s.revokeSession(ctx, id, func(*models.Session) bool { return true })
+}
+
+// RevokeUserSession revokes a session only if it belongs to the user
+func (s *RedisSessionStore) RevokeUserSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	err := s.revokeSession(ctx, sessionID, func(session *models.Session) bool {
+		return session.UserID == userID
+	})
+	if errors.Is(err, errRedisSessionNotRevocable) {
+		return fmt.Errorf("session not found, already revoked, or does not belong to user")
+	}
+	return err
+}
+
+// RevokeAllUserSessions revokes all sessions for a user except the current one
+func (s *RedisSessionStore) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID, exceptSessionID *uuid.UUID) error {
+	sessions, err := s.GetUserSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if exceptSessionID != nil && session.ID == *exceptSessionID {
+			continue
+		}
+		// Sessions revoked meanwhile are already what this call wants
+		err := s.revokeSession(ctx, session.ID, func(*models.Session) bool { return true })
+		if err != nil && !errors.Is(err, errRedisSessionNotRevocable) {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateSessionName updates the session name
+func (s *RedisSessionStore) UpdateSessionName(ctx context.Context, sessionID uuid.UUID, name string) error {
+	return s.update(ctx, sessionID, func(session *models.Session) error {
+		if session == nil {
+			return fmt.Errorf("session not found")
+		}
+		session.SessionName = name
+		return nil
+	})
+}
+
+// UpdateSessionAccessTokenHash updates the access token hash for a session
+func (s *RedisSessionStore) UpdateSessionAccessTokenHash(ctx context.Context, sessionID uuid.UUID, accessTokenHash string) error {
+	return s.update(ctx, sessionID, func(session *models.Session) error {
+		if session == nil || session.RevokedAt != nil {
+			return fmt.Errorf("session not found or already revoked")
+		}
+		session.AccessTokenHash = accessTokenHash
+		return nil
+	})
+}
+
+// RefreshSessionTokens moves a session to a new refresh token and extends its expiration.
+// The old token is claimed with GETDEL, so of two concurrent refreshes with it only one wins.
+func (s *RedisSessionStore) RefreshSessionTokens(ctx context.Context, oldTokenHash, newTokenHash, newAccessTokenHash string, newExpiresAt time.Time) error {
+	id, err := s.client.GetDel(ctx, redisSessionTokenKey(oldTokenHash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("session not found or already revoked")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to refresh session tokens: %w", err)
+	}
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("failed to refresh session tokens: %w", err)
+	}
+
+	return s.update(ctx, sessionID, func(session *models.Session) error {
+		if session == nil || session.RevokedAt != nil || session.TokenHash != oldTokenHash {
+			return fmt.Errorf("session not found or already revoked")
+		}
+		session.TokenHash = newTokenHash
+		session.AccessTokenHash = newAccessTokenHash
+		session.ExpiresAt = newExpiresAt
+		session.LastActiveAt = time.Now()
+		return nil
+	})
+}
+
+// GetSessionStats retrieves session statistics
+func (s *RedisSessionStore) GetSessionStats(ctx context.Context) (*models.SessionStats, error) {
+	if err := s.pruneExpired(ctx); err != nil {
+		return nil, err
+	}
+	ids, err := s.client.ZRange(ctx, redisSessionActiveKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	stats := &models.SessionStats{
+		SessionsByDevice:  make(map[string]int),
+		SessionsByOS:      make(map[string]int),
+		SessionsByBrowser: make(map[string]int),
+	}
+	now := time.Now()
+	for start := 0; start < len(ids); start += redisSessionLoadBatch {
+		end := min(start+redisSessionLoadBatch, len(ids))
+		sessions, err := s.loadMany(ctx, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, session := range sessions {
+			if session == nil || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+				continue
+			}
+			stats.TotalActiveSessions++
+			stats.SessionsByDevice[orUnknown(session.DeviceType)]++
+			stats.SessionsByOS[orUnknown(session.OS)]++
+			stats.SessionsByBrowser[orUnknown(session.Browser)]++
+		}
+	}
+	stats.SessionsByOS = topCounts(stats.SessionsByOS, redisSessionStatsTopN)
+	stats.SessionsByBrowser = topCounts(stats.SessionsByBrowser, redisSessionStatsTopN)
+	return stats, nil
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// topCounts keeps the n largest counts, breaking ties by name
+func topCounts(counts map[string]int, n int) map[string]int {
+	if len(counts) <= n {
+		return counts
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	top := make(map[string]int, n)
+	for _, name := range names[:n] {
+		top[name] = counts[name]
+	}
+	return top
+}
+
+// DeleteExpiredSessions drops expired sessions from the lists. Session values, revoked or
+// not, expire with the session, so olderThan does not apply.
+func (s *RedisSessionStore) DeleteExpiredSessions(ctx context.Context, olderThan time.Duration) error {
+	return s.pruneExpired(ctx)
+}
+
+// Archive copies sessions changed since the last run to the archive, batchSize at a time,
+// and returns how many were copied. Sessions that expired before being copied are skipped;
+// on a failed write the batch is queued again.
+func (s *RedisSessionStore) Archive(ctx context.Context, batchSize int) (int, error) {
+	if s.archive == nil {
+		return 0, nil
+	}
+
+	archived := 0
+	for {
+		ids, err := s.client.SPopN(ctx, redisSessionArchiveKey, int64(batchSize)).Result()
+		if err != nil {
+			return archived, fmt.Errorf("failed to read session archive queue: %w", err)
+		}
+		if len(ids) == 0 {
+			return archived, nil
+		}
+
+		loaded, err := s.loadMany(ctx, ids)
+		if err != nil {
+			s.requeue(ctx, ids)
+			return archived, err
+		}
+		sessions := make([]models.Session, 0, len(loaded))
+		for _, session := range loaded {
+			if session != nil {
+				sessions = append(sessions, *session)
+			}
+		}
+		if err := s.archive.ArchiveSessions(ctx, sessions); err != nil {
+			s.requeue(ctx, ids)
+			return archived, err
+		}
+		archived += len(sessions)
+
+		if len(ids) < batchSize {
+			return archived, nil
+		}
+	}
+}
+
+// requeue puts sessions back in the archive queue after a failed run
+func (s *RedisSessionStore) requeue(ctx context.Context, ids []string) {
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+	if err := s.client.SAdd(ctx, redisSessionArchiveKey, members...).Err(); err != nil {
+		s.logger.Error("Failed to requeue sessions for archiving", map[string]interface{}{
+			"count": len(ids),
+			"error": err.Error(),
+		})
+	}
+}
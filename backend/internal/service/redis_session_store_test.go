@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisSessionEncoding(t *testing.T) {
+	appID := uuid.New()
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	session := &models.Session{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		ApplicationID:   &appID,
+		TokenHash:       "refresh-hash",
+		AccessTokenHash: "access-hash",
+		DeviceType:      "desktop",
+		SessionName:     "Work Laptop",
+		LastActiveAt:    revokedAt.Add(-time.Hour),
+		ExpiresAt:       revokedAt.Add(24 * time.Hour),
+		CreatedAt:       revokedAt.Add(-2 * time.Hour),
+		RevokedAt:       &revokedAt,
+		User:            &models.User{Email: "user@example.com"},
+	}
+
+	data, err := encodeRedisSession(session)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "user@example.com", "relations must not be stored")
+
+	decoded, err := decodeRedisSession(string(data))
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-hash", decoded.TokenHash)
+	assert.Equal(t, "access-hash", decoded.AccessTokenHash)
+	assert.Equal(t, session.ID, decoded.ID)
+	assert.Equal(t, appID, *decoded.ApplicationID)
+	assert.Equal(t, "Work Laptop", decoded.SessionName)
+	assert.True(t, session.ExpiresAt.Equal(decoded.ExpiresAt))
+	require.NotNil(t, decoded.RevokedAt)
+	assert.True(t, revokedAt.Equal(*decoded.RevokedAt))
+	assert.Nil(t, decoded.User)
+	assert.NotNil(t, session.User, "encoding must not change the session")
+}
+
+func TestRedisSessionExpiryMember(t *testing.T) {
+	t.Run("WithApplication", func(t *testing.T) {
+		appID := uuid.New()
+		session := &models.Session{ID: uuid.New(), UserID: uuid.New(), ApplicationID: &appID}
+
+		id, userID, parsedAppID, err := parseRedisSessionExpiryMember(redisSessionExpiryMember(session))
+		require.NoError(t, err)
+		assert.Equal(t, session.ID, id)
+		assert.Equal(t, session.UserID, userID)
+		require.NotNil(t, parsedAppID)
+		assert.Equal(t, appID, *parsedAppID)
+	})
+
+	t.Run("WithoutApplication", func(t *testing.T) {
+		session := &models.Session{ID: uuid.New(), UserID: uuid.New()}
+
+		_, _, appID, err := parseRedisSessionExpiryMember(redisSessionExpiryMember(session))
+		require.NoError(t, err)
+		assert.Nil(t, appID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, _, _, err := parseRedisSessionExpiryMember("not-a-member")
+		assert.Error(t, err)
+	})
+}
+
+func TestTopCounts(t *testing.T) {
+	counts := map[string]int{"a": 5, "b": 3, "c": 3, "d": 1}
+
+	assert.Equal(t, map[string]int{"a": 5, "b": 3}, topCounts(counts, 2))
+	assert.Equal(t, counts, topCounts(counts, 10))
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// StateReasonRetention is recorded when an account is erased by the inactive account policy
+	StateReasonRetention = "retention"
+	// StateReasonGDPRErasure is recorded when an administrator erases an account on request
+	StateReasonGDPRErasure = "gdpr_erasure"
+)
+
+const (
+	// retentionBatchSize is how many rows are deleted or accounts loaded per query
+	retentionBatchSize = 1000
+	// retentionMaxBatches caps the batches deleted per table in one run, so a large backlog
+	// is worked off over several runs instead of holding the database in one
+	retentionMaxBatches = 100
+)
+
+var (
+	errEraseSelf  = models.NewAppError(http.StatusBadRequest, "Cannot erase your own account")
+	errEraseAdmin = models.NewAppError(http.StatusBadRequest, "Cannot erase an administrator", "Remove the admin role before erasing the account")
+)
+
+// UserErasureParams describes an administrator's request to erase a user's personal data
+type UserErasureParams struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IP        string
+	UserAgent string
+}
+
+// RetentionService enforces the configured data retention policies and erases personal data.
+// Audit log entries and ended sessions are deleted once they are older than their retention
+// period. Accounts inactive for longer than theirs are erased like a GDPR erasure request:
+// the user row stays, so everything referencing it keeps its integrity, but its profile,
+// contact details, credentials and sign-in history are scrubbed, its audit log entries lose
+// their IP address, user agent, location and details, and the account is moved to the deleted
+// state, which revokes its access.
+type RetentionService struct {
+	policy     config.RetentionConfig
+	store      RetentionStore
+	anonymizer UserAnonymizer
+	roles      UserRoleRepository
+	lifecycle  *UserLifecycleService
+	audit      AuditLogger
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(policy config.RetentionConfig, store RetentionStore, anonymizer UserAnonymizer, roles UserRoleRepository, lifecycle *UserLifecycleService, audit AuditLogger, logger *logger.Logger) *RetentionService {
+	return &RetentionService{
+		policy:     policy,
+		store:      store,
+		anonymizer: anonymizer,
+		roles:      roles,
+		lifecycle:  lifecycle,
+		audit:      audit,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run applies the retention policies once. Policies set to 0 keep data forever and are
+// skipped. Accounts that fail to be erased are logged, counted and retried on the next run.
+func (s *RetentionService) Run(ctx context.Context) (*models.RetentionRunResult, error) {
+	result := &models.RetentionRunResult{}
+	now := s.now()
+
+	if s.policy.AuditLogDays > 0 {
+		deleted, err := s.deleteInBatches(ctx, now.AddDate(0, 0, -s.policy.AuditLogDays), s.store.DeleteAuditLogsBefore)
+		result.AuditLogsDeleted = deleted
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if s.policy.SessionDays > 0 {
+		deleted, err := s.deleteInBatches(ctx, now.AddDate(0, 0, -s.policy.SessionDays), s.store.DeleteSessionsEndedBefore)
+		result.SessionsDeleted = deleted
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if s.policy.InactiveAccountMonths > 0 {
+		if err := s.eraseInactiveAccounts(ctx, now.AddDate(0, -s.policy.InactiveAccountMonths, 0), result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// EraseUser erases the personal data of a user on an administrator's request and moves the
+// account to the deleted state. Erasing an account that was erased before scrubs it again.
+// Administrators cannot erase themselves or other administrators.
+func (s *RetentionService) EraseUser(ctx context.Context, params UserErasureParams) (*models.UserErasureResponse, error) {
+	if params.UserID == params.ActorID {
+		return nil, errEraseSelf
+	}
+
+	roles, err := s.roles.GetUserRoles(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == string(models.RoleAdmin) {
+			return nil, errEraseAdmin
+		}
+	}
+
+	scrubbed, err := s.erase(ctx, params.UserID, StateReasonGDPRErasure, &params.ActorID, params.IP, params.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserErasureResponse{
+		UserID:            params.UserID,
+		ErasedAt:          s.now(),
+		AuditLogsScrubbed: scrubbed,
+	}, nil
+}
+
+// erase scrubs an account and its activity before deleting it, so a failed deletion is
+// picked up again by the next run. The erasure itself is audited after the scrub, so its
+// own entry is kept intact.
+func (s *RetentionService) erase(ctx context.Context, userID uuid.UUID, reason string, actorID *uuid.UUID, ip, userAgent string) (int, error) {
+	if err := s.anonymizer.Anonymize(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	scrubbed, err := s.store.ScrubUserActivity(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	details := map[string]interface{}{"reason": reason}
+	if actorID != nil {
+		details["actor_id"] = actorID.String()
+	}
+	s.audit.Log(AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionUserAnonymize,
+		Status:    models.StatusSuccess,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details:   details,
+	})
+
+	_, err = s.lifecycle.Transition(ctx, UserStateTransitionParams{
+		UserID:    userID,
+		State:     models.UserStateDeleted,
+		Reason:    reason,
+		ActorID:   actorID,
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+	return scrubbed, err
+}
+
+// eraseInactiveAccounts erases every account without activity since cutoff
+func (s *RetentionService) eraseInactiveAccounts(ctx context.Context, cutoff time.Time, result *models.RetentionRunResult) error {
+	afterID := uuid.Nil
+
+	for {
+		ids, err := s.store.ListInactiveAccounts(ctx, cutoff, afterID, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := s.erase(ctx, id, StateReasonRetention, nil, "", ""); err != nil {
+				result.Failed++
+				s.logger.Warn("Failed to erase inactive account", map[string]interface{}{
+					"user_id": id.String(),
+					"error":   err.Error(),
+				})
+				continue
+			}
+			result.AccountsErased++
+		}
+
+		if len(ids) < retentionBatchSize {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// deleteInBatches calls deleteFn until it deletes less than a full batch or the per-run cap
+// is reached, and returns the total deleted
+func (s *RetentionService) deleteInBatches(ctx context.Context, before time.Time, deleteFn func(context.Context, time.Time, int) (int, error)) (int, error) {
+	total := 0
+	for i := 0; i < retentionMaxBatches; i++ {
+		deleted, err := deleteFn(ctx, before, retentionBatchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < retentionBatchSize {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRetentionStore struct {
+	auditLogs     []time.Time
+	sessions      []time.Time
+	lastActive    map[uuid.UUID]time.Time
+	scrubbed      []uuid.UUID
+	auditCutoff   time.Time
+	sessionCutoff time.Time
+}
+
+func deleteBefore(rows *[]time.Time, before time.Time, limit int) int {
+	kept := (*rows)[:0]
+	deleted := 0
+	for _, at := range *rows {
+		if at.Before(before) && deleted < limit {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	*rows = kept
+	return deleted
+}
+
+func (m *mockRetentionStore) DeleteAuditLogsBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.auditCutoff = before
+	return deleteBefore(&m.auditLogs, before, limit), nil
+}
+
+func (m *mockRetentionStore) DeleteSessionsEndedBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.sessionCutoff = before
+	return deleteBefore(&m.sessions, before, limit), nil
+}
+
+func (m *mockRetentionStore) ListInactiveAccounts(ctx context.Context, cutoff time.Time, afterID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if afterID != uuid.Nil {
+		return ids, nil
+	}
+	for id, at := range m.lastActive {
+		if at.Before(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockRetentionStore) ScrubUserActivity(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.scrubbed = append(m.scrubbed, userID)
+	return 3, nil
+}
+
+type retentionFixture struct {
+	svc        *RetentionService
+	store      *mockRetentionStore
+	anonymizer *mockDormantAccountStore
+	rbac       *mockRBACStore
+	lifecycle  *lifecycleFixture
+	audited    []AuditLogParams
+	now        time.Time
+}
+
+func setupRetentionService(policy config.RetentionConfig, users ...*models.User) *retentionFixture {
+	f := &retentionFixture{
+		store:      &mockRetentionStore{lastActive: map[uuid.UUID]time.Time{}},
+		anonymizer: &mockDormantAccountStore{},
+		rbac:       &mockRBACStore{},
+		lifecycle:  setupUserLifecycleService(users...),
+		now:        time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.audited = append(f.audited, params) }}
+	f.svc = NewRetentionService(policy, f.store, f.anonymizer, f.rbac, f.lifecycle.svc, audit, testLogger())
+	f.svc.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestRetentionService_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Zero retention keeps everything", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateActive, IsActive: true}
+		f := setupRetentionService(config.RetentionConfig{}, user)
+		f.store.auditLogs = []time.Time{f.now.AddDate(-5, 0, 0)}
+		f.store.sessions = []time.Time{f.now.AddDate(-5, 0, 0)}
+		f.store.lastActive[user.ID] = f.now.AddDate(-5, 0, 0)
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.RetentionRunResult{}, *result)
+		assert.Len(t, f.store.auditLogs, 1)
+		assert.Len(t, f.store.sessions, 1)
+		assert.Empty(t, f.anonymizer.anonymized)
+	})
+
+	t.Run("Deletes audit logs and sessions past retention in batches", func(t *testing.T) {
+		f := setupRetentionService(config.RetentionConfig{AuditLogDays: 90, SessionDays: 30})
+		for i := 0; i < retentionBatchSize+5; i++ {
+			f.store.auditLogs = append(f.store.auditLogs, f.now.AddDate(0, 0, -100))
+		}
+		f.store.auditLogs = append(f.store.auditLogs, f.now.AddDate(0, 0, -10))
+		f.store.sessions = []time.Time{f.now.AddDate(0, 0, -31), f.now.AddDate(0, 0, -29)}
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, retentionBatchSize+5, result.AuditLogsDeleted)
+		assert.Equal(t, 1, result.SessionsDeleted)
+		assert.Len(t, f.store.auditLogs, 1)
+		assert.Len(t, f.store.sessions, 1)
+		assert.Equal(t, f.now.AddDate(0, 0, -90), f.store.auditCutoff)
+		assert.Equal(t, f.now.AddDate(0, 0, -30), f.store.sessionCutoff)
+	})
+
+	t.Run("Erases inactive accounts", func(t *testing.T) {
+		stale := &models.User{ID: uuid.New(), State: models.UserStateSuspended}
+		recent := &models.User{ID: uuid.New(), State: models.UserStateActive, IsActive: true}
+		f := setupRetentionService(config.RetentionConfig{InactiveAccountMonths: 24}, stale, recent)
+		f.store.lastActive[stale.ID] = f.now.AddDate(-3, 0, 0)
+		f.store.lastActive[recent.ID] = f.now.AddDate(0, -6, 0)
+
+		result, err := f.svc.Run(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.AccountsErased)
+		assert.Equal(t, []uuid.UUID{stale.ID}, f.anonymizer.anonymized)
+		assert.Equal(t, []uuid.UUID{stale.ID}, f.store.scrubbed)
+		assert.Equal(t, models.UserStateDeleted, f.lifecycle.store.users[stale.ID].State)
+		assert.Equal(t, models.UserStateActive, f.lifecycle.store.users[recent.ID].State)
+
+		require.NotEmpty(t, f.audited)
+		assert.Equal(t, models.ActionUserAnonymize, f.audited[0].Action)
+		assert.Equal(t, StateReasonRetention, f.audited[0].Details["reason"])
+	})
+}
+
+func TestRetentionService_EraseUser(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("Erases the user and keeps the row", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateActive, IsActive: true}
+		f := setupRetentionService(config.RetentionConfig{}, user)
+
+		result, err := f.svc.EraseUser(ctx, UserErasureParams{UserID: user.ID, ActorID: adminID, IP: "10.0.0.1"})
+		require.NoError(t, err)
+
+		assert.Equal(t, user.ID, result.UserID)
+		assert.Equal(t, f.now, result.ErasedAt)
+		assert.Equal(t, 3, result.AuditLogsScrubbed)
+		assert.Equal(t, []uuid.UUID{user.ID}, f.anonymizer.anonymized)
+		assert.Equal(t, models.UserStateDeleted, f.lifecycle.store.users[user.ID].State)
+
+		require.NotEmpty(t, f.audited)
+		assert.Equal(t, models.ActionUserAnonymize, f.audited[0].Action)
+		assert.Equal(t, StateReasonGDPRErasure, f.audited[0].Details["reason"])
+		assert.Equal(t, adminID.String(), f.audited[0].Details["actor_id"])
+	})
+
+	t.Run("Refuses to erase the caller", func(t *testing.T) {
+		f := setupRetentionService(config.RetentionConfig{})
+
+		_, err := f.svc.EraseUser(ctx, UserErasureParams{UserID: adminID, ActorID: adminID})
+		assert.ErrorIs(t, err, errEraseSelf)
+		assert.Empty(t, f.anonymizer.anonymized)
+	})
+
+	t.Run("Refuses to erase an administrator", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateActive, IsActive: true}
+		f := setupRetentionService(config.RetentionConfig{}, user)
+		f.rbac.GetUserRolesFunc = func(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+			return []models.Role{{Name: string(models.RoleAdmin)}}, nil
+		}
+
+		_, err := f.svc.EraseUser(ctx, UserErasureParams{UserID: user.ID, ActorID: adminID})
+		assert.ErrorIs(t, err, errEraseAdmin)
+		assert.Empty(t, f.anonymizer.anonymized)
+		assert.Equal(t, models.UserStateActive, f.lifecycle.store.users[user.ID].State)
+	})
+}
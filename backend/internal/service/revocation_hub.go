@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// revocationSubscriberBuffer is how many events a subscriber may fall behind before it is dropped
+const revocationSubscriberBuffer = 256
+
+var (
+	// ErrRevocationsMissed ends subscriptions when events from other instances may have been lost
+	ErrRevocationsMissed = errors.New("revocation events may have been missed")
+	// ErrRevocationSubscriberSlow ends a subscription that stopped keeping up with events
+	ErrRevocationSubscriberSlow = errors.New("revocation subscriber fell behind")
+	// ErrRevocationHubClosed ends subscriptions when the hub shuts down
+	ErrRevocationHubClosed = errors.New("revocation hub stopped")
+)
+
+// RevocationHub fans revocation events out to local subscribers, such as gRPC WatchRevocations
+// streams. Events are published through the bus so subscribers on every instance receive them.
+//
+// Delivery is best effort but never silently lossy: when a subscriber may have missed an event
+// its subscription ends with an error, and the subscriber is expected to resync.
+type RevocationHub struct {
+	bus    RevocationBus
+	logger *logger.Logger
+
+	mu          sync.Mutex
+	subscribers map[*RevocationSubscription]struct{}
+}
+
+// RevocationSubscription receives revocation events until it is closed or ends with an error
+type RevocationSubscription struct {
+	hub    *RevocationHub
+	userID *uuid.UUID
+	events chan models.RevocationEvent
+	done   chan struct{}
+	err    error
+}
+
+// NewRevocationHub creates a hub publishing through bus. A nil bus keeps events on this instance.
+func NewRevocationHub(bus RevocationBus, logger *logger.Logger) *RevocationHub {
+	return &RevocationHub{
+		bus:         bus,
+		logger:      logger,
+		subscribers: make(map[*RevocationSubscription]struct{}),
+	}
+}
+
+// Publish announces a revocation to subscribers on every instance. Safe to call on a nil hub.
+func (h *RevocationHub) Publish(ctx context.Context, event models.RevocationEvent) {
+	if h == nil {
+		return
+	}
+
+	if h.bus != nil {
+		err := h.bus.PublishRevocation(ctx, &event)
+		if err == nil {
+			// Delivered back to this instance through Run
+			return
+		}
+		h.logger.Warn("Failed to publish revocation event", map[string]interface{}{
+			"type":  string(event.Type),
+			"error": err.Error(),
+		})
+	}
+	h.broadcast(event)
+}
+
+// Run delivers events published on any instance to local subscribers until ctx is done.
+// Subscriptions open while the bus is interrupted end with ErrRevocationsMissed.
+func (h *RevocationHub) Run(ctx context.Context) {
+	if h.bus != nil {
+		h.bus.WatchRevocations(ctx, h.broadcast, func() {}, func() {
+			h.endAll(ErrRevocationsMissed)
+		})
+	} else {
+		<-ctx.Done()
+	}
+	h.endAll(ErrRevocationHubClosed)
+}
+
+// Subscribe starts receiving events. With a user ID only that user's events and "all" events
+// are delivered. Close the subscription when done.
+func (h *RevocationHub) Subscribe(userID *uuid.UUID) *RevocationSubscription {
+	sub := &RevocationSubscription{
+		hub:    h,
+		userID: userID,
+		events: make(chan models.RevocationEvent, revocationSubscriberBuffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Events delivers the subscribed events
+func (s *RevocationSubscription) Events() <-chan models.RevocationEvent {
+	return s.events
+}
+
+// Done is closed when the subscription ends; Err then reports why
+func (s *RevocationSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err reports why the subscription ended, or nil while it is active or after Close
+func (s *RevocationSubscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close stops the subscription
+func (s *RevocationSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.end(s, nil)
+}
+
+func (s *RevocationSubscription) matches(event models.RevocationEvent) bool {
+	if s.userID == nil || event.Type == models.RevocationTypeAll {
+		return true
+	}
+	return event.UserID != nil && *event.UserID == *s.userID
+}
+
+func (h *RevocationHub) broadcast(event models.RevocationEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.end(sub, ErrRevocationSubscriberSlow)
+		}
+	}
+}
+
+func (h *RevocationHub) endAll(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		h.end(sub, err)
+	}
+}
+
+// end removes sub and closes its done channel; h.mu must be held
+func (h *RevocationHub) end(sub *RevocationSubscription, err error) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	sub.err = err
+	close(sub.done)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRevocationBus loops published events back to the watcher, like Redis pub/sub
+type mockRevocationBus struct {
+	PublishErr  error
+	events      chan models.RevocationEvent
+	interrupted chan struct{}
+}
+
+func newMockRevocationBus() *mockRevocationBus {
+	return &mockRevocationBus{
+		events:      make(chan models.RevocationEvent, 16),
+		interrupted: make(chan struct{}, 1),
+	}
+}
+
+func (m *mockRevocationBus) PublishRevocation(ctx context.Context, event *models.RevocationEvent) error {
+	if m.PublishErr != nil {
+		return m.PublishErr
+	}
+	m.events <- *event
+	return nil
+}
+
+func (m *mockRevocationBus) WatchRevocations(ctx context.Context, onEvent func(event models.RevocationEvent), onSubscribed, onInterrupted func()) {
+	onSubscribed()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-m.events:
+			onEvent(event)
+		case <-m.interrupted:
+			onInterrupted()
+		}
+	}
+}
+
+func receiveRevocation(t *testing.T, sub *RevocationSubscription) models.RevocationEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no revocation event received")
+		return models.RevocationEvent{}
+	}
+}
+
+func TestRevocationHub(t *testing.T) {
+	log := logger.New("test", logger.ErrorLevel, false)
+
+	t.Run("Should deliver events published through the bus", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		hub := NewRevocationHub(newMockRevocationBus(), log)
+		go hub.Run(ctx)
+
+		sub := hub.Subscribe(nil)
+		defer sub.Close()
+		hub.Publish(ctx, models.RevocationEvent{Type: models.RevocationTypeToken, TokenHash: "hash"})
+
+		assert.Equal(t, "hash", receiveRevocation(t, sub).TokenHash)
+	})
+
+	t.Run("Should filter events by user", func(t *testing.T) {
+		hub := NewRevocationHub(nil, log)
+		userID, otherID := uuid.New(), uuid.New()
+		sub := hub.Subscribe(&userID)
+		defer sub.Close()
+
+		hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeUser, UserID: &otherID})
+		hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeUser, UserID: &userID})
+		hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeAll})
+
+		assert.Equal(t, &userID, receiveRevocation(t, sub).UserID)
+		assert.Equal(t, models.RevocationTypeAll, receiveRevocation(t, sub).Type)
+		assert.Empty(t, sub.Events())
+	})
+
+	t.Run("Should deliver locally when the bus fails", func(t *testing.T) {
+		bus := newMockRevocationBus()
+		bus.PublishErr = errors.New("redis down")
+		hub := NewRevocationHub(bus, log)
+		sub := hub.Subscribe(nil)
+		defer sub.Close()
+
+		hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeAll})
+
+		assert.Equal(t, models.RevocationTypeAll, receiveRevocation(t, sub).Type)
+	})
+
+	t.Run("Should end subscriptions when the bus is interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		bus := newMockRevocationBus()
+		hub := NewRevocationHub(bus, log)
+		go hub.Run(ctx)
+
+		sub := hub.Subscribe(nil)
+		bus.interrupted <- struct{}{}
+
+		select {
+		case <-sub.Done():
+		case <-time.After(time.Second):
+			t.Fatal("subscription not ended")
+		}
+		assert.ErrorIs(t, sub.Err(), ErrRevocationsMissed)
+	})
+
+	t.Run("Should drop subscribers that fall behind", func(t *testing.T) {
+		hub := NewRevocationHub(nil, log)
+		sub := hub.Subscribe(nil)
+
+		for i := 0; i <= revocationSubscriberBuffer; i++ {
+			hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeAll})
+		}
+
+		require.Len(t, sub.Events(), revocationSubscriberBuffer)
+		<-sub.Done()
+		assert.ErrorIs(t, sub.Err(), ErrRevocationSubscriberSlow)
+	})
+
+	t.Run("Should end without error when closed", func(t *testing.T) {
+		hub := NewRevocationHub(nil, log)
+		sub := hub.Subscribe(nil)
+		sub.Close()
+		sub.Close()
+
+		<-sub.Done()
+		assert.NoError(t, sub.Err())
+	})
+
+	t.Run("Should ignore publishing on a nil hub", func(t *testing.T) {
+		var hub *RevocationHub
+		hub.Publish(context.Background(), models.RevocationEvent{Type: models.RevocationTypeAll})
+	})
+}
+
+func TestTokenVersionService_ShouldPublishRevocations(t *testing.T) {
+	userID := uuid.New()
+	svc, _, _ := setupTokenVersionService(userID)
+	hub := NewRevocationHub(nil, logger.New("test", logger.ErrorLevel, false))
+	svc.SetRevocationHub(hub)
+	sub := hub.Subscribe(nil)
+	defer sub.Close()
+
+	_, err := svc.Bump(context.Background(), userID)
+	require.NoError(t, err)
+	event := receiveRevocation(t, sub)
+	assert.Equal(t, models.RevocationTypeUser, event.Type)
+	assert.Equal(t, &userID, event.UserID)
+
+	epoch, err := svc.InvalidateAll(context.Background(), nil)
+	require.NoError(t, err)
+	event = receiveRevocation(t, sub)
+	assert.Equal(t, models.RevocationTypeAll, event.Type)
+	assert.Equal(t, epoch.Unix(), event.RevokedAt.Unix())
+}
+
+func TestBlacklistService_ShouldPublishRevocations(t *testing.T) {
+	userID := uuid.New()
+	svc := NewBlacklistService(&mockCacheService{}, &mockTokenStore{}, &mockSessionStore{}, &mockTokenService{}, logger.New("test", logger.ErrorLevel, false), &mockAuditLogger{})
+	hub := NewRevocationHub(nil, logger.New("test", logger.ErrorLevel, false))
+	svc.SetRevocationHub(hub)
+	sub := hub.Subscribe(&userID)
+	defer sub.Close()
+
+	require.NoError(t, svc.AddToBlacklist(context.Background(), "hash", &userID, time.Hour))
+	event := receiveRevocation(t, sub)
+	assert.Equal(t, models.RevocationTypeToken, event.Type)
+	assert.Equal(t, "hash", event.TokenHash)
+	require.NotNil(t, event.ExpiresAt)
+
+	session := &models.Session{ID: uuid.New(), UserID: userID}
+	require.NoError(t, svc.BlacklistSessionTokens(context.Background(), session))
+	event = receiveRevocation(t, sub)
+	assert.Equal(t, models.RevocationTypeSession, event.Type)
+	assert.Equal(t, &session.ID, event.SessionID)
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	riskDevicesKeyPrefix      = "risk:devices:"
+	riskCountriesKeyPrefix    = "risk:countries:"
+	riskLastLocationKeyPrefix = "risk:last_location:"
+	riskAttemptsKeyPrefix     = "risk:attempts:"
+	riskHistoryTTL            = 90 * 24 * time.Hour // 90 days
+
+	// Hops shorter than this never raise impossible_travel; IP geolocation is too coarse
+	riskMinTravelDistanceKm = 100
+	// Sign-ins closer together are treated as this far apart, so a fast second sign-in
+	// does not divide by zero
+	riskMinTravelInterval = time.Minute
+
+	earthRadiusKm = 6371.0
+)
+
+var (
+	errRiskPolicyNotFound  = models.NewAppError(http.StatusNotFound, "Risk policy not found")
+	errSignInBlockedByRisk = models.NewAppError(http.StatusForbidden, "Sign-in blocked")
+)
+
+// riskLocation is the last sign-in location of a user
+type riskLocation struct {
+	Latitude  float64   `json:"lat"`
+	Longitude float64   `json:"lon"`
+	At        time.Time `json:"at"`
+}
+
+// RiskService scores sign-in attempts. It compares each attempt with the user's sign-in
+// history - known devices, known countries and the last location - and with the Tor exit
+// list and the user's recent attempts, then weighs the raised signals with the risk policy
+// of the application (or the global policy) to allow the attempt, require 2FA or block it.
+// Sign-ins are never blocked because the engine itself failed: errors are logged and the
+// attempt is allowed.
+type RiskService struct {
+	store  RiskPolicyStore
+	state  RiskStateStore
+	geo    GeoLocator   // nil disables new_geo and impossible_travel
+	tor    *TorExitList // nil disables tor_exit_node
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewRiskService creates a new risk service
+func NewRiskService(store RiskPolicyStore, state RiskStateStore, geo GeoLocator, tor *TorExitList, logger *logger.Logger) *RiskService {
+	return &RiskService{
+		store:  store,
+		state:  state,
+		geo:    geo,
+		tor:    tor,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Assess scores a sign-in attempt of a user whose password was verified and decides
+// whether to allow it, require 2FA or block it. Step-up for users without 2FA resolves
+// to the policy's step_up_fallback. Returns nil when no active policy applies.
+func (s *RiskService) Assess(ctx context.Context, user *models.User, appID *uuid.UUID, ip string, device models.DeviceInfo) *models.RiskAssessment {
+	policy := s.activePolicy(ctx, appID)
+	if policy == nil {
+		return nil
+	}
+
+	signals := make([]string, 0, len(models.RiskSignals))
+	if s.isNewMember(ctx, riskDevicesKeyPrefix+user.ID.String(), computeFingerprint(device)) {
+		signals = append(signals, models.RiskSignalNewDevice)
+	}
+
+	if location := s.locate(ctx, ip); location != nil {
+		if location.CountryCode != "" && s.isNewMember(ctx, riskCountriesKeyPrefix+user.ID.String(), location.CountryCode) {
+			signals = append(signals, models.RiskSignalNewGeo)
+		}
+		if s.isImpossibleTravel(ctx, user.ID, location, policy.MaxTravelSpeedKmh) {
+			signals = append(signals, models.RiskSignalImpossibleTravel)
+		}
+	}
+
+	if s.tor != nil && s.tor.Contains(ip) {
+		signals = append(signals, models.RiskSignalTorExitNode)
+	}
+
+	if s.countAttempt(ctx, policy, user.ID) {
+		signals = append(signals, models.RiskSignalVelocity)
+	}
+
+	assessment := &models.RiskAssessment{
+		PolicyID: policy.ID,
+		Signals:  signals,
+		Action:   models.RiskActionAllow,
+	}
+	for _, signal := range signals {
+		assessment.Score += policy.Weights[signal]
+	}
+
+	switch {
+	case policy.BlockThreshold > 0 && assessment.Score >= policy.BlockThreshold:
+		assessment.Action = models.RiskActionBlock
+	case policy.StepUpThreshold > 0 && assessment.Score >= policy.StepUpThreshold:
+		assessment.Action = models.RiskActionStepUp
+		if !user.TwoFactorEnabled() {
+			assessment.Action = policy.StepUpFallback
+		}
+	}
+
+	return assessment
+}
+
+// RecordSignIn adds the device and location of a successful sign-in to the user's history
+func (s *RiskService) RecordSignIn(ctx context.Context, userID uuid.UUID, ip string, device models.DeviceInfo) {
+	s.addMember(ctx, riskDevicesKeyPrefix+userID.String(), computeFingerprint(device))
+
+	location := s.locate(ctx, ip)
+	if location == nil {
+		return
+	}
+	if location.CountryCode != "" {
+		s.addMember(ctx, riskCountriesKeyPrefix+userID.String(), location.CountryCode)
+	}
+	if hasCoordinates(location) {
+		data, err := json.Marshal(riskLocation{
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+			At:        s.now(),
+		})
+		if err == nil {
+			err = s.state.Set(ctx, riskLastLocationKeyPrefix+userID.String(), data, riskHistoryTTL)
+		}
+		if err != nil {
+			s.warn("Failed to record sign-in location", err)
+		}
+	}
+}
+
+// RecordFailedAttempt counts a sign-in attempt with a wrong password towards the user's
+// velocity
+func (s *RiskService) RecordFailedAttempt(ctx context.Context, userID uuid.UUID, appID *uuid.UUID) {
+	if policy := s.activePolicy(ctx, appID); policy != nil {
+		s.countAttempt(ctx, policy, userID)
+	}
+}
+
+// CreatePolicy creates a risk policy
+func (s *RiskService) CreatePolicy(ctx context.Context, req *models.CreateRiskPolicyRequest) (*models.RiskPolicy, error) {
+	policy := &models.RiskPolicy{
+		Name:                  strings.TrimSpace(req.Name),
+		Description:           req.Description,
+		ApplicationID:         req.ApplicationID,
+		Weights:               req.Weights,
+		StepUpThreshold:       req.StepUpThreshold,
+		BlockThreshold:        req.BlockThreshold,
+		StepUpFallback:        req.StepUpFallback,
+		MaxTravelSpeedKmh:     req.MaxTravelSpeedKmh,
+		VelocityMaxAttempts:   req.VelocityMaxAttempts,
+		VelocityWindowSeconds: req.VelocityWindowSeconds,
+		IsActive:              true,
+	}
+	if policy.StepUpFallback == "" {
+		policy.StepUpFallback = models.RiskActionAllow
+	}
+	if policy.MaxTravelSpeedKmh == 0 {
+		policy.MaxTravelSpeedKmh = 1000
+	}
+	if policy.VelocityWindowSeconds == 0 {
+		policy.VelocityWindowSeconds = 600
+	}
+	if err := validateRiskPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateRiskPolicy(ctx, policy); err != nil {
+		if errors.Is(err, models.ErrAlreadyExists) {
+			return nil, models.NewAppError(http.StatusConflict, "Risk policy with this name or application already exists")
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdatePolicy updates a risk policy. Its name and application cannot change.
+func (s *RiskService) UpdatePolicy(ctx context.Context, id uuid.UUID, req *models.UpdateRiskPolicyRequest) (*models.RiskPolicy, error) {
+	policy, err := s.getPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		policy.Description = *req.Description
+	}
+	if req.Weights != nil {
+		policy.Weights = req.Weights
+	}
+	if req.StepUpThreshold != nil {
+		policy.StepUpThreshold = *req.StepUpThreshold
+	}
+	if req.BlockThreshold != nil {
+		policy.BlockThreshold = *req.BlockThreshold
+	}
+	if req.StepUpFallback != nil {
+		policy.StepUpFallback = *req.StepUpFallback
+	}
+	if req.MaxTravelSpeedKmh != nil {
+		policy.MaxTravelSpeedKmh = *req.MaxTravelSpeedKmh
+	}
+	if req.VelocityMaxAttempts != nil {
+		policy.VelocityMaxAttempts = *req.VelocityMaxAttempts
+	}
+	if req.VelocityWindowSeconds != nil {
+		policy.VelocityWindowSeconds = *req.VelocityWindowSeconds
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	if err := validateRiskPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateRiskPolicy(ctx, policy); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errRiskPolicyNotFound
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeletePolicy deletes a risk policy and returns it
+func (s *RiskService) DeletePolicy(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error) {
+	policy, err := s.getPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.DeleteRiskPolicy(ctx, id); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errRiskPolicyNotFound
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// GetPolicy returns a risk policy
+func (s *RiskService) GetPolicy(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error) {
+	return s.getPolicy(ctx, id)
+}
+
+// ListPolicies lists all risk policies
+func (s *RiskService) ListPolicies(ctx context.Context) ([]*models.RiskPolicy, error) {
+	return s.store.ListRiskPolicies(ctx)
+}
+
+func validateRiskPolicy(policy *models.RiskPolicy) error {
+	if len(policy.Weights) == 0 {
+		return models.NewAppError(http.StatusBadRequest, "weights must score at least one signal")
+	}
+	if policy.StepUpThreshold == 0 && policy.BlockThreshold == 0 {
+		return models.NewAppError(http.StatusBadRequest, "step_up_threshold or block_threshold is required")
+	}
+	if policy.StepUpThreshold > 0 && policy.BlockThreshold > 0 && policy.StepUpThreshold >= policy.BlockThreshold {
+		return models.NewAppError(http.StatusBadRequest, "step_up_threshold must be below block_threshold")
+	}
+	if policy.StepUpFallback != models.RiskActionAllow && policy.StepUpFallback != models.RiskActionBlock {
+		return models.NewAppError(http.StatusBadRequest, "step_up_fallback must be allow or block")
+	}
+	if policy.VelocityMaxAttempts > 0 && policy.VelocityWindowSeconds <= 0 {
+		return models.NewAppError(http.StatusBadRequest, "velocity_window_seconds is required with velocity_max_attempts")
+	}
+	return nil
+}
+
+func (s *RiskService) getPolicy(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error) {
+	policy, err := s.store.GetRiskPolicyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errRiskPolicyNotFound
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// activePolicy returns the policy sign-ins to the application are scored by, or nil
+func (s *RiskService) activePolicy(ctx context.Context, appID *uuid.UUID) *models.RiskPolicy {
+	policy, err := s.store.GetActiveRiskPolicy(ctx, appID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.warn("Failed to load risk policy", err)
+		}
+		return nil
+	}
+	return policy
+}
+
+// isNewMember reports whether member is missing from a history set that already has
+// entries. The first sign-in only establishes the history.
+func (s *RiskService) isNewMember(ctx context.Context, key, member string) bool {
+	exists, err := s.state.Exists(ctx, key)
+	if err != nil {
+		s.warn("Failed to read sign-in history", err)
+		return false
+	}
+	if !exists {
+		return false
+	}
+	known, err := s.state.SIsMember(ctx, key, member)
+	if err != nil {
+		s.warn("Failed to read sign-in history", err)
+		return false
+	}
+	return !known
+}
+
+func (s *RiskService) addMember(ctx context.Context, key, member string) {
+	if err := s.state.SAdd(ctx, key, member); err != nil {
+		s.warn("Failed to record sign-in history", err)
+		return
+	}
+	if err := s.state.Expire(ctx, key, riskHistoryTTL); err != nil {
+		s.warn("Failed to record sign-in history", err)
+	}
+}
+
+// isImpossibleTravel reports whether reaching location since the user's last sign-in
+// needed a speed above maxSpeedKmh
+func (s *RiskService) isImpossibleTravel(ctx context.Context, userID uuid.UUID, location *models.GeoLocation, maxSpeedKmh int) bool {
+	if maxSpeedKmh <= 0 || !hasCoordinates(location) {
+		return false
+	}
+
+	data, err := s.state.Get(ctx, riskLastLocationKeyPrefix+userID.String())
+	if err != nil {
+		// A missing key means there is no previous location
+		return false
+	}
+	var last riskLocation
+	if err := json.Unmarshal([]byte(data), &last); err != nil {
+		return false
+	}
+
+	distance := haversineKm(last.Latitude, last.Longitude, location.Latitude, location.Longitude)
+	if distance < riskMinTravelDistanceKm {
+		return false
+	}
+	elapsed := s.now().Sub(last.At)
+	if elapsed < riskMinTravelInterval {
+		elapsed = riskMinTravelInterval
+	}
+	return distance/elapsed.Hours() > float64(maxSpeedKmh)
+}
+
+// countAttempt records a sign-in attempt and reports whether the user made more attempts
+// than the policy allows in its velocity window
+func (s *RiskService) countAttempt(ctx context.Context, policy *models.RiskPolicy, userID uuid.UUID) bool {
+	if policy.VelocityMaxAttempts <= 0 || policy.VelocityWindowSeconds <= 0 {
+		return false
+	}
+	result, err := s.state.SlidingWindowRateLimit(ctx, riskAttemptsKeyPrefix+userID.String(),
+		policy.VelocityMaxAttempts, time.Duration(policy.VelocityWindowSeconds)*time.Second)
+	if err != nil {
+		s.warn("Failed to count sign-in attempt", err)
+		return false
+	}
+	return !result.Allowed
+}
+
+func (s *RiskService) locate(ctx context.Context, ip string) *models.GeoLocation {
+	if s.geo == nil || ip == "" {
+		return nil
+	}
+	return s.geo.GetLocation(ctx, ip)
+}
+
+func (s *RiskService) warn(msg string, err error) {
+	s.logger.Warn(msg, map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func hasCoordinates(location *models.GeoLocation) bool {
+	return location.Latitude != 0 || location.Longitude != 0
+}
+
+// haversineKm returns the great-circle distance between two points in kilometres
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// TorExitList holds the addresses of Tor exit nodes, loaded from a plain-text list with
+// one address per line such as https://check.torproject.org/torbulkexitlist
+type TorExitList struct {
+	url    string
+	client *http.Client
+	mu     sync.RWMutex
+	addrs  map[string]struct{}
+}
+
+// NewTorExitList creates an empty Tor exit list loaded from url by Refresh
+func NewTorExitList(url string) *TorExitList {
+	return &TorExitList{
+		url:    url,
+		client: &http.Client{Timeout: 30 * time.Second},
+		addrs:  make(map[string]struct{}),
+	}
+}
+
+// Refresh replaces the list with the current contents of its URL
+func (l *TorExitList) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tor exit list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch tor exit list: status %d", resp.StatusCode)
+	}
+
+	addrs := make(map[string]struct{})
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if ip := net.ParseIP(line); ip != nil {
+			addrs[ip.String()] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read tor exit list: %w", err)
+	}
+
+	l.mu.Lock()
+	l.addrs = addrs
+	l.mu.Unlock()
+	return nil
+}
+
+// Contains reports whether ip is a Tor exit node
+func (l *TorExitList) Contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.addrs[parsed.String()]
+	return ok
+}
+
+// Len returns the number of addresses in the list
+func (l *TorExitList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.addrs)
+}
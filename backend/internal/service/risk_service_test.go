@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRiskPolicyStore struct {
+	policies map[uuid.UUID]*models.RiskPolicy
+}
+
+func newMockRiskPolicyStore(policies ...*models.RiskPolicy) *mockRiskPolicyStore {
+	m := &mockRiskPolicyStore{policies: make(map[uuid.UUID]*models.RiskPolicy)}
+	for _, policy := range policies {
+		m.policies[policy.ID] = policy
+	}
+	return m
+}
+
+func (m *mockRiskPolicyStore) CreateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error {
+	for _, existing := range m.policies {
+		if existing.Name == policy.Name {
+			return models.ErrAlreadyExists
+		}
+	}
+	policy.ID = uuid.New()
+	m.policies[policy.ID] = policy
+	return nil
+}
+
+func (m *mockRiskPolicyStore) GetRiskPolicyByID(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error) {
+	if policy, ok := m.policies[id]; ok {
+		copied := *policy
+		return &copied, nil
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockRiskPolicyStore) GetActiveRiskPolicy(ctx context.Context, appID *uuid.UUID) (*models.RiskPolicy, error) {
+	var global *models.RiskPolicy
+	for _, policy := range m.policies {
+		if !policy.IsActive {
+			continue
+		}
+		if policy.ApplicationID == nil {
+			global = policy
+		} else if appID != nil && *policy.ApplicationID == *appID {
+			return policy, nil
+		}
+	}
+	if global == nil {
+		return nil, models.ErrNotFound
+	}
+	return global, nil
+}
+
+func (m *mockRiskPolicyStore) ListRiskPolicies(ctx context.Context) ([]*models.RiskPolicy, error) {
+	policies := make([]*models.RiskPolicy, 0, len(m.policies))
+	for _, policy := range m.policies {
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (m *mockRiskPolicyStore) UpdateRiskPolicy(ctx context.Context, policy *models.RiskPolicy) error {
+	if _, ok := m.policies[policy.ID]; !ok {
+		return models.ErrNotFound
+	}
+	m.policies[policy.ID] = policy
+	return nil
+}
+
+func (m *mockRiskPolicyStore) DeleteRiskPolicy(ctx context.Context, id uuid.UUID) error {
+	if _, ok := m.policies[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(m.policies, id)
+	return nil
+}
+
+// mockRiskState keeps sets, strings and attempt counters in memory
+type mockRiskState struct {
+	sets     map[string]map[string]bool
+	values   map[string]string
+	attempts map[string]int
+	err      error
+}
+
+func newMockRiskState() *mockRiskState {
+	return &mockRiskState{
+		sets:     make(map[string]map[string]bool),
+		values:   make(map[string]string),
+		attempts: make(map[string]int),
+	}
+}
+
+func (m *mockRiskState) SAdd(ctx context.Context, key string, members ...string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.sets[key] == nil {
+		m.sets[key] = make(map[string]bool)
+	}
+	for _, member := range members {
+		m.sets[key][member] = true
+	}
+	return nil
+}
+
+func (m *mockRiskState) SIsMember(ctx context.Context, key string, member string) (bool, error) {
+	return m.sets[key][member], m.err
+}
+
+func (m *mockRiskState) Exists(ctx context.Context, key string) (bool, error) {
+	_, isSet := m.sets[key]
+	_, isValue := m.values[key]
+	return isSet || isValue, m.err
+}
+
+func (m *mockRiskState) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return m.err
+}
+
+func (m *mockRiskState) Get(ctx context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, m.err
+}
+
+func (m *mockRiskState) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.values[key] = fmt.Sprintf("%s", value)
+	return nil
+}
+
+func (m *mockRiskState) SlidingWindowRateLimit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.attempts[key] >= limit {
+		return &RateLimitResult{Allowed: false}, nil
+	}
+	m.attempts[key]++
+	return &RateLimitResult{Allowed: true, Remaining: limit - m.attempts[key]}, nil
+}
+
+// mockGeoLocator resolves IPs from a fixed table
+type mockGeoLocator map[string]*models.GeoLocation
+
+func (m mockGeoLocator) GetLocation(ctx context.Context, ip string) *models.GeoLocation {
+	return m[ip]
+}
+
+var (
+	riskTestBerlin = "198.51.100.1"
+	riskTestTokyo  = "203.0.113.1"
+	riskTestDevice = models.DeviceInfo{DeviceType: "desktop", OS: "macOS 14.2", Browser: "Chrome 120.0"}
+	riskTestPhone  = models.DeviceInfo{DeviceType: "mobile", OS: "iOS 17.2", Browser: "Safari 17.0"}
+)
+
+type riskFixture struct {
+	service *RiskService
+	store   *mockRiskPolicyStore
+	state   *mockRiskState
+	policy  *models.RiskPolicy
+	now     time.Time
+}
+
+func newRiskFixture(tor *TorExitList) *riskFixture {
+	policy := &models.RiskPolicy{
+		ID:   uuid.New(),
+		Name: "default",
+		Weights: map[string]int{
+			models.RiskSignalNewDevice:        20,
+			models.RiskSignalNewGeo:           30,
+			models.RiskSignalImpossibleTravel: 60,
+			models.RiskSignalTorExitNode:      50,
+			models.RiskSignalVelocity:         40,
+		},
+		StepUpThreshold:       20,
+		BlockThreshold:        80,
+		StepUpFallback:        models.RiskActionAllow,
+		MaxTravelSpeedKmh:     1000,
+		VelocityMaxAttempts:   3,
+		VelocityWindowSeconds: 600,
+		IsActive:              true,
+	}
+	f := &riskFixture{
+		store:  newMockRiskPolicyStore(policy),
+		state:  newMockRiskState(),
+		policy: policy,
+		now:    time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC),
+	}
+	geo := mockGeoLocator{
+		riskTestBerlin: {CountryCode: "DE", Latitude: 52.52, Longitude: 13.405},
+		riskTestTokyo:  {CountryCode: "JP", Latitude: 35.6762, Longitude: 139.6503},
+	}
+	f.service = NewRiskService(f.store, f.state, geo, tor, logger.New("test", logger.InfoLevel, false))
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestRiskService_Assess_ShouldAllowFirstSignIn(t *testing.T) {
+	f := newRiskFixture(nil)
+	user := &models.User{ID: uuid.New()}
+
+	assessment := f.service.Assess(context.Background(), user, nil, riskTestBerlin, riskTestDevice)
+
+	require.NotNil(t, assessment)
+	assert.Empty(t, assessment.Signals)
+	assert.Equal(t, models.RiskActionAllow, assessment.Action)
+	assert.Equal(t, f.policy.ID, assessment.PolicyID)
+}
+
+func TestRiskService_Assess_ShouldReturnNil_WithoutPolicy(t *testing.T) {
+	f := newRiskFixture(nil)
+	f.policy.IsActive = false
+
+	assert.Nil(t, f.service.Assess(context.Background(), &models.User{ID: uuid.New()}, nil, riskTestBerlin, riskTestDevice))
+}
+
+func TestRiskService_Assess_ShouldStepUp_OnNewDevice(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+	user := &models.User{ID: uuid.New(), TOTPEnabled: true}
+	f.service.RecordSignIn(ctx, user.ID, riskTestBerlin, riskTestDevice)
+
+	// A newer browser version is the same device
+	sameDevice := riskTestDevice
+	sameDevice.Browser = "Chrome 121.0"
+	assessment := f.service.Assess(ctx, user, nil, riskTestBerlin, sameDevice)
+	assert.Empty(t, assessment.Signals)
+
+	assessment = f.service.Assess(ctx, user, nil, riskTestBerlin, riskTestPhone)
+	assert.Equal(t, []string{models.RiskSignalNewDevice}, assessment.Signals)
+	assert.Equal(t, 20, assessment.Score)
+	assert.Equal(t, models.RiskActionStepUp, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldApplyFallback_WhenUserHasNo2FA(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+	user := &models.User{ID: uuid.New()}
+	f.service.RecordSignIn(ctx, user.ID, riskTestBerlin, riskTestDevice)
+
+	assessment := f.service.Assess(ctx, user, nil, riskTestBerlin, riskTestPhone)
+	assert.Equal(t, models.RiskActionAllow, assessment.Action)
+
+	f.policy.StepUpFallback = models.RiskActionBlock
+	assessment = f.service.Assess(ctx, user, nil, riskTestBerlin, riskTestPhone)
+	assert.Equal(t, models.RiskActionBlock, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldBlock_OnImpossibleTravel(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+	user := &models.User{ID: uuid.New(), TOTPEnabled: true}
+	f.service.RecordSignIn(ctx, user.ID, riskTestBerlin, riskTestDevice)
+
+	// Berlin to Tokyo is about 8900 km
+	f.now = f.now.Add(2 * time.Hour)
+	assessment := f.service.Assess(ctx, user, nil, riskTestTokyo, riskTestDevice)
+	assert.ElementsMatch(t, []string{models.RiskSignalNewGeo, models.RiskSignalImpossibleTravel}, assessment.Signals)
+	assert.Equal(t, 90, assessment.Score)
+	assert.Equal(t, models.RiskActionBlock, assessment.Action)
+
+	// A day later the flight is possible
+	f.now = f.now.Add(22 * time.Hour)
+	assessment = f.service.Assess(ctx, user, nil, riskTestTokyo, riskTestDevice)
+	assert.Equal(t, []string{models.RiskSignalNewGeo}, assessment.Signals)
+	assert.Equal(t, models.RiskActionStepUp, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldRaiseVelocity_IncludingFailedAttempts(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+	user := &models.User{ID: uuid.New(), TOTPEnabled: true}
+
+	for i := 0; i < 3; i++ {
+		f.service.RecordFailedAttempt(ctx, user.ID, nil)
+	}
+
+	assessment := f.service.Assess(ctx, user, nil, riskTestBerlin, riskTestDevice)
+	assert.Equal(t, []string{models.RiskSignalVelocity}, assessment.Signals)
+	assert.Equal(t, models.RiskActionStepUp, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldUseApplicationPolicy(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+	appID := uuid.New()
+	appPolicy := &models.RiskPolicy{
+		ID:             uuid.New(),
+		ApplicationID:  &appID,
+		Weights:        map[string]int{models.RiskSignalNewDevice: 90},
+		BlockThreshold: 80,
+		StepUpFallback: models.RiskActionAllow,
+		IsActive:       true,
+	}
+	f.store.policies[appPolicy.ID] = appPolicy
+	user := &models.User{ID: uuid.New()}
+	f.service.RecordSignIn(ctx, user.ID, riskTestBerlin, riskTestDevice)
+
+	assessment := f.service.Assess(ctx, user, &appID, riskTestBerlin, riskTestPhone)
+	assert.Equal(t, appPolicy.ID, assessment.PolicyID)
+	assert.Equal(t, models.RiskActionBlock, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldFailOpen_WhenStateUnavailable(t *testing.T) {
+	f := newRiskFixture(nil)
+	f.state.err = errors.New("connection refused")
+
+	assessment := f.service.Assess(context.Background(), &models.User{ID: uuid.New()}, nil, riskTestBerlin, riskTestDevice)
+
+	require.NotNil(t, assessment)
+	assert.Empty(t, assessment.Signals)
+	assert.Equal(t, models.RiskActionAllow, assessment.Action)
+}
+
+func TestRiskService_Assess_ShouldRaiseTorExitNode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# exit nodes\n192.0.2.10\n\n2001:db8::1\nnot-an-ip\n")
+	}))
+	defer server.Close()
+	tor := NewTorExitList(server.URL)
+	require.NoError(t, tor.Refresh(context.Background()))
+	assert.Equal(t, 2, tor.Len())
+	assert.True(t, tor.Contains("2001:0db8::0001"))
+
+	f := newRiskFixture(tor)
+	assessment := f.service.Assess(context.Background(), &models.User{ID: uuid.New()}, nil, "192.0.2.10", riskTestDevice)
+
+	assert.Equal(t, []string{models.RiskSignalTorExitNode}, assessment.Signals)
+	assert.Equal(t, 50, assessment.Score)
+}
+
+func TestRiskService_CreatePolicy(t *testing.T) {
+	f := newRiskFixture(nil)
+	ctx := context.Background()
+
+	t.Run("Defaults", func(t *testing.T) {
+		policy, err := f.service.CreatePolicy(ctx, &models.CreateRiskPolicyRequest{
+			Name:            " strict ",
+			Weights:         map[string]int{models.RiskSignalTorExitNode: 100},
+			BlockThreshold:  50,
+			StepUpThreshold: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "strict", policy.Name)
+		assert.Equal(t, models.RiskActionAllow, policy.StepUpFallback)
+		assert.Equal(t, 1000, policy.MaxTravelSpeedKmh)
+		assert.True(t, policy.IsActive)
+	})
+
+	t.Run("ThresholdsOutOfOrder", func(t *testing.T) {
+		_, err := f.service.CreatePolicy(ctx, &models.CreateRiskPolicyRequest{
+			Name:            "backwards",
+			Weights:         map[string]int{models.RiskSignalTorExitNode: 100},
+			StepUpThreshold: 50,
+			BlockThreshold:  50,
+		})
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	})
+
+	t.Run("NoThreshold", func(t *testing.T) {
+		_, err := f.service.CreatePolicy(ctx, &models.CreateRiskPolicyRequest{
+			Name:    "toothless",
+			Weights: map[string]int{models.RiskSignalTorExitNode: 100},
+		})
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		_, err := f.service.CreatePolicy(ctx, &models.CreateRiskPolicyRequest{
+			Name:           "default",
+			Weights:        map[string]int{models.RiskSignalTorExitNode: 100},
+			BlockThreshold: 50,
+		})
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.Code)
+	})
+}
+
+func TestRiskService_UpdatePolicy_ShouldReturn404_WhenMissing(t *testing.T) {
+	f := newRiskFixture(nil)
+
+	_, err := f.service.UpdatePolicy(context.Background(), uuid.New(), &models.UpdateRiskPolicyRequest{IsActive: utils.Ptr(false)})
+
+	assert.Equal(t, errRiskPolicyNotFound, err)
+}
+
+// mockRiskAssessor returns a fixed assessment and records sign-ins
+type mockRiskAssessor struct {
+	assessment *models.RiskAssessment
+	signIns    int
+	failures   int
+}
+
+func (m *mockRiskAssessor) Assess(ctx context.Context, user *models.User, appID *uuid.UUID, ip string, device models.DeviceInfo) *models.RiskAssessment {
+	return m.assessment
+}
+
+func (m *mockRiskAssessor) RecordSignIn(ctx context.Context, userID uuid.UUID, ip string, device models.DeviceInfo) {
+	m.signIns++
+}
+
+func (m *mockRiskAssessor) RecordFailedAttempt(ctx context.Context, userID uuid.UUID, appID *uuid.UUID) {
+	m.failures++
+}
+
+func TestAuthService_SignIn_RiskEngine(t *testing.T) {
+	svc, mUser, _, _, mAudit, _, _, _, _ := setupAuthService()
+	risk := &mockRiskAssessor{}
+	svc.SetRiskEngine(risk)
+	ctx := context.Background()
+
+	hash, _ := utils.HashPassword("password123", 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: hash, IsActive: true, EmailVerified: true}
+	mUser.GetByEmailFunc = func(ctx context.Context, email string, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return user, nil
+	}
+	var audited []AuditLogParams
+	mAudit.LogFunc = func(params AuditLogParams) { audited = append(audited, params) }
+
+	t.Run("WrongPasswordCountsAttempt", func(t *testing.T) {
+		_, err := svc.SignIn(ctx, &models.SignInRequest{Email: user.Email, Password: "wrong"}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Equal(t, models.ErrInvalidCredentials, err)
+		assert.Equal(t, 1, risk.failures)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		audited = nil
+		risk.assessment = &models.RiskAssessment{Score: 90, Signals: []string{models.RiskSignalImpossibleTravel}, Action: models.RiskActionBlock}
+
+		resp, err := svc.SignIn(ctx, &models.SignInRequest{Email: user.Email, Password: "password123"}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		assert.Nil(t, resp)
+		assert.Equal(t, errSignInBlockedByRisk, err)
+		require.Len(t, audited, 1)
+		assert.Equal(t, models.ActionRiskBlock, audited[0].Action)
+		assert.Equal(t, 90, audited[0].Details["score"])
+		assert.Zero(t, risk.signIns)
+	})
+
+	t.Run("StepUpRequires2FA", func(t *testing.T) {
+		audited = nil
+		user.TOTPEnabled = true
+		risk.assessment = &models.RiskAssessment{Score: 20, Signals: []string{models.RiskSignalNewDevice}, Action: models.RiskActionStepUp}
+
+		resp, err := svc.SignIn(ctx, &models.SignInRequest{Email: user.Email, Password: "password123"}, "1.1.1.1", "ua", models.DeviceInfo{}, nil)
+		require.NoError(t, err)
+		assert.True(t, resp.Requires2FA)
+		require.Len(t, audited, 1)
+		assert.Equal(t, models.ActionRiskStepUp, audited[0].Action)
+	})
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/google/uuid"
@@ -155,7 +158,7 @@ func (s *SCIMService) UpdateUser(ctx context.Context, id string, scimUser *model
 	}
 	user.Username = utils.SanitizeUsername(scimUser.UserName)
 	user.FullName = utils.SanitizeHTML(s.formatSCIMName(scimUser.Name))
-	user.IsActive = scimUser.Active
+	user.SetActive(scimUser.Active)
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
@@ -208,12 +211,12 @@ func (s *SCIMService) DeleteUser(ctx context.Context, id string) error {
 		return models.NewAppError(400, "Invalid user ID")
 	}
 
-	// Soft delete by setting is_active = false
+	// Soft delete: the user moves to the deleted state
 	user, err := s.userRepo.GetByID(ctx, userID, nil)
 	if err != nil {
 		return err
 	}
-	user.IsActive = false
+	user.SetState(models.UserStateDeleted)
 	return s.userRepo.Update(ctx, user)
 }
 
@@ -266,8 +269,280 @@ func (s *SCIMService) GetGroup(ctx context.Context, id string) (*models.SCIMGrou
 	return s.groupToSCIM(group), nil
 }
 
+// scimMemberFilterPath matches PATCH paths that select a single member, e.g. members[value eq "id"]
+var scimMemberFilterPath = regexp.MustCompile(`^members\[value eq "([^"]+)"\]$`)
+
+// CreateGroup creates a group from SCIM format along with its members
+func (s *SCIMService) CreateGroup(ctx context.Context, scimGroup *models.SCIMGroup) (*models.SCIMGroup, error) {
+	name := strings.TrimSpace(scimGroup.DisplayName)
+	if name == "" {
+		return nil, models.NewAppError(http.StatusBadRequest, "displayName is required")
+	}
+
+	memberIDs, err := s.resolveSCIMMembers(ctx, scimGroup.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.Group{
+		Name:        name,
+		DisplayName: utils.SanitizeHTML(name),
+	}
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		if errors.Is(err, models.ErrAlreadyExists) {
+			return nil, models.NewAppError(http.StatusConflict, "Group already exists")
+		}
+		return nil, err
+	}
+
+	if err := s.setGroupMembers(ctx, group.ID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	return s.groupToSCIM(group), nil
+}
+
+// ReplaceGroup replaces the display name and members of a group (PUT - full update)
+func (s *SCIMService) ReplaceGroup(ctx context.Context, id string, scimGroup *models.SCIMGroup) (*models.SCIMGroup, error) {
+	group, err := s.getSCIMGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.resolveSCIMMembers(ctx, scimGroup.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(scimGroup.DisplayName); name != "" && name != group.DisplayName {
+		group.DisplayName = utils.SanitizeHTML(name)
+		if err := s.groupRepo.Update(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.setGroupMembers(ctx, group.ID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	return s.groupToSCIM(group), nil
+}
+
+// PatchGroup applies SCIM PATCH operations to a group. Supported are adding and removing
+// members and replacing the display name or the whole member list.
+func (s *SCIMService) PatchGroup(ctx context.Context, id string, patchReq *models.SCIMPatchRequest) (*models.SCIMGroup, error) {
+	group, err := s.getSCIMGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range patchReq.Operations {
+		path := strings.TrimSpace(op.Path)
+		switch strings.ToLower(op.Op) {
+		case "add":
+			if !strings.EqualFold(path, "members") {
+				return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unsupported add path: %s", op.Path))
+			}
+			memberIDs, err := s.resolveSCIMMembers(ctx, scimMembersFromValue(op.Value))
+			if err != nil {
+				return nil, err
+			}
+			for _, userID := range memberIDs {
+				if err := s.groupRepo.AddUser(ctx, group.ID, userID); err != nil {
+					return nil, err
+				}
+			}
+
+		case "remove":
+			var memberIDs []uuid.UUID
+			if match := scimMemberFilterPath.FindStringSubmatch(path); match != nil {
+				userID, err := uuid.Parse(match[1])
+				if err != nil {
+					return nil, models.NewAppError(http.StatusBadRequest, "Invalid member ID")
+				}
+				memberIDs = []uuid.UUID{userID}
+			} else if strings.EqualFold(path, "members") {
+				if op.Value == nil {
+					if memberIDs, err = s.currentMemberIDs(ctx, group.ID); err != nil {
+						return nil, err
+					}
+				} else if memberIDs, err = parseSCIMMemberIDs(scimMembersFromValue(op.Value)); err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unsupported remove path: %s", op.Path))
+			}
+			for _, userID := range memberIDs {
+				if err := s.groupRepo.RemoveUser(ctx, group.ID, userID); err != nil {
+					return nil, err
+				}
+			}
+
+		case "replace":
+			values := map[string]interface{}{path: op.Value}
+			if path == "" {
+				obj, ok := op.Value.(map[string]interface{})
+				if !ok {
+					return nil, models.NewAppError(http.StatusBadRequest, "Replace without path requires an object value")
+				}
+				values = obj
+			}
+			for attr, value := range values {
+				switch strings.ToLower(attr) {
+				case "displayname":
+					name, ok := value.(string)
+					if !ok || strings.TrimSpace(name) == "" {
+						return nil, models.NewAppError(http.StatusBadRequest, "displayName must be a non-empty string")
+					}
+					group.DisplayName = utils.SanitizeHTML(strings.TrimSpace(name))
+					if err := s.groupRepo.Update(ctx, group); err != nil {
+						return nil, err
+					}
+				case "members":
+					memberIDs, err := s.resolveSCIMMembers(ctx, scimMembersFromValue(value))
+					if err != nil {
+						return nil, err
+					}
+					if err := s.setGroupMembers(ctx, group.ID, memberIDs); err != nil {
+						return nil, err
+					}
+				default:
+					return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unsupported replace path: %s", attr))
+				}
+			}
+
+		default:
+			return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Unsupported operation: %s", op.Op))
+		}
+	}
+
+	return s.groupToSCIM(group), nil
+}
+
+// DeleteGroup removes all members from a group and deletes it. Groups with child groups
+// cannot be deleted.
+func (s *SCIMService) DeleteGroup(ctx context.Context, id string) error {
+	group, err := s.getSCIMGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setGroupMembers(ctx, group.ID, nil); err != nil {
+		return err
+	}
+	return s.groupRepo.Delete(ctx, group.ID)
+}
+
 // Helper methods
 
+func (s *SCIMService) getSCIMGroup(ctx context.Context, id string) (*models.Group, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, models.NewAppError(http.StatusBadRequest, "Invalid group ID")
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.NewAppError(http.StatusNotFound, "Group not found")
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// resolveSCIMMembers parses member IDs and checks that every member is an existing user
+func (s *SCIMService) resolveSCIMMembers(ctx context.Context, members []models.SCIMMember) ([]uuid.UUID, error) {
+	memberIDs, err := parseSCIMMemberIDs(members)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range memberIDs {
+		user, err := s.userRepo.GetByID(ctx, userID, nil)
+		if err != nil || user == nil {
+			return nil, models.NewAppError(http.StatusBadRequest, fmt.Sprintf("Member %s does not exist", userID))
+		}
+	}
+	return memberIDs, nil
+}
+
+// setGroupMembers adds and removes members so that the group contains exactly memberIDs
+func (s *SCIMService) setGroupMembers(ctx context.Context, groupID uuid.UUID, memberIDs []uuid.UUID) error {
+	current, err := s.currentMemberIDs(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[uuid.UUID]bool, len(memberIDs))
+	for _, userID := range memberIDs {
+		desired[userID] = true
+	}
+	existing := make(map[uuid.UUID]bool, len(current))
+	for _, userID := range current {
+		existing[userID] = true
+		if !desired[userID] {
+			if err := s.groupRepo.RemoveUser(ctx, groupID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, userID := range memberIDs {
+		if !existing[userID] {
+			if err := s.groupRepo.AddUser(ctx, groupID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SCIMService) currentMemberIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	members, _, err := s.groupRepo.GetGroupMembers(ctx, groupID, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		ids[i] = member.ID
+	}
+	return ids, nil
+}
+
+func parseSCIMMemberIDs(members []models.SCIMMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		userID, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, models.NewAppError(http.StatusBadRequest, "Invalid member ID")
+		}
+		ids = append(ids, userID)
+	}
+	return uniqueUUIDs(ids), nil
+}
+
+// scimMembersFromValue converts the untyped value of a PATCH operation into members. It
+// accepts a list of member objects or a single member object.
+func scimMembersFromValue(value interface{}) []models.SCIMMember {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items = []interface{}{v}
+	}
+
+	members := make([]models.SCIMMember, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		memberID, _ := obj["value"].(string)
+		members = append(members, models.SCIMMember{Value: memberID})
+	}
+	return members
+}
+
 func (s *SCIMService) userToSCIM(user *models.User) *models.SCIMUser {
 	return &models.SCIMUser{
 		Schemas:  []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
@@ -352,7 +627,7 @@ func (s *SCIMService) applyReplaceOperation(user *models.User, path string, valu
 		}
 	case "active":
 		if active, ok := value.(bool); ok {
-			user.IsActive = active
+			user.SetActive(active)
 		}
 	}
 	return nil
@@ -367,7 +642,7 @@ func (s *SCIMService) applyRemoveOperation(user *models.User, path string) error
 	// For remove, we might set to empty or false
 	switch path {
 	case "active":
-		user.IsActive = false
+		user.SetActive(false)
 	}
 	return nil
 }
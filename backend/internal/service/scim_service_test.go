@@ -211,3 +211,124 @@ func TestSCIMService_GetGroup(t *testing.T) {
 		assert.NotNil(t, scimGroup)
 	})
 }
+
+func setupSCIMGroupTest(t *testing.T, userIDs ...uuid.UUID) (*SCIMService, *mockGroupStore, *models.Group) {
+	svc, mUser, mGroup := setupSCIMService()
+	known := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		known[id] = true
+	}
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		if known[id] {
+			return &models.User{ID: id}, nil
+		}
+		return nil, models.ErrNotFound
+	}
+
+	group := &models.Group{Name: "engineering", DisplayName: "Engineering"}
+	require.NoError(t, mGroup.Create(context.Background(), group))
+	return svc, mGroup, group
+}
+
+func TestSCIMService_CreateGroup_ShouldAddMembers(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	svc, mGroup, _ := setupSCIMGroupTest(t, alice, bob)
+
+	result, err := svc.CreateGroup(context.Background(), &models.SCIMGroup{
+		DisplayName: "Platform",
+		Members:     []models.SCIMMember{{Value: alice.String()}, {Value: bob.String()}, {Value: alice.String()}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", result.DisplayName)
+	assert.Len(t, result.Members, 2)
+
+	created := mGroup.groupsByName["Platform"]
+	require.NotNil(t, created)
+	assert.ElementsMatch(t, []uuid.UUID{alice, bob}, mGroup.users[created.ID])
+}
+
+func TestSCIMService_CreateGroup_ShouldRejectInvalidInput(t *testing.T) {
+	svc, _, _ := setupSCIMGroupTest(t)
+
+	tests := []struct {
+		name  string
+		group *models.SCIMGroup
+		code  int
+	}{
+		{"missing display name", &models.SCIMGroup{}, 400},
+		{"invalid member", &models.SCIMGroup{DisplayName: "x", Members: []models.SCIMMember{{Value: "nope"}}}, 400},
+		{"unknown member", &models.SCIMGroup{DisplayName: "x", Members: []models.SCIMMember{{Value: uuid.NewString()}}}, 400},
+		{"duplicate name", &models.SCIMGroup{DisplayName: "engineering"}, 409},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateGroup(context.Background(), tt.group)
+
+			var appErr *models.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.code, appErr.Code)
+		})
+	}
+}
+
+func TestSCIMService_ReplaceGroup_ShouldSyncMembers(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	svc, mGroup, group := setupSCIMGroupTest(t, alice, bob, carol)
+	mGroup.users[group.ID] = []uuid.UUID{alice, bob}
+
+	result, err := svc.ReplaceGroup(context.Background(), group.ID.String(), &models.SCIMGroup{
+		DisplayName: "Engineering Org",
+		Members:     []models.SCIMMember{{Value: bob.String()}, {Value: carol.String()}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Engineering Org", result.DisplayName)
+	assert.ElementsMatch(t, []uuid.UUID{bob, carol}, mGroup.users[group.ID])
+}
+
+func TestSCIMService_PatchGroup_ShouldApplyMemberOperations(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	svc, mGroup, group := setupSCIMGroupTest(t, alice, bob, carol)
+	mGroup.users[group.ID] = []uuid.UUID{alice}
+
+	_, err := svc.PatchGroup(context.Background(), group.ID.String(), &models.SCIMPatchRequest{
+		Operations: []models.SCIMPatchOperation{
+			{Op: "Add", Path: "members", Value: []interface{}{
+				map[string]interface{}{"value": bob.String()},
+				map[string]interface{}{"value": carol.String()},
+			}},
+			{Op: "remove", Path: `members[value eq "` + alice.String() + `"]`},
+			{Op: "replace", Value: map[string]interface{}{"displayName": "Eng"}},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{bob, carol}, mGroup.users[group.ID])
+	assert.Equal(t, "Eng", mGroup.groups[group.ID].DisplayName)
+}
+
+func TestSCIMService_PatchGroup_ShouldRejectUnsupportedPath(t *testing.T) {
+	svc, _, group := setupSCIMGroupTest(t)
+
+	_, err := svc.PatchGroup(context.Background(), group.ID.String(), &models.SCIMPatchRequest{
+		Operations: []models.SCIMPatchOperation{{Op: "add", Path: "externalId", Value: "x"}},
+	})
+
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestSCIMService_DeleteGroup_ShouldRemoveMembersFirst(t *testing.T) {
+	alice := uuid.New()
+	svc, mGroup, group := setupSCIMGroupTest(t, alice)
+	mGroup.users[group.ID] = []uuid.UUID{alice}
+
+	err := svc.DeleteGroup(context.Background(), group.ID.String())
+
+	require.NoError(t, err)
+	assert.Empty(t, mGroup.users[group.ID])
+	assert.NotContains(t, mGroup.groups, group.ID)
+}
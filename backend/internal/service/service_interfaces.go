@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"io"
+	"net/url"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/smilemakc/auth-gateway/pkg/jwt"
 )
 
 // AuthServicer abstracts authentication operations
@@ -20,6 +25,7 @@ type AuthServicer interface {
 	InitPasswordlessRegistration(ctx context.Context, req *models.InitPasswordlessRegistrationRequest, ip, userAgent string) error
 	CompletePasswordlessRegistration(ctx context.Context, req *models.CompletePasswordlessRegistrationRequest, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error)
 	GenerateTokensForUser(ctx context.Context, user *models.User, ip, userAgent string) (*models.AuthResponse, error)
+	CompleteRequiredPasswordChange(ctx context.Context, resetToken, newPassword, ip, userAgent string) error
 }
 
 // UserServicer abstracts user profile and lookup operations
@@ -42,11 +48,13 @@ type OTPServicer interface {
 
 // AdminUserServicer abstracts admin user management operations
 type AdminUserServicer interface {
-	ListUsers(ctx context.Context, appID *uuid.UUID, page, pageSize int) (*models.AdminUserListResponse, error)
+	ListUsers(ctx context.Context, appID *uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error)
+	SearchUsers(ctx context.Context, page, pageSize int, opts ...UserFilterOption) (*models.AdminUserListResponse, error)
 	GetUser(ctx context.Context, userID uuid.UUID) (*models.AdminUserResponse, error)
 	CreateUser(ctx context.Context, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
 	UpdateUser(ctx context.Context, userID uuid.UUID, req *models.AdminUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
-	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	DeleteUser(ctx context.Context, userID, adminID uuid.UUID) error
+	UpdateUserState(ctx context.Context, userID uuid.UUID, req *models.UpdateUserStateRequest, adminID uuid.UUID, ip, userAgent string) (*models.AdminUserResponse, error)
 	AdminReset2FA(ctx context.Context, userID, adminID uuid.UUID) error
 	GetUserOAuthAccounts(ctx context.Context, userID uuid.UUID) ([]*models.OAuthAccount, error)
 	AssignRole(ctx context.Context, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error)
@@ -61,7 +69,9 @@ type AdminAPIKeyServicer interface {
 
 // AdminAuditServicer abstracts admin audit log operations
 type AdminAuditServicer interface {
-	ListAuditLogs(ctx context.Context, page, pageSize int, userID *uuid.UUID) (*models.AuditLogListResponse, error)
+	ListAuditLogs(ctx context.Context, page, pageSize int, opts ...AuditLogListOption) (*models.AuditLogListResponse, error)
+	GetAuditActionStats(ctx context.Context, limit int, opts ...AuditLogListOption) (*models.AuditActionStatsResponse, error)
+	GetAuditFailureStats(ctx context.Context, opts ...AuditLogListOption) (*models.AuditFailureStatsResponse, error)
 }
 
 // AdminStatsServicer abstracts admin statistics operations
@@ -137,11 +147,18 @@ type RBACServicer interface {
 	UpdateRole(ctx context.Context, id uuid.UUID, req *models.UpdateRoleRequest) (*models.Role, error)
 	DeleteRole(ctx context.Context, id uuid.UUID) error
 	SetRolePermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
+	GetEffectivePermissions(ctx context.Context, roleID uuid.UUID) (*models.EffectivePermissionsResponse, error)
+	CreatePermissionBundle(ctx context.Context, req *models.CreatePermissionBundleRequest) (*models.PermissionBundle, error)
+	GetPermissionBundle(ctx context.Context, id uuid.UUID) (*models.PermissionBundle, error)
+	ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error)
+	UpdatePermissionBundle(ctx context.Context, id uuid.UUID, req *models.UpdatePermissionBundleRequest) (*models.PermissionBundle, error)
+	DeletePermissionBundle(ctx context.Context, id uuid.UUID) error
 	CheckUserPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
 	CheckUserAnyPermission(ctx context.Context, userID uuid.UUID, permissions []string) (bool, error)
 	CheckUserAllPermissions(ctx context.Context, userID uuid.UUID, permissions []string) (bool, error)
 	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]models.Permission, error)
 	GetPermissionMatrix(ctx context.Context) (*models.PermissionMatrix, error)
+	SimulatePermission(ctx context.Context, req *models.RBACSimulationRequest) (*models.RBACSimulationResponse, error)
 	AssignRoleToUser(ctx context.Context, userID, roleID, assignedBy uuid.UUID) error
 	RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.UUID) error
 	SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, assignedBy uuid.UUID) error
@@ -190,6 +207,46 @@ type BlacklistServicer interface {
 	BlacklistAllUserSessions(ctx context.Context, userID uuid.UUID) error
 }
 
+// TokenVersionServicer abstracts per-user token versioning and global token invalidation
+type TokenVersionServicer interface {
+	IsRevoked(ctx context.Context, claims *jwt.Claims) bool
+	CurrentVersion(ctx context.Context, userID uuid.UUID) (int, error)
+	Bump(ctx context.Context, userID uuid.UUID) (int, error)
+	Epoch(ctx context.Context) (time.Time, error)
+	InvalidateAll(ctx context.Context, updatedBy *uuid.UUID) (time.Time, error)
+}
+
+// PasswordExpiryServicer abstracts password max-age policies and forced password rotation
+type PasswordExpiryServicer interface {
+	SetRoleMaxAge(ctx context.Context, roleID uuid.UUID, maxAgeDays *int) error
+	SetGroupMaxAge(ctx context.Context, groupID uuid.UUID, maxAgeDays *int) error
+	RequirePasswordChange(ctx context.Context, userID uuid.UUID) error
+	ListUpcomingExpirations(ctx context.Context, days int) (*models.PasswordExpiryListResponse, error)
+}
+
+// AccountRecoveryServicer abstracts account recovery operations
+type AccountRecoveryServicer interface {
+	Start(ctx context.Context, req *models.StartAccountRecoveryRequest, ip, userAgent string) (*models.StartAccountRecoveryResponse, error)
+	VerifyStep(ctx context.Context, id uuid.UUID, req *models.VerifyAccountRecoveryStepRequest, ip, userAgent string) (*models.AccountRecoveryStatusResponse, error)
+	GetStatus(ctx context.Context, id uuid.UUID, clientSecret string) (*models.AccountRecoveryStatusResponse, error)
+	Complete(ctx context.Context, id uuid.UUID, req *models.CompleteAccountRecoveryRequest, ip, userAgent string) error
+	List(ctx context.Context, status string, page, pageSize int) (*models.AccountRecoveryListResponse, error)
+	Get(ctx context.Context, id uuid.UUID) (*models.AccountRecoveryRequest, error)
+	Approve(ctx context.Context, id, adminID uuid.UUID, note string) error
+	Reject(ctx context.Context, id, adminID uuid.UUID, note string) error
+}
+
+// UserEmailServicer abstracts secondary email address operations
+type UserEmailServicer interface {
+	List(ctx context.Context, userID uuid.UUID) (*models.UserEmailListResponse, error)
+	Add(ctx context.Context, userID uuid.UUID, email, ip, userAgent string) (*models.UserEmail, error)
+	Verify(ctx context.Context, userID uuid.UUID, email, code, ip, userAgent string) (*models.UserEmail, error)
+	Remove(ctx context.Context, userID, id uuid.UUID, ip, userAgent string) error
+	SetPrimary(ctx context.Context, userID, id uuid.UUID, ip, userAgent string) error
+	SetRecoveryEmail(ctx context.Context, userID uuid.UUID, email, ip, userAgent string) error
+	ClearRecoveryEmail(ctx context.Context, userID uuid.UUID, ip, userAgent string) error
+}
+
 // RedisServicer abstracts Redis cache operations
 type RedisServicer interface {
 	Close() error
@@ -219,6 +276,9 @@ type OAuthServicer interface {
 	ExchangeCode(ctx context.Context, provider models.OAuthProvider, code string, appID *uuid.UUID) (*OAuthTokenResponse, error)
 	GetUserInfo(ctx context.Context, provider models.OAuthProvider, accessToken string, appID *uuid.UUID) (*models.OAuthUserInfo, error)
 	HandleCallback(ctx context.Context, provider models.OAuthProvider, code, ipAddress, userAgent string, appID *uuid.UUID) (*models.OAuthLoginResponse, error)
+	ConfirmLink(ctx context.Context, req *models.ConfirmOAuthLinkRequest, ipAddress, userAgent string) (*models.OAuthLoginResponse, error)
+	GetProviderToken(ctx context.Context, userID uuid.UUID, provider models.OAuthProvider, appID *uuid.UUID) (*models.ProviderTokenResponse, error)
+	IsProviderDisabled(provider models.OAuthProvider) bool
 }
 
 // OAuthProviderServicer abstracts OAuth/OIDC provider operations
@@ -240,17 +300,57 @@ type OAuthProviderServicer interface {
 	ApproveDeviceCode(ctx context.Context, userID uuid.UUID, userCode string, approve bool) error
 	IntrospectToken(ctx context.Context, token, tokenTypeHint string, clientID *string) (*models.IntrospectionResponse, error)
 	RevokeToken(ctx context.Context, token, tokenTypeHint string, clientID *string) error
-	GetUserInfo(ctx context.Context, accessToken string) (*models.UserInfoResponse, error)
+	GetUserInfo(ctx context.Context, accessToken, clientCertThumbprint string) (*models.UserInfoResponse, error)
 	GetDiscoveryDocument() *models.OIDCDiscoveryDocument
 	GetJWKS() *models.JWKSDocument
+	SignAuthorizationResponse(clientID string, params url.Values) (string, error)
+	EndSession(ctx context.Context, req *models.EndSessionRequest, userID *uuid.UUID) (*models.EndSessionResult, error)
 	GetConsentInfo(ctx context.Context, clientID string, scopes []string) (*ConsentInfo, error)
+	GetDeviceConsentInfo(ctx context.Context, userCode string) (*ConsentInfo, error)
 	GrantConsent(ctx context.Context, userID uuid.UUID, clientID string, scopes []string) error
 	RevokeConsent(ctx context.Context, userID, clientID uuid.UUID) error
 	ListUserConsents(ctx context.Context, userID uuid.UUID) ([]*models.UserConsent, error)
+	ListConnectedApps(ctx context.Context, userID uuid.UUID) ([]*models.ConnectedApp, error)
+	RevokeConnectedApp(ctx context.Context, userID, clientID uuid.UUID) error
 	ListScopes(ctx context.Context) ([]*models.OAuthScope, error)
 	CreateScope(ctx context.Context, scope *models.OAuthScope) error
 	DeleteScope(ctx context.Context, id uuid.UUID) error
+	ListScopeGroups(ctx context.Context) ([]*models.OAuthScopeGroup, error)
+	CreateScopeGroup(ctx context.Context, req *models.CreateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error)
+	UpdateScopeGroup(ctx context.Context, id uuid.UUID, req *models.UpdateOAuthScopeGroupRequest) (*models.OAuthScopeGroup, error)
+	DeleteScopeGroup(ctx context.Context, id uuid.UUID) error
 	ListClientConsents(ctx context.Context, clientID uuid.UUID) ([]*models.UserConsent, error)
+	EmulateClientFlow(ctx context.Context, id uuid.UUID, req *models.OAuthClientEmulateRequest) (*models.OAuthClientEmulateResponse, error)
+	PreviewTokens(ctx context.Context, req *models.TokenPreviewRequest) (*models.TokenPreviewResponse, error)
+}
+
+// OAuthClientWebhookServicer abstracts the webhooks OAuth client owners manage for their clients
+type OAuthClientWebhookServicer interface {
+	ListWebhooks(ctx context.Context, ownerID, clientID uuid.UUID) (*models.OAuthClientWebhookListResponse, error)
+	CreateWebhook(ctx context.Context, ownerID, clientID uuid.UUID, req *models.CreateOAuthClientWebhookRequest) (*models.CreateOAuthClientWebhookResponse, error)
+	GetWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID) (*models.OAuthClientWebhook, error)
+	UpdateWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID, req *models.UpdateOAuthClientWebhookRequest) (*models.OAuthClientWebhook, error)
+	DeleteWebhook(ctx context.Context, ownerID, clientID, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, ownerID, clientID, id uuid.UUID, page, perPage int) (*models.OAuthClientWebhookDeliveryListResponse, error)
+}
+
+// OIDCConformanceServicer abstracts the OIDC provider conformance self-test
+type OIDCConformanceServicer interface {
+	Run(ctx context.Context, req *models.OIDCConformanceRequest) *models.OIDCConformanceReport
+}
+
+// OAuthClientLogoServicer abstracts uploading and serving OAuth client logos
+type OAuthClientLogoServicer interface {
+	Upload(ctx context.Context, clientID uuid.UUID, content io.Reader) (*models.OAuthClient, error)
+	Delete(ctx context.Context, clientID uuid.UUID) (*models.OAuthClient, error)
+	Get(ctx context.Context, name string) (*models.OAuthClientLogo, error)
+	MaxBytes() int
+}
+
+// PermissionCatalogServicer abstracts the machine-readable permissions catalog
+type PermissionCatalogServicer interface {
+	GetCatalog(ctx context.Context) (*models.PermissionCatalogResponse, error)
+	RegisterEntries(ctx context.Context, source string, entries []models.PermissionCatalogEntry) (int, error)
 }
 
 // TwoFactorServicer abstracts TOTP two-factor authentication operations
@@ -263,6 +363,25 @@ type TwoFactorServicer interface {
 	RegenerateBackupCodes(ctx context.Context, userID uuid.UUID, password string) ([]string, error)
 }
 
+// WebAuthnServicer abstracts registering and managing security keys as a second factor
+type WebAuthnServicer interface {
+	BeginWebAuthnRegistration(ctx context.Context, userID uuid.UUID, password string) (*protocol.CredentialCreation, error)
+	FinishWebAuthnRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*models.WebAuthnRegisterFinishResponse, error)
+	ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error)
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID uuid.UUID, password string) error
+}
+
+// WebAuthnLoginServicer abstracts completing a 2FA sign-in with a security key
+type WebAuthnLoginServicer interface {
+	BeginWebAuthn2FALogin(ctx context.Context, twoFactorToken string) (*protocol.CredentialAssertion, error)
+	VerifyWebAuthn2FALogin(ctx context.Context, twoFactorToken string, response []byte, ip, userAgent string, deviceInfo models.DeviceInfo) (*models.AuthResponse, error)
+}
+
+// SMSDeliveryServicer abstracts recording SMS delivery status callbacks
+type SMSDeliveryServicer interface {
+	RecordDeliveryReport(ctx context.Context, provider string, report *sms.DeliveryReport, ip string) error
+}
+
 // IPFilterServicer abstracts IP filtering operations
 type IPFilterServicer interface {
 	CreateIPFilter(ctx context.Context, req *models.CreateIPFilterRequest, createdBy uuid.UUID) (*models.IPFilter, error)
@@ -318,6 +437,7 @@ type WebhookServicer interface {
 	DeleteWebhook(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
 	TriggerWebhook(ctx context.Context, eventType string, data map[string]interface{}) error
 	ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, page, perPage int) (*models.WebhookDeliveryListResponse, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
 	TestWebhook(ctx context.Context, id uuid.UUID, req *models.TestWebhookRequest) error
 	GetAvailableEvents() []string
 	ListWebhooksByApp(ctx context.Context, appID uuid.UUID) ([]*models.Webhook, error)
@@ -367,6 +487,10 @@ type SCIMServicer interface {
 	DeleteUser(ctx context.Context, id string) error
 	GetGroups(ctx context.Context, filter string, startIndex, count int) (*models.SCIMListResponse, error)
 	GetGroup(ctx context.Context, id string) (*models.SCIMGroup, error)
+	CreateGroup(ctx context.Context, scimGroup *models.SCIMGroup) (*models.SCIMGroup, error)
+	ReplaceGroup(ctx context.Context, id string, scimGroup *models.SCIMGroup) (*models.SCIMGroup, error)
+	PatchGroup(ctx context.Context, id string, patchReq *models.SCIMPatchRequest) (*models.SCIMGroup, error)
+	DeleteGroup(ctx context.Context, id string) error
 	GetServiceProviderConfig(ctx context.Context) *models.SCIMServiceProviderConfig
 	GetSchemas(ctx context.Context) []*models.SCIMSchema
 }
@@ -379,6 +503,27 @@ type BulkServicer interface {
 	BulkAssignRoles(ctx context.Context, req *models.BulkAssignRolesRequest, assignedBy uuid.UUID) (*models.BulkOperationResult, error)
 }
 
+// BulkRoleJobServicer abstracts background bulk role assignment jobs
+type BulkRoleJobServicer interface {
+	StartJob(ctx context.Context, req *models.BulkRoleJobRequest, createdBy uuid.UUID) (*models.BulkRoleJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*models.BulkRoleJob, error)
+	ListJobs(ctx context.Context) (*models.BulkRoleJobListResponse, error)
+}
+
+// JobQueueServicer abstracts inspecting and retrying background jobs
+type JobQueueServicer interface {
+	ListJobs(ctx context.Context, status, jobType string, page, perPage int) (*models.BackgroundJobListResponse, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error)
+	RetryJob(ctx context.Context, id uuid.UUID) (*models.BackgroundJob, error)
+	GetStats(ctx context.Context) (*models.BackgroundJobStatsResponse, error)
+}
+
+// AuditExportServicer abstracts inspecting audit export sinks and replaying past ranges
+type AuditExportServicer interface {
+	Status(ctx context.Context) (*models.AuditExportStatusResponse, error)
+	Replay(ctx context.Context, sink string, from, to time.Time) (*models.AuditExportReplayResponse, error)
+}
+
 // GroupServicer abstracts user group operations
 type GroupServicer interface {
 	CreateGroup(ctx context.Context, req *models.CreateGroupRequest) (*models.Group, error)
@@ -393,9 +538,133 @@ type GroupServicer interface {
 	GetGroupMemberCount(ctx context.Context, groupID uuid.UUID) (int, error)
 	EvaluateDynamicGroupMembers(ctx context.Context, group *models.Group, allUsers []*models.User) []uuid.UUID
 	GetGroupPermissions(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+	GetGroupRoles(ctx context.Context, groupID uuid.UUID) (*models.GroupRolesResponse, error)
+	SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) (*models.GroupRolesResponse, error)
 	SyncDynamicGroupMembers(ctx context.Context, groupID uuid.UUID) error
 }
 
+// OrgAdminServicer abstracts organization admin delegation and the operations organization
+// admins perform on the members of their organization
+type OrgAdminServicer interface {
+	ListAdmins(ctx context.Context, groupID uuid.UUID) (*models.GroupAdminListResponse, error)
+	AddAdmin(ctx context.Context, groupID, userID, createdBy uuid.UUID) (*models.GroupAdmin, error)
+	RemoveAdmin(ctx context.Context, groupID, userID uuid.UUID) error
+	ListManagedOrgs(ctx context.Context, userID uuid.UUID) (*models.OrgListResponse, error)
+	IsOrgAdmin(ctx context.Context, userID, groupID uuid.UUID) (bool, error)
+	ListUsers(ctx context.Context, groupID uuid.UUID, search string, page, pageSize int) (*models.AdminUserListResponse, error)
+	GetUser(ctx context.Context, groupID, userID uuid.UUID) (*models.AdminUserResponse, error)
+	CreateUser(ctx context.Context, groupID uuid.UUID, req *models.AdminCreateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	UpdateUser(ctx context.Context, groupID, userID uuid.UUID, req *models.OrgUpdateUserRequest, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	RemoveUser(ctx context.Context, groupID, userID uuid.UUID) error
+	ListRoles(ctx context.Context, groupID uuid.UUID) ([]models.Role, error)
+	AssignRole(ctx context.Context, groupID, userID, roleID, adminID uuid.UUID) (*models.AdminUserResponse, error)
+	RemoveRole(ctx context.Context, groupID, userID, roleID uuid.UUID) (*models.AdminUserResponse, error)
+	ListAPIKeys(ctx context.Context, groupID uuid.UUID) (*models.AdminAPIKeyListResponse, error)
+	RevokeAPIKey(ctx context.Context, groupID, keyID uuid.UUID) error
+}
+
+// UsageReportServicer abstracts organization usage and seat reports
+type UsageReportServicer interface {
+	GetReport(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReport, error)
+	ListReports(ctx context.Context) (*models.OrgUsageReportListResponse, error)
+	GetSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error)
+	UpdateSettings(ctx context.Context, groupID uuid.UUID, req *models.UpdateOrgUsageReportSettingsRequest) (*models.OrgUsageReportSettings, error)
+	SendReport(ctx context.Context, groupID uuid.UUID) (int, error)
+}
+
+// SignupPolicyServicer abstracts sign-up restrictions and organization domain claims
+type SignupPolicyServicer interface {
+	GetPolicy(ctx context.Context) (*models.SignupPolicy, error)
+	UpdatePolicy(ctx context.Context, req *models.UpdateSignupPolicyRequest, updatedBy uuid.UUID) (*models.SignupPolicy, error)
+	ListDomains(ctx context.Context, groupID uuid.UUID) (*models.GroupDomainListResponse, error)
+	AddDomain(ctx context.Context, groupID uuid.UUID, req *models.AddGroupDomainRequest, createdBy uuid.UUID) (*models.GroupDomain, error)
+	VerifyDomain(ctx context.Context, groupID, domainID uuid.UUID) (*models.GroupDomain, error)
+	RemoveDomain(ctx context.Context, groupID, domainID uuid.UUID) error
+}
+
+// DormantAccountServicer abstracts the dormant account policy and its dry-run report
+type DormantAccountServicer interface {
+	GetPolicy(ctx context.Context) (*models.DormantAccountPolicy, error)
+	UpdatePolicy(ctx context.Context, req *models.UpdateDormantAccountPolicyRequest, updatedBy uuid.UUID) (*models.DormantAccountPolicy, error)
+	Report(ctx context.Context) (*models.DormantAccountReport, error)
+}
+
+// RetentionServicer abstracts the erasure of a user's personal data
+type RetentionServicer interface {
+	EraseUser(ctx context.Context, params UserErasureParams) (*models.UserErasureResponse, error)
+}
+
+// DataExportServicer abstracts user data exports
+type DataExportServicer interface {
+	RequestExport(ctx context.Context, userID uuid.UUID, format models.DataExportFormat, ip, userAgent string) (*models.DataExportResponse, error)
+	Download(ctx context.Context, exportID uuid.UUID, method string, u *url.URL, ip, userAgent string) (*DataExportFile, error)
+}
+
+// PasswordPolicyServicer abstracts password policy management
+type PasswordPolicyServicer interface {
+	GetPolicy(ctx context.Context) (*models.PasswordPolicy, error)
+	UpdatePolicy(ctx context.Context, req *models.UpdatePasswordPolicyRequest, updatedBy uuid.UUID) (*models.PasswordPolicy, error)
+}
+
+// LogLevelServicer changes log levels at runtime
+type LogLevelServicer interface {
+	Get() *models.LogLevelResponse
+	Set(ctx context.Context, req *models.SetLogLevelRequest) (*models.LogLevelResponse, error)
+	Reset(ctx context.Context) *models.LogLevelResponse
+}
+
+// GuestServicer abstracts anonymous guest sessions and their upgrade to full accounts
+type GuestServicer interface {
+	CreateGuest(ctx context.Context, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error)
+	UpgradeGuest(ctx context.Context, userID uuid.UUID, req *models.UpgradeGuestRequest, ip, userAgent string, deviceInfo models.DeviceInfo, appID *uuid.UUID) (*models.AuthResponse, error)
+}
+
+// MagicLinkServicer abstracts passwordless sign-in by emailed links
+type MagicLinkServicer interface {
+	Request(ctx context.Context, req *models.RequestMagicLinkRequest, appID *uuid.UUID, ip, userAgent string) error
+	Verify(ctx context.Context, token, ip, userAgent string, deviceInfo models.DeviceInfo) string
+	Complete(ctx context.Context, code string) (*models.AuthResponse, error)
+}
+
+// CredentialsBrokerServicer abstracts issuing short-lived downstream credentials
+type CredentialsBrokerServicer interface {
+	CreateTarget(ctx context.Context, req *models.CreateCredentialTargetRequest) (*models.CredentialTarget, error)
+	UpdateTarget(ctx context.Context, id uuid.UUID, req *models.UpdateCredentialTargetRequest) (*models.CredentialTarget, error)
+	DeleteTarget(ctx context.Context, id uuid.UUID) (*models.CredentialTarget, error)
+	ListTargets(ctx context.Context) ([]*models.CredentialTarget, error)
+	ListAvailableTargets(ctx context.Context, userID uuid.UUID) ([]*models.CredentialTarget, error)
+	Issue(ctx context.Context, userID uuid.UUID, targetName string, req *models.IssueCredentialsRequest, ip, userAgent string) (*models.IssuedCredentials, error)
+	ListIssuances(ctx context.Context, userID *uuid.UUID, page, pageSize int) (*models.CredentialIssuanceListResponse, error)
+	Revoke(ctx context.Context, issuanceID uuid.UUID) (*models.CredentialIssuance, error)
+}
+
+// SignedURLServicer abstracts minting and verifying signed resource URLs
+type SignedURLServicer interface {
+	Create(ctx context.Context, userID uuid.UUID, req *models.CreateSignedURLRequest, ip, userAgent string) (*models.SignedURLResponse, error)
+	Verify(req *models.VerifySignedURLRequest) (*models.VerifySignedURLResponse, error)
+}
+
+// RiskPolicyServicer abstracts management of the risk policies sign-ins are scored by
+type RiskPolicyServicer interface {
+	CreatePolicy(ctx context.Context, req *models.CreateRiskPolicyRequest) (*models.RiskPolicy, error)
+	GetPolicy(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error)
+	ListPolicies(ctx context.Context) ([]*models.RiskPolicy, error)
+	UpdatePolicy(ctx context.Context, id uuid.UUID, req *models.UpdateRiskPolicyRequest) (*models.RiskPolicy, error)
+	DeletePolicy(ctx context.Context, id uuid.UUID) (*models.RiskPolicy, error)
+}
+
+// AccountLockoutServicer abstracts admin management of locked accounts
+type AccountLockoutServicer interface {
+	ListLocked(ctx context.Context, page, pageSize int) (*models.LockedAccountListResponse, error)
+	GetLockout(ctx context.Context, userID uuid.UUID) (*models.AccountLockout, error)
+	Unlock(ctx context.Context, userID uuid.UUID) (*models.AccountLockout, error)
+}
+
+// UserTimelineServicer abstracts the admin view of a user's activity timeline
+type UserTimelineServicer interface {
+	ListUserTimeline(ctx context.Context, userID uuid.UUID, sources []string, page, pageSize int) (*models.UserTimelineResponse, error)
+}
+
 // LDAPServicer abstracts LDAP integration operations
 type LDAPServicer interface {
 	CreateConfig(ctx context.Context, req *models.CreateLDAPConfigRequest) (*models.LDAPConfig, error)
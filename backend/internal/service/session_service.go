@@ -66,6 +66,7 @@ type SessionService struct {
 	blacklistService BlackListStore
 	logger           *logger.Logger
 	maxSessions      int
+	logoutNotifier   BackchannelLogoutNotifier
 }
 
 // NewSessionService creates a new session service
@@ -78,6 +79,12 @@ func NewSessionService(sessionRepo SessionStore, blacklistService *BlacklistServ
 	}
 }
 
+// SetBackchannelLogout notifies the back-channel logout URIs of clients through notifier
+// when sessions are revoked
+func (s *SessionService) SetBackchannelLogout(notifier BackchannelLogoutNotifier) {
+	s.logoutNotifier = notifier
+}
+
 // =============================================================================
 // Session Creation Methods
 // =============================================================================
@@ -383,16 +390,32 @@ func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID uu
 		return fmt.Errorf("session does not belong to user")
 	}
 
+	if err := s.revokeSession(ctx, session); err != nil {
+		return err
+	}
+	s.notifyLogout(ctx, userID)
+	return nil
+}
+
+// revokeSession blacklists the tokens of a session and revokes it
+func (s *SessionService) revokeSession(ctx context.Context, session *models.Session) error {
 	// Blacklist both access and refresh tokens
 	if err := s.blacklistService.BlacklistSessionTokens(ctx, session); err != nil {
 		s.logger.Error("Failed to blacklist session tokens", map[string]interface{}{
-			"session_id": sessionID,
+			"session_id": session.ID,
 			"error":      err.Error(),
 		})
 		// Continue with revocation even if blacklisting fails
 	}
 
-	return s.sessionRepo.RevokeUserSession(ctx, userID, sessionID)
+	return s.sessionRepo.RevokeUserSession(ctx, session.UserID, session.ID)
+}
+
+// notifyLogout tells clients the user is signed in to that the user logged out
+func (s *SessionService) notifyLogout(ctx context.Context, userID uuid.UUID) {
+	if s.logoutNotifier != nil {
+		s.logoutNotifier.NotifyLogout(ctx, userID)
+	}
 }
 
 // AdminRevokeSession revokes any session by ID (admin only, no user ownership check)
@@ -411,7 +434,11 @@ func (s *SessionService) AdminRevokeSession(ctx context.Context, sessionID uuid.
 		// Continue with revocation even if blacklisting fails
 	}
 
-	return s.sessionRepo.RevokeSession(ctx, sessionID)
+	if err := s.sessionRepo.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+	s.notifyLogout(ctx, session.UserID)
+	return nil
 }
 
 // RevokeAllUserSessions revokes all sessions for a user except the current one
@@ -421,11 +448,15 @@ func (s *SessionService) RevokeAllUserSessions(ctx context.Context, userID uuid.
 		if exceptSessionID != nil && *exceptSessionID == session.ID {
 			continue
 		}
-		if err := s.RevokeSession(ctx, userID, session.ID); err != nil {
+		if err := s.revokeSession(ctx, &session); err != nil {
 			return err
 		}
 	}
-	return s.sessionRepo.RevokeAllUserSessions(ctx, userID, exceptSessionID)
+	if err := s.sessionRepo.RevokeAllUserSessions(ctx, userID, exceptSessionID); err != nil {
+		return err
+	}
+	s.notifyLogout(ctx, userID)
+	return nil
 }
 
 // RevokeSessionByTokenHash revokes a session by its token hash.
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/signedurl"
+)
+
+// SignedURLService mints short-lived URLs to protected resources bound to a user and scopes,
+// and verifies them for resource services that do not hold the signing secret.
+type SignedURLService struct {
+	signer         *signedurl.Signer
+	permissions    PermissionChecker
+	auditService   AuditLogger
+	allowedOrigins map[string]struct{}
+	defaultTTL     time.Duration
+	maxTTL         time.Duration
+	now            func() time.Time
+}
+
+// NewSignedURLService creates a new signed URL service. Only URLs whose scheme and host are
+// in allowedOrigins are signed.
+func NewSignedURLService(
+	signer *signedurl.Signer,
+	permissions PermissionChecker,
+	auditService AuditLogger,
+	allowedOrigins []string,
+	defaultTTL, maxTTL time.Duration,
+) *SignedURLService {
+	origins := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+
+	return &SignedURLService{
+		signer:         signer,
+		permissions:    permissions,
+		auditService:   auditService,
+		allowedOrigins: origins,
+		defaultTTL:     defaultTTL,
+		maxTTL:         maxTTL,
+		now:            time.Now,
+	}
+}
+
+// Create signs a URL for the user. The user must hold every requested scope as a permission.
+func (s *SignedURLService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateSignedURLRequest, ip, userAgent string) (*models.SignedURLResponse, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, models.NewAppError(http.StatusBadRequest, "url must be an absolute http or https URL")
+	}
+	origin := strings.ToLower(target.Scheme + "://" + target.Host)
+	if _, ok := s.allowedOrigins[origin]; !ok {
+		return nil, models.NewAppError(http.StatusBadRequest, "URLs on this origin cannot be signed", origin)
+	}
+
+	if len(req.Scopes) > 0 {
+		allowed, err := s.permissions.HasAllPermissions(ctx, userID, req.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permissions: %w", err)
+		}
+		if !allowed {
+			return nil, models.NewAppError(http.StatusForbidden, "Missing permission for a requested scope")
+		}
+	}
+
+	ttl := s.defaultTTL
+	if req.ExpiresIn > 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if ttl > s.maxTTL {
+		ttl = s.maxTTL
+	}
+	// Signed URLs carry whole seconds
+	expiresAt := s.now().Add(ttl).Truncate(time.Second)
+
+	signed, err := s.signer.Sign(req.URL, signedurl.Claims{
+		UserID:    userID.String(),
+		Scopes:    req.Scopes,
+		Method:    req.Method,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, models.NewAppError(http.StatusBadRequest, "URL cannot be signed", err.Error())
+	}
+
+	s.auditService.Log(AuditLogParams{
+		UserID:    &userID,
+		Action:    models.ActionSignedURLCreate,
+		Status:    models.StatusSuccess,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details: map[string]interface{}{
+			"origin":     origin,
+			"path":       target.Path,
+			"scopes":     req.Scopes,
+			"expires_at": expiresAt,
+		},
+	})
+
+	return &models.SignedURLResponse{URL: signed, ExpiresAt: expiresAt}, nil
+}
+
+// Verify checks a signed URL as received by a resource service. The URL may be absolute or
+// just the request URI, since the scheme and host are not signed.
+func (s *SignedURLService) Verify(req *models.VerifySignedURLRequest) (*models.VerifySignedURLResponse, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, models.NewAppError(http.StatusUnauthorized, "Invalid signed URL")
+	}
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	claims, err := s.signer.Verify(method, u)
+	switch {
+	case errors.Is(err, signedurl.ErrExpired):
+		return nil, models.NewAppError(http.StatusUnauthorized, "Signed URL expired")
+	case errors.Is(err, signedurl.ErrMethodNotAllowed):
+		return nil, models.NewAppError(http.StatusUnauthorized, "Method not allowed for this signed URL")
+	case err != nil:
+		return nil, models.NewAppError(http.StatusUnauthorized, "Invalid signed URL")
+	}
+
+	return &models.VerifySignedURLResponse{
+		Valid:     true,
+		UserID:    claims.UserID,
+		Scopes:    claims.Scopes,
+		Method:    claims.Method,
+		ExpiresAt: claims.ExpiresAt.UTC(),
+	}, nil
+}
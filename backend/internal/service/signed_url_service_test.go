@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/signedurl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signedURLFixture struct {
+	service *SignedURLService
+	granted map[string]bool
+	logged  []AuditLogParams
+}
+
+func newSignedURLFixture() *signedURLFixture {
+	f := &signedURLFixture{granted: map[string]bool{"exports:read": true}}
+	rbac := &mockRBACStore{
+		HasAllPermissionsFunc: func(ctx context.Context, userID uuid.UUID, permissionNames []string) (bool, error) {
+			for _, name := range permissionNames {
+				if !f.granted[name] {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.logged = append(f.logged, params) }}
+	f.service = NewSignedURLService(signedurl.New([]byte("0123456789abcdef0123456789abcdef")), rbac, audit,
+		[]string{"https://files.example.com/"}, 5*time.Minute, time.Hour)
+	return f
+}
+
+func TestSignedURLService_Create_ShouldSignAndAudit(t *testing.T) {
+	f := newSignedURLFixture()
+	userID := uuid.New()
+
+	resp, err := f.service.Create(context.Background(), userID, &models.CreateSignedURLRequest{
+		URL:    "https://files.example.com/exports/report.csv",
+		Scopes: []string{"exports:read"},
+	}, "10.0.0.1", "test")
+
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), resp.ExpiresAt, 2*time.Second)
+	signed, err := url.Parse(resp.URL)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), signed.Query().Get(signedurl.ParamUser))
+	require.Len(t, f.logged, 1)
+	assert.Equal(t, models.ActionSignedURLCreate, f.logged[0].Action)
+}
+
+func TestSignedURLService_Create_ShouldClampTTL(t *testing.T) {
+	f := newSignedURLFixture()
+
+	resp, err := f.service.Create(context.Background(), uuid.New(), &models.CreateSignedURLRequest{
+		URL:       "https://files.example.com/exports/report.csv",
+		ExpiresIn: int((24 * time.Hour).Seconds()),
+	}, "", "")
+
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resp.ExpiresAt, 2*time.Second)
+}
+
+func TestSignedURLService_Create_ShouldRejectUnknownOrigin(t *testing.T) {
+	f := newSignedURLFixture()
+
+	_, err := f.service.Create(context.Background(), uuid.New(), &models.CreateSignedURLRequest{
+		URL: "https://evil.example.com/exports/report.csv",
+	}, "", "")
+
+	appErr, ok := err.(*models.AppError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	assert.Empty(t, f.logged)
+}
+
+func TestSignedURLService_Create_ShouldReturn403_WhenScopeNotGranted(t *testing.T) {
+	f := newSignedURLFixture()
+
+	_, err := f.service.Create(context.Background(), uuid.New(), &models.CreateSignedURLRequest{
+		URL:    "https://files.example.com/exports/report.csv",
+		Scopes: []string{"exports:read", "exports:delete"},
+	}, "", "")
+
+	appErr, ok := err.(*models.AppError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, appErr.Code)
+	assert.Empty(t, f.logged)
+}
+
+func TestSignedURLService_Verify(t *testing.T) {
+	f := newSignedURLFixture()
+	userID := uuid.New()
+	resp, err := f.service.Create(context.Background(), userID, &models.CreateSignedURLRequest{
+		URL:    "https://files.example.com/exports/report.csv",
+		Scopes: []string{"exports:read"},
+	}, "", "")
+	require.NoError(t, err)
+
+	t.Run("ShouldAcceptRequestURI", func(t *testing.T) {
+		signed, _ := url.Parse(resp.URL)
+
+		result, err := f.service.Verify(&models.VerifySignedURLRequest{URL: signed.RequestURI()})
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, userID.String(), result.UserID)
+		assert.Equal(t, []string{"exports:read"}, result.Scopes)
+		assert.Equal(t, http.MethodGet, result.Method)
+	})
+
+	t.Run("ShouldReturn401_WhenMethodDiffers", func(t *testing.T) {
+		_, err := f.service.Verify(&models.VerifySignedURLRequest{URL: resp.URL, Method: http.MethodDelete})
+
+		appErr, ok := err.(*models.AppError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, appErr.Code)
+		assert.Equal(t, "Method not allowed for this signed URL", appErr.Message)
+	})
+
+	t.Run("ShouldReturn401_WhenTampered", func(t *testing.T) {
+		_, err := f.service.Verify(&models.VerifySignedURLRequest{URL: resp.URL + "&extra=1"})
+
+		appErr, ok := err.(*models.AppError)
+		require.True(t, ok)
+		assert.Equal(t, "Invalid signed URL", appErr.Message)
+	})
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SigningKeyRotationService rotates the OIDC signing keys on a schedule.
+//
+// The keys live in the database, encrypted, and their state follows from when each one
+// activates: the newest active key signs, a key is published in the JWKS from when it is
+// generated, PrePublish before it activates, and the key it replaces stays published for
+// Grace after that and is then deleted. Every replica runs Sync; the one that finds a
+// rotation due generates the key under a database lock, and all of them load the same keys.
+type SigningKeyRotationService struct {
+	store         SigningKeyStore
+	static        *keys.Manager // configured keys, imported when nothing is stored yet
+	algorithm     keys.Algorithm
+	encryptionKey string
+	cfg           config.OIDCKeyRotationConfig
+	logger        *logger.Logger
+	now           func() time.Time
+
+	manager    *keys.Manager
+	currentKID string
+}
+
+// NewSigningKeyRotationService creates a key rotation service generating algorithm keys.
+// static holds the configured keys, or is nil when there are none.
+func NewSigningKeyRotationService(
+	store SigningKeyStore,
+	static *keys.Manager,
+	algorithm keys.Algorithm,
+	encryptionKey string,
+	cfg config.OIDCKeyRotationConfig,
+	log *logger.Logger,
+) *SigningKeyRotationService {
+	return &SigningKeyRotationService{
+		store:         store,
+		static:        static,
+		algorithm:     algorithm,
+		encryptionKey: encryptionKey,
+		cfg:           cfg,
+		logger:        log,
+		now:           time.Now,
+	}
+}
+
+// Manager returns the key manager holding the rotated keys; nil until Sync first succeeds
+func (s *SigningKeyRotationService) Manager() *keys.Manager {
+	return s.manager
+}
+
+// Sync generates a key when a rotation is due, deletes retired keys and loads the stored
+// keys into the manager. Sync is not safe for concurrent use.
+func (s *SigningKeyRotationService) Sync(ctx context.Context) error {
+	now := s.now().UTC()
+
+	var stored []*models.StoredSigningKey
+	err := s.store.Update(ctx, func(current []*models.StoredSigningKey) ([]*models.StoredSigningKey, []string, error) {
+		add, remove, err := s.rotate(current, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		stored = applyKeyChanges(current, add, remove)
+		return add, remove, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rotate signing keys: %w", err)
+	}
+
+	return s.load(stored, now)
+}
+
+// rotate decides which keys to add and delete; current is ordered by activation
+func (s *SigningKeyRotationService) rotate(current []*models.StoredSigningKey, now time.Time) ([]*models.StoredSigningKey, []string, error) {
+	var add []*models.StoredSigningKey
+
+	if len(current) == 0 {
+		imported, err := s.bootstrap(now)
+		if err != nil {
+			return nil, nil, err
+		}
+		add = imported
+		current = imported
+	}
+
+	latest := current[len(current)-1]
+	if !now.Before(latest.ActivatesAt.Add(s.cfg.Interval - s.cfg.PrePublish)) {
+		// Relying parties learn the key PrePublish before it signs, even after an outage
+		activatesAt := latest.ActivatesAt.Add(s.cfg.Interval)
+		if earliest := now.Add(s.cfg.PrePublish); activatesAt.Before(earliest) {
+			activatesAt = earliest
+		}
+		key, err := s.generate(activatesAt, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		add = append(add, key)
+		s.logger.Info("Generated OIDC signing key", map[string]interface{}{
+			"kid":          key.KID,
+			"algorithm":    key.Algorithm,
+			"activates_at": activatesAt,
+		})
+	}
+
+	var remove []string
+	for i := 0; i < len(current)-1; i++ {
+		successor := current[i+1]
+		if !now.Before(successor.ActivatesAt.Add(s.cfg.Grace)) {
+			remove = append(remove, current[i].KID)
+			s.logger.Info("Retired OIDC signing key", map[string]interface{}{
+				"kid": current[i].KID,
+			})
+		}
+	}
+
+	return add, remove, nil
+}
+
+// bootstrap returns the first keys to store: the configured keys with the configured signing
+// key active, or a new key when none are configured
+func (s *SigningKeyRotationService) bootstrap(now time.Time) ([]*models.StoredSigningKey, error) {
+	if s.static == nil {
+		key, err := s.generate(now, now)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.StoredSigningKey{key}, nil
+	}
+
+	backup, err := s.static.Backup()
+	if err != nil {
+		return nil, err
+	}
+	imported := make([]*models.StoredSigningKey, 0, len(backup.Keys))
+	var current *models.StoredSigningKey
+	for i, key := range backup.Keys {
+		encrypted, err := utils.EncryptAESGCM(key.PrivateKey, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt signing key %s: %w", key.KID, err)
+		}
+		stored := &models.StoredSigningKey{
+			KID:        key.KID,
+			Algorithm:  string(key.Algorithm),
+			PrivateKey: encrypted,
+			CreatedAt:  now,
+		}
+		if key.KID == backup.CurrentKID {
+			current = stored
+			continue
+		}
+		// The other keys were replaced by the current one just now and retire after Grace
+		stored.ActivatesAt = now.Add(-time.Duration(len(backup.Keys)-i) * time.Second)
+		imported = append(imported, stored)
+	}
+	current.ActivatesAt = now
+	imported = append(imported, current)
+
+	s.logger.Info("Imported configured OIDC signing keys for rotation", map[string]interface{}{
+		"keys":    len(imported),
+		"current": current.KID,
+	})
+	return imported, nil
+}
+
+// generate creates an encrypted key activating at activatesAt
+func (s *SigningKeyRotationService) generate(activatesAt, now time.Time) (*models.StoredSigningKey, error) {
+	privateKey, err := keys.GenerateKey(s.algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	encoded, err := keys.EncodePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	encrypted, err := utils.EncryptAESGCM(string(encoded), s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate key ID: %w", err)
+	}
+	return &models.StoredSigningKey{
+		KID:         "key-" + activatesAt.Format("20060102") + "-" + hex.EncodeToString(suffix),
+		Algorithm:   string(s.algorithm),
+		PrivateKey:  encrypted,
+		ActivatesAt: activatesAt,
+		CreatedAt:   now,
+	}, nil
+}
+
+// load decrypts the stored keys into the manager, signing with the newest active key. The
+// manager keeps its keys when any stored key cannot be read.
+func (s *SigningKeyRotationService) load(stored []*models.StoredSigningKey, now time.Time) error {
+	signingKeys := make([]*keys.SigningKey, 0, len(stored))
+	currentKID := ""
+	for _, key := range stored {
+		signingKey, err := s.decrypt(key)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key %s: %w", key.KID, err)
+		}
+		signingKeys = append(signingKeys, signingKey)
+		if !key.ActivatesAt.After(now) {
+			currentKID = key.KID
+		}
+	}
+	if currentKID == "" {
+		return fmt.Errorf("no active signing key stored")
+	}
+
+	if s.manager == nil {
+		manager, err := keys.NewManagerFromKeys(signingKeys, currentKID)
+		if err != nil {
+			return err
+		}
+		s.manager = manager
+	} else if err := s.manager.SetKeys(signingKeys, currentKID); err != nil {
+		return err
+	}
+
+	if currentKID != s.currentKID {
+		if s.currentKID != "" {
+			s.logger.Info("OIDC signing key rotated", map[string]interface{}{
+				"kid":      currentKID,
+				"previous": s.currentKID,
+			})
+		}
+		s.currentKID = currentKID
+	}
+	return nil
+}
+
+func (s *SigningKeyRotationService) decrypt(stored *models.StoredSigningKey) (*keys.SigningKey, error) {
+	decrypted, err := utils.DecryptAESGCM(stored.PrivateKey, s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm := keys.Algorithm(stored.Algorithm)
+	var privateKey interface{}
+	switch algorithm {
+	case keys.RS256:
+		privateKey, err = keys.ParseRSAPrivateKey([]byte(decrypted))
+	case keys.ES256:
+		privateKey, err = keys.ParseECDSAPrivateKey([]byte(decrypted))
+	default:
+		err = fmt.Errorf("unsupported algorithm: %s", stored.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewSigningKey(stored.KID, algorithm, privateKey)
+}
+
+// applyKeyChanges returns current with add appended and remove left out
+func applyKeyChanges(current, add []*models.StoredSigningKey, remove []string) []*models.StoredSigningKey {
+	removed := make(map[string]bool, len(remove))
+	for _, kid := range remove {
+		removed[kid] = true
+	}
+	result := make([]*models.StoredSigningKey, 0, len(current)+len(add))
+	for _, key := range append(append([]*models.StoredSigningKey{}, current...), add...) {
+		if !removed[key.KID] {
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningKeyEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+// mockSigningKeyStore keeps keys in memory, shared like the database between services
+type mockSigningKeyStore struct {
+	keys map[string]*models.StoredSigningKey
+}
+
+func (m *mockSigningKeyStore) sorted() []*models.StoredSigningKey {
+	result := make([]*models.StoredSigningKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ActivatesAt.Before(result[j].ActivatesAt) })
+	return result
+}
+
+func (m *mockSigningKeyStore) List(ctx context.Context) ([]*models.StoredSigningKey, error) {
+	return m.sorted(), nil
+}
+
+func (m *mockSigningKeyStore) Update(ctx context.Context, fn func(keys []*models.StoredSigningKey) ([]*models.StoredSigningKey, []string, error)) error {
+	add, remove, err := fn(m.sorted())
+	if err != nil {
+		return err
+	}
+	for _, key := range add {
+		m.keys[key.KID] = key
+	}
+	for _, kid := range remove {
+		delete(m.keys, kid)
+	}
+	return nil
+}
+
+var testKeyRotationConfig = config.OIDCKeyRotationConfig{
+	Enabled:       true,
+	Interval:      30 * 24 * time.Hour,
+	PrePublish:    24 * time.Hour,
+	Grace:         48 * time.Hour,
+	CheckInterval: 5 * time.Minute,
+}
+
+func newTestKeyRotation(store *mockSigningKeyStore, static *keys.Manager, now *time.Time) *SigningKeyRotationService {
+	svc := NewSigningKeyRotationService(store, static, keys.ES256, testSigningKeyEncryptionKey, testKeyRotationConfig, logger.New("test", logger.ErrorLevel, false))
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func publishedKIDs(manager *keys.Manager) []string {
+	var kids []string
+	for _, info := range manager.Keys() {
+		kids = append(kids, info.KID)
+	}
+	return kids
+}
+
+func TestSigningKeyRotationService_Rotates(t *testing.T) {
+	store := &mockSigningKeyStore{keys: map[string]*models.StoredSigningKey{}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newTestKeyRotation(store, nil, &now)
+	ctx := context.Background()
+
+	// A first key is generated and signs at once
+	require.NoError(t, svc.Sync(ctx))
+	first, err := svc.Manager().GetCurrentKey()
+	require.NoError(t, err)
+	require.Len(t, store.keys, 1)
+	assert.NotContains(t, store.keys[first.KID].PrivateKey, "PRIVATE KEY", "keys should be stored encrypted")
+
+	// The next key is published ahead of its activation, the first one keeps signing
+	now = now.Add(29*24*time.Hour + time.Minute)
+	require.NoError(t, svc.Sync(ctx))
+	require.Len(t, store.keys, 2)
+	current, err := svc.Manager().GetCurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, first.KID, current.KID)
+	assert.Len(t, svc.Manager().GetJWKS().Keys, 2)
+
+	// It signs once active, and the first key stays published for the grace period
+	now = now.Add(24 * time.Hour)
+	require.NoError(t, svc.Sync(ctx))
+	second, err := svc.Manager().GetCurrentKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, first.KID, second.KID)
+	assert.Contains(t, publishedKIDs(svc.Manager()), first.KID)
+
+	now = now.Add(48 * time.Hour)
+	require.NoError(t, svc.Sync(ctx))
+	assert.Equal(t, []string{second.KID}, publishedKIDs(svc.Manager()))
+	assert.NotContains(t, store.keys, first.KID)
+}
+
+func TestSigningKeyRotationService_ReplicasShareKeys(t *testing.T) {
+	store := &mockSigningKeyStore{keys: map[string]*models.StoredSigningKey{}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	replica1 := newTestKeyRotation(store, nil, &now)
+	replica2 := newTestKeyRotation(store, nil, &now)
+	ctx := context.Background()
+
+	require.NoError(t, replica1.Sync(ctx))
+	require.NoError(t, replica2.Sync(ctx))
+	now = now.Add(29*24*time.Hour + time.Minute)
+	require.NoError(t, replica1.Sync(ctx))
+	require.NoError(t, replica2.Sync(ctx))
+
+	assert.Len(t, store.keys, 2, "only one replica should generate the rotated key")
+	assert.Equal(t, publishedKIDs(replica1.Manager()), publishedKIDs(replica2.Manager()))
+
+	// A token signed on one replica verifies on the other
+	signature, kid, err := replica1.Manager().Sign([]byte("token"))
+	require.NoError(t, err)
+	assert.NoError(t, replica2.Manager().Verify([]byte("token"), signature, kid))
+}
+
+func TestSigningKeyRotationService_ImportsConfiguredKeys(t *testing.T) {
+	var signingKeys []*keys.SigningKey
+	for _, kid := range []string{"old", "static"} {
+		privateKey, err := keys.GenerateKey(keys.RS256)
+		require.NoError(t, err)
+		key, err := keys.NewSigningKey(kid, keys.RS256, privateKey)
+		require.NoError(t, err)
+		signingKeys = append(signingKeys, key)
+	}
+	static, err := keys.NewManagerFromKeys(signingKeys, "static")
+	require.NoError(t, err)
+
+	store := &mockSigningKeyStore{keys: map[string]*models.StoredSigningKey{}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newTestKeyRotation(store, static, &now)
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	current, err := svc.Manager().GetCurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, "static", current.KID)
+	assert.Equal(t, []string{"old", "static"}, publishedKIDs(svc.Manager()))
+
+	// The replaced configured key retires after the grace period
+	now = now.Add(48 * time.Hour)
+	require.NoError(t, svc.Sync(context.Background()))
+	assert.Equal(t, []string{"static"}, publishedKIDs(svc.Manager()))
+}
+
+func TestSigningKeyRotationService_KeepsKeysWhenUnreadable(t *testing.T) {
+	store := &mockSigningKeyStore{keys: map[string]*models.StoredSigningKey{}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newTestKeyRotation(store, nil, &now)
+	require.NoError(t, svc.Sync(context.Background()))
+	loaded := publishedKIDs(svc.Manager())
+
+	svc.encryptionKey = "fedcba9876543210fedcba9876543210"
+	assert.Error(t, svc.Sync(context.Background()))
+	assert.Equal(t, loaded, publishedKIDs(svc.Manager()))
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+var (
+	errSignupDomainNotAllowed = models.NewAppError(http.StatusForbidden, "Sign-up with this email domain is not allowed")
+	errSignupDisposableEmail  = models.NewAppError(http.StatusForbidden, "Disposable email addresses are not allowed")
+	errSignupInviteOnly       = models.NewAppError(http.StatusForbidden, "Sign-up is by invitation only")
+	errSignupEmailRequired    = models.NewAppError(http.StatusForbidden, "An email address on an allowed domain is required to sign up")
+	errGroupDomainNotFound    = models.NewAppError(http.StatusNotFound, "Domain not found")
+	errGroupDomainTaken       = models.NewAppError(http.StatusConflict, "Domain is already claimed by a group")
+	errGroupDomainUnverified  = models.NewAppError(http.StatusBadRequest, "Verification TXT record not found")
+)
+
+// SignupPolicyService enforces the sign-up policy on self-service registrations (password,
+// passwordless and social login) and manages the email domains claimed by organizations.
+// Once a group has proven ownership of a domain through DNS, users signing up with a
+// verified address on it join the group automatically, even in invite-only mode.
+type SignupPolicyService struct {
+	settings  SettingStore
+	domains   GroupDomainStore
+	groupRepo GroupRepository
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	logger    *logger.Logger
+}
+
+// NewSignupPolicyService creates a new sign-up policy service
+func NewSignupPolicyService(settings SettingStore, domains GroupDomainStore, groupRepo GroupRepository, logger *logger.Logger) *SignupPolicyService {
+	return &SignupPolicyService{
+		settings:  settings,
+		domains:   domains,
+		groupRepo: groupRepo,
+		lookupTXT: net.DefaultResolver.LookupTXT,
+		logger:    logger,
+	}
+}
+
+// GetPolicy returns the current sign-up policy
+func (s *SignupPolicyService) GetPolicy(ctx context.Context) (*models.SignupPolicy, error) {
+	setting, err := s.settings.GetSetting(ctx, models.SettingSignupPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.SignupPolicy{}
+	if err := json.Unmarshal([]byte(setting.Value), policy); err != nil {
+		return nil, fmt.Errorf("invalid signup policy setting: %w", err)
+	}
+	if policy.AllowedDomains == nil {
+		policy.AllowedDomains = []string{}
+	}
+	if policy.BlockedDomains == nil {
+		policy.BlockedDomains = []string{}
+	}
+	return policy, nil
+}
+
+// UpdatePolicy replaces the sign-up policy
+func (s *SignupPolicyService) UpdatePolicy(ctx context.Context, req *models.UpdateSignupPolicyRequest, updatedBy uuid.UUID) (*models.SignupPolicy, error) {
+	allowed, err := normalizeDomains(req.AllowedDomains)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := normalizeDomains(req.BlockedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.SignupPolicy{
+		AllowedDomains:  allowed,
+		BlockedDomains:  blocked,
+		BlockDisposable: req.BlockDisposable,
+		InviteOnly:      req.InviteOnly,
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settings.UpdateSetting(ctx, models.SettingSignupPolicy, string(value), &updatedBy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CheckSignup returns an error if the policy does not allow a new account for the email
+// address. Phone-only registrations pass an empty email.
+func (s *SignupPolicyService) CheckSignup(ctx context.Context, email string) error {
+	return s.checkSignup(ctx, email, true)
+}
+
+// CheckUnverifiedSignup is CheckSignup for an email address nobody vouches for, such as one
+// reported unverified by an OAuth provider. The blocklists still apply, but the address
+// matches neither the allowlist nor a verified organization domain.
+func (s *SignupPolicyService) CheckUnverifiedSignup(ctx context.Context, email string) error {
+	return s.checkSignup(ctx, email, false)
+}
+
+func (s *SignupPolicyService) checkSignup(ctx context.Context, email string, trusted bool) error {
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	domain := utils.EmailDomain(email)
+	if domain == "" {
+		if policy.InviteOnly || len(policy.AllowedDomains) > 0 {
+			return errSignupEmailRequired
+		}
+		return nil
+	}
+
+	if matchesAnyDomain(domain, policy.BlockedDomains) {
+		return errSignupDomainNotAllowed
+	}
+	if policy.BlockDisposable && utils.IsDisposableEmailDomain(domain) {
+		return errSignupDisposableEmail
+	}
+
+	if !trusted {
+		if policy.InviteOnly {
+			return errSignupInviteOnly
+		}
+		if len(policy.AllowedDomains) > 0 {
+			return errSignupDomainNotAllowed
+		}
+		return nil
+	}
+
+	// Organizations that verified the domain invite everyone on it
+	orgDomains, err := s.domains.ListVerifiedForDomain(ctx, domain)
+	if err != nil {
+		return err
+	}
+	for _, orgDomain := range orgDomains {
+		if orgDomain.AutoJoin {
+			return nil
+		}
+	}
+
+	if policy.InviteOnly {
+		return errSignupInviteOnly
+	}
+	if len(policy.AllowedDomains) > 0 && !matchesAnyDomain(domain, policy.AllowedDomains) {
+		return errSignupDomainNotAllowed
+	}
+	return nil
+}
+
+// AutoJoin adds a user with a verified email address to the groups that verified its domain
+// with auto-join enabled. Failures are logged and do not fail the registration.
+func (s *SignupPolicyService) AutoJoin(ctx context.Context, user *models.User) {
+	if user == nil || !user.EmailVerified {
+		return
+	}
+	domain := utils.EmailDomain(user.Email)
+	if domain == "" {
+		return
+	}
+
+	orgDomains, err := s.domains.ListVerifiedForDomain(ctx, domain)
+	if err != nil {
+		s.logger.Warn("Failed to look up organization domains", map[string]interface{}{
+			"user_id": user.ID.String(),
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	for _, orgDomain := range orgDomains {
+		if !orgDomain.AutoJoin {
+			continue
+		}
+		if err := s.groupRepo.AddUser(ctx, orgDomain.GroupID, user.ID); err != nil {
+			s.logger.Warn("Failed to auto-join organization", map[string]interface{}{
+				"user_id":  user.ID.String(),
+				"group_id": orgDomain.GroupID.String(),
+				"error":    err.Error(),
+			})
+			continue
+		}
+		s.logger.Info("User auto-joined organization", map[string]interface{}{
+			"user_id":  user.ID.String(),
+			"group_id": orgDomain.GroupID.String(),
+			"domain":   orgDomain.Domain,
+		})
+	}
+}
+
+// ListDomains returns the email domains claimed by a group
+func (s *SignupPolicyService) ListDomains(ctx context.Context, groupID uuid.UUID) (*models.GroupDomainListResponse, error) {
+	if err := s.checkGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	domains, err := s.domains.ListByGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		domain.VerificationRecord = verificationRecordName(domain.Domain)
+	}
+	return &models.GroupDomainListResponse{Domains: domains}, nil
+}
+
+// AddDomain claims an email domain for a group. Ownership must then be proven by publishing
+// the verification token as a DNS TXT record and calling VerifyDomain.
+func (s *SignupPolicyService) AddDomain(ctx context.Context, groupID uuid.UUID, req *models.AddGroupDomainRequest, createdBy uuid.UUID) (*models.GroupDomain, error) {
+	if err := s.checkGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	name := utils.NormalizeDomain(req.Domain)
+	if !utils.IsValidDomain(name) {
+		return nil, models.NewAppError(http.StatusBadRequest, "Invalid domain", req.Domain)
+	}
+
+	token, err := generateSecureToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	autoJoin := true
+	if req.AutoJoin != nil {
+		autoJoin = *req.AutoJoin
+	}
+
+	domain := &models.GroupDomain{
+		GroupID:           groupID,
+		Domain:            name,
+		VerificationToken: "auth-gateway-verification=" + token,
+		AutoJoin:          autoJoin,
+		CreatedBy:         &createdBy,
+	}
+	if err := s.domains.Create(ctx, domain); err != nil {
+		if errors.Is(err, models.ErrAlreadyExists) {
+			return nil, errGroupDomainTaken
+		}
+		return nil, err
+	}
+	domain.VerificationRecord = verificationRecordName(domain.Domain)
+	return domain, nil
+}
+
+// VerifyDomain checks the DNS TXT record of a claimed domain and marks it verified
+func (s *SignupPolicyService) VerifyDomain(ctx context.Context, groupID, domainID uuid.UUID) (*models.GroupDomain, error) {
+	domain, err := s.getDomain(ctx, groupID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	domain.VerificationRecord = verificationRecordName(domain.Domain)
+	if domain.VerifiedAt != nil {
+		return domain, nil
+	}
+
+	records, err := s.lookupTXT(ctx, domain.VerificationRecord)
+	if err != nil {
+		return nil, errGroupDomainUnverified
+	}
+	found := false
+	for _, record := range records {
+		if record == domain.VerificationToken {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errGroupDomainUnverified
+	}
+
+	now := time.Now()
+	if err := s.domains.MarkVerified(ctx, domain.ID, now); err != nil {
+		return nil, err
+	}
+	domain.VerifiedAt = &now
+
+	s.logger.Info("Organization domain verified", map[string]interface{}{
+		"group_id": groupID.String(),
+		"domain":   domain.Domain,
+	})
+	return domain, nil
+}
+
+// RemoveDomain releases a domain claimed by a group
+func (s *SignupPolicyService) RemoveDomain(ctx context.Context, groupID, domainID uuid.UUID) error {
+	if _, err := s.getDomain(ctx, groupID, domainID); err != nil {
+		return err
+	}
+	return s.domains.Delete(ctx, domainID)
+}
+
+func (s *SignupPolicyService) getDomain(ctx context.Context, groupID, domainID uuid.UUID) (*models.GroupDomain, error) {
+	domain, err := s.domains.GetByID(ctx, domainID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errGroupDomainNotFound
+		}
+		return nil, err
+	}
+	if domain.GroupID != groupID {
+		return nil, errGroupDomainNotFound
+	}
+	return domain, nil
+}
+
+func (s *SignupPolicyService) checkGroup(ctx context.Context, groupID uuid.UUID) error {
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return errGroupNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func verificationRecordName(domain string) string {
+	return models.GroupDomainVerificationPrefix + "." + domain
+}
+
+func matchesAnyDomain(domain string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if utils.DomainMatches(domain, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDomains validates and de-duplicates a domain list
+func normalizeDomains(domains []string) ([]string, error) {
+	normalized := make([]string, 0, len(domains))
+	seen := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		name := utils.NormalizeDomain(domain)
+		if !utils.IsValidDomain(name) {
+			return nil, models.NewAppError(http.StatusBadRequest, "Invalid domain", domain)
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
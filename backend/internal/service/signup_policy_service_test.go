@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGroupDomainStore struct {
+	domains map[uuid.UUID]*models.GroupDomain
+}
+
+func newMockGroupDomainStore() *mockGroupDomainStore {
+	return &mockGroupDomainStore{domains: make(map[uuid.UUID]*models.GroupDomain)}
+}
+
+func (m *mockGroupDomainStore) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]*models.GroupDomain, error) {
+	var result []*models.GroupDomain
+	for _, domain := range m.domains {
+		if domain.GroupID == groupID {
+			result = append(result, domain)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockGroupDomainStore) GetByID(ctx context.Context, id uuid.UUID) (*models.GroupDomain, error) {
+	domain, ok := m.domains[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return domain, nil
+}
+
+func (m *mockGroupDomainStore) Create(ctx context.Context, domain *models.GroupDomain) error {
+	for _, existing := range m.domains {
+		if existing.Domain == domain.Domain {
+			return models.ErrAlreadyExists
+		}
+	}
+	domain.ID = uuid.New()
+	m.domains[domain.ID] = domain
+	return nil
+}
+
+func (m *mockGroupDomainStore) MarkVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	domain, ok := m.domains[id]
+	if !ok {
+		return models.ErrNotFound
+	}
+	domain.VerifiedAt = &verifiedAt
+	return nil
+}
+
+func (m *mockGroupDomainStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := m.domains[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(m.domains, id)
+	return nil
+}
+
+func (m *mockGroupDomainStore) ListVerifiedForDomain(ctx context.Context, domain string) ([]*models.GroupDomain, error) {
+	var result []*models.GroupDomain
+	for _, d := range m.domains {
+		if d.VerifiedAt != nil && utils.DomainMatches(domain, d.Domain) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func newTestSignupPolicyService(policy string) (*SignupPolicyService, *mockGroupDomainStore, *mockGroupStore) {
+	settings := &mockSettingStore{values: map[string]string{models.SettingSignupPolicy: policy}}
+	domains := newMockGroupDomainStore()
+	groups := newMockGroupStore()
+	svc := NewSignupPolicyService(settings, domains, groups, logger.New("test", logger.InfoLevel, false))
+	return svc, domains, groups
+}
+
+func addVerifiedDomain(domains *mockGroupDomainStore, groupID uuid.UUID, name string, autoJoin bool) {
+	now := time.Now()
+	domain := &models.GroupDomain{ID: uuid.New(), GroupID: groupID, Domain: name, AutoJoin: autoJoin, VerifiedAt: &now}
+	domains.domains[domain.ID] = domain
+}
+
+func TestSignupPolicyService_CheckSignup(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		policy string
+		email  string
+		want   error
+	}{
+		{"open policy", `{}`, "user@gmail.com", nil},
+		{"phone-only on open policy", `{}`, "", nil},
+		{"allowed domain", `{"allowed_domains":["acme.com"]}`, "user@acme.com", nil},
+		{"allowed subdomain", `{"allowed_domains":["acme.com"]}`, "user@eu.acme.com", nil},
+		{"domain not in allowlist", `{"allowed_domains":["acme.com"]}`, "user@evil-acme.com", errSignupDomainNotAllowed},
+		{"phone-only with allowlist", `{"allowed_domains":["acme.com"]}`, "", errSignupEmailRequired},
+		{"blocked domain", `{"blocked_domains":["competitor.com"]}`, "spy@competitor.com", errSignupDomainNotAllowed},
+		{"disposable blocked", `{"block_disposable":true}`, "x@mailinator.com", errSignupDisposableEmail},
+		{"disposable allowed when not blocked", `{}`, "x@mailinator.com", nil},
+		{"invite only", `{"invite_only":true}`, "user@gmail.com", errSignupInviteOnly},
+		{"invite only with verified org domain", `{"invite_only":true}`, "user@partner.io", nil},
+		{"verified org domain outside allowlist", `{"allowed_domains":["acme.com"]}`, "user@partner.io", nil},
+		{"blocklist wins over org domain", `{"blocked_domains":["partner.io"]}`, "user@partner.io", errSignupDomainNotAllowed},
+		{"org domain without auto-join", `{"invite_only":true}`, "user@manual.io", errSignupInviteOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, domains, _ := newTestSignupPolicyService(tt.policy)
+			addVerifiedDomain(domains, uuid.New(), "partner.io", true)
+			addVerifiedDomain(domains, uuid.New(), "manual.io", false)
+
+			err := svc.CheckSignup(ctx, tt.email)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestSignupPolicyService_CheckUnverifiedSignup(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		policy string
+		email  string
+		want   error
+	}{
+		{"open policy", `{}`, "user@gmail.com", nil},
+		{"allowed domain", `{"allowed_domains":["acme.com"]}`, "user@acme.com", errSignupDomainNotAllowed},
+		{"verified org domain", `{"invite_only":true}`, "user@partner.io", errSignupInviteOnly},
+		{"blocked domain", `{"blocked_domains":["competitor.com"]}`, "spy@competitor.com", errSignupDomainNotAllowed},
+		{"disposable blocked", `{"block_disposable":true}`, "x@mailinator.com", errSignupDisposableEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, domains, _ := newTestSignupPolicyService(tt.policy)
+			addVerifiedDomain(domains, uuid.New(), "partner.io", true)
+
+			err := svc.CheckUnverifiedSignup(ctx, tt.email)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestSignupPolicyService_UpdatePolicy(t *testing.T) {
+	svc, _, _ := newTestSignupPolicyService(`{}`)
+	ctx := context.Background()
+
+	policy, err := svc.UpdatePolicy(ctx, &models.UpdateSignupPolicyRequest{
+		AllowedDomains: []string{" ACME.com ", "acme.com", "@beta.io"},
+		InviteOnly:     true,
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme.com", "beta.io"}, policy.AllowedDomains)
+	assert.Equal(t, []string{}, policy.BlockedDomains)
+
+	stored, err := svc.GetPolicy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, policy, stored)
+
+	_, err = svc.UpdatePolicy(ctx, &models.UpdateSignupPolicyRequest{BlockedDomains: []string{"not a domain"}}, uuid.New())
+	var appErr *models.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestSignupPolicyService_AutoJoin(t *testing.T) {
+	svc, domains, groups := newTestSignupPolicyService(`{}`)
+	ctx := context.Background()
+
+	autoGroup := uuid.New()
+	manualGroup := uuid.New()
+	addVerifiedDomain(domains, autoGroup, "acme.com", true)
+	addVerifiedDomain(domains, manualGroup, "eu.acme.com", false)
+
+	unverified := &models.User{ID: uuid.New(), Email: "new@eu.acme.com"}
+	svc.AutoJoin(ctx, unverified)
+	assert.Empty(t, groups.users[autoGroup])
+
+	user := &models.User{ID: uuid.New(), Email: "new@eu.acme.com", EmailVerified: true}
+	svc.AutoJoin(ctx, user)
+	assert.Equal(t, []uuid.UUID{user.ID}, groups.users[autoGroup])
+	assert.Empty(t, groups.users[manualGroup])
+}
+
+func TestSignupPolicyService_DomainVerification(t *testing.T) {
+	svc, domains, groups := newTestSignupPolicyService(`{}`)
+	ctx := context.Background()
+
+	group := &models.Group{ID: uuid.New(), Name: "acme"}
+	groups.groups[group.ID] = group
+
+	domain, err := svc.AddDomain(ctx, group.ID, &models.AddGroupDomainRequest{Domain: "Acme.com"}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "acme.com", domain.Domain)
+	assert.True(t, domain.AutoJoin)
+	assert.Equal(t, "_auth-gateway-verification.acme.com", domain.VerificationRecord)
+
+	_, err = svc.AddDomain(ctx, group.ID, &models.AddGroupDomainRequest{Domain: "acme.com"}, uuid.New())
+	assert.Equal(t, errGroupDomainTaken, err)
+
+	var lookedUp string
+	records := []string{"v=spf1 -all"}
+	svc.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		lookedUp = name
+		return records, nil
+	}
+
+	_, err = svc.VerifyDomain(ctx, group.ID, domain.ID)
+	assert.Equal(t, errGroupDomainUnverified, err)
+	assert.Equal(t, domain.VerificationRecord, lookedUp)
+
+	records = append(records, domain.VerificationToken)
+	verified, err := svc.VerifyDomain(ctx, group.ID, domain.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, verified.VerifiedAt)
+	assert.NotNil(t, domains.domains[domain.ID].VerifiedAt)
+
+	_, err = svc.VerifyDomain(ctx, uuid.New(), domain.ID)
+	assert.Equal(t, errGroupDomainNotFound, err)
+
+	require.NoError(t, svc.RemoveDomain(ctx, group.ID, domain.ID))
+	assert.Empty(t, domains.domains)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/metrics"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	// sloBurnBucketWidth is the resolution of the burn rate windows
+	sloBurnBucketWidth = time.Minute
+	// sloBudgetBucketWidth is the resolution of the SLO window
+	sloBudgetBucketWidth = time.Hour
+	// sloAlertMinRequests is how many requests the long window of an alert rule needs before
+	// the rule is evaluated, so that a few failures on an idle endpoint raise no alert
+	sloAlertMinRequests = 100
+)
+
+// sloAlertRule raises an alert when the error budget burns faster than burnRate over both
+// windows: the long one proves the burn is significant, the short one that it still goes on.
+// The rates are those of the Google SRE workbook for a 30 day window: 2% of the budget spent
+// in an hour, or 5% in six hours.
+type sloAlertRule struct {
+	severity    string
+	longWindow  time.Duration
+	shortWindow time.Duration
+	burnRate    float64
+}
+
+// sloAlertRules are ordered by severity, the first rule that fires wins
+var sloAlertRules = []sloAlertRule{
+	{severity: models.SLOAlertCritical, longWindow: time.Hour, shortWindow: 5 * time.Minute, burnRate: 14.4},
+	{severity: models.SLOAlertWarning, longWindow: 6 * time.Hour, shortWindow: 30 * time.Minute, burnRate: 6},
+}
+
+// sloBurnWindows are the windows burn rates are reported for
+var sloBurnWindows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour}
+
+// DefaultSLODefinitions returns the built-in SLOs of the critical endpoints
+func DefaultSLODefinitions() []models.SLODefinition {
+	return []models.SLODefinition{
+		{Name: "signin_availability", Method: http.MethodPost, Route: "/api/auth/signin", Type: models.SLOTypeAvailability, Objective: 0.999},
+		{Name: "signin_latency", Method: http.MethodPost, Route: "/api/auth/signin", Type: models.SLOTypeLatency, Objective: 0.99, LatencyThreshold: time.Second},
+		{Name: "token_availability", Method: http.MethodPost, Route: "/oauth/token", Type: models.SLOTypeAvailability, Objective: 0.999},
+		{Name: "token_latency", Method: http.MethodPost, Route: "/oauth/token", Type: models.SLOTypeLatency, Objective: 0.99, LatencyThreshold: 500 * time.Millisecond},
+		{Name: "validate_availability", Method: http.MethodPost, Route: "/api/v1/token/validate", Type: models.SLOTypeAvailability, Objective: 0.9995},
+		{Name: "validate_latency", Method: http.MethodPost, Route: "/api/v1/token/validate", Type: models.SLOTypeLatency, Objective: 0.99, LatencyThreshold: 100 * time.Millisecond},
+	}
+}
+
+// sloBucket counts the requests of one time slot
+type sloBucket struct {
+	slot  int64
+	total int64
+	bad   int64
+}
+
+// sloRing counts requests in fixed-width time slots, reusing the buckets of slots older
+// than the span it was created for
+type sloRing struct {
+	width   time.Duration
+	buckets []sloBucket
+}
+
+func newSLORing(width, span time.Duration) *sloRing {
+	return &sloRing{width: width, buckets: make([]sloBucket, int(span/width)+1)}
+}
+
+func (r *sloRing) add(now time.Time, bad bool) {
+	slot := now.UnixNano() / int64(r.width)
+	bucket := &r.buckets[slot%int64(len(r.buckets))]
+	if bucket.slot != slot {
+		*bucket = sloBucket{slot: slot}
+	}
+	bucket.total++
+	if bad {
+		bucket.bad++
+	}
+}
+
+// sum returns the requests of the span ending now, the current slot included
+func (r *sloRing) sum(now time.Time, span time.Duration) (total, bad int64) {
+	slot := now.UnixNano() / int64(r.width)
+	slots := int64(span / r.width)
+	if slots > int64(len(r.buckets)) {
+		slots = int64(len(r.buckets))
+	}
+	for i := int64(0); i < slots; i++ {
+		bucket := r.buckets[(slot-i)%int64(len(r.buckets))]
+		if bucket.slot == slot-i {
+			total += bucket.total
+			bad += bucket.bad
+		}
+	}
+	return total, bad
+}
+
+// trackedSLO counts the requests of one SLO, per minute for the burn rate windows and per
+// hour for the SLO window
+type trackedSLO struct {
+	definition models.SLODefinition
+
+	mu     sync.Mutex
+	burn   *sloRing
+	budget *sloRing
+}
+
+// SLOService tracks the built-in service level objectives of the critical endpoints,
+// computes their rolling error budgets and alerts through the slo.budget_burn webhook when a
+// budget burns too fast. Requests are counted in memory, so the budgets are those of this
+// instance; the auth_gateway_slo_requests_total metric aggregates them across instances.
+type SLOService struct {
+	slos          []*trackedSLO
+	byRoute       map[string][]*trackedSLO
+	window        time.Duration
+	alertCooldown time.Duration
+	startedAt     time.Time
+	redis         RedisServicer
+	logger        *logger.Logger
+	now           func() time.Time
+
+	webhooks WebhookTrigger
+
+	alertMu     sync.Mutex
+	lastAlerted map[string]time.Time
+}
+
+// NewSLOService creates a new SLO service tracking the given SLOs over window
+func NewSLOService(definitions []models.SLODefinition, window, alertCooldown time.Duration, redis RedisServicer, logger *logger.Logger) *SLOService {
+	s := &SLOService{
+		byRoute:       make(map[string][]*trackedSLO),
+		window:        window,
+		alertCooldown: alertCooldown,
+		startedAt:     time.Now(),
+		redis:         redis,
+		logger:        logger,
+		now:           time.Now,
+		lastAlerted:   make(map[string]time.Time),
+	}
+
+	burnSpan := sloBurnWindows[len(sloBurnWindows)-1]
+	for _, definition := range definitions {
+		slo := &trackedSLO{
+			definition: definition,
+			burn:       newSLORing(sloBurnBucketWidth, burnSpan),
+			budget:     newSLORing(sloBudgetBucketWidth, window),
+		}
+		s.slos = append(s.slos, slo)
+		key := definition.Method + " " + definition.Route
+		s.byRoute[key] = append(s.byRoute[key], slo)
+	}
+	return s
+}
+
+// SetWebhooks announces budget burn alerts through webhooks
+func (s *SLOService) SetWebhooks(webhooks WebhookTrigger) {
+	s.webhooks = webhooks
+}
+
+// Record counts a request against the SLOs of its route. Server errors are bad for
+// availability; latency SLOs count the requests answered without one.
+func (s *SLOService) Record(method, route string, status int, latency time.Duration) {
+	slos := s.byRoute[method+" "+route]
+	if len(slos) == 0 {
+		return
+	}
+
+	now := s.now()
+	for _, slo := range slos {
+		var bad bool
+		switch slo.definition.Type {
+		case models.SLOTypeAvailability:
+			bad = status >= http.StatusInternalServerError
+		case models.SLOTypeLatency:
+			if status >= http.StatusInternalServerError {
+				continue
+			}
+			bad = latency > slo.definition.LatencyThreshold
+		}
+
+		slo.mu.Lock()
+		slo.burn.add(now, bad)
+		slo.budget.add(now, bad)
+		slo.mu.Unlock()
+
+		metrics.RecordSLORequest(slo.definition.Name, !bad)
+	}
+}
+
+// ListSLOs returns the SLOs with their error budgets and burn rates
+func (s *SLOService) ListSLOs() *models.SLOListResponse {
+	now := s.now()
+	response := &models.SLOListResponse{
+		Window:       s.window.String(),
+		SLOs:         make([]models.SLOStatus, 0, len(s.slos)),
+		TrackedSince: s.startedAt.UTC(),
+	}
+	for _, slo := range s.slos {
+		status, _ := s.status(slo, now)
+		response.SLOs = append(response.SLOs, status)
+	}
+	return response
+}
+
+// CheckBudgets updates the SLO metrics and alerts for the SLOs whose error budget burns too
+// fast. Each SLO alerts at most once per severity and cooldown, across all instances. It
+// returns the number of alerts raised.
+func (s *SLOService) CheckBudgets(ctx context.Context) int {
+	now := s.now()
+	raised := 0
+	for _, slo := range s.slos {
+		status, rule := s.status(slo, now)
+
+		metrics.UpdateSLOBudget(status.Name, status.Objective, status.ErrorBudgetRemaining)
+		for _, burnRate := range status.BurnRates {
+			metrics.UpdateSLOBurnRate(status.Name, burnRate.Window, burnRate.BurnRate)
+		}
+
+		if rule != nil && s.claimAlert(ctx, status.Name, rule.severity, now) {
+			s.alert(status, rule)
+			raised++
+		}
+	}
+	return raised
+}
+
+// status computes the error budget of an SLO and the alert rule firing for it, if any
+func (s *SLOService) status(slo *trackedSLO, now time.Time) (models.SLOStatus, *sloAlertRule) {
+	definition := slo.definition
+	errorBudget := 1 - definition.Objective
+
+	slo.mu.Lock()
+	defer slo.mu.Unlock()
+
+	total, bad := slo.budget.sum(now, s.window)
+	status := models.SLOStatus{
+		Name:                 definition.Name,
+		Endpoint:             definition.Method + " " + definition.Route,
+		Type:                 definition.Type,
+		Objective:            definition.Objective,
+		LatencyThresholdMs:   definition.LatencyThreshold.Milliseconds(),
+		TotalRequests:        total,
+		BadRequests:          bad,
+		SLI:                  1,
+		ErrorBudgetRemaining: 1,
+		BurnRates:            make([]models.SLOBurnRate, 0, len(sloBurnWindows)),
+	}
+	if total > 0 {
+		status.SLI = 1 - float64(bad)/float64(total)
+		status.ErrorBudgetRemaining = 1 - float64(bad)/float64(total)/errorBudget
+	}
+
+	burnRates := make(map[time.Duration]models.SLOBurnRate, len(sloBurnWindows))
+	for _, window := range sloBurnWindows {
+		total, bad := slo.burn.sum(now, window)
+		burnRate := models.SLOBurnRate{Window: formatSLOWindow(window), TotalRequests: total, BadRequests: bad}
+		if total > 0 {
+			burnRate.BurnRate = float64(bad) / float64(total) / errorBudget
+		}
+		burnRates[window] = burnRate
+		status.BurnRates = append(status.BurnRates, burnRate)
+	}
+
+	for i := range sloAlertRules {
+		rule := &sloAlertRules[i]
+		long, short := burnRates[rule.longWindow], burnRates[rule.shortWindow]
+		if long.TotalRequests >= sloAlertMinRequests && long.BurnRate >= rule.burnRate && short.BurnRate >= rule.burnRate {
+			status.Alert = rule.severity
+			return status, rule
+		}
+	}
+	return status, nil
+}
+
+// claimAlert reports whether an alert of the SLO and severity may be sent now. Redis keeps
+// other instances from sending the same alert; when it can't be reached, the cooldown of
+// this instance still applies.
+func (s *SLOService) claimAlert(ctx context.Context, name, severity string, now time.Time) bool {
+	key := name + ":" + severity
+
+	s.alertMu.Lock()
+	defer s.alertMu.Unlock()
+	if last, ok := s.lastAlerted[key]; ok && now.Sub(last) < s.alertCooldown {
+		return false
+	}
+
+	if s.redis != nil {
+		claimed, err := s.redis.SetNX(ctx, "slo_alert:"+key, now.Unix(), s.alertCooldown)
+		if err != nil {
+			s.logger.Warn("failed to claim SLO alert, sending it from this instance", map[string]interface{}{
+				"slo":   name,
+				"error": err.Error(),
+			})
+		} else if !claimed {
+			s.lastAlerted[key] = now
+			return false
+		}
+	}
+
+	s.lastAlerted[key] = now
+	return true
+}
+
+// alert logs a budget burn alert and announces it through the slo.budget_burn webhook
+func (s *SLOService) alert(status models.SLOStatus, rule *sloAlertRule) {
+	var longBurnRate float64
+	for _, burnRate := range status.BurnRates {
+		if burnRate.Window == formatSLOWindow(rule.longWindow) {
+			longBurnRate = burnRate.BurnRate
+		}
+	}
+
+	s.logger.Warn("SLO error budget burns too fast", map[string]interface{}{
+		"slo":                    status.Name,
+		"severity":               rule.severity,
+		"burn_rate":              longBurnRate,
+		"window":                 formatSLOWindow(rule.longWindow),
+		"error_budget_remaining": status.ErrorBudgetRemaining,
+	})
+
+	if s.webhooks == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"slo":                    status.Name,
+		"endpoint":               status.Endpoint,
+		"type":                   string(status.Type),
+		"objective":              status.Objective,
+		"severity":               rule.severity,
+		"burn_rate":              longBurnRate,
+		"burn_rate_threshold":    rule.burnRate,
+		"long_window":            formatSLOWindow(rule.longWindow),
+		"short_window":           formatSLOWindow(rule.shortWindow),
+		"error_budget_remaining": status.ErrorBudgetRemaining,
+		"timestamp":              time.Now().UTC().Format(time.RFC3339),
+	}
+	go func() {
+		webhookCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := s.webhooks.TriggerWebhook(webhookCtx, models.WebhookEventSLOBudgetBurn, data); err != nil {
+			s.logger.Warn("failed to trigger slo.budget_burn webhook", map[string]interface{}{
+				"slo":   status.Name,
+				"error": err.Error(),
+			})
+		}
+	}()
+}
+
+// formatSLOWindow formats a window the way Prometheus ranges are written, e.g. 5m or 6h
+func formatSLOWindow(window time.Duration) string {
+	if window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", window/time.Hour)
+	}
+	return fmt.Sprintf("%dm", window/time.Minute)
+}
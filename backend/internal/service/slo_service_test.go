@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSLOAlertClaims answers the SetNX calls alerts are claimed with
+type mockSLOAlertClaims struct {
+	RedisServicer
+	claimed bool
+	keys    []string
+}
+
+func (m *mockSLOAlertClaims) SetNX(_ context.Context, key string, _ interface{}, _ time.Duration) (bool, error) {
+	m.keys = append(m.keys, key)
+	return m.claimed, nil
+}
+
+// mockSLOWebhooks records the webhook events triggered
+type mockSLOWebhooks struct {
+	events chan map[string]interface{}
+}
+
+func (m *mockSLOWebhooks) TriggerWebhook(_ context.Context, eventType string, data map[string]interface{}) error {
+	if eventType == models.WebhookEventSLOBudgetBurn {
+		m.events <- data
+	}
+	return nil
+}
+
+var testTokenSLOs = []models.SLODefinition{
+	{Name: "token_availability", Method: http.MethodPost, Route: "/oauth/token", Type: models.SLOTypeAvailability, Objective: 0.999},
+	{Name: "token_latency", Method: http.MethodPost, Route: "/oauth/token", Type: models.SLOTypeLatency, Objective: 0.99, LatencyThreshold: 500 * time.Millisecond},
+}
+
+func newTestSLOService(redis RedisServicer) (*SLOService, *time.Time) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	s := NewSLOService(testTokenSLOs, 720*time.Hour, time.Hour, redis, logger.New("test", logger.DebugLevel, false))
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func findSLO(t *testing.T, list *models.SLOListResponse, name string) models.SLOStatus {
+	for _, slo := range list.SLOs {
+		if slo.Name == name {
+			return slo
+		}
+	}
+	t.Fatalf("SLO %s not listed", name)
+	return models.SLOStatus{}
+}
+
+func findBurnRate(t *testing.T, slo models.SLOStatus, window string) models.SLOBurnRate {
+	for _, burnRate := range slo.BurnRates {
+		if burnRate.Window == window {
+			return burnRate
+		}
+	}
+	t.Fatalf("burn rate over %s not listed", window)
+	return models.SLOBurnRate{}
+}
+
+func TestSLOService_Record_ShouldCountRequestsAgainstRouteSLOs(t *testing.T) {
+	s, _ := newTestSLOService(nil)
+
+	s.Record(http.MethodPost, "/oauth/token", http.StatusOK, 100*time.Millisecond)
+	s.Record(http.MethodPost, "/oauth/token", http.StatusOK, time.Second)
+	s.Record(http.MethodPost, "/oauth/token", http.StatusBadRequest, 10*time.Millisecond)
+	s.Record(http.MethodPost, "/oauth/token", http.StatusServiceUnavailable, 2*time.Second)
+	s.Record(http.MethodPost, "/oauth/revoke", http.StatusInternalServerError, time.Millisecond)
+
+	list := s.ListSLOs()
+
+	availability := findSLO(t, list, "token_availability")
+	assert.Equal(t, int64(4), availability.TotalRequests)
+	assert.Equal(t, int64(1), availability.BadRequests, "only server errors are bad for availability")
+
+	latency := findSLO(t, list, "token_latency")
+	assert.Equal(t, int64(3), latency.TotalRequests, "server errors don't count for latency")
+	assert.Equal(t, int64(1), latency.BadRequests)
+	assert.Equal(t, int64(500), latency.LatencyThresholdMs)
+}
+
+func TestSLOService_ListSLOs_ShouldComputeErrorBudget(t *testing.T) {
+	s, _ := newTestSLOService(nil)
+
+	for i := 0; i < 2000; i++ {
+		status := http.StatusOK
+		if i < 1 {
+			status = http.StatusInternalServerError
+		}
+		s.Record(http.MethodPost, "/oauth/token", status, time.Millisecond)
+	}
+
+	slo := findSLO(t, s.ListSLOs(), "token_availability")
+
+	assert.InDelta(t, 0.9995, slo.SLI, 1e-9)
+	assert.InDelta(t, 0.5, slo.ErrorBudgetRemaining, 1e-9)
+	assert.InDelta(t, 0.5, findBurnRate(t, slo, "1h").BurnRate, 1e-9)
+	assert.Empty(t, slo.Alert)
+}
+
+func TestSLOService_ListSLOs_ShouldDropRequestsOutsideWindows(t *testing.T) {
+	s, now := newTestSLOService(nil)
+	s.Record(http.MethodPost, "/oauth/token", http.StatusInternalServerError, time.Millisecond)
+
+	*now = now.Add(2 * time.Hour)
+	slo := findSLO(t, s.ListSLOs(), "token_availability")
+
+	assert.Equal(t, int64(0), findBurnRate(t, slo, "1h").TotalRequests)
+	assert.Equal(t, int64(1), findBurnRate(t, slo, "6h").TotalRequests)
+	assert.Equal(t, int64(1), slo.TotalRequests)
+
+	*now = now.Add(721 * time.Hour)
+	slo = findSLO(t, s.ListSLOs(), "token_availability")
+
+	assert.Equal(t, int64(0), slo.TotalRequests)
+	assert.Equal(t, float64(1), slo.ErrorBudgetRemaining)
+}
+
+func TestSLOService_CheckBudgets_ShouldAlertOnce_WhenBudgetBurnsFast(t *testing.T) {
+	claims := &mockSLOAlertClaims{claimed: true}
+	webhooks := &mockSLOWebhooks{events: make(chan map[string]interface{}, 10)}
+	s, _ := newTestSLOService(claims)
+	s.SetWebhooks(webhooks)
+
+	for i := 0; i < 200; i++ {
+		status := http.StatusOK
+		if i%10 == 0 {
+			status = http.StatusBadGateway
+		}
+		s.Record(http.MethodPost, "/oauth/token", status, time.Millisecond)
+	}
+
+	assert.Equal(t, models.SLOAlertCritical, findSLO(t, s.ListSLOs(), "token_availability").Alert)
+	assert.Equal(t, 1, s.CheckBudgets(context.Background()))
+	assert.Equal(t, 0, s.CheckBudgets(context.Background()), "alert is sent once per cooldown")
+
+	select {
+	case data := <-webhooks.events:
+		assert.Equal(t, "token_availability", data["slo"])
+		assert.Equal(t, models.SLOAlertCritical, data["severity"])
+		assert.Equal(t, "1h", data["long_window"])
+		assert.InDelta(t, 100, data["burn_rate"], 1e-9)
+	case <-time.After(time.Second):
+		t.Fatal("slo.budget_burn webhook not triggered")
+	}
+	assert.Equal(t, []string{"slo_alert:token_availability:critical"}, claims.keys)
+}
+
+func TestSLOService_CheckBudgets_ShouldNotAlert_WhenAnotherInstanceAlerted(t *testing.T) {
+	s, _ := newTestSLOService(&mockSLOAlertClaims{claimed: false})
+
+	for i := 0; i < 200; i++ {
+		s.Record(http.MethodPost, "/oauth/token", http.StatusInternalServerError, time.Millisecond)
+	}
+
+	assert.Equal(t, 0, s.CheckBudgets(context.Background()))
+}
+
+func TestSLOService_CheckBudgets_ShouldNotAlert_WhenTooFewRequests(t *testing.T) {
+	s, _ := newTestSLOService(nil)
+
+	for i := 0; i < sloAlertMinRequests-1; i++ {
+		s.Record(http.MethodPost, "/oauth/token", http.StatusInternalServerError, time.Millisecond)
+	}
+
+	require.Empty(t, findSLO(t, s.ListSLOs(), "token_availability").Alert)
+	assert.Equal(t, 0, s.CheckBudgets(context.Background()))
+}
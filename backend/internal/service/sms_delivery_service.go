@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// SMSDeliveryService records the delivery status SMS providers report for sent messages
+type SMSDeliveryService struct {
+	smsLogRepo   SMSLogStore
+	auditService AuditLogger
+	logger       *logger.Logger
+}
+
+// NewSMSDeliveryService creates a new SMS delivery service
+func NewSMSDeliveryService(smsLogRepo SMSLogStore, auditService AuditLogger, log *logger.Logger) *SMSDeliveryService {
+	return &SMSDeliveryService{
+		smsLogRepo:   smsLogRepo,
+		auditService: auditService,
+		logger:       log,
+	}
+}
+
+// RecordDeliveryReport updates the SMS log of a reported message and writes the outcome to
+// the audit log. Intermediate states such as queued are ignored, as are reports for messages
+// this gateway did not send and repeated reports for a message whose outcome is known.
+func (s *SMSDeliveryService) RecordDeliveryReport(ctx context.Context, provider string, report *sms.DeliveryReport, ip string) error {
+	var status models.SMSStatus
+	switch report.Status {
+	case sms.DeliveryStatusDelivered:
+		status = models.SMSStatusDelivered
+	case sms.DeliveryStatusFailed:
+		status = models.SMSStatusFailed
+	default:
+		return nil
+	}
+
+	smsLog, err := s.smsLogRepo.GetByMessageID(ctx, provider, report.MessageID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			s.logger.Debug("Delivery report for unknown SMS message", map[string]interface{}{
+				"provider":   provider,
+				"message_id": report.MessageID,
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to find SMS log: %w", err)
+	}
+
+	// Providers may retry or deliver reports out of order
+	if smsLog.Status == models.SMSStatusDelivered || smsLog.Status == models.SMSStatusFailed {
+		return nil
+	}
+
+	var errMsg *string
+	if status == models.SMSStatusFailed {
+		msg := fmt.Sprintf("delivery failed: %s", report.ProviderStatus)
+		if report.ErrorCode != "" {
+			msg += fmt.Sprintf(" (error code %s)", report.ErrorCode)
+		}
+		errMsg = &msg
+	}
+
+	if err := s.smsLogRepo.UpdateStatus(ctx, smsLog.ID, status, errMsg); err != nil {
+		return fmt.Errorf("failed to update SMS log: %w", err)
+	}
+
+	details := map[string]interface{}{
+		"sms_log_id":      smsLog.ID.String(),
+		"message_id":      report.MessageID,
+		"provider":        provider,
+		"type":            smsLog.Type,
+		"status":          status,
+		"provider_status": report.ProviderStatus,
+	}
+	if report.ErrorCode != "" {
+		details["error_code"] = report.ErrorCode
+	}
+
+	auditStatus := models.StatusSuccess
+	if status == models.SMSStatusFailed {
+		auditStatus = models.StatusFailed
+	}
+
+	s.auditService.Log(AuditLogParams{
+		UserID:  smsLog.UserID,
+		Action:  models.ActionSMSDeliveryStatus,
+		Status:  auditStatus,
+		IP:      ip,
+		Details: details,
+	})
+
+	return nil
+}
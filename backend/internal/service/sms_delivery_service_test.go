@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/sms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSMSDeliveryService(smsLog *models.SMSLog) (*SMSDeliveryService, *mockSMSLogStore, *[]AuditLogParams, *[]models.SMSStatus) {
+	var audited []AuditLogParams
+	var updated []models.SMSStatus
+	store := &mockSMSLogStore{
+		GetByMessageIDFunc: func(ctx context.Context, provider, messageID string) (*models.SMSLog, error) {
+			if smsLog == nil || provider != smsLog.Provider || messageID != *smsLog.MessageID {
+				return nil, models.ErrNotFound
+			}
+			return smsLog, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id uuid.UUID, status models.SMSStatus, errorMsg *string) error {
+			updated = append(updated, status)
+			return nil
+		},
+	}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { audited = append(audited, params) }}
+	return NewSMSDeliveryService(store, audit, testLogger()), store, &audited, &updated
+}
+
+func TestSMSDeliveryService_RecordDeliveryReport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	newLog := func(status models.SMSStatus) *models.SMSLog {
+		messageID := "SM123"
+		return &models.SMSLog{ID: uuid.New(), Provider: "twilio", MessageID: &messageID, Status: status, UserID: &userID, Type: models.OTPTypeLogin}
+	}
+
+	t.Run("Delivered message is recorded and audited", func(t *testing.T) {
+		svc, _, audited, updated := setupSMSDeliveryService(newLog(models.SMSStatusSent))
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM123", Status: sms.DeliveryStatusDelivered, ProviderStatus: "delivered"}, "203.0.113.7")
+		require.NoError(t, err)
+		assert.Equal(t, []models.SMSStatus{models.SMSStatusDelivered}, *updated)
+		require.Len(t, *audited, 1)
+		assert.Equal(t, models.ActionSMSDeliveryStatus, (*audited)[0].Action)
+		assert.Equal(t, models.StatusSuccess, (*audited)[0].Status)
+		assert.Equal(t, &userID, (*audited)[0].UserID)
+		assert.Equal(t, "SM123", (*audited)[0].Details["message_id"])
+	})
+
+	t.Run("Failed message keeps the error code", func(t *testing.T) {
+		svc, store, audited, _ := setupSMSDeliveryService(newLog(models.SMSStatusSent))
+		var errMsg string
+		store.UpdateStatusFunc = func(ctx context.Context, id uuid.UUID, status models.SMSStatus, errorMsg *string) error {
+			errMsg = *errorMsg
+			return nil
+		}
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM123", Status: sms.DeliveryStatusFailed, ProviderStatus: "undelivered", ErrorCode: "30003"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "delivery failed: undelivered (error code 30003)", errMsg)
+		require.Len(t, *audited, 1)
+		assert.Equal(t, models.StatusFailed, (*audited)[0].Status)
+		assert.Equal(t, "30003", (*audited)[0].Details["error_code"])
+	})
+
+	t.Run("Intermediate states are ignored", func(t *testing.T) {
+		svc, _, audited, updated := setupSMSDeliveryService(newLog(models.SMSStatusSent))
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM123", Status: sms.DeliveryStatusPending, ProviderStatus: "queued"}, "")
+		require.NoError(t, err)
+		assert.Empty(t, *updated)
+		assert.Empty(t, *audited)
+	})
+
+	t.Run("Late reports do not overwrite a final status", func(t *testing.T) {
+		svc, _, audited, updated := setupSMSDeliveryService(newLog(models.SMSStatusDelivered))
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM123", Status: sms.DeliveryStatusFailed, ProviderStatus: "failed"}, "")
+		require.NoError(t, err)
+		assert.Empty(t, *updated)
+		assert.Empty(t, *audited)
+	})
+
+	t.Run("Unknown messages are ignored", func(t *testing.T) {
+		svc, _, audited, _ := setupSMSDeliveryService(nil)
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM999", Status: sms.DeliveryStatusDelivered}, "")
+		require.NoError(t, err)
+		assert.Empty(t, *audited)
+	})
+
+	t.Run("Storage errors are returned", func(t *testing.T) {
+		svc, store, _, _ := setupSMSDeliveryService(nil)
+		store.GetByMessageIDFunc = func(ctx context.Context, provider, messageID string) (*models.SMSLog, error) {
+			return nil, errors.New("connection refused")
+		}
+
+		err := svc.RecordDeliveryReport(ctx, "twilio", &sms.DeliveryReport{MessageID: "SM123", Status: sms.DeliveryStatusDelivered}, "")
+		assert.Error(t, err)
+	})
+}
@@ -155,7 +155,7 @@ func (s *SMSService) SendOTP(ctx context.Context, req *models.SendSMSRequest, ip
 	}
 
 	// Update log with success
-	_ = s.smsLogRepo.UpdateStatus(ctx, smsLog.ID, models.SMSStatusSent, nil)
+	_ = s.smsLogRepo.MarkSent(ctx, smsLog.ID, messageID)
 
 	return &models.SendSMSResponse{
 		Success:   true,
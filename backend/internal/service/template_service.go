@@ -326,6 +326,12 @@ func (s *TemplateService) GetAvailableTemplateTypes() []string {
 		models.EmailTemplateTypeLoginAlert,
 		models.EmailTemplateType2FAEnabled,
 		models.EmailTemplateType2FADisabled,
+		models.EmailTemplateTypePasswordExpiring,
+		models.EmailTemplateTypeAccountRecovery,
+		models.EmailTemplateTypeOrgUsageReport,
+		models.EmailTemplateTypeEmailVerificationReminder,
+		models.EmailTemplateTypeAccountDormant,
+		models.EmailTemplateTypeMagicLink,
 		models.EmailTemplateTypeCustom,
 	}
 }
@@ -514,6 +520,12 @@ func (s *TemplateService) InitializeTemplatesForApp(ctx context.Context, applica
 		models.EmailTemplateTypeLoginAlert,
 		models.EmailTemplateType2FAEnabled,
 		models.EmailTemplateType2FADisabled,
+		models.EmailTemplateTypePasswordExpiring,
+		models.EmailTemplateTypeAccountRecovery,
+		models.EmailTemplateTypeOrgUsageReport,
+		models.EmailTemplateTypeEmailVerificationReminder,
+		models.EmailTemplateTypeAccountDormant,
+		models.EmailTemplateTypeMagicLink,
 	}
 
 	for _, templateType := range templateTypes {
@@ -575,6 +587,18 @@ func (s *TemplateService) getDefaultTemplateName(templateType string) string {
 		return "2FA Enabled"
 	case models.EmailTemplateType2FADisabled:
 		return "2FA Disabled"
+	case models.EmailTemplateTypePasswordExpiring:
+		return "Password Expiring"
+	case models.EmailTemplateTypeAccountRecovery:
+		return "Account Recovery"
+	case models.EmailTemplateTypeOrgUsageReport:
+		return "Organization Usage Report"
+	case models.EmailTemplateTypeEmailVerificationReminder:
+		return "Email Verification Reminder"
+	case models.EmailTemplateTypeAccountDormant:
+		return "Account Dormant"
+	case models.EmailTemplateTypeMagicLink:
+		return "Magic Link"
 	default:
 		return "Custom Template"
 	}
@@ -622,6 +646,30 @@ func (s *TemplateService) getDefaultTemplateContent(templateType string) (subjec
 		subject = "Two-Factor Authentication Disabled"
 		htmlBody = `<html><body><h2>2FA Disabled</h2><p>Hello {{.username}},</p><p>Two-factor authentication has been disabled on your account.</p><p><strong>Time:</strong> {{.timestamp}}</p><p>Your account is now less secure. We recommend re-enabling 2FA as soon as possible.</p></body></html>`
 		textBody = `2FA Disabled\n\nHello {{.username}},\n\nTwo-factor authentication has been disabled on your account.\n\nTime: {{.timestamp}}\n\nWe recommend re-enabling 2FA as soon as possible.`
+	case models.EmailTemplateTypePasswordExpiring:
+		subject = "Your Password Will Expire Soon"
+		htmlBody = `<html><body><h2>Password Expiring</h2><p>Hello {{.username}},</p><p>Your password will expire in {{.days_left}} days, on {{.expires_at}}.</p><p>Please change it before then to keep access to your account.</p></body></html>`
+		textBody = `Password Expiring\n\nHello {{.username}},\n\nYour password will expire in {{.days_left}} days, on {{.expires_at}}.\n\nPlease change it before then to keep access to your account.`
+	case models.EmailTemplateTypeAccountRecovery:
+		subject = "Account Recovery Activity"
+		htmlBody = `<html><body><h2>Account Recovery</h2><p>Hello {{.username}},</p><p>Account recovery activity on your account: <strong>{{.event}}</strong></p><p><strong>IP Address:</strong> {{.ip_address}}</p><p><strong>Time:</strong> {{.timestamp}}</p><p>If this wasn't you, contact support immediately.</p></body></html>`
+		textBody = `Account Recovery\n\nHello {{.username}},\n\nAccount recovery activity on your account: {{.event}}\n\nIP Address: {{.ip_address}}\nTime: {{.timestamp}}\n\nIf this wasn't you, contact support immediately.`
+	case models.EmailTemplateTypeOrgUsageReport:
+		subject = "Usage Report for {{.organization}}"
+		htmlBody = `<html><body><h2>Usage Report</h2><p>Usage report for <strong>{{.organization}}</strong> generated on {{.generated_at}}.</p><ul><li><strong>Members:</strong> {{.total_members}}</li><li><strong>Seats used:</strong> {{.seats_used}} of {{.licensed_seats}}</li><li><strong>Active users (last {{.active_days}} days):</strong> {{.active_users}}</li><li><strong>MFA adoption:</strong> {{.mfa_adoption_rate}}%</li></ul></body></html>`
+		textBody = `Usage Report\n\nUsage report for {{.organization}} generated on {{.generated_at}}.\n\nMembers: {{.total_members}}\nSeats used: {{.seats_used}} of {{.licensed_seats}}\nActive users (last {{.active_days}} days): {{.active_users}}\nMFA adoption: {{.mfa_adoption_rate}}%`
+	case models.EmailTemplateTypeEmailVerificationReminder:
+		subject = "Please Verify Your Email Address"
+		htmlBody = `<html><body><h2>Verify Your Email</h2><p>Hello {{.username}},</p><p>Your email address <strong>{{.email}}</strong> is not verified yet. Request a new verification code to verify it.</p>{{if .deadline}}<p>Verify it before <strong>{{.deadline}}</strong> to keep access to your account.</p>{{end}}</body></html>`
+		textBody = `Verify Your Email\n\nHello {{.username}},\n\nYour email address {{.email}} is not verified yet. Request a new verification code to verify it.{{if .deadline}}\n\nVerify it before {{.deadline}} to keep access to your account.{{end}}`
+	case models.EmailTemplateTypeAccountDormant:
+		subject = "Your Account Is Inactive"
+		htmlBody = `<html><body><h2>Account Inactive</h2><p>Hello {{.username}},</p><p>Your account has not been used since {{.last_active_at}} ({{.inactive_days}} days or more).</p><p>It will be <strong>{{.action}}</strong> on <strong>{{.enforce_at}}</strong>. Sign in before then to keep it.</p></body></html>`
+		textBody = `Account Inactive\n\nHello {{.username}},\n\nYour account has not been used since {{.last_active_at}} ({{.inactive_days}} days or more).\n\nIt will be {{.action}} on {{.enforce_at}}. Sign in before then to keep it.`
+	case models.EmailTemplateTypeMagicLink:
+		subject = "Your Sign-In Link"
+		htmlBody = `<html><body><h2>Sign In</h2><p>Hello {{.username}},</p><p><a href="{{.link}}">Click here to sign in</a>.</p><p>The link expires in {{.expiry_minutes}} minutes and works once. If you did not request it, you can ignore this email.</p></body></html>`
+		textBody = `Sign In\n\nHello {{.username}},\n\nOpen this link to sign in: {{.link}}\n\nThe link expires in {{.expiry_minutes}} minutes and works once. If you did not request it, you can ignore this email.`
 	default:
 		subject = "Notification"
 		htmlBody = `<html><body><p>Default template content</p></body></html>`
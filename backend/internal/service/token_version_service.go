@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/jwt"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+const (
+	tokenVersionKeyPrefix = "token_version:"
+	tokenEpochKey         = "token_epoch"
+	tokenVersionCacheTTL  = 15 * time.Minute
+)
+
+// TokenVersionService invalidates issued JWTs without tracking them individually.
+// Each user has a token_version embedded in their tokens; bumping it revokes every
+// token issued before. A global epoch does the same for all users at once.
+// Redis caches both values; PostgreSQL is the source of truth.
+type TokenVersionService struct {
+	store    TokenVersionStore
+	settings SettingStore
+	cache    KeyValueCache
+	logger   *logger.Logger
+
+	revocations *RevocationHub
+}
+
+// NewTokenVersionService creates a new token version service
+func NewTokenVersionService(store TokenVersionStore, settings SettingStore, cache KeyValueCache, logger *logger.Logger) *TokenVersionService {
+	return &TokenVersionService{
+		store:    store,
+		settings: settings,
+		cache:    cache,
+		logger:   logger,
+	}
+}
+
+// SetRevocationHub announces bumped token versions and epoch changes through hub
+func (s *TokenVersionService) SetRevocationHub(hub *RevocationHub) {
+	s.revocations = hub
+}
+
+// IsRevoked reports whether the token was issued before the user's current token version
+// or in/before the second of the global epoch. Storage errors are logged and the token is accepted,
+// matching the blacklist behaviour.
+func (s *TokenVersionService) IsRevoked(ctx context.Context, claims *jwt.Claims) bool {
+	epoch, err := s.Epoch(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load token epoch", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if !epoch.IsZero() && issuedByEpoch(claims, epoch) {
+		return true
+	}
+
+	version, err := s.CurrentVersion(ctx, claims.UserID)
+	if err != nil {
+		s.logger.Warn("Failed to load token version", map[string]interface{}{
+			"user_id": claims.UserID.String(),
+			"error":   err.Error(),
+		})
+		return false
+	}
+
+	return claims.TokenVersion < version
+}
+
+// CurrentVersion returns the user's current token version
+func (s *TokenVersionService) CurrentVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	key := tokenVersionKeyPrefix + userID.String()
+	if cached, err := s.cache.Get(ctx, key); err == nil {
+		if version, err := strconv.Atoi(cached); err == nil {
+			return version, nil
+		}
+	}
+
+	version, err := s.store.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.cache.Set(ctx, key, version, tokenVersionCacheTTL); err != nil {
+		s.logger.Warn("Failed to cache token version", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+
+	return version, nil
+}
+
+// Bump increments the user's token version, revoking all of their outstanding tokens
+func (s *TokenVersionService) Bump(ctx context.Context, userID uuid.UUID) (int, error) {
+	version, err := s.store.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	key := tokenVersionKeyPrefix + userID.String()
+	if err := s.cache.Set(ctx, key, version, tokenVersionCacheTTL); err != nil {
+		// A stale cached version would keep old tokens alive until it expires
+		if delErr := s.cache.Delete(ctx, key); delErr != nil {
+			s.logger.Error("Failed to refresh cached token version", map[string]interface{}{
+				"user_id": userID.String(),
+				"error":   delErr.Error(),
+			})
+		}
+	}
+
+	s.logger.Info("Token version bumped", map[string]interface{}{
+		"user_id":       userID.String(),
+		"token_version": version,
+	})
+	s.revocations.Publish(ctx, models.RevocationEvent{
+		Type:      models.RevocationTypeUser,
+		UserID:    &userID,
+		RevokedAt: time.Now(),
+	})
+
+	return version, nil
+}
+
+// Epoch returns the global invalidation epoch, or the zero time if it was never set
+func (s *TokenVersionService) Epoch(ctx context.Context) (time.Time, error) {
+	if cached, err := s.cache.Get(ctx, tokenEpochKey); err == nil {
+		if unix, err := strconv.ParseInt(cached, 10, 64); err == nil {
+			return epochTime(unix), nil
+		}
+	}
+
+	setting, err := s.settings.GetSetting(ctx, models.SettingTokenEpoch)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	unix, err := strconv.ParseInt(setting.Value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s setting: %w", models.SettingTokenEpoch, err)
+	}
+
+	if err := s.cache.Set(ctx, tokenEpochKey, unix, 0); err != nil {
+		s.logger.Warn("Failed to cache token epoch", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return epochTime(unix), nil
+}
+
+// InvalidateAll moves the global epoch to now, revoking every token issued so far
+// without rotating signing keys. Users have to sign in again.
+func (s *TokenVersionService) InvalidateAll(ctx context.Context, updatedBy *uuid.UUID) (time.Time, error) {
+	unix := time.Now().Unix()
+
+	if err := s.settings.UpdateSetting(ctx, models.SettingTokenEpoch, strconv.FormatInt(unix, 10), updatedBy); err != nil {
+		return time.Time{}, fmt.Errorf("failed to update token epoch: %w", err)
+	}
+
+	if err := s.cache.Set(ctx, tokenEpochKey, unix, 0); err != nil {
+		// Every instance reads the epoch through Redis, so a stale value there defeats the switch
+		return time.Time{}, fmt.Errorf("failed to publish token epoch: %w", err)
+	}
+
+	s.logger.Warn("All outstanding tokens invalidated", map[string]interface{}{
+		"epoch":      unix,
+		"updated_by": updatedBy,
+	})
+	s.revocations.Publish(ctx, models.RevocationEvent{
+		Type:      models.RevocationTypeAll,
+		RevokedAt: epochTime(unix),
+	})
+
+	return epochTime(unix), nil
+}
+
+// issuedByEpoch reports whether the token was issued in or before the epoch's second.
+// Both sides are compared in whole seconds: iat carries no more precision, so a token
+// issued in the same second as the bump cannot be told apart from one issued just
+// before it and is always rejected.
+func issuedByEpoch(claims *jwt.Claims, epoch time.Time) bool {
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Unix() <= epoch.Unix()
+}
+
+func epochTime(unix int64) time.Time {
+	if unix <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/jwt"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTokenVersionStore struct {
+	versions map[uuid.UUID]int
+	gets     int
+}
+
+func (m *mockTokenVersionStore) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.gets++
+	version, ok := m.versions[userID]
+	if !ok {
+		return 0, models.ErrUserNotFound
+	}
+	return version, nil
+}
+
+func (m *mockTokenVersionStore) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	if _, ok := m.versions[userID]; !ok {
+		return 0, models.ErrUserNotFound
+	}
+	m.versions[userID]++
+	return m.versions[userID], nil
+}
+
+type mockSettingStore struct {
+	values map[string]string
+}
+
+func (m *mockSettingStore) GetSetting(ctx context.Context, key string) (*models.SystemSetting, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return nil, errors.New("setting not found: " + key)
+	}
+	return &models.SystemSetting{Key: key, Value: value}, nil
+}
+
+func (m *mockSettingStore) UpdateSetting(ctx context.Context, key, value string, updatedBy *uuid.UUID) error {
+	if _, ok := m.values[key]; !ok {
+		return errors.New("setting not found: " + key)
+	}
+	m.values[key] = value
+	return nil
+}
+
+type mockKeyValueCache struct {
+	values map[string]string
+}
+
+func (m *mockKeyValueCache) Get(ctx context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, nil
+}
+
+func (m *mockKeyValueCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	switch v := value.(type) {
+	case int:
+		m.values[key] = strconv.Itoa(v)
+	case int64:
+		m.values[key] = strconv.FormatInt(v, 10)
+	case []byte:
+		m.values[key] = string(v)
+	default:
+		m.values[key] = v.(string)
+	}
+	return nil
+}
+
+func (m *mockKeyValueCache) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func setupTokenVersionService(userID uuid.UUID) (*TokenVersionService, *mockTokenVersionStore, *mockKeyValueCache) {
+	store := &mockTokenVersionStore{versions: map[uuid.UUID]int{userID: 0}}
+	settings := &mockSettingStore{values: map[string]string{models.SettingTokenEpoch: "0"}}
+	cache := &mockKeyValueCache{values: map[string]string{}}
+	svc := NewTokenVersionService(store, settings, cache, logger.New("test", logger.DebugLevel, false))
+	return svc, store, cache
+}
+
+func claimsAt(userID uuid.UUID, version int, issuedAt time.Time) *jwt.Claims {
+	return &jwt.Claims{
+		UserID:       userID,
+		TokenVersion: version,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			IssuedAt: gojwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func TestTokenVersionService_IsRevoked(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CurrentVersionAccepted", func(t *testing.T) {
+		userID := uuid.New()
+		svc, _, _ := setupTokenVersionService(userID)
+
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(userID, 0, time.Now())))
+	})
+
+	t.Run("StaleVersionRejectedAfterBump", func(t *testing.T) {
+		userID := uuid.New()
+		svc, _, _ := setupTokenVersionService(userID)
+
+		version, err := svc.Bump(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+
+		assert.True(t, svc.IsRevoked(ctx, claimsAt(userID, 0, time.Now())))
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(userID, 1, time.Now())))
+	})
+
+	t.Run("VersionIsCached", func(t *testing.T) {
+		userID := uuid.New()
+		svc, store, _ := setupTokenVersionService(userID)
+
+		svc.IsRevoked(ctx, claimsAt(userID, 0, time.Now()))
+		svc.IsRevoked(ctx, claimsAt(userID, 0, time.Now()))
+		assert.Equal(t, 1, store.gets)
+	})
+
+	t.Run("TokensIssuedBeforeEpochRejected", func(t *testing.T) {
+		userID := uuid.New()
+		svc, _, _ := setupTokenVersionService(userID)
+
+		issued := time.Now().Add(-time.Minute)
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(userID, 0, issued)))
+
+		epoch, err := svc.InvalidateAll(ctx, nil)
+		require.NoError(t, err)
+		assert.False(t, epoch.IsZero())
+
+		assert.True(t, svc.IsRevoked(ctx, claimsAt(userID, 0, issued)))
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(userID, 0, epoch.Add(time.Second))))
+	})
+
+	t.Run("TokensIssuedInEpochSecondRejected", func(t *testing.T) {
+		userID := uuid.New()
+		svc, _, _ := setupTokenVersionService(userID)
+
+		epoch, err := svc.InvalidateAll(ctx, nil)
+		require.NoError(t, err)
+
+		// Sub-second iat values (from issuers with finer time precision) must not slip past a truncated epoch
+		sameSecond := &jwt.Claims{UserID: userID}
+		sameSecond.IssuedAt = &gojwt.NumericDate{Time: epoch.Add(999 * time.Millisecond)}
+		assert.True(t, svc.IsRevoked(ctx, sameSecond))
+		assert.True(t, svc.IsRevoked(ctx, claimsAt(userID, 0, epoch)))
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(userID, 0, epoch.Add(time.Second))))
+	})
+
+	t.Run("StoreErrorAccepted", func(t *testing.T) {
+		svc, _, _ := setupTokenVersionService(uuid.New())
+
+		assert.False(t, svc.IsRevoked(ctx, claimsAt(uuid.New(), 0, time.Now())))
+	})
+}
+
+func TestTokenVersionService_Epoch(t *testing.T) {
+	ctx := context.Background()
+	svc, _, cache := setupTokenVersionService(uuid.New())
+
+	epoch, err := svc.Epoch(ctx)
+	require.NoError(t, err)
+	assert.True(t, epoch.IsZero())
+
+	set, err := svc.InvalidateAll(ctx, nil)
+	require.NoError(t, err)
+
+	// A fresh cache must fall back to the persisted setting
+	cache.values = map[string]string{}
+	epoch, err = svc.Epoch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, set.Unix(), epoch.Unix())
+}
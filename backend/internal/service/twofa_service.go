@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
@@ -24,6 +25,11 @@ type TwoFactorService struct {
 	userRepo       UserStore
 	backupCodeRepo BackupCodeStore
 	issuer         string
+
+	// Security keys, set by SetWebAuthn; nil when WebAuthn is not configured
+	webauthn    *webauthn.WebAuthn
+	credentials WebAuthnCredentialStore
+	sessions    KeyValueCache
 }
 
 // NewTwoFactorService creates a new 2FA service
@@ -63,35 +69,17 @@ func (s *TwoFactorService) SetupTOTP(ctx context.Context, userID uuid.UUID, pass
 		return nil, fmt.Errorf("failed to generate TOTP key: %w", err)
 	}
 
-	// Generate backup codes
-	backupCodes, err := s.generateBackupCodes(userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate backup codes: %w", err)
-	}
-
 	// Store TOTP secret (but don't enable yet - requires verification)
 	if err := s.userRepo.UpdateTOTPSecret(ctx, userID, key.Secret()); err != nil {
 		return nil, err
 	}
 
-	// Get plain text backup codes before they're hashed
-	plainBackupCodes := make([]string, len(backupCodes))
-	for i, code := range backupCodes {
-		plainBackupCodes[i] = code.CodeHash // Temporarily stored as plain text
-	}
-
-	// Hash backup codes before storing
-	for i := range backupCodes {
-		hash, err := utils.HashPassword(backupCodes[i].CodeHash, 10)
-		if err != nil {
-			return nil, fmt.Errorf("failed to hash backup code: %w", err)
+	// Users who already have a security key keep their backup codes
+	var plainBackupCodes []string
+	if !user.WebAuthnEnabled {
+		if plainBackupCodes, err = s.issueBackupCodes(ctx, userID); err != nil {
+			return nil, err
 		}
-		backupCodes[i].CodeHash = hash
-	}
-
-	// Store backup codes
-	if err := s.backupCodeRepo.CreateBatch(ctx, backupCodes); err != nil {
-		return nil, err
 	}
 
 	return &models.TwoFactorSetupResponse{
@@ -204,6 +192,11 @@ func (s *TwoFactorService) DisableTOTP(ctx context.Context, userID uuid.UUID, pa
 		return err
 	}
 
+	// Backup codes stay while security keys remain
+	if user.WebAuthnEnabled {
+		return nil
+	}
+
 	// Delete all backup codes
 	if err := s.backupCodeRepo.DeleteAllByUserID(ctx, userID); err != nil {
 		return err
@@ -226,10 +219,22 @@ func (s *TwoFactorService) GetStatus(ctx context.Context, userID uuid.UUID) (*mo
 		return nil, err
 	}
 
+	webauthnCredentials := 0
+	if s.credentials != nil && user.WebAuthnEnabled {
+		credentials, err := s.credentials.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		webauthnCredentials = len(credentials)
+	}
+
 	return &models.TwoFactorStatusResponse{
-		Enabled:     user.TOTPEnabled,
-		EnabledAt:   user.TOTPEnabledAt,
-		BackupCodes: backupCodeCount,
+		Enabled:             user.TwoFactorEnabled(),
+		EnabledAt:           user.TOTPEnabledAt,
+		BackupCodes:         backupCodeCount,
+		TOTPEnabled:         user.TOTPEnabled,
+		WebAuthnCredentials: webauthnCredentials,
+		Methods:             s.Methods(user),
 	}, nil
 }
 
@@ -247,7 +252,7 @@ func (s *TwoFactorService) RegenerateBackupCodes(ctx context.Context, userID uui
 	}
 
 	// Verify 2FA is enabled
-	if !user.TOTPEnabled {
+	if !user.TwoFactorEnabled() {
 		return nil, models.NewAppError(400, "2FA not enabled")
 	}
 
@@ -256,19 +261,23 @@ func (s *TwoFactorService) RegenerateBackupCodes(ctx context.Context, userID uui
 		return nil, err
 	}
 
-	// Generate new backup codes
+	return s.issueBackupCodes(ctx, userID)
+}
+
+// issueBackupCodes stores a fresh set of hashed backup codes and returns them in plain text
+func (s *TwoFactorService) issueBackupCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
 	backupCodes, err := s.generateBackupCodes(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate backup codes: %w", err)
 	}
 
-	// Get plain text backup codes
+	// Get plain text backup codes before they're hashed
 	plainBackupCodes := make([]string, len(backupCodes))
 	for i, code := range backupCodes {
 		plainBackupCodes[i] = code.CodeHash
 	}
 
-	// Hash backup codes
+	// Hash backup codes before storing
 	for i := range backupCodes {
 		hash, err := utils.HashPassword(backupCodes[i].CodeHash, 10)
 		if err != nil {
@@ -277,7 +286,6 @@ func (s *TwoFactorService) RegenerateBackupCodes(ctx context.Context, userID uui
 		backupCodes[i].CodeHash = hash
 	}
 
-	// Store backup codes
 	if err := s.backupCodeRepo.CreateBatch(ctx, backupCodes); err != nil {
 		return nil, err
 	}
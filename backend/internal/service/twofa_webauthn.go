@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+const (
+	webauthnRegistrationKeyPrefix = "webauthn:registration:"
+	webauthnLoginKeyPrefix        = "webauthn:login:"
+	// webauthnCeremonyTTL bounds the time between the begin and finish calls of a ceremony
+	webauthnCeremonyTTL = 5 * time.Minute
+)
+
+var (
+	errWebAuthnNotConfigured = models.NewAppError(http.StatusBadRequest, "Security keys are not enabled")
+	errWebAuthnNoCeremony    = models.NewAppError(http.StatusBadRequest, "Security key challenge not found or expired, start again")
+	errWebAuthnVerification  = models.NewAppError(http.StatusUnauthorized, "Security key verification failed")
+	errWebAuthnNotFound      = models.NewAppError(http.StatusNotFound, "Security key not found")
+)
+
+// SetWebAuthn enables FIDO2 security keys as a second factor. Challenges live in sessions
+// between the begin and finish calls of a ceremony.
+func (s *TwoFactorService) SetWebAuthn(relyingParty *webauthn.WebAuthn, credentials WebAuthnCredentialStore, sessions KeyValueCache) {
+	s.webauthn = relyingParty
+	s.credentials = credentials
+	s.sessions = sessions
+}
+
+// Methods returns the second factors the user can sign in with, preferred first:
+// security keys, then TOTP, then backup codes
+func (s *TwoFactorService) Methods(user *models.User) []string {
+	methods := make([]string, 0, 3)
+	if user.WebAuthnEnabled && s.webauthn != nil {
+		methods = append(methods, models.TwoFactorMethodWebAuthn)
+	}
+	if user.TOTPEnabled {
+		methods = append(methods, models.TwoFactorMethodTOTP)
+	}
+	if user.TwoFactorEnabled() {
+		methods = append(methods, models.TwoFactorMethodBackupCode)
+	}
+	return methods
+}
+
+// VerifyCode checks a sign-in code against the user's TOTP secret, then against their
+// unused backup codes. It returns the method the code matched.
+func (s *TwoFactorService) VerifyCode(ctx context.Context, user *models.User, code string) (string, bool, error) {
+	if user.TOTPEnabled && user.TOTPSecret != nil && totp.Validate(code, *user.TOTPSecret) {
+		return models.TwoFactorMethodTOTP, true, nil
+	}
+
+	valid, err := s.verifyBackupCode(ctx, user.ID, code)
+	if err != nil || !valid {
+		return "", false, err
+	}
+	return models.TwoFactorMethodBackupCode, true, nil
+}
+
+// BeginWebAuthnRegistration starts registering a security key and returns the options
+// for navigator.credentials.create()
+func (s *TwoFactorService) BeginWebAuthnRegistration(ctx context.Context, userID uuid.UUID, password string) (*protocol.CredentialCreation, error) {
+	if s.webauthn == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, utils.Ptr(true))
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckPassword(user.PasswordHash, password); err != nil {
+		return nil, models.NewAppError(401, "Invalid password")
+	}
+
+	account, err := s.webauthnAccount(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := s.webauthn.BeginRegistration(account,
+		webauthn.WithExclusions(webauthn.Credentials(account.WebAuthnCredentials()).CredentialDescriptors()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin webauthn registration: %w", err)
+	}
+	if err := s.saveCeremony(ctx, webauthnRegistrationKeyPrefix+userID.String(), session); err != nil {
+		return nil, err
+	}
+
+	return creation, nil
+}
+
+// FinishWebAuthnRegistration verifies the authenticator's response and stores the key.
+// The first second factor of an account also gets backup codes.
+func (s *TwoFactorService) FinishWebAuthnRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*models.WebAuthnRegisterFinishResponse, error) {
+	if s.webauthn == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+
+	session, err := s.takeCeremony(ctx, webauthnRegistrationKeyPrefix+userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, utils.Ptr(true))
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.webauthnAccount(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, models.NewAppError(http.StatusBadRequest, "Invalid security key response", webauthnErrorDetails(err))
+	}
+	credential, err := s.webauthn.CreateCredential(account, *session, parsed)
+	if err != nil {
+		return nil, models.NewAppError(http.StatusBadRequest, "Security key registration failed", webauthnErrorDetails(err))
+	}
+
+	transports := make([]string, len(credential.Transport))
+	for i, transport := range credential.Transport {
+		transports[i] = string(transport)
+	}
+	record := &models.WebAuthnCredential{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := s.credentials.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	result := &models.WebAuthnRegisterFinishResponse{Credential: record}
+	if !user.TwoFactorEnabled() {
+		// Codes left over from a TOTP setup that was never verified are replaced
+		if err := s.backupCodeRepo.DeleteAllByUserID(ctx, userID); err != nil {
+			return nil, err
+		}
+		if result.BackupCodes, err = s.issueBackupCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ListWebAuthnCredentials returns the security keys of a user
+func (s *TwoFactorService) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	if s.credentials == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+	return s.credentials.ListByUserID(ctx, userID)
+}
+
+// DeleteWebAuthnCredential removes a security key. Removing the last second factor also
+// removes the backup codes.
+func (s *TwoFactorService) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID uuid.UUID, password string) error {
+	if s.credentials == nil {
+		return errWebAuthnNotConfigured
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, utils.Ptr(true))
+	if err != nil {
+		return err
+	}
+	if err := utils.CheckPassword(user.PasswordHash, password); err != nil {
+		return models.NewAppError(401, "Invalid password")
+	}
+
+	if err := s.credentials.Delete(ctx, userID, credentialID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return errWebAuthnNotFound
+		}
+		return err
+	}
+
+	if user.TOTPEnabled {
+		return nil
+	}
+	remaining, err := s.credentials.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(remaining) == 0 {
+		return s.backupCodeRepo.DeleteAllByUserID(ctx, userID)
+	}
+	return nil
+}
+
+// BeginWebAuthnLogin starts the security key step of a sign-in and returns the options
+// for navigator.credentials.get()
+func (s *TwoFactorService) BeginWebAuthnLogin(ctx context.Context, user *models.User) (*protocol.CredentialAssertion, error) {
+	if s.webauthn == nil {
+		return nil, errWebAuthnNotConfigured
+	}
+
+	account, err := s.webauthnAccount(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(account.credentials) == 0 {
+		return nil, models.NewAppError(http.StatusBadRequest, "No security keys registered")
+	}
+
+	assertion, session, err := s.webauthn.BeginLogin(account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin webauthn login: %w", err)
+	}
+	if err := s.saveCeremony(ctx, webauthnLoginKeyPrefix+user.ID.String(), session); err != nil {
+		return nil, err
+	}
+
+	return assertion, nil
+}
+
+// FinishWebAuthnLogin verifies the authenticator's assertion for the security key step of a sign-in
+func (s *TwoFactorService) FinishWebAuthnLogin(ctx context.Context, user *models.User, response []byte) error {
+	if s.webauthn == nil {
+		return errWebAuthnNotConfigured
+	}
+
+	session, err := s.takeCeremony(ctx, webauthnLoginKeyPrefix+user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return models.NewAppError(http.StatusBadRequest, "Invalid security key response", webauthnErrorDetails(err))
+	}
+
+	account, err := s.webauthnAccount(ctx, user)
+	if err != nil {
+		return err
+	}
+	credential, err := s.webauthn.ValidateLogin(account, *session, parsed)
+	if err != nil {
+		return errWebAuthnVerification
+	}
+	// A signature counter that went backwards means two authenticators hold the same key
+	if credential.Authenticator.CloneWarning {
+		return errWebAuthnVerification
+	}
+
+	for _, record := range account.credentials {
+		if bytes.Equal(record.CredentialID, credential.ID) {
+			return s.credentials.RecordUse(ctx, record.ID, credential.Authenticator.SignCount, credential.Flags.BackupState, time.Now())
+		}
+	}
+	return nil
+}
+
+// webauthnAccount loads the user's security keys
+func (s *TwoFactorService) webauthnAccount(ctx context.Context, user *models.User) (*webauthnUser, error) {
+	credentials, err := s.credentials.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthnUser{user: user, credentials: credentials}, nil
+}
+
+// saveCeremony stores the challenge of a ceremony until its finish call
+func (s *TwoFactorService) saveCeremony(ctx context.Context, key string, session *webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode webauthn session: %w", err)
+	}
+	if err := s.sessions.Set(ctx, key, string(data), webauthnCeremonyTTL); err != nil {
+		return fmt.Errorf("failed to store webauthn session: %w", err)
+	}
+	return nil
+}
+
+// takeCeremony returns the challenge of a ceremony and forgets it, so each challenge is answered once
+func (s *TwoFactorService) takeCeremony(ctx context.Context, key string) (*webauthn.SessionData, error) {
+	data, err := s.sessions.Get(ctx, key)
+	if err != nil || data == "" {
+		return nil, errWebAuthnNoCeremony
+	}
+	_ = s.sessions.Delete(ctx, key)
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, errWebAuthnNoCeremony
+	}
+	return &session, nil
+}
+
+// webauthnErrorDetails returns the developer-facing detail of a WebAuthn protocol error
+func webauthnErrorDetails(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.Details != "" {
+		return protocolErr.Details
+	}
+	return err.Error()
+}
+
+// webauthnUser presents a user and their security keys to the WebAuthn library
+type webauthnUser struct {
+	user        *models.User
+	credentials []*models.WebAuthnCredential
+}
+
+// WebAuthnID is the user handle: the user ID, which reveals nothing about the account
+func (u *webauthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	if u.user.Email != "" {
+		return u.user.Email
+	}
+	return u.user.Username
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if u.user.FullName != "" {
+		return u.user.FullName
+	}
+	return u.WebAuthnName()
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.credentials))
+	for i, record := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, len(record.Transports))
+		for j, transport := range record.Transports {
+			transports[j] = protocol.AuthenticatorTransport(transport)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              record.CredentialID,
+			PublicKey:       record.PublicKey,
+			AttestationType: record.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: record.BackupEligible,
+				BackupState:    record.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    record.AAGUID,
+				SignCount: record.SignCount,
+			},
+		}
+	}
+	return credentials
+}
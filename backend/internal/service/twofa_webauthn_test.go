@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebAuthnCredentialStore struct {
+	credentials []*models.WebAuthnCredential
+	deleted     []uuid.UUID
+}
+
+func (m *mockWebAuthnCredentialStore) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	m.credentials = append(m.credentials, credential)
+	return nil
+}
+
+func (m *mockWebAuthnCredentialStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.WebAuthnCredential, error) {
+	result := make([]*models.WebAuthnCredential, 0)
+	for _, credential := range m.credentials {
+		if credential.UserID == userID {
+			result = append(result, credential)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebAuthnCredentialStore) RecordUse(ctx context.Context, id uuid.UUID, signCount uint32, backupState bool, usedAt time.Time) error {
+	return nil
+}
+
+func (m *mockWebAuthnCredentialStore) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, credential := range m.credentials {
+		if credential.ID == id && credential.UserID == userID {
+			m.credentials = append(m.credentials[:i], m.credentials[i+1:]...)
+			m.deleted = append(m.deleted, id)
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func (m *mockWebAuthnCredentialStore) DeleteAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.credentials = nil
+	return nil
+}
+
+func setupWebAuthnTwoFactorService(t *testing.T) (*TwoFactorService, *mockUserStore, *mockBackupCodeStore, *mockWebAuthnCredentialStore, *mockKeyValueCache) {
+	t.Helper()
+	relyingParty, err := webauthn.New(&webauthn.Config{
+		RPID:          "auth.example.com",
+		RPDisplayName: "Auth Gateway",
+		RPOrigins:     []string{"https://auth.example.com"},
+	})
+	require.NoError(t, err)
+
+	mUser := &mockUserStore{}
+	mBackup := &mockBackupCodeStore{}
+	credentials := &mockWebAuthnCredentialStore{}
+	cache := &mockKeyValueCache{values: map[string]string{}}
+	svc := NewTwoFactorService(mUser, mBackup, "TestApp")
+	svc.SetWebAuthn(relyingParty, credentials, cache)
+	return svc, mUser, mBackup, credentials, cache
+}
+
+func TestTwoFactorService_Methods(t *testing.T) {
+	svc, _, _, _, _ := setupWebAuthnTwoFactorService(t)
+
+	t.Run("Security keys come before TOTP and backup codes", func(t *testing.T) {
+		user := &models.User{TOTPEnabled: true, WebAuthnEnabled: true}
+		assert.Equal(t, []string{models.TwoFactorMethodWebAuthn, models.TwoFactorMethodTOTP, models.TwoFactorMethodBackupCode}, svc.Methods(user))
+	})
+
+	t.Run("Security keys only", func(t *testing.T) {
+		user := &models.User{WebAuthnEnabled: true}
+		assert.Equal(t, []string{models.TwoFactorMethodWebAuthn, models.TwoFactorMethodBackupCode}, svc.Methods(user))
+	})
+
+	t.Run("No second factor", func(t *testing.T) {
+		assert.Empty(t, svc.Methods(&models.User{}))
+	})
+
+	t.Run("Security keys are not offered when WebAuthn is not configured", func(t *testing.T) {
+		plain := NewTwoFactorService(&mockUserStore{}, &mockBackupCodeStore{}, "TestApp")
+		user := &models.User{WebAuthnEnabled: true}
+		assert.Equal(t, []string{models.TwoFactorMethodBackupCode}, plain.Methods(user))
+	})
+}
+
+func TestTwoFactorService_VerifyCode_AcceptsBackupCodeWithoutTOTP(t *testing.T) {
+	svc, _, mBackup, _, _ := setupWebAuthnTwoFactorService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	hash, _ := utils.HashPassword("ABCD1234", 10)
+	codeID := uuid.New()
+
+	mBackup.GetUnusedByUserIDFunc = func(ctx context.Context, id uuid.UUID) ([]*models.BackupCode, error) {
+		return []*models.BackupCode{{ID: codeID, UserID: userID, CodeHash: hash}}, nil
+	}
+	var used uuid.UUID
+	mBackup.MarkAsUsedFunc = func(ctx context.Context, id uuid.UUID) error {
+		used = id
+		return nil
+	}
+
+	method, ok, err := svc.VerifyCode(ctx, &models.User{ID: userID, WebAuthnEnabled: true}, "ABCD1234")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, models.TwoFactorMethodBackupCode, method)
+	assert.Equal(t, codeID, used)
+
+	_, ok, err = svc.VerifyCode(ctx, &models.User{ID: userID, WebAuthnEnabled: true}, "WRONG123")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTwoFactorService_BeginWebAuthnRegistration(t *testing.T) {
+	svc, mUser, _, credentials, cache := setupWebAuthnTwoFactorService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	hashedPassword, _ := utils.HashPassword("password123", 10)
+
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return &models.User{ID: userID, Email: "keys@example.com", PasswordHash: hashedPassword}, nil
+	}
+	credentials.credentials = []*models.WebAuthnCredential{
+		{ID: uuid.New(), UserID: userID, CredentialID: []byte("existing-key")},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		options, err := svc.BeginWebAuthnRegistration(ctx, userID, "password123")
+		require.NoError(t, err)
+		assert.Equal(t, "auth.example.com", options.Response.RelyingParty.ID)
+		assert.Equal(t, "keys@example.com", options.Response.User.Name)
+		require.Len(t, options.Response.CredentialExcludeList, 1)
+		assert.Equal(t, []byte("existing-key"), []byte(options.Response.CredentialExcludeList[0].CredentialID))
+		assert.Contains(t, cache.values, webauthnRegistrationKeyPrefix+userID.String())
+	})
+
+	t.Run("Invalid password", func(t *testing.T) {
+		_, err := svc.BeginWebAuthnRegistration(ctx, userID, "wrong")
+		appErr, ok := err.(*models.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 401, appErr.Code)
+	})
+}
+
+func TestTwoFactorService_FinishWebAuthnRegistration_RequiresCeremony(t *testing.T) {
+	svc, _, _, _, _ := setupWebAuthnTwoFactorService(t)
+
+	_, err := svc.FinishWebAuthnRegistration(context.Background(), uuid.New(), "YubiKey", []byte(`{}`))
+	assert.Equal(t, errWebAuthnNoCeremony, err)
+}
+
+func TestTwoFactorService_DeleteWebAuthnCredential(t *testing.T) {
+	ctx := context.Background()
+	hashedPassword, _ := utils.HashPassword("password123", 10)
+
+	t.Run("Removing the last key removes backup codes", func(t *testing.T) {
+		svc, mUser, mBackup, credentials, _ := setupWebAuthnTwoFactorService(t)
+		userID := uuid.New()
+		keyID := uuid.New()
+		credentials.credentials = []*models.WebAuthnCredential{{ID: keyID, UserID: userID}}
+		mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			return &models.User{ID: userID, PasswordHash: hashedPassword, WebAuthnEnabled: true}, nil
+		}
+		codesDeleted := false
+		mBackup.DeleteAllByUserIDFunc = func(ctx context.Context, id uuid.UUID) error {
+			codesDeleted = true
+			return nil
+		}
+
+		err := svc.DeleteWebAuthnCredential(ctx, userID, keyID, "password123")
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{keyID}, credentials.deleted)
+		assert.True(t, codesDeleted)
+	})
+
+	t.Run("Backup codes stay while TOTP is enabled", func(t *testing.T) {
+		svc, mUser, mBackup, credentials, _ := setupWebAuthnTwoFactorService(t)
+		userID := uuid.New()
+		keyID := uuid.New()
+		credentials.credentials = []*models.WebAuthnCredential{{ID: keyID, UserID: userID}}
+		mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			return &models.User{ID: userID, PasswordHash: hashedPassword, WebAuthnEnabled: true, TOTPEnabled: true}, nil
+		}
+		mBackup.DeleteAllByUserIDFunc = func(ctx context.Context, id uuid.UUID) error {
+			t.Fatal("backup codes must not be deleted")
+			return nil
+		}
+
+		assert.NoError(t, svc.DeleteWebAuthnCredential(ctx, userID, keyID, "password123"))
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		svc, mUser, _, _, _ := setupWebAuthnTwoFactorService(t)
+		userID := uuid.New()
+		mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			return &models.User{ID: userID, PasswordHash: hashedPassword, WebAuthnEnabled: true}, nil
+		}
+
+		err := svc.DeleteWebAuthnCredential(ctx, userID, uuid.New(), "password123")
+		assert.Equal(t, errWebAuthnNotFound, err)
+	})
+}
+
+func TestAuthService_Verify2FALogin_ShouldSucceed_WhenBackupCodeWithOnlySecurityKeys(t *testing.T) {
+	svc, mUser, mToken, _, mAudit, mJWT, _, _, _, mBackupCode := setupAuthServiceWith2FA()
+	ctx := context.Background()
+	userID := uuid.New()
+	hash, _ := utils.HashPassword("ABCD1234", 10)
+
+	mJWT.ValidateAccessTokenFunc = func(tokenString string) (*jwt.Claims, error) {
+		return &jwt.Claims{UserID: userID}, nil
+	}
+	mUser.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return &models.User{ID: userID, Email: "keys@example.com", WebAuthnEnabled: true, IsActive: true}, nil
+	}
+	mBackupCode.GetUnusedByUserIDFunc = func(ctx context.Context, uid uuid.UUID) ([]*models.BackupCode, error) {
+		return []*models.BackupCode{{ID: uuid.New(), UserID: userID, CodeHash: hash}}, nil
+	}
+	mJWT.GenerateAccessTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) {
+		return "access-token", nil
+	}
+	mJWT.GenerateRefreshTokenFunc = func(user *models.User, applicationID ...*uuid.UUID) (string, error) {
+		return "refresh-token", nil
+	}
+	mJWT.GetAccessTokenExpirationFunc = func() time.Duration { return time.Hour }
+	mJWT.GetRefreshTokenExpirationFunc = func() time.Duration { return 24 * time.Hour }
+	mToken.CreateRefreshTokenFunc = func(ctx context.Context, token *models.RefreshToken) error { return nil }
+	var method interface{}
+	mAudit.LogFunc = func(params AuditLogParams) {
+		if params.Action == models.ActionSignIn {
+			method = params.Details["2fa_method"]
+		}
+	}
+
+	resp, err := svc.Verify2FALogin(ctx, "2fa-token", "ABCD1234", "1.1.1.1", "ua", models.DeviceInfo{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", resp.AccessToken)
+	assert.Equal(t, models.TwoFactorMethodBackupCode, method)
+}
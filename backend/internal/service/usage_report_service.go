@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// usageReportGroupLimit caps how many groups are scanned for the all-organizations report
+const usageReportGroupLimit = 1000
+
+var errUsageReportNoRecipients = models.NewAppError(http.StatusBadRequest, "No report recipients configured for this organization")
+
+// UsageReportService builds per-organization usage reports: active users, seats used
+// against the licensed seat count and MFA adoption. Organizations are groups and the
+// counts include the members of their child groups. Reports can be emailed to the
+// configured recipients weekly or monthly.
+type UsageReportService struct {
+	store     UsageReportStore
+	groupRepo GroupRepository
+	notifier  NotificationSender
+	logger    *logger.Logger
+}
+
+// NewUsageReportService creates a new usage report service
+func NewUsageReportService(store UsageReportStore, groupRepo GroupRepository, notifier NotificationSender, logger *logger.Logger) *UsageReportService {
+	return &UsageReportService{
+		store:     store,
+		groupRepo: groupRepo,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// GetReport builds the current usage report of an organization
+func (s *UsageReportService) GetReport(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReport, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.loadSettings(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildReport(ctx, group, settings.LicensedSeats)
+}
+
+// ListReports builds the usage reports of all top-level organizations
+func (s *UsageReportService) ListReports(ctx context.Context) (*models.OrgUsageReportListResponse, error) {
+	groups, _, err := s.groupRepo.List(ctx, 1, usageReportGroupLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.store.ListSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seats := make(map[uuid.UUID]*int, len(all))
+	for _, settings := range all {
+		seats[settings.GroupID] = settings.LicensedSeats
+	}
+
+	reports := make([]*models.OrgUsageReport, 0, len(groups))
+	for _, group := range groups {
+		// Child groups roll up into their parent's report
+		if group.ParentGroupID != nil {
+			continue
+		}
+		report, err := s.buildReport(ctx, group, seats[group.ID])
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return &models.OrgUsageReportListResponse{Reports: reports}, nil
+}
+
+// GetSettings returns the seat license and delivery settings of an organization
+func (s *UsageReportService) GetSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	return s.loadSettings(ctx, groupID)
+}
+
+// UpdateSettings replaces the seat license and delivery settings of an organization
+func (s *UsageReportService) UpdateSettings(ctx context.Context, groupID uuid.UUID, req *models.UpdateOrgUsageReportSettingsRequest) (*models.OrgUsageReportSettings, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	settings := &models.OrgUsageReportSettings{
+		GroupID:       groupID,
+		LicensedSeats: req.LicensedSeats,
+		Recipients:    req.Recipients,
+		Frequency:     req.Frequency,
+	}
+	if settings.Recipients == nil {
+		settings.Recipients = []string{}
+	}
+	if settings.Frequency == "" {
+		settings.Frequency = models.UsageReportFrequencyMonthly
+	}
+
+	if err := s.store.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SendReport emails the current usage report of an organization to its recipients.
+// Returns the number of emails sent.
+func (s *UsageReportService) SendReport(ctx context.Context, groupID uuid.UUID) (int, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	settings, err := s.loadSettings(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	if len(settings.Recipients) == 0 {
+		return 0, errUsageReportNoRecipients
+	}
+	return s.deliver(ctx, group, settings)
+}
+
+// SendDueReports emails the usage reports whose weekly or monthly delivery is due.
+// Returns the number of emails sent.
+func (s *UsageReportService) SendDueReports(ctx context.Context) (int, error) {
+	if s.notifier == nil {
+		return 0, nil
+	}
+
+	all, err := s.store.ListSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, settings := range all {
+		if len(settings.Recipients) == 0 || !usageReportDue(settings, now) {
+			continue
+		}
+
+		group, err := s.groupRepo.GetByID(ctx, settings.GroupID)
+		if err != nil {
+			s.logger.Warn("Failed to load group for usage report", map[string]interface{}{
+				"group_id": settings.GroupID.String(),
+				"error":    err.Error(),
+			})
+			continue
+		}
+
+		n, err := s.deliver(ctx, group, settings)
+		if err != nil {
+			s.logger.Warn("Failed to send usage report", map[string]interface{}{
+				"group_id": settings.GroupID.String(),
+				"error":    err.Error(),
+			})
+			continue
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+// deliver emails the report to every recipient and records the delivery if at least one
+// email went out
+func (s *UsageReportService) deliver(ctx context.Context, group *models.Group, settings *models.OrgUsageReportSettings) (int, error) {
+	if s.notifier == nil {
+		return 0, models.NewAppError(http.StatusServiceUnavailable, "Email delivery is not configured")
+	}
+
+	report, err := s.buildReport(ctx, group, settings.LicensedSeats)
+	if err != nil {
+		return 0, err
+	}
+	variables := usageReportVariables(report)
+
+	sent := 0
+	for _, recipient := range settings.Recipients {
+		if err := s.notifier.SendEmail(ctx, nil, nil, recipient, models.EmailTemplateTypeOrgUsageReport, variables); err != nil {
+			s.logger.Warn("Failed to send usage report email", map[string]interface{}{
+				"group_id": group.ID.String(),
+				"error":    err.Error(),
+			})
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		if err := s.store.MarkSent(ctx, group.ID, report.GeneratedAt); err != nil {
+			s.logger.Warn("Failed to mark usage report sent", map[string]interface{}{
+				"group_id": group.ID.String(),
+				"error":    err.Error(),
+			})
+		}
+	}
+	return sent, nil
+}
+
+func (s *UsageReportService) buildReport(ctx context.Context, group *models.Group, licensedSeats *int) (*models.OrgUsageReport, error) {
+	now := time.Now()
+	counts, err := s.store.GetUsageCounts(ctx, group.ID, now.AddDate(0, 0, -models.UsageReportActiveDays))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.OrgUsageReport{
+		GroupID:         group.ID,
+		GroupName:       group.Name,
+		DisplayName:     group.DisplayName,
+		GeneratedAt:     now,
+		TotalMembers:    counts.TotalMembers,
+		ActiveUsers:     counts.ActiveUsers,
+		ActiveDays:      models.UsageReportActiveDays,
+		SeatsUsed:       counts.SeatsUsed,
+		LicensedSeats:   licensedSeats,
+		MFAEnabledUsers: counts.MFAEnabled,
+	}
+	if licensedSeats != nil {
+		available := *licensedSeats - counts.SeatsUsed
+		report.OverLicense = available < 0
+		if available < 0 {
+			available = 0
+		}
+		report.SeatsAvailable = &available
+	}
+	if counts.SeatsUsed > 0 {
+		rate := float64(counts.MFAEnabled) / float64(counts.SeatsUsed) * 100
+		report.MFAAdoptionRate = math.Round(rate*10) / 10
+	}
+	return report, nil
+}
+
+// loadSettings returns the stored settings of a group, or the defaults if none were saved
+func (s *UsageReportService) loadSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error) {
+	settings, err := s.store.GetSettings(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return &models.OrgUsageReportSettings{
+				GroupID:    groupID,
+				Recipients: []string{},
+				Frequency:  models.UsageReportFrequencyMonthly,
+			}, nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *UsageReportService) getGroup(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// usageReportDue reports whether the next scheduled delivery of a report is due
+func usageReportDue(settings *models.OrgUsageReportSettings, now time.Time) bool {
+	if settings.LastSentAt == nil {
+		return true
+	}
+	next := settings.LastSentAt.AddDate(0, 1, 0)
+	if settings.Frequency == models.UsageReportFrequencyWeekly {
+		next = settings.LastSentAt.AddDate(0, 0, 7)
+	}
+	return !now.Before(next)
+}
+
+func usageReportVariables(report *models.OrgUsageReport) map[string]interface{} {
+	organization := report.DisplayName
+	if organization == "" {
+		organization = report.GroupName
+	}
+	licensedSeats := "unlimited"
+	if report.LicensedSeats != nil {
+		licensedSeats = strconv.Itoa(*report.LicensedSeats)
+	}
+
+	return map[string]interface{}{
+		"organization":      organization,
+		"total_members":     report.TotalMembers,
+		"active_users":      report.ActiveUsers,
+		"active_days":       report.ActiveDays,
+		"seats_used":        report.SeatsUsed,
+		"licensed_seats":    licensedSeats,
+		"mfa_adoption_rate": report.MFAAdoptionRate,
+		"generated_at":      report.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"),
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUsageReportStore struct {
+	settings map[uuid.UUID]*models.OrgUsageReportSettings
+	counts   map[uuid.UUID]*models.OrgUsageCounts
+	sent     map[uuid.UUID]time.Time
+}
+
+func newMockUsageReportStore() *mockUsageReportStore {
+	return &mockUsageReportStore{
+		settings: make(map[uuid.UUID]*models.OrgUsageReportSettings),
+		counts:   make(map[uuid.UUID]*models.OrgUsageCounts),
+		sent:     make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (m *mockUsageReportStore) GetSettings(ctx context.Context, groupID uuid.UUID) (*models.OrgUsageReportSettings, error) {
+	settings, ok := m.settings[groupID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return settings, nil
+}
+
+func (m *mockUsageReportStore) UpsertSettings(ctx context.Context, settings *models.OrgUsageReportSettings) error {
+	m.settings[settings.GroupID] = settings
+	return nil
+}
+
+func (m *mockUsageReportStore) ListSettings(ctx context.Context) ([]*models.OrgUsageReportSettings, error) {
+	all := make([]*models.OrgUsageReportSettings, 0, len(m.settings))
+	for _, settings := range m.settings {
+		all = append(all, settings)
+	}
+	return all, nil
+}
+
+func (m *mockUsageReportStore) MarkSent(ctx context.Context, groupID uuid.UUID, sentAt time.Time) error {
+	m.sent[groupID] = sentAt
+	return nil
+}
+
+func (m *mockUsageReportStore) GetUsageCounts(ctx context.Context, groupID uuid.UUID, activeSince time.Time) (*models.OrgUsageCounts, error) {
+	if counts, ok := m.counts[groupID]; ok {
+		return counts, nil
+	}
+	return &models.OrgUsageCounts{}, nil
+}
+
+func newTestUsageReportService() (*UsageReportService, *mockUsageReportStore, *mockGroupStore, *mockNotificationSender) {
+	store := newMockUsageReportStore()
+	groups := newMockGroupStore()
+	notifier := &mockNotificationSender{}
+	svc := NewUsageReportService(store, groups, notifier, logger.New("test", logger.InfoLevel, false))
+	return svc, store, groups, notifier
+}
+
+func TestUsageReportService_GetReport(t *testing.T) {
+	svc, store, groups, _ := newTestUsageReportService()
+	ctx := context.Background()
+
+	group := &models.Group{ID: uuid.New(), Name: "acme", DisplayName: "Acme"}
+	groups.groups[group.ID] = group
+	store.counts[group.ID] = &models.OrgUsageCounts{TotalMembers: 12, SeatsUsed: 10, ActiveUsers: 7, MFAEnabled: 3}
+
+	t.Run("unlimited seats by default", func(t *testing.T) {
+		report, err := svc.GetReport(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 12, report.TotalMembers)
+		assert.Equal(t, 10, report.SeatsUsed)
+		assert.Equal(t, 7, report.ActiveUsers)
+		assert.Equal(t, models.UsageReportActiveDays, report.ActiveDays)
+		assert.Nil(t, report.LicensedSeats)
+		assert.Nil(t, report.SeatsAvailable)
+		assert.False(t, report.OverLicense)
+		assert.Equal(t, 30.0, report.MFAAdoptionRate)
+	})
+
+	t.Run("over license", func(t *testing.T) {
+		store.settings[group.ID] = &models.OrgUsageReportSettings{GroupID: group.ID, LicensedSeats: intPtr(8)}
+
+		report, err := svc.GetReport(ctx, group.ID)
+		require.NoError(t, err)
+		require.NotNil(t, report.SeatsAvailable)
+		assert.Equal(t, 0, *report.SeatsAvailable)
+		assert.True(t, report.OverLicense)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		_, err := svc.GetReport(ctx, uuid.New())
+		assert.Equal(t, errGroupNotFound, err)
+	})
+}
+
+func TestUsageReportService_ListReports_SkipsChildGroups(t *testing.T) {
+	svc, _, groups, _ := newTestUsageReportService()
+
+	parent := &models.Group{ID: uuid.New(), Name: "acme"}
+	child := &models.Group{ID: uuid.New(), Name: "acme-eng", ParentGroupID: &parent.ID}
+	groups.groups[parent.ID] = parent
+	groups.groups[child.ID] = child
+
+	resp, err := svc.ListReports(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Reports, 1)
+	assert.Equal(t, parent.ID, resp.Reports[0].GroupID)
+}
+
+func TestUsageReportService_UpdateSettings_DefaultsFrequency(t *testing.T) {
+	svc, store, groups, _ := newTestUsageReportService()
+
+	group := &models.Group{ID: uuid.New(), Name: "acme"}
+	groups.groups[group.ID] = group
+
+	settings, err := svc.UpdateSettings(context.Background(), group.ID, &models.UpdateOrgUsageReportSettingsRequest{LicensedSeats: intPtr(25)})
+	require.NoError(t, err)
+	assert.Equal(t, models.UsageReportFrequencyMonthly, settings.Frequency)
+	assert.Equal(t, []string{}, settings.Recipients)
+	assert.Same(t, settings, store.settings[group.ID])
+}
+
+func TestUsageReportService_SendReport(t *testing.T) {
+	svc, store, groups, notifier := newTestUsageReportService()
+	ctx := context.Background()
+
+	group := &models.Group{ID: uuid.New(), Name: "acme"}
+	groups.groups[group.ID] = group
+
+	_, err := svc.SendReport(ctx, group.ID)
+	assert.Equal(t, errUsageReportNoRecipients, err)
+
+	store.settings[group.ID] = &models.OrgUsageReportSettings{
+		GroupID:    group.ID,
+		Recipients: []string{"billing@example.com", "down@example.com"},
+		Frequency:  models.UsageReportFrequencyMonthly,
+	}
+	notifier.fail = map[string]bool{"down@example.com": true}
+
+	sent, err := svc.SendReport(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"billing@example.com:" + models.EmailTemplateTypeOrgUsageReport}, notifier.sent)
+	assert.Contains(t, store.sent, group.ID)
+}
+
+func TestUsageReportService_SendDueReports(t *testing.T) {
+	svc, store, groups, notifier := newTestUsageReportService()
+
+	now := time.Now()
+	recent := now.Add(-24 * time.Hour)
+	lastWeek := now.AddDate(0, 0, -8)
+
+	add := func(frequency string, lastSent *time.Time, recipients ...string) uuid.UUID {
+		group := &models.Group{ID: uuid.New(), Name: uuid.NewString()}
+		groups.groups[group.ID] = group
+		store.settings[group.ID] = &models.OrgUsageReportSettings{
+			GroupID:    group.ID,
+			Recipients: recipients,
+			Frequency:  frequency,
+			LastSentAt: lastSent,
+		}
+		return group.ID
+	}
+
+	never := add(models.UsageReportFrequencyMonthly, nil, "never@example.com")
+	weeklyDue := add(models.UsageReportFrequencyWeekly, &lastWeek, "weekly@example.com")
+	add(models.UsageReportFrequencyWeekly, &recent, "recent@example.com")
+	add(models.UsageReportFrequencyMonthly, &lastWeek, "monthly@example.com")
+	add(models.UsageReportFrequencyMonthly, nil)
+
+	sent, err := svc.SendDueReports(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.ElementsMatch(t, []string{
+		"never@example.com:" + models.EmailTemplateTypeOrgUsageReport,
+		"weekly@example.com:" + models.EmailTemplateTypeOrgUsageReport,
+	}, notifier.sent)
+	assert.Len(t, store.sent, 2)
+	assert.Contains(t, store.sent, never)
+	assert.Contains(t, store.sent, weeklyDue)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/metrics"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	userCacheKeyPrefix  = "user_cache:"
+	defaultUserCacheTTL = 5 * time.Minute
+)
+
+// UserCache is a Redis read-through cache of users with their roles, used on the token
+// validation and userinfo paths. Users are held in their JSON form, so password hashes, TOTP
+// secrets and other fields hidden from API responses are never cached. Returned users lack
+// them even on a miss and must not be used to authenticate or be written back.
+//
+// Repositories report changed profiles and role assignments through Invalidate, which drops
+// the entry and announces the change on the bus. Concurrent misses for a user on one instance
+// share a single database load, and a load that overlaps a change announced on any instance
+// is not written back, so it cannot restore a stale entry. While the bus is down loads are
+// served but not written back at all.
+type UserCache struct {
+	users  UserStore
+	cache  KeyValueCache
+	bus    UserChangeBus
+	ttl    time.Duration
+	logger *logger.Logger
+
+	group singleflight.Group
+
+	mu        sync.Mutex
+	loads     map[*userLoad]struct{} // in-flight loads
+	connected bool                   // changes from other instances are being received
+}
+
+// userLoad tracks an in-flight load; stale is set when the user changes before it is cached
+type userLoad struct {
+	userID uuid.UUID
+	stale  bool
+}
+
+// NewUserCache creates a cache loading users from users. A nil bus keeps invalidations on
+// this instance; with a bus, loads are written back once Run has subscribed to it.
+func NewUserCache(users UserStore, cache KeyValueCache, bus UserChangeBus, ttl time.Duration, logger *logger.Logger) *UserCache {
+	if ttl <= 0 {
+		ttl = defaultUserCacheTTL
+	}
+	return &UserCache{
+		users:     users,
+		cache:     cache,
+		bus:       bus,
+		ttl:       ttl,
+		logger:    logger,
+		loads:     make(map[*userLoad]struct{}),
+		connected: bus == nil,
+	}
+}
+
+// Get returns the user with roles, active or not. Misses and Redis errors fall through to
+// the database.
+func (c *UserCache) Get(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	key := userCacheKeyPrefix + userID.String()
+	if cached, err := c.cache.Get(ctx, key); err == nil {
+		var user models.User
+		if err := json.Unmarshal([]byte(cached), &user); err == nil {
+			metrics.RecordUserCacheLookup(true)
+			return &user, nil
+		}
+	}
+	metrics.RecordUserCacheLookup(false)
+
+	// Callers share the load, so one caller giving up must not fail the others
+	payload, err, _ := c.group.Do(userID.String(), func() (interface{}, error) {
+		return c.load(context.WithoutCancel(ctx), userID, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Every caller decodes its own copy
+	var user models.User
+	if err := json.Unmarshal(payload.([]byte), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *UserCache) load(ctx context.Context, userID uuid.UUID, key string) ([]byte, error) {
+	load := &userLoad{userID: userID}
+	c.mu.Lock()
+	c.loads[load] = struct{}{}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, load)
+		c.mu.Unlock()
+	}()
+
+	user, err := c.users.GetByID(ctx, userID, nil, UserGetWithRoles())
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.stale(load) {
+		return payload, nil
+	}
+	if err := c.cache.Set(ctx, key, payload, c.ttl); err != nil {
+		c.logger.Warn("Failed to cache user", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return payload, nil
+	}
+	// The change may have been announced while the entry was written
+	if c.stale(load) {
+		_ = c.cache.Delete(ctx, key)
+	}
+	return payload, nil
+}
+
+// stale reports whether the load's result must not be cached
+func (c *UserCache) stale(load *userLoad) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return load.stale || !c.connected
+}
+
+// Invalidate drops the cached user on every instance. Failures are logged: the entry then
+// lives until its TTL.
+func (c *UserCache) Invalidate(ctx context.Context, userID uuid.UUID) {
+	c.changed(userID)
+
+	if err := c.cache.Delete(ctx, userCacheKeyPrefix+userID.String()); err != nil {
+		c.logger.Warn("Failed to invalidate cached user", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+	if c.bus != nil {
+		if err := c.bus.PublishUserChanged(ctx, userID); err != nil {
+			c.logger.Warn("Failed to publish user change", map[string]interface{}{
+				"user_id": userID.String(),
+				"error":   err.Error(),
+			})
+		}
+	}
+}
+
+// Run receives changes announced by other instances until ctx is done
+func (c *UserCache) Run(ctx context.Context) {
+	if c.bus == nil {
+		<-ctx.Done()
+		return
+	}
+	c.bus.WatchUserChanged(ctx, c.changed, func() {
+		c.setConnected(true)
+	}, func() {
+		c.setConnected(false)
+	})
+}
+
+// changed keeps in-flight loads of the user from being cached and makes new misses start
+// a fresh load
+func (c *UserCache) changed(userID uuid.UUID) {
+	c.mu.Lock()
+	for load := range c.loads {
+		if load.userID == userID {
+			load.stale = true
+		}
+	}
+	c.mu.Unlock()
+
+	c.group.Forget(userID.String())
+}
+
+func (c *UserCache) setConnected(connected bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.connected = connected
+	if !connected {
+		for load := range c.loads {
+			load.stale = true
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserChangeBus struct {
+	published []uuid.UUID
+}
+
+func (m *mockUserChangeBus) PublishUserChanged(ctx context.Context, userID uuid.UUID) error {
+	m.published = append(m.published, userID)
+	return nil
+}
+
+func (m *mockUserChangeBus) WatchUserChanged(ctx context.Context, onChange func(userID uuid.UUID), onSubscribed, onInterrupted func()) {
+	onSubscribed()
+	<-ctx.Done()
+}
+
+func setupUserCache(bus UserChangeBus) (*UserCache, *mockKeyValueCache, *int) {
+	loads := 0
+	users := &mockUserStore{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			loads++
+			return &models.User{
+				ID:           id,
+				Email:        "user@example.com",
+				PasswordHash: "hash",
+				Roles:        []models.Role{{Name: "admin"}},
+			}, nil
+		},
+	}
+	cache := &mockKeyValueCache{values: map[string]string{}}
+	return NewUserCache(users, cache, bus, 0, logger.New("test", logger.ErrorLevel, false)), cache, &loads
+}
+
+func TestUserCache_ReadThrough(t *testing.T) {
+	userCache, cache, loads := setupUserCache(nil)
+	userID := uuid.New()
+
+	_, err := userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+
+	user, err := userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, *loads)
+	assert.Equal(t, "user@example.com", user.Email)
+	require.Len(t, user.Roles, 1)
+	assert.Equal(t, "admin", user.Roles[0].Name)
+	assert.Empty(t, user.PasswordHash, "secrets are not cached")
+	assert.NotContains(t, cache.values[userCacheKeyPrefix+userID.String()], "hash")
+}
+
+func TestUserCache_Invalidate(t *testing.T) {
+	bus := &mockUserChangeBus{}
+	userCache, cache, loads := setupUserCache(bus)
+	userCache.setConnected(true)
+	userID := uuid.New()
+
+	_, err := userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	require.Contains(t, cache.values, userCacheKeyPrefix+userID.String())
+
+	userCache.Invalidate(context.Background(), userID)
+	assert.NotContains(t, cache.values, userCacheKeyPrefix+userID.String())
+	assert.Equal(t, []uuid.UUID{userID}, bus.published)
+
+	_, err = userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *loads)
+}
+
+func TestUserCache_ChangeDuringLoadIsNotCached(t *testing.T) {
+	userCache, cache, _ := setupUserCache(nil)
+	userID := uuid.New()
+
+	users := userCache.users.(*mockUserStore)
+	load := users.GetByIDFunc
+	users.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		user, err := load(ctx, id, isActive, opts...)
+		// Another instance changes the user after the row was read
+		userCache.changed(id)
+		return user, err
+	}
+
+	_, err := userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.NotContains(t, cache.values, userCacheKeyPrefix+userID.String())
+}
+
+func TestUserCache_NotWrittenBackUntilSubscribed(t *testing.T) {
+	userCache, cache, _ := setupUserCache(&mockUserChangeBus{})
+	userID := uuid.New()
+
+	_, err := userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.NotContains(t, cache.values, userCacheKeyPrefix+userID.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		userCache.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		return !userCache.stale(&userLoad{})
+	}, time.Second, time.Millisecond)
+
+	_, err = userCache.Get(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Contains(t, cache.values, userCacheKeyPrefix+userID.String())
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+var (
+	errUserEmailNotFound    = models.NewAppError(404, "Email address not found")
+	errUserEmailNotVerified = models.NewAppError(400, "Email address is not verified")
+)
+
+// UserEmailService manages the secondary email addresses of users. A secondary address
+// has to be verified before it can be used to sign in, made primary or chosen as the
+// address account recovery messages go to.
+type UserEmailService struct {
+	store        UserEmailStore
+	userRepo     UserStore
+	otpService   OTPServicer
+	auditService AuditLogger
+	logger       *logger.Logger
+}
+
+// NewUserEmailService creates a new user email service
+func NewUserEmailService(store UserEmailStore, userRepo UserStore, otpService OTPServicer, auditService AuditLogger, log *logger.Logger) *UserEmailService {
+	return &UserEmailService{
+		store:        store,
+		userRepo:     userRepo,
+		otpService:   otpService,
+		auditService: auditService,
+		logger:       log,
+	}
+}
+
+// List returns the primary and secondary email addresses of a user
+func (s *UserEmailService) List(ctx context.Context, userID uuid.UUID) (*models.UserEmailListResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, email := range emails {
+		email.IsRecovery = user.RecoveryEmail != nil && *user.RecoveryEmail == email.Email
+	}
+
+	return &models.UserEmailListResponse{
+		Primary:         user.Email,
+		PrimaryVerified: user.EmailVerified,
+		RecoveryEmail:   user.RecoveryEmail,
+		Emails:          emails,
+	}, nil
+}
+
+// Add adds an unverified secondary email address and sends a verification code to it.
+// Adding an address that is already pending verification sends a new code.
+func (s *UserEmailService) Add(ctx context.Context, userID uuid.UUID, email, ip, userAgent string) (*models.UserEmail, error) {
+	email = utils.NormalizeEmail(email)
+
+	user, err := s.userRepo.GetByID(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == email {
+		return nil, models.NewAppError(400, "This is already your primary email address")
+	}
+
+	existing, err := s.store.GetByUserAndEmail(ctx, userID, email)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Verified {
+			return nil, models.NewAppError(409, "Email address already added")
+		}
+		s.sendCode(ctx, existing)
+		return existing, nil
+	}
+
+	taken, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, models.ErrEmailAlreadyExists
+	}
+
+	emails, err := s.store.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(emails) >= models.MaxSecondaryEmails {
+		return nil, models.NewAppError(400, "Too many email addresses")
+	}
+
+	entry := &models.UserEmail{
+		ID:     uuid.New(),
+		UserID: userID,
+		Email:  email,
+	}
+	if err := s.store.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	s.sendCode(ctx, entry)
+	s.logAudit(userID, models.ActionEmailAdd, ip, userAgent, map[string]interface{}{"email": email})
+
+	return entry, nil
+}
+
+// Verify verifies a secondary email address with the code sent to it
+func (s *UserEmailService) Verify(ctx context.Context, userID uuid.UUID, email, code, ip, userAgent string) (*models.UserEmail, error) {
+	email = utils.NormalizeEmail(email)
+
+	entry, err := s.store.GetByUserAndEmail(ctx, userID, email)
+	if isNotFound(err) {
+		return nil, errUserEmailNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Verified {
+		return entry, nil
+	}
+
+	resp, err := s.otpService.VerifyOTP(ctx, &models.VerifyOTPRequest{
+		Email: &entry.Email,
+		Code:  code,
+		Type:  models.OTPTypeSecondaryEmail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Valid {
+		return nil, models.NewAppError(400, "Invalid or expired verification code")
+	}
+
+	// Someone else may have claimed the address since it was added
+	taken, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, models.ErrEmailAlreadyExists
+	}
+
+	if err := s.store.MarkVerified(ctx, entry.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry.Verified = true
+	entry.VerifiedAt = &now
+
+	s.logAudit(userID, models.ActionEmailVerify, ip, userAgent, map[string]interface{}{"email": email})
+
+	return entry, nil
+}
+
+// Remove removes a secondary email address
+func (s *UserEmailService) Remove(ctx context.Context, userID, id uuid.UUID, ip, userAgent string) error {
+	entry, err := s.store.GetByID(ctx, userID, id)
+	if isNotFound(err) {
+		return errUserEmailNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.logAudit(userID, models.ActionEmailRemove, ip, userAgent, map[string]interface{}{"email": entry.Email})
+
+	return nil
+}
+
+// SetPrimary makes a verified secondary email address the primary one.
+// The previous primary address is kept as a secondary address.
+func (s *UserEmailService) SetPrimary(ctx context.Context, userID, id uuid.UUID, ip, userAgent string) error {
+	entry, err := s.store.GetByID(ctx, userID, id)
+	if isNotFound(err) {
+		return errUserEmailNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !entry.Verified {
+		return errUserEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.SetPrimary(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.logAudit(userID, models.ActionEmailPrimaryChange, ip, userAgent, map[string]interface{}{
+		"old_email": user.Email,
+		"new_email": entry.Email,
+	})
+
+	return nil
+}
+
+// SetRecoveryEmail chooses which verified secondary email address receives account recovery messages
+func (s *UserEmailService) SetRecoveryEmail(ctx context.Context, userID uuid.UUID, email, ip, userAgent string) error {
+	email = utils.NormalizeEmail(email)
+
+	entry, err := s.store.GetByUserAndEmail(ctx, userID, email)
+	if isNotFound(err) {
+		return errUserEmailNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !entry.Verified {
+		return errUserEmailNotVerified
+	}
+
+	if err := s.store.SetRecoveryEmail(ctx, userID, &entry.Email); err != nil {
+		return err
+	}
+
+	s.logAudit(userID, models.ActionRecoveryEmailChange, ip, userAgent, map[string]interface{}{"email": entry.Email})
+
+	return nil
+}
+
+// ClearRecoveryEmail stops sending account recovery messages to a secondary email address
+func (s *UserEmailService) ClearRecoveryEmail(ctx context.Context, userID uuid.UUID, ip, userAgent string) error {
+	if err := s.store.SetRecoveryEmail(ctx, userID, nil); err != nil {
+		return err
+	}
+
+	s.logAudit(userID, models.ActionRecoveryEmailChange, ip, userAgent, map[string]interface{}{"email": nil})
+
+	return nil
+}
+
+func (s *UserEmailService) sendCode(ctx context.Context, entry *models.UserEmail) {
+	err := s.otpService.SendOTP(ctx, &models.SendOTPRequest{
+		Email: &entry.Email,
+		Type:  models.OTPTypeSecondaryEmail,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to send email verification code", map[string]interface{}{
+			"user_id": entry.UserID.String(),
+			"error":   err.Error(),
+		})
+	}
+}
+
+func (s *UserEmailService) logAudit(userID uuid.UUID, action models.AuditAction, ip, userAgent string, details map[string]interface{}) {
+	s.auditService.Log(AuditLogParams{
+		UserID:    &userID,
+		Action:    action,
+		Status:    models.StatusSuccess,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details:   details,
+	})
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserEmailStore struct {
+	emails   map[uuid.UUID]*models.UserEmail
+	users    map[uuid.UUID]*models.User
+	promoted []uuid.UUID
+}
+
+func newMockUserEmailStore() *mockUserEmailStore {
+	return &mockUserEmailStore{
+		emails: make(map[uuid.UUID]*models.UserEmail),
+		users:  make(map[uuid.UUID]*models.User),
+	}
+}
+
+func (m *mockUserEmailStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserEmail, error) {
+	emails := make([]*models.UserEmail, 0)
+	for _, email := range m.emails {
+		if email.UserID == userID {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
+func (m *mockUserEmailStore) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.UserEmail, error) {
+	email, ok := m.emails[id]
+	if !ok || email.UserID != userID {
+		return nil, models.ErrNotFound
+	}
+	return email, nil
+}
+
+func (m *mockUserEmailStore) GetByUserAndEmail(ctx context.Context, userID uuid.UUID, address string) (*models.UserEmail, error) {
+	for _, email := range m.emails {
+		if email.UserID == userID && email.Email == address {
+			return email, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *mockUserEmailStore) Create(ctx context.Context, email *models.UserEmail) error {
+	m.emails[email.ID] = email
+	return nil
+}
+
+func (m *mockUserEmailStore) MarkVerified(ctx context.Context, id uuid.UUID) error {
+	m.emails[id].Verified = true
+	return nil
+}
+
+func (m *mockUserEmailStore) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	delete(m.emails, id)
+	return nil
+}
+
+func (m *mockUserEmailStore) SetPrimary(ctx context.Context, userID, id uuid.UUID) error {
+	m.promoted = append(m.promoted, id)
+	return nil
+}
+
+func (m *mockUserEmailStore) SetRecoveryEmail(ctx context.Context, userID uuid.UUID, email *string) error {
+	m.users[userID].RecoveryEmail = email
+	m.users[userID].RecoveryEmailVerified = email != nil
+	return nil
+}
+
+type userEmailFixture struct {
+	svc   *UserEmailService
+	store *mockUserEmailStore
+	otp   *mockRecoveryOTPService
+	user  *models.User
+	taken map[string]bool
+}
+
+func newUserEmailFixture(t *testing.T) *userEmailFixture {
+	t.Helper()
+
+	store := newMockUserEmailStore()
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", EmailVerified: true, IsActive: true}
+	store.users[user.ID] = user
+
+	f := &userEmailFixture{
+		store: store,
+		otp:   &mockRecoveryOTPService{},
+		user:  user,
+		taken: map[string]bool{"other@example.com": true},
+	}
+
+	userRepo := &mockUserStore{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			if id == user.ID {
+				return user, nil
+			}
+			return nil, models.ErrUserNotFound
+		},
+		EmailExistsFunc: func(ctx context.Context, email string) (bool, error) {
+			return f.taken[email], nil
+		},
+	}
+
+	f.svc = NewUserEmailService(store, userRepo, f.otp, &mockAuditLogger{}, logger.New("test", logger.DebugLevel, false))
+	return f
+}
+
+func TestUserEmailService_AddAndVerify(t *testing.T) {
+	f := newUserEmailFixture(t)
+	ctx := context.Background()
+
+	added, err := f.svc.Add(ctx, f.user.ID, " Backup@Example.com ", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "backup@example.com", added.Email)
+	assert.False(t, added.Verified)
+	assert.Equal(t, []string{"backup@example.com"}, f.otp.sent)
+
+	// Adding it again re-sends the code instead of creating a duplicate
+	again, err := f.svc.Add(ctx, f.user.ID, "backup@example.com", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, added.ID, again.ID)
+	assert.Len(t, f.otp.sent, 2)
+
+	// An unverified address cannot receive recovery messages
+	err = f.svc.SetRecoveryEmail(ctx, f.user.ID, "backup@example.com", "", "")
+	require.Error(t, err)
+
+	_, err = f.svc.Verify(ctx, f.user.ID, "backup@example.com", "000000", "", "")
+	require.Error(t, err)
+
+	verified, err := f.svc.Verify(ctx, f.user.ID, "backup@example.com", "123456", "", "")
+	require.NoError(t, err)
+	assert.True(t, verified.Verified)
+	assert.NotNil(t, verified.VerifiedAt)
+
+	require.NoError(t, f.svc.SetRecoveryEmail(ctx, f.user.ID, "backup@example.com", "", ""))
+
+	list, err := f.svc.List(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", list.Primary)
+	require.Len(t, list.Emails, 1)
+	assert.True(t, list.Emails[0].IsRecovery)
+	assert.Equal(t, "backup@example.com", *list.RecoveryEmail)
+
+	require.NoError(t, f.svc.ClearRecoveryEmail(ctx, f.user.ID, "", ""))
+	assert.Nil(t, f.user.RecoveryEmail)
+}
+
+func TestUserEmailService_AddRejectsTakenAddresses(t *testing.T) {
+	f := newUserEmailFixture(t)
+	ctx := context.Background()
+
+	_, err := f.svc.Add(ctx, f.user.ID, "user@example.com", "", "")
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 400, appErr.Code)
+
+	_, err = f.svc.Add(ctx, f.user.ID, "other@example.com", "", "")
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 409, appErr.Code)
+	assert.Empty(t, f.otp.sent)
+}
+
+func TestUserEmailService_VerifyRejectsAddressClaimedMeanwhile(t *testing.T) {
+	f := newUserEmailFixture(t)
+	ctx := context.Background()
+
+	_, err := f.svc.Add(ctx, f.user.ID, "shared@example.com", "", "")
+	require.NoError(t, err)
+
+	f.taken["shared@example.com"] = true
+
+	_, err = f.svc.Verify(ctx, f.user.ID, "shared@example.com", "123456", "", "")
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 409, appErr.Code)
+}
+
+func TestUserEmailService_SetPrimaryRequiresVerifiedAddress(t *testing.T) {
+	f := newUserEmailFixture(t)
+	ctx := context.Background()
+
+	added, err := f.svc.Add(ctx, f.user.ID, "new@example.com", "", "")
+	require.NoError(t, err)
+
+	err = f.svc.SetPrimary(ctx, f.user.ID, added.ID, "", "")
+	require.Error(t, err)
+	assert.Empty(t, f.store.promoted)
+
+	_, err = f.svc.Verify(ctx, f.user.ID, "new@example.com", "123456", "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, f.svc.SetPrimary(ctx, f.user.ID, added.ID, "", ""))
+	assert.Equal(t, []uuid.UUID{added.ID}, f.store.promoted)
+
+	// Another user's address is not found
+	err = f.svc.SetPrimary(ctx, uuid.New(), added.ID, "", "")
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 404, appErr.Code)
+}
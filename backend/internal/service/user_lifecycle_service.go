@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
+)
+
+// Reasons recorded for transitions the gateway makes on its own
+const (
+	StateReasonDormant            = "dormant"
+	StateReasonInvitationAccepted = "invitation_accepted"
+	StateReasonDeleted            = "deleted"
+)
+
+// UserStateTransitionParams describes a lifecycle state change
+type UserStateTransitionParams struct {
+	UserID    uuid.UUID
+	State     models.UserState
+	Reason    string
+	ActorID   *uuid.UUID // nil for transitions made by the gateway itself
+	IP        string
+	UserAgent string
+}
+
+// UserLifecycleService moves accounts between lifecycle states. Only transitions allowed by
+// models.UserState are applied, and each one is a compare-and-set on the stored state, so
+// concurrent changes cannot both win. Entering a state that blocks sign-in revokes the
+// account's refresh tokens and sessions and bumps its token version, so outstanding access
+// tokens stop working immediately. Every change is audited and announced through the
+// user.state_changed webhook.
+type UserLifecycleService struct {
+	users         UserStateStore
+	tokens        TokenStore
+	sessions      SessionStore
+	tokenVersions TokenVersionChecker
+	audit         AuditLogger
+	logger        *logger.Logger
+
+	webhooks WebhookTrigger
+}
+
+// NewUserLifecycleService creates a new user lifecycle service
+func NewUserLifecycleService(users UserStateStore, tokens TokenStore, sessions SessionStore, tokenVersions TokenVersionChecker, audit AuditLogger, logger *logger.Logger) *UserLifecycleService {
+	return &UserLifecycleService{
+		users:         users,
+		tokens:        tokens,
+		sessions:      sessions,
+		tokenVersions: tokenVersions,
+		audit:         audit,
+		logger:        logger,
+	}
+}
+
+// SetWebhooks announces state changes through webhooks
+func (s *UserLifecycleService) SetWebhooks(webhooks WebhookTrigger) {
+	s.webhooks = webhooks
+}
+
+// Transition moves a user to params.State and returns the updated user. Moving a user to the
+// state it is already in changes nothing, except that access is revoked again for states that
+// revoke it, so a transition that failed half way can simply be retried.
+func (s *UserLifecycleService) Transition(ctx context.Context, params UserStateTransitionParams) (*models.User, error) {
+	if !params.State.IsValid() {
+		return nil, models.ErrInvalidUserState
+	}
+
+	user, err := s.users.GetByID(ctx, params.UserID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	from := user.LifecycleState()
+	if from == params.State {
+		if params.State.RevokesTokens() {
+			if err := s.revokeAccess(ctx, user.ID); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+	if !from.CanTransitionTo(params.State) {
+		return nil, models.NewAppError(http.StatusConflict, "Invalid user state transition",
+			fmt.Sprintf("A %s user cannot become %s", from, params.State))
+	}
+
+	if err := s.users.UpdateState(ctx, user.ID, from, params.State); err != nil {
+		return nil, err
+	}
+	user.SetState(params.State)
+
+	if params.State.RevokesTokens() {
+		if err := s.revokeAccess(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("User state changed", map[string]interface{}{
+		"user_id":    user.ID.String(),
+		"from_state": from,
+		"to_state":   params.State,
+		"reason":     params.Reason,
+	})
+	s.recordChange(user, from, params)
+
+	return user, nil
+}
+
+// RecordSignIn stores the time of a successful sign-in, which dormancy is measured from.
+// Failures are logged; they never block the sign-in.
+func (s *UserLifecycleService) RecordSignIn(ctx context.Context, userID uuid.UUID) {
+	if err := s.users.TouchLastLogin(ctx, userID); err != nil {
+		s.logger.Warn("failed to record last sign-in", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+}
+
+// AcceptInvitation activates an invited user once they have set their own password.
+// Users in any other state are left alone.
+func (s *UserLifecycleService) AcceptInvitation(ctx context.Context, userID uuid.UUID, ip, userAgent string) error {
+	user, err := s.users.GetByID(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	if user.LifecycleState() != models.UserStateInvited {
+		return nil
+	}
+
+	_, err = s.Transition(ctx, UserStateTransitionParams{
+		UserID:    userID,
+		State:     models.UserStateActive,
+		Reason:    StateReasonInvitationAccepted,
+		ActorID:   &userID,
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+	return err
+}
+
+// revokeAccess invalidates every token and session the user holds. The token version bump
+// is what rejects access tokens, so only its failure is returned; the rest is bookkeeping.
+func (s *UserLifecycleService) revokeAccess(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.tokenVersions.Bump(ctx, userID); err != nil {
+		return fmt.Errorf("failed to bump token version: %w", err)
+	}
+	if err := s.tokens.RevokeAllUserTokens(ctx, userID); err != nil {
+		s.logger.Warn("failed to revoke refresh tokens after state change", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+	if err := s.sessions.RevokeAllUserSessions(ctx, userID, nil); err != nil {
+		s.logger.Warn("failed to revoke sessions after state change", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+	return nil
+}
+
+// recordChange audits a transition and triggers the user.state_changed webhook
+func (s *UserLifecycleService) recordChange(user *models.User, from models.UserState, params UserStateTransitionParams) {
+	actorID := ""
+	if params.ActorID != nil {
+		actorID = params.ActorID.String()
+	}
+
+	details := map[string]interface{}{
+		"from_state": string(from),
+		"to_state":   string(params.State),
+	}
+	if params.Reason != "" {
+		details["reason"] = params.Reason
+	}
+	if actorID != "" {
+		details["actor_id"] = actorID
+	}
+	s.audit.Log(AuditLogParams{
+		UserID:    &user.ID,
+		Action:    models.ActionUserStateChange,
+		Status:    models.StatusSuccess,
+		IP:        params.IP,
+		UserAgent: params.UserAgent,
+		Details:   details,
+	})
+
+	if s.webhooks == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"user_id":    user.ID.String(),
+		"email":      user.Email,
+		"from_state": string(from),
+		"to_state":   string(params.State),
+		"reason":     params.Reason,
+		"actor_id":   actorID,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	}
+	go func() {
+		webhookCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := s.webhooks.TriggerWebhook(webhookCtx, models.WebhookEventUserStateChanged, data); err != nil {
+			s.logger.Warn("failed to trigger user.state_changed webhook", map[string]interface{}{
+				"user_id": user.ID.String(),
+				"error":   err.Error(),
+			})
+		}
+	}()
+}
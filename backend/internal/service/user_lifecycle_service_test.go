@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserStateStore struct {
+	users   map[uuid.UUID]*models.User
+	touched []uuid.UUID
+}
+
+func (m *mockUserStateStore) GetByID(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (m *mockUserStateStore) UpdateState(ctx context.Context, id uuid.UUID, from, to models.UserState) error {
+	user, ok := m.users[id]
+	if !ok || user.LifecycleState() != from {
+		return models.ErrUserStateConflict
+	}
+	user.SetState(to)
+	return nil
+}
+
+func (m *mockUserStateStore) TouchLastLogin(ctx context.Context, id uuid.UUID) error {
+	m.touched = append(m.touched, id)
+	return nil
+}
+
+type lifecycleFixture struct {
+	svc           *UserLifecycleService
+	store         *mockUserStateStore
+	tokenVersions *mockTokenVersionChecker
+	revokedTokens []uuid.UUID
+	revokedSess   []uuid.UUID
+	audited       []AuditLogParams
+}
+
+func setupUserLifecycleService(users ...*models.User) *lifecycleFixture {
+	f := &lifecycleFixture{
+		store:         &mockUserStateStore{users: map[uuid.UUID]*models.User{}},
+		tokenVersions: &mockTokenVersionChecker{},
+	}
+	for _, user := range users {
+		f.store.users[user.ID] = user
+	}
+	tokens := &mockTokenStore{RevokeAllUserTokensFunc: func(ctx context.Context, userID uuid.UUID) error {
+		f.revokedTokens = append(f.revokedTokens, userID)
+		return nil
+	}}
+	sessions := &mockSessionStore{RevokeAllUserSessionsFunc: func(ctx context.Context, userID uuid.UUID, except *uuid.UUID) error {
+		f.revokedSess = append(f.revokedSess, userID)
+		return nil
+	}}
+	audit := &mockAuditLogger{LogFunc: func(params AuditLogParams) { f.audited = append(f.audited, params) }}
+	f.svc = NewUserLifecycleService(f.store, tokens, sessions, f.tokenVersions, audit, testLogger())
+	return f
+}
+
+func TestUserLifecycleService_Transition(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("Suspending revokes access and is audited", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateActive, IsActive: true}
+		f := setupUserLifecycleService(user)
+
+		updated, err := f.svc.Transition(ctx, UserStateTransitionParams{UserID: user.ID, State: models.UserStateSuspended, Reason: "chargeback", ActorID: &adminID})
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStateSuspended, updated.State)
+		assert.False(t, updated.IsActive)
+		assert.Equal(t, models.UserStateSuspended, f.store.users[user.ID].State)
+
+		assert.Equal(t, []uuid.UUID{user.ID}, f.tokenVersions.bumped)
+		assert.Equal(t, []uuid.UUID{user.ID}, f.revokedTokens)
+		assert.Equal(t, []uuid.UUID{user.ID}, f.revokedSess)
+
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, models.ActionUserStateChange, f.audited[0].Action)
+		assert.Equal(t, &user.ID, f.audited[0].UserID)
+		assert.Equal(t, "active", f.audited[0].Details["from_state"])
+		assert.Equal(t, "suspended", f.audited[0].Details["to_state"])
+		assert.Equal(t, "chargeback", f.audited[0].Details["reason"])
+		assert.Equal(t, adminID.String(), f.audited[0].Details["actor_id"])
+	})
+
+	t.Run("Reactivating keeps tokens alone", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateSuspended}
+		f := setupUserLifecycleService(user)
+
+		updated, err := f.svc.Transition(ctx, UserStateTransitionParams{UserID: user.ID, State: models.UserStateActive, ActorID: &adminID})
+		require.NoError(t, err)
+		assert.True(t, updated.IsActive)
+		assert.Empty(t, f.tokenVersions.bumped)
+		assert.Len(t, f.audited, 1)
+	})
+
+	t.Run("Disallowed transition is a conflict", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateDeleted}
+		f := setupUserLifecycleService(user)
+
+		_, err := f.svc.Transition(ctx, UserStateTransitionParams{UserID: user.ID, State: models.UserStateActive})
+		var appErr *models.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.Code)
+		assert.Equal(t, models.UserStateDeleted, f.store.users[user.ID].State)
+		assert.Empty(t, f.audited)
+	})
+
+	t.Run("Unknown state is rejected", func(t *testing.T) {
+		f := setupUserLifecycleService()
+
+		_, err := f.svc.Transition(ctx, UserStateTransitionParams{UserID: uuid.New(), State: "banned"})
+		assert.ErrorIs(t, err, models.ErrInvalidUserState)
+	})
+
+	t.Run("Repeating a revoking state revokes again without auditing", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateDeactivated}
+		f := setupUserLifecycleService(user)
+
+		_, err := f.svc.Transition(ctx, UserStateTransitionParams{UserID: user.ID, State: models.UserStateDeactivated})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user.ID}, f.tokenVersions.bumped)
+		assert.Empty(t, f.audited)
+	})
+}
+
+func TestUserLifecycleService_AcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Invited user becomes active", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateInvited}
+		f := setupUserLifecycleService(user)
+
+		require.NoError(t, f.svc.AcceptInvitation(ctx, user.ID, "203.0.113.7", "ua"))
+		assert.Equal(t, models.UserStateActive, f.store.users[user.ID].State)
+		require.Len(t, f.audited, 1)
+		assert.Equal(t, StateReasonInvitationAccepted, f.audited[0].Details["reason"])
+	})
+
+	t.Run("Suspended user stays suspended", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), State: models.UserStateSuspended}
+		f := setupUserLifecycleService(user)
+
+		require.NoError(t, f.svc.AcceptInvitation(ctx, user.ID, "", ""))
+		assert.Equal(t, models.UserStateSuspended, f.store.users[user.ID].State)
+		assert.Empty(t, f.audited)
+	})
+}
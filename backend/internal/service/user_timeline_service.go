@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// UserTimelineService merges a user's audit log, sessions, role changes, OAuth consents and
+// API key activity into one chronological feed for support staff
+type UserTimelineService struct {
+	store UserTimelineStore
+	users UserStore
+}
+
+// NewUserTimelineService creates a new user timeline service
+func NewUserTimelineService(store UserTimelineStore, users UserStore) *UserTimelineService {
+	return &UserTimelineService{
+		store: store,
+		users: users,
+	}
+}
+
+// ListUserTimeline returns a page of the user's events, newest first. sources limits the
+// feed to some of models.TimelineSources; an empty list means all of them.
+func (s *UserTimelineService) ListUserTimeline(ctx context.Context, userID uuid.UUID, sources []string, page, pageSize int) (*models.UserTimelineResponse, error) {
+	if len(sources) == 0 {
+		sources = models.TimelineSources
+	}
+	for _, source := range sources {
+		if !slices.Contains(models.TimelineSources, source) {
+			return nil, models.NewAppError(http.StatusBadRequest,
+				fmt.Sprintf("Unknown timeline source %q, expected one of: %s", source, strings.Join(models.TimelineSources, ", ")))
+		}
+	}
+
+	if _, err := s.users.GetByID(ctx, userID, nil); err != nil {
+		return nil, err
+	}
+
+	events, total, err := s.store.ListUserTimeline(ctx, userID, sources, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (total + pageSize - 1) / pageSize
+	}
+
+	return &models.UserTimelineResponse{
+		Events:     events,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockUserTimelineStore records the sources it was asked for
+type mockUserTimelineStore struct {
+	sources []string
+	events  []*models.UserTimelineEvent
+	total   int
+}
+
+func (m *mockUserTimelineStore) ListUserTimeline(ctx context.Context, userID uuid.UUID, sources []string, page, pageSize int) ([]*models.UserTimelineEvent, int, error) {
+	m.sources = sources
+	return m.events, m.total, nil
+}
+
+func newTestUserTimelineService() (*UserTimelineService, *mockUserTimelineStore, *mockUserStore) {
+	store := &mockUserTimelineStore{}
+	users := &mockUserStore{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+			return &models.User{ID: id}, nil
+		},
+	}
+	return NewUserTimelineService(store, users), store, users
+}
+
+func TestUserTimelineService_ListUserTimeline_ShouldDefaultToAllSources(t *testing.T) {
+	svc, store, _ := newTestUserTimelineService()
+	store.events = []*models.UserTimelineEvent{{Source: models.TimelineSourceSession, Event: "session_started"}}
+	store.total = 41
+
+	resp, err := svc.ListUserTimeline(context.Background(), uuid.New(), nil, 2, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TimelineSources, store.sources)
+	assert.Len(t, resp.Events, 1)
+	assert.Equal(t, 41, resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 3, resp.TotalPages)
+}
+
+func TestUserTimelineService_ListUserTimeline_ShouldFilterSources(t *testing.T) {
+	svc, store, _ := newTestUserTimelineService()
+
+	_, err := svc.ListUserTimeline(context.Background(), uuid.New(), []string{models.TimelineSourceRole, models.TimelineSourceConsent}, 1, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.TimelineSourceRole, models.TimelineSourceConsent}, store.sources)
+}
+
+func TestUserTimelineService_ListUserTimeline_ShouldRejectUnknownSource(t *testing.T) {
+	svc, store, _ := newTestUserTimelineService()
+
+	_, err := svc.ListUserTimeline(context.Background(), uuid.New(), []string{"webhooks"}, 1, 20)
+
+	var appErr *models.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	assert.Nil(t, store.sources)
+}
+
+func TestUserTimelineService_ListUserTimeline_ShouldReturnNotFound_WhenUserMissing(t *testing.T) {
+	svc, store, users := newTestUserTimelineService()
+	users.GetByIDFunc = func(ctx context.Context, id uuid.UUID, isActive *bool, opts ...UserGetOption) (*models.User, error) {
+		return nil, models.ErrUserNotFound
+	}
+
+	_, err := svc.ListUserTimeline(context.Background(), uuid.New(), nil, 1, 20)
+
+	assert.Equal(t, models.ErrUserNotFound, err)
+	assert.Nil(t, store.sources)
+}
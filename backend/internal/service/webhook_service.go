@@ -8,20 +8,34 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/smilemakc/auth-gateway/internal/chaos"
 	"github.com/smilemakc/auth-gateway/internal/models"
 	"github.com/smilemakc/auth-gateway/internal/repository"
+	"github.com/smilemakc/auth-gateway/internal/tracing"
 )
 
+// webhookDeliveryJob is the background job type of queued webhook deliveries
+const webhookDeliveryJob = "webhook.delivery"
+
+// webhookDeliveryJobPayload identifies the delivery a queued job sends
+type webhookDeliveryJobPayload struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+var errWebhookInactive = models.NewAppError(http.StatusConflict, "Webhook is inactive")
+
 // WebhookService handles webhook operations
 type WebhookService struct {
 	repo         *repository.WebhookRepository
 	auditService *AuditService
 	httpClient   *http.Client
+	jobs         *JobQueueService
 }
 
 // NewWebhookService creates a new webhook service
@@ -35,6 +49,25 @@ func NewWebhookService(repo *repository.WebhookRepository, auditService *AuditSe
 	}
 }
 
+// SetFaultInjector injects faults into webhook deliveries (non-production only)
+func (s *WebhookService) SetFaultInjector(faults *chaos.Injector) {
+	s.httpClient.Transport = faults.Transport(s.httpClient.Transport)
+}
+
+// EnableTracing records a span for each delivery and passes the trace on in the
+// traceparent header
+func (s *WebhookService) EnableTracing() {
+	s.httpClient.Transport = tracing.Transport(s.httpClient.Transport)
+}
+
+// SetJobQueue sends webhook deliveries through the background job queue, which retries
+// failed deliveries according to the retry config of each webhook. Without it every
+// delivery is attempted once.
+func (s *WebhookService) SetJobQueue(jobs *JobQueueService) {
+	s.jobs = jobs
+	jobs.Register(webhookDeliveryJob, s.runDeliveryJob)
+}
+
 // CreateWebhook creates a new webhook
 func (s *WebhookService) CreateWebhook(ctx context.Context, req *models.CreateWebhookRequest, createdBy uuid.UUID) (*models.Webhook, string, error) {
 	// Validate events
@@ -236,48 +269,172 @@ func (s *WebhookService) TriggerWebhook(ctx context.Context, eventType string, d
 		Data:      data,
 	}
 
+	payload, _ := json.Marshal(event)
+
+	var errs []error
 	for _, webhook := range webhooks {
+		if s.jobs != nil {
+			if _, err := s.queueDelivery(ctx, webhook, eventType, payload); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
 		// Use context with timeout for webhook delivery
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		go func(w models.Webhook) {
 			defer cancel()
-			s.deliverWebhook(ctx, w, event)
+			s.deliverWebhook(ctx, w, eventType, payload)
 		}(webhook)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// deliverWebhook delivers a webhook to a single endpoint
-func (s *WebhookService) deliverWebhook(ctx context.Context, webhook models.Webhook, event models.WebhookEvent) {
-	payload, _ := json.Marshal(event)
+// RedeliverWebhookDelivery sends the payload of a past delivery again. The redelivery is a
+// new delivery with its own attempts, retried like any other; the original is left as is.
+func (s *WebhookService) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	original, err := s.repo.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, models.ErrNotFound
+	}
+	webhook, err := s.repo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.IsActive {
+		return nil, errWebhookInactive
+	}
+
+	if s.jobs != nil {
+		return s.queueDelivery(ctx, *webhook, original.EventType, original.Payload)
+	}
+
+	delivery, err := s.createDelivery(ctx, webhook.ID, original.EventType, original.Payload)
+	if err != nil {
+		return nil, err
+	}
+	sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	go func() {
+		defer cancel()
+		s.sendDelivery(sendCtx, *webhook, delivery)
+	}()
+	return delivery, nil
+}
 
-	// Create delivery record
+// createDelivery records a pending delivery of payload
+func (s *WebhookService) createDelivery(ctx context.Context, webhookID uuid.UUID, eventType string, payload []byte) (*models.WebhookDelivery, error) {
 	delivery := &models.WebhookDelivery{
-		WebhookID: webhook.ID,
-		EventType: event.EventType,
+		WebhookID: webhookID,
+		EventType: eventType,
 		Payload:   payload,
 		Status:    "pending",
 		Attempts:  0,
 	}
-
 	if err := s.repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// queueDelivery records a pending delivery and queues a job to send it
+func (s *WebhookService) queueDelivery(ctx context.Context, webhook models.Webhook, eventType string, payload []byte) (*models.WebhookDelivery, error) {
+	delivery, err := s.createDelivery(ctx, webhook.ID, eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first attempt plus the configured retries
+	retryConfig := webhookRetryConfig(webhook)
+	if _, err := s.jobs.Enqueue(ctx, webhookDeliveryJob, webhookDeliveryJobPayload{DeliveryID: delivery.ID}, retryConfig.MaxAttempts+1); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// runDeliveryJob makes one attempt of a queued delivery. Failed attempts are retried after
+// the webhook's backoff; the delivery record follows the job.
+func (s *WebhookService) runDeliveryJob(ctx context.Context, job *models.BackgroundJob) error {
+	var payload webhookDeliveryJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return PermanentJobError(fmt.Errorf("invalid webhook delivery job: %w", err))
+	}
+
+	// Deliveries are deleted along with their webhook
+	delivery, err := s.repo.GetWebhookDeliveryByID(ctx, payload.DeliveryID)
+	if errors.Is(err, models.ErrNotFound) {
+		return PermanentJobError(errors.New("webhook delivery was deleted"))
+	}
+	if err != nil {
+		return err
+	}
+	webhook, err := s.repo.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if !webhook.IsActive {
+		s.updateDeliveryFailed(ctx, delivery.ID, 0, "webhook is inactive")
+		return PermanentJobError(errors.New("webhook is inactive"))
+	}
+
+	statusCode, err := s.postWebhook(ctx, *webhook, delivery.EventType, delivery.Payload)
+	if err == nil {
+		s.repo.UpdateDeliveryStatus(ctx, delivery.ID, "success", &statusCode, "", nil)
+		s.repo.UpdateWebhookLastTriggered(ctx, webhook.ID)
+		return nil
+	}
+
+	if job.Attempts < job.MaxAttempts {
+		delay := webhookRetryDelay(webhookRetryConfig(*webhook), job.Attempts)
+		var statusPtr *int
+		if statusCode > 0 {
+			statusPtr = &statusCode
+		}
+		var nextRetry interface{} = time.Now().Add(delay)
+		s.repo.UpdateDeliveryStatus(ctx, delivery.ID, "failed", statusPtr, err.Error(), &nextRetry)
+		return RetryJobAfter(err, delay)
+	}
+
+	s.updateDeliveryFailed(ctx, delivery.ID, statusCode, err.Error())
+	return err
+}
+
+// deliverWebhook delivers a webhook to a single endpoint once
+func (s *WebhookService) deliverWebhook(ctx context.Context, webhook models.Webhook, eventType string, payload []byte) {
+	delivery, err := s.createDelivery(ctx, webhook.ID, eventType, payload)
+	if err != nil {
 		return
 	}
+	s.sendDelivery(ctx, webhook, delivery)
+}
 
-	// Create signature
-	signature := s.createSignature(payload, webhook.SecretKey)
+// sendDelivery makes a single attempt of a recorded delivery
+func (s *WebhookService) sendDelivery(ctx context.Context, webhook models.Webhook, delivery *models.WebhookDelivery) {
+	statusCode, err := s.postWebhook(ctx, webhook, delivery.EventType, delivery.Payload)
+	if err != nil {
+		s.updateDeliveryFailed(ctx, delivery.ID, statusCode, err.Error())
+		return
+	}
 
+	s.repo.UpdateDeliveryStatus(ctx, delivery.ID, "success", &statusCode, "", nil)
+	s.repo.UpdateWebhookLastTriggered(ctx, webhook.ID)
+}
+
+// postWebhook sends a signed payload to the webhook endpoint. It returns the response status
+// (0 if there was none) and an error unless the endpoint answered 2xx.
+func (s *WebhookService) postWebhook(ctx context.Context, webhook models.Webhook, eventType string, payload []byte) (int, error) {
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewReader(payload))
 	if err != nil {
-		s.updateDeliveryFailed(ctx, delivery.ID, 0, err.Error())
-		return
+		return 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Webhook-Signature", signature)
-	req.Header.Set("X-Webhook-Event", event.EventType)
+	setWebhookSignature(req, payload, webhook.SecretKey)
+	req.Header.Set("X-Webhook-Event", eventType)
 
 	// Add custom headers
 	var headers map[string]string
@@ -290,18 +447,15 @@ func (s *WebhookService) deliverWebhook(ctx context.Context, webhook models.Webh
 	// Send request
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		s.updateDeliveryFailed(ctx, delivery.ID, 0, err.Error())
-		return
+		return 0, err
 	}
 	defer resp.Body.Close()
 
 	// Check response
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		s.repo.UpdateDeliveryStatus(ctx, delivery.ID, "success", &resp.StatusCode, "", nil)
-		s.repo.UpdateWebhookLastTriggered(ctx, webhook.ID)
-	} else {
-		s.updateDeliveryFailed(ctx, delivery.ID, resp.StatusCode, "non-2xx response")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.New("non-2xx response")
 	}
+	return resp.StatusCode, nil
 }
 
 // updateDeliveryFailed updates a delivery as failed
@@ -313,13 +467,47 @@ func (s *WebhookService) updateDeliveryFailed(ctx context.Context, id uuid.UUID,
 	s.repo.UpdateDeliveryStatus(ctx, id, "failed", statusPtr, responseBody, nil)
 }
 
-// createSignature creates HMAC-SHA256 signature
-func (s *WebhookService) createSignature(payload []byte, secret string) string {
+// webhookRetryConfig returns the retry config of a webhook, or the default if it has none
+func webhookRetryConfig(webhook models.Webhook) models.RetryConfig {
+	var retryConfig models.RetryConfig
+	if err := json.Unmarshal(webhook.RetryConfig, &retryConfig); err != nil || retryConfig.MaxAttempts < 0 {
+		return models.DefaultRetryConfig()
+	}
+	return retryConfig
+}
+
+// webhookRetryDelay is the wait after the given number of failed attempts; the last backoff
+// is repeated if there are more retries than backoffs
+func webhookRetryDelay(retryConfig models.RetryConfig, attempts int) time.Duration {
+	if len(retryConfig.BackoffSeconds) == 0 {
+		return jobQueueBackoff(attempts)
+	}
+	i := attempts - 1
+	if i >= len(retryConfig.BackoffSeconds) {
+		i = len(retryConfig.BackoffSeconds) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return time.Duration(retryConfig.BackoffSeconds[i]) * time.Second
+}
+
+// signWebhookPayload creates the hex HMAC-SHA256 signature of a webhook payload
+func signWebhookPayload(payload []byte, secret string) string {
 	mac := hmac.New(sha256.New, []byte(secret))
 	mac.Write(payload)
 	return hex.EncodeToString(mac.Sum(nil))
 }
 
+// setWebhookSignature signs a webhook request with the webhook's secret. The signature is
+// sent in X-AuthGateway-Signature as "sha256=<hex>", and as bare hex in X-Webhook-Signature
+// for receivers written before it.
+func setWebhookSignature(req *http.Request, payload []byte, secret string) {
+	signature := signWebhookPayload(payload, secret)
+	req.Header.Set("X-AuthGateway-Signature", "sha256="+signature)
+	req.Header.Set("X-Webhook-Signature", signature)
+}
+
 // ListWebhookDeliveries lists deliveries for a webhook
 func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, page, perPage int) (*models.WebhookDeliveryListResponse, error) {
 	if page < 1 {
@@ -358,11 +546,13 @@ func (s *WebhookService) TestWebhook(ctx context.Context, id uuid.UUID, req *mod
 		Data:      req.Payload,
 	}
 
+	payload, _ := json.Marshal(event)
+
 	// Use context with timeout for test webhook delivery
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	go func() {
 		defer cancel()
-		s.deliverWebhook(ctx, *webhook, event)
+		s.deliverWebhook(ctx, *webhook, req.EventType, payload)
 	}()
 
 	return nil
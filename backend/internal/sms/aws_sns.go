@@ -65,6 +65,11 @@ func NewAWSSNSProvider(ctx context.Context, cfg AWSSNSConfig) (*AWSSNSProvider,
 
 // SendSMS sends an SMS via AWS SNS
 func (a *AWSSNSProvider) SendSMS(ctx context.Context, to, message string) (string, error) {
+	return a.SendSMSFrom(ctx, a.fromNumber, to, message)
+}
+
+// SendSMSFrom sends an SMS via AWS SNS with the given sender ID
+func (a *AWSSNSProvider) SendSMSFrom(ctx context.Context, from, to, message string) (string, error) {
 	input := &sns.PublishInput{
 		Message:     aws.String(message),
 		PhoneNumber: aws.String(to),
@@ -77,10 +82,10 @@ func (a *AWSSNSProvider) SendSMS(ctx context.Context, to, message string) (strin
 	}
 
 	// Add sender ID if configured
-	if a.fromNumber != "" {
+	if from != "" {
 		input.MessageAttributes["AWS.SNS.SMS.SenderID"] = types.MessageAttributeValue{
 			DataType:    aws.String("String"),
-			StringValue: aws.String(a.fromNumber),
+			StringValue: aws.String(from),
 		}
 	}
 
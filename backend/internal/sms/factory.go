@@ -10,11 +10,27 @@ type ProviderConfig struct {
 	Provider        ProviderType
 	TwilioConfig    *TwilioConfig
 	AWSSNSConfig    *AWSSNSConfig
+	VonageConfig    *VonageConfig
+	SMPPConfig      *SMPPConfig
 	EnableMockInDev bool
+	// Senders maps calling codes such as "+44" to the sender used for that country; optional
+	Senders map[string]string
 }
 
-// NewProvider creates a new SMS provider based on configuration
+// NewProvider creates a new SMS provider based on configuration. When per-country
+// senders are configured the provider is wrapped in a CountryRouter.
 func NewProvider(ctx context.Context, config ProviderConfig) (SMSProvider, error) {
+	provider, err := newProvider(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Senders) == 0 {
+		return provider, nil
+	}
+	return NewCountryRouter(provider, config.Senders)
+}
+
+func newProvider(ctx context.Context, config ProviderConfig) (SMSProvider, error) {
 	// Force mock provider in development if enabled
 	if config.EnableMockInDev && config.Provider == ProviderMock {
 		return NewMockProvider(), nil
@@ -33,6 +49,18 @@ func NewProvider(ctx context.Context, config ProviderConfig) (SMSProvider, error
 		}
 		return NewAWSSNSProvider(ctx, *config.AWSSNSConfig)
 
+	case ProviderVonage:
+		if config.VonageConfig == nil {
+			return nil, fmt.Errorf("%w: Vonage configuration is required", ErrProviderNotConfigured)
+		}
+		return NewVonageProvider(*config.VonageConfig)
+
+	case ProviderSMPP:
+		if config.SMPPConfig == nil {
+			return nil, fmt.Errorf("%w: SMPP configuration is required", ErrProviderNotConfigured)
+		}
+		return NewSMPPProvider(*config.SMPPConfig)
+
 	case ProviderMock:
 		return NewMockProvider(), nil
 
@@ -20,6 +20,7 @@ type MockProvider struct {
 // MockMessage represents a message sent via the mock provider
 type MockMessage struct {
 	ID        string
+	From      string // Empty when sent from the default sender
 	To        string
 	Message   string
 	Timestamp time.Time
@@ -35,6 +36,11 @@ func NewMockProvider() *MockProvider {
 
 // SendSMS sends a mock SMS (just logs it)
 func (m *MockProvider) SendSMS(ctx context.Context, to, message string) (string, error) {
+	return m.SendSMSFrom(ctx, "", to, message)
+}
+
+// SendSMSFrom sends a mock SMS from the given sender (just logs it)
+func (m *MockProvider) SendSMSFrom(ctx context.Context, from, to, message string) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -48,6 +54,7 @@ func (m *MockProvider) SendSMS(ctx context.Context, to, message string) (string,
 	messageID := uuid.New().String()
 	msg := MockMessage{
 		ID:        messageID,
+		From:      from,
 		To:        to,
 		Message:   message,
 		Timestamp: time.Now(),
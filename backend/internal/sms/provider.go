@@ -3,6 +3,7 @@ package sms
 import (
 	"context"
 	"errors"
+	"net/http"
 )
 
 // SMSProvider defines the interface for SMS providers
@@ -17,6 +18,39 @@ type SMSProvider interface {
 	ValidateConfig() error
 }
 
+// SenderProvider is implemented by providers that can send from a sender other than
+// their configured default, such as a country-specific number or alphanumeric sender ID
+type SenderProvider interface {
+	SendSMSFrom(ctx context.Context, from, to, message string) (messageID string, err error)
+}
+
+// DeliveryReporter is implemented by providers that report delivery status to a callback URL
+type DeliveryReporter interface {
+	// ParseDeliveryReport reads a delivery status callback sent by the provider
+	ParseDeliveryReport(r *http.Request) (*DeliveryReport, error)
+}
+
+// DeliveryStatus is the outcome a delivery report gives for a message
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending means the message is still on its way, e.g. queued or sent to the carrier
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// DeliveryReport is a provider's report on a sent message
+type DeliveryReport struct {
+	// MessageID is the ID SendSMS returned for the message
+	MessageID string
+	Status    DeliveryStatus
+	// ProviderStatus is the status as the provider names it, e.g. "undelivered"
+	ProviderStatus string
+	// ErrorCode is the provider or carrier error code for failed messages
+	ErrorCode string
+}
+
 // SMSMessage represents an SMS message to be sent
 type SMSMessage struct {
 	To      string
@@ -47,6 +81,12 @@ var (
 
 	// ErrRateLimitExceeded is returned when rate limit is exceeded
 	ErrRateLimitExceeded = errors.New("sms rate limit exceeded")
+
+	// ErrInvalidDeliveryReport is returned when a delivery status callback cannot be read
+	ErrInvalidDeliveryReport = errors.New("invalid delivery report")
+
+	// ErrDeliveryReportsUnsupported is returned by providers that do not report delivery status
+	ErrDeliveryReportsUnsupported = errors.New("sms provider does not report delivery status")
 )
 
 // ProviderType represents the type of SMS provider
@@ -56,13 +96,14 @@ const (
 	ProviderTwilio ProviderType = "twilio"
 	ProviderAWSSNS ProviderType = "aws_sns"
 	ProviderVonage ProviderType = "vonage"
+	ProviderSMPP   ProviderType = "smpp"
 	ProviderMock   ProviderType = "mock" // For testing
 )
 
 // IsValid checks if the provider type is valid
 func (p ProviderType) IsValid() bool {
 	switch p {
-	case ProviderTwilio, ProviderAWSSNS, ProviderVonage, ProviderMock:
+	case ProviderTwilio, ProviderAWSSNS, ProviderVonage, ProviderSMPP, ProviderMock:
 		return true
 	}
 	return false
@@ -0,0 +1,77 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CountryRouter sends each message from the sender configured for the recipient's
+// country calling code, falling back to the provider's default sender
+type CountryRouter struct {
+	provider SMSProvider
+	// senders maps calling code prefixes such as "+44" to sender numbers or IDs
+	senders map[string]string
+}
+
+// NewCountryRouter wraps a provider with per-country senders. The provider must be able
+// to send from a custom sender.
+func NewCountryRouter(provider SMSProvider, senders map[string]string) (*CountryRouter, error) {
+	if _, ok := provider.(SenderProvider); !ok {
+		return nil, fmt.Errorf("%w: %s does not support per-country senders", ErrProviderNotConfigured, provider.GetProviderName())
+	}
+
+	normalized := make(map[string]string, len(senders))
+	for prefix, sender := range senders {
+		prefix = strings.TrimSpace(prefix)
+		if !strings.HasPrefix(prefix, "+") || len(prefix) < 2 || strings.Trim(prefix[1:], "0123456789") != "" {
+			return nil, fmt.Errorf("%w: invalid calling code %q", ErrProviderNotConfigured, prefix)
+		}
+		if strings.TrimSpace(sender) == "" {
+			return nil, fmt.Errorf("%w: empty sender for %s", ErrProviderNotConfigured, prefix)
+		}
+		normalized[prefix] = strings.TrimSpace(sender)
+	}
+
+	return &CountryRouter{provider: provider, senders: normalized}, nil
+}
+
+// SendSMS sends an SMS from the sender configured for the recipient's country
+func (r *CountryRouter) SendSMS(ctx context.Context, to, message string) (string, error) {
+	if sender, ok := r.SenderFor(to); ok {
+		return r.provider.(SenderProvider).SendSMSFrom(ctx, sender, to, message)
+	}
+	return r.provider.SendSMS(ctx, to, message)
+}
+
+// SenderFor returns the sender for a phone number in E.164 format. The longest matching
+// calling code wins, so "+1684" takes precedence over "+1".
+func (r *CountryRouter) SenderFor(to string) (string, bool) {
+	var sender, matched string
+	for prefix, candidate := range r.senders {
+		if strings.HasPrefix(to, prefix) && len(prefix) > len(matched) {
+			matched, sender = prefix, candidate
+		}
+	}
+	return sender, matched != ""
+}
+
+// GetProviderName returns the name of the wrapped provider
+func (r *CountryRouter) GetProviderName() string {
+	return r.provider.GetProviderName()
+}
+
+// ValidateConfig validates the wrapped provider configuration
+func (r *CountryRouter) ValidateConfig() error {
+	return r.provider.ValidateConfig()
+}
+
+// ParseDeliveryReport delegates to the wrapped provider
+func (r *CountryRouter) ParseDeliveryReport(req *http.Request) (*DeliveryReport, error) {
+	reporter, ok := r.provider.(DeliveryReporter)
+	if !ok {
+		return nil, ErrDeliveryReportsUnsupported
+	}
+	return reporter.ParseDeliveryReport(req)
+}
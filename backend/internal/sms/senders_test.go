@@ -0,0 +1,74 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountryRouter_SendSMS(t *testing.T) {
+	mock := NewMockProvider()
+	router, err := NewCountryRouter(mock, map[string]string{
+		"+1":    "+15550001111",
+		"+1684": "+16845550000",
+		"+44":   "AuthGW",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		to   string
+		from string
+	}{
+		{"+447700900123", "AuthGW"},
+		{"+12025550123", "+15550001111"},
+		{"+16845550123", "+16845550000"},
+		{"+4915112345678", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			_, err := router.SendSMS(context.Background(), tt.to, "code")
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, mock.GetLastMessage().From)
+		})
+	}
+}
+
+func TestNewCountryRouter_RejectsInvalidSenders(t *testing.T) {
+	_, err := NewCountryRouter(NewMockProvider(), map[string]string{"44": "AuthGW"})
+	assert.True(t, errors.Is(err, ErrProviderNotConfigured))
+
+	_, err = NewCountryRouter(NewMockProvider(), map[string]string{"+44": " "})
+	assert.True(t, errors.Is(err, ErrProviderNotConfigured))
+}
+
+func TestCountryRouter_ParseDeliveryReport(t *testing.T) {
+	t.Run("Delegates to the provider", func(t *testing.T) {
+		twilio, err := NewTwilioProvider(TwilioConfig{AccountSID: "AC123", AuthToken: "token", FromNumber: "+15550001111"})
+		require.NoError(t, err)
+		router, err := NewCountryRouter(twilio, map[string]string{"+44": "AuthGW"})
+		require.NoError(t, err)
+
+		form := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+		r := httptest.NewRequest(http.MethodPost, "/api/sms/status/twilio", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		report, err := router.ParseDeliveryReport(r)
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{MessageID: "SM123", Status: DeliveryStatusFailed, ProviderStatus: "undelivered", ErrorCode: "30003"}, report)
+	})
+
+	t.Run("Provider without delivery reports", func(t *testing.T) {
+		router, err := NewCountryRouter(NewMockProvider(), map[string]string{"+44": "AuthGW"})
+		require.NoError(t, err)
+
+		_, err = router.ParseDeliveryReport(httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.True(t, errors.Is(err, ErrDeliveryReportsUnsupported))
+	})
+}
@@ -0,0 +1,346 @@
+package sms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// SMPP 3.4 command IDs
+const (
+	smppGenericNack         uint32 = 0x80000000
+	smppBindTransmitter     uint32 = 0x00000002
+	smppBindTransmitterResp uint32 = 0x80000002
+	smppSubmitSM            uint32 = 0x00000004
+	smppSubmitSMResp        uint32 = 0x80000004
+	smppUnbind              uint32 = 0x00000006
+	smppUnbindResp          uint32 = 0x80000006
+	smppEnquireLink         uint32 = 0x00000015
+	smppEnquireLinkResp     uint32 = 0x80000015
+)
+
+const (
+	smppInterfaceVersion  = 0x34
+	smppStatusThrottled   = 0x00000058
+	smppMaxShortMessage   = 254
+	smppTagMessagePayload = 0x0424
+	smppMaxPDULength      = 64 << 10
+	smppDefaultTimeout    = 30 * time.Second
+
+	smppCodingIA5  = 0x01 // ASCII
+	smppCodingUCS2 = 0x08
+
+	smppTONInternational = 0x01
+	smppTONAlphanumeric  = 0x05
+	smppNPIISDN          = 0x01
+	smppNPIUnknown       = 0x00
+)
+
+// SMPPProvider implements SMSProvider for any SMSC that speaks SMPP 3.4. Each message
+// opens a transmitter session, so delivery receipts, which arrive on receiver sessions,
+// are not collected.
+type SMPPProvider struct {
+	addr       string
+	systemID   string
+	password   string
+	systemType string
+	sourceAddr string
+	useTLS     bool
+	timeout    time.Duration
+}
+
+// SMPPConfig holds SMPP configuration
+type SMPPConfig struct {
+	// Addr is the SMSC address as host:port
+	Addr       string
+	SystemID   string
+	Password   string
+	SystemType string // Optional: identifies the type of ESME to the SMSC
+	// SourceAddr is the default sender: a number in international format or an alphanumeric ID
+	SourceAddr string
+	UseTLS     bool
+	// Timeout bounds a whole send when the context has no deadline; defaults to 30s
+	Timeout time.Duration
+}
+
+// NewSMPPProvider creates a new SMPP SMS provider
+func NewSMPPProvider(config SMPPConfig) (*SMPPProvider, error) {
+	provider := &SMPPProvider{
+		addr:       config.Addr,
+		systemID:   config.SystemID,
+		password:   config.Password,
+		systemType: config.SystemType,
+		sourceAddr: config.SourceAddr,
+		useTLS:     config.UseTLS,
+		timeout:    config.Timeout,
+	}
+	if provider.timeout <= 0 {
+		provider.timeout = smppDefaultTimeout
+	}
+
+	if err := provider.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// SendSMS sends an SMS over SMPP
+func (p *SMPPProvider) SendSMS(ctx context.Context, to, message string) (string, error) {
+	return p.SendSMSFrom(ctx, p.sourceAddr, to, message)
+}
+
+// SendSMSFrom sends an SMS over SMPP from the given number or sender ID
+func (p *SMPPProvider) SendSMSFrom(ctx context.Context, from, to, message string) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	conn, err := p.dial(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+
+	session := &smppSession{conn: conn, reader: bufio.NewReader(conn)}
+
+	if _, err := session.call(smppBindTransmitter, p.bindBody(), smppBindTransmitterResp); err != nil {
+		return "", fmt.Errorf("%w: bind failed: %v", ErrProviderUnavailable, err)
+	}
+
+	resp, err := session.call(smppSubmitSM, submitSMBody(from, to, message), smppSubmitSMResp)
+	if err != nil {
+		var statusErr *smppStatusError
+		if errors.As(err, &statusErr) && statusErr.status == smppStatusThrottled {
+			return "", fmt.Errorf("%w: smsc throttled the request", ErrRateLimitExceeded)
+		}
+		return "", fmt.Errorf("%w: %v", ErrSendFailed, err)
+	}
+
+	messageID, _ := readCString(bytes.NewReader(resp))
+
+	// The message is accepted; a failed unbind does not change that
+	_, _ = session.call(smppUnbind, nil, smppUnbindResp)
+
+	return messageID, nil
+}
+
+// GetProviderName returns the provider name
+func (p *SMPPProvider) GetProviderName() string {
+	return string(ProviderSMPP)
+}
+
+// ValidateConfig validates the SMPP configuration
+func (p *SMPPProvider) ValidateConfig() error {
+	if p.addr == "" {
+		return fmt.Errorf("%w: SMSC address is required", ErrProviderNotConfigured)
+	}
+	if _, _, err := net.SplitHostPort(p.addr); err != nil {
+		return fmt.Errorf("%w: SMSC address must be host:port", ErrProviderNotConfigured)
+	}
+	if p.systemID == "" {
+		return fmt.Errorf("%w: system ID is required", ErrProviderNotConfigured)
+	}
+	if p.sourceAddr == "" {
+		return fmt.Errorf("%w: source address is required", ErrProviderNotConfigured)
+	}
+	return nil
+}
+
+func (p *SMPPProvider) dial(ctx context.Context) (net.Conn, error) {
+	if p.useTLS {
+		host, _, _ := net.SplitHostPort(p.addr)
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		return dialer.DialContext(ctx, "tcp", p.addr)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", p.addr)
+}
+
+func (p *SMPPProvider) bindBody() []byte {
+	var body bytes.Buffer
+	writeCString(&body, p.systemID)
+	writeCString(&body, p.password)
+	writeCString(&body, p.systemType)
+	body.WriteByte(smppInterfaceVersion)
+	body.WriteByte(0) // addr_ton
+	body.WriteByte(0) // addr_npi
+	writeCString(&body, "")
+	return body.Bytes()
+}
+
+// submitSMBody builds a submit_sm body. ASCII text is sent as IA5, anything else as UCS-2.
+// Text longer than a short message travels in the message_payload TLV.
+func submitSMBody(from, to, message string) []byte {
+	coding := byte(smppCodingIA5)
+	text := []byte(message)
+	if !isASCII(message) {
+		coding = smppCodingUCS2
+		text = encodeUCS2(message)
+	}
+
+	var body bytes.Buffer
+	writeCString(&body, "") // service_type
+	sourceTON, sourceNPI, source := smppAddress(from)
+	body.WriteByte(sourceTON)
+	body.WriteByte(sourceNPI)
+	writeCString(&body, source)
+	body.WriteByte(smppTONInternational)
+	body.WriteByte(smppNPIISDN)
+	writeCString(&body, strings.TrimPrefix(to, "+"))
+	body.WriteByte(0)       // esm_class
+	body.WriteByte(0)       // protocol_id
+	body.WriteByte(0)       // priority_flag
+	writeCString(&body, "") // schedule_delivery_time
+	writeCString(&body, "") // validity_period
+	body.WriteByte(0)       // registered_delivery
+	body.WriteByte(0)       // replace_if_present_flag
+	body.WriteByte(coding)
+	body.WriteByte(0) // sm_default_msg_id
+
+	if len(text) <= smppMaxShortMessage {
+		body.WriteByte(byte(len(text)))
+		body.Write(text)
+	} else {
+		body.WriteByte(0)
+		_ = binary.Write(&body, binary.BigEndian, uint16(smppTagMessagePayload))
+		_ = binary.Write(&body, binary.BigEndian, uint16(len(text)))
+		body.Write(text)
+	}
+	return body.Bytes()
+}
+
+// smppAddress returns the TON, NPI and digits of a sender: numbers are international,
+// anything else an alphanumeric sender ID
+func smppAddress(addr string) (byte, byte, string) {
+	digits := strings.TrimPrefix(addr, "+")
+	if digits != "" && strings.Trim(digits, "0123456789") == "" {
+		return smppTONInternational, smppNPIISDN, digits
+	}
+	return smppTONAlphanumeric, smppNPIUnknown, addr
+}
+
+// smppSession exchanges PDUs with an SMSC over one connection
+type smppSession struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	sequence uint32
+}
+
+// smppStatusError is a response with a non-zero command_status
+type smppStatusError struct {
+	status uint32
+}
+
+func (e *smppStatusError) Error() string {
+	return fmt.Sprintf("smsc returned command status 0x%08X", e.status)
+}
+
+// call sends a request and waits for its response, answering keep-alives from the SMSC meanwhile
+func (s *smppSession) call(commandID uint32, body []byte, respID uint32) ([]byte, error) {
+	s.sequence++
+	sequence := s.sequence
+	if err := s.write(commandID, 0, sequence, body); err != nil {
+		return nil, err
+	}
+
+	for {
+		id, status, seq, respBody, err := s.read()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case id == smppEnquireLink:
+			if err := s.write(smppEnquireLinkResp, 0, seq, nil); err != nil {
+				return nil, err
+			}
+		case (id == respID || id == smppGenericNack) && seq == sequence:
+			if status != 0 {
+				return nil, &smppStatusError{status: status}
+			}
+			if id == smppGenericNack {
+				return nil, fmt.Errorf("smsc rejected command 0x%08X", commandID)
+			}
+			return respBody, nil
+		}
+	}
+}
+
+func (s *smppSession) write(commandID, status, sequence uint32, body []byte) error {
+	pdu := make([]byte, 16, 16+len(body))
+	binary.BigEndian.PutUint32(pdu[0:], uint32(16+len(body)))
+	binary.BigEndian.PutUint32(pdu[4:], commandID)
+	binary.BigEndian.PutUint32(pdu[8:], status)
+	binary.BigEndian.PutUint32(pdu[12:], sequence)
+	_, err := s.conn.Write(append(pdu, body...))
+	return err
+}
+
+func (s *smppSession) read() (commandID, status, sequence uint32, body []byte, err error) {
+	header := make([]byte, 16)
+	if _, err = io.ReadFull(s.reader, header); err != nil {
+		return 0, 0, 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[0:])
+	if length < 16 || length > smppMaxPDULength {
+		return 0, 0, 0, nil, fmt.Errorf("invalid PDU length %d", length)
+	}
+	body = make([]byte, length-16)
+	if _, err = io.ReadFull(s.reader, body); err != nil {
+		return 0, 0, 0, nil, err
+	}
+	return binary.BigEndian.Uint32(header[4:]), binary.BigEndian.Uint32(header[8:]), binary.BigEndian.Uint32(header[12:]), body, nil
+}
+
+func writeCString(buf *bytes.Buffer, value string) {
+	buf.WriteString(value)
+	buf.WriteByte(0)
+}
+
+func readCString(r *bytes.Reader) (string, error) {
+	var value []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return string(value), err
+		}
+		if b == 0 {
+			return string(value), nil
+		}
+		value = append(value, b)
+	}
+}
+
+func isASCII(message string) bool {
+	for i := 0; i < len(message); i++ {
+		if message[i] > 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeUCS2(message string) []byte {
+	units := utf16.Encode([]rune(message))
+	encoded := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.BigEndian.PutUint16(encoded[2*i:], unit)
+	}
+	return encoded
+}
@@ -0,0 +1,161 @@
+package sms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMSC accepts one SMPP session and answers binds, submits and unbinds
+type fakeSMSC struct {
+	listener     net.Listener
+	submitStatus uint32
+	enquireFirst bool
+	bind         chan []byte
+	submit       chan []byte
+}
+
+func newFakeSMSC(t *testing.T) *fakeSMSC {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	smsc := &fakeSMSC{listener: listener, bind: make(chan []byte, 1), submit: make(chan []byte, 1)}
+	go smsc.serve()
+	return smsc
+}
+
+func (f *fakeSMSC) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &smppSession{conn: conn, reader: bufio.NewReader(conn)}
+	for {
+		id, _, seq, body, err := session.read()
+		if err != nil {
+			return
+		}
+		switch id {
+		case smppBindTransmitter:
+			f.bind <- body
+			_ = session.write(smppBindTransmitterResp, 0, seq, []byte("SMSC\x00"))
+		case smppSubmitSM:
+			f.submit <- body
+			if f.enquireFirst {
+				_ = session.write(smppEnquireLink, 0, 99, nil)
+				if id, _, _, _, err := session.read(); err != nil || id != smppEnquireLinkResp {
+					return
+				}
+			}
+			if f.submitStatus != 0 {
+				_ = session.write(smppSubmitSMResp, f.submitStatus, seq, nil)
+				continue
+			}
+			_ = session.write(smppSubmitSMResp, 0, seq, []byte("msg-42\x00"))
+		case smppUnbind:
+			_ = session.write(smppUnbindResp, 0, seq, nil)
+			return
+		}
+	}
+}
+
+func newTestSMPPProvider(t *testing.T, addr string) *SMPPProvider {
+	t.Helper()
+	provider, err := NewSMPPProvider(SMPPConfig{
+		Addr:       addr,
+		SystemID:   "gateway",
+		Password:   "secret",
+		SourceAddr: "+15550001111",
+		Timeout:    5 * time.Second,
+	})
+	require.NoError(t, err)
+	return provider
+}
+
+func TestSMPPProvider_SendSMS(t *testing.T) {
+	smsc := newFakeSMSC(t)
+	smsc.enquireFirst = true
+	provider := newTestSMPPProvider(t, smsc.listener.Addr().String())
+
+	messageID, err := provider.SendSMS(context.Background(), "+447700900123", "Your code is 123456")
+	require.NoError(t, err)
+	assert.Equal(t, "msg-42", messageID)
+
+	bind := <-smsc.bind
+	assert.True(t, bytes.HasPrefix(bind, []byte("gateway\x00secret\x00\x00\x34")))
+
+	submit := bytes.NewReader(<-smsc.submit)
+	serviceType, _ := readCString(submit)
+	assert.Empty(t, serviceType)
+	ton, _ := submit.ReadByte()
+	npi, _ := submit.ReadByte()
+	source, _ := readCString(submit)
+	assert.Equal(t, []byte{smppTONInternational, smppNPIISDN}, []byte{ton, npi})
+	assert.Equal(t, "15550001111", source)
+	_, _ = submit.ReadByte()
+	_, _ = submit.ReadByte()
+	destination, _ := readCString(submit)
+	assert.Equal(t, "447700900123", destination)
+}
+
+func TestSMPPProvider_SendSMS_Throttled(t *testing.T) {
+	smsc := newFakeSMSC(t)
+	smsc.submitStatus = smppStatusThrottled
+	provider := newTestSMPPProvider(t, smsc.listener.Addr().String())
+
+	_, err := provider.SendSMS(context.Background(), "+447700900123", "Your code is 123456")
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+}
+
+func TestSMPPProvider_SendSMS_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	provider := newTestSMPPProvider(t, addr)
+	_, err = provider.SendSMS(context.Background(), "+447700900123", "Your code is 123456")
+	assert.True(t, errors.Is(err, ErrProviderUnavailable))
+}
+
+func TestSubmitSMBody(t *testing.T) {
+	t.Run("Alphanumeric sender", func(t *testing.T) {
+		body := submitSMBody("AuthGW", "+15551234567", "code")
+		assert.True(t, bytes.HasPrefix(body, []byte{0, smppTONAlphanumeric, smppNPIUnknown, 'A', 'u', 't', 'h', 'G', 'W', 0}))
+	})
+
+	t.Run("ASCII text is sent as IA5", func(t *testing.T) {
+		body := submitSMBody("AuthGW", "+15551234567", "code")
+		assert.True(t, bytes.HasSuffix(body, []byte{smppCodingIA5, 0, 4, 'c', 'o', 'd', 'e'}))
+	})
+
+	t.Run("Other text is sent as UCS-2", func(t *testing.T) {
+		body := submitSMBody("AuthGW", "+15551234567", "Код")
+		assert.True(t, bytes.HasSuffix(body, []byte{smppCodingUCS2, 0, 6, 0x04, 0x1A, 0x04, 0x3E, 0x04, 0x34}))
+	})
+
+	t.Run("Long text uses message_payload", func(t *testing.T) {
+		text := string(bytes.Repeat([]byte("a"), 300))
+		body := submitSMBody("AuthGW", "+15551234567", text)
+		assert.True(t, bytes.HasSuffix(body, append([]byte{smppCodingIA5, 0, 0, 0x04, 0x24, 0x01, 0x2C}, text...)))
+	})
+}
+
+func TestNewSMPPProvider_ValidatesConfig(t *testing.T) {
+	_, err := NewSMPPProvider(SMPPConfig{Addr: "smsc.example.com", SystemID: "gateway", SourceAddr: "AuthGW"})
+	assert.True(t, errors.Is(err, ErrProviderNotConfigured))
+
+	_, err = NewSMPPProvider(SMPPConfig{Addr: "smsc.example.com:2775", SourceAddr: "AuthGW"})
+	assert.True(t, errors.Is(err, ErrProviderNotConfigured))
+}
@@ -13,10 +13,11 @@ import (
 
 // TwilioProvider implements SMSProvider for Twilio
 type TwilioProvider struct {
-	accountSID string
-	authToken  string
-	fromNumber string
-	httpClient *http.Client
+	accountSID        string
+	authToken         string
+	fromNumber        string
+	statusCallbackURL string
+	httpClient        *http.Client
 }
 
 // TwilioConfig holds Twilio configuration
@@ -24,14 +25,17 @@ type TwilioConfig struct {
 	AccountSID string
 	AuthToken  string
 	FromNumber string
+	// StatusCallbackURL receives delivery status updates; optional
+	StatusCallbackURL string
 }
 
 // NewTwilioProvider creates a new Twilio SMS provider
 func NewTwilioProvider(config TwilioConfig) (*TwilioProvider, error) {
 	provider := &TwilioProvider{
-		accountSID: config.AccountSID,
-		authToken:  config.AuthToken,
-		fromNumber: config.FromNumber,
+		accountSID:        config.AccountSID,
+		authToken:         config.AuthToken,
+		fromNumber:        config.FromNumber,
+		statusCallbackURL: config.StatusCallbackURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -46,12 +50,20 @@ func NewTwilioProvider(config TwilioConfig) (*TwilioProvider, error) {
 
 // SendSMS sends an SMS via Twilio
 func (t *TwilioProvider) SendSMS(ctx context.Context, to, message string) (string, error) {
+	return t.SendSMSFrom(ctx, t.fromNumber, to, message)
+}
+
+// SendSMSFrom sends an SMS via Twilio from the given number or sender ID
+func (t *TwilioProvider) SendSMSFrom(ctx context.Context, from, to, message string) (string, error) {
 	apiURL := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", t.accountSID)
 
 	data := url.Values{}
 	data.Set("To", to)
-	data.Set("From", t.fromNumber)
+	data.Set("From", from)
 	data.Set("Body", message)
+	if t.statusCallbackURL != "" {
+		data.Set("StatusCallback", t.statusCallbackURL)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -113,3 +125,30 @@ func (t *TwilioProvider) ValidateConfig() error {
 	}
 	return nil
 }
+
+// ParseDeliveryReport reads a Twilio message status callback
+func (t *TwilioProvider) ParseDeliveryReport(r *http.Request) (*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeliveryReport, err)
+	}
+
+	messageID := r.PostForm.Get("MessageSid")
+	providerStatus := r.PostForm.Get("MessageStatus")
+	if messageID == "" || providerStatus == "" {
+		return nil, fmt.Errorf("%w: MessageSid and MessageStatus are required", ErrInvalidDeliveryReport)
+	}
+
+	report := &DeliveryReport{
+		MessageID:      messageID,
+		ProviderStatus: providerStatus,
+		ErrorCode:      r.PostForm.Get("ErrorCode"),
+		Status:         DeliveryStatusPending,
+	}
+	switch providerStatus {
+	case "delivered", "read":
+		report.Status = DeliveryStatusDelivered
+	case "undelivered", "failed", "canceled":
+		report.Status = DeliveryStatusFailed
+	}
+	return report, nil
+}
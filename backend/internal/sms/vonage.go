@@ -0,0 +1,209 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const vonageSMSURL = "https://rest.nexmo.com/sms/json"
+
+// VonageProvider implements SMSProvider for the Vonage (formerly Nexmo) SMS API
+type VonageProvider struct {
+	apiKey            string
+	apiSecret         string
+	fromNumber        string
+	statusCallbackURL string
+	apiURL            string
+	httpClient        *http.Client
+}
+
+// VonageConfig holds Vonage configuration
+type VonageConfig struct {
+	APIKey    string
+	APISecret string
+	// FromNumber is a number or an alphanumeric sender ID
+	FromNumber string
+	// StatusCallbackURL receives delivery receipts; optional
+	StatusCallbackURL string
+}
+
+// NewVonageProvider creates a new Vonage SMS provider
+func NewVonageProvider(config VonageConfig) (*VonageProvider, error) {
+	provider := &VonageProvider{
+		apiKey:            config.APIKey,
+		apiSecret:         config.APISecret,
+		fromNumber:        config.FromNumber,
+		statusCallbackURL: config.StatusCallbackURL,
+		apiURL:            vonageSMSURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	if err := provider.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// SendSMS sends an SMS via Vonage
+func (v *VonageProvider) SendSMS(ctx context.Context, to, message string) (string, error) {
+	return v.SendSMSFrom(ctx, v.fromNumber, to, message)
+}
+
+// SendSMSFrom sends an SMS via Vonage from the given number or sender ID
+func (v *VonageProvider) SendSMSFrom(ctx context.Context, from, to, message string) (string, error) {
+	data := url.Values{}
+	data.Set("api_key", v.apiKey)
+	data.Set("api_secret", v.apiSecret)
+	data.Set("from", from)
+	// Vonage expects the number in international format without the leading +
+	data.Set("to", strings.TrimPrefix(to, "+"))
+	data.Set("text", message)
+	if !isGSM7(message) {
+		data.Set("type", "unicode")
+	}
+	if v.statusCallbackURL != "" {
+		data.Set("callback", v.statusCallbackURL)
+		data.Set("status-report-req", "1")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", v.apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSendFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read response: %v", ErrSendFailed, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: vonage returned status %d: %s", ErrSendFailed, resp.StatusCode, string(body))
+	}
+
+	// Vonage answers 200 and reports errors per message; status "0" means accepted
+	var vonageResp struct {
+		Messages []struct {
+			MessageID string `json:"message-id"`
+			Status    string `json:"status"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}
+
+	if err := json.Unmarshal(body, &vonageResp); err != nil {
+		return "", fmt.Errorf("%w: failed to parse response: %v", ErrSendFailed, err)
+	}
+
+	if len(vonageResp.Messages) == 0 {
+		return "", fmt.Errorf("%w: vonage returned no messages", ErrSendFailed)
+	}
+
+	// A long text is split into parts; the first part identifies the message
+	first := vonageResp.Messages[0]
+	switch first.Status {
+	case "0":
+		return first.MessageID, nil
+	case "1":
+		return "", fmt.Errorf("%w: vonage throttled the request", ErrRateLimitExceeded)
+	default:
+		return "", fmt.Errorf("%w: vonage error %s: %s", ErrSendFailed, first.Status, first.ErrorText)
+	}
+}
+
+// GetProviderName returns the provider name
+func (v *VonageProvider) GetProviderName() string {
+	return string(ProviderVonage)
+}
+
+// ValidateConfig validates the Vonage configuration
+func (v *VonageProvider) ValidateConfig() error {
+	if v.apiKey == "" {
+		return fmt.Errorf("%w: API key is required", ErrProviderNotConfigured)
+	}
+	if v.apiSecret == "" {
+		return fmt.Errorf("%w: API secret is required", ErrProviderNotConfigured)
+	}
+	if v.fromNumber == "" {
+		return fmt.Errorf("%w: from number is required", ErrProviderNotConfigured)
+	}
+	return nil
+}
+
+// ParseDeliveryReport reads a Vonage delivery receipt. Vonage sends receipts as GET query
+// parameters, form posts or JSON posts depending on the account settings.
+func (v *VonageProvider) ParseDeliveryReport(r *http.Request) (*DeliveryReport, error) {
+	fields := map[string]string{}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Method == http.MethodPost && mediaType == "application/json" {
+		var body map[string]interface{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&body); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDeliveryReport, err)
+		}
+		for key, value := range body {
+			if value != nil {
+				fields[key] = fmt.Sprint(value)
+			}
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDeliveryReport, err)
+		}
+		for key := range r.Form {
+			fields[key] = r.Form.Get(key)
+		}
+	}
+
+	messageID := fields["messageId"]
+	providerStatus := fields["status"]
+	if messageID == "" || providerStatus == "" {
+		return nil, fmt.Errorf("%w: messageId and status are required", ErrInvalidDeliveryReport)
+	}
+
+	report := &DeliveryReport{
+		MessageID:      messageID,
+		ProviderStatus: providerStatus,
+		Status:         DeliveryStatusPending,
+	}
+	switch providerStatus {
+	case "delivered":
+		report.Status = DeliveryStatusDelivered
+	case "failed", "expired", "rejected":
+		report.Status = DeliveryStatusFailed
+	}
+	// err-code 0 means no error
+	if code := fields["err-code"]; code != "" && code != "0" {
+		report.ErrorCode = code
+	}
+	return report, nil
+}
+
+// isGSM7 reports whether a message fits the GSM 03.38 default alphabet, so it can be sent
+// without switching to UCS-2
+func isGSM7(message string) bool {
+	const gsm7 = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà" +
+		"^{}\\[~]|€\f"
+	for _, r := range message {
+		if !strings.ContainsRune(gsm7, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVonageProvider(t *testing.T, handler http.HandlerFunc) *VonageProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := NewVonageProvider(VonageConfig{
+		APIKey:            "key",
+		APISecret:         "secret",
+		FromNumber:        "AuthGW",
+		StatusCallbackURL: "https://auth.example.com/api/sms/status/vonage?token=t",
+	})
+	require.NoError(t, err)
+	provider.apiURL = server.URL
+	return provider
+}
+
+func TestVonageProvider_SendSMSFrom(t *testing.T) {
+	var form url.Values
+	provider := newTestVonageProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		w.Write([]byte(`{"message-count":"1","messages":[{"message-id":"0A000000123","status":"0"}]}`))
+	})
+
+	messageID, err := provider.SendSMSFrom(context.Background(), "+447700900000", "+447700900123", "Ваш код 123456")
+	require.NoError(t, err)
+	assert.Equal(t, "0A000000123", messageID)
+	assert.Equal(t, "+447700900000", form.Get("from"))
+	assert.Equal(t, "447700900123", form.Get("to"))
+	assert.Equal(t, "unicode", form.Get("type"))
+	assert.Equal(t, "1", form.Get("status-report-req"))
+	assert.Equal(t, "https://auth.example.com/api/sms/status/vonage?token=t", form.Get("callback"))
+}
+
+func TestVonageProvider_SendSMS_Errors(t *testing.T) {
+	t.Run("Throttled", func(t *testing.T) {
+		provider := newTestVonageProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"messages":[{"status":"1","error-text":"Throttled"}]}`))
+		})
+		_, err := provider.SendSMS(context.Background(), "+447700900123", "code")
+		assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		provider := newTestVonageProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"messages":[{"status":"4","error-text":"Bad Credentials"}]}`))
+		})
+		_, err := provider.SendSMS(context.Background(), "+447700900123", "code")
+		assert.True(t, errors.Is(err, ErrSendFailed))
+		assert.Contains(t, err.Error(), "Bad Credentials")
+	})
+}
+
+func TestVonageProvider_ParseDeliveryReport(t *testing.T) {
+	provider := &VonageProvider{}
+
+	t.Run("Query parameters", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/sms/status/vonage?messageId=0A000000123&status=delivered&err-code=0", nil)
+		report, err := provider.ParseDeliveryReport(r)
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryReport{MessageID: "0A000000123", Status: DeliveryStatusDelivered, ProviderStatus: "delivered"}, report)
+	})
+
+	t.Run("JSON body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/api/sms/status/vonage", strings.NewReader(`{"messageId":"0A000000123","status":"rejected","err-code":"6"}`))
+		r.Header.Set("Content-Type", "application/json")
+		report, err := provider.ParseDeliveryReport(r)
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryStatusFailed, report.Status)
+		assert.Equal(t, "6", report.ErrorCode)
+	})
+
+	t.Run("Missing message ID", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/sms/status/vonage?status=delivered", nil)
+		_, err := provider.ParseDeliveryReport(r)
+		assert.True(t, errors.Is(err, ErrInvalidDeliveryReport))
+	})
+}
@@ -0,0 +1,21 @@
+// Sends the user back to the application once the front-channel logout frames have loaded,
+// or after a few seconds if a client does not answer
+(function () {
+    var container = document.querySelector('[data-redirect-uri]');
+    if (!container) {
+        return;
+    }
+
+    var target = container.getAttribute('data-redirect-uri');
+    var redirected = false;
+    function redirect() {
+        if (redirected) {
+            return;
+        }
+        redirected = true;
+        window.location.assign(target);
+    }
+
+    window.addEventListener('load', redirect);
+    setTimeout(redirect, 5000);
+})();
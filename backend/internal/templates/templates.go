@@ -0,0 +1,141 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// AssetPathPrefix is the URL prefix under which hashed assets are served
+const AssetPathPrefix = "/assets/"
+
+//go:embed *.html
+var htmlFS embed.FS
+
+//go:embed assets
+var assetFS embed.FS
+
+// Asset describes a single embedded static asset
+type Asset struct {
+	Name        string // Logical name, e.g. "consent.css"
+	HashedName  string // Content-addressed name, e.g. "consent.3f2a9c1b.css"
+	Integrity   string // SRI value, e.g. "sha384-..."
+	ContentType string
+	Content     []byte
+}
+
+// Pipeline holds embedded HTML templates together with their hashed static assets
+type Pipeline struct {
+	byName   map[string]*Asset
+	byHashed map[string]*Asset
+}
+
+// NewPipeline loads all embedded assets and computes their hashes and SRI values
+func NewPipeline() (*Pipeline, error) {
+	p := &Pipeline{
+		byName:   make(map[string]*Asset),
+		byHashed: make(map[string]*Asset),
+	}
+
+	err := fs.WalkDir(assetFS, "assets", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := assetFS.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read asset %s: %w", filePath, err)
+		}
+
+		asset := newAsset(strings.TrimPrefix(filePath, "assets/"), content)
+		p.byName[asset.Name] = asset
+		p.byHashed[asset.HashedName] = asset
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func newAsset(name string, content []byte) *Asset {
+	digest := sha256.Sum256(content)
+	sri := sha512.Sum384(content)
+
+	ext := path.Ext(name)
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &Asset{
+		Name:        name,
+		HashedName:  strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(digest[:])[:12] + ext,
+		Integrity:   "sha384-" + base64.StdEncoding.EncodeToString(sri[:]),
+		ContentType: contentType,
+		Content:     content,
+	}
+}
+
+// URL returns the hashed URL for the named asset
+func (p *Pipeline) URL(name string) (string, error) {
+	asset, ok := p.byName[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset: %s", name)
+	}
+	return AssetPathPrefix + asset.HashedName, nil
+}
+
+// Integrity returns the SRI attribute value for the named asset
+func (p *Pipeline) Integrity(name string) (string, error) {
+	asset, ok := p.byName[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset: %s", name)
+	}
+	return asset.Integrity, nil
+}
+
+// FuncMap returns template helpers for referencing assets
+func (p *Pipeline) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"asset":     p.URL,
+		"integrity": p.Integrity,
+	}
+}
+
+// Templates parses the embedded HTML templates with asset helpers registered
+func (p *Pipeline) Templates() (*template.Template, error) {
+	return template.New("").Funcs(p.FuncMap()).ParseFS(htmlFS, "*.html")
+}
+
+// ServeHTTP serves hashed assets. Content is immutable per URL, so it is cached aggressively.
+func (p *Pipeline) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	asset, ok := p.byHashed[path.Base(r.URL.Path)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", asset.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+strings.TrimPrefix(asset.Integrity, "sha384-")+`"`)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(asset.Content)
+}
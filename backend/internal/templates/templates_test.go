@@ -0,0 +1,75 @@
+package templates
+
+import (
+	"bytes"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_HashedURLAndIntegrity(t *testing.T) {
+	p, err := NewPipeline()
+	require.NoError(t, err)
+
+	url, err := p.URL("consent.css")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, AssetPathPrefix+"consent."))
+	assert.True(t, strings.HasSuffix(url, ".css"))
+
+	sri, err := p.Integrity("consent.css")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sri, "sha384-"))
+
+	_, err = p.URL("missing.css")
+	assert.Error(t, err)
+}
+
+func TestPipeline_TemplatesRenderAssetLinks(t *testing.T) {
+	p, err := NewPipeline()
+	require.NoError(t, err)
+
+	tmpl, err := p.Templates()
+	require.NoError(t, err)
+
+	for _, name := range []string{"consent.html", "device.html", "error.html", "logout.html"} {
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.ExecuteTemplate(&buf, name, map[string]interface{}{}), name)
+
+		// html/template entity-encodes "+" in attributes; browsers decode it before SRI checks
+		rendered := html.UnescapeString(buf.String())
+		css := strings.TrimSuffix(name, ".html") + ".css"
+		url, _ := p.URL(css)
+		sri, _ := p.Integrity(css)
+		assert.Contains(t, rendered, `href="`+url+`"`, name)
+		assert.Contains(t, rendered, `integrity="`+sri+`"`, name)
+		assert.NotContains(t, rendered, "<style>", name)
+	}
+}
+
+func TestPipeline_ServeHTTP(t *testing.T) {
+	p, err := NewPipeline()
+	require.NoError(t, err)
+
+	url, err := p.URL("error.css")
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/css")
+	assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+	assert.NotEmpty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, AssetPathPrefix+"error.css", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
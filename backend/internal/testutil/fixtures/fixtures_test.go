@@ -0,0 +1,65 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+func TestUserBuilder_ShouldBeDeterministic(t *testing.T) {
+	first := NewUserBuilder().WithName("alice").WithRoles("admin", "user").Build()
+	second := NewUserBuilder().WithName("alice").WithRoles("admin", "user").Build()
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, ID("user", "alice"), first.ID)
+	assert.Equal(t, "alice@example.com", first.Email)
+	assert.Equal(t, Epoch, first.CreatedAt)
+	assert.NotEqual(t, first.ID, NewUserBuilder().WithName("bob").Build().ID)
+}
+
+func TestUserBuilder_ShouldNotShareStateBetweenBuilds(t *testing.T) {
+	builder := NewUserBuilder().WithPhone("+15550100")
+	first := builder.Build()
+	first.Roles[0].Name = "admin"
+	*first.Phone = "+15550199"
+
+	second := builder.Build()
+	assert.Equal(t, "user", second.Roles[0].Name)
+	assert.Equal(t, "+15550100", *second.Phone)
+}
+
+func TestUserBuilder_WithPassword(t *testing.T) {
+	user := NewUserBuilder().WithPassword("").Build()
+	assert.NoError(t, utils.CheckPassword(user.PasswordHash, DefaultPassword))
+}
+
+func TestOAuthClientBuilder(t *testing.T) {
+	confidential := NewOAuthClientBuilder().Build()
+	require.NotNil(t, confidential.ClientSecretHash)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*confidential.ClientSecretHash), []byte(DefaultClientSecret)))
+	assert.Equal(t, string(models.ClientTypeConfidential), confidential.ClientType)
+
+	public := NewOAuthClientBuilder().WithName("spa").Public().Build()
+	assert.Nil(t, public.ClientSecretHash)
+	assert.True(t, public.RequirePKCE)
+	assert.Equal(t, "agw_spa", public.ClientID)
+}
+
+func TestSessionBuilder(t *testing.T) {
+	user := NewUserBuilder().WithName("alice").Build()
+	session := NewSessionBuilder().ForUser(user).WithRefreshToken("refresh").Build()
+
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, utils.HashToken("refresh"), session.TokenHash)
+	assert.Equal(t, FarFuture, session.ExpiresAt)
+	assert.Nil(t, session.RevokedAt)
+
+	revoked := NewSessionBuilder().Expired().Revoked().Build()
+	assert.Equal(t, Epoch, revoked.ExpiresAt)
+	require.NotNil(t, revoked.RevokedAt)
+}
@@ -0,0 +1,142 @@
+package fixtures
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// DefaultClientSecret is the secret of confidential clients built without WithSecret
+const DefaultClientSecret = "agws_test_secret"
+
+// OAuthClientBuilder builds models.OAuthClient fixtures
+type OAuthClientBuilder struct {
+	client models.OAuthClient
+	secret string
+}
+
+// NewOAuthClientBuilder returns a builder for an active confidential client named "client"
+// with the authorization code, refresh token and client credentials grants
+func NewOAuthClientBuilder() *OAuthClientBuilder {
+	b := &OAuthClientBuilder{
+		client: models.OAuthClient{
+			ClientType:   string(models.ClientTypeConfidential),
+			RedirectURIs: []string{"https://example.com/callback"},
+			AllowedGrantTypes: []string{
+				string(models.GrantTypeAuthorizationCode),
+				string(models.GrantTypeRefreshToken),
+				string(models.GrantTypeClientCredentials),
+			},
+			AllowedScopes:   []string{"openid", "profile", "email"},
+			DefaultScopes:   []string{"openid"},
+			AccessTokenTTL:  900,
+			RefreshTokenTTL: 604800,
+			IDTokenTTL:      3600,
+			RequireConsent:  true,
+			IsActive:        true,
+			CreatedAt:       Epoch,
+			UpdatedAt:       Epoch,
+		},
+		secret: DefaultClientSecret,
+	}
+	return b.WithName("client")
+}
+
+// WithName derives the ID, client_id and display name from name
+func (b *OAuthClientBuilder) WithName(name string) *OAuthClientBuilder {
+	b.client.ID = ID("oauth_client", name)
+	b.client.ClientID = "agw_" + name
+	b.client.Name = "Test " + name
+	return b
+}
+
+// WithClientID sets the public client_id
+func (b *OAuthClientBuilder) WithClientID(clientID string) *OAuthClientBuilder {
+	b.client.ClientID = clientID
+	return b
+}
+
+// WithSecret sets the client secret of a confidential client
+func (b *OAuthClientBuilder) WithSecret(secret string) *OAuthClientBuilder {
+	b.secret = secret
+	return b
+}
+
+// Public makes the client a public client without a secret that requires PKCE
+func (b *OAuthClientBuilder) Public() *OAuthClientBuilder {
+	b.client.ClientType = string(models.ClientTypePublic)
+	b.client.RequirePKCE = true
+	b.secret = ""
+	return b
+}
+
+// WithRedirectURIs replaces the allowed redirect URIs
+func (b *OAuthClientBuilder) WithRedirectURIs(uris ...string) *OAuthClientBuilder {
+	b.client.RedirectURIs = uris
+	return b
+}
+
+// WithGrantTypes replaces the allowed grant types
+func (b *OAuthClientBuilder) WithGrantTypes(grantTypes ...models.GrantType) *OAuthClientBuilder {
+	b.client.AllowedGrantTypes = make([]string, len(grantTypes))
+	for i, grantType := range grantTypes {
+		b.client.AllowedGrantTypes[i] = string(grantType)
+	}
+	return b
+}
+
+// WithScopes replaces the allowed scopes
+func (b *OAuthClientBuilder) WithScopes(scopes ...string) *OAuthClientBuilder {
+	b.client.AllowedScopes = scopes
+	return b
+}
+
+// RequirePKCE requires PKCE for the authorization code grant
+func (b *OAuthClientBuilder) RequirePKCE() *OAuthClientBuilder {
+	b.client.RequirePKCE = true
+	return b
+}
+
+// FirstParty marks the client as first-party, which skips the consent screen
+func (b *OAuthClientBuilder) FirstParty() *OAuthClientBuilder {
+	b.client.FirstParty = true
+	b.client.RequireConsent = false
+	return b
+}
+
+// ForApplication binds the client to an application
+func (b *OAuthClientBuilder) ForApplication(applicationID uuid.UUID) *OAuthClientBuilder {
+	b.client.ApplicationID = &applicationID
+	return b
+}
+
+// OwnedBy sets the owning user
+func (b *OAuthClientBuilder) OwnedBy(userID uuid.UUID) *OAuthClientBuilder {
+	b.client.OwnerID = &userID
+	return b
+}
+
+// Inactive marks the client as disabled
+func (b *OAuthClientBuilder) Inactive() *OAuthClientBuilder {
+	b.client.IsActive = false
+	return b
+}
+
+// Build returns a new client with a bcrypt hash of its secret at minimum cost
+func (b *OAuthClientBuilder) Build() *models.OAuthClient {
+	client := b.client
+	client.RedirectURIs = append([]string(nil), b.client.RedirectURIs...)
+	client.AllowedGrantTypes = append([]string(nil), b.client.AllowedGrantTypes...)
+	client.AllowedScopes = append([]string(nil), b.client.AllowedScopes...)
+	client.DefaultScopes = append([]string(nil), b.client.DefaultScopes...)
+	if b.secret != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(b.secret), bcrypt.MinCost)
+		if err != nil {
+			panic(err)
+		}
+		secretHash := string(hash)
+		client.ClientSecretHash = &secretHash
+	}
+	return &client
+}
@@ -0,0 +1,56 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uptrace/bun"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+)
+
+// SeedUser inserts the user and assigns its roles. Roles are resolved by name, so the
+// migrated system roles are used; unknown role names fail the test.
+func SeedUser(t testing.TB, db bun.IDB, user *models.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewInsert().Model(user).Exec(ctx); err != nil {
+		t.Fatalf("Failed to seed user %s: %v", user.Username, err)
+	}
+
+	for _, role := range user.Roles {
+		userRole := &models.UserRole{UserID: user.ID}
+		err := db.NewSelect().
+			Model((*models.Role)(nil)).
+			Column("id").
+			Where("name = ?", role.Name).
+			Where("application_id IS NULL").
+			Scan(ctx, &userRole.RoleID)
+		if err != nil {
+			t.Fatalf("Failed to find role %s for user %s: %v", role.Name, user.Username, err)
+		}
+
+		if _, err := db.NewInsert().Model(userRole).Exec(ctx); err != nil {
+			t.Fatalf("Failed to assign role %s to user %s: %v", role.Name, user.Username, err)
+		}
+	}
+}
+
+// SeedOAuthClient inserts the OAuth client
+func SeedOAuthClient(t testing.TB, db bun.IDB, client *models.OAuthClient) {
+	t.Helper()
+
+	if _, err := db.NewInsert().Model(client).Exec(context.Background()); err != nil {
+		t.Fatalf("Failed to seed OAuth client %s: %v", client.ClientID, err)
+	}
+}
+
+// SeedSession inserts the session. Its user must already be seeded.
+func SeedSession(t testing.TB, db bun.IDB, session *models.Session) {
+	t.Helper()
+
+	if _, err := db.NewInsert().Model(session).Exec(context.Background()); err != nil {
+		t.Fatalf("Failed to seed session %s: %v", session.ID, err)
+	}
+}
@@ -0,0 +1,105 @@
+package fixtures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// SessionBuilder builds models.Session fixtures
+type SessionBuilder struct {
+	session models.Session
+}
+
+// NewSessionBuilder returns a builder for an active desktop session of the default user that
+// expires at FarFuture
+func NewSessionBuilder() *SessionBuilder {
+	b := &SessionBuilder{session: models.Session{
+		UserID:       ID("user", "user"),
+		DeviceType:   "desktop",
+		OS:           "Linux",
+		Browser:      "Firefox 120",
+		IPAddress:    "127.0.0.1",
+		UserAgent:    "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+		LastActiveAt: Epoch,
+		ExpiresAt:    FarFuture,
+		CreatedAt:    Epoch,
+	}}
+	return b.WithName("session")
+}
+
+// WithName derives the ID and the refresh and access token hashes from name
+func (b *SessionBuilder) WithName(name string) *SessionBuilder {
+	b.session.ID = ID("session", name)
+	return b.WithRefreshToken("refresh-" + name).WithAccessToken("access-" + name)
+}
+
+// ForUser binds the session to the user
+func (b *SessionBuilder) ForUser(user *models.User) *SessionBuilder {
+	b.session.UserID = user.ID
+	return b
+}
+
+// ForApplication binds the session to an application
+func (b *SessionBuilder) ForApplication(applicationID uuid.UUID) *SessionBuilder {
+	b.session.ApplicationID = &applicationID
+	return b
+}
+
+// WithRefreshToken stores the hash of the refresh token
+func (b *SessionBuilder) WithRefreshToken(token string) *SessionBuilder {
+	b.session.TokenHash = utils.HashToken(token)
+	return b
+}
+
+// WithAccessToken stores the hash of the access token
+func (b *SessionBuilder) WithAccessToken(token string) *SessionBuilder {
+	b.session.AccessTokenHash = utils.HashToken(token)
+	return b
+}
+
+// WithDevice sets the device type, OS and browser
+func (b *SessionBuilder) WithDevice(deviceType, os, browser string) *SessionBuilder {
+	b.session.DeviceType = deviceType
+	b.session.OS = os
+	b.session.Browser = browser
+	return b
+}
+
+// WithIP sets the client IP address
+func (b *SessionBuilder) WithIP(ip string) *SessionBuilder {
+	b.session.IPAddress = ip
+	return b
+}
+
+// ExpiresAt sets the expiry
+func (b *SessionBuilder) ExpiresAt(expiresAt time.Time) *SessionBuilder {
+	b.session.ExpiresAt = expiresAt
+	return b
+}
+
+// Expired sets the expiry to Epoch
+func (b *SessionBuilder) Expired() *SessionBuilder {
+	b.session.ExpiresAt = Epoch
+	return b
+}
+
+// Revoked marks the session as revoked at Epoch
+func (b *SessionBuilder) Revoked() *SessionBuilder {
+	revokedAt := Epoch
+	b.session.RevokedAt = &revokedAt
+	return b
+}
+
+// Build returns a new session; the builder can be reused
+func (b *SessionBuilder) Build() *models.Session {
+	session := b.session
+	if b.session.RevokedAt != nil {
+		revokedAt := *b.session.RevokedAt
+		session.RevokedAt = &revokedAt
+	}
+	return &session
+}
@@ -0,0 +1,133 @@
+package fixtures
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// DefaultPassword is the password of users built with WithPassword("")
+const DefaultPassword = "Password123!"
+
+// UserBuilder builds models.User fixtures
+type UserBuilder struct {
+	user models.User
+}
+
+// NewUserBuilder returns a builder for an active, verified human user named "user" with the
+// "user" role
+func NewUserBuilder() *UserBuilder {
+	b := &UserBuilder{user: models.User{
+		AccountType:   string(models.AccountTypeHuman),
+		IsActive:      true,
+		EmailVerified: true,
+		FullName:      "Test User",
+		CreatedAt:     Epoch,
+		UpdatedAt:     Epoch,
+	}}
+	return b.WithName("user").WithRoles("user")
+}
+
+// WithName derives the ID, email and username from name
+func (b *UserBuilder) WithName(name string) *UserBuilder {
+	b.user.ID = ID("user", name)
+	b.user.Email = name + "@example.com"
+	b.user.Username = name
+	return b
+}
+
+// WithID overrides the derived ID
+func (b *UserBuilder) WithID(id uuid.UUID) *UserBuilder {
+	b.user.ID = id
+	return b
+}
+
+// WithFullName sets the full name
+func (b *UserBuilder) WithFullName(fullName string) *UserBuilder {
+	b.user.FullName = fullName
+	return b
+}
+
+// WithEmail sets the email address
+func (b *UserBuilder) WithEmail(email string) *UserBuilder {
+	b.user.Email = email
+	return b
+}
+
+// WithUsername sets the username
+func (b *UserBuilder) WithUsername(username string) *UserBuilder {
+	b.user.Username = username
+	return b
+}
+
+// WithPhone sets the phone number
+func (b *UserBuilder) WithPhone(phone string) *UserBuilder {
+	b.user.Phone = &phone
+	return b
+}
+
+// WithPassword sets a bcrypt hash of password at minimum cost; empty uses DefaultPassword
+func (b *UserBuilder) WithPassword(password string) *UserBuilder {
+	if password == "" {
+		password = DefaultPassword
+	}
+	hash, err := utils.HashPassword(password, bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+	b.user.PasswordHash = hash
+	return b
+}
+
+// WithRoles replaces the roles. Role IDs are deterministic; seeding resolves roles by name.
+func (b *UserBuilder) WithRoles(names ...string) *UserBuilder {
+	b.user.Roles = make([]models.Role, len(names))
+	for i, name := range names {
+		b.user.Roles[i] = models.Role{ID: ID("role", name), Name: name, DisplayName: name}
+	}
+	return b
+}
+
+// Inactive marks the user as deactivated
+func (b *UserBuilder) Inactive() *UserBuilder {
+	b.user.SetState(models.UserStateDeactivated)
+	return b
+}
+
+// Unverified marks the email address as not verified
+func (b *UserBuilder) Unverified() *UserBuilder {
+	b.user.EmailVerified = false
+	return b
+}
+
+// WithTOTP enables TOTP two-factor authentication with the given secret
+func (b *UserBuilder) WithTOTP(secret string) *UserBuilder {
+	b.user.TOTPEnabled = true
+	b.user.TOTPSecret = &secret
+	enabledAt := Epoch
+	b.user.TOTPEnabledAt = &enabledAt
+	return b
+}
+
+// Guest turns the user into an anonymous guest without email or credentials
+func (b *UserBuilder) Guest() *UserBuilder {
+	b.user.IsGuest = true
+	b.user.Email = ""
+	b.user.EmailVerified = false
+	b.user.PasswordHash = ""
+	b.user.Username = models.GuestUsernamePrefix + b.user.Username
+	return b
+}
+
+// Build returns a new user; the builder can be reused
+func (b *UserBuilder) Build() *models.User {
+	user := b.user
+	user.Roles = append([]models.Role(nil), b.user.Roles...)
+	if b.user.Phone != nil {
+		phone := *b.user.Phone
+		user.Phone = &phone
+	}
+	return &user
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryHook returns a bun query hook that records a span for each database query. The
+// statement itself is left out, as its arguments may hold personal data.
+func QueryHook() bun.QueryHook {
+	return queryHook{}
+}
+
+type queryHook struct{}
+
+// BeforeQuery implements bun.QueryHook
+func (queryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	operation := strings.ToLower(event.Operation())
+	ctx, _ = Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.StartTime),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+		),
+	)
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook
+func (queryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	err := event.Err
+	// Finding nothing is an answer, not a failure
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	End(trace.SpanFromContext(ctx), err)
+}
+
+// RedisHook returns a go-redis hook that records a span for each Redis command
+func RedisHook() redis.Hook {
+	return redisHook{}
+}
+
+type redisHook struct{}
+
+// DialHook implements redis.Hook
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startRedis(ctx, cmd.Name())
+		err := next(ctx, cmd)
+		End(span, redisError(err))
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startRedis(ctx, "pipeline")
+		span.SetAttributes(attribute.Int("db.redis.commands", len(cmds)))
+		err := next(ctx, cmds)
+		End(span, redisError(err))
+		return err
+	}
+}
+
+func startRedis(ctx context.Context, command string) (context.Context, trace.Span) {
+	return Start(ctx, "redis "+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", command),
+		),
+	)
+}
+
+// redisError drops redis.Nil, which only reports a missing key
+func redisError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestQueryHook(t *testing.T) {
+	recorder := setupRecorder(t)
+	hook := QueryHook()
+
+	// Finding no rows is not an error
+	for _, err := range []error{sql.ErrNoRows, errors.New("connection reset")} {
+		event := &bun.QueryEvent{Query: "SELECT 1", StartTime: time.Now(), Err: err}
+		hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "db select", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestRedisHook(t *testing.T) {
+	recorder := setupRecorder(t)
+	hook := RedisHook()
+
+	// A missing key is not an error
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return redis.Nil })
+	assert.ErrorIs(t, process(context.Background(), redis.NewStringCmd(context.Background(), "get", "key")), redis.Nil)
+	process = hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return errors.New("i/o timeout") })
+	assert.Error(t, process(context.Background(), redis.NewStringCmd(context.Background(), "get", "key")))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis get", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,122 @@
+// Package tracing sets up OpenTelemetry tracing: the OTLP exporter, W3C trace context
+// propagation, and the spans of HTTP, gRPC, database and Redis calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+	"github.com/smilemakc/auth-gateway/internal/utils"
+)
+
+// instrumentationName names the tracer of the gateway's own spans
+const instrumentationName = "github.com/smilemakc/auth-gateway"
+
+// Setup installs a global tracer provider exporting spans to the collector of cfg, and the
+// W3C trace context propagator. The returned function flushes pending spans and stops the
+// exporter; it is a no-op when tracing is disabled.
+func Setup(ctx context.Context, cfg *config.TracingConfig, version string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", version),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the gateway's own tracer
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the ID of the trace ctx belongs to, or "" outside a trace
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// Middleware starts a span for each HTTP request, continuing the trace of the traceparent
+// header, and adds its trace ID to the request's log fields
+func Middleware(serviceName string) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		otelgin.Middleware(serviceName),
+		func(c *gin.Context) {
+			if traceID := TraceID(c.Request.Context()); traceID != "" {
+				c.Header("X-Trace-ID", traceID)
+				utils.AddLogFields(c, map[string]interface{}{"trace_id": traceID})
+			}
+			c.Next()
+		},
+	}
+}
+
+// ServerHandler returns the gRPC stats handler that starts a span for each call, continuing
+// the trace of the traceparent metadata
+func ServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
+
+// Transport wraps next so that outgoing requests get a client span and a traceparent header
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(next)
+}
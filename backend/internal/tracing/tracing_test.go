@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/smilemakc/auth-gateway/internal/config"
+)
+
+// setupRecorder installs a tracer provider that keeps the spans in memory
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &config.TracingConfig{}, "test")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestTraceID(t *testing.T) {
+	setupRecorder(t)
+	assert.Empty(t, TraceID(context.Background()))
+
+	ctx, span := Start(context.Background(), "test")
+	defer span.End()
+	assert.Equal(t, span.SpanContext().TraceID().String(), TraceID(ctx))
+}
+
+func TestMiddleware_ContinuesTrace(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var requestTraceID string
+	router := gin.New()
+	router.Use(Middleware("auth-gateway")...)
+	router.GET("/ping", func(c *gin.Context) {
+		requestTraceID = TraceID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, traceID, requestTraceID)
+	assert.Equal(t, traceID, w.Header().Get("X-Trace-ID"))
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, traceID, recorder.Ended()[0].SpanContext().TraceID().String())
+}
+
+func TestTransport_PropagatesTrace(t *testing.T) {
+	setupRecorder(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "deliver")
+	defer span.End()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Contains(t, traceparent, TraceID(ctx))
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientCertThumbprintKey holds the thumbprint of the client certificate a request was made with
+const ClientCertThumbprintKey = "client_cert_thumbprint"
+
+// GetClientCertThumbprint returns the thumbprint set by the ClientCertificate middleware, or ""
+// if the request carried no client certificate
+func GetClientCertThumbprint(c *gin.Context) string {
+	return c.GetString(ClientCertThumbprintKey)
+}
+
+// CertThumbprint returns the x5t#S256 thumbprint of cert: the base64url SHA-256 hash of its
+// DER encoding (RFC 8705, section 3.1)
+func CertThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ParseForwardedClientCert parses a client certificate forwarded by a TLS-terminating proxy.
+// It accepts URL-escaped PEM (nginx $ssl_client_escaped_cert, AWS ALB), PEM and base64 DER.
+func ParseForwardedClientCert(value string) (*x509.Certificate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.New("empty client certificate")
+	}
+	if strings.Contains(value, "%") {
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		value = unescaped
+	}
+
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+	der, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("client certificate is neither PEM nor base64 DER")
+	}
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestCertThumbprint(t *testing.T) {
+	cert := newTestCertificate(t)
+	sum := sha256.Sum256(cert.Raw)
+
+	thumbprint := CertThumbprint(cert)
+
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), thumbprint)
+	assert.Len(t, thumbprint, 43)
+}
+
+func TestParseForwardedClientCert(t *testing.T) {
+	cert := newTestCertificate(t)
+	pemCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"URL-escaped PEM", url.QueryEscape(pemCert)},
+		{"PEM", pemCert},
+		{"base64 DER", base64.StdEncoding.EncodeToString(cert.Raw)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseForwardedClientCert(tt.value)
+
+			require.NoError(t, err)
+			assert.Equal(t, cert.Raw, parsed.Raw)
+		})
+	}
+
+	t.Run("ShouldRejectGarbage", func(t *testing.T) {
+		_, err := ParseForwardedClientCert("not a certificate")
+		assert.Error(t, err)
+	})
+}
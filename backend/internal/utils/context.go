@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/logger"
 )
 
 // Context keys
@@ -16,6 +17,10 @@ const (
 	UserRolesKey     = "user_roles"
 	TokenKey         = "access_token"
 	ApplicationIDKey = "application_id"
+	RequestIDKey     = "request_id"
+	// DelegatedAdminKey marks requests let into the admin API by a delegated admin
+	// permission rather than the admin role
+	DelegatedAdminKey = "delegated_admin"
 )
 
 // GetUserIDFromContext retrieves the user ID from the Gin context
@@ -127,6 +132,12 @@ func GetUserRolesFromContext(c *gin.Context) ([]string, bool) {
 	return roleSlice, ok
 }
 
+// IsDelegatedAdmin reports whether the request reached the admin API through a delegated
+// admin permission rather than the admin role
+func IsDelegatedAdmin(c *gin.Context) bool {
+	return c.GetBool(DelegatedAdminKey)
+}
+
 // GetTokenFromContext retrieves raw access token set by auth middleware
 func GetTokenFromContext(c *gin.Context) (string, bool) {
 	value, exists := c.Get(TokenKey)
@@ -170,3 +181,14 @@ func GetDeviceInfoFromContext(c *gin.Context) models.DeviceInfo {
 func SetApplicationIDInContext(c *gin.Context, applicationID uuid.UUID) {
 	c.Set(ApplicationIDKey, applicationID)
 }
+
+// GetRequestIDFromContext retrieves the request ID from the Gin context
+func GetRequestIDFromContext(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// AddLogFields attaches fields to the request context so that every entry logged with it
+// carries them (see logger.ContextWithFields)
+func AddLogFields(c *gin.Context, fields map[string]interface{}) {
+	c.Request = c.Request.WithContext(logger.ContextWithFields(c.Request.Context(), fields))
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var domainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// disposableEmailDomains lists well-known throwaway email providers. Subdomains match too.
+var disposableEmailDomains = map[string]bool{
+	"10minutemail.com":       true,
+	"20minutemail.com":       true,
+	"33mail.com":             true,
+	"anonbox.net":            true,
+	"burnermail.io":          true,
+	"discard.email":          true,
+	"dispostable.com":        true,
+	"dropmail.me":            true,
+	"emailondeck.com":        true,
+	"fakeinbox.com":          true,
+	"getairmail.com":         true,
+	"getnada.com":            true,
+	"guerrillamail.biz":      true,
+	"guerrillamail.com":      true,
+	"guerrillamail.de":       true,
+	"guerrillamail.info":     true,
+	"guerrillamail.net":      true,
+	"guerrillamail.org":      true,
+	"guerrillamailblock.com": true,
+	"harakirimail.com":       true,
+	"inboxbear.com":          true,
+	"incognitomail.org":      true,
+	"mailcatch.com":          true,
+	"maildrop.cc":            true,
+	"mailinator.com":         true,
+	"mailinator.net":         true,
+	"mailnesia.com":          true,
+	"mintemail.com":          true,
+	"moakt.com":              true,
+	"mohmal.com":             true,
+	"mytemp.email":           true,
+	"sharklasers.com":        true,
+	"spam4.me":               true,
+	"spamgourmet.com":        true,
+	"temp-mail.io":           true,
+	"temp-mail.org":          true,
+	"tempail.com":            true,
+	"tempmail.com":           true,
+	"tempmail.net":           true,
+	"tempmailo.com":          true,
+	"tempr.email":            true,
+	"throwawaymail.com":      true,
+	"trashmail.com":          true,
+	"trashmail.de":           true,
+	"trashmail.net":          true,
+	"yopmail.com":            true,
+	"yopmail.fr":             true,
+	"yopmail.net":            true,
+}
+
+// EmailDomain returns the normalized domain part of an email address, or an empty string
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return NormalizeDomain(email[at+1:])
+}
+
+// NormalizeDomain normalizes a domain name (lowercase, trimmed, no leading "@" or trailing dot)
+func NormalizeDomain(domain string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), "."), "@")
+}
+
+// IsValidDomain checks if a (normalized) domain name is valid
+func IsValidDomain(domain string) bool {
+	return len(domain) <= 253 && domainRegex.MatchString(domain)
+}
+
+// DomainMatches reports whether domain equals pattern or is a subdomain of it
+func DomainMatches(domain, pattern string) bool {
+	return domain == pattern || strings.HasSuffix(domain, "."+pattern)
+}
+
+// IsDisposableEmailDomain reports whether a domain belongs to a known disposable email provider
+func IsDisposableEmailDomain(domain string) bool {
+	for {
+		if disposableEmailDomains[domain] {
+			return true
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+}
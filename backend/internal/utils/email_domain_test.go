@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailDomain(t *testing.T) {
+	assert.Equal(t, "example.com", EmailDomain("user@Example.COM"))
+	assert.Equal(t, "example.com", EmailDomain("user@example.com."))
+	assert.Equal(t, "", EmailDomain("no-at-sign"))
+}
+
+func TestIsValidDomain(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"example.com", true},
+		{"mail.example.co.uk", true},
+		{"my-domain.io", true},
+		{"localhost", false},
+		{"-bad.com", false},
+		{"bad-.com", false},
+		{"exa mple.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsValidDomain(tt.domain), tt.domain)
+	}
+}
+
+func TestDomainMatches(t *testing.T) {
+	assert.True(t, DomainMatches("example.com", "example.com"))
+	assert.True(t, DomainMatches("eu.example.com", "example.com"))
+	assert.False(t, DomainMatches("badexample.com", "example.com"))
+	assert.False(t, DomainMatches("example.com", "eu.example.com"))
+}
+
+func TestIsDisposableEmailDomain(t *testing.T) {
+	assert.True(t, IsDisposableEmailDomain("mailinator.com"))
+	assert.True(t, IsDisposableEmailDomain("inbox.mailinator.com"))
+	assert.False(t, IsDisposableEmailDomain("example.com"))
+	assert.False(t, IsDisposableEmailDomain("notmailinator.com"))
+}
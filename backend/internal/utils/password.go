@@ -15,6 +15,7 @@ type PasswordPolicy struct {
 	RequireNumbers   bool
 	RequireSpecial   bool
 	MaxLength        int // 0 means no maximum
+	HistoryCount     int // Number of previous passwords that cannot be reused (0 disables the check)
 }
 
 // DefaultPasswordPolicy returns a default password policy
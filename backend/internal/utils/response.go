@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +10,33 @@ import (
 
 // RespondWithError sends an appropriate JSON error response.
 // If the error is an *models.AppError, it uses the error's status code.
+// A *models.PasswordChangeRequiredError becomes a 401 carrying the reset token, and a
+// *models.PasswordPolicyError a 400 listing the broken password policy rules.
 // Otherwise, it responds with 500 Internal Server Error.
 func RespondWithError(c *gin.Context, err error) {
+	var changeErr *models.PasswordChangeRequiredError
+	if errors.As(err, &changeErr) {
+		c.JSON(http.StatusUnauthorized, &models.PasswordChangeRequiredResponse{
+			Error:      http.StatusText(http.StatusUnauthorized),
+			Message:    "Password change required",
+			Code:       models.PasswordChangeRequiredCode,
+			Reason:     changeErr.Reason,
+			ResetToken: changeErr.ResetToken,
+		})
+		return
+	}
+
+	var policyErr *models.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, &models.PasswordPolicyViolationResponse{
+			Error:      http.StatusText(http.StatusBadRequest),
+			Message:    "Password does not meet the password policy",
+			Code:       models.PasswordPolicyViolationCode,
+			Violations: policyErr.Violations,
+		})
+		return
+	}
+
 	if appErr, ok := err.(*models.AppError); ok {
 		c.JSON(appErr.Code, models.NewErrorResponse(appErr))
 	} else {
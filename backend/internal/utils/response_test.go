@@ -33,3 +33,32 @@ func TestRespondWithError_GenericError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	assert.Contains(t, w.Body.String(), "something went wrong")
 }
+
+func TestRespondWithError_PasswordChangeRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	RespondWithError(c, &models.PasswordChangeRequiredError{Reason: models.PasswordChangeReasonExpired, ResetToken: "reset-token"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"PASSWORD_CHANGE_REQUIRED"`)
+	assert.Contains(t, w.Body.String(), `"reason":"expired"`)
+	assert.Contains(t, w.Body.String(), `"reset_token":"reset-token"`)
+}
+
+func TestRespondWithError_PasswordPolicyViolation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	RespondWithError(c, &models.PasswordPolicyError{Violations: []models.PasswordPolicyViolation{
+		{Rule: models.PasswordRuleMinLength, Message: "Password must be at least 12 characters long"},
+		{Rule: models.PasswordRuleNumber, Message: "Password must contain at least one number"},
+	}})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"PASSWORD_POLICY_VIOLATION"`)
+	assert.Contains(t, w.Body.String(), `{"rule":"min_length","message":"Password must be at least 12 characters long"}`)
+	assert.Contains(t, w.Body.String(), `"rule":"number"`)
+}
@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Clock is the time source and clock skew tolerance used to issue and validate tokens.
+// The zero value uses time.Now and no tolerance.
+type Clock struct {
+	// Now returns the current time. Nil uses time.Now; tests inject a fixed time.
+	Now func() time.Time
+	// Skew is tolerated when checking exp and nbf, so hosts whose clocks drift apart
+	// still accept each other's tokens
+	Skew time.Duration
+}
+
+func (c Clock) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
+
+// newParser returns a parser that reads the time from c on every call, so later changes to
+// c.Now apply, while the skew is fixed when the parser is built
+func (c *Clock) newParser() *jwt.Parser {
+	return jwt.NewParser(
+		jwt.WithTimeFunc(func() time.Time { return c.now() }),
+		jwt.WithLeeway(c.Skew),
+	)
+}
+
+func (c Clock) parserOptions() []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithTimeFunc(c.now),
+		jwt.WithLeeway(c.Skew),
+	}
+}
@@ -2,17 +2,40 @@ package jwt
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
 )
 
+// Token types carried in the token_type claim. Asymmetric tokens share one signing key,
+// so the claim is what keeps a refresh token from being accepted as an access token.
+const (
+	TokenTypeAccess    = "access"
+	TokenTypeRefresh   = "refresh"
+	TokenTypeTwoFactor = "2fa"
+	// TokenTypePasswordChange authorizes only setting a new password after sign-in was
+	// blocked by an expired or admin-required password change
+	TokenTypePasswordChange = "password_change"
+	// TokenTypeOAuthLink authorizes only linking a provider account to an existing
+	// account once the owner of that account confirmed it with their password
+	TokenTypeOAuthLink = "oauth_link"
+)
+
+// PasswordChangeTokenTTL is how long a password change token remains valid
+const PasswordChangeTokenTTL = 15 * time.Minute
+
+// OAuthLinkTokenTTL is how long an OAuth link token remains valid
+const OAuthLinkTokenTTL = 10 * time.Minute
+
 var (
-	ErrInvalidToken  = errors.New("invalid token")
-	ErrExpiredToken  = errors.New("expired token")
-	ErrInvalidClaims = errors.New("invalid token claims")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrExpiredToken    = errors.New("expired token")
+	ErrInvalidClaims   = errors.New("invalid token claims")
+	ErrInvalidAudience = errors.New("invalid token audience")
 )
 
 // Service provides JWT token operations
@@ -21,6 +44,50 @@ type Service struct {
 	refreshSecret  string
 	accessExpires  time.Duration
 	refreshExpires time.Duration
+	issuer         string   // iss claim set on issued tokens and required on validation
+	audience       []string // aud claim set on issued tokens; validation requires at least one match
+	keyManager     *keys.Manager
+	acceptHMAC     bool // accept HMAC-signed tokens while migrating to asymmetric signing
+	clock          Clock
+
+	// Built once in NewService so validation does not rebuild them on every call
+	parser         *jwt.Parser
+	accessKeyFunc  jwt.Keyfunc
+	refreshKeyFunc jwt.Keyfunc
+}
+
+// ServiceOption configures optional Service behaviour
+type ServiceOption func(*Service)
+
+// WithIssuer sets the iss claim on issued tokens and rejects tokens with a different issuer
+func WithIssuer(issuer string) ServiceOption {
+	return func(s *Service) {
+		s.issuer = issuer
+	}
+}
+
+// WithAudience sets the aud claim on issued tokens and rejects tokens not addressed to any of them
+func WithAudience(audience ...string) ServiceOption {
+	return func(s *Service) {
+		s.audience = audience
+	}
+}
+
+// WithKeyManager signs tokens with the key manager's current RS256/ES256 key and a kid header.
+// When acceptHMAC is true, tokens signed with the legacy shared secrets are still accepted so
+// sessions issued before the migration keep working until they expire.
+func WithKeyManager(keyManager *keys.Manager, acceptHMAC bool) ServiceOption {
+	return func(s *Service) {
+		s.keyManager = keyManager
+		s.acceptHMAC = acceptHMAC
+	}
+}
+
+// WithClock sets the time source and clock skew tolerance for issuing and validating tokens
+func WithClock(clock Clock) ServiceOption {
+	return func(s *Service) {
+		s.clock = clock
+	}
 }
 
 // Claims represents the custom JWT claims
@@ -30,24 +97,38 @@ type Claims struct {
 	Username      string     `json:"username"`
 	Roles         []string   `json:"roles"`
 	IsActive      bool       `json:"is_active"`
+	EmailVerified bool       `json:"email_verified"`
+	IsGuest       bool       `json:"is_guest,omitempty"` // anonymous guest that has not been upgraded to a full account
 	ApplicationID *uuid.UUID `json:"application_id,omitempty"`
+	TokenType     string     `json:"token_type,omitempty"`
+	TokenVersion  int        `json:"tv,omitempty"` // user's token_version at issue time
+	// Provider account an OAuth link token may link to the user
+	OAuthProvider       string `json:"oauth_provider,omitempty"`
+	OAuthProviderUserID string `json:"oauth_provider_user_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // NewService creates a new JWT service
-func NewService(accessSecret, refreshSecret string, accessExpires, refreshExpires time.Duration) *Service {
-	return &Service{
+func NewService(accessSecret, refreshSecret string, accessExpires, refreshExpires time.Duration, opts ...ServiceOption) *Service {
+	s := &Service{
 		accessSecret:   accessSecret,
 		refreshSecret:  refreshSecret,
 		accessExpires:  accessExpires,
 		refreshExpires: refreshExpires,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.parser = s.clock.newParser()
+	s.accessKeyFunc = s.keyFunc([]byte(accessSecret))
+	s.refreshKeyFunc = s.keyFunc([]byte(refreshSecret))
+	return s
 }
 
 // GenerateAccessToken generates a new access token for the user
 // Optional applicationID can be passed to bind token to a specific application
 func (s *Service) GenerateAccessToken(user *models.User, applicationID ...*uuid.UUID) (string, error) {
-	now := time.Now()
+	now := s.clock.now()
 
 	roleNames := make([]string, len(user.Roles))
 	for i, role := range user.Roles {
@@ -58,17 +139,23 @@ func (s *Service) GenerateAccessToken(user *models.User, applicationID ...*uuid.
 	}
 
 	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
-		Roles:    roleNames,
-		IsActive: user.IsActive,
+		UserID:        user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		Roles:         roleNames,
+		IsActive:      user.IsActive,
+		EmailVerified: user.EmailVerified,
+		IsGuest:       user.IsGuest,
+		TokenType:     TokenTypeAccess,
+		TokenVersion:  user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessExpires)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Subject:   user.ID.String(),
+			Issuer:    s.issuer,
+			Audience:  s.audience,
 		},
 	}
 
@@ -76,14 +163,13 @@ func (s *Service) GenerateAccessToken(user *models.User, applicationID ...*uuid.
 		claims.ApplicationID = applicationID[0]
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.accessSecret))
+	return s.sign(claims, s.accessSecret)
 }
 
 // GenerateRefreshToken generates a new refresh token for the user
 // Optional applicationID can be passed to bind token to a specific application
 func (s *Service) GenerateRefreshToken(user *models.User, applicationID ...*uuid.UUID) (string, error) {
-	now := time.Now()
+	now := s.clock.now()
 
 	roleNames := make([]string, len(user.Roles))
 	for i, role := range user.Roles {
@@ -94,17 +180,21 @@ func (s *Service) GenerateRefreshToken(user *models.User, applicationID ...*uuid
 	}
 
 	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
-		Roles:    roleNames,
-		IsActive: user.IsActive,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Username:     user.Username,
+		Roles:        roleNames,
+		IsActive:     user.IsActive,
+		TokenType:    TokenTypeRefresh,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshExpires)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Subject:   user.ID.String(),
+			Issuer:    s.issuer,
+			Audience:  s.audience,
 		},
 	}
 
@@ -112,14 +202,13 @@ func (s *Service) GenerateRefreshToken(user *models.User, applicationID ...*uuid
 		claims.ApplicationID = applicationID[0]
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.refreshSecret))
+	return s.sign(claims, s.refreshSecret)
 }
 
 // GenerateTwoFactorToken generates a short-lived token for 2FA verification
 // Optional applicationID can be passed to bind token to a specific application
 func (s *Service) GenerateTwoFactorToken(user *models.User, applicationID ...*uuid.UUID) (string, error) {
-	now := time.Now()
+	now := s.clock.now()
 
 	roleNames := make([]string, len(user.Roles))
 	for i, role := range user.Roles {
@@ -130,17 +219,21 @@ func (s *Service) GenerateTwoFactorToken(user *models.User, applicationID ...*uu
 	}
 
 	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
-		Roles:    roleNames,
-		IsActive: user.IsActive,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Username:     user.Username,
+		Roles:        roleNames,
+		IsActive:     user.IsActive,
+		TokenType:    TokenTypeTwoFactor,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)), // 5 minutes expiration
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Subject:   user.ID.String(),
+			Issuer:    s.issuer,
+			Audience:  s.audience,
 		},
 	}
 
@@ -148,43 +241,254 @@ func (s *Service) GenerateTwoFactorToken(user *models.User, applicationID ...*uu
 		claims.ApplicationID = applicationID[0]
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.accessSecret))
+	return s.sign(claims, s.accessSecret)
+}
+
+// GeneratePasswordChangeToken generates a short-lived token that can only be used to set a new password
+func (s *Service) GeneratePasswordChangeToken(user *models.User) (string, error) {
+	now := s.clock.now()
+
+	claims := &Claims{
+		UserID:       user.ID,
+		Email:        user.Email,
+		Username:     user.Username,
+		IsActive:     user.IsActive,
+		TokenType:    TokenTypePasswordChange,
+		TokenVersion: user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(PasswordChangeTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Subject:   user.ID.String(),
+			Issuer:    s.issuer,
+			Audience:  s.audience,
+		},
+	}
+
+	return s.sign(claims, s.accessSecret)
+}
+
+// GenerateOAuthLinkToken generates a short-lived token that can only be used to link the given
+// provider account to the user after confirming the user's password
+func (s *Service) GenerateOAuthLinkToken(user *models.User, provider, providerUserID string, applicationID *uuid.UUID) (string, error) {
+	now := s.clock.now()
+
+	claims := &Claims{
+		UserID:              user.ID,
+		Email:               user.Email,
+		Username:            user.Username,
+		IsActive:            user.IsActive,
+		ApplicationID:       applicationID,
+		TokenType:           TokenTypeOAuthLink,
+		TokenVersion:        user.TokenVersion,
+		OAuthProvider:       provider,
+		OAuthProviderUserID: providerUserID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(OAuthLinkTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Subject:   user.ID.String(),
+			Issuer:    s.issuer,
+			Audience:  s.audience,
+		},
+	}
+
+	return s.sign(claims, s.accessSecret)
+}
+
+// sign signs claims with the current asymmetric key when configured, otherwise with the HMAC secret
+func (s *Service) sign(claims *Claims, secret string) (string, error) {
+	if s.keyManager == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(secret))
+	}
+
+	signingKey, err := s.keyManager.GetCurrentKey()
+	if err != nil {
+		return "", err
+	}
+
+	signingMethod, err := signingMethodFor(signingKey.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	token.Header["kid"] = signingKey.KID
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+var claimsPool = sync.Pool{
+	New: func() interface{} { return new(Claims) },
+}
+
+// AcquireClaims returns empty claims from a pool for ParseAccessToken.
+// Release them with ReleaseClaims once the *Claims is no longer referenced.
+func AcquireClaims() *Claims {
+	return claimsPool.Get().(*Claims)
+}
+
+// ReleaseClaims resets claims and returns them to the pool. Values read from the claims,
+// such as the Roles slice, stay valid after release.
+func ReleaseClaims(claims *Claims) {
+	*claims = Claims{}
+	claimsPool.Put(claims)
 }
 
 // ValidateAccessToken validates an access token and returns the claims
 func (s *Service) ValidateAccessToken(tokenString string) (*Claims, error) {
-	return s.validateToken(tokenString, s.accessSecret)
+	claims := &Claims{}
+	if err := s.ParseAccessToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ParseAccessToken validates an access token into claims, which must be empty.
+// Hot paths pair it with AcquireClaims and ReleaseClaims to avoid allocating claims per call.
+func (s *Service) ParseAccessToken(tokenString string, claims *Claims) error {
+	// 2FA tokens have always been signed with the access secret and are verified through this path
+	return s.validateToken(tokenString, s.accessKeyFunc, claims, TokenTypeAccess, TokenTypeTwoFactor)
 }
 
 // ValidateRefreshToken validates a refresh token and returns the claims
 func (s *Service) ValidateRefreshToken(tokenString string) (*Claims, error) {
-	return s.validateToken(tokenString, s.refreshSecret)
+	claims := &Claims{}
+	if err := s.validateToken(tokenString, s.refreshKeyFunc, claims, TokenTypeRefresh); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ValidatePasswordChangeToken validates a password change token and returns the claims.
+// Unlike other types, the token_type claim is mandatory here even for HMAC tokens.
+func (s *Service) ValidatePasswordChangeToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := s.validateToken(tokenString, s.accessKeyFunc, claims, TokenTypePasswordChange); err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypePasswordChange {
+		return nil, ErrInvalidClaims
+	}
+	return claims, nil
+}
+
+// ValidateOAuthLinkToken validates an OAuth link token and returns the claims.
+// Like password change tokens, the token_type claim is mandatory even for HMAC tokens.
+func (s *Service) ValidateOAuthLinkToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := s.validateToken(tokenString, s.accessKeyFunc, claims, TokenTypeOAuthLink); err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeOAuthLink || claims.OAuthProvider == "" || claims.OAuthProviderUserID == "" {
+		return nil, ErrInvalidClaims
+	}
+	return claims, nil
 }
 
-// validateToken validates a token with the given secret
-func (s *Service) validateToken(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// keyFunc returns the verification key lookup for tokens signed either with the given
+// secret or with a key manager key
+func (s *Service) keyFunc(secret []byte) jwt.Keyfunc {
+	// Boxed once here rather than on every returned key
+	var hmacKey interface{} = secret
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if s.keyManager != nil && !s.acceptHMAC {
+				return nil, ErrUnsupportedAlg
+			}
+			return hmacKey, nil
+		}
+		if s.keyManager == nil {
 			return nil, ErrInvalidToken
 		}
-		return []byte(secret), nil
-	})
+		return verificationKey(s.keyManager, token)
+	}
+}
 
+// validateToken validates a token into claims using keyFunc to look up the verification key
+func (s *Service) validateToken(tokenString string, keyFunc jwt.Keyfunc, claims *Claims, allowedTypes ...string) error {
+	token, err := s.parser.ParseWithClaims(tokenString, claims, keyFunc)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
+			return ErrExpiredToken
 		}
-		return nil, ErrInvalidToken
+		return ErrInvalidToken
 	}
 
-	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
-		return nil, ErrInvalidClaims
+	if !token.Valid {
+		return ErrInvalidClaims
 	}
 
-	return claims, nil
+	_, hmac := token.Method.(*jwt.SigningMethodHMAC)
+	if !tokenTypeAllowed(claims.TokenType, !hmac, allowedTypes) {
+		return ErrInvalidClaims
+	}
+
+	// Tokens minted by another environment sharing the same secret must not be accepted
+	if s.issuer != "" && claims.Issuer != s.issuer {
+		return ErrInvalidIssuer
+	}
+	if !s.audienceAllowed(claims.Audience) {
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+// tokenTypeAllowed checks the token_type claim. HMAC tokens issued before the claim existed carry
+// none and are distinguished by their secret; asymmetric tokens must always declare their type.
+func tokenTypeAllowed(tokenType string, asymmetric bool, allowedTypes []string) bool {
+	if tokenType == "" {
+		return !asymmetric
+	}
+	for _, allowed := range allowedTypes {
+		if tokenType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// audienceAllowed reports whether the token audience intersects the configured audience.
+// When no audience is configured every token is accepted.
+func (s *Service) audienceAllowed(tokenAudience jwt.ClaimStrings) bool {
+	if len(s.audience) == 0 {
+		return true
+	}
+	for _, expected := range s.audience {
+		for _, aud := range tokenAudience {
+			if aud == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetIssuer returns the configured token issuer
+func (s *Service) GetIssuer() string {
+	return s.issuer
+}
+
+// GetAudience returns the configured token audience
+func (s *Service) GetAudience() []string {
+	return s.audience
+}
+
+// GetJWKS returns the public keys used to sign first-party tokens.
+// The key set is empty while tokens are signed with HMAC secrets.
+func (s *Service) GetJWKS() *keys.JWKS {
+	if s.keyManager == nil {
+		return &keys.JWKS{Keys: []keys.JWK{}}
+	}
+	return s.keyManager.GetJWKS()
+}
+
+// IsAsymmetric reports whether tokens are signed with an asymmetric key
+func (s *Service) IsAsymmetric() bool {
+	return s.keyManager != nil
 }
 
 // GetAccessTokenExpiration returns the access token expiration duration
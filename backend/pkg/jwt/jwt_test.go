@@ -1,12 +1,20 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/smilemakc/auth-gateway/internal/models"
+	"github.com/smilemakc/auth-gateway/pkg/keys"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -138,6 +146,36 @@ func TestService_GenerateAccessToken_ShouldBindApplicationID_WhenProvided(t *tes
 	assert.Equal(t, appID, *claims.ApplicationID)
 }
 
+func TestService_GenerateAccessToken_ShouldCarryEmailVerified(t *testing.T) {
+	svc := newTestService()
+	user := newTestUser()
+
+	token, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	claims, err := svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.False(t, claims.EmailVerified)
+
+	user.EmailVerified = true
+	token, err = svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	claims, err = svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.True(t, claims.EmailVerified)
+}
+
+func TestService_GenerateAccessToken_ShouldMarkGuests(t *testing.T) {
+	svc := newTestService()
+	user := newTestUser()
+	user.IsGuest = true
+
+	token, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	claims, err := svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.True(t, claims.IsGuest)
+}
+
 func TestService_GenerateAccessToken_ShouldNotIncludeApplicationID_WhenNotProvided(t *testing.T) {
 	svc := newTestService()
 	user := newTestUser()
@@ -305,6 +343,57 @@ func TestService_GenerateTwoFactorToken_ShouldBindApplicationID(t *testing.T) {
 	assert.Equal(t, appID, *claims.ApplicationID)
 }
 
+// ============================================================
+// GeneratePasswordChangeToken Tests
+// ============================================================
+
+func TestService_GeneratePasswordChangeToken_ShouldOnlyValidateAsPasswordChange(t *testing.T) {
+	svc := newTestService()
+	user := newTestUser()
+	user.TokenVersion = 3
+
+	token, err := svc.GeneratePasswordChangeToken(user)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidatePasswordChangeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, TokenTypePasswordChange, claims.TokenType)
+	assert.Equal(t, 3, claims.TokenVersion)
+	assert.WithinDuration(t, time.Now().Add(PasswordChangeTokenTTL), claims.ExpiresAt.Time, 2*time.Second)
+
+	// Must not be usable as an access token
+	_, err = svc.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
+
+func TestService_ValidatePasswordChangeToken_ShouldRejectOtherTokenTypes(t *testing.T) {
+	svc := newTestService()
+	user := newTestUser()
+
+	accessToken, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	_, err = svc.ValidatePasswordChangeToken(accessToken)
+	assert.Error(t, err)
+
+	twoFactorToken, err := svc.GenerateTwoFactorToken(user)
+	require.NoError(t, err)
+	_, err = svc.ValidatePasswordChangeToken(twoFactorToken)
+	assert.Error(t, err)
+
+	// Legacy HMAC access tokens carry no token_type and must not pass either
+	legacy := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, &Claims{
+		UserID: user.ID,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	legacyToken, err := legacy.SignedString([]byte("access-secret"))
+	require.NoError(t, err)
+	_, err = svc.ValidatePasswordChangeToken(legacyToken)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
+
 // ============================================================
 // ValidateAccessToken Tests
 // ============================================================
@@ -322,6 +411,31 @@ func TestService_ValidateAccessToken_ShouldReturnClaims_WhenValid(t *testing.T)
 	assert.Equal(t, user.ID, claims.UserID)
 }
 
+func TestService_ParseAccessToken_ShouldReusePooledClaims(t *testing.T) {
+	svc := newTestService()
+	first, second := newTestUser(), newTestUser()
+	second.Roles = []models.Role{{Name: "admin"}}
+
+	firstToken, err := svc.GenerateAccessToken(first)
+	require.NoError(t, err)
+	secondToken, err := svc.GenerateAccessToken(second)
+	require.NoError(t, err)
+
+	claims := AcquireClaims()
+	require.NoError(t, svc.ParseAccessToken(firstToken, claims))
+	roles := claims.Roles
+	ReleaseClaims(claims)
+
+	// Released claims are empty and values read from them are not overwritten by reuse
+	assert.Equal(t, Claims{}, *claims)
+	require.NoError(t, svc.ParseAccessToken(secondToken, claims))
+	assert.Equal(t, second.ID, claims.UserID)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+	assert.Equal(t, []string{"user"}, roles)
+
+	assert.ErrorIs(t, svc.ParseAccessToken("not-a-jwt", AcquireClaims()), ErrInvalidToken)
+}
+
 func TestService_ValidateAccessToken_ShouldReturnExpiredError_WhenExpired(t *testing.T) {
 	// Create a service with very short expiration
 	svc := NewService("access-secret", "refresh-secret", -1*time.Second, 7*24*time.Hour)
@@ -336,6 +450,36 @@ func TestService_ValidateAccessToken_ShouldReturnExpiredError_WhenExpired(t *tes
 	assert.ErrorIs(t, err, ErrExpiredToken)
 }
 
+func TestService_ValidateAccessToken_ShouldTolerateClockSkew(t *testing.T) {
+	issuedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	issuer := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		WithClock(Clock{Now: func() time.Time { return issuedAt }}))
+	token, err := issuer.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	// Validating host runs 20s behind the issuer, so the token is not yet valid by its clock
+	behind := issuedAt.Add(-20 * time.Second)
+	strict := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		WithClock(Clock{Now: func() time.Time { return behind }}))
+	_, err = strict.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	tolerant := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		WithClock(Clock{Now: func() time.Time { return behind }, Skew: 30 * time.Second}))
+	_, err = tolerant.ValidateAccessToken(token)
+	assert.NoError(t, err)
+
+	// Expiry is tolerated by the skew as well, but not beyond it
+	expired := issuedAt.Add(15*time.Minute + 20*time.Second)
+	tolerant.clock.Now = func() time.Time { return expired }
+	_, err = tolerant.ValidateAccessToken(token)
+	assert.NoError(t, err)
+
+	expired = expired.Add(time.Minute)
+	_, err = tolerant.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
 func TestService_ValidateAccessToken_ShouldReturnInvalidError_WhenBadSignature(t *testing.T) {
 	svc := newTestService()
 	user := newTestUser()
@@ -654,3 +798,298 @@ func TestService_NewService_ShouldStoreConfiguration(t *testing.T) {
 	assert.Equal(t, accessExp, svc.GetAccessTokenExpiration())
 	assert.Equal(t, refreshExp, svc.GetRefreshTokenExpiration())
 }
+
+// ============================================================
+// Issuer / Audience Tests
+// ============================================================
+
+func TestService_ShouldSetIssuerAndAudience_WhenConfigured(t *testing.T) {
+	svc := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour,
+		WithIssuer("https://auth.prod.example.com"), WithAudience("api", "admin"))
+
+	token, err := svc.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.prod.example.com", claims.Issuer)
+	assert.Equal(t, jwtlib.ClaimStrings{"api", "admin"}, claims.Audience)
+}
+
+func TestService_ShouldRejectToken_WhenIssuerDiffers(t *testing.T) {
+	staging := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour,
+		WithIssuer("https://auth.staging.example.com"))
+	prod := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour,
+		WithIssuer("https://auth.prod.example.com"))
+
+	token, err := staging.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	_, err = prod.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+
+	refresh, err := staging.GenerateRefreshToken(newTestUser())
+	require.NoError(t, err)
+
+	_, err = prod.ValidateRefreshToken(refresh)
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+}
+
+func TestService_ShouldRejectToken_WhenIssuerMissing(t *testing.T) {
+	unbound := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour)
+	prod := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour,
+		WithIssuer("https://auth.prod.example.com"))
+
+	token, err := unbound.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	_, err = prod.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+}
+
+func TestService_ShouldValidateAudience(t *testing.T) {
+	issuerA := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour, WithAudience("service-a"))
+	issuerAB := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour, WithAudience("service-a", "service-b"))
+	verifierB := NewService("shared-secret", "shared-refresh", 15*time.Minute, 7*24*time.Hour, WithAudience("service-b"))
+
+	tokenA, err := issuerA.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+	_, err = verifierB.ValidateAccessToken(tokenA)
+	assert.ErrorIs(t, err, ErrInvalidAudience)
+
+	tokenAB, err := issuerAB.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+	_, err = verifierB.ValidateAccessToken(tokenAB)
+	assert.NoError(t, err)
+}
+
+// ============================================================
+// Asymmetric Signing Tests
+// ============================================================
+
+func newTestKeyManager(t *testing.T, kid string) *keys.Manager {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), kid+".pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	require.NoError(t, os.WriteFile(path, keyPEM, 0600))
+
+	km, err := keys.NewManager([]keys.KeyConfig{{ID: kid, Algorithm: keys.RS256, PrivateKeyPath: path}}, kid)
+	require.NoError(t, err)
+	return km
+}
+
+func TestService_ShouldSignWithKeyManager_WhenConfigured(t *testing.T) {
+	km := newTestKeyManager(t, "fp-1")
+	svc := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(km, false))
+
+	token, err := svc.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	parsed, _, err := new(jwtlib.Parser).ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "fp-1", parsed.Header["kid"])
+
+	claims, err := svc.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, TokenTypeAccess, claims.TokenType)
+
+	jwks := svc.GetJWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "fp-1", jwks.Keys[0].KID)
+}
+
+func TestService_ShouldRejectRefreshTokenAsAccessToken_WhenAsymmetric(t *testing.T) {
+	km := newTestKeyManager(t, "fp-1")
+	svc := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(km, false))
+
+	refresh, err := svc.GenerateRefreshToken(newTestUser())
+	require.NoError(t, err)
+	_, err = svc.ValidateAccessToken(refresh)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	access, err := svc.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+	_, err = svc.ValidateRefreshToken(access)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	_, err = svc.ValidateRefreshToken(refresh)
+	assert.NoError(t, err)
+}
+
+func TestService_ShouldDualVerify_WhenAcceptHMACEnabled(t *testing.T) {
+	legacy := newTestService()
+	legacyToken, err := legacy.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	km := newTestKeyManager(t, "fp-1")
+	migrating := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(km, true))
+	_, err = migrating.ValidateAccessToken(legacyToken)
+	assert.NoError(t, err)
+
+	strict := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(km, false))
+	_, err = strict.ValidateAccessToken(legacyToken)
+	assert.Error(t, err)
+}
+
+func TestService_ShouldRejectAsymmetricToken_WhenKeyUnknown(t *testing.T) {
+	signer := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(newTestKeyManager(t, "other"), false))
+	token, err := signer.GenerateAccessToken(newTestUser())
+	require.NoError(t, err)
+
+	verifier := NewService("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour, WithKeyManager(newTestKeyManager(t, "fp-1"), false))
+	_, err = verifier.ValidateAccessToken(token)
+	assert.Error(t, err)
+
+	hmacOnly := newTestService()
+	_, err = hmacOnly.ValidateAccessToken(token)
+	assert.Error(t, err)
+}
+
+func TestService_GetJWKS_ShouldBeEmpty_WhenHMAC(t *testing.T) {
+	svc := newTestService()
+	assert.Empty(t, svc.GetJWKS().Keys)
+	assert.False(t, svc.IsAsymmetric())
+}
+
+// ============================================================
+// GenerateOAuthLinkToken Tests
+// ============================================================
+
+func TestService_GenerateOAuthLinkToken_ShouldOnlyValidateAsOAuthLink(t *testing.T) {
+	svc := newTestService()
+	user := newTestUser()
+	appID := uuid.New()
+
+	token, err := svc.GenerateOAuthLinkToken(user, "google", "google-uid", &appID)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateOAuthLinkToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, "google", claims.OAuthProvider)
+	assert.Equal(t, "google-uid", claims.OAuthProviderUserID)
+	require.NotNil(t, claims.ApplicationID)
+	assert.Equal(t, appID, *claims.ApplicationID)
+	assert.WithinDuration(t, time.Now().Add(OAuthLinkTokenTTL), claims.ExpiresAt.Time, 2*time.Second)
+
+	// Must not be usable as an access or password change token
+	_, err = svc.ValidateAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+	_, err = svc.ValidatePasswordChangeToken(token)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	// Other tokens must not be usable as link tokens
+	accessToken, err := svc.GenerateAccessToken(user)
+	require.NoError(t, err)
+	_, err = svc.ValidateOAuthLinkToken(accessToken)
+	assert.Error(t, err)
+}
+
+// ============================================================
+// JARM Authorization Response Tests
+// ============================================================
+
+func TestOIDCService_GenerateAuthorizationResponse_ShouldCarryResponseParameters(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+
+	token, err := svc.GenerateAuthorizationResponse("app", url.Values{"code": {"code-1"}, "state": {"s1"}}, 5*time.Minute)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateAuthorizationResponse(token, "app")
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", claims.Issuer)
+	assert.Equal(t, "code-1", claims.Code)
+	assert.Equal(t, "s1", claims.State)
+	assert.Empty(t, claims.Error)
+
+	_, err = svc.ValidateAuthorizationResponse(token, "other-app")
+	assert.Error(t, err)
+}
+
+func TestOIDCService_GenerateAuthorizationResponse_ShouldCarryErrors(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+
+	token, err := svc.GenerateAuthorizationResponse("app", url.Values{"error": {"access_denied"}, "error_description": {"User denied consent"}}, 5*time.Minute)
+	require.NoError(t, err)
+
+	claims, err := svc.ValidateAuthorizationResponse(token, "app")
+	require.NoError(t, err)
+	assert.Equal(t, "access_denied", claims.Error)
+	assert.Equal(t, "User denied consent", claims.ErrorDescription)
+	assert.Empty(t, claims.Code)
+}
+
+// ============================================================
+// Back-Channel Logout Token Tests
+// ============================================================
+
+func TestOIDCService_GenerateLogoutToken_ShouldCarryLogoutEvent(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+	userID := uuid.New()
+
+	token, err := svc.GenerateLogoutToken("app", userID.String(), 2*time.Minute)
+	require.NoError(t, err)
+
+	parsed, _, err := new(jwtlib.Parser).ParseUnverified(token, &LogoutTokenClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "logout+jwt", parsed.Header["typ"])
+
+	claims, err := svc.ValidateLogoutToken(token, "app")
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), claims.Subject)
+	assert.NotEmpty(t, claims.ID)
+	assert.Contains(t, claims.Events, BackchannelLogoutEvent)
+
+	_, err = svc.ValidateLogoutToken(token, "other-app")
+	assert.Error(t, err)
+}
+
+func TestOIDCService_ValidateLogoutToken_ShouldRejectIDTokens(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+
+	idToken, err := svc.GenerateIDToken(uuid.New(), "app", "", []string{"openid"}, newTestUser(), time.Hour)
+	require.NoError(t, err)
+
+	_, err = svc.ValidateLogoutToken(idToken, "app")
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
+
+// ============================================================
+// ID Token Hint Tests
+// ============================================================
+
+func TestOIDCService_ValidateIDTokenHint_ShouldAcceptExpiredIDTokens(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+	userID := uuid.New()
+
+	idToken, err := svc.GenerateIDToken(userID, "app", "", []string{"openid"}, newTestUser(), -time.Hour)
+	require.NoError(t, err)
+
+	_, err = svc.ValidateIDToken(idToken)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+
+	claims, err := svc.ValidateIDTokenHint(idToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), claims.Subject)
+	assert.Equal(t, jwtlib.ClaimStrings{"app"}, claims.Audience)
+}
+
+func TestOIDCService_ValidateIDTokenHint_ShouldRejectOtherTokens(t *testing.T) {
+	svc := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+
+	logoutToken, err := svc.GenerateLogoutToken("app", uuid.New().String(), time.Minute)
+	require.NoError(t, err)
+	_, err = svc.ValidateIDTokenHint(logoutToken)
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	other := NewOIDCService(newTestKeyManager(t, "fp-1"), "https://auth.example.com")
+	idToken, err := other.GenerateIDToken(uuid.New(), "app", "", []string{"openid"}, newTestUser(), time.Hour)
+	require.NoError(t, err)
+	_, err = svc.ValidateIDTokenHint(idToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
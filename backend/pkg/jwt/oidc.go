@@ -3,8 +3,12 @@ package jwt
 import (
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -24,6 +28,7 @@ var (
 type OIDCService struct {
 	keyManager *keys.Manager
 	issuer     string
+	clock      Clock
 }
 
 type IDTokenClaims struct {
@@ -34,6 +39,8 @@ type IDTokenClaims struct {
 	ACR      string   `json:"acr,omitempty"`
 	AMR      []string `json:"amr,omitempty"`
 	AZP      string   `json:"azp,omitempty"`
+	// SessionID identifies the user's session at the client, see OIDCService.SessionID
+	SessionID string `json:"sid,omitempty"`
 
 	Name              string `json:"name,omitempty"`
 	GivenName         string `json:"given_name,omitempty"`
@@ -57,6 +64,42 @@ type IDTokenClaims struct {
 	PhoneVerified bool   `json:"phone_number_verified,omitempty"`
 
 	Address *AddressClaim `json:"address,omitempty"`
+
+	// Custom claims of the client's claims mapping
+	Custom map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON writes the custom claims next to the standard ones, which take precedence
+func (c IDTokenClaims) MarshalJSON() ([]byte, error) {
+	type idTokenClaims IDTokenClaims
+	data, err := json.Marshal(idTokenClaims(c))
+	if err != nil || len(c.Custom) == 0 {
+		return data, err
+	}
+	return models.MergeClaims(data, c.Custom)
+}
+
+// AuthorizationResponseClaims are the claims of a JWT Secured Authorization Response (JARM):
+// the parameters of the authorization response, issued by the provider to the client
+type AuthorizationResponseClaims struct {
+	jwt.RegisteredClaims
+
+	Code             string `json:"code,omitempty"`
+	State            string `json:"state,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// BackchannelLogoutEvent is the member of a logout token's events claim that identifies it
+// as a back-channel logout request (OIDC Back-Channel Logout 1.0)
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenClaims are the claims of a back-channel logout token
+type LogoutTokenClaims struct {
+	jwt.RegisteredClaims
+
+	SessionID string                            `json:"sid,omitempty"`
+	Events    map[string]map[string]interface{} `json:"events"`
 }
 
 type AddressClaim struct {
@@ -75,6 +118,15 @@ type OAuthAccessTokenClaims struct {
 	ClientID  string   `json:"client_id,omitempty"`
 	TokenType string   `json:"token_type,omitempty"`
 	Roles     []string `json:"roles,omitempty"`
+
+	// Set when the token is bound to a client certificate (RFC 8705)
+	Cnf *Confirmation `json:"cnf,omitempty"`
+}
+
+// Confirmation identifies the key a token is bound to (RFC 7800). X5TS256 is the
+// base64url SHA-256 thumbprint of the client certificate.
+type Confirmation struct {
+	X5TS256 string `json:"x5t#S256"`
 }
 
 func NewOIDCService(keyManager *keys.Manager, issuer string) *OIDCService {
@@ -84,9 +136,41 @@ func NewOIDCService(keyManager *keys.Manager, issuer string) *OIDCService {
 	}
 }
 
+// Issuer returns the iss claim of the tokens the service issues
+func (s *OIDCService) Issuer() string {
+	return s.issuer
+}
+
+// SessionID returns the sid claim of the ID tokens issued to clientID for userID, which
+// front-channel logout requests carry. Logout ends every session of the user, so the value
+// is stable across sign-ins and needs no storage.
+func (s *OIDCService) SessionID(userID uuid.UUID, clientID string) string {
+	sum := sha256.Sum256([]byte(s.issuer + "\x00" + userID.String() + "\x00" + clientID))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SetClock sets the time source and clock skew tolerance for issuing and validating tokens
+func (s *OIDCService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
 func (s *OIDCService) GenerateIDToken(userID uuid.UUID, clientID, nonce string, scopes []string, user *models.User, ttl time.Duration) (string, error) {
-	claims := s.BuildIDTokenClaims(userID, clientID, nonce, scopes, user, ttl)
+	return s.SignIDToken(s.BuildIDTokenClaims(userID, clientID, nonce, scopes, user, ttl))
+}
+
+// SignIDToken signs ID token claims built with BuildIDTokenClaims, for callers that add
+// custom claims first
+func (s *OIDCService) SignIDToken(claims *IDTokenClaims) (string, error) {
+	return s.sign(claims, "")
+}
+
+func (s *OIDCService) GenerateOAuthAccessToken(userID *uuid.UUID, clientID string, scope string, roles []string, ttl time.Duration) (string, error) {
+	return s.SignOAuthAccessToken(s.BuildOAuthAccessTokenClaims(userID, clientID, scope, roles, ttl))
+}
 
+// SignOAuthAccessToken signs access token claims built with BuildOAuthAccessTokenClaims,
+// for callers that add claims such as cnf first
+func (s *OIDCService) SignOAuthAccessToken(claims *OAuthAccessTokenClaims) (string, error) {
 	signingKey, err := s.keyManager.GetCurrentKey()
 	if err != nil {
 		return "", fmt.Errorf("failed to get signing key: %w", err)
@@ -103,26 +187,97 @@ func (s *OIDCService) GenerateIDToken(userID uuid.UUID, clientID, nonce string,
 	return token.SignedString(signingKey.PrivateKey)
 }
 
-func (s *OIDCService) GenerateOAuthAccessToken(userID *uuid.UUID, clientID string, scope string, roles []string, ttl time.Duration) (string, error) {
-	now := time.Now()
-	claims := &OAuthAccessTokenClaims{
+// GenerateAuthorizationResponse signs the parameters of an authorization response (code, state,
+// error, error_description) for clientID as a JARM response JWT
+func (s *OIDCService) GenerateAuthorizationResponse(clientID string, params url.Values, ttl time.Duration) (string, error) {
+	now := s.clock.now()
+	claims := &AuthorizationResponseClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings{clientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Code:             params.Get("code"),
+		State:            params.Get("state"),
+		Error:            params.Get("error"),
+		ErrorDescription: params.Get("error_description"),
+	}
+
+	return s.sign(claims, "")
+}
+
+// ValidateAuthorizationResponse verifies a JARM response JWT issued to clientID
+func (s *OIDCService) ValidateAuthorizationResponse(tokenString, clientID string) (*AuthorizationResponseClaims, error) {
+	opts := append(s.clock.parserOptions(), jwt.WithAudience(clientID))
+	token, err := jwt.ParseWithClaims(tokenString, &AuthorizationResponseClaims{}, s.keyFunc, opts...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(*AuthorizationResponseClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidClaims
+	}
+
+	if claims.Issuer != s.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	return claims, nil
+}
+
+// GenerateLogoutToken signs a back-channel logout token telling clientID that subject was
+// logged out. Logout tokens carry no nonce and are typed logout+jwt so they cannot be
+// mistaken for ID tokens.
+func (s *OIDCService) GenerateLogoutToken(clientID, subject string, ttl time.Duration) (string, error) {
+	now := s.clock.now()
+	claims := &LogoutTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{clientID},
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
-			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 			ID:        uuid.New().String(),
 		},
-		Scope:     scope,
-		ClientID:  clientID,
-		TokenType: "Bearer",
-		Roles:     roles,
+		Events: map[string]map[string]interface{}{BackchannelLogoutEvent: {}},
 	}
 
-	if userID != nil {
-		claims.Subject = userID.String()
+	return s.sign(claims, "logout+jwt")
+}
+
+// ValidateLogoutToken verifies a back-channel logout token issued to clientID
+func (s *OIDCService) ValidateLogoutToken(tokenString, clientID string) (*LogoutTokenClaims, error) {
+	opts := append(s.clock.parserOptions(), jwt.WithAudience(clientID))
+	token, err := jwt.ParseWithClaims(tokenString, &LogoutTokenClaims{}, s.keyFunc, opts...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
+	claims, ok := token.Claims.(*LogoutTokenClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidClaims
+	}
+
+	if claims.Issuer != s.issuer {
+		return nil, ErrInvalidIssuer
+	}
+	if _, ok := claims.Events[BackchannelLogoutEvent]; !ok || (claims.Subject == "" && claims.SessionID == "") {
+		return nil, ErrInvalidClaims
+	}
+
+	return claims, nil
+}
+
+// sign signs claims with the current key, setting the typ header when given
+func (s *OIDCService) sign(claims jwt.Claims, typ string) (string, error) {
 	signingKey, err := s.keyManager.GetCurrentKey()
 	if err != nil {
 		return "", fmt.Errorf("failed to get signing key: %w", err)
@@ -135,12 +290,38 @@ func (s *OIDCService) GenerateOAuthAccessToken(userID *uuid.UUID, clientID strin
 
 	token := jwt.NewWithClaims(signingMethod, claims)
 	token.Header["kid"] = signingKey.KID
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
 
 	return token.SignedString(signingKey.PrivateKey)
 }
 
+func (s *OIDCService) BuildOAuthAccessTokenClaims(userID *uuid.UUID, clientID string, scope string, roles []string, ttl time.Duration) *OAuthAccessTokenClaims {
+	now := s.clock.now()
+	claims := &OAuthAccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        uuid.New().String(),
+		},
+		Scope:     scope,
+		ClientID:  clientID,
+		TokenType: "Bearer",
+		Roles:     roles,
+	}
+
+	if userID != nil {
+		claims.Subject = userID.String()
+	}
+
+	return claims
+}
+
 func (s *OIDCService) ValidateIDToken(tokenString string) (*IDTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, s.keyFunc)
+	token, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, s.keyFunc, s.clock.parserOptions()...)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
@@ -160,8 +341,34 @@ func (s *OIDCService) ValidateIDToken(tokenString string) (*IDTokenClaims, error
 	return claims, nil
 }
 
+// ValidateIDTokenHint validates an ID token passed back as id_token_hint in a logout request.
+// Relying parties commonly send tokens that have expired by then, so expiry is not checked;
+// the signature and issuer are.
+func (s *OIDCService) ValidateIDTokenHint(tokenString string) (*IDTokenClaims, error) {
+	opts := append(s.clock.parserOptions(), jwt.WithoutClaimsValidation())
+	token, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, s.keyFunc, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(*IDTokenClaims)
+	if !ok || !token.Valid || claims.Subject == "" || len(claims.Audience) == 0 {
+		return nil, ErrInvalidClaims
+	}
+	// Logout tokens are signed with the same keys
+	if typ, _ := token.Header["typ"].(string); typ != "" && typ != "JWT" {
+		return nil, ErrInvalidClaims
+	}
+
+	if claims.Issuer != s.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	return claims, nil
+}
+
 func (s *OIDCService) ValidateOAuthAccessToken(tokenString string) (*OAuthAccessTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &OAuthAccessTokenClaims{}, s.keyFunc)
+	token, err := jwt.ParseWithClaims(tokenString, &OAuthAccessTokenClaims{}, s.keyFunc, s.clock.parserOptions()...)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
@@ -190,7 +397,7 @@ func (s *OIDCService) GetSigningKeyID() (string, error) {
 }
 
 func (s *OIDCService) BuildIDTokenClaims(userID uuid.UUID, clientID, nonce string, scopes []string, user *models.User, ttl time.Duration) *IDTokenClaims {
-	now := time.Now()
+	now := s.clock.now()
 	claims := &IDTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
@@ -200,8 +407,9 @@ func (s *OIDCService) BuildIDTokenClaims(userID uuid.UUID, clientID, nonce strin
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		AuthTime: now.Unix(),
-		AZP:      clientID,
+		AuthTime:  now.Unix(),
+		AZP:       clientID,
+		SessionID: s.SessionID(userID, clientID),
 	}
 
 	if nonce != "" {
@@ -243,12 +451,21 @@ func (s *OIDCService) GetIssuer() string {
 }
 
 func (s *OIDCService) keyFunc(token *jwt.Token) (interface{}, error) {
+	return verificationKey(s.keyManager, token)
+}
+
+func (s *OIDCService) getSigningMethod(algorithm keys.Algorithm) (jwt.SigningMethod, error) {
+	return signingMethodFor(algorithm)
+}
+
+// verificationKey resolves the public key for a token's kid header and checks it matches the token's algorithm
+func verificationKey(keyManager *keys.Manager, token *jwt.Token) (interface{}, error) {
 	kid, ok := token.Header["kid"].(string)
 	if !ok || kid == "" {
 		return nil, ErrInvalidKID
 	}
 
-	signingKey, err := s.keyManager.GetKey(kid)
+	signingKey, err := keyManager.GetKey(kid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get key %s: %w", kid, err)
 	}
@@ -279,7 +496,7 @@ func (s *OIDCService) keyFunc(token *jwt.Token) (interface{}, error) {
 	}
 }
 
-func (s *OIDCService) getSigningMethod(algorithm keys.Algorithm) (jwt.SigningMethod, error) {
+func signingMethodFor(algorithm keys.Algorithm) (jwt.SigningMethod, error) {
 	switch algorithm {
 	case keys.RS256:
 		return jwt.SigningMethodRS256, nil
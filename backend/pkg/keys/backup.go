@@ -0,0 +1,311 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// BackupFormat identifies encrypted signing key backups
+	BackupFormat = "auth-gateway-key-backup"
+
+	backupVersion = 1
+
+	// MinBackupPassphraseLength is the shortest passphrase backups are encrypted with
+	MinBackupPassphraseLength = 16
+
+	// scrypt cost: twice the N recommended for interactive logins in 2017
+	scryptN = 1 << 16
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrBackupDecrypt is returned for a wrong passphrase or a tampered backup
+var ErrBackupDecrypt = errors.New("failed to decrypt key backup: wrong passphrase or corrupted file")
+
+// Backup is the plaintext of a signing key backup
+type Backup struct {
+	CreatedAt  time.Time   `json:"created_at"`
+	CurrentKID string      `json:"current_kid"`
+	Keys       []BackupKey `json:"keys"`
+}
+
+// BackupKey is a signing key in a backup; PrivateKey is PKCS#8 PEM
+type BackupKey struct {
+	KID        string    `json:"kid"`
+	Algorithm  Algorithm `json:"alg"`
+	PrivateKey string    `json:"private_key"`
+}
+
+// EncryptedBackup is the file format of a backup: the JSON of a Backup sealed with
+// AES-256-GCM under a key derived from a passphrase with scrypt. The key IDs are kept in
+// the clear, authenticated, so a backup can be identified without the passphrase.
+type EncryptedBackup struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	KIDs       []string  `json:"kids"`
+	KDF        string    `json:"kdf"`
+	N          int       `json:"n"`
+	R          int       `json:"r"`
+	P          int       `json:"p"`
+	Salt       []byte    `json:"salt"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// KeyInfo describes a loaded signing key without its private part
+type KeyInfo struct {
+	KID        string    `json:"kid"`
+	Algorithm  Algorithm `json:"alg"`
+	Current    bool      `json:"current"`
+	Thumbprint string    `json:"thumbprint"` // RFC 7638 JWK thumbprint
+}
+
+// Keys lists the loaded keys, ordered by key ID
+func (m *Manager) Keys() []KeyInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]KeyInfo, 0, len(m.keys))
+	for kid, key := range m.keys {
+		infos = append(infos, KeyInfo{
+			KID:        kid,
+			Algorithm:  key.Algorithm,
+			Current:    kid == m.currentKID,
+			Thumbprint: Thumbprint(key),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].KID < infos[j].KID })
+	return infos
+}
+
+// Backup returns all loaded keys, ordered by key ID
+func (m *Manager) Backup() (*Backup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	backup := &Backup{
+		CreatedAt:  time.Now().UTC(),
+		CurrentKID: m.currentKID,
+		Keys:       make([]BackupKey, 0, len(m.keys)),
+	}
+	for _, key := range m.keys {
+		encoded, err := EncodePrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode key %s: %w", key.KID, err)
+		}
+		backup.Keys = append(backup.Keys, BackupKey{KID: key.KID, Algorithm: key.Algorithm, PrivateKey: string(encoded)})
+	}
+	sort.Slice(backup.Keys, func(i, j int) bool { return backup.Keys[i].KID < backup.Keys[j].KID })
+	return backup, nil
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key's public JWK, base64url encoded
+func Thumbprint(key *SigningKey) string {
+	var members string
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk := RSAPublicKeyToJWK(pub, key.KID, string(key.Algorithm))
+		members = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, jwk.E, jwk.KTY, jwk.N)
+	case *ecdsa.PublicKey:
+		jwk := ECDSAPublicKeyToJWK(pub, key.KID, string(key.Algorithm))
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, jwk.Crv, jwk.KTY, jwk.X, jwk.Y)
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64URLEncode(sum[:])
+}
+
+// Encrypt seals the backup with passphrase
+func (b *Backup) Encrypt(passphrase []byte) ([]byte, error) {
+	if len(passphrase) < MinBackupPassphraseLength {
+		return nil, fmt.Errorf("backup passphrase must be at least %d characters", MinBackupPassphraseLength)
+	}
+
+	plaintext, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &EncryptedBackup{
+		Format:    BackupFormat,
+		Version:   backupVersion,
+		CreatedAt: b.CreatedAt,
+		KIDs:      make([]string, 0, len(b.Keys)),
+		KDF:       "scrypt",
+		N:         scryptN,
+		R:         scryptR,
+		P:         scryptP,
+		Salt:      make([]byte, 16),
+	}
+	for _, key := range b.Keys {
+		envelope.KIDs = append(envelope.KIDs, key.KID)
+	}
+	if _, err := rand.Read(envelope.Salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return nil, err
+	}
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, plaintext, envelope.additionalData())
+
+	return json.MarshalIndent(envelope, "", "  ")
+}
+
+// DecryptBackup opens a backup written by Backup.Encrypt
+func DecryptBackup(data, passphrase []byte) (*Backup, error) {
+	var envelope EncryptedBackup
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse key backup: %w", err)
+	}
+	if envelope.Format != BackupFormat || envelope.Version != backupVersion || envelope.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key backup format %q version %d", envelope.Format, envelope.Version)
+	}
+
+	aead, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, ErrBackupDecrypt
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, envelope.additionalData())
+	if err != nil {
+		return nil, ErrBackupDecrypt
+	}
+
+	var backup Backup
+	if err := json.Unmarshal(plaintext, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse key backup: %w", err)
+	}
+	for _, key := range backup.Keys {
+		if _, err := parseBackupKey(key); err != nil {
+			return nil, fmt.Errorf("key %s: %w", key.KID, err)
+		}
+	}
+	return &backup, nil
+}
+
+func (e *EncryptedBackup) aead(passphrase []byte) (cipher.AEAD, error) {
+	// Bound the work factor (memory is 128*N*r bytes), so a crafted file cannot exhaust memory
+	if e.N <= 1 || e.N > 1<<18 || e.R <= 0 || e.R > 8 || e.P <= 0 || e.P > 4 {
+		return nil, fmt.Errorf("unsupported scrypt parameters in key backup")
+	}
+	key, err := scrypt.Key(passphrase, e.Salt, e.N, e.R, e.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData authenticates the cleartext header
+func (e *EncryptedBackup) additionalData() []byte {
+	header := *e
+	header.Nonce, header.Ciphertext = nil, nil
+	data, _ := json.Marshal(header)
+	return data
+}
+
+// WriteKeyFiles writes every key of the backup to dir as <kid>.pem, readable by the owner
+// only, and returns the configuration to load them with
+func (b *Backup) WriteKeyFiles(dir string) ([]KeyConfig, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	configs := make([]KeyConfig, 0, len(b.Keys))
+	for _, key := range b.Keys {
+		if key.KID == "" || filepath.Base(key.KID) != key.KID {
+			return nil, fmt.Errorf("invalid key ID %q", key.KID)
+		}
+		path := filepath.Join(dir, key.KID+".pem")
+		// O_EXCL: never overwrite a key that may still be in use
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		_, err = f.WriteString(key.PrivateKey)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, KeyConfig{ID: key.KID, Algorithm: key.Algorithm, PrivateKeyPath: path})
+	}
+	return configs, nil
+}
+
+func parseBackupKey(key BackupKey) (interface{}, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.Algorithm {
+	case RS256:
+		if _, ok := privateKey.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("not an RSA private key")
+		}
+	case ES256:
+		if _, ok := privateKey.(*ecdsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("not an ECDSA private key")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", key.Algorithm)
+	}
+	return privateKey, nil
+}
+
+// GenerateKey creates a new private key for algorithm: RSA 2048 for RS256, P-256 for ES256
+func GenerateKey(algorithm Algorithm) (interface{}, error) {
+	switch algorithm {
+	case RS256:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+	}
+}
+
+// EncodePrivateKey encodes a private key as PKCS#8 PEM, which LoadRSAPrivateKey and
+// LoadECDSAPrivateKey read
+func EncodePrivateKey(privateKey interface{}) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,130 @@
+package keys
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPassphrase = []byte("correct horse battery staple")
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	tmpDir, cleanup := createTempKeyFiles(t)
+	t.Cleanup(cleanup)
+
+	manager, err := NewManager([]KeyConfig{
+		{ID: "rsa-key", Algorithm: RS256, PrivateKeyPath: filepath.Join(tmpDir, "rsa_private.pem")},
+		{ID: "ecdsa-key", Algorithm: ES256, PrivateKeyPath: filepath.Join(tmpDir, "ecdsa_private.pem")},
+	}, "rsa-key")
+	require.NoError(t, err)
+	return manager
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	manager := newTestManager(t)
+	backup, err := manager.Backup()
+	require.NoError(t, err)
+
+	encrypted, err := backup.Encrypt(testPassphrase)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "PRIVATE KEY")
+
+	restored, err := DecryptBackup(encrypted, testPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-key", restored.CurrentKID)
+	require.Len(t, restored.Keys, 2)
+
+	dir := filepath.Join(t.TempDir(), "keys")
+	configs, err := restored.WriteKeyFiles(dir)
+	require.NoError(t, err)
+
+	imported, err := NewManager(configs, restored.CurrentKID)
+	require.NoError(t, err)
+	assert.Equal(t, manager.Keys(), imported.Keys())
+
+	info, err := os.Stat(filepath.Join(dir, "rsa-key.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestDecryptBackup_ShouldFail_WhenPassphraseIsWrong(t *testing.T) {
+	backup, err := newTestManager(t).Backup()
+	require.NoError(t, err)
+	encrypted, err := backup.Encrypt(testPassphrase)
+	require.NoError(t, err)
+
+	_, err = DecryptBackup(encrypted, []byte("wrong horse battery staple"))
+
+	assert.ErrorIs(t, err, ErrBackupDecrypt)
+}
+
+func TestDecryptBackup_ShouldFail_WhenHeaderIsTampered(t *testing.T) {
+	backup, err := newTestManager(t).Backup()
+	require.NoError(t, err)
+	encrypted, err := backup.Encrypt(testPassphrase)
+	require.NoError(t, err)
+
+	var envelope EncryptedBackup
+	require.NoError(t, json.Unmarshal(encrypted, &envelope))
+	envelope.KIDs = []string{"other-key"}
+	tampered, err := json.Marshal(envelope)
+	require.NoError(t, err)
+
+	_, err = DecryptBackup(tampered, testPassphrase)
+
+	assert.ErrorIs(t, err, ErrBackupDecrypt)
+}
+
+func TestBackup_Encrypt_ShouldRejectShortPassphrase(t *testing.T) {
+	backup, err := newTestManager(t).Backup()
+	require.NoError(t, err)
+
+	_, err = backup.Encrypt([]byte("short"))
+
+	assert.Error(t, err)
+}
+
+func TestWriteKeyFiles_ShouldNotOverwriteExistingKeys(t *testing.T) {
+	backup, err := newTestManager(t).Backup()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rsa-key.pem"), []byte("existing"), 0o600))
+
+	_, err = backup.WriteKeyFiles(dir)
+
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestGenerateKey(t *testing.T) {
+	for _, alg := range []Algorithm{RS256, ES256} {
+		t.Run(string(alg), func(t *testing.T) {
+			privateKey, err := GenerateKey(alg)
+			require.NoError(t, err)
+			encoded, err := EncodePrivateKey(privateKey)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, os.WriteFile(path, encoded, 0o600))
+			manager, err := NewManager([]KeyConfig{{ID: "new", Algorithm: alg, PrivateKeyPath: path}}, "new")
+			require.NoError(t, err)
+			assert.NotEmpty(t, manager.Keys()[0].Thumbprint)
+		})
+	}
+}
+
+func TestThumbprint_RFC7638Example(t *testing.T) {
+	// The RSA key of RFC 7638, section 3.1
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+	key := &SigningKey{KID: "2011-04-29", Algorithm: RS256, PublicKey: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", Thumbprint(key))
+}
@@ -27,6 +27,9 @@ type KeyConfig struct {
 	Algorithm      Algorithm
 	PrivateKeyPath string
 	PublicKeyPath  string
+	// PrivateKeyPEM is the private key itself, e.g. read from a secrets manager; it takes
+	// precedence over PrivateKeyPath
+	PrivateKeyPEM []byte
 }
 
 type Manager struct {
@@ -85,6 +88,62 @@ func NewManager(configs []KeyConfig, currentKID string) (*Manager, error) {
 	return manager, nil
 }
 
+// NewManagerFromKeys creates a manager from keys already in memory, e.g. keys read from
+// the database
+func NewManagerFromKeys(signingKeys []*SigningKey, currentKID string) (*Manager, error) {
+	manager := &Manager{}
+	if err := manager.SetKeys(signingKeys, currentKID); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+// SetKeys replaces all keys at once; tokens signed with a key that is no longer listed stop
+// verifying
+func (m *Manager) SetKeys(signingKeys []*SigningKey, currentKID string) error {
+	loaded := make(map[string]*SigningKey, len(signingKeys))
+	for _, key := range signingKeys {
+		loaded[key.KID] = key
+	}
+	if _, exists := loaded[currentKID]; !exists {
+		return fmt.Errorf("current key ID %s not found in loaded keys", currentKID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = loaded
+	m.currentKID = currentKID
+	return nil
+}
+
+// NewSigningKey wraps a private key of algorithm, as returned by GenerateKey
+func NewSigningKey(kid string, algorithm Algorithm, privateKey interface{}) (*SigningKey, error) {
+	var publicKey interface{}
+	switch algorithm {
+	case RS256:
+		rsaPrivateKey, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("not an RSA private key")
+		}
+		publicKey = &rsaPrivateKey.PublicKey
+	case ES256:
+		ecdsaPrivateKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("not an ECDSA private key")
+		}
+		publicKey = &ecdsaPrivateKey.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+	}
+
+	return &SigningKey{
+		KID:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
 func loadSigningKey(config KeyConfig) (*SigningKey, error) {
 	var privateKey interface{}
 	var publicKey interface{}
@@ -92,7 +151,11 @@ func loadSigningKey(config KeyConfig) (*SigningKey, error) {
 
 	switch config.Algorithm {
 	case RS256:
-		privateKey, err = LoadRSAPrivateKey(config.PrivateKeyPath)
+		if config.PrivateKeyPEM != nil {
+			privateKey, err = ParseRSAPrivateKey(config.PrivateKeyPEM)
+		} else {
+			privateKey, err = LoadRSAPrivateKey(config.PrivateKeyPath)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -100,7 +163,11 @@ func loadSigningKey(config KeyConfig) (*SigningKey, error) {
 		publicKey = &rsaPrivateKey.PublicKey
 
 	case ES256:
-		privateKey, err = LoadECDSAPrivateKey(config.PrivateKeyPath)
+		if config.PrivateKeyPEM != nil {
+			privateKey, err = ParseECDSAPrivateKey(config.PrivateKeyPEM)
+		} else {
+			privateKey, err = LoadECDSAPrivateKey(config.PrivateKeyPath)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -260,7 +327,11 @@ func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read key file: %w", err)
 	}
+	return ParseRSAPrivateKey(data)
+}
 
+// ParseRSAPrivateKey parses a PEM encoded PKCS#1 or PKCS#8 RSA private key
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
 		return nil, fmt.Errorf("failed to decode PEM block")
@@ -287,7 +358,11 @@ func LoadECDSAPrivateKey(path string) (*ecdsa.PrivateKey, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read key file: %w", err)
 	}
+	return ParseECDSAPrivateKey(data)
+}
 
+// ParseECDSAPrivateKey parses a PEM encoded SEC 1 or PKCS#8 ECDSA private key
+func ParseECDSAPrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
 		return nil, fmt.Errorf("failed to decode PEM block")
@@ -125,6 +125,72 @@ func TestNewManager_Success(t *testing.T) {
 	assert.Equal(t, "key-1", manager.currentKID)
 }
 
+func TestNewManager_PrivateKeyPEM(t *testing.T) {
+	tmpDir, cleanup := createTempKeyFiles(t)
+	defer cleanup()
+
+	rsaPEM, err := os.ReadFile(filepath.Join(tmpDir, "rsa_private.pem"))
+	require.NoError(t, err)
+	ecdsaPEM, err := os.ReadFile(filepath.Join(tmpDir, "ecdsa_private.pem"))
+	require.NoError(t, err)
+
+	configs := []KeyConfig{
+		{
+			ID:             "key-1",
+			Algorithm:      RS256,
+			PrivateKeyPEM:  rsaPEM,
+			PrivateKeyPath: "/nonexistent/path/key.pem",
+		},
+		{
+			ID:            "key-2",
+			Algorithm:     ES256,
+			PrivateKeyPEM: ecdsaPEM,
+		},
+	}
+
+	manager, err := NewManager(configs, "key-1")
+	require.NoError(t, err)
+	assert.Len(t, manager.keys, 2)
+
+	_, err = NewManager([]KeyConfig{{ID: "key-1", Algorithm: RS256, PrivateKeyPEM: []byte("not a key")}}, "key-1")
+	assert.Error(t, err)
+}
+
+func TestManager_SetKeys(t *testing.T) {
+	rsaKey, err := GenerateKey(RS256)
+	require.NoError(t, err)
+	ecdsaKey, err := GenerateKey(ES256)
+	require.NoError(t, err)
+
+	oldKey, err := NewSigningKey("old", RS256, rsaKey)
+	require.NoError(t, err)
+	newKey, err := NewSigningKey("new", ES256, ecdsaKey)
+	require.NoError(t, err)
+	_, err = NewSigningKey("wrong", ES256, rsaKey)
+	assert.Error(t, err)
+
+	manager, err := NewManagerFromKeys([]*SigningKey{oldKey}, "old")
+	require.NoError(t, err)
+	signature, kid, err := manager.Sign([]byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", kid)
+
+	// The old key still verifies after the new one takes over
+	require.NoError(t, manager.SetKeys([]*SigningKey{oldKey, newKey}, "new"))
+	assert.NoError(t, manager.Verify([]byte("data"), signature, "old"))
+	_, kid, err = manager.Sign([]byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", kid)
+	assert.Len(t, manager.GetJWKS().Keys, 2)
+
+	// A failed update keeps the keys
+	assert.Error(t, manager.SetKeys([]*SigningKey{oldKey}, "new"))
+	assert.Len(t, manager.GetJWKS().Keys, 2)
+
+	require.NoError(t, manager.SetKeys([]*SigningKey{newKey}, "new"))
+	assert.Error(t, manager.Verify([]byte("data"), signature, "old"))
+}
+
 func TestNewManager_NoKeys(t *testing.T) {
 	manager, err := NewManager([]KeyConfig{}, "key-1")
 	assert.Error(t, err)
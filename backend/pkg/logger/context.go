@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a context carrying fields that are added to every entry logged
+// with it (the *Context methods and slog's context-aware calls). Fields already carried are
+// kept unless overridden by key. Middleware uses it for request and user IDs so call sites
+// don't repeat them.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := contextAttrs(ctx)
+	attrs := make([]slog.Attr, 0, len(parent)+len(fields))
+	for _, a := range parent {
+		if _, overridden := fields[a.Key]; !overridden {
+			attrs = append(attrs, a)
+		}
+	}
+	attrs = append(attrs, fieldAttrs(fields)...)
+	return context.WithValue(ctx, contextFieldsKey{}, attrs)
+}
+
+// FieldsFromContext returns the fields carried by ctx
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	attrs := contextAttrs(ctx)
+	if len(attrs) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		addField(fields, a)
+	}
+	return fields
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(contextFieldsKey{}).([]slog.Attr)
+	return attrs
+}
@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// handler runs every entry through sampling, context fields and redaction before the
+// output handler
+type handler struct {
+	next    slog.Handler
+	level   *slog.LevelVar
+	sampler *sampler
+	redact  *redactor
+}
+
+// Enabled implements slog.Handler
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if h.sampler != nil && !h.sampler.allow(r.Level, r.Message, r.Time) {
+		return nil
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	for _, a := range contextAttrs(ctx) {
+		out.AddAttrs(h.redact.attr(a))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redact.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact.attr(a)
+	}
+	clone := *h
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *handler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// fieldAttrs converts a fields map to attributes, with nested maps as groups so that
+// redaction reaches their keys. Keys are sorted to keep output stable.
+func fieldAttrs(fields map[string]interface{}) []slog.Attr {
+	if len(fields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, key := range keys {
+		attrs = append(attrs, fieldAttr(key, fields[key]))
+	}
+	return attrs
+}
+
+func fieldAttr(key string, value interface{}) slog.Attr {
+	if nested, ok := value.(map[string]interface{}); ok {
+		return slog.Attr{Key: key, Value: slog.GroupValue(fieldAttrs(nested)...)}
+	}
+	return slog.Any(key, value)
+}
+
+// textHandler writes entries in the plain layout of earlier releases:
+// [timestamp] level [service] message {"field":...}
+type textHandler struct {
+	w       io.Writer
+	mu      *sync.Mutex
+	level   slog.Leveler
+	service string
+	fields  map[string]interface{} // from WithAttrs, nested by group
+	groups  []string
+}
+
+func newTextHandler(w io.Writer, service string, level slog.Leveler) *textHandler {
+	return &textHandler{w: w, mu: &sync.Mutex{}, level: level, service: service}
+}
+
+// Enabled implements slog.Handler
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := copyFields(h.fields)
+	target := groupFields(fields, h.groups)
+	r.Attrs(func(a slog.Attr) bool {
+		addField(target, a)
+		return true
+	})
+
+	fieldsStr := ""
+	if len(fields) > 0 {
+		fieldsJSON, _ := json.Marshal(fields)
+		fieldsStr = " " + string(fieldsJSON)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintf(h.w, "[%s] %s [%s] %s%s\n",
+		r.Time.UTC().Format(time.RFC3339),
+		levelName(r.Level),
+		h.service,
+		r.Message,
+		fieldsStr,
+	)
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = copyFields(h.fields)
+	target := groupFields(clone.fields, h.groups)
+	for _, a := range attrs {
+		addField(target, a)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+// copyFields deep-copies nested field maps so handlers never share them
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyFields(nested)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// groupFields returns the map under the group path, creating it as needed
+func groupFields(fields map[string]interface{}, groups []string) map[string]interface{} {
+	for _, name := range groups {
+		nested, ok := fields[name].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			fields[name] = nested
+		}
+		fields = nested
+	}
+	return fields
+}
+
+func addField(fields map[string]interface{}, a slog.Attr) {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		target := fields
+		if a.Key != "" {
+			target = groupFields(fields, []string{a.Key})
+		}
+		for _, ga := range value.Group() {
+			addField(target, ga)
+		}
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			fields[a.Key] = err.Error()
+			return
+		}
+		fields[a.Key] = value.Any()
+	default:
+		fields[a.Key] = value.Any()
+	}
+}
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+// minLevel lets every entry through output handlers; levels are checked before them
+const minLevel = slog.Level(-1 << 10)
+
+// ParseLevel returns the level named s, if it is one entries can be filtered at
+func ParseLevel(s string) (LogLevel, bool) {
+	switch level := LogLevel(s); level {
+	case DebugLevel, InfoLevel, WarnLevel, ErrorLevel:
+		return level, true
+	}
+	return "", false
+}
+
+// levelRegistry holds the root level and the levels of modules. A module follows the root
+// level until it is given one of its own.
+type levelRegistry struct {
+	mu      sync.Mutex
+	root    *slog.LevelVar
+	modules map[string]*moduleLevel
+}
+
+type moduleLevel struct {
+	level *slog.LevelVar
+	own   bool
+}
+
+func newLevelRegistry(root *slog.LevelVar) *levelRegistry {
+	return &levelRegistry{root: root, modules: make(map[string]*moduleLevel)}
+}
+
+// module returns the level of a module, registering it
+func (r *levelRegistry) module(name string) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.modules[name]
+	if !ok {
+		m = &moduleLevel{level: new(slog.LevelVar)}
+		m.level.Set(r.root.Level())
+		r.modules[name] = m
+	}
+	return m.level
+}
+
+func (r *levelRegistry) setRoot(level slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.root.Set(level)
+	for _, m := range r.modules {
+		if !m.own {
+			m.level.Set(level)
+		}
+	}
+}
+
+// setModule gives a module a level of its own; it reports false for unknown modules
+func (r *levelRegistry) setModule(name string, level slog.Level) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.modules[name]
+	if !ok {
+		return false
+	}
+	m.level.Set(level)
+	m.own = true
+	return true
+}
+
+// resetModules makes every module follow the root level again
+func (r *levelRegistry) resetModules() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.modules {
+		m.level.Set(r.root.Level())
+		m.own = false
+	}
+}
+
+// Module returns a logger for a part of the gateway whose level can be set apart from the
+// rest (see SetModuleLevel). Its entries carry the module name. Loggers for the same module
+// share their level.
+func (l *Logger) Module(name string) *Logger {
+	level := l.levels.module(name)
+
+	h := *l.slog.Handler().(*handler)
+	h.level = level
+
+	return &Logger{
+		slog:   slog.New(&h).With(slog.String("module", name)),
+		level:  level,
+		levels: l.levels,
+		module: name,
+	}
+}
+
+// SetModuleLevel sets the level of a module created with Module. It reports false for a
+// module that does not exist.
+func (l *Logger) SetModuleLevel(module string, level LogLevel) bool {
+	return l.levels.setModule(module, level.slogLevel())
+}
+
+// ResetModuleLevels makes every module follow the root level again
+func (l *Logger) ResetModuleLevels() {
+	l.levels.resetModules()
+}
+
+// ModuleLevels returns the modules that have a level of their own
+func (l *Logger) ModuleLevels() map[string]LogLevel {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+
+	levels := make(map[string]LogLevel)
+	for name, m := range l.levels.modules {
+		if m.own {
+			levels[name] = LogLevel(levelName(m.level.Level()))
+		}
+	}
+	return levels
+}
+
+// Modules returns the names of the modules created with Module, sorted
+func (l *Logger) Modules() []string {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+
+	names := make([]string, 0, len(l.levels.modules))
+	for name := range l.levels.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RootLevel returns the level of loggers outside any module
+func (l *Logger) RootLevel() LogLevel {
+	return LogLevel(levelName(l.levels.root.Level()))
+}
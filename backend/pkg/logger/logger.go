@@ -1,9 +1,9 @@
 package logger
 
 import (
-	"encoding/json"
-	"fmt"
-	"log"
+	"context"
+	"io"
+	"log/slog"
 	"os"
 	"time"
 )
@@ -19,129 +19,236 @@ const (
 	FatalLevel LogLevel = "fatal"
 )
 
-// Logger provides structured logging
+// LevelFatal is the slog level of Fatal entries
+const LevelFatal = slog.Level(12)
+
+// slogLevel maps a level to slog. Unknown levels log at info.
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return LevelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// levelName returns the name of a slog level as this package spells it
+func levelName(level slog.Level) string {
+	switch {
+	case level >= LevelFatal:
+		return string(FatalLevel)
+	case level >= slog.LevelError:
+		return string(ErrorLevel)
+	case level >= slog.LevelWarn:
+		return string(WarnLevel)
+	case level >= slog.LevelInfo:
+		return string(InfoLevel)
+	default:
+		return string(DebugLevel)
+	}
+}
+
+// Logger provides structured logging on top of log/slog. Entries pass through sampling,
+// redaction of secrets and PII, and get the fields carried by the context (see
+// ContextWithFields) before reaching the output handler.
 type Logger struct {
-	level      LogLevel
-	service    string
-	jsonOutput bool
+	slog   *slog.Logger
+	level  *slog.LevelVar
+	levels *levelRegistry
+	module string
+}
+
+// Option configures New
+type Option func(*options)
+
+type options struct {
+	output   io.Writer
+	handler  slog.Handler
+	sampling *SamplingConfig
+	redact   RedactionConfig
+}
+
+// WithOutput writes entries to w instead of stdout
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.output = w
+	}
 }
 
-// LogEntry represents a single log entry
-type LogEntry struct {
-	Timestamp string                 `json:"timestamp"`
-	Level     string                 `json:"level"`
-	Service   string                 `json:"service"`
-	Message   string                 `json:"message"`
-	Fields    map[string]interface{} `json:"fields,omitempty"`
+// WithHandler sends entries to h instead of the built-in JSON or text output. The logger's
+// level, sampling, redaction and context fields still apply; h gets the service as an attribute.
+func WithHandler(h slog.Handler) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
+// WithSampling thins out repeated debug and info entries, see SamplingConfig
+func WithSampling(cfg SamplingConfig) Option {
+	return func(o *options) {
+		o.sampling = &cfg
+	}
+}
+
+// WithRedaction configures which fields are redacted, see RedactionConfig
+func WithRedaction(cfg RedactionConfig) Option {
+	return func(o *options) {
+		o.redact = cfg
+	}
 }
 
 // New creates a new logger instance
-func New(service string, level LogLevel, jsonOutput bool) *Logger {
-	return &Logger{
-		level:      level,
-		service:    service,
-		jsonOutput: jsonOutput,
+func New(service string, level LogLevel, jsonOutput bool, opts ...Option) *Logger {
+	o := options{
+		output: os.Stdout,
+		redact: RedactionConfig{PII: true},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(level.slogLevel())
+
+	// Levels are checked ahead of the output handler, per module
+	var out slog.Handler
+	switch {
+	case o.handler != nil:
+		out = o.handler.WithAttrs([]slog.Attr{slog.String("service", service)})
+	case jsonOutput:
+		out = slog.NewJSONHandler(o.output, &slog.HandlerOptions{
+			Level:       minLevel,
+			ReplaceAttr: replaceJSONAttr,
+		}).WithAttrs([]slog.Attr{slog.String("service", service)}).WithGroup("fields")
+	default:
+		out = newTextHandler(o.output, service, minLevel)
+	}
+
+	h := &handler{
+		next:   out,
+		level:  levelVar,
+		redact: newRedactor(o.redact),
 	}
+	if o.sampling != nil {
+		h.sampler = newSampler(*o.sampling)
+	}
+
+	return &Logger{slog: slog.New(h), level: levelVar, levels: newLevelRegistry(levelVar)}
+}
+
+// replaceJSONAttr keeps the JSON entry layout of earlier releases:
+// {"timestamp", "level", "service", "message", "fields": {...}}
+func replaceJSONAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		return slog.String("timestamp", a.Value.Time().UTC().Format(time.RFC3339))
+	case slog.LevelKey:
+		return slog.String(slog.LevelKey, levelName(a.Value.Any().(slog.Level)))
+	case slog.MessageKey:
+		a.Key = "message"
+	}
+	return a
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(message string, fields ...map[string]interface{}) {
-	if l.shouldLog(DebugLevel) {
-		l.log(DebugLevel, message, fields...)
-	}
+	l.log(context.Background(), slog.LevelDebug, message, fields)
 }
 
 // Info logs an info message
 func (l *Logger) Info(message string, fields ...map[string]interface{}) {
-	if l.shouldLog(InfoLevel) {
-		l.log(InfoLevel, message, fields...)
-	}
+	l.log(context.Background(), slog.LevelInfo, message, fields)
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(message string, fields ...map[string]interface{}) {
-	if l.shouldLog(WarnLevel) {
-		l.log(WarnLevel, message, fields...)
-	}
+	l.log(context.Background(), slog.LevelWarn, message, fields)
 }
 
 // Error logs an error message
 func (l *Logger) Error(message string, fields ...map[string]interface{}) {
-	if l.shouldLog(ErrorLevel) {
-		l.log(ErrorLevel, message, fields...)
-	}
+	l.log(context.Background(), slog.LevelError, message, fields)
 }
 
 // Fatal logs a fatal message and exits
 func (l *Logger) Fatal(message string, fields ...map[string]interface{}) {
-	l.log(FatalLevel, message, fields...)
+	l.log(context.Background(), LevelFatal, message, fields)
 	os.Exit(1)
 }
 
-// log performs the actual logging
-func (l *Logger) log(level LogLevel, message string, fields ...map[string]interface{}) {
-	entry := LogEntry{
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Level:     string(level),
-		Service:   l.service,
-		Message:   message,
-	}
-
-	if len(fields) > 0 && fields[0] != nil {
-		entry.Fields = fields[0]
-	}
+// DebugContext logs a debug message with the fields carried by ctx
+func (l *Logger) DebugContext(ctx context.Context, message string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelDebug, message, fields)
+}
 
-	if l.jsonOutput {
-		output, err := json.Marshal(entry)
-		if err != nil {
-			log.Printf("Error marshaling log entry: %v", err)
-			return
-		}
-		fmt.Println(string(output))
-	} else {
-		l.printPlain(entry)
-	}
+// InfoContext logs an info message with the fields carried by ctx
+func (l *Logger) InfoContext(ctx context.Context, message string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelInfo, message, fields)
 }
 
-// printPlain prints a log entry in plain text format
-func (l *Logger) printPlain(entry LogEntry) {
-	fieldsStr := ""
-	if entry.Fields != nil {
-		fieldsJSON, _ := json.Marshal(entry.Fields)
-		fieldsStr = " " + string(fieldsJSON)
-	}
+// WarnContext logs a warning message with the fields carried by ctx
+func (l *Logger) WarnContext(ctx context.Context, message string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelWarn, message, fields)
+}
 
-	fmt.Printf("[%s] %s [%s] %s%s\n",
-		entry.Timestamp,
-		entry.Level,
-		entry.Service,
-		entry.Message,
-		fieldsStr,
-	)
+// ErrorContext logs an error message with the fields carried by ctx
+func (l *Logger) ErrorContext(ctx context.Context, message string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelError, message, fields)
 }
 
-// shouldLog checks if the log level should be logged
-func (l *Logger) shouldLog(level LogLevel) bool {
-	levels := map[LogLevel]int{
-		DebugLevel: 0,
-		InfoLevel:  1,
-		WarnLevel:  2,
-		ErrorLevel: 3,
-		FatalLevel: 4,
+// log performs the actual logging
+func (l *Logger) log(ctx context.Context, level slog.Level, message string, fields []map[string]interface{}) {
+	if !l.slog.Enabled(ctx, level) {
+		return
+	}
+	var attrs []slog.Attr
+	if len(fields) > 0 {
+		attrs = fieldAttrs(fields[0])
 	}
+	l.slog.LogAttrs(ctx, level, message, attrs...)
+}
 
-	return levels[level] >= levels[l.level]
+// Enabled reports whether messages at level are logged. Hot paths check it before
+// building the fields map so disabled levels cost nothing.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level.slogLevel() >= l.level.Level()
 }
 
-// WithFields returns a new logger with additional fields
+// WithFields returns a logger that adds fields to every entry
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
-	// This is a simplified version - in production, you might want to maintain fields
-	return l
+	clone := *l
+	clone.slog = slog.New(l.slog.Handler().WithAttrs(fieldAttrs(fields)))
+	return &clone
 }
 
-// SetLevel sets the logging level
+// SetLevel sets the logging level. It is safe to call while logging and applies to
+// loggers derived with WithFields. On the root logger it also applies to modules without
+// a level of their own; on a module logger it sets the module's level.
 func (l *Logger) SetLevel(level LogLevel) {
-	l.level = level
+	if l.module != "" {
+		l.levels.setModule(l.module, level.slogLevel())
+		return
+	}
+	l.levels.setRoot(level.slogLevel())
+}
+
+// Level returns the current logging level
+func (l *Logger) Level() LogLevel {
+	return LogLevel(levelName(l.level.Level()))
+}
+
+// Slog returns the logger as a *slog.Logger, for libraries that log through slog
+func (l *Logger) Slog() *slog.Logger {
+	return l.slog
 }
 
 // Default logger instance
@@ -174,7 +281,9 @@ func Fatal(message string, fields ...map[string]interface{}) {
 	defaultLogger.Fatal(message, fields...)
 }
 
-// SetDefault sets the default logger
+// SetDefault sets the default logger. It also becomes the slog default, so output of the
+// log and log/slog packages goes through it.
 func SetDefault(logger *Logger) {
 	defaultLogger = logger
+	slog.SetDefault(logger.slog)
 }
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_JSONLayout(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithOutput(&buf))
+
+	log.Debug("hidden")
+	log.Info("no fields")
+	log.Warn("with fields", map[string]interface{}{"count": 3, "error": errors.New("boom")})
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "svc", entries[0]["service"])
+	assert.Equal(t, "no fields", entries[0]["message"])
+	assert.NotContains(t, entries[0], "fields")
+	_, err := time.Parse(time.RFC3339, entries[0]["timestamp"].(string))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "warn", entries[1]["level"])
+	assert.Equal(t, map[string]interface{}{"count": float64(3), "error": "boom"}, entries[1]["fields"])
+}
+
+func TestLogger_TextLayout(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", DebugLevel, false, WithOutput(&buf))
+
+	log.Info("hello", map[string]interface{}{"user_id": "u1"})
+
+	line := buf.String()
+	assert.Contains(t, line, "] info [svc] hello {\"user_id\":\"u1\"}\n")
+}
+
+func TestLogger_RedactsSecretsAndPII(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithOutput(&buf), WithRedaction(RedactionConfig{
+		Keys: []string{"ssn"},
+		PII:  true,
+	}))
+
+	log.Info("login", map[string]interface{}{
+		"access_token":  "eyJhbGciOi",
+		"Client_Secret": "s3cr3t",
+		"password":      "hunter2",
+		"SSN":           "123-45-6789",
+		"email":         "jane@example.com",
+		"phone":         "+15551234567",
+		"user_id":       "u1",
+		"request": map[string]interface{}{
+			"authorization": "Bearer abc",
+			"path":          "/login",
+		},
+	})
+
+	fields := decodeEntries(t, &buf)[0]["fields"].(map[string]interface{})
+	assert.Equal(t, Redacted, fields["access_token"])
+	assert.Equal(t, Redacted, fields["Client_Secret"])
+	assert.Equal(t, Redacted, fields["password"])
+	assert.Equal(t, Redacted, fields["SSN"])
+	assert.Equal(t, "j***@example.com", fields["email"])
+	assert.Equal(t, "***4567", fields["phone"])
+	assert.Equal(t, "u1", fields["user_id"])
+	assert.Equal(t, map[string]interface{}{"authorization": Redacted, "path": "/login"}, fields["request"])
+}
+
+func TestLogger_PIIMaskingCanBeDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithOutput(&buf), WithRedaction(RedactionConfig{}))
+
+	log.Info("login", map[string]interface{}{"email": "jane@example.com", "token": "abc"})
+
+	fields := decodeEntries(t, &buf)[0]["fields"].(map[string]interface{})
+	assert.Equal(t, "jane@example.com", fields["email"])
+	assert.Equal(t, Redacted, fields["token"])
+}
+
+func TestLogger_ContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithOutput(&buf))
+
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"request_id": "r1", "user_id": "u1"})
+	ctx = ContextWithFields(ctx, map[string]interface{}{"user_id": "u2"})
+
+	log.InfoContext(ctx, "handled", map[string]interface{}{"status": 200})
+	log.Info("no context")
+	log.Slog().InfoContext(ctx, "through slog", "refresh_token", "abc")
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 3)
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "user_id": "u2", "status": float64(200)}, entries[0]["fields"])
+	assert.NotContains(t, entries[1], "fields")
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "user_id": "u2", "refresh_token": Redacted}, entries[2]["fields"])
+
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "user_id": "u2"}, FieldsFromContext(ctx))
+}
+
+func TestLogger_WithFieldsAndSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", WarnLevel, true, WithOutput(&buf))
+	child := log.WithFields(map[string]interface{}{"component": "sync", "secret": "x"})
+
+	child.Info("hidden")
+	assert.False(t, child.Enabled(InfoLevel))
+
+	log.SetLevel(DebugLevel)
+	assert.True(t, child.Enabled(DebugLevel))
+	assert.Equal(t, DebugLevel, child.Level())
+	child.Debug("shown", map[string]interface{}{"n": 1})
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"component": "sync", "secret": Redacted, "n": float64(1)}, entries[0]["fields"])
+}
+
+func TestLogger_WithHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithHandler(slog.NewTextHandler(&buf, nil)))
+
+	log.Info("custom", map[string]interface{}{"api_key": "agw_123"})
+
+	line := buf.String()
+	assert.Contains(t, line, "msg=custom")
+	assert.Contains(t, line, "service=svc")
+	assert.Contains(t, line, "api_key="+Redacted)
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", DebugLevel, true, WithOutput(&buf), WithSampling(SamplingConfig{
+		Initial:    2,
+		Thereafter: 3,
+		Tick:       time.Hour,
+	}))
+
+	for i := 0; i < 10; i++ {
+		log.Info("hot")
+		log.Error("failure")
+	}
+	log.Info("other")
+
+	counts := map[string]int{}
+	for _, entry := range decodeEntries(t, &buf) {
+		counts[entry["message"].(string)]++
+	}
+	// 1, 2 pass initially, then 5 and 8 as every third
+	assert.Equal(t, 4, counts["hot"])
+	assert.Equal(t, 10, counts["failure"], "errors are never sampled")
+	assert.Equal(t, 1, counts["other"])
+}
+
+func TestSampler_NewTick(t *testing.T) {
+	s := newSampler(SamplingConfig{Initial: 1, Tick: time.Second})
+	now := time.Now()
+
+	assert.True(t, s.allow(slog.LevelInfo, "msg", now))
+	assert.False(t, s.allow(slog.LevelInfo, "msg", now))
+	assert.True(t, s.allow(slog.LevelInfo, "msg", now.Add(time.Second)))
+}
+
+func TestLogger_ModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := New("svc", InfoLevel, true, WithOutput(&buf))
+	grpc := log.Module("grpc")
+	http := log.Module("http")
+
+	assert.True(t, log.SetModuleLevel("grpc", DebugLevel))
+	assert.False(t, log.SetModuleLevel("unknown", DebugLevel))
+
+	grpc.Debug("grpc debug")
+	http.Debug("http debug")
+	log.Debug("root debug")
+
+	entries := decodeEntries(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "grpc debug", entries[0]["message"])
+	assert.Equal(t, "grpc", entries[0]["fields"].(map[string]interface{})["module"])
+	assert.Equal(t, map[string]LogLevel{"grpc": DebugLevel}, log.ModuleLevels())
+	assert.Equal(t, []string{"grpc", "http"}, log.Modules())
+
+	// Modules without a level of their own follow the root level
+	log.SetLevel(ErrorLevel)
+	assert.Equal(t, ErrorLevel, http.Level())
+	assert.Equal(t, DebugLevel, grpc.Level())
+
+	log.ResetModuleLevels()
+	assert.Equal(t, ErrorLevel, grpc.Level())
+	assert.Empty(t, log.ModuleLevels())
+	assert.Equal(t, ErrorLevel, log.RootLevel())
+}
+
+func TestParseLevel(t *testing.T) {
+	level, ok := ParseLevel("warn")
+	assert.True(t, ok)
+	assert.Equal(t, WarnLevel, level)
+
+	_, ok = ParseLevel("fatal")
+	assert.False(t, ok)
+	_, ok = ParseLevel("verbose")
+	assert.False(t, ok)
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of secret fields
+const Redacted = "[REDACTED]"
+
+// RedactionConfig configures which fields are redacted. Tokens, passwords, secrets and other
+// credentials are always redacted, by field name at any nesting depth.
+type RedactionConfig struct {
+	// Keys are additional field names to redact, matched case-insensitively
+	Keys []string
+	// PII masks email addresses and phone numbers, keeping enough to tell entries apart
+	PII bool
+}
+
+// secretKeys are redacted wherever they appear; so is any key ending in secretSuffixes
+var secretKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"api_key":       true,
+	"private_key":   true,
+	"otp":           true,
+	"otp_code":      true,
+	"backup_codes":  true,
+	"code_verifier": true,
+	"token":         true,
+}
+
+var secretSuffixes = []string{"password", "secret", "_token"}
+
+type redactor struct {
+	keys map[string]bool
+	pii  bool
+}
+
+func newRedactor(cfg RedactionConfig) *redactor {
+	r := &redactor{keys: make(map[string]bool, len(cfg.Keys)), pii: cfg.PII}
+	for _, key := range cfg.Keys {
+		r.keys[strings.ToLower(key)] = true
+	}
+	return r
+}
+
+// attr returns a with secret values replaced and PII masked, recursing into groups
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = r.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	key := strings.ToLower(a.Key)
+	if r.secret(key) {
+		return slog.String(a.Key, Redacted)
+	}
+	if r.pii && value.Kind() == slog.KindString {
+		switch {
+		case key == "email" || strings.HasSuffix(key, "_email"):
+			return slog.String(a.Key, maskEmail(value.String()))
+		case key == "phone" || strings.HasSuffix(key, "_phone") || key == "phone_number":
+			return slog.String(a.Key, maskPhone(value.String()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+func (r *redactor) secret(key string) bool {
+	if secretKeys[key] || r.keys[key] {
+		return true
+	}
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskEmail keeps the first character of the local part and the domain: j***@example.com
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 {
+		return Redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPhone keeps the last four digits: ***4567
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return Redacted
+	}
+	return "***" + phone[len(phone)-4:]
+}
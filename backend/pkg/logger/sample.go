@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig thins out repeated debug and info entries. Within each Tick, the first
+// Initial entries with the same level and message are logged, then every Thereafter-th.
+// Warnings and errors are never sampled.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// samplerBuckets bounds the sampler's memory; messages sharing a bucket share a budget
+const samplerBuckets = 4096
+
+type sampler struct {
+	initial    uint64
+	thereafter uint64
+	tick       int64
+	counters   [samplerBuckets]sampleCounter
+}
+
+type sampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+func newSampler(cfg SamplingConfig) *sampler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+	if cfg.Initial < 0 {
+		cfg.Initial = 0
+	}
+	return &sampler{
+		initial:    uint64(cfg.Initial),
+		thereafter: uint64(max(cfg.Thereafter, 0)),
+		tick:       int64(cfg.Tick),
+	}
+}
+
+// allow reports whether an entry is logged. Thereafter of 0 drops everything past Initial.
+func (s *sampler) allow(level slog.Level, message string, now time.Time) bool {
+	if level >= slog.LevelWarn {
+		return true
+	}
+
+	counter := &s.counters[sampleKey(level, message)%samplerBuckets]
+
+	n := counter.inc(now.UnixNano(), s.tick)
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// sampleKey hashes level and message with FNV-1a, without allocating
+func sampleKey(level slog.Level, message string) uint32 {
+	const prime = 16777619
+	h := uint32(2166136261)
+	h = (h ^ uint32(byte(level))) * prime
+	for i := 0; i < len(message); i++ {
+		h = (h ^ uint32(message[i])) * prime
+	}
+	return h
+}
+
+// inc counts an entry in the current tick, starting a new tick once the last one is over
+func (c *sampleCounter) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if now < resetAt {
+		return c.count.Add(1)
+	}
+	// One caller starts the new tick; the others count into it
+	if c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		c.count.Store(1)
+		return 1
+	}
+	return c.count.Add(1)
+}
@@ -0,0 +1,136 @@
+package signedurl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+func newTestSigner() *Signer {
+	s := New([]byte("0123456789abcdef0123456789abcdef"))
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
+
+func TestSigner_Sign_ShouldMatchReferenceVector(t *testing.T) {
+	// The Go SDK asserts the same vector; both must change together
+	signed, err := newTestSigner().Sign("https://files.example.com/exports/report.csv?format=csv", Claims{
+		UserID:    "123e4567-e89b-12d3-a456-426614174000",
+		Scopes:    []string{"exports:read"},
+		ExpiresAt: testNow.Add(5 * time.Minute),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://files.example.com/exports/report.csv?ag_expires=1741082700&ag_scope=exports%3Aread&ag_signature=1a_X1cBZz8dxq_VNnB-RxOMzWP5mtF0Kd24nFndu__Q&ag_user=123e4567-e89b-12d3-a456-426614174000&format=csv", signed)
+}
+
+func TestSigner_Verify(t *testing.T) {
+	s := newTestSigner()
+	signed, err := s.Sign("https://files.example.com/exports/report.csv?format=csv", Claims{
+		UserID:    "user-1",
+		Scopes:    []string{"exports:read", "exports:list"},
+		ExpiresAt: testNow.Add(5 * time.Minute),
+	})
+	require.NoError(t, err)
+
+	t.Run("ShouldReturnClaims", func(t *testing.T) {
+		claims, err := s.Verify(http.MethodGet, mustParse(t, signed))
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.UserID)
+		assert.Equal(t, []string{"exports:read", "exports:list"}, claims.Scopes)
+		assert.Equal(t, testNow.Add(5*time.Minute), claims.ExpiresAt.UTC())
+	})
+
+	t.Run("ShouldIgnoreSchemeAndHost", func(t *testing.T) {
+		u := mustParse(t, signed)
+		u.Scheme, u.Host = "http", "internal:8080"
+
+		_, err := s.Verify(http.MethodGet, u)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ShouldAdmitHeadForGet", func(t *testing.T) {
+		_, err := s.Verify(http.MethodHead, mustParse(t, signed))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ShouldRejectOtherMethods", func(t *testing.T) {
+		_, err := s.Verify(http.MethodDelete, mustParse(t, signed))
+
+		assert.True(t, errors.Is(err, ErrMethodNotAllowed))
+	})
+
+	t.Run("ShouldRejectTamperedURL", func(t *testing.T) {
+		for _, tamper := range []func(u *url.URL){
+			func(u *url.URL) { u.Path = "/exports/other.csv" },
+			func(u *url.URL) { q := u.Query(); q.Set("format", "json"); u.RawQuery = q.Encode() },
+			func(u *url.URL) { q := u.Query(); q.Set(ParamUser, "user-2"); u.RawQuery = q.Encode() },
+			func(u *url.URL) { q := u.Query(); q.Set(ParamScope, "exports:write"); u.RawQuery = q.Encode() },
+			func(u *url.URL) { q := u.Query(); q.Set(ParamExpires, "9999999999"); u.RawQuery = q.Encode() },
+			func(u *url.URL) { q := u.Query(); q.Add("extra", "1"); u.RawQuery = q.Encode() },
+		} {
+			u := mustParse(t, signed)
+			tamper(u)
+
+			_, err := s.Verify(http.MethodGet, u)
+
+			assert.True(t, errors.Is(err, ErrInvalidSignature), "expected invalid signature for %s", u)
+		}
+	})
+
+	t.Run("ShouldRejectOtherSecret", func(t *testing.T) {
+		_, err := New([]byte("another-secret-another-secret-00")).Verify(http.MethodGet, mustParse(t, signed))
+
+		assert.True(t, errors.Is(err, ErrInvalidSignature))
+	})
+
+	t.Run("ShouldRejectExpired", func(t *testing.T) {
+		later := newTestSigner()
+		later.now = func() time.Time { return testNow.Add(5 * time.Minute) }
+
+		_, err := later.Verify(http.MethodGet, mustParse(t, signed))
+
+		assert.True(t, errors.Is(err, ErrExpired))
+	})
+
+	t.Run("ShouldRejectUnsigned", func(t *testing.T) {
+		_, err := s.Verify(http.MethodGet, mustParse(t, "https://files.example.com/exports/report.csv"))
+
+		assert.True(t, errors.Is(err, ErrMissingSignature))
+	})
+}
+
+func TestSigner_Sign_ShouldBindMethod(t *testing.T) {
+	s := newTestSigner()
+	signed, err := s.Sign("https://files.example.com/uploads/1", Claims{UserID: "user-1", Method: "put", ExpiresAt: testNow.Add(time.Minute)})
+	require.NoError(t, err)
+
+	_, err = s.Verify(http.MethodPut, mustParse(t, signed))
+	assert.NoError(t, err)
+
+	_, err = s.Verify(http.MethodGet, mustParse(t, signed))
+	assert.True(t, errors.Is(err, ErrMethodNotAllowed))
+}
+
+func TestSigner_Sign_ShouldRejectURLWithSignedParams(t *testing.T) {
+	_, err := newTestSigner().Sign("https://files.example.com/a?ag_user=someone", Claims{UserID: "user-1", ExpiresAt: testNow.Add(time.Minute)})
+
+	assert.Error(t, err)
+}
@@ -146,6 +146,7 @@ type ValidateTokenResponse struct {
 	IsActive      bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
 	ApplicationId string                 `protobuf:"bytes,9,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // Application ID from token claims
 	AppRoles      []string               `protobuf:"bytes,10,rep,name=app_roles,json=appRoles,proto3" json:"app_roles,omitempty"`               // Per-application roles
+	IsGuest       bool                   `protobuf:"varint,11,opt,name=is_guest,json=isGuest,proto3" json:"is_guest,omitempty"`                 // Anonymous guest that has not been upgraded to a full account
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -250,6 +251,13 @@ func (x *ValidateTokenResponse) GetAppRoles() []string {
 	return nil
 }
 
+func (x *ValidateTokenResponse) GetIsGuest() bool {
+	if x != nil {
+		return x.IsGuest
+	}
+	return false
+}
+
 // GetUserRequest contains the user ID to retrieve
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -316,6 +324,8 @@ type User struct {
 	IsActive          bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
 	CreatedAt         int64                  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt         int64                  `protobuf:"varint,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Phone             string                 `protobuf:"bytes,11,opt,name=phone,proto3" json:"phone,omitempty"`
+	State             string                 `protobuf:"bytes,12,opt,name=state,proto3" json:"state,omitempty"` // invited, active, suspended, deactivated or deleted
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -420,6 +430,20 @@ func (x *User) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *User) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *User) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
 // GetUserResponse contains user information
 type GetUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -480,8 +504,12 @@ type CheckPermissionRequest struct {
 	Resource      string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`                                // e.g., "users", "products", "orders"
 	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                                    // e.g., "read", "write", "delete"
 	ApplicationId string                 `protobuf:"bytes,4,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // Optional: check per-app permission
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Attributes conditional permissions are evaluated against, read as resource.<key>
+	// and context.<key>; user.* and time.* are filled in by the server
+	ResourceAttributes map[string]string `protobuf:"bytes,5,rep,name=resource_attributes,json=resourceAttributes,proto3" json:"resource_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ContextAttributes  map[string]string `protobuf:"bytes,6,rep,name=context_attributes,json=contextAttributes,proto3" json:"context_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CheckPermissionRequest) Reset() {
@@ -542,6 +570,20 @@ func (x *CheckPermissionRequest) GetApplicationId() string {
 	return ""
 }
 
+func (x *CheckPermissionRequest) GetResourceAttributes() map[string]string {
+	if x != nil {
+		return x.ResourceAttributes
+	}
+	return nil
+}
+
+func (x *CheckPermissionRequest) GetContextAttributes() map[string]string {
+	if x != nil {
+		return x.ContextAttributes
+	}
+	return nil
+}
+
 // CheckPermissionResponse contains permission check result
 type CheckPermissionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -3799,30 +3841,31 @@ func (x *SendEmailResponse) GetErrorMessage() string {
 	return ""
 }
 
-type SyncUsersRequest struct {
+// ListUsersRequest contains pagination and filters for listing users
+type ListUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UpdatedAfter  string                 `protobuf:"bytes,1,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`    // RFC3339 timestamp
-	ApplicationId string                 `protobuf:"bytes,2,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // Optional: filter by application
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                     // Pagination limit (max 1000)
-	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`                                   // Pagination offset
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`               // 1-100, default 20
+	Search        string                 `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`                                    // Optional: match email, username or full name
+	ApplicationId string                 `protobuf:"bytes,4,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // Optional: only users of this application
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncUsersRequest) Reset() {
-	*x = SyncUsersRequest{}
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
 	mi := &file_proto_auth_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncUsersRequest) String() string {
+func (x *ListUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncUsersRequest) ProtoMessage() {}
+func (*ListUsersRequest) ProtoMessage() {}
 
-func (x *SyncUsersRequest) ProtoReflect() protoreflect.Message {
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3834,64 +3877,65 @@ func (x *SyncUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncUsersRequest.ProtoReflect.Descriptor instead.
-func (*SyncUsersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *SyncUsersRequest) GetUpdatedAfter() string {
+func (x *ListUsersRequest) GetPage() int32 {
 	if x != nil {
-		return x.UpdatedAfter
+		return x.Page
 	}
-	return ""
+	return 0
 }
 
-func (x *SyncUsersRequest) GetApplicationId() string {
+func (x *ListUsersRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.ApplicationId
+		return x.PageSize
 	}
-	return ""
+	return 0
 }
 
-func (x *SyncUsersRequest) GetLimit() int32 {
+func (x *ListUsersRequest) GetSearch() string {
 	if x != nil {
-		return x.Limit
+		return x.Search
 	}
-	return 0
+	return ""
 }
 
-func (x *SyncUsersRequest) GetOffset() int32 {
+func (x *ListUsersRequest) GetApplicationId() string {
 	if x != nil {
-		return x.Offset
+		return x.ApplicationId
 	}
-	return 0
+	return ""
 }
 
-type SyncUsersResponse struct {
+// ListUsersResponse contains a page of users
+type ListUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*SyncUser            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
-	SyncTimestamp string                 `protobuf:"bytes,4,opt,name=sync_timestamp,json=syncTimestamp,proto3" json:"sync_timestamp,omitempty"` // Server timestamp for next sync
-	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncUsersResponse) Reset() {
-	*x = SyncUsersResponse{}
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
 	mi := &file_proto_auth_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncUsersResponse) String() string {
+func (x *ListUsersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncUsersResponse) ProtoMessage() {}
+func (*ListUsersResponse) ProtoMessage() {}
 
-func (x *SyncUsersResponse) ProtoReflect() protoreflect.Message {
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3903,74 +3947,68 @@ func (x *SyncUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncUsersResponse.ProtoReflect.Descriptor instead.
-func (*SyncUsersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *SyncUsersResponse) GetUsers() []*SyncUser {
+func (x *ListUsersResponse) GetUsers() []*User {
 	if x != nil {
 		return x.Users
 	}
 	return nil
 }
 
-func (x *SyncUsersResponse) GetTotal() int32 {
+func (x *ListUsersResponse) GetTotal() int32 {
 	if x != nil {
 		return x.Total
 	}
 	return 0
 }
 
-func (x *SyncUsersResponse) GetHasMore() bool {
+func (x *ListUsersResponse) GetPage() int32 {
 	if x != nil {
-		return x.HasMore
+		return x.Page
 	}
-	return false
+	return 0
 }
 
-func (x *SyncUsersResponse) GetSyncTimestamp() string {
+func (x *ListUsersResponse) GetPageSize() int32 {
 	if x != nil {
-		return x.SyncTimestamp
+		return x.PageSize
 	}
-	return ""
+	return 0
 }
 
-func (x *SyncUsersResponse) GetErrorMessage() string {
+func (x *ListUsersResponse) GetTotalPages() int32 {
 	if x != nil {
-		return x.ErrorMessage
+		return x.TotalPages
 	}
-	return ""
+	return 0
 }
 
-type SyncUser struct {
+// GetUserByEmailRequest contains the email address of the user to retrieve
+type GetUserByEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
-	FullName      string                 `protobuf:"bytes,4,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
-	IsActive      bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	EmailVerified bool                   `protobuf:"varint,6,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339 timestamp
-	AppProfile    *SyncUserAppProfile    `protobuf:"bytes,8,opt,name=app_profile,json=appProfile,proto3" json:"app_profile,omitempty"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncUser) Reset() {
-	*x = SyncUser{}
+func (x *GetUserByEmailRequest) Reset() {
+	*x = GetUserByEmailRequest{}
 	mi := &file_proto_auth_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncUser) String() string {
+func (x *GetUserByEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncUser) ProtoMessage() {}
+func (*GetUserByEmailRequest) ProtoMessage() {}
 
-func (x *SyncUser) ProtoReflect() protoreflect.Message {
+func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3982,92 +4020,45 @@ func (x *SyncUser) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncUser.ProtoReflect.Descriptor instead.
-func (*SyncUser) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetUserByEmailRequest.ProtoReflect.Descriptor instead.
+func (*GetUserByEmailRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *SyncUser) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *SyncUser) GetEmail() string {
+func (x *GetUserByEmailRequest) GetEmail() string {
 	if x != nil {
 		return x.Email
 	}
 	return ""
 }
 
-func (x *SyncUser) GetUsername() string {
-	if x != nil {
-		return x.Username
-	}
-	return ""
-}
-
-func (x *SyncUser) GetFullName() string {
-	if x != nil {
-		return x.FullName
-	}
-	return ""
-}
-
-func (x *SyncUser) GetIsActive() bool {
-	if x != nil {
-		return x.IsActive
-	}
-	return false
-}
-
-func (x *SyncUser) GetEmailVerified() bool {
-	if x != nil {
-		return x.EmailVerified
-	}
-	return false
-}
-
-func (x *SyncUser) GetUpdatedAt() string {
-	if x != nil {
-		return x.UpdatedAt
-	}
-	return ""
-}
-
-func (x *SyncUser) GetAppProfile() *SyncUserAppProfile {
-	if x != nil {
-		return x.AppProfile
-	}
-	return nil
-}
-
-type SyncUserAppProfile struct {
+// UpdateUserRequest contains the account fields to change
+type UpdateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DisplayName   string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
-	AvatarUrl     string                 `protobuf:"bytes,2,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
-	AppRoles      []string               `protobuf:"bytes,3,rep,name=app_roles,json=appRoles,proto3" json:"app_roles,omitempty"`
-	IsActive      bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	IsBanned      bool                   `protobuf:"varint,5,opt,name=is_banned,json=isBanned,proto3" json:"is_banned,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	FullName      string                 `protobuf:"bytes,4,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Phone         string                 `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"`
+	EmailVerified *bool                  `protobuf:"varint,6,opt,name=email_verified,json=emailVerified,proto3,oneof" json:"email_verified,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncUserAppProfile) Reset() {
-	*x = SyncUserAppProfile{}
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
 	mi := &file_proto_auth_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncUserAppProfile) String() string {
+func (x *UpdateUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncUserAppProfile) ProtoMessage() {}
+func (*UpdateUserRequest) ProtoMessage() {}
 
-func (x *SyncUserAppProfile) ProtoReflect() protoreflect.Message {
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4079,67 +4070,76 @@ func (x *SyncUserAppProfile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncUserAppProfile.ProtoReflect.Descriptor instead.
-func (*SyncUserAppProfile) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *SyncUserAppProfile) GetDisplayName() string {
+func (x *UpdateUserRequest) GetUserId() string {
 	if x != nil {
-		return x.DisplayName
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *SyncUserAppProfile) GetAvatarUrl() string {
+func (x *UpdateUserRequest) GetEmail() string {
 	if x != nil {
-		return x.AvatarUrl
+		return x.Email
 	}
 	return ""
 }
 
-func (x *SyncUserAppProfile) GetAppRoles() []string {
+func (x *UpdateUserRequest) GetUsername() string {
 	if x != nil {
-		return x.AppRoles
+		return x.Username
 	}
-	return nil
+	return ""
 }
 
-func (x *SyncUserAppProfile) GetIsActive() bool {
+func (x *UpdateUserRequest) GetFullName() string {
 	if x != nil {
-		return x.IsActive
+		return x.FullName
 	}
-	return false
+	return ""
 }
 
-func (x *SyncUserAppProfile) GetIsBanned() bool {
+func (x *UpdateUserRequest) GetPhone() string {
 	if x != nil {
-		return x.IsBanned
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmailVerified() bool {
+	if x != nil && x.EmailVerified != nil {
+		return *x.EmailVerified
 	}
 	return false
 }
 
-type GetApplicationAuthConfigRequest struct {
+// DeactivateUserRequest contains the user to deactivate
+type DeactivateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // UUID
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Recorded in the audit log and the webhook
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetApplicationAuthConfigRequest) Reset() {
-	*x = GetApplicationAuthConfigRequest{}
+func (x *DeactivateUserRequest) Reset() {
+	*x = DeactivateUserRequest{}
 	mi := &file_proto_auth_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetApplicationAuthConfigRequest) String() string {
+func (x *DeactivateUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetApplicationAuthConfigRequest) ProtoMessage() {}
+func (*DeactivateUserRequest) ProtoMessage() {}
 
-func (x *GetApplicationAuthConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *DeactivateUserRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4151,44 +4151,49 @@ func (x *GetApplicationAuthConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetApplicationAuthConfigRequest.ProtoReflect.Descriptor instead.
-func (*GetApplicationAuthConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeactivateUserRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateUserRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *GetApplicationAuthConfigRequest) GetApplicationId() string {
+func (x *DeactivateUserRequest) GetUserId() string {
 	if x != nil {
-		return x.ApplicationId
+		return x.UserId
 	}
 	return ""
 }
 
-type GetApplicationAuthConfigResponse struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	ApplicationId      string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
-	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	DisplayName        string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
-	AllowedAuthMethods []string               `protobuf:"bytes,4,rep,name=allowed_auth_methods,json=allowedAuthMethods,proto3" json:"allowed_auth_methods,omitempty"`
-	OauthProviders     []string               `protobuf:"bytes,5,rep,name=oauth_providers,json=oauthProviders,proto3" json:"oauth_providers,omitempty"`
-	ErrorMessage       string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *DeactivateUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
-func (x *GetApplicationAuthConfigResponse) Reset() {
-	*x = GetApplicationAuthConfigResponse{}
+// AssignRoleRequest identifies a user and a role by ID or name
+type AssignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,2,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	RoleName      string                 `protobuf:"bytes,3,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"` // Used when role_id is empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
 	mi := &file_proto_auth_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetApplicationAuthConfigResponse) String() string {
+func (x *AssignRoleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetApplicationAuthConfigResponse) ProtoMessage() {}
+func (*AssignRoleRequest) ProtoMessage() {}
 
-func (x *GetApplicationAuthConfigResponse) ProtoReflect() protoreflect.Message {
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4200,42 +4205,936 @@ func (x *GetApplicationAuthConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetApplicationAuthConfigResponse.ProtoReflect.Descriptor instead.
-func (*GetApplicationAuthConfigResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
+func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *GetApplicationAuthConfigResponse) GetApplicationId() string {
+func (x *AssignRoleRequest) GetUserId() string {
 	if x != nil {
-		return x.ApplicationId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetApplicationAuthConfigResponse) GetName() string {
+func (x *AssignRoleRequest) GetRoleId() string {
 	if x != nil {
-		return x.Name
+		return x.RoleId
 	}
 	return ""
 }
 
-func (x *GetApplicationAuthConfigResponse) GetDisplayName() string {
+func (x *AssignRoleRequest) GetRoleName() string {
 	if x != nil {
-		return x.DisplayName
+		return x.RoleName
 	}
 	return ""
 }
 
-func (x *GetApplicationAuthConfigResponse) GetAllowedAuthMethods() []string {
-	if x != nil {
-		return x.AllowedAuthMethods
-	}
-	return nil
+// RemoveRoleRequest identifies a user and a role by ID or name
+type RemoveRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoleId        string                 `protobuf:"bytes,2,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	RoleName      string                 `protobuf:"bytes,3,opt,name=role_name,json=roleName,proto3" json:"role_name,omitempty"` // Used when role_id is empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetApplicationAuthConfigResponse) GetOauthProviders() []string {
-	if x != nil {
-		return x.OauthProviders
+func (x *RemoveRoleRequest) Reset() {
+	*x = RemoveRoleRequest{}
+	mi := &file_proto_auth_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRoleRequest) ProtoMessage() {}
+
+func (x *RemoveRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRoleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RemoveRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RemoveRoleRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+func (x *RemoveRoleRequest) GetRoleName() string {
+	if x != nil {
+		return x.RoleName
+	}
+	return ""
+}
+
+// UserResponse contains the user after a change
+type UserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserResponse) Reset() {
+	*x = UserResponse{}
+	mi := &file_proto_auth_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserResponse) ProtoMessage() {}
+
+func (x *UserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
+func (*UserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// ListUserSessionsRequest contains the user and pagination for listing sessions
+type ListUserSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 1-100, default 20
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserSessionsRequest) Reset() {
+	*x = ListUserSessionsRequest{}
+	mi := &file_proto_auth_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserSessionsRequest) ProtoMessage() {}
+
+func (x *ListUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListUserSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUserSessionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUserSessionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// Session represents an active session of a user
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,3,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Os            string                 `protobuf:"bytes,4,opt,name=os,proto3" json:"os,omitempty"`
+	Browser       string                 `protobuf:"bytes,5,opt,name=browser,proto3" json:"browser,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,7,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	SessionName   string                 `protobuf:"bytes,8,opt,name=session_name,json=sessionName,proto3" json:"session_name,omitempty"`
+	LastActiveAt  int64                  `protobuf:"varint,9,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,11,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_proto_auth_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Session) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Session) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Session) GetBrowser() string {
+	if x != nil {
+		return x.Browser
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *Session) GetSessionName() string {
+	if x != nil {
+		return x.SessionName
+	}
+	return ""
+}
+
+func (x *Session) GetLastActiveAt() int64 {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return 0
+}
+
+func (x *Session) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Session) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// ListUserSessionsResponse contains a page of sessions
+type ListUserSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserSessionsResponse) Reset() {
+	*x = ListUserSessionsResponse{}
+	mi := &file_proto_auth_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserSessionsResponse) ProtoMessage() {}
+
+func (x *ListUserSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListUserSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *ListUserSessionsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListUserSessionsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUserSessionsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUserSessionsResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+// RevokeSessionRequest identifies a session and the user it must belong to
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_proto_auth_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RevokeSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RevokeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// RevokeAllUserSessionsRequest contains the user whose sessions to revoke
+type RevokeAllUserSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllUserSessionsRequest) Reset() {
+	*x = RevokeAllUserSessionsRequest{}
+	mi := &file_proto_auth_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllUserSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllUserSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeAllUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RevokeAllUserSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type SyncUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedAfter  string                 `protobuf:"bytes,1,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`    // RFC3339 timestamp
+	ApplicationId string                 `protobuf:"bytes,2,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // Optional: filter by application
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                     // Pagination limit (max 1000)
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`                                   // Pagination offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncUsersRequest) Reset() {
+	*x = SyncUsersRequest{}
+	mi := &file_proto_auth_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncUsersRequest) ProtoMessage() {}
+
+func (x *SyncUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncUsersRequest.ProtoReflect.Descriptor instead.
+func (*SyncUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SyncUsersRequest) GetUpdatedAfter() string {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return ""
+}
+
+func (x *SyncUsersRequest) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+func (x *SyncUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SyncUsersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SyncUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*SyncUser            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	SyncTimestamp string                 `protobuf:"bytes,4,opt,name=sync_timestamp,json=syncTimestamp,proto3" json:"sync_timestamp,omitempty"` // Server timestamp for next sync
+	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncUsersResponse) Reset() {
+	*x = SyncUsersResponse{}
+	mi := &file_proto_auth_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncUsersResponse) ProtoMessage() {}
+
+func (x *SyncUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncUsersResponse.ProtoReflect.Descriptor instead.
+func (*SyncUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SyncUsersResponse) GetUsers() []*SyncUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SyncUsersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SyncUsersResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *SyncUsersResponse) GetSyncTimestamp() string {
+	if x != nil {
+		return x.SyncTimestamp
+	}
+	return ""
+}
+
+func (x *SyncUsersResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type SyncUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	FullName      string                 `protobuf:"bytes,4,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	IsActive      bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,6,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339 timestamp
+	AppProfile    *SyncUserAppProfile    `protobuf:"bytes,8,opt,name=app_profile,json=appProfile,proto3" json:"app_profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncUser) Reset() {
+	*x = SyncUser{}
+	mi := &file_proto_auth_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncUser) ProtoMessage() {}
+
+func (x *SyncUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncUser.ProtoReflect.Descriptor instead.
+func (*SyncUser) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SyncUser) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SyncUser) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SyncUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SyncUser) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *SyncUser) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *SyncUser) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *SyncUser) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *SyncUser) GetAppProfile() *SyncUserAppProfile {
+	if x != nil {
+		return x.AppProfile
+	}
+	return nil
+}
+
+type SyncUserAppProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName   string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,2,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	AppRoles      []string               `protobuf:"bytes,3,rep,name=app_roles,json=appRoles,proto3" json:"app_roles,omitempty"`
+	IsActive      bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsBanned      bool                   `protobuf:"varint,5,opt,name=is_banned,json=isBanned,proto3" json:"is_banned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncUserAppProfile) Reset() {
+	*x = SyncUserAppProfile{}
+	mi := &file_proto_auth_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncUserAppProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncUserAppProfile) ProtoMessage() {}
+
+func (x *SyncUserAppProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncUserAppProfile.ProtoReflect.Descriptor instead.
+func (*SyncUserAppProfile) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SyncUserAppProfile) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *SyncUserAppProfile) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *SyncUserAppProfile) GetAppRoles() []string {
+	if x != nil {
+		return x.AppRoles
+	}
+	return nil
+}
+
+func (x *SyncUserAppProfile) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *SyncUserAppProfile) GetIsBanned() bool {
+	if x != nil {
+		return x.IsBanned
+	}
+	return false
+}
+
+type GetApplicationAuthConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"` // UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetApplicationAuthConfigRequest) Reset() {
+	*x = GetApplicationAuthConfigRequest{}
+	mi := &file_proto_auth_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetApplicationAuthConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetApplicationAuthConfigRequest) ProtoMessage() {}
+
+func (x *GetApplicationAuthConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetApplicationAuthConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetApplicationAuthConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetApplicationAuthConfigRequest) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+type GetApplicationAuthConfigResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId      string                 `protobuf:"bytes,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName        string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AllowedAuthMethods []string               `protobuf:"bytes,4,rep,name=allowed_auth_methods,json=allowedAuthMethods,proto3" json:"allowed_auth_methods,omitempty"`
+	OauthProviders     []string               `protobuf:"bytes,5,rep,name=oauth_providers,json=oauthProviders,proto3" json:"oauth_providers,omitempty"`
+	ErrorMessage       string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetApplicationAuthConfigResponse) Reset() {
+	*x = GetApplicationAuthConfigResponse{}
+	mi := &file_proto_auth_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetApplicationAuthConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetApplicationAuthConfigResponse) ProtoMessage() {}
+
+func (x *GetApplicationAuthConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetApplicationAuthConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetApplicationAuthConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetApplicationAuthConfigResponse) GetApplicationId() string {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return ""
+}
+
+func (x *GetApplicationAuthConfigResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetApplicationAuthConfigResponse) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *GetApplicationAuthConfigResponse) GetAllowedAuthMethods() []string {
+	if x != nil {
+		return x.AllowedAuthMethods
+	}
+	return nil
+}
+
+func (x *GetApplicationAuthConfigResponse) GetOauthProviders() []string {
+	if x != nil {
+		return x.OauthProviders
 	}
 	return nil
 }
@@ -4257,7 +5156,7 @@ type CreateTokenExchangeGrpcRequest struct {
 
 func (x *CreateTokenExchangeGrpcRequest) Reset() {
 	*x = CreateTokenExchangeGrpcRequest{}
-	mi := &file_proto_auth_proto_msgTypes[57]
+	mi := &file_proto_auth_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4269,7 +5168,7 @@ func (x *CreateTokenExchangeGrpcRequest) String() string {
 func (*CreateTokenExchangeGrpcRequest) ProtoMessage() {}
 
 func (x *CreateTokenExchangeGrpcRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[57]
+	mi := &file_proto_auth_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4282,7 +5181,7 @@ func (x *CreateTokenExchangeGrpcRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTokenExchangeGrpcRequest.ProtoReflect.Descriptor instead.
 func (*CreateTokenExchangeGrpcRequest) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{57}
+	return file_proto_auth_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *CreateTokenExchangeGrpcRequest) GetAccessToken() string {
@@ -4311,7 +5210,7 @@ type CreateTokenExchangeGrpcResponse struct {
 
 func (x *CreateTokenExchangeGrpcResponse) Reset() {
 	*x = CreateTokenExchangeGrpcResponse{}
-	mi := &file_proto_auth_proto_msgTypes[58]
+	mi := &file_proto_auth_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4323,7 +5222,7 @@ func (x *CreateTokenExchangeGrpcResponse) String() string {
 func (*CreateTokenExchangeGrpcResponse) ProtoMessage() {}
 
 func (x *CreateTokenExchangeGrpcResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[58]
+	mi := &file_proto_auth_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4336,7 +5235,7 @@ func (x *CreateTokenExchangeGrpcResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTokenExchangeGrpcResponse.ProtoReflect.Descriptor instead.
 func (*CreateTokenExchangeGrpcResponse) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{58}
+	return file_proto_auth_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *CreateTokenExchangeGrpcResponse) GetExchangeCode() string {
@@ -4376,7 +5275,7 @@ type RedeemTokenExchangeGrpcRequest struct {
 
 func (x *RedeemTokenExchangeGrpcRequest) Reset() {
 	*x = RedeemTokenExchangeGrpcRequest{}
-	mi := &file_proto_auth_proto_msgTypes[59]
+	mi := &file_proto_auth_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4388,7 +5287,7 @@ func (x *RedeemTokenExchangeGrpcRequest) String() string {
 func (*RedeemTokenExchangeGrpcRequest) ProtoMessage() {}
 
 func (x *RedeemTokenExchangeGrpcRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[59]
+	mi := &file_proto_auth_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4401,7 +5300,7 @@ func (x *RedeemTokenExchangeGrpcRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RedeemTokenExchangeGrpcRequest.ProtoReflect.Descriptor instead.
 func (*RedeemTokenExchangeGrpcRequest) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{59}
+	return file_proto_auth_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *RedeemTokenExchangeGrpcRequest) GetExchangeCode() string {
@@ -4425,7 +5324,7 @@ type RedeemTokenExchangeGrpcResponse struct {
 
 func (x *RedeemTokenExchangeGrpcResponse) Reset() {
 	*x = RedeemTokenExchangeGrpcResponse{}
-	mi := &file_proto_auth_proto_msgTypes[60]
+	mi := &file_proto_auth_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4437,7 +5336,7 @@ func (x *RedeemTokenExchangeGrpcResponse) String() string {
 func (*RedeemTokenExchangeGrpcResponse) ProtoMessage() {}
 
 func (x *RedeemTokenExchangeGrpcResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_proto_msgTypes[60]
+	mi := &file_proto_auth_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4450,7 +5349,7 @@ func (x *RedeemTokenExchangeGrpcResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RedeemTokenExchangeGrpcResponse.ProtoReflect.Descriptor instead.
 func (*RedeemTokenExchangeGrpcResponse) Descriptor() ([]byte, []int) {
-	return file_proto_auth_proto_rawDescGZIP(), []int{60}
+	return file_proto_auth_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *RedeemTokenExchangeGrpcResponse) GetAccessToken() string {
@@ -4495,6 +5394,310 @@ func (x *RedeemTokenExchangeGrpcResponse) GetErrorMessage() string {
 	return ""
 }
 
+type PermissionCatalogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resource      string                 `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionCatalogEntry) Reset() {
+	*x = PermissionCatalogEntry{}
+	mi := &file_proto_auth_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionCatalogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionCatalogEntry) ProtoMessage() {}
+
+func (x *PermissionCatalogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionCatalogEntry.ProtoReflect.Descriptor instead.
+func (*PermissionCatalogEntry) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *PermissionCatalogEntry) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *PermissionCatalogEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PermissionCatalogEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type RegisterPermissionCatalogRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Service       string                    `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"` // Name of the registering service, e.g. "billing-service"
+	Entries       []*PermissionCatalogEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterPermissionCatalogRequest) Reset() {
+	*x = RegisterPermissionCatalogRequest{}
+	mi := &file_proto_auth_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPermissionCatalogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPermissionCatalogRequest) ProtoMessage() {}
+
+func (x *RegisterPermissionCatalogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPermissionCatalogRequest.ProtoReflect.Descriptor instead.
+func (*RegisterPermissionCatalogRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *RegisterPermissionCatalogRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *RegisterPermissionCatalogRequest) GetEntries() []*PermissionCatalogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type RegisterPermissionCatalogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Registered    int32                  `protobuf:"varint,2,opt,name=registered,proto3" json:"registered,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterPermissionCatalogResponse) Reset() {
+	*x = RegisterPermissionCatalogResponse{}
+	mi := &file_proto_auth_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPermissionCatalogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPermissionCatalogResponse) ProtoMessage() {}
+
+func (x *RegisterPermissionCatalogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPermissionCatalogResponse.ProtoReflect.Descriptor instead.
+func (*RegisterPermissionCatalogResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *RegisterPermissionCatalogResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterPermissionCatalogResponse) GetRegistered() int32 {
+	if x != nil {
+		return x.Registered
+	}
+	return 0
+}
+
+func (x *RegisterPermissionCatalogResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type WatchRevocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Optional: only events for this user (plus "all" events)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRevocationsRequest) Reset() {
+	*x = WatchRevocationsRequest{}
+	mi := &file_proto_auth_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRevocationsRequest) ProtoMessage() {}
+
+func (x *WatchRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *WatchRevocationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RevocationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "token": one access or refresh token, identified by token_hash
+	// "session": every token of session_id
+	// "user": every token issued to user_id before revoked_at
+	// "all": every token issued before revoked_at
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	TokenHash     string `protobuf:"bytes,2,opt,name=token_hash,json=tokenHash,proto3" json:"token_hash,omitempty"` // SHA-256 hex of the revoked token
+	SessionId     string `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RevokedAt     int64  `protobuf:"varint,5,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"` // Unix seconds
+	ExpiresAt     int64  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix seconds after which a "token" revocation no longer matters (0 = unknown)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevocationEvent) Reset() {
+	*x = RevocationEvent{}
+	mi := &file_proto_auth_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevocationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationEvent) ProtoMessage() {}
+
+func (x *RevocationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationEvent.ProtoReflect.Descriptor instead.
+func (*RevocationEvent) Descriptor() ([]byte, []int) {
+	return file_proto_auth_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *RevocationEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetTokenHash() string {
+	if x != nil {
+		return x.TokenHash
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetRevokedAt() int64 {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return 0
+}
+
+func (x *RevocationEvent) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
 var File_proto_auth_proto protoreflect.FileDescriptor
 
 const file_proto_auth_proto_rawDesc = "" +
@@ -4502,7 +5705,7 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\x10proto/auth.proto\x12\x04auth\"`\n" +
 	"\x14ValidateTokenRequest\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12%\n" +
-	"\x0eapplication_id\x18\x02 \x01(\tR\rapplicationId\"\xb3\x02\n" +
+	"\x0eapplication_id\x18\x02 \x01(\tR\rapplicationId\"\xce\x02\n" +
 	"\x15ValidateTokenResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -4515,10 +5718,11 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\tis_active\x18\b \x01(\bR\bisActive\x12%\n" +
 	"\x0eapplication_id\x18\t \x01(\tR\rapplicationId\x12\x1b\n" +
 	"\tapp_roles\x18\n" +
-	" \x03(\tR\bappRoles\"P\n" +
+	" \x03(\tR\bappRoles\x12\x19\n" +
+	"\bis_guest\x18\v \x01(\bR\aisGuest\"P\n" +
 	"\x0eGetUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
-	"\x0eapplication_id\x18\x02 \x01(\tR\rapplicationId\"\xad\x02\n" +
+	"\x0eapplication_id\x18\x02 \x01(\tR\rapplicationId\"\xd9\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
@@ -4532,16 +5736,26 @@ const file_proto_auth_proto_rawDesc = "" +
 	"created_at\x18\t \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
 	"updated_at\x18\n" +
-	" \x01(\x03R\tupdatedAt\"V\n" +
+	" \x01(\x03R\tupdatedAt\x12\x14\n" +
+	"\x05phone\x18\v \x01(\tR\x05phone\x12\x14\n" +
+	"\x05state\x18\f \x01(\tR\x05state\"V\n" +
 	"\x0fGetUserResponse\x12\x1e\n" +
 	"\x04user\x18\x01 \x01(\v2\n" +
 	".auth.UserR\x04user\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\x8c\x01\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\xe4\x03\n" +
 	"\x16CheckPermissionRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
 	"\bresource\x18\x02 \x01(\tR\bresource\x12\x16\n" +
 	"\x06action\x18\x03 \x01(\tR\x06action\x12%\n" +
-	"\x0eapplication_id\x18\x04 \x01(\tR\rapplicationId\"l\n" +
+	"\x0eapplication_id\x18\x04 \x01(\tR\rapplicationId\x12e\n" +
+	"\x13resource_attributes\x18\x05 \x03(\v24.auth.CheckPermissionRequest.ResourceAttributesEntryR\x12resourceAttributes\x12b\n" +
+	"\x12context_attributes\x18\x06 \x03(\v23.auth.CheckPermissionRequest.ContextAttributesEntryR\x11contextAttributes\x1aE\n" +
+	"\x17ResourceAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aD\n" +
+	"\x16ContextAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"l\n" +
 	"\x17CheckPermissionResponse\x12\x18\n" +
 	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x12\n" +
 	"\x04role\x18\x02 \x01(\tR\x04role\x12#\n" +
@@ -4835,7 +6049,79 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\x11SendEmailResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\x8c\x01\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\x82\x01\n" +
+	"\x10ListUsersRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06search\x18\x03 \x01(\tR\x06search\x12%\n" +
+	"\x0eapplication_id\x18\x04 \x01(\tR\rapplicationId\"\x9d\x01\n" +
+	"\x11ListUsersResponse\x12 \n" +
+	"\x05users\x18\x01 \x03(\v2\n" +
+	".auth.UserR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages\"-\n" +
+	"\x15GetUserByEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"\xd0\x01\n" +
+	"\x11UpdateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1b\n" +
+	"\tfull_name\x18\x04 \x01(\tR\bfullName\x12\x14\n" +
+	"\x05phone\x18\x05 \x01(\tR\x05phone\x12*\n" +
+	"\x0eemail_verified\x18\x06 \x01(\bH\x00R\remailVerified\x88\x01\x01B\x11\n" +
+	"\x0f_email_verified\"H\n" +
+	"\x15DeactivateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"b\n" +
+	"\x11AssignRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\arole_id\x18\x02 \x01(\tR\x06roleId\x12\x1b\n" +
+	"\trole_name\x18\x03 \x01(\tR\broleName\"b\n" +
+	"\x11RemoveRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\arole_id\x18\x02 \x01(\tR\x06roleId\x12\x1b\n" +
+	"\trole_name\x18\x03 \x01(\tR\broleName\".\n" +
+	"\fUserResponse\x12\x1e\n" +
+	"\x04user\x18\x01 \x01(\v2\n" +
+	".auth.UserR\x04user\"c\n" +
+	"\x17ListUserSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"\xc2\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vdevice_type\x18\x03 \x01(\tR\n" +
+	"deviceType\x12\x0e\n" +
+	"\x02os\x18\x04 \x01(\tR\x02os\x12\x18\n" +
+	"\abrowser\x18\x05 \x01(\tR\abrowser\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x06 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\a \x01(\tR\tipAddress\x12!\n" +
+	"\fsession_name\x18\b \x01(\tR\vsessionName\x12$\n" +
+	"\x0elast_active_at\x18\t \x01(\x03R\flastActiveAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\v \x01(\x03R\texpiresAt\"\xad\x01\n" +
+	"\x18ListUserSessionsResponse\x12)\n" +
+	"\bsessions\x18\x01 \x03(\v2\r.auth.SessionR\bsessions\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages\"N\n" +
+	"\x14RevokeSessionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"7\n" +
+	"\x1cRevokeAllUserSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x8c\x01\n" +
 	"\x10SyncUsersRequest\x12#\n" +
 	"\rupdated_after\x18\x01 \x01(\tR\fupdatedAfter\x12%\n" +
 	"\x0eapplication_id\x18\x02 \x01(\tR\rapplicationId\x12\x14\n" +
@@ -4891,14 +6177,40 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05email\x18\x04 \x01(\tR\x05email\x12%\n" +
 	"\x0eapplication_id\x18\x05 \x01(\tR\rapplicationId\x12#\n" +
-	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage*\x9f\x01\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\"n\n" +
+	"\x16PermissionCatalogEntry\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"t\n" +
+	" RegisterPermissionCatalogRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x126\n" +
+	"\aentries\x18\x02 \x03(\v2\x1c.auth.PermissionCatalogEntryR\aentries\"\x82\x01\n" +
+	"!RegisterPermissionCatalogResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x02 \x01(\x05R\n" +
+	"registered\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"2\n" +
+	"\x17WatchRevocationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xba\x01\n" +
+	"\x0fRevocationEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"token_hash\x18\x02 \x01(\tR\ttokenHash\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x05 \x01(\x03R\trevokedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt*\x9f\x01\n" +
 	"\aOTPType\x12\x18\n" +
 	"\x14OTP_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
 	"\x15OTP_TYPE_VERIFICATION\x10\x01\x12\x1b\n" +
 	"\x17OTP_TYPE_PASSWORD_RESET\x10\x02\x12\x13\n" +
 	"\x0fOTP_TYPE_TWO_FA\x10\x03\x12\x12\n" +
 	"\x0eOTP_TYPE_LOGIN\x10\x04\x12\x19\n" +
-	"\x15OTP_TYPE_REGISTRATION\x10\x052\x89\x13\n" +
+	"\x15OTP_TYPE_REGISTRATION\x10\x052\xa6\x19\n" +
 	"\vAuthService\x12H\n" +
 	"\rValidateToken\x12\x1a.auth.ValidateTokenRequest\x1a\x1b.auth.ValidateTokenResponse\x126\n" +
 	"\aGetUser\x12\x14.auth.GetUserRequest\x1a\x15.auth.GetUserResponse\x12N\n" +
@@ -4927,10 +6239,24 @@ const file_proto_auth_proto_rawDesc = "" +
 	"\aBanUser\x12\x14.auth.BanUserRequest\x1a\x15.auth.GenericResponse\x12:\n" +
 	"\tUnbanUser\x12\x16.auth.UnbanUserRequest\x1a\x15.auth.GenericResponse\x12]\n" +
 	"\x14ListApplicationUsers\x12!.auth.ListApplicationUsersRequest\x1a\".auth.ListApplicationUsersResponse\x12<\n" +
+	"\tListUsers\x12\x16.auth.ListUsersRequest\x1a\x17.auth.ListUsersResponse\x12D\n" +
+	"\x0eGetUserByEmail\x12\x1b.auth.GetUserByEmailRequest\x1a\x15.auth.GetUserResponse\x129\n" +
+	"\n" +
+	"UpdateUser\x12\x17.auth.UpdateUserRequest\x1a\x12.auth.UserResponse\x12A\n" +
+	"\x0eDeactivateUser\x12\x1b.auth.DeactivateUserRequest\x1a\x12.auth.UserResponse\x129\n" +
+	"\n" +
+	"AssignRole\x12\x17.auth.AssignRoleRequest\x1a\x12.auth.UserResponse\x129\n" +
+	"\n" +
+	"RemoveRole\x12\x17.auth.RemoveRoleRequest\x1a\x12.auth.UserResponse\x12Q\n" +
+	"\x10ListUserSessions\x12\x1d.auth.ListUserSessionsRequest\x1a\x1e.auth.ListUserSessionsResponse\x12B\n" +
+	"\rRevokeSession\x12\x1a.auth.RevokeSessionRequest\x1a\x15.auth.GenericResponse\x12R\n" +
+	"\x15RevokeAllUserSessions\x12\".auth.RevokeAllUserSessionsRequest\x1a\x15.auth.GenericResponse\x12<\n" +
 	"\tSyncUsers\x12\x16.auth.SyncUsersRequest\x1a\x17.auth.SyncUsersResponse\x12i\n" +
 	"\x18GetApplicationAuthConfig\x12%.auth.GetApplicationAuthConfigRequest\x1a&.auth.GetApplicationAuthConfigResponse\x12b\n" +
 	"\x13CreateTokenExchange\x12$.auth.CreateTokenExchangeGrpcRequest\x1a%.auth.CreateTokenExchangeGrpcResponse\x12b\n" +
-	"\x13RedeemTokenExchange\x12$.auth.RedeemTokenExchangeGrpcRequest\x1a%.auth.RedeemTokenExchangeGrpcResponseB)Z'github.com/smilemakc/auth-gateway/protob\x06proto3"
+	"\x13RedeemTokenExchange\x12$.auth.RedeemTokenExchangeGrpcRequest\x1a%.auth.RedeemTokenExchangeGrpcResponse\x12l\n" +
+	"\x19RegisterPermissionCatalog\x12&.auth.RegisterPermissionCatalogRequest\x1a'.auth.RegisterPermissionCatalogResponse\x12J\n" +
+	"\x10WatchRevocations\x12\x1d.auth.WatchRevocationsRequest\x1a\x15.auth.RevocationEvent0\x01B)Z'github.com/smilemakc/auth-gateway/protob\x06proto3"
 
 var (
 	file_proto_auth_proto_rawDescOnce sync.Once
@@ -4945,7 +6271,7 @@ func file_proto_auth_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_auth_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 65)
+var file_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 85)
 var file_proto_auth_proto_goTypes = []any{
 	(OTPType)(0),                                     // 0: auth.OTPType
 	(*ValidateTokenRequest)(nil),                     // 1: auth.ValidateTokenRequest
@@ -4999,104 +6325,152 @@ var file_proto_auth_proto_goTypes = []any{
 	(*UserTelegramBotsResponse)(nil),                 // 49: auth.UserTelegramBotsResponse
 	(*SendEmailRequest)(nil),                         // 50: auth.SendEmailRequest
 	(*SendEmailResponse)(nil),                        // 51: auth.SendEmailResponse
-	(*SyncUsersRequest)(nil),                         // 52: auth.SyncUsersRequest
-	(*SyncUsersResponse)(nil),                        // 53: auth.SyncUsersResponse
-	(*SyncUser)(nil),                                 // 54: auth.SyncUser
-	(*SyncUserAppProfile)(nil),                       // 55: auth.SyncUserAppProfile
-	(*GetApplicationAuthConfigRequest)(nil),          // 56: auth.GetApplicationAuthConfigRequest
-	(*GetApplicationAuthConfigResponse)(nil),         // 57: auth.GetApplicationAuthConfigResponse
-	(*CreateTokenExchangeGrpcRequest)(nil),           // 58: auth.CreateTokenExchangeGrpcRequest
-	(*CreateTokenExchangeGrpcResponse)(nil),          // 59: auth.CreateTokenExchangeGrpcResponse
-	(*RedeemTokenExchangeGrpcRequest)(nil),           // 60: auth.RedeemTokenExchangeGrpcRequest
-	(*RedeemTokenExchangeGrpcResponse)(nil),          // 61: auth.RedeemTokenExchangeGrpcResponse
-	nil,                                              // 62: auth.UserAppProfileResponse.MetadataEntry
-	nil,                                              // 63: auth.UpdateUserProfileRequest.MetadataEntry
-	nil,                                              // 64: auth.CreateUserProfileRequest.MetadataEntry
-	nil,                                              // 65: auth.SendEmailRequest.VariablesEntry
+	(*ListUsersRequest)(nil),                         // 52: auth.ListUsersRequest
+	(*ListUsersResponse)(nil),                        // 53: auth.ListUsersResponse
+	(*GetUserByEmailRequest)(nil),                    // 54: auth.GetUserByEmailRequest
+	(*UpdateUserRequest)(nil),                        // 55: auth.UpdateUserRequest
+	(*DeactivateUserRequest)(nil),                    // 56: auth.DeactivateUserRequest
+	(*AssignRoleRequest)(nil),                        // 57: auth.AssignRoleRequest
+	(*RemoveRoleRequest)(nil),                        // 58: auth.RemoveRoleRequest
+	(*UserResponse)(nil),                             // 59: auth.UserResponse
+	(*ListUserSessionsRequest)(nil),                  // 60: auth.ListUserSessionsRequest
+	(*Session)(nil),                                  // 61: auth.Session
+	(*ListUserSessionsResponse)(nil),                 // 62: auth.ListUserSessionsResponse
+	(*RevokeSessionRequest)(nil),                     // 63: auth.RevokeSessionRequest
+	(*RevokeAllUserSessionsRequest)(nil),             // 64: auth.RevokeAllUserSessionsRequest
+	(*SyncUsersRequest)(nil),                         // 65: auth.SyncUsersRequest
+	(*SyncUsersResponse)(nil),                        // 66: auth.SyncUsersResponse
+	(*SyncUser)(nil),                                 // 67: auth.SyncUser
+	(*SyncUserAppProfile)(nil),                       // 68: auth.SyncUserAppProfile
+	(*GetApplicationAuthConfigRequest)(nil),          // 69: auth.GetApplicationAuthConfigRequest
+	(*GetApplicationAuthConfigResponse)(nil),         // 70: auth.GetApplicationAuthConfigResponse
+	(*CreateTokenExchangeGrpcRequest)(nil),           // 71: auth.CreateTokenExchangeGrpcRequest
+	(*CreateTokenExchangeGrpcResponse)(nil),          // 72: auth.CreateTokenExchangeGrpcResponse
+	(*RedeemTokenExchangeGrpcRequest)(nil),           // 73: auth.RedeemTokenExchangeGrpcRequest
+	(*RedeemTokenExchangeGrpcResponse)(nil),          // 74: auth.RedeemTokenExchangeGrpcResponse
+	(*PermissionCatalogEntry)(nil),                   // 75: auth.PermissionCatalogEntry
+	(*RegisterPermissionCatalogRequest)(nil),         // 76: auth.RegisterPermissionCatalogRequest
+	(*RegisterPermissionCatalogResponse)(nil),        // 77: auth.RegisterPermissionCatalogResponse
+	(*WatchRevocationsRequest)(nil),                  // 78: auth.WatchRevocationsRequest
+	(*RevocationEvent)(nil),                          // 79: auth.RevocationEvent
+	nil,                                              // 80: auth.CheckPermissionRequest.ResourceAttributesEntry
+	nil,                                              // 81: auth.CheckPermissionRequest.ContextAttributesEntry
+	nil,                                              // 82: auth.UserAppProfileResponse.MetadataEntry
+	nil,                                              // 83: auth.UpdateUserProfileRequest.MetadataEntry
+	nil,                                              // 84: auth.CreateUserProfileRequest.MetadataEntry
+	nil,                                              // 85: auth.SendEmailRequest.VariablesEntry
 }
 var file_proto_auth_proto_depIdxs = []int32{
 	4,  // 0: auth.GetUserResponse.user:type_name -> auth.User
-	4,  // 1: auth.CreateUserResponse.user:type_name -> auth.User
-	4,  // 2: auth.LoginResponse.user:type_name -> auth.User
-	4,  // 3: auth.CompletePasswordlessRegistrationResponse.user:type_name -> auth.User
-	0,  // 4: auth.SendOTPRequest.otp_type:type_name -> auth.OTPType
-	0,  // 5: auth.VerifyOTPRequest.otp_type:type_name -> auth.OTPType
-	4,  // 6: auth.VerifyRegistrationOTPResponse.user:type_name -> auth.User
-	4,  // 7: auth.VerifyLoginOTPResponse.user:type_name -> auth.User
-	35, // 8: auth.GetOAuthClientResponse.client:type_name -> auth.OAuthClient
-	62, // 9: auth.UserAppProfileResponse.metadata:type_name -> auth.UserAppProfileResponse.MetadataEntry
-	63, // 10: auth.UpdateUserProfileRequest.metadata:type_name -> auth.UpdateUserProfileRequest.MetadataEntry
-	64, // 11: auth.CreateUserProfileRequest.metadata:type_name -> auth.CreateUserProfileRequest.MetadataEntry
-	38, // 12: auth.ListApplicationUsersResponse.profiles:type_name -> auth.UserAppProfileResponse
-	48, // 13: auth.UserTelegramBotsResponse.bots:type_name -> auth.TelegramBotAccess
-	65, // 14: auth.SendEmailRequest.variables:type_name -> auth.SendEmailRequest.VariablesEntry
-	54, // 15: auth.SyncUsersResponse.users:type_name -> auth.SyncUser
-	55, // 16: auth.SyncUser.app_profile:type_name -> auth.SyncUserAppProfile
-	1,  // 17: auth.AuthService.ValidateToken:input_type -> auth.ValidateTokenRequest
-	3,  // 18: auth.AuthService.GetUser:input_type -> auth.GetUserRequest
-	6,  // 19: auth.AuthService.CheckPermission:input_type -> auth.CheckPermissionRequest
-	8,  // 20: auth.AuthService.IntrospectToken:input_type -> auth.IntrospectTokenRequest
-	10, // 21: auth.AuthService.CreateUser:input_type -> auth.CreateUserRequest
-	12, // 22: auth.AuthService.Login:input_type -> auth.LoginRequest
-	14, // 23: auth.AuthService.InitPasswordlessRegistration:input_type -> auth.InitPasswordlessRegistrationRequest
-	16, // 24: auth.AuthService.CompletePasswordlessRegistration:input_type -> auth.CompletePasswordlessRegistrationRequest
-	18, // 25: auth.AuthService.SendOTP:input_type -> auth.SendOTPRequest
-	20, // 26: auth.AuthService.VerifyOTP:input_type -> auth.VerifyOTPRequest
-	22, // 27: auth.AuthService.LoginWithOTP:input_type -> auth.LoginWithOTPRequest
-	28, // 28: auth.AuthService.VerifyLoginOTP:input_type -> auth.VerifyLoginOTPRequest
-	24, // 29: auth.AuthService.RegisterWithOTP:input_type -> auth.RegisterWithOTPRequest
-	26, // 30: auth.AuthService.VerifyRegistrationOTP:input_type -> auth.VerifyRegistrationOTPRequest
-	30, // 31: auth.AuthService.IntrospectOAuthToken:input_type -> auth.IntrospectOAuthTokenRequest
-	32, // 32: auth.AuthService.ValidateOAuthClient:input_type -> auth.ValidateOAuthClientRequest
-	34, // 33: auth.AuthService.GetOAuthClient:input_type -> auth.GetOAuthClientRequest
-	50, // 34: auth.AuthService.SendEmail:input_type -> auth.SendEmailRequest
-	37, // 35: auth.AuthService.GetUserApplicationProfile:input_type -> auth.GetUserAppProfileRequest
-	47, // 36: auth.AuthService.GetUserTelegramBots:input_type -> auth.GetUserTelegramBotsRequest
-	39, // 37: auth.AuthService.UpdateUserProfile:input_type -> auth.UpdateUserProfileRequest
-	40, // 38: auth.AuthService.CreateUserProfile:input_type -> auth.CreateUserProfileRequest
-	41, // 39: auth.AuthService.DeleteUserProfile:input_type -> auth.DeleteUserProfileRequest
-	42, // 40: auth.AuthService.BanUser:input_type -> auth.BanUserRequest
-	43, // 41: auth.AuthService.UnbanUser:input_type -> auth.UnbanUserRequest
-	44, // 42: auth.AuthService.ListApplicationUsers:input_type -> auth.ListApplicationUsersRequest
-	52, // 43: auth.AuthService.SyncUsers:input_type -> auth.SyncUsersRequest
-	56, // 44: auth.AuthService.GetApplicationAuthConfig:input_type -> auth.GetApplicationAuthConfigRequest
-	58, // 45: auth.AuthService.CreateTokenExchange:input_type -> auth.CreateTokenExchangeGrpcRequest
-	60, // 46: auth.AuthService.RedeemTokenExchange:input_type -> auth.RedeemTokenExchangeGrpcRequest
-	2,  // 47: auth.AuthService.ValidateToken:output_type -> auth.ValidateTokenResponse
-	5,  // 48: auth.AuthService.GetUser:output_type -> auth.GetUserResponse
-	7,  // 49: auth.AuthService.CheckPermission:output_type -> auth.CheckPermissionResponse
-	9,  // 50: auth.AuthService.IntrospectToken:output_type -> auth.IntrospectTokenResponse
-	11, // 51: auth.AuthService.CreateUser:output_type -> auth.CreateUserResponse
-	13, // 52: auth.AuthService.Login:output_type -> auth.LoginResponse
-	15, // 53: auth.AuthService.InitPasswordlessRegistration:output_type -> auth.InitPasswordlessRegistrationResponse
-	17, // 54: auth.AuthService.CompletePasswordlessRegistration:output_type -> auth.CompletePasswordlessRegistrationResponse
-	19, // 55: auth.AuthService.SendOTP:output_type -> auth.SendOTPResponse
-	21, // 56: auth.AuthService.VerifyOTP:output_type -> auth.VerifyOTPResponse
-	23, // 57: auth.AuthService.LoginWithOTP:output_type -> auth.LoginWithOTPResponse
-	29, // 58: auth.AuthService.VerifyLoginOTP:output_type -> auth.VerifyLoginOTPResponse
-	25, // 59: auth.AuthService.RegisterWithOTP:output_type -> auth.RegisterWithOTPResponse
-	27, // 60: auth.AuthService.VerifyRegistrationOTP:output_type -> auth.VerifyRegistrationOTPResponse
-	31, // 61: auth.AuthService.IntrospectOAuthToken:output_type -> auth.IntrospectOAuthTokenResponse
-	33, // 62: auth.AuthService.ValidateOAuthClient:output_type -> auth.ValidateOAuthClientResponse
-	36, // 63: auth.AuthService.GetOAuthClient:output_type -> auth.GetOAuthClientResponse
-	51, // 64: auth.AuthService.SendEmail:output_type -> auth.SendEmailResponse
-	38, // 65: auth.AuthService.GetUserApplicationProfile:output_type -> auth.UserAppProfileResponse
-	49, // 66: auth.AuthService.GetUserTelegramBots:output_type -> auth.UserTelegramBotsResponse
-	38, // 67: auth.AuthService.UpdateUserProfile:output_type -> auth.UserAppProfileResponse
-	38, // 68: auth.AuthService.CreateUserProfile:output_type -> auth.UserAppProfileResponse
-	46, // 69: auth.AuthService.DeleteUserProfile:output_type -> auth.GenericResponse
-	46, // 70: auth.AuthService.BanUser:output_type -> auth.GenericResponse
-	46, // 71: auth.AuthService.UnbanUser:output_type -> auth.GenericResponse
-	45, // 72: auth.AuthService.ListApplicationUsers:output_type -> auth.ListApplicationUsersResponse
-	53, // 73: auth.AuthService.SyncUsers:output_type -> auth.SyncUsersResponse
-	57, // 74: auth.AuthService.GetApplicationAuthConfig:output_type -> auth.GetApplicationAuthConfigResponse
-	59, // 75: auth.AuthService.CreateTokenExchange:output_type -> auth.CreateTokenExchangeGrpcResponse
-	61, // 76: auth.AuthService.RedeemTokenExchange:output_type -> auth.RedeemTokenExchangeGrpcResponse
-	47, // [47:77] is the sub-list for method output_type
-	17, // [17:47] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	80, // 1: auth.CheckPermissionRequest.resource_attributes:type_name -> auth.CheckPermissionRequest.ResourceAttributesEntry
+	81, // 2: auth.CheckPermissionRequest.context_attributes:type_name -> auth.CheckPermissionRequest.ContextAttributesEntry
+	4,  // 3: auth.CreateUserResponse.user:type_name -> auth.User
+	4,  // 4: auth.LoginResponse.user:type_name -> auth.User
+	4,  // 5: auth.CompletePasswordlessRegistrationResponse.user:type_name -> auth.User
+	0,  // 6: auth.SendOTPRequest.otp_type:type_name -> auth.OTPType
+	0,  // 7: auth.VerifyOTPRequest.otp_type:type_name -> auth.OTPType
+	4,  // 8: auth.VerifyRegistrationOTPResponse.user:type_name -> auth.User
+	4,  // 9: auth.VerifyLoginOTPResponse.user:type_name -> auth.User
+	35, // 10: auth.GetOAuthClientResponse.client:type_name -> auth.OAuthClient
+	82, // 11: auth.UserAppProfileResponse.metadata:type_name -> auth.UserAppProfileResponse.MetadataEntry
+	83, // 12: auth.UpdateUserProfileRequest.metadata:type_name -> auth.UpdateUserProfileRequest.MetadataEntry
+	84, // 13: auth.CreateUserProfileRequest.metadata:type_name -> auth.CreateUserProfileRequest.MetadataEntry
+	38, // 14: auth.ListApplicationUsersResponse.profiles:type_name -> auth.UserAppProfileResponse
+	48, // 15: auth.UserTelegramBotsResponse.bots:type_name -> auth.TelegramBotAccess
+	85, // 16: auth.SendEmailRequest.variables:type_name -> auth.SendEmailRequest.VariablesEntry
+	4,  // 17: auth.ListUsersResponse.users:type_name -> auth.User
+	4,  // 18: auth.UserResponse.user:type_name -> auth.User
+	61, // 19: auth.ListUserSessionsResponse.sessions:type_name -> auth.Session
+	67, // 20: auth.SyncUsersResponse.users:type_name -> auth.SyncUser
+	68, // 21: auth.SyncUser.app_profile:type_name -> auth.SyncUserAppProfile
+	75, // 22: auth.RegisterPermissionCatalogRequest.entries:type_name -> auth.PermissionCatalogEntry
+	1,  // 23: auth.AuthService.ValidateToken:input_type -> auth.ValidateTokenRequest
+	3,  // 24: auth.AuthService.GetUser:input_type -> auth.GetUserRequest
+	6,  // 25: auth.AuthService.CheckPermission:input_type -> auth.CheckPermissionRequest
+	8,  // 26: auth.AuthService.IntrospectToken:input_type -> auth.IntrospectTokenRequest
+	10, // 27: auth.AuthService.CreateUser:input_type -> auth.CreateUserRequest
+	12, // 28: auth.AuthService.Login:input_type -> auth.LoginRequest
+	14, // 29: auth.AuthService.InitPasswordlessRegistration:input_type -> auth.InitPasswordlessRegistrationRequest
+	16, // 30: auth.AuthService.CompletePasswordlessRegistration:input_type -> auth.CompletePasswordlessRegistrationRequest
+	18, // 31: auth.AuthService.SendOTP:input_type -> auth.SendOTPRequest
+	20, // 32: auth.AuthService.VerifyOTP:input_type -> auth.VerifyOTPRequest
+	22, // 33: auth.AuthService.LoginWithOTP:input_type -> auth.LoginWithOTPRequest
+	28, // 34: auth.AuthService.VerifyLoginOTP:input_type -> auth.VerifyLoginOTPRequest
+	24, // 35: auth.AuthService.RegisterWithOTP:input_type -> auth.RegisterWithOTPRequest
+	26, // 36: auth.AuthService.VerifyRegistrationOTP:input_type -> auth.VerifyRegistrationOTPRequest
+	30, // 37: auth.AuthService.IntrospectOAuthToken:input_type -> auth.IntrospectOAuthTokenRequest
+	32, // 38: auth.AuthService.ValidateOAuthClient:input_type -> auth.ValidateOAuthClientRequest
+	34, // 39: auth.AuthService.GetOAuthClient:input_type -> auth.GetOAuthClientRequest
+	50, // 40: auth.AuthService.SendEmail:input_type -> auth.SendEmailRequest
+	37, // 41: auth.AuthService.GetUserApplicationProfile:input_type -> auth.GetUserAppProfileRequest
+	47, // 42: auth.AuthService.GetUserTelegramBots:input_type -> auth.GetUserTelegramBotsRequest
+	39, // 43: auth.AuthService.UpdateUserProfile:input_type -> auth.UpdateUserProfileRequest
+	40, // 44: auth.AuthService.CreateUserProfile:input_type -> auth.CreateUserProfileRequest
+	41, // 45: auth.AuthService.DeleteUserProfile:input_type -> auth.DeleteUserProfileRequest
+	42, // 46: auth.AuthService.BanUser:input_type -> auth.BanUserRequest
+	43, // 47: auth.AuthService.UnbanUser:input_type -> auth.UnbanUserRequest
+	44, // 48: auth.AuthService.ListApplicationUsers:input_type -> auth.ListApplicationUsersRequest
+	52, // 49: auth.AuthService.ListUsers:input_type -> auth.ListUsersRequest
+	54, // 50: auth.AuthService.GetUserByEmail:input_type -> auth.GetUserByEmailRequest
+	55, // 51: auth.AuthService.UpdateUser:input_type -> auth.UpdateUserRequest
+	56, // 52: auth.AuthService.DeactivateUser:input_type -> auth.DeactivateUserRequest
+	57, // 53: auth.AuthService.AssignRole:input_type -> auth.AssignRoleRequest
+	58, // 54: auth.AuthService.RemoveRole:input_type -> auth.RemoveRoleRequest
+	60, // 55: auth.AuthService.ListUserSessions:input_type -> auth.ListUserSessionsRequest
+	63, // 56: auth.AuthService.RevokeSession:input_type -> auth.RevokeSessionRequest
+	64, // 57: auth.AuthService.RevokeAllUserSessions:input_type -> auth.RevokeAllUserSessionsRequest
+	65, // 58: auth.AuthService.SyncUsers:input_type -> auth.SyncUsersRequest
+	69, // 59: auth.AuthService.GetApplicationAuthConfig:input_type -> auth.GetApplicationAuthConfigRequest
+	71, // 60: auth.AuthService.CreateTokenExchange:input_type -> auth.CreateTokenExchangeGrpcRequest
+	73, // 61: auth.AuthService.RedeemTokenExchange:input_type -> auth.RedeemTokenExchangeGrpcRequest
+	76, // 62: auth.AuthService.RegisterPermissionCatalog:input_type -> auth.RegisterPermissionCatalogRequest
+	78, // 63: auth.AuthService.WatchRevocations:input_type -> auth.WatchRevocationsRequest
+	2,  // 64: auth.AuthService.ValidateToken:output_type -> auth.ValidateTokenResponse
+	5,  // 65: auth.AuthService.GetUser:output_type -> auth.GetUserResponse
+	7,  // 66: auth.AuthService.CheckPermission:output_type -> auth.CheckPermissionResponse
+	9,  // 67: auth.AuthService.IntrospectToken:output_type -> auth.IntrospectTokenResponse
+	11, // 68: auth.AuthService.CreateUser:output_type -> auth.CreateUserResponse
+	13, // 69: auth.AuthService.Login:output_type -> auth.LoginResponse
+	15, // 70: auth.AuthService.InitPasswordlessRegistration:output_type -> auth.InitPasswordlessRegistrationResponse
+	17, // 71: auth.AuthService.CompletePasswordlessRegistration:output_type -> auth.CompletePasswordlessRegistrationResponse
+	19, // 72: auth.AuthService.SendOTP:output_type -> auth.SendOTPResponse
+	21, // 73: auth.AuthService.VerifyOTP:output_type -> auth.VerifyOTPResponse
+	23, // 74: auth.AuthService.LoginWithOTP:output_type -> auth.LoginWithOTPResponse
+	29, // 75: auth.AuthService.VerifyLoginOTP:output_type -> auth.VerifyLoginOTPResponse
+	25, // 76: auth.AuthService.RegisterWithOTP:output_type -> auth.RegisterWithOTPResponse
+	27, // 77: auth.AuthService.VerifyRegistrationOTP:output_type -> auth.VerifyRegistrationOTPResponse
+	31, // 78: auth.AuthService.IntrospectOAuthToken:output_type -> auth.IntrospectOAuthTokenResponse
+	33, // 79: auth.AuthService.ValidateOAuthClient:output_type -> auth.ValidateOAuthClientResponse
+	36, // 80: auth.AuthService.GetOAuthClient:output_type -> auth.GetOAuthClientResponse
+	51, // 81: auth.AuthService.SendEmail:output_type -> auth.SendEmailResponse
+	38, // 82: auth.AuthService.GetUserApplicationProfile:output_type -> auth.UserAppProfileResponse
+	49, // 83: auth.AuthService.GetUserTelegramBots:output_type -> auth.UserTelegramBotsResponse
+	38, // 84: auth.AuthService.UpdateUserProfile:output_type -> auth.UserAppProfileResponse
+	38, // 85: auth.AuthService.CreateUserProfile:output_type -> auth.UserAppProfileResponse
+	46, // 86: auth.AuthService.DeleteUserProfile:output_type -> auth.GenericResponse
+	46, // 87: auth.AuthService.BanUser:output_type -> auth.GenericResponse
+	46, // 88: auth.AuthService.UnbanUser:output_type -> auth.GenericResponse
+	45, // 89: auth.AuthService.ListApplicationUsers:output_type -> auth.ListApplicationUsersResponse
+	53, // 90: auth.AuthService.ListUsers:output_type -> auth.ListUsersResponse
+	5,  // 91: auth.AuthService.GetUserByEmail:output_type -> auth.GetUserResponse
+	59, // 92: auth.AuthService.UpdateUser:output_type -> auth.UserResponse
+	59, // 93: auth.AuthService.DeactivateUser:output_type -> auth.UserResponse
+	59, // 94: auth.AuthService.AssignRole:output_type -> auth.UserResponse
+	59, // 95: auth.AuthService.RemoveRole:output_type -> auth.UserResponse
+	62, // 96: auth.AuthService.ListUserSessions:output_type -> auth.ListUserSessionsResponse
+	46, // 97: auth.AuthService.RevokeSession:output_type -> auth.GenericResponse
+	46, // 98: auth.AuthService.RevokeAllUserSessions:output_type -> auth.GenericResponse
+	66, // 99: auth.AuthService.SyncUsers:output_type -> auth.SyncUsersResponse
+	70, // 100: auth.AuthService.GetApplicationAuthConfig:output_type -> auth.GetApplicationAuthConfigResponse
+	72, // 101: auth.AuthService.CreateTokenExchange:output_type -> auth.CreateTokenExchangeGrpcResponse
+	74, // 102: auth.AuthService.RedeemTokenExchange:output_type -> auth.RedeemTokenExchangeGrpcResponse
+	77, // 103: auth.AuthService.RegisterPermissionCatalog:output_type -> auth.RegisterPermissionCatalogResponse
+	79, // 104: auth.AuthService.WatchRevocations:output_type -> auth.RevocationEvent
+	64, // [64:105] is the sub-list for method output_type
+	23, // [23:64] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_proto_auth_proto_init() }
@@ -5104,13 +6478,14 @@ func file_proto_auth_proto_init() {
 	if File_proto_auth_proto != nil {
 		return
 	}
+	file_proto_auth_proto_msgTypes[54].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_auth_proto_rawDesc), len(file_proto_auth_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   65,
+			NumMessages:   85,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
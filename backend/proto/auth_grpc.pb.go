@@ -45,10 +45,21 @@ const (
 	AuthService_BanUser_FullMethodName                          = "/auth.AuthService/BanUser"
 	AuthService_UnbanUser_FullMethodName                        = "/auth.AuthService/UnbanUser"
 	AuthService_ListApplicationUsers_FullMethodName             = "/auth.AuthService/ListApplicationUsers"
+	AuthService_ListUsers_FullMethodName                        = "/auth.AuthService/ListUsers"
+	AuthService_GetUserByEmail_FullMethodName                   = "/auth.AuthService/GetUserByEmail"
+	AuthService_UpdateUser_FullMethodName                       = "/auth.AuthService/UpdateUser"
+	AuthService_DeactivateUser_FullMethodName                   = "/auth.AuthService/DeactivateUser"
+	AuthService_AssignRole_FullMethodName                       = "/auth.AuthService/AssignRole"
+	AuthService_RemoveRole_FullMethodName                       = "/auth.AuthService/RemoveRole"
+	AuthService_ListUserSessions_FullMethodName                 = "/auth.AuthService/ListUserSessions"
+	AuthService_RevokeSession_FullMethodName                    = "/auth.AuthService/RevokeSession"
+	AuthService_RevokeAllUserSessions_FullMethodName            = "/auth.AuthService/RevokeAllUserSessions"
 	AuthService_SyncUsers_FullMethodName                        = "/auth.AuthService/SyncUsers"
 	AuthService_GetApplicationAuthConfig_FullMethodName         = "/auth.AuthService/GetApplicationAuthConfig"
 	AuthService_CreateTokenExchange_FullMethodName              = "/auth.AuthService/CreateTokenExchange"
 	AuthService_RedeemTokenExchange_FullMethodName              = "/auth.AuthService/RedeemTokenExchange"
+	AuthService_RegisterPermissionCatalog_FullMethodName        = "/auth.AuthService/RegisterPermissionCatalog"
+	AuthService_WatchRevocations_FullMethodName                 = "/auth.AuthService/WatchRevocations"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -109,6 +120,25 @@ type AuthServiceClient interface {
 	UnbanUser(ctx context.Context, in *UnbanUserRequest, opts ...grpc.CallOption) (*GenericResponse, error)
 	// ListApplicationUsers lists all users for a specific application with pagination
 	ListApplicationUsers(ctx context.Context, in *ListApplicationUsersRequest, opts ...grpc.CallOption) (*ListApplicationUsersResponse, error)
+	// ListUsers lists users with pagination, optionally searching by email, username or name
+	// or restricted to the users of an application
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// GetUserByEmail retrieves user information by email address
+	GetUserByEmail(ctx context.Context, in *GetUserByEmailRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	// UpdateUser updates a user's account; empty fields are left unchanged
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// DeactivateUser deactivates a user's account and revokes their tokens
+	DeactivateUser(ctx context.Context, in *DeactivateUserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// AssignRole assigns a role to a user
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// RemoveRole removes a role from a user; a user keeps at least one role
+	RemoveRole(ctx context.Context, in *RemoveRoleRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// ListUserSessions lists the active sessions of a user with pagination
+	ListUserSessions(ctx context.Context, in *ListUserSessionsRequest, opts ...grpc.CallOption) (*ListUserSessionsResponse, error)
+	// RevokeSession revokes one session of a user and blacklists its tokens
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	// RevokeAllUserSessions revokes every session of a user and blacklists their tokens
+	RevokeAllUserSessions(ctx context.Context, in *RevokeAllUserSessionsRequest, opts ...grpc.CallOption) (*GenericResponse, error)
 	// SyncUsers returns users updated after a given timestamp for shadow table sync
 	SyncUsers(ctx context.Context, in *SyncUsersRequest, opts ...grpc.CallOption) (*SyncUsersResponse, error)
 	// GetApplicationAuthConfig returns auth configuration for a specific application
@@ -117,6 +147,15 @@ type AuthServiceClient interface {
 	CreateTokenExchange(ctx context.Context, in *CreateTokenExchangeGrpcRequest, opts ...grpc.CallOption) (*CreateTokenExchangeGrpcResponse, error)
 	// RedeemTokenExchange redeems an exchange code for tokens in the target application
 	RedeemTokenExchange(ctx context.Context, in *RedeemTokenExchangeGrpcRequest, opts ...grpc.CallOption) (*RedeemTokenExchangeGrpcResponse, error)
+	// RegisterPermissionCatalog publishes the resources and actions a service checks, so they
+	// show up in the admin permission catalog. Replaces the entries previously registered by
+	// the same service.
+	RegisterPermissionCatalog(ctx context.Context, in *RegisterPermissionCatalogRequest, opts ...grpc.CallOption) (*RegisterPermissionCatalogResponse, error)
+	// WatchRevocations streams token and session revocations as they happen, so services can
+	// drop cached validation results instead of polling IntrospectToken. The stream ends with
+	// UNAVAILABLE when events may have been missed; clients should then flush their caches and
+	// subscribe again.
+	WatchRevocations(ctx context.Context, in *WatchRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RevocationEvent], error)
 }
 
 type authServiceClient struct {
@@ -387,6 +426,96 @@ func (c *authServiceClient) ListApplicationUsers(ctx context.Context, in *ListAp
 	return out, nil
 }
 
+func (c *authServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, AuthService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) GetUserByEmail(ctx context.Context, in *GetUserByEmailRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, AuthService_GetUserByEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AuthService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) DeactivateUser(ctx context.Context, in *DeactivateUserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AuthService_DeactivateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AuthService_AssignRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RemoveRole(ctx context.Context, in *RemoveRoleRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, AuthService_RemoveRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ListUserSessions(ctx context.Context, in *ListUserSessionsRequest, opts ...grpc.CallOption) (*ListUserSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserSessionsResponse)
+	err := c.cc.Invoke(ctx, AuthService_ListUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, AuthService_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RevokeAllUserSessions(ctx context.Context, in *RevokeAllUserSessionsRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, AuthService_RevokeAllUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authServiceClient) SyncUsers(ctx context.Context, in *SyncUsersRequest, opts ...grpc.CallOption) (*SyncUsersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SyncUsersResponse)
@@ -427,6 +556,35 @@ func (c *authServiceClient) RedeemTokenExchange(ctx context.Context, in *RedeemT
 	return out, nil
 }
 
+func (c *authServiceClient) RegisterPermissionCatalog(ctx context.Context, in *RegisterPermissionCatalogRequest, opts ...grpc.CallOption) (*RegisterPermissionCatalogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterPermissionCatalogResponse)
+	err := c.cc.Invoke(ctx, AuthService_RegisterPermissionCatalog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) WatchRevocations(ctx context.Context, in *WatchRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RevocationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuthService_ServiceDesc.Streams[0], AuthService_WatchRevocations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRevocationsRequest, RevocationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthService_WatchRevocationsClient = grpc.ServerStreamingClient[RevocationEvent]
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -485,6 +643,25 @@ type AuthServiceServer interface {
 	UnbanUser(context.Context, *UnbanUserRequest) (*GenericResponse, error)
 	// ListApplicationUsers lists all users for a specific application with pagination
 	ListApplicationUsers(context.Context, *ListApplicationUsersRequest) (*ListApplicationUsersResponse, error)
+	// ListUsers lists users with pagination, optionally searching by email, username or name
+	// or restricted to the users of an application
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	// GetUserByEmail retrieves user information by email address
+	GetUserByEmail(context.Context, *GetUserByEmailRequest) (*GetUserResponse, error)
+	// UpdateUser updates a user's account; empty fields are left unchanged
+	UpdateUser(context.Context, *UpdateUserRequest) (*UserResponse, error)
+	// DeactivateUser deactivates a user's account and revokes their tokens
+	DeactivateUser(context.Context, *DeactivateUserRequest) (*UserResponse, error)
+	// AssignRole assigns a role to a user
+	AssignRole(context.Context, *AssignRoleRequest) (*UserResponse, error)
+	// RemoveRole removes a role from a user; a user keeps at least one role
+	RemoveRole(context.Context, *RemoveRoleRequest) (*UserResponse, error)
+	// ListUserSessions lists the active sessions of a user with pagination
+	ListUserSessions(context.Context, *ListUserSessionsRequest) (*ListUserSessionsResponse, error)
+	// RevokeSession revokes one session of a user and blacklists its tokens
+	RevokeSession(context.Context, *RevokeSessionRequest) (*GenericResponse, error)
+	// RevokeAllUserSessions revokes every session of a user and blacklists their tokens
+	RevokeAllUserSessions(context.Context, *RevokeAllUserSessionsRequest) (*GenericResponse, error)
 	// SyncUsers returns users updated after a given timestamp for shadow table sync
 	SyncUsers(context.Context, *SyncUsersRequest) (*SyncUsersResponse, error)
 	// GetApplicationAuthConfig returns auth configuration for a specific application
@@ -493,6 +670,15 @@ type AuthServiceServer interface {
 	CreateTokenExchange(context.Context, *CreateTokenExchangeGrpcRequest) (*CreateTokenExchangeGrpcResponse, error)
 	// RedeemTokenExchange redeems an exchange code for tokens in the target application
 	RedeemTokenExchange(context.Context, *RedeemTokenExchangeGrpcRequest) (*RedeemTokenExchangeGrpcResponse, error)
+	// RegisterPermissionCatalog publishes the resources and actions a service checks, so they
+	// show up in the admin permission catalog. Replaces the entries previously registered by
+	// the same service.
+	RegisterPermissionCatalog(context.Context, *RegisterPermissionCatalogRequest) (*RegisterPermissionCatalogResponse, error)
+	// WatchRevocations streams token and session revocations as they happen, so services can
+	// drop cached validation results instead of polling IntrospectToken. The stream ends with
+	// UNAVAILABLE when events may have been missed; clients should then flush their caches and
+	// subscribe again.
+	WatchRevocations(*WatchRevocationsRequest, grpc.ServerStreamingServer[RevocationEvent]) error
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -581,6 +767,33 @@ func (UnimplementedAuthServiceServer) UnbanUser(context.Context, *UnbanUserReque
 func (UnimplementedAuthServiceServer) ListApplicationUsers(context.Context, *ListApplicationUsersRequest) (*ListApplicationUsersResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListApplicationUsers not implemented")
 }
+func (UnimplementedAuthServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedAuthServiceServer) GetUserByEmail(context.Context, *GetUserByEmailRequest) (*GetUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserByEmail not implemented")
+}
+func (UnimplementedAuthServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*UserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedAuthServiceServer) DeactivateUser(context.Context, *DeactivateUserRequest) (*UserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeactivateUser not implemented")
+}
+func (UnimplementedAuthServiceServer) AssignRole(context.Context, *AssignRoleRequest) (*UserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedAuthServiceServer) RemoveRole(context.Context, *RemoveRoleRequest) (*UserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveRole not implemented")
+}
+func (UnimplementedAuthServiceServer) ListUserSessions(context.Context, *ListUserSessionsRequest) (*ListUserSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUserSessions not implemented")
+}
+func (UnimplementedAuthServiceServer) RevokeSession(context.Context, *RevokeSessionRequest) (*GenericResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedAuthServiceServer) RevokeAllUserSessions(context.Context, *RevokeAllUserSessionsRequest) (*GenericResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAllUserSessions not implemented")
+}
 func (UnimplementedAuthServiceServer) SyncUsers(context.Context, *SyncUsersRequest) (*SyncUsersResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SyncUsers not implemented")
 }
@@ -593,6 +806,12 @@ func (UnimplementedAuthServiceServer) CreateTokenExchange(context.Context, *Crea
 func (UnimplementedAuthServiceServer) RedeemTokenExchange(context.Context, *RedeemTokenExchangeGrpcRequest) (*RedeemTokenExchangeGrpcResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RedeemTokenExchange not implemented")
 }
+func (UnimplementedAuthServiceServer) RegisterPermissionCatalog(context.Context, *RegisterPermissionCatalogRequest) (*RegisterPermissionCatalogResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterPermissionCatalog not implemented")
+}
+func (UnimplementedAuthServiceServer) WatchRevocations(*WatchRevocationsRequest, grpc.ServerStreamingServer[RevocationEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchRevocations not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -1082,6 +1301,168 @@ func _AuthService_ListApplicationUsers_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_GetUserByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserByEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetUserByEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetUserByEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetUserByEmail(ctx, req.(*GetUserByEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_DeactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).DeactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_DeactivateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).DeactivateUser(ctx, req.(*DeactivateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AssignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RemoveRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RemoveRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RemoveRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RemoveRole(ctx, req.(*RemoveRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ListUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ListUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListUserSessions(ctx, req.(*ListUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RevokeAllUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RevokeAllUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RevokeAllUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RevokeAllUserSessions(ctx, req.(*RevokeAllUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthService_SyncUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SyncUsersRequest)
 	if err := dec(in); err != nil {
@@ -1154,6 +1535,35 @@ func _AuthService_RedeemTokenExchange_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_RegisterPermissionCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterPermissionCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RegisterPermissionCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RegisterPermissionCatalog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RegisterPermissionCatalog(ctx, req.(*RegisterPermissionCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_WatchRevocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRevocationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServiceServer).WatchRevocations(m, &grpc.GenericServerStream[WatchRevocationsRequest, RevocationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthService_WatchRevocationsServer = grpc.ServerStreamingServer[RevocationEvent]
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1265,6 +1675,42 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListApplicationUsers",
 			Handler:    _AuthService_ListApplicationUsers_Handler,
 		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _AuthService_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUserByEmail",
+			Handler:    _AuthService_GetUserByEmail_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _AuthService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeactivateUser",
+			Handler:    _AuthService_DeactivateUser_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _AuthService_AssignRole_Handler,
+		},
+		{
+			MethodName: "RemoveRole",
+			Handler:    _AuthService_RemoveRole_Handler,
+		},
+		{
+			MethodName: "ListUserSessions",
+			Handler:    _AuthService_ListUserSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _AuthService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllUserSessions",
+			Handler:    _AuthService_RevokeAllUserSessions_Handler,
+		},
 		{
 			MethodName: "SyncUsers",
 			Handler:    _AuthService_SyncUsers_Handler,
@@ -1281,7 +1727,17 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RedeemTokenExchange",
 			Handler:    _AuthService_RedeemTokenExchange_Handler,
 		},
+		{
+			MethodName: "RegisterPermissionCatalog",
+			Handler:    _AuthService_RegisterPermissionCatalog_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRevocations",
+			Handler:       _AuthService_WatchRevocations_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/auth.proto",
 }
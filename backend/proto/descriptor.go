@@ -0,0 +1,40 @@
+package proto
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorSet returns the self-contained descriptor set of the gRPC API: proto/auth.proto
+// and every file it imports, dependencies first, as protoc --include_imports writes it.
+// Clients in other languages load it to call the API without gRPC reflection or a copy of
+// the .proto sources.
+func DescriptorSet() *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		if seen[file.Path()] {
+			return
+		}
+		seen[file.Path()] = true
+
+		imports := file.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+	}
+	add(File_proto_auth_proto)
+
+	return set
+}
+
+// MarshalDescriptorSet returns the binary encoding of DescriptorSet (.binpb). The output is
+// deterministic so that builds of the same API produce identical files.
+func MarshalDescriptorSet() ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(DescriptorSet())
+}
@@ -0,0 +1,39 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMarshalDescriptorSet(t *testing.T) {
+	data, err := MarshalDescriptorSet()
+	require.NoError(t, err)
+
+	again, err := MarshalDescriptorSet()
+	require.NoError(t, err)
+	assert.Equal(t, data, again, "output must be deterministic")
+
+	set := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, proto.Unmarshal(data, set))
+
+	// The set is self-contained: it resolves without the generated Go code
+	files, err := protodesc.NewFiles(set)
+	require.NoError(t, err)
+
+	desc, err := files.FindDescriptorByName("auth.AuthService")
+	require.NoError(t, err)
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	require.True(t, ok)
+
+	assert.Equal(t, "proto/auth.proto", service.ParentFile().Path())
+	assert.Equal(t, len(AuthService_ServiceDesc.Methods)+len(AuthService_ServiceDesc.Streams), service.Methods().Len())
+	for _, method := range AuthService_ServiceDesc.Methods {
+		assert.NotNil(t, service.Methods().ByName(protoreflect.Name(method.MethodName)), method.MethodName)
+	}
+}
@@ -17,6 +17,9 @@ const AVAILABLE_SCOPES = [
   'email:send',
   'oauth:read',
   'exchange:manage',
+  'rbac:register',
+  'sessions:read',
+  'sessions:revoke',
   'admin:all',
   'all',
 ];
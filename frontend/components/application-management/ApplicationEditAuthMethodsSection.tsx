@@ -1,7 +1,7 @@
 import React from 'react';
 import {
   ToggleLeft, ToggleRight, ShieldCheck, KeyRound, Mail, Smartphone,
-  Chrome, Github, Globe, Send, Key,
+  Chrome, Github, Globe, Send, Key, UserRound, Link, Apple, Building2, Linkedin, Fingerprint,
 } from 'lucide-react';
 import { useLanguage } from '../../services/i18n';
 
@@ -13,8 +13,14 @@ const AUTH_METHODS = [
   { value: 'oauth_github', label: 'apps.auth_methods.oauth_github', icon: Github },
   { value: 'oauth_yandex', label: 'apps.auth_methods.oauth_yandex', icon: Globe },
   { value: 'oauth_telegram', label: 'apps.auth_methods.oauth_telegram', icon: Send },
+  { value: 'oauth_apple', label: 'apps.auth_methods.oauth_apple', icon: Apple },
+  { value: 'oauth_microsoft', label: 'apps.auth_methods.oauth_microsoft', icon: Building2 },
+  { value: 'oauth_linkedin', label: 'apps.auth_methods.oauth_linkedin', icon: Linkedin },
+  { value: 'oauth_oidc-generic', label: 'apps.auth_methods.oauth_oidc-generic', icon: Fingerprint },
   { value: 'totp', label: 'apps.auth_methods.totp', icon: ShieldCheck },
   { value: 'api_key', label: 'apps.auth_methods.api_key', icon: Key },
+  { value: 'guest', label: 'apps.auth_methods.guest', icon: UserRound },
+  { value: 'magic_link', label: 'apps.auth_methods.magic_link', icon: Link },
 ] as const;
 
 interface ApplicationEditAuthMethodsSectionProps {
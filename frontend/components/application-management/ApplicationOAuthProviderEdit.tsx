@@ -1,5 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
+import type { OIDCClaimMapping } from '@auth-gateway/client-sdk';
 import { Save, HelpCircle, Loader2 } from 'lucide-react';
 import { useLanguage } from '../../services/i18n';
 import { LoadingSpinner, PageHeader } from '../ui';
@@ -14,6 +15,7 @@ import { logger } from '@/lib/logger';
 import OAuthProviderSelectionSection from './OAuthProviderSelectionSection';
 import OAuthProviderCredentialsSection from './OAuthProviderCredentialsSection';
 import OAuthProviderAdvancedSection from './OAuthProviderAdvancedSection';
+import OAuthProviderClaimMappingSection from './OAuthProviderClaimMappingSection';
 
 const ApplicationOAuthProviderEdit: React.FC = () => {
   const { applicationId, providerId } = useParams<{ applicationId: string; providerId: string }>();
@@ -31,6 +33,11 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
     auth_url: '',
     token_url: '',
     user_info_url: '',
+    team_id: '',
+    key_id: '',
+    tenant_id: '',
+    issuer: '',
+    claim_mapping: {} as OIDCClaimMapping,
     is_active: true
   });
 
@@ -53,6 +60,11 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
         auth_url: existingProvider.auth_url || '',
         token_url: existingProvider.token_url || '',
         user_info_url: existingProvider.user_info_url || '',
+        team_id: existingProvider.team_id || '',
+        key_id: existingProvider.key_id || '',
+        tenant_id: existingProvider.tenant_id || '',
+        issuer: existingProvider.issuer || '',
+        claim_mapping: existingProvider.claim_mapping || {},
         is_active: existingProvider.is_active ?? true
       });
     }
@@ -63,6 +75,9 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
     if (type === 'checkbox') {
       const checked = (e.target as HTMLInputElement).checked;
       setFormData(prev => ({ ...prev, [name]: checked }));
+    } else if (name.startsWith('claim_mapping.')) {
+      const claim = name.slice('claim_mapping.'.length);
+      setFormData(prev => ({ ...prev, claim_mapping: { ...prev.claim_mapping, [claim]: value } }));
     } else {
       setFormData(prev => ({ ...prev, [name]: value }));
     }
@@ -76,6 +91,7 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
       return;
     }
 
+    const isOIDC = formData.provider === 'oidc-generic';
     const scopesArray = formData.scopes
       .split(',')
       .map(s => s.trim())
@@ -94,6 +110,11 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
             auth_url: formData.auth_url || undefined,
             token_url: formData.token_url || undefined,
             user_info_url: formData.user_info_url || undefined,
+            team_id: formData.team_id || undefined,
+            key_id: formData.key_id || undefined,
+            tenant_id: formData.tenant_id || undefined,
+            issuer: formData.issuer || undefined,
+            claim_mapping: isOIDC ? formData.claim_mapping : undefined,
             is_active: formData.is_active
           }
         });
@@ -109,6 +130,11 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
             auth_url: formData.auth_url || undefined,
             token_url: formData.token_url || undefined,
             user_info_url: formData.user_info_url || undefined,
+            team_id: formData.team_id || undefined,
+            key_id: formData.key_id || undefined,
+            tenant_id: formData.tenant_id || undefined,
+            issuer: formData.issuer || undefined,
+            claim_mapping: isOIDC ? formData.claim_mapping : undefined,
             is_active: formData.is_active
           }
         });
@@ -167,14 +193,26 @@ const ApplicationOAuthProviderEdit: React.FC = () => {
           />
 
           <OAuthProviderCredentialsSection
+            provider={formData.provider}
             clientId={formData.client_id}
             clientSecret={formData.client_secret}
             callbackUrl={formData.callback_url}
             scopes={formData.scopes}
+            teamId={formData.team_id}
+            keyId={formData.key_id}
+            tenantId={formData.tenant_id}
             isEditMode={!!isEditMode}
             onChange={handleChange}
           />
 
+          {formData.provider === 'oidc-generic' && (
+            <OAuthProviderClaimMappingSection
+              issuer={formData.issuer}
+              claimMapping={formData.claim_mapping}
+              onChange={handleChange}
+            />
+          )}
+
           <OAuthProviderAdvancedSection
             authUrl={formData.auth_url}
             tokenUrl={formData.token_url}
@@ -0,0 +1,71 @@
+import React from 'react';
+import type { OIDCClaimMapping } from '@auth-gateway/client-sdk';
+import { useLanguage } from '../../services/i18n';
+
+interface OAuthProviderClaimMappingSectionProps {
+  issuer: string;
+  claimMapping: OIDCClaimMapping;
+  onChange: (e: React.ChangeEvent<HTMLInputElement>) => void;
+}
+
+// Placeholders are the standard claims used when a field is left empty
+const CLAIMS: { key: keyof OIDCClaimMapping; placeholder: string }[] = [
+  { key: 'subject', placeholder: 'sub' },
+  { key: 'email', placeholder: 'email' },
+  { key: 'email_verified', placeholder: 'email_verified' },
+  { key: 'name', placeholder: 'name' },
+  { key: 'username', placeholder: 'preferred_username' },
+  { key: 'picture', placeholder: 'picture' },
+];
+
+const OAuthProviderClaimMappingSection: React.FC<OAuthProviderClaimMappingSectionProps> = ({
+  issuer,
+  claimMapping,
+  onChange,
+}) => {
+  const { t } = useLanguage();
+
+  return (
+    <div className="space-y-4">
+      <div>
+        <label htmlFor="issuer" className="block text-sm font-medium text-muted-foreground mb-1">
+          {t('app_oauth.issuer')} <span className="text-xs font-normal">{t('app_oauth.issuer_hint')}</span>
+        </label>
+        <input
+          type="url"
+          id="issuer"
+          name="issuer"
+          value={issuer}
+          onChange={onChange}
+          className="w-full px-4 py-2 border border-input rounded-lg focus:ring-2 focus:ring-ring focus:border-transparent outline-none font-mono text-sm"
+          placeholder="https://sso.example.com/realms/corp"
+        />
+      </div>
+
+      <div>
+        <h3 className="text-sm font-semibold text-foreground">{t('app_oauth.claim_mapping')}</h3>
+        <p className="text-xs text-muted-foreground mb-3">{t('app_oauth.claim_mapping_desc')}</p>
+        <div className="grid grid-cols-1 sm:grid-cols-2 gap-4">
+          {CLAIMS.map(({ key, placeholder }) => (
+            <div key={key}>
+              <label htmlFor={`claim_mapping.${key}`} className="block text-sm font-medium text-muted-foreground mb-1">
+                {t(`app_oauth.claim.${key}`)}
+              </label>
+              <input
+                type="text"
+                id={`claim_mapping.${key}`}
+                name={`claim_mapping.${key}`}
+                value={claimMapping[key] || ''}
+                onChange={onChange}
+                className="w-full px-4 py-2 border border-input rounded-lg focus:ring-2 focus:ring-ring focus:border-transparent outline-none font-mono text-sm"
+                placeholder={placeholder}
+              />
+            </div>
+          ))}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default OAuthProviderClaimMappingSection;
@@ -7,7 +7,11 @@ interface OAuthProviderSelectionSectionProps {
   onChange: (e: React.ChangeEvent<HTMLInputElement>) => void;
 }
 
-const PROVIDERS = ['google', 'github', 'yandex', 'telegram', 'instagram'];
+const PROVIDERS = ['google', 'github', 'yandex', 'telegram', 'instagram', 'apple', 'microsoft', 'linkedin', 'oidc-generic'];
+
+const PROVIDER_LABELS: Record<string, string> = {
+  'oidc-generic': 'OpenID Connect',
+};
 
 const OAuthProviderSelectionSection: React.FC<OAuthProviderSelectionSectionProps> = ({
   selectedProvider,
@@ -19,7 +23,7 @@ const OAuthProviderSelectionSection: React.FC<OAuthProviderSelectionSectionProps
   return (
     <div>
       <label className="block text-sm font-medium text-muted-foreground mb-2">{t('app_oauth.provider')}</label>
-      <div className="grid grid-cols-2 sm:grid-cols-3 md:grid-cols-5 gap-3">
+      <div className="grid grid-cols-2 sm:grid-cols-3 md:grid-cols-4 gap-3">
         {PROVIDERS.map(p => (
           <label key={p} className={`
             cursor-pointer border rounded-lg p-3 text-center transition-all hover:bg-accent
@@ -34,7 +38,7 @@ const OAuthProviderSelectionSection: React.FC<OAuthProviderSelectionSectionProps
               className="sr-only"
               disabled={isEditMode}
             />
-            <span className="capitalize font-medium block text-foreground">{p}</span>
+            <span className="capitalize font-medium block text-foreground">{PROVIDER_LABELS[p] || p}</span>
           </label>
         ))}
       </div>
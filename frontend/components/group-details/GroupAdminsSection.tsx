@@ -0,0 +1,109 @@
+import React, { useState } from 'react';
+import { Plus, Trash2, Loader } from 'lucide-react';
+import type { AdminUserResponse } from '@auth-gateway/client-sdk';
+import { useLanguage } from '../../services/i18n';
+import { confirm } from '../../services/confirm';
+import { toast } from '../../services/toast';
+import { logger } from '@/lib/logger';
+import { useGroupAdmins, useAddGroupAdmin, useRemoveGroupAdmin } from '../../hooks/useGroups';
+
+interface GroupAdminsSectionProps {
+  groupId: string;
+  candidates: AdminUserResponse[];
+}
+
+export const GroupAdminsSection: React.FC<GroupAdminsSectionProps> = ({ groupId, candidates }) => {
+  const { t } = useLanguage();
+  const { data, isLoading } = useGroupAdmins(groupId);
+  const addAdmin = useAddGroupAdmin();
+  const removeAdmin = useRemoveGroupAdmin();
+  const [selectedUserId, setSelectedUserId] = useState('');
+
+  const admins = data?.admins || [];
+  const available = candidates.filter((u) => !admins.some((a) => a.user_id === u.id));
+
+  const handleAdd = async () => {
+    if (!selectedUserId) return;
+    try {
+      await addAdmin.mutateAsync({ id: groupId, data: { user_id: selectedUserId } });
+      setSelectedUserId('');
+    } catch (error) {
+      logger.error('Failed to add group admin:', error);
+      toast.error(t('group_details.admins_error'));
+    }
+  };
+
+  const handleRemove = async (userId: string) => {
+    const ok = await confirm({
+      description: t('group_details.remove_admin_confirm'),
+      variant: 'danger'
+    });
+    if (!ok) return;
+    try {
+      await removeAdmin.mutateAsync({ groupId, userId });
+    } catch (error) {
+      logger.error('Failed to remove group admin:', error);
+      toast.error(t('group_details.admins_error'));
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl shadow-sm border border-border overflow-hidden">
+      <div className="p-4 border-b border-border">
+        <h2 className="text-lg font-semibold text-foreground">{t('group_details.admins')}</h2>
+        <p className="text-sm text-muted-foreground">{t('group_details.admins_hint')}</p>
+      </div>
+
+      <div className="p-4 border-b border-border flex gap-2">
+        <select
+          value={selectedUserId}
+          onChange={(e) => setSelectedUserId(e.target.value)}
+          className="flex-1 px-3 py-1.5 border border-input rounded-lg text-sm bg-background text-foreground"
+        >
+          <option value="">{t('group_details.select_admin')}</option>
+          {available.map((user) => (
+            <option key={user.id} value={user.id}>
+              {user.full_name || user.username} ({user.email})
+            </option>
+          ))}
+        </select>
+        <button
+          onClick={handleAdd}
+          disabled={!selectedUserId || addAdmin.isPending}
+          className="px-3 py-1.5 bg-primary hover:bg-primary-600 text-primary-foreground rounded-lg text-sm transition-colors flex items-center gap-2 disabled:opacity-50 disabled:cursor-not-allowed"
+        >
+          {addAdmin.isPending ? <Loader size={16} className="animate-spin" /> : <Plus size={16} />}
+          {t('group_details.add_admin')}
+        </button>
+      </div>
+
+      {isLoading ? (
+        <div className="p-8 text-center">
+          <Loader size={24} className="animate-spin text-primary mx-auto" />
+        </div>
+      ) : admins.length === 0 ? (
+        <p className="p-4 text-sm text-muted-foreground">{t('group_details.no_admins')}</p>
+      ) : (
+        <ul className="divide-y divide-border">
+          {admins.map((admin) => (
+            <li key={admin.user_id} className="p-4 flex items-center justify-between">
+              <div>
+                <div className="text-sm font-medium text-foreground">
+                  {admin.user?.full_name || admin.user?.username || admin.user_id}
+                </div>
+                {admin.user?.email && <div className="text-xs text-muted-foreground">{admin.user.email}</div>}
+              </div>
+              <button
+                onClick={() => handleRemove(admin.user_id)}
+                disabled={removeAdmin.isPending}
+                className="text-muted-foreground hover:text-destructive disabled:opacity-50"
+              >
+                <Trash2 size={16} />
+              </button>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
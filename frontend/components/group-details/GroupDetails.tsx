@@ -10,6 +10,9 @@ import { confirm } from '../../services/confirm';
 import { useLanguage } from '../../services/i18n';
 import { logger } from '@/lib/logger';
 import { GroupMembersSection } from './GroupMembersSection';
+import { GroupRolesSection } from './GroupRolesSection';
+import { GroupAdminsSection } from './GroupAdminsSection';
+import { GroupUsageReportSection } from './GroupUsageReportSection';
 import type { AdminUserResponse } from '@auth-gateway/client-sdk';
 
 const GroupDetails: React.FC = () => {
@@ -124,6 +127,12 @@ const GroupDetails: React.FC = () => {
         </div>
       </div>
 
+      <GroupRolesSection groupId={id || ''} />
+
+      <GroupAdminsSection groupId={id || ''} candidates={members} />
+
+      <GroupUsageReportSection groupId={id || ''} />
+
       <GroupMembersSection
         groupId={id || ''}
         members={members}
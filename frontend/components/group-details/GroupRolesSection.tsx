@@ -0,0 +1,127 @@
+import React, { useState } from 'react';
+import { Edit, X, Loader } from 'lucide-react';
+import type { Role } from '@auth-gateway/client-sdk';
+import { useLanguage } from '../../services/i18n';
+import { toast } from '../../services/toast';
+import { logger } from '@/lib/logger';
+import { useGroupRoles, useSetGroupRoles } from '../../hooks/useGroups';
+import { useRoles } from '../../hooks/rbac/useRoles';
+
+interface GroupRolesSectionProps {
+  groupId: string;
+}
+
+export const GroupRolesSection: React.FC<GroupRolesSectionProps> = ({ groupId }) => {
+  const { t } = useLanguage();
+  const { data: groupRoles, isLoading } = useGroupRoles(groupId);
+  const { data: allRoles } = useRoles();
+  const setGroupRoles = useSetGroupRoles();
+  const [isEditing, setIsEditing] = useState(false);
+  const [selectedRoleIds, setSelectedRoleIds] = useState<string[]>([]);
+
+  const roles = groupRoles?.roles || [];
+  const inheritedRoles = groupRoles?.inherited_roles || [];
+
+  const startEditing = () => {
+    setSelectedRoleIds(roles.map((role) => role.id));
+    setIsEditing(true);
+  };
+
+  const handleSave = async () => {
+    try {
+      await setGroupRoles.mutateAsync({ id: groupId, data: { role_ids: selectedRoleIds } });
+      setIsEditing(false);
+    } catch (error) {
+      logger.error('Failed to update group roles:', error);
+      toast.error(t('group_details.roles_error'));
+    }
+  };
+
+  const renderRole = (role: Role, inherited: boolean) => (
+    <span
+      key={role.id}
+      className={`px-2.5 py-1 rounded-full text-xs font-medium ${
+        inherited ? 'bg-muted text-muted-foreground' : 'bg-primary/10 text-primary'
+      }`}
+    >
+      {role.display_name || role.name}
+    </span>
+  );
+
+  return (
+    <div className="bg-card rounded-xl shadow-sm border border-border overflow-hidden">
+      <div className="p-4 border-b border-border flex items-center justify-between">
+        <div>
+          <h2 className="text-lg font-semibold text-foreground">{t('group_details.roles')}</h2>
+          <p className="text-sm text-muted-foreground">{t('group_details.roles_hint')}</p>
+        </div>
+        {!isEditing && (
+          <button
+            onClick={startEditing}
+            className="px-3 py-1.5 border border-input rounded-lg text-sm text-foreground hover:bg-accent transition-colors flex items-center gap-2"
+          >
+            <Edit size={16} />
+            {t('group_details.edit_roles')}
+          </button>
+        )}
+      </div>
+
+      {isEditing && (
+        <div className="p-4 border-b border-border bg-muted">
+          <div className="flex items-center justify-between mb-3">
+            <h3 className="font-medium text-foreground">{t('group_details.select_roles')}</h3>
+            <button onClick={() => setIsEditing(false)} className="text-muted-foreground hover:text-foreground">
+              <X size={20} />
+            </button>
+          </div>
+          <div className="max-h-48 overflow-y-auto space-y-2 mb-3">
+            {(allRoles || []).map((role) => (
+              <label key={role.id} className="flex items-center gap-2 p-2 hover:bg-card rounded cursor-pointer">
+                <input
+                  type="checkbox"
+                  checked={selectedRoleIds.includes(role.id)}
+                  onChange={(e) => {
+                    if (e.target.checked) {
+                      setSelectedRoleIds([...selectedRoleIds, role.id]);
+                    } else {
+                      setSelectedRoleIds(selectedRoleIds.filter((id) => id !== role.id));
+                    }
+                  }}
+                  className="rounded border-input text-primary focus:ring-ring"
+                />
+                <span className="text-sm text-foreground">{role.display_name || role.name}</span>
+              </label>
+            ))}
+          </div>
+          <button
+            onClick={handleSave}
+            disabled={setGroupRoles.isPending}
+            className="w-full px-4 py-2 bg-primary hover:bg-primary-600 text-primary-foreground rounded-lg text-sm transition-colors disabled:opacity-50 disabled:cursor-not-allowed flex items-center justify-center gap-2"
+          >
+            {setGroupRoles.isPending && <Loader size={16} className="animate-spin" />}
+            {t('common.save')}
+          </button>
+        </div>
+      )}
+
+      {isLoading ? (
+        <div className="p-8 text-center">
+          <Loader size={24} className="animate-spin text-primary mx-auto" />
+        </div>
+      ) : (
+        <div className="p-4 space-y-4">
+          <div className="flex flex-wrap gap-2">
+            {roles.map((role) => renderRole(role, false))}
+            {roles.length === 0 && <p className="text-sm text-muted-foreground">{t('group_details.no_roles')}</p>}
+          </div>
+          {inheritedRoles.length > 0 && (
+            <div>
+              <div className="text-sm text-muted-foreground mb-2">{t('group_details.inherited_roles')}</div>
+              <div className="flex flex-wrap gap-2">{inheritedRoles.map((role) => renderRole(role, true))}</div>
+            </div>
+          )}
+        </div>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,167 @@
+import React, { useEffect, useState } from 'react';
+import { Send, Save, Loader, AlertTriangle } from 'lucide-react';
+import { useLanguage } from '../../services/i18n';
+import { toast } from '../../services/toast';
+import { logger } from '@/lib/logger';
+import { formatDate } from '../../lib/date';
+import {
+  useGroupUsageReport,
+  useGroupUsageReportSettings,
+  useUpdateGroupUsageReportSettings,
+  useSendGroupUsageReport,
+} from '../../hooks/useGroups';
+
+interface GroupUsageReportSectionProps {
+  groupId: string;
+}
+
+export const GroupUsageReportSection: React.FC<GroupUsageReportSectionProps> = ({ groupId }) => {
+  const { t } = useLanguage();
+  const { data: report, isLoading } = useGroupUsageReport(groupId);
+  const { data: settings } = useGroupUsageReportSettings(groupId);
+  const updateSettings = useUpdateGroupUsageReportSettings();
+  const sendReport = useSendGroupUsageReport();
+
+  const [licensedSeats, setLicensedSeats] = useState('');
+  const [recipients, setRecipients] = useState('');
+  const [frequency, setFrequency] = useState<'weekly' | 'monthly'>('monthly');
+
+  useEffect(() => {
+    if (!settings) return;
+    setLicensedSeats(settings.licensed_seats != null ? String(settings.licensed_seats) : '');
+    setRecipients(settings.recipients.join(', '));
+    setFrequency(settings.frequency);
+  }, [settings]);
+
+  const handleSave = async () => {
+    try {
+      await updateSettings.mutateAsync({
+        id: groupId,
+        data: {
+          licensed_seats: licensedSeats === '' ? null : Number(licensedSeats),
+          recipients: recipients.split(',').map((r) => r.trim()).filter(Boolean),
+          frequency,
+        },
+      });
+      toast.success(t('common.saved'));
+    } catch (error) {
+      logger.error('Failed to update usage report settings:', error);
+      toast.error(t('group_details.usage_error'));
+    }
+  };
+
+  const handleSend = async () => {
+    try {
+      const result = await sendReport.mutateAsync(groupId);
+      toast.success(t('group_details.usage_sent').replace('{count}', String(result.sent)));
+    } catch (error) {
+      logger.error('Failed to send usage report:', error);
+      toast.error(t('group_details.usage_error'));
+    }
+  };
+
+  return (
+    <div className="bg-card rounded-xl shadow-sm border border-border overflow-hidden">
+      <div className="p-4 border-b border-border">
+        <h2 className="text-lg font-semibold text-foreground">{t('group_details.usage_report')}</h2>
+        <p className="text-sm text-muted-foreground">{t('group_details.usage_report_hint')}</p>
+      </div>
+
+      {isLoading || !report ? (
+        <div className="p-8 text-center">
+          <Loader size={24} className="animate-spin text-primary mx-auto" />
+        </div>
+      ) : (
+        <div className="p-4 border-b border-border">
+          <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+            <div>
+              <div className="text-sm text-muted-foreground">{t('group_details.usage_members')}</div>
+              <div className="text-lg font-semibold text-foreground">{report.total_members}</div>
+            </div>
+            <div>
+              <div className="text-sm text-muted-foreground">
+                {t('group_details.usage_active_users').replace('{days}', String(report.active_days))}
+              </div>
+              <div className="text-lg font-semibold text-foreground">{report.active_users}</div>
+            </div>
+            <div>
+              <div className="text-sm text-muted-foreground">{t('group_details.usage_seats')}</div>
+              <div className="text-lg font-semibold text-foreground">
+                {report.seats_used} / {report.licensed_seats ?? t('group_details.usage_unlimited')}
+              </div>
+            </div>
+            <div>
+              <div className="text-sm text-muted-foreground">{t('group_details.usage_mfa')}</div>
+              <div className="text-lg font-semibold text-foreground">{report.mfa_adoption_rate}%</div>
+            </div>
+          </div>
+          {report.over_license && (
+            <p className="mt-3 text-sm text-destructive flex items-center gap-2">
+              <AlertTriangle size={16} />
+              {t('group_details.usage_over_license')}
+            </p>
+          )}
+        </div>
+      )}
+
+      <div className="p-4 grid grid-cols-1 md:grid-cols-3 gap-4">
+        <label className="text-sm text-muted-foreground">
+          {t('group_details.usage_licensed_seats')}
+          <input
+            type="number"
+            min={0}
+            value={licensedSeats}
+            placeholder={t('group_details.usage_unlimited')}
+            onChange={(e) => setLicensedSeats(e.target.value)}
+            className="mt-1 w-full px-3 py-1.5 border border-input rounded-lg text-sm bg-background text-foreground"
+          />
+        </label>
+        <label className="text-sm text-muted-foreground">
+          {t('group_details.usage_recipients')}
+          <input
+            type="text"
+            value={recipients}
+            placeholder="billing@example.com"
+            onChange={(e) => setRecipients(e.target.value)}
+            className="mt-1 w-full px-3 py-1.5 border border-input rounded-lg text-sm bg-background text-foreground"
+          />
+        </label>
+        <label className="text-sm text-muted-foreground">
+          {t('group_details.usage_frequency')}
+          <select
+            value={frequency}
+            onChange={(e) => setFrequency(e.target.value as 'weekly' | 'monthly')}
+            className="mt-1 w-full px-3 py-1.5 border border-input rounded-lg text-sm bg-background text-foreground"
+          >
+            <option value="weekly">{t('group_details.usage_weekly')}</option>
+            <option value="monthly">{t('group_details.usage_monthly')}</option>
+          </select>
+        </label>
+      </div>
+
+      <div className="p-4 pt-0 flex items-center justify-between gap-2">
+        <span className="text-xs text-muted-foreground">
+          {settings?.last_sent_at && `${t('group_details.usage_last_sent')}: ${formatDate(settings.last_sent_at)}`}
+        </span>
+        <div className="flex gap-2">
+          <button
+            onClick={handleSend}
+            disabled={sendReport.isPending || !settings?.recipients.length}
+            className="px-3 py-1.5 border border-input rounded-lg text-sm text-foreground hover:bg-accent transition-colors flex items-center gap-2 disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            {sendReport.isPending ? <Loader size={16} className="animate-spin" /> : <Send size={16} />}
+            {t('group_details.usage_send_now')}
+          </button>
+          <button
+            onClick={handleSave}
+            disabled={updateSettings.isPending}
+            className="px-3 py-1.5 bg-primary hover:bg-primary-600 text-primary-foreground rounded-lg text-sm transition-colors flex items-center gap-2 disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            {updateSettings.isPending ? <Loader size={16} className="animate-spin" /> : <Save size={16} />}
+            {t('common.save')}
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
@@ -26,93 +26,84 @@ export const SettingsSecurityTab: React.FC<SettingsSecurityTabProps> = ({
       </div>
       {localPasswordPolicy && (
         <div className="p-6 space-y-6">
-          <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
-            <div>
-              <label className="block text-sm font-medium text-foreground mb-2">{t('settings.jwt_ttl')}</label>
-              <input
-                type="number"
-                name="jwtTtlMinutes"
-                value={localPasswordPolicy.jwtTtlMinutes}
-                onChange={onPolicyChange}
-                className="w-full border-input border rounded-lg p-2.5 focus:ring-ring focus:border-ring"
-              />
-            </div>
-            <div>
-              <label className="block text-sm font-medium text-foreground mb-2">{t('settings.refresh_ttl')}</label>
-              <input
-                type="number"
-                name="refreshTtlDays"
-                value={localPasswordPolicy.refreshTtlDays}
-                onChange={onPolicyChange}
-                className="w-full border-input border rounded-lg p-2.5 focus:ring-ring focus:border-ring"
-              />
-            </div>
-          </div>
-
-          <div className="border-t border-border pt-6">
+          <div>
             <h3 className="text-md font-medium text-foreground mb-4">{t('settings.password_policy')}</h3>
             <div className="grid grid-cols-1 md:grid-cols-3 gap-6 mb-6">
               <div>
                 <label className="block text-sm font-medium text-foreground mb-2">{t('settings.min_pass')}</label>
                 <input
                   type="number"
-                  name="minLength"
-                  value={localPasswordPolicy.minLength}
+                  name="min_length"
+                  value={localPasswordPolicy.min_length}
                   onChange={onPolicyChange}
                   className="w-full border-input border rounded-lg p-2.5 focus:ring-ring focus:border-ring"
                 />
               </div>
               <div>
-                <label className="block text-sm font-medium text-foreground mb-2">{t('settings.pass_history')}</label>
+                <label className="block text-sm font-medium text-foreground mb-2">{t('settings.max_pass')}</label>
                 <input
                   type="number"
-                  name="historyCount"
-                  value={localPasswordPolicy.historyCount}
+                  name="max_length"
+                  value={localPasswordPolicy.max_length}
                   onChange={onPolicyChange}
                   className="w-full border-input border rounded-lg p-2.5 focus:ring-ring focus:border-ring"
                 />
               </div>
-               <div>
-                <label className="block text-sm font-medium text-foreground mb-2">{t('settings.pass_expiry')}</label>
+              <div>
+                <label className="block text-sm font-medium text-foreground mb-2">{t('settings.pass_history')}</label>
                 <input
                   type="number"
-                  name="expiryDays"
-                  value={localPasswordPolicy.expiryDays}
+                  name="history_count"
+                  value={localPasswordPolicy.history_count}
                   onChange={onPolicyChange}
                   className="w-full border-input border rounded-lg p-2.5 focus:ring-ring focus:border-ring"
                 />
               </div>
             </div>
 
-            <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+            <div className="grid grid-cols-2 md:grid-cols-3 gap-4">
               <label className="flex items-center gap-3 cursor-pointer"
-                onClick={() => onTogglePolicy('requireUppercase')}>
-                <span className={`transition-colors ${localPasswordPolicy.requireUppercase ? 'text-success' : 'text-muted-foreground'}`}>
-                  {localPasswordPolicy.requireUppercase ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                onClick={() => onTogglePolicy('require_uppercase')}>
+                <span className={`transition-colors ${localPasswordPolicy.require_uppercase ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.require_uppercase ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
                 </span>
                 <span className="text-sm text-foreground">{t('settings.req_uppercase')}</span>
               </label>
               <label className="flex items-center gap-3 cursor-pointer"
-                onClick={() => onTogglePolicy('requireLowercase')}>
-                <span className={`transition-colors ${localPasswordPolicy.requireLowercase ? 'text-success' : 'text-muted-foreground'}`}>
-                  {localPasswordPolicy.requireLowercase ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                onClick={() => onTogglePolicy('require_lowercase')}>
+                <span className={`transition-colors ${localPasswordPolicy.require_lowercase ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.require_lowercase ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
                 </span>
                 <span className="text-sm text-foreground">{t('settings.req_lowercase')}</span>
               </label>
               <label className="flex items-center gap-3 cursor-pointer"
-                onClick={() => onTogglePolicy('requireNumbers')}>
-                <span className={`transition-colors ${localPasswordPolicy.requireNumbers ? 'text-success' : 'text-muted-foreground'}`}>
-                  {localPasswordPolicy.requireNumbers ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                onClick={() => onTogglePolicy('require_numbers')}>
+                <span className={`transition-colors ${localPasswordPolicy.require_numbers ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.require_numbers ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
                 </span>
                 <span className="text-sm text-foreground">{t('settings.req_numbers')}</span>
               </label>
               <label className="flex items-center gap-3 cursor-pointer"
-                onClick={() => onTogglePolicy('requireSpecial')}>
-                <span className={`transition-colors ${localPasswordPolicy.requireSpecial ? 'text-success' : 'text-muted-foreground'}`}>
-                  {localPasswordPolicy.requireSpecial ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                onClick={() => onTogglePolicy('require_special')}>
+                <span className={`transition-colors ${localPasswordPolicy.require_special ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.require_special ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
                 </span>
                 <span className="text-sm text-foreground">{t('settings.req_special')}</span>
               </label>
+              <label className="flex items-center gap-3 cursor-pointer"
+                onClick={() => onTogglePolicy('check_dictionary')}>
+                <span className={`transition-colors ${localPasswordPolicy.check_dictionary ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.check_dictionary ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                </span>
+                <span className="text-sm text-foreground">{t('settings.check_dictionary')}</span>
+              </label>
+              <label className="flex items-center gap-3 cursor-pointer"
+                onClick={() => onTogglePolicy('check_compromised')}>
+                <span className={`transition-colors ${localPasswordPolicy.check_compromised ? 'text-success' : 'text-muted-foreground'}`}>
+                  {localPasswordPolicy.check_compromised ? <ToggleRight size={28} /> : <ToggleLeft size={28} />}
+                </span>
+                <span className="text-sm text-foreground">{t('settings.check_compromised')}</span>
+              </label>
             </div>
           </div>
         </div>
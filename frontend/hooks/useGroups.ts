@@ -1,7 +1,7 @@
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { apiClient } from '../services/apiClient';
 import { queryKeys } from '../services/queryClient';
-import type { CreateGroupRequest, UpdateGroupRequest, AddGroupMembersRequest } from '@auth-gateway/client-sdk';
+import type { CreateGroupRequest, UpdateGroupRequest, AddGroupMembersRequest, SetGroupRolesRequest, AddGroupAdminRequest, UpdateOrgUsageReportSettingsRequest } from '@auth-gateway/client-sdk';
 import { useCurrentAppId } from './useAppAwareQuery';
 
 export function useGroups(page: number = 1, pageSize: number = 20) {
@@ -90,3 +90,96 @@ export function useRemoveGroupMember() {
   });
 }
 
+
+export function useGroupRoles(groupId: string) {
+  return useQuery({
+    queryKey: queryKeys.groups.roles(groupId),
+    queryFn: () => apiClient.admin.groups.getRoles(groupId),
+    enabled: !!groupId,
+  });
+}
+
+export function useSetGroupRoles() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: string; data: SetGroupRolesRequest }) =>
+      apiClient.admin.groups.setRoles(id, data),
+    onSuccess: () => {
+      // Child groups inherit the roles, so their cached role lists are stale too
+      queryClient.invalidateQueries({ queryKey: ['groups', 'roles'] });
+    },
+  });
+}
+
+export function useGroupAdmins(groupId: string) {
+  return useQuery({
+    queryKey: queryKeys.groups.admins(groupId),
+    queryFn: () => apiClient.admin.groups.getAdmins(groupId),
+    enabled: !!groupId,
+  });
+}
+
+export function useAddGroupAdmin() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: string; data: AddGroupAdminRequest }) =>
+      apiClient.admin.groups.addAdmin(id, data),
+    onSuccess: (_, { id }) => {
+      queryClient.invalidateQueries({ queryKey: queryKeys.groups.admins(id) });
+    },
+  });
+}
+
+export function useRemoveGroupAdmin() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ groupId, userId }: { groupId: string; userId: string }) =>
+      apiClient.admin.groups.removeAdmin(groupId, userId),
+    onSuccess: (_, { groupId }) => {
+      queryClient.invalidateQueries({ queryKey: queryKeys.groups.admins(groupId) });
+    },
+  });
+}
+
+export function useGroupUsageReport(groupId: string) {
+  return useQuery({
+    queryKey: queryKeys.groups.usageReport(groupId),
+    queryFn: () => apiClient.admin.groups.getUsageReport(groupId),
+    enabled: !!groupId,
+  });
+}
+
+export function useGroupUsageReportSettings(groupId: string) {
+  return useQuery({
+    queryKey: queryKeys.groups.usageReportSettings(groupId),
+    queryFn: () => apiClient.admin.groups.getUsageReportSettings(groupId),
+    enabled: !!groupId,
+  });
+}
+
+export function useUpdateGroupUsageReportSettings() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({ id, data }: { id: string; data: UpdateOrgUsageReportSettingsRequest }) =>
+      apiClient.admin.groups.updateUsageReportSettings(id, data),
+    onSuccess: (_, { id }) => {
+      queryClient.invalidateQueries({ queryKey: queryKeys.groups.usageReportSettings(id) });
+      queryClient.invalidateQueries({ queryKey: queryKeys.groups.usageReport(id) });
+    },
+  });
+}
+
+export function useSendGroupUsageReport() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: (id: string) => apiClient.admin.groups.sendUsageReport(id),
+    onSuccess: (_, id) => {
+      queryClient.invalidateQueries({ queryKey: queryKeys.groups.usageReportSettings(id) });
+    },
+  });
+}
@@ -96,8 +96,14 @@ const applications: Record<string, string> = {
   'apps.auth_methods.oauth_github': 'GitHub OAuth',
   'apps.auth_methods.oauth_yandex': 'Yandex OAuth',
   'apps.auth_methods.oauth_telegram': 'Telegram',
+  'apps.auth_methods.oauth_apple': 'Sign in with Apple',
+  'apps.auth_methods.oauth_microsoft': 'Microsoft',
+  'apps.auth_methods.oauth_linkedin': 'LinkedIn',
+  'apps.auth_methods.oauth_oidc-generic': 'OpenID Connect (SSO)',
   'apps.auth_methods.totp': '2FA (TOTP)',
   'apps.auth_methods.api_key': 'API Key',
+  'apps.auth_methods.guest': 'Guest session',
+  'apps.auth_methods.magic_link': 'Magic link',
   'apps.auth_methods.error_empty': 'Select at least one method',
   'apps.secret.title': 'Application Secret',
   'apps.secret.prefix': 'Prefix',
@@ -132,6 +138,21 @@ const applications: Record<string, string> = {
   'app_oauth.provider': 'Provider',
   'app_oauth.client_secret': 'Client Secret',
   'app_oauth.secret_unchanged': '(unchanged)',
+  'app_oauth.private_key': 'Private Key (.p8)',
+  'app_oauth.team_id': 'Team ID',
+  'app_oauth.key_id': 'Key ID',
+  'app_oauth.tenant_id': 'Tenant',
+  'app_oauth.tenant_id_hint': '(tenant ID or domain, or common, organizations, consumers)',
+  'app_oauth.issuer': 'Issuer',
+  'app_oauth.issuer_hint': '(endpoints are discovered from it unless set below)',
+  'app_oauth.claim_mapping': 'Claim Mapping',
+  'app_oauth.claim_mapping_desc': 'Claims of the provider that describe the user. Leave empty to use the standard claim shown.',
+  'app_oauth.claim.subject': 'User ID claim',
+  'app_oauth.claim.email': 'Email claim',
+  'app_oauth.claim.email_verified': 'Email verified claim',
+  'app_oauth.claim.name': 'Name claim',
+  'app_oauth.claim.username': 'Username claim',
+  'app_oauth.claim.picture': 'Picture claim',
   'app_oauth.scopes_hint': '(comma-separated)',
   'app_oauth.advanced': 'Advanced Configuration',
   'app_oauth.auth_url': 'Authorization URL',
@@ -41,9 +41,8 @@ const dashboard: Record<string, string> = {
   'settings.password_policy': 'Password Policy',
   'settings.email_smtp': 'Email & SMTP',
   'settings.manage_templates': 'Manage Templates',
-  'settings.jwt_ttl': 'JWT Access Token TTL (minutes)',
-  'settings.refresh_ttl': 'JWT Refresh Token TTL (days)',
   'settings.min_pass': 'Password Minimum Length',
+  'settings.max_pass': 'Password Maximum Length (0 = none)',
   'settings.require_2fa_admin': 'Require 2FA for Admins',
   'settings.smtp_host': 'SMTP Host',
   'settings.smtp_port': 'Port',
@@ -52,8 +51,9 @@ const dashboard: Record<string, string> = {
   'settings.req_lowercase': 'Require Lowercase',
   'settings.req_numbers': 'Require Numbers',
   'settings.req_special': 'Require Special Chars',
+  'settings.check_dictionary': 'Reject Common Passwords',
+  'settings.check_compromised': 'Reject Breached Passwords',
   'settings.pass_history': 'Password History',
-  'settings.pass_expiry': 'Password Expiry (days)',
   'settings.status_label': 'Status',
   'settings.db_label': 'DB',
   'settings.redis_label': 'Redis',
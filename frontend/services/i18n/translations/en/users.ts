@@ -128,6 +128,37 @@ const users: Record<string, string> = {
   'group_details.all_members': 'All users are already in this group.',
   'group_details.add': 'Add',
   'group_details.no_members': 'No members in this group.',
+  'group_details.roles': 'Roles',
+  'group_details.roles_hint': 'Members of this group and its child groups receive these roles.',
+  'group_details.edit_roles': 'Edit Roles',
+  'group_details.select_roles': 'Select Roles',
+  'group_details.no_roles': 'No roles are mapped to this group.',
+  'group_details.inherited_roles': 'Inherited from parent groups',
+  'group_details.roles_error': 'Error updating group roles',
+  'group_details.admins': 'Organization Admins',
+  'group_details.admins_hint': 'Organization admins manage the users, roles and API keys of this group and its child groups.',
+  'group_details.add_admin': 'Add Admin',
+  'group_details.select_admin': 'Select a member',
+  'group_details.no_admins': 'No organization admins are designated for this group.',
+  'group_details.remove_admin_confirm': 'Are you sure you want to revoke this organization admin?',
+  'group_details.admins_error': 'Error updating organization admins',
+  'group_details.usage_report': 'Usage Report',
+  'group_details.usage_report_hint': 'Seat usage and MFA adoption across this group and its child groups.',
+  'group_details.usage_members': 'Members',
+  'group_details.usage_active_users': 'Active (last {days} days)',
+  'group_details.usage_seats': 'Seats used',
+  'group_details.usage_mfa': 'MFA adoption',
+  'group_details.usage_unlimited': 'unlimited',
+  'group_details.usage_over_license': 'Seat usage exceeds the licensed seat count.',
+  'group_details.usage_licensed_seats': 'Licensed seats',
+  'group_details.usage_recipients': 'Report recipients (comma separated)',
+  'group_details.usage_frequency': 'Frequency',
+  'group_details.usage_weekly': 'Weekly',
+  'group_details.usage_monthly': 'Monthly',
+  'group_details.usage_last_sent': 'Last sent',
+  'group_details.usage_send_now': 'Send Now',
+  'group_details.usage_sent': 'Usage report sent to {count} recipient(s)',
+  'group_details.usage_error': 'Error updating usage report settings',
 
   'group_edit.create_title': 'Create Group',
   'group_edit.edit_title': 'Edit Group',
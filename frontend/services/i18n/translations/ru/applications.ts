@@ -96,8 +96,14 @@ const applications: Record<string, string> = {
   'apps.auth_methods.oauth_github': 'GitHub OAuth',
   'apps.auth_methods.oauth_yandex': 'Yandex OAuth',
   'apps.auth_methods.oauth_telegram': 'Telegram',
+  'apps.auth_methods.oauth_apple': 'Вход через Apple',
+  'apps.auth_methods.oauth_microsoft': 'Microsoft',
+  'apps.auth_methods.oauth_linkedin': 'LinkedIn',
+  'apps.auth_methods.oauth_oidc-generic': 'OpenID Connect (SSO)',
   'apps.auth_methods.totp': '2FA (TOTP)',
   'apps.auth_methods.api_key': 'API Key',
+  'apps.auth_methods.guest': 'Гостевая сессия',
+  'apps.auth_methods.magic_link': 'Вход по ссылке',
   'apps.auth_methods.error_empty': 'Выберите хотя бы один метод',
   'apps.secret.title': 'Application Secret',
   'apps.secret.prefix': 'Префикс',
@@ -132,6 +138,21 @@ const applications: Record<string, string> = {
   'app_oauth.provider': 'Провайдер',
   'app_oauth.client_secret': 'Client Secret',
   'app_oauth.secret_unchanged': '(без изменений)',
+  'app_oauth.private_key': 'Закрытый ключ (.p8)',
+  'app_oauth.team_id': 'Team ID',
+  'app_oauth.key_id': 'Key ID',
+  'app_oauth.tenant_id': 'Клиент (tenant)',
+  'app_oauth.tenant_id_hint': '(ID или домен клиента либо common, organizations, consumers)',
+  'app_oauth.issuer': 'Issuer',
+  'app_oauth.issuer_hint': '(адреса берутся из его discovery-документа, если не заданы ниже)',
+  'app_oauth.claim_mapping': 'Сопоставление claims',
+  'app_oauth.claim_mapping_desc': 'Claims провайдера, описывающие пользователя. Оставьте пустым, чтобы использовать стандартный claim из подсказки.',
+  'app_oauth.claim.subject': 'Claim ID пользователя',
+  'app_oauth.claim.email': 'Claim email',
+  'app_oauth.claim.email_verified': 'Claim подтверждения email',
+  'app_oauth.claim.name': 'Claim имени',
+  'app_oauth.claim.username': 'Claim имени пользователя',
+  'app_oauth.claim.picture': 'Claim аватара',
   'app_oauth.scopes_hint': '(через запятую)',
   'app_oauth.advanced': 'Расширенная конфигурация',
   'app_oauth.auth_url': 'URL авторизации',
@@ -41,9 +41,8 @@ const dashboard: Record<string, string> = {
   'settings.password_policy': 'Политика паролей',
   'settings.email_smtp': 'Email и SMTP',
   'settings.manage_templates': 'Шаблоны писем',
-  'settings.jwt_ttl': 'Время жизни JWT (мин)',
-  'settings.refresh_ttl': 'Время жизни Refresh (дней)',
   'settings.min_pass': 'Мин. длина пароля',
+  'settings.max_pass': 'Макс. длина пароля (0 = без ограничения)',
   'settings.require_2fa_admin': 'Обязательная 2FA для админов',
   'settings.smtp_host': 'SMTP Хост',
   'settings.smtp_port': 'Порт',
@@ -52,8 +51,9 @@ const dashboard: Record<string, string> = {
   'settings.req_lowercase': 'Требовать строчные',
   'settings.req_numbers': 'Требовать цифры',
   'settings.req_special': 'Требовать спецсимволы',
+  'settings.check_dictionary': 'Запретить распространённые пароли',
+  'settings.check_compromised': 'Запретить утёкшие пароли',
   'settings.pass_history': 'История паролей',
-  'settings.pass_expiry': 'Срок действия пароля (дней)',
   'settings.status_label': 'Статус',
   'settings.db_label': 'БД',
   'settings.redis_label': 'Redis',
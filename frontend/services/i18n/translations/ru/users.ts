@@ -128,6 +128,37 @@ const users: Record<string, string> = {
   'group_details.all_members': 'Все пользователи уже в группе.',
   'group_details.add': 'Добавить',
   'group_details.no_members': 'Нет участников в группе.',
+  'group_details.roles': 'Роли',
+  'group_details.roles_hint': 'Участники этой группы и её дочерних групп получают эти роли.',
+  'group_details.edit_roles': 'Изменить роли',
+  'group_details.select_roles': 'Выберите роли',
+  'group_details.no_roles': 'С группой не связано ни одной роли.',
+  'group_details.inherited_roles': 'Унаследованы от родительских групп',
+  'group_details.roles_error': 'Ошибка обновления ролей группы',
+  'group_details.admins': 'Администраторы организации',
+  'group_details.admins_hint': 'Администраторы организации управляют пользователями, ролями и API-ключами этой группы и её дочерних групп.',
+  'group_details.add_admin': 'Добавить администратора',
+  'group_details.select_admin': 'Выберите участника',
+  'group_details.no_admins': 'Для этой группы не назначено администраторов организации.',
+  'group_details.remove_admin_confirm': 'Вы уверены, что хотите отозвать права администратора организации?',
+  'group_details.admins_error': 'Ошибка обновления администраторов организации',
+  'group_details.usage_report': 'Отчёт об использовании',
+  'group_details.usage_report_hint': 'Использование лицензий и внедрение MFA в этой группе и её дочерних группах.',
+  'group_details.usage_members': 'Участники',
+  'group_details.usage_active_users': 'Активны (последние {days} дн.)',
+  'group_details.usage_seats': 'Занято лицензий',
+  'group_details.usage_mfa': 'Внедрение MFA',
+  'group_details.usage_unlimited': 'без ограничений',
+  'group_details.usage_over_license': 'Число занятых лицензий превышает лицензированное.',
+  'group_details.usage_licensed_seats': 'Лицензий',
+  'group_details.usage_recipients': 'Получатели отчёта (через запятую)',
+  'group_details.usage_frequency': 'Периодичность',
+  'group_details.usage_weekly': 'Еженедельно',
+  'group_details.usage_monthly': 'Ежемесячно',
+  'group_details.usage_last_sent': 'Последняя отправка',
+  'group_details.usage_send_now': 'Отправить сейчас',
+  'group_details.usage_sent': 'Отчёт отправлен получателям: {count}',
+  'group_details.usage_error': 'Ошибка обновления настроек отчёта',
 
   'group_edit.create_title': 'Создать группу',
   'group_edit.edit_title': 'Редактировать группу',
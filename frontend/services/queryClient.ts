@@ -140,6 +140,10 @@ export const queryKeys = {
     detail: (id: string) => ['groups', 'detail', id] as const,
     members: (id: string, page: number, pageSize: number) =>
       ['groups', 'members', id, { page, pageSize }] as const,
+    roles: (id: string) => ['groups', 'roles', id] as const,
+    admins: (id: string) => ['groups', 'admins', id] as const,
+    usageReport: (id: string) => ['groups', 'usage-report', id] as const,
+    usageReportSettings: (id: string) => ['groups', 'usage-report-settings', id] as const,
   },
 
   // LDAP
@@ -170,15 +170,15 @@ export interface DashboardStats {
 
 /** Password policy settings for security configuration */
 export interface PasswordPolicy {
-  minLength: number;
-  requireUppercase: boolean;
-  requireLowercase: boolean;
-  requireNumbers: boolean;
-  requireSpecial: boolean;
-  historyCount: number;
-  expiryDays: number;
-  jwtTtlMinutes: number;
-  refreshTtlDays: number;
+  min_length: number;
+  max_length: number;
+  require_uppercase: boolean;
+  require_lowercase: boolean;
+  require_numbers: boolean;
+  require_special: boolean;
+  history_count: number;
+  check_dictionary: boolean;
+  check_compromised: boolean;
 }
 
 /** SMS provider type for SMS settings */
@@ -184,6 +184,27 @@ describe('AdminUsersService', () => {
       expect(url).toContain('page=3');
       expect(url).toContain('page_size=50');
     });
+
+    it('should send filters and cursor', async () => {
+      fetchMock.mockResolvedValueOnce(
+        mockFetchJsonResponse({ users: [], total: 0, page: 0, page_size: 20, total_pages: 0 })
+      );
+
+      await service.list(1, 20, {
+        role: 'admin,editor',
+        emailVerified: true,
+        metadata: { plan: 'pro' },
+        cursor: 'next',
+      });
+
+      const [url] = fetchMock.mock.calls[0]!;
+      const query = new URL(url).searchParams;
+      expect(query.get('role')).toBe('admin,editor');
+      expect(query.get('email_verified')).toBe('true');
+      expect(query.get('metadata[plan]')).toBe('pro');
+      expect(query.get('cursor')).toBe('next');
+      expect(query.has('status')).toBe(false);
+    });
   });
 
   describe('get', () => {
@@ -225,6 +246,18 @@ describe('AdminUsersService', () => {
       expect(url).toBe(`${TEST_BASE_URL}/api/admin/users`);
       expect(options.method).toBe('POST');
     });
+
+    it('should send the idempotency key', async () => {
+      fetchMock.mockResolvedValueOnce(mockFetchJsonResponse(createMockAdminUser()));
+
+      await service.create(
+        { email: 'new@example.com', username: 'newuser', password: 'Pass123!', full_name: 'New User' },
+        { idempotencyKey: 'create-user-1' }
+      );
+
+      const [, options] = fetchMock.mock.calls[0]!;
+      expect(options.headers['Idempotency-Key']).toBe('create-user-1');
+    });
   });
 
   describe('update', () => {
@@ -1289,7 +1322,7 @@ describe('AdminSystemService', () => {
       expect(result.require_uppercase).toBe(true);
 
       const [url] = fetchMock.mock.calls[0]!;
-      expect(url).toBe(`${TEST_BASE_URL}/api/admin/system/password-policy`);
+      expect(url).toBe(`${TEST_BASE_URL}/api/admin/system/password-policy/v2`);
     });
 
     it('should update password policy', async () => {
@@ -1302,7 +1335,7 @@ describe('AdminSystemService', () => {
       expect(result.min_length).toBe(12);
 
       const [url, options] = fetchMock.mock.calls[0]!;
-      expect(url).toBe(`${TEST_BASE_URL}/api/admin/system/password-policy`);
+      expect(url).toBe(`${TEST_BASE_URL}/api/admin/system/password-policy/v2`);
       expect(options.method).toBe('PUT');
     });
   });
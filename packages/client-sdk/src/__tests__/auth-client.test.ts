@@ -157,6 +157,52 @@ describe('AuthService', () => {
     });
   });
 
+  // -----------------------------------------------------------------------
+  // guest sessions
+  // -----------------------------------------------------------------------
+  describe('guest sessions', () => {
+    it('should start a guest session and store tokens', async () => {
+      fetchMock.mockResolvedValueOnce({
+        ok: true,
+        status: 201,
+        headers: new Headers({ 'Content-Type': 'application/json' }),
+        json: () => Promise.resolve(createMockAuthResponse({
+          user: createMockUser({ email: '', is_guest: true }),
+        })),
+      });
+
+      const result = await authService.startGuestSession();
+
+      expect(result.user.is_guest).toBe(true);
+      expect(await http.getTokenStorage().getAccessToken()).toBe('mock-access-token');
+      const [url, options] = fetchMock.mock.calls[0]!;
+      expect(url).toBe(`${TEST_BASE_URL}/api/auth/guest`);
+      expect(options.method).toBe('POST');
+    });
+
+    it('should keep guest tokens when the upgrade requires email verification', async () => {
+      await http.getTokenStorage().setAccessToken('guest-token');
+      fetchMock.mockResolvedValueOnce({
+        ok: true,
+        status: 200,
+        headers: new Headers({ 'Content-Type': 'application/json' }),
+        json: () => Promise.resolve({ user: createMockUser({ email_verified: false }), requires_email_verification: true }),
+      });
+
+      const result = await authService.upgradeGuest({
+        email: 'new@example.com',
+        username: 'newuser',
+        password: 'Password123!',
+      });
+
+      expect(result.requires_email_verification).toBe(true);
+      expect(await http.getTokenStorage().getAccessToken()).toBe('guest-token');
+      const [url, options] = fetchMock.mock.calls[0]!;
+      expect(url).toBe(`${TEST_BASE_URL}/api/auth/guest/upgrade`);
+      expect(options.headers['Authorization']).toBe('Bearer guest-token');
+    });
+  });
+
   // -----------------------------------------------------------------------
   // signIn
   // -----------------------------------------------------------------------
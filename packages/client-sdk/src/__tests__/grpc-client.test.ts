@@ -350,6 +350,8 @@ describe('AuthGrpcClient', () => {
         resource: 'articles',
         action: 'write',
         applicationId: '',
+        resourceAttributes: {},
+        contextAttributes: {},
       });
     });
 
@@ -363,6 +365,29 @@ describe('AuthGrpcClient', () => {
 
       expect(result.allowed).toBe(false);
     });
+
+    it('should send resource and context attributes for conditional permissions', async () => {
+      const client = createClient();
+      await client.connect();
+
+      setupGrpcMethodResponse('checkPermission', { allowed: true, role: 'editor' });
+
+      const result = await client.checkPermissionWithAttributes('user-1', 'documents', 'edit', {
+        resource: { owner_id: 'user-1' },
+      });
+
+      expect(result.allowed).toBe(true);
+
+      const callArgs = mockGrpcMethods['checkPermission']!.mock.calls[0]!;
+      expect(callArgs[0]).toEqual({
+        userId: 'user-1',
+        resource: 'documents',
+        action: 'edit',
+        applicationId: '',
+        resourceAttributes: { owner_id: 'user-1' },
+        contextAttributes: {},
+      });
+    });
   });
 
   // -----------------------------------------------------------------------
@@ -760,6 +760,24 @@ describe('OAuthProviderClient', () => {
       expect(() => client.decodeIdToken(idToken)).toThrow('Token expired');
     });
 
+    it('should tolerate clock skew', () => {
+      const now = Date.now();
+      const skewedClient = new OAuthProviderClient({
+        issuer: 'https://auth.example.com',
+        clientId: 'test-client-id',
+        redirectUri: 'https://app.example.com/callback',
+        clockSkewSeconds: 60,
+        now: () => now,
+      });
+      const justExpired = createMockIdToken({ ...validClaims, exp: Math.floor(now / 1000) - 30 });
+      const longExpired = createMockIdToken({ ...validClaims, exp: Math.floor(now / 1000) - 120 });
+      const notYetValid = createMockIdToken({ ...validClaims, nbf: Math.floor(now / 1000) + 120 });
+
+      expect(skewedClient.decodeIdToken(justExpired).sub).toBe('user-123');
+      expect(() => skewedClient.decodeIdToken(longExpired)).toThrow('Token expired');
+      expect(() => skewedClient.decodeIdToken(notYetValid)).toThrow('Token not yet valid');
+    });
+
     it('should return all standard claims', () => {
       const fullClaims = {
         ...validClaims,
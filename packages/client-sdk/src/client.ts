@@ -6,6 +6,7 @@
 import type { ClientConfig, RequestInterceptor, ResponseInterceptor, ErrorInterceptor, TokenStorage, ClientCallbacks, RetryConfig } from './config/types';
 import { HttpClient, MemoryTokenStorage } from './core/http';
 import { WebSocketClient, type WebSocketConfig } from './core/websocket';
+import { SDK_VERSION } from './version';
 import {
   AuthService,
   OAuthService,
@@ -17,6 +18,7 @@ import {
   SessionsService,
   HealthService,
   TokenExchangeService,
+  OrganizationsService,
   AdminUsersService,
   AdminRBACService,
   AdminSessionsService,
@@ -169,6 +171,9 @@ export class AuthGatewayClient {
   /** Token exchange service (cross-application SSO) */
   public readonly tokenExchange: TokenExchangeService;
 
+  /** Organizations service (requires organization admin designation) */
+  public readonly organizations: OrganizationsService;
+
   /** Admin services (requires admin role) */
   public readonly admin: AdminServices;
 
@@ -191,6 +196,7 @@ export class AuthGatewayClient {
     this.sessions = new SessionsService(this.http);
     this.health = new HealthService(this.http);
     this.tokenExchange = new TokenExchangeService(this.http);
+    this.organizations = new OrganizationsService(this.http);
 
     // Initialize admin services
     this.admin = {
@@ -220,6 +226,21 @@ export class AuthGatewayClient {
       emailProviders: new AdminEmailProvidersService(this.http),
       emailProfiles: new AdminEmailProfilesService(this.http),
     };
+
+    if (config.checkCompatibility) {
+      this.health
+        .checkCompatibility()
+        .then((result) => {
+          if (!result.compatible) {
+            console.warn(
+              `[AuthGatewayClient] server ${result.serverVersion} is not compatible with SDK ${SDK_VERSION}: ${result.reason}`
+            );
+          }
+        })
+        .catch(() => {
+          // The server is unreachable; requests will report it
+        });
+    }
   }
 
   // ==================== CONFIGURATION ====================
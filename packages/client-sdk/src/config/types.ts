@@ -84,6 +84,11 @@ export interface ClientConfig {
   applicationId?: string;
   /** Enable debug logging */
   debug?: boolean;
+  /**
+   * Check the server version when the client is created and warn on the console
+   * when the server is older than the SDK needs (default: false)
+   */
+  checkCompatibility?: boolean;
 }
 
 /** Request configuration */
@@ -96,6 +101,16 @@ export interface RequestConfig {
   timeout?: number;
   skipAuth?: boolean;
   retryConfig?: Partial<RetryConfig>;
+  /** Sent as the Idempotency-Key header */
+  idempotencyKey?: string;
+}
+
+/**
+ * Options of requests the server deduplicates: a request retried with the same
+ * idempotency key gets the response of the first one instead of running again
+ */
+export interface IdempotentRequestOptions {
+  idempotencyKey?: string;
 }
 
 /** API response wrapper */
@@ -259,6 +259,9 @@ export class HttpClient {
       ...this.defaultHeaders,
       ...config.headers,
     };
+    if (config.idempotencyKey) {
+      headers['Idempotency-Key'] = config.idempotencyKey;
+    }
 
     // Add auth header if not skipped
     if (!config.skipAuth) {
@@ -12,6 +12,7 @@ import type {
   GrpcCallOptions,
   GrpcClientConfig,
   IntrospectTokenRequest,
+  PermissionAttributes,
   IntrospectTokenResponse,
   SyncUsersOptions,
   TokenExchangeResult,
@@ -52,6 +53,19 @@ import type {
   UserTelegramBotsResponse,
   SendEmailRequest,
   SendEmailResponse,
+  ListUsersRequest,
+  ListUsersResponse,
+  GetUserByEmailRequest,
+  UpdateUserRequest,
+  DeactivateUserRequest,
+  AssignRoleRequest,
+  RemoveRoleRequest,
+  UserResponse,
+  ListUserSessionsRequest,
+  ListUserSessionsResponse,
+  RevokeSessionRequest,
+  RevokeAllUserSessionsRequest,
+  GenericResponse,
   SyncUsersResponse,
   GetApplicationAuthConfigRequest,
   CreateTokenExchangeGrpcRequest,
@@ -141,6 +155,15 @@ export class AuthGrpcClient {
       sendEmail: this.promisify('sendEmail'),
       getUserApplicationProfile: this.promisify('getUserApplicationProfile'),
       getUserTelegramBots: this.promisify('getUserTelegramBots'),
+      listUsers: this.promisify('listUsers'),
+      getUserByEmail: this.promisify('getUserByEmail'),
+      updateUser: this.promisify('updateUser'),
+      deactivateUser: this.promisify('deactivateUser'),
+      assignRole: this.promisify('assignRole'),
+      removeRole: this.promisify('removeRole'),
+      listUserSessions: this.promisify('listUserSessions'),
+      revokeSession: this.promisify('revokeSession'),
+      revokeAllUserSessions: this.promisify('revokeAllUserSessions'),
       syncUsers: this.promisify('syncUsers'),
       getApplicationAuthConfig: this.promisify('getApplicationAuthConfig'),
       createTokenExchange: this.promisify('createTokenExchange'),
@@ -301,12 +324,52 @@ export class AuthGrpcClient {
     await this.ensureConnected();
     const method = this.ensureMethod('checkPermission');
 
-    const request: CheckPermissionRequest = { userId, resource, action, applicationId: '' };
+    const request: CheckPermissionRequest = {
+      userId,
+      resource,
+      action,
+      applicationId: '',
+      resourceAttributes: {},
+      contextAttributes: {},
+    };
     this.log('CheckPermission:', { userId, resource, action });
 
     return await method(request, options) as CheckPermissionResponse;
   }
 
+  /**
+   * Check a permission that may carry a condition (e.g. resource.owner_id == user.id)
+   * against the attributes of the resource and the request
+   * @param userId User UUID
+   * @param resource Resource name (e.g., 'documents')
+   * @param action Action name (e.g., 'edit')
+   * @param attributes Resource and context attributes the condition reads
+   * @param options Call options
+   * @returns Permission check result
+   */
+  async checkPermissionWithAttributes(
+    userId: string,
+    resource: string,
+    action: string,
+    attributes: PermissionAttributes,
+    options?: GrpcCallOptions
+  ): Promise<CheckPermissionResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('checkPermission');
+
+    const request: CheckPermissionRequest = {
+      userId,
+      resource,
+      action,
+      applicationId: '',
+      resourceAttributes: attributes.resource ?? {},
+      contextAttributes: attributes.context ?? {},
+    };
+    this.log('CheckPermission:', { userId, resource, action, attributes });
+
+    return await method(request, options) as CheckPermissionResponse;
+  }
+
   /**
    * Introspect a token for detailed information
    * @param accessToken JWT token
@@ -626,6 +689,213 @@ export class AuthGrpcClient {
     return await method(request, options) as UserTelegramBotsResponse;
   }
 
+  // ========== User Management Methods ==========
+
+  /**
+   * List users with pagination. search and applicationId cannot be combined.
+   * @param request Page, page size and optional search or application filter
+   * @param options Call options
+   * @returns Page of users
+   */
+  async listUsers(
+    request: Partial<ListUsersRequest> = {},
+    options?: GrpcCallOptions
+  ): Promise<ListUsersResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('listUsers');
+
+    const fullRequest: ListUsersRequest = {
+      page: request.page || 1,
+      pageSize: request.pageSize || 20,
+      search: request.search || '',
+      applicationId: request.applicationId || '',
+    };
+    this.log('ListUsers:', fullRequest);
+
+    return await method(fullRequest, options) as ListUsersResponse;
+  }
+
+  /**
+   * Get user information by email address
+   * @param email Email address
+   * @param options Call options
+   * @returns User information
+   */
+  async getUserByEmail(
+    email: string,
+    options?: GrpcCallOptions
+  ): Promise<GetUserResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('getUserByEmail');
+
+    const request: GetUserByEmailRequest = { email };
+    this.log('GetUserByEmail:', { email });
+
+    return await method(request, options) as GetUserResponse;
+  }
+
+  /**
+   * Update a user's account. Empty fields are left unchanged.
+   * Requires the users:write scope; an API key must belong to an admin.
+   * @param request User ID and fields to change
+   * @param options Call options
+   * @returns Updated user
+   */
+  async updateUser(
+    request: Pick<UpdateUserRequest, 'userId'> & Partial<UpdateUserRequest>,
+    options?: GrpcCallOptions
+  ): Promise<UserResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('updateUser');
+
+    const fullRequest: UpdateUserRequest = {
+      userId: request.userId,
+      email: request.email || '',
+      username: request.username || '',
+      fullName: request.fullName || '',
+      phone: request.phone || '',
+      emailVerified: request.emailVerified,
+    };
+    this.log('UpdateUser:', { userId: request.userId });
+
+    return await method(fullRequest, options) as UserResponse;
+  }
+
+  /**
+   * Deactivate a user's account and revoke their tokens.
+   * Requires the users:write scope; an API key must belong to an admin.
+   * @param userId User UUID
+   * @param reason Reason recorded in the audit log
+   * @param options Call options
+   * @returns Deactivated user
+   */
+  async deactivateUser(
+    userId: string,
+    reason = '',
+    options?: GrpcCallOptions
+  ): Promise<UserResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('deactivateUser');
+
+    const request: DeactivateUserRequest = { userId, reason };
+    this.log('DeactivateUser:', { userId });
+
+    return await method(request, options) as UserResponse;
+  }
+
+  /**
+   * Assign a role, identified by roleId or roleName, to a user.
+   * Requires the admin:all scope; an API key must belong to an admin.
+   * @param request User ID and role
+   * @param options Call options
+   * @returns User with updated roles
+   */
+  async assignRole(
+    request: Pick<AssignRoleRequest, 'userId'> & Partial<AssignRoleRequest>,
+    options?: GrpcCallOptions
+  ): Promise<UserResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('assignRole');
+
+    const fullRequest: AssignRoleRequest = {
+      userId: request.userId,
+      roleId: request.roleId || '',
+      roleName: request.roleName || '',
+    };
+    this.log('AssignRole:', fullRequest);
+
+    return await method(fullRequest, options) as UserResponse;
+  }
+
+  /**
+   * Remove a role, identified by roleId or roleName, from a user. A user's last role cannot be removed.
+   * Requires the admin:all scope; an API key must belong to an admin.
+   * @param request User ID and role
+   * @param options Call options
+   * @returns User with updated roles
+   */
+  async removeRole(
+    request: Pick<RemoveRoleRequest, 'userId'> & Partial<RemoveRoleRequest>,
+    options?: GrpcCallOptions
+  ): Promise<UserResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('removeRole');
+
+    const fullRequest: RemoveRoleRequest = {
+      userId: request.userId,
+      roleId: request.roleId || '',
+      roleName: request.roleName || '',
+    };
+    this.log('RemoveRole:', fullRequest);
+
+    return await method(fullRequest, options) as UserResponse;
+  }
+
+  // ========== Session Management Methods ==========
+
+  /**
+   * List the active sessions of a user with pagination.
+   * Requires the sessions:read scope.
+   * @param request User ID and pagination
+   * @param options Call options
+   * @returns Page of sessions
+   */
+  async listUserSessions(
+    request: Pick<ListUserSessionsRequest, 'userId'> & Partial<ListUserSessionsRequest>,
+    options?: GrpcCallOptions
+  ): Promise<ListUserSessionsResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('listUserSessions');
+
+    const fullRequest: ListUserSessionsRequest = {
+      userId: request.userId,
+      page: request.page || 1,
+      pageSize: request.pageSize || 20,
+    };
+    this.log('ListUserSessions:', fullRequest);
+
+    return await method(fullRequest, options) as ListUserSessionsResponse;
+  }
+
+  /**
+   * Revoke one session of a user and blacklist its tokens.
+   * Requires the sessions:revoke scope; an API key must belong to an admin.
+   * @param request User ID and session ID
+   * @param options Call options
+   * @returns Operation result
+   */
+  async revokeSession(
+    request: RevokeSessionRequest,
+    options?: GrpcCallOptions
+  ): Promise<GenericResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('revokeSession');
+
+    this.log('RevokeSession:', request);
+
+    return await method(request, options) as GenericResponse;
+  }
+
+  /**
+   * Revoke every session of a user and blacklist their tokens.
+   * Requires the sessions:revoke scope; an API key must belong to an admin.
+   * @param userId User ID
+   * @param options Call options
+   * @returns Operation result
+   */
+  async revokeAllUserSessions(
+    userId: string,
+    options?: GrpcCallOptions
+  ): Promise<GenericResponse> {
+    await this.ensureConnected();
+    const method = this.ensureMethod('revokeAllUserSessions');
+
+    const request: RevokeAllUserSessionsRequest = { userId };
+    this.log('RevokeAllUserSessions:', request);
+
+    return await method(request, options) as GenericResponse;
+  }
+
   // ========== Sync & Config Methods ==========
 
   /**
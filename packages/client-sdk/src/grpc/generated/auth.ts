@@ -41,6 +41,8 @@ export interface ValidateTokenResponse {
   applicationId: string;
   /** Per-application roles */
   appRoles: string[];
+  /** Anonymous guest that has not been upgraded to a full account */
+  isGuest: boolean;
 }
 
 /** GetUserRequest contains the user ID to retrieve */
@@ -62,6 +64,9 @@ export interface User {
   isActive: boolean;
   createdAt: number;
   updatedAt: number;
+  phone: string;
+  /** invited, active, suspended, deactivated or deleted */
+  state: string;
 }
 
 /** GetUserResponse contains user information */
@@ -79,6 +84,12 @@ export interface CheckPermissionRequest {
   action: string;
   /** Optional: check per-app permission */
   applicationId: string;
+  /**
+   * Attributes conditional permissions are evaluated against, read as resource.<key>
+   * and context.<key>; user.* and time.* are filled in by the server
+   */
+  resourceAttributes: { [key: string]: string };
+  contextAttributes: { [key: string]: string };
 }
 
 /** CheckPermissionResponse contains permission check result */
@@ -532,6 +543,112 @@ export interface SendEmailResponse {
   errorMessage: string;
 }
 
+/** ListUsersRequest contains pagination and filters for listing users */
+export interface ListUsersRequest {
+  page: number;
+  /** 1-100, default 20 */
+  pageSize: number;
+  /** Optional: match email, username or full name */
+  search: string;
+  /** Optional: only users of this application */
+  applicationId: string;
+}
+
+/** ListUsersResponse contains a page of users */
+export interface ListUsersResponse {
+  users: User[];
+  total: number;
+  page: number;
+  pageSize: number;
+  totalPages: number;
+}
+
+/** GetUserByEmailRequest contains the email address of the user to retrieve */
+export interface GetUserByEmailRequest {
+  email: string;
+}
+
+/** UpdateUserRequest contains the account fields to change */
+export interface UpdateUserRequest {
+  userId: string;
+  email: string;
+  username: string;
+  fullName: string;
+  phone: string;
+  emailVerified?: boolean | undefined;
+}
+
+/** DeactivateUserRequest contains the user to deactivate */
+export interface DeactivateUserRequest {
+  userId: string;
+  /** Recorded in the audit log and the webhook */
+  reason: string;
+}
+
+/** AssignRoleRequest identifies a user and a role by ID or name */
+export interface AssignRoleRequest {
+  userId: string;
+  roleId: string;
+  /** Used when role_id is empty */
+  roleName: string;
+}
+
+/** RemoveRoleRequest identifies a user and a role by ID or name */
+export interface RemoveRoleRequest {
+  userId: string;
+  roleId: string;
+  /** Used when role_id is empty */
+  roleName: string;
+}
+
+/** UserResponse contains the user after a change */
+export interface UserResponse {
+  user?: User | undefined;
+}
+
+/** ListUserSessionsRequest contains the user and pagination for listing sessions */
+export interface ListUserSessionsRequest {
+  userId: string;
+  page: number;
+  /** 1-100, default 20 */
+  pageSize: number;
+}
+
+/** Session represents an active session of a user */
+export interface Session {
+  id: string;
+  userId: string;
+  deviceType: string;
+  os: string;
+  browser: string;
+  userAgent: string;
+  ipAddress: string;
+  sessionName: string;
+  lastActiveAt: number;
+  createdAt: number;
+  expiresAt: number;
+}
+
+/** ListUserSessionsResponse contains a page of sessions */
+export interface ListUserSessionsResponse {
+  sessions: Session[];
+  total: number;
+  page: number;
+  pageSize: number;
+  totalPages: number;
+}
+
+/** RevokeSessionRequest identifies a session and the user it must belong to */
+export interface RevokeSessionRequest {
+  userId: string;
+  sessionId: string;
+}
+
+/** RevokeAllUserSessionsRequest contains the user whose sessions to revoke */
+export interface RevokeAllUserSessionsRequest {
+  userId: string;
+}
+
 export interface SyncUsersRequest {
   /** RFC3339 timestamp */
   updatedAfter: string;
@@ -611,6 +728,47 @@ export interface RedeemTokenExchangeGrpcResponse {
   errorMessage: string;
 }
 
+export interface PermissionCatalogEntry {
+  resource: string;
+  action: string;
+  description: string;
+}
+
+export interface RegisterPermissionCatalogRequest {
+  /** Name of the registering service, e.g. "billing-service" */
+  service: string;
+  entries: PermissionCatalogEntry[];
+}
+
+export interface RegisterPermissionCatalogResponse {
+  success: boolean;
+  registered: number;
+  errorMessage: string;
+}
+
+export interface WatchRevocationsRequest {
+  /** Optional: only events for this user (plus "all" events) */
+  userId: string;
+}
+
+export interface RevocationEvent {
+  /**
+   * "token": one access or refresh token, identified by token_hash
+   * "session": every token of session_id
+   * "user": every token issued to user_id before revoked_at
+   * "all": every token issued before revoked_at
+   */
+  type: string;
+  /** SHA-256 hex of the revoked token */
+  tokenHash: string;
+  sessionId: string;
+  userId: string;
+  /** Unix seconds */
+  revokedAt: number;
+  /** Unix seconds after which a "token" revocation no longer matters (0 = unknown) */
+  expiresAt: number;
+}
+
 /** AuthService provides authentication and authorization operations for microservices */
 export interface AuthService {
   /** ValidateToken validates a JWT access token and returns user information */
@@ -669,6 +827,27 @@ export interface AuthService {
   UnbanUser(request: UnbanUserRequest): Promise<GenericResponse>;
   /** ListApplicationUsers lists all users for a specific application with pagination */
   ListApplicationUsers(request: ListApplicationUsersRequest): Promise<ListApplicationUsersResponse>;
+  /**
+   * ListUsers lists users with pagination, optionally searching by email, username or name
+   * or restricted to the users of an application
+   */
+  ListUsers(request: ListUsersRequest): Promise<ListUsersResponse>;
+  /** GetUserByEmail retrieves user information by email address */
+  GetUserByEmail(request: GetUserByEmailRequest): Promise<GetUserResponse>;
+  /** UpdateUser updates a user's account; empty fields are left unchanged */
+  UpdateUser(request: UpdateUserRequest): Promise<UserResponse>;
+  /** DeactivateUser deactivates a user's account and revokes their tokens */
+  DeactivateUser(request: DeactivateUserRequest): Promise<UserResponse>;
+  /** AssignRole assigns a role to a user */
+  AssignRole(request: AssignRoleRequest): Promise<UserResponse>;
+  /** RemoveRole removes a role from a user; a user keeps at least one role */
+  RemoveRole(request: RemoveRoleRequest): Promise<UserResponse>;
+  /** ListUserSessions lists the active sessions of a user with pagination */
+  ListUserSessions(request: ListUserSessionsRequest): Promise<ListUserSessionsResponse>;
+  /** RevokeSession revokes one session of a user and blacklists its tokens */
+  RevokeSession(request: RevokeSessionRequest): Promise<GenericResponse>;
+  /** RevokeAllUserSessions revokes every session of a user and blacklists their tokens */
+  RevokeAllUserSessions(request: RevokeAllUserSessionsRequest): Promise<GenericResponse>;
   /** SyncUsers returns users updated after a given timestamp for shadow table sync */
   SyncUsers(request: SyncUsersRequest): Promise<SyncUsersResponse>;
   /** GetApplicationAuthConfig returns auth configuration for a specific application */
@@ -677,4 +856,10 @@ export interface AuthService {
   CreateTokenExchange(request: CreateTokenExchangeGrpcRequest): Promise<CreateTokenExchangeGrpcResponse>;
   /** RedeemTokenExchange redeems an exchange code for tokens in the target application */
   RedeemTokenExchange(request: RedeemTokenExchangeGrpcRequest): Promise<RedeemTokenExchangeGrpcResponse>;
+  /**
+   * RegisterPermissionCatalog publishes the resources and actions a service checks, so they
+   * show up in the admin permission catalog. Replaces the entries previously registered by
+   * the same service.
+   */
+  RegisterPermissionCatalog(request: RegisterPermissionCatalogRequest): Promise<RegisterPermissionCatalogResponse>;
 }
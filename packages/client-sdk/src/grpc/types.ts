@@ -50,6 +50,20 @@ export type {
   UserTelegramBotsResponse,
   SendEmailRequest,
   SendEmailResponse,
+  ListUsersRequest,
+  ListUsersResponse,
+  GetUserByEmailRequest,
+  UpdateUserRequest,
+  DeactivateUserRequest,
+  AssignRoleRequest,
+  RemoveRoleRequest,
+  UserResponse,
+  ListUserSessionsRequest,
+  ListUserSessionsResponse,
+  Session,
+  RevokeSessionRequest,
+  RevokeAllUserSessionsRequest,
+  GenericResponse,
   SyncUsersRequest,
   SyncUsersResponse,
   SyncUser,
@@ -101,6 +115,14 @@ export interface GrpcCallOptions {
   metadata?: Record<string, string>;
 }
 
+/** Attributes for checking conditional permissions (SDK convenience wrapper) */
+export interface PermissionAttributes {
+  /** Attributes of the resource, read as resource.<key> (e.g. owner_id) */
+  resource?: Record<string, string>;
+  /** Attributes of the request, read as context.<key> (e.g. ip_country) */
+  context?: Record<string, string>;
+}
+
 /** Sync users options (SDK convenience wrapper) */
 export interface SyncUsersOptions {
   updatedAfter: Date | string;
@@ -101,6 +101,7 @@ export {
   APIKeysService,
   SessionsService,
   HealthService,
+  OrganizationsService,
   // Admin services
   AdminUsersService,
   AdminRBACService,
@@ -124,7 +125,9 @@ export {
   AdminTelegramBotsService,
   AdminUserTelegramService,
   type AuditLogQueryOptions,
+  type AuditStatsOptions,
   type ListClientsParams,
+  type UserListFilters,
 } from './services';
 
 // OAuth Provider Client (for apps using Auth Gateway as OAuth provider)
@@ -134,5 +137,15 @@ export {
   type OAuthProviderClientConfig,
 } from './oauth-provider-client';
 
+// Version and server compatibility
+export {
+  SDK_VERSION,
+  API_VERSION,
+  MIN_SERVER_VERSION,
+  isVersionOlder,
+  checkServerCompatibility,
+  type CompatibilityResult,
+} from './version';
+
 // Types
 export * from './types';
@@ -17,6 +17,7 @@ export class RestClientAdapter implements AuthValidator {
       roles: result.roles,
       appRoles: [],
       applicationId: undefined,
+      isGuest: result.is_guest,
     };
   }
 }
@@ -35,6 +36,7 @@ export class GrpcClientAdapter implements AuthValidator {
       roles: result.roles || [],
       appRoles: [],
       applicationId: undefined,
+      isGuest: result.isGuest,
     };
   }
 }
@@ -82,6 +82,7 @@ export function createAuthMiddleware(
         roles: validation.roles || [],
         appRoles: validation.appRoles || [],
         applicationId: validation.applicationId,
+        isGuest: validation.isGuest,
       };
 
       if (cache) {
@@ -126,3 +127,16 @@ export function requireAppRole(...roles: string[]) {
     next();
   };
 }
+
+/** Reject anonymous guests that have not upgraded to a full account */
+export function requireFullAccount() {
+  return (req: any, res: any, next: any) => {
+    if (!req.auth) {
+      return res.status(401).json({ error: 'Unauthorized' });
+    }
+    if (req.auth.isGuest) {
+      return res.status(403).json({ error: 'A full account is required' });
+    }
+    next();
+  };
+}
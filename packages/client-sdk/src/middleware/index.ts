@@ -5,6 +5,7 @@ export {
   createAuthMiddleware,
   requireRole,
   requireAppRole,
+  requireFullAccount,
   bearerTokenExtractor,
   cookieTokenExtractor,
   queryTokenExtractor,
@@ -10,6 +10,7 @@ export interface AuthData {
   roles: string[];
   appRoles: string[];
   applicationId?: string;
+  isGuest?: boolean;
 }
 
 /** Token extractor function */
@@ -37,6 +38,7 @@ export interface TokenValidationResult {
   roles?: string[];
   appRoles?: string[];
   applicationId?: string;
+  isGuest?: boolean;
 }
 
 declare global {
@@ -49,6 +49,10 @@ export interface OAuthProviderClientConfig {
   redirectUri: string;
   scopes?: string[];
   usePKCE?: boolean;
+  /** Tolerated clock drift in seconds when checking exp and nbf (default: 30) */
+  clockSkewSeconds?: number;
+  /** Time source in milliseconds since the epoch (default: Date.now); inject a fixed clock in tests */
+  now?: () => number;
 }
 
 export class OAuthProviderClient {
@@ -60,6 +64,8 @@ export class OAuthProviderClient {
     this.config = {
       usePKCE: true,
       scopes: ['openid'],
+      clockSkewSeconds: 30,
+      now: Date.now,
       ...config,
     };
   }
@@ -274,9 +280,14 @@ export class OAuthProviderClient {
     if (payload.aud !== this.config.clientId) {
       throw new Error('Invalid audience');
     }
-    if (payload.exp < Date.now() / 1000) {
+    const now = this.config.now!() / 1000;
+    const skew = this.config.clockSkewSeconds!;
+    if (payload.exp < now - skew) {
       throw new Error('Token expired');
     }
+    if (payload.nbf !== undefined && payload.nbf > now + skew) {
+      throw new Error('Token not yet valid');
+    }
 
     return payload as IDTokenClaims;
   }
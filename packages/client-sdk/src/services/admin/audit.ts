@@ -3,21 +3,36 @@
  */
 
 import type { HttpClient } from '../../core/http';
-import type { AuditLogEntry, AuditLogListResponse } from '../../types/admin';
+import type {
+  AuditActionStats,
+  AuditFailureStats,
+  AuditLogEntry,
+  AuditLogListResponse,
+} from '../../types/admin';
 import { BaseService } from '../base';
 
 /** Query options for audit logs */
 export interface AuditLogQueryOptions {
   userId?: string;
+  /** Action; comma-separated for several */
   action?: string;
+  /** @deprecated The server ignores it */
   resource?: string;
-  status?: 'success' | 'failure';
+  status?: 'success' | 'failed' | 'blocked';
+  ipAddress?: string;
+  /** ISO country code */
+  country?: string;
+  /** Text in the action, resource, IP address, user agent, city or details */
+  search?: string;
   startDate?: Date;
   endDate?: Date;
   page?: number;
   pageSize?: number;
 }
 
+/** Filters for audit statistics; without dates the last 24 hours are counted (at most 31 days) */
+export type AuditStatsOptions = Omit<AuditLogQueryOptions, 'page' | 'pageSize' | 'resource'>;
+
 /** Admin Audit service for audit log management */
 export class AdminAuditService extends BaseService {
   constructor(http: HttpClient) {
@@ -35,12 +50,7 @@ export class AdminAuditService extends BaseService {
       {
         headers: {},
         query: {
-          user_id: options.userId,
-          action: options.action,
-          resource: options.resource,
-          status: options.status,
-          start_date: options.startDate?.toISOString(),
-          end_date: options.endDate?.toISOString(),
+          ...auditFilterQuery(options),
           page: options.page ?? 1,
           page_size: options.pageSize ?? 50,
         },
@@ -100,7 +110,32 @@ export class AdminAuditService extends BaseService {
    * @returns Failed audit logs
    */
   async getFailures(page = 1, pageSize = 50): Promise<AuditLogListResponse> {
-    return this.list({ status: 'failure', page, pageSize });
+    return this.list({ status: 'failed', page, pageSize });
+  }
+
+  /**
+   * Get the most frequent audit actions
+   * @param options Filters
+   * @param limit Number of actions
+   * @returns Actions with how many did not succeed, most frequent first
+   */
+  async getActionStats(options: AuditStatsOptions = {}, limit = 10): Promise<AuditActionStats> {
+    const response = await this.http.get<AuditActionStats>('/api/admin/audit-logs/stats/actions', {
+      query: { ...auditFilterQuery(options), limit },
+    });
+    return response.data;
+  }
+
+  /**
+   * Get unsuccessful audit actions per hour
+   * @param options Filters
+   * @returns Failures of every hour of the range
+   */
+  async getFailureStats(options: AuditStatsOptions = {}): Promise<AuditFailureStats> {
+    const response = await this.http.get<AuditFailureStats>('/api/admin/audit-logs/stats/failures', {
+      query: auditFilterQuery(options),
+    });
+    return response.data;
   }
 
   /**
@@ -134,15 +169,26 @@ export class AdminAuditService extends BaseService {
     page = 1,
     pageSize = 50
   ): Promise<AuditLogEntry[]> {
-    // Fetch login logs and filter by IP
     const response = await this.list({
-      action: 'login',
+      action: 'signin,signin_failed',
+      ipAddress,
       page,
-      pageSize: pageSize * 5, // Fetch more to filter
+      pageSize,
     });
 
-    return response.logs
-      .filter((log) => log.ip_address === ipAddress)
-      .slice(0, pageSize);
+    return response.logs;
   }
 }
+
+function auditFilterQuery(options: AuditStatsOptions): Record<string, string | undefined> {
+  return {
+    user_id: options.userId,
+    action: options.action,
+    status: options.status,
+    ip: options.ipAddress,
+    country: options.country,
+    q: options.search,
+    from: options.startDate?.toISOString(),
+    to: options.endDate?.toISOString(),
+  };
+}
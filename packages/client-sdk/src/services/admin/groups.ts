@@ -11,6 +11,19 @@ import type {
   AddGroupMembersRequest,
   GroupListResponse,
   GroupMembersResponse,
+  GroupRolesResponse,
+  SetGroupRolesRequest,
+  GroupAdmin,
+  AddGroupAdminRequest,
+  GroupAdminListResponse,
+  OrgUsageReport,
+  OrgUsageReportListResponse,
+  OrgUsageReportSettings,
+  UpdateOrgUsageReportSettingsRequest,
+  SendOrgUsageReportResponse,
+  GroupDomain,
+  AddGroupDomainRequest,
+  GroupDomainListResponse,
 } from '../../types/admin';
 import { BaseService } from '../base';
 
@@ -111,4 +124,158 @@ export class AdminGroupsService extends BaseService {
     const response = await this.http.delete<MessageResponse>(`/api/admin/groups/${id}/members/${userId}`);
     return response.data;
   }
+
+  /**
+   * Get the roles a group grants to its members, including roles inherited from parent groups
+   * @param id Group ID
+   * @returns Direct and inherited group roles
+   */
+  async getRoles(id: string): Promise<GroupRolesResponse> {
+    const response = await this.http.get<GroupRolesResponse>(`/api/admin/groups/${id}/roles`);
+    return response.data;
+  }
+
+  /**
+   * Replace the roles mapped to a group. Members of the group and its child groups
+   * gain or lose the roles accordingly; directly assigned roles are not affected.
+   * @param id Group ID
+   * @param data Role IDs
+   * @returns Direct and inherited group roles
+   */
+  async setRoles(id: string, data: SetGroupRolesRequest): Promise<GroupRolesResponse> {
+    const response = await this.http.put<GroupRolesResponse>(`/api/admin/groups/${id}/roles`, data);
+    return response.data;
+  }
+
+  /**
+   * List the organization admins designated on a group
+   * @param id Group ID
+   * @returns Group admins
+   */
+  async getAdmins(id: string): Promise<GroupAdminListResponse> {
+    const response = await this.http.get<GroupAdminListResponse>(`/api/admin/groups/${id}/admins`);
+    return response.data;
+  }
+
+  /**
+   * Designate a user as organization admin of a group and its child groups
+   * @param id Group ID
+   * @param data User to designate
+   * @returns Created designation
+   */
+  async addAdmin(id: string, data: AddGroupAdminRequest): Promise<GroupAdmin> {
+    const response = await this.http.post<GroupAdmin>(`/api/admin/groups/${id}/admins`, data);
+    return response.data;
+  }
+
+  /**
+   * Revoke a user's organization admin designation on a group
+   * @param id Group ID
+   * @param userId User ID
+   * @returns Success message
+   */
+  async removeAdmin(id: string, userId: string): Promise<MessageResponse> {
+    const response = await this.http.delete<MessageResponse>(`/api/admin/groups/${id}/admins/${userId}`);
+    return response.data;
+  }
+
+  /**
+   * List the usage reports of all top-level groups
+   * @returns Usage reports
+   */
+  async listUsageReports(): Promise<OrgUsageReportListResponse> {
+    const response = await this.http.get<OrgUsageReportListResponse>('/api/admin/usage-reports');
+    return response.data;
+  }
+
+  /**
+   * Get the usage report of a group and its child groups
+   * @param id Group ID
+   * @returns Active users, seat usage and MFA adoption
+   */
+  async getUsageReport(id: string): Promise<OrgUsageReport> {
+    const response = await this.http.get<OrgUsageReport>(`/api/admin/groups/${id}/usage-report`);
+    return response.data;
+  }
+
+  /**
+   * Get the seat license and report delivery settings of a group
+   * @param id Group ID
+   * @returns Usage report settings
+   */
+  async getUsageReportSettings(id: string): Promise<OrgUsageReportSettings> {
+    const response = await this.http.get<OrgUsageReportSettings>(`/api/admin/groups/${id}/usage-report/settings`);
+    return response.data;
+  }
+
+  /**
+   * Replace the seat license and report delivery settings of a group
+   * @param id Group ID
+   * @param data Licensed seats, recipients and frequency
+   * @returns Updated settings
+   */
+  async updateUsageReportSettings(
+    id: string,
+    data: UpdateOrgUsageReportSettingsRequest
+  ): Promise<OrgUsageReportSettings> {
+    const response = await this.http.put<OrgUsageReportSettings>(
+      `/api/admin/groups/${id}/usage-report/settings`,
+      data
+    );
+    return response.data;
+  }
+
+  /**
+   * Email the current usage report of a group to its recipients
+   * @param id Group ID
+   * @returns Number of emails sent
+   */
+  async sendUsageReport(id: string): Promise<SendOrgUsageReportResponse> {
+    const response = await this.http.post<SendOrgUsageReportResponse>(`/api/admin/groups/${id}/usage-report/send`);
+    return response.data;
+  }
+
+  /**
+   * List the email domains claimed by a group
+   * @param id Group ID
+   * @returns Domains with their verification status
+   */
+  async listDomains(id: string): Promise<GroupDomainListResponse> {
+    const response = await this.http.get<GroupDomainListResponse>(`/api/admin/groups/${id}/domains`);
+    return response.data;
+  }
+
+  /**
+   * Claim an email domain for a group. Publish the returned verification_token as a
+   * TXT record at verification_record, then call verifyDomain.
+   * @param id Group ID
+   * @param data Domain and auto-join flag
+   * @returns Claimed domain
+   */
+  async addDomain(id: string, data: AddGroupDomainRequest): Promise<GroupDomain> {
+    const response = await this.http.post<GroupDomain>(`/api/admin/groups/${id}/domains`, data);
+    return response.data;
+  }
+
+  /**
+   * Check the DNS TXT record of a claimed domain
+   * @param id Group ID
+   * @param domainId Domain ID
+   * @returns Verified domain
+   */
+  async verifyDomain(id: string, domainId: string): Promise<GroupDomain> {
+    const response = await this.http.post<GroupDomain>(`/api/admin/groups/${id}/domains/${domainId}/verify`);
+    return response.data;
+  }
+
+  /**
+   * Release a domain claimed by a group
+   * @param id Group ID
+   * @param domainId Domain ID
+   * @returns Success message
+   */
+  async removeDomain(id: string, domainId: string): Promise<MessageResponse> {
+    const response = await this.http.delete<MessageResponse>(`/api/admin/groups/${id}/domains/${domainId}`);
+    return response.data;
+  }
 }
@@ -2,11 +2,11 @@
  * Admin services exports
  */
 
-export { AdminUsersService } from './users';
+export { AdminUsersService, type UserListFilters } from './users';
 export { AdminRBACService } from './rbac';
 export { AdminSessionsService } from './sessions';
 export { AdminIPFiltersService } from './ip-filters';
-export { AdminAuditService, type AuditLogQueryOptions } from './audit';
+export { AdminAuditService, type AuditLogQueryOptions, type AuditStatsOptions } from './audit';
 export { AdminBrandingService } from './branding';
 export { AdminSystemService } from './system';
 export { AdminAPIKeysService } from './api-keys';
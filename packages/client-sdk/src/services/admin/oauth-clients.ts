@@ -3,6 +3,7 @@
  */
 
 import type { HttpClient } from '../../core/http';
+import type { IdempotentRequestOptions } from '../../config/types';
 import type {
   OAuthClient,
   CreateOAuthClientRequest,
@@ -14,6 +15,8 @@ import type {
   CreateScopeRequest,
   OAuthScopeListResponse,
   UserConsentListResponse,
+  TokenPreviewRequest,
+  TokenPreviewResponse,
 } from '../../types/oauth-provider';
 import { BaseService } from '../base';
 
@@ -36,12 +39,17 @@ export class AdminOAuthClientsService extends BaseService {
   /**
    * Create a new OAuth client
    * @param data Client creation data
+   * @param options Idempotency key to make retries safe
    * @returns Client with plain client_secret (shown only once)
    */
-  async create(data: CreateOAuthClientRequest): Promise<CreateOAuthClientResponse> {
+  async create(
+    data: CreateOAuthClientRequest,
+    options?: IdempotentRequestOptions
+  ): Promise<CreateOAuthClientResponse> {
     const response = await this.http.post<CreateOAuthClientResponse>(
       '/api/admin/oauth/clients',
-      data
+      data,
+      { idempotencyKey: options?.idempotencyKey }
     );
     return response.data;
   }
@@ -172,4 +180,20 @@ export class AdminOAuthClientsService extends BaseService {
   async revokeUserConsent(clientId: string, userId: string): Promise<void> {
     await this.http.delete(`/api/admin/oauth/clients/${clientId}/consents/${userId}`);
   }
+
+  // ============= Token Debugging =============
+
+  /**
+   * Preview the access token, ID token and userinfo claims that would be
+   * issued to a user through a client, without issuing any token
+   * @param data User, client and requested scopes
+   * @returns Claims and the policies that would block issuance
+   */
+  async previewTokens(data: TokenPreviewRequest): Promise<TokenPreviewResponse> {
+    const response = await this.http.post<TokenPreviewResponse>(
+      '/api/admin/debug/token-preview',
+      data
+    );
+    return response.data;
+  }
 }
@@ -5,11 +5,15 @@
 import type { HttpClient } from '../../core/http';
 import type { MessageResponse } from '../../types/common';
 import type {
+  CreatePermissionBundleRequest,
   CreatePermissionRequest,
   CreateRoleRequest,
+  EffectivePermissionsResponse,
   Permission,
+  PermissionBundle,
   PermissionMatrix,
   Role,
+  UpdatePermissionBundleRequest,
   UpdateRoleRequest,
 } from '../../types/rbac';
 import { BaseService } from '../base';
@@ -60,7 +64,7 @@ export class AdminRBACService extends BaseService {
    * @param data Permission update data
    * @returns Updated permission
    */
-  async updatePermission(id: string, data: { name?: string; description?: string }): Promise<Permission> {
+  async updatePermission(id: string, data: { name?: string; description?: string; condition?: string }): Promise<Permission> {
     const response = await this.http.put<Permission>(
       `/api/admin/rbac/permissions/${id}`,
       data
@@ -180,6 +184,71 @@ export class AdminRBACService extends BaseService {
     return this.updateRole(roleId, { permissions: newIds });
   }
 
+  /**
+   * Get the effective permissions of a role
+   * Includes the permissions of its bundles and of the roles it inherits from
+   * @param roleId Role ID
+   * @returns Effective permissions with their sources
+   */
+  async getEffectivePermissions(roleId: string): Promise<EffectivePermissionsResponse> {
+    const response = await this.http.get<EffectivePermissionsResponse>(
+      `/api/admin/rbac/roles/${roleId}/effective-permissions`
+    );
+    return response.data;
+  }
+
+  // ==================== PERMISSION BUNDLES ====================
+
+  /**
+   * List all permission bundles
+   * @returns List of permission bundles
+   */
+  async listBundles(): Promise<PermissionBundle[]> {
+    const response = await this.http.get<{ bundles: PermissionBundle[]; total: number }>(
+      '/api/admin/rbac/bundles'
+    );
+    return response.data.bundles;
+  }
+
+  /**
+   * Get permission bundle by ID
+   * @param id Bundle ID
+   * @returns Permission bundle
+   */
+  async getBundle(id: string): Promise<PermissionBundle> {
+    const response = await this.http.get<PermissionBundle>(`/api/admin/rbac/bundles/${id}`);
+    return response.data;
+  }
+
+  /**
+   * Create a new permission bundle
+   * @param data Bundle data
+   * @returns Created bundle
+   */
+  async createBundle(data: CreatePermissionBundleRequest): Promise<PermissionBundle> {
+    const response = await this.http.post<PermissionBundle>('/api/admin/rbac/bundles', data);
+    return response.data;
+  }
+
+  /**
+   * Update a permission bundle
+   * @param id Bundle ID
+   * @param data Update data
+   * @returns Updated bundle
+   */
+  async updateBundle(id: string, data: UpdatePermissionBundleRequest): Promise<PermissionBundle> {
+    const response = await this.http.put<PermissionBundle>(`/api/admin/rbac/bundles/${id}`, data);
+    return response.data;
+  }
+
+  /**
+   * Delete a permission bundle
+   * @param id Bundle ID
+   */
+  async deleteBundle(id: string): Promise<void> {
+    await this.http.delete(`/api/admin/rbac/bundles/${id}`);
+  }
+
   // ==================== PERMISSION MATRIX ====================
 
   /**
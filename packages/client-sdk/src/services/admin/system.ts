@@ -4,10 +4,24 @@
 
 import type { HttpClient } from '../../core/http';
 import type {
+  AuditExportReplayRequest,
+  AuditExportReplayResponse,
+  AuditExportStatus,
+  AuthorizationMap,
+  BackgroundJob,
+  BackgroundJobListResponse,
+  BackgroundJobStats,
+  BackgroundJobStatus,
+  DormantAccountPolicy,
+  DormantAccountReport,
   GeoDistributionResponse,
   MaintenanceModeResponse,
+  SignupPolicy,
+  SLOListResponse,
   SystemHealthResponse,
+  UpdateDormantAccountPolicyRequest,
   UpdateMaintenanceModeRequest,
+  UpdateSignupPolicyRequest,
 } from '../../types/admin';
 import { BaseService } from '../base';
 
@@ -28,6 +42,98 @@ export class AdminSystemService extends BaseService {
     return response.data;
   }
 
+  /**
+   * List the built-in SLOs with their error budgets, as counted by the
+   * instance answering
+   * @returns SLOs of the sign-in, token and token validation endpoints
+   */
+  async listSLOs(): Promise<SLOListResponse> {
+    const response = await this.http.get<SLOListResponse>('/api/admin/slos');
+    return response.data;
+  }
+
+  /**
+   * Get what every HTTP route and gRPC method requires of its caller,
+   * generated from the router and middleware registration
+   * @returns Authorization map
+   */
+  async getAuthorizationMap(): Promise<AuthorizationMap> {
+    const response = await this.http.get<AuthorizationMap>('/api/admin/authorization-map');
+    return response.data;
+  }
+
+  /**
+   * List jobs of the background job queue, newest first
+   * @param page Page number
+   * @param perPage Items per page
+   * @param status Filter by status
+   * @param type Filter by job type, e.g. webhook.delivery
+   * @returns Paginated jobs
+   */
+  async listJobs(
+    page = 1,
+    perPage = 20,
+    status?: BackgroundJobStatus,
+    type?: string
+  ): Promise<BackgroundJobListResponse> {
+    const response = await this.http.get<BackgroundJobListResponse>(
+      '/api/admin/jobs',
+      { query: { page, page_size: perPage, status, type } }
+    );
+    return response.data;
+  }
+
+  /**
+   * Count jobs of the background job queue by status
+   * @returns Job counts
+   */
+  async getJobStats(): Promise<BackgroundJobStats> {
+    const response = await this.http.get<BackgroundJobStats>('/api/admin/jobs/stats');
+    return response.data;
+  }
+
+  /**
+   * Get a background job with its payload and last error
+   * @param id Job ID
+   * @returns Job
+   */
+  async getJob(id: string): Promise<BackgroundJob> {
+    const response = await this.http.get<BackgroundJob>(`/api/admin/jobs/${id}`);
+    return response.data;
+  }
+
+  /**
+   * Requeue a dead background job with a fresh set of attempts
+   * @param id Job ID
+   * @returns Requeued job
+   */
+  async retryJob(id: string): Promise<BackgroundJob> {
+    const response = await this.http.post<BackgroundJob>(`/api/admin/jobs/${id}/retry`);
+    return response.data;
+  }
+
+  /**
+   * List the sinks audit logs are exported to
+   * @returns Sinks with how far logs have been scheduled for export
+   */
+  async getAuditExportStatus(): Promise<AuditExportStatus> {
+    const response = await this.http.get<AuditExportStatus>('/api/admin/audit-export');
+    return response.data;
+  }
+
+  /**
+   * Export the audit logs of a past range to a sink again
+   * @param data Sink and range
+   * @returns Range and number of export jobs queued
+   */
+  async replayAuditExport(data: AuditExportReplayRequest): Promise<AuditExportReplayResponse> {
+    const response = await this.http.post<AuditExportReplayResponse>(
+      '/api/admin/audit-export/replay',
+      data
+    );
+    return response.data;
+  }
+
   /**
    * Get maintenance mode status
    * @returns Maintenance mode status
@@ -143,7 +249,7 @@ export class AdminSystemService extends BaseService {
    * @returns Password policy settings
    */
   async getPasswordPolicy(): Promise<any> {
-    const response = await this.http.get<any>('/api/admin/system/password-policy');
+    const response = await this.http.get<any>('/api/admin/system/password-policy/v2');
     return response.data;
   }
 
@@ -153,7 +259,54 @@ export class AdminSystemService extends BaseService {
    * @returns Updated password policy
    */
   async updatePasswordPolicy(policy: any): Promise<any> {
-    const response = await this.http.put<any>('/api/admin/system/password-policy', policy);
+    const response = await this.http.put<any>('/api/admin/system/password-policy/v2', policy);
+    return response.data;
+  }
+
+  /**
+   * Get the sign-up policy
+   * @returns Allowed and blocked domains, disposable blocking and invite-only mode
+   */
+  async getSignupPolicy(): Promise<SignupPolicy> {
+    const response = await this.http.get<SignupPolicy>('/api/admin/system/signup-policy');
+    return response.data;
+  }
+
+  /**
+   * Replace the sign-up policy
+   * @param policy Sign-up policy
+   * @returns Updated sign-up policy
+   */
+  async updateSignupPolicy(policy: UpdateSignupPolicyRequest): Promise<SignupPolicy> {
+    const response = await this.http.put<SignupPolicy>('/api/admin/system/signup-policy', policy);
+    return response.data;
+  }
+
+  /**
+   * Get the dormant account policy
+   * @returns Inactivity period, notice period, action and exclusions
+   */
+  async getDormantAccountPolicy(): Promise<DormantAccountPolicy> {
+    const response = await this.http.get<DormantAccountPolicy>('/api/admin/system/dormant-account-policy');
+    return response.data;
+  }
+
+  /**
+   * Replace the dormant account policy
+   * @param policy Dormant account policy
+   * @returns Updated dormant account policy
+   */
+  async updateDormantAccountPolicy(policy: UpdateDormantAccountPolicyRequest): Promise<DormantAccountPolicy> {
+    const response = await this.http.put<DormantAccountPolicy>('/api/admin/system/dormant-account-policy', policy);
+    return response.data;
+  }
+
+  /**
+   * Dry run of the dormant account policy; changes nothing
+   * @returns Dormant accounts and what the next run would do to each
+   */
+  async getDormantAccountReport(): Promise<DormantAccountReport> {
+    const response = await this.http.get<DormantAccountReport>('/api/admin/system/dormant-account-policy/report');
     return response.data;
   }
 }
@@ -3,6 +3,7 @@
  */
 
 import type { HttpClient } from '../../core/http';
+import type { IdempotentRequestOptions } from '../../config/types';
 import type { MessageResponse } from '../../types/common';
 import type { OAuthAccountListResponse } from '../../types/oauth';
 import type {
@@ -11,9 +12,29 @@ import type {
   AdminCreateUserRequest,
   AdminUserListResponse,
   AdminUserResponse,
+  UserErasureResponse,
 } from '../../types/user';
 import { BaseService } from '../base';
 
+/** Filters for listing users */
+export interface UserListFilters {
+  /** Text in the username, full name or any email address */
+  search?: string;
+  /** Role name; comma-separated for several */
+  role?: string;
+  /** User state; comma-separated for several */
+  status?: string;
+  emailVerified?: boolean;
+  /** Whether TOTP or a security key is enabled */
+  twoFactor?: boolean;
+  createdFrom?: Date;
+  createdTo?: Date;
+  /** Application profile metadata attributes */
+  metadata?: Record<string, string>;
+  /** next_cursor of the previous page; the page number is ignored with a cursor */
+  cursor?: string;
+}
+
 /** Admin Users service for user management */
 export class AdminUsersService extends BaseService {
   constructor(http: HttpClient) {
@@ -30,15 +51,32 @@ export class AdminUsersService extends BaseService {
   }
 
   /**
-   * List all users with pagination
+   * List users matching the filters, newest first
    * @param page Page number
    * @param pageSize Items per page
+   * @param filters Filters and cursor
    * @returns Paginated list of users
    */
-  async list(page = 1, pageSize = 20): Promise<AdminUserListResponse> {
+  async list(page = 1, pageSize = 20, filters: UserListFilters = {}): Promise<AdminUserListResponse> {
+    const query: Record<string, string | number | boolean | undefined> = {
+      page,
+      page_size: pageSize,
+      search: filters.search,
+      role: filters.role,
+      status: filters.status,
+      email_verified: filters.emailVerified,
+      two_factor: filters.twoFactor,
+      created_from: filters.createdFrom?.toISOString(),
+      created_to: filters.createdTo?.toISOString(),
+      cursor: filters.cursor,
+    };
+    for (const [key, value] of Object.entries(filters.metadata ?? {})) {
+      query[`metadata[${key}]`] = value;
+    }
+
     const response = await this.http.get<AdminUserListResponse>('/api/admin/users', {
       headers: {},
-      query: { page, page_size: pageSize },
+      query,
     });
     return response.data;
   }
@@ -56,10 +94,16 @@ export class AdminUsersService extends BaseService {
   /**
    * Create a new user
    * @param data User creation data
+   * @param options Idempotency key to make retries safe
    * @returns Created user
    */
-  async create(data: AdminCreateUserRequest): Promise<AdminUserResponse> {
-    const response = await this.http.post<AdminUserResponse>('/api/admin/users', data);
+  async create(
+    data: AdminCreateUserRequest,
+    options?: IdempotentRequestOptions
+  ): Promise<AdminUserResponse> {
+    const response = await this.http.post<AdminUserResponse>('/api/admin/users', data, {
+      idempotencyKey: options?.idempotencyKey,
+    });
     return response.data;
   }
 
@@ -87,6 +131,19 @@ export class AdminUsersService extends BaseService {
     return response.data;
   }
 
+  /**
+   * Erase a user's personal data (GDPR erasure) and delete the account.
+   * Administrators cannot be erased.
+   * @param id User ID
+   * @returns Erasure summary
+   */
+  async gdprErase(id: string): Promise<UserErasureResponse> {
+    const response = await this.http.delete<UserErasureResponse>(
+      `/api/admin/users/${id}/gdpr-erase`
+    );
+    return response.data;
+  }
+
   /**
    * Activate a user
    * @param id User ID
@@ -3,6 +3,7 @@
  */
 
 import type { HttpClient } from '../../core/http';
+import type { IdempotentRequestOptions } from '../../config/types';
 import type { MessageResponse } from '../../types/common';
 import type {
   Webhook,
@@ -11,6 +12,7 @@ import type {
   CreateWebhookResponse,
   UpdateWebhookRequest,
   TestWebhookRequest,
+  WebhookDelivery,
   WebhookDeliveryListResponse,
 } from '../../types/admin';
 import { BaseService } from '../base';
@@ -88,12 +90,18 @@ export class AdminWebhooksService extends BaseService {
    * Test webhook with a specific event
    * @param id Webhook ID
    * @param data Test data with event type
+   * @param options Idempotency key to make retries safe
    * @returns Success message
    */
-  async test(id: string, data: TestWebhookRequest): Promise<MessageResponse> {
+  async test(
+    id: string,
+    data: TestWebhookRequest,
+    options?: IdempotentRequestOptions
+  ): Promise<MessageResponse> {
     const response = await this.http.post<MessageResponse>(
       `/api/admin/webhooks/${id}/test`,
-      data
+      data,
+      { idempotencyKey: options?.idempotencyKey }
     );
     return response.data;
   }
@@ -117,6 +125,19 @@ export class AdminWebhooksService extends BaseService {
     return response.data;
   }
 
+  /**
+   * Send the payload of a past delivery again
+   * @param id Webhook ID
+   * @param deliveryId Delivery ID
+   * @returns The new delivery, sent and retried in the background
+   */
+  async redeliver(id: string, deliveryId: string): Promise<WebhookDelivery> {
+    const response = await this.http.post<WebhookDelivery>(
+      `/api/admin/webhooks/${id}/deliveries/${deliveryId}/redeliver`
+    );
+    return response.data;
+  }
+
   /**
    * Get available webhook events
    * @returns List of available event types
@@ -3,6 +3,7 @@
  */
 
 import type { HttpClient } from '../core/http';
+import type { IdempotentRequestOptions } from '../config/types';
 import type { MessageResponse } from '../types/common';
 import type {
   APIKey,
@@ -22,12 +23,17 @@ export class APIKeysService extends BaseService {
   /**
    * Create a new API key
    * @param data API key creation data
+   * @param options Idempotency key to make retries safe
    * @returns Created API key with plain key (only shown once!)
    */
-  async create(data: CreateAPIKeyRequest): Promise<CreateAPIKeyResponse> {
+  async create(
+    data: CreateAPIKeyRequest,
+    options?: IdempotentRequestOptions
+  ): Promise<CreateAPIKeyResponse> {
     const response = await this.http.post<CreateAPIKeyResponse>(
       '/api/api-keys',
-      data
+      data,
+      { idempotencyKey: options?.idempotencyKey }
     );
     return response.data;
   }
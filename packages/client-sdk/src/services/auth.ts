@@ -13,14 +13,25 @@ import type {
   SignInRequest,
   SignUpRequest,
   TokenValidationResponse,
+  UpgradeGuestRequest,
   VerifyEmailRequest,
 } from '../types/auth';
 import type {
   ChangePasswordRequest,
+  DataExportFormat,
+  DataExportResponse,
   UpdateProfileRequest,
   User,
 } from '../types/user';
 import type { MessageResponse, EmailMessageResponse, ValidationResponse } from '../types/common';
+import type {
+  CreateOAuthClientWebhookRequest,
+  CreateOAuthClientWebhookResponse,
+  OAuthClientWebhook,
+  OAuthClientWebhookDeliveryListResponse,
+  OAuthClientWebhookListResponse,
+  UpdateOAuthClientWebhookRequest,
+} from '../types/oauth-provider';
 import { BaseService } from './base';
 
 /** Authentication service for sign up, sign in, token management, and profile */
@@ -47,6 +58,33 @@ export class AuthService extends BaseService {
     return response.data;
   }
 
+  /**
+   * Start an anonymous guest session (must be enabled on the server)
+   * @returns Authentication response with tokens and the guest user
+   */
+  async startGuestSession(): Promise<AuthResponse> {
+    const response = await this.http.post<AuthResponse>('/api/auth/guest', undefined, {
+      skipAuth: true,
+    });
+
+    await this.storeTokens(response.data);
+
+    return response.data;
+  }
+
+  /**
+   * Upgrade the current guest session to a full account, keeping the user ID
+   * @param data Credentials for the account
+   * @returns Authentication response with new tokens (none if the email must be verified first)
+   */
+  async upgradeGuest(data: UpgradeGuestRequest): Promise<AuthResponse> {
+    const response = await this.http.post<AuthResponse>('/api/auth/guest/upgrade', data);
+
+    await this.storeTokens(response.data);
+
+    return response.data;
+  }
+
   /**
    * Sign in with email/phone and password
    * @param data Sign in credentials
@@ -152,6 +190,117 @@ export class AuthService extends BaseService {
     return response.data;
   }
 
+  /**
+   * Request an export of the current user's data. The export is generated in the
+   * background: poll until its status is ready, then open download_url, a signed link
+   * that works without a bearer token. A ready export is returned again until it expires.
+   * @param format File format (default json)
+   * @returns The export and, once ready, its download link
+   */
+  async requestDataExport(format?: DataExportFormat): Promise<DataExportResponse> {
+    const response = await this.http.get<DataExportResponse>('/api/auth/me/export', {
+      query: format ? { format } : undefined,
+    });
+    return response.data;
+  }
+
+  /**
+   * List webhooks of an OAuth client owned by the current user
+   * @param clientId OAuth client ID
+   * @returns Webhooks and the events they can subscribe to
+   */
+  async listOAuthClientWebhooks(
+    clientId: string
+  ): Promise<OAuthClientWebhookListResponse> {
+    const response = await this.http.get<OAuthClientWebhookListResponse>(
+      `/api/oauth-clients/${clientId}/webhooks`
+    );
+    return response.data;
+  }
+
+  /**
+   * Register a webhook for consent, token revocation and secret rotation
+   * events of an OAuth client owned by the current user
+   * @param clientId OAuth client ID
+   * @param data HTTPS URL and events
+   * @returns Created webhook and its signing secret (only returned once)
+   */
+  async createOAuthClientWebhook(
+    clientId: string,
+    data: CreateOAuthClientWebhookRequest
+  ): Promise<CreateOAuthClientWebhookResponse> {
+    const response = await this.http.post<CreateOAuthClientWebhookResponse>(
+      `/api/oauth-clients/${clientId}/webhooks`,
+      data
+    );
+    return response.data;
+  }
+
+  /**
+   * Get a webhook of an OAuth client owned by the current user
+   * @param clientId OAuth client ID
+   * @param id Webhook ID
+   * @returns Webhook
+   */
+  async getOAuthClientWebhook(
+    clientId: string,
+    id: string
+  ): Promise<OAuthClientWebhook> {
+    const response = await this.http.get<OAuthClientWebhook>(
+      `/api/oauth-clients/${clientId}/webhooks/${id}`
+    );
+    return response.data;
+  }
+
+  /**
+   * Update a webhook of an OAuth client owned by the current user
+   * @param clientId OAuth client ID
+   * @param id Webhook ID
+   * @param data Fields to update
+   * @returns Updated webhook
+   */
+  async updateOAuthClientWebhook(
+    clientId: string,
+    id: string,
+    data: UpdateOAuthClientWebhookRequest
+  ): Promise<OAuthClientWebhook> {
+    const response = await this.http.put<OAuthClientWebhook>(
+      `/api/oauth-clients/${clientId}/webhooks/${id}`,
+      data
+    );
+    return response.data;
+  }
+
+  /**
+   * Delete a webhook of an OAuth client owned by the current user
+   * @param clientId OAuth client ID
+   * @param id Webhook ID
+   */
+  async deleteOAuthClientWebhook(clientId: string, id: string): Promise<void> {
+    await this.http.delete(`/api/oauth-clients/${clientId}/webhooks/${id}`);
+  }
+
+  /**
+   * Get delivery attempts of an OAuth client webhook, newest first
+   * @param clientId OAuth client ID
+   * @param id Webhook ID
+   * @param page Page number
+   * @param perPage Items per page
+   * @returns Paginated deliveries
+   */
+  async getOAuthClientWebhookDeliveries(
+    clientId: string,
+    id: string,
+    page = 1,
+    perPage = 20
+  ): Promise<OAuthClientWebhookDeliveryListResponse> {
+    const response = await this.http.get<OAuthClientWebhookDeliveryListResponse>(
+      `/api/oauth-clients/${clientId}/webhooks/${id}/deliveries`,
+      { query: { page, page_size: perPage } }
+    );
+    return response.data;
+  }
+
   /**
    * Verify email address with code
    * @param data Email and verification code
@@ -266,6 +415,10 @@ export class AuthService extends BaseService {
 
   /** Store tokens from auth response */
   private async storeTokens(response: AuthResponse): Promise<void> {
+    // No tokens are issued until a required email verification is done
+    if (!response.access_token) {
+      return;
+    }
     const tokenStorage = this.http.getTokenStorage();
     await tokenStorage.setAccessToken(response.access_token);
     await tokenStorage.setRefreshToken(response.refresh_token);
@@ -3,7 +3,14 @@
  */
 
 import type { HttpClient } from '../core/http';
-import type { HealthResponse, MaintenanceModeResponse } from '../types/admin';
+import type {
+  HealthResponse,
+  MaintenanceModeResponse,
+  PublicStatusResponse,
+  ServerVersionResponse,
+} from '../types/admin';
+import { NotFoundError } from '../core/errors';
+import { checkServerCompatibility, type CompatibilityResult } from '../version';
 import { BaseService } from './base';
 
 /** Status response for readiness and liveness */
@@ -58,6 +65,47 @@ export class HealthService extends BaseService {
     }
   }
 
+  /**
+   * Get the public status for status pages
+   * @returns Version, uptime, component statuses and the maintenance in progress
+   */
+  async status(): Promise<PublicStatusResponse> {
+    const response = await this.http.get<PublicStatusResponse>('/status', {
+      skipAuth: true,
+    });
+    return response.data;
+  }
+
+  /**
+   * Get the build information of the server
+   * @returns Version, commit and the API versions the server serves
+   */
+  async version(): Promise<ServerVersionResponse> {
+    const response = await this.http.get<ServerVersionResponse>('/version', {
+      skipAuth: true,
+    });
+    return response.data;
+  }
+
+  /**
+   * Check that the server has the features this SDK needs
+   * @returns Whether the server is compatible, and why not
+   */
+  async checkCompatibility(): Promise<CompatibilityResult> {
+    try {
+      return checkServerCompatibility(await this.version());
+    } catch (error) {
+      if (error instanceof NotFoundError) {
+        return {
+          compatible: false,
+          serverVersion: 'unknown',
+          reason: 'the server predates the /version endpoint',
+        };
+      }
+      throw error;
+    }
+  }
+
   /**
    * Check if server is in maintenance mode
    * @returns Maintenance mode status
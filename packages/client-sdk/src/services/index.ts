@@ -13,6 +13,7 @@ export { APIKeysService } from './api-keys';
 export { SessionsService } from './sessions';
 export { HealthService } from './health';
 export { TokenExchangeService } from './token-exchange';
+export { OrganizationsService } from './organizations';
 
 // Admin services
 export * from './admin';
@@ -0,0 +1,176 @@
+/**
+ * Organizations service for organization admins
+ */
+
+import type { HttpClient } from '../core/http';
+import type { IdempotentRequestOptions } from '../config/types';
+import type { MessageResponse } from '../types/common';
+import type { Role } from '../types/rbac';
+import type {
+  AdminUserResponse,
+  AdminUserListResponse,
+  AdminCreateUserRequest,
+} from '../types/user';
+import type {
+  OrgListResponse,
+  OrgUpdateUserRequest,
+  OrgAPIKeyListResponse,
+  OrgUsageReport,
+} from '../types/admin';
+import { BaseService } from './base';
+
+/**
+ * Organizations service. Organizations are groups; an organization admin manages the
+ * members of a group and of its child groups without global admin rights.
+ */
+export class OrganizationsService extends BaseService {
+  constructor(http: HttpClient) {
+    super(http);
+  }
+
+  /**
+   * List the organizations the current user administers
+   * @returns Organizations
+   */
+  async listMine(): Promise<OrgListResponse> {
+    const response = await this.http.get<OrgListResponse>('/api/orgs');
+    return response.data;
+  }
+
+  /**
+   * List organization members
+   * @param orgId Organization (group) ID
+   * @param page Page number
+   * @param pageSize Items per page
+   * @param search Optional search by username, full name or email
+   * @returns Paginated list of members
+   */
+  async listUsers(orgId: string, page = 1, pageSize = 20, search?: string): Promise<AdminUserListResponse> {
+    const response = await this.http.get<AdminUserListResponse>(`/api/orgs/${orgId}/users`, {
+      query: { page, page_size: pageSize, search },
+    });
+    return response.data;
+  }
+
+  /**
+   * Get an organization member
+   * @param orgId Organization (group) ID
+   * @param userId User ID
+   * @returns User details
+   */
+  async getUser(orgId: string, userId: string): Promise<AdminUserResponse> {
+    const response = await this.http.get<AdminUserResponse>(`/api/orgs/${orgId}/users/${userId}`);
+    return response.data;
+  }
+
+  /**
+   * Create a user inside the organization
+   * @param orgId Organization (group) ID
+   * @param data User data; only non-system roles may be requested
+   * @param options Idempotency key to make retries safe
+   * @returns Created user
+   */
+  async createUser(
+    orgId: string,
+    data: AdminCreateUserRequest,
+    options?: IdempotentRequestOptions
+  ): Promise<AdminUserResponse> {
+    const response = await this.http.post<AdminUserResponse>(`/api/orgs/${orgId}/users`, data, {
+      idempotencyKey: options?.idempotencyKey,
+    });
+    return response.data;
+  }
+
+  /**
+   * Update an organization member
+   * @param orgId Organization (group) ID
+   * @param userId User ID
+   * @param data Update data
+   * @returns Updated user
+   */
+  async updateUser(orgId: string, userId: string, data: OrgUpdateUserRequest): Promise<AdminUserResponse> {
+    const response = await this.http.put<AdminUserResponse>(`/api/orgs/${orgId}/users/${userId}`, data);
+    return response.data;
+  }
+
+  /**
+   * Remove a member from the organization and its child groups. The account is kept.
+   * @param orgId Organization (group) ID
+   * @param userId User ID
+   * @returns Success message
+   */
+  async removeUser(orgId: string, userId: string): Promise<MessageResponse> {
+    const response = await this.http.delete<MessageResponse>(`/api/orgs/${orgId}/users/${userId}`);
+    return response.data;
+  }
+
+  /**
+   * List the roles organization admins may assign
+   * @param orgId Organization (group) ID
+   * @returns Non-system roles
+   */
+  async listRoles(orgId: string): Promise<Role[]> {
+    const response = await this.http.get<Role[]>(`/api/orgs/${orgId}/roles`);
+    return response.data;
+  }
+
+  /**
+   * Assign a role to an organization member
+   * @param orgId Organization (group) ID
+   * @param userId User ID
+   * @param roleId Role ID
+   * @returns Updated user
+   */
+  async assignRole(orgId: string, userId: string, roleId: string): Promise<AdminUserResponse> {
+    const response = await this.http.post<AdminUserResponse>(
+      `/api/orgs/${orgId}/users/${userId}/roles`,
+      { role_id: roleId }
+    );
+    return response.data;
+  }
+
+  /**
+   * Remove a role from an organization member
+   * @param orgId Organization (group) ID
+   * @param userId User ID
+   * @param roleId Role ID
+   * @returns Updated user
+   */
+  async removeRole(orgId: string, userId: string, roleId: string): Promise<AdminUserResponse> {
+    const response = await this.http.delete<AdminUserResponse>(
+      `/api/orgs/${orgId}/users/${userId}/roles/${roleId}`
+    );
+    return response.data;
+  }
+
+  /**
+   * List the API keys owned by organization members
+   * @param orgId Organization (group) ID
+   * @returns API keys
+   */
+  async listAPIKeys(orgId: string): Promise<OrgAPIKeyListResponse> {
+    const response = await this.http.get<OrgAPIKeyListResponse>(`/api/orgs/${orgId}/api-keys`);
+    return response.data;
+  }
+
+  /**
+   * Revoke an API key owned by an organization member
+   * @param orgId Organization (group) ID
+   * @param keyId API key ID
+   * @returns Success message
+   */
+  async revokeAPIKey(orgId: string, keyId: string): Promise<MessageResponse> {
+    const response = await this.http.post<MessageResponse>(`/api/orgs/${orgId}/api-keys/${keyId}/revoke`);
+    return response.data;
+  }
+
+  /**
+   * Get the usage report of an organization and its child groups
+   * @param orgId Organization (group) ID
+   * @returns Active users, seat usage and MFA adoption
+   */
+  async getUsageReport(orgId: string): Promise<OrgUsageReport> {
+    const response = await this.http.get<OrgUsageReport>(`/api/orgs/${orgId}/usage-report`);
+    return response.data;
+  }
+}
@@ -3,8 +3,15 @@
  */
 
 import type { HttpClient } from '../core/http';
-import type { AuthResponse, PasswordlessRequestRequest, PasswordlessVerifyRequest } from '../types/auth';
-import type { EmailMessageResponse } from '../types/common';
+import { TwoFactorRequiredError } from '../core/errors';
+import type {
+  AuthResponse,
+  MagicLinkCompleteRequest,
+  MagicLinkRequestRequest,
+  PasswordlessRequestRequest,
+  PasswordlessVerifyRequest,
+} from '../types/auth';
+import type { EmailMessageResponse, MessageResponse } from '../types/common';
 import { BaseService } from './base';
 
 /** Passwordless authentication service (magic link / email code) */
@@ -48,6 +55,49 @@ export class PasswordlessService extends BaseService {
     return response.data;
   }
 
+  /**
+   * Email a one-click sign-in link.
+   * Once clicked, the link redirects to the app with a `code` query parameter
+   * for {@link completeMagicLink}, or an `error` parameter
+   * (`invalid_link`, `access_denied` or `server_error`).
+   * @param email Email address
+   * @param redirectUrl App URL to land on (defaults to the gateway's configured URL)
+   * @returns Success message (the same whether or not the account exists)
+   */
+  async requestMagicLink(email: string, redirectUrl?: string): Promise<MessageResponse> {
+    const response = await this.http.post<MessageResponse>(
+      '/api/auth/magic-link/request',
+      { email, redirect_url: redirectUrl } satisfies MagicLinkRequestRequest,
+      { skipAuth: true }
+    );
+    return response.data;
+  }
+
+  /**
+   * Exchange the code from a magic link redirect for tokens
+   * @param code Code from the redirect URL
+   * @returns Authentication response with tokens
+   * @throws TwoFactorRequiredError if 2FA is required
+   */
+  async completeMagicLink(code: string): Promise<AuthResponse> {
+    const response = await this.http.post<AuthResponse>(
+      '/api/auth/magic-link/complete',
+      { code } satisfies MagicLinkCompleteRequest,
+      { skipAuth: true }
+    );
+
+    if (response.data.requires_2fa && response.data.two_factor_token) {
+      throw new TwoFactorRequiredError(response.data.two_factor_token);
+    }
+
+    // Store tokens
+    const tokenStorage = this.http.getTokenStorage();
+    await tokenStorage.setAccessToken(response.data.access_token);
+    await tokenStorage.setRefreshToken(response.data.refresh_token);
+
+    return response.data;
+  }
+
   /**
    * Complete passwordless login flow
    * Combines request and verify steps
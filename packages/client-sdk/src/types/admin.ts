@@ -3,6 +3,9 @@
  */
 
 import type { IPFilterType, TimestampedEntity } from './common';
+import type { Role } from './rbac';
+import type { User } from './user';
+import type { AdminAPIKeyResponse } from './api-key';
 
 /** IP Filter entity */
 export interface IPFilter extends TimestampedEntity {
@@ -39,6 +42,10 @@ export interface AuditLogEntry {
   user_agent: string;
   created_at: string;
   details?: Record<string, unknown>;
+  resource_type?: string;
+  resource_id?: string;
+  country_code?: string;
+  city?: string;
 }
 
 /** Audit log list response */
@@ -50,6 +57,35 @@ export interface AuditLogListResponse {
   total_pages: number;
 }
 
+/** Number of audit logs of an action */
+export interface AuditActionCount {
+  action: string;
+  total: number;
+  /** Logs that did not succeed (failed, blocked) */
+  failed: number;
+}
+
+/** Most frequent audit actions of a time range */
+export interface AuditActionStats {
+  from: string;
+  to: string;
+  actions: AuditActionCount[];
+}
+
+/** Number of audit logs of an hour */
+export interface AuditHourlyCount {
+  hour: string;
+  count: number;
+}
+
+/** Unsuccessful audit actions per hour; every hour of the range is listed */
+export interface AuditFailureStats {
+  from: string;
+  to: string;
+  hours: AuditHourlyCount[];
+  total: number;
+}
+
 /** Theme settings */
 export interface ThemeSettings {
   primary_color: string;
@@ -112,6 +148,143 @@ export interface SystemHealthResponse {
   version: string;
 }
 
+/** Burn rate of the error budget of an SLO over a recent window */
+export interface SLOBurnRate {
+  window: string;
+  total_requests: number;
+  bad_requests: number;
+  /** 1 exhausts the budget exactly at the end of the SLO window */
+  burn_rate: number;
+}
+
+/** Error budget of a built-in service level objective */
+export interface SLOStatus {
+  name: string;
+  endpoint: string;
+  type: 'availability' | 'latency';
+  objective: number;
+  latency_threshold_ms?: number;
+  total_requests: number;
+  bad_requests: number;
+  sli: number;
+  /** Negative once the budget is exhausted */
+  error_budget_remaining: number;
+  burn_rates: SLOBurnRate[];
+  alert?: 'critical' | 'warning';
+}
+
+/** SLOs with their error budgets */
+export interface SLOListResponse {
+  window: string;
+  slos: SLOStatus[];
+  tracked_since: string;
+}
+
+/** One check the gateway performs before a route handler runs */
+export interface AuthRequirement {
+  kind: 'authentication' | 'role' | 'permission' | 'admin_or_permission' | 'scope' | 'org_admin' | 'policy';
+  values?: string[];
+  /** All of values are required rather than any one of them */
+  all?: boolean;
+  /** Callers let through without meeting the requirement, e.g. admin or app_secret */
+  bypass?: string[];
+  description?: string;
+}
+
+/** What an HTTP route requires of its caller */
+export interface RouteAuthorization {
+  method: string;
+  path: string;
+  /** Public routes may still authenticate the caller in the handler */
+  public: boolean;
+  authentication?: Array<'jwt' | 'api_key' | 'app_secret'>;
+  requirements: AuthRequirement[];
+  handler: string;
+}
+
+/** What a gRPC method requires of its caller */
+export interface GRPCMethodAuthorization {
+  method: string;
+  streaming: boolean;
+  authentication?: Array<'api_key' | 'app_secret'>;
+  scope?: string;
+  /** Methods without a configured scope are rejected for every caller */
+  denied: boolean;
+}
+
+/** Authorization requirements of every HTTP route and gRPC method */
+export interface AuthorizationMap {
+  generated_at: string;
+  http: RouteAuthorization[];
+  grpc: GRPCMethodAuthorization[];
+}
+
+/** Status of a background job; dead jobs are kept until retried */
+export type BackgroundJobStatus = 'pending' | 'running' | 'succeeded' | 'dead';
+
+/** Job of the persistent background job queue */
+export interface BackgroundJob {
+  id: string;
+  /** e.g. webhook.delivery */
+  type: string;
+  payload: Record<string, unknown>;
+  status: BackgroundJobStatus;
+  attempts: number;
+  max_attempts: number;
+  /** Next attempt, or end of the lease while running */
+  run_at: string;
+  last_error?: string;
+  created_at: string;
+  updated_at: string;
+  completed_at?: string;
+}
+
+/** Background job list response, newest first */
+export interface BackgroundJobListResponse {
+  jobs: BackgroundJob[];
+  total: number;
+  page: number;
+  page_size: number;
+  total_pages: number;
+}
+
+/** Background jobs counted by status */
+export interface BackgroundJobStats {
+  pending: number;
+  running: number;
+  succeeded: number;
+  dead: number;
+}
+
+/** Sink audit logs are exported to */
+export interface AuditExportSinkStatus {
+  name: 'syslog' | 's3';
+  /** Length of the windows exported at once, e.g. 1m0s */
+  interval: string;
+  /** Logs created before this time have export jobs queued */
+  scheduled_until?: string;
+}
+
+/** Configured audit export sinks */
+export interface AuditExportStatus {
+  sinks: AuditExportSinkStatus[];
+}
+
+/** Request to export the audit logs of a past range, at most 31 days, again */
+export interface AuditExportReplayRequest {
+  sink: 'syslog' | 's3';
+  from: string;
+  to: string;
+}
+
+/** Export jobs queued for a replay; the range is widened to whole intervals of the sink */
+export interface AuditExportReplayResponse {
+  sink: string;
+  from: string;
+  to: string;
+  jobs: number;
+}
+
 /** Health check response */
 export interface HealthResponse {
   status: 'healthy' | 'unhealthy';
@@ -121,6 +294,42 @@ export interface HealthResponse {
   };
 }
 
+/** Public status of the service or of one of its components */
+export type PublicStatus = 'operational' | 'degraded' | 'outage' | 'maintenance';
+
+/** Status of one dependency on the public status */
+export interface PublicStatusComponent {
+  name: string;
+  status: Exclude<PublicStatus, 'maintenance'>;
+  /** Check latency in milliseconds, absent when the component is unreachable */
+  latency_ms?: number;
+}
+
+/** Public status response, for status pages */
+export interface PublicStatusResponse {
+  status: PublicStatus;
+  version: string;
+  uptime_seconds: number;
+  components: PublicStatusComponent[];
+  /** Maintenance in progress, absent when maintenance mode is off */
+  maintenance?: {
+    message: string;
+    since: string;
+  };
+  checked_at: string;
+}
+
+/** Build information of the server */
+export interface ServerVersionResponse {
+  /** Semantic version, or 'dev' for untagged builds */
+  version: string;
+  commit?: string;
+  build_date?: string;
+  go_version: string;
+  /** API versions the server serves, oldest first */
+  api_versions: string[];
+}
+
 /** Geo distribution location */
 export interface GeoLocation {
   country_code: string;
@@ -310,7 +519,7 @@ export interface EmailTemplateVariablesResponse {
 // ============================================
 
 /** OAuth provider type */
-export type OAuthProviderType = 'google' | 'github' | 'yandex' | 'telegram' | 'instagram' | 'onec' | string;
+export type OAuthProviderType = 'google' | 'github' | 'yandex' | 'telegram' | 'instagram' | 'onec' | 'apple' | 'microsoft' | 'linkedin' | 'oidc-generic' | string;
 
 /** OAuth provider configuration entity */
 export interface OAuthProviderConfig extends TimestampedEntity {
@@ -401,6 +610,204 @@ export interface GroupListResponse {
   total_pages: number;
 }
 
+/** Set group roles request */
+export interface SetGroupRolesRequest {
+  role_ids: string[];
+}
+
+/** Roles a group grants to its members and the members of its child groups */
+export interface GroupRolesResponse {
+  group_id: string;
+  /** Roles mapped to the group itself */
+  roles: Role[];
+  /** Roles mapped to parent groups, inherited by members */
+  inherited_roles: Role[];
+}
+
+/** Organization admin designation on a group. Organization admins manage the group and its child groups. */
+export interface GroupAdmin {
+  group_id: string;
+  user_id: string;
+  created_by?: string;
+  created_at: string;
+  user?: User;
+}
+
+/** Add group admin request */
+export interface AddGroupAdminRequest {
+  user_id: string;
+}
+
+/** Group admins response */
+export interface GroupAdminListResponse {
+  admins: GroupAdmin[];
+}
+
+// ============================================
+// Organizations Types (organizations are groups)
+// ============================================
+
+/** Organizations the current user administers */
+export interface OrgListResponse {
+  organizations: Group[];
+}
+
+/** Fields an organization admin may change on a member */
+export interface OrgUpdateUserRequest {
+  username?: string;
+  full_name?: string;
+  phone?: string;
+  is_active?: boolean;
+}
+
+/** API keys owned by organization members */
+export interface OrgAPIKeyListResponse {
+  api_keys: AdminAPIKeyResponse[];
+  total: number;
+  page: number;
+  page_size: number;
+  total_pages: number;
+}
+
+/** Seat license and scheduled delivery settings of an organization usage report */
+export interface OrgUsageReportSettings {
+  group_id: string;
+  /** Licensed seats; omitted means unlimited */
+  licensed_seats?: number;
+  /** Report recipients; empty disables scheduled delivery */
+  recipients: string[];
+  frequency: 'weekly' | 'monthly';
+  last_sent_at?: string;
+  updated_at: string;
+}
+
+/** Update organization usage report settings request */
+export interface UpdateOrgUsageReportSettingsRequest {
+  licensed_seats?: number | null;
+  recipients?: string[];
+  frequency?: 'weekly' | 'monthly';
+}
+
+/** Usage report of an organization and its child groups */
+export interface OrgUsageReport {
+  group_id: string;
+  group_name: string;
+  display_name: string;
+  generated_at: string;
+  total_members: number;
+  /** Members that signed in or used a session in the last active_days days */
+  active_users: number;
+  active_days: number;
+  /** Active accounts counted against the license */
+  seats_used: number;
+  licensed_seats?: number;
+  seats_available?: number;
+  over_license: boolean;
+  mfa_enabled_users: number;
+  /** Percentage of seats with two-factor authentication enabled */
+  mfa_adoption_rate: number;
+}
+
+/** Usage reports of all top-level organizations */
+export interface OrgUsageReportListResponse {
+  reports: OrgUsageReport[];
+}
+
+/** Send usage report response */
+export interface SendOrgUsageReportResponse {
+  sent: number;
+}
+
+/** Self-service sign-up restrictions */
+export interface SignupPolicy {
+  /** Only these email domains (and their subdomains) may sign up; empty allows all */
+  allowed_domains: string[];
+  /** These email domains (and their subdomains) may never sign up */
+  blocked_domains: string[];
+  /** Reject addresses from known disposable email providers */
+  block_disposable: boolean;
+  /** Reject sign-up unless the email domain is a verified auto-join organization domain */
+  invite_only: boolean;
+}
+
+/** Update sign-up policy request */
+export type UpdateSignupPolicyRequest = SignupPolicy;
+
+/** What happens to an account once it has been dormant past its notice period */
+export type DormantAction = 'suspend' | 'deactivate' | 'anonymize';
+
+/** Which accounts count as dormant and what happens to them */
+export interface DormantAccountPolicy {
+  /** Whether the background job notifies and enforces; the report works either way */
+  enabled: boolean;
+  /** Days without a sign-in or session activity after which an account is dormant */
+  inactive_days: number;
+  /** Days between the notice email and enforcement; 0 enforces without notice */
+  notice_days: number;
+  action: DormantAction;
+  /** Accounts holding any of these roles are never dormant */
+  excluded_roles: string[];
+  /** Accounts with an email address on these domains (and their subdomains) are never dormant */
+  excluded_domains: string[];
+  /** These accounts are never dormant */
+  excluded_user_ids: string[];
+}
+
+/** Update dormant account policy request */
+export type UpdateDormantAccountPolicyRequest = DormantAccountPolicy;
+
+/** Dormant account in a dry-run report */
+export interface DormantAccount {
+  user_id: string;
+  email: string;
+  username: string;
+  last_active_at: string;
+  notified_at?: string;
+  enforce_at: string;
+  /** What the next run does: send the notice, wait for the notice period or apply the action */
+  stage: 'notify' | 'notice_period' | 'enforce';
+}
+
+/** Dry run of the dormant account policy */
+export interface DormantAccountReport {
+  policy: DormantAccountPolicy;
+  generated_at: string;
+  to_notify: number;
+  in_notice_period: number;
+  to_enforce: number;
+  /** Capped; see truncated */
+  accounts: DormantAccount[];
+  truncated: boolean;
+}
+
+/** Email domain claimed by a group */
+export interface GroupDomain {
+  id: string;
+  group_id: string;
+  domain: string;
+  /** Value of the DNS TXT record that proves ownership of the domain */
+  verification_token: string;
+  /** DNS name the TXT record must be published at */
+  verification_record: string;
+  /** Add users signing up with a verified address on the domain to the group */
+  auto_join: boolean;
+  verified_at?: string;
+  created_by?: string;
+  created_at: string;
+}
+
+/** Add group domain request */
+export interface AddGroupDomainRequest {
+  domain: string;
+  /** Defaults to true */
+  auto_join?: boolean;
+}
+
+/** Group domains response */
+export interface GroupDomainListResponse {
+  domains: GroupDomain[];
+}
+
 /** Group members response */
 export interface GroupMembersResponse {
   users: Array<{
@@ -706,7 +1113,7 @@ export interface SCIMMetadataResponse {
 // ============================================
 
 /** Authentication method */
-export type AuthMethod = 'password' | 'otp_email' | 'otp_sms' | 'oauth_google' | 'oauth_github' | 'oauth_yandex' | 'oauth_telegram' | 'totp' | 'api_key';
+export type AuthMethod = 'password' | 'otp_email' | 'otp_sms' | 'oauth_google' | 'oauth_github' | 'oauth_yandex' | 'oauth_telegram' | 'oauth_apple' | 'oauth_microsoft' | 'oauth_linkedin' | 'oauth_oidc-generic' | 'totp' | 'api_key' | 'guest' | 'magic_link';
 
 /** Application entity */
 export interface Application {
@@ -2,6 +2,19 @@
  * Application OAuth Provider types
  */
 
+/**
+ * Claim names of a generic OpenID Connect provider; empty fields use the standard
+ * claims (sub, email, email_verified, name, preferred_username, picture)
+ */
+export interface OIDCClaimMapping {
+  subject?: string;
+  email?: string;
+  email_verified?: string;
+  name?: string;
+  username?: string;
+  picture?: string;
+}
+
 export interface ApplicationOAuthProvider {
   id: string;
   application_id: string;
@@ -13,6 +26,16 @@ export interface ApplicationOAuthProvider {
   auth_url: string;
   token_url: string;
   user_info_url: string;
+  /** Sign in with Apple team ID */
+  team_id?: string;
+  /** Sign in with Apple key ID */
+  key_id?: string;
+  /** Microsoft Entra ID tenant ID or domain, or common, organizations or consumers */
+  tenant_id?: string;
+  /** Issuer of a generic OpenID Connect provider (oidc-generic) */
+  issuer?: string;
+  /** Claims of a generic OpenID Connect provider that describe the user */
+  claim_mapping?: OIDCClaimMapping;
   is_active: boolean;
   created_at: string;
   updated_at: string;
@@ -27,6 +50,11 @@ export interface CreateAppOAuthProviderRequest {
   auth_url?: string;
   token_url?: string;
   user_info_url?: string;
+  team_id?: string;
+  key_id?: string;
+  tenant_id?: string;
+  issuer?: string;
+  claim_mapping?: OIDCClaimMapping;
   is_active?: boolean;
 }
 
@@ -38,5 +66,10 @@ export interface UpdateAppOAuthProviderRequest {
   auth_url?: string;
   token_url?: string;
   user_info_url?: string;
+  team_id?: string;
+  key_id?: string;
+  tenant_id?: string;
+  issuer?: string;
+  claim_mapping?: OIDCClaimMapping;
   is_active?: boolean;
 }
@@ -25,6 +25,17 @@ export interface SignUpRequest {
   account_type?: AccountType;
 }
 
+/** Request to turn the current guest account into a full account */
+export interface UpgradeGuestRequest {
+  /** Email address (optional if phone is provided) */
+  email?: string;
+  /** Phone number in E.164 format (optional if email is provided) */
+  phone?: string;
+  username: string;
+  password: string;
+  full_name?: string;
+}
+
 /** Sign in request (email-based) */
 export interface SignInEmailRequest {
   email: string;
@@ -48,6 +59,8 @@ export interface AuthResponse {
   expires_in: number;
   requires_2fa?: boolean;
   two_factor_token?: string;
+  /** Set on sign-up when the email must be verified before tokens are issued */
+  requires_email_verification?: boolean;
 }
 
 /** Refresh token request */
@@ -111,6 +124,19 @@ export interface PasswordlessVerifyRequest {
   code: string;
 }
 
+/** Magic link request */
+export interface MagicLinkRequestRequest {
+  email: string;
+  /** App URL the link lands on; defaults to the gateway's configured redirect URL */
+  redirect_url?: string;
+}
+
+/** Magic link completion request */
+export interface MagicLinkCompleteRequest {
+  /** Code from the magic link redirect */
+  code: string;
+}
+
 /** Token validation request */
 export interface TokenValidationRequest {
   access_token: string;
@@ -125,5 +151,7 @@ export interface TokenValidationResponse {
   roles?: string[];
   expires_at?: number;
   is_active?: boolean;
+  /** Anonymous guest that has not been upgraded to a full account */
+  is_guest?: boolean;
   error_message?: string;
 }
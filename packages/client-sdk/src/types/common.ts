@@ -62,7 +62,7 @@ export type UserRole = 'user' | 'moderator' | 'admin';
 export type OTPType = 'verification' | 'password_reset' | '2fa' | 'login';
 
 /** OAuth providers */
-export type OAuthProvider = 'google' | 'yandex' | 'github' | 'instagram' | 'telegram';
+export type OAuthProvider = 'google' | 'yandex' | 'github' | 'instagram' | 'telegram' | 'apple' | 'microsoft' | 'linkedin' | 'oidc-generic';
 
 /** API key scopes */
 export type APIKeyScope =
@@ -255,6 +255,94 @@ export interface OAuthError {
   error_uri?: string;
 }
 
+// Token Preview
+
+/** User and client to preview token claims for */
+export interface TokenPreviewRequest {
+  user_id: string;
+  /** Public client_id of the OAuth client */
+  client_id: string;
+  /** Space separated scopes requested at the authorize endpoint */
+  scope?: string;
+  nonce?: string;
+  /** SHA-256 thumbprint of the client certificate, for certificate-bound clients */
+  client_cert_thumbprint?: string;
+}
+
+/** Claims the gateway would issue for a user and client; nothing is signed or stored */
+export interface TokenPreviewResponse {
+  client_id: string;
+  user_id: string;
+  /** False when problems lists policies that would block issuance */
+  issuable: boolean;
+  problems?: string[];
+  granted_scopes: string[];
+  roles: string[];
+  refresh_token: boolean;
+  access_token_claims: Record<string, unknown>;
+  id_token_claims?: Record<string, unknown>;
+  userinfo_claims: Record<string, unknown>;
+}
+
+/** Webhook registered by the owner of an OAuth client; it only receives events about that client */
+export interface OAuthClientWebhook {
+  id: string;
+  client_id: string;
+  /** HTTPS endpoint the events are posted to */
+  url: string;
+  events: string[];
+  is_active: boolean;
+  created_by?: string;
+  created_at: string;
+  updated_at: string;
+  last_triggered_at?: string;
+}
+
+/** One attempt to deliver an event to an OAuth client webhook; failures are not retried */
+export interface OAuthClientWebhookDelivery {
+  id: string;
+  webhook_id: string;
+  event_type: string;
+  payload: Record<string, unknown>;
+  status: 'pending' | 'success' | 'failed';
+  http_status_code?: number;
+  response_body?: string;
+  created_at: string;
+  completed_at?: string;
+}
+
+export interface CreateOAuthClientWebhookRequest {
+  /** Must use https */
+  url: string;
+  events: string[];
+}
+
+/** Omitted fields keep their value */
+export interface UpdateOAuthClientWebhookRequest {
+  url?: string;
+  events?: string[];
+  is_active?: boolean;
+}
+
+/** Created webhook and its signing secret, which is only returned here */
+export interface CreateOAuthClientWebhookResponse {
+  webhook: OAuthClientWebhook;
+  secret_key: string;
+}
+
+export interface OAuthClientWebhookListResponse {
+  webhooks: OAuthClientWebhook[];
+  available_events: string[];
+}
+
+export interface OAuthClientWebhookDeliveryListResponse {
+  deliveries: OAuthClientWebhookDelivery[];
+  total: number;
+  page: number;
+  page_size: number;
+  total_pages: number;
+}
+
 // List Response wrappers
 
 export interface OAuthClientListResponse extends ListResponse<OAuthClient> {
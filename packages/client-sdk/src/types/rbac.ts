@@ -10,6 +10,8 @@ export interface Permission extends TimestampedEntity {
   resource: string;
   action: string;
   description?: string;
+  /** Condition the permission is granted under, checked by gRPC CheckPermission */
+  condition?: string;
 }
 
 /** Create permission request */
@@ -18,6 +20,7 @@ export interface CreatePermissionRequest {
   resource: string;
   action: string;
   description?: string;
+  condition?: string;
 }
 
 /** Role entity */
@@ -27,6 +30,10 @@ export interface Role extends TimestampedEntity {
   description?: string;
   is_system_role: boolean;
   permissions: Permission[];
+  /** IDs of the roles this role inherits permissions from */
+  parent_roles?: string[];
+  /** IDs of the permission bundles granted to the role */
+  bundles?: string[];
 }
 
 /** Create role request */
@@ -35,6 +42,8 @@ export interface CreateRoleRequest {
   display_name: string;
   description?: string;
   permissions: string[]; // Permission IDs
+  parent_roles?: string[]; // Role IDs
+  bundles?: string[]; // Permission bundle IDs
 }
 
 /** Update role request */
@@ -42,6 +51,63 @@ export interface UpdateRoleRequest {
   display_name?: string;
   description?: string;
   permissions?: string[]; // Permission IDs
+  parent_roles?: string[]; // Role IDs, replaces the current parents
+  bundles?: string[]; // Permission bundle IDs, replaces the current bundles
+}
+
+/** Named set of permissions granted to roles as a whole */
+export interface PermissionBundle extends TimestampedEntity {
+  name: string;
+  display_name: string;
+  description?: string;
+  permissions: Permission[];
+}
+
+/** Create permission bundle request */
+export interface CreatePermissionBundleRequest {
+  name: string;
+  display_name: string;
+  description?: string;
+  permissions?: string[]; // Permission IDs
+}
+
+/** Update permission bundle request */
+export interface UpdatePermissionBundleRequest {
+  display_name?: string;
+  description?: string;
+  permissions?: string[]; // Permission IDs
+}
+
+/** One way a role gets an effective permission */
+export interface PermissionSource {
+  type: 'direct' | 'bundle';
+  role_id: string;
+  role_name: string;
+  /** Granted by a role the requested role inherits from */
+  inherited: boolean;
+  bundle_id?: string;
+  bundle_name?: string;
+}
+
+/** Permission a role grants, with where it comes from */
+export interface EffectivePermission extends Permission {
+  sources: PermissionSource[];
+}
+
+/** Role another role inherits from, depth levels up */
+export interface InheritedRole {
+  id: string;
+  name: string;
+  depth: number;
+}
+
+/** Effective permissions of a role */
+export interface EffectivePermissionsResponse {
+  role_id: string;
+  role_name: string;
+  inherited_roles: InheritedRole[];
+  permissions: EffectivePermission[];
+  total: number;
 }
 
 /** Permission matrix entry */
@@ -23,6 +23,8 @@ export interface User extends TimestampedEntity {
   email_verified: boolean;
   phone_verified: boolean;
   is_active: boolean;
+  /** Anonymous guest that has not been upgraded to a full account */
+  is_guest?: boolean;
   totp_enabled: boolean;
   totp_enabled_at?: string;
 }
@@ -52,6 +54,34 @@ export interface AdminUserResponse extends User {
   last_login?: string;
 }
 
+/** Result of erasing a user's personal data; the anonymized account row is kept */
+export interface UserErasureResponse {
+  user_id: string;
+  erased_at: string;
+  /** Audit log entries stripped of IP address, user agent, location and details */
+  audit_logs_scrubbed: number;
+}
+
+/** File format of a user data export */
+export type DataExportFormat = 'json' | 'zip';
+
+/** Export of the current user's data, generated in the background */
+export interface DataExportResponse {
+  id: string;
+  status: 'pending' | 'ready' | 'failed';
+  format: DataExportFormat;
+  /** Size of the file in bytes, once ready */
+  size?: number;
+  error?: string;
+  created_at: string;
+  completed_at?: string;
+  /** When the file is deleted; a new export can be requested afterwards */
+  expires_at: string;
+  /** Signed link downloading the file without a bearer token, once ready */
+  download_url?: string;
+  download_url_expires_at?: string;
+}
+
 /** Admin user update request (snake_case for backend API) */
 export interface AdminUpdateUserRequest {
   role_ids?: string[];
@@ -86,6 +116,8 @@ export interface AdminUserListResponse {
   page: number;
   page_size: number;
   total_pages: number;
+  /** Cursor of the next page when there are more users; pages listed by cursor are not counted */
+  next_cursor?: string;
 }
 
 /** Admin statistics response */
@@ -0,0 +1,75 @@
+/**
+ * SDK version and server compatibility
+ */
+
+import type { ServerVersionResponse } from './types/admin';
+
+/** Version of this SDK */
+export const SDK_VERSION = '1.0.0';
+
+/** Version of the Auth Gateway API this SDK speaks */
+export const API_VERSION = 'v1';
+
+/** Oldest server release with every endpoint this SDK calls */
+export const MIN_SERVER_VERSION = 'v1.0.0';
+
+/** Result of a server compatibility check */
+export interface CompatibilityResult {
+  compatible: boolean;
+  /** Server version, 'unknown' when the server predates /version */
+  serverVersion: string;
+  /** Why the server is not compatible */
+  reason?: string;
+}
+
+/**
+ * Parse the major, minor and patch numbers of a semantic version.
+ * Pre-release and git describe suffixes are ignored.
+ */
+function parseVersion(version: string): [number, number, number] | null {
+  const match = /^v?(\d+)\.(\d+)\.(\d+)(?:[-+].*)?$/.exec(version);
+  if (!match) {
+    return null;
+  }
+  return [Number(match[1]), Number(match[2]), Number(match[3])];
+}
+
+/**
+ * Check whether semantic version a is older than b
+ * @returns null when either version can't be parsed
+ */
+export function isVersionOlder(a: string, b: string): boolean | null {
+  const va = parseVersion(a);
+  const vb = parseVersion(b);
+  if (!va || !vb) {
+    return null;
+  }
+  for (let i = 0; i < 3; i++) {
+    if (va[i] !== vb[i]) {
+      return va[i] < vb[i];
+    }
+  }
+  return false;
+}
+
+/**
+ * Check a server version against what this SDK needs.
+ * Development builds, whose version is not semantic, are assumed to be compatible.
+ */
+export function checkServerCompatibility(info: ServerVersionResponse): CompatibilityResult {
+  if (!info.api_versions.includes(API_VERSION)) {
+    return {
+      compatible: false,
+      serverVersion: info.version,
+      reason: `the server serves API versions ${info.api_versions.join(', ')}, not ${API_VERSION}`,
+    };
+  }
+  if (isVersionOlder(info.version, MIN_SERVER_VERSION)) {
+    return {
+      compatible: false,
+      serverVersion: info.version,
+      reason: `the SDK needs server ${MIN_SERVER_VERSION} or later`,
+    };
+  }
+  return { compatible: true, serverVersion: info.version };
+}
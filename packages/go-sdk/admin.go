@@ -3,6 +3,8 @@ package authgateway
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 
 	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
 )
@@ -11,6 +13,9 @@ import (
 // All methods require admin privileges.
 type AdminService struct {
 	client *Client
+
+	// OAuthClients manages the clients and scopes of the OAuth 2.0 / OIDC provider.
+	OAuthClients *AdminOAuthClientsService
 }
 
 // --- Statistics ---
@@ -72,6 +77,16 @@ func (s *AdminService) DeleteUser(ctx context.Context, id string) error {
 	return s.client.delete(ctx, fmt.Sprintf("/api/admin/users/%s", id), nil)
 }
 
+// EraseUser erases the personal data of a user (GDPR erasure) and deletes the account.
+// Administrators cannot be erased.
+func (s *AdminService) EraseUser(ctx context.Context, id string) (*models.UserErasure, error) {
+	var resp models.UserErasure
+	if err := s.client.delete(ctx, fmt.Sprintf("/api/admin/users/%s/gdpr-erase", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // AssignRole assigns a role to a user.
 func (s *AdminService) AssignRole(ctx context.Context, userID, roleID string) (*models.MessageResponse, error) {
 	req := &models.AssignRoleRequest{RoleID: roleID}
@@ -92,15 +107,46 @@ func (s *AdminService) RemoveRole(ctx context.Context, userID, roleID string) (*
 	return &resp, nil
 }
 
+// StartBulkRoleJob starts a background job that assigns a role to, or removes it
+// from, every user matching a query, every member of a group, or a list of user IDs.
+// Poll GetBulkRoleJob for progress and per-user failures.
+func (s *AdminService) StartBulkRoleJob(ctx context.Context, req *models.BulkRoleJobRequest) (*models.BulkRoleJob, error) {
+	var resp models.BulkRoleJob
+	if err := s.client.post(ctx, "/api/admin/users/bulk-role-jobs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetBulkRoleJob retrieves the status, progress and failures of a bulk role job.
+func (s *AdminService) GetBulkRoleJob(ctx context.Context, id string) (*models.BulkRoleJob, error) {
+	var resp models.BulkRoleJob
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/users/bulk-role-jobs/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListBulkRoleJobs retrieves the most recent bulk role jobs.
+func (s *AdminService) ListBulkRoleJobs(ctx context.Context) (*models.BulkRoleJobListResponse, error) {
+	var resp models.BulkRoleJobListResponse
+	if err := s.client.get(ctx, "/api/admin/users/bulk-role-jobs", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // --- RBAC Management ---
 
 // ListPermissions retrieves all permissions.
 func (s *AdminService) ListPermissions(ctx context.Context) ([]models.Permission, error) {
-	var resp []models.Permission
+	var resp struct {
+		Permissions []models.Permission `json:"permissions"`
+	}
 	if err := s.client.get(ctx, "/api/admin/rbac/permissions", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.Permissions, nil
 }
 
 // CreatePermission creates a new permission.
@@ -114,11 +160,13 @@ func (s *AdminService) CreatePermission(ctx context.Context, req *models.CreateP
 
 // ListRoles retrieves all roles.
 func (s *AdminService) ListRoles(ctx context.Context) ([]models.Role, error) {
-	var resp []models.Role
+	var resp struct {
+		Roles []models.Role `json:"roles"`
+	}
 	if err := s.client.get(ctx, "/api/admin/rbac/roles", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.Roles, nil
 }
 
 // CreateRole creates a new role.
@@ -153,6 +201,59 @@ func (s *AdminService) DeleteRole(ctx context.Context, id string) error {
 	return s.client.delete(ctx, fmt.Sprintf("/api/admin/rbac/roles/%s", id), nil)
 }
 
+// GetEffectivePermissions retrieves every permission a role grants, including those of
+// its permission bundles and of the roles it inherits from, with their sources.
+func (s *AdminService) GetEffectivePermissions(ctx context.Context, roleID string) (*models.EffectivePermissionsResponse, error) {
+	var resp models.EffectivePermissionsResponse
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/rbac/roles/%s/effective-permissions", roleID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListPermissionBundles retrieves all permission bundles.
+func (s *AdminService) ListPermissionBundles(ctx context.Context) ([]models.PermissionBundle, error) {
+	var resp struct {
+		Bundles []models.PermissionBundle `json:"bundles"`
+	}
+	if err := s.client.get(ctx, "/api/admin/rbac/bundles", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Bundles, nil
+}
+
+// CreatePermissionBundle creates a new permission bundle.
+func (s *AdminService) CreatePermissionBundle(ctx context.Context, req *models.CreatePermissionBundleRequest) (*models.PermissionBundle, error) {
+	var resp models.PermissionBundle
+	if err := s.client.post(ctx, "/api/admin/rbac/bundles", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPermissionBundle retrieves a permission bundle by ID.
+func (s *AdminService) GetPermissionBundle(ctx context.Context, id string) (*models.PermissionBundle, error) {
+	var resp models.PermissionBundle
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/rbac/bundles/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdatePermissionBundle updates a permission bundle.
+func (s *AdminService) UpdatePermissionBundle(ctx context.Context, id string, req *models.UpdatePermissionBundleRequest) (*models.PermissionBundle, error) {
+	var resp models.PermissionBundle
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/rbac/bundles/%s", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeletePermissionBundle deletes a permission bundle.
+func (s *AdminService) DeletePermissionBundle(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/rbac/bundles/%s", id), nil)
+}
+
 // GetPermissionMatrix retrieves the permission matrix for UI.
 func (s *AdminService) GetPermissionMatrix(ctx context.Context) (*models.PermissionMatrixResponse, error) {
 	var resp models.PermissionMatrixResponse
@@ -162,15 +263,37 @@ func (s *AdminService) GetPermissionMatrix(ctx context.Context) (*models.Permiss
 	return &resp, nil
 }
 
+// GetPermissionCatalog retrieves every known resource/action pair with the roles
+// that grant it, including pairs registered by services.
+func (s *AdminService) GetPermissionCatalog(ctx context.Context) (*models.PermissionCatalogResponse, error) {
+	var resp models.PermissionCatalogResponse
+	if err := s.client.get(ctx, "/api/admin/rbac/catalog", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SimulatePermission evaluates whether a user may perform an action on a resource
+// and returns the decision trace explaining why access was allowed or denied.
+func (s *AdminService) SimulatePermission(ctx context.Context, req *models.SimulatePermissionRequest) (*models.SimulatePermissionResponse, error) {
+	var resp models.SimulatePermissionResponse
+	if err := s.client.post(ctx, "/api/admin/rbac/simulate", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // --- API Keys Management ---
 
 // ListAllAPIKeys retrieves all API keys across all users.
 func (s *AdminService) ListAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
-	var resp []models.APIKey
+	var resp struct {
+		APIKeys []models.APIKey `json:"api_keys"`
+	}
 	if err := s.client.get(ctx, "/api/admin/api-keys", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.APIKeys, nil
 }
 
 // RevokeUserAPIKey revokes a user's API key.
@@ -198,6 +321,34 @@ func (s *AdminService) ListAuditLogs(ctx context.Context, params *models.ListAud
 	return &resp, nil
 }
 
+// GetAuditActionStats retrieves the most frequent audit actions with how many did not succeed.
+func (s *AdminService) GetAuditActionStats(ctx context.Context, params *models.AuditStatsParams) (*models.AuditActionStats, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.AuditActionStats
+	if err := s.client.get(ctx, "/api/admin/audit-logs/stats/actions"+query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAuditFailureStats retrieves the number of unsuccessful audit actions per hour.
+func (s *AdminService) GetAuditFailureStats(ctx context.Context, params *models.AuditStatsParams) (*models.AuditFailureStats, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.AuditFailureStats
+	if err := s.client.get(ctx, "/api/admin/audit-logs/stats/failures"+query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // --- Session Management ---
 
 // ListAllSessions retrieves all sessions across all users.
@@ -227,11 +378,13 @@ func (s *AdminService) GetSessionStats(ctx context.Context) (*models.SessionStat
 
 // ListIPFilters retrieves all IP filters.
 func (s *AdminService) ListIPFilters(ctx context.Context) ([]models.IPFilter, error) {
-	var resp []models.IPFilter
+	var resp struct {
+		Filters []models.IPFilter `json:"filters"`
+	}
 	if err := s.client.get(ctx, "/api/admin/ip-filters", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.Filters, nil
 }
 
 // CreateIPFilter creates a new IP filter.
@@ -250,9 +403,18 @@ func (s *AdminService) DeleteIPFilter(ctx context.Context, id string) error {
 
 // --- System Configuration ---
 
-// UpdateBranding updates branding settings.
-func (s *AdminService) UpdateBranding(ctx context.Context, req *models.UpdateBrandingRequest) (*models.MessageResponse, error) {
-	var resp models.MessageResponse
+// GetBranding retrieves the branding shown on the gateway's pages.
+func (s *AdminService) GetBranding(ctx context.Context) (*models.PublicBranding, error) {
+	var resp models.PublicBranding
+	if err := s.client.get(ctx, "/api/admin/branding", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateBranding updates branding settings and returns them.
+func (s *AdminService) UpdateBranding(ctx context.Context, req *models.UpdateBrandingRequest) (*models.BrandingSettings, error) {
+	var resp models.BrandingSettings
 	if err := s.client.put(ctx, "/api/admin/branding", req, &resp); err != nil {
 		return nil, err
 	}
@@ -260,14 +422,25 @@ func (s *AdminService) UpdateBranding(ctx context.Context, req *models.UpdateBra
 }
 
 // SetMaintenanceMode enables or disables maintenance mode.
-func (s *AdminService) SetMaintenanceMode(ctx context.Context, req *models.MaintenanceModeRequest) (*models.MessageResponse, error) {
-	var resp models.MessageResponse
+func (s *AdminService) SetMaintenanceMode(ctx context.Context, req *models.MaintenanceModeRequest) (*models.MaintenanceStatus, error) {
+	var resp models.MaintenanceStatus
 	if err := s.client.put(ctx, "/api/admin/system/maintenance", req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// EnableMaintenanceMode puts the gateway in maintenance mode. An empty message keeps
+// the current one.
+func (s *AdminService) EnableMaintenanceMode(ctx context.Context, message string) (*models.MaintenanceStatus, error) {
+	return s.SetMaintenanceMode(ctx, &models.MaintenanceModeRequest{Enabled: true, Message: message})
+}
+
+// DisableMaintenanceMode takes the gateway out of maintenance mode.
+func (s *AdminService) DisableMaintenanceMode(ctx context.Context) (*models.MaintenanceStatus, error) {
+	return s.SetMaintenanceMode(ctx, &models.MaintenanceModeRequest{Enabled: false})
+}
+
 // GetSystemHealth retrieves detailed system health information.
 func (s *AdminService) GetSystemHealth(ctx context.Context) (*models.HealthStatus, error) {
 	var resp models.HealthStatus
@@ -277,120 +450,420 @@ func (s *AdminService) GetSystemHealth(ctx context.Context) (*models.HealthStatu
 	return &resp, nil
 }
 
-// --- Analytics ---
+// ListSLOs retrieves the built-in SLOs with their error budgets, as counted by the
+// instance answering.
+func (s *AdminService) ListSLOs(ctx context.Context) (*models.SLOList, error) {
+	var resp models.SLOList
+	if err := s.client.get(ctx, "/api/admin/slos", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
 
-// GetGeoDistribution retrieves user geographic distribution.
-func (s *AdminService) GetGeoDistribution(ctx context.Context) ([]models.GeoDistribution, error) {
-	var resp []models.GeoDistribution
-	if err := s.client.get(ctx, "/api/admin/analytics/geo-distribution", &resp); err != nil {
+// GetAuthorizationMap retrieves what every HTTP route and gRPC method of the gateway
+// requires of its caller, generated from its router and middleware registration.
+func (s *AdminService) GetAuthorizationMap(ctx context.Context) (*models.AuthorizationMap, error) {
+	var resp models.AuthorizationMap
+	if err := s.client.get(ctx, "/api/admin/authorization-map", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PreviewTokens returns the claims of the access token, ID token and userinfo response
+// the gateway would issue to a user through an OAuth client, and the policies that would
+// block issuance. Nothing is signed or stored.
+func (s *AdminService) PreviewTokens(ctx context.Context, req *models.TokenPreviewRequest) (*models.TokenPreviewResponse, error) {
+	var resp models.TokenPreviewResponse
+	if err := s.client.post(ctx, "/api/admin/debug/token-preview", req, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
-// --- OAuth Client Management ---
+// --- Background jobs ---
 
-// CreateOAuthClient creates a new OAuth client.
-func (s *AdminService) CreateOAuthClient(ctx context.Context, req *models.CreateOAuthClientRequest) (*models.CreateOAuthClientResponse, error) {
-	var resp models.CreateOAuthClientResponse
-	if err := s.client.post(ctx, "/api/admin/oauth/clients", req, &resp); err != nil {
+// ListBackgroundJobs retrieves jobs of the background job queue, newest first.
+func (s *AdminService) ListBackgroundJobs(ctx context.Context, params *models.ListBackgroundJobsParams) (*models.BackgroundJobListResponse, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.BackgroundJobListResponse
+	if err := s.client.get(ctx, "/api/admin/jobs"+query, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// ListOAuthClients lists OAuth clients with pagination.
-func (s *AdminService) ListOAuthClients(ctx context.Context, page, perPage int, ownerID *string) (*models.ListOAuthClientsResponse, error) {
-	params := map[string]string{
-		"page":     fmt.Sprintf("%d", page),
-		"per_page": fmt.Sprintf("%d", perPage),
+// GetBackgroundJobStats counts the jobs of the background job queue by status.
+func (s *AdminService) GetBackgroundJobStats(ctx context.Context) (*models.BackgroundJobStats, error) {
+	var resp models.BackgroundJobStats
+	if err := s.client.get(ctx, "/api/admin/jobs/stats", &resp); err != nil {
+		return nil, err
 	}
-	if ownerID != nil {
-		params["owner_id"] = *ownerID
+	return &resp, nil
+}
+
+// GetBackgroundJob retrieves a background job with its payload and last error.
+func (s *AdminService) GetBackgroundJob(ctx context.Context, id string) (*models.BackgroundJob, error) {
+	var resp models.BackgroundJob
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/jobs/%s", id), &resp); err != nil {
+		return nil, err
 	}
+	return &resp, nil
+}
 
-	path := "/api/admin/oauth/clients"
-	if len(params) > 0 {
-		query := buildQueryStringFromMap(params)
-		path += query
+// RetryBackgroundJob requeues a dead background job with a fresh set of attempts.
+func (s *AdminService) RetryBackgroundJob(ctx context.Context, id string) (*models.BackgroundJob, error) {
+	var resp models.BackgroundJob
+	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/jobs/%s/retry", id), nil, &resp); err != nil {
+		return nil, err
 	}
+	return &resp, nil
+}
 
-	var resp models.ListOAuthClientsResponse
-	if err := s.client.get(ctx, path, &resp); err != nil {
+// --- Audit export ---
+
+// GetAuditExportStatus lists the sinks audit logs are exported to.
+func (s *AdminService) GetAuditExportStatus(ctx context.Context) (*models.AuditExportStatus, error) {
+	var resp models.AuditExportStatus
+	if err := s.client.get(ctx, "/api/admin/audit-export", &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetOAuthClient retrieves an OAuth client by ID.
-func (s *AdminService) GetOAuthClient(ctx context.Context, id string) (*models.OAuthClient, error) {
-	var resp models.OAuthClient
-	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), &resp); err != nil {
+// ReplayAuditExport queues jobs exporting the audit logs of a past range to a sink again.
+// The jobs run in the background job queue as audit.export.
+func (s *AdminService) ReplayAuditExport(ctx context.Context, req *models.AuditExportReplayRequest) (*models.AuditExportReplayResponse, error) {
+	var resp models.AuditExportReplayResponse
+	if err := s.client.post(ctx, "/api/admin/audit-export/replay", req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// UpdateOAuthClient updates an OAuth client.
-func (s *AdminService) UpdateOAuthClient(ctx context.Context, id string, req *models.UpdateOAuthClientRequest) (*models.OAuthClient, error) {
-	var resp models.OAuthClient
-	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), req, &resp); err != nil {
+// --- Webhooks ---
+
+// ListWebhooks retrieves webhooks with pagination.
+func (s *AdminService) ListWebhooks(ctx context.Context, params *models.ListWebhooksParams) (*models.WebhookListResponse, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.WebhookListResponse
+	if err := s.client.get(ctx, "/api/admin/webhooks"+query, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// DeleteOAuthClient deletes an OAuth client.
-func (s *AdminService) DeleteOAuthClient(ctx context.Context, id string) error {
-	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), nil)
+// CreateWebhook creates a webhook. The response holds the secret deliveries are signed
+// with; it cannot be retrieved later.
+func (s *AdminService) CreateWebhook(ctx context.Context, req *models.CreateWebhookRequest) (*models.CreateWebhookResponse, error) {
+	var resp models.CreateWebhookResponse
+	if err := s.client.post(ctx, "/api/admin/webhooks", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
-// RotateOAuthClientSecret rotates an OAuth client's secret.
-func (s *AdminService) RotateOAuthClientSecret(ctx context.Context, id string) (*models.RotateSecretResponse, error) {
-	var resp models.RotateSecretResponse
-	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/rotate-secret", id), nil, &resp); err != nil {
+// GetWebhook retrieves a webhook by ID.
+func (s *AdminService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	var resp models.Webhook
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/webhooks/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateWebhook updates a webhook.
+func (s *AdminService) UpdateWebhook(ctx context.Context, id string, req *models.UpdateWebhookRequest) (*models.MessageResponse, error) {
+	var resp models.MessageResponse
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/webhooks/%s", id), req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// --- OAuth Scope Management ---
+// DeleteWebhook deletes a webhook.
+func (s *AdminService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/webhooks/%s", id), nil)
+}
 
-// ListOAuthScopes lists all OAuth scopes.
-func (s *AdminService) ListOAuthScopes(ctx context.Context) (*models.ListScopesResponse, error) {
-	var resp models.ListScopesResponse
-	if err := s.client.get(ctx, "/api/admin/oauth/scopes", &resp); err != nil {
+// TestWebhook sends a test event to a webhook.
+func (s *AdminService) TestWebhook(ctx context.Context, id string, req *models.TestWebhookRequest) (*models.MessageResponse, error) {
+	var resp models.MessageResponse
+	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/webhooks/%s/test", id), req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// CreateOAuthScope creates a custom OAuth scope.
-func (s *AdminService) CreateOAuthScope(ctx context.Context, req *models.CreateScopeRequest) (*models.OAuthScope, error) {
-	var resp models.OAuthScope
-	if err := s.client.post(ctx, "/api/admin/oauth/scopes", req, &resp); err != nil {
+// ListWebhookDeliveries retrieves the delivery attempts of a webhook, newest first.
+func (s *AdminService) ListWebhookDeliveries(ctx context.Context, id string, params *models.ListWebhookDeliveriesParams) (*models.WebhookDeliveryListResponse, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.WebhookDeliveryListResponse
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/webhooks/%s/deliveries", id)+query, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// RedeliverWebhookDelivery sends the payload of a past delivery again. It returns the new
+// delivery, which is sent and retried in the background.
+func (s *AdminService) RedeliverWebhookDelivery(ctx context.Context, id, deliveryID string) (*models.WebhookDelivery, error) {
+	var resp models.WebhookDelivery
+	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/webhooks/%s/deliveries/%s/redeliver", id, deliveryID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListWebhookEvents retrieves the event types webhooks can subscribe to.
+func (s *AdminService) ListWebhookEvents(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Events []string `json:"events"`
+	}
+	if err := s.client.get(ctx, "/api/admin/webhooks/events", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
+}
+
+// --- Email Templates ---
+
+// ListEmailTemplates retrieves all email templates.
+func (s *AdminService) ListEmailTemplates(ctx context.Context) (*models.EmailTemplateListResponse, error) {
+	var resp models.EmailTemplateListResponse
+	if err := s.client.get(ctx, "/api/admin/templates", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateEmailTemplate creates an email template.
+func (s *AdminService) CreateEmailTemplate(ctx context.Context, req *models.CreateEmailTemplateRequest) (*models.EmailTemplate, error) {
+	var resp models.EmailTemplate
+	if err := s.client.post(ctx, "/api/admin/templates", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetEmailTemplate retrieves an email template by ID.
+func (s *AdminService) GetEmailTemplate(ctx context.Context, id string) (*models.EmailTemplate, error) {
+	var resp models.EmailTemplate
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/templates/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateEmailTemplate updates an email template.
+func (s *AdminService) UpdateEmailTemplate(ctx context.Context, id string, req *models.UpdateEmailTemplateRequest) (*models.MessageResponse, error) {
+	var resp models.MessageResponse
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/templates/%s", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteEmailTemplate deletes an email template.
+func (s *AdminService) DeleteEmailTemplate(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/templates/%s", id), nil)
+}
+
+// PreviewEmailTemplate renders template bodies with sample variables without saving them.
+func (s *AdminService) PreviewEmailTemplate(ctx context.Context, req *models.PreviewEmailTemplateRequest) (*models.PreviewEmailTemplateResponse, error) {
+	var resp models.PreviewEmailTemplateResponse
+	if err := s.client.post(ctx, "/api/admin/templates/preview", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListEmailTemplateTypes retrieves the template types emails are sent for.
+func (s *AdminService) ListEmailTemplateTypes(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Types []string `json:"types"`
+	}
+	if err := s.client.get(ctx, "/api/admin/templates/types", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Types, nil
+}
+
+// GetEmailTemplateVariables retrieves the variables available to templates of a type.
+func (s *AdminService) GetEmailTemplateVariables(ctx context.Context, templateType string) ([]string, error) {
+	var resp struct {
+		Variables []string `json:"variables"`
+	}
+	if err := s.client.get(ctx, "/api/admin/templates/variables/"+url.PathEscape(templateType), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Variables, nil
+}
+
+// --- Analytics ---
+
+// GetGeoDistribution retrieves the geographic distribution of logins.
+func (s *AdminService) GetGeoDistribution(ctx context.Context) (*models.GeoDistribution, error) {
+	var resp models.GeoDistribution
+	if err := s.client.get(ctx, "/api/admin/analytics/geo-distribution", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// --- OAuth Clients ---
+//
+// Deprecated wrappers; use AdminService.OAuthClients.
+
+// CreateOAuthClient creates a new OAuth client.
+//
+// Deprecated: Use OAuthClients.Create.
+func (s *AdminService) CreateOAuthClient(ctx context.Context, req *models.CreateOAuthClientRequest) (*models.CreateOAuthClientResponse, error) {
+	return s.OAuthClients.Create(ctx, req)
+}
+
+// ListOAuthClients lists OAuth clients with pagination.
+//
+// Deprecated: Use OAuthClients.List.
+func (s *AdminService) ListOAuthClients(ctx context.Context, page, perPage int, ownerID *string) (*models.ListOAuthClientsResponse, error) {
+	params := &models.ListOAuthClientsParams{Page: page, PageSize: perPage}
+	if ownerID != nil {
+		params.OwnerID = *ownerID
+	}
+	return s.OAuthClients.List(ctx, params)
+}
+
+// GetOAuthClient retrieves an OAuth client by ID.
+//
+// Deprecated: Use OAuthClients.Get.
+func (s *AdminService) GetOAuthClient(ctx context.Context, id string) (*models.OAuthClient, error) {
+	return s.OAuthClients.Get(ctx, id)
+}
+
+// UpdateOAuthClient updates an OAuth client.
+//
+// Deprecated: Use OAuthClients.Update.
+func (s *AdminService) UpdateOAuthClient(ctx context.Context, id string, req *models.UpdateOAuthClientRequest) (*models.OAuthClient, error) {
+	return s.OAuthClients.Update(ctx, id, req)
+}
+
+// DeleteOAuthClient deletes an OAuth client.
+//
+// Deprecated: Use OAuthClients.Delete.
+func (s *AdminService) DeleteOAuthClient(ctx context.Context, id string) error {
+	return s.OAuthClients.Delete(ctx, id)
+}
+
+// RotateOAuthClientSecret rotates an OAuth client's secret.
+//
+// Deprecated: Use OAuthClients.RotateSecret.
+func (s *AdminService) RotateOAuthClientSecret(ctx context.Context, id string) (*models.RotateSecretResponse, error) {
+	return s.OAuthClients.RotateSecret(ctx, id)
+}
+
+// UploadOAuthClientLogo uploads a logo for an OAuth client.
+//
+// Deprecated: Use OAuthClients.UploadLogo.
+func (s *AdminService) UploadOAuthClientLogo(ctx context.Context, id string, logo io.Reader) (*models.OAuthClient, error) {
+	return s.OAuthClients.UploadLogo(ctx, id, logo)
+}
+
+// DeleteOAuthClientLogo removes the uploaded logo of an OAuth client.
+//
+// Deprecated: Use OAuthClients.DeleteLogo.
+func (s *AdminService) DeleteOAuthClientLogo(ctx context.Context, id string) (*models.OAuthClient, error) {
+	return s.OAuthClients.DeleteLogo(ctx, id)
+}
+
+// EmulateOAuthClient emulates the authorization flow of an OAuth client.
+//
+// Deprecated: Use OAuthClients.Emulate.
+func (s *AdminService) EmulateOAuthClient(ctx context.Context, id string, req *models.EmulateOAuthClientRequest) (*models.EmulateOAuthClientResponse, error) {
+	return s.OAuthClients.Emulate(ctx, id, req)
+}
+
+// ListOAuthScopes lists all OAuth scopes.
+//
+// Deprecated: Use OAuthClients.ListScopes.
+func (s *AdminService) ListOAuthScopes(ctx context.Context) (*models.ListScopesResponse, error) {
+	return s.OAuthClients.ListScopes(ctx)
+}
+
+// CreateOAuthScope creates a custom OAuth scope.
+//
+// Deprecated: Use OAuthClients.CreateScope.
+func (s *AdminService) CreateOAuthScope(ctx context.Context, req *models.CreateScopeRequest) (*models.OAuthScope, error) {
+	return s.OAuthClients.CreateScope(ctx, req)
+}
+
 // DeleteOAuthScope deletes a non-system OAuth scope.
+//
+// Deprecated: Use OAuthClients.DeleteScope.
 func (s *AdminService) DeleteOAuthScope(ctx context.Context, id string) error {
-	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/scopes/%s", id), nil)
+	return s.OAuthClients.DeleteScope(ctx, id)
 }
 
-// --- User Consent Management ---
+// ListOAuthScopeGroups lists all OAuth scope groups.
+//
+// Deprecated: Use OAuthClients.ListScopeGroups.
+func (s *AdminService) ListOAuthScopeGroups(ctx context.Context) (*models.ListScopeGroupsResponse, error) {
+	return s.OAuthClients.ListScopeGroups(ctx)
+}
+
+// CreateOAuthScopeGroup creates an OAuth scope group.
+//
+// Deprecated: Use OAuthClients.CreateScopeGroup.
+func (s *AdminService) CreateOAuthScopeGroup(ctx context.Context, req *models.CreateScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	return s.OAuthClients.CreateScopeGroup(ctx, req)
+}
+
+// UpdateOAuthScopeGroup updates an OAuth scope group.
+//
+// Deprecated: Use OAuthClients.UpdateScopeGroup.
+func (s *AdminService) UpdateOAuthScopeGroup(ctx context.Context, id string, req *models.UpdateScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	return s.OAuthClients.UpdateScopeGroup(ctx, id, req)
+}
+
+// DeleteOAuthScopeGroup deletes an OAuth scope group.
+//
+// Deprecated: Use OAuthClients.DeleteScopeGroup.
+func (s *AdminService) DeleteOAuthScopeGroup(ctx context.Context, id string) error {
+	return s.OAuthClients.DeleteScopeGroup(ctx, id)
+}
 
 // ListOAuthClientConsents lists all user consents for an OAuth client.
+//
+// Deprecated: Use OAuthClients.ListConsents.
 func (s *AdminService) ListOAuthClientConsents(ctx context.Context, clientID string) (*models.ListConsentsResponse, error) {
-	var resp models.ListConsentsResponse
-	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/consents", clientID), &resp); err != nil {
-		return nil, err
-	}
-	return &resp, nil
+	return s.OAuthClients.ListConsents(ctx, clientID)
 }
 
 // RevokeOAuthUserConsent revokes a user's consent for an OAuth client.
+//
+// Deprecated: Use OAuthClients.RevokeConsent.
 func (s *AdminService) RevokeOAuthUserConsent(ctx context.Context, clientID, userID string) error {
-	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/consents/%s", clientID, userID), nil)
+	return s.OAuthClients.RevokeConsent(ctx, clientID, userID)
+}
+
+// --- OIDC Provider ---
+
+// RunOIDCConformance runs the OIDC provider conformance checks against the server.
+func (s *AdminService) RunOIDCConformance(ctx context.Context, req *models.OIDCConformanceRequest) (*models.OIDCConformanceReport, error) {
+	var resp models.OIDCConformanceReport
+	if err := s.client.post(ctx, "/api/admin/oauth/conformance", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
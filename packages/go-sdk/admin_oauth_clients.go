@@ -0,0 +1,181 @@
+package authgateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+// AdminOAuthClientsService manages the OAuth clients and scopes of Auth Gateway's
+// OAuth 2.0 / OIDC provider. All methods require admin privileges.
+//
+// Client secrets are returned only by Create and RotateSecret; the server stores
+// a hash, so a lost secret has to be rotated.
+type AdminOAuthClientsService struct {
+	client *Client
+}
+
+// --- Clients ---
+
+// Create registers an OAuth client. For confidential clients the response holds the
+// client secret, which cannot be retrieved later.
+func (s *AdminOAuthClientsService) Create(ctx context.Context, req *models.CreateOAuthClientRequest) (*models.CreateOAuthClientResponse, error) {
+	var resp models.CreateOAuthClientResponse
+	if err := s.client.post(ctx, "/api/admin/oauth/clients", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// List retrieves OAuth clients with pagination.
+func (s *AdminOAuthClientsService) List(ctx context.Context, params *models.ListOAuthClientsParams) (*models.ListOAuthClientsResponse, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.ListOAuthClientsResponse
+	if err := s.client.get(ctx, "/api/admin/oauth/clients"+query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Get retrieves an OAuth client by ID.
+func (s *AdminOAuthClientsService) Get(ctx context.Context, id string) (*models.OAuthClient, error) {
+	var resp models.OAuthClient
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Update updates an OAuth client. Fields left nil or empty are kept.
+func (s *AdminOAuthClientsService) Update(ctx context.Context, id string, req *models.UpdateOAuthClientRequest) (*models.OAuthClient, error) {
+	var resp models.OAuthClient
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Delete deletes an OAuth client.
+func (s *AdminOAuthClientsService) Delete(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s", id), nil)
+}
+
+// RotateSecret replaces the secret of a confidential client. The old secret stops
+// working at once; the new one is only in the response.
+func (s *AdminOAuthClientsService) RotateSecret(ctx context.Context, id string) (*models.RotateSecretResponse, error) {
+	var resp models.RotateSecretResponse
+	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/rotate-secret", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UploadLogo uploads a PNG, JPEG, GIF or WebP logo for an OAuth client.
+// The server detects the format from the content and sets the client's LogoURL
+// to a URL it serves the logo from.
+func (s *AdminOAuthClientsService) UploadLogo(ctx context.Context, id string, logo io.Reader) (*models.OAuthClient, error) {
+	var resp models.OAuthClient
+	body := &rawBody{contentType: "application/octet-stream", reader: logo}
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/logo", id), body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteLogo removes the uploaded logo of an OAuth client.
+func (s *AdminOAuthClientsService) DeleteLogo(ctx context.Context, id string) (*models.OAuthClient, error) {
+	var resp models.OAuthClient
+	if err := s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/logo", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Emulate walks an OAuth client through authorize, consent and token issuance
+// with a synthetic user and returns the trace. Nothing is stored and no tokens are issued.
+func (s *AdminOAuthClientsService) Emulate(ctx context.Context, id string, req *models.EmulateOAuthClientRequest) (*models.EmulateOAuthClientResponse, error) {
+	var resp models.EmulateOAuthClientResponse
+	if err := s.client.post(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/emulate", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// --- Consents ---
+
+// ListConsents lists the user consents given to an OAuth client.
+func (s *AdminOAuthClientsService) ListConsents(ctx context.Context, clientID string) (*models.ListConsentsResponse, error) {
+	var resp models.ListConsentsResponse
+	if err := s.client.get(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/consents", clientID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeConsent revokes a user's consent for an OAuth client.
+func (s *AdminOAuthClientsService) RevokeConsent(ctx context.Context, clientID, userID string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/clients/%s/consents/%s", clientID, userID), nil)
+}
+
+// --- Scopes ---
+
+// ListScopes lists all OAuth scopes.
+func (s *AdminOAuthClientsService) ListScopes(ctx context.Context) (*models.ListScopesResponse, error) {
+	var resp models.ListScopesResponse
+	if err := s.client.get(ctx, "/api/admin/oauth/scopes", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateScope creates a custom OAuth scope.
+func (s *AdminOAuthClientsService) CreateScope(ctx context.Context, req *models.CreateScopeRequest) (*models.OAuthScope, error) {
+	var resp models.OAuthScope
+	if err := s.client.post(ctx, "/api/admin/oauth/scopes", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteScope deletes a non-system OAuth scope.
+func (s *AdminOAuthClientsService) DeleteScope(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/scopes/%s", id), nil)
+}
+
+// ListScopeGroups lists all OAuth scope groups with the scopes each expands to.
+func (s *AdminOAuthClientsService) ListScopeGroups(ctx context.Context) (*models.ListScopeGroupsResponse, error) {
+	var resp models.ListScopeGroupsResponse
+	if err := s.client.get(ctx, "/api/admin/oauth/scope-groups", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateScopeGroup creates an OAuth scope group.
+func (s *AdminOAuthClientsService) CreateScopeGroup(ctx context.Context, req *models.CreateScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	var resp models.OAuthScopeGroup
+	if err := s.client.post(ctx, "/api/admin/oauth/scope-groups", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateScopeGroup updates an OAuth scope group.
+func (s *AdminOAuthClientsService) UpdateScopeGroup(ctx context.Context, id string, req *models.UpdateScopeGroupRequest) (*models.OAuthScopeGroup, error) {
+	var resp models.OAuthScopeGroup
+	if err := s.client.put(ctx, fmt.Sprintf("/api/admin/oauth/scope-groups/%s", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteScopeGroup deletes an OAuth scope group.
+func (s *AdminOAuthClientsService) DeleteScopeGroup(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/admin/oauth/scope-groups/%s", id), nil)
+}
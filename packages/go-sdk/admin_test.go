@@ -0,0 +1,307 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+func TestAdminService_Webhooks(t *testing.T) {
+	t.Run("ShouldReturnSecretOfCreatedWebhook", func(t *testing.T) {
+		// Arrange
+		var got models.CreateWebhookRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/admin/webhooks", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"webhook":{"id":"wh-1","name":"Users","url":"https://example.com/hook","events":["user.created"],"is_active":true,"retry_config":{"max_attempts":3,"backoff_seconds":[60,300,900]}},"secret_key":"whsec_1"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Admin.CreateWebhook(context.Background(), &models.CreateWebhookRequest{
+			Name:   "Users",
+			URL:    "https://example.com/hook",
+			Events: []string{"user.created"},
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Name != "Users" || len(got.Events) != 1 {
+			t.Errorf("unexpected request: %+v", got)
+		}
+		if resp.SecretKey != "whsec_1" || resp.Webhook.ID != "wh-1" || resp.Webhook.Events[0] != "user.created" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+		if resp.Webhook.RetryConfig == nil || resp.Webhook.RetryConfig.MaxAttempts != 3 {
+			t.Errorf("expected retry config, got %+v", resp.Webhook.RetryConfig)
+		}
+	})
+
+	t.Run("ShouldPageDeliveries", func(t *testing.T) {
+		// Arrange
+		var query string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/admin/webhooks/wh-1/deliveries", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Write([]byte(`{"deliveries":[{"id":"d-1","webhook_id":"wh-1","event_type":"user.created","payload":{"user_id":"u-1"},"status":"failed","http_status_code":500,"attempts":3}],"total":1,"page":2,"page_size":10,"total_pages":1}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Admin.ListWebhookDeliveries(context.Background(), "wh-1", &models.ListWebhookDeliveriesParams{Page: 2, PageSize: 10})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if query != "page=2&page_size=10" {
+			t.Errorf("unexpected query %q", query)
+		}
+		if len(resp.Deliveries) != 1 || *resp.Deliveries[0].HTTPStatusCode != 500 || string(resp.Deliveries[0].Payload) != `{"user_id":"u-1"}` {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("ShouldRedeliverDelivery", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/admin/webhooks/wh-1/deliveries/d-1/redeliver", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"d-2","webhook_id":"wh-1","event_type":"user.created","payload":{"user_id":"u-1"},"status":"pending","attempts":0}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		delivery, err := client.Admin.RedeliverWebhookDelivery(context.Background(), "wh-1", "d-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if delivery.ID != "d-2" || delivery.Status != "pending" {
+			t.Errorf("unexpected delivery: %+v", delivery)
+		}
+	})
+
+	t.Run("ShouldSendIdempotencyKey", func(t *testing.T) {
+		// Arrange
+		var key, requestID string
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/admin/webhooks/wh-1/test", func(w http.ResponseWriter, r *http.Request) {
+			key = r.Header.Get("Idempotency-Key")
+			requestID = r.Header.Get("X-Request-ID")
+			w.Write([]byte(`{"message":"Test webhook sent"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+		ctx := WithIdempotencyKey(WithRequestID(context.Background(), "req-1"), "test-1")
+
+		// Act
+		_, err := client.Admin.TestWebhook(ctx, "wh-1", &models.TestWebhookRequest{EventType: "user.created"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key != "test-1" || requestID != "req-1" {
+			t.Errorf("unexpected headers: Idempotency-Key %q, X-Request-ID %q", key, requestID)
+		}
+	})
+}
+
+func TestAdminService_EraseUser(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/admin/users/u-1/gdpr-erase", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"user_id":"u-1","erased_at":"2025-06-01T12:00:00Z","audit_logs_scrubbed":42}`))
+	})
+	client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+	// Act
+	resp, err := client.Admin.EraseUser(context.Background(), "u-1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.UserID != "u-1" || resp.AuditLogsScrubbed != 42 || resp.ErasedAt.IsZero() {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+	// Arrange
+	var query url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"users":[{"id":"u-1","email":"jane@example.com"}],"total":0,"page":0,"page_size":1,"total_pages":0,"next_cursor":"next"}`))
+	})
+	client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+	verified := true
+
+	// Act
+	resp, err := client.Admin.ListUsers(context.Background(), &models.ListUsersParams{
+		PageSize:      1,
+		Role:          "admin,editor",
+		EmailVerified: &verified,
+		Metadata:      map[string]string{"plan": "pro"},
+		Cursor:        "prev",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Get("role") != "admin,editor" || query.Get("email_verified") != "true" || query.Get("metadata[plan]") != "pro" || query.Get("cursor") != "prev" || query.Get("page_size") != "1" {
+		t.Errorf("unexpected query: %v", query)
+	}
+	if len(resp.Items) != 1 || resp.Pagination.NextCursor != "next" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminService_EmailTemplates(t *testing.T) {
+	t.Run("ShouldEscapeTemplateType", func(t *testing.T) {
+		// Arrange
+		var path string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.EscapedPath()
+			w.Write([]byte(`{"variables":["username","code"]}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		variables, err := client.Admin.GetEmailTemplateVariables(context.Background(), "a/b")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != "/api/admin/templates/variables/a%2Fb" {
+			t.Errorf("unexpected path %q", path)
+		}
+		if len(variables) != 2 {
+			t.Errorf("unexpected variables %v", variables)
+		}
+	})
+}
+
+func TestAdminService_MaintenanceMode(t *testing.T) {
+	t.Run("ShouldToggleMaintenanceMode", func(t *testing.T) {
+		// Arrange
+		var requests []models.MaintenanceModeRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /api/admin/system/maintenance", func(w http.ResponseWriter, r *http.Request) {
+			var req models.MaintenanceModeRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+			json.NewEncoder(w).Encode(models.MaintenanceStatus{Enabled: req.Enabled, Message: req.Message})
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		enabled, err := client.Admin.EnableMaintenanceMode(context.Background(), "Upgrading")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		disabled, err := client.Admin.DisableMaintenanceMode(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Assert
+		if !enabled.Enabled || enabled.Message != "Upgrading" || disabled.Enabled {
+			t.Errorf("unexpected statuses %+v, %+v", enabled, disabled)
+		}
+		if len(requests) != 2 || !requests[0].Enabled || requests[1].Enabled {
+			t.Errorf("unexpected requests %+v", requests)
+		}
+	})
+}
+
+func TestAdminOAuthClientsService(t *testing.T) {
+	t.Run("ShouldReturnSecretOfCreatedClient", func(t *testing.T) {
+		// Arrange
+		var got models.CreateOAuthClientRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/admin/oauth/clients", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"client":{"id":"c-1","client_id":"agw_1","name":"App","client_type":"confidential","redirect_uris":["https://app.example.com/cb"],"post_logout_redirect_uris":["https://app.example.com/bye"]},"client_secret":"agws_1"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Admin.OAuthClients.Create(context.Background(), &models.CreateOAuthClientRequest{
+			Name:                   "App",
+			ClientType:             "confidential",
+			RedirectURIs:           []string{"https://app.example.com/cb"},
+			PostLogoutRedirectURIs: []string{"https://app.example.com/bye"},
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Name != "App" || len(got.PostLogoutRedirectURIs) != 1 {
+			t.Errorf("unexpected request: %+v", got)
+		}
+		if resp.ClientSecret != "agws_1" || resp.Client.ClientID != "agw_1" || resp.Client.PostLogoutRedirectURIs[0] != "https://app.example.com/bye" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("ShouldFilterAndPageClients", func(t *testing.T) {
+		// Arrange
+		var query string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/admin/oauth/clients", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Write([]byte(`{"clients":[{"id":"c-1","client_id":"agw_1","name":"App"}],"total":21,"page":2,"page_size":20,"total_pages":2}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+		active := true
+
+		// Act
+		resp, err := client.Admin.OAuthClients.List(context.Background(), &models.ListOAuthClientsParams{Page: 2, PageSize: 20, OwnerID: "u-1", IsActive: &active})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if query != "is_active=true&owner_id=u-1&page=2&page_size=20" {
+			t.Errorf("unexpected query %q", query)
+		}
+		if len(resp.Clients) != 1 || resp.PageSize != 20 || resp.TotalPages != 2 {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("ShouldReturnRotatedSecret", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/admin/oauth/clients/c-1/rotate-secret", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"client_secret":"agws_2"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Admin.OAuthClients.RotateSecret(context.Background(), "c-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.ClientSecret != "agws_2" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+}
@@ -24,11 +24,13 @@ func (s *APIKeysService) Create(ctx context.Context, req *models.CreateAPIKeyReq
 
 // List retrieves all API keys for the current user.
 func (s *APIKeysService) List(ctx context.Context) ([]models.APIKey, error) {
-	var resp []models.APIKey
+	var resp struct {
+		APIKeys []models.APIKey `json:"api_keys"`
+	}
 	if err := s.client.get(ctx, "/api/api-keys", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.APIKeys, nil
 }
 
 // Get retrieves a specific API key by ID.
@@ -0,0 +1,255 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+const (
+	// DefaultAuditFollowInterval is how often FollowAuditLogs polls for new entries
+	DefaultAuditFollowInterval = 5 * time.Second
+	// DefaultAuditFollowMaxBackoff caps the wait between polls after failures
+	DefaultAuditFollowMaxBackoff = time.Minute
+
+	auditFollowPageSize = 100
+)
+
+// AuditLogFilter selects the entries FollowAuditLogs delivers and where it starts
+type AuditLogFilter struct {
+	// UserID delivers only entries of this user
+	UserID string
+	// Actions delivers only entries with one of these actions. Empty delivers all.
+	Actions []string
+	// Status delivers only entries with this status ("success", "failed", ...)
+	Status string
+
+	// ResumeToken continues after the entry it was handed out with. It takes
+	// precedence over Since.
+	ResumeToken string
+	// Since starts with entries created at or after it. With neither ResumeToken
+	// nor Since, only entries created from now on are delivered.
+	Since time.Time
+
+	// PollInterval defaults to DefaultAuditFollowInterval
+	PollInterval time.Duration
+	// MaxBackoff defaults to DefaultAuditFollowMaxBackoff
+	MaxBackoff time.Duration
+}
+
+// AuditLogHandler receives followed entries, oldest first. resumeToken resumes
+// after entry; store it alongside whatever the handler did with the entry.
+// Returning an error stops FollowAuditLogs.
+type AuditLogHandler func(ctx context.Context, entry models.AuditLog, resumeToken string) error
+
+// auditCursor is the position of the last delivered entry. Entries sharing its
+// timestamp are told apart by ID.
+type auditCursor struct {
+	at   time.Time
+	seen map[string]struct{}
+}
+
+func (c *auditCursor) before(entry *models.AuditLog) bool {
+	if entry.CreatedAt.After(c.at) {
+		return true
+	}
+	if !entry.CreatedAt.Equal(c.at) {
+		return false
+	}
+	_, seen := c.seen[entry.ID]
+	return !seen
+}
+
+func (c *auditCursor) advance(entry *models.AuditLog) {
+	if !entry.CreatedAt.Equal(c.at) {
+		c.at = entry.CreatedAt
+		c.seen = make(map[string]struct{})
+	}
+	c.seen[entry.ID] = struct{}{}
+}
+
+func auditResumeToken(entry *models.AuditLog) string {
+	raw := entry.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + entry.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parseAuditResumeToken(token string) (*auditCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit log resume token: %w", err)
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("invalid audit log resume token")
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit log resume token: %w", err)
+	}
+	return &auditCursor{at: t, seen: map[string]struct{}{id: {}}}, nil
+}
+
+// FollowAuditLogs delivers new audit log entries to handler as they are written,
+// until ctx is done or handler returns an error. It polls the audit log, backing
+// off while the server is unreachable, and returns at once on errors retrying
+// will not fix, such as missing admin rights.
+//
+// Delivery is at least once: after a restart with a resume token, entries
+// written in the same microsecond as the token's entry may be delivered again.
+func (s *AdminService) FollowAuditLogs(ctx context.Context, filter AuditLogFilter, handler AuditLogHandler) error {
+	if filter.PollInterval <= 0 {
+		filter.PollInterval = DefaultAuditFollowInterval
+	}
+	if filter.MaxBackoff <= 0 {
+		filter.MaxBackoff = DefaultAuditFollowMaxBackoff
+	}
+	if filter.MaxBackoff < filter.PollInterval {
+		filter.MaxBackoff = filter.PollInterval
+	}
+
+	var cursor *auditCursor
+	switch {
+	case filter.ResumeToken != "":
+		var err error
+		if cursor, err = parseAuditResumeToken(filter.ResumeToken); err != nil {
+			return err
+		}
+	case !filter.Since.IsZero():
+		cursor = &auditCursor{at: filter.Since, seen: make(map[string]struct{})}
+	}
+
+	wait := time.Duration(0)
+	for {
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		entries, err := s.auditLogsAfter(ctx, filter.UserID, cursor)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !retryableFollowError(err) {
+				return err
+			}
+			wait = nextFollowBackoff(wait, filter)
+			continue
+		}
+		wait = filter.PollInterval
+
+		if cursor == nil {
+			// Starting from now: skip everything already written
+			cursor = &auditCursor{seen: make(map[string]struct{})}
+			if len(entries) > 0 {
+				cursor.advance(&entries[len(entries)-1])
+			}
+			continue
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			cursor.advance(entry)
+			if !filter.matches(entry) {
+				continue
+			}
+			if err := handler(ctx, *entry, auditResumeToken(entry)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// auditLogsAfter returns the entries written after cursor, oldest first. With no
+// cursor it returns only the newest entry.
+func (s *AdminService) auditLogsAfter(ctx context.Context, userID string, cursor *auditCursor) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	seen := make(map[string]struct{})
+
+	// Pages are newest first; entries written meanwhile shift older ones to later
+	// pages, so some come up twice but none are skipped
+	for page := 1; ; page++ {
+		resp, err := s.ListAuditLogs(ctx, &models.ListAuditLogsParams{
+			Page:     page,
+			PageSize: auditFollowPageSize,
+			UserID:   userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		reachedCursor := false
+		for _, entry := range resp.Items {
+			if cursor == nil || !cursor.before(&entry) {
+				if cursor == nil {
+					entries = append(entries, entry)
+				}
+				reachedCursor = true
+				break
+			}
+			if _, dup := seen[entry.ID]; dup {
+				continue
+			}
+			seen[entry.ID] = struct{}{}
+			entries = append(entries, entry)
+		}
+		if reachedCursor || len(resp.Items) < auditFollowPageSize {
+			break
+		}
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (f *AuditLogFilter) matches(entry *models.AuditLog) bool {
+	if f.Status != "" && entry.Status != f.Status {
+		return false
+	}
+	if len(f.Actions) == 0 {
+		return true
+	}
+	for _, action := range f.Actions {
+		if entry.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// retryableFollowError reports whether polling again may succeed
+func retryableFollowError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// Network errors and undecodable responses
+		return true
+	}
+	return apiErr.StatusCode >= 500 || apiErr.IsTooManyRequests()
+}
+
+func nextFollowBackoff(wait time.Duration, filter AuditLogFilter) time.Duration {
+	if wait < filter.PollInterval {
+		return filter.PollInterval
+	}
+	wait *= 2
+	if wait > filter.MaxBackoff {
+		wait = filter.MaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,233 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+// auditLogServer serves an audit log newest first, like the admin endpoint
+type auditLogServer struct {
+	mu       sync.Mutex
+	logs     []models.AuditLog // oldest first
+	requests int
+	// onRequest runs before each response with the 1-based request number
+	onRequest func(n int) int
+}
+
+func (s *auditLogServer) add(action string, at time.Time) models.AuditLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := models.AuditLog{
+		ID:        fmt.Sprintf("log-%d", len(s.logs)+1),
+		Action:    action,
+		Status:    "success",
+		CreatedAt: at,
+	}
+	s.logs = append(s.logs, entry)
+	return entry
+}
+
+func (s *auditLogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	n := s.requests
+	s.mu.Unlock()
+	if s.onRequest != nil {
+		if status := s.onRequest(n); status != 0 {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(status)})
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	logs := make([]models.AuditLog, 0, pageSize)
+	for i := len(s.logs) - 1 - (page-1)*pageSize; i >= 0 && len(logs) < pageSize; i-- {
+		logs = append(logs, s.logs[i])
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"logs":      logs,
+		"total":     len(s.logs),
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func newAuditFollowClient(t *testing.T, server *auditLogServer) *Client {
+	mux := http.NewServeMux()
+	mux.Handle("/api/admin/audit-logs", server)
+	return NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+}
+
+// follow collects delivered entries until want of them arrived
+func follow(t *testing.T, client *Client, filter AuditLogFilter, want int) ([]models.AuditLog, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter.PollInterval = 5 * time.Millisecond
+
+	var entries []models.AuditLog
+	var tokens []string
+	err := client.Admin.FollowAuditLogs(ctx, filter, func(ctx context.Context, entry models.AuditLog, token string) error {
+		entries = append(entries, entry)
+		tokens = append(tokens, token)
+		if len(entries) == want {
+			cancel()
+		}
+		return nil
+	})
+	return entries, tokens, err
+}
+
+func ids(entries []models.AuditLog) []string {
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.ID)
+	}
+	return result
+}
+
+func TestAdminService_FollowAuditLogs(t *testing.T) {
+	base := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("ShouldDeliverOnlyNewMatchingEntriesOldestFirst", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{}
+		server.add("signin", base)
+		server.add("signin", base.Add(time.Second))
+		server.onRequest = func(n int) int {
+			if n == 2 {
+				server.add("signin", base.Add(2*time.Second))
+				server.add("token_refresh", base.Add(3*time.Second))
+				server.add("signout", base.Add(4*time.Second))
+			}
+			return 0
+		}
+		client := newAuditFollowClient(t, server)
+
+		// Act
+		entries, _, err := follow(t, client, AuditLogFilter{Actions: []string{"signin", "signout"}}, 2)
+
+		// Assert
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if got := fmt.Sprint(ids(entries)); got != "[log-3 log-5]" {
+			t.Errorf("expected [log-3 log-5], got %s", got)
+		}
+	})
+
+	t.Run("ShouldResumeAfterToken", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{}
+		for i := 0; i < 3; i++ {
+			server.add("signin", base.Add(time.Duration(i)*time.Second))
+		}
+		client := newAuditFollowClient(t, server)
+		_, tokens, _ := follow(t, client, AuditLogFilter{Since: base}, 3)
+
+		// Act
+		entries, _, _ := follow(t, client, AuditLogFilter{ResumeToken: tokens[0]}, 2)
+
+		// Assert
+		if got := fmt.Sprint(ids(entries)); got != "[log-2 log-3]" {
+			t.Errorf("expected [log-2 log-3], got %s", got)
+		}
+	})
+
+	t.Run("ShouldPageBackToSince", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{}
+		server.add("signin", base.Add(-time.Second))
+		for i := 0; i < 250; i++ {
+			server.add("signin", base.Add(time.Duration(i)*time.Millisecond))
+		}
+		client := newAuditFollowClient(t, server)
+
+		// Act
+		entries, _, _ := follow(t, client, AuditLogFilter{Since: base}, 250)
+
+		// Assert
+		if len(entries) != 250 || entries[0].ID != "log-2" || entries[249].ID != "log-251" {
+			t.Errorf("expected log-2 through log-251, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("ShouldRetryServerErrors", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{}
+		server.add("signin", base)
+		server.onRequest = func(n int) int {
+			if n < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return 0
+		}
+		client := newAuditFollowClient(t, server)
+
+		// Act
+		entries, _, _ := follow(t, client, AuditLogFilter{Since: base}, 1)
+
+		// Assert
+		if len(entries) != 1 {
+			t.Errorf("expected the entry after retries, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("ShouldStopOnForbidden", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{onRequest: func(int) int { return http.StatusForbidden }}
+		client := newAuditFollowClient(t, server)
+
+		// Act
+		_, _, err := follow(t, client, AuditLogFilter{}, 1)
+
+		// Assert
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsForbidden() {
+			t.Errorf("expected forbidden error, got %v", err)
+		}
+	})
+
+	t.Run("ShouldStopWithHandlerError", func(t *testing.T) {
+		// Arrange
+		server := &auditLogServer{}
+		server.add("signin", base)
+		client := newAuditFollowClient(t, server)
+		stop := errors.New("stop")
+
+		// Act
+		err := client.Admin.FollowAuditLogs(context.Background(), AuditLogFilter{Since: base}, func(context.Context, models.AuditLog, string) error {
+			return stop
+		})
+
+		// Assert
+		if !errors.Is(err, stop) {
+			t.Errorf("expected handler error, got %v", err)
+		}
+	})
+
+	t.Run("ShouldRejectMalformedResumeToken", func(t *testing.T) {
+		// Arrange
+		client := newAuditFollowClient(t, &auditLogServer{})
+
+		// Act
+		err := client.Admin.FollowAuditLogs(context.Background(), AuditLogFilter{ResumeToken: "!!"}, nil)
+
+		// Assert
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
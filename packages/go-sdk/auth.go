@@ -26,7 +26,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *models.SignUpRequest) (*m
 	return &resp, nil
 }
 
-// SignIn authenticates a user with email/phone and password.
+// SignIn authenticates a user with email, phone or username and password.
 // Returns AuthResponse with tokens or TwoFactorRequiredError if 2FA is required.
 func (s *AuthService) SignIn(ctx context.Context, req *models.SignInRequest) (*models.AuthResponse, error) {
 	var resp models.AuthResponse
@@ -56,6 +56,7 @@ func (s *AuthService) SignInWithEmail(ctx context.Context, email, password strin
 }
 
 // SignInWithPhone is a convenience method for phone/password login.
+// The phone number should be in E.164 format (e.g. +15551234567).
 func (s *AuthService) SignInWithPhone(ctx context.Context, phone, password string) (*models.AuthResponse, error) {
 	return s.SignIn(ctx, &models.SignInRequest{
 		Phone:    &phone,
@@ -63,6 +64,23 @@ func (s *AuthService) SignInWithPhone(ctx context.Context, phone, password strin
 	})
 }
 
+// SignInWithUsername is a convenience method for username/password login.
+func (s *AuthService) SignInWithUsername(ctx context.Context, username, password string) (*models.AuthResponse, error) {
+	return s.SignIn(ctx, &models.SignInRequest{
+		Username: username,
+		Password: password,
+	})
+}
+
+// SignInWithIdentifier signs in with whatever the user typed: an email, phone or username.
+// The server detects the kind of identifier; digits-only values are treated as phones.
+func (s *AuthService) SignInWithIdentifier(ctx context.Context, identifier, password string) (*models.AuthResponse, error) {
+	return s.SignIn(ctx, &models.SignInRequest{
+		Identifier: identifier,
+		Password:   password,
+	})
+}
+
 // Verify2FA completes login with 2FA verification.
 func (s *AuthService) Verify2FA(ctx context.Context, twoFactorToken, code string) (*models.AuthResponse, error) {
 	req := &models.TwoFactorLoginVerifyRequest{
@@ -159,6 +177,42 @@ func (s *AuthService) ResetPassword(ctx context.Context, email, code, newPasswor
 	return &resp, nil
 }
 
+// RequestMagicLink emails a one-click sign-in link to the account with the given email.
+// The response is the same whether or not the account exists. Once clicked, the link
+// redirects to redirectURL (or the gateway's configured URL when empty) with a code
+// query parameter to pass to CompleteMagicLink, or an error parameter
+// (invalid_link, access_denied or server_error).
+func (s *AuthService) RequestMagicLink(ctx context.Context, email, redirectURL string) (*models.MessageResponse, error) {
+	req := &models.RequestMagicLinkRequest{
+		Email:       email,
+		RedirectURL: redirectURL,
+	}
+
+	var resp models.MessageResponse
+	if err := s.client.post(ctx, "/api/auth/magic-link/request", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CompleteMagicLink exchanges the code from the magic link redirect for tokens.
+// If the user has 2FA enabled, the response has Requires2FA set; finish with Verify2FA.
+func (s *AuthService) CompleteMagicLink(ctx context.Context, code string) (*models.AuthResponse, error) {
+	req := &models.CompleteMagicLinkRequest{Code: code}
+
+	var resp models.AuthResponse
+	if err := s.client.post(ctx, "/api/auth/magic-link/complete", req, &resp); err != nil {
+		return nil, err
+	}
+
+	// Store tokens if received
+	if resp.AccessToken != "" {
+		s.client.SetTokens(resp.AccessToken, resp.RefreshToken, resp.ExpiresIn)
+	}
+
+	return &resp, nil
+}
+
 // InitPasswordlessRegistration initiates a two-step passwordless registration.
 // Step 1: User provides email or phone and optional name/username.
 // An OTP is sent to the provided email or phone.
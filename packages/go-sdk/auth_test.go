@@ -0,0 +1,106 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+func TestAuthService_MagicLink(t *testing.T) {
+	t.Run("ShouldRequestLinkWithRedirectURL", func(t *testing.T) {
+		// Arrange
+		var got models.RequestMagicLinkRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/magic-link/request", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"message":"If an account exists for this email, a sign-in link has been sent to it"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Auth.RequestMagicLink(context.Background(), "user@example.com", "https://app.example.com/callback")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Email != "user@example.com" || got.RedirectURL != "https://app.example.com/callback" {
+			t.Errorf("unexpected request: %+v", got)
+		}
+		if resp.Message == "" {
+			t.Error("expected a message")
+		}
+	})
+
+	t.Run("ShouldStoreTokensOnComplete", func(t *testing.T) {
+		// Arrange
+		var got models.CompleteMagicLinkRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/magic-link/complete", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"access_token":"access","refresh_token":"refresh","expires_in":900,"user":{"id":"u-1","email":"user@example.com"}}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Auth.CompleteMagicLink(context.Background(), "code-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Code != "code-1" {
+			t.Errorf("expected code-1, got %q", got.Code)
+		}
+		if resp.User == nil || resp.User.Email != "user@example.com" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+		if client.GetAccessToken() != "access" || client.GetRefreshToken() != "refresh" {
+			t.Errorf("expected tokens to be stored, got %q and %q", client.GetAccessToken(), client.GetRefreshToken())
+		}
+	})
+
+	t.Run("ShouldNotStoreTokensWhen2FARequired", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/magic-link/complete", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"requires_2fa":true,"two_factor_token":"2fa-token","two_factor_methods":["totp"]}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.Auth.CompleteMagicLink(context.Background(), "code-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.Requires2FA || resp.TwoFactorToken != "2fa-token" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+		if client.IsAuthenticated() {
+			t.Error("expected no tokens to be stored")
+		}
+	})
+
+	t.Run("ShouldReturnErrorForInvalidCode", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/magic-link/complete", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad Request","message":"Invalid or expired code","code":400}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		_, err := client.Auth.CompleteMagicLink(context.Background(), "used")
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package authgateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+// clientCertThumbprintMetadata carries the thumbprint of the caller's client certificate
+// to IntrospectOAuthToken
+const clientCertThumbprintMetadata = "x-client-cert-thumbprint"
+
+// CertificateThumbprint returns the x5t#S256 thumbprint of cert, the base64url SHA-256
+// hash of its DER encoding, as certificate-bound access tokens carry it (RFC 8705).
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// WithClientCertificate returns a context for IntrospectOAuthToken calls made on behalf of
+// a request that arrived with cert over mTLS. Tokens bound to another certificate, or
+// presented without one, are then reported inactive.
+func WithClientCertificate(ctx context.Context, cert *x509.Certificate) context.Context {
+	return WithGRPCMetadata(ctx, map[string]string{clientCertThumbprintMetadata: CertificateThumbprint(cert)})
+}
+
+// CertificateBound reports whether an introspected token may be used by a caller presenting
+// cert, which may be nil. Tokens that are not certificate-bound may be used by anyone.
+func CertificateBound(resp *models.TokenIntrospectionResponse, cert *x509.Certificate) bool {
+	if resp.Cnf == nil {
+		return true
+	}
+	return cert != nil && resp.Cnf.X5TS256 == CertificateThumbprint(cert)
+}
@@ -0,0 +1,82 @@
+package authgateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestCertificate(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return cert
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	// Arrange
+	cert := newTestCertificate(t, "client")
+
+	// Act
+	ctx := WithClientCertificate(context.Background(), cert)
+
+	// Assert
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if got := md.Get(clientCertThumbprintMetadata); len(got) != 1 || got[0] != CertificateThumbprint(cert) {
+		t.Errorf("expected thumbprint metadata, got %v", got)
+	}
+}
+
+func TestCertificateBound(t *testing.T) {
+	client := newTestCertificate(t, "client")
+	other := newTestCertificate(t, "other")
+	bound := &models.TokenIntrospectionResponse{Active: true, Cnf: &models.Confirmation{X5TS256: CertificateThumbprint(client)}}
+
+	cases := []struct {
+		name string
+		resp *models.TokenIntrospectionResponse
+		cert *x509.Certificate
+		want bool
+	}{
+		{"ShouldAcceptSameCertificate", bound, client, true},
+		{"ShouldRejectOtherCertificate", bound, other, false},
+		{"ShouldRejectMissingCertificate", bound, nil, false},
+		{"ShouldAcceptUnboundToken", &models.TokenIntrospectionResponse{Active: true}, nil, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := CertificateBound(tc.resp, tc.cert)
+
+			// Assert
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -27,6 +27,8 @@ type Client struct {
 	refreshToken string
 	tokenMu      sync.RWMutex
 	expiresAt    time.Time
+	clockSkew    time.Duration
+	now          func() time.Time
 
 	// Auto-refresh configuration
 	autoRefresh bool
@@ -45,6 +47,7 @@ type Client struct {
 	OAuth        *OAuthService
 	Passwordless *PasswordlessService
 	Admin        *AdminService
+	SignedURLs   *SignedURLsService
 }
 
 // Config contains client configuration options.
@@ -67,6 +70,21 @@ type Config struct {
 	// Headers contains custom headers to include in every request.
 	// Common headers: X-Application-ID, X-Client-Name, X-Request-ID, etc.
 	Headers map[string]string
+
+	// ClockSkew is how long before its expiry the access token is treated as expired,
+	// so that it is refreshed before the server rejects it (default: 30s)
+	ClockSkew time.Duration
+
+	// Now is the time source for token expiry checks (default: time.Now).
+	// Inject a fixed clock in tests.
+	Now func() time.Time
+
+	// CheckCompatibility makes NewClient call CheckCompatibility and log a warning
+	// through Logger when the server is not compatible with this SDK
+	CheckCompatibility bool
+
+	// Logger receives the compatibility warning (default: the standard log package)
+	Logger Logger
 }
 
 // NewClient creates a new Auth Gateway client.
@@ -81,6 +99,12 @@ func NewClient(config Config) *Client {
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
 	}
+	if config.ClockSkew == 0 {
+		config.ClockSkew = 30 * time.Second
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
 
 	httpClient := config.HTTPClient
 	if httpClient == nil {
@@ -95,6 +119,8 @@ func NewClient(config Config) *Client {
 		apiKey:      config.APIKey,
 		autoRefresh: config.AutoRefresh,
 		headers:     config.Headers,
+		clockSkew:   config.ClockSkew,
+		now:         config.Now,
 	}
 
 	// Initialize services
@@ -106,7 +132,20 @@ func NewClient(config Config) *Client {
 	c.OTP = &OTPService{client: c}
 	c.OAuth = &OAuthService{client: c}
 	c.Passwordless = &PasswordlessService{client: c}
-	c.Admin = &AdminService{client: c}
+	c.Admin = &AdminService{client: c, OAuthClients: &AdminOAuthClientsService{client: c}}
+	c.SignedURLs = &SignedURLsService{client: c}
+
+	if config.CheckCompatibility {
+		logger := config.Logger
+		if logger == nil {
+			logger = &defaultLogger{}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		if err := c.CheckCompatibility(ctx); err != nil {
+			logger.Error("auth gateway compatibility check: %v", err)
+		}
+		cancel()
+	}
 
 	return c
 }
@@ -119,7 +158,7 @@ func (c *Client) SetTokens(accessToken, refreshToken string, expiresIn int64) {
 	c.accessToken = accessToken
 	c.refreshToken = refreshToken
 	if expiresIn > 0 {
-		c.expiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+		c.expiresAt = c.now().Add(time.Duration(expiresIn) * time.Second)
 	}
 }
 
@@ -162,8 +201,8 @@ func (c *Client) IsTokenExpired() bool {
 	if c.expiresAt.IsZero() {
 		return false
 	}
-	// Consider token expired 30 seconds before actual expiry
-	return time.Now().Add(30 * time.Second).After(c.expiresAt)
+	// Consider token expired ClockSkew before actual expiry
+	return c.now().Add(c.clockSkew).After(c.expiresAt)
 }
 
 // SetHeader sets a custom header to be included in all requests.
@@ -236,6 +275,27 @@ func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return WithHeaders(ctx, map[string]string{"X-Request-ID": requestID})
 }
 
+// WithIdempotencyKey returns a context that sends an Idempotency-Key header, keeping
+// headers already set with WithHeaders. Creating users, API keys and OAuth clients and
+// testing webhooks honour it: a request retried with the same key gets the response of
+// the first one instead of creating a duplicate.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	headers := map[string]string{}
+	if ctxHeaders, ok := ctx.Value(headersContextKey).(map[string]string); ok {
+		for k, v := range ctxHeaders {
+			headers[k] = v
+		}
+	}
+	headers["Idempotency-Key"] = key
+	return WithHeaders(ctx, headers)
+}
+
+// rawBody is a request body that is sent as is instead of being encoded as JSON.
+type rawBody struct {
+	contentType string
+	reader      io.Reader
+}
+
 // request performs an HTTP request with authentication and JSON handling.
 func (c *Client) request(ctx context.Context, method, path string, body, result interface{}) error {
 	// Check if we need to refresh the token
@@ -247,8 +307,12 @@ func (c *Client) request(ctx context.Context, method, path string, body, result
 
 	reqURL := c.baseURL + path
 
+	contentType := "application/json"
 	var bodyReader io.Reader
-	if body != nil {
+	if raw, ok := body.(*rawBody); ok {
+		contentType = raw.contentType
+		bodyReader = raw.reader
+	} else if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
@@ -262,7 +326,7 @@ func (c *Client) request(ctx context.Context, method, path string, body, result
 	}
 
 	// Set default headers
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
 
 	// Set custom headers from client configuration
@@ -337,10 +401,20 @@ func (c *Client) parseErrorResponse(statusCode int, body []byte) error {
 		return &TwoFactorRequiredError{TwoFactorToken: token}
 	}
 
+	if errResp.Code == ErrCodePasswordPolicy {
+		return &PasswordPolicyError{Message: errResp.Message, Violations: errResp.Violations}
+	}
+
+	// Validation endpoints explain rejections in error_message
+	message := errResp.Message
+	if message == "" {
+		message = errResp.ErrorMessage
+	}
+
 	return &APIError{
 		StatusCode: statusCode,
 		Code:       errResp.Code,
-		Message:    errResp.Message,
+		Message:    message,
 		Details:    errResp.Details,
 	}
 }
@@ -418,6 +492,13 @@ func buildQueryString(params interface{}) string {
 			strVal = fmt.Sprintf("%d", field.Int())
 		case reflect.Bool:
 			strVal = fmt.Sprintf("%t", field.Bool())
+		case reflect.Map:
+			// Maps are sent as name[key]=value
+			iter := field.MapRange()
+			for iter.Next() {
+				values.Add(fmt.Sprintf("%s[%v]", name, iter.Key()), fmt.Sprint(iter.Value()))
+			}
+			continue
 		default:
 			continue
 		}
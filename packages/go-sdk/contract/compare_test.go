@@ -0,0 +1,242 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is an http.RoundTripper that keeps the last response body per request
+type recorder struct {
+	next http.RoundTripper
+
+	mu     sync.Mutex
+	bodies map[string][]byte
+}
+
+func newRecorder() *recorder {
+	return &recorder{next: http.DefaultTransport, bodies: make(map[string][]byte)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.bodies[req.Method+" "+req.URL.Path] = body
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// body returns the last response body for "METHOD /path"
+func (r *recorder) body(t *testing.T, request string) []byte {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, ok := r.bodies[request]
+	if !ok {
+		t.Fatalf("no response recorded for %s", request)
+	}
+	return body
+}
+
+// assertContract fails the test for every field of model missing from body and logs
+// response keys the model does not declare
+func assertContract(t *testing.T, body []byte, model interface{}) {
+	t.Helper()
+
+	var d diff
+	d.compare("$", reflect.TypeOf(model), body)
+
+	for _, key := range d.unknown {
+		t.Logf("response key %s is not modelled by %T", key, model)
+	}
+	for _, key := range d.missing {
+		t.Errorf("%T expects %s, missing from response", model, key)
+	}
+}
+
+// diff collects the differences between a JSON document and a Go type
+type diff struct {
+	missing []string
+	unknown []string
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+func (d *diff) compare(path string, typ reflect.Type, raw json.RawMessage) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if len(raw) == 0 || string(raw) == "null" || typ == timeType {
+		return
+	}
+	// Custom decoders define their own shape
+	if reflect.PointerTo(typ).Implements(unmarshalerType) {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(raw, &fields) != nil {
+			return
+		}
+		seen := make(map[string]bool)
+		d.compareStruct(path, typ, fields, seen)
+
+		unknown := make([]string, 0)
+		for key := range fields {
+			if !seen[key] {
+				unknown = append(unknown, path+"."+key)
+			}
+		}
+		sort.Strings(unknown)
+		d.unknown = append(d.unknown, unknown...)
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return
+		}
+		for i, item := range items {
+			d.compare(path+"["+strconv.Itoa(i)+"]", typ.Elem(), item)
+		}
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if json.Unmarshal(raw, &values) != nil {
+			return
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			d.compare(path+"."+key, typ.Elem(), values[key])
+		}
+	}
+}
+
+func (d *diff) compareStruct(path string, typ reflect.Type, fields map[string]json.RawMessage, seen map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are flattened into the parent
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				d.compareStruct(path, embedded, fields, seen)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		seen[name] = true
+		raw, ok := fields[name]
+		if !ok {
+			if !strings.Contains(opts, "omitempty") {
+				d.missing = append(d.missing, path+"."+name)
+			}
+			continue
+		}
+		d.compare(path+"."+name, field.Type, raw)
+	}
+}
+
+func TestAssertContract(t *testing.T) {
+	type location struct {
+		City    string `json:"city"`
+		Country string `json:"country,omitempty"`
+	}
+	type base struct {
+		ID string `json:"id"`
+	}
+	type model struct {
+		base
+		Name      string              `json:"name"`
+		Note      string              `json:"note,omitempty"`
+		CreatedAt time.Time           `json:"created_at"`
+		Locations []location          `json:"locations"`
+		ByName    map[string]location `json:"by_name,omitempty"`
+		Internal  string              `json:"-"`
+	}
+
+	t.Run("ShouldPass_WhenResponseMatches", func(t *testing.T) {
+		// Arrange
+		body := []byte(`{"id":"1","name":"a","created_at":"2025-01-01T00:00:00Z","locations":[{"city":"x"}]}`)
+
+		// Act
+		var d diff
+		d.compare("$", reflect.TypeOf(model{}), body)
+
+		// Assert
+		if len(d.missing) != 0 || len(d.unknown) != 0 {
+			t.Errorf("expected no differences, got missing %v unknown %v", d.missing, d.unknown)
+		}
+	})
+
+	t.Run("ShouldReportMissingFields_Recursively", func(t *testing.T) {
+		// Arrange
+		body := []byte(`{"name":"a","created_at":"2025-01-01T00:00:00Z","locations":[{"city":"x"},{"country":"y"}],"by_name":{"k":{}}}`)
+
+		// Act
+		var d diff
+		d.compare("$", reflect.TypeOf(&model{}), body)
+
+		// Assert
+		want := []string{"$.id", "$.locations[1].city", "$.by_name.k.city"}
+		if !reflect.DeepEqual(d.missing, want) {
+			t.Errorf("expected missing %v, got %v", want, d.missing)
+		}
+	})
+
+	t.Run("ShouldReportUnknownKeys", func(t *testing.T) {
+		// Arrange
+		body := []byte(`{"id":"1","name":"a","created_at":"2025-01-01T00:00:00Z","locations":[],"total":1,"Internal":"x"}`)
+
+		// Act
+		var d diff
+		d.compare("$", reflect.TypeOf(model{}), body)
+
+		// Assert
+		want := []string{"$.Internal", "$.total"}
+		if !reflect.DeepEqual(d.unknown, want) {
+			t.Errorf("expected unknown %v, got %v", want, d.unknown)
+		}
+	})
+}
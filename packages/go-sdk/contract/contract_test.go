@@ -0,0 +1,381 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+var (
+	baseURL  = flag.String("contract.base-url", os.Getenv("AUTH_GATEWAY_CONTRACT_BASE_URL"), "Auth Gateway base URL; the contract suite is skipped when empty")
+	email    = flag.String("contract.email", os.Getenv("AUTH_GATEWAY_CONTRACT_EMAIL"), "email of the user the suite signs in as")
+	password = flag.String("contract.password", os.Getenv("AUTH_GATEWAY_CONTRACT_PASSWORD"), "password of the user the suite signs in as")
+)
+
+// newClient returns an SDK client for the backend under test and the recorder of its responses
+func newClient(t *testing.T) (*authgateway.Client, *recorder) {
+	t.Helper()
+	if *baseURL == "" {
+		t.Skip("contract tests need -contract.base-url or AUTH_GATEWAY_CONTRACT_BASE_URL")
+	}
+
+	rec := newRecorder()
+	client := authgateway.NewClient(authgateway.Config{
+		BaseURL:    *baseURL,
+		HTTPClient: &http.Client{Transport: rec, Timeout: 30 * time.Second},
+	})
+	return client, rec
+}
+
+// signIn returns a client signed in as the configured user
+func signIn(t *testing.T) (*authgateway.Client, *recorder) {
+	t.Helper()
+	client, rec := newClient(t)
+	if *email == "" || *password == "" {
+		t.Skip("contract tests for signed-in endpoints need -contract.email and -contract.password")
+	}
+
+	if _, err := client.Auth.SignInWithEmail(context.Background(), *email, *password); err != nil {
+		t.Fatalf("sign in failed: %v", err)
+	}
+	return client, rec
+}
+
+// skipIfForbidden skips admin tests when the configured user is not an admin
+func skipIfForbidden(t *testing.T, err error) {
+	t.Helper()
+	var apiErr *authgateway.APIError
+	if errors.As(err, &apiErr) && apiErr.IsForbidden() {
+		t.Skip("the contract user is not an admin")
+	}
+}
+
+func TestPublicContract(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Health", func(t *testing.T) {
+		client, rec := newClient(t)
+
+		if _, err := client.Health(ctx); err != nil {
+			t.Fatalf("Health: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /health"), models.HealthStatus{})
+
+		if _, err := client.Ready(ctx); err != nil {
+			t.Fatalf("Ready: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /ready"), models.HealthStatus{})
+
+		if _, err := client.Live(ctx); err != nil {
+			t.Fatalf("Live: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /live"), models.HealthStatus{})
+	})
+
+	t.Run("MaintenanceStatus", func(t *testing.T) {
+		client, rec := newClient(t)
+
+		if _, err := client.MaintenanceStatus(ctx); err != nil {
+			t.Fatalf("MaintenanceStatus: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /system/maintenance"), models.MaintenanceStatus{})
+	})
+}
+
+func TestUserContract(t *testing.T) {
+	ctx := context.Background()
+	client, rec := signIn(t)
+
+	t.Run("SignIn", func(t *testing.T) {
+		assertContract(t, rec.body(t, "POST /api/auth/signin"), models.AuthResponse{})
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		if _, err := client.Profile.Get(ctx); err != nil {
+			t.Fatalf("Profile.Get: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/auth/profile"), models.User{})
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		if _, err := client.Sessions.List(ctx); err != nil {
+			t.Fatalf("Sessions.List: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/sessions"), struct {
+			Sessions []models.Session `json:"sessions"`
+		}{})
+	})
+
+	t.Run("APIKeys", func(t *testing.T) {
+		created, err := client.APIKeys.Create(ctx, &models.CreateAPIKeyRequest{
+			Name:   fmt.Sprintf("contract-%d", time.Now().UnixNano()),
+			Scopes: []string{"profile:read"},
+		})
+		if err != nil {
+			t.Fatalf("APIKeys.Create: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/api-keys"), models.CreateAPIKeyResponse{})
+		id := created.APIKey.ID
+		t.Cleanup(func() {
+			if err := client.APIKeys.Delete(context.Background(), id); err != nil {
+				t.Errorf("APIKeys.Delete: %v", err)
+			}
+		})
+
+		keys, err := client.APIKeys.List(ctx)
+		if err != nil {
+			t.Fatalf("APIKeys.List: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/api-keys"), struct {
+			APIKeys []models.APIKey `json:"api_keys"`
+		}{})
+		if len(keys) == 0 {
+			t.Error("APIKeys.List returned no keys after Create")
+		}
+
+		key, err := client.APIKeys.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("APIKeys.Get: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/api-keys/"+id), models.APIKey{})
+		if len(key.Scopes) != 1 || key.Scopes[0] != "profile:read" {
+			t.Errorf("expected scopes [profile:read], got %v", key.Scopes)
+		}
+
+		if _, err := client.APIKeys.Revoke(ctx, id); err != nil {
+			t.Fatalf("APIKeys.Revoke: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/api-keys/"+id+"/revoke"), models.MessageResponse{})
+	})
+}
+
+func TestAdminContract(t *testing.T) {
+	ctx := context.Background()
+	client, rec := signIn(t)
+
+	if _, err := client.Admin.GetStats(ctx); err != nil {
+		skipIfForbidden(t, err)
+		t.Fatalf("Admin.GetStats: %v", err)
+	}
+
+	t.Run("Stats", func(t *testing.T) {
+		assertContract(t, rec.body(t, "GET /api/admin/stats"), models.SystemStats{})
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		users, err := client.Admin.ListUsers(ctx, &models.ListUsersParams{Page: 1, Limit: 5})
+		if err != nil {
+			t.Fatalf("Admin.ListUsers: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/users"), struct {
+			Users []models.User `json:"users"`
+		}{})
+		if len(users.Items) == 0 || users.Pagination.Total == 0 {
+			t.Errorf("expected users and pagination, got %d items and %+v", len(users.Items), users.Pagination)
+		}
+	})
+
+	t.Run("RBAC", func(t *testing.T) {
+		if _, err := client.Admin.ListPermissions(ctx); err != nil {
+			t.Fatalf("Admin.ListPermissions: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/rbac/permissions"), struct {
+			Permissions []models.Permission `json:"permissions"`
+		}{})
+
+		if _, err := client.Admin.ListRoles(ctx); err != nil {
+			t.Fatalf("Admin.ListRoles: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/rbac/roles"), struct {
+			Roles []models.Role `json:"roles"`
+		}{})
+	})
+
+	t.Run("APIKeys", func(t *testing.T) {
+		if _, err := client.Admin.ListAllAPIKeys(ctx); err != nil {
+			t.Fatalf("Admin.ListAllAPIKeys: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/api-keys"), struct {
+			APIKeys []models.APIKey `json:"api_keys"`
+		}{})
+	})
+
+	t.Run("AuditLogs", func(t *testing.T) {
+		if _, err := client.Admin.ListAuditLogs(ctx, &models.ListAuditLogsParams{Page: 1, Limit: 5}); err != nil {
+			t.Fatalf("Admin.ListAuditLogs: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/audit-logs"), struct {
+			Logs []models.AuditLog `json:"logs"`
+		}{})
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		if _, err := client.Admin.ListAllSessions(ctx, nil); err != nil {
+			t.Fatalf("Admin.ListAllSessions: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/sessions"), struct {
+			Sessions []models.Session `json:"sessions"`
+		}{})
+
+		if _, err := client.Admin.GetSessionStats(ctx); err != nil {
+			t.Fatalf("Admin.GetSessionStats: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/sessions/stats"), models.SessionStats{})
+	})
+
+	t.Run("IPFilters", func(t *testing.T) {
+		created, err := client.Admin.CreateIPFilter(ctx, &models.CreateIPFilterRequest{
+			IPCIDR:     "198.51.100.0/24",
+			FilterType: "blacklist",
+			Reason:     "contract test",
+		})
+		if err != nil {
+			t.Fatalf("Admin.CreateIPFilter: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/admin/ip-filters"), models.IPFilter{})
+		t.Cleanup(func() {
+			if err := client.Admin.DeleteIPFilter(context.Background(), created.ID); err != nil {
+				t.Errorf("Admin.DeleteIPFilter: %v", err)
+			}
+		})
+
+		if _, err := client.Admin.ListIPFilters(ctx); err != nil {
+			t.Fatalf("Admin.ListIPFilters: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/ip-filters"), struct {
+			Filters []models.IPFilter `json:"filters"`
+		}{})
+	})
+
+	t.Run("Webhooks", func(t *testing.T) {
+		created, err := client.Admin.CreateWebhook(ctx, &models.CreateWebhookRequest{
+			Name:   "contract test",
+			URL:    "https://example.com/contract-test",
+			Events: []string{"user.created"},
+		})
+		if err != nil {
+			t.Fatalf("Admin.CreateWebhook: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/admin/webhooks"), models.CreateWebhookResponse{})
+		id := created.Webhook.ID
+		t.Cleanup(func() {
+			if err := client.Admin.DeleteWebhook(context.Background(), id); err != nil {
+				t.Errorf("Admin.DeleteWebhook: %v", err)
+			}
+		})
+
+		if _, err := client.Admin.GetWebhook(ctx, id); err != nil {
+			t.Fatalf("Admin.GetWebhook: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/webhooks/"+id), models.Webhook{})
+
+		if _, err := client.Admin.ListWebhooks(ctx, nil); err != nil {
+			t.Fatalf("Admin.ListWebhooks: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/webhooks"), models.WebhookListResponse{})
+
+		if _, err := client.Admin.ListWebhookDeliveries(ctx, id, nil); err != nil {
+			t.Fatalf("Admin.ListWebhookDeliveries: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/webhooks/"+id+"/deliveries"), models.WebhookDeliveryListResponse{})
+
+		events, err := client.Admin.ListWebhookEvents(ctx)
+		if err != nil {
+			t.Fatalf("Admin.ListWebhookEvents: %v", err)
+		}
+		if len(events) == 0 {
+			t.Error("expected webhook event types")
+		}
+	})
+
+	t.Run("EmailTemplates", func(t *testing.T) {
+		if _, err := client.Admin.ListEmailTemplates(ctx); err != nil {
+			t.Fatalf("Admin.ListEmailTemplates: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/templates"), models.EmailTemplateListResponse{})
+
+		types, err := client.Admin.ListEmailTemplateTypes(ctx)
+		if err != nil {
+			t.Fatalf("Admin.ListEmailTemplateTypes: %v", err)
+		}
+		if len(types) == 0 {
+			t.Error("expected email template types")
+		}
+
+		if _, err := client.Admin.PreviewEmailTemplate(ctx, &models.PreviewEmailTemplateRequest{
+			HTMLBody:  "<p>Hello {{.username}}</p>",
+			Variables: map[string]interface{}{"username": "contract"},
+		}); err != nil {
+			t.Fatalf("Admin.PreviewEmailTemplate: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/admin/templates/preview"), models.PreviewEmailTemplateResponse{})
+	})
+
+	t.Run("OAuthClients", func(t *testing.T) {
+		created, err := client.Admin.OAuthClients.Create(ctx, &models.CreateOAuthClientRequest{
+			Name:              "contract test",
+			ClientType:        "confidential",
+			RedirectURIs:      []string{"https://example.com/contract-test/callback"},
+			AllowedGrantTypes: []string{"authorization_code"},
+			AllowedScopes:     []string{"openid"},
+		})
+		if err != nil {
+			t.Fatalf("Admin.OAuthClients.Create: %v", err)
+		}
+		assertContract(t, rec.body(t, "POST /api/admin/oauth/clients"), models.CreateOAuthClientResponse{})
+		if created.ClientSecret == "" {
+			t.Error("expected the secret of the confidential client")
+		}
+		id := created.Client.ID
+		t.Cleanup(func() {
+			if err := client.Admin.OAuthClients.Delete(context.Background(), id); err != nil {
+				t.Errorf("Admin.OAuthClients.Delete: %v", err)
+			}
+		})
+
+		if _, err := client.Admin.OAuthClients.Get(ctx, id); err != nil {
+			t.Fatalf("Admin.OAuthClients.Get: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/oauth/clients/"+id), models.OAuthClient{})
+
+		if _, err := client.Admin.OAuthClients.List(ctx, nil); err != nil {
+			t.Fatalf("Admin.OAuthClients.List: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/oauth/clients"), models.ListOAuthClientsResponse{})
+
+		rotated, err := client.Admin.OAuthClients.RotateSecret(ctx, id)
+		if err != nil {
+			t.Fatalf("Admin.OAuthClients.RotateSecret: %v", err)
+		}
+		if rotated.ClientSecret == "" || rotated.ClientSecret == created.ClientSecret {
+			t.Error("expected a new client secret")
+		}
+
+		if _, err := client.Admin.OAuthClients.ListScopes(ctx); err != nil {
+			t.Fatalf("Admin.OAuthClients.ListScopes: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/oauth/scopes"), models.ListScopesResponse{})
+	})
+
+	t.Run("Branding", func(t *testing.T) {
+		if _, err := client.Admin.GetBranding(ctx); err != nil {
+			t.Fatalf("Admin.GetBranding: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/branding"), models.PublicBranding{})
+	})
+
+	t.Run("GeoDistribution", func(t *testing.T) {
+		if _, err := client.Admin.GetGeoDistribution(ctx); err != nil {
+			t.Fatalf("Admin.GetGeoDistribution: %v", err)
+		}
+		assertContract(t, rec.body(t, "GET /api/admin/analytics/geo-distribution"), models.GeoDistribution{})
+	})
+}
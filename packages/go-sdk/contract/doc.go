@@ -0,0 +1,24 @@
+// Package contract holds the contract tests between the Go SDK and a running Auth Gateway.
+//
+// The tests call the backend through the SDK and check every response body against the
+// SDK model it is decoded into: each field the SDK expects (no omitempty) must be present
+// in the response, recursively. Keys the backend sends but the SDK does not model are
+// logged, not failed. This catches renamed fields and envelope changes that would
+// otherwise decode silently into zero values.
+//
+// The suite is skipped unless a base URL is given, so it is safe to run with the rest of
+// the module's tests:
+//
+//	go test ./contract/... -contract.base-url=http://localhost:3000
+//
+// Endpoints that need a user sign in with -contract.email and -contract.password. Admin
+// endpoints are skipped when that user is not an admin. API keys and IP filters created
+// by the suite are deleted again.
+//
+// Every flag falls back to an environment variable, for CI systems that cannot pass test
+// flags:
+//
+//	AUTH_GATEWAY_CONTRACT_BASE_URL
+//	AUTH_GATEWAY_CONTRACT_EMAIL
+//	AUTH_GATEWAY_CONTRACT_PASSWORD
+package contract
@@ -3,6 +3,9 @@ package authgateway
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
 )
 
 // Error codes
@@ -21,6 +24,7 @@ const (
 	ErrCodeAccountDisabled    = "ACCOUNT_DISABLED"
 	ErrCodeTokenExpired       = "TOKEN_EXPIRED"
 	ErrCodeInvalidToken       = "INVALID_TOKEN"
+	ErrCodePasswordPolicy     = "PASSWORD_POLICY_VIOLATION"
 )
 
 // APIError represents an error returned by the Auth Gateway API.
@@ -72,6 +76,34 @@ func (e *TwoFactorRequiredError) Error() string {
 	return "two-factor authentication required"
 }
 
+// PasswordPolicyError is returned when a new password breaks the password policy, at
+// sign-up, password change, password reset or admin user creation.
+type PasswordPolicyError struct {
+	Message    string
+	Violations []models.PasswordPolicyViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	if len(messages) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(messages, "; "))
+}
+
+// Broke reports whether the password broke rule, such as "compromised" or "history".
+func (e *PasswordPolicyError) Broke(rule string) bool {
+	for _, violation := range e.Violations {
+		if violation.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
 // AuthenticationError is returned for authentication failures.
 type AuthenticationError struct {
 	Message string
@@ -0,0 +1,56 @@
+package authgateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseErrorResponse(t *testing.T) {
+	t.Run("ShouldReturnPasswordPolicyError_WhenPasswordBreaksPolicy", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/change-password", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad Request","message":"Password does not meet the password policy","code":"PASSWORD_POLICY_VIOLATION",
+				"violations":[{"rule":"min_length","message":"Password must be at least 12 characters long"},
+				{"rule":"dictionary","message":"Password is too common. Please choose a different password."}]}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		_, err := client.Profile.ChangePassword(context.Background(), "old-password", "password1")
+
+		// Assert
+		var policyErr *PasswordPolicyError
+		if !errors.As(err, &policyErr) {
+			t.Fatalf("expected *PasswordPolicyError, got %v", err)
+		}
+		if len(policyErr.Violations) != 2 || !policyErr.Broke("min_length") || !policyErr.Broke("dictionary") || policyErr.Broke("history") {
+			t.Errorf("unexpected violations: %+v", policyErr.Violations)
+		}
+		if policyErr.Error() != "Password does not meet the password policy: Password must be at least 12 characters long; Password is too common. Please choose a different password." {
+			t.Errorf("unexpected message: %q", policyErr.Error())
+		}
+	})
+
+	t.Run("ShouldReturnAPIError_WhenCodeIsOther", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/auth/change-password", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Unauthorized","message":"Invalid credentials"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		_, err := client.Profile.ChangePassword(context.Background(), "old-password", "password1")
+
+		// Assert
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 *APIError, got %v", err)
+		}
+	})
+}
@@ -35,9 +35,8 @@ func main() {
 	} else {
 		fmt.Printf("Total Users: %d\n", stats.TotalUsers)
 		fmt.Printf("Active Users: %d\n", stats.ActiveUsers)
-		fmt.Printf("Total Sessions: %d\n", stats.TotalSessions)
 		fmt.Printf("Total API Keys: %d\n", stats.TotalAPIKeys)
-		fmt.Printf("2FA Enabled Users: %d\n", stats.TwoFAEnabledUsers)
+		fmt.Printf("2FA Enabled Users: %d\n", stats.Users2FAEnabled)
 	}
 
 	// Example 2: List users with pagination
@@ -116,9 +115,9 @@ func main() {
 	} else {
 		for _, logEntry := range logsResp.Items {
 			fmt.Printf("- [%s] %s: %s (%s)\n",
-				logEntry.Timestamp.Format("2006-01-02 15:04"),
+				logEntry.CreatedAt.Format("2006-01-02 15:04"),
 				logEntry.Action,
-				logEntry.Resource,
+				logEntry.IP,
 				logEntry.Status)
 		}
 	}
@@ -146,7 +145,7 @@ func main() {
 			fmt.Println("No IP filters configured")
 		} else {
 			for _, filter := range filters {
-				fmt.Printf("- %s: %s (%s)\n", filter.Type, filter.IPAddress, filter.Description)
+				fmt.Printf("- %s: %s (%s)\n", filter.FilterType, filter.IPCIDR, filter.Reason)
 			}
 		}
 	}
@@ -154,14 +153,14 @@ func main() {
 	// Example 9: Create IP filter
 	fmt.Println("\n=== Create IP Filter ===")
 	newFilter, err := client.Admin.CreateIPFilter(ctx, &models.CreateIPFilterRequest{
-		IPAddress:   "192.168.1.100",
-		Type:        "whitelist",
-		Description: "Office IP",
+		IPCIDR:     "192.168.1.100",
+		FilterType: "whitelist",
+		Reason:     "Office IP",
 	})
 	if err != nil {
 		log.Printf("Failed to create IP filter: %v", err)
 	} else {
-		fmt.Printf("Created IP filter: %s (%s)\n", newFilter.IPAddress, newFilter.Type)
+		fmt.Printf("Created IP filter: %s (%s)\n", newFilter.IPCIDR, newFilter.FilterType)
 	}
 
 	// Example 10: Get geo distribution
@@ -170,8 +169,8 @@ func main() {
 	if err != nil {
 		log.Printf("Failed to get geo distribution: %v", err)
 	} else {
-		for _, geo := range geoDist {
-			fmt.Printf("- %s: %d users\n", geo.Country, geo.Count)
+		for _, loc := range geoDist.Locations {
+			fmt.Printf("- %s %s: %d logins\n", loc.CountryName, loc.City, loc.LoginCount)
 		}
 	}
 }
@@ -58,5 +58,5 @@ func main() {
 		log.Printf("Health check failed: %v", err)
 		return
 	}
-	fmt.Printf("Status: %s, Database: %s, Redis: %s\n", health.Status, health.Database, health.Redis)
+	fmt.Printf("Status: %s, Database: %s, Redis: %s\n", health.Status, health.Services["database"], health.Services["redis"])
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
@@ -23,6 +24,9 @@ type GRPCClient struct {
 	metadataMu sync.RWMutex
 
 	apiKey string
+
+	// cache holds validated tokens; nil unless GRPCConfig.ValidationCache is set
+	cache *tokenValidationCache
 }
 
 // GRPCConfig contains configuration for the gRPC client.
@@ -33,6 +37,10 @@ type GRPCConfig struct {
 	// Insecure disables TLS (for development)
 	Insecure bool
 
+	// TLS configures server verification and the client certificate for mutual TLS.
+	// Nil leaves transport credentials to DialOptions.
+	TLS *GRPCTLSConfig
+
 	// DialTimeout is the timeout for establishing the connection
 	DialTimeout time.Duration
 
@@ -47,6 +55,9 @@ type GRPCConfig struct {
 	// Supports both API keys (agw_...) and application secrets (app_...).
 	// When using an app secret, application_id is automatically resolved from context.
 	APIKey string
+
+	// ValidationCache caches ValidateToken results in process. Nil disables it.
+	ValidationCache *ValidationCacheConfig
 }
 
 // NewGRPCClient creates a new gRPC client for the Auth Gateway.
@@ -59,10 +70,21 @@ func NewGRPCClient(config GRPCConfig) (*GRPCClient, error) {
 		config.DialTimeout = 10 * time.Second
 	}
 
+	if config.Insecure && config.TLS != nil {
+		return nil, fmt.Errorf("insecure and TLS options are mutually exclusive")
+	}
+
 	opts := config.DialOptions
 	if config.Insecure {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure gRPC TLS: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
 
 	conn, err := grpc.NewClient(config.Address, opts...)
 	if err != nil {
@@ -75,6 +97,9 @@ func NewGRPCClient(config GRPCConfig) (*GRPCClient, error) {
 		metadata: config.Metadata,
 		apiKey:   config.APIKey,
 	}
+	if config.ValidationCache != nil {
+		c.cache = newTokenValidationCache(*config.ValidationCache)
+	}
 
 	if config.APIKey != "" {
 		c.SetMetadata("x-api-key", config.APIKey)
@@ -203,7 +228,17 @@ func (c *GRPCClient) Close() error {
 }
 
 // ValidateToken validates a JWT access token and returns user information.
+// With GRPCConfig.ValidationCache set, valid tokens are answered from the cache.
 func (c *GRPCClient) ValidateToken(ctx context.Context, accessToken string) (*proto.ValidateTokenResponse, error) {
+	if c.cache != nil {
+		return c.cache.validate(ctx, accessToken, func(ctx context.Context) (*proto.ValidateTokenResponse, error) {
+			return c.validateToken(ctx, accessToken)
+		})
+	}
+	return c.validateToken(ctx, accessToken)
+}
+
+func (c *GRPCClient) validateToken(ctx context.Context, accessToken string) (*proto.ValidateTokenResponse, error) {
 	resp, err := c.client.ValidateToken(c.withMetadata(ctx), &proto.ValidateTokenRequest{
 		AccessToken: accessToken,
 	})
@@ -242,10 +277,28 @@ func (c *GRPCClient) GetUser(ctx context.Context, userID string) (*proto.User, e
 
 // CheckPermission checks if a user has a specific permission.
 func (c *GRPCClient) CheckPermission(ctx context.Context, userID, resource, action string) (*proto.CheckPermissionResponse, error) {
+	return c.CheckPermissionWithAttributes(ctx, userID, resource, action, PermissionAttributes{})
+}
+
+// PermissionAttributes are the attributes conditional permissions are evaluated against.
+// The gateway adds the user.* attributes of the user and the time.* attributes of the check.
+type PermissionAttributes struct {
+	// Resource attributes, read by conditions as resource.<key> (e.g. owner_id)
+	Resource map[string]string
+	// Request attributes, read by conditions as context.<key> (e.g. ip_country)
+	Context map[string]string
+}
+
+// CheckPermissionWithAttributes checks if a user has a specific permission, evaluating
+// the conditions of conditional permissions (e.g. resource.owner_id == user.id) against
+// the given attributes.
+func (c *GRPCClient) CheckPermissionWithAttributes(ctx context.Context, userID, resource, action string, attrs PermissionAttributes) (*proto.CheckPermissionResponse, error) {
 	resp, err := c.client.CheckPermission(c.withMetadata(ctx), &proto.CheckPermissionRequest{
-		UserId:   userID,
-		Resource: resource,
-		Action:   action,
+		UserId:             userID,
+		Resource:           resource,
+		Action:             action,
+		ResourceAttributes: attrs.Resource,
+		ContextAttributes:  attrs.Context,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to check permission: %w", err)
@@ -270,6 +323,28 @@ func (c *GRPCClient) HasPermission(ctx context.Context, userID, resource, action
 	return resp.Allowed, nil
 }
 
+// RegisterPermissionCatalog publishes the resources and actions this service checks
+// to the gateway's permissions catalog, replacing the ones it registered before.
+// Requires an API key with the rbac:register scope.
+func (c *GRPCClient) RegisterPermissionCatalog(ctx context.Context, serviceName string, entries []*proto.PermissionCatalogEntry) (int32, error) {
+	resp, err := c.client.RegisterPermissionCatalog(c.withMetadata(ctx), &proto.RegisterPermissionCatalogRequest{
+		Service: serviceName,
+		Entries: entries,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to register permission catalog: %w", err)
+	}
+
+	if resp.ErrorMessage != "" {
+		return 0, &APIError{
+			Code:    ErrCodeBadRequest,
+			Message: resp.ErrorMessage,
+		}
+	}
+
+	return resp.Registered, nil
+}
+
 // IntrospectToken provides detailed information about a token.
 func (c *GRPCClient) IntrospectToken(ctx context.Context, accessToken string) (*proto.IntrospectTokenResponse, error) {
 	resp, err := c.client.IntrospectToken(c.withMetadata(ctx), &proto.IntrospectTokenRequest{
@@ -289,6 +364,47 @@ func (c *GRPCClient) IntrospectToken(ctx context.Context, accessToken string) (*
 	return resp, nil
 }
 
+// WatchRevocations subscribes to token, session and user revocations as they happen, so
+// cached validation results can be dropped without polling IntrospectToken. Pass a user ID
+// to receive only that user's revocations (and global ones), or "" for all of them.
+// Requires an API key with the token:introspect scope.
+//
+// Events are delivered on the first channel until ctx is cancelled or the stream fails;
+// both channels are then closed. A stream failure is sent on the error channel first.
+// The server ends the stream with codes.Unavailable when events may have been missed:
+// flush any cached validation results and call WatchRevocations again.
+func (c *GRPCClient) WatchRevocations(ctx context.Context, userID string) (<-chan *proto.RevocationEvent, <-chan error, error) {
+	stream, err := c.client.WatchRevocations(c.withMetadata(ctx), &proto.WatchRevocationsRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to watch revocations: %w", err)
+	}
+
+	events := make(chan *proto.RevocationEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- fmt.Errorf("revocation stream ended: %w", err)
+				}
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, errs, nil
+}
+
 // CreateUser creates a new user account via gRPC.
 func (c *GRPCClient) CreateUser(ctx context.Context, req *proto.CreateUserRequest) (*proto.CreateUserResponse, error) {
 	resp, err := c.client.CreateUser(c.withMetadata(ctx), req)
@@ -579,6 +695,114 @@ func (c *GRPCClient) GetUserTelegramBots(ctx context.Context, userID, applicatio
 	return resp, nil
 }
 
+// ========== User Management Methods ==========
+
+// ListUsers lists users with pagination. Search matches email, username or full name;
+// ApplicationId restricts the list to the users of an application. The two cannot be combined.
+func (c *GRPCClient) ListUsers(ctx context.Context, req *proto.ListUsersRequest) (*proto.ListUsersResponse, error) {
+	resp, err := c.client.ListUsers(c.withMetadata(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return resp, nil
+}
+
+// GetUserByEmail retrieves user information by email address.
+func (c *GRPCClient) GetUserByEmail(ctx context.Context, email string) (*proto.User, error) {
+	resp, err := c.client.GetUserByEmail(c.withMetadata(ctx), &proto.GetUserByEmailRequest{
+		Email: email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return resp.User, nil
+}
+
+// UpdateUser updates a user's account. Empty fields are left unchanged.
+// Requires the users:write scope; an API key must belong to an admin.
+func (c *GRPCClient) UpdateUser(ctx context.Context, req *proto.UpdateUserRequest) (*proto.User, error) {
+	resp, err := c.client.UpdateUser(c.withMetadata(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return resp.User, nil
+}
+
+// DeactivateUser deactivates a user's account and revokes their tokens.
+// Requires the users:write scope; an API key must belong to an admin.
+func (c *GRPCClient) DeactivateUser(ctx context.Context, userID, reason string) (*proto.User, error) {
+	resp, err := c.client.DeactivateUser(c.withMetadata(ctx), &proto.DeactivateUserRequest{
+		UserId: userID,
+		Reason: reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	return resp.User, nil
+}
+
+// AssignRole assigns a role, identified by RoleId or RoleName, to a user.
+// Requires the admin:all scope; an API key must belong to an admin.
+func (c *GRPCClient) AssignRole(ctx context.Context, req *proto.AssignRoleRequest) (*proto.User, error) {
+	resp, err := c.client.AssignRole(c.withMetadata(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign role: %w", err)
+	}
+	return resp.User, nil
+}
+
+// RemoveRole removes a role, identified by RoleId or RoleName, from a user. A user's last
+// role cannot be removed.
+// Requires the admin:all scope; an API key must belong to an admin.
+func (c *GRPCClient) RemoveRole(ctx context.Context, req *proto.RemoveRoleRequest) (*proto.User, error) {
+	resp, err := c.client.RemoveRole(c.withMetadata(ctx), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove role: %w", err)
+	}
+	return resp.User, nil
+}
+
+// ========== Session Management Methods ==========
+
+// ListUserSessions lists the active sessions of a user with pagination.
+// Requires the sessions:read scope.
+func (c *GRPCClient) ListUserSessions(ctx context.Context, userID string, page, pageSize int32) (*proto.ListUserSessionsResponse, error) {
+	resp, err := c.client.ListUserSessions(c.withMetadata(ctx), &proto.ListUserSessionsRequest{
+		UserId:   userID,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user sessions: %w", err)
+	}
+	return resp, nil
+}
+
+// RevokeSession revokes one session of a user and blacklists its tokens.
+// Requires the sessions:revoke scope; an API key must belong to an admin.
+func (c *GRPCClient) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	_, err := c.client.RevokeSession(c.withMetadata(ctx), &proto.RevokeSessionRequest{
+		UserId:    userID,
+		SessionId: sessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllUserSessions revokes every session of a user and blacklists their tokens.
+// Requires the sessions:revoke scope; an API key must belong to an admin.
+func (c *GRPCClient) RevokeAllUserSessions(ctx context.Context, userID string) error {
+	_, err := c.client.RevokeAllUserSessions(c.withMetadata(ctx), &proto.RevokeAllUserSessionsRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+	return nil
+}
+
 // ========== Sync & Config Methods ==========
 
 // SyncUsers returns users updated after a given timestamp for shadow table sync.
@@ -0,0 +1,269 @@
+package authgateway
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultValidationCacheTTL is how long a validated token is cached
+	DefaultValidationCacheTTL = 30 * time.Second
+	// DefaultValidationCacheMaxEntries bounds the number of cached tokens
+	DefaultValidationCacheMaxEntries = 10000
+
+	revocationWatchMaxBackoff = 30 * time.Second
+)
+
+// ValidationCacheConfig enables caching of ValidateToken results in the
+// GRPCClient. Only valid tokens are cached, for TTL or until they expire if that
+// is sooner. Concurrent validations of the same token share one call.
+//
+// A cached token stays valid after it is revoked until its entry expires, unless
+// the client follows the revocation stream with InvalidateOnRevocations.
+// Results are cached per token, so per-call metadata must not change the answer.
+type ValidationCacheConfig struct {
+	// TTL defaults to DefaultValidationCacheTTL
+	TTL time.Duration
+	// MaxEntries defaults to DefaultValidationCacheMaxEntries; the least recently
+	// used token is evicted first
+	MaxEntries int
+}
+
+type validationEntry struct {
+	key       string // SHA-256 hex of the token, as in RevocationEvent.TokenHash
+	resp      *proto.ValidateTokenResponse
+	expiresAt time.Time
+}
+
+// tokenValidationCache is an LRU cache of validation results keyed by token hash
+type tokenValidationCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	entries    map[string]*list.Element
+	lru        *list.List // front is most recently used
+	// generation is bumped by every invalidation, so results fetched while one
+	// happened are not cached
+	generation uint64
+
+	flights singleflight.Group
+}
+
+func newTokenValidationCache(cfg ValidationCacheConfig) *tokenValidationCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultValidationCacheTTL
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultValidationCacheMaxEntries
+	}
+	return &tokenValidationCache{
+		ttl:        cfg.TTL,
+		maxEntries: cfg.MaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// validate returns the cached result for token or calls fetch once for all
+// concurrent callers
+func (c *tokenValidationCache) validate(ctx context.Context, token string, fetch func(ctx context.Context) (*proto.ValidateTokenResponse, error)) (*proto.ValidateTokenResponse, error) {
+	key := tokenHash(token)
+	resp, generation, ok := c.get(key)
+	if ok {
+		return resp, nil
+	}
+
+	ch := c.flights.DoChan(key, func() (interface{}, error) {
+		// The call is shared, so one caller giving up must not fail the others
+		callCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithDeadline(callCtx, deadline)
+			defer cancel()
+		}
+		resp, err := fetch(callCtx)
+		if err == nil && resp.GetValid() {
+			c.set(key, resp, generation)
+		}
+		return resp, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*proto.ValidateTokenResponse), nil
+	}
+}
+
+func (c *tokenValidationCache) get(key string) (*proto.ValidateTokenResponse, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	entry := elem.Value.(*validationEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, c.generation, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.resp, c.generation, true
+}
+
+func (c *tokenValidationCache) set(key string, resp *proto.ValidateTokenResponse, generation uint64) {
+	expiresAt := c.now().Add(c.ttl)
+	if resp.GetExpiresAt() > 0 {
+		if tokenExpiry := time.Unix(resp.GetExpiresAt(), 0); tokenExpiry.Before(expiresAt) {
+			expiresAt = tokenExpiry
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&validationEntry{key: key, resp: resp, expiresAt: expiresAt})
+	for c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *tokenValidationCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*validationEntry).key)
+}
+
+// invalidate drops the entries matching drop, or all of them for a nil drop
+func (c *tokenValidationCache) invalidate(drop func(*validationEntry) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if drop == nil {
+		c.entries = make(map[string]*list.Element)
+		c.lru.Init()
+		return
+	}
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if drop(elem.Value.(*validationEntry)) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+// applyRevocation drops the entries a revocation event affects. Validation
+// results carry no session, so a session revocation drops the user's entries.
+func (c *tokenValidationCache) applyRevocation(event *proto.RevocationEvent) {
+	switch event.GetType() {
+	case "token":
+		hash := event.GetTokenHash()
+		c.invalidate(func(e *validationEntry) bool { return e.key == hash })
+	case "session", "user":
+		if userID := event.GetUserId(); userID != "" {
+			c.invalidate(func(e *validationEntry) bool { return e.resp.GetUserId() == userID })
+			return
+		}
+		c.invalidate(nil)
+	default:
+		c.invalidate(nil)
+	}
+}
+
+// InvalidateToken drops token from the validation cache
+func (c *GRPCClient) InvalidateToken(token string) {
+	if c.cache == nil {
+		return
+	}
+	hash := tokenHash(token)
+	c.cache.invalidate(func(e *validationEntry) bool { return e.key == hash })
+}
+
+// InvalidateRevocation drops the cached tokens a revocation event affects. Use it
+// when consuming WatchRevocations yourself; InvalidateOnRevocations calls it for
+// every event.
+func (c *GRPCClient) InvalidateRevocation(event *proto.RevocationEvent) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.applyRevocation(event)
+}
+
+// FlushValidationCache drops every cached token
+func (c *GRPCClient) FlushValidationCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.invalidate(nil)
+}
+
+// InvalidateOnRevocations follows the revocation stream and drops revoked tokens
+// from the validation cache, until ctx is done. Run it in its own goroutine.
+// Whenever the stream ends the cache is flushed, since events may have been
+// missed, and the stream is resubscribed with backoff. It returns at once on
+// errors retrying will not fix, such as an API key without the token:introspect
+// scope.
+func (c *GRPCClient) InvalidateOnRevocations(ctx context.Context) error {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		events, errs, err := c.WatchRevocations(ctx, "")
+		if err == nil {
+			for event := range events {
+				c.InvalidateRevocation(event)
+			}
+			err = <-errs
+		}
+		c.FlushValidationCache()
+		if time.Since(started) > revocationWatchMaxBackoff {
+			backoff = time.Second
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.PermissionDenied, codes.Unimplemented:
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > revocationWatchMaxBackoff {
+			backoff = revocationWatchMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package authgateway
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeAuthServiceClient answers ValidateToken with the user named by the token
+type fakeAuthServiceClient struct {
+	proto.AuthServiceClient
+	calls   int32
+	release chan struct{} // blocks ValidateToken until closed, if set
+	events  chan *proto.RevocationEvent
+}
+
+func (f *fakeAuthServiceClient) ValidateToken(ctx context.Context, in *proto.ValidateTokenRequest, _ ...grpc.CallOption) (*proto.ValidateTokenResponse, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.release != nil {
+		<-f.release
+	}
+	if in.AccessToken == "bad" {
+		return &proto.ValidateTokenResponse{ErrorMessage: "token expired"}, nil
+	}
+	return &proto.ValidateTokenResponse{Valid: true, UserId: "user-" + in.AccessToken}, nil
+}
+
+func (f *fakeAuthServiceClient) WatchRevocations(ctx context.Context, _ *proto.WatchRevocationsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[proto.RevocationEvent], error) {
+	return &fakeRevocationStream{ctx: ctx, events: f.events}, nil
+}
+
+type fakeRevocationStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events chan *proto.RevocationEvent
+}
+
+func (s *fakeRevocationStream) Recv() (*proto.RevocationEvent, error) {
+	select {
+	case event := <-s.events:
+		return event, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func newCachingTestClient(cfg ValidationCacheConfig) (*GRPCClient, *fakeAuthServiceClient) {
+	fake := &fakeAuthServiceClient{events: make(chan *proto.RevocationEvent)}
+	return &GRPCClient{client: fake, cache: newTokenValidationCache(cfg)}, fake
+}
+
+func TestGRPCClient_ValidateToken_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ShouldAnswerRepeatedValidationsFromCache", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{})
+
+		// Act
+		for i := 0; i < 3; i++ {
+			if _, err := client.ValidateToken(ctx, "a"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		// Assert
+		if fake.calls != 1 {
+			t.Errorf("expected 1 call, got %d", fake.calls)
+		}
+	})
+
+	t.Run("ShouldShareConcurrentValidations", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{})
+		fake.release = make(chan struct{})
+		var wg sync.WaitGroup
+
+		// Act
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				client.ValidateToken(ctx, "a")
+			}()
+		}
+		time.Sleep(20 * time.Millisecond)
+		close(fake.release)
+		wg.Wait()
+
+		// Assert
+		if got := atomic.LoadInt32(&fake.calls); got != 1 {
+			t.Errorf("expected 1 call, got %d", got)
+		}
+	})
+
+	t.Run("ShouldNotCacheInvalidTokens", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{})
+
+		// Act
+		_, err1 := client.ValidateToken(ctx, "bad")
+		_, err2 := client.ValidateToken(ctx, "bad")
+
+		// Assert
+		if err1 == nil || err2 == nil {
+			t.Fatalf("expected errors, got %v and %v", err1, err2)
+		}
+		if fake.calls != 2 {
+			t.Errorf("expected 2 calls, got %d", fake.calls)
+		}
+	})
+
+	t.Run("ShouldExpireEntriesAfterTTL", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{TTL: time.Minute})
+		now := time.Now()
+		client.cache.now = func() time.Time { return now }
+		client.ValidateToken(ctx, "a")
+
+		// Act
+		now = now.Add(time.Minute)
+		client.ValidateToken(ctx, "a")
+
+		// Assert
+		if fake.calls != 2 {
+			t.Errorf("expected 2 calls, got %d", fake.calls)
+		}
+	})
+
+	t.Run("ShouldEvictLeastRecentlyUsed", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{MaxEntries: 2})
+		client.ValidateToken(ctx, "a")
+		client.ValidateToken(ctx, "b")
+		client.ValidateToken(ctx, "a")
+
+		// Act
+		client.ValidateToken(ctx, "c")
+		client.ValidateToken(ctx, "a")
+		client.ValidateToken(ctx, "b")
+
+		// Assert
+		if fake.calls != 4 {
+			t.Errorf("expected a, b, c and the evicted b to be fetched, got %d calls", fake.calls)
+		}
+	})
+}
+
+func TestGRPCClient_InvalidateRevocation(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name      string
+		event     *proto.RevocationEvent
+		refetched int32
+	}{
+		{"ShouldDropRevokedToken", &proto.RevocationEvent{Type: "token", TokenHash: tokenHash("a")}, 1},
+		{"ShouldDropTokensOfRevokedUser", &proto.RevocationEvent{Type: "user", UserId: "user-b"}, 1},
+		{"ShouldDropTokensOfUserOfRevokedSession", &proto.RevocationEvent{Type: "session", SessionId: "s-1", UserId: "user-a"}, 1},
+		{"ShouldDropEverything_WhenAllRevoked", &proto.RevocationEvent{Type: "all"}, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			client, fake := newCachingTestClient(ValidationCacheConfig{})
+			client.ValidateToken(ctx, "a")
+			client.ValidateToken(ctx, "b")
+
+			// Act
+			client.InvalidateRevocation(tc.event)
+			client.ValidateToken(ctx, "a")
+			client.ValidateToken(ctx, "b")
+
+			// Assert
+			if got := fake.calls - 2; got != tc.refetched {
+				t.Errorf("expected %d tokens to be fetched again, got %d", tc.refetched, got)
+			}
+		})
+	}
+}
+
+func TestGRPCClient_InvalidateOnRevocations(t *testing.T) {
+	t.Run("ShouldApplyStreamedEvents", func(t *testing.T) {
+		// Arrange
+		client, fake := newCachingTestClient(ValidationCacheConfig{})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- client.InvalidateOnRevocations(ctx) }()
+		client.ValidateToken(context.Background(), "a")
+
+		// Act
+		fake.events <- &proto.RevocationEvent{Type: "token", TokenHash: tokenHash("a")}
+		// Events pass through WatchRevocations one at a time: once the third is
+		// received, the first has been applied
+		fake.events <- &proto.RevocationEvent{Type: "token", TokenHash: tokenHash("other")}
+		fake.events <- &proto.RevocationEvent{Type: "token", TokenHash: tokenHash("other")}
+		client.ValidateToken(context.Background(), "a")
+		cancel()
+
+		// Assert
+		if err := <-done; err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if got := atomic.LoadInt32(&fake.calls); got != 2 {
+			t.Errorf("expected the revoked token to be fetched again, got %d calls", got)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package authgateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// GRPCTLSConfig configures TLS and mutual TLS for the gRPC client.
+type GRPCTLSConfig struct {
+	// CAFile is a PEM bundle of CAs trusted to sign the server certificate.
+	// Empty uses RootCAs, or the system roots when RootCAs is nil too.
+	CAFile string
+
+	// RootCAs is the pool of CAs trusted to sign the server certificate; ignored when CAFile is set.
+	RootCAs *x509.CertPool
+
+	// CertFile and KeyFile hold the client certificate presented for mutual TLS.
+	// They are read again when they change, so certificates rotated on disk
+	// (cert-manager, spiffe-helper) are used without recreating the client.
+	CertFile string
+	KeyFile  string
+
+	// Certificate is the client certificate presented for mutual TLS; ignored when CertFile is set.
+	Certificate *tls.Certificate
+
+	// ServerName overrides the name the server certificate is verified against,
+	// e.g. when connecting by IP or through a proxy.
+	ServerName string
+}
+
+// build returns the crypto/tls configuration of the client
+func (c *GRPCTLSConfig) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    c.RootCAs,
+		ServerName: c.ServerName,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates in CA file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case c.CertFile != "" || c.KeyFile != "":
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("both CertFile and KeyFile are required for a client certificate")
+		}
+		cert, err := newClientCertFile(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert.get(), nil
+		}
+	case c.Certificate != nil:
+		tlsConfig.Certificates = []tls.Certificate{*c.Certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertFile is a client certificate loaded from disk and loaded again on the next
+// handshake after one of its files changes. A rotation that fails to load keeps the
+// previous certificate.
+type clientCertFile struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.Mutex
+	modTimes [2]time.Time
+	cert     *tls.Certificate
+}
+
+func newClientCertFile(certFile, keyFile string) (*clientCertFile, error) {
+	f := &clientCertFile{certFile: certFile, keyFile: keyFile}
+	if err := f.load(f.currentModTimes()); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *clientCertFile) get() *tls.Certificate {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if modTimes := f.currentModTimes(); modTimes != f.modTimes {
+		// The files may be half written; the next handshake retries
+		_ = f.load(modTimes)
+	}
+	return f.cert
+}
+
+func (f *clientCertFile) currentModTimes() [2]time.Time {
+	var modTimes [2]time.Time
+	for i, name := range []string{f.certFile, f.keyFile} {
+		if info, err := os.Stat(name); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func (f *clientCertFile) load(modTimes [2]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	f.cert, f.modTimes = &cert, modTimes
+	return nil
+}
@@ -0,0 +1,233 @@
+package authgateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+type testTLSCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestTLSCA(t *testing.T) *testTLSCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return &testTLSCA{cert: cert, key: key}
+}
+
+func (ca *testTLSCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// issue returns the PEM certificate and key of a leaf certificate for name
+func (ca *testTLSCA) issue(t *testing.T, name string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("rand.Int() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeTestFile(t *testing.T, name string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+// startMTLSServer starts a gRPC server for "auth-gateway" that requires client
+// certificates signed by clientCA, returning its address
+func startMTLSServer(t *testing.T, serverCA, clientCA *testTLSCA) string {
+	t.Helper()
+	certPEM, keyPEM := serverCA.issue(t, "auth-gateway")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair() error = %v", err)
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCA.pool(),
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv := grpc.NewServer(grpc.Creds(creds))
+	proto.RegisterAuthServiceServer(srv, proto.UnimplementedAuthServiceServer{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestNewGRPCClient_MutualTLS(t *testing.T) {
+	serverCA, clientCA := newTestTLSCA(t), newTestTLSCA(t)
+	addr := startMTLSServer(t, serverCA, clientCA)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	writeTestFile(t, caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCA.cert.Raw}), time.Now())
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	certPEM, keyPEM := clientCA.issue(t, "billing")
+	writeTestFile(t, certFile, certPEM, time.Now())
+	writeTestFile(t, keyFile, keyPEM, time.Now())
+
+	tests := []struct {
+		name string
+		tls  *GRPCTLSConfig
+		want codes.Code
+	}{
+		{
+			name: "client certificate from files",
+			tls:  &GRPCTLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "auth-gateway"},
+			want: codes.Unimplemented,
+		},
+		{
+			name: "no client certificate",
+			tls:  &GRPCTLSConfig{RootCAs: serverCA.pool(), ServerName: "auth-gateway"},
+			want: codes.Unavailable,
+		},
+		{
+			name: "server name mismatch",
+			tls:  &GRPCTLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+			want: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewGRPCClient(GRPCConfig{Address: addr, TLS: tt.tls})
+			if err != nil {
+				t.Fatalf("NewGRPCClient() error = %v", err)
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = client.ValidateToken(ctx, "token")
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("ValidateToken() code = %v, want %v (err = %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewGRPCClient_TLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	badCA := filepath.Join(dir, "bad.crt")
+	writeTestFile(t, badCA, []byte("not a certificate"), time.Now())
+
+	tests := []struct {
+		name   string
+		config GRPCConfig
+	}{
+		{"insecure with TLS", GRPCConfig{Insecure: true, TLS: &GRPCTLSConfig{}}},
+		{"missing CA file", GRPCConfig{TLS: &GRPCTLSConfig{CAFile: filepath.Join(dir, "missing.crt")}}},
+		{"CA file without certificates", GRPCConfig{TLS: &GRPCTLSConfig{CAFile: badCA}}},
+		{"cert file without key file", GRPCConfig{TLS: &GRPCTLSConfig{CertFile: badCA}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGRPCClient(tt.config); err == nil {
+				t.Error("NewGRPCClient() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestClientCertFile_ReloadsRotatedCertificate(t *testing.T) {
+	ca := newTestTLSCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	certPEM, keyPEM := ca.issue(t, "billing")
+	writeTestFile(t, certFile, certPEM, time.Now())
+	writeTestFile(t, keyFile, keyPEM, time.Now())
+
+	f, err := newClientCertFile(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newClientCertFile() error = %v", err)
+	}
+	first := f.get().Certificate[0]
+
+	later := time.Now().Add(time.Minute)
+	writeTestFile(t, certFile, []byte("half written"), later)
+	if got := f.get().Certificate[0]; string(got) != string(first) {
+		t.Error("get() after a broken rotation should keep the previous certificate")
+	}
+
+	certPEM, keyPEM = ca.issue(t, "billing")
+	writeTestFile(t, certFile, certPEM, later.Add(time.Minute))
+	writeTestFile(t, keyFile, keyPEM, later.Add(time.Minute))
+	if got := f.get().Certificate[0]; string(got) == string(first) {
+		t.Error("get() after a rotation should return the new certificate")
+	}
+}
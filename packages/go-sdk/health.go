@@ -9,7 +9,7 @@ import (
 // Health performs a full health check on the Auth Gateway.
 func (c *Client) Health(ctx context.Context) (*models.HealthStatus, error) {
 	var resp models.HealthStatus
-	if err := c.get(ctx, "/auth/health", &resp); err != nil {
+	if err := c.get(ctx, "/health", &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -18,7 +18,7 @@ func (c *Client) Health(ctx context.Context) (*models.HealthStatus, error) {
 // Ready checks if the Auth Gateway is ready to accept requests.
 func (c *Client) Ready(ctx context.Context) (*models.HealthStatus, error) {
 	var resp models.HealthStatus
-	if err := c.get(ctx, "/auth/ready", &resp); err != nil {
+	if err := c.get(ctx, "/ready", &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -27,7 +27,26 @@ func (c *Client) Ready(ctx context.Context) (*models.HealthStatus, error) {
 // Live checks if the Auth Gateway is alive.
 func (c *Client) Live(ctx context.Context) (*models.HealthStatus, error) {
 	var resp models.HealthStatus
-	if err := c.get(ctx, "/auth/live", &resp); err != nil {
+	if err := c.get(ctx, "/live", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Status returns the public status of the Auth Gateway: version, uptime, the status of
+// each dependency and the maintenance in progress. It needs no credentials.
+func (c *Client) Status(ctx context.Context) (*models.PublicStatus, error) {
+	var resp models.PublicStatus
+	if err := c.get(ctx, "/status", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Version returns the build information of the Auth Gateway and the API versions it serves.
+func (c *Client) Version(ctx context.Context) (*models.ServerVersion, error) {
+	var resp models.ServerVersion
+	if err := c.get(ctx, "/version", &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -0,0 +1,168 @@
+package authgateway
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultM2MEarlyRenewal is how long before expiry a cached M2M token is renewed
+const DefaultM2MEarlyRenewal = 30 * time.Second
+
+// M2MTokenCache caches client_credentials tokens for service-to-service calls.
+//
+// Tokens are pooled per audience and scope set, so every caller asking for the same
+// audience and scopes (in any order) shares one token. A token is renewed shortly before
+// it expires, and concurrent callers of an expired pool wait for a single token request
+// instead of each requesting their own.
+//
+// Example:
+//
+//	cache := authgateway.NewM2MTokenCache(oauthClient, authgateway.M2MOptions{})
+//	ts := cache.TokenSource(ctx, "https://billing.internal", "invoices:read")
+//	httpClient := oauth2.NewClient(ctx, ts)
+type M2MTokenCache struct {
+	client       *OAuthProviderClient
+	earlyRenewal time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	pools map[string]*m2mPool
+}
+
+// M2MOptions configures an M2MTokenCache
+type M2MOptions struct {
+	// EarlyRenewal renews a token this long before it expires.
+	// Defaults to DefaultM2MEarlyRenewal.
+	EarlyRenewal time.Duration
+
+	// Now is the time source for expiry checks. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// m2mPool holds the cached token for one audience and scope set
+type m2mPool struct {
+	audience string
+	scopes   []string
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// NewM2MTokenCache creates a token cache on top of the client's ClientCredentialsGrant.
+// The client must be configured with a client secret.
+func NewM2MTokenCache(client *OAuthProviderClient, opts M2MOptions) *M2MTokenCache {
+	if opts.EarlyRenewal <= 0 {
+		opts.EarlyRenewal = DefaultM2MEarlyRenewal
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &M2MTokenCache{
+		client:       client,
+		earlyRenewal: opts.EarlyRenewal,
+		now:          opts.Now,
+		pools:        make(map[string]*m2mPool),
+	}
+}
+
+// TokenSource returns an oauth2.TokenSource for the given audience and scopes.
+// An empty audience omits the audience parameter. Token requests use ctx.
+func (c *M2MTokenCache) TokenSource(ctx context.Context, audience string, scopes ...string) oauth2.TokenSource {
+	return &m2mTokenSource{ctx: ctx, cache: c, pool: c.pool(audience, scopes)}
+}
+
+// Token returns a cached token for the given audience and scopes, requesting a new one
+// if there is none or it is about to expire
+func (c *M2MTokenCache) Token(ctx context.Context, audience string, scopes ...string) (*oauth2.Token, error) {
+	return c.token(ctx, c.pool(audience, scopes))
+}
+
+// Invalidate drops the cached token for the given audience and scopes, for example after
+// the token was rejected by the receiving service
+func (c *M2MTokenCache) Invalidate(audience string, scopes ...string) {
+	p := c.pool(audience, scopes)
+	p.mu.Lock()
+	p.token = nil
+	p.mu.Unlock()
+}
+
+func (c *M2MTokenCache) pool(audience string, scopes []string) *m2mPool {
+	normalized := normalizeScopes(scopes)
+	key := audience + "\x00" + strings.Join(normalized, " ")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pools[key]
+	if !ok {
+		p = &m2mPool{audience: audience, scopes: normalized}
+		c.pools[key] = p
+	}
+	return p
+}
+
+func (c *M2MTokenCache) token(ctx context.Context, p *m2mPool) (*oauth2.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != nil && !c.needsRenewal(p.token) {
+		return p.token, nil
+	}
+
+	resp, err := c.client.clientCredentialsGrant(ctx, p.scopes, p.audience)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &oauth2.Token{
+		AccessToken: resp.AccessToken,
+		TokenType:   resp.TokenType,
+	}
+	if resp.ExpiresIn > 0 {
+		token.Expiry = c.now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	p.token = token
+
+	return token, nil
+}
+
+// needsRenewal reports whether the token expires within the early renewal window.
+// Tokens without an expiry never need renewal.
+func (c *M2MTokenCache) needsRenewal(token *oauth2.Token) bool {
+	if token.Expiry.IsZero() {
+		return false
+	}
+	return token.Expiry.Sub(c.now()) <= c.earlyRenewal
+}
+
+// normalizeScopes sorts and deduplicates scopes so that the same set shares a pool
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// m2mTokenSource adapts a pool to oauth2.TokenSource
+type m2mTokenSource struct {
+	ctx   context.Context
+	cache *M2MTokenCache
+	pool  *m2mPool
+}
+
+// Token implements oauth2.TokenSource
+func (s *m2mTokenSource) Token() (*oauth2.Token, error) {
+	return s.cache.token(s.ctx, s.pool)
+}
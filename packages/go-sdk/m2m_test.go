@@ -0,0 +1,181 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+// newM2MTestCache returns a cache backed by a token endpoint that issues a new token per request
+func newM2MTestCache(t *testing.T, expiresIn int64, opts M2MOptions) (*M2MTokenCache, *int32, *sync.Map) {
+	var serverURL string
+	var requests int32
+	forms := &sync.Map{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", discoveryHandler(&serverURL))
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		n := atomic.AddInt32(&requests, 1)
+		forms.Store(n, r.Form)
+		json.NewEncoder(w).Encode(models.OAuthTokenResponse{
+			AccessToken: fmt.Sprintf("token-%d", n),
+			TokenType:   "Bearer",
+			ExpiresIn:   expiresIn,
+		})
+	})
+	server := newTestServer(t, mux)
+	serverURL = server.URL
+
+	client := NewOAuthProviderClient(OAuthProviderConfig{
+		Issuer:       serverURL,
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+	})
+	return NewM2MTokenCache(client, opts), &requests, forms
+}
+
+func TestM2MTokenCache(t *testing.T) {
+	t.Run("ShouldReuseTokenForSameScopeSet", func(t *testing.T) {
+		// Arrange
+		cache, requests, forms := newM2MTestCache(t, 3600, M2MOptions{})
+		ts := cache.TokenSource(context.Background(), "billing", "b", "a")
+
+		// Act
+		first, err := ts.Token()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := cache.Token(context.Background(), "billing", "a", "b", "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Assert
+		if first.AccessToken != second.AccessToken {
+			t.Errorf("expected cached token, got %s and %s", first.AccessToken, second.AccessToken)
+		}
+		if got := atomic.LoadInt32(requests); got != 1 {
+			t.Errorf("expected 1 token request, got %d", got)
+		}
+		form, _ := forms.Load(int32(1))
+		if scope := form.(url.Values)["scope"]; len(scope) != 1 || scope[0] != "a b" {
+			t.Errorf("expected normalized scope, got %v", scope)
+		}
+		if audience := form.(url.Values)["audience"]; len(audience) != 1 || audience[0] != "billing" {
+			t.Errorf("expected audience billing, got %v", audience)
+		}
+	})
+
+	t.Run("ShouldPoolPerAudienceAndScopes", func(t *testing.T) {
+		// Arrange
+		cache, requests, _ := newM2MTestCache(t, 3600, M2MOptions{})
+		ctx := context.Background()
+
+		// Act
+		billing, _ := cache.Token(ctx, "billing", "read")
+		orders, _ := cache.Token(ctx, "orders", "read")
+		billingWrite, _ := cache.Token(ctx, "billing", "read", "write")
+
+		// Assert
+		if billing.AccessToken == orders.AccessToken || billing.AccessToken == billingWrite.AccessToken {
+			t.Error("expected separate tokens per audience and scope set")
+		}
+		if got := atomic.LoadInt32(requests); got != 3 {
+			t.Errorf("expected 3 token requests, got %d", got)
+		}
+	})
+
+	t.Run("ShouldRenewEarly", func(t *testing.T) {
+		// Arrange
+		cache, requests, _ := newM2MTestCache(t, 60, M2MOptions{EarlyRenewal: 2 * time.Minute})
+		ts := cache.TokenSource(context.Background(), "")
+
+		// Act
+		first, _ := ts.Token()
+		second, _ := ts.Token()
+
+		// Assert
+		if first.AccessToken == second.AccessToken {
+			t.Error("expected token inside the renewal window to be renewed")
+		}
+		if got := atomic.LoadInt32(requests); got != 2 {
+			t.Errorf("expected 2 token requests, got %d", got)
+		}
+	})
+
+	t.Run("ShouldUseInjectedClock", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+		cache, requests, _ := newM2MTestCache(t, 3600, M2MOptions{Now: func() time.Time { return now }})
+		ctx := context.Background()
+		first, _ := cache.Token(ctx, "billing")
+
+		// Act
+		now = now.Add(59 * time.Minute)
+		second, _ := cache.Token(ctx, "billing")
+		now = now.Add(time.Minute)
+		third, _ := cache.Token(ctx, "billing")
+
+		// Assert
+		if first.AccessToken != second.AccessToken {
+			t.Error("expected token outside the renewal window to be reused")
+		}
+		if second.AccessToken == third.AccessToken {
+			t.Error("expected token inside the renewal window to be renewed")
+		}
+		if got := atomic.LoadInt32(requests); got != 2 {
+			t.Errorf("expected 2 token requests, got %d", got)
+		}
+	})
+
+	t.Run("ShouldRequestOnceForConcurrentCallers", func(t *testing.T) {
+		// Arrange
+		cache, requests, _ := newM2MTestCache(t, 3600, M2MOptions{})
+		ts := cache.TokenSource(context.Background(), "billing", "read")
+
+		// Act
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := ts.Token(); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		if got := atomic.LoadInt32(requests); got != 1 {
+			t.Errorf("expected 1 token request, got %d", got)
+		}
+	})
+
+	t.Run("ShouldRequestNewToken_AfterInvalidate", func(t *testing.T) {
+		// Arrange
+		cache, requests, _ := newM2MTestCache(t, 3600, M2MOptions{})
+		ctx := context.Background()
+		first, _ := cache.Token(ctx, "billing", "read")
+
+		// Act
+		cache.Invalidate("billing", "read")
+		second, _ := cache.Token(ctx, "billing", "read")
+
+		// Assert
+		if first.AccessToken == second.AccessToken {
+			t.Error("expected a new token after invalidate")
+		}
+		if got := atomic.LoadInt32(requests); got != 2 {
+			t.Errorf("expected 2 token requests, got %d", got)
+		}
+	})
+}
@@ -17,6 +17,7 @@ const (
 	contextKeyRoles          = "roles"
 	contextKeyAppRoles       = "app_roles"
 	contextKeyApplicationID  = "application_id"
+	contextKeyIsGuest        = "is_guest"
 	contextKeyAuthValidation = "auth_validation"
 )
 
@@ -120,6 +121,21 @@ func RequireAppRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// RequireFullAccount returns middleware that rejects anonymous guests
+// who have not upgraded to a full account yet.
+func RequireFullAccount() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if IsGuest(ctx) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "GUEST_NOT_ALLOWED",
+				"message": "A full account is required",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
+
 // RequirePermission returns middleware that checks a specific resource/action
 // permission for the authenticated user via gRPC.
 func (c *GRPCClient) RequirePermission(resource, action string) gin.HandlerFunc {
@@ -180,6 +196,10 @@ func GetApplicationID(ctx *gin.Context) string {
 	return stringFromContext(ctx, contextKeyApplicationID)
 }
 
+func IsGuest(ctx *gin.Context) bool {
+	return ctx.GetBool(contextKeyIsGuest)
+}
+
 func HasRole(ctx *gin.Context, role string) bool {
 	return containsString(GetRoles(ctx), role)
 }
@@ -279,6 +299,7 @@ func setContextFromValidation(ctx *gin.Context, resp *proto.ValidateTokenRespons
 	ctx.Set(contextKeyRoles, resp.GetRoles())
 	ctx.Set(contextKeyAppRoles, resp.GetAppRoles())
 	ctx.Set(contextKeyApplicationID, resp.GetApplicationId())
+	ctx.Set(contextKeyIsGuest, resp.GetIsGuest())
 	ctx.Set(contextKeyAuthValidation, resp)
 }
 
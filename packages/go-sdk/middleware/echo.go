@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// Echo returns the middleware for echo. The user is put into the context of
+// c.Request(); read it with FromContext(c.Request().Context()).
+func (m *Middleware) Echo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r, err := m.authenticate(c.Request())
+			if err != nil {
+				m.cfg.onError(c.Response(), c.Request(), err)
+				return nil
+			}
+			c.SetRequest(r)
+			return next(c)
+		}
+	}
+}
+
+// EchoRequireScopes is RequireScopes for echo
+func EchoRequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return echoRequire(func(u *User) error { return checkScopes(u, scopes) })
+}
+
+// EchoRequireRoles is RequireRoles for echo
+func EchoRequireRoles(roles ...string) echo.MiddlewareFunc {
+	return echoRequire(func(u *User) error { return checkRoles(u, roles) })
+}
+
+func echoRequire(check func(*User) error) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := authorize(c.Request().Context(), check); err != nil {
+				WriteError(c.Response(), c.Request(), err)
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
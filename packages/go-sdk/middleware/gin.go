@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Gin returns the middleware as a gin handler. The user is put into the
+// context of c.Request; read it with FromContext(c.Request.Context()).
+func (m *Middleware) Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := m.authenticate(c.Request)
+		if err != nil {
+			m.cfg.onError(c.Writer, c.Request, err)
+			c.Abort()
+			return
+		}
+		c.Request = r
+		c.Next()
+	}
+}
+
+// GinRequireScopes is RequireScopes for gin
+func GinRequireScopes(scopes ...string) gin.HandlerFunc {
+	return ginRequire(func(u *User) error { return checkScopes(u, scopes) })
+}
+
+// GinRequireRoles is RequireRoles for gin
+func GinRequireRoles(roles ...string) gin.HandlerFunc {
+	return ginRequire(func(u *User) error { return checkRoles(u, roles) })
+}
+
+func ginRequire(check func(*User) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := authorize(c.Request.Context(), check); err != nil {
+			WriteError(c.Writer, c.Request, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
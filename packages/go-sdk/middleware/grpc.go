@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
+)
+
+// TokenValidationClient validates tokens with the gateway; *authgateway.GRPCClient
+// implements it
+type TokenValidationClient interface {
+	ValidateToken(ctx context.Context, accessToken string) (*proto.ValidateTokenResponse, error)
+}
+
+var _ TokenValidationClient = (*authgateway.GRPCClient)(nil)
+
+// GRPCValidator validates tokens remotely with the gateway, so revoked tokens
+// and blocked users are rejected immediately. Tokens carry no scopes over gRPC;
+// use the JWKS validator for routes guarded by RequireScopes.
+type GRPCValidator struct {
+	client TokenValidationClient
+}
+
+// NewGRPCValidator creates a validator calling the gateway through client
+func NewGRPCValidator(client TokenValidationClient) *GRPCValidator {
+	return &GRPCValidator{client: client}
+}
+
+// Validate asks the gateway whether token is valid
+func (v *GRPCValidator) Validate(ctx context.Context, token string) (*User, error) {
+	resp, err := v.client.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if !resp.GetValid() {
+		return nil, invalidToken("%s", resp.GetErrorMessage())
+	}
+
+	user := &User{
+		ID:            resp.GetUserId(),
+		Email:         resp.GetEmail(),
+		Username:      resp.GetUsername(),
+		Roles:         resp.GetRoles(),
+		AppRoles:      resp.GetAppRoles(),
+		ApplicationID: resp.GetApplicationId(),
+		IsGuest:       resp.GetIsGuest(),
+	}
+	if resp.GetExpiresAt() > 0 {
+		user.ExpiresAt = time.Unix(resp.GetExpiresAt(), 0)
+	}
+	return user, nil
+}
@@ -0,0 +1,378 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+const (
+	defaultJWKSRefreshInterval = time.Hour
+	// minJWKSRefetchInterval limits refetches for tokens signed with unknown keys
+	minJWKSRefetchInterval = 30 * time.Second
+)
+
+// JWKSConfig configures NewJWKSValidator
+type JWKSConfig struct {
+	// JWKSURL is the gateway's key set, e.g. https://auth.example.com/.well-known/jwks.json
+	JWKSURL string
+	// Issuer is the expected iss claim. Empty accepts any issuer.
+	Issuer string
+	// Audience must be one of the token's aud values. Empty accepts any audience.
+	Audience string
+	// TrustedIssuers accepts these iss values besides Issuer. With both empty any issuer is accepted.
+	TrustedIssuers []string
+	// TrustedAudiences accepts these aud values besides Audience. With both empty any
+	// audience is accepted.
+	TrustedAudiences []string
+	// PinnedKeyThumbprints pins the signing keys by their RFC 7638 SHA-256 thumbprint
+	// (see authgateway.JWKThumbprint). Keys served by JWKSURL that are not pinned are
+	// ignored, and a key set without a pinned key fails to load. Empty trusts every key.
+	PinnedKeyThumbprints []string
+	// Leeway tolerates clock skew when checking exp and nbf
+	Leeway time.Duration
+	// RefreshInterval is how long fetched keys are used before refetching (default 1h).
+	// Tokens signed with an unknown key trigger an earlier refetch.
+	RefreshInterval time.Duration
+	HTTPClient      *http.Client
+}
+
+// JWKSValidator validates RS256 and ES256 access tokens locally against the
+// gateway's published keys. It does not see revocations: a revoked token is
+// accepted until it expires. Use the gRPC validator where that matters.
+type JWKSValidator struct {
+	cfg JWKSConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time // last successful fetch
+	triedAt   time.Time // last fetch attempt
+}
+
+// NewJWKSValidator creates a validator for tokens signed with the keys at cfg.JWKSURL
+func NewJWKSValidator(cfg JWKSConfig) *JWKSValidator {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultJWKSRefreshInterval
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKSValidator{cfg: cfg, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// accessTokenClaims covers both the gateway's own access tokens and access
+// tokens issued by its OAuth provider
+type accessTokenClaims struct {
+	Subject       string   `json:"sub"`
+	Issuer        string   `json:"iss"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     *int64   `json:"exp"`
+	NotBefore     *int64   `json:"nbf"`
+	TokenType     string   `json:"token_type"`
+	UserID        string   `json:"user_id"`
+	Email         string   `json:"email"`
+	Username      string   `json:"username"`
+	Roles         []string `json:"roles"`
+	AppRoles      []string `json:"app_roles"`
+	Scope         string   `json:"scope"`
+	ClientID      string   `json:"client_id"`
+	ApplicationID string   `json:"application_id"`
+	IsGuest       bool     `json:"is_guest"`
+}
+
+// audience accepts aud as a string or an array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Validate verifies the token's signature and claims
+func (v *JWKSValidator) Validate(ctx context.Context, token string) (*User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidToken("malformed header")
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("malformed signature")
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims accessTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidToken("malformed claims")
+	}
+	return v.user(&claims)
+}
+
+func (v *JWKSValidator) user(claims *accessTokenClaims) (*User, error) {
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return nil, invalidToken("token has no expiry")
+	}
+	expiresAt := time.Unix(*claims.ExpiresAt, 0)
+	if now.After(expiresAt.Add(v.cfg.Leeway)) {
+		return nil, invalidToken("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(v.cfg.Leeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return nil, invalidToken("token not valid yet")
+	}
+	if issuers := withTrusted(v.cfg.Issuer, v.cfg.TrustedIssuers); len(issuers) > 0 && !contains(issuers, claims.Issuer) {
+		return nil, invalidToken("unexpected issuer")
+	}
+	if audiences := withTrusted(v.cfg.Audience, v.cfg.TrustedAudiences); len(audiences) > 0 && !containsAny(claims.Audience, audiences) {
+		return nil, invalidToken("unexpected audience")
+	}
+	// Refresh, 2FA and ID tokens are signed with the same keys
+	if claims.TokenType != "access" && claims.TokenType != "Bearer" {
+		return nil, invalidToken("not an access token")
+	}
+
+	userID := claims.UserID
+	if userID == "" {
+		userID = claims.Subject
+	}
+	return &User{
+		ID:            userID,
+		Email:         claims.Email,
+		Username:      claims.Username,
+		Roles:         claims.Roles,
+		AppRoles:      claims.AppRoles,
+		Scopes:        splitScopes(claims.Scope),
+		ClientID:      claims.ClientID,
+		ApplicationID: claims.ApplicationID,
+		IsGuest:       claims.IsGuest,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// key returns the public key with kid, refetching the key set when it is stale
+// or does not have the key
+func (v *JWKSValidator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if key, ok := v.lookup(kid); ok && now.Sub(v.fetchedAt) < v.cfg.RefreshInterval {
+		return key, nil
+	}
+	if now.Sub(v.triedAt) >= minJWKSRefetchInterval {
+		v.triedAt = now
+		if err := v.fetch(ctx); err != nil {
+			// Keep validating with the keys already fetched while the gateway is unreachable
+			if key, ok := v.lookup(kid); ok {
+				return key, nil
+			}
+			return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+	}
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	if v.keys == nil {
+		return nil, fmt.Errorf("%w: JWKS has not been fetched", ErrUnavailable)
+	}
+	return nil, invalidToken("unknown signing key")
+}
+
+// lookup finds kid; a token without kid matches a key set holding a single key
+func (v *JWKSValidator) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+func (v *JWKSValidator) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := v.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var jwks models.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if len(v.cfg.PinnedKeyThumbprints) > 0 {
+			thumbprint, err := authgateway.JWKThumbprint(jwk)
+			if err != nil || !contains(v.cfg.PinnedKeyThumbprints, thumbprint) {
+				continue
+			}
+		}
+		// Keys of unsupported types are skipped rather than failing the whole set
+		if key, err := publicKey(jwk); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	if len(v.cfg.PinnedKeyThumbprints) > 0 && len(keys) == 0 {
+		return authgateway.ErrNoPinnedKeys
+	}
+	v.keys = keys
+	v.fetchedAt = v.now()
+	return nil
+}
+
+// withTrusted returns value, unless empty, followed by trusted
+func withTrusted(value string, trusted []string) []string {
+	if value == "" {
+		return trusted
+	}
+	return append([]string{value}, trusted...)
+}
+
+// containsAny reports whether values and targets share an element
+func containsAny(values, targets []string) bool {
+	for _, target := range targets {
+		if contains(values, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func publicKey(jwk models.JWK) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// verifySignature checks the signature of a token signed with alg. The
+// algorithm must match the key type, so an RSA key is never used for another
+// algorithm.
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidToken("algorithm does not match key")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return invalidToken("bad signature")
+		}
+		return nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidToken("algorithm does not match key")
+		}
+		if len(signature) != 64 {
+			return invalidToken("bad signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return invalidToken("bad signature")
+		}
+		return nil
+	default:
+		return invalidToken("unsupported algorithm %q", alg)
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,415 @@
+// Package middleware provides drop-in HTTP middleware for services that accept
+// Auth Gateway access tokens.
+//
+// A Middleware validates the bearer token of each request with a Validator,
+// either locally against the gateway's JWKS (NewJWKSValidator) or remotely over
+// gRPC (NewGRPCValidator), and puts the authenticated User into the request
+// context. RequireScopes and RequireRoles then guard individual routes.
+//
+// The same Middleware plugs into net/http, gin and echo:
+//
+//	auth := middleware.New(middleware.NewJWKSValidator(middleware.JWKSConfig{
+//	    JWKSURL: "https://auth.example.com/.well-known/jwks.json",
+//	    Issuer:  "https://auth.example.com",
+//	}))
+//
+//	// net/http
+//	mux.Handle("/orders", auth.Handler(middleware.RequireScopes("orders:read")(ordersHandler)))
+//
+//	// gin
+//	router.Use(auth.Gin())
+//	router.GET("/orders", middleware.GinRequireScopes("orders:read"), listOrders)
+//
+//	// echo
+//	e.Use(auth.Echo())
+//	e.GET("/orders", listOrders, middleware.EchoRequireScopes("orders:read"))
+//
+// Handlers read the user with FromContext.
+//
+// WithSignedURLs additionally admits requests carrying a URL signed by the
+// gateway, so downloads linked from a browser need no bearer token:
+//
+//	auth := middleware.New(validator, middleware.WithSignedURLs(
+//	    middleware.NewRemoteSignedURLVerifier(client.SignedURLs),
+//	))
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+)
+
+var (
+	// ErrMissingToken is returned when a request carries no token
+	ErrMissingToken = &authgateway.APIError{
+		StatusCode: http.StatusUnauthorized,
+		Code:       "MISSING_TOKEN",
+		Message:    "Missing authorization token",
+	}
+	// ErrInvalidToken is returned for tokens that are malformed, expired, not
+	// signed by the gateway or not access tokens
+	ErrInvalidToken = &authgateway.APIError{
+		StatusCode: http.StatusUnauthorized,
+		Code:       "INVALID_TOKEN",
+		Message:    "Invalid or expired token",
+	}
+	// ErrInsufficientScope is returned when the user lacks a required scope
+	ErrInsufficientScope = &authgateway.APIError{
+		StatusCode: http.StatusForbidden,
+		Code:       "INSUFFICIENT_SCOPE",
+		Message:    "Insufficient scope",
+	}
+	// ErrForbidden is returned when the user holds none of the required roles
+	ErrForbidden = &authgateway.APIError{
+		StatusCode: http.StatusForbidden,
+		Code:       "FORBIDDEN",
+		Message:    "Insufficient role",
+	}
+	// ErrUnavailable is returned when tokens cannot be validated because the
+	// gateway or its key set cannot be reached
+	ErrUnavailable = &authgateway.APIError{
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "AUTH_UNAVAILABLE",
+		Message:    "Token validation is unavailable",
+	}
+)
+
+// User is the authenticated subject of a request
+type User struct {
+	ID            string
+	Email         string
+	Username      string
+	Roles         []string
+	AppRoles      []string
+	Scopes        []string
+	ClientID      string // OAuth client the token was issued to, if any
+	ApplicationID string
+	IsGuest       bool
+	ExpiresAt     time.Time
+}
+
+// HasScope reports whether the token was granted scope
+func (u *User) HasScope(scope string) bool {
+	return contains(u.Scopes, scope)
+}
+
+// HasRole reports whether the user holds the global role
+func (u *User) HasRole(role string) bool {
+	return contains(u.Roles, role)
+}
+
+// Validator validates an access token and returns its user. Errors for tokens
+// that are not acceptable should wrap ErrInvalidToken.
+type Validator interface {
+	Validate(ctx context.Context, token string) (*User, error)
+}
+
+// ValidatorFunc adapts a function to Validator
+type ValidatorFunc func(ctx context.Context, token string) (*User, error)
+
+// Validate calls f
+func (f ValidatorFunc) Validate(ctx context.Context, token string) (*User, error) {
+	return f(ctx, token)
+}
+
+// ErrorHandler writes the response for a request that failed authentication or
+// authorization
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// Option configures New
+type Option func(*config)
+
+type config struct {
+	extractors []authgateway.TokenExtractor
+	skipPaths  map[string]struct{}
+	onError    ErrorHandler
+	cacheTTL   time.Duration
+	signedURLs SignedURLVerifier
+}
+
+// WithTokenExtractors replaces the default bearer token extractor. Extractors
+// are tried in order.
+func WithTokenExtractors(extractors ...authgateway.TokenExtractor) Option {
+	return func(cfg *config) {
+		cfg.extractors = extractors
+	}
+}
+
+// WithSkipPaths lets requests to the exact paths through without a token
+func WithSkipPaths(paths ...string) Option {
+	return func(cfg *config) {
+		for _, p := range paths {
+			cfg.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// WithErrorHandler replaces the default JSON error response
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(cfg *config) {
+		cfg.onError = handler
+	}
+}
+
+// WithCache remembers validated tokens for ttl, or until they expire if that is
+// sooner. Mostly useful with the gRPC validator; revoked tokens are accepted
+// until their entry expires.
+func WithCache(ttl time.Duration) Option {
+	return func(cfg *config) {
+		cfg.cacheTTL = ttl
+	}
+}
+
+// Middleware authenticates requests with a Validator
+type Middleware struct {
+	validator Validator
+	cfg       config
+	cache     *userCache
+}
+
+// New creates middleware that validates tokens with v
+func New(v Validator, opts ...Option) *Middleware {
+	cfg := config{
+		extractors: []authgateway.TokenExtractor{authgateway.BearerTokenExtractor()},
+		skipPaths:  make(map[string]struct{}),
+		onError:    WriteError,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	m := &Middleware{validator: v, cfg: cfg}
+	if cfg.cacheTTL > 0 {
+		m.cache = &userCache{ttl: cfg.cacheTTL, items: make(map[string]cachedUser)}
+	}
+	return m
+}
+
+// Handler returns net/http middleware that rejects requests without a valid
+// token and puts the user into the request context
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, err := m.authenticate(r)
+		if err != nil {
+			m.cfg.onError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns r with the user in its context. Skipped paths are
+// returned unchanged.
+func (m *Middleware) authenticate(r *http.Request) (*http.Request, error) {
+	if _, skip := m.cfg.skipPaths[r.URL.Path]; skip {
+		return r, nil
+	}
+
+	if m.isSignedURL(r) {
+		user, err := m.cfg.signedURLs.VerifySignedURL(r.Context(), r.Method, r.URL)
+		if err != nil {
+			return r, err
+		}
+		return r.WithContext(NewContext(r.Context(), user)), nil
+	}
+
+	token := ""
+	for _, extract := range m.cfg.extractors {
+		if token = extract(r); token != "" {
+			break
+		}
+	}
+	if token == "" || m.validator == nil {
+		return r, ErrMissingToken
+	}
+
+	user := m.cache.get(token)
+	if user == nil {
+		var err error
+		user, err = m.validator.Validate(r.Context(), token)
+		if err != nil {
+			return r, err
+		}
+		m.cache.set(token, user)
+	}
+	return r.WithContext(NewContext(r.Context(), user)), nil
+}
+
+// RequireScopes returns net/http middleware that admits users whose token was
+// granted every one of scopes. It must run after Handler.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return require(func(u *User) error { return checkScopes(u, scopes) })
+}
+
+// RequireRoles returns net/http middleware that admits users holding at least
+// one of roles. It must run after Handler.
+func RequireRoles(roles ...string) func(http.Handler) http.Handler {
+	return require(func(u *User) error { return checkRoles(u, roles) })
+}
+
+func require(check func(*User) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := authorize(r.Context(), check); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func authorize(ctx context.Context, check func(*User) error) error {
+	user, ok := FromContext(ctx)
+	if !ok {
+		return ErrMissingToken
+	}
+	return check(user)
+}
+
+func checkScopes(u *User, scopes []string) error {
+	for _, scope := range scopes {
+		if !u.HasScope(scope) {
+			return ErrInsufficientScope
+		}
+	}
+	return nil
+}
+
+func checkRoles(u *User, roles []string) error {
+	for _, role := range roles {
+		if u.HasRole(role) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// --- Context ---
+
+type userKey struct{}
+
+// NewContext returns a copy of ctx carrying user
+func NewContext(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// FromContext returns the user put into ctx by the middleware
+func FromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userKey{}).(*User)
+	return user, ok && user != nil
+}
+
+// --- Errors ---
+
+// WriteError is the default ErrorHandler. It writes the error as
+// {"error": code, "message": message} with a WWW-Authenticate challenge
+// (RFC 6750) on 401 and 403 responses.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, message := errorResponse(err)
+
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+	case errors.Is(err, ErrInvalidToken):
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	case errors.Is(err, ErrInsufficientScope):
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":   code,
+		"message": message,
+	})
+}
+
+func errorResponse(err error) (int, string, string) {
+	var apiErr *authgateway.APIError
+	if errors.As(err, &apiErr) {
+		status := apiErr.StatusCode
+		if status == 0 {
+			status = http.StatusUnauthorized
+		}
+		return status, apiErr.Code, apiErr.Message
+	}
+	return http.StatusUnauthorized, "UNAUTHORIZED", err.Error()
+}
+
+// invalidToken wraps ErrInvalidToken with the reason a token was rejected
+func invalidToken(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+}
+
+// --- Cache ---
+
+type cachedUser struct {
+	user      *User
+	expiresAt time.Time
+}
+
+type userCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[string]cachedUser
+}
+
+func (c *userCache) get(token string) *User {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[token]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.items, token)
+		return nil
+	}
+	return entry.user
+}
+
+func (c *userCache) set(token string, user *User) {
+	if c == nil {
+		return
+	}
+	expiresAt := time.Now().Add(c.ttl)
+	if !user.ExpiresAt.IsZero() && user.ExpiresAt.Before(expiresAt) {
+		expiresAt = user.ExpiresAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for t, entry := range c.items {
+		if now.After(entry.expiresAt) {
+			delete(c.items, t)
+		}
+	}
+	c.items[token] = cachedUser{user: user, expiresAt: expiresAt}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func splitScopes(scope string) []string {
+	return strings.Fields(scope)
+}
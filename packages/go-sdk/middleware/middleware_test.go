@@ -0,0 +1,563 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/proto"
+)
+
+const testIssuer = "https://auth.example.com"
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	return &testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+func (k *testKeys) jwks() models.JWKS {
+	b64 := base64.RawURLEncoding.EncodeToString
+	return models.JWKS{Keys: []models.JWK{
+		{
+			Kty: "RSA", Use: "sig", Kid: "rsa-1", Alg: "RS256",
+			N: b64(k.rsa.N.Bytes()),
+			E: b64(big.NewInt(int64(k.rsa.E)).Bytes()),
+		},
+		{
+			Kty: "EC", Use: "sig", Kid: "ec-1", Alg: "ES256", Crv: "P-256",
+			X: b64(k.ec.X.FillBytes(make([]byte, 32))),
+			Y: b64(k.ec.Y.FillBytes(make([]byte, 32))),
+		},
+	}}
+}
+
+// sign returns a token over claims signed with the key for alg
+func (k *testKeys) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		signature = sig
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func accessClaims(overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":        testIssuer,
+		"sub":        "user-1",
+		"user_id":    "user-1",
+		"email":      "user@example.com",
+		"roles":      []string{"admin"},
+		"token_type": "access",
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+// newJWKSServer serves the keys and counts JWKS requests
+func newJWKSServer(t *testing.T, keys *testKeys) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(keys.jwks())
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestJWKSValidator(t *testing.T) {
+	keys := newTestKeys(t)
+	server, requests := newJWKSServer(t, keys)
+	validator := NewJWKSValidator(JWKSConfig{JWKSURL: server.URL, Issuer: testIssuer})
+
+	t.Run("ShouldAcceptRS256AccessToken", func(t *testing.T) {
+		// Arrange
+		token := keys.sign(t, "RS256", "rsa-1", accessClaims(nil))
+
+		// Act
+		user, err := validator.Validate(context.Background(), token)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.ID != "user-1" || user.Email != "user@example.com" || !user.HasRole("admin") {
+			t.Errorf("unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("ShouldAcceptES256OAuthAccessTokenWithScopes", func(t *testing.T) {
+		// Arrange
+		token := keys.sign(t, "ES256", "ec-1", accessClaims(map[string]interface{}{
+			"user_id":    "",
+			"sub":        "user-2",
+			"token_type": "Bearer",
+			"scope":      "openid orders:read",
+			"client_id":  "shop",
+		}))
+
+		// Act
+		user, err := validator.Validate(context.Background(), token)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.ID != "user-2" || user.ClientID != "shop" || !user.HasScope("orders:read") {
+			t.Errorf("unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("ShouldReuseFetchedKeys", func(t *testing.T) {
+		// Act
+		for i := 0; i < 3; i++ {
+			validator.Validate(context.Background(), keys.sign(t, "RS256", "rsa-1", accessClaims(nil)))
+		}
+
+		// Assert
+		if got := atomic.LoadInt32(requests); got != 1 {
+			t.Errorf("expected 1 JWKS request, got %d", got)
+		}
+	})
+
+	rejected := map[string]string{
+		"Expired":      keys.sign(t, "RS256", "rsa-1", accessClaims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})),
+		"RefreshToken": keys.sign(t, "RS256", "rsa-1", accessClaims(map[string]interface{}{"token_type": "refresh"})),
+		"IDToken":      keys.sign(t, "RS256", "rsa-1", accessClaims(map[string]interface{}{"token_type": ""})),
+		"WrongIssuer":  keys.sign(t, "RS256", "rsa-1", accessClaims(map[string]interface{}{"iss": "https://evil.example.com"})),
+		"UnknownKey":   keys.sign(t, "RS256", "rsa-2", accessClaims(nil)),
+		"KeyMismatch":  keys.sign(t, "ES256", "rsa-1", accessClaims(nil)),
+		"Malformed":    "not-a-token",
+	}
+	for name, token := range rejected {
+		t.Run("ShouldReject"+name, func(t *testing.T) {
+			// Act
+			_, err := validator.Validate(context.Background(), token)
+
+			// Assert
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	t.Run("ShouldRejectTamperedToken", func(t *testing.T) {
+		// Arrange
+		token := keys.sign(t, "RS256", "rsa-1", accessClaims(nil))
+		other := keys.sign(t, "RS256", "rsa-1", accessClaims(map[string]interface{}{"roles": []string{"superadmin"}}))
+		parts, otherParts := strings.Split(token, "."), strings.Split(other, ".")
+
+		// Act
+		_, err := validator.Validate(context.Background(), parts[0]+"."+otherParts[1]+"."+parts[2])
+
+		// Assert
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("ShouldReportUnavailableWhenJWKSCannotBeFetched", func(t *testing.T) {
+		// Arrange
+		down := NewJWKSValidator(JWKSConfig{JWKSURL: "http://127.0.0.1:1/jwks.json"})
+
+		// Act
+		_, err := down.Validate(context.Background(), keys.sign(t, "RS256", "rsa-1", accessClaims(nil)))
+
+		// Assert
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("expected ErrUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestJWKSValidator_TrustedIssuersAndAudiences(t *testing.T) {
+	keys := newTestKeys(t)
+	server, _ := newJWKSServer(t, keys)
+	validator := NewJWKSValidator(JWKSConfig{
+		JWKSURL:          server.URL,
+		Issuer:           testIssuer,
+		TrustedIssuers:   []string{"https://auth.eu.example.com"},
+		TrustedAudiences: []string{"orders", "billing"},
+	})
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{"Issuer", map[string]interface{}{"aud": "orders"}, true},
+		{"TrustedIssuer", map[string]interface{}{"iss": "https://auth.eu.example.com", "aud": "orders"}, true},
+		{"OneOfSeveralAudiences", map[string]interface{}{"aud": []string{"crm", "billing"}}, true},
+		{"UntrustedIssuer", map[string]interface{}{"iss": "https://evil.example.com", "aud": "orders"}, false},
+		{"UntrustedAudience", map[string]interface{}{"aud": "crm"}, false},
+		{"NoAudience", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := validator.Validate(context.Background(), keys.sign(t, "RS256", "rsa-1", accessClaims(tt.claims)))
+
+			// Assert
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJWKSValidator_PinnedKeyThumbprints(t *testing.T) {
+	keys := newTestKeys(t)
+	server, _ := newJWKSServer(t, keys)
+	ecPin, err := authgateway.JWKThumbprint(keys.jwks().Keys[1])
+	if err != nil {
+		t.Fatalf("failed to compute thumbprint: %v", err)
+	}
+
+	t.Run("ShouldAcceptTokenSignedWithPinnedKey", func(t *testing.T) {
+		// Arrange
+		validator := NewJWKSValidator(JWKSConfig{JWKSURL: server.URL, PinnedKeyThumbprints: []string{ecPin}})
+
+		// Act
+		_, err := validator.Validate(context.Background(), keys.sign(t, "ES256", "ec-1", accessClaims(nil)))
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ShouldRejectTokenSignedWithUnpinnedKey", func(t *testing.T) {
+		// Arrange
+		validator := NewJWKSValidator(JWKSConfig{JWKSURL: server.URL, PinnedKeyThumbprints: []string{ecPin}})
+
+		// Act
+		_, err := validator.Validate(context.Background(), keys.sign(t, "RS256", "rsa-1", accessClaims(nil)))
+
+		// Assert
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("ShouldReportUnavailableWhenNoPinnedKeyServed", func(t *testing.T) {
+		// Arrange
+		validator := NewJWKSValidator(JWKSConfig{JWKSURL: server.URL, PinnedKeyThumbprints: []string{"unknown"}})
+
+		// Act
+		_, err := validator.Validate(context.Background(), keys.sign(t, "RS256", "rsa-1", accessClaims(nil)))
+
+		// Assert
+		if !errors.Is(err, ErrUnavailable) || !errors.Is(err, authgateway.ErrNoPinnedKeys) {
+			t.Errorf("expected ErrUnavailable caused by ErrNoPinnedKeys, got %v", err)
+		}
+	})
+}
+
+type fakeValidationClient struct {
+	resp  *proto.ValidateTokenResponse
+	err   error
+	calls int32
+}
+
+func (f *fakeValidationClient) ValidateToken(ctx context.Context, accessToken string) (*proto.ValidateTokenResponse, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return f.resp, f.err
+}
+
+func TestGRPCValidator(t *testing.T) {
+	t.Run("ShouldMapValidResponse", func(t *testing.T) {
+		// Arrange
+		client := &fakeValidationClient{resp: &proto.ValidateTokenResponse{
+			Valid:    true,
+			UserId:   "user-1",
+			Roles:    []string{"admin"},
+			AppRoles: []string{"editor"},
+			IsGuest:  true,
+		}}
+
+		// Act
+		user, err := NewGRPCValidator(client).Validate(context.Background(), "token")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.ID != "user-1" || !user.HasRole("admin") || user.AppRoles[0] != "editor" || !user.IsGuest {
+			t.Errorf("unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("ShouldRejectInvalidToken", func(t *testing.T) {
+		// Arrange
+		client := &fakeValidationClient{resp: &proto.ValidateTokenResponse{Valid: false, ErrorMessage: "token revoked"}}
+
+		// Act
+		_, err := NewGRPCValidator(client).Validate(context.Background(), "token")
+
+		// Assert
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("ShouldReportUnavailableOnTransportError", func(t *testing.T) {
+		// Arrange
+		client := &fakeValidationClient{err: errors.New("connection refused")}
+
+		// Act
+		_, err := NewGRPCValidator(client).Validate(context.Background(), "token")
+
+		// Assert
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("expected ErrUnavailable, got %v", err)
+		}
+	})
+}
+
+// staticValidator accepts the token "good" for user-1 with the orders:read scope
+var staticValidator = ValidatorFunc(func(ctx context.Context, token string) (*User, error) {
+	if token != "good" {
+		return nil, invalidToken("unknown token")
+	}
+	return &User{ID: "user-1", Roles: []string{"viewer"}, Scopes: []string{"orders:read"}}, nil
+})
+
+func userHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := FromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusTeapot)
+		return
+	}
+	w.Write([]byte(user.ID))
+}
+
+func request(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	auth := New(staticValidator)
+
+	cases := []struct {
+		name      string
+		handler   http.Handler
+		token     string
+		status    int
+		challenge string
+	}{
+		{"ShouldInjectUser", auth.Handler(http.HandlerFunc(userHandler)), "good", http.StatusOK, ""},
+		{"ShouldRejectMissingToken", auth.Handler(http.HandlerFunc(userHandler)), "", http.StatusUnauthorized, `Bearer`},
+		{"ShouldRejectInvalidToken", auth.Handler(http.HandlerFunc(userHandler)), "bad", http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"ShouldAdmitGrantedScope", auth.Handler(RequireScopes("orders:read")(http.HandlerFunc(userHandler))), "good", http.StatusOK, ""},
+		{"ShouldRejectMissingScope", auth.Handler(RequireScopes("orders:read", "orders:write")(http.HandlerFunc(userHandler))), "good", http.StatusForbidden, `Bearer error="insufficient_scope"`},
+		{"ShouldAdmitAnyRole", auth.Handler(RequireRoles("admin", "viewer")(http.HandlerFunc(userHandler))), "good", http.StatusOK, ""},
+		{"ShouldRejectMissingRole", auth.Handler(RequireRoles("admin")(http.HandlerFunc(userHandler))), "good", http.StatusForbidden, ""},
+		{"ShouldRejectRequireWithoutAuthentication", RequireRoles("admin")(http.HandlerFunc(userHandler)), "good", http.StatusUnauthorized, `Bearer`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			rec := httptest.NewRecorder()
+
+			// Act
+			tc.handler.ServeHTTP(rec, request(tc.token))
+
+			// Assert
+			if rec.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tc.challenge {
+				t.Errorf("expected challenge %q, got %q", tc.challenge, got)
+			}
+		})
+	}
+
+	t.Run("ShouldSkipPaths", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+		handler := New(staticValidator, WithSkipPaths("/orders")).Handler(http.HandlerFunc(userHandler))
+
+		// Act
+		handler.ServeHTTP(rec, request(""))
+
+		// Assert
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("expected the handler to run without a user, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldCacheValidatedTokens", func(t *testing.T) {
+		// Arrange
+		client := &fakeValidationClient{resp: &proto.ValidateTokenResponse{Valid: true, UserId: "user-1"}}
+		handler := New(NewGRPCValidator(client), WithCache(time.Minute)).Handler(http.HandlerFunc(userHandler))
+
+		// Act
+		for i := 0; i < 3; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), request("good"))
+		}
+
+		// Assert
+		if got := atomic.LoadInt32(&client.calls); got != 1 {
+			t.Errorf("expected 1 validation call, got %d", got)
+		}
+	})
+}
+
+func TestMiddleware_Gin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(New(staticValidator).Gin())
+	router.GET("/orders", GinRequireScopes("orders:read"), func(c *gin.Context) { userHandler(c.Writer, c.Request) })
+	router.GET("/admin", GinRequireRoles("admin"), func(c *gin.Context) { userHandler(c.Writer, c.Request) })
+
+	t.Run("ShouldInjectUser", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, request("good"))
+
+		// Assert
+		if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+			t.Errorf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("ShouldRejectInvalidToken", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, request("bad"))
+
+		// Assert
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldRejectMissingRole", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+		req := request("good")
+		req.URL.Path = "/admin"
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+	})
+}
+
+func TestMiddleware_Echo(t *testing.T) {
+	e := echo.New()
+	e.Use(New(staticValidator).Echo())
+	handler := func(c echo.Context) error {
+		userHandler(c.Response(), c.Request())
+		return nil
+	}
+	e.GET("/orders", handler, EchoRequireScopes("orders:read"))
+	e.GET("/admin", handler, EchoRequireRoles("admin"))
+
+	t.Run("ShouldInjectUser", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		e.ServeHTTP(rec, request("good"))
+
+		// Assert
+		if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+			t.Errorf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("ShouldRejectMissingToken", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		e.ServeHTTP(rec, request(""))
+
+		// Assert
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldRejectMissingRole", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+		req := request("good")
+		req.URL.Path = "/admin"
+
+		// Act
+		e.ServeHTTP(rec, req)
+
+		// Assert
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+	})
+}
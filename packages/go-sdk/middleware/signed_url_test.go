@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authgateway "github.com/smilemakc/auth-gateway/packages/go-sdk"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/signedurl"
+)
+
+var testSignedURLSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func signURL(t *testing.T, rawURL string, scopes ...string) string {
+	t.Helper()
+	signed, err := signedurl.New(testSignedURLSecret).Sign(rawURL, signedurl.Claims{
+		UserID:    "user-2",
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return signed
+}
+
+func TestMiddleware_SignedURLs(t *testing.T) {
+	auth := New(staticValidator, WithSignedURLs(NewLocalSignedURLVerifier(testSignedURLSecret)))
+	handler := auth.Handler(RequireScopes("exports:read")(http.HandlerFunc(userHandler)))
+
+	t.Run("ShouldInjectUserOfSignedURL", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signURL(t, "/exports/1", "exports:read"), nil))
+
+		// Assert
+		if rec.Code != http.StatusOK || rec.Body.String() != "user-2" {
+			t.Errorf("expected user-2, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("ShouldRejectMissingScope", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signURL(t, "/exports/1"), nil))
+
+		// Assert
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldRejectTamperedURL", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signURL(t, "/exports/1", "exports:read")+"&extra=1", nil))
+
+		// Assert
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldStillAcceptTokens", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+
+		// Act
+		auth.Handler(http.HandlerFunc(userHandler)).ServeHTTP(rec, request("good"))
+
+		// Assert
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("ShouldRejectTokens_WhenValidatorIsNil", func(t *testing.T) {
+		// Arrange
+		rec := httptest.NewRecorder()
+		signedOnly := New(nil, WithSignedURLs(NewLocalSignedURLVerifier(testSignedURLSecret)))
+
+		// Act
+		signedOnly.Handler(http.HandlerFunc(userHandler)).ServeHTTP(rec, request("good"))
+
+		// Assert
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+}
+
+type fakeSignedURLClient struct {
+	req  *models.VerifySignedURLRequest
+	resp *models.VerifySignedURLResponse
+	err  error
+}
+
+func (f *fakeSignedURLClient) Verify(_ context.Context, req *models.VerifySignedURLRequest) (*models.VerifySignedURLResponse, error) {
+	f.req = req
+	return f.resp, f.err
+}
+
+func TestRemoteSignedURLVerifier(t *testing.T) {
+	t.Run("ShouldSendRequestURIAndMethod", func(t *testing.T) {
+		// Arrange
+		client := &fakeSignedURLClient{resp: &models.VerifySignedURLResponse{Valid: true, UserID: "user-2", Scopes: []string{"exports:read"}}}
+		req := httptest.NewRequest(http.MethodHead, "https://files.example.com/exports/1?ag_signature=x", nil)
+
+		// Act
+		user, err := NewRemoteSignedURLVerifier(client).VerifySignedURL(context.Background(), req.Method, req.URL)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.req.URL != "/exports/1?ag_signature=x" || client.req.Method != http.MethodHead {
+			t.Errorf("unexpected request: %+v", client.req)
+		}
+		if user.ID != "user-2" || !user.HasScope("exports:read") {
+			t.Errorf("unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("ShouldMapErrors", func(t *testing.T) {
+		cases := []struct {
+			name string
+			err  error
+			want error
+		}{
+			{"Rejected", &authgateway.APIError{StatusCode: http.StatusUnauthorized, Message: "Signed URL expired"}, ErrInvalidSignedURL},
+			{"Unreachable", &authgateway.NetworkError{Err: errors.New("connection refused")}, ErrUnavailable},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				req := httptest.NewRequest(http.MethodGet, "/exports/1?ag_signature=x", nil)
+
+				// Act
+				_, err := NewRemoteSignedURLVerifier(&fakeSignedURLClient{err: tc.err}).VerifySignedURL(context.Background(), req.Method, req.URL)
+
+				// Assert
+				if !errors.Is(err, tc.want) {
+					t.Errorf("expected %v, got %v", tc.want, err)
+				}
+			})
+		}
+	})
+}
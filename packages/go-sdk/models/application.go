@@ -2,19 +2,35 @@ package models
 
 // ApplicationOAuthProvider represents a per-app OAuth provider configuration
 type ApplicationOAuthProvider struct {
-	ID            string   `json:"id"`
-	ApplicationID string   `json:"application_id"`
-	Provider      string   `json:"provider"`
-	ClientID      string   `json:"client_id"`
-	ClientSecret  string   `json:"client_secret,omitempty"`
-	CallbackURL   string   `json:"callback_url"`
-	Scopes        []string `json:"scopes"`
-	AuthURL       string   `json:"auth_url"`
-	TokenURL      string   `json:"token_url"`
-	UserInfoURL   string   `json:"user_info_url"`
-	IsActive      bool     `json:"is_active"`
-	CreatedAt     string   `json:"created_at"`
-	UpdatedAt     string   `json:"updated_at"`
+	ID            string            `json:"id"`
+	ApplicationID string            `json:"application_id"`
+	Provider      string            `json:"provider"`
+	ClientID      string            `json:"client_id"`
+	ClientSecret  string            `json:"client_secret,omitempty"`
+	CallbackURL   string            `json:"callback_url"`
+	Scopes        []string          `json:"scopes"`
+	AuthURL       string            `json:"auth_url"`
+	TokenURL      string            `json:"token_url"`
+	UserInfoURL   string            `json:"user_info_url"`
+	TeamID        string            `json:"team_id,omitempty"`
+	KeyID         string            `json:"key_id,omitempty"`
+	TenantID      string            `json:"tenant_id,omitempty"`
+	Issuer        string            `json:"issuer,omitempty"`
+	ClaimMapping  *OIDCClaimMapping `json:"claim_mapping,omitempty"`
+	IsActive      bool              `json:"is_active"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
+}
+
+// OIDCClaimMapping names the claims of a generic OpenID Connect provider that describe
+// the user. Empty fields use the standard claims.
+type OIDCClaimMapping struct {
+	Subject       string `json:"subject,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified string `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Picture       string `json:"picture,omitempty"`
 }
 
 // TelegramBot represents a Telegram bot for an application
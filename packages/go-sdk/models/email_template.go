@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// EmailTemplate is a template for emails the gateway sends.
+type EmailTemplate struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"` // e.g. "verification", "password_reset", "welcome"
+	Name          string    `json:"name"`
+	Subject       string    `json:"subject"`
+	HTMLBody      string    `json:"html_body"`
+	TextBody      string    `json:"text_body,omitempty"`
+	Variables     []string  `json:"variables"`
+	IsActive      bool      `json:"is_active"`
+	ApplicationID *string   `json:"application_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateEmailTemplateRequest creates an email template.
+type CreateEmailTemplateRequest struct {
+	Type          string   `json:"type"`
+	Name          string   `json:"name"`
+	Subject       string   `json:"subject"`
+	HTMLBody      string   `json:"html_body"`
+	TextBody      string   `json:"text_body,omitempty"`
+	Variables     []string `json:"variables,omitempty"`
+	ApplicationID *string  `json:"application_id,omitempty"`
+}
+
+// UpdateEmailTemplateRequest updates an email template. Empty fields are left unchanged.
+type UpdateEmailTemplateRequest struct {
+	Name      string   `json:"name,omitempty"`
+	Subject   string   `json:"subject,omitempty"`
+	HTMLBody  string   `json:"html_body,omitempty"`
+	TextBody  string   `json:"text_body,omitempty"`
+	Variables []string `json:"variables,omitempty"`
+	IsActive  *bool    `json:"is_active,omitempty"`
+}
+
+// PreviewEmailTemplateRequest renders template bodies with sample variables.
+type PreviewEmailTemplateRequest struct {
+	HTMLBody  string                 `json:"html_body"`
+	TextBody  string                 `json:"text_body,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// PreviewEmailTemplateResponse contains rendered template bodies.
+type PreviewEmailTemplateResponse struct {
+	RenderedHTML string `json:"rendered_html"`
+	RenderedText string `json:"rendered_text"`
+}
+
+// EmailTemplateListResponse lists email templates.
+type EmailTemplateListResponse struct {
+	Templates  []EmailTemplate `json:"templates"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	TotalPages int             `json:"total_pages"`
+}
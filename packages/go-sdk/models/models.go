@@ -1,7 +1,10 @@
 // Package models provides data types for the Auth Gateway SDK.
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // User represents a user account in the system.
 type User struct {
@@ -9,30 +12,81 @@ type User struct {
 	Email             string     `json:"email"`
 	Phone             *string    `json:"phone,omitempty"`
 	Username          string     `json:"username"`
-	FullName          string     `json:"full_name"`
-	ProfilePictureURL string     `json:"profile_picture_url"`
+	FullName          string     `json:"full_name,omitempty"`
+	ProfilePictureURL string     `json:"profile_picture_url,omitempty"`
 	AccountType       string     `json:"account_type"`
 	EmailVerified     bool       `json:"email_verified"`
 	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty"`
 	PhoneVerified     bool       `json:"phone_verified"`
 	IsActive          bool       `json:"is_active"`
+	IsGuest           bool       `json:"is_guest,omitempty"`
 	TOTPEnabled       bool       `json:"totp_enabled"`
 	TOTPEnabledAt     *time.Time `json:"totp_enabled_at,omitempty"`
+	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	Roles             []Role     `json:"roles,omitempty"`
 }
 
+// UserErasure describes a completed erasure of a user's personal data. The account is deleted,
+// but its anonymized row is kept so references to it stay valid.
+type UserErasure struct {
+	UserID            string    `json:"user_id"`
+	ErasedAt          time.Time `json:"erased_at"`
+	AuditLogsScrubbed int       `json:"audit_logs_scrubbed"` // audit log entries stripped of IP, user agent, location and details
+}
+
+// Data export formats.
+const (
+	DataExportFormatJSON = "json"
+	DataExportFormatZIP  = "zip"
+)
+
+// Data export statuses.
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+// DataExport describes an export of the current user's data. Once ready, DownloadURL is a
+// signed link that downloads the file without a bearer token until DownloadURLExpiresAt.
+type DataExport struct {
+	ID                   string     `json:"id"`
+	Status               string     `json:"status"`
+	Format               string     `json:"format"`
+	Size                 int64      `json:"size,omitempty"`
+	Error                string     `json:"error,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	DownloadURL          string     `json:"download_url,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty"`
+}
+
 // Role represents a role in the RBAC system.
 type Role struct {
 	ID           string       `json:"id"`
 	Name         string       `json:"name"`
 	DisplayName  string       `json:"display_name"`
-	Description  string       `json:"description"`
-	IsSystemRole bool         `json:"is_system_role"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
+	Description  string       `json:"description,omitempty"`
+	IsSystemRole bool         `json:"is_system_role,omitempty"`
+	CreatedAt    time.Time    `json:"created_at,omitempty"`
+	UpdatedAt    time.Time    `json:"updated_at,omitempty"`
 	Permissions  []Permission `json:"permissions,omitempty"`
+	ParentRoles  []string     `json:"parent_roles,omitempty"` // IDs of the roles this role inherits from
+	Bundles      []string     `json:"bundles,omitempty"`      // IDs of the permission bundles granted to the role
+}
+
+// PermissionBundle is a named set of permissions granted to roles as a whole.
+type PermissionBundle struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	DisplayName string       `json:"display_name"`
+	Description string       `json:"description,omitempty"`
+	CreatedAt   time.Time    `json:"created_at,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
 }
 
 // Permission represents a permission in the RBAC system.
@@ -41,7 +95,8 @@ type Permission struct {
 	Name        string    `json:"name"`
 	Resource    string    `json:"resource"`
 	Action      string    `json:"action"`
-	Description string    `json:"description"`
+	Description string    `json:"description,omitempty"`
+	Condition   string    `json:"condition,omitempty"` // checked by GRPCClient.CheckPermissionWithAttributes
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -50,26 +105,28 @@ type APIKey struct {
 	ID          string     `json:"id"`
 	UserID      string     `json:"user_id"`
 	Name        string     `json:"name"`
-	Description string     `json:"description"`
+	Description string     `json:"description,omitempty"`
 	KeyPrefix   string     `json:"key_prefix"`
 	Scopes      []string   `json:"scopes"`
 	IsActive    bool       `json:"is_active"`
 	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
 	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	UpdatedAt   time.Time  `json:"updated_at,omitempty"`
 }
 
 // Session represents an active user session.
 type Session struct {
 	ID           string    `json:"id"`
 	UserID       string    `json:"user_id"`
-	DeviceType   string    `json:"device_type"`
-	OS           string    `json:"os"`
-	Browser      string    `json:"browser"`
-	IPAddress    string    `json:"ip_address"`
+	UserEmail    string    `json:"user_email,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	DeviceType   string    `json:"device_type,omitempty"`
+	OS           string    `json:"os,omitempty"`
+	Browser      string    `json:"browser,omitempty"`
+	IPAddress    string    `json:"ip_address,omitempty"`
 	UserAgent    string    `json:"user_agent"`
-	SessionName  string    `json:"session_name"`
+	SessionName  string    `json:"session_name,omitempty"`
 	LastActiveAt time.Time `json:"last_active_at"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
@@ -78,14 +135,29 @@ type Session struct {
 
 // AuditLog represents an audit log entry.
 type AuditLog struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Action    string    `json:"action"`
-	Resource  string    `json:"resource"`
-	Detail    string    `json:"detail"`
-	IPAddress string    `json:"ip_address"`
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
+	ID        string                 `json:"id"`
+	UserID    string                 `json:"user_id,omitempty"`
+	UserEmail string                 `json:"user_email,omitempty"`
+	Action    string                 `json:"action"`
+	Status    string                 `json:"status"`
+	IP        string                 `json:"ip"`
+	UserAgent string                 `json:"user_agent"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	City         string `json:"city,omitempty"`
+
+	// Deprecated: not returned by the server. Use Details.
+	Resource string `json:"resource,omitempty"`
+	// Deprecated: not returned by the server. Use Details.
+	Detail string `json:"detail,omitempty"`
+	// Deprecated: not returned by the server. Use IP.
+	IPAddress string `json:"ip_address,omitempty"`
+	// Deprecated: not returned by the server. Use CreatedAt.
+	Timestamp time.Time `json:"timestamp,omitempty"`
 }
 
 // OAuthAccount represents a linked OAuth account.
@@ -103,12 +175,17 @@ type OAuthAccount struct {
 
 // IPFilter represents an IP filter rule.
 type IPFilter struct {
-	ID          string    `json:"id"`
-	IPAddress   string    `json:"ip_address"`
-	Type        string    `json:"type"` // "whitelist" or "blacklist"
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	CreatedBy   string    `json:"created_by"`
+	ID              string     `json:"id"`
+	IPCIDR          string     `json:"ip_cidr"`     // IP address or CIDR range
+	FilterType      string     `json:"filter_type"` // "whitelist" or "blacklist"
+	Reason          string     `json:"reason,omitempty"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	CreatorUsername string     `json:"creator_username,omitempty"`
+	CreatorEmail    string     `json:"creator_email,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // OAuthProvider represents an OAuth provider configuration.
@@ -118,16 +195,38 @@ type OAuthProvider struct {
 	Enabled     bool   `json:"enabled"`
 }
 
+// ProviderToken is an access token issued by an OAuth provider the user signed in with.
+type ProviderToken struct {
+	Provider    string     `json:"provider"`
+	AccessToken string     `json:"access_token"`
+	TokenType   string     `json:"token_type"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
 // SystemStats represents system statistics.
 type SystemStats struct {
-	TotalUsers      int64 `json:"total_users"`
-	ActiveUsers     int64 `json:"active_users"`
-	TotalSessions   int64 `json:"total_sessions"`
-	TotalAPIKeys    int64 `json:"total_api_keys"`
-	TotalRoles      int64 `json:"total_roles"`
-	TotalAuditLogs  int64 `json:"total_audit_logs"`
-	VerifiedUsers   int64 `json:"verified_users"`
-	TwoFAEnabledUsers int64 `json:"two_fa_enabled_users"`
+	TotalUsers         int64            `json:"total_users"`
+	ActiveUsers        int64            `json:"active_users"`
+	VerifiedEmailUsers int64            `json:"verified_email_users"`
+	VerifiedPhoneUsers int64            `json:"verified_phone_users"`
+	Users2FAEnabled    int64            `json:"users_2fa_enabled"`
+	TotalAPIKeys       int64            `json:"total_api_keys"`
+	ActiveAPIKeys      int64            `json:"active_api_keys"`
+	TotalOAuthAccounts int64            `json:"total_oauth_accounts"`
+	UsersByRole        map[string]int64 `json:"users_by_role"`
+	RecentSignups      int64            `json:"recent_signups_24h"`
+	RecentLogins       int64            `json:"recent_logins_24h"`
+
+	// Deprecated: not returned by the server. Use GetSessionStats.
+	TotalSessions int64 `json:"total_sessions,omitempty"`
+	// Deprecated: not returned by the server. Use len(UsersByRole).
+	TotalRoles int64 `json:"total_roles,omitempty"`
+	// Deprecated: not returned by the server.
+	TotalAuditLogs int64 `json:"total_audit_logs,omitempty"`
+	// Deprecated: not returned by the server. Use VerifiedEmailUsers.
+	VerifiedUsers int64 `json:"verified_users,omitempty"`
+	// Deprecated: not returned by the server. Use Users2FAEnabled.
+	TwoFAEnabledUsers int64 `json:"two_fa_enabled_users,omitempty"`
 }
 
 // SessionStats represents session statistics.
@@ -138,19 +237,216 @@ type SessionStats struct {
 	SessionsByBrowser   map[string]int64 `json:"sessions_by_browser"`
 }
 
-// GeoDistribution represents geographic distribution of users.
+// LoginLocation is the number of logins from one location.
+type LoginLocation struct {
+	CountryCode string    `json:"country_code"`
+	CountryName string    `json:"country_name"`
+	City        string    `json:"city,omitempty"`
+	Latitude    float64   `json:"latitude,omitempty"`
+	Longitude   float64   `json:"longitude,omitempty"`
+	LoginCount  int64     `json:"login_count"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// GeoDistribution represents the geographic distribution of logins.
 type GeoDistribution struct {
-	Country string `json:"country"`
-	Count   int64  `json:"count"`
+	Locations       []LoginLocation `json:"locations"`
+	TotalLogins     int64           `json:"total_logins"`
+	UniqueCountries int64           `json:"unique_countries"`
+	UniqueCities    int64           `json:"unique_cities"`
 }
 
 // HealthStatus represents the system health status.
 type HealthStatus struct {
-	Status    string            `json:"status"`
-	Database  string            `json:"database"`
-	Redis     string            `json:"redis"`
-	Timestamp time.Time         `json:"timestamp"`
-	Details   map[string]string `json:"details,omitempty"`
+	Status   string            `json:"status"`
+	Services map[string]string `json:"services,omitempty"` // dependency name -> "healthy" or "unhealthy: <reason>"
+	Details  map[string]string `json:"details,omitempty"`
+
+	// Deprecated: not returned by the server. Use Services["database"].
+	Database string `json:"database,omitempty"`
+	// Deprecated: not returned by the server. Use Services["redis"].
+	Redis string `json:"redis,omitempty"`
+	// Deprecated: not returned by the server.
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// SLOBurnRate is how fast the error budget of an SLO burned over a recent window.
+// A burn rate of 1 exhausts the budget exactly at the end of the SLO window.
+type SLOBurnRate struct {
+	Window        string  `json:"window"`
+	TotalRequests int64   `json:"total_requests"`
+	BadRequests   int64   `json:"bad_requests"`
+	BurnRate      float64 `json:"burn_rate"`
+}
+
+// SLOStatus is the error budget of a built-in service level objective.
+type SLOStatus struct {
+	Name               string  `json:"name"`
+	Endpoint           string  `json:"endpoint"`
+	Type               string  `json:"type"` // availability or latency
+	Objective          float64 `json:"objective"`
+	LatencyThresholdMs int64   `json:"latency_threshold_ms,omitempty"`
+	TotalRequests      int64   `json:"total_requests"`
+	BadRequests        int64   `json:"bad_requests"`
+	SLI                float64 `json:"sli"`
+	// Share of the error budget left; negative once the budget is exhausted
+	ErrorBudgetRemaining float64       `json:"error_budget_remaining"`
+	BurnRates            []SLOBurnRate `json:"burn_rates"`
+	Alert                string        `json:"alert,omitempty"` // critical, warning or empty
+}
+
+// SLOList lists the SLOs of the gateway with their error budgets.
+type SLOList struct {
+	Window       string      `json:"window"`
+	SLOs         []SLOStatus `json:"slos"`
+	TrackedSince time.Time   `json:"tracked_since"`
+}
+
+// Background job statuses.
+const (
+	BackgroundJobPending   = "pending"
+	BackgroundJobRunning   = "running"
+	BackgroundJobSucceeded = "succeeded"
+	BackgroundJobDead      = "dead"
+)
+
+// BackgroundJob is a job of the gateway's persistent background job queue. Dead jobs ran
+// out of attempts or failed permanently and are kept until retried.
+type BackgroundJob struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"` // e.g. webhook.delivery
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"` // next attempt, or end of the lease while running
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// BackgroundJobListResponse is a page of background jobs, newest first.
+type BackgroundJobListResponse struct {
+	Jobs       []BackgroundJob `json:"jobs"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// BackgroundJobStats counts background jobs by status.
+type BackgroundJobStats struct {
+	Pending   int `json:"pending"`
+	Running   int `json:"running"`
+	Succeeded int `json:"succeeded"`
+	Dead      int `json:"dead"`
+}
+
+// ListBackgroundJobsParams contains parameters for listing background jobs.
+type ListBackgroundJobsParams struct {
+	Page     int    `url:"page,omitempty"`
+	PageSize int    `url:"page_size,omitempty"`
+	Status   string `url:"status,omitempty"`
+	Type     string `url:"type,omitempty"`
+}
+
+// AuditActionCount is the number of audit logs of an action.
+type AuditActionCount struct {
+	Action string `json:"action"`
+	Total  int    `json:"total"`
+	Failed int    `json:"failed"` // did not succeed (failed, blocked)
+}
+
+// AuditActionStats lists the most frequent audit actions of a time range.
+type AuditActionStats struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Actions []AuditActionCount `json:"actions"`
+}
+
+// AuditHourlyCount is the number of audit logs of an hour.
+type AuditHourlyCount struct {
+	Hour  time.Time `json:"hour"`
+	Count int       `json:"count"`
+}
+
+// AuditFailureStats counts unsuccessful audit actions per hour; every hour of the range is listed.
+type AuditFailureStats struct {
+	From  time.Time          `json:"from"`
+	To    time.Time          `json:"to"`
+	Hours []AuditHourlyCount `json:"hours"`
+	Total int                `json:"total"`
+}
+
+// AuditExportSinkStatus describes a sink audit logs are exported to (syslog or s3).
+type AuditExportSinkStatus struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"` // length of the windows exported at once, e.g. 1m0s
+	// Logs created before this time have export jobs queued
+	ScheduledUntil *time.Time `json:"scheduled_until,omitempty"`
+}
+
+// AuditExportStatus lists the configured audit export sinks.
+type AuditExportStatus struct {
+	Sinks []AuditExportSinkStatus `json:"sinks"`
+}
+
+// AuditExportReplayRequest asks to export the audit logs of a past range, at most 31 days,
+// to a sink again.
+type AuditExportReplayRequest struct {
+	Sink string    `json:"sink"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// AuditExportReplayResponse reports the range, widened to whole intervals of the sink, and
+// the number of export jobs queued.
+type AuditExportReplayResponse struct {
+	Sink string    `json:"sink"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Jobs int       `json:"jobs"`
+}
+
+// AuthRequirement is one check the gateway performs before a route handler runs.
+type AuthRequirement struct {
+	// authentication, role, permission, admin_or_permission, scope, org_admin or policy
+	Kind   string   `json:"kind"`
+	Values []string `json:"values,omitempty"`
+	// All of Values are required rather than any one of them
+	All bool `json:"all,omitempty"`
+	// Callers let through without meeting the requirement, e.g. admin or app_secret
+	Bypass      []string `json:"bypass,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// RouteAuthorization is what an HTTP route requires of its caller.
+type RouteAuthorization struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Public routes may still authenticate the caller in the handler
+	Public         bool              `json:"public"`
+	Authentication []string          `json:"authentication,omitempty"` // jwt, api_key, app_secret
+	Requirements   []AuthRequirement `json:"requirements"`
+	Handler        string            `json:"handler"`
+}
+
+// GRPCMethodAuthorization is what a gRPC method requires of its caller.
+type GRPCMethodAuthorization struct {
+	Method         string   `json:"method"`
+	Streaming      bool     `json:"streaming"`
+	Authentication []string `json:"authentication,omitempty"`
+	Scope          string   `json:"scope,omitempty"`
+	// Methods without a configured scope are rejected for every caller
+	Denied bool `json:"denied"`
+}
+
+// AuthorizationMap lists the authorization requirements of every HTTP route and gRPC method.
+type AuthorizationMap struct {
+	GeneratedAt time.Time                 `json:"generated_at"`
+	HTTP        []RouteAuthorization      `json:"http"`
+	GRPC        []GRPCMethodAuthorization `json:"grpc"`
 }
 
 // MaintenanceStatus represents maintenance mode status.
@@ -160,12 +456,81 @@ type MaintenanceStatus struct {
 	StartedAt *time.Time `json:"started_at,omitempty"`
 }
 
+// PublicStatus is the public status of the gateway, for status pages.
+type PublicStatus struct {
+	Status      string                   `json:"status"` // "operational", "degraded", "outage" or "maintenance"
+	Version     string                   `json:"version"`
+	Uptime      int64                    `json:"uptime_seconds"`
+	Components  []PublicStatusComponent  `json:"components"`
+	Maintenance *PublicMaintenanceWindow `json:"maintenance,omitempty"`
+	CheckedAt   time.Time                `json:"checked_at"`
+}
+
+// PublicStatusComponent is the status of one dependency of the gateway.
+type PublicStatusComponent struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`               // "operational", "degraded" or "outage"
+	LatencyMs *int64 `json:"latency_ms,omitempty"` // nil when the component is unreachable
+}
+
+// PublicMaintenanceWindow is the maintenance in progress.
+type PublicMaintenanceWindow struct {
+	Message string    `json:"message"`
+	Since   time.Time `json:"since"`
+}
+
+// ServerVersion is the build information of the gateway.
+type ServerVersion struct {
+	Version     string   `json:"version"` // semantic version, or "dev" for untagged builds
+	Commit      string   `json:"commit,omitempty"`
+	BuildDate   string   `json:"build_date,omitempty"`
+	GoVersion   string   `json:"go_version"`
+	APIVersions []string `json:"api_versions"`
+}
+
+// BrandingSettings are the branding and theming settings of the gateway's pages.
+type BrandingSettings struct {
+	ID              string    `json:"id"`
+	LogoURL         string    `json:"logo_url,omitempty"`
+	FaviconURL      string    `json:"favicon_url,omitempty"`
+	PrimaryColor    string    `json:"primary_color"`
+	SecondaryColor  string    `json:"secondary_color"`
+	BackgroundColor string    `json:"background_color"`
+	CustomCSS       string    `json:"custom_css,omitempty"`
+	CompanyName     string    `json:"company_name,omitempty"`
+	SupportEmail    string    `json:"support_email,omitempty"`
+	TermsURL        string    `json:"terms_url,omitempty"`
+	PrivacyURL      string    `json:"privacy_url,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	UpdatedBy       *string   `json:"updated_by,omitempty"`
+}
+
+// BrandingTheme contains the branding colors.
+type BrandingTheme struct {
+	PrimaryColor    string `json:"primary_color"`
+	SecondaryColor  string `json:"secondary_color"`
+	BackgroundColor string `json:"background_color"`
+}
+
+// PublicBranding is the branding shown on the gateway's pages.
+type PublicBranding struct {
+	LogoURL      string        `json:"logo_url,omitempty"`
+	FaviconURL   string        `json:"favicon_url,omitempty"`
+	Theme        BrandingTheme `json:"theme"`
+	CompanyName  string        `json:"company_name,omitempty"`
+	SupportEmail string        `json:"support_email,omitempty"`
+	TermsURL     string        `json:"terms_url,omitempty"`
+	PrivacyURL   string        `json:"privacy_url,omitempty"`
+}
+
 // Pagination contains pagination information.
 type Pagination struct {
 	Page       int   `json:"page"`
 	Limit      int   `json:"limit"`
 	Total      int64 `json:"total"`
 	TotalPages int   `json:"total_pages"`
+	// NextCursor continues the listing where it supports cursors; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedList is a generic paginated list response.
@@ -173,3 +538,41 @@ type PaginatedList[T any] struct {
 	Items      []T        `json:"items"`
 	Pagination Pagination `json:"pagination"`
 }
+
+// UnmarshalJSON decodes both the items/pagination shape and the flat shape returned by
+// admin list endpoints, where the items are under a resource key ("users", "logs", ...)
+// next to total, page, page_size and total_pages.
+func (l *PaginatedList[T]) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if _, ok := fields["items"]; ok {
+		type plain PaginatedList[T]
+		return json.Unmarshal(data, (*plain)(l))
+	}
+
+	var flat struct {
+		Total      int64  `json:"total"`
+		Page       int    `json:"page"`
+		PageSize   int    `json:"page_size"`
+		TotalPages int    `json:"total_pages"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(data, &flat); err != nil {
+		return err
+	}
+	l.Pagination = Pagination{Page: flat.Page, Limit: flat.PageSize, Total: flat.Total, TotalPages: flat.TotalPages, NextCursor: flat.NextCursor}
+
+	for key, raw := range fields {
+		switch key {
+		case "total", "page", "page_size", "total_pages", "next_cursor":
+			continue
+		}
+		if len(raw) > 0 && raw[0] == '[' {
+			return json.Unmarshal(raw, &l.Items)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPaginatedList_UnmarshalJSON(t *testing.T) {
+	t.Run("ShouldDecodeItemsShape", func(t *testing.T) {
+		// Arrange
+		data := []byte(`{"items":[{"id":"1"}],"pagination":{"page":2,"limit":10,"total":11,"total_pages":2}}`)
+
+		// Act
+		var list PaginatedList[User]
+		err := json.Unmarshal(data, &list)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list.Items) != 1 || list.Items[0].ID != "1" {
+			t.Errorf("expected one user with ID 1, got %+v", list.Items)
+		}
+		if list.Pagination != (Pagination{Page: 2, Limit: 10, Total: 11, TotalPages: 2}) {
+			t.Errorf("unexpected pagination %+v", list.Pagination)
+		}
+	})
+
+	t.Run("ShouldDecodeFlatShape", func(t *testing.T) {
+		// Arrange
+		data := []byte(`{"users":[{"id":"1"},{"id":"2"}],"total":12,"page":1,"page_size":2,"total_pages":6}`)
+
+		// Act
+		var list PaginatedList[User]
+		err := json.Unmarshal(data, &list)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list.Items) != 2 || list.Items[1].ID != "2" {
+			t.Errorf("expected two users, got %+v", list.Items)
+		}
+		if list.Pagination != (Pagination{Page: 1, Limit: 2, Total: 12, TotalPages: 6}) {
+			t.Errorf("unexpected pagination %+v", list.Pagination)
+		}
+	})
+}
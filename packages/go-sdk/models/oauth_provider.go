@@ -21,6 +21,9 @@ type OIDCDiscovery struct {
 	ClaimsSupported                   []string `json:"claims_supported,omitempty"`
 	GrantTypesSupported               []string `json:"grant_types_supported,omitempty"`
 	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported,omitempty"`
+	// TLSClientCertificateBoundAccessTokens is set when the server can bind access tokens
+	// to client certificates (RFC 8705)
+	TLSClientCertificateBoundAccessTokens bool `json:"tls_client_certificate_bound_access_tokens,omitempty"`
 }
 
 // JWKS represents JSON Web Key Set (RFC 7517)
@@ -50,6 +53,9 @@ type OAuthTokenResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Scope        string `json:"scope,omitempty"`
 	IDToken      string `json:"id_token,omitempty"` // For OIDC
+	// RefreshNonce must be sent with RefreshToken on the next refresh when the client
+	// requires refresh nonces; see OAuthProviderClient.RefreshTokensWithNonce.
+	RefreshNonce string `json:"refresh_nonce,omitempty"`
 }
 
 // TokenIntrospectionResponse represents token introspection response (RFC 7662)
@@ -66,6 +72,14 @@ type TokenIntrospectionResponse struct {
 	Aud       string `json:"aud,omitempty"`
 	Iss       string `json:"iss,omitempty"`
 	Jti       string `json:"jti,omitempty"`
+	// Cnf is set for tokens bound to a client certificate (RFC 8705)
+	Cnf *Confirmation `json:"cnf,omitempty"`
+}
+
+// Confirmation identifies the key a token is bound to (RFC 7800)
+type Confirmation struct {
+	// X5TS256 is the base64url SHA-256 thumbprint of the client certificate
+	X5TS256 string `json:"x5t#S256"`
 }
 
 // UserInfo represents OIDC UserInfo response
@@ -166,81 +180,229 @@ const (
 
 // OAuthClient represents an OAuth 2.0 client application.
 type OAuthClient struct {
-	ID                string    `json:"id"`
-	ClientID          string    `json:"client_id"`
-	Name              string    `json:"name"`
-	Description       string    `json:"description,omitempty"`
-	LogoURL           string    `json:"logo_url,omitempty"`
-	ClientType        string    `json:"client_type"`
-	RedirectURIs      []string  `json:"redirect_uris"`
-	AllowedGrantTypes []string  `json:"allowed_grant_types"`
-	AllowedScopes     []string  `json:"allowed_scopes"`
-	DefaultScopes     []string  `json:"default_scopes"`
-	AccessTokenTTL    int       `json:"access_token_ttl"`
-	RefreshTokenTTL   int       `json:"refresh_token_ttl"`
-	IDTokenTTL        int       `json:"id_token_ttl"`
-	RequirePKCE       bool      `json:"require_pkce"`
-	RequireConsent    bool      `json:"require_consent"`
-	FirstParty        bool      `json:"first_party"`
-	IsActive          bool      `json:"is_active"`
-	OwnerID           *string   `json:"owner_id,omitempty"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID           string   `json:"id"`
+	ClientID     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	LogoURL      string   `json:"logo_url,omitempty"`
+	ClientType   string   `json:"client_type"`
+	RedirectURIs []string `json:"redirect_uris"`
+	// Logout endpoints of the client, see the OIDC back-channel, front-channel and
+	// RP-initiated logout specs
+	BackchannelLogoutURI   string    `json:"backchannel_logout_uri,omitempty"`
+	FrontchannelLogoutURI  string    `json:"frontchannel_logout_uri,omitempty"`
+	PostLogoutRedirectURIs []string  `json:"post_logout_redirect_uris,omitempty"`
+	AllowedGrantTypes      []string  `json:"allowed_grant_types"`
+	AllowedScopes          []string  `json:"allowed_scopes"`
+	DefaultScopes          []string  `json:"default_scopes"`
+	AccessTokenTTL         int       `json:"access_token_ttl"`
+	RefreshTokenTTL        int       `json:"refresh_token_ttl"`
+	IDTokenTTL             int       `json:"id_token_ttl"`
+	RequirePKCE            bool      `json:"require_pkce"`
+	RequireConsent         bool      `json:"require_consent"`
+	FirstParty             bool      `json:"first_party"`
+	CertBoundAccessTokens  bool      `json:"tls_client_certificate_bound_access_tokens"`
+	RequireRefreshNonce    bool      `json:"require_refresh_nonce"`
+	IsActive               bool      `json:"is_active"`
+	OwnerID                *string   `json:"owner_id,omitempty"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+
+	// ClaimsMapping adds custom claims to the client's ID tokens and userinfo responses
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty"`
 }
 
 // CreateOAuthClientRequest is the request body for creating an OAuth client.
 type CreateOAuthClientRequest struct {
-	Name              string   `json:"name"`
-	Description       string   `json:"description,omitempty"`
-	LogoURL           string   `json:"logo_url,omitempty"`
-	ClientType        string   `json:"client_type,omitempty"`
-	RedirectURIs      []string `json:"redirect_uris"`
-	AllowedGrantTypes []string `json:"allowed_grant_types,omitempty"`
-	AllowedScopes     []string `json:"allowed_scopes,omitempty"`
-	DefaultScopes     []string `json:"default_scopes,omitempty"`
-	AccessTokenTTL    *int     `json:"access_token_ttl,omitempty"`
-	RefreshTokenTTL   *int     `json:"refresh_token_ttl,omitempty"`
-	IDTokenTTL        *int     `json:"id_token_ttl,omitempty"`
-	RequirePKCE       *bool    `json:"require_pkce,omitempty"`
-	RequireConsent    *bool    `json:"require_consent,omitempty"`
-	FirstParty        *bool    `json:"first_party,omitempty"`
+	Name                   string   `json:"name"`
+	Description            string   `json:"description,omitempty"`
+	LogoURL                string   `json:"logo_url,omitempty"`
+	ClientType             string   `json:"client_type,omitempty"`
+	RedirectURIs           []string `json:"redirect_uris"`
+	BackchannelLogoutURI   string   `json:"backchannel_logout_uri,omitempty"`
+	FrontchannelLogoutURI  string   `json:"frontchannel_logout_uri,omitempty"`
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty"`
+	AllowedGrantTypes      []string `json:"allowed_grant_types,omitempty"`
+	AllowedScopes          []string `json:"allowed_scopes,omitempty"`
+	DefaultScopes          []string `json:"default_scopes,omitempty"`
+	AccessTokenTTL         *int     `json:"access_token_ttl,omitempty"`
+	RefreshTokenTTL        *int     `json:"refresh_token_ttl,omitempty"`
+	IDTokenTTL             *int     `json:"id_token_ttl,omitempty"`
+	RequirePKCE            *bool    `json:"require_pkce,omitempty"`
+	RequireConsent         *bool    `json:"require_consent,omitempty"`
+	FirstParty             *bool    `json:"first_party,omitempty"`
+	CertBoundAccessTokens  *bool    `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	RequireRefreshNonce    *bool    `json:"require_refresh_nonce,omitempty"`
+
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty"`
 }
 
 // CreateOAuthClientResponse is returned when creating an OAuth client.
 type CreateOAuthClientResponse struct {
-	Client       *OAuthClient `json:"client"`
-	ClientSecret string       `json:"client_secret,omitempty"`
+	Client *OAuthClient `json:"client"`
+	// ClientSecret is the secret of a confidential client. The server keeps only a hash,
+	// so this is the one time it can be read; empty for public clients.
+	ClientSecret string `json:"client_secret,omitempty"`
 }
 
 // UpdateOAuthClientRequest is the request body for updating an OAuth client.
 type UpdateOAuthClientRequest struct {
-	Name              *string  `json:"name,omitempty"`
-	Description       *string  `json:"description,omitempty"`
-	LogoURL           *string  `json:"logo_url,omitempty"`
-	RedirectURIs      []string `json:"redirect_uris,omitempty"`
-	AllowedGrantTypes []string `json:"allowed_grant_types,omitempty"`
-	AllowedScopes     []string `json:"allowed_scopes,omitempty"`
-	DefaultScopes     []string `json:"default_scopes,omitempty"`
-	AccessTokenTTL    *int     `json:"access_token_ttl,omitempty"`
-	RefreshTokenTTL   *int     `json:"refresh_token_ttl,omitempty"`
-	IDTokenTTL        *int     `json:"id_token_ttl,omitempty"`
-	RequirePKCE       *bool    `json:"require_pkce,omitempty"`
-	RequireConsent    *bool    `json:"require_consent,omitempty"`
-	FirstParty        *bool    `json:"first_party,omitempty"`
-	IsActive          *bool    `json:"is_active,omitempty"`
-}
-
-// RotateSecretResponse is returned when rotating a client secret.
+	Name         *string  `json:"name,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	LogoURL      *string  `json:"logo_url,omitempty"`
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	// Logout URIs are cleared with a pointer to an empty string
+	BackchannelLogoutURI   *string  `json:"backchannel_logout_uri,omitempty"`
+	FrontchannelLogoutURI  *string  `json:"frontchannel_logout_uri,omitempty"`
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty"`
+	AllowedGrantTypes      []string `json:"allowed_grant_types,omitempty"`
+	AllowedScopes          []string `json:"allowed_scopes,omitempty"`
+	DefaultScopes          []string `json:"default_scopes,omitempty"`
+	AccessTokenTTL         *int     `json:"access_token_ttl,omitempty"`
+	RefreshTokenTTL        *int     `json:"refresh_token_ttl,omitempty"`
+	IDTokenTTL             *int     `json:"id_token_ttl,omitempty"`
+	RequirePKCE            *bool    `json:"require_pkce,omitempty"`
+	RequireConsent         *bool    `json:"require_consent,omitempty"`
+	FirstParty             *bool    `json:"first_party,omitempty"`
+	CertBoundAccessTokens  *bool    `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	RequireRefreshNonce    *bool    `json:"require_refresh_nonce,omitempty"`
+	IsActive               *bool    `json:"is_active,omitempty"`
+
+	// ClaimsMapping replaces the custom claims; an empty mapping removes them
+	ClaimsMapping *ClaimsMapping `json:"claims_mapping,omitempty"`
+}
+
+// ClaimsMapping configures the custom claims an OAuth client receives in its ID tokens and
+// userinfo responses. Claim names are prefixed with Namespace and cannot override standard claims.
+type ClaimsMapping struct {
+	Namespace string `json:"namespace,omitempty"`
+	// Static claims with fixed values
+	Static map[string]interface{} `json:"static,omitempty"`
+	// Attributes maps claim names to user attributes: id, email, email_verified, username,
+	// full_name, phone, phone_verified, profile_picture_url, account_type or created_at
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// RolesClaim and GroupsClaim name the claims listing the user's roles and groups
+	RolesClaim  string `json:"roles_claim,omitempty"`
+	GroupsClaim string `json:"groups_claim,omitempty"`
+}
+
+// RotateSecretResponse is returned when rotating a client secret. The previous secret
+// stops working at once and the new one cannot be read again.
 type RotateSecretResponse struct {
 	ClientSecret string `json:"client_secret"`
 }
 
+// ConnectedApp is an OAuth client the user has granted access to their account.
+type ConnectedApp struct {
+	ID           string     `json:"id"`
+	ClientID     string     `json:"client_id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	LogoURL      string     `json:"logo_url,omitempty"`
+	Scopes       []string   `json:"scopes"`
+	GrantedAt    time.Time  `json:"granted_at"`
+	FirstUsedAt  *time.Time `json:"first_used_at,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	ActiveTokens int        `json:"active_tokens"`
+}
+
+// ConnectedAppListResponse contains the apps connected to the user's account.
+type ConnectedAppListResponse struct {
+	Apps  []ConnectedApp `json:"apps"`
+	Total int            `json:"total"`
+}
+
+// EmulateOAuthClientRequest describes the authorization request to emulate for a client.
+// Empty fields fall back to the client's registered redirect URI and default scopes.
+type EmulateOAuthClientRequest struct {
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	ResponseType        string `json:"response_type,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	CodeVerifier        string `json:"code_verifier,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+}
+
+// OAuthFlowStep is one step of an emulated OAuth flow.
+type OAuthFlowStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// EmulateOAuthClientResponse is the step-by-step trace of an emulated authorization code flow.
+type EmulateOAuthClientResponse struct {
+	ClientID          string                 `json:"client_id"`
+	Success           bool                   `json:"success"`
+	Steps             []OAuthFlowStep        `json:"steps"`
+	GrantedScopes     []string               `json:"granted_scopes,omitempty"`
+	RefreshToken      bool                   `json:"refresh_token"`
+	AccessTokenClaims map[string]interface{} `json:"access_token_claims,omitempty"`
+	IDTokenClaims     map[string]interface{} `json:"id_token_claims,omitempty"`
+}
+
+// TokenPreviewRequest selects the user and OAuth client to preview token claims for.
+// ClientID is the public client_id; an empty Scope is passed through as the authorize endpoint would.
+type TokenPreviewRequest struct {
+	UserID               string `json:"user_id"`
+	ClientID             string `json:"client_id"`
+	Scope                string `json:"scope,omitempty"`
+	Nonce                string `json:"nonce,omitempty"`
+	ClientCertThumbprint string `json:"client_cert_thumbprint,omitempty"`
+}
+
+// TokenPreviewResponse carries the claims the gateway would issue for a user and client.
+// Issuable is false when Problems lists policies that would block issuance.
+type TokenPreviewResponse struct {
+	ClientID          string                 `json:"client_id"`
+	UserID            string                 `json:"user_id"`
+	Issuable          bool                   `json:"issuable"`
+	Problems          []string               `json:"problems,omitempty"`
+	GrantedScopes     []string               `json:"granted_scopes"`
+	Roles             []string               `json:"roles"`
+	RefreshToken      bool                   `json:"refresh_token"`
+	AccessTokenClaims map[string]interface{} `json:"access_token_claims"`
+	IDTokenClaims     map[string]interface{} `json:"id_token_claims,omitempty"`
+	UserInfoClaims    map[string]interface{} `json:"userinfo_claims"`
+}
+
+// OIDCConformanceRequest configures a conformance run.
+// Client-specific checks are skipped when no client is given.
+type OIDCConformanceRequest struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// OIDCConformanceResult is the outcome of a single conformance check.
+type OIDCConformanceResult struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Status   string `json:"status"`
+	Spec     string `json:"spec"`
+	Message  string `json:"message"`
+}
+
+// OIDCConformanceReport is the result of a conformance run against the OIDC provider.
+type OIDCConformanceReport struct {
+	Issuer     string                  `json:"issuer"`
+	Passed     int                     `json:"passed"`
+	Failed     int                     `json:"failed"`
+	Skipped    int                     `json:"skipped"`
+	Results    []OIDCConformanceResult `json:"results"`
+	StartedAt  time.Time               `json:"started_at"`
+	DurationMs int64                   `json:"duration_ms"`
+}
+
 // ListOAuthClientsResponse is the paginated list of OAuth clients.
 type ListOAuthClientsResponse struct {
-	Clients []OAuthClient `json:"clients"`
-	Total   int           `json:"total"`
-	Page    int           `json:"page"`
-	PerPage int           `json:"per_page"`
+	Clients    []OAuthClient `json:"clients"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalPages int           `json:"total_pages"`
+	// Deprecated: The server does not return it; use PageSize.
+	PerPage int `json:"per_page,omitempty"`
 }
 
 // OAuthScope represents an OAuth scope.
@@ -264,6 +426,41 @@ type CreateScopeRequest struct {
 // ListScopesResponse is the list of OAuth scopes.
 type ListScopesResponse struct {
 	Scopes []OAuthScope `json:"scopes"`
+	Total  int          `json:"total"`
+}
+
+// OAuthScopeGroup is a named set of scopes that clients can be allowed and can request
+// as a single scope. Members may be scopes, "prefix:*" wildcards or other groups.
+type OAuthScopeGroup struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	DisplayName    string    `json:"display_name"`
+	Description    string    `json:"description,omitempty"`
+	Scopes         []string  `json:"scopes"`
+	ExpandedScopes []string  `json:"expanded_scopes,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateScopeGroupRequest is the request body for creating a scope group.
+type CreateScopeGroupRequest struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description,omitempty"`
+	Scopes      []string `json:"scopes"`
+}
+
+// UpdateScopeGroupRequest is the request body for updating a scope group.
+type UpdateScopeGroupRequest struct {
+	DisplayName string   `json:"display_name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+}
+
+// ListScopeGroupsResponse is the list of OAuth scope groups.
+type ListScopeGroupsResponse struct {
+	Groups []OAuthScopeGroup `json:"groups"`
+	Total  int               `json:"total"`
 }
 
 // UserConsent represents a user's consent for an OAuth client.
@@ -300,9 +497,13 @@ const (
 // ListOAuthClientsParams contains parameters for listing OAuth clients.
 type ListOAuthClientsParams struct {
 	Page     int    `url:"page,omitempty"`
-	Limit    int    `url:"limit,omitempty"`
-	Search   string `url:"search,omitempty"`
+	PageSize int    `url:"page_size,omitempty"`
+	OwnerID  string `url:"owner_id,omitempty"`
 	IsActive *bool  `url:"is_active,omitempty"`
+	// Deprecated: The server ignores it; use PageSize.
+	Limit int `url:"limit,omitempty"`
+	// Deprecated: The server does not search clients.
+	Search string `url:"search,omitempty"`
 }
 
 // ListScopesParams contains parameters for listing OAuth scopes.
@@ -14,9 +14,18 @@ type SignUpRequest struct {
 
 // SignInRequest contains data for user login.
 type SignInRequest struct {
-	Email    string  `json:"email,omitempty"`
-	Phone    *string `json:"phone,omitempty"`
-	Password string  `json:"password"`
+	Email      string  `json:"email,omitempty"`
+	Phone      *string `json:"phone,omitempty"` // E.164, e.g. +15551234567
+	Username   string  `json:"username,omitempty"`
+	Identifier string  `json:"identifier,omitempty"` // email, phone or username; detected by the server
+	Password   string  `json:"password"`
+}
+
+// ConfirmOAuthLinkRequest links a provider account to the existing account with the same email.
+type ConfirmOAuthLinkRequest struct {
+	LinkToken string `json:"link_token"`
+	Password  string `json:"password"`
+	TOTPCode  string `json:"totp_code,omitempty"` // required when the account has 2FA enabled
 }
 
 // RefreshTokenRequest contains the refresh token for token renewal.
@@ -127,6 +136,18 @@ type CompletePasswordlessRegistrationRequest struct {
 	Code  string  `json:"code"`            // 6-digit OTP code
 }
 
+// RequestMagicLinkRequest asks the gateway to email a one-click sign-in link.
+type RequestMagicLinkRequest struct {
+	Email string `json:"email"`
+	// App URL the link lands on; empty uses the gateway's configured redirect URL
+	RedirectURL string `json:"redirect_url,omitempty"`
+}
+
+// CompleteMagicLinkRequest exchanges the code a magic link redirected with for the session.
+type CompleteMagicLinkRequest struct {
+	Code string `json:"code"`
+}
+
 // CreateUserRequest is for admin user creation.
 type CreateUserRequest struct {
 	Email       string  `json:"email"`
@@ -151,7 +172,9 @@ type CreateRoleRequest struct {
 	Name        string   `json:"name"`
 	DisplayName string   `json:"display_name"`
 	Description string   `json:"description,omitempty"`
-	Permissions []string `json:"permissions,omitempty"` // Permission IDs
+	Permissions []string `json:"permissions,omitempty"`  // Permission IDs
+	ParentRoles []string `json:"parent_roles,omitempty"` // IDs of roles to inherit from
+	Bundles     []string `json:"bundles,omitempty"`      // Permission bundle IDs
 }
 
 // UpdateRoleRequest updates a role.
@@ -159,6 +182,25 @@ type UpdateRoleRequest struct {
 	DisplayName string   `json:"display_name,omitempty"`
 	Description string   `json:"description,omitempty"`
 	Permissions []string `json:"permissions,omitempty"` // Permission IDs
+	// ParentRoles and Bundles replace the role's parents and bundles; nil keeps them
+	// and an empty slice clears them
+	ParentRoles []string `json:"parent_roles"`
+	Bundles     []string `json:"bundles"`
+}
+
+// CreatePermissionBundleRequest creates a new permission bundle.
+type CreatePermissionBundleRequest struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"` // Permission IDs
+}
+
+// UpdatePermissionBundleRequest updates a permission bundle.
+type UpdatePermissionBundleRequest struct {
+	DisplayName string   `json:"display_name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"` // Permission IDs
 }
 
 // CreatePermissionRequest creates a new permission.
@@ -167,6 +209,27 @@ type CreatePermissionRequest struct {
 	Resource    string `json:"resource"`
 	Action      string `json:"action"`
 	Description string `json:"description,omitempty"`
+	Condition   string `json:"condition,omitempty"` // e.g. resource.owner_id == user.id
+}
+
+// BulkRoleJobRequest starts a bulk role job. Set exactly one of Query, GroupID or UserIDs.
+type BulkRoleJobRequest struct {
+	Operation     string   `json:"operation"` // assign or remove
+	RoleID        string   `json:"role_id"`
+	ApplicationID string   `json:"application_id,omitempty"`
+	Query         string   `json:"query,omitempty"`
+	GroupID       string   `json:"group_id,omitempty"`
+	UserIDs       []string `json:"user_ids,omitempty"`
+}
+
+// SimulatePermissionRequest describes a permission check to simulate.
+type SimulatePermissionRequest struct {
+	UserID             string                 `json:"user_id"`
+	Resource           string                 `json:"resource"`
+	Action             string                 `json:"action"`
+	ApplicationID      string                 `json:"application_id,omitempty"`
+	Context            map[string]interface{} `json:"context,omitempty"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes,omitempty"`
 }
 
 // AssignRoleRequest assigns a role to a user.
@@ -176,20 +239,24 @@ type AssignRoleRequest struct {
 
 // CreateIPFilterRequest creates an IP filter.
 type CreateIPFilterRequest struct {
-	IPAddress   string `json:"ip_address"`
-	Type        string `json:"type"` // "whitelist" or "blacklist"
-	Description string `json:"description,omitempty"`
+	IPCIDR     string     `json:"ip_cidr"`     // IP address or CIDR range
+	FilterType string     `json:"filter_type"` // "whitelist" or "blacklist"
+	Reason     string     `json:"reason,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
 }
 
-// UpdateBrandingRequest updates branding settings.
+// UpdateBrandingRequest updates branding settings. Empty fields are left unchanged.
 type UpdateBrandingRequest struct {
-	LogoURL        string `json:"logo_url,omitempty"`
-	PrimaryColor   string `json:"primary_color,omitempty"`
-	SecondaryColor string `json:"secondary_color,omitempty"`
-	CompanyName    string `json:"company_name,omitempty"`
-	SupportEmail   string `json:"support_email,omitempty"`
-	TermsURL       string `json:"terms_url,omitempty"`
-	PrivacyURL     string `json:"privacy_url,omitempty"`
+	LogoURL         string `json:"logo_url,omitempty"`
+	FaviconURL      string `json:"favicon_url,omitempty"`
+	PrimaryColor    string `json:"primary_color,omitempty"`    // hex, e.g. "#3B82F6"
+	SecondaryColor  string `json:"secondary_color,omitempty"`  // hex
+	BackgroundColor string `json:"background_color,omitempty"` // hex
+	CustomCSS       string `json:"custom_css,omitempty"`
+	CompanyName     string `json:"company_name,omitempty"`
+	SupportEmail    string `json:"support_email,omitempty"`
+	TermsURL        string `json:"terms_url,omitempty"`
+	PrivacyURL      string `json:"privacy_url,omitempty"`
 }
 
 // MaintenanceModeRequest enables/disables maintenance mode.
@@ -198,25 +265,59 @@ type MaintenanceModeRequest struct {
 	Message string `json:"message,omitempty"`
 }
 
-// ListUsersParams contains parameters for listing users.
+// ListUsersParams contains parameters for listing users, newest first.
 type ListUsersParams struct {
-	Page     int    `url:"page,omitempty"`
-	Limit    int    `url:"limit,omitempty"`
-	Search   string `url:"search,omitempty"`
-	IsActive *bool  `url:"is_active,omitempty"`
-	Role     string `url:"role,omitempty"`
+	Page          int    `url:"page,omitempty"`
+	PageSize      int    `url:"page_size,omitempty"`
+	Search        string `url:"search,omitempty"`
+	Role          string `url:"role,omitempty"`   // comma-separated for several
+	Status        string `url:"status,omitempty"` // user state; comma-separated for several
+	EmailVerified *bool  `url:"email_verified,omitempty"`
+	TwoFactor     *bool  `url:"two_factor,omitempty"`
+	CreatedFrom   string `url:"created_from,omitempty"` // RFC 3339
+	CreatedTo     string `url:"created_to,omitempty"`   // RFC 3339
+	// Metadata filters by attributes of the users' application profile metadata.
+	Metadata map[string]string `url:"metadata,omitempty"`
+	// Cursor continues after the page that returned it as Pagination.NextCursor. Pages
+	// listed by cursor are not counted and ignore Page.
+	Cursor string `url:"cursor,omitempty"`
+	// Deprecated: The server ignores it; use PageSize.
+	Limit int `url:"limit,omitempty"`
+	// Deprecated: The server ignores it; use Status.
+	IsActive *bool `url:"is_active,omitempty"`
 }
 
 // ListAuditLogsParams contains parameters for listing audit logs.
 type ListAuditLogsParams struct {
 	Page     int    `url:"page,omitempty"`
-	Limit    int    `url:"limit,omitempty"`
+	PageSize int    `url:"page_size,omitempty"`
 	UserID   string `url:"user_id,omitempty"`
-	Action   string `url:"action,omitempty"`
-	Resource string `url:"resource,omitempty"`
+	Action   string `url:"action,omitempty"` // comma-separated for several
 	Status   string `url:"status,omitempty"`
-	From     string `url:"from,omitempty"`
-	To       string `url:"to,omitempty"`
+	IP       string `url:"ip,omitempty"`
+	Country  string `url:"country,omitempty"`
+	From     string `url:"from,omitempty"` // RFC 3339
+	To       string `url:"to,omitempty"`   // RFC 3339
+	// Search is text in the action, resource, IP address, user agent, city or details.
+	Search string `url:"q,omitempty"`
+	// Deprecated: The server ignores it.
+	Resource string `url:"resource,omitempty"`
+	// Deprecated: The server ignores it; use PageSize.
+	Limit int `url:"limit,omitempty"`
+}
+
+// AuditStatsParams contains filters for audit statistics. Without From and To the last
+// 24 hours are counted; the range may span 31 days.
+type AuditStatsParams struct {
+	Limit   int    `url:"limit,omitempty"` // number of top actions, 10 by default
+	UserID  string `url:"user_id,omitempty"`
+	Action  string `url:"action,omitempty"`
+	Status  string `url:"status,omitempty"`
+	IP      string `url:"ip,omitempty"`
+	Country string `url:"country,omitempty"`
+	From    string `url:"from,omitempty"`
+	To      string `url:"to,omitempty"`
+	Search  string `url:"q,omitempty"`
 }
 
 // ListSessionsParams contains parameters for listing sessions.
@@ -228,27 +329,37 @@ type ListSessionsParams struct {
 
 // CreateAppOAuthProviderRequest creates a per-app OAuth provider
 type CreateAppOAuthProviderRequest struct {
-	Provider     string   `json:"provider"`
-	ClientID     string   `json:"client_id"`
-	ClientSecret string   `json:"client_secret"`
-	CallbackURL  string   `json:"callback_url"`
-	Scopes       []string `json:"scopes,omitempty"`
-	AuthURL      string   `json:"auth_url,omitempty"`
-	TokenURL     string   `json:"token_url,omitempty"`
-	UserInfoURL  string   `json:"user_info_url,omitempty"`
-	IsActive     *bool    `json:"is_active,omitempty"`
+	Provider     string            `json:"provider"`
+	ClientID     string            `json:"client_id"`
+	ClientSecret string            `json:"client_secret"`
+	CallbackURL  string            `json:"callback_url"`
+	Scopes       []string          `json:"scopes,omitempty"`
+	AuthURL      string            `json:"auth_url,omitempty"`
+	TokenURL     string            `json:"token_url,omitempty"`
+	UserInfoURL  string            `json:"user_info_url,omitempty"`
+	TeamID       string            `json:"team_id,omitempty"`
+	KeyID        string            `json:"key_id,omitempty"`
+	TenantID     string            `json:"tenant_id,omitempty"`
+	Issuer       string            `json:"issuer,omitempty"`
+	ClaimMapping *OIDCClaimMapping `json:"claim_mapping,omitempty"`
+	IsActive     *bool             `json:"is_active,omitempty"`
 }
 
 // UpdateAppOAuthProviderRequest updates a per-app OAuth provider
 type UpdateAppOAuthProviderRequest struct {
-	ClientID     *string  `json:"client_id,omitempty"`
-	ClientSecret *string  `json:"client_secret,omitempty"`
-	CallbackURL  *string  `json:"callback_url,omitempty"`
-	Scopes       []string `json:"scopes,omitempty"`
-	AuthURL      *string  `json:"auth_url,omitempty"`
-	TokenURL     *string  `json:"token_url,omitempty"`
-	UserInfoURL  *string  `json:"user_info_url,omitempty"`
-	IsActive     *bool    `json:"is_active,omitempty"`
+	ClientID     *string           `json:"client_id,omitempty"`
+	ClientSecret *string           `json:"client_secret,omitempty"`
+	CallbackURL  *string           `json:"callback_url,omitempty"`
+	Scopes       []string          `json:"scopes,omitempty"`
+	AuthURL      *string           `json:"auth_url,omitempty"`
+	TokenURL     *string           `json:"token_url,omitempty"`
+	UserInfoURL  *string           `json:"user_info_url,omitempty"`
+	TeamID       *string           `json:"team_id,omitempty"`
+	KeyID        *string           `json:"key_id,omitempty"`
+	TenantID     *string           `json:"tenant_id,omitempty"`
+	Issuer       *string           `json:"issuer,omitempty"`
+	ClaimMapping *OIDCClaimMapping `json:"claim_mapping,omitempty"`
+	IsActive     *bool             `json:"is_active,omitempty"`
 }
 
 // CreateTelegramBotRequest creates a Telegram bot for an app
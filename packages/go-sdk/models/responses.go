@@ -1,13 +1,18 @@
 package models
 
+import "time"
+
 // AuthResponse contains authentication tokens and user info.
 type AuthResponse struct {
-	AccessToken    string `json:"access_token"`
-	RefreshToken   string `json:"refresh_token"`
-	User           *User  `json:"user,omitempty"`
-	ExpiresIn      int64  `json:"expires_in"`
-	Requires2FA    bool   `json:"requires_2fa"`
-	TwoFactorToken string `json:"two_factor_token,omitempty"`
+	AccessToken               string `json:"access_token,omitempty"`
+	RefreshToken              string `json:"refresh_token,omitempty"`
+	User                      *User  `json:"user,omitempty"`
+	ExpiresIn                 int64  `json:"expires_in,omitempty"`
+	Requires2FA               bool   `json:"requires_2fa,omitempty"`
+	TwoFactorToken            string `json:"two_factor_token,omitempty"`
+	RequiresEmailVerification bool   `json:"requires_email_verification,omitempty"`
+	LinkRequired              bool   `json:"link_required,omitempty"` // OAuth email belongs to an existing account; see OAuth.ConfirmLink
+	LinkToken                 string `json:"link_token,omitempty"`
 }
 
 // TokenResponse contains only tokens.
@@ -66,6 +71,132 @@ type PermissionMatrixResponse struct {
 	Matrix      map[string][]string `json:"matrix"` // role_id -> []permission_id
 }
 
+// PermissionSource is one way a role gets an effective permission.
+type PermissionSource struct {
+	Type       string `json:"type"` // direct or bundle
+	RoleID     string `json:"role_id"`
+	RoleName   string `json:"role_name"`
+	Inherited  bool   `json:"inherited"` // granted by a role the requested role inherits from
+	BundleID   string `json:"bundle_id,omitempty"`
+	BundleName string `json:"bundle_name,omitempty"`
+}
+
+// EffectivePermission is a permission a role grants, with where it comes from.
+type EffectivePermission struct {
+	Permission
+	Sources []PermissionSource `json:"sources"`
+}
+
+// InheritedRole is a role another role inherits from, Depth levels up.
+type InheritedRole struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+}
+
+// EffectivePermissionsResponse contains every permission a role grants: its own, those
+// of its bundles and those of the roles it inherits from.
+type EffectivePermissionsResponse struct {
+	RoleID         string                `json:"role_id"`
+	RoleName       string                `json:"role_name"`
+	InheritedRoles []InheritedRole       `json:"inherited_roles"`
+	Permissions    []EffectivePermission `json:"permissions"`
+	Total          int                   `json:"total"`
+}
+
+// PermissionCatalogRole is a role that grants a permission catalog item.
+type PermissionCatalogRole struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// PermissionCatalogItem describes one resource/action pair known to the gateway.
+// Permission is empty when the pair was only registered by a service.
+type PermissionCatalogItem struct {
+	Resource      string                  `json:"resource"`
+	Action        string                  `json:"action"`
+	ApplicationID string                  `json:"application_id,omitempty"`
+	Permission    string                  `json:"permission,omitempty"`
+	PermissionID  string                  `json:"permission_id,omitempty"`
+	Description   string                  `json:"description,omitempty"`
+	Sources       []string                `json:"sources"`
+	GrantedBy     []PermissionCatalogRole `json:"granted_by"`
+}
+
+// PermissionCatalogResponse contains every resource/action pair known to the gateway.
+type PermissionCatalogResponse struct {
+	Items []PermissionCatalogItem `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// BulkRoleJobFailure is a user a bulk role job could not process.
+type BulkRoleJobFailure struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email,omitempty"`
+	Error  string `json:"error"`
+}
+
+// BulkRoleJob is the status and progress of a bulk role job.
+type BulkRoleJob struct {
+	ID            string               `json:"id"`
+	Operation     string               `json:"operation"`
+	RoleID        string               `json:"role_id"`
+	ApplicationID string               `json:"application_id,omitempty"`
+	Query         string               `json:"query,omitempty"`
+	GroupID       string               `json:"group_id,omitempty"`
+	UserIDs       []string             `json:"user_ids,omitempty"`
+	Status        string               `json:"status"` // pending, running, completed or failed
+	Total         int                  `json:"total"`
+	Processed     int                  `json:"processed"`
+	Succeeded     int                  `json:"succeeded"`
+	Failed        int                  `json:"failed"`
+	Failures      []BulkRoleJobFailure `json:"failures,omitempty"`
+	Error         string               `json:"error,omitempty"`
+	CreatedBy     string               `json:"created_by"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`
+}
+
+// BulkRoleJobListResponse lists the most recent bulk role jobs.
+type BulkRoleJobListResponse struct {
+	Jobs  []BulkRoleJob `json:"jobs"`
+	Total int           `json:"total"`
+}
+
+// SimulationRole is a role of the user as seen by a simulated permission check.
+type SimulationRole struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	DisplayName         string   `json:"display_name"`
+	Considered          bool     `json:"considered"`
+	Matched             bool     `json:"matched"`
+	Detail              string   `json:"detail"`
+	ResourcePermissions []string `json:"resource_permissions"`
+}
+
+// SimulationStep is one condition evaluated by a simulated permission check.
+type SimulationStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // passed, failed or skipped
+	Detail string `json:"detail"`
+}
+
+// SimulatePermissionResponse is the decision trace of a simulated permission check.
+type SimulatePermissionResponse struct {
+	Allowed           bool                   `json:"allowed"`
+	Decision          string                 `json:"decision"` // allow or deny
+	Rule              string                 `json:"rule"`
+	Reason            string                 `json:"reason"`
+	MatchedRole       string                 `json:"matched_role,omitempty"`
+	MatchedPermission string                 `json:"matched_permission,omitempty"`
+	Roles             []SimulationRole       `json:"roles"`
+	Steps             []SimulationStep       `json:"steps"`
+	GrantingRoles     []string               `json:"granting_roles"`
+	Context           map[string]interface{} `json:"context,omitempty"`
+}
+
 // ErrorResponse represents an API error.
 type ErrorResponse struct {
 	Error      string            `json:"error"`
@@ -73,6 +204,17 @@ type ErrorResponse struct {
 	Code       string            `json:"code,omitempty"`
 	Details    map[string]string `json:"details,omitempty"`
 	StatusCode int               `json:"-"`
+	// ErrorMessage is set instead of Message by validation endpoints
+	ErrorMessage string `json:"error_message,omitempty"`
+	// Violations lists the broken rules of a PASSWORD_POLICY_VIOLATION error
+	Violations []PasswordPolicyViolation `json:"violations,omitempty"`
+}
+
+// PasswordPolicyViolation is a password policy rule a new password breaks.
+type PasswordPolicyViolation struct {
+	// Rule is min_length, max_length, uppercase, lowercase, number, special, dictionary, compromised or history
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 func (e *ErrorResponse) String() string {
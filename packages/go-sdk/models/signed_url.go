@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// CreateSignedURLRequest asks the gateway to sign a URL to a protected resource.
+type CreateSignedURLRequest struct {
+	// URL is the absolute URL of the resource; its origin must be allowed by the gateway
+	URL string `json:"url"`
+	// Method the URL may be used with; empty means GET, which also admits HEAD
+	Method string `json:"method,omitempty"`
+	// Scopes bound to the URL; the user must hold every one as a permission
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresIn is the lifetime in seconds; zero uses the gateway default
+	ExpiresIn int `json:"expires_in,omitempty"`
+}
+
+// SignedURLResponse is a signed URL.
+type SignedURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerifySignedURLRequest asks the gateway whether a signed URL may be used.
+type VerifySignedURLRequest struct {
+	// URL is the signed URL as received, absolute or just the request URI
+	URL string `json:"url"`
+	// Method of the request the URL came with; empty means GET
+	Method string `json:"method,omitempty"`
+}
+
+// VerifySignedURLResponse holds the claims of a valid signed URL.
+type VerifySignedURLResponse struct {
+	Valid     bool      `json:"valid"`
+	UserID    string    `json:"user_id"`
+	Scopes    []string  `json:"scopes"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook is an endpoint the gateway posts events to.
+type Webhook struct {
+	ID              string            `json:"id"`
+	ApplicationID   *string           `json:"application_id,omitempty"`
+	Name            string            `json:"name"`
+	URL             string            `json:"url"`
+	Events          []string          `json:"events"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	IsActive        bool              `json:"is_active"`
+	RetryConfig     *RetryConfig      `json:"retry_config,omitempty"`
+	CreatedBy       *string           `json:"created_by,omitempty"`
+	CreatorUsername string            `json:"creator_username,omitempty"` // set in lists
+	CreatorEmail    string            `json:"creator_email,omitempty"`    // set in lists
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	LastTriggeredAt *time.Time        `json:"last_triggered_at,omitempty"`
+}
+
+// RetryConfig controls how failed webhook deliveries are retried.
+type RetryConfig struct {
+	MaxAttempts    int   `json:"max_attempts"`
+	BackoffSeconds []int `json:"backoff_seconds"`
+}
+
+// WebhookDelivery is one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // "pending", "success" or "failed"
+	HTTPStatusCode *int            `json:"http_status_code,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	Attempts       int             `json:"attempts"`
+	NextRetryAt    *time.Time      `json:"next_retry_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+}
+
+// CreateWebhookRequest creates a webhook.
+type CreateWebhookRequest struct {
+	Name        string            `json:"name"`
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	RetryConfig *RetryConfig      `json:"retry_config,omitempty"`
+}
+
+// CreateWebhookResponse contains the created webhook and its signing secret, which is
+// only returned here.
+type CreateWebhookResponse struct {
+	Webhook   Webhook `json:"webhook"`
+	SecretKey string  `json:"secret_key"`
+}
+
+// UpdateWebhookRequest updates a webhook. Empty fields are left unchanged.
+type UpdateWebhookRequest struct {
+	Name        string            `json:"name,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Events      []string          `json:"events,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	IsActive    *bool             `json:"is_active,omitempty"`
+	RetryConfig *RetryConfig      `json:"retry_config,omitempty"`
+}
+
+// TestWebhookRequest sends a test event to a webhook.
+type TestWebhookRequest struct {
+	EventType string                 `json:"event_type"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+}
+
+// WebhookListResponse is a page of webhooks.
+type WebhookListResponse struct {
+	Webhooks   []Webhook `json:"webhooks"`
+	Total      int       `json:"total"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalPages int       `json:"total_pages"`
+}
+
+// WebhookDeliveryListResponse is a page of webhook deliveries.
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ListWebhooksParams contains parameters for listing webhooks.
+type ListWebhooksParams struct {
+	Page     int `url:"page,omitempty"`
+	PageSize int `url:"page_size,omitempty"`
+}
+
+// ListWebhookDeliveriesParams contains parameters for listing webhook deliveries.
+type ListWebhookDeliveriesParams struct {
+	Page     int `url:"page,omitempty"`
+	PageSize int `url:"page_size,omitempty"`
+}
+
+// OAuthClientWebhook is a webhook registered by the owner of an OAuth client. It only
+// receives events about that client.
+type OAuthClientWebhook struct {
+	ID              string     `json:"id"`
+	ClientID        string     `json:"client_id"`
+	URL             string     `json:"url"`
+	Events          []string   `json:"events"`
+	IsActive        bool       `json:"is_active"`
+	CreatedBy       *string    `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+}
+
+// OAuthClientWebhookDelivery is one attempt to deliver an event to an OAuth client webhook.
+// Failed deliveries are not retried.
+type OAuthClientWebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // "pending", "success" or "failed"
+	HTTPStatusCode *int            `json:"http_status_code,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+}
+
+// CreateOAuthClientWebhookRequest registers a webhook for an OAuth client. URL must use https.
+type CreateOAuthClientWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// CreateOAuthClientWebhookResponse contains the created webhook and its signing secret,
+// which is only returned here.
+type CreateOAuthClientWebhookResponse struct {
+	Webhook   OAuthClientWebhook `json:"webhook"`
+	SecretKey string             `json:"secret_key"`
+}
+
+// UpdateOAuthClientWebhookRequest updates an OAuth client webhook. Empty fields are left unchanged.
+type UpdateOAuthClientWebhookRequest struct {
+	URL      string   `json:"url,omitempty"`
+	Events   []string `json:"events,omitempty"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+// OAuthClientWebhookListResponse contains the webhooks of an OAuth client and the events
+// they can subscribe to.
+type OAuthClientWebhookListResponse struct {
+	Webhooks        []OAuthClientWebhook `json:"webhooks"`
+	AvailableEvents []string             `json:"available_events"`
+}
+
+// OAuthClientWebhookDeliveryListResponse is a page of OAuth client webhook deliveries.
+type OAuthClientWebhookDeliveryListResponse struct {
+	Deliveries []OAuthClientWebhookDelivery `json:"deliveries"`
+	Total      int                          `json:"total"`
+	Page       int                          `json:"page"`
+	PageSize   int                          `json:"page_size"`
+	TotalPages int                          `json:"total_pages"`
+}
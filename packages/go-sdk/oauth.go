@@ -19,6 +19,11 @@ const (
 	OAuthProviderYandex    = "yandex"
 	OAuthProviderInstagram = "instagram"
 	OAuthProviderTelegram  = "telegram"
+	OAuthProviderApple     = "apple"
+	OAuthProviderMicrosoft = "microsoft"
+	OAuthProviderLinkedIn  = "linkedin"
+	// OAuthProviderOIDCGeneric is any OpenID Connect provider configured by its issuer
+	OAuthProviderOIDCGeneric = "oidc-generic"
 )
 
 // GetProviders retrieves the list of enabled OAuth providers.
@@ -56,6 +61,33 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider, code, state
 	return &resp, nil
 }
 
+// ConfirmLink links the provider account from a social login to the existing account
+// with the same email by confirming that account's password, then signs in.
+// Used when the login response has LinkRequired set.
+func (s *OAuthService) ConfirmLink(ctx context.Context, req *models.ConfirmOAuthLinkRequest) (*models.AuthResponse, error) {
+	var resp models.AuthResponse
+	if err := s.client.post(ctx, "/api/auth/oauth/link/confirm", req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.AccessToken != "" {
+		s.client.SetTokens(resp.AccessToken, resp.RefreshToken, resp.ExpiresIn)
+	}
+
+	return &resp, nil
+}
+
+// GetProviderToken returns the provider access token of the current user so the
+// provider's API can be called on their behalf. The server refreshes expired tokens.
+// Requires provider token storage to be enabled on the server.
+func (s *OAuthService) GetProviderToken(ctx context.Context, provider string) (*models.ProviderToken, error) {
+	var resp models.ProviderToken
+	if err := s.client.get(ctx, fmt.Sprintf("/api/auth/%s/token", provider), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // PasswordlessService handles passwordless login operations.
 type PasswordlessService struct {
 	client *Client
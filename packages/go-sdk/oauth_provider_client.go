@@ -74,14 +74,38 @@ type OAuthProviderConfig struct {
 	// Headers contains custom headers to include in every request.
 	// Common headers: X-Application-ID, X-Client-Name, etc.
 	Headers map[string]string
+
+	// TrustedIssuers restricts the issuer the discovery document may declare.
+	// Discovery fails if the document's issuer is not listed. Empty allows any issuer.
+	TrustedIssuers []string
+
+	// PinnedKeyThumbprints pins the signing keys by their RFC 7638 SHA-256 thumbprint
+	// (base64url, see JWKThumbprint). Keys served by the JWKS endpoint that are not pinned
+	// are dropped, and GetJWKS fails if no pinned key remains. Empty trusts every key.
+	PinnedKeyThumbprints []string
+
+	// StateStore keeps the state, nonce and PKCE verifier of authorization requests started
+	// with StartAuthorization until ValidateCallback consumes them. See MemoryStateStore,
+	// RedisStateStore and CookieStateStore.
+	StateStore StateStore
+
+	// StateTTL is how long a started authorization may take to come back. Default: 10 minutes.
+	StateTTL time.Duration
 }
 
+// ErrUntrustedIssuer is returned when the discovery document declares an issuer outside TrustedIssuers
+var ErrUntrustedIssuer = errors.New("discovery issuer is not trusted")
+
+// ErrNoPinnedKeys is returned when the JWKS endpoint serves none of the pinned keys
+var ErrNoPinnedKeys = errors.New("JWKS contains no pinned keys")
+
 type OAuthProviderClient struct {
 	config     OAuthProviderConfig
 	httpClient *http.Client
 
-	discovery     *models.OIDCDiscovery
-	discoveryOnce sync.Once
+	// discovery is cached once fetched; failed fetches are retried on the next call
+	discovery   *models.OIDCDiscovery
+	discoveryMu sync.Mutex
 
 	jwks   *models.JWKS
 	jwksMu sync.RWMutex
@@ -101,6 +125,9 @@ func NewOAuthProviderClient(config OAuthProviderConfig) *OAuthProviderClient {
 	if !config.UsePKCE {
 		config.UsePKCE = true
 	}
+	if config.StateTTL <= 0 {
+		config.StateTTL = 10 * time.Minute
+	}
 
 	return &OAuthProviderClient{
 		config:     config,
@@ -174,36 +201,49 @@ func (c *OAuthProviderClient) applyHeaders(req *http.Request) {
 }
 
 func (c *OAuthProviderClient) GetDiscovery(ctx context.Context) (*models.OIDCDiscovery, error) {
-	var err error
-	c.discoveryOnce.Do(func() {
-		url := c.config.Issuer + "/.well-known/openid-configuration"
-		req, reqErr := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if reqErr != nil {
-			err = reqErr
-			return
-		}
-		c.applyHeaders(req)
+	c.discoveryMu.Lock()
+	defer c.discoveryMu.Unlock()
 
-		resp, respErr := c.httpClient.Do(req)
-		if respErr != nil {
-			err = respErr
-			return
-		}
-		defer resp.Body.Close()
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			err = fmt.Errorf("discovery endpoint returned status %d", resp.StatusCode)
-			return
-		}
+	discovery, err := c.fetchDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.discovery = discovery
+	return c.discovery, nil
+}
 
-		c.discovery = &models.OIDCDiscovery{}
-		err = json.NewDecoder(resp.Body).Decode(c.discovery)
-	})
+func (c *OAuthProviderClient) fetchDiscovery(ctx context.Context) (*models.OIDCDiscovery, error) {
+	url := c.config.Issuer + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.applyHeaders(req)
 
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	return c.discovery, nil
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery endpoint returned status %d", resp.StatusCode)
+	}
+
+	discovery := &models.OIDCDiscovery{}
+	if err := json.NewDecoder(resp.Body).Decode(discovery); err != nil {
+		return nil, err
+	}
+
+	if len(c.config.TrustedIssuers) > 0 && !containsString(c.config.TrustedIssuers, discovery.Issuer) {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedIssuer, discovery.Issuer)
+	}
+
+	return discovery, nil
 }
 
 func (c *OAuthProviderClient) GetJWKS(ctx context.Context) (*models.JWKS, error) {
@@ -235,15 +275,70 @@ func (c *OAuthProviderClient) GetJWKS(ctx context.Context) (*models.JWKS, error)
 		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
 	}
 
+	jwks := &models.JWKS{}
+	if err := json.NewDecoder(resp.Body).Decode(jwks); err != nil {
+		return nil, err
+	}
+
+	if len(c.config.PinnedKeyThumbprints) > 0 {
+		if jwks, err = c.pinnedKeys(jwks); err != nil {
+			return nil, err
+		}
+	}
+
 	c.jwksMu.Lock()
 	defer c.jwksMu.Unlock()
 
-	c.jwks = &models.JWKS{}
-	if err := json.NewDecoder(resp.Body).Decode(c.jwks); err != nil {
-		return nil, err
+	c.jwks = jwks
+	return c.jwks, nil
+}
+
+// pinnedKeys keeps only the keys whose thumbprint is pinned
+func (c *OAuthProviderClient) pinnedKeys(jwks *models.JWKS) (*models.JWKS, error) {
+	pinned := &models.JWKS{}
+	for _, key := range jwks.Keys {
+		thumbprint, err := JWKThumbprint(key)
+		if err != nil {
+			continue
+		}
+		if containsString(c.config.PinnedKeyThumbprints, thumbprint) {
+			pinned.Keys = append(pinned.Keys, key)
+		}
 	}
 
-	return c.jwks, nil
+	if len(pinned.Keys) == 0 {
+		return nil, ErrNoPinnedKeys
+	}
+	return pinned, nil
+}
+
+// JWKThumbprint computes the RFC 7638 SHA-256 thumbprint of a key, base64url encoded
+// without padding. Use it to obtain the values for OAuthProviderConfig.PinnedKeyThumbprints.
+func JWKThumbprint(key models.JWK) (string, error) {
+	// Required members in lexicographic order, as RFC 7638 section 3.2 prescribes
+	var canonical string
+	switch key.Kty {
+	case "RSA":
+		if key.E == "" || key.N == "" {
+			return "", errors.New("RSA key requires e and n")
+		}
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, key.E, key.N)
+	case "EC":
+		if key.Crv == "" || key.X == "" || key.Y == "" {
+			return "", errors.New("EC key requires crv, x and y")
+		}
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, key.Crv, key.X, key.Y)
+	case "OKP":
+		if key.Crv == "" || key.X == "" {
+			return "", errors.New("OKP key requires crv and x")
+		}
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, key.Crv, key.X)
+	default:
+		return "", fmt.Errorf("unsupported key type %q", key.Kty)
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
 }
 
 type AuthorizationURLResult struct {
@@ -346,6 +441,13 @@ func (c *OAuthProviderClient) ExchangeCode(ctx context.Context, code, codeVerifi
 }
 
 func (c *OAuthProviderClient) RefreshTokens(ctx context.Context, refreshToken string) (*models.OAuthTokenResponse, error) {
+	return c.RefreshTokensWithNonce(ctx, refreshToken, "")
+}
+
+// RefreshTokensWithNonce refreshes tokens for a client that requires refresh nonces. nonce is
+// the RefreshNonce of the response refreshToken came with; the new response carries the nonce
+// for the next refresh. A refresh token presented with a wrong nonce is revoked by the server.
+func (c *OAuthProviderClient) RefreshTokensWithNonce(ctx context.Context, refreshToken, nonce string) (*models.OAuthTokenResponse, error) {
 	discovery, err := c.GetDiscovery(ctx)
 	if err != nil {
 		return nil, err
@@ -361,6 +463,10 @@ func (c *OAuthProviderClient) RefreshTokens(ctx context.Context, refreshToken st
 		params.Set("client_secret", c.config.ClientSecret)
 	}
 
+	if nonce != "" {
+		params.Set("refresh_nonce", nonce)
+	}
+
 	return c.tokenRequest(ctx, discovery.TokenEndpoint, params)
 }
 
@@ -557,6 +663,12 @@ func (c *OAuthProviderClient) PollDeviceToken(ctx context.Context, deviceCode st
 }
 
 func (c *OAuthProviderClient) ClientCredentialsGrant(ctx context.Context, scopes []string) (*models.OAuthTokenResponse, error) {
+	return c.clientCredentialsGrant(ctx, scopes, "")
+}
+
+// clientCredentialsGrant requests a client_credentials token. A non-empty audience is sent
+// as the audience parameter.
+func (c *OAuthProviderClient) clientCredentialsGrant(ctx context.Context, scopes []string, audience string) (*models.OAuthTokenResponse, error) {
 	if c.config.ClientSecret == "" {
 		return nil, errors.New("client_credentials grant requires client_secret")
 	}
@@ -577,6 +689,9 @@ func (c *OAuthProviderClient) ClientCredentialsGrant(ctx context.Context, scopes
 	if scope != "" {
 		params.Set("scope", scope)
 	}
+	if audience != "" {
+		params.Set("audience", audience)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", discovery.TokenEndpoint, strings.NewReader(params.Encode()))
 	if err != nil {
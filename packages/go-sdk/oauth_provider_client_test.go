@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -759,6 +760,49 @@ func TestRefreshTokens(t *testing.T) {
 			t.Error("expected nil result when refresh fails")
 		}
 	})
+
+	t.Run("ShouldSendRefreshNonce", func(t *testing.T) {
+		// Arrange
+		var serverURL string
+		var capturedNonce string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+			discovery := discoveryResponse(serverURL)
+			json.NewEncoder(w).Encode(discovery)
+		})
+		mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			capturedNonce = r.Form.Get("refresh_nonce")
+
+			json.NewEncoder(w).Encode(models.OAuthTokenResponse{
+				AccessToken:  "new-access-token",
+				TokenType:    "Bearer",
+				RefreshToken: "new-refresh-token",
+				RefreshNonce: "next-nonce",
+			})
+		})
+		server := newTestServer(t, mux)
+		serverURL = server.URL
+
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:   serverURL,
+			ClientID: "test-client",
+		})
+
+		// Act
+		result, err := client.RefreshTokensWithNonce(context.Background(), "old-refresh-token", "issued-nonce")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if capturedNonce != "issued-nonce" {
+			t.Errorf("expected refresh_nonce issued-nonce, got %q", capturedNonce)
+		}
+		if result.RefreshNonce != "next-nonce" {
+			t.Errorf("expected next-nonce, got %s", result.RefreshNonce)
+		}
+	})
 }
 
 // TestGetDiscovery tests OIDC discovery
@@ -850,6 +894,42 @@ func TestGetDiscovery(t *testing.T) {
 			t.Error("expected nil discovery when endpoint fails")
 		}
 	})
+
+	t.Run("ShouldRetry_AfterDiscoveryEndpointFailed", func(t *testing.T) {
+		// Arrange
+		var serverURL string
+		var callCount int32
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&callCount, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			json.NewEncoder(w).Encode(discoveryResponse(serverURL))
+		})
+		server := newTestServer(t, mux)
+		serverURL = server.URL
+
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:   serverURL,
+			ClientID: "test-client",
+		})
+
+		// Act
+		_, firstErr := client.GetDiscovery(context.Background())
+		discovery, err := client.GetDiscovery(context.Background())
+
+		// Assert
+		if firstErr == nil {
+			t.Error("expected error from the failed first fetch")
+		}
+		if err != nil {
+			t.Fatalf("unexpected error after retry: %v", err)
+		}
+		if discovery.Issuer != serverURL {
+			t.Errorf("expected issuer %s, got %s", serverURL, discovery.Issuer)
+		}
+	})
 }
 
 // TestGetJWKS tests JWKS fetching
@@ -1791,3 +1871,139 @@ func TestPKCECodeChallengeVerification(t *testing.T) {
 		}
 	})
 }
+
+// TestJWKThumbprint tests RFC 7638 thumbprints
+func TestJWKThumbprint(t *testing.T) {
+	t.Run("ShouldMatchRFC7638Example", func(t *testing.T) {
+		// Arrange
+		key := models.JWK{
+			Kty: "RSA",
+			Kid: "2011-04-29",
+			Alg: "RS256",
+			N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			E:   "AQAB",
+		}
+
+		// Act
+		thumbprint, err := JWKThumbprint(key)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if thumbprint != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+			t.Errorf("unexpected thumbprint %s", thumbprint)
+		}
+	})
+
+	t.Run("ShouldRejectIncompleteKey", func(t *testing.T) {
+		// Act
+		_, err := JWKThumbprint(models.JWK{Kty: "EC", Crv: "P-256", X: "x"})
+
+		// Assert
+		if err == nil {
+			t.Error("expected error for EC key without y")
+		}
+	})
+}
+
+// TestKeyPinning tests JWKS key pinning and issuer allowlisting
+func TestKeyPinning(t *testing.T) {
+	pinnedKey := models.JWK{Kty: "RSA", Kid: "pinned", N: "pinned-modulus", E: "AQAB"}
+	rogueKey := models.JWK{Kty: "RSA", Kid: "rogue", N: "rogue-modulus", E: "AQAB"}
+	pin, _ := JWKThumbprint(pinnedKey)
+
+	newServer := func(t *testing.T, keys ...models.JWK) string {
+		var serverURL string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/openid-configuration", discoveryHandler(&serverURL))
+		mux.HandleFunc("/oauth/jwks", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(models.JWKS{Keys: keys})
+		})
+		server := newTestServer(t, mux)
+		serverURL = server.URL
+		return serverURL
+	}
+
+	t.Run("ShouldDropUnpinnedKeys", func(t *testing.T) {
+		// Arrange
+		serverURL := newServer(t, pinnedKey, rogueKey)
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:               serverURL,
+			ClientID:             "test-client",
+			PinnedKeyThumbprints: []string{pin},
+		})
+
+		// Act
+		jwks, err := client.GetJWKS(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "pinned" {
+			t.Errorf("expected only the pinned key, got %+v", jwks.Keys)
+		}
+	})
+
+	t.Run("ShouldFail_WhenNoPinnedKeyServed", func(t *testing.T) {
+		// Arrange
+		serverURL := newServer(t, rogueKey)
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:               serverURL,
+			ClientID:             "test-client",
+			PinnedKeyThumbprints: []string{pin},
+		})
+
+		// Act
+		_, err := client.GetJWKS(context.Background())
+
+		// Assert
+		if !errors.Is(err, ErrNoPinnedKeys) {
+			t.Errorf("expected ErrNoPinnedKeys, got %v", err)
+		}
+	})
+
+	t.Run("ShouldRejectUntrustedIssuer", func(t *testing.T) {
+		// Arrange
+		serverURL := newServer(t, pinnedKey)
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:         serverURL,
+			ClientID:       "test-client",
+			TrustedIssuers: []string{"https://auth.example.com"},
+		})
+
+		// Act
+		_, err := client.GetDiscovery(context.Background())
+		_, jwksErr := client.GetJWKS(context.Background())
+
+		// Assert
+		if !errors.Is(err, ErrUntrustedIssuer) {
+			t.Errorf("expected ErrUntrustedIssuer, got %v", err)
+		}
+		if !errors.Is(jwksErr, ErrUntrustedIssuer) {
+			t.Errorf("expected later calls to keep failing, got %v", jwksErr)
+		}
+	})
+
+	t.Run("ShouldAcceptTrustedIssuer", func(t *testing.T) {
+		// Arrange
+		serverURL := newServer(t, pinnedKey)
+		client := NewOAuthProviderClient(OAuthProviderConfig{
+			Issuer:         serverURL,
+			ClientID:       "test-client",
+			TrustedIssuers: []string{serverURL},
+		})
+
+		// Act
+		discovery, err := client.GetDiscovery(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if discovery.Issuer != serverURL {
+			t.Errorf("expected issuer %s, got %s", serverURL, discovery.Issuer)
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package authgateway
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrInvalidState is returned by ValidateCallback when the callback's state was not issued by
+// StartAuthorization, has expired or was already used
+var ErrInvalidState = errors.New("invalid or expired OAuth state")
+
+// ErrNoStateStore is returned by StartAuthorization and ValidateCallback when
+// OAuthProviderConfig.StateStore is not set
+var ErrNoStateStore = errors.New("no OAuth state store configured")
+
+// AuthorizationState is what an authorization request started with StartAuthorization needs
+// back on its callback.
+type AuthorizationState struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// StateStore keeps authorization states between StartAuthorization and ValidateCallback.
+// Consume must hand out a state at most once.
+type StateStore interface {
+	// Save keeps state until its ExpiresAt. w is the response that redirects to the
+	// authorization endpoint; stores keeping states on the server ignore it.
+	Save(ctx context.Context, w http.ResponseWriter, state *AuthorizationState) error
+	// Consume returns and removes the state saved under state, or ErrInvalidState if there
+	// is none or it expired. r is the callback request.
+	Consume(ctx context.Context, r *http.Request, state string) (*AuthorizationState, error)
+}
+
+// AuthorizationCallback is a callback that passed ValidateCallback
+type AuthorizationCallback struct {
+	// Code is the authorization code to pass to ExchangeCode
+	Code string
+	// State is the saved state of the authorization request; pass its CodeVerifier to
+	// ExchangeCode and check its Nonce against the ID token
+	State *AuthorizationState
+}
+
+// StartAuthorization builds an authorization URL like GetAuthorizationURL and saves its state,
+// nonce and PKCE verifier in the configured StateStore. Redirect the user to the URL and
+// call ValidateCallback on the redirect URI.
+//
+//	authURL, err := client.StartAuthorization(r.Context(), w, nil)
+//	if err != nil { ... }
+//	http.Redirect(w, r, authURL.URL, http.StatusFound)
+func (c *OAuthProviderClient) StartAuthorization(ctx context.Context, w http.ResponseWriter, opts *AuthorizationURLOptions) (*AuthorizationURLResult, error) {
+	if c.config.StateStore == nil {
+		return nil, ErrNoStateStore
+	}
+
+	result, err := c.GetAuthorizationURL(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.config.StateStore.Save(ctx, w, &AuthorizationState{
+		State:        result.State,
+		Nonce:        result.Nonce,
+		CodeVerifier: result.CodeVerifier,
+		ExpiresAt:    time.Now().Add(c.config.StateTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save OAuth state: %w", err)
+	}
+	return result, nil
+}
+
+// ValidateCallback checks the request to the redirect URI of an authorization started with
+// StartAuthorization. The state is looked up and consumed before anything else, so a forged
+// or replayed callback fails with ErrInvalidState, even one carrying an error. A user who
+// declined gets ErrAccessDenied.
+//
+//	callback, err := client.ValidateCallback(r.Context(), r)
+//	if err != nil { ... }
+//	tokens, err := client.ExchangeCode(r.Context(), callback.Code, callback.State.CodeVerifier)
+func (c *OAuthProviderClient) ValidateCallback(ctx context.Context, r *http.Request) (*AuthorizationCallback, error) {
+	if c.config.StateStore == nil {
+		return nil, ErrNoStateStore
+	}
+
+	// FormValue also covers response_mode=form_post
+	state := r.FormValue("state")
+	if state == "" {
+		return nil, ErrInvalidState
+	}
+	saved, err := c.config.StateStore.Consume(ctx, r, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if errCode := r.FormValue("error"); errCode != "" {
+		if errCode == "access_denied" {
+			return nil, ErrAccessDenied
+		}
+		return nil, fmt.Errorf("%s: %s", errCode, r.FormValue("error_description"))
+	}
+
+	code := r.FormValue("code")
+	if code == "" {
+		return nil, errors.New("callback carries no authorization code")
+	}
+
+	return &AuthorizationCallback{Code: code, State: saved}, nil
+}
+
+// MemoryStateStore keeps authorization states in memory. It only works when the callback
+// reaches the instance that started the authorization; use RedisStateStore or
+// CookieStateStore behind a load balancer.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]*AuthorizationState
+}
+
+// NewMemoryStateStore creates an in-memory state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: make(map[string]*AuthorizationState)}
+}
+
+// Save keeps state in memory, dropping the expired ones
+func (s *MemoryStateStore) Save(ctx context.Context, w http.ResponseWriter, state *AuthorizationState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, saved := range s.states {
+		if now.After(saved.ExpiresAt) {
+			delete(s.states, key)
+		}
+	}
+	s.states[state.State] = state
+	return nil
+}
+
+// Consume returns and removes the state
+func (s *MemoryStateStore) Consume(ctx context.Context, r *http.Request, state string) (*AuthorizationState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.states[state]
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	delete(s.states, state)
+	if time.Now().After(saved.ExpiresAt) {
+		return nil, ErrInvalidState
+	}
+	return saved, nil
+}
+
+// RedisStateStore keeps authorization states in Redis, shared by every instance of the
+// application. Consume needs Redis 6.2 or later (GETDEL).
+type RedisStateStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStateStore creates a Redis state store. Keys are prefix followed by the state;
+// an empty prefix uses "authgateway:oauth_state:".
+func NewRedisStateStore(client redis.UniversalClient, prefix string) *RedisStateStore {
+	if prefix == "" {
+		prefix = "authgateway:oauth_state:"
+	}
+	return &RedisStateStore{client: client, prefix: prefix}
+}
+
+// Save keeps state in Redis until it expires
+func (s *RedisStateStore) Save(ctx context.Context, w http.ResponseWriter, state *AuthorizationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(state.ExpiresAt)
+	if ttl <= 0 {
+		return ErrInvalidState
+	}
+	return s.client.Set(ctx, s.prefix+state.State, data, ttl).Err()
+}
+
+// Consume gets and deletes the state in one command, so concurrent callbacks can't both
+// get it
+func (s *RedisStateStore) Consume(ctx context.Context, r *http.Request, state string) (*AuthorizationState, error) {
+	data, err := s.client.GetDel(ctx, s.prefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &AuthorizationState{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, err
+	}
+	if time.Now().After(saved.ExpiresAt) {
+		return nil, ErrInvalidState
+	}
+	return saved, nil
+}
+
+// CookieStateStore keeps authorization states in the user's browser, in a cookie encrypted
+// with AES-256-GCM, so it needs no server-side storage. The cookie is HttpOnly and SameSite=Lax,
+// which lets it come back on the redirect from Auth Gateway, and bound to its state. A cookie
+// can't be deleted from Consume, so it stays usable until it expires; the authorization code
+// it pairs with is single-use on the server.
+type CookieStateStore struct {
+	// CookieName prefixes the cookie names; each authorization gets its own cookie so logins
+	// in several tabs don't clash. Default: "agw_oauth_state".
+	CookieName string
+	// Path and Domain scope the cookie. Default path: "/".
+	Path   string
+	Domain string
+	// Insecure drops the Secure attribute, for development over plain HTTP
+	Insecure bool
+
+	aead cipher.AEAD
+}
+
+// NewCookieStateStore creates a cookie state store encrypting with key, which must be 32
+// random bytes shared by every instance of the application
+func NewCookieStateStore(key []byte) (*CookieStateStore, error) {
+	if len(key) != 32 {
+		return nil, errors.New("cookie state store key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieStateStore{
+		CookieName: "agw_oauth_state",
+		Path:       "/",
+		aead:       aead,
+	}, nil
+}
+
+// cookieName names the cookie of a state
+func (s *CookieStateStore) cookieName(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return s.CookieName + "_" + hex.EncodeToString(sum[:8])
+}
+
+// Save sets the encrypted state cookie on w
+func (s *CookieStateStore) Save(ctx context.Context, w http.ResponseWriter, state *AuthorizationState) error {
+	if w == nil {
+		return errors.New("cookie state store needs the response writer")
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := s.aead.Seal(nonce, nonce, data, []byte(state.State))
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookieName(state.State),
+		Value:    base64.RawURLEncoding.EncodeToString(sealed),
+		Path:     s.Path,
+		Domain:   s.Domain,
+		Expires:  state.ExpiresAt,
+		MaxAge:   int(time.Until(state.ExpiresAt).Seconds()),
+		Secure:   !s.Insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Consume decrypts the state cookie of r
+func (s *CookieStateStore) Consume(ctx context.Context, r *http.Request, state string) (*AuthorizationState, error) {
+	cookie, err := r.Cookie(s.cookieName(state))
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return nil, ErrInvalidState
+	}
+
+	nonceSize := s.aead.NonceSize()
+	data, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(state))
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+
+	saved := &AuthorizationState{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, ErrInvalidState
+	}
+	if saved.State != state || time.Now().After(saved.ExpiresAt) {
+		return nil, ErrInvalidState
+	}
+	return saved, nil
+}
@@ -0,0 +1,254 @@
+package authgateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newStateTestClient creates a client with store against a test server serving discovery
+func newStateTestClient(t *testing.T, store StateStore) *OAuthProviderClient {
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", discoveryHandler(&serverURL))
+	server := newTestServer(t, mux)
+	serverURL = server.URL
+
+	return NewOAuthProviderClient(OAuthProviderConfig{
+		Issuer:      serverURL,
+		ClientID:    "test-client",
+		RedirectURI: "https://app.example.com/callback",
+		StateStore:  store,
+	})
+}
+
+// callbackRequest builds the redirect back from the authorization endpoint, carrying the
+// cookies set on start
+func callbackRequest(start *httptest.ResponseRecorder, params url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "https://app.example.com/callback?"+params.Encode(), nil)
+	for _, cookie := range start.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func TestValidateCallback(t *testing.T) {
+	ctx := context.Background()
+
+	cookieStore, err := NewCookieStateStore(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stores := map[string]StateStore{
+		"Memory": NewMemoryStateStore(),
+		"Cookie": cookieStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name+"_ShouldReturnCodeAndSavedState", func(t *testing.T) {
+			// Arrange
+			client := newStateTestClient(t, store)
+			w := httptest.NewRecorder()
+			authURL, err := client.StartAuthorization(ctx, w, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			callback, err := client.ValidateCallback(ctx, callbackRequest(w, url.Values{
+				"code":  {"auth-code"},
+				"state": {authURL.State},
+			}))
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if callback.Code != "auth-code" {
+				t.Errorf("expected code auth-code, got %q", callback.Code)
+			}
+			if callback.State.Nonce != authURL.Nonce || callback.State.CodeVerifier != authURL.CodeVerifier {
+				t.Errorf("unexpected saved state: %+v", callback.State)
+			}
+		})
+
+		t.Run(name+"_ShouldRejectUnknownState", func(t *testing.T) {
+			// Arrange
+			client := newStateTestClient(t, store)
+			w := httptest.NewRecorder()
+			if _, err := client.StartAuthorization(ctx, w, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			_, err := client.ValidateCallback(ctx, callbackRequest(w, url.Values{
+				"code":  {"auth-code"},
+				"state": {"forged"},
+			}))
+
+			// Assert
+			if !errors.Is(err, ErrInvalidState) {
+				t.Errorf("expected ErrInvalidState, got %v", err)
+			}
+		})
+
+		t.Run(name+"_ShouldCheckStateBeforeError", func(t *testing.T) {
+			// Arrange
+			client := newStateTestClient(t, store)
+			w := httptest.NewRecorder()
+			authURL, err := client.StartAuthorization(ctx, w, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			_, forgedErr := client.ValidateCallback(ctx, callbackRequest(w, url.Values{
+				"error": {"access_denied"},
+				"state": {"forged"},
+			}))
+			_, deniedErr := client.ValidateCallback(ctx, callbackRequest(w, url.Values{
+				"error": {"access_denied"},
+				"state": {authURL.State},
+			}))
+
+			// Assert
+			if !errors.Is(forgedErr, ErrInvalidState) {
+				t.Errorf("expected ErrInvalidState, got %v", forgedErr)
+			}
+			if !errors.Is(deniedErr, ErrAccessDenied) {
+				t.Errorf("expected ErrAccessDenied, got %v", deniedErr)
+			}
+		})
+	}
+
+	t.Run("ShouldConsumeStateOnce", func(t *testing.T) {
+		// Arrange
+		client := newStateTestClient(t, NewMemoryStateStore())
+		w := httptest.NewRecorder()
+		authURL, err := client.StartAuthorization(ctx, w, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		params := url.Values{"code": {"auth-code"}, "state": {authURL.State}}
+
+		// Act
+		_, firstErr := client.ValidateCallback(ctx, callbackRequest(w, params))
+		_, replayErr := client.ValidateCallback(ctx, callbackRequest(w, params))
+
+		// Assert
+		if firstErr != nil {
+			t.Fatalf("unexpected error: %v", firstErr)
+		}
+		if !errors.Is(replayErr, ErrInvalidState) {
+			t.Errorf("expected ErrInvalidState on replay, got %v", replayErr)
+		}
+	})
+
+	t.Run("ShouldFailWithoutStore", func(t *testing.T) {
+		// Arrange
+		client := newStateTestClient(t, nil)
+
+		// Act
+		_, startErr := client.StartAuthorization(ctx, httptest.NewRecorder(), nil)
+		_, callbackErr := client.ValidateCallback(ctx, httptest.NewRequest(http.MethodGet, "/callback?state=s&code=c", nil))
+
+		// Assert
+		if !errors.Is(startErr, ErrNoStateStore) || !errors.Is(callbackErr, ErrNoStateStore) {
+			t.Errorf("expected ErrNoStateStore, got %v and %v", startErr, callbackErr)
+		}
+	})
+}
+
+func TestMemoryStateStore_ShouldRejectExpiredState(t *testing.T) {
+	// Arrange
+	store := NewMemoryStateStore()
+	store.Save(context.Background(), nil, &AuthorizationState{State: "s", ExpiresAt: time.Now().Add(-time.Second)})
+
+	// Act
+	_, err := store.Consume(context.Background(), nil, "s")
+
+	// Assert
+	if !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestCookieStateStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ShouldSetProtectedCookie", func(t *testing.T) {
+		// Arrange
+		store, _ := NewCookieStateStore(make([]byte, 32))
+		w := httptest.NewRecorder()
+
+		// Act
+		err := store.Save(ctx, w, &AuthorizationState{State: "s", Nonce: "n", ExpiresAt: time.Now().Add(time.Minute)})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("expected one cookie, got %d", len(cookies))
+		}
+		cookie := cookies[0]
+		if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+			t.Errorf("expected HttpOnly, Secure, SameSite=Lax cookie, got %+v", cookie)
+		}
+		if cookie.MaxAge <= 0 {
+			t.Errorf("expected positive Max-Age, got %d", cookie.MaxAge)
+		}
+	})
+
+	t.Run("ShouldRejectTamperedCookie", func(t *testing.T) {
+		// Arrange
+		store, _ := NewCookieStateStore(make([]byte, 32))
+		w := httptest.NewRecorder()
+		store.Save(ctx, w, &AuthorizationState{State: "s", ExpiresAt: time.Now().Add(time.Minute)})
+		cookie := w.Result().Cookies()[0]
+		tampered := []byte(cookie.Value)
+		tampered[20] ^= 1 // flips a bit of the ciphertext
+		cookie.Value = string(tampered)
+		r := httptest.NewRequest(http.MethodGet, "/callback", nil)
+		r.AddCookie(cookie)
+
+		// Act
+		_, err := store.Consume(ctx, r, "s")
+
+		// Assert
+		if !errors.Is(err, ErrInvalidState) {
+			t.Errorf("expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("ShouldRejectCookieOfOtherKey", func(t *testing.T) {
+		// Arrange
+		store, _ := NewCookieStateStore(make([]byte, 32))
+		otherKey := make([]byte, 32)
+		otherKey[0] = 1
+		other, _ := NewCookieStateStore(otherKey)
+		w := httptest.NewRecorder()
+		other.Save(ctx, w, &AuthorizationState{State: "s", ExpiresAt: time.Now().Add(time.Minute)})
+		r := httptest.NewRequest(http.MethodGet, "/callback", nil)
+		r.AddCookie(w.Result().Cookies()[0])
+
+		// Act
+		_, err := store.Consume(ctx, r, "s")
+
+		// Assert
+		if !errors.Is(err, ErrInvalidState) {
+			t.Errorf("expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("ShouldRequire32ByteKey", func(t *testing.T) {
+		if _, err := NewCookieStateStore(make([]byte, 16)); err == nil {
+			t.Error("expected error for a 16-byte key")
+		}
+	})
+}
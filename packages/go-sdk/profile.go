@@ -2,6 +2,10 @@ package authgateway
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 
 	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
 )
@@ -42,3 +46,131 @@ func (s *ProfileService) ChangePassword(ctx context.Context, oldPassword, newPas
 	}
 	return &resp, nil
 }
+
+// ListConnectedApps lists the OAuth clients the current user has consented to,
+// with the granted scopes, token usage and the number of tokens still valid.
+func (s *ProfileService) ListConnectedApps(ctx context.Context) (*models.ConnectedAppListResponse, error) {
+	var resp models.ConnectedAppListResponse
+	if err := s.client.get(ctx, "/api/auth/connected-apps", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeConnectedApp revokes the current user's consent for an OAuth client
+// and every token issued to it. id is the ConnectedApp ID.
+func (s *ProfileService) RevokeConnectedApp(ctx context.Context, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/auth/connected-apps/%s", id), nil)
+}
+
+// RequestDataExport requests an export of the current user's data in format
+// (models.DataExportFormatJSON or models.DataExportFormatZIP; empty means JSON). The export
+// is generated in the background: poll until its status is ready, then download it with
+// DownloadDataExport. A ready export is returned again until it expires.
+func (s *ProfileService) RequestDataExport(ctx context.Context, format string) (*models.DataExport, error) {
+	path := "/api/auth/me/export"
+	if format != "" {
+		path += "?format=" + url.QueryEscape(format)
+	}
+
+	var resp models.DataExport
+	if err := s.client.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DownloadDataExport downloads a ready export through its signed download link.
+func (s *ProfileService) DownloadDataExport(ctx context.Context, export *models.DataExport) ([]byte, error) {
+	if export.DownloadURL == "" {
+		return nil, fmt.Errorf("data export %s has no download link (status %s)", export.ID, export.Status)
+	}
+	link, err := url.Parse(export.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download link: %w", err)
+	}
+	if !link.IsAbs() {
+		base, err := url.Parse(s.client.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %w", err)
+		}
+		link = base.ResolveReference(link)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+	if resp.StatusCode >= 400 {
+		return nil, s.client.parseErrorResponse(resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// ListOAuthClientWebhooks lists the webhooks of an OAuth client owned by the current user.
+func (s *ProfileService) ListOAuthClientWebhooks(ctx context.Context, clientID string) (*models.OAuthClientWebhookListResponse, error) {
+	var resp models.OAuthClientWebhookListResponse
+	if err := s.client.get(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks", clientID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateOAuthClientWebhook registers a webhook for consent, token revocation and secret
+// rotation events of an OAuth client owned by the current user. The signing secret is
+// only returned here.
+func (s *ProfileService) CreateOAuthClientWebhook(ctx context.Context, clientID string, req *models.CreateOAuthClientWebhookRequest) (*models.CreateOAuthClientWebhookResponse, error) {
+	var resp models.CreateOAuthClientWebhookResponse
+	if err := s.client.post(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks", clientID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetOAuthClientWebhook retrieves a webhook of an OAuth client owned by the current user.
+func (s *ProfileService) GetOAuthClientWebhook(ctx context.Context, clientID, id string) (*models.OAuthClientWebhook, error) {
+	var resp models.OAuthClientWebhook
+	if err := s.client.get(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks/%s", clientID, id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateOAuthClientWebhook updates a webhook of an OAuth client owned by the current user.
+func (s *ProfileService) UpdateOAuthClientWebhook(ctx context.Context, clientID, id string, req *models.UpdateOAuthClientWebhookRequest) (*models.OAuthClientWebhook, error) {
+	var resp models.OAuthClientWebhook
+	if err := s.client.put(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks/%s", clientID, id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteOAuthClientWebhook deletes a webhook of an OAuth client owned by the current user.
+func (s *ProfileService) DeleteOAuthClientWebhook(ctx context.Context, clientID, id string) error {
+	return s.client.delete(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks/%s", clientID, id), nil)
+}
+
+// ListOAuthClientWebhookDeliveries retrieves the delivery attempts of an OAuth client
+// webhook, newest first.
+func (s *ProfileService) ListOAuthClientWebhookDeliveries(ctx context.Context, clientID, id string, params *models.ListWebhookDeliveriesParams) (*models.OAuthClientWebhookDeliveryListResponse, error) {
+	query := ""
+	if params != nil {
+		query = buildQueryString(params)
+	}
+
+	var resp models.OAuthClientWebhookDeliveryListResponse
+	if err := s.client.get(ctx, fmt.Sprintf("/api/oauth-clients/%s/webhooks/%s/deliveries", clientID, id)+query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,83 @@
+package authgateway
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+func TestProfileService_DataExport(t *testing.T) {
+	t.Run("ShouldRequestExportInFormat", func(t *testing.T) {
+		// Arrange
+		var format string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/auth/me/export", func(w http.ResponseWriter, r *http.Request) {
+			format = r.URL.Query().Get("format")
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"e-1","status":"pending","format":"zip","created_at":"2025-05-01T12:00:00Z","expires_at":"2025-05-02T12:00:00Z"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		export, err := client.Profile.RequestDataExport(context.Background(), models.DataExportFormatZIP)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if format != "zip" {
+			t.Errorf("expected format zip, got %q", format)
+		}
+		if export.ID != "e-1" || export.Status != models.DataExportStatusPending {
+			t.Errorf("unexpected export: %+v", export)
+		}
+	})
+
+	t.Run("ShouldDownloadThroughSignedLinkWithoutBearerToken", func(t *testing.T) {
+		// Arrange
+		var authorization, signature string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/auth/me/export/e-1/download", func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			signature = r.URL.Query().Get("ag_signature")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"profile":{}}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+		client.SetTokens("access", "refresh", 900)
+		export := &models.DataExport{
+			ID:          "e-1",
+			Status:      models.DataExportStatusReady,
+			DownloadURL: "/api/auth/me/export/e-1/download?ag_expires=1&ag_user=u-1&ag_signature=sig",
+		}
+
+		// Act
+		content, err := client.Profile.DownloadDataExport(context.Background(), export)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != `{"profile":{}}` {
+			t.Errorf("unexpected content: %s", content)
+		}
+		if signature != "sig" {
+			t.Errorf("expected the signed query to be sent, got %q", signature)
+		}
+		if authorization != "" {
+			t.Errorf("expected no bearer token, got %q", authorization)
+		}
+	})
+
+	t.Run("ShouldFailForPendingExport", func(t *testing.T) {
+		client := NewClient(Config{BaseURL: "http://localhost"})
+
+		_, err := client.Profile.DownloadDataExport(context.Background(), &models.DataExport{ID: "e-1", Status: models.DataExportStatusPending})
+
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -68,9 +68,11 @@ type AuthProxyResponse struct {
 
 // SignInProxyRequest contains sign-in request data.
 type SignInProxyRequest struct {
-	Email    string  `json:"email,omitempty"`
-	Phone    *string `json:"phone,omitempty"`
-	Password string  `json:"password"`
+	Email      string  `json:"email,omitempty"`
+	Phone      *string `json:"phone,omitempty"`
+	Username   string  `json:"username,omitempty"`
+	Identifier string  `json:"identifier,omitempty"`
+	Password   string  `json:"password"`
 }
 
 // SignUpProxyRequest contains sign-up request data.
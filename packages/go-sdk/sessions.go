@@ -14,11 +14,13 @@ type SessionsService struct {
 
 // List retrieves all active sessions for the current user.
 func (s *SessionsService) List(ctx context.Context) ([]models.Session, error) {
-	var resp []models.Session
+	var resp struct {
+		Sessions []models.Session `json:"sessions"`
+	}
 	if err := s.client.get(ctx, "/api/sessions", &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return resp.Sessions, nil
 }
 
 // Revoke revokes a specific session.
@@ -0,0 +1,35 @@
+package authgateway
+
+import (
+	"context"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+// SignedURLsService mints and verifies short-lived URLs to protected resources,
+// so downloads need no bearer token in the query string.
+type SignedURLsService struct {
+	client *Client
+}
+
+// Create signs a URL for the authenticated user. The URL is bound to the user,
+// req.Scopes and one method.
+func (s *SignedURLsService) Create(ctx context.Context, req *models.CreateSignedURLRequest) (*models.SignedURLResponse, error) {
+	var resp models.SignedURLResponse
+	if err := s.client.post(ctx, "/api/signed-urls", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Verify asks the gateway whether a signed URL may be used. It needs no
+// credentials; invalid URLs return an *APIError with status 401 and the reason.
+// Services holding the signing secret can verify offline with the signedurl
+// package instead.
+func (s *SignedURLsService) Verify(ctx context.Context, req *models.VerifySignedURLRequest) (*models.VerifySignedURLResponse, error) {
+	var resp models.VerifySignedURLResponse
+	if err := s.client.post(ctx, "/api/v1/signed-urls/verify", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
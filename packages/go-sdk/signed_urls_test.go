@@ -0,0 +1,65 @@
+package authgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/smilemakc/auth-gateway/packages/go-sdk/models"
+)
+
+func TestSignedURLsService(t *testing.T) {
+	t.Run("ShouldCreateSignedURL", func(t *testing.T) {
+		// Arrange
+		var got models.CreateSignedURLRequest
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/signed-urls", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"url":"https://files.example.com/exports/report.csv?ag_signature=x","expires_at":"2025-03-04T10:05:00Z"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		resp, err := client.SignedURLs.Create(context.Background(), &models.CreateSignedURLRequest{
+			URL:       "https://files.example.com/exports/report.csv",
+			Scopes:    []string{"exports:read"},
+			ExpiresIn: 60,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.URL != "https://files.example.com/exports/report.csv" || got.ExpiresIn != 60 || len(got.Scopes) != 1 {
+			t.Errorf("unexpected request: %+v", got)
+		}
+		if resp.URL == "" || resp.ExpiresAt.IsZero() {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("ShouldReturnReason_WhenURLIsInvalid", func(t *testing.T) {
+		// Arrange
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/signed-urls/verify", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"valid":false,"error_message":"Signed URL expired"}`))
+		})
+		client := NewClient(Config{BaseURL: newTestServer(t, mux).URL})
+
+		// Act
+		_, err := client.SignedURLs.Verify(context.Background(), &models.VerifySignedURLRequest{URL: "/exports/report.csv?ag_signature=x"})
+
+		// Assert
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected *APIError, got %v", err)
+		}
+		if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Signed URL expired" {
+			t.Errorf("unexpected error: %+v", apiErr)
+		}
+	})
+}
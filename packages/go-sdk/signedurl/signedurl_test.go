@@ -0,0 +1,87 @@
+package signedurl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+func newTestSigner() *Signer {
+	s := New([]byte("0123456789abcdef0123456789abcdef"))
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func TestSigner_Sign_ShouldMatchGatewayReferenceVector(t *testing.T) {
+	// Arrange
+	// The gateway's pkg/signedurl asserts the same vector
+	want := "https://files.example.com/exports/report.csv?ag_expires=1741082700&ag_scope=exports%3Aread&ag_signature=1a_X1cBZz8dxq_VNnB-RxOMzWP5mtF0Kd24nFndu__Q&ag_user=123e4567-e89b-12d3-a456-426614174000&format=csv"
+
+	// Act
+	signed, err := newTestSigner().Sign("https://files.example.com/exports/report.csv?format=csv", Claims{
+		UserID:    "123e4567-e89b-12d3-a456-426614174000",
+		Scopes:    []string{"exports:read"},
+		ExpiresAt: testNow.Add(5 * time.Minute),
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if signed != want {
+		t.Errorf("Sign() = %s, want %s", signed, want)
+	}
+}
+
+func TestSigner_Verify(t *testing.T) {
+	s := newTestSigner()
+	signed, err := s.Sign("https://files.example.com/exports/report.csv", Claims{
+		UserID:    "user-1",
+		Scopes:    []string{"exports:read"},
+		ExpiresAt: testNow.Add(5 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		rawURL  string
+		signer  *Signer
+		wantErr error
+	}{
+		{name: "valid", method: http.MethodGet, rawURL: signed, signer: s},
+		{name: "head for get", method: http.MethodHead, rawURL: signed, signer: s},
+		{name: "other method", method: http.MethodPost, rawURL: signed, signer: s, wantErr: ErrMethodNotAllowed},
+		{name: "tampered", method: http.MethodGet, rawURL: signed + "&extra=1", signer: s, wantErr: ErrInvalidSignature},
+		{name: "other secret", method: http.MethodGet, rawURL: signed, signer: New([]byte("another-secret-another-secret-00")), wantErr: ErrInvalidSignature},
+		{name: "unsigned", method: http.MethodGet, rawURL: "https://files.example.com/exports/report.csv", signer: s, wantErr: ErrMissingSignature},
+		{name: "expired", method: http.MethodGet, rawURL: signed, signer: &Signer{secret: s.secret, now: func() time.Time { return testNow.Add(time.Hour) }}, wantErr: ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			u, err := url.Parse(tt.rawURL)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+
+			// Act
+			claims, err := tt.signer.Verify(tt.method, u)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && claims.UserID != "user-1" {
+				t.Errorf("UserID = %q, want user-1", claims.UserID)
+			}
+		})
+	}
+}
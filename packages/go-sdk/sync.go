@@ -65,7 +65,11 @@ func (ws *WebhookSyncer) GinWebhookHandler() gin.HandlerFunc {
 			return
 		}
 
-		signature := c.GetHeader("X-Webhook-Signature")
+		signature := c.GetHeader("X-AuthGateway-Signature")
+		if signature == "" {
+			// Older gateways only send the bare hex in X-Webhook-Signature
+			signature = "sha256=" + c.GetHeader("X-Webhook-Signature")
+		}
 		if !ws.verifySignature(body, signature) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
 			return
@@ -0,0 +1,104 @@
+package authgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SDKVersion is the version of this SDK.
+	SDKVersion = "0.1.0"
+
+	// APIVersion is the version of the Auth Gateway API this SDK speaks.
+	APIVersion = "v1"
+
+	// MinServerVersion is the oldest server release with every endpoint this SDK calls.
+	MinServerVersion = "v1.0.0"
+)
+
+// IncompatibleServerError is returned by CheckCompatibility when the server lacks the
+// features this SDK needs.
+type IncompatibleServerError struct {
+	ServerVersion string // "unknown" when the server predates /version
+	Reason        string
+}
+
+func (e *IncompatibleServerError) Error() string {
+	return fmt.Sprintf("auth gateway server %s is not compatible with SDK %s: %s", e.ServerVersion, SDKVersion, e.Reason)
+}
+
+// CheckCompatibility reads the version of the server and returns an
+// *IncompatibleServerError when it doesn't serve APIVersion or is older than
+// MinServerVersion. Development builds, whose version is not semantic, are assumed
+// to be compatible.
+func (c *Client) CheckCompatibility(ctx context.Context) error {
+	info, err := c.Version(ctx)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+			return &IncompatibleServerError{ServerVersion: "unknown", Reason: "the server predates the /version endpoint"}
+		}
+		return fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	if !containsString(info.APIVersions, APIVersion) {
+		return &IncompatibleServerError{
+			ServerVersion: info.Version,
+			Reason:        fmt.Sprintf("the server serves API versions %v, not %s", info.APIVersions, APIVersion),
+		}
+	}
+
+	if older, ok := versionOlder(info.Version, MinServerVersion); ok && older {
+		return &IncompatibleServerError{
+			ServerVersion: info.Version,
+			Reason:        fmt.Sprintf("the SDK needs server %s or later", MinServerVersion),
+		}
+	}
+
+	return nil
+}
+
+// versionOlder reports whether semantic version a is older than b. ok is false when a
+// can't be parsed. Pre-release and git describe suffixes ("-rc.1", "-3-g9f1c2d3") are
+// ignored, so a build is compared as the release it is based on.
+func versionOlder(a, b string) (older, ok bool) {
+	va, ok := parseVersion(a)
+	if !ok {
+		return false, false
+	}
+	vb, ok := parseVersion(b)
+	if !ok {
+		return false, false
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] < vb[i], true
+		}
+	}
+	return false, true
+}
+
+// parseVersion parses the major, minor and patch numbers of a semantic version
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
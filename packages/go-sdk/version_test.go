@@ -0,0 +1,93 @@
+package authgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type recordingLogger struct {
+	errors []string
+}
+
+func (l *recordingLogger) Info(msg string, args ...interface{}) {}
+func (l *recordingLogger) Error(msg string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(msg, args...))
+}
+
+func versionServer(t *testing.T, body string) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	return newTestServer(t, mux).URL
+}
+
+func TestClient_CheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		incompatible bool
+	}{
+		{"ShouldAcceptCurrentServer", `{"version":"v1.4.0","api_versions":["v1"]}`, false},
+		{"ShouldAcceptGitDescribeBuild", `{"version":"v1.4.0-3-g9f1c2d3-dirty","api_versions":["v1"]}`, false},
+		{"ShouldAcceptDevBuild", `{"version":"dev","api_versions":["v1"]}`, false},
+		{"ShouldRejectOlderServer", `{"version":"v0.9.2","api_versions":["v1"]}`, true},
+		{"ShouldRejectMissingAPIVersion", `{"version":"v2.0.0","api_versions":["v2"]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(Config{BaseURL: versionServer(t, tt.body)})
+
+			err := client.CheckCompatibility(context.Background())
+
+			var incompatible *IncompatibleServerError
+			if got := errors.As(err, &incompatible); got != tt.incompatible {
+				t.Errorf("expected incompatible=%v, got error %v", tt.incompatible, err)
+			}
+		})
+	}
+
+	t.Run("ShouldRejectServerWithoutVersionEndpoint", func(t *testing.T) {
+		client := NewClient(Config{BaseURL: newTestServer(t, http.NewServeMux()).URL})
+
+		err := client.CheckCompatibility(context.Background())
+
+		var incompatible *IncompatibleServerError
+		if !errors.As(err, &incompatible) || incompatible.ServerVersion != "unknown" {
+			t.Errorf("expected unknown server version, got %v", err)
+		}
+	})
+}
+
+func TestNewClient_CheckCompatibility(t *testing.T) {
+	t.Run("ShouldWarnWhenServerIsOlder", func(t *testing.T) {
+		logger := &recordingLogger{}
+
+		NewClient(Config{
+			BaseURL:            versionServer(t, `{"version":"v0.9.2","api_versions":["v1"]}`),
+			CheckCompatibility: true,
+			Logger:             logger,
+		})
+
+		if len(logger.errors) != 1 {
+			t.Fatalf("expected one warning, got %v", logger.errors)
+		}
+	})
+
+	t.Run("ShouldNotWarnWhenServerIsCompatible", func(t *testing.T) {
+		logger := &recordingLogger{}
+
+		NewClient(Config{
+			BaseURL:            versionServer(t, `{"version":"v1.4.0","api_versions":["v1"]}`),
+			CheckCompatibility: true,
+			Logger:             logger,
+		})
+
+		if len(logger.errors) != 0 {
+			t.Errorf("expected no warning, got %v", logger.errors)
+		}
+	})
+}